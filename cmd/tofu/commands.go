@@ -101,8 +101,15 @@ func initCommands(
 		RunningInAutomation: inAutomation,
 		CLIConfigDir:        configDir,
 		PluginCacheDir:      config.PluginCacheDir,
+		ModuleCacheDir:      config.ModuleCacheDir,
 
 		PluginCacheMayBreakDependencyLockFile: config.PluginCacheMayBreakDependencyLockFile,
+		StrictDeprecations:                    config.StrictDeprecations,
+
+		EncryptionPluginChecksums: config.EncryptionPluginChecksums(),
+
+		StateLockRetryPolicy: config.StateLockRetryPolicy(),
+		NotificationWebhooks: config.NotificationWebhooks(),
 
 		ShutdownCh:    makeShutdownCh(),
 		CallerContext: ctx,
@@ -111,6 +118,10 @@ func initCommands(
 		ProviderDevOverrides: providerDevOverrides,
 		UnmanagedProviders:   unmanagedProviders,
 
+		ModulePackageFetcherEnv: modulePackageFetcherEnv{
+			getOCICredsPolicy: config.OCICredentialsPolicy,
+		},
+
 		AllowExperimentalFeatures: experimentsAreAllowed(),
 	}
 
@@ -127,6 +138,24 @@ func initCommands(
 			}, nil
 		},
 
+		"assert": func() (cli.Command, error) {
+			return &command.AssertCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"channels": func() (cli.Command, error) {
+			return &command.ChannelsCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"channels promote": func() (cli.Command, error) {
+			return &command.ChannelsPromoteCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"console": func() (cli.Command, error) {
 			return &command.ConsoleCommand{
 				Meta: meta,
@@ -193,6 +222,30 @@ func initCommands(
 			}, nil
 		},
 
+		"history": func() (cli.Command, error) {
+			return &command.HistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"history list": func() (cli.Command, error) {
+			return &command.HistoryListCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"history show": func() (cli.Command, error) {
+			return &command.HistoryShowCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"impact": func() (cli.Command, error) {
+			return &command.ImpactCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"import": func() (cli.Command, error) {
 			return &command.ImportCommand{
 				Meta: meta,
@@ -205,6 +258,12 @@ func initCommands(
 			}, nil
 		},
 
+		"lint": func() (cli.Command, error) {
+			return &command.LintCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"login": func() (cli.Command, error) {
 			return &command.LoginCommand{
 				Meta: meta,
@@ -223,6 +282,12 @@ func initCommands(
 			}, nil
 		},
 
+		"metadata config-schema": func() (cli.Command, error) {
+			return &command.MetadataConfigSchemaCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"metadata functions": func() (cli.Command, error) {
 			return &command.MetadataFunctionsCommand{
 				Meta: meta,
@@ -247,6 +312,12 @@ func initCommands(
 			}, nil
 		},
 
+		"providers constrain": func() (cli.Command, error) {
+			return &command.ProvidersConstrainCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers lock": func() (cli.Command, error) {
 			return &command.ProvidersLockCommand{
 				Meta: meta,
@@ -277,6 +348,24 @@ func initCommands(
 			}, nil
 		},
 
+		"refactor": func() (cli.Command, error) {
+			return &command.RefactorCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"refactor rename-variable": func() (cli.Command, error) {
+			return &command.RefactorRenameVariableCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"run-report": func() (cli.Command, error) {
+			return &command.RunReportCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"show": func() (cli.Command, error) {
 			return &command.ShowCommand{
 				Meta: meta,
@@ -316,6 +405,18 @@ func initCommands(
 			}, nil
 		},
 
+		"vars": func() (cli.Command, error) {
+			return &command.VarsCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"vars explain": func() (cli.Command, error) {
+			return &command.VarsExplainCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"workspace": func() (cli.Command, error) {
 			return &command.WorkspaceCommand{
 				Meta: meta,
@@ -352,6 +453,12 @@ func initCommands(
 			}, nil
 		},
 
+		"workspace sync": func() (cli.Command, error) {
+			return &command.WorkspaceSyncCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		//-----------------------------------------------------------
 		// Plumbing
 		//-----------------------------------------------------------
@@ -366,6 +473,14 @@ func initCommands(
 			return &command.StateCommand{}, nil
 		},
 
+		"state annotate": func() (cli.Command, error) {
+			return &command.StateAnnotateCommand{
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
+
 		"state list": func() (cli.Command, error) {
 			return &command.StateListCommand{
 				Meta: meta,
@@ -428,6 +543,12 @@ func initCommands(
 			}, nil
 		},
 
+		"state scan-secrets": func() (cli.Command, error) {
+			return &command.StateScanSecretsCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state show": func() (cli.Command, error) {
 			return &command.StateShowCommand{
 				Meta: meta,
@@ -441,6 +562,14 @@ func initCommands(
 				},
 			}, nil
 		},
+
+		"state unquarantine": func() (cli.Command, error) {
+			return &command.StateUnquarantineCommand{
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
 	}
 
 	primaryCommands = []string{
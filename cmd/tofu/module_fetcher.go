@@ -0,0 +1,34 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/getmodules"
+)
+
+// modulePackageFetcherEnv is our implementation of
+// getmodules.PackageFetcherEnvironment, which gives the module installer
+// access to OCI registries using the same credentials as provider
+// installation.
+type modulePackageFetcherEnv struct {
+	getOCICredsPolicy ociCredsPolicyBuilder
+}
+
+var _ getmodules.PackageFetcherEnvironment = modulePackageFetcherEnv{}
+
+// OCIRepositoryStore implements getmodules.PackageFetcherEnvironment.
+func (e modulePackageFetcherEnv) OCIRepositoryStore(ctx context.Context, registryDomain, repositoryName string) (getmodules.OCIRepositoryStore, error) {
+	// As with provider installation, we delay finalizing the credentials
+	// policy until we actually need to interact with an OCI registry.
+	credsPolicy, err := e.getOCICredsPolicy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials configuration for OCI registries: %w", err)
+	}
+	return getOCIRepositoryStore(ctx, registryDomain, repositoryName, credsPolicy)
+}
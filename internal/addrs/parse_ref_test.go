@@ -320,6 +320,55 @@ func TestParseRef(t *testing.T) {
 			`The "data" object must be followed by two attribute names: the data source type and the resource name.`,
 		},
 
+		// ephemeral
+		{
+			`ephemeral.vault_token.foo`,
+			&Reference{
+				Subject: Resource{
+					Mode: EphemeralResourceMode,
+					Type: "vault_token",
+					Name: "foo",
+				},
+				SourceRange: tfdiags.SourceRange{
+					Start: tfdiags.SourcePos{Line: 1, Column: 1, Byte: 0},
+					End:   tfdiags.SourcePos{Line: 1, Column: 26, Byte: 25},
+				},
+			},
+			``,
+		},
+		{
+			`ephemeral.vault_token.foo[0].token`,
+			&Reference{
+				Subject: ResourceInstance{
+					Resource: Resource{
+						Mode: EphemeralResourceMode,
+						Type: "vault_token",
+						Name: "foo",
+					},
+					Key: IntKey(0),
+				},
+				SourceRange: tfdiags.SourceRange{
+					Start: tfdiags.SourcePos{Line: 1, Column: 1, Byte: 0},
+					End:   tfdiags.SourcePos{Line: 1, Column: 29, Byte: 28},
+				},
+				Remaining: hcl.Traversal{
+					hcl.TraverseAttr{
+						Name: "token",
+						SrcRange: hcl.Range{
+							Start: hcl.Pos{Line: 1, Column: 29, Byte: 28},
+							End:   hcl.Pos{Line: 1, Column: 35, Byte: 34},
+						},
+					},
+				},
+			},
+			``,
+		},
+		{
+			`ephemeral.vault_token`,
+			nil,
+			`The "ephemeral" object must be followed by two attribute names: the ephemeral resource type and the resource name.`,
+		},
+
 		// local
 		{
 			`local.foo`,
@@ -152,6 +152,15 @@ type Enhanced interface {
 	ServiceDiscoveryAliases() ([]HostAlias, error)
 }
 
+// StateKeyer is an optional interface for backends that store the state of
+// each workspace under a key, such as a file path or an object name, that is
+// determined by the backend configuration and the workspace name.
+type StateKeyer interface {
+	// StateKey returns the key under which the backend stores the state of
+	// the given workspace.
+	StateKey(workspace string) string
+}
+
 // Local implements additional behavior on a Backend that allows local
 // operations in addition to remote operations.
 //
@@ -284,6 +293,23 @@ type Operation struct {
 	Targets      []addrs.Targetable
 	Excludes     []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
+
+	// FrozenModules causes planning to fail if the source code of a module
+	// has changed since it was last applied, except for the modules listed
+	// in UnfrozenModules.
+	FrozenModules   bool
+	UnfrozenModules []addrs.ModuleInstance
+
+	// Reencrypt requests that the state is written back encrypted with the
+	// primary encryption configuration at the end of a plan or apply, even if
+	// the operation makes no changes to it.
+	Reencrypt bool
+
+	// ExplainUnknown requests that a plan operation explains why values in
+	// the plan are unknown until apply, by tracing them back through the
+	// configuration.
+	ExplainUnknown bool
+
 	// Injected by the command creating the operation (plan/apply/refresh/etc...)
 	Variables map[string]UnparsedVariableValue
 	RootCall  configs.StaticModuleCall
@@ -390,6 +416,11 @@ type RunningOperation struct {
 	// the exit status because the plan value is not available at that point.
 	PlanEmpty bool
 
+	// Plan is the plan that a Plan or Apply operation created or applied, if
+	// any. Like State, this should only be read after the operation
+	// completes.
+	Plan *plans.Plan
+
 	// State is the final state after the operation completed. Persisting
 	// this state is managed by the backend. This should only be read
 	// after the operation completes to avoid read/write races.
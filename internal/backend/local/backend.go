@@ -95,6 +95,7 @@ type Local struct {
 }
 
 var _ backend.Backend = (*Local)(nil)
+var _ backend.StateKeyer = (*Local)(nil)
 
 // New returns a new initialized local backend.
 func New(enc encryption.StateEncryption) *Local {
@@ -263,7 +264,11 @@ func (b *Local) StateMgr(name string) (statemgr.Full, error) {
 	statePath, stateOutPath, backupPath := b.StatePaths(name)
 	log.Printf("[TRACE] backend/local: state manager for workspace %q will:\n - read initial snapshot from %s\n - write new snapshots to %s\n - create any backup at %s", name, statePath, stateOutPath, backupPath)
 
-	s := statemgr.NewFilesystemBetweenPaths(statePath, stateOutPath, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+	s := statemgr.NewFilesystemBetweenPaths(statePath, stateOutPath, enc)
 	if backupPath != "" {
 		s.SetBackupPath(backupPath)
 	}
@@ -402,6 +407,14 @@ func (b *Local) opWait(
 
 // StatePaths returns the StatePath, StateOutPath, and StateBackupPath as
 // configured from the CLI.
+// StateKey implements backend.StateKeyer, returning the path of the state
+// file of the given workspace. If b.Backend is set, the states are stored by
+// that backend instead, so callers must use its keys.
+func (b *Local) StateKey(name string) string {
+	statePath, _, _ := b.StatePaths(name)
+	return filepath.ToSlash(statePath)
+}
+
 func (b *Local) StatePaths(name string) (stateIn, stateOut, backupOut string) {
 	statePath := b.OverrideStatePath
 	stateOutPath := b.OverrideStateOutPath
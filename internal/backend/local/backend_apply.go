@@ -78,7 +78,8 @@ func (b *Local) opApply(
 	}
 
 	stateHook := new(StateHook)
-	op.Hooks = append(op.Hooks, stateHook)
+	interruptHook := new(InterruptHook)
+	op.Hooks = append(op.Hooks, stateHook, interruptHook)
 
 	// Get our context
 	lr, _, opState, contextDiags := b.localRun(ctx, op)
@@ -124,6 +125,7 @@ func (b *Local) opApply(
 		// Perform the plan
 		log.Printf("[INFO] backend/local: apply calling Plan")
 		plan, moreDiags = lr.Core.Plan(ctx, lr.Config, lr.InputState, lr.PlanOpts)
+		runningOp.Plan = plan
 		diags = diags.Append(moreDiags)
 		if moreDiags.HasErrors() {
 			// If OpenTofu Core generated a partial plan despite the errors
@@ -240,8 +242,10 @@ func (b *Local) opApply(
 			}
 			diags = filteredDiags
 		}
+		recordIntention(opState, "apply", "")
 	} else {
 		plan = lr.Plan
+		runningOp.Plan = plan
 		if plan.Errored {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -256,6 +260,9 @@ func (b *Local) opApply(
 				op.View.PlannedChange(change)
 			}
 		}
+		if lp, ok := op.PlanFile.Local(); ok {
+			diags = diags.Append(checkLaterIntentions(opState, lp, plan))
+		}
 	}
 
 	// Set up our hook for continuous state updates
@@ -274,6 +281,7 @@ func (b *Local) opApply(
 	}()
 
 	if b.opWait(doneCh, stopCtx, cancelCtx, lr.Core, opState, op.View) {
+		b.snapshotCanceledApply(opState, schemas, interruptHook, op.View)
 		return
 	}
 	diags = diags.Append(applyDiags)
@@ -288,6 +296,13 @@ func (b *Local) opApply(
 
 	// Store the final state
 	runningOp.State = applyState
+	if op.Reencrypt {
+		// The state is always persisted at the end of an apply, but state
+		// managers may skip writing a snapshot that has not changed.
+		if r, ok := opState.(statemgr.Reencrypter); ok {
+			r.ForceReencrypt()
+		}
+	}
 	err := statemgr.WriteAndPersist(opState, applyState, schemas)
 	if err != nil {
 		// Export the state file from the state manager and assign the new
@@ -314,6 +329,26 @@ func (b *Local) opApply(
 	op.View.Diagnostics(diags)
 }
 
+// snapshotCanceledApply is called when an apply operation is forcefully
+// canceled. It persists the latest state snapshot reported by OpenTofu Core
+// before the process exits and reports the operations that were still in
+// progress, whose outcome is therefore unknown.
+func (b *Local) snapshotCanceledApply(opState statemgr.Full, schemas *tofu.Schemas, interruptHook *InterruptHook, view views.Operation) {
+	var diags tfdiags.Diagnostics
+
+	if err := opState.PersistState(schemas); err != nil {
+		stateFile := statemgr.Export(opState)
+		if stateFile == nil {
+			stateFile = &statefile.File{}
+		}
+		stateFile.State = opState.State()
+		diags = diags.Append(b.backupStateForError(stateFile, err, view))
+	}
+
+	diags = diags.Append(interruptHook.Report())
+	view.Diagnostics(diags)
+}
+
 // backupStateForError is called in a scenario where we're unable to persist the
 // state for some reason, and will attempt to save a backup copy of the state
 // to local disk to help the user recover. This is a "last ditch effort" sort
@@ -438,3 +438,47 @@ func TestApply_applyCanceledAutoApprove(t *testing.T) {
 	}
 
 }
+
+func TestLocal_applyCanceledInFlight(t *testing.T) {
+	b := TestLocal(t)
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		close(started)
+		<-release
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+
+	op, configCleanup, done := testOperationApply(t, "./testdata/apply")
+	op.AutoApprove = true
+	defer configCleanup()
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("error starting operation: %v", err)
+	}
+
+	<-started
+	run.Stop()
+	run.Cancel()
+	<-run.Done()
+
+	output := done(t).All()
+	for _, want := range []string{
+		"Operations interrupted with unknown outcome",
+		"- test_instance.foo: create, in progress for",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	// The state snapshot must have been persisted even though the apply
+	// was canceled before it could complete.
+	if _, err := os.Stat(b.StateOutPath); err != nil {
+		t.Fatalf("state was not persisted: %s", err)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -69,6 +71,13 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 		diags = diags.Append(fmt.Errorf("error loading state: %w", err))
 		return nil, nil, nil, diags
 	}
+	if r, ok := s.(statemgr.Reencrypter); ok && r.EncryptionStatus() == encryption.StatusMigration && !op.Reencrypt {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"State not encrypted with the primary encryption configuration",
+			fmt.Sprintf("The latest state snapshot for workspace %q could only be decrypted using a fallback encryption configuration, so the encryption configuration has changed since the state was last written. OpenTofu will re-encrypt the state with the primary configuration the next time it writes the state.\n\nTo re-encrypt the state now without making any other changes, run \"tofu apply -reencrypt\". Do not remove the fallback configuration until the state has been re-encrypted.", op.Workspace),
+		))
+	}
 
 	ret := &backend.LocalRun{}
 
@@ -153,6 +162,11 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 	}
 	run.Config = config
 
+	diags = diags.Append(config.Module.Workspaces.CheckWorkspace(op.Workspace))
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
 	if errs := config.VerifyDependencySelections(op.DependencyLocks); len(errs) > 0 {
 		var buf strings.Builder
 		for _, err := range errs {
@@ -191,7 +205,9 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 		// values through interactive prompts.
 		// TODO: Need to route the operation context through into here, so that
 		// the interactive prompts can be sensitive to its timeouts/etc.
-		rawVariables = b.interactiveCollectVariables(context.TODO(), op.Variables, config.Module.Variables, op.UIIn)
+		var inputDiags tfdiags.Diagnostics
+		rawVariables, inputDiags = b.interactiveCollectVariables(context.TODO(), op.Variables, config.Module.Variables, op.UIIn)
+		diags = diags.Append(inputDiags)
 	}
 
 	variables, varDiags := backend.ParseVariableValues(rawVariables, config.Module.Variables)
@@ -205,6 +221,8 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 		Targets:            op.Targets,
 		Excludes:           op.Excludes,
 		ForceReplace:       op.ForceReplace,
+		FrozenModules:      op.FrozenModules,
+		UnfrozenModules:    op.UnfrozenModules,
 		SetVariables:       variables,
 		SkipRefresh:        op.Type != backend.OperationTypeRefresh && !op.PlanRefresh,
 		GenerateConfigPath: op.GenerateConfigOut,
@@ -269,6 +287,26 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 		var diags hcl.Diagnostics
 
 		name := variable.Name
+		if variable.Ephemeral {
+			// Ephemeral variables are not saved in the plan file, so they
+			// must be set again when applying it.
+			rv, ok := op.Variables[name]
+			if !ok {
+				if variable.Required() {
+					return cty.DynamicVal, diags
+				}
+				return variable.Default, nil
+			}
+			val, valDiags := rv.ParseVariableValue(variable.ParsingMode)
+			if valDiags.HasErrors() {
+				return cty.DynamicVal, diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  valDiags.Err().Error(),
+				})
+			}
+			return val.Value, diags
+		}
+
 		v, ok := plan.VariableValues[name]
 		if !ok {
 			if variable.Required() {
@@ -299,6 +337,13 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 	}
 	run.Config = config
 
+	ephemeralVals, moreDiags := ephemeralPlanVariableValues(op.Variables, config.Module.Variables)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, snap, diags
+	}
+	plan.EphemeralVariableValues = ephemeralVals
+
 	// NOTE: We're intentionally comparing the current locks with the
 	// configuration snapshot, rather than the lock snapshot in the plan file,
 	// because it's the current locks which dictate our plugin selections
@@ -394,6 +439,65 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 	return run, snap, diags
 }
 
+// ephemeralPlanVariableValues returns the values of the ephemeral root module
+// input variables for applying a saved plan, which doesn't include them.
+//
+// The values of all other variables are taken from the saved plan, so it is
+// an error to set them explicitly with -var or -var-file options.
+func ephemeralPlanVariableValues(vv map[string]backend.UnparsedVariableValue, decls map[string]*configs.Variable) (map[string]plans.DynamicValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	ret := make(map[string]plans.DynamicValue)
+
+	for name, rv := range vv {
+		decl, declared := decls[name]
+		parsingMode := configs.VariableParseLiteral
+		if declared {
+			parsingMode = decl.ParsingMode
+		}
+		val, valDiags := rv.ParseVariableValue(parsingMode)
+		diags = diags.Append(valDiags)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		if !declared || !decl.Ephemeral {
+			switch val.SourceType {
+			case tofu.ValueFromCLIArg, tofu.ValueFromNamedFile:
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Can't set variables when applying a saved plan",
+					fmt.Sprintf("The -var and -var-file options can only set ephemeral variables when applying a saved plan file, because a saved plan includes the values of all other variables that were set when it was created. The variable %q is not an ephemeral variable of the root module.", name),
+				))
+			}
+			continue
+		}
+
+		dv, err := plans.NewDynamicValue(val.Value, cty.DynamicPseudoType)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid variable value",
+				fmt.Sprintf("The value for ephemeral variable %q could not be prepared for the apply: %s.", name, err),
+			))
+			continue
+		}
+		ret[name] = dv
+	}
+
+	for name, decl := range decls {
+		if _, isSet := vv[name]; !isSet && decl.Ephemeral && decl.Required() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "No value for required variable",
+				Detail:   fmt.Sprintf("The root module input variable %q is ephemeral, so its value is not saved in the plan file, and it has no default value. Use a -var or -var-file command line argument to provide a value for this variable when applying the plan.", name),
+				Subject:  decl.DeclRange.Ptr(),
+			})
+		}
+	}
+
+	return ret, diags
+}
+
 // interactiveCollectVariables attempts to complete the given existing
 // map of variables by interactively prompting for any variables that are
 // declared as required but not yet present.
@@ -403,6 +507,12 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 // map will be incomplete. For these reasons, the caller must still validate
 // that the result is complete and valid.
 //
+// Each value is checked against the variable's type constraint as soon as it
+// is entered, and the user is asked again if it isn't valid. When more than
+// one variable was prompted for, the user may then choose to save the values
+// to a variable definitions file, so that they need not be entered again.
+// The returned diagnostics only report problems saving that file.
+//
 // This function does not modify the map given in "existing", but may return
 // it unchanged if no modifications are required. If modifications are required,
 // the result is a new map with all the elements from "existing" plus
@@ -414,7 +524,7 @@ func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader,
 // messages that variables are not set rather than reporting that input failed:
 // the primary resolution to missing variables is to provide them by some other
 // means.
-func (b *Local) interactiveCollectVariables(ctx context.Context, existing map[string]backend.UnparsedVariableValue, vcs map[string]*configs.Variable, uiInput tofu.UIInput) map[string]backend.UnparsedVariableValue {
+func (b *Local) interactiveCollectVariables(ctx context.Context, existing map[string]backend.UnparsedVariableValue, vcs map[string]*configs.Variable, uiInput tofu.UIInput) (map[string]backend.UnparsedVariableValue, tfdiags.Diagnostics) {
 	var needed []string
 	if b.OpInput && uiInput != nil {
 		for name, vc := range vcs {
@@ -429,7 +539,7 @@ func (b *Local) interactiveCollectVariables(ctx context.Context, existing map[st
 		log.Print("[DEBUG] backend/local: Skipping interactive prompts for variables because input is disabled")
 	}
 	if len(needed) == 0 {
-		return existing
+		return existing, nil
 	}
 
 	log.Printf("[DEBUG] backend/local: will prompt for input of unset required variables %s", needed)
@@ -441,14 +551,14 @@ func (b *Local) interactiveCollectVariables(ctx context.Context, existing map[st
 	for k, v := range existing {
 		ret[k] = v
 	}
-	for _, name := range needed {
+	answers := make(map[string]cty.Value, len(needed))
+	for i, name := range needed {
 		vc := vcs[name]
-		rawValue, err := uiInput.Input(ctx, &tofu.InputOpts{
-			Id:          fmt.Sprintf("var.%s", name),
-			Query:       fmt.Sprintf("var.%s", name),
-			Description: vc.Description,
-			Secret:      vc.Sensitive,
-		})
+		query := fmt.Sprintf("var.%s", name)
+		if len(needed) > 1 {
+			query = fmt.Sprintf("var.%s (%d of %d)", name, i+1, len(needed))
+		}
+		rawValue, val, err := promptVariableValue(ctx, uiInput, query, name, vc)
 		if err != nil {
 			// Since interactive prompts are best-effort, we'll just continue
 			// here and let subsequent validation report this as a variable
@@ -457,8 +567,16 @@ func (b *Local) interactiveCollectVariables(ctx context.Context, existing map[st
 			continue
 		}
 		ret[name] = unparsedInteractiveVariableValue{Name: name, RawValue: rawValue}
+		if val != cty.NilVal {
+			answers[name] = val
+		}
 	}
-	return ret
+
+	var diags tfdiags.Diagnostics
+	if len(needed) > 1 && len(answers) > 0 {
+		diags = diags.Append(saveInteractiveVariables(ctx, uiInput, answers, vcs))
+	}
+	return ret, diags
 }
 
 // stubUnsetVariables ensures that all required variables defined in the
@@ -10,11 +10,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/explain"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -144,6 +146,18 @@ func (b *Local) opPlan(
 
 	// Record whether this plan includes any side-effects that could be applied.
 	runningOp.PlanEmpty = !plan.CanApply()
+	runningOp.Plan = plan
+
+	// Re-encrypt the state before saving the plan, so that the plan file
+	// refers to the state snapshot we write here.
+	if op.Reencrypt && !diags.HasErrors() {
+		moreDiags := b.reencryptState(lr, opState)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
 
 	// Save the plan to disk
 	if path := op.PlanOutPath; path != "" {
@@ -194,6 +208,18 @@ func (b *Local) opPlan(
 		}
 	}
 
+	// Record that we intend to change the state, so that an apply of an
+	// older saved plan can warn that it would make this plan stale.
+	if !runningOp.PlanEmpty && !diags.HasErrors() {
+		var planHash string
+		if op.PlanOutPath != "" {
+			if raw, err := os.ReadFile(op.PlanOutPath); err == nil {
+				planHash = planfile.Hash(raw)
+			}
+		}
+		recordIntention(opState, "plan", planHash)
+	}
+
 	// Render the plan, if we produced one.
 	// (This might potentially be a partial plan with Errored set to true)
 	schemas, moreDiags := lr.Core.Schemas(lr.Config, lr.InputState)
@@ -213,6 +239,10 @@ func (b *Local) opPlan(
 
 	op.View.Plan(plan, schemas)
 
+	if op.ExplainUnknown {
+		op.View.UnknownExplanations(explain.Plan(lr.Config, plan, schemas, diags))
+	}
+
 	// If we've accumulated any diagnostics along the way then we'll show them
 	// here just before we show the summary and next steps. This can potentially
 	// include errors, because we intentionally try to show a partial plan
@@ -229,6 +259,31 @@ func (b *Local) opPlan(
 	}
 }
 
+// reencryptState writes the unchanged state back to the state manager, which
+// encrypts it with the primary encryption configuration.
+func (b *Local) reencryptState(lr *backend.LocalRun, opState statemgr.Full) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	schemas, moreDiags := lr.Core.Schemas(lr.Config, lr.InputState)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	if r, ok := opState.(statemgr.Reencrypter); ok {
+		r.ForceReencrypt()
+	}
+	log.Printf("[INFO] backend/local: re-encrypting state")
+	if err := opState.PersistState(schemas); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to re-encrypt state",
+			fmt.Sprintf("The state could not be written with the primary encryption configuration: %s.", err),
+		))
+	}
+	return diags
+}
+
 func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool, diags tfdiags.Diagnostics) {
 	if genconfig.ShouldWriteConfig(out) {
 		diags := genconfig.ValidateTargetFile(out)
@@ -22,6 +22,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/enctest"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
@@ -60,6 +61,44 @@ func TestLocal_planBasic(t *testing.T) {
 	}
 }
 
+func TestLocal_planExplainUnknown(t *testing.T) {
+	b := TestLocal(t)
+	TestLocalProvider(t, b, "test", providers.ProviderSchema{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":  {Type: cty.String, Computed: true},
+						"ami": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	})
+
+	op, configCleanup, done := testOperationPlan(t, "./testdata/plan-explain-unknown")
+	defer configCleanup()
+	op.ExplainUnknown = true
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	output := done(t)
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("plan operation failed\n%s", output.Stderr())
+	}
+
+	want := `
+  # test_instance.b.ami (at testdata/plan-explain-unknown/main.tf:5,9) depends on:
+    - test_instance.a.id (at testdata/plan-explain-unknown/main.tf:5,16): test_instance.a will be created, and the provider decides its id during apply
+`
+	if got := output.Stdout(); !strings.Contains(got, want) {
+		t.Fatalf("missing explanation\nwant:%s\ngot:\n%s", want, got)
+	}
+}
+
 func TestLocal_planInAutomation(t *testing.T) {
 	b := TestLocal(t)
 	TestLocalProvider(t, b, "test", planFixtureSchema())
@@ -119,6 +158,36 @@ func TestLocal_planNoConfig(t *testing.T) {
 	assertBackendStateUnlocked(t, b)
 }
 
+func TestLocal_planUndeclaredWorkspace(t *testing.T) {
+	b := TestLocal(t)
+	p := TestLocalProvider(t, b, "test", planFixtureSchema())
+
+	op, configCleanup, done := testOperationPlan(t, "./testdata/plan-workspaces")
+	defer configCleanup()
+	op.Workspace = backend.DefaultStateName
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+
+	output := done(t)
+
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("plan operation succeeded; want failure")
+	}
+	if stderr := output.Stderr(); !strings.Contains(stderr, "Workspace not declared in configuration") {
+		t.Fatalf("bad: %s", stderr)
+	}
+	if p.PlanResourceChangeCalled {
+		t.Fatal("PlanResourceChange should not be called")
+	}
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+}
+
 // This test validates the state lacking behavior when the inner call to
 // Context() fails
 func TestLocal_plan_context_error(t *testing.T) {
@@ -546,6 +615,68 @@ func TestLocal_planRefreshFalse(t *testing.T) {
 	}
 }
 
+func TestLocal_planReencrypt(t *testing.T) {
+	b := TestLocal(t)
+	b.encryption = enctest.EncryptionWithFallback().State()
+
+	TestLocalProvider(t, b, "test", planFixtureSchema())
+	// The existing state is unencrypted, which is only allowed by the
+	// fallback configuration.
+	testStateFile(t, b.StatePath, testPlanState())
+
+	isEncrypted := func() bool {
+		t.Helper()
+		data, err := os.ReadFile(b.StatePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := encryption.IsEncryptionPayload(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	// A normal plan warns about the state but doesn't write it.
+	op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+	defer configCleanup()
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("plan operation failed")
+	}
+	output := done(t)
+	if want := "State not encrypted with the primary encryption configuration"; !strings.Contains(output.All(), want) {
+		t.Errorf("output does not contain %q:\n%s", want, output.All())
+	}
+	if isEncrypted() {
+		t.Fatal("state was re-encrypted by a normal plan")
+	}
+
+	// With -reencrypt the unchanged state is written back encrypted.
+	op, configCleanup2, done := testOperationPlan(t, "./testdata/plan")
+	defer configCleanup2()
+	op.Reencrypt = true
+	run, err = b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("plan operation failed:\n%s", done(t).All())
+	}
+	if errOutput := done(t).Stderr(); errOutput != "" {
+		t.Fatalf("unexpected error output:\n%s", errOutput)
+	}
+	if !isEncrypted() {
+		t.Fatal("state was not re-encrypted")
+	}
+	assertBackendStateUnlocked(t, b)
+}
+
 func TestLocal_planDestroy(t *testing.T) {
 	b := TestLocal(t)
 
@@ -0,0 +1,169 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// InterruptHook is a hook that keeps track of the resource instance changes
+// that are in progress during an apply, so that if the operation is canceled
+// forcefully we can tell the user which of them have an unknown outcome.
+type InterruptHook struct {
+	tofu.NilHook
+	sync.Mutex
+
+	inFlight map[string]*inFlightOperation
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+type inFlightOperation struct {
+	Addr        addrs.AbsResourceInstance
+	DeposedKey  states.DeposedKey
+	Action      plans.Action
+	Provisioner string
+	Started     time.Time
+}
+
+var _ tofu.Hook = (*InterruptHook)(nil)
+
+func (h *InterruptHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.inFlight == nil {
+		h.inFlight = make(map[string]*inFlightOperation)
+	}
+	op := &inFlightOperation{
+		Addr:    addr,
+		Action:  action,
+		Started: h.timeNow(),
+	}
+	if dk, ok := gen.(states.DeposedKey); ok {
+		op.DeposedKey = dk
+	}
+	h.inFlight[inFlightKey(addr, gen)] = op
+	return tofu.HookActionContinue, nil
+}
+
+func (h *InterruptHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	delete(h.inFlight, inFlightKey(addr, gen))
+	return tofu.HookActionContinue, nil
+}
+
+func (h *InterruptHook) PreProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string) (tofu.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	// Provisioners run between PreApply and PostApply for the new object.
+	if op, ok := h.inFlight[inFlightKey(addr, states.CurrentGen)]; ok {
+		op.Provisioner = typeName
+	}
+	return tofu.HookActionContinue, nil
+}
+
+func (h *InterruptHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string, err error) (tofu.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if op, ok := h.inFlight[inFlightKey(addr, states.CurrentGen)]; ok {
+		op.Provisioner = ""
+	}
+	return tofu.HookActionContinue, nil
+}
+
+// Report returns a warning describing each of the operations that were still
+// in progress, or no diagnostics at all if there were none.
+func (h *InterruptHook) Report() tfdiags.Diagnostics {
+	h.Lock()
+	defer h.Unlock()
+
+	var diags tfdiags.Diagnostics
+	if len(h.inFlight) == 0 {
+		return diags
+	}
+
+	ops := make([]*inFlightOperation, 0, len(h.inFlight))
+	for _, op := range h.inFlight {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].Addr.Equal(ops[j].Addr) {
+			return ops[i].Addr.Less(ops[j].Addr)
+		}
+		return ops[i].DeposedKey < ops[j].DeposedKey
+	})
+
+	now := h.timeNow()
+	var buf strings.Builder
+	for _, op := range ops {
+		fmt.Fprintf(&buf, "\n  - %s", op.Addr)
+		if op.DeposedKey != states.NotDeposed {
+			fmt.Fprintf(&buf, " (deposed object %s)", op.DeposedKey)
+		}
+		fmt.Fprintf(&buf, ": %s", interruptedActionDescription(op.Action))
+		if op.Provisioner != "" {
+			fmt.Fprintf(&buf, ", running provisioner %q", op.Provisioner)
+		}
+		fmt.Fprintf(&buf, ", in progress for %s", now.Sub(op.Started).Round(time.Second))
+	}
+
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Operations interrupted with unknown outcome",
+		fmt.Sprintf(
+			"OpenTofu was canceled while the following operations were still in progress:\n%s\n\nThe providers may or may not have completed these operations, so the corresponding remote objects might differ from what is recorded in the state. Verify these objects before running OpenTofu again, and use \"tofu import\" or \"tofu state rm\" to reconcile the state if necessary.",
+			buf.String(),
+		),
+	))
+	return diags
+}
+
+func (h *InterruptHook) timeNow() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
+
+func inFlightKey(addr addrs.AbsResourceInstance, gen states.Generation) string {
+	if dk, ok := gen.(states.DeposedKey); ok {
+		return addr.String() + " " + dk.String()
+	}
+	return addr.String()
+}
+
+func interruptedActionDescription(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update in-place"
+	case plans.Delete:
+		return "destroy"
+	case plans.Read:
+		return "read"
+	default:
+		return strings.ToLower(action.String())
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestInterruptHook(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hook := &InterruptHook{
+		now: func() time.Time { return now },
+	}
+
+	foo := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "foo"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	bar := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "bar"}.Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance)
+	baz := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "baz"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	if diags := hook.Report(); len(diags) != 0 {
+		t.Fatalf("unexpected report before any operation: %s", diags.ErrWithWarnings())
+	}
+
+	hook.PreApply(foo, states.CurrentGen, plans.Create, cty.NullVal(cty.DynamicPseudoType), cty.EmptyObjectVal)
+	hook.PreApply(bar, states.DeposedKey("00000001"), plans.Delete, cty.EmptyObjectVal, cty.NullVal(cty.DynamicPseudoType))
+	hook.PreApply(baz, states.CurrentGen, plans.Update, cty.EmptyObjectVal, cty.EmptyObjectVal)
+	now = now.Add(90 * time.Second)
+	hook.PreProvisionInstanceStep(foo, "local-exec")
+	hook.PostApply(baz, states.CurrentGen, cty.EmptyObjectVal, nil)
+
+	diags := hook.Report()
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics %d; want 1", len(diags))
+	}
+	desc := diags[0].Description()
+	if got, want := diags[0].Severity(), tfdiags.Warning; got != want {
+		t.Errorf("wrong severity %s; want %s", got, want)
+	}
+	if got, want := desc.Summary, "Operations interrupted with unknown outcome"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	want := `OpenTofu was canceled while the following operations were still in progress:
+
+  - test_instance.bar[0] (deposed object 00000001): destroy, in progress for 1m30s
+  - test_instance.foo: create, running provisioner "local-exec", in progress for 1m30s
+
+The providers may or may not have completed these operations, so the corresponding remote objects might differ from what is recorded in the state. Verify these objects before running OpenTofu again, and use "tofu import" or "tofu state rm" to reconcile the state if necessary.`
+	if got := desc.Detail; got != want {
+		t.Errorf("wrong detail\ngot:\n%s\n\nwant:\n%s", got, want)
+	}
+}
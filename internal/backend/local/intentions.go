@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// recordIntention records that the given operation intends to change the
+// objects tracked by the given state, if its state manager can store
+// intentions.
+//
+// Intentions are only advisory, so a failure to record one is logged rather
+// than reported as a diagnostic.
+func recordIntention(opState statemgr.Full, operation, planHash string) {
+	recorder, ok := opState.(statemgr.IntentionRecorder)
+	if !ok {
+		return
+	}
+	intention := statemgr.NewIntention(operation, stateLineage(opState), planHash)
+	if err := recorder.RecordIntention(intention); err != nil {
+		log.Printf("[WARN] backend/local: failed to record %s intention: %s", operation, err)
+	}
+}
+
+// checkLaterIntentions returns a warning if another plan or apply for the
+// same state lineage recorded its intention after the given saved plan was
+// created, in which case applying the saved plan is likely to make the other
+// operation's plan stale.
+func checkLaterIntentions(opState statemgr.Full, pf *planfile.Reader, plan *plans.Plan) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	recorder, ok := opState.(statemgr.IntentionRecorder)
+	if !ok {
+		return diags
+	}
+	intentions, err := recorder.Intentions()
+	if err != nil {
+		log.Printf("[WARN] backend/local: failed to read intentions: %s", err)
+		return diags
+	}
+
+	later := statemgr.LaterIntentions(intentions, stateLineage(opState), pf.Hash(), plan.Timestamp)
+	if len(later) == 0 {
+		return diags
+	}
+
+	var buf strings.Builder
+	for _, intention := range later {
+		fmt.Fprintf(&buf, "\n  - %s", intention)
+	}
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Saved plan is older than other operations",
+		fmt.Sprintf(
+			"Since this plan was created, other operations have started for the same workspace:%s\n\nApplying this plan will make any plans created by those operations stale, so they will need to be created again before they can be applied.",
+			buf.String(),
+		),
+	))
+	return diags
+}
+
+// stateLineage returns the lineage of the most recent snapshot of the given
+// state, or an empty string if its state manager doesn't expose it.
+func stateLineage(opState statemgr.Full) string {
+	if sm, ok := opState.(statemgr.PersistentMeta); ok {
+		return sm.StateSnapshotMeta().Lineage
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestLocal_planIntentions(t *testing.T) {
+	b := TestLocal(t)
+	TestLocalProvider(t, b, "test", planFixtureSchema())
+
+	runPlan := func(planPath string) {
+		t.Helper()
+		op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+		defer configCleanup()
+		if planPath != "" {
+			op.PlanOutPath = planPath
+			cfg := cty.ObjectVal(map[string]cty.Value{
+				"path": cty.StringVal(b.StatePath),
+			})
+			cfgRaw, err := plans.NewDynamicValue(cfg, cfg.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			op.PlanOutBackend = &plans.Backend{
+				Type:   "local",
+				Config: cfgRaw,
+			}
+		}
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		<-run.Done()
+		if run.Result != backend.OperationSuccess {
+			t.Fatalf("plan operation failed:\n%s", done(t).All())
+		}
+	}
+
+	planPath := filepath.Join(t.TempDir(), "plan.tfplan")
+	runPlan(planPath)
+
+	pf, err := planfile.Open(planPath, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := pf.ReadPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stateMgr := statemgr.NewFilesystem(b.StatePath, encryption.StateEncryptionDisabled())
+	intentions, err := stateMgr.Intentions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intentions) != 1 {
+		t.Fatalf("wrong number of intentions %d; want 1", len(intentions))
+	}
+	if got, want := intentions[0].Operation, "plan"; got != want {
+		t.Errorf("wrong operation %q; want %q", got, want)
+	}
+	if got, want := intentions[0].PlanHash, pf.Hash(); got != want {
+		t.Errorf("wrong plan hash %q; want %q", got, want)
+	}
+
+	// The plan's own intention is not reported.
+	if diags := checkLaterIntentions(stateMgr, pf, plan); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags.ErrWithWarnings())
+	}
+
+	// Another plan for the same workspace is reported, because applying the
+	// saved plan would make it stale.
+	runPlan("")
+	diags := checkLaterIntentions(stateMgr, pf, plan)
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics %d; want 1", len(diags))
+	}
+	if got, want := diags[0].Description().Summary, "Saved plan is older than other operations"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	if detail := diags[0].Description().Detail; !strings.Contains(detail, "plan by ") {
+		t.Errorf("detail doesn't describe the later plan:\n%s", detail)
+	}
+}
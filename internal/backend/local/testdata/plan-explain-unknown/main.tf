@@ -0,0 +1,6 @@
+resource "test_instance" "a" {
+}
+
+resource "test_instance" "b" {
+  ami = "ami-${test_instance.a.id}"
+}
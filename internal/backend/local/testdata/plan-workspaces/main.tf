@@ -0,0 +1,7 @@
+workspaces {
+  workspace "prod" {}
+}
+
+resource "test_instance" "foo" {
+  ami = "bar"
+}
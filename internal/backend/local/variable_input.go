@@ -0,0 +1,184 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// maxVariableInputAttempts is how many times the user is asked for the value
+// of a variable before giving up on an invalid value, which is then reported
+// by the usual validation of variable values.
+const maxVariableInputAttempts = 3
+
+// promptVariableValue asks the user for the value of the given variable,
+// asking again if the value is not valid for the variable's type constraint.
+//
+// It returns the raw value that was entered, and the parsed value if it is
+// valid or cty.NilVal if the user didn't enter a valid value.
+func promptVariableValue(ctx context.Context, uiInput tofu.UIInput, query, name string, vc *configs.Variable) (string, cty.Value, error) {
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		rawValue, err := uiInput.Input(ctx, &tofu.InputOpts{
+			Id:          fmt.Sprintf("var.%s", name),
+			Query:       query,
+			Description: variableInputDescription(vc, lastErr),
+			Secret:      vc.Sensitive,
+		})
+		if err != nil {
+			return "", cty.NilVal, err
+		}
+
+		val, err := checkInteractiveVariableValue(name, vc, rawValue)
+		if err == nil {
+			return rawValue, val, nil
+		}
+		if attempt == maxVariableInputAttempts {
+			log.Printf("[WARN] backend/local: Giving up on asking for a valid value for variable %q: %s", name, err)
+			return rawValue, cty.NilVal, nil
+		}
+		lastErr = err
+	}
+}
+
+// variableInputDescription returns the description to show when asking for
+// the value of the given variable, which includes its type constraint and, if
+// the previous value that was entered was invalid, the reason why.
+func variableInputDescription(vc *configs.Variable, lastErr error) string {
+	var buf strings.Builder
+	if lastErr != nil {
+		fmt.Fprintf(&buf, "Invalid value: %s\n\n", lastErr)
+	}
+	if vc.Description != "" {
+		buf.WriteString(vc.Description)
+		buf.WriteString("\n\n")
+	}
+	if vc.Type != cty.NilType && vc.Type != cty.DynamicPseudoType {
+		fmt.Fprintf(&buf, "Type: %s\n", typeexpr.TypeString(vc.Type))
+	}
+	if vc.ParsingMode == configs.VariableParseHCL {
+		buf.WriteString("Enter the value using HCL syntax, like in a .tfvars file.\n")
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// checkInteractiveVariableValue parses the given raw value for the given
+// variable and checks that it is valid for the variable's type constraint.
+// Custom validation rules are not checked, because they may refer to other
+// objects that are not yet known.
+func checkInteractiveVariableValue(name string, vc *configs.Variable, rawValue string) (cty.Value, error) {
+	val, diags := vc.ParsingMode.Parse(name, rawValue)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.New(firstErrorDetail(diags))
+	}
+
+	if vc.ConstraintType != cty.NilType {
+		converted, err := convert.Convert(vc.ApplyTypeDefaults(val), vc.ConstraintType)
+		if err == nil {
+			converted, err = vc.TypeRefinements.Apply(converted)
+		}
+		if err != nil {
+			return cty.NilVal, errors.New(tfdiags.FormatError(err))
+		}
+		if !vc.Nullable && converted.IsNull() {
+			return cty.NilVal, errors.New("the value must not be null")
+		}
+	}
+	return val, nil
+}
+
+func firstErrorDetail(diags hcl.Diagnostics) string {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		if diag.Detail == "" {
+			return diag.Summary
+		}
+		return fmt.Sprintf("%s: %s", diag.Summary, diag.Detail)
+	}
+	return diags.Error()
+}
+
+// saveInteractiveVariables offers to save the given values, which the user
+// entered interactively, to a variable definitions file. Values of sensitive
+// variables are never saved.
+func saveInteractiveVariables(ctx context.Context, uiInput tofu.UIInput, values map[string]cty.Value, vcs map[string]*configs.Variable) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	filename, err := uiInput.Input(ctx, &tofu.InputOpts{
+		Id:          "save-variables",
+		Query:       "Save these values to a variable definitions file?",
+		Description: "Enter a filename, such as input.tfvars, to save the values entered above so that they can be given with -var-file next time. Values of sensitive variables are not saved.\nLeave empty to skip.",
+	})
+	if err != nil {
+		log.Printf("[WARN] backend/local: Failed to request user input for saving variables: %s", err)
+		return diags
+	}
+	filename = strings.TrimSpace(filename)
+	if filename == "" {
+		return diags
+	}
+
+	src := variableDefinitionsFile(values, vcs)
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err == nil {
+		_, err = f.Write(src)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to save variable values",
+			fmt.Sprintf("The values entered for the input variables could not be saved to %s: %s.", filename, err),
+		))
+	}
+	return diags
+}
+
+// variableDefinitionsFile returns the source code of a variable definitions
+// file that sets the given variables to the given values, leaving out the
+// values of any sensitive variables.
+func variableDefinitionsFile(values map[string]cty.Value, vcs map[string]*configs.Variable) []byte {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for _, name := range names {
+		if vc := vcs[name]; vc != nil && vc.Sensitive {
+			body.AppendUnstructuredTokens(hclwrite.Tokens{{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte(fmt.Sprintf("# %s is sensitive, so its value was not saved.\n", name)),
+			}})
+			continue
+		}
+		body.SetAttributeValue(name, values[name])
+	}
+	return hclwrite.Format(f.Bytes())
+}
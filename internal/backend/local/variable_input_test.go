@@ -0,0 +1,176 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestLocalInteractiveCollectVariables(t *testing.T) {
+	b := TestLocal(t)
+	b.OpInput = true
+
+	saveFile := filepath.Join(t.TempDir(), "input.tfvars")
+	answers := map[string][]string{
+		"var.count":       {"many", "3"},
+		"var.zones":       {`["a", "b"]`},
+		"var.token":       {"hunter2"},
+		"save-variables":  {saveFile},
+		"var.unprompted":  nil,
+		"var.has_default": nil,
+	}
+	var queries, descriptions []string
+	uiInput := &tofu.MockUIInput{
+		InputFn: func(opts *tofu.InputOpts) (string, error) {
+			queries = append(queries, opts.Query)
+			descriptions = append(descriptions, opts.Description)
+			if len(answers[opts.Id]) == 0 {
+				t.Fatalf("unexpected input request %s", opts.Id)
+			}
+			ret := answers[opts.Id][0]
+			answers[opts.Id] = answers[opts.Id][1:]
+			return ret, nil
+		},
+	}
+
+	vcs := map[string]*configs.Variable{
+		"count": {
+			Name:           "count",
+			Type:           cty.Number,
+			ConstraintType: cty.Number,
+			ParsingMode:    configs.VariableParseLiteral,
+			Nullable:       true,
+		},
+		"zones": {
+			Name:           "zones",
+			Description:    "The availability zones to use.",
+			Type:           cty.List(cty.String),
+			ConstraintType: cty.List(cty.String),
+			ParsingMode:    configs.VariableParseHCL,
+			Nullable:       true,
+		},
+		"token": {
+			Name:           "token",
+			Type:           cty.String,
+			ConstraintType: cty.String,
+			ParsingMode:    configs.VariableParseLiteral,
+			Sensitive:      true,
+			Nullable:       true,
+		},
+		"unprompted": {
+			Name:           "unprompted",
+			Type:           cty.String,
+			ConstraintType: cty.String,
+			ParsingMode:    configs.VariableParseLiteral,
+		},
+		"has_default": {
+			Name:           "has_default",
+			Type:           cty.String,
+			ConstraintType: cty.String,
+			ParsingMode:    configs.VariableParseLiteral,
+			Default:        cty.StringVal("default"),
+		},
+	}
+	existing := map[string]backend.UnparsedVariableValue{
+		"unprompted": unparsedInteractiveVariableValue{Name: "unprompted", RawValue: "given"},
+	}
+
+	got, diags := b.interactiveCollectVariables(context.Background(), existing, vcs, uiInput)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	wantQueries := []string{
+		"var.count (1 of 3)",
+		"var.count (1 of 3)",
+		"var.token (2 of 3)",
+		"var.zones (3 of 3)",
+		"Save these values to a variable definitions file?",
+	}
+	if diff := cmp.Diff(wantQueries, queries); diff != "" {
+		t.Errorf("wrong queries\n%s", diff)
+	}
+	if want := "Type: number"; descriptions[0] != want {
+		t.Errorf("wrong description for the first prompt\ngot:  %q\nwant: %q", descriptions[0], want)
+	}
+	if !strings.HasPrefix(descriptions[1], "Invalid value: a number is required") {
+		t.Errorf("the second prompt doesn't explain the invalid value: %q", descriptions[1])
+	}
+	if want := "The availability zones to use.\n\nType: list(string)\nEnter the value using HCL syntax, like in a .tfvars file."; descriptions[3] != want {
+		t.Errorf("wrong description for var.zones\ngot:  %q\nwant: %q", descriptions[3], want)
+	}
+
+	gotRaw := make(map[string]string)
+	for name, v := range got {
+		gotRaw[name] = v.(unparsedInteractiveVariableValue).RawValue
+	}
+	wantRaw := map[string]string{
+		"count":      "3",
+		"zones":      `["a", "b"]`,
+		"token":      "hunter2",
+		"unprompted": "given",
+	}
+	if diff := cmp.Diff(wantRaw, gotRaw); diff != "" {
+		t.Errorf("wrong values\n%s", diff)
+	}
+
+	src, err := os.ReadFile(saveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSrc := `count = "3"
+# token is sensitive, so its value was not saved.
+zones = ["a", "b"]
+`
+	if diff := cmp.Diff(wantSrc, string(src)); diff != "" {
+		t.Errorf("wrong saved variables\n%s", diff)
+	}
+}
+
+func TestLocalInteractiveCollectVariables_giveUp(t *testing.T) {
+	b := TestLocal(t)
+	b.OpInput = true
+
+	var calls int
+	uiInput := &tofu.MockUIInput{
+		InputFn: func(opts *tofu.InputOpts) (string, error) {
+			calls++
+			return "not a number", nil
+		},
+	}
+	vcs := map[string]*configs.Variable{
+		"count": {
+			Name:           "count",
+			Type:           cty.Number,
+			ConstraintType: cty.Number,
+			ParsingMode:    configs.VariableParseLiteral,
+			Nullable:       true,
+		},
+	}
+
+	got, diags := b.interactiveCollectVariables(context.Background(), nil, vcs, uiInput)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if calls != maxVariableInputAttempts {
+		t.Errorf("asked %d times; want %d", calls, maxVariableInputAttempts)
+	}
+	// The last value is kept, so that the usual validation reports it.
+	if got, want := got["count"].(unparsedInteractiveVariableValue).RawValue, "not a number"; got != want {
+		t.Errorf("wrong value %q; want %q", got, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -99,7 +100,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 		timeoutSeconds:     b.armClient.timeoutSeconds,
 	}
 
-	stateMgr := remote.NewState(client, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMgr := remote.NewState(client, enc)
 
 	// Grab the value
 	if err := stateMgr.RefreshState(); err != nil {
@@ -156,6 +162,11 @@ func (b *Backend) client() *RemoteClient {
 	return &RemoteClient{}
 }
 
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.path(name)
+}
+
 func (b *Backend) path(name string) string {
 	if name == backend.DefaultStateName {
 		return b.keyName
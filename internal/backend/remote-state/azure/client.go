@@ -7,9 +7,11 @@ package azure
 
 import (
 	"context"
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"time"
@@ -23,6 +25,9 @@ import (
 
 const (
 	leaseHeader = "x-ms-lease-id"
+	// azureBlockSize is the size of the blocks that PutStream uploads, which
+	// must be at most 100 MiB in the storage API version that we use.
+	azureBlockSize = 16 << 20
 	// Must be lower case
 	lockInfoMetaKey = "terraformlockid"
 )
@@ -63,11 +68,91 @@ func (c *RemoteClient) Get() (*remote.Payload, error) {
 
 func (c *RemoteClient) Put(data []byte) error {
 	ctx := context.TODO()
+	metaData, err := c.prepareUpload(ctx)
+	if err != nil {
+		return err
+	}
+
+	contentType := "application/json"
+	putOptions := blobs.PutBlockBlobInput{
+		LeaseID:     c.leaseID,
+		Content:     &data,
+		ContentType: &contentType,
+		MetaData:    metaData,
+	}
+	_, err = c.giovanniBlobClient.PutBlockBlob(ctx, c.accountName, c.containerName, c.keyName, putOptions)
+
+	return err
+}
+
+// PutStream uploads the state from a reader in blocks of azureBlockSize
+// bytes, so that only one block at a time is held in memory. The blob isn't
+// changed until all of the blocks have been uploaded.
+//
+// Implements remote.ClientStreamer
+func (c *RemoteClient) PutStream(payload *remote.StreamPayload) error {
+	ctx := context.TODO()
+	metaData, err := c.prepareUpload(ctx)
+	if err != nil {
+		return err
+	}
+
+	var blockIDs []blobs.BlockID
+	buf := make([]byte, azureBlockSize)
+	for {
+		n, err := io.ReadFull(payload, buf)
+		if n == 0 && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("error reading state: %w", err)
+		}
+		block := buf[:n]
+
+		// Block IDs must all be the same length.
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", len(blockIDs))))
+		blockMD5 := md5.Sum(block)
+		blockContentMD5 := base64.StdEncoding.EncodeToString(blockMD5[:])
+		input := blobs.PutBlockInput{
+			BlockID:    blockID,
+			Content:    block,
+			ContentMD5: &blockContentMD5,
+			LeaseID:    c.leaseID,
+		}
+		log.Printf("[DEBUG] Uploading block %d of Blob %q (Container %q / Account %q)", len(blockIDs), c.keyName, c.containerName, c.accountName)
+		if _, err := c.giovanniBlobClient.PutBlock(ctx, c.accountName, c.containerName, c.keyName, input); err != nil {
+			return fmt.Errorf("error uploading block %d of Blob %q (Container %q / Account %q): %w", len(blockIDs), c.keyName, c.containerName, c.accountName, err)
+		}
+		blockIDs = append(blockIDs, blobs.BlockID{Value: blockID})
+	}
+	checksums, err := payload.Checksums()
+	if err != nil {
+		return err
+	}
+
+	contentType := "application/json"
+	contentMD5 := base64.StdEncoding.EncodeToString(checksums.MD5)
+	putOptions := blobs.PutBlockListInput{
+		BlockList:   blobs.BlockList{LatestBlockIDs: blockIDs},
+		ContentMD5:  &contentMD5,
+		ContentType: &contentType,
+		MetaData:    metaData,
+		LeaseID:     c.leaseID,
+	}
+	_, err = c.giovanniBlobClient.PutBlockList(ctx, c.accountName, c.containerName, c.keyName, putOptions)
+
+	return err
+}
+
+// prepareUpload snapshots the existing blob if snapshots are enabled, and
+// returns the metadata of the existing blob to keep in the new one, which
+// includes the lock information.
+func (c *RemoteClient) prepareUpload(ctx context.Context) (map[string]string, error) {
 	if c.snapshot {
 		snapshotInput := blobs.SnapshotInput{LeaseID: c.leaseID}
 		log.Printf("[DEBUG] Snapshotting existing Blob %q (Container %q / Account %q)", c.keyName, c.containerName, c.accountName)
 		if _, err := c.giovanniBlobClient.Snapshot(ctx, c.accountName, c.containerName, c.keyName, snapshotInput); err != nil {
-			return fmt.Errorf("error snapshotting Blob %q (Container %q / Account %q): %w", c.keyName, c.containerName, c.accountName, err)
+			return nil, fmt.Errorf("error snapshotting Blob %q (Container %q / Account %q): %w", c.keyName, c.containerName, c.accountName, err)
 		}
 
 		log.Print("[DEBUG] Created blob snapshot")
@@ -76,20 +161,10 @@ func (c *RemoteClient) Put(data []byte) error {
 	properties, err := c.getBlobProperties()
 	if err != nil {
 		if properties.StatusCode != http.StatusNotFound {
-			return err
+			return nil, err
 		}
 	}
-
-	contentType := "application/json"
-	putOptions := blobs.PutBlockBlobInput{
-		LeaseID:     c.leaseID,
-		Content:     &data,
-		ContentType: &contentType,
-		MetaData:    properties.MetaData,
-	}
-	_, err = c.giovanniBlobClient.PutBlockBlob(ctx, c.accountName, c.containerName, c.keyName, putOptions)
-
-	return err
+	return properties.MetaData, nil
 }
 
 func (c *RemoteClient) Delete() error {
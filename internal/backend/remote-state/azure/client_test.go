@@ -20,6 +20,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientStreamer = new(RemoteClient)
 }
 
 func TestRemoteClientAccessKeyBasic(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -75,6 +76,11 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	// Determine whether to gzip or not
 	gzip := b.configData.Get("gzip").(bool)
 
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build the state client
 	var stateMgr = remote.NewState(
 		&RemoteClient{
@@ -83,7 +89,7 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 			GZip:      gzip,
 			lockState: b.lock,
 		},
-		b.encryption,
+		enc,
 	)
 
 	if !b.lock {
@@ -140,6 +146,11 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	return stateMgr, nil
 }
 
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.path(name)
+}
+
 func (b *Backend) path(name string) string {
 	path := b.configData.Get("path").(string)
 	if name != backend.DefaultStateName {
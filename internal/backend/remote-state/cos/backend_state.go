@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -85,7 +86,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	if err != nil {
 		return nil, err
 	}
-	stateMgr := remote.NewState(c, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMgr := remote.NewState(c, enc)
 
 	ws, err := b.Workspaces()
 	if err != nil {
@@ -165,6 +171,11 @@ func (b *Backend) client(name string) (*remoteClient, error) {
 }
 
 // stateFile returns state file path by name
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.stateFile(name)
+}
+
 func (b *Backend) stateFile(name string) string {
 	if name == backend.DefaultStateName {
 		return path.Join(b.prefix, b.key)
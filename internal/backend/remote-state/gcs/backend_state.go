@@ -15,6 +15,7 @@ import (
 	"google.golang.org/api/iterator"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -98,7 +99,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 		return nil, err
 	}
 
-	st := remote.NewState(c, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	st := remote.NewState(c, enc)
 
 	// Grab the value
 	if err := st.RefreshState(); err != nil {
@@ -151,6 +157,11 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	return st, nil
 }
 
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.stateFile(name)
+}
+
 func (b *Backend) stateFile(name string) string {
 	return path.Join(b.prefix, name+stateFileSuffix)
 }
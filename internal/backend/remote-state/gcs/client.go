@@ -6,6 +6,7 @@
 package gcs
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -79,6 +80,47 @@ func (c *remoteClient) Put(data []byte) error {
 	return nil
 }
 
+// PutStream uploads the state from a reader. The storage client already
+// uploads it in chunks, so only one chunk at a time is held in memory.
+//
+// The checksum of the state is only known once it has been read, so rather
+// than giving it to GCS in advance it is compared with the checksum GCS
+// calculated for the uploaded object.
+//
+// Implements "state/remote".ClientStreamer
+func (c *remoteClient) PutStream(payload *remote.StreamPayload) error {
+	err := func() error {
+		// Cancelling the context aborts the upload if we fail to read
+		// the whole state.
+		ctx, cancel := context.WithCancel(c.storageContext)
+		defer cancel()
+
+		stateFileWriter := c.stateFile().NewWriter(ctx)
+		if len(c.kmsKeyName) > 0 {
+			stateFileWriter.KMSKeyName = c.kmsKeyName
+		}
+		if _, err := io.Copy(stateFileWriter, payload); err != nil {
+			return err
+		}
+		checksums, err := payload.Checksums()
+		if err != nil {
+			return err
+		}
+		if err := stateFileWriter.Close(); err != nil {
+			return err
+		}
+		if attrs := stateFileWriter.Attrs(); attrs != nil && len(attrs.MD5) != 0 && !bytes.Equal(attrs.MD5, checksums.MD5) {
+			return fmt.Errorf("the uploaded state has the MD5 checksum %x, but %x was expected", attrs.MD5, checksums.MD5)
+		}
+		return nil
+	}()
+	if err != nil {
+		return fmt.Errorf("Failed to upload state to %v: %w", c.stateFileURL(), err)
+	}
+
+	return nil
+}
+
 func (c *remoteClient) Delete() error {
 	if err := c.stateFile().Delete(c.storageContext); err != nil {
 		return fmt.Errorf("Failed to delete state file %v: %w", c.stateFileURL(), err)
@@ -308,7 +308,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 		return nil, backend.ErrWorkspacesNotSupported
 	}
 
-	return remote.NewState(b.client, b.encryption), nil
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.NewState(b.client, enc), nil
 }
 
 func (b *Backend) Workspaces() ([]string, error) {
@@ -17,6 +17,7 @@ import (
 	"github.com/aliyun/aliyun-tablestore-go-sdk/tablestore"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -118,7 +119,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	if err != nil {
 		return nil, err
 	}
-	stateMgr := remote.NewState(client, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMgr := remote.NewState(client, enc)
 
 	// Check to see if this state already exists.
 	existing, err := b.Workspaces()
@@ -180,6 +186,11 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	return stateMgr, nil
 }
 
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.stateFile(name)
+}
+
 func (b *Backend) stateFile(name string) string {
 	if name == backend.DefaultStateName {
 		return path.Join(b.statePrefix, b.stateKey)
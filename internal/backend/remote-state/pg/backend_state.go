@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -55,6 +56,11 @@ func (b *Backend) DeleteWorkspace(name string, _ bool) error {
 }
 
 func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build the state client
 	var stateMgr statemgr.Full = remote.NewState(
 		&RemoteClient{
@@ -62,7 +68,7 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 			Name:       name,
 			SchemaName: b.schemaName,
 		},
-		b.encryption,
+		enc,
 	)
 
 	// Check to see if this state already exists.
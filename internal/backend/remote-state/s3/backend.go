@@ -54,6 +54,12 @@ type Backend struct {
 	workspaceKeyPrefix    string
 	skipS3Checksum        bool
 	useLockfile           bool
+
+	// replicaS3Client and replicaBucketName are set only if a replica
+	// bucket is configured, which is then used for reading state when the
+	// primary bucket's region is unavailable.
+	replicaS3Client   *s3.Client
+	replicaBucketName string
 }
 
 // ConfigSchema returns a description of the expected configuration
@@ -459,6 +465,25 @@ See details: https://cs.opensource.google/go/x/net/+/refs/tags/v0.17.0:http/http
 				Optional:    true,
 				Description: "Manage locking in the same configured S3 bucket",
 			},
+			"replica": {
+				Optional: true,
+				NestedType: &configschema.Object{
+					Nesting: configschema.NestingSingle,
+					Attributes: map[string]*configschema.Attribute{
+						"bucket": {
+							Type:        cty.String,
+							Required:    true,
+							Description: "The name of the S3 bucket that the state bucket is replicated to.",
+						},
+						"region": {
+							Type:        cty.String,
+							Required:    true,
+							Description: "AWS region of the replica S3 bucket.",
+						},
+					},
+				},
+				Description: "A replica of the state bucket in another region, which is used for reading state when the region of the state bucket is unavailable.",
+			},
 		},
 	}
 }
@@ -638,6 +663,10 @@ func (b *Backend) PrepareConfig(obj cty.Value) (cty.Value, tfdiags.Diagnostics)
 		endpoint.Validate(obj, &diags)
 	}
 
+	if val := obj.GetAttr("replica"); !val.IsNull() {
+		diags = diags.Append(validateReplica(val, obj, cty.GetAttrPath("replica")))
+	}
+
 	return obj, diags
 }
 
@@ -670,6 +699,18 @@ func (b *Backend) Configure(obj cty.Value) tfdiags.Diagnostics {
 		}
 	}
 
+	if replica := obj.GetAttr("replica"); !replica.IsNull() && !boolAttr(obj, "skip_region_validation") {
+		if err := awsbaseValidation.SupportedRegion(stringAttr(replica, "region")); err != nil {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid replica region value",
+				err.Error(),
+				cty.GetAttrPath("replica").GetAttr("region"),
+			))
+			return diags
+		}
+	}
+
 	b.bucketName = stringAttr(obj, "bucket")
 	b.keyName = stringAttr(obj, "key")
 	b.acl = stringAttr(obj, "acl")
@@ -835,6 +876,13 @@ func (b *Backend) Configure(obj cty.Value) tfdiags.Diagnostics {
 
 	b.s3Client = s3.NewFromConfig(awsConfig, getS3Config(obj))
 
+	if replica := obj.GetAttr("replica"); !replica.IsNull() {
+		b.replicaBucketName = stringAttr(replica, "bucket")
+		b.replicaS3Client = s3.NewFromConfig(awsConfig, getS3Config(obj), func(options *s3.Options) {
+			options.Region = stringAttr(replica, "region")
+		})
+	}
+
 	return diags
 }
 
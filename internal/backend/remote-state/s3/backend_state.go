@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"path"
 	"sort"
 	"strings"
@@ -19,32 +20,47 @@ import (
 	"github.com/aws/smithy-go"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
 func (b *Backend) Workspaces() ([]string, error) {
-	const maxKeys = 1000
-
 	prefix := ""
 
 	if b.workspaceKeyPrefix != "" {
 		prefix = b.workspaceKeyPrefix + "/"
 	}
 
+	ctx := context.TODO()
+
+	ctx, _ = attachLoggerToContext(ctx)
+
+	wss, err := b.listWorkspaces(ctx, b.s3Client, b.bucketName, prefix)
+	if err != nil && b.replicaS3Client != nil && isRegionUnavailableError(err) {
+		log.Printf("[WARN] backend-s3: state bucket %q is unavailable, listing workspaces in replica bucket %q instead: %s", b.bucketName, b.replicaBucketName, err)
+		wss, err = b.listWorkspaces(ctx, b.replicaS3Client, b.replicaBucketName, prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(wss[1:])
+	return wss, nil
+}
+
+func (b *Backend) listWorkspaces(ctx context.Context, s3Client *s3.Client, bucketName, prefix string) ([]string, error) {
+	const maxKeys = 1000
+
 	params := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(b.bucketName),
+		Bucket:  aws.String(bucketName),
 		Prefix:  aws.String(prefix),
 		MaxKeys: aws.Int32(maxKeys),
 	}
 
-	ctx := context.TODO()
-
-	ctx, _ = attachLoggerToContext(ctx)
-
 	wss := []string{backend.DefaultStateName}
-	pg := s3.NewListObjectsV2Paginator(b.s3Client, params)
+	pg := s3.NewListObjectsV2Paginator(s3Client, params)
 
 	for pg.HasMorePages() {
 		page, err := pg.NextPage(ctx)
@@ -72,7 +88,6 @@ func (b *Backend) Workspaces() ([]string, error) {
 		}
 	}
 
-	sort.Strings(wss[1:])
 	return wss, nil
 }
 
@@ -146,6 +161,8 @@ func (b *Backend) remoteClient(name string) (*RemoteClient, error) {
 		ddbTable:              b.ddbTable,
 		skipS3Checksum:        b.skipS3Checksum,
 		useLockfile:           b.useLockfile,
+		replicaS3Client:       b.replicaS3Client,
+		replicaBucketName:     b.replicaBucketName,
 	}
 
 	return client, nil
@@ -157,7 +174,12 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 		return nil, err
 	}
 
-	stateMgr := remote.NewState(client, b.encryption)
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMgr := remote.NewState(client, enc)
 	// Check to see if this state already exists.
 	// If we're trying to force-unlock a state, we can't take the lock before
 	// fetching the state. If the state doesn't exist, we have to assume this
@@ -227,6 +249,11 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	return stateMgr, nil
 }
 
+// StateKey implements backend.StateKeyer.
+func (b *Backend) StateKey(name string) string {
+	return b.path(name)
+}
+
 func (b *Backend) path(name string) string {
 	if name == backend.DefaultStateName {
 		return b.keyName
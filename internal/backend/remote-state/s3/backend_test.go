@@ -771,6 +771,41 @@ func TestBackendConfig_PrepareConfigValidation(t *testing.T) {
 			}),
 			expectedErr: `Invalid Attribute Combination: Only one of endpoints.dynamodb, dynamodb_endpoint can be set.`,
 		},
+		"replica": {
+			config: cty.ObjectVal(map[string]cty.Value{
+				"bucket": cty.StringVal("test"),
+				"key":    cty.StringVal("test"),
+				"region": cty.StringVal("us-west-2"),
+				"replica": cty.ObjectVal(map[string]cty.Value{
+					"bucket": cty.StringVal("test-replica"),
+					"region": cty.StringVal("us-east-1"),
+				}),
+			}),
+		},
+		"replica empty bucket": {
+			config: cty.ObjectVal(map[string]cty.Value{
+				"bucket": cty.StringVal("test"),
+				"key":    cty.StringVal("test"),
+				"region": cty.StringVal("us-west-2"),
+				"replica": cty.ObjectVal(map[string]cty.Value{
+					"bucket": cty.StringVal(""),
+					"region": cty.StringVal("us-east-1"),
+				}),
+			}),
+			expectedErr: `The attribute "replica.bucket" is required by the backend.`,
+		},
+		"replica same as state bucket": {
+			config: cty.ObjectVal(map[string]cty.Value{
+				"bucket": cty.StringVal("test"),
+				"key":    cty.StringVal("test"),
+				"region": cty.StringVal("us-west-2"),
+				"replica": cty.ObjectVal(map[string]cty.Value{
+					"bucket": cty.StringVal("test"),
+					"region": cty.StringVal("us-west-2"),
+				}),
+			}),
+			expectedErr: `The replica must be a different bucket than the state bucket`,
+		},
 	}
 
 	for name, tc := range cases {
@@ -17,6 +17,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/http"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -27,6 +29,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	uuid "github.com/hashicorp/go-uuid"
 
+	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
@@ -39,6 +42,19 @@ const (
 	s3ErrCodeInternalError = "InternalError"
 
 	contentTypeJSON = "application/json"
+
+	// replicaReadOnlyLockID is the lock ID returned when planning without a
+	// lock because the state bucket is unavailable.
+	replicaReadOnlyLockID = "replica-read-only"
+)
+
+// States larger than s3MultipartThreshold are uploaded by PutStream in parts
+// of s3MultipartPartSize bytes. S3 allows at most 10,000 parts, so this
+// supports states of up to about 156 GiB. These are variables only so that
+// tests can use smaller parts.
+var (
+	s3MultipartThreshold int64 = 64 << 20
+	s3MultipartPartSize        = 16 << 20
 )
 
 type RemoteClient struct {
@@ -55,6 +71,17 @@ type RemoteClient struct {
 	skipS3Checksum bool
 
 	useLockfile bool
+
+	// replicaS3Client and replicaBucketName are set if the state bucket is
+	// replicated to a bucket in another region, which is then used for
+	// reading the state when the region of the state bucket is unavailable.
+	replicaS3Client   *s3.Client
+	replicaBucketName string
+
+	// primaryUnavailable is set once the state was read from the replica
+	// or a lock was skipped because the region of the state bucket was
+	// unavailable, after which any changes to the state are refused.
+	primaryUnavailable bool
 }
 
 var (
@@ -76,8 +103,11 @@ func (c *RemoteClient) Get() (payload *remote.Payload, err error) {
 	// If we have a checksum, and the returned payload doesn't match, we retry
 	// up until deadline.
 	for {
-		payload, err = c.get(ctx)
+		payload, err = c.get(ctx, c.s3Client, c.bucketName)
 		if err != nil {
+			if c.replicaS3Client != nil && isRegionUnavailableError(err) {
+				return c.getFromReplica(ctx, err)
+			}
 			return nil, err
 		}
 
@@ -114,14 +144,31 @@ func (c *RemoteClient) Get() (payload *remote.Payload, err error) {
 	return payload, err
 }
 
-func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
+// getFromReplica reads the state from the replica bucket after reading it
+// from the state bucket failed with the given error because its region is
+// unavailable.
+//
+// The replica may lag behind the state bucket, so the state's checksum is
+// not verified and any later attempt to change the state is refused.
+func (c *RemoteClient) getFromReplica(ctx context.Context, primaryErr error) (*remote.Payload, error) {
+	log.Printf("[WARN] backend-s3: state bucket %q is unavailable, reading state from replica bucket %q instead: %s", c.bucketName, c.replicaBucketName, primaryErr)
+
+	payload, err := c.get(ctx, c.replicaS3Client, c.replicaBucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state from the replica bucket %q after the state bucket %q was unavailable: %w", c.replicaBucketName, c.bucketName, err)
+	}
+	c.primaryUnavailable = true
+	return payload, nil
+}
+
+func (c *RemoteClient) get(ctx context.Context, s3Client *s3.Client, bucketName string) (*remote.Payload, error) {
 	var output *s3.GetObjectOutput
 	var err error
 
 	ctx, _ = attachLoggerToContext(ctx)
 
 	inputHead := &s3.HeadObjectInput{
-		Bucket: &c.bucketName,
+		Bucket: &bucketName,
 		Key:    &c.path,
 	}
 
@@ -132,7 +179,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 	}
 
 	// Head works around some s3 compatible backends not handling missing GetObject requests correctly (ex: minio Get returns Missing Bucket)
-	_, err = c.s3Client.HeadObject(ctx, inputHead, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	_, err = s3Client.HeadObject(ctx, inputHead, s3optDisableDefaultChecksum(c.skipS3Checksum))
 	if err != nil {
 		var nb *types.NoSuchBucket
 		if errors.As(err, &nb) {
@@ -148,7 +195,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 	}
 
 	input := &s3.GetObjectInput{
-		Bucket: &c.bucketName,
+		Bucket: &bucketName,
 		Key:    &c.path,
 	}
 
@@ -158,7 +205,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 		input.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
 	}
 
-	output, err = c.s3Client.GetObject(ctx, input, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	output, err = s3Client.GetObject(ctx, input, s3optDisableDefaultChecksum(c.skipS3Checksum))
 	if err != nil {
 		var nb *types.NoSuchBucket
 		if errors.As(err, &nb) {
@@ -195,12 +242,77 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 }
 
 func (c *RemoteClient) Put(data []byte) error {
-	contentLength := int64(len(data))
+	if c.primaryUnavailable {
+		return fmt.Errorf(errPrimaryUnavailableFmt, c.bucketName, "save the state")
+	}
+
+	ctx := context.TODO()
+	ctx, _ = attachLoggerToContext(ctx)
+
+	sha256Sum := sha256.Sum256(data)
+	if err := c.putObject(ctx, bytes.NewReader(data), int64(len(data)), sha256Sum[:]); err != nil {
+		return fmt.Errorf("failed to upload state: %w", err)
+	}
+
+	sum := md5.Sum(data)
+	if err := c.putMD5(ctx, sum[:]); err != nil {
+		// if this errors out, we unfortunately have to error out altogether,
+		// since the next Get will inevitably fail.
+		return fmt.Errorf("failed to store state MD5: %w", err)
+
+	}
+
+	return nil
+}
+
+// PutStream uploads the state from a reader, using a multipart upload if
+// the state is too large to upload comfortably in a single request. The
+// size of the state is only known once it has been read, so up to
+// s3MultipartThreshold bytes are read before deciding how to upload it.
+//
+// Implements remote.ClientStreamer
+func (c *RemoteClient) PutStream(payload *remote.StreamPayload) error {
+	if c.primaryUnavailable {
+		return fmt.Errorf(errPrimaryUnavailableFmt, c.bucketName, "save the state")
+	}
+
+	ctx := context.TODO()
+	ctx, _ = attachLoggerToContext(ctx)
+
+	var head bytes.Buffer
+	_, err := io.CopyN(&head, payload, s3MultipartThreshold+1)
+	switch {
+	case err == io.EOF:
+		sha256Sum := sha256.Sum256(head.Bytes())
+		err = c.putObject(ctx, bytes.NewReader(head.Bytes()), int64(head.Len()), sha256Sum[:])
+	case err != nil:
+		return fmt.Errorf("failed to read state: %w", err)
+	default:
+		err = c.putMultipart(ctx, io.MultiReader(&head, payload))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload state: %w", err)
+	}
+
+	checksums, err := payload.Checksums()
+	if err != nil {
+		return err
+	}
+	if err := c.putMD5(ctx, checksums.MD5); err != nil {
+		// if this errors out, we unfortunately have to error out altogether,
+		// since the next Get will inevitably fail.
+		return fmt.Errorf("failed to store state MD5: %w", err)
+	}
 
+	return nil
+}
+
+// putObject uploads the state in a single request.
+func (c *RemoteClient) putObject(ctx context.Context, body io.Reader, size int64, sha256Sum []byte) error {
 	i := &s3.PutObjectInput{
 		ContentType:   aws.String(contentTypeJSON),
-		ContentLength: aws.Int64(contentLength),
-		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(size),
+		Body:          body,
 		Bucket:        &c.bucketName,
 		Key:           &c.path,
 	}
@@ -211,9 +323,7 @@ func (c *RemoteClient) Put(data []byte) error {
 		// There is a conflict in the aws-go-sdk-v2 that prevents it from working with many s3 compatible services
 		// Since we can pre-compute the hash here, we can work around it.
 		// ref: https://github.com/aws/aws-sdk-go-v2/issues/1689
-		algo := sha256.New()
-		algo.Write(data)
-		sum64str := base64.StdEncoding.EncodeToString(algo.Sum(nil))
+		sum64str := base64.StdEncoding.EncodeToString(sha256Sum)
 		i.ChecksumSHA256 = &sum64str
 	}
 
@@ -236,26 +346,135 @@ func (c *RemoteClient) Put(data []byte) error {
 
 	log.Printf("[DEBUG] Uploading remote state to S3: %#v", i)
 
-	ctx := context.TODO()
-	ctx, _ = attachLoggerToContext(ctx)
-
 	_, err := c.s3Client.PutObject(ctx, i, s3optDisableDefaultChecksum(c.skipS3Checksum))
-	if err != nil {
-		return fmt.Errorf("failed to upload state: %w", err)
+	return err
+}
+
+// putMultipart uploads the state in parts of s3MultipartPartSize bytes, so
+// that only one part at a time is held in memory. If any part fails then the
+// whole upload is aborted, leaving the existing state as it was.
+func (c *RemoteClient) putMultipart(ctx context.Context, body io.Reader) error {
+	i := &s3.CreateMultipartUploadInput{
+		ContentType: aws.String(contentTypeJSON),
+		Bucket:      &c.bucketName,
+		Key:         &c.path,
 	}
 
-	sum := md5.Sum(data)
-	if err := c.putMD5(ctx, sum[:]); err != nil {
-		// if this errors out, we unfortunately have to error out altogether,
-		// since the next Get will inevitably fail.
-		return fmt.Errorf("failed to store state MD5: %w", err)
+	if !c.skipS3Checksum {
+		i.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	}
+
+	if c.serverSideEncryption {
+		if c.kmsKeyID != "" {
+			i.SSEKMSKeyId = &c.kmsKeyID
+			i.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		} else if c.customerEncryptionKey != nil {
+			i.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+			i.SSECustomerAlgorithm = aws.String(string(s3EncryptionAlgorithm))
+			i.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+		} else {
+			i.ServerSideEncryption = s3EncryptionAlgorithm
+		}
+	}
 
+	if c.acl != "" {
+		i.ACL = types.ObjectCannedACL(c.acl)
+	}
+
+	log.Printf("[DEBUG] Starting multipart upload of remote state to S3: %#v", i)
+
+	upload, err := c.s3Client.CreateMultipartUpload(ctx, i, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	if err != nil {
+		return err
+	}
+
+	parts, err := c.uploadParts(ctx, upload.UploadId, body)
+	if err == nil {
+		complete := &s3.CompleteMultipartUploadInput{
+			Bucket:          &c.bucketName,
+			Key:             &c.path,
+			UploadId:        upload.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		}
+		if c.serverSideEncryption && c.kmsKeyID == "" && c.customerEncryptionKey != nil {
+			complete.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+			complete.SSECustomerAlgorithm = aws.String(string(s3EncryptionAlgorithm))
+			complete.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+		}
+		_, err = c.s3Client.CompleteMultipartUpload(ctx, complete, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	}
+	if err != nil {
+		abort := &s3.AbortMultipartUploadInput{
+			Bucket:   &c.bucketName,
+			Key:      &c.path,
+			UploadId: upload.UploadId,
+		}
+		if _, abortErr := c.s3Client.AbortMultipartUpload(ctx, abort); abortErr != nil {
+			log.Printf("[WARN] failed to abort multipart upload %q of remote state: %s", aws.ToString(upload.UploadId), abortErr)
+		}
+		return err
 	}
 
 	return nil
 }
 
+// uploadParts uploads the parts of a multipart upload from the given body,
+// returning the parts to complete the upload with.
+func (c *RemoteClient) uploadParts(ctx context.Context, uploadID *string, body io.Reader) ([]types.CompletedPart, error) {
+	var parts []types.CompletedPart
+	buf := make([]byte, s3MultipartPartSize)
+	for partNumber := int32(1); ; partNumber++ {
+		n, err := io.ReadFull(body, buf)
+		if n == 0 && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("failed to read state: %w", err)
+		}
+		part := buf[:n]
+
+		i := &s3.UploadPartInput{
+			Bucket:        &c.bucketName,
+			Key:           &c.path,
+			UploadId:      uploadID,
+			PartNumber:    aws.Int32(partNumber),
+			ContentLength: aws.Int64(int64(n)),
+			Body:          bytes.NewReader(part),
+		}
+		if !c.skipS3Checksum {
+			// As for a single request, we pre-compute the hash to work
+			// with S3 compatible services.
+			sum := sha256.Sum256(part)
+			i.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+			i.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+		}
+		if c.serverSideEncryption && c.kmsKeyID == "" && c.customerEncryptionKey != nil {
+			i.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+			i.SSECustomerAlgorithm = aws.String(string(s3EncryptionAlgorithm))
+			i.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+		}
+
+		log.Printf("[DEBUG] Uploading part %d of remote state to S3 (%d bytes)", partNumber, n)
+
+		out, err := c.s3Client.UploadPart(ctx, i, s3optDisableDefaultChecksum(c.skipS3Checksum))
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:           out.ETag,
+			PartNumber:     aws.Int32(partNumber),
+			ChecksumSHA256: i.ChecksumSHA256,
+		})
+	}
+
+	return parts, nil
+}
+
 func (c *RemoteClient) Delete() error {
+	if c.primaryUnavailable {
+		return fmt.Errorf(errPrimaryUnavailableFmt, c.bucketName, "delete the state")
+	}
+
 	ctx := context.TODO()
 	ctx, _ = attachLoggerToContext(ctx)
 
@@ -290,18 +509,41 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	info.Path = c.lockPath()
 
 	if err := c.s3Lock(info); err != nil {
-		return "", err
+		return c.lockUnavailable(info, err)
 	}
 	if err := c.dynamoDBLock(info); err != nil {
 		// when the second lock fails from getting acquired, release the initially acquired one
 		if uErr := c.s3Unlock(info.ID); uErr != nil {
 			log.Printf("[WARN] failed to release the S3 lock after failed to acquire the dynamoDD lock: %v", uErr)
 		}
-		return "", err
+		return c.lockUnavailable(info, err)
 	}
 	return info.ID, nil
 }
 
+// lockUnavailable handles a failure to acquire the lock with the given
+// error. If a replica is configured and the lock failed only because the
+// region of the state bucket is unavailable then a plan can still go ahead
+// without the lock, using the state from the replica, since it will not
+// change the state. Any other operation is refused.
+func (c *RemoteClient) lockUnavailable(info *statemgr.LockInfo, lockErr error) (string, error) {
+	cause := lockErr
+	var stateLockErr *statemgr.LockError
+	if errors.As(lockErr, &stateLockErr) {
+		cause = stateLockErr.Err
+	}
+	if c.replicaS3Client == nil || !isRegionUnavailableError(cause) {
+		return "", lockErr
+	}
+	if info.Operation != backend.OperationTypePlan.String() {
+		return "", fmt.Errorf(errPrimaryUnavailableFmt, c.bucketName, "lock the state")
+	}
+
+	log.Printf("[WARN] backend-s3: state bucket %q is unavailable, planning without a state lock: %s", c.bucketName, lockErr)
+	c.primaryUnavailable = true
+	return replicaReadOnlyLockID, nil
+}
+
 // dynamoDBLock expects the statemgr.LockInfo#ID to be filled already
 func (c *RemoteClient) dynamoDBLock(info *statemgr.LockInfo) error {
 	if c.ddbTable == "" {
@@ -525,6 +767,11 @@ func (c *RemoteClient) getLockInfoFromS3(ctx context.Context) (*statemgr.LockInf
 }
 
 func (c *RemoteClient) Unlock(id string) error {
+	if id == replicaReadOnlyLockID {
+		// No lock was acquired, because the state bucket was unavailable.
+		return nil
+	}
+
 	// Attempt to release the lock from both sources.
 	// We want to do so to be sure that we are leaving no locks unhandled
 	s3Err := c.s3Unlock(id)
@@ -620,6 +867,21 @@ func (c *RemoteClient) dynamoDBUnlock(id string) *statemgr.LockError {
 	return nil
 }
 
+// isRegionUnavailableError returns true if the given error from an S3 or
+// DynamoDB request suggests that the region of the service is unavailable,
+// rather than that the request itself was invalid or not allowed.
+func isRegionUnavailableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode() >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 func (c *RemoteClient) lockPath() string {
 	return fmt.Sprintf("%s/%s", c.bucketName, c.path)
 }
@@ -660,6 +922,14 @@ to manually verify the remote state and update the Digest value stored in the
 DynamoDB table to the following value: %x
 `
 
+const errPrimaryUnavailableFmt = `S3 bucket %q is unavailable, so OpenTofu cannot %s.
+
+The region of the state bucket could not be reached. While it is unavailable
+the state can only be read from the replica bucket, which may not include the
+latest changes to the state, so only plans are possible until the state bucket
+is available again.
+`
+
 const errS3NoSuchBucket = `S3 bucket does not exist.
 
 The referenced S3 bucket must have been previously created. If the S3 bucket
@@ -19,6 +19,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/go-cmp/cmp"
 	awsbase "github.com/hashicorp/aws-sdk-go-base/v2"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -30,6 +31,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientStreamer = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -963,6 +965,180 @@ func TestS3LockingWritingHeaders(t *testing.T) {
 	}
 }
 
+func TestRemoteClient_replicaFallback(t *testing.T) {
+	_, awsCfg, _ := awsbase.GetAwsConfig(context.Background(), &awsbase.Config{Region: "us-east-1", AccessKey: "test", SecretKey: "key"})
+	const state = `{"version": 4}`
+	var primaryReqs, replicaReqs int
+	newClient := func(primaryStatus int) *RemoteClient {
+		primaryReqs, replicaReqs = 0, 0
+		primary := s3.NewFromConfig(awsCfg, func(options *s3.Options) {
+			options.RetryMaxAttempts = 1
+			options.HTTPClient = mockHttpClientFunc(func(r *http.Request) (*http.Response, error) {
+				primaryReqs++
+				return &http.Response{StatusCode: primaryStatus, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+			})
+		})
+		replica := s3.NewFromConfig(awsCfg, func(options *s3.Options) {
+			options.Region = "us-west-2"
+			options.HTTPClient = mockHttpClientFunc(func(r *http.Request) (*http.Response, error) {
+				replicaReqs++
+				if got, want := r.URL.Host, "test-bucket-replica.s3.us-west-2.amazonaws.com"; got != want {
+					t.Errorf("wrong replica host %q; want %q", got, want)
+				}
+				return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(state))}, nil
+			})
+		})
+		return &RemoteClient{
+			s3Client:          primary,
+			bucketName:        "test-bucket",
+			path:              "state-file",
+			useLockfile:       true,
+			replicaS3Client:   replica,
+			replicaBucketName: "test-bucket-replica",
+		}
+	}
+
+	t.Run("primary unavailable", func(t *testing.T) {
+		rc := newClient(http.StatusServiceUnavailable)
+
+		planLock := &statemgr.LockInfo{Operation: backend.OperationTypePlan.String()}
+		id, err := rc.Lock(planLock)
+		if err != nil {
+			t.Fatalf("unexpected error locking for a plan: %s", err)
+		}
+		payload, err := rc.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if payload == nil || string(payload.Data) != state {
+			t.Fatalf("wrong payload %#v; want the state from the replica", payload)
+		}
+		if primaryReqs == 0 || replicaReqs == 0 {
+			t.Errorf("made %d requests to the state bucket and %d to the replica; want both", primaryReqs, replicaReqs)
+		}
+		if err := rc.Put([]byte(state)); err == nil || !strings.Contains(err.Error(), "cannot save the state") {
+			t.Errorf("wrong error from Put: %v", err)
+		}
+		if err := rc.Delete(); err == nil || !strings.Contains(err.Error(), "cannot delete the state") {
+			t.Errorf("wrong error from Delete: %v", err)
+		}
+		if err := rc.Unlock(id); err != nil {
+			t.Errorf("unexpected error unlocking: %s", err)
+		}
+
+		applyLock := &statemgr.LockInfo{Operation: backend.OperationTypeApply.String()}
+		if _, err := rc.Lock(applyLock); err == nil || !strings.Contains(err.Error(), "cannot lock the state") {
+			t.Errorf("wrong error from Lock for apply: %v", err)
+		}
+	})
+
+	t.Run("primary access denied", func(t *testing.T) {
+		rc := newClient(http.StatusForbidden)
+
+		if _, err := rc.Get(); err == nil {
+			t.Fatal("expected an error, got none")
+		}
+		if replicaReqs != 0 {
+			t.Errorf("made %d requests to the replica; want none", replicaReqs)
+		}
+		if _, err := rc.Lock(&statemgr.LockInfo{Operation: backend.OperationTypePlan.String()}); err == nil {
+			t.Error("expected an error locking, got none")
+		}
+	})
+}
+
+func TestRemoteClient_putStreamMultipart(t *testing.T) {
+	defer func(threshold int64, partSize int) {
+		s3MultipartThreshold, s3MultipartPartSize = threshold, partSize
+	}(s3MultipartThreshold, s3MultipartPartSize)
+	s3MultipartThreshold, s3MultipartPartSize = 10, 4
+
+	_, awsCfg, _ := awsbase.GetAwsConfig(context.Background(), &awsbase.Config{Region: "us-east-1", AccessKey: "test", SecretKey: "key"})
+	const state = `{"version":4}`
+
+	newClient := func(failPart string) (*RemoteClient, *[]string, map[string]string) {
+		var reqs []string
+		parts := make(map[string]string)
+		client := s3.NewFromConfig(awsCfg, func(options *s3.Options) {
+			options.RetryMaxAttempts = 1
+			options.HTTPClient = mockHttpClientFunc(func(r *http.Request) (*http.Response, error) {
+				query := r.URL.Query()
+				resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+				switch {
+				case r.Method == http.MethodPost && query.Has("uploads"):
+					reqs = append(reqs, "CreateMultipartUpload")
+					resp.Body = io.NopCloser(strings.NewReader(`<InitiateMultipartUploadResult><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`))
+				case r.Method == http.MethodPut && query.Has("partNumber"):
+					partNumber := query.Get("partNumber")
+					reqs = append(reqs, "UploadPart "+partNumber)
+					if partNumber == failPart {
+						resp.StatusCode = http.StatusInternalServerError
+						break
+					}
+					body, _ := io.ReadAll(r.Body)
+					parts[partNumber] = string(body)
+					resp.Header.Set("ETag", `"etag-`+partNumber+`"`)
+				case r.Method == http.MethodPost && query.Get("uploadId") == "upload-id":
+					reqs = append(reqs, "CompleteMultipartUpload")
+					resp.Body = io.NopCloser(strings.NewReader(`<CompleteMultipartUploadResult></CompleteMultipartUploadResult>`))
+				case r.Method == http.MethodDelete && query.Get("uploadId") == "upload-id":
+					reqs = append(reqs, "AbortMultipartUpload")
+					resp.StatusCode = http.StatusNoContent
+				default:
+					reqs = append(reqs, r.Method+" "+r.URL.String())
+				}
+				return resp, nil
+			})
+		})
+		return &RemoteClient{
+			s3Client:   client,
+			bucketName: "test-bucket",
+			path:       "state-file",
+		}, &reqs, parts
+	}
+	payload := func() *remote.StreamPayload {
+		return remote.NewStreamPayload(strings.NewReader(state))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		rc, reqs, parts := newClient("")
+		if err := rc.PutStream(payload()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "UploadPart 3", "UploadPart 4", "CompleteMultipartUpload"}
+		if diff := cmp.Diff(want, *reqs); diff != "" {
+			t.Fatalf("wrong requests\n%s", diff)
+		}
+		if got := parts["1"] + parts["2"] + parts["3"] + parts["4"]; got != state {
+			t.Errorf("wrong parts %#v", parts)
+		}
+	})
+
+	t.Run("failed part", func(t *testing.T) {
+		rc, reqs, _ := newClient("2")
+		if err := rc.PutStream(payload()); err == nil || !strings.Contains(err.Error(), "failed to upload part 2") {
+			t.Fatalf("wrong error: %v", err)
+		}
+		want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "AbortMultipartUpload"}
+		if diff := cmp.Diff(want, *reqs); diff != "" {
+			t.Fatalf("wrong requests\n%s", diff)
+		}
+	})
+
+	t.Run("small state", func(t *testing.T) {
+		s3MultipartThreshold = int64(len(state))
+		defer func() { s3MultipartThreshold = 10 }()
+
+		rc, reqs, _ := newClient("")
+		if err := rc.PutStream(payload()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got, want := *reqs, []string{"PUT https://test-bucket.s3.us-east-1.amazonaws.com/state-file?x-id=PutObject"}; !slices.Equal(got, want) {
+			t.Fatalf("wrong requests %#v; want %#v", got, want)
+		}
+	})
+}
+
 // mockHttpClient is used to test the interaction of the s3 backend with the aws-sdk.
 // This is meant to be configured with a response that will be returned to the aws-sdk.
 // The receivedReq is going to contain the last request received by it.
@@ -975,3 +1151,11 @@ func (m *mockHttpClient) Do(r *http.Request) (*http.Response, error) {
 	m.receivedReq = r
 	return m.resp, nil
 }
+
+// mockHttpClientFunc is used to return a new response for each request, for
+// tests that make more than one request with a body.
+type mockHttpClientFunc func(r *http.Request) (*http.Response, error)
+
+func (f mockHttpClientFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
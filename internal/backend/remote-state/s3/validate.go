@@ -105,6 +105,32 @@ func validateNestedAssumeRole(obj cty.Value, objPath cty.Path) tfdiags.Diagnosti
 	return diags
 }
 
+func validateReplica(obj, backendObj cty.Value, objPath cty.Path) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, name := range []string{"bucket", "region"} {
+		if val, ok := stringAttrOk(obj, name); !ok || val == "" {
+			path := objPath.GetAttr(name)
+			diags = diags.Append(attributeErrDiag(
+				"Missing Required Value",
+				fmt.Sprintf("The attribute %q is required by the backend.\n\n", pathString(path))+
+					"Refer to the backend documentation for additional information which attributes are required.",
+				path,
+			))
+		}
+	}
+
+	if stringAttr(obj, "bucket") == stringAttr(backendObj, "bucket") && stringAttr(obj, "region") == stringAttr(backendObj, "region") {
+		diags = diags.Append(attributeErrDiag(
+			"Invalid replica configuration",
+			"The replica must be a different bucket than the state bucket, usually in another region.",
+			objPath,
+		))
+	}
+
+	return diags
+}
+
 func validateAssumeRoleWithWebIdentity(obj cty.Value, objPath cty.Path) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
@@ -656,6 +656,11 @@ func (b *Remote) StateMgr(name string) (statemgr.Full, error) {
 		return nil, backend.ErrWorkspacesNotSupported
 	}
 
+	enc, err := encryption.StateEncryptionForWorkspace(b.encryption, name)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure the remote workspace name.
 	switch {
 	case name == backend.DefaultStateName:
@@ -708,10 +713,10 @@ func (b *Remote) StateMgr(name string) (statemgr.Full, error) {
 		// This is optionally set during OpenTofu Enterprise runs.
 		runID: os.Getenv("TFE_RUN_ID"),
 
-		encryption: b.encryption,
+		encryption: enc,
 	}
 
-	state := remote.NewState(client, b.encryption)
+	state := remote.NewState(client, enc)
 	if client.runID != "" {
 		// client.runID will be set if we're running a Terraform Cloud
 		// or Terraform Enterprise remote execution environment, in which
@@ -169,6 +169,14 @@ func (b *Remote) opApply(stopCtx, cancelCtx context.Context, op *backend.Operati
 		))
 	}
 
+	if op.FrozenModules {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-frozen-modules option is not supported",
+			"The -frozen-modules option is not currently supported for remote plans.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
@@ -136,6 +136,14 @@ func (b *Remote) opPlan(stopCtx, cancelCtx context.Context, op *backend.Operatio
 		))
 	}
 
+	if op.FrozenModules {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-frozen-modules option is not supported",
+			"The -frozen-modules option is not currently supported for remote plans.",
+		))
+	}
+
 	if !op.PlanRefresh {
 		desiredAPIVersion, _ := version.NewVersion("2.4")
 
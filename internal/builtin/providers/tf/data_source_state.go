@@ -175,6 +175,11 @@ func dataSourceRemoteStateRead(d cty.Value, enc encryption.StateEncryption) (cty
 	mod := remoteState.RootModule()
 	if mod != nil { // should always have a root module in any valid state
 		for k, os := range mod.OutputValues {
+			if os.Internal {
+				// Internal outputs are not part of the public interface
+				// of the configuration.
+				continue
+			}
 			outputs[k] = os.Value
 		}
 	}
@@ -43,7 +43,11 @@ func (p *Provider) GetProviderSchema() providers.GetProviderSchemaResponse {
 			"terraform_remote_state": dataSourceRemoteStateGetSchema(),
 		},
 		ResourceTypes: map[string]providers.Schema{
-			"terraform_data": dataStoreResourceSchema(),
+			"terraform_data":   dataStoreResourceSchema(),
+			"terraform_random": randomResourceSchema(),
+		},
+		EphemeralResources: map[string]providers.Schema{
+			"terraform_workload_identity": workloadIdentitySchema(),
 		},
 		Functions: p.getFunctionSpecs(),
 	}
@@ -100,19 +104,10 @@ func (p *Provider) ReadDataSourceEncrypted(req providers.ReadDataSourceRequest,
 		return res
 	}
 
-	// These string manipulations are kind of funky
-	key := path.String()
-
-	// data.terraform_remote_state.foo[4] -> foo[4]
-	// module.submod[1].data.terraform_remote_state.bar -> module.submod[1].bar
-	key = strings.Replace(key, "data.terraform_remote_state.", "", 1)
-
-	// module.submod[1].bar -> submod[1].bar
-	key = strings.TrimPrefix(key, "module.")
+	keys := remoteStateEncryptionKeys(path)
+	log.Printf("[DEBUG] accessing remote state at %s", keys[0])
 
-	log.Printf("[DEBUG] accessing remote state at %s", key)
-
-	newState, diags := dataSourceRemoteStateRead(req.Config, enc.RemoteState(key))
+	newState, diags := dataSourceRemoteStateRead(req.Config, enc.RemoteState(keys...))
 
 	if diags.HasErrors() {
 		diags = diags.Append(fmt.Errorf("%s: Unable to read remote state", path.String()))
@@ -124,6 +119,40 @@ func (p *Provider) ReadDataSourceEncrypted(req providers.ReadDataSourceRequest,
 	return res
 }
 
+// remoteStateEncryptionKeys returns the names that a remote_state_data_source
+// block in the encryption configuration can use to refer to the given data
+// source instance, from the most to the least specific. This allows a single
+// block to configure the encryption of all instances of a data source that
+// uses count or for_each, or that is declared in a module with multiple
+// instances, while still allowing individual instances to be overridden.
+//
+// For example, for module.submod[1].data.terraform_remote_state.bar[0] these
+// are submod[1].bar[0], submod[1].bar and submod.bar.
+func remoteStateEncryptionKeys(path addrs.AbsResourceInstance) []string {
+	candidates := []string{
+		path.String(),
+		path.ContainingResource().String(),
+		path.ConfigResource().String(),
+	}
+
+	var keys []string
+	for _, key := range candidates {
+		// These string manipulations are kind of funky
+
+		// data.terraform_remote_state.foo[4] -> foo[4]
+		// module.submod[1].data.terraform_remote_state.bar -> module.submod[1].bar
+		key = strings.Replace(key, "data.terraform_remote_state.", "", 1)
+
+		// module.submod[1].bar -> submod[1].bar
+		key = strings.TrimPrefix(key, "module.")
+
+		if len(keys) == 0 || keys[len(keys)-1] != key {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 // Stop is called when the provider should halt any in-flight actions.
 func (p *Provider) Stop() error {
 	log.Println("[DEBUG] terraform provider cannot Stop")
@@ -131,25 +160,35 @@ func (p *Provider) Stop() error {
 }
 
 // All the Resource-specific functions are below.
-// The terraform provider supplies a single data source, `terraform_remote_state`
-// and no resources.
+// The terraform provider supplies a single data source, `terraform_remote_state`,
+// the `terraform_data` and `terraform_random` resources, and the
+// `terraform_workload_identity` ephemeral resource.
 
 // UpgradeResourceState is called when the state loader encounters an
 // instance state whose schema version is less than the one reported by the
 // currently-used version of the corresponding provider, and the upgraded
 // result is used for any further processing.
 func (p *Provider) UpgradeResourceState(req providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	if req.TypeName == "terraform_random" {
+		return upgradeRandomResourceState(req)
+	}
 	return upgradeDataStoreResourceState(req)
 }
 
 // ReadResource refreshes a resource and returns its current state.
 func (p *Provider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	if req.TypeName == "terraform_random" {
+		return readRandomResourceState(req)
+	}
 	return readDataStoreResourceState(req)
 }
 
 // PlanResourceChange takes the current state and proposed state of a
 // resource, and returns the planned final state.
 func (p *Provider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	if req.TypeName == "terraform_random" {
+		return planRandomResourceChange(req)
+	}
 	return planDataStoreResourceChange(req)
 }
 
@@ -157,14 +196,28 @@ func (p *Provider) PlanResourceChange(req providers.PlanResourceChangeRequest) p
 // yet contain unknown computed values, and applies the changes returning
 // the final state.
 func (p *Provider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	if req.TypeName == "terraform_random" {
+		return applyRandomResourceChange(req)
+	}
 	return applyDataStoreResourceChange(req)
 }
 
+// ApplyResourceChanges is not supported, since the provider doesn't declare
+// the ApplyResourceChanges capability.
+func (p *Provider) ApplyResourceChanges(req providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+	var resp providers.ApplyResourceChangesResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported operation ApplyResourceChanges"))
+	return resp
+}
+
 // ImportResourceState requests that the given resource be imported.
 func (p *Provider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
 	if req.TypeName == "terraform_data" {
 		return importDataStore(req)
 	}
+	if req.TypeName == "terraform_random" {
+		return importRandomResource(req)
+	}
 
 	panic("unimplemented - terraform_remote_state has no resources")
 }
@@ -178,9 +231,52 @@ func (p *Provider) MoveResourceState(r providers.MoveResourceStateRequest) (resp
 
 // ValidateResourceConfig is used to validate the resource configuration values.
 func (p *Provider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	if req.TypeName == "terraform_random" {
+		return validateRandomResourceConfig(req)
+	}
 	return validateDataStoreResourceConfig(req)
 }
 
+// ValidateEphemeralResourceConfig is used to validate the ephemeral resource
+// configuration values.
+func (p *Provider) ValidateEphemeralResourceConfig(req providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	if req.TypeName == "terraform_workload_identity" {
+		return validateWorkloadIdentityConfig(req)
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Error: unsupported ephemeral resource %s", req.TypeName))
+	return resp
+}
+
+// OpenEphemeralResource opens an ephemeral resource.
+func (p *Provider) OpenEphemeralResource(req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	if req.TypeName == "terraform_workload_identity" {
+		return openWorkloadIdentity(req)
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Error: unsupported ephemeral resource %s", req.TypeName))
+	return resp
+}
+
+// RenewEphemeralResource renews an open ephemeral resource.
+func (p *Provider) RenewEphemeralResource(req providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	if req.TypeName == "terraform_workload_identity" {
+		// The credentials are exchanged for a fixed lifetime and we never
+		// request a renewal, so there is nothing to do.
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Error: unsupported ephemeral resource %s", req.TypeName))
+	return resp
+}
+
+// CloseEphemeralResource closes an open ephemeral resource.
+func (p *Provider) CloseEphemeralResource(req providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	if req.TypeName == "terraform_workload_identity" {
+		// Exchanged credentials can't be revoked, so they just expire.
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Error: unsupported ephemeral resource %s", req.TypeName))
+	return resp
+}
+
 func (p *Provider) GetFunctions() providers.GetFunctionsResponse {
 	return providers.GetFunctionsResponse{
 		Functions: p.getFunctionSpecs(),
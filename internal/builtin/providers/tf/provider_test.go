@@ -6,6 +6,11 @@
 package tf
 
 import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
 	backendInit "github.com/opentofu/opentofu/internal/backend/init"
 )
 
@@ -13,3 +18,27 @@ func init() {
 	// Initialize the backends
 	backendInit.Init(nil)
 }
+
+func TestRemoteStateEncryptionKeys(t *testing.T) {
+	tests := map[string][]string{
+		"data.terraform_remote_state.foo":                                 {"foo"},
+		"data.terraform_remote_state.foo[4]":                              {"foo[4]", "foo"},
+		`data.terraform_remote_state.foo["a"]`:                            {`foo["a"]`, "foo"},
+		"module.submod.data.terraform_remote_state.bar":                   {"submod.bar"},
+		"module.submod[1].data.terraform_remote_state.bar":                {"submod[1].bar", "submod.bar"},
+		"module.submod[1].data.terraform_remote_state.bar[0]":             {"submod[1].bar[0]", "submod[1].bar", "submod.bar"},
+		`module.submod["x"].module.inner.data.terraform_remote_state.baz`: {`submod["x"].module.inner.baz`, "submod.module.inner.baz"},
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			addr, diags := addrs.ParseAbsResourceInstanceStr(input)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			got := remoteStateEncryptionKeys(addr)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// The terraform_random resource generates its value while planning instead of
+// while applying, so the plan shows the actual value instead of
+// "(known after apply)". The generated value is carried from the plan to the
+// apply in the planned state, which OpenTofu proposes again when planning the
+// change during the apply, and only committed to the state when the plan is
+// applied.
+
+const (
+	randomTypePetname = "petname"
+	randomTypeUUID    = "uuid"
+	randomTypeBytes   = "bytes"
+
+	// maxRandomLength limits the number of words or bytes, since the value is
+	// stored in the plan and the state.
+	maxRandomLength = 1024
+)
+
+// defaultRandomLength contains the default value of the length argument for
+// each type that supports it.
+var defaultRandomLength = map[string]int{
+	randomTypePetname: 2,
+	randomTypeBytes:   32,
+}
+
+func randomResourceSchema() providers.Schema {
+	return providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"type":             {Type: cty.String, Required: true},
+				"length":           {Type: cty.Number, Optional: true},
+				"separator":        {Type: cty.String, Optional: true},
+				"triggers_replace": {Type: cty.DynamicPseudoType, Optional: true},
+				"result":           {Type: cty.String, Computed: true},
+				"sensitive_result": {Type: cty.String, Computed: true, Sensitive: true},
+				"id":               {Type: cty.String, Computed: true},
+			},
+		},
+	}
+}
+
+func validateRandomResourceConfig(req providers.ValidateResourceConfigRequest) (resp providers.ValidateResourceConfigResponse) {
+	if req.Config.IsNull() {
+		return resp
+	}
+
+	// Core does not currently validate computed values are not set in the
+	// configuration.
+	for _, attr := range []string{"id", "result", "sensitive_result"} {
+		if !req.Config.GetAttr(attr).IsNull() {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf(`%q attribute is read-only`, attr))
+		}
+	}
+
+	typ := req.Config.GetAttr("type")
+	if !typ.IsKnown() || typ.IsNull() {
+		return resp
+	}
+	switch typ.AsString() {
+	case randomTypePetname, randomTypeUUID, randomTypeBytes:
+	default:
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid random value type",
+			fmt.Sprintf("The type must be %q, %q or %q.", randomTypePetname, randomTypeUUID, randomTypeBytes),
+			cty.GetAttrPath("type"),
+		))
+		return resp
+	}
+
+	if length := req.Config.GetAttr("length"); length.IsKnown() && !length.IsNull() {
+		if _, ok := defaultRandomLength[typ.AsString()]; !ok {
+			resp.Diagnostics = resp.Diagnostics.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Unsupported argument",
+				fmt.Sprintf("The length argument is not supported for random values of type %q.", typ.AsString()),
+				cty.GetAttrPath("length"),
+			))
+		} else if _, err := randomLength(length, typ.AsString()); err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid length",
+				err.Error(),
+				cty.GetAttrPath("length"),
+			))
+		}
+	}
+	if separator := req.Config.GetAttr("separator"); !separator.IsNull() && typ.AsString() != randomTypePetname {
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Unsupported argument",
+			fmt.Sprintf("The separator argument is only supported for random values of type %q.", randomTypePetname),
+			cty.GetAttrPath("separator"),
+		))
+	}
+	return resp
+}
+
+func upgradeRandomResourceState(req providers.UpgradeResourceStateRequest) (resp providers.UpgradeResourceStateResponse) {
+	ty := randomResourceSchema().Block.ImpliedType()
+	val, err := ctyjson.Unmarshal(req.RawStateJSON, ty)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	resp.UpgradedState = val
+	return resp
+}
+
+func readRandomResourceState(req providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
+	resp.NewState = req.PriorState
+	resp.Private = req.Private
+	return resp
+}
+
+func planRandomResourceChange(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+	if req.ProposedNewState.IsNull() {
+		// destroy op
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+
+	planned := req.ProposedNewState.AsValueMap()
+
+	if !req.PriorState.IsNull() {
+		for _, attr := range []string{"type", "length", "separator", "triggers_replace"} {
+			if !req.PriorState.GetAttr(attr).RawEquals(req.ProposedNewState.GetAttr(attr)) {
+				resp.RequiresReplace = append(resp.RequiresReplace, cty.GetAttrPath(attr))
+			}
+		}
+		if len(resp.RequiresReplace) == 0 {
+			// Nothing changed, so we keep the existing value.
+			resp.PlannedState = req.ProposedNewState
+			resp.PlannedPrivate = req.PriorPrivate
+			return resp
+		}
+	} else if id := req.ProposedNewState.GetAttr("id"); id.IsKnown() && !id.IsNull() {
+		// The id can't be configured, so if it's proposed for a new object,
+		// this is the apply phase and OpenTofu proposes the values generated
+		// during the plan phase again. We keep them, so the final plan
+		// matches the plan the user approved.
+		resp.PlannedState = req.ProposedNewState
+		return resp
+	}
+
+	// Create or replace. If the arguments are not known yet, we cannot
+	// generate the value until the apply.
+	if !req.ProposedNewState.IsWhollyKnown() {
+		planned["result"] = cty.UnknownVal(cty.String)
+		planned["sensitive_result"] = cty.UnknownVal(cty.String)
+		if typ := req.ProposedNewState.GetAttr("type"); typ.IsKnown() {
+			set, unset := randomResultAttrs(typ.AsString())
+			planned[set] = cty.UnknownVal(cty.String).RefineNotNull()
+			planned[unset] = cty.NullVal(cty.String)
+		}
+		planned["id"] = cty.UnknownVal(cty.String).RefineNotNull()
+		resp.PlannedState = cty.ObjectVal(planned)
+		return resp
+	}
+
+	if err := setRandomValue(planned); err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.PlannedState = cty.ObjectVal(planned)
+	return resp
+}
+
+func applyRandomResourceChange(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+	if req.PlannedState.IsNull() {
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	resp.Private = req.PlannedPrivate
+	if req.PlannedState.GetAttr("id").IsKnown() {
+		// The value was generated during the plan.
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	// The arguments were not known during the plan.
+	newState := req.PlannedState.AsValueMap()
+	if err := setRandomValue(newState); err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.NewState = cty.ObjectVal(newState)
+	return resp
+}
+
+func importRandomResource(req providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("%s does not support import, as the import ID does not contain its arguments", req.TypeName))
+	return resp
+}
+
+// randomLength returns the length argument, or the default length of the
+// given type if it is not set.
+func randomLength(val cty.Value, typ string) (int, error) {
+	if val.IsNull() {
+		return defaultRandomLength[typ], nil
+	}
+	var length int
+	if err := gocty.FromCtyValue(val, &length); err != nil {
+		return 0, fmt.Errorf("the length must be a whole number")
+	}
+	if length < 1 || length > maxRandomLength {
+		return 0, fmt.Errorf("the length must be between 1 and %d", maxRandomLength)
+	}
+	return length, nil
+}
+
+// randomResultAttrs returns the name of the attribute that contains the
+// value generated for the given type, and the name of the one that is null.
+// Random bytes can be used as secrets, so they are in the sensitive_result
+// attribute instead of result.
+func randomResultAttrs(typ string) (set, unset string) {
+	if typ == randomTypeBytes {
+		return "sensitive_result", "result"
+	}
+	return "result", "sensitive_result"
+}
+
+// setRandomValue generates a random value as described by the attributes of
+// the given terraform_random object, and sets it in the attributes together
+// with a new id. The id is a random UUID rather than the value itself, so it
+// never reveals the value.
+func setRandomValue(attrs map[string]cty.Value) error {
+	typ := attrs["type"].AsString()
+	result, err := generateRandomValue(cty.ObjectVal(attrs))
+	if err != nil {
+		return err
+	}
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	set, unset := randomResultAttrs(typ)
+	attrs[set] = cty.StringVal(result)
+	attrs[unset] = cty.NullVal(cty.String)
+	attrs["id"] = cty.StringVal(id)
+	return nil
+}
+
+// generateRandomValue generates a random value as described by the given
+// terraform_random object.
+func generateRandomValue(obj cty.Value) (string, error) {
+	typ := obj.GetAttr("type").AsString()
+	length, err := randomLength(obj.GetAttr("length"), typ)
+	if err != nil {
+		return "", err
+	}
+
+	switch typ {
+	case randomTypePetname:
+		separator := "-"
+		if sep := obj.GetAttr("separator"); !sep.IsNull() {
+			separator = sep.AsString()
+		}
+		return randomPetname(length, separator)
+	case randomTypeUUID:
+		if testUUIDHook != nil {
+			return testUUIDHook(), nil
+		}
+		return uuid.GenerateUUID()
+	case randomTypeBytes:
+		buf := make([]byte, length)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(buf), nil
+	default:
+		return "", fmt.Errorf("unsupported random value type %q", typ)
+	}
+}
+
+// randomPetname returns the given number of random words joined by the
+// separator, with a name last and adjectives before it, such as
+// "brave-otter".
+func randomPetname(words int, separator string) (string, error) {
+	parts := make([]string, words)
+	for i := range parts {
+		list := petnameAdjectives
+		if i == words-1 {
+			list = petnameNames
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(list))))
+		if err != nil {
+			return "", err
+		}
+		parts[i] = list[n.Int64()]
+	}
+	return strings.Join(parts, separator), nil
+}
+
+var petnameAdjectives = []string{
+	"able", "amazing", "amused", "bold", "brave", "bright", "calm", "careful",
+	"charming", "clever", "cool", "cosmic", "crisp", "curious", "daring", "eager",
+	"easy", "epic", "fair", "famous", "fancy", "fast", "fine", "fond",
+	"free", "fresh", "gentle", "glad", "golden", "good", "grand", "happy",
+	"helpful", "honest", "humble", "jolly", "keen", "kind", "lively", "loyal",
+	"lucky", "merry", "mighty", "modest", "neat", "nice", "noble", "patient",
+	"polite", "proud", "quick", "quiet", "rapid", "ready", "sharp", "shy",
+	"smart", "social", "solid", "steady", "sunny", "swift", "tidy", "vivid",
+	"warm", "wise", "witty", "young", "zealous",
+}
+
+var petnameNames = []string{
+	"alpaca", "badger", "beagle", "beaver", "bison", "bobcat", "camel", "cheetah",
+	"condor", "corgi", "coyote", "crane", "dingo", "dolphin", "eagle", "falcon",
+	"ferret", "finch", "fox", "gazelle", "gecko", "gibbon", "gopher", "heron",
+	"hippo", "ibex", "iguana", "jackal", "jaguar", "kiwi", "koala", "lemur",
+	"leopard", "llama", "lynx", "marmot", "mole", "moose", "narwhal", "ocelot",
+	"orca", "osprey", "otter", "owl", "panda", "panther", "pelican", "penguin",
+	"puffin", "quail", "rabbit", "raven", "robin", "salmon", "seal", "sloth",
+	"sparrow", "squid", "stork", "tapir", "tiger", "toucan", "turtle", "walrus",
+	"weasel", "whale", "wombat", "yak", "zebra",
+}
@@ -0,0 +1,358 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func testRandomConfig(attrs map[string]cty.Value) cty.Value {
+	cfg := map[string]cty.Value{
+		"type":             cty.NullVal(cty.String),
+		"length":           cty.NullVal(cty.Number),
+		"separator":        cty.NullVal(cty.String),
+		"triggers_replace": cty.NullVal(cty.DynamicPseudoType),
+		"result":           cty.NullVal(cty.String),
+		"sensitive_result": cty.NullVal(cty.String),
+		"id":               cty.NullVal(cty.String),
+	}
+	for name, val := range attrs {
+		cfg[name] = val
+	}
+	return cty.ObjectVal(cfg)
+}
+
+func TestManagedRandomValidate(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]cty.Value
+		err    string
+	}{
+		"petname": {
+			config: map[string]cty.Value{
+				"type":      cty.StringVal("petname"),
+				"length":    cty.NumberIntVal(3),
+				"separator": cty.StringVal("_"),
+			},
+		},
+		"uuid": {
+			config: map[string]cty.Value{
+				"type": cty.StringVal("uuid"),
+			},
+		},
+		"unknown type": {
+			config: map[string]cty.Value{
+				"type": cty.UnknownVal(cty.String),
+			},
+		},
+		"invalid type": {
+			config: map[string]cty.Value{
+				"type": cty.StringVal("password"),
+			},
+			err: "The type must be",
+		},
+		"uuid length": {
+			config: map[string]cty.Value{
+				"type":   cty.StringVal("uuid"),
+				"length": cty.NumberIntVal(3),
+			},
+			err: "length argument is not supported",
+		},
+		"invalid length": {
+			config: map[string]cty.Value{
+				"type":   cty.StringVal("bytes"),
+				"length": cty.NumberIntVal(0),
+			},
+			err: "length must be between",
+		},
+		"fractional length": {
+			config: map[string]cty.Value{
+				"type":   cty.StringVal("bytes"),
+				"length": cty.NumberFloatVal(1.5),
+			},
+			err: "length must be a whole number",
+		},
+		"bytes separator": {
+			config: map[string]cty.Value{
+				"type":      cty.StringVal("bytes"),
+				"separator": cty.StringVal("-"),
+			},
+			err: "separator argument is only supported",
+		},
+		"computed result": {
+			config: map[string]cty.Value{
+				"type":   cty.StringVal("uuid"),
+				"result": cty.StringVal("oops"),
+			},
+			err: "attribute is read-only",
+		},
+		"computed sensitive result": {
+			config: map[string]cty.Value{
+				"type":             cty.StringVal("bytes"),
+				"sensitive_result": cty.StringVal("oops"),
+			},
+			err: "attribute is read-only",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := validateRandomResourceConfig(providers.ValidateResourceConfigRequest{
+				TypeName: "terraform_random",
+				Config:   testRandomConfig(test.config),
+			})
+			if test.err == "" {
+				if resp.Diagnostics.HasErrors() {
+					t.Fatal("unexpected error:", resp.Diagnostics.ErrWithWarnings())
+				}
+				return
+			}
+			if !resp.Diagnostics.HasErrors() {
+				t.Fatal("expected error")
+			}
+			if msg := resp.Diagnostics.ErrWithWarnings().Error(); !strings.Contains(msg, test.err) {
+				t.Fatalf("wrong error %q, want %q", msg, test.err)
+			}
+		})
+	}
+}
+
+func TestManagedRandomPlanApply(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]cty.Value
+		// attr is the attribute that contains the generated value.
+		attr string
+		want *regexp.Regexp
+	}{
+		"petname": {
+			config: map[string]cty.Value{
+				"type": cty.StringVal("petname"),
+			},
+			attr: "result",
+			want: regexp.MustCompile(`^[a-z]+-[a-z]+$`),
+		},
+		"petname length and separator": {
+			config: map[string]cty.Value{
+				"type":      cty.StringVal("petname"),
+				"length":    cty.NumberIntVal(3),
+				"separator": cty.StringVal("_"),
+			},
+			attr: "result",
+			want: regexp.MustCompile(`^[a-z]+_[a-z]+_[a-z]+$`),
+		},
+		"uuid": {
+			config: map[string]cty.Value{
+				"type": cty.StringVal("uuid"),
+			},
+			attr: "result",
+			want: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+		},
+		"bytes": {
+			config: map[string]cty.Value{
+				"type":   cty.StringVal("bytes"),
+				"length": cty.NumberIntVal(16),
+			},
+			attr: "sensitive_result",
+			want: regexp.MustCompile(`^[A-Za-z0-9+/]{22}==$`),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testRandomConfig(test.config)
+			planResp := planRandomResourceChange(providers.PlanResourceChangeRequest{
+				TypeName:         "terraform_random",
+				PriorState:       cty.NullVal(config.Type()),
+				ProposedNewState: config,
+				Config:           config,
+			})
+			if planResp.Diagnostics.HasErrors() {
+				t.Fatal(planResp.Diagnostics.ErrWithWarnings())
+			}
+
+			// The value must be known during the plan.
+			result := planResp.PlannedState.GetAttr(test.attr)
+			if !result.IsKnown() || result.IsNull() {
+				t.Fatalf("%s is not known during the plan: %#v", test.attr, result)
+			}
+			if !test.want.MatchString(result.AsString()) {
+				t.Fatalf("unexpected %s %q", test.attr, result.AsString())
+			}
+			for _, attr := range []string{"result", "sensitive_result"} {
+				if attr != test.attr && !planResp.PlannedState.GetAttr(attr).IsNull() {
+					t.Fatalf("%s must be null", attr)
+				}
+			}
+			id := planResp.PlannedState.GetAttr("id")
+			if !id.IsKnown() || id.IsNull() || id.RawEquals(result) {
+				t.Fatalf("the id must be known and differ from the value: %#v", id)
+			}
+
+			// When planning again during the apply, OpenTofu proposes the
+			// values from the plan, which must be kept.
+			replanResp := planRandomResourceChange(providers.PlanResourceChangeRequest{
+				TypeName:         "terraform_random",
+				PriorState:       cty.NullVal(config.Type()),
+				ProposedNewState: planResp.PlannedState,
+				Config:           config,
+			})
+			if replanResp.Diagnostics.HasErrors() {
+				t.Fatal(replanResp.Diagnostics.ErrWithWarnings())
+			}
+			if !replanResp.PlannedState.RawEquals(planResp.PlannedState) {
+				t.Fatalf("the values from the plan were not kept\nreplanned: %#v\nplanned:   %#v", replanResp.PlannedState, planResp.PlannedState)
+			}
+
+			applyResp := applyRandomResourceChange(providers.ApplyResourceChangeRequest{
+				TypeName:       "terraform_random",
+				PriorState:     cty.NullVal(config.Type()),
+				PlannedState:   planResp.PlannedState,
+				Config:         config,
+				PlannedPrivate: planResp.PlannedPrivate,
+			})
+			if applyResp.Diagnostics.HasErrors() {
+				t.Fatal(applyResp.Diagnostics.ErrWithWarnings())
+			}
+			if !applyResp.NewState.RawEquals(planResp.PlannedState) {
+				t.Fatalf("the applied state does not match the plan\napplied: %#v\nplanned: %#v", applyResp.NewState, planResp.PlannedState)
+			}
+		})
+	}
+}
+
+func TestManagedRandomPlanUpdate(t *testing.T) {
+	prior := testRandomConfig(map[string]cty.Value{
+		"type":   cty.StringVal("petname"),
+		"result": cty.StringVal("brave-otter"),
+		"id":     cty.StringVal("0f6c2b7e-5d2a-4b8e-9c43-7a1e4f3d2b10"),
+	})
+	priorPrivate := []byte(`private`)
+
+	// Without changes, the existing value is kept.
+	resp := planRandomResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "terraform_random",
+		PriorState:       prior,
+		ProposedNewState: prior,
+		PriorPrivate:     priorPrivate,
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.ErrWithWarnings())
+	}
+	if len(resp.RequiresReplace) != 0 {
+		t.Fatalf("unexpected replacement: %#v", resp.RequiresReplace)
+	}
+	if !resp.PlannedState.RawEquals(prior) {
+		t.Fatalf("unexpected planned state: %#v", resp.PlannedState)
+	}
+
+	// Changing an argument requires a replacement.
+	proposed := testRandomConfig(map[string]cty.Value{
+		"type":   cty.StringVal("petname"),
+		"length": cty.NumberIntVal(3),
+		"result": cty.StringVal("brave-otter"),
+		"id":     cty.StringVal("0f6c2b7e-5d2a-4b8e-9c43-7a1e4f3d2b10"),
+	})
+	resp = planRandomResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "terraform_random",
+		PriorState:       prior,
+		ProposedNewState: proposed,
+		PriorPrivate:     priorPrivate,
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.ErrWithWarnings())
+	}
+	if len(resp.RequiresReplace) != 1 || !resp.RequiresReplace[0].Equals(cty.GetAttrPath("length")) {
+		t.Fatalf("wrong replacement paths: %#v", resp.RequiresReplace)
+	}
+}
+
+func TestManagedRandomUnknownConfig(t *testing.T) {
+	config := testRandomConfig(map[string]cty.Value{
+		"type":   cty.StringVal("bytes"),
+		"length": cty.UnknownVal(cty.Number),
+	})
+	planResp := planRandomResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "terraform_random",
+		PriorState:       cty.NullVal(config.Type()),
+		ProposedNewState: config,
+		Config:           config,
+	})
+	if planResp.Diagnostics.HasErrors() {
+		t.Fatal(planResp.Diagnostics.ErrWithWarnings())
+	}
+	if planResp.PlannedState.GetAttr("sensitive_result").IsKnown() {
+		t.Fatal("sensitive_result must not be known during the plan when the arguments are unknown")
+	}
+	if !planResp.PlannedState.GetAttr("result").IsNull() {
+		t.Fatal("result must be null for random bytes")
+	}
+
+	// The value is generated during the apply instead.
+	applyConfig := testRandomConfig(map[string]cty.Value{
+		"type":   cty.StringVal("bytes"),
+		"length": cty.NumberIntVal(8),
+	})
+	planned := applyConfig.AsValueMap()
+	planned["sensitive_result"] = cty.UnknownVal(cty.String)
+	planned["id"] = cty.UnknownVal(cty.String)
+	applyResp := applyRandomResourceChange(providers.ApplyResourceChangeRequest{
+		TypeName:     "terraform_random",
+		PriorState:   cty.NullVal(config.Type()),
+		PlannedState: cty.ObjectVal(planned),
+		Config:       applyConfig,
+	})
+	if applyResp.Diagnostics.HasErrors() {
+		t.Fatal(applyResp.Diagnostics.ErrWithWarnings())
+	}
+	decoded, err := base64.StdEncoding.DecodeString(applyResp.NewState.GetAttr("sensitive_result").AsString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 8 {
+		t.Fatalf("wrong number of bytes %d", len(decoded))
+	}
+}
+
+func TestManagedRandomSensitiveBytes(t *testing.T) {
+	attr := randomResourceSchema().Block.Attributes["sensitive_result"]
+	if !attr.Sensitive {
+		t.Fatal("sensitive_result must be sensitive")
+	}
+	if randomResourceSchema().Block.Attributes["result"].Sensitive {
+		t.Fatal("result must not be sensitive, so the plan shows it")
+	}
+}
+
+func TestManagedRandomPlanKeepsPlannedValue(t *testing.T) {
+	config := testRandomConfig(map[string]cty.Value{
+		"type": cty.StringVal("uuid"),
+	})
+
+	// When planning again during the apply, OpenTofu proposes the values
+	// from the plan phase, which must be kept.
+	proposed := testRandomConfig(map[string]cty.Value{
+		"type":   cty.StringVal("uuid"),
+		"result": cty.StringVal("c5e5c7e0-76ab-4d26-a6b2-1ba0b2c2b1a8"),
+		"id":     cty.StringVal("0f6c2b7e-5d2a-4b8e-9c43-7a1e4f3d2b10"),
+	})
+	resp := planRandomResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         "terraform_random",
+		PriorState:       cty.NullVal(config.Type()),
+		ProposedNewState: proposed,
+		Config:           config,
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.ErrWithWarnings())
+	}
+	if !resp.PlannedState.RawEquals(proposed) {
+		t.Fatalf("the planned values were not kept: %#v", resp.PlannedState)
+	}
+}
@@ -0,0 +1,553 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// The terraform_workload_identity ephemeral resource exchanges a workload
+// identity token, such as a SPIFFE JWT-SVID or a GitHub Actions OIDC token,
+// for short-lived cloud credentials using a security token service, so that
+// the credentials can be passed to a provider configuration without storing
+// long-lived secrets. It's deliberately not offered as a function, because
+// function results are neither sensitive nor ephemeral and functions are
+// also called while validating and planning.
+
+const (
+	// workloadIdentityProtocolTokenExchange is the OAuth 2.0 Token Exchange
+	// protocol from RFC 8693, which is supported by the security token
+	// services of Google Cloud, Azure AD and many identity providers.
+	workloadIdentityProtocolTokenExchange = "token_exchange"
+
+	// workloadIdentityProtocolAWSSTS is the AssumeRoleWithWebIdentity action
+	// of the AWS Security Token Service.
+	workloadIdentityProtocolAWSSTS = "aws_sts"
+
+	workloadIdentityDefaultSessionName = "opentofu"
+
+	// workloadIdentityTimeout limits how long we wait for each request to
+	// the token service or the identity token issuer.
+	workloadIdentityTimeout = 30 * time.Second
+
+	// workloadIdentityResponseLimit limits the size of the responses that we
+	// read, since a token response is never large.
+	workloadIdentityResponseLimit = 1 << 20
+)
+
+// workloadIdentityArguments are the arguments of the ephemeral resource.
+var workloadIdentityArguments = map[string]*configschema.Attribute{
+	"endpoint": {
+		Type:        cty.String,
+		Required:    true,
+		Description: "The URL of the security token service.",
+	},
+	"protocol": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: `The protocol of the security token service: "token_exchange" (the default) or "aws_sts".`,
+	},
+	"token": {
+		Type:        cty.String,
+		Optional:    true,
+		Sensitive:   true,
+		Description: "The workload identity token to exchange.",
+	},
+	"token_file": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: "The path of a file containing the workload identity token to exchange, such as a SPIFFE JWT-SVID.",
+	},
+	"github_actions_audience": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: "Request a GitHub Actions OIDC token with this audience to exchange.",
+	},
+	"audience": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: "The audience of the requested credentials, for the token_exchange protocol.",
+	},
+	"scopes": {
+		Type:        cty.List(cty.String),
+		Optional:    true,
+		Description: "The scopes of the requested credentials, for the token_exchange protocol.",
+	},
+	"role_arn": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: "The ARN of the role to assume, for the aws_sts protocol.",
+	},
+	"session_name": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: "The name of the role session, for the aws_sts protocol.",
+	},
+	"duration": {
+		Type:        cty.String,
+		Optional:    true,
+		Description: `How long the requested credentials should be valid for, like "1h".`,
+	},
+}
+
+// workloadIdentityResults are the attributes that hold the credentials, which
+// are those that are not arguments.
+var workloadIdentityResults = map[string]*configschema.Attribute{
+	"access_token":      {Type: cty.String, Computed: true, Sensitive: true},
+	"token_type":        {Type: cty.String, Computed: true},
+	"access_key_id":     {Type: cty.String, Computed: true},
+	"secret_access_key": {Type: cty.String, Computed: true, Sensitive: true},
+	"session_token":     {Type: cty.String, Computed: true, Sensitive: true},
+	"expires_at":        {Type: cty.String, Computed: true},
+}
+
+func workloadIdentitySchema() providers.Schema {
+	attrs := make(map[string]*configschema.Attribute, len(workloadIdentityArguments)+len(workloadIdentityResults))
+	for name, attr := range workloadIdentityArguments {
+		attrs[name] = attr
+	}
+	for name, attr := range workloadIdentityResults {
+		attrs[name] = attr
+	}
+	return providers.Schema{
+		Block: &configschema.Block{
+			Attributes: attrs,
+		},
+	}
+}
+
+// workloadIdentityRequest is the decoded form of the arguments.
+type workloadIdentityRequest struct {
+	Endpoint              string
+	Protocol              string
+	Token                 string
+	TokenFile             string
+	GitHubActionsAudience string
+	Audience              string
+	Scopes                []string
+	RoleARN               string
+	SessionName           string
+	Duration              time.Duration
+}
+
+// workloadIdentityCredentials are the credentials returned by a security
+// token service. Only the fields that apply to the protocol are set.
+type workloadIdentityCredentials struct {
+	AccessToken     string
+	TokenType       string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+// decodeWorkloadIdentityRequest decodes and validates the arguments in the
+// given object, which must be wholly known.
+func decodeWorkloadIdentityRequest(obj cty.Value) (*workloadIdentityRequest, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	str := func(name string) string {
+		if !obj.Type().HasAttribute(name) {
+			return ""
+		}
+		v := obj.GetAttr(name)
+		if v.IsNull() {
+			return ""
+		}
+		return v.AsString()
+	}
+	invalid := func(name, summary, detail string) {
+		diags = diags.Append(tfdiags.AttributeValue(tfdiags.Error, summary, detail, cty.GetAttrPath(name)))
+	}
+
+	req := &workloadIdentityRequest{
+		Endpoint:              str("endpoint"),
+		Protocol:              str("protocol"),
+		Token:                 str("token"),
+		TokenFile:             str("token_file"),
+		GitHubActionsAudience: str("github_actions_audience"),
+		Audience:              str("audience"),
+		RoleARN:               str("role_arn"),
+		SessionName:           str("session_name"),
+	}
+	if obj.Type().HasAttribute("scopes") {
+		if scopes := obj.GetAttr("scopes"); !scopes.IsNull() {
+			for it := scopes.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				if v.IsNull() {
+					invalid("scopes", "Invalid scopes", "The scopes must not be null.")
+					continue
+				}
+				req.Scopes = append(req.Scopes, v.AsString())
+			}
+		}
+	}
+
+	if req.Endpoint == "" {
+		invalid("endpoint", "Missing security token service endpoint", "The endpoint argument is required.")
+	} else if u, err := url.Parse(req.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		// The workload identity token is sent to the endpoint, and the
+		// credentials returned, so we never allow plain HTTP.
+		invalid("endpoint", "Invalid security token service endpoint", "The endpoint must be an absolute HTTPS URL.")
+	}
+
+	if req.Protocol == "" {
+		req.Protocol = workloadIdentityProtocolTokenExchange
+	}
+	switch req.Protocol {
+	case workloadIdentityProtocolTokenExchange:
+		if req.RoleARN != "" || req.SessionName != "" {
+			invalid("role_arn", "Unsupported argument", fmt.Sprintf("The role_arn and session_name arguments are only supported with the %q protocol.", workloadIdentityProtocolAWSSTS))
+		}
+	case workloadIdentityProtocolAWSSTS:
+		if req.RoleARN == "" {
+			invalid("role_arn", "Missing role ARN", fmt.Sprintf("The role_arn argument is required with the %q protocol.", workloadIdentityProtocolAWSSTS))
+		}
+		if req.Audience != "" || len(req.Scopes) != 0 {
+			invalid("audience", "Unsupported argument", fmt.Sprintf("The audience and scopes arguments are only supported with the %q protocol.", workloadIdentityProtocolTokenExchange))
+		}
+		if req.SessionName == "" {
+			req.SessionName = workloadIdentityDefaultSessionName
+		}
+	default:
+		invalid("protocol", "Invalid security token service protocol", fmt.Sprintf("The protocol must be %q or %q.", workloadIdentityProtocolTokenExchange, workloadIdentityProtocolAWSSTS))
+	}
+
+	sources := 0
+	for _, s := range []string{req.Token, req.TokenFile, req.GitHubActionsAudience} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		invalid("token", "Invalid workload identity token source", "Exactly one of the token, token_file and github_actions_audience arguments must be set.")
+	}
+
+	if d := str("duration"); d != "" {
+		duration, err := time.ParseDuration(d)
+		if err != nil || duration <= 0 {
+			invalid("duration", "Invalid duration", `The duration must be a positive duration, like "1h" or "15m".`)
+		}
+		req.Duration = duration
+	}
+
+	return req, diags
+}
+
+// newWorkloadIdentityClient returns the HTTP client to use for the requests
+// of an ephemeral resource. It's a variable so that tests can trust their own
+// TLS servers.
+var newWorkloadIdentityClient = func() *http.Client {
+	client := httpclient.New()
+	client.Timeout = workloadIdentityTimeout
+	return client
+}
+
+// exchangeWorkloadIdentity obtains the workload identity token described by
+// the given request and exchanges it for cloud credentials.
+func exchangeWorkloadIdentity(ctx context.Context, client *http.Client, req *workloadIdentityRequest) (*workloadIdentityCredentials, error) {
+	token, err := workloadIdentityToken(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Protocol {
+	case workloadIdentityProtocolAWSSTS:
+		return exchangeWorkloadIdentityAWSSTS(ctx, client, req, token)
+	default:
+		return exchangeWorkloadIdentityTokenExchange(ctx, client, req, token)
+	}
+}
+
+// workloadIdentityToken returns the workload identity token to exchange.
+func workloadIdentityToken(ctx context.Context, client *http.Client, req *workloadIdentityRequest) (string, error) {
+	switch {
+	case req.Token != "":
+		return req.Token, nil
+
+	case req.TokenFile != "":
+		// Token files are typically kept up to date by a separate agent,
+		// such as the SPIFFE helper or the kubelet, so we read the file
+		// every time we need a token.
+		src, err := os.ReadFile(req.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading workload identity token: %w", err)
+		}
+		token := strings.TrimSpace(string(src))
+		if token == "" {
+			return "", fmt.Errorf("workload identity token file %s is empty", req.TokenFile)
+		}
+		return token, nil
+
+	default:
+		return githubActionsIDToken(ctx, client, req.GitHubActionsAudience)
+	}
+}
+
+// githubActionsIDToken requests an OIDC token with the given audience from
+// GitHub Actions, which makes its token endpoint available to jobs that have
+// the "id-token: write" permission.
+func githubActionsIDToken(ctx context.Context, client *http.Client, audience string) (string, error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", errors.New("GitHub Actions OIDC tokens are not available: this must run in a GitHub Actions job with the \"id-token: write\" permission")
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+requestToken)
+	httpReq.Header.Set("Accept", "application/json")
+
+	body, err := doWorkloadIdentityRequest(client, httpReq)
+	if err != nil {
+		return "", fmt.Errorf("requesting GitHub Actions OIDC token: %w", err)
+	}
+	var resp struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Value == "" {
+		return "", errors.New("requesting GitHub Actions OIDC token: invalid response")
+	}
+	return resp.Value, nil
+}
+
+func exchangeWorkloadIdentityTokenExchange(ctx context.Context, client *http.Client, req *workloadIdentityRequest, token string) (*workloadIdentityCredentials, error) {
+	form := url.Values{
+		"grant_type":           {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"subject_token":        {token},
+		"subject_token_type":   {"urn:ietf:params:oauth:token-type:jwt"},
+		"requested_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+	}
+	if req.Audience != "" {
+		form.Set("audience", req.Audience)
+	}
+	if len(req.Scopes) != 0 {
+		form.Set("scope", strings.Join(req.Scopes, " "))
+	}
+	if req.Duration != 0 {
+		// Not part of RFC 8693, but supported by some token services.
+		form.Set("expires_in", strconv.Itoa(int(req.Duration.Seconds())))
+	}
+
+	body, err := postWorkloadIdentityForm(ctx, client, req.Endpoint, form)
+	if err != nil {
+		var resp struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		var httpErr workloadIdentityHTTPError
+		if errors.As(err, &httpErr) && json.Unmarshal(httpErr.body, &resp) == nil && resp.Error != "" {
+			if resp.ErrorDescription != "" {
+				return nil, fmt.Errorf("token exchange failed: %s: %s", resp.Error, resp.ErrorDescription)
+			}
+			return nil, fmt.Errorf("token exchange failed: %s", resp.Error)
+		}
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("token exchange failed: invalid response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return nil, errors.New("token exchange failed: the response has no access token")
+	}
+	ret := &workloadIdentityCredentials{
+		AccessToken: resp.AccessToken,
+		TokenType:   resp.TokenType,
+	}
+	if resp.ExpiresIn > 0 {
+		ret.Expiration = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second).UTC().Truncate(time.Second)
+	}
+	return ret, nil
+}
+
+func exchangeWorkloadIdentityAWSSTS(ctx context.Context, client *http.Client, req *workloadIdentityRequest, token string) (*workloadIdentityCredentials, error) {
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {req.RoleARN},
+		"RoleSessionName":  {req.SessionName},
+		"WebIdentityToken": {token},
+	}
+	if req.Duration != 0 {
+		form.Set("DurationSeconds", strconv.Itoa(int(req.Duration.Seconds())))
+	}
+
+	body, err := postWorkloadIdentityForm(ctx, client, req.Endpoint, form)
+	if err != nil {
+		var resp struct {
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		var httpErr workloadIdentityHTTPError
+		if errors.As(err, &httpErr) && xml.Unmarshal(httpErr.body, &resp) == nil && resp.Error.Code != "" {
+			return nil, fmt.Errorf("AssumeRoleWithWebIdentity failed: %s: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return nil, fmt.Errorf("AssumeRoleWithWebIdentity failed: %w", err)
+	}
+
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("AssumeRoleWithWebIdentity failed: invalid response: %w", err)
+	}
+	creds := resp.Credentials
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("AssumeRoleWithWebIdentity failed: the response has no credentials")
+	}
+	return &workloadIdentityCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expiration:      creds.Expiration.UTC(),
+	}, nil
+}
+
+// workloadIdentityHTTPError is returned for unsuccessful responses, so that
+// callers can extract protocol-specific error details from the body.
+type workloadIdentityHTTPError struct {
+	status string
+	body   []byte
+}
+
+func (e workloadIdentityHTTPError) Error() string {
+	return fmt.Sprintf("unexpected response: %s", e.status)
+}
+
+func postWorkloadIdentityForm(ctx context.Context, client *http.Client, endpoint string, form url.Values) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json, application/xml")
+	return doWorkloadIdentityRequest(client, httpReq)
+}
+
+func doWorkloadIdentityRequest(client *http.Client, httpReq *http.Request) ([]byte, error) {
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, workloadIdentityResponseLimit))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, workloadIdentityHTTPError{status: resp.Status, body: body}
+	}
+	return body, nil
+}
+
+// object returns the attributes in workloadIdentityResults for the
+// credentials, with null for those that the
+// protocol doesn't return.
+func (c *workloadIdentityCredentials) object() map[string]cty.Value {
+	str := func(s string) cty.Value {
+		if s == "" {
+			return cty.NullVal(cty.String)
+		}
+		return cty.StringVal(s)
+	}
+	expiresAt := cty.NullVal(cty.String)
+	if !c.Expiration.IsZero() {
+		expiresAt = cty.StringVal(c.Expiration.Format(time.RFC3339))
+	}
+	return map[string]cty.Value{
+		"access_token":      str(c.AccessToken),
+		"token_type":        str(c.TokenType),
+		"access_key_id":     str(c.AccessKeyID),
+		"secret_access_key": str(c.SecretAccessKey),
+		"session_token":     str(c.SessionToken),
+		"expires_at":        expiresAt,
+	}
+}
+
+func validateWorkloadIdentityConfig(req providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	if req.Config.IsNull() || !req.Config.IsWhollyKnown() {
+		// We'll check again when opening the ephemeral resource.
+		return resp
+	}
+	for name := range workloadIdentityResults {
+		if !req.Config.GetAttr(name).IsNull() {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf(`%q attribute is read-only`, name))
+		}
+	}
+	_, diags := decodeWorkloadIdentityRequest(req.Config)
+	resp.Diagnostics = resp.Diagnostics.Append(diags)
+	return resp
+}
+
+func openWorkloadIdentity(req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	wiReq, diags := decodeWorkloadIdentityRequest(req.Config)
+	resp.Diagnostics = resp.Diagnostics.Append(diags)
+	if diags.HasErrors() {
+		return resp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workloadIdentityTimeout)
+	defer cancel()
+	creds, err := exchangeWorkloadIdentity(ctx, newWorkloadIdentityClient(), wiReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to exchange workload identity token",
+			fmt.Sprintf("Could not obtain credentials from %s: %s.", wiReq.Endpoint, err),
+		))
+		return resp
+	}
+
+	vals := req.Config.AsValueMap()
+	for name, v := range creds.object() {
+		vals[name] = v
+	}
+	resp.Result = cty.ObjectVal(vals)
+	return resp
+}
@@ -0,0 +1,335 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func testWorkloadIdentityConfig(attrs map[string]cty.Value) cty.Value {
+	cfg := workloadIdentitySchema().Block.EmptyValue().AsValueMap()
+	for name, val := range attrs {
+		cfg[name] = val
+	}
+	return cty.ObjectVal(cfg)
+}
+
+// newWorkloadIdentityTestServer starts a TLS server for the given handler and
+// makes the ephemeral resource trust it until the end of the test.
+func newWorkloadIdentityTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	prev := newWorkloadIdentityClient
+	newWorkloadIdentityClient = server.Client
+	t.Cleanup(func() {
+		newWorkloadIdentityClient = prev
+	})
+	return server
+}
+
+func TestWorkloadIdentityValidate(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]cty.Value
+		err    string
+	}{
+		"token exchange": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.example.com/token"),
+				"token":    cty.StringVal("jwt"),
+				"audience": cty.StringVal("example"),
+				"scopes":   cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			},
+		},
+		"aws sts": {
+			config: map[string]cty.Value{
+				"endpoint":   cty.StringVal("https://sts.amazonaws.com/"),
+				"protocol":   cty.StringVal("aws_sts"),
+				"token_file": cty.StringVal("/run/spiffe/jwt"),
+				"role_arn":   cty.StringVal("arn:aws:iam::123456789012:role/example"),
+				"duration":   cty.StringVal("15m"),
+			},
+		},
+		"unknown": {
+			config: map[string]cty.Value{
+				"endpoint": cty.UnknownVal(cty.String),
+				"token":    cty.StringVal("jwt"),
+			},
+		},
+		"invalid endpoint": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("sts.example.com"),
+				"token":    cty.StringVal("jwt"),
+			},
+			err: "The endpoint must be an absolute HTTPS URL",
+		},
+		"http endpoint": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("http://sts.example.com/token"),
+				"token":    cty.StringVal("jwt"),
+			},
+			err: "The endpoint must be an absolute HTTPS URL",
+		},
+		"invalid protocol": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.example.com/token"),
+				"protocol": cty.StringVal("saml"),
+				"token":    cty.StringVal("jwt"),
+			},
+			err: "The protocol must be",
+		},
+		"no token source": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.example.com/token"),
+			},
+			err: "Exactly one of the token, token_file and github_actions_audience",
+		},
+		"two token sources": {
+			config: map[string]cty.Value{
+				"endpoint":   cty.StringVal("https://sts.example.com/token"),
+				"token":      cty.StringVal("jwt"),
+				"token_file": cty.StringVal("/run/spiffe/jwt"),
+			},
+			err: "Exactly one of the token, token_file and github_actions_audience",
+		},
+		"aws sts without role": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.amazonaws.com/"),
+				"protocol": cty.StringVal("aws_sts"),
+				"token":    cty.StringVal("jwt"),
+			},
+			err: "The role_arn argument is required",
+		},
+		"aws sts with scopes": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.amazonaws.com/"),
+				"protocol": cty.StringVal("aws_sts"),
+				"token":    cty.StringVal("jwt"),
+				"role_arn": cty.StringVal("arn:aws:iam::123456789012:role/example"),
+				"scopes":   cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			},
+			err: "only supported with the \"token_exchange\" protocol",
+		},
+		"invalid duration": {
+			config: map[string]cty.Value{
+				"endpoint": cty.StringVal("https://sts.example.com/token"),
+				"token":    cty.StringVal("jwt"),
+				"duration": cty.StringVal("forever"),
+			},
+			err: "The duration must be a positive duration",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := validateWorkloadIdentityConfig(providers.ValidateEphemeralResourceConfigRequest{
+				TypeName: "terraform_workload_identity",
+				Config:   testWorkloadIdentityConfig(test.config),
+			})
+			if test.err == "" {
+				if resp.Diagnostics.HasErrors() {
+					t.Fatalf("unexpected error: %s", resp.Diagnostics.Err())
+				}
+				return
+			}
+			if !resp.Diagnostics.HasErrors() {
+				t.Fatalf("expected error containing %q, got none", test.err)
+			}
+			if got := resp.Diagnostics.Err().Error(); !strings.Contains(got, test.err) {
+				t.Fatalf("expected error containing %q, got %q", test.err, got)
+			}
+		})
+	}
+}
+
+func TestWorkloadIdentityOpen_tokenExchange(t *testing.T) {
+	server := newWorkloadIdentityTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid request: %s", err)
+		}
+		want := map[string]string{
+			"grant_type":         "urn:ietf:params:oauth:grant-type:token-exchange",
+			"subject_token":      "spiffe-jwt",
+			"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+			"audience":           "//iam.example.com/pool",
+			"scope":              "read write",
+		}
+		for name, value := range want {
+			if got := r.PostForm.Get(name); got != value {
+				t.Errorf("wrong %s %q; want %q", name, got, value)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"exchanged","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(tokenFile, []byte("spiffe-jwt\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := openWorkloadIdentity(providers.OpenEphemeralResourceRequest{
+		TypeName: "terraform_workload_identity",
+		Config: testWorkloadIdentityConfig(map[string]cty.Value{
+			"endpoint":   cty.StringVal(server.URL),
+			"token_file": cty.StringVal(tokenFile),
+			"audience":   cty.StringVal("//iam.example.com/pool"),
+			"scopes":     cty.ListVal([]cty.Value{cty.StringVal("read"), cty.StringVal("write")}),
+		}),
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error: %s", resp.Diagnostics.Err())
+	}
+
+	if got, want := resp.Result.GetAttr("access_token"), cty.StringVal("exchanged"); !got.RawEquals(want) {
+		t.Errorf("wrong access_token %#v; want %#v", got, want)
+	}
+	if got, want := resp.Result.GetAttr("token_type"), cty.StringVal("Bearer"); !got.RawEquals(want) {
+		t.Errorf("wrong token_type %#v; want %#v", got, want)
+	}
+	if got := resp.Result.GetAttr("expires_at"); got.IsNull() {
+		t.Errorf("expires_at is null")
+	}
+	if got := resp.Result.GetAttr("access_key_id"); !got.IsNull() {
+		t.Errorf("access_key_id is %#v; want null", got)
+	}
+	if got, want := resp.Result.GetAttr("token_file"), cty.StringVal(tokenFile); !got.RawEquals(want) {
+		t.Errorf("wrong token_file %#v; want %#v", got, want)
+	}
+}
+
+func TestWorkloadIdentityOpen_tokenExchangeError(t *testing.T) {
+	server := newWorkloadIdentityTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"The token has expired."}`)
+	}))
+	defer server.Close()
+
+	resp := openWorkloadIdentity(providers.OpenEphemeralResourceRequest{
+		TypeName: "terraform_workload_identity",
+		Config: testWorkloadIdentityConfig(map[string]cty.Value{
+			"endpoint": cty.StringVal(server.URL),
+			"token":    cty.StringVal("expired"),
+		}),
+	})
+	if !resp.Diagnostics.HasErrors() {
+		t.Fatal("expected an error, got none")
+	}
+	want := "token exchange failed: invalid_grant: The token has expired."
+	if got := resp.Diagnostics[0].Description().Detail; !strings.Contains(got, want) {
+		t.Fatalf("wrong error %q; want it to contain %q", got, want)
+	}
+}
+
+func TestWorkloadIdentityOpen_awsSTS(t *testing.T) {
+	server := newWorkloadIdentityTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid request: %s", err)
+		}
+		want := map[string]string{
+			"Action":           "AssumeRoleWithWebIdentity",
+			"RoleArn":          "arn:aws:iam::123456789012:role/example",
+			"RoleSessionName":  "opentofu",
+			"WebIdentityToken": "github-jwt",
+			"DurationSeconds":  "900",
+		}
+		for name, value := range want {
+			if got := r.PostForm.Get(name); got != value {
+				t.Errorf("wrong %s %q; want %q", name, got, value)
+			}
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>AKIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2030-01-02T03:04:05Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer server.Close()
+
+	resp := openWorkloadIdentity(providers.OpenEphemeralResourceRequest{
+		TypeName: "terraform_workload_identity",
+		Config: testWorkloadIdentityConfig(map[string]cty.Value{
+			"endpoint": cty.StringVal(server.URL),
+			"protocol": cty.StringVal("aws_sts"),
+			"token":    cty.StringVal("github-jwt"),
+			"role_arn": cty.StringVal("arn:aws:iam::123456789012:role/example"),
+			"duration": cty.StringVal("15m"),
+		}),
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error: %s", resp.Diagnostics.Err())
+	}
+
+	want := map[string]cty.Value{
+		"access_key_id":     cty.StringVal("AKIAEXAMPLE"),
+		"secret_access_key": cty.StringVal("secret"),
+		"session_token":     cty.StringVal("session"),
+		"expires_at":        cty.StringVal("2030-01-02T03:04:05Z"),
+		"access_token":      cty.NullVal(cty.String),
+	}
+	for name, wantVal := range want {
+		if got := resp.Result.GetAttr(name); !got.RawEquals(wantVal) {
+			t.Errorf("wrong %s %#v; want %#v", name, got, wantVal)
+		}
+	}
+}
+
+func TestWorkloadIdentityOpen_githubActions(t *testing.T) {
+	sts := newWorkloadIdentityTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.FormValue("subject_token"), "github-jwt"; got != want {
+			t.Errorf("wrong subject_token %q; want %q", got, want)
+		}
+		fmt.Fprint(w, `{"access_token":"exchanged","token_type":"Bearer"}`)
+	}))
+	defer sts.Close()
+
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer request-token"; got != want {
+			t.Errorf("wrong Authorization header %q; want %q", got, want)
+		}
+		if got, want := r.URL.Query().Get("audience"), "sts.example.com"; got != want {
+			t.Errorf("wrong audience %q; want %q", got, want)
+		}
+		fmt.Fprint(w, `{"value":"github-jwt"}`)
+	}))
+	defer github.Close()
+
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", github.URL+"/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	resp := openWorkloadIdentity(providers.OpenEphemeralResourceRequest{
+		TypeName: "terraform_workload_identity",
+		Config: testWorkloadIdentityConfig(map[string]cty.Value{
+			"endpoint":                cty.StringVal(sts.URL),
+			"github_actions_audience": cty.StringVal("sts.example.com"),
+		}),
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error: %s", resp.Diagnostics.Err())
+	}
+	if got, want := resp.Result.GetAttr("access_token"), cty.StringVal("exchanged"); !got.RawEquals(want) {
+		t.Errorf("wrong access_token %#v; want %#v", got, want)
+	}
+	if got := resp.Result.GetAttr("expires_at"); !got.IsNull() {
+		t.Errorf("expires_at is %#v; want null", got)
+	}
+}
@@ -6,6 +6,7 @@
 package file
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -47,6 +48,16 @@ func (p *provisioner) GetSchema() (resp provisioners.GetSchemaResponse) {
 				Optional: true,
 			},
 
+			"template_file": {
+				Type:     cty.String,
+				Optional: true,
+			},
+
+			"template_vars": {
+				Type:     cty.DynamicPseudoType,
+				Optional: true,
+			},
+
 			"destination": {
 				Type:     cty.String,
 				Required: true,
@@ -65,14 +76,29 @@ func (p *provisioner) ValidateProvisionerConfig(req provisioners.ValidateProvisi
 
 	source := cfg.GetAttr("source")
 	content := cfg.GetAttr("content")
+	templateFile := cfg.GetAttr("template_file")
+	templateVars := cfg.GetAttr("template_vars")
 
 	switch {
 	case !source.IsNull() && !content.IsNull():
 		resp.Diagnostics = resp.Diagnostics.Append(errors.New("Cannot set both 'source' and 'content'"))
 		return resp
-	case source.IsNull() && content.IsNull():
-		resp.Diagnostics = resp.Diagnostics.Append(errors.New("Must provide one of 'source' or 'content'"))
+	case !templateFile.IsNull() && !(source.IsNull() && content.IsNull()):
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New("Cannot combine 'template_file' with 'source' or 'content'"))
 		return resp
+	case source.IsNull() && content.IsNull() && templateFile.IsNull():
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New("Must provide one of 'source', 'content' or 'template_file'"))
+		return resp
+	case templateFile.IsNull() && !templateVars.IsNull():
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New("'template_vars' can only be used with 'template_file'"))
+		return resp
+	}
+
+	if templateVars.IsKnown() && !templateVars.IsNull() {
+		if ty := templateVars.Type(); !(ty.IsObjectType() || ty.IsMapType()) {
+			resp.Diagnostics = resp.Diagnostics.Append(errors.New("'template_vars' must be a map or an object"))
+			return resp
+		}
 	}
 
 	return resp
@@ -99,7 +125,7 @@ func (p *provisioner) ProvisionResource(req provisioners.ProvisionResourceReques
 	}
 
 	// Get the source
-	src, deleteSource, err := getSrc(req.Config)
+	src, deleteSource, err := getSrc(req.Config, req.TemplateRenderer)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.WholeContainingBody(
 			tfdiags.Error,
@@ -127,11 +153,23 @@ func (p *provisioner) ProvisionResource(req provisioners.ProvisionResourceReques
 }
 
 // getSrc returns the file to use as source
-func getSrc(v cty.Value) (string, bool, error) {
+func getSrc(v cty.Value, renderer provisioners.TemplateRenderer) (string, bool, error) {
 	content := v.GetAttr("content")
 	src := v.GetAttr("source")
+	templateFile := cty.NullVal(cty.String)
+	if v.Type().HasAttribute("template_file") {
+		templateFile = v.GetAttr("template_file")
+	}
 
 	switch {
+	case !templateFile.IsNull():
+		vars := cty.NullVal(cty.DynamicPseudoType)
+		if v.Type().HasAttribute("template_vars") {
+			vars = v.GetAttr("template_vars")
+		}
+		path, err := renderTemplateFile(renderer, templateFile.AsString(), vars)
+		return path, true, err
+
 	case !content.IsNull():
 		file, err := os.CreateTemp("", "tf-file-content")
 		if err != nil {
@@ -153,6 +191,33 @@ func getSrc(v cty.Value) (string, bool, error) {
 	}
 }
 
+// renderTemplateFile renders the given template file into a temporary file
+// and returns its name. The renderer writes the template piece by piece, so
+// the rendered result never needs to be held in memory as a whole.
+func renderTemplateFile(renderer provisioners.TemplateRenderer, path string, vars cty.Value) (string, error) {
+	if renderer == nil {
+		return "", errors.New("'template_file' is not supported when the file provisioner runs as an external plugin")
+	}
+	if vars.IsNull() {
+		vars = cty.EmptyObjectVal
+	}
+
+	file, err := os.CreateTemp("", "tf-file-content")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if err := renderer.RenderTemplateFile(w, path, vars); err != nil {
+		return file.Name(), fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		return file.Name(), err
+	}
+	return file.Name(), file.Close()
+}
+
 // copyFiles is used to copy the files from a source to a destination
 func copyFiles(ctx context.Context, comm communicator.Communicator, src, dst string) error {
 	retryCtx, cancel := context.WithTimeout(ctx, comm.Timeout())
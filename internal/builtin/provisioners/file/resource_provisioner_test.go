@@ -6,6 +6,9 @@
 package file
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"strings"
 	"testing"
 
@@ -146,3 +149,105 @@ func TestResourceProvisioner_nullSrcVars(t *testing.T) {
 		t.Fatalf("file provisioner error: source and content cannot both be null' error: got %q", got)
 	}
 }
+
+func TestResourceProvider_Validate_good_template_file(t *testing.T) {
+	v := cty.ObjectVal(map[string]cty.Value{
+		"template_file": cty.StringVal("app.conf.tftpl"),
+		"template_vars": cty.ObjectVal(map[string]cty.Value{
+			"port": cty.NumberIntVal(8080),
+		}),
+		"destination": cty.StringVal("/tmp/bar"),
+	})
+
+	resp := New().ValidateProvisionerConfig(provisioners.ValidateProvisionerConfigRequest{
+		Config: v,
+	})
+
+	if len(resp.Diagnostics) > 0 {
+		t.Fatal(resp.Diagnostics.ErrWithWarnings())
+	}
+}
+
+func TestResourceProvider_Validate_bad_template_file(t *testing.T) {
+	tests := map[string]cty.Value{
+		"with content": cty.ObjectVal(map[string]cty.Value{
+			"template_file": cty.StringVal("app.conf.tftpl"),
+			"content":       cty.StringVal("value to copy"),
+			"destination":   cty.StringVal("/tmp/bar"),
+		}),
+		"vars without template": cty.ObjectVal(map[string]cty.Value{
+			"content":       cty.StringVal("value to copy"),
+			"template_vars": cty.EmptyObjectVal,
+			"destination":   cty.StringVal("/tmp/bar"),
+		}),
+		"vars not a map": cty.ObjectVal(map[string]cty.Value{
+			"template_file": cty.StringVal("app.conf.tftpl"),
+			"template_vars": cty.StringVal("nope"),
+			"destination":   cty.StringVal("/tmp/bar"),
+		}),
+	}
+
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := New().ValidateProvisionerConfig(provisioners.ValidateProvisionerConfigRequest{
+				Config: v,
+			})
+
+			if !resp.Diagnostics.HasErrors() {
+				t.Fatal("Should have errors")
+			}
+		})
+	}
+}
+
+func TestGetSrc_templateFile(t *testing.T) {
+	cfg := cty.ObjectVal(map[string]cty.Value{
+		"source":        cty.NullVal(cty.String),
+		"content":       cty.NullVal(cty.String),
+		"template_file": cty.StringVal("app.conf.tftpl"),
+		"template_vars": cty.ObjectVal(map[string]cty.Value{
+			"port": cty.NumberIntVal(8080),
+		}),
+		"destination": cty.StringVal("/tmp/bar"),
+	})
+
+	renderer := &testTemplateRenderer{}
+	src, deleteSource, err := getSrc(cfg, renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(src)
+	if !deleteSource {
+		t.Error("rendered template should be deleted after upload")
+	}
+	if renderer.path != "app.conf.tftpl" {
+		t.Errorf("wrong template path %q", renderer.path)
+	}
+	if !renderer.vars.RawEquals(cfg.GetAttr("template_vars")) {
+		t.Errorf("wrong template vars %#v", renderer.vars)
+	}
+
+	got, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "rendered app.conf.tftpl\n"; string(got) != want {
+		t.Errorf("wrong rendered content\ngot:  %q\nwant: %q", got, want)
+	}
+
+	if _, _, err := getSrc(cfg, nil); err == nil {
+		t.Error("expected an error without a template renderer")
+	}
+}
+
+type testTemplateRenderer struct {
+	path string
+	vars cty.Value
+}
+
+func (r *testTemplateRenderer) RenderTemplateFile(w io.Writer, path string, vars cty.Value) error {
+	r.path = path
+	r.vars = vars
+	_, err := fmt.Fprintf(w, "rendered %s\n", path)
+	return err
+}
@@ -86,6 +86,14 @@ func (b *Cloud) opApply(stopCtx, cancelCtx context.Context, op *backend.Operatio
 		))
 	}
 
+	if op.FrozenModules {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-frozen-modules option is not supported",
+			"The -frozen-modules option is not currently supported for remote plans.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
@@ -87,6 +87,14 @@ func (b *Cloud) opPlan(stopCtx, cancelCtx context.Context, op *backend.Operation
 		))
 	}
 
+	if op.FrozenModules {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-frozen-modules option is not supported",
+			"The -frozen-modules option is not currently supported for remote plans.",
+		))
+	}
+
 	if len(op.GenerateConfigOut) > 0 {
 		diags = diags.Append(genconfig.ValidateTargetFile(op.GenerateConfigOut))
 	}
@@ -13,7 +13,9 @@ import (
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -30,6 +32,7 @@ type ApplyCommand struct {
 func (c *ApplyCommand) Run(rawArgs []string) int {
 	var diags tfdiags.Diagnostics
 	ctx := c.CommandContext()
+	historyArgs := rawArgs
 
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
@@ -88,8 +91,10 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
-	// Check for invalid combination of plan file and variable overrides
-	if planFile != nil && !args.Vars.Empty() {
+	// Check for invalid combination of plan file and variable overrides.
+	// Local plan files don't include the values of ephemeral variables, so
+	// the local backend checks that only those are set when applying them.
+	if planFile != nil && !planFile.IsLocal() && !args.Vars.Empty() {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Can't set variables when applying a saved plan",
@@ -111,6 +116,9 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.schedule = args.Operation.Schedule
+	c.Meta.quarantineAfter = args.Operation.QuarantineAfter
+	c.Meta.runTimeout = args.Operation.RunTimeout
 
 	// Prepare the backend, passing the plan file if present, and the
 	// backend-specific arguments
@@ -134,9 +142,21 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	}
 	diags = nil
 
+	// Record the operation in the history of the workspace
+	operation := "apply"
+	if c.Destroy || opReq.PlanMode == plans.DestroyMode {
+		operation = "destroy"
+	}
+	hist := c.startHistory(be, operation, historyArgs)
+	opReq.Hooks = append(opReq.Hooks, hist)
+
 	// Run the operation
 	op, diags := c.RunOperation(ctx, be, opReq)
+	hist.Finish(op, diags)
 	view.Diagnostics(diags)
+	if hook := c.notificationHook(); hook != nil {
+		hook.ApplyComplete(ctx, !diags.HasErrors() && op.Result == backend.OperationSuccess)
+	}
 	if diags.HasErrors() {
 		return 1
 	}
@@ -150,7 +170,7 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	if rb, isRemoteBackend := be.(BackendWithRemoteTerraformVersion); !isRemoteBackend || rb.IsLocalOperations() {
 		view.ResourceCount(args.State.StateOutPath)
 		if !c.Destroy && op.State != nil {
-			view.Outputs(op.State.RootModule().OutputValues)
+			view.Outputs(states.PublicOutputValues(op.State.RootModule().OutputValues))
 		}
 	}
 
@@ -282,11 +302,17 @@ func (c *ApplyCommand) OperationRequest(
 	opReq.ConfigDir = "."
 	opReq.PlanMode = args.PlanMode
 	opReq.Hooks = view.Hooks()
+	if hook := c.notificationHook(); hook != nil {
+		opReq.Hooks = append(opReq.Hooks, hook)
+	}
 	opReq.PlanFile = planFile
 	opReq.PlanRefresh = args.Refresh
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
 	opReq.ForceReplace = args.ForceReplace
+	opReq.FrozenModules = args.FrozenModules
+	opReq.UnfrozenModules = args.UnfrozenModules
+	opReq.Reencrypt = args.Reencrypt
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
 
@@ -370,12 +396,39 @@ Options:
                          The command "tofu destroy" is a convenience alias
                          for this option.
 
+  -frozen-modules        Fail the plan if the source code of a module has
+                         changed since it was last applied. OpenTofu records
+                         the source code of each module instance in the
+                         state when applying.
+
+  -unfreeze=module       Allow the source code of the given module, and the
+                         modules nested in it, to change when -frozen-modules
+                         is set. You can use this option multiple times to
+                         unfreeze more than one module.
+
   -lock=false            Don't hold a state lock during the operation. This is
                          dangerous if others might concurrently run commands
                          against the same workspace.
 
   -lock-timeout=0s       Duration to retry a state lock.
 
+  -lock-retry-delay=1s   Delay before the first retry of a state lock held by
+                         another process. Overrides the CLI configuration.
+
+  -lock-retry-max-delay=16s
+                         Maximum delay between retries of a state lock.
+
+  -lock-retry-multiplier=2
+                         Factor to increase the delay by after each retry.
+
+  -lock-retry-jitter=0   Fraction between 0 and 1 to randomly vary each delay
+                         by.
+
+  -lock-retry-max-attempts=0
+                         Maximum number of attempts to acquire a state lock.
+                         Defaults to no limit; -lock-timeout limits the total
+                         time.
+
   -input=true            Ask for input for variables if not directly set.
 
   -no-color              If specified, output won't contain any color.
@@ -385,6 +438,27 @@ Options:
   -parallelism=n         Limit the number of parallel resource operations.
                          Defaults to 10.
 
+  -schedule=policy       Order in which operations that are ready to run
+                         are started when all parallelism slots are busy:
+                         "fifo" (the default), "fair" to share the slots
+                         between modules, or "critical-path" to start the
+                         longest dependency chains first.
+
+  -run-timeout=duration  Stop gracefully, as if interrupted, when the run takes
+                         longer than the given duration, such as "90m".
+                         Changes applied before then are saved in the state.
+
+  -reencrypt             Write the state back encrypted with the primary
+                         encryption configuration, even if there are no
+                         changes to apply. Use this after changing the state
+                         encryption configuration.
+
+  -quarantine-after=n    Quarantine each resource instance that has failed to
+                         apply in n consecutive runs, so that future plans
+                         skip it until it's released with "tofu state
+                         unquarantine". Defaults to 0, which disables
+                         counting the failures.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
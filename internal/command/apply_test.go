@@ -954,6 +954,86 @@ func TestApply_planVars(t *testing.T) {
 	}
 }
 
+func TestApply_planEphemeralVars(t *testing.T) {
+	_, snap := testModuleWithSnapshot(t, "apply-ephemeral-vars")
+	plannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.UnknownVal(cty.String),
+		"ami": cty.StringVal("bar"),
+	})
+	priorValRaw, err := plans.NewDynamicValue(cty.NullVal(plannedVal.Type()), plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plannedValRaw, err := plans.NewDynamicValue(plannedVal, plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := testPlan(t)
+	plan.Changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr: addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: "foo",
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		ProviderAddr: addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		ChangeSrc: plans.ChangeSrc{
+			Action: plans.Create,
+			Before: priorValRaw,
+			After:  plannedValRaw,
+		},
+	})
+	planPath := testPlanFile(t, snap, states.NewState(), plan)
+
+	tests := map[string]struct {
+		vars    []string
+		wantErr string
+	}{
+		"ephemeral variable set": {
+			vars: []string{"-var", "token=secret"},
+		},
+		"ephemeral variable not set": {
+			wantErr: "No value for required variable",
+		},
+		"non-ephemeral variable set": {
+			vars:    []string{"-var", "token=secret", "-var", "foo=baz"},
+			wantErr: "Can't set variables when applying a saved plan",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			statePath := testTempFile(t)
+
+			p := applyFixtureProvider()
+			view, done := testView(t)
+			c := &ApplyCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					View:             view,
+				},
+			}
+
+			args := append([]string{"-state-out", statePath}, test.vars...)
+			code := c.Run(append(args, planPath))
+			output := done(t)
+			if test.wantErr == "" {
+				if code != 0 {
+					t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+				}
+				return
+			}
+			if code == 0 {
+				t.Fatal("should've failed: ", output.Stdout())
+			}
+			if got := output.Stderr(); !strings.Contains(got, test.wantErr) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+			}
+		})
+	}
+}
+
 // we should be able to apply a plan file with no other file dependencies
 func TestApply_planNoModuleFiles(t *testing.T) {
 	// temporary data directory which we can remove between commands
@@ -1185,6 +1265,160 @@ func TestApply_shutdown(t *testing.T) {
 	}
 }
 
+func TestApply_runTimeout(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-shutdown"), td)
+	defer testChdir(t, td)()
+
+	stopped := make(chan struct{})
+
+	statePath := testTempFile(t)
+	p := testProvider()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	p.StopFn = func() error {
+		close(stopped)
+		return nil
+	}
+
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return
+	}
+
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		// Simulate a slow in-flight operation which the provider
+		// cancels once it is asked to stop.
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+		}
+		resp.NewState = req.PlannedState
+		return
+	}
+
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"ami": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-run-timeout", "100ms",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("provider was not stopped")
+	}
+
+	if got, want := output.Stdout(), "Run timeout of 100ms exceeded."; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:\n%s\nwant to contain: %s", got, want)
+	}
+	if got, want := output.Stderr(), "Run timeout exceeded"; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:\n%s\nwant to contain: %s", got, want)
+	}
+
+	// The first resource instance was applied before the timeout, so it
+	// must have been saved, while the second one was never started.
+	state := testStateRead(t, statePath)
+	if state == nil {
+		t.Fatal("state should not be nil")
+	}
+	foo := state.ResourceInstance(mustResourceAddr("test_instance.foo").Resource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance))
+	if foo == nil || foo.Current == nil {
+		t.Error("test_instance.foo was not saved in the state")
+	}
+	bar := state.ResourceInstance(mustResourceAddr("test_instance.bar").Resource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance))
+	if bar != nil {
+		t.Error("test_instance.bar should not have been created")
+	}
+}
+
+func TestApply_runTimeoutCancel(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-shutdown"), td)
+	defer testChdir(t, td)()
+
+	// The provider ignores the request to stop, so the operation must be
+	// canceled after the grace period.
+	release := make(chan struct{})
+	defer close(release)
+
+	statePath := testTempFile(t)
+	p := testProvider()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			runTimeoutGrace:  100 * time.Millisecond,
+		},
+	}
+
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		resp.PlannedState = req.ProposedNewState
+		return
+	}
+
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		<-release
+		resp.NewState = req.PlannedState
+		return
+	}
+
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"ami": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-run-timeout", "100ms",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if got, want := output.Stderr(), "did not stop within 100ms after that"; !strings.Contains(got, want) {
+		t.Errorf("wrong error output\ngot:\n%s\nwant to contain: %s", got, want)
+	}
+}
+
 func TestApply_state(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
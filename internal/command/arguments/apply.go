@@ -51,6 +51,8 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
 	cmdFlags.BoolVar(&apply.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.BoolVar(&apply.Operation.Reencrypt, "reencrypt", false, "reencrypt")
+	cmdFlags.IntVar(&apply.Operation.QuarantineAfter, "quarantine-after", 0, "quarantine-after")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -93,7 +95,16 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	if apply.Operation.QuarantineAfter < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid quarantine threshold",
+			fmt.Sprintf("The -quarantine-after option must be a positive number of failures, or zero to disable quarantining, not %d.", apply.Operation.QuarantineAfter),
+		))
+	}
+
 	diags = diags.Append(apply.Operation.Parse())
+	diags = diags.Append(apply.State.LockRetry.validate())
 
 	switch {
 	case json:
@@ -34,6 +34,7 @@ func TestParseApply_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -50,6 +51,7 @@ func TestParseApply_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -66,10 +68,47 @@ func TestParseApply_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
 		},
+		"reencrypt": {
+			[]string{"-reencrypt"},
+			&Apply{
+				AutoApprove:  false,
+				InputEnabled: true,
+				PlanPath:     "",
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Schedule:    "fifo",
+					Refresh:     true,
+					Reencrypt:   true,
+				},
+			},
+		},
+		"quarantine after": {
+			[]string{"-quarantine-after=3"},
+			&Apply{
+				AutoApprove:  false,
+				InputEnabled: true,
+				PlanPath:     "",
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Operation: &Operation{
+					PlanMode:        plans.NormalMode,
+					Parallelism:     10,
+					Schedule:        "fifo",
+					Refresh:         true,
+					QuarantineAfter: 3,
+				},
+			},
+		},
 		"JSON view disables input": {
 			[]string{"-json", "-auto-approve"},
 			&Apply{
@@ -82,6 +121,7 @@ func TestParseApply_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -156,6 +196,16 @@ func TestParseApply_invalid(t *testing.T) {
 	}
 }
 
+func TestParseApply_invalidQuarantineAfter(t *testing.T) {
+	_, diags := ParseApply([]string{"-quarantine-after=-1"})
+	if len(diags) == 0 {
+		t.Fatal("expected diags but got none")
+	}
+	if got, want := diags.Err().Error(), "Invalid quarantine threshold"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParseApply_tooManyArguments(t *testing.T) {
 	got, diags := ParseApply([]string{"saved.tfplan", "please"})
 	if len(diags) == 0 {
@@ -423,6 +473,7 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -438,6 +489,7 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -8,6 +8,7 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -22,6 +24,10 @@ import (
 // operations as it walks the dependency graph.
 const DefaultParallelism = 10
 
+// DefaultSchedule is the policy OpenTofu uses to order the graph nodes that
+// are ready to execute, unless overridden with -schedule.
+const DefaultSchedule = "fifo"
+
 // State describes arguments which are used to define how OpenTofu interacts
 // with state.
 type State struct {
@@ -33,6 +39,10 @@ type State struct {
 	// The default is 0, meaning no limit.
 	LockTimeout time.Duration
 
+	// LockRetry overrides parts of the policy for retrying to acquire the
+	// state lock from the CLI configuration.
+	LockRetry LockRetry
+
 	// StatePath specifies a non-default location for the state file. The
 	// default value is blank, which is interpreted as "terraform.tfstate".
 	StatePath string
@@ -49,6 +59,73 @@ type State struct {
 	BackupPath string
 }
 
+// LockRetry describes arguments which override parts of the policy for
+// retrying to acquire a state lock held by another process. Each field is
+// nil unless the corresponding option is given.
+type LockRetry struct {
+	InitialDelay *time.Duration
+	MaxDelay     *time.Duration
+	Multiplier   *float64
+	Jitter       *float64
+	MaxAttempts  *int
+}
+
+// Apply returns a copy of the given policy with the parts set by these
+// arguments replaced.
+func (r LockRetry) Apply(policy statemgr.LockRetryPolicy) statemgr.LockRetryPolicy {
+	if r.InitialDelay != nil {
+		policy.InitialDelay = *r.InitialDelay
+		if policy.MaxDelay < policy.InitialDelay {
+			policy.MaxDelay = policy.InitialDelay
+		}
+	}
+	if r.MaxDelay != nil {
+		policy.MaxDelay = *r.MaxDelay
+	}
+	if r.Multiplier != nil {
+		policy.Multiplier = *r.Multiplier
+	}
+	if r.Jitter != nil {
+		policy.Jitter = *r.Jitter
+	}
+	if r.MaxAttempts != nil {
+		policy.MaxAttempts = *r.MaxAttempts
+	}
+	return policy
+}
+
+// validate returns an error diagnostic for each argument that can't be used
+// in a retry policy.
+func (r LockRetry) validate() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	invalid := func(flag, detail string) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid state lock retry option",
+			fmt.Sprintf("The %s option %s.", flag, detail),
+		))
+	}
+	if r.InitialDelay != nil && *r.InitialDelay <= 0 {
+		invalid("-lock-retry-delay", "must be greater than zero")
+	}
+	if r.MaxDelay != nil && *r.MaxDelay <= 0 {
+		invalid("-lock-retry-max-delay", "must be greater than zero")
+	}
+	if r.InitialDelay != nil && r.MaxDelay != nil && *r.MaxDelay < *r.InitialDelay {
+		invalid("-lock-retry-max-delay", "must not be less than -lock-retry-delay")
+	}
+	if r.Multiplier != nil && *r.Multiplier < 1 {
+		invalid("-lock-retry-multiplier", "must be at least 1")
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		invalid("-lock-retry-jitter", "must be between 0 and 1")
+	}
+	if r.MaxAttempts != nil && *r.MaxAttempts < 0 {
+		invalid("-lock-retry-max-attempts", "must not be negative")
+	}
+	return diags
+}
+
 // Operation describes arguments which are used to configure how a OpenTofu
 // operation such as a plan or apply executes.
 type Operation struct {
@@ -61,6 +138,16 @@ type Operation struct {
 	// as it walks the dependency graph.
 	Parallelism int
 
+	// Schedule is the name of the policy deciding which of the graph nodes
+	// that are ready to execute is given the next parallelism slot: "fifo",
+	// "fair", or "critical-path".
+	Schedule string
+
+	// RunTimeout is the maximum duration of the whole operation. Once it is
+	// exceeded, OpenTofu stops the operation gracefully as if it had been
+	// interrupted. Zero means no timeout.
+	RunTimeout time.Duration
+
 	// Refresh controls whether or not the operation should refresh existing
 	// state before proceeding. Default is true.
 	Refresh bool
@@ -85,12 +172,33 @@ type Operation struct {
 	// learn a use-case for broader matching.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// FrozenModules causes the plan to fail if the source code of a module
+	// has changed since it was last applied. UnfrozenModules lists the
+	// modules that are allowed to change anyway.
+	FrozenModules   bool
+	UnfrozenModules []addrs.ModuleInstance
+
+	// Reencrypt causes the state to be written back encrypted with the
+	// primary encryption configuration, even if the operation makes no
+	// changes to it. Only the plan and apply commands accept this option.
+	Reencrypt bool
+
+	// QuarantineAfter is the number of consecutive applies in which a
+	// resource instance must fail before it's quarantined, or zero to not
+	// track the failures. Only the apply command accepts this option.
+	QuarantineAfter int
+
+	// ExplainUnknown requests explanations of why values in the plan are
+	// unknown until apply. Only the plan command accepts this option.
+	ExplainUnknown bool
+
 	// These private fields are used only temporarily during decoding. Use
 	// method Parse to populate the exported fields from these, validating
 	// the raw values in the process.
 	targetsRaw      []string
 	excludesRaw     []string
 	forceReplaceRaw []string
+	unfreezeRaw     []string
 	destroyRaw      bool
 	refreshOnlyRaw  bool
 }
@@ -195,6 +303,64 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		o.ForceReplace = append(o.ForceReplace, addr)
 	}
 
+	for _, raw := range o.unfreezeRaw {
+		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
+		if syntaxDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid unfreeze address %q", raw),
+				syntaxDiags[0].Detail,
+			))
+			continue
+		}
+
+		addr, addrDiags := addrs.ParseModuleInstance(traversal)
+		if addrDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid unfreeze address %q", raw),
+				addrDiags[0].Description().Detail,
+			))
+			continue
+		}
+
+		if addr.IsRoot() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid unfreeze address %q", raw),
+				"Only module calls, such as module.example, can be used with the -unfreeze=... option.",
+			))
+			continue
+		}
+
+		o.UnfrozenModules = append(o.UnfrozenModules, addr)
+	}
+	switch o.Schedule {
+	case "fifo", "fair", "critical-path":
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid schedule policy",
+			fmt.Sprintf("The -schedule option must be one of \"fifo\", \"fair\", or \"critical-path\", not %q.", o.Schedule),
+		))
+	}
+
+	if o.RunTimeout < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid run timeout",
+			fmt.Sprintf("The -run-timeout option must not be negative, not %s.", o.RunTimeout),
+		))
+	}
+
+	if len(o.UnfrozenModules) > 0 && !o.FrozenModules {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid combination of arguments",
+			"The -unfreeze option can only be used together with -frozen-modules.",
+		))
+	}
+
 	// If you add a new possible value for o.PlanMode here, consider also
 	// adding a specialized error message for it in ParseApplyDestroy.
 	switch {
@@ -259,6 +425,11 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 	if state != nil {
 		f.BoolVar(&state.Lock, "lock", true, "lock")
 		f.DurationVar(&state.LockTimeout, "lock-timeout", 0, "lock-timeout")
+		f.Var(flagOptional[time.Duration]{&state.LockRetry.InitialDelay, time.ParseDuration}, "lock-retry-delay", "lock-retry-delay")
+		f.Var(flagOptional[time.Duration]{&state.LockRetry.MaxDelay, time.ParseDuration}, "lock-retry-max-delay", "lock-retry-max-delay")
+		f.Var(flagOptional[float64]{&state.LockRetry.Multiplier, parseFloat}, "lock-retry-multiplier", "lock-retry-multiplier")
+		f.Var(flagOptional[float64]{&state.LockRetry.Jitter, parseFloat}, "lock-retry-jitter", "lock-retry-jitter")
+		f.Var(flagOptional[int]{&state.LockRetry.MaxAttempts, strconv.Atoi}, "lock-retry-max-attempts", "lock-retry-max-attempts")
 		f.StringVar(&state.StatePath, "state", "", "state-path")
 		f.StringVar(&state.StateOutPath, "state-out", "", "state-path")
 		f.StringVar(&state.BackupPath, "backup", "", "backup-path")
@@ -266,12 +437,16 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 
 	if operation != nil {
 		f.IntVar(&operation.Parallelism, "parallelism", DefaultParallelism, "parallelism")
+		f.StringVar(&operation.Schedule, "schedule", DefaultSchedule, "schedule")
+		f.DurationVar(&operation.RunTimeout, "run-timeout", 0, "run-timeout")
 		f.BoolVar(&operation.Refresh, "refresh", true, "refresh")
 		f.BoolVar(&operation.destroyRaw, "destroy", false, "destroy")
 		f.BoolVar(&operation.refreshOnlyRaw, "refresh-only", false, "refresh-only")
 		f.Var((*flagStringSlice)(&operation.targetsRaw), "target", "target")
 		f.Var((*flagStringSlice)(&operation.excludesRaw), "exclude", "exclude")
 		f.Var((*flagStringSlice)(&operation.forceReplaceRaw), "replace", "replace")
+		f.BoolVar(&operation.FrozenModules, "frozen-modules", false, "frozen-modules")
+		f.Var((*flagStringSlice)(&operation.unfreezeRaw), "unfreeze", "unfreeze")
 	}
 
 	// Gather all -var and -var-file arguments into one heterogeneous structure
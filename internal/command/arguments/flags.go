@@ -8,6 +8,7 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"strconv"
 )
 
 // flagStringSlice is a flag.Value implementation which allows collecting
@@ -100,3 +101,35 @@ func FlagIsSet(flags *flag.FlagSet, name string) bool {
 	})
 	return isSet
 }
+
+// flagOptional is a flag.Value implementation which stores a pointer to the
+// parsed value, leaving the target nil if the flag isn't set. This is used
+// for flags that override settings from elsewhere only when given.
+type flagOptional[T any] struct {
+	target **T
+	parse  func(string) (T, error)
+}
+
+var _ flag.Value = flagOptional[int]{}
+
+func (f flagOptional[T]) String() string {
+	if f.target == nil || *f.target == nil {
+		return ""
+	}
+	return fmt.Sprint(**f.target)
+}
+
+func (f flagOptional[T]) Set(raw string) error {
+	v, err := f.parse(raw)
+	if err != nil {
+		return err
+	}
+	*f.target = &v
+	return nil
+}
+
+// parseFloat parses a 64-bit floating point number, for use with
+// flagOptional.
+func parseFloat(raw string) (float64, error) {
+	return strconv.ParseFloat(raw, 64)
+}
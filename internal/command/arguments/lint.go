@@ -0,0 +1,71 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Lint represents the command-line arguments for the lint command.
+type Lint struct {
+	// Path is the directory containing the configuration to be checked. If
+	// unspecified, lint will use the current directory.
+	Path string
+
+	// Disable are the names of the rules that should not be run.
+	Disable []string
+
+	// ViewType specifies which output format to use: human or JSON.
+	ViewType ViewType
+
+	Vars *Vars
+}
+
+// ParseLint processes CLI arguments, returning a Lint value and errors.
+// If errors are encountered, a Lint value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseLint(args []string) (*Lint, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	lint := &Lint{
+		Path: ".",
+		Vars: &Vars{},
+	}
+
+	var jsonOutput bool
+	cmdFlags := extendedFlagSet("lint", nil, nil, lint.Vars)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Var((*flagStringSlice)(&lint.Disable), "disable", "disable")
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"Expected at most one positional argument.",
+		))
+	}
+
+	if len(args) > 0 {
+		lint.Path = args[0]
+	}
+
+	switch {
+	case jsonOutput:
+		lint.ViewType = ViewJSON
+	default:
+		lint.ViewType = ViewHuman
+	}
+
+	return lint, diags
+}
@@ -56,6 +56,8 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
 	cmdFlags.BoolVar(&plan.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.BoolVar(&plan.Operation.Reencrypt, "reencrypt", false, "reencrypt")
+	cmdFlags.BoolVar(&plan.Operation.ExplainUnknown, "explain-unknown", false, "explain-unknown")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -79,6 +81,7 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 	}
 
 	diags = diags.Append(plan.Operation.Parse())
+	diags = diags.Append(plan.State.LockRetry.validate())
 
 	// JSON view currently does not support input, so we disable it here
 	if json {
@@ -8,6 +8,7 @@ package arguments
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/tfdiags"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
 func TestParsePlan_basicValid(t *testing.T) {
@@ -34,6 +36,7 @@ func TestParsePlan_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -50,10 +53,47 @@ func TestParsePlan_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
 		},
+		"reencrypt": {
+			[]string{"-reencrypt"},
+			&Plan{
+				DetailedExitCode: false,
+				InputEnabled:     true,
+				OutPath:          "",
+				ViewType:         ViewHuman,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Schedule:    "fifo",
+					Refresh:     true,
+					Reencrypt:   true,
+				},
+			},
+		},
+		"explain unknown": {
+			[]string{"-explain-unknown"},
+			&Plan{
+				DetailedExitCode: false,
+				InputEnabled:     true,
+				OutPath:          "",
+				ViewType:         ViewHuman,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				Operation: &Operation{
+					PlanMode:       plans.NormalMode,
+					Parallelism:    10,
+					Schedule:       "fifo",
+					Refresh:        true,
+					ExplainUnknown: true,
+				},
+			},
+		},
 		"JSON view disables input": {
 			[]string{"-json"},
 			&Plan{
@@ -66,6 +106,7 @@ func TestParsePlan_basicValid(t *testing.T) {
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
+					Schedule:    "fifo",
 					Refresh:     true,
 				},
 			},
@@ -100,6 +141,55 @@ func TestParsePlan_invalid(t *testing.T) {
 	}
 }
 
+func TestParsePlan_lockRetry(t *testing.T) {
+	got, diags := ParsePlan([]string{
+		"-lock-timeout=5m",
+		"-lock-retry-delay=2s",
+		"-lock-retry-jitter=0.25",
+		"-lock-retry-max-attempts=7",
+	})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+
+	base := statemgr.DefaultLockRetryPolicy()
+	base.MaxDelay = time.Minute
+	want := statemgr.LockRetryPolicy{
+		InitialDelay: 2 * time.Second,
+		Multiplier:   2,
+		MaxDelay:     time.Minute,
+		Jitter:       0.25,
+		MaxAttempts:  7,
+	}
+	if diff := cmp.Diff(want, got.State.LockRetry.Apply(base)); diff != "" {
+		t.Errorf("wrong policy\n%s", diff)
+	}
+	if got.State.LockTimeout != 5*time.Minute {
+		t.Errorf("wrong lock timeout %s", got.State.LockTimeout)
+	}
+}
+
+func TestParsePlan_lockRetryInvalid(t *testing.T) {
+	_, diags := ParsePlan([]string{
+		"-lock-retry-delay=10s",
+		"-lock-retry-max-delay=1s",
+		"-lock-retry-multiplier=0.5",
+		"-lock-retry-jitter=2",
+	})
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Description().Detail)
+	}
+	want := []string{
+		"The -lock-retry-max-delay option must not be less than -lock-retry-delay.",
+		"The -lock-retry-multiplier option must be at least 1.",
+		"The -lock-retry-jitter option must be between 0 and 1.",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
+
 func TestParsePlan_tooManyArguments(t *testing.T) {
 	got, diags := ParsePlan([]string{"saved.tfplan"})
 	if len(diags) == 0 {
@@ -226,6 +316,146 @@ func TestParsePlan_excludes(t *testing.T) {
 	}
 }
 
+func TestParsePlan_frozenModules(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		wantFrozen bool
+		want       []addrs.ModuleInstance
+		wantErr    string
+	}{
+		"not frozen by default": {
+			args: nil,
+		},
+		"frozen": {
+			args:       []string{"-frozen-modules"},
+			wantFrozen: true,
+		},
+		"unfrozen modules": {
+			args:       []string{"-frozen-modules", "-unfreeze=module.foo", "-unfreeze", `module.bar["a"].module.baz`},
+			wantFrozen: true,
+			want: []addrs.ModuleInstance{
+				addrs.RootModuleInstance.Child("foo", addrs.NoKey),
+				addrs.RootModuleInstance.Child("bar", addrs.StringKey("a")).Child("baz", addrs.NoKey),
+			},
+		},
+		"unfreeze without frozen modules": {
+			args:    []string{"-unfreeze=module.foo"},
+			want:    []addrs.ModuleInstance{addrs.RootModuleInstance.Child("foo", addrs.NoKey)},
+			wantErr: "The -unfreeze option can only be used together with -frozen-modules.",
+		},
+		"invalid unfreeze address": {
+			args:       []string{"-frozen-modules", "-unfreeze=foo_bar.baz"},
+			wantFrozen: true,
+			wantErr:    `Invalid unfreeze address "foo_bar.baz"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			} else if tc.wantErr != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diags but got none")
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+			if got.Operation.FrozenModules != tc.wantFrozen {
+				t.Errorf("wrong FrozenModules %t; want %t", got.Operation.FrozenModules, tc.wantFrozen)
+			}
+			if !cmp.Equal(got.Operation.UnfrozenModules, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.UnfrozenModules, tc.want))
+			}
+		})
+	}
+}
+
+func TestParsePlan_schedule(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    string
+		wantErr string
+	}{
+		"default": {
+			args: nil,
+			want: "fifo",
+		},
+		"fair": {
+			args: []string{"-schedule=fair"},
+			want: "fair",
+		},
+		"critical path": {
+			args: []string{"-schedule", "critical-path"},
+			want: "critical-path",
+		},
+		"invalid": {
+			args:    []string{"-schedule=random"},
+			want:    "random",
+			wantErr: `The -schedule option must be one of "fifo", "fair", or "critical-path", not "random".`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			} else if tc.wantErr != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diags but got none")
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+			if got.Operation.Schedule != tc.want {
+				t.Errorf("wrong Schedule %q; want %q", got.Operation.Schedule, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePlan_runTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    time.Duration
+		wantErr string
+	}{
+		"default": {
+			args: nil,
+			want: 0,
+		},
+		"minutes": {
+			args: []string{"-run-timeout=90m"},
+			want: 90 * time.Minute,
+		},
+		"negative": {
+			args:    []string{"-run-timeout", "-1h"},
+			want:    -time.Hour,
+			wantErr: "The -run-timeout option must not be negative, not -1h0m0s.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" && len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			} else if tc.wantErr != "" {
+				if len(diags) == 0 {
+					t.Fatalf("expected diags but got none")
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+			if got.Operation.RunTimeout != tc.want {
+				t.Errorf("wrong RunTimeout %s; want %s", got.Operation.RunTimeout, tc.want)
+			}
+		})
+	}
+}
+
 func TestParsePlan_excludeAndTarget(t *testing.T) {
 	got, gotDiags := ParsePlan([]string{"-exclude=foo_bar.baz", "-target=foo_bar.bar"})
 	if len(gotDiags) == 0 {
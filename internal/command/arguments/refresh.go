@@ -59,6 +59,7 @@ func ParseRefresh(args []string) (*Refresh, tfdiags.Diagnostics) {
 	}
 
 	diags = diags.Append(refresh.Operation.Parse())
+	diags = diags.Append(refresh.State.LockRetry.validate())
 
 	// JSON view currently does not support input, so we disable it here
 	if json {
@@ -22,6 +22,15 @@ type Show struct {
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
 	ShowSensitive bool
+
+	// Reveal is the address of a single sensitive value to display, such as
+	// "aws_db_instance.main.password" or "output.token". It is empty unless
+	// the -reveal option is used.
+	Reveal string
+
+	// JSONFilters are the privacy filters to apply to the JSON output, in the
+	// order they were given using the -json-filter option.
+	JSONFilters []string
 }
 
 // ParseShow processes CLI arguments, returning a Show value and errors.
@@ -38,6 +47,8 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 	cmdFlags := extendedFlagSet("show", nil, nil, show.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&show.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.StringVar(&show.Reveal, "reveal", "", "reveal")
+	cmdFlags.Var((*flagStringSlice)(&show.JSONFilters), "json-filter", "json-filter")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -67,5 +78,13 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		show.ViewType = ViewHuman
 	}
 
+	if len(show.JSONFilters) > 0 && !jsonOutput {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid combination of options",
+			"The -json-filter option can only be used together with -json.",
+		))
+	}
+
 	return show, diags
 }
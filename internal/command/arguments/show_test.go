@@ -39,6 +39,22 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType: ViewJSON,
 			},
 		},
+		"reveal": {
+			[]string{"-reveal=test_instance.foo.password", "foo"},
+			&Show{
+				Path:     "foo",
+				ViewType: ViewHuman,
+				Reveal:   "test_instance.foo.password",
+			},
+		},
+		"json filters": {
+			[]string{"-json", "-json-filter=drop=password", "-json-filter=strip-provider-config"},
+			&Show{
+				Path:        "",
+				ViewType:    ViewJSON,
+				JSONFilters: []string{"drop=password", "strip-provider-config"},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -48,7 +64,7 @@ func TestParseShow_valid(t *testing.T) {
 			if len(diags) > 0 {
 				t.Fatalf("unexpected diags: %v", diags)
 			}
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 		})
@@ -89,13 +105,28 @@ func TestParseShow_invalid(t *testing.T) {
 				),
 			},
 		},
+		"json filters without json": {
+			[]string{"-json-filter=drop=password"},
+			&Show{
+				Path:        "",
+				ViewType:    ViewHuman,
+				JSONFilters: []string{"drop=password"},
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid combination of options",
+					"The -json-filter option can only be used together with -json.",
+				),
+			},
+		},
 	}
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			got, gotDiags := ParseShow(tc.args)
 			got.Vars = nil
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 			if !reflect.DeepEqual(gotDiags, tc.wantDiags) {
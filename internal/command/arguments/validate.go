@@ -24,6 +24,18 @@ type Validate struct {
 	// included with the module.
 	NoTests bool
 
+	// Strict indicates that uses of deprecated language features should be
+	// reported as errors rather than warnings.
+	Strict bool
+
+	// UnusedProviders indicates that provider configurations which are not
+	// used anywhere in the configuration should be reported as warnings.
+	UnusedProviders bool
+
+	// Watch indicates that OpenTofu should keep running after validating the
+	// configuration, validating it again whenever any of its files change.
+	Watch bool
+
 	// ViewType specifies which output format to use: human, JSON, or "raw".
 	ViewType ViewType
 
@@ -45,6 +57,9 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.StringVar(&validate.TestDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&validate.NoTests, "no-tests", false, "no-tests")
+	cmdFlags.BoolVar(&validate.Strict, "strict", false, "strict")
+	cmdFlags.BoolVar(&validate.UnusedProviders, "unused-providers", false, "unused-providers")
+	cmdFlags.BoolVar(&validate.Watch, "watch", false, "watch")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
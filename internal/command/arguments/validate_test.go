@@ -60,6 +60,33 @@ func TestParseValidate_valid(t *testing.T) {
 				NoTests:       true,
 			},
 		},
+		"strict": {
+			[]string{"-strict"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Strict:        true,
+			},
+		},
+		"unused providers": {
+			[]string{"-unused-providers"},
+			&Validate{
+				Path:            ".",
+				TestDirectory:   "tests",
+				ViewType:        ViewHuman,
+				UnusedProviders: true,
+			},
+		},
+		"watch": {
+			[]string{"-watch"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Watch:         true,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
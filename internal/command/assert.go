@@ -0,0 +1,441 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// AssertCommand is a Command implementation that evaluates a set of
+// user-provided assertions against the current state without creating a
+// plan, exiting with a non-zero status if any of them do not hold.
+type AssertCommand struct {
+	Meta
+}
+
+// assertRule is a single assertion decoded from an assertions file.
+type assertRule struct {
+	Condition    hcl.Expression
+	ErrorMessage hcl.Expression
+	DeclRange    hcl.Range
+
+	// conditionSrc is the source text of the condition expression, which
+	// is included in the reported failures.
+	conditionSrc string
+}
+
+// assertFailure describes an assertion that did not hold. It is also the
+// JSON representation used when the -json flag is set.
+type assertFailure struct {
+	Condition    string `json:"condition"`
+	ErrorMessage string `json:"error_message"`
+	Filename     string `json:"filename"`
+	Line         int    `json:"line"`
+	Column       int    `json:"column"`
+}
+
+type assertResult struct {
+	Valid      bool            `json:"valid"`
+	Assertions int             `json:"assertion_count"`
+	Failures   []assertFailure `json:"failures"`
+}
+
+var assertFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "assert"},
+	},
+}
+
+var assertBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition", Required: true},
+		{Name: "error_message"},
+	},
+}
+
+func (c *AssertCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+
+	var fromFile string
+	var jsonOutput bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.extendedFlagSet("assert")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&fromFile, "from-file", "", "path")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	if fromFile == "" {
+		c.Ui.Error("The -from-file option is required.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	configPath, err := modulePath(cmdFlags.Args())
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	configPath = c.Meta.normalizePath(configPath)
+
+	// Check for user-supplied plugin path
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	rules, rulesDiags := loadAssertRules(c.Meta.normalizePath(fromFile))
+	diags = diags.Append(rulesDiags)
+	if rulesDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.showDiagnostics(diags) // in case of any warnings in here
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	{
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	lr, _, ctxDiags := local.LocalRun(ctx, opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+		}
+	}()
+
+	evalOpts := &tofu.EvalOpts{}
+	if lr.PlanOpts != nil {
+		evalOpts.SetVariables = lr.PlanOpts.SetVariables
+	}
+
+	scope, scopeDiags := lr.Core.Eval(ctx, lr.Config, lr.InputState, addrs.RootModuleInstance, evalOpts)
+	diags = diags.Append(scopeDiags)
+	if scope == nil || scopeDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Assertions may refer to root module output values directly, in the
+	// same way as assertions in test files.
+	scope.ParseRef = addrs.ParseRefFromTestingScope
+
+	result := assertResult{
+		Valid:      true,
+		Assertions: len(rules),
+		Failures:   []assertFailure{},
+	}
+	for _, rule := range rules {
+		failure, ruleDiags := evalAssertRule(scope, rule)
+		diags = diags.Append(ruleDiags)
+		if failure != nil {
+			result.Valid = false
+			result.Failures = append(result.Failures, *failure)
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal assertion results: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+	} else {
+		for _, failure := range result.Failures {
+			c.Ui.Error(fmt.Sprintf(
+				"Assertion failed at %s:%d,%d: %s\n  condition: %s",
+				failure.Filename, failure.Line, failure.Column, failure.ErrorMessage, failure.Condition,
+			))
+		}
+		if result.Valid {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[green]Success![reset] %d assertion(s) passed.", result.Assertions)))
+		}
+	}
+
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
+
+// loadAssertRules parses the given assertions file, which may be written
+// in either the native HCL syntax or the JSON variant, into its assertions.
+func loadAssertRules(filename string) ([]*assertRule, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var hclDiags hcl.Diagnostics
+	if strings.HasSuffix(filename, ".json") {
+		file, hclDiags = parser.ParseJSONFile(filename)
+	} else {
+		file, hclDiags = parser.ParseHCLFile(filename)
+	}
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	content, hclDiags := file.Body.Content(assertFileSchema)
+	diags = diags.Append(hclDiags)
+
+	var rules []*assertRule
+	for _, block := range content.Blocks {
+		blockContent, hclDiags := block.Body.Content(assertBlockSchema)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+
+		cond := blockContent.Attributes["condition"].Expr
+		rule := &assertRule{
+			Condition:    cond,
+			DeclRange:    block.DefRange,
+			conditionSrc: strings.TrimSpace(string(cond.Range().SliceBytes(file.Bytes))),
+		}
+		if attr, ok := blockContent.Attributes["error_message"]; ok {
+			rule.ErrorMessage = attr.Expr
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 && !diags.HasErrors() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "No assertions found",
+			Detail:   fmt.Sprintf("The file %q does not contain any assert blocks.", filename),
+			Subject:  file.Body.MissingItemRange().Ptr(),
+		})
+	}
+
+	return rules, diags
+}
+
+// evalAssertRule evaluates a single assertion in the given scope, returning
+// a non-nil failure if the condition evaluated to false. Errors preventing
+// the condition from being evaluated at all are returned as diagnostics.
+func evalAssertRule(scope *lang.Scope, rule *assertRule) (*assertFailure, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	refs, moreDiags := lang.ReferencesInExpr(scope.ParseRef, rule.Condition)
+	diags = diags.Append(moreDiags)
+	if rule.ErrorMessage != nil {
+		moreRefs, moreDiags := lang.ReferencesInExpr(scope.ParseRef, rule.ErrorMessage)
+		diags = diags.Append(moreDiags)
+		refs = append(refs, moreRefs...)
+	}
+
+	hclCtx, moreDiags := scope.EvalContext(refs)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	condVal, hclDiags := rule.Condition.Value(hclCtx)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	// The condition result may be marked if the expression refers to a
+	// sensitive value.
+	condVal, _ = condVal.Unmark()
+
+	condVal, err := convert.Convert(condVal, cty.Bool)
+	if err != nil {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid assertion condition",
+			Detail:      fmt.Sprintf("Invalid condition result value: %s.", tfdiags.FormatError(err)),
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+	}
+	if condVal.IsNull() {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid assertion condition",
+			Detail:      "Condition expression must return either true or false, not null.",
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+	}
+	if !condVal.IsKnown() {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Unknown assertion condition",
+			Detail:      "Condition expression could not be evaluated because it depends on values that are not yet known.",
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+	}
+
+	if condVal.True() {
+		return nil, diags
+	}
+
+	failure := &assertFailure{
+		Condition:    rule.conditionSrc,
+		ErrorMessage: "Assertion condition evaluated to false.",
+		Filename:     rule.Condition.Range().Filename,
+		Line:         rule.Condition.Range().Start.Line,
+		Column:       rule.Condition.Range().Start.Column,
+	}
+	if rule.ErrorMessage != nil {
+		msgVal, hclDiags := rule.ErrorMessage.Value(hclCtx)
+		diags = diags.Append(hclDiags)
+		if !hclDiags.HasErrors() {
+			msgVal, err = convert.Convert(msgVal, cty.String)
+			switch {
+			case err != nil:
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity:    hcl.DiagError,
+					Summary:     "Invalid assertion error message",
+					Detail:      fmt.Sprintf("Unsuitable value for error message: %s.", tfdiags.FormatError(err)),
+					Subject:     rule.ErrorMessage.Range().Ptr(),
+					Expression:  rule.ErrorMessage,
+					EvalContext: hclCtx,
+				})
+			case msgVal.HasMark(marks.Sensitive):
+				failure.ErrorMessage = "The error message included a sensitive value, so it will not be displayed."
+			case !msgVal.IsNull() && msgVal.IsWhollyKnown():
+				msgVal, _ = msgVal.Unmark()
+				failure.ErrorMessage = strings.TrimSpace(msgVal.AsString())
+			}
+		}
+	}
+
+	return failure, diags
+}
+
+func (c *AssertCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] assert [options] -from-file=FILE
+
+  Evaluates the assertions declared in the given file against the current
+  state, without creating a plan, and exits with a non-zero status if any
+  of them do not hold.
+
+  The file contains one or more "assert" blocks, each with a "condition"
+  expression and an optional "error_message". Conditions may refer to
+  resources, data sources, input variables, local values, module outputs
+  and root module output values using "output.NAME".
+
+  This command will never modify your state.
+
+Options:
+
+  -from-file=path        Path to the file containing the assertions to
+                         evaluate. Required.
+
+  -json                  Produce the results as machine-readable JSON.
+
+  -state=path            Legacy option for the local backend only. See the local
+                         backend's documentation for more information.
+
+  -var 'foo=bar'         Set a variable in the OpenTofu configuration. This
+                         flag can be set multiple times.
+
+  -var-file=foo          Set variables in the OpenTofu configuration from
+                         a file. If "terraform.tfvars" or any ".auto.tfvars"
+                         files are present, they will be automatically loaded.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AssertCommand) Synopsis() string {
+	return "Check assertions against the current state"
+}
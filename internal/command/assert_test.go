@@ -0,0 +1,136 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func testAssertState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "replicas"}.Absolute(addrs.RootModuleInstance),
+			cty.NumberIntVal(3),
+			false,
+		)
+	})
+}
+
+func TestAssert_pass(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("assert"), td)
+	defer testChdir(t, td)()
+	testStateFileDefault(t, testAssertState())
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &AssertCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-from-file", "pass.tofuassert.hcl"})
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "2 assertion(s) passed"; !strings.Contains(got, want) {
+		t.Fatalf("output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestAssert_fail(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("assert"), td)
+	defer testChdir(t, td)()
+	testStateFileDefault(t, testAssertState())
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &AssertCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-from-file", "fail.tofuassert.hcl"})
+	if code != 1 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	got := ui.ErrorWriter.String()
+	if want := "Expected production, got staging."; !strings.Contains(got, want) {
+		t.Fatalf("output does not contain %q\n%s", want, got)
+	}
+	if strings.Contains(got, "At least two replicas") {
+		t.Fatalf("passing assertion was reported as failed\n%s", got)
+	}
+}
+
+func TestAssert_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("assert"), td)
+	defer testChdir(t, td)()
+	testStateFileDefault(t, testAssertState())
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &AssertCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-from-file", "fail.tofuassert.hcl", "-json"})
+	if code != 1 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var result assertResult
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &result); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	if result.Valid || result.Assertions != 2 || len(result.Failures) != 1 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	failure := result.Failures[0]
+	if failure.Condition != `var.environment == "production"` {
+		t.Errorf("wrong condition %q", failure.Condition)
+	}
+	if failure.Line != 7 {
+		t.Errorf("wrong line %d", failure.Line)
+	}
+}
+
+func TestAssert_missingFile(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &AssertCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "-from-file option is required"; !strings.Contains(got, want) {
+		t.Fatalf("output does not contain %q\n%s", want, got)
+	}
+}
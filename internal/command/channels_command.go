@@ -0,0 +1,42 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// ChannelsCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type ChannelsCommand struct {
+	Meta
+}
+
+func (c *ChannelsCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *ChannelsCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] channels <subcommand> [options] [args]
+
+  This command has subcommands for managing module channels.
+
+  A module channel is a file in the "channels" directory of the root module,
+  such as channels/prod.json, that selects exact versions for registry
+  modules. Running "tofu init -channel=prod" installs the versions selected
+  by that channel, so that new module versions can be rolled out to one
+  environment at a time without editing the configuration.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ChannelsCommand) Synopsis() string {
+	return "Manage module channels"
+}
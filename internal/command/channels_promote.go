@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/modchannel"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ChannelsPromoteCommand is a Command implementation that copies the module
+// versions selected by one module channel into another.
+type ChannelsPromoteCommand struct {
+	Meta
+}
+
+func (c *ChannelsPromoteCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var flagModules FlagStringSlice
+	cmdFlags := c.Meta.defaultFlagSet("channels promote")
+	cmdFlags.Var(&flagModules, "module", "module")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("The channels promote command expects exactly two arguments: the source and destination channel names.\n")
+		return cli.RunResultHelp
+	}
+	fromName, toName := args[0], args[1]
+	if fromName == toName {
+		c.Ui.Error("The source and destination channels must be different.\n")
+		return cli.RunResultHelp
+	}
+
+	var diags tfdiags.Diagnostics
+
+	var only []addrs.ModuleRegistryPackage
+	for _, raw := range flagModules {
+		addr, err := modchannel.ParseModulePackage(raw)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module address",
+				fmt.Sprintf("The -module option value %q is not a valid module registry address: %s.", raw, err),
+			))
+			continue
+		}
+		only = append(only, addr)
+	}
+
+	rootDir := c.normalizePath(".")
+	from, fromDiags := modchannel.Load(rootDir, fromName)
+	diags = diags.Append(fromDiags)
+	to, toDiags := modchannel.LoadOrNew(rootDir, toName)
+	diags = diags.Append(toDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if len(only) == 0 {
+		only = from.Modules()
+	}
+
+	changed := 0
+	for _, addr := range only {
+		v := from.ModuleVersion(addr)
+		if v == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Module not in source channel",
+				fmt.Sprintf("The module channel %q does not select a version of module %s.", fromName, addr.ForDisplay()),
+			))
+			continue
+		}
+		switch prev := to.ModuleVersion(addr); {
+		case prev == nil:
+			c.Ui.Output(fmt.Sprintf("%s: (none) -> %s", addr.ForDisplay(), v))
+		case prev.Equal(v):
+			c.Ui.Output(fmt.Sprintf("%s: %s (unchanged)", addr.ForDisplay(), v))
+			continue
+		default:
+			c.Ui.Output(fmt.Sprintf("%s: %s -> %s", addr.ForDisplay(), prev, v))
+		}
+		to.SetModuleVersion(addr, v)
+		changed++
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if changed != 0 {
+		diags = diags.Append(to.Save(rootDir))
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf("Promoted %d module version(s) from channel %q to channel %q.", changed, fromName, toName))
+	return 0
+}
+
+func (c *ChannelsPromoteCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] channels promote [options] SOURCE DESTINATION
+
+  Copy the registry module versions selected by the module channel SOURCE
+  into the module channel DESTINATION, creating the destination channel if
+  it doesn't exist yet.
+
+  Modules that are only selected by the destination channel are left
+  unchanged. Run "tofu init -channel=DESTINATION" afterwards to install the
+  promoted versions.
+
+Options:
+
+  -module=ADDR    Promote only the version of the module with the given
+                  registry address, such as "hashicorp/consul/aws". Use this
+                  option more than once to promote more than one module.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ChannelsPromoteCommand) Synopsis() string {
+	return "Copy module versions from one channel to another"
+}
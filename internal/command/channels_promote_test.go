@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func testChannelsPromoteCommand(t *testing.T) (*ChannelsPromoteCommand, *cli.MockUi) {
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	return &ChannelsPromoteCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}, ui
+}
+
+func testChannelFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll("channels", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("channels", name+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChannelsPromote(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	testChannelFile(t, "staging", `{
+  "modules": {
+    "hashicorp/consul/aws": "0.2.0",
+    "registry.opentofu.org/hashicorp/vpc/aws": "1.1.0"
+  }
+}`)
+	testChannelFile(t, "prod", `{
+  "modules": {
+    "registry.opentofu.org/hashicorp/consul/aws": "0.1.0",
+    "registry.opentofu.org/hashicorp/dns/aws": "3.0.0"
+  }
+}`)
+
+	c, ui := testChannelsPromoteCommand(t)
+	if code := c.Run([]string{"staging", "prod"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		"hashicorp/consul/aws: 0.1.0 -> 0.2.0",
+		"hashicorp/vpc/aws: (none) -> 1.1.0",
+		`Promoted 2 module version(s) from channel "staging" to channel "prod".`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output doesn't contain %q\n%s", want, output)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join("channels", "prod.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "modules": {
+    "registry.opentofu.org/hashicorp/consul/aws": "0.2.0",
+    "registry.opentofu.org/hashicorp/dns/aws": "3.0.0",
+    "registry.opentofu.org/hashicorp/vpc/aws": "1.1.0"
+  }
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong prod channel\n%s", diff)
+	}
+}
+
+func TestChannelsPromote_module(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	testChannelFile(t, "staging", `{
+  "modules": {
+    "hashicorp/consul/aws": "0.2.0",
+    "hashicorp/vpc/aws": "1.1.0"
+  }
+}`)
+
+	c, ui := testChannelsPromoteCommand(t)
+	if code := c.Run([]string{"-module=hashicorp/vpc/aws", "staging", "prod"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	got, err := os.ReadFile(filepath.Join("channels", "prod.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "modules": {
+    "registry.opentofu.org/hashicorp/vpc/aws": "1.1.0"
+  }
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong prod channel\n%s", diff)
+	}
+}
+
+func TestChannelsPromote_missingSource(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	c, ui := testChannelsPromoteCommand(t)
+	if code := c.Run([]string{"staging", "prod"}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	if _, err := os.Stat(filepath.Join("channels", "prod.json")); !os.IsNotExist(err) {
+		t.Errorf("destination channel was created despite the error")
+	}
+}
@@ -31,6 +31,7 @@ import (
 
 const pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
 const pluginCacheMayBreakLockFileEnvVar = "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE"
+const moduleCacheDirEnvVar = "TF_MODULE_CACHE_DIR"
 
 // Config is the structure of the configuration for the OpenTofu CLI.
 //
@@ -55,11 +56,40 @@ type Config struct {
 	// over the requirements of the dependency lock file.
 	PluginCacheMayBreakDependencyLockFile bool `hcl:"plugin_cache_may_break_dependency_lock_file"`
 
+	// If set, enables local caching of registry module packages in this
+	// directory, shared between all working directories, to avoid
+	// downloading the same module version repeatedly.
+	ModuleCacheDir string `hcl:"module_cache_dir"`
+
+	// StrictDeprecations causes uses of deprecated language features in the
+	// configuration to be reported as errors rather than warnings.
+	StrictDeprecations bool `hcl:"strict_deprecations"`
+
 	Hosts map[string]*ConfigHost `hcl:"host"`
 
 	Credentials        map[string]map[string]interface{}   `hcl:"credentials"`
 	CredentialsHelpers map[string]*ConfigCredentialsHelper `hcl:"credentials_helper"`
 
+	// EncryptionPlugins declares the checksums of the encryption plugins
+	// in the plugin cache directory that OpenTofu may run, keyed by the
+	// file name of the plugin executable.
+	EncryptionPlugins map[string]*ConfigEncryptionPlugin `hcl:"encryption_plugin"`
+
+	// StateLockRetry represents any state_lock_retry blocks in the
+	// configuration. Only one of these is allowed across the whole
+	// configuration, but we decode into a slice here so that we can handle
+	// that validation at validation time rather than initial decode time.
+	StateLockRetry []*ConfigStateLockRetry
+
+	// Notifications represents any notifications blocks in the
+	// configuration. Only one of these is allowed across the whole
+	// configuration, but we decode into a slice here so that we can handle
+	// that validation at validation time rather than initial decode time.
+	// HCL 1 matches field names case-insensitively, so this field is
+	// excluded from DecodeObject, which would otherwise consume the nested
+	// webhook blocks before decodeNotificationsFromConfig sees them.
+	Notifications []*ConfigNotifications `hcl:"-"`
+
 	// ProviderInstallation represents any provider_installation blocks
 	// in the configuration. Only one of these is allowed across the whole
 	// configuration, but we decode into a slice here so that we can handle
@@ -200,6 +230,12 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	ociCredsBlocks, ociCredsDiags := decodeOCIRepositoryCredentialsFromConfig(obj)
 	diags = diags.Append(ociCredsDiags)
 	result.OCIRepositoryCredentials = ociCredsBlocks
+	stateLockRetryBlocks, stateLockRetryDiags := decodeStateLockRetryFromConfig(obj)
+	diags = diags.Append(stateLockRetryDiags)
+	result.StateLockRetry = stateLockRetryBlocks
+	notificationsBlocks, notificationsDiags := decodeNotificationsFromConfig(obj)
+	diags = diags.Append(notificationsDiags)
+	result.Notifications = notificationsBlocks
 
 	// Replace all env vars
 	for k, v := range result.Providers {
@@ -212,6 +248,9 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	if result.PluginCacheDir != "" {
 		result.PluginCacheDir = os.ExpandEnv(result.PluginCacheDir)
 	}
+	if result.ModuleCacheDir != "" {
+		result.ModuleCacheDir = os.ExpandEnv(result.ModuleCacheDir)
+	}
 
 	return result, diags
 }
@@ -274,6 +313,11 @@ func envConfig(env map[string]string) *Config {
 		config.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	if envModuleCacheDir := env[moduleCacheDirEnvVar]; envModuleCacheDir != "" {
+		// As with TF_PLUGIN_CACHE_DIR, no ExpandEnv here.
+		config.ModuleCacheDir = envModuleCacheDir
+	}
+
 	return config
 }
 
@@ -337,6 +381,41 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		)
 	}
 
+	// Check that all "encryption_plugin" blocks have valid checksums.
+	for name, plugin := range c.EncryptionPlugins {
+		if _, err := plugin.checksum(); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The encryption_plugin %q block is invalid: %w", name, err),
+			)
+		}
+	}
+
+	// Should have zero or one "state_lock_retry" blocks
+	if len(c.StateLockRetry) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one state_lock_retry block may be specified"),
+		)
+	} else if len(c.StateLockRetry) == 1 {
+		if _, err := c.StateLockRetry[0].Policy(); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The state_lock_retry block is invalid: %w", err),
+			)
+		}
+	}
+
+	// Should have zero or one "notifications" blocks
+	if len(c.Notifications) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one notifications block may be specified"),
+		)
+	} else if len(c.Notifications) == 1 {
+		if _, err := c.Notifications[0].webhooks(); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The notifications block is invalid: %w", err),
+			)
+		}
+	}
+
 	// Should have zero or one "provider_installation" blocks
 	if len(c.ProviderInstallation) > 1 {
 		diags = diags.Append(
@@ -374,6 +453,15 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		}
 	}
 
+	if c.ModuleCacheDir != "" {
+		_, err := os.Stat(c.ModuleCacheDir)
+		if err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The specified module cache dir %s cannot be opened: %w", c.ModuleCacheDir, err),
+			)
+		}
+	}
+
 	return diags
 }
 
@@ -407,12 +495,20 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginCacheDir = c2.PluginCacheDir
 	}
 
+	result.ModuleCacheDir = c.ModuleCacheDir
+	if result.ModuleCacheDir == "" {
+		result.ModuleCacheDir = c2.ModuleCacheDir
+	}
+
 	if c.PluginCacheMayBreakDependencyLockFile || c2.PluginCacheMayBreakDependencyLockFile {
 		// This setting saturates to "on"; once either configuration sets it,
 		// there is no way to override it back to off again.
 		result.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	// Like the above, this setting saturates to "on".
+	result.StrictDeprecations = c.StrictDeprecations || c2.StrictDeprecations
+
 	if (len(c.Hosts) + len(c2.Hosts)) > 0 {
 		result.Hosts = make(map[string]*ConfigHost)
 		for name, host := range c.Hosts {
@@ -446,6 +542,26 @@ func (c *Config) Merge(c2 *Config) *Config {
 		}
 	}
 
+	if (len(c.EncryptionPlugins) + len(c2.EncryptionPlugins)) > 0 {
+		result.EncryptionPlugins = make(map[string]*ConfigEncryptionPlugin)
+		for name, plugin := range c.EncryptionPlugins {
+			result.EncryptionPlugins[name] = plugin
+		}
+		for name, plugin := range c2.EncryptionPlugins {
+			result.EncryptionPlugins[name] = plugin
+		}
+	}
+
+	if (len(c.StateLockRetry) + len(c2.StateLockRetry)) > 0 {
+		result.StateLockRetry = append(result.StateLockRetry, c.StateLockRetry...)
+		result.StateLockRetry = append(result.StateLockRetry, c2.StateLockRetry...)
+	}
+
+	if (len(c.Notifications) + len(c2.Notifications)) > 0 {
+		result.Notifications = append(result.Notifications, c.Notifications...)
+		result.Notifications = append(result.Notifications, c2.Notifications...)
+	}
+
 	if (len(c.ProviderInstallation) + len(c2.ProviderInstallation)) > 0 {
 		result.ProviderInstallation = append(result.ProviderInstallation, c.ProviderInstallation...)
 		result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
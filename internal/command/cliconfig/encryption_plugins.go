@@ -0,0 +1,46 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ConfigEncryptionPlugin is the structure of the "encryption_plugin" nested
+// block within the CLI configuration, which allows OpenTofu to run the
+// encryption plugin in the plugin cache directory whose file name is the
+// block label.
+type ConfigEncryptionPlugin struct {
+	SHA256 string `hcl:"sha256"`
+}
+
+// checksum returns the decoded SHA-256 checksum of the plugin executable.
+func (c *ConfigEncryptionPlugin) checksum() ([]byte, error) {
+	checksum, err := hex.DecodeString(c.SHA256)
+	if err != nil || len(checksum) != sha256.Size {
+		return nil, fmt.Errorf("sha256 must be a SHA-256 checksum of %d hexadecimal digits", sha256.Size*2)
+	}
+	return checksum, nil
+}
+
+// EncryptionPluginChecksums returns the SHA-256 checksums of the encryption
+// plugins that OpenTofu may run, keyed by the file name of the plugin
+// executable. Plugins with an invalid checksum are left out; Validate
+// reports them.
+func (c *Config) EncryptionPluginChecksums() map[string][]byte {
+	if c == nil || len(c.EncryptionPlugins) == 0 {
+		return nil
+	}
+	ret := make(map[string][]byte, len(c.EncryptionPlugins))
+	for name, plugin := range c.EncryptionPlugins {
+		if checksum, err := plugin.checksum(); err == nil {
+			ret[name] = checksum
+		}
+	}
+	return ret
+}
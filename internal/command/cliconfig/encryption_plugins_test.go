@@ -0,0 +1,36 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"crypto/sha256"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_encryptionPlugins(t *testing.T) {
+	c, diags := loadConfigFile(filepath.Join(fixtureDir, "encryption-plugins"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	diags = c.Validate()
+	if !diags.HasErrors() || !strings.Contains(diags.Err().Error(), `The encryption_plugin "tofu-encryption-method-xchacha" block is invalid`) {
+		t.Fatalf("expected an error for the invalid checksum, got: %v", diags.Err())
+	}
+
+	// The plugin with the invalid checksum is not allowed to run.
+	checksum := sha256.Sum256(nil)
+	want := map[string][]byte{
+		"tofu-encryption-keyprovider-conjur": checksum[:],
+	}
+	if diff := cmp.Diff(want, c.EncryptionPluginChecksums()); diff != "" {
+		t.Errorf("wrong checksums\n%s", diff)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/command/notifications"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigNotifications is the structure of the "notifications" block within
+// the CLI configuration, which declares the external services that are
+// notified about the progress of plan and apply operations.
+type ConfigNotifications struct {
+	Webhooks map[string]*ConfigNotificationWebhook `hcl:"webhook"`
+}
+
+// ConfigNotificationWebhook is the structure of the "webhook" nested block
+// within a "notifications" block.
+type ConfigNotificationWebhook struct {
+	URL      string            `hcl:"url"`
+	Events   []string          `hcl:"events"`
+	Template string            `hcl:"template"`
+	Headers  map[string]string `hcl:"headers"`
+}
+
+// decodeNotificationsFromConfig uses the HCL AST API directly to decode
+// "notifications" blocks from the given file.
+//
+// HCL 1's DecodeObject would split a single block into one element per
+// argument when decoding into a slice, so we decode each block separately.
+func decodeNotificationsFromConfig(hclFile *hclast.File) ([]*ConfigNotifications, tfdiags.Diagnostics) {
+	var ret []*ConfigNotifications
+	var diags tfdiags.Diagnostics
+
+	root, ok := hclFile.Node.(*hclast.ObjectList)
+	if !ok {
+		// A HCL file that doesn't have an object list at its root is weird, but
+		// dealing with that is outside the scope of this function.
+		return ret, diags
+	}
+	for _, block := range root.Items {
+		if block.Keys[0].Token.Value() != "notifications" {
+			continue
+		}
+
+		const errInvalidSummary = "Invalid notifications block"
+		isJSON := block.Keys[0].Token.JSON
+		if block.Assign.Line != 0 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The notifications block at %s must not be introduced with an equals sign.", block.Pos()),
+			))
+			continue
+		}
+		if len(block.Keys) > 1 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The notifications block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+		body, ok := block.Val.(*hclast.ObjectType)
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The notifications block at %s must be represented by a JSON object.", block.Pos()),
+			))
+			continue
+		}
+
+		result := &ConfigNotifications{}
+		if err := hcl.DecodeObject(result, body); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("Invalid notifications block at %s: %s.", body.Pos(), err),
+			))
+			continue
+		}
+		// Webhook URLs and headers usually contain secrets, which are best
+		// kept out of the configuration file.
+		for _, webhook := range result.Webhooks {
+			webhook.URL = os.ExpandEnv(webhook.URL)
+			for k, v := range webhook.Headers {
+				webhook.Headers[k] = os.ExpandEnv(v)
+			}
+		}
+		ret = append(ret, result)
+	}
+
+	return ret, diags
+}
+
+// webhooks returns the webhooks declared in the block, ordered by name.
+func (c *ConfigNotifications) webhooks() ([]*notifications.Webhook, error) {
+	names := make([]string, 0, len(c.Webhooks))
+	for name := range c.Webhooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]*notifications.Webhook, 0, len(names))
+	for _, name := range names {
+		cfg := c.Webhooks[name]
+		webhook, err := notifications.NewWebhook(name, cfg.URL, cfg.Events, cfg.Template, cfg.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %q: %w", name, err)
+		}
+		ret = append(ret, webhook)
+	}
+	return ret, nil
+}
+
+// NotificationWebhooks returns the webhooks to notify about the progress of
+// plan and apply operations, as configured in the CLI configuration. It
+// returns nothing if there is no valid notifications block; Validate reports
+// the problems with an invalid one.
+func (c *Config) NotificationWebhooks() []*notifications.Webhook {
+	if c == nil || len(c.Notifications) != 1 {
+		return nil
+	}
+	webhooks, err := c.Notifications[0].webhooks()
+	if err != nil {
+		return nil
+	}
+	return webhooks
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/notifications"
+)
+
+func TestLoadConfig_notifications(t *testing.T) {
+	t.Setenv("TF_TEST_NOTIFICATIONS_TOKEN", "secret")
+
+	c, diags := loadConfigFile(filepath.Join(fixtureDir, "notifications"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := c.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	webhooks := c.NotificationWebhooks()
+	if len(webhooks) != 2 {
+		t.Fatalf("wrong number of webhooks: got %d, want 2", len(webhooks))
+	}
+
+	audit, slack := webhooks[0], webhooks[1]
+	if got, want := slack.URL, "https://hooks.slack.example.com/services/secret"; got != want {
+		t.Errorf("wrong url %q; want %q", got, want)
+	}
+	if diff := cmp.Diff([]notifications.Event{notifications.EventApplySuccess, notifications.EventApplyFailure}, slack.Events); diff != "" {
+		t.Errorf("wrong events\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"Authorization": "Bearer secret"}, audit.Headers); diff != "" {
+		t.Errorf("wrong headers\n%s", diff)
+	}
+	text, err := audit.Text(notifications.Message{Event: notifications.EventPlanComplete, Workspace: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text, "plan_complete in prod"; got != want {
+		t.Errorf("wrong text %q; want %q", got, want)
+	}
+}
+
+func TestConfigValidate_notifications(t *testing.T) {
+	tests := map[string]struct {
+		config  *Config
+		wantErr string
+	}{
+		"multiple blocks": {
+			config: &Config{
+				Notifications: []*ConfigNotifications{{}, {}},
+			},
+			wantErr: "No more than one notifications block may be specified",
+		},
+		"invalid event": {
+			config: &Config{
+				Notifications: []*ConfigNotifications{{
+					Webhooks: map[string]*ConfigNotificationWebhook{
+						"slack": {URL: "https://example.com", Events: []string{"destroy"}},
+					},
+				}},
+			},
+			wantErr: `The notifications block is invalid: webhook "slack": unsupported event "destroy"; must be one of "plan_complete", "apply_start", "apply_success", "apply_failure", "sensitive_reveal"`,
+		},
+		"invalid url": {
+			config: &Config{
+				Notifications: []*ConfigNotifications{{
+					Webhooks: map[string]*ConfigNotificationWebhook{
+						"slack": {URL: "example.com"},
+					},
+				}},
+			},
+			wantErr: `The notifications block is invalid: webhook "slack": invalid url "example.com": must be an http or https URL`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := test.config.Validate()
+			if got := diags.Err().Error(); got != test.wantErr {
+				t.Errorf("wrong error %q; want %q", got, test.wantErr)
+			}
+			if webhooks := test.config.NotificationWebhooks(); len(webhooks) != 0 {
+				t.Errorf("unexpected webhooks for an invalid configuration: %#v", webhooks)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigStateLockRetry is the structure of the "state_lock_retry" block
+// within the CLI configuration, which customizes how OpenTofu retries to
+// acquire a state lock held by another process.
+//
+// Unset arguments keep the values of statemgr.DefaultLockRetryPolicy.
+type ConfigStateLockRetry struct {
+	InitialDelay string  `hcl:"initial_delay"`
+	MaxDelay     string  `hcl:"max_delay"`
+	Multiplier   float64 `hcl:"multiplier"`
+	Jitter       float64 `hcl:"jitter"`
+	MaxAttempts  int     `hcl:"max_attempts"`
+	MaxElapsed   string  `hcl:"max_elapsed"`
+}
+
+// decodeStateLockRetryFromConfig uses the HCL AST API directly to decode
+// "state_lock_retry" blocks from the given file.
+//
+// HCL 1's DecodeObject would split a single block into one element per
+// argument when decoding into a slice, so we decode each block separately.
+func decodeStateLockRetryFromConfig(hclFile *hclast.File) ([]*ConfigStateLockRetry, tfdiags.Diagnostics) {
+	var ret []*ConfigStateLockRetry
+	var diags tfdiags.Diagnostics
+
+	root, ok := hclFile.Node.(*hclast.ObjectList)
+	if !ok {
+		// A HCL file that doesn't have an object list at its root is weird, but
+		// dealing with that is outside the scope of this function.
+		return ret, diags
+	}
+	for _, block := range root.Items {
+		if block.Keys[0].Token.Value() != "state_lock_retry" {
+			continue
+		}
+
+		const errInvalidSummary = "Invalid state_lock_retry block"
+		isJSON := block.Keys[0].Token.JSON
+		if block.Assign.Line != 0 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The state_lock_retry block at %s must not be introduced with an equals sign.", block.Pos()),
+			))
+			continue
+		}
+		if len(block.Keys) > 1 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The state_lock_retry block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+		body, ok := block.Val.(*hclast.ObjectType)
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("The state_lock_retry block at %s must be represented by a JSON object.", block.Pos()),
+			))
+			continue
+		}
+
+		result := &ConfigStateLockRetry{}
+		if err := hcl.DecodeObject(result, body); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				errInvalidSummary,
+				fmt.Sprintf("Invalid state_lock_retry block at %s: %s.", body.Pos(), err),
+			))
+			continue
+		}
+		ret = append(ret, result)
+	}
+
+	return ret, diags
+}
+
+// Policy returns the retry policy described by the block.
+func (c *ConfigStateLockRetry) Policy() (statemgr.LockRetryPolicy, error) {
+	policy := statemgr.DefaultLockRetryPolicy()
+
+	for _, d := range []struct {
+		name   string
+		raw    string
+		target *time.Duration
+	}{
+		{"initial_delay", c.InitialDelay, &policy.InitialDelay},
+		{"max_delay", c.MaxDelay, &policy.MaxDelay},
+		{"max_elapsed", c.MaxElapsed, &policy.MaxElapsed},
+	} {
+		if d.raw == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.raw)
+		if err != nil {
+			return policy, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		*d.target = v
+	}
+	if c.MaxDelay == "" && policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+	if c.Multiplier != 0 {
+		policy.Multiplier = c.Multiplier
+	}
+	policy.Jitter = c.Jitter
+	policy.MaxAttempts = c.MaxAttempts
+
+	return policy, policy.Validate()
+}
+
+// StateLockRetryPolicy returns the policy for retrying to acquire a state
+// lock, as configured in the CLI configuration. It returns
+// statemgr.DefaultLockRetryPolicy if there is no valid state_lock_retry
+// block; Validate reports the problems with an invalid one.
+func (c *Config) StateLockRetryPolicy() statemgr.LockRetryPolicy {
+	if c == nil || len(c.StateLockRetry) != 1 {
+		return statemgr.DefaultLockRetryPolicy()
+	}
+	policy, err := c.StateLockRetry[0].Policy()
+	if err != nil {
+		return statemgr.DefaultLockRetryPolicy()
+	}
+	return policy
+}
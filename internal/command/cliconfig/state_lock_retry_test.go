@@ -0,0 +1,102 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestLoadConfig_stateLockRetry(t *testing.T) {
+	c, diags := loadConfigFile(filepath.Join(fixtureDir, "state-lock-retry"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := c.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	want := statemgr.LockRetryPolicy{
+		InitialDelay: 500 * time.Millisecond,
+		Multiplier:   1.5,
+		MaxDelay:     30 * time.Second,
+		Jitter:       0.2,
+		MaxAttempts:  10,
+		MaxElapsed:   5 * time.Minute,
+	}
+	if diff := cmp.Diff(want, c.StateLockRetryPolicy()); diff != "" {
+		t.Errorf("wrong policy\n%s", diff)
+	}
+}
+
+func TestConfigStateLockRetry_Policy(t *testing.T) {
+	tests := map[string]struct {
+		block   ConfigStateLockRetry
+		want    statemgr.LockRetryPolicy
+		wantErr string
+	}{
+		"empty": {
+			block: ConfigStateLockRetry{},
+			want:  statemgr.DefaultLockRetryPolicy(),
+		},
+		"initial delay above default max delay": {
+			block: ConfigStateLockRetry{InitialDelay: "1m"},
+			want: statemgr.LockRetryPolicy{
+				InitialDelay: time.Minute,
+				Multiplier:   2,
+				MaxDelay:     time.Minute,
+			},
+		},
+		"invalid duration": {
+			block:   ConfigStateLockRetry{MaxElapsed: "soon"},
+			wantErr: `invalid max_elapsed: time: invalid duration "soon"`,
+		},
+		"invalid jitter": {
+			block:   ConfigStateLockRetry{Jitter: 2},
+			wantErr: "the jitter must be between 0 and 1",
+		},
+		"max delay below initial delay": {
+			block:   ConfigStateLockRetry{InitialDelay: "10s", MaxDelay: "1s"},
+			wantErr: "the maximum delay must not be less than the initial delay",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.block.Policy()
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("wrong error %v; want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong policy\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_stateLockRetry(t *testing.T) {
+	c := &Config{
+		StateLockRetry: []*ConfigStateLockRetry{{}, {}},
+	}
+	diags := c.Validate()
+	if got, want := diags.Err().Error(), "No more than one state_lock_retry block may be specified"; got != want {
+		t.Errorf("wrong error %q; want %q", got, want)
+	}
+	if diff := cmp.Diff(statemgr.DefaultLockRetryPolicy(), c.StateLockRetryPolicy()); diff != "" {
+		t.Errorf("wrong policy\n%s", diff)
+	}
+}
@@ -12,6 +12,7 @@ package clistate
 import (
 	"context"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
@@ -72,6 +73,7 @@ type Locker interface {
 type locker struct {
 	ctx     context.Context
 	timeout time.Duration
+	policy  statemgr.LockRetryPolicy
 	mu      sync.Mutex
 	state   statemgr.Locker
 	view    views.StateLocker
@@ -85,9 +87,17 @@ var _ Locker = (*locker)(nil)
 // timeout is reached, or the context is canceled. Lock progress will be be
 // reported to the user through the provided UI.
 func NewLocker(timeout time.Duration, view views.StateLocker) Locker {
+	return NewLockerWithRetryPolicy(timeout, statemgr.DefaultLockRetryPolicy(), view)
+}
+
+// NewLockerWithRetryPolicy is like NewLocker, but retries the lock according
+// to the given policy. A non-zero timeout takes precedence over the policy's
+// MaxElapsed.
+func NewLockerWithRetryPolicy(timeout time.Duration, policy statemgr.LockRetryPolicy, view views.StateLocker) Locker {
 	return &locker{
 		ctx:     context.Background(),
 		timeout: timeout,
+		policy:  policy,
 		view:    view,
 	}
 }
@@ -101,6 +111,7 @@ func (l *locker) WithContext(ctx context.Context) Locker {
 	return &locker{
 		ctx:     ctx,
 		timeout: l.timeout,
+		policy:  l.policy,
 		view:    l.view,
 	}
 }
@@ -116,14 +127,19 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 
 	l.state = s
 
-	ctx, cancel := context.WithTimeout(l.ctx, l.timeout)
+	policy := l.policy
+	if l.timeout != 0 {
+		policy.MaxElapsed = l.timeout
+	}
+	// Without a time limit we try to acquire the lock only once.
+	ctx, cancel := context.WithTimeout(l.ctx, policy.MaxElapsed)
 	defer cancel()
 
 	lockInfo := statemgr.NewLockInfo()
 	lockInfo.Operation = reason
 
 	err := slowmessage.Do(LockThreshold, func() error {
-		id, err := statemgr.LockWithContext(ctx, s, lockInfo)
+		id, err := statemgr.LockWithRetryPolicy(ctx, s, lockInfo, policy, l.waiting)
 		l.lockID = id
 		return err
 	}, l.view.Locking)
@@ -139,6 +155,12 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 	return diags
 }
 
+// waiting reports a wait for a lock held by another process.
+func (l *locker) waiting(wait statemgr.LockWait) {
+	log.Printf("[INFO] clistate: state is locked, retrying in %s (attempt %d, %s elapsed)", wait.Delay, wait.Attempt, wait.Elapsed)
+	l.view.Waiting(wait)
+}
+
 func (l *locker) Unlock() tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
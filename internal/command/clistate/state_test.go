@@ -6,7 +6,9 @@
 package clistate
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
@@ -28,3 +30,33 @@ func TestUnlock(t *testing.T) {
 		t.Error("expected error")
 	}
 }
+
+func TestLock_retryPolicy(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+
+	s := statemgr.NewFullFake(nil, nil)
+	if _, err := s.Lock(statemgr.NewLockInfo()); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := statemgr.LockRetryPolicy{
+		InitialDelay: time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     time.Millisecond,
+		MaxAttempts:  3,
+	}
+	l := NewLockerWithRetryPolicy(time.Minute, policy, views.NewStateLocker(arguments.ViewJSON, view))
+	diags := l.Lock(s, "test-lock")
+	if !diags.HasErrors() {
+		t.Fatal("expected an error after the maximum number of attempts")
+	}
+
+	stdout := done(t).Stdout()
+	if got := strings.Count(stdout, `"type":"state_lock_wait"`); got != 2 {
+		t.Errorf("wrong number of wait events %d\n%s", got, stdout)
+	}
+	if !strings.Contains(stdout, `"max_attempts":3`) {
+		t.Errorf("wait events don't include the maximum number of attempts\n%s", stdout)
+	}
+}
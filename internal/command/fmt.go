@@ -47,6 +47,7 @@ type FmtCommand struct {
 	diff      bool
 	check     bool
 	recursive bool
+	watch     bool
 	input     io.Reader // STDIN if nil
 }
 
@@ -62,6 +63,7 @@ func (c *FmtCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.diff, "diff", false, "diff")
 	cmdFlags.BoolVar(&c.check, "check", false, "check")
 	cmdFlags.BoolVar(&c.recursive, "recursive", false, "recursive")
+	cmdFlags.BoolVar(&c.watch, "watch", false, "watch")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -80,6 +82,11 @@ func (c *FmtCommand) Run(args []string) int {
 		paths = args
 	}
 
+	if c.watch && (len(paths) == 0 || c.check) {
+		c.Ui.Error("The -watch option cannot be used when reading from stdin or together with -check.")
+		return 1
+	}
+
 	var output io.Writer
 	list := c.list // preserve the original value of -list
 	if c.check {
@@ -92,6 +99,10 @@ func (c *FmtCommand) Run(args []string) int {
 		output = &cli.UiWriter{Ui: c.Ui}
 	}
 
+	if c.watch {
+		return c.fmtWatch(paths, output)
+	}
+
 	diags := c.fmt(paths, c.input, output)
 	c.showDiagnostics(diags)
 	if diags.HasErrors() {
@@ -171,6 +182,48 @@ func (c *FmtCommand) fmt(paths []string, stdin io.Reader, stdout io.Writer) tfdi
 	return diags
 }
 
+// fmtWatch formats the files at the given paths, and then keeps formatting
+// any of them that change until interrupted. The exit code reflects the
+// result of the last run.
+func (c *FmtCommand) fmtWatch(paths []string, stdout io.Writer) int {
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+
+	watcher := newFileWatcher(paths, c.recursive, func(name string) bool {
+		for _, ext := range fmtSupportedExts {
+			if strings.HasSuffix(name, ext) {
+				return true
+			}
+		}
+		return false
+	})
+
+	diags := c.fmt(paths, nil, stdout)
+	for {
+		c.showDiagnostics(diags)
+		ret := 0
+		if diags.HasErrors() {
+			ret = 2
+		}
+
+		changed := watcher.Wait(ctx)
+		if changed == nil {
+			return ret
+		}
+
+		// Formatting a file changes it again, but formatting it a second
+		// time makes no further changes, so this doesn't loop forever.
+		diags = nil
+		for _, path := range changed {
+			if _, err := os.Stat(path); err != nil {
+				// The file was deleted.
+				continue
+			}
+			diags = diags.Append(c.fmt([]string{path}, nil, stdout))
+		}
+	}
+}
+
 func (c *FmtCommand) processFile(path string, r io.Reader, w io.Writer, isStdout bool) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
@@ -583,6 +636,10 @@ Options:
 
   -recursive     Also process files in subdirectories. By default, only the
                  given directory (or current directory) is processed.
+
+  -watch         Keep running after formatting the files, and format them
+                 again whenever they change, until interrupted. Cannot be
+                 used with -check or when reading from STDIN.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
@@ -477,6 +478,76 @@ func TestFmt_checkStdin(t *testing.T) {
 	}
 }
 
+func TestFmt_watch(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	shutdownCh := make(chan struct{})
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			ShutdownCh:       shutdownCh,
+		},
+	}
+
+	codeCh := make(chan int)
+	go func() {
+		codeCh <- c.Run([]string{"-watch", tempDir})
+	}()
+
+	// waitFormatted writes the given file until the running command has
+	// formatted it, since a write made before the command started watching
+	// would not be noticed as a change.
+	waitFormatted := func(path string, write bool) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if write {
+				if err := os.WriteFile(path, fmtFixture.input, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			time.Sleep(4 * watchInterval)
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(got, fmtFixture.golden) {
+				return
+			}
+		}
+		t.Fatalf("%s was not formatted", path)
+	}
+
+	waitFormatted(filepath.Join(tempDir, fmtFixture.filename), false)
+	waitFormatted(filepath.Join(tempDir, "other.tf"), true)
+
+	close(shutdownCh)
+	if code := <-codeCh; code != 0 {
+		t.Fatalf("wrong exit code %d; output:\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
+func TestFmt_watchCheck(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-watch", "-check", tempDir}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "cannot be used when reading from stdin or together with -check"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 var fmtFixture = struct {
 	filename      string
 	altFilename   string
@@ -28,6 +28,7 @@ func (c *GetCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("get")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.BoolVar(&update, "update", false, "update")
+	cmdFlags.StringVar(&c.Meta.moduleChannel, "channel", "", "module channel")
 	cmdFlags.StringVar(&testsDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&c.outputInJSON, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
@@ -88,6 +89,10 @@ Options:
   -update               Check already-downloaded modules for available updates
                         and install the newest versions available.
 
+  -channel=NAME         Install the exact registry module versions selected
+                        by the module channel NAME, which is read from the
+                        file channels/NAME.json in the root module directory.
+
   -no-color             Disable text coloring in the output.
 
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
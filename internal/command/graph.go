@@ -32,6 +32,9 @@ func (c *GraphCommand) Run(args []string) int {
 	var moduleDepth int
 	var verbose bool
 	var planPath string
+	var format string
+	var collapseModules int
+	var include string
 
 	ctx := c.CommandContext()
 
@@ -43,6 +46,9 @@ func (c *GraphCommand) Run(args []string) int {
 	cmdFlags.IntVar(&moduleDepth, "module-depth", -1, "module-depth")
 	cmdFlags.BoolVar(&verbose, "verbose", false, "verbose")
 	cmdFlags.StringVar(&planPath, "plan", "", "plan")
+	cmdFlags.StringVar(&format, "format", "dot", "format")
+	cmdFlags.IntVar(&collapseModules, "collapse-modules", -1, "collapse-modules")
+	cmdFlags.StringVar(&include, "include", "", "include")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -55,6 +61,23 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
+	// The summarized graph only shows the objects of the configuration,
+	// so it is used whenever any of the options that affect it are set.
+	summarize := format != "dot" || collapseModules >= 0 || include != ""
+	summaryOpts, summaryDiags := parseGraphSummaryOpts(format, collapseModules, include)
+	diags = diags.Append(summaryDiags)
+	if summarize && (drawCycles || verbose) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible graph options",
+			"The -draw-cycles and -verbose options show the internal steps of an operation, so they can't be combined with the -format=mermaid, -collapse-modules, or -include options.",
+		))
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Check for user-supplied plugin path
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
@@ -225,14 +248,22 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
-	graphStr, err := tofu.GraphDot(g, &dag.DotOpts{
-		DrawCycles: drawCycles,
-		MaxDepth:   moduleDepth,
-		Verbose:    verbose,
-	})
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
-		return 1
+	var graphStr string
+	switch {
+	case summarize && format == "mermaid":
+		graphStr = tofu.SummarizeGraph(g, summaryOpts).Mermaid()
+	case summarize:
+		graphStr = tofu.SummarizeGraph(g, summaryOpts).Dot()
+	default:
+		graphStr, err = tofu.GraphDot(g, &dag.DotOpts{
+			DrawCycles: drawCycles,
+			MaxDepth:   moduleDepth,
+			Verbose:    verbose,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
+			return 1
+		}
 	}
 
 	if diags.HasErrors() {
@@ -248,6 +279,39 @@ func (c *GraphCommand) Run(args []string) int {
 	return 0
 }
 
+// parseGraphSummaryOpts validates the options of the summarized graph.
+func parseGraphSummaryOpts(format string, collapseModules int, include string) (tofu.GraphSummaryOpts, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	opts := tofu.GraphSummaryOpts{
+		CollapseModules: collapseModules,
+	}
+
+	if format != "dot" && format != "mermaid" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported graph format",
+			`The -format=... argument must be either "dot" or "mermaid".`,
+		))
+	}
+
+	if include != "" {
+		for _, kind := range strings.Split(include, ",") {
+			switch kind := tofu.GraphSummaryNodeKind(strings.TrimSpace(kind)); kind {
+			case tofu.GraphSummaryResource, tofu.GraphSummaryData, tofu.GraphSummaryProvider:
+				opts.Include = append(opts.Include, kind)
+			default:
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid -include option",
+					fmt.Sprintf(`The -include=... argument must be a comma-separated list of "resources", "data", and "providers", but it contains %q.`, kind),
+				))
+			}
+		}
+	}
+
+	return opts, diags
+}
+
 func (c *GraphCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] graph [options]
@@ -255,9 +319,13 @@ Usage: tofu [global options] graph [options]
   Produces a representation of the dependency graph between different
   objects in the current configuration and state.
 
-  The graph is presented in the DOT language. The typical program that can
-  read this format is GraphViz, but many web services are also available
-  to read this format.
+  The graph is presented in the DOT language by default. The typical program
+  that can read this format is GraphViz, but many web services are also
+  available to read this format.
+
+  The -format=mermaid, -collapse-modules, and -include options produce a
+  simplified graph which only contains the resources, data resources, and
+  providers of the configuration, meant for documentation.
 
 Options:
 
@@ -274,6 +342,19 @@ Options:
   -module-depth=n  (deprecated) In prior versions of OpenTofu, specified the
 				   depth of modules to show in the output.
 
+  -format=dot      Format of the output. Can be: dot, or mermaid for a
+                   Mermaid flowchart.
+
+  -collapse-modules=n
+                   Show the objects of only n levels of modules, and a single
+                   node for each module below that, which represents all of
+                   its objects. Use 0 to show a node for each module called
+                   by the root module.
+
+  -include=resources,data,providers
+                   Comma-separated list of the kinds of objects to show. By
+                   default all of them are shown.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
@@ -42,6 +42,64 @@ func TestGraph(t *testing.T) {
 	}
 }
 
+func TestGraph_mermaid(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("graph"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{"-format=mermaid", "-include=resources"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	want := "flowchart LR\n\tn0[\"test_instance.foo\"]\n"
+	if output != want+"\n" {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", output, want)
+	}
+}
+
+func TestGraph_invalidSummaryOptions(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"format":  {[]string{"-format=svg"}, "Unsupported graph format"},
+		"include": {[]string{"-include=outputs"}, "Invalid -include option"},
+		"verbose": {[]string{"-format=mermaid", "-verbose"}, "Incompatible graph options"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath("graph"), td)
+			defer testChdir(t, td)()
+
+			ui := new(cli.MockUi)
+			c := &GraphCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+					Ui:               ui,
+				},
+			}
+
+			if code := c.Run(test.args); code != 1 {
+				t.Fatalf("wrong exit code %d; want 1\n%s", code, ui.OutputWriter.String())
+			}
+			if got := ui.ErrorWriter.String(); !strings.Contains(got, test.want) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
 func TestGraph_multipleArgs(t *testing.T) {
 	ui := new(cli.MockUi)
 	c := &GraphCommand{
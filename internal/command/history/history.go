@@ -0,0 +1,310 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package history records the plan, apply and destroy operations run in a
+// working directory, so that operators can find out what happened in a
+// working directory that is shared between people or automation runs.
+//
+// The history of each workspace is an append-only log of events in the
+// working directory's data directory. An operation appends one event when it
+// starts and another when it finishes, and the entries shown to the user are
+// rebuilt from those events. An operation that never finished, for example
+// because the process was killed, therefore still appears in the history.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir is the name of the directory within the data directory that contains
+// the history of each workspace.
+const Dir = "history"
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// EventStart records the start of an operation, along with what is known
+	// about it before it runs.
+	EventStart EventType = "start"
+
+	// EventFinish records the result of an operation.
+	EventFinish EventType = "finish"
+)
+
+// Outcome describes how an operation ended.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+
+	// OutcomeIncomplete is the outcome of an operation that has started but
+	// hasn't finished, either because it is still running or because the
+	// process running it stopped before it could record its result.
+	OutcomeIncomplete Outcome = "incomplete"
+)
+
+// Summary counts the changes of an operation.
+type Summary struct {
+	Add    int `json:"add"`
+	Change int `json:"change"`
+	Remove int `json:"remove"`
+	Import int `json:"import"`
+	Forget int `json:"forget"`
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d to add, %d to change, %d to destroy", s.Add, s.Change, s.Remove)
+}
+
+// Event is one record in the history of a workspace. Fields that don't apply
+// to the event type are omitted.
+type Event struct {
+	Type EventType `json:"type"`
+
+	// ID identifies the operation that the event belongs to.
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+
+	// The following fields are set for EventStart.
+	Operation string   `json:"operation,omitempty"`
+	Args      []string `json:"args,omitempty"`
+	User      string   `json:"user,omitempty"`
+	Host      string   `json:"host,omitempty"`
+	Version   string   `json:"version,omitempty"`
+
+	// Serial is the serial of the latest state snapshot when the operation
+	// started, for EventStart, or when it finished, for EventFinish. It is
+	// nil if the serial wasn't available, such as when the workspace has no
+	// state yet or the state storage doesn't report serials.
+	Serial *uint64 `json:"serial,omitempty"`
+
+	// The following fields are set for EventFinish.
+	Outcome Outcome  `json:"outcome,omitempty"`
+	Summary *Summary `json:"summary,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Entry is an operation in the history of a workspace, rebuilt from its
+// events.
+type Entry struct {
+	ID        string   `json:"id"`
+	Operation string   `json:"operation"`
+	Args      []string `json:"args"`
+	User      string   `json:"user,omitempty"`
+	Host      string   `json:"host,omitempty"`
+	Version   string   `json:"version,omitempty"`
+
+	Started time.Time `json:"started"`
+
+	// Finished is nil for an incomplete operation.
+	Finished *time.Time `json:"finished,omitempty"`
+
+	Outcome      Outcome  `json:"outcome"`
+	Summary      *Summary `json:"summary,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	SerialBefore *uint64  `json:"serial_before,omitempty"`
+	SerialAfter  *uint64  `json:"serial_after,omitempty"`
+}
+
+// Duration returns how long the operation took, or zero if it is
+// incomplete.
+func (e *Entry) Duration() time.Duration {
+	if e.Finished == nil {
+		return 0
+	}
+	return e.Finished.Sub(e.Started)
+}
+
+// Log is the history of one workspace.
+type Log struct {
+	path string
+}
+
+// NewLog returns the history of the given workspace, stored in the given
+// data directory. The history file is created when the first event is
+// appended.
+func NewLog(dataDir, workspace string) *Log {
+	return &Log{
+		path: filepath.Join(dataDir, Dir, workspace+".jsonl"),
+	}
+}
+
+// NewID returns a new random operation ID.
+func NewID() string {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		// This should not happen, since crypto/rand doesn't fail on the
+		// platforms we support.
+		panic(fmt.Sprintf("failed to generate operation ID: %s", err))
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// Append adds the given event to the end of the history.
+//
+// Each event is written in a single write to a file opened for appending,
+// so events from operations running at the same time in the same working
+// directory are not interleaved.
+func (l *Log) Append(ev Event) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns the operations in the history, oldest first.
+//
+// Lines of the history file that can't be decoded, such as a line that was
+// only partly written when the process stopped, are skipped.
+func (l *Log) Entries() ([]*Entry, error) {
+	src, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Entry)
+	var entries []*Entry
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil || ev.ID == "" {
+			continue
+		}
+		entry, ok := byID[ev.ID]
+		if !ok {
+			entry = &Entry{ID: ev.ID, Outcome: OutcomeIncomplete}
+			byID[ev.ID] = entry
+			entries = append(entries, entry)
+		}
+		apply(entry, ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Started.Before(entries[j].Started)
+	})
+	return entries, nil
+}
+
+// Entry returns the operation with the given ID, or with the only ID that
+// starts with the given prefix. It returns nil if there is no such
+// operation.
+func (l *Log) Entry(id string) (*Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var found *Entry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("more than one operation has an ID starting with %q", id)
+			}
+			found = entry
+		}
+	}
+	return found, nil
+}
+
+// apply updates the given entry with the given event.
+func apply(entry *Entry, ev Event) {
+	switch ev.Type {
+	case EventStart:
+		entry.Operation = ev.Operation
+		entry.Args = ev.Args
+		entry.User = ev.User
+		entry.Host = ev.Host
+		entry.Version = ev.Version
+		entry.Started = ev.Time
+		entry.SerialBefore = ev.Serial
+	case EventFinish:
+		finished := ev.Time
+		entry.Finished = &finished
+		entry.Outcome = ev.Outcome
+		entry.Summary = ev.Summary
+		entry.Error = ev.Error
+		entry.SerialAfter = ev.Serial
+	}
+}
+
+// redactedFlags are the command line options whose values may contain
+// secrets. Their values are name=value pairs, or paths to files containing
+// them.
+var redactedFlags = map[string]bool{
+	"var":            true,
+	"backend-config": true,
+}
+
+// RedactArgs returns a copy of the given command line arguments with the
+// values of the options in redactedFlags, such as -var and -backend-config,
+// removed, since they may contain secrets. The names of the variables and
+// settings are kept, as are the paths of files.
+func RedactArgs(args []string) []string {
+	ret := make([]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch {
+		case !strings.HasPrefix(arg, "-") || !redactedFlags[name]:
+			ret[i] = arg
+		case hasValue:
+			ret[i] = strings.TrimSuffix(arg, value) + redactValue(value)
+		default:
+			ret[i] = arg
+			if i+1 < len(args) {
+				i++
+				ret[i] = redactValue(args[i])
+			}
+		}
+	}
+	return ret
+}
+
+// redactValue replaces the value in the given name=value argument of an
+// option in redactedFlags. Arguments without a value, such as the path of
+// a file, are kept.
+func redactValue(s string) string {
+	name, _, ok := strings.Cut(s, "=")
+	if !ok {
+		return s
+	}
+	return name + "=(redacted)"
+}
@@ -0,0 +1,132 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLog(dir, "default")
+
+	entries, err := l.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("unexpected entries in new history: %#v", entries)
+	}
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	serial := func(s uint64) *uint64 { return &s }
+	events := []Event{
+		{Type: EventStart, ID: "aaaa0001", Time: start, Operation: "apply", Args: []string{"-auto-approve"}, Serial: serial(3)},
+		{Type: EventStart, ID: "bbbb0002", Time: start.Add(time.Second), Operation: "plan"},
+		{Type: EventFinish, ID: "aaaa0001", Time: start.Add(time.Minute), Outcome: OutcomeSuccess, Summary: &Summary{Add: 2}, Serial: serial(4)},
+	}
+	for _, ev := range events {
+		if err := l.Append(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A line that was only partly written is skipped.
+	f, err := os.OpenFile(filepath.Join(dir, Dir, "default.jsonl"), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"type":"finish","id":"bbbb`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	entries, err = l.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	finished := start.Add(time.Minute)
+	want := []*Entry{
+		{
+			ID:           "aaaa0001",
+			Operation:    "apply",
+			Args:         []string{"-auto-approve"},
+			Started:      start,
+			Finished:     &finished,
+			Outcome:      OutcomeSuccess,
+			Summary:      &Summary{Add: 2},
+			SerialBefore: serial(3),
+			SerialAfter:  serial(4),
+		},
+		{
+			ID:        "bbbb0002",
+			Operation: "plan",
+			Started:   start.Add(time.Second),
+			Outcome:   OutcomeIncomplete,
+		},
+	}
+	if diff := cmp.Diff(want, entries); diff != "" {
+		t.Errorf("wrong entries\n%s", diff)
+	}
+	if got, want := entries[0].Duration(), time.Minute; got != want {
+		t.Errorf("wrong duration %s; want %s", got, want)
+	}
+
+	entry, err := l.Entry("bbbb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry == nil || entry.ID != "bbbb0002" {
+		t.Errorf("wrong entry for prefix: %#v", entry)
+	}
+	entry, err = l.Entry("cccc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Errorf("unexpected entry for unknown ID: %#v", entry)
+	}
+
+	// Each workspace has its own history.
+	entries, err = NewLog(dir, "other").Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("unexpected entries in history of other workspace: %#v", entries)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	got := RedactArgs([]string{
+		"-auto-approve",
+		"-var", "password=hunter2",
+		"-var=token=abc=def",
+		"-var-file=prod.tfvars",
+		"-backend-config", "secret_key=abc",
+		"--backend-config=access_key=def",
+		"-backend-config=backend.hcl",
+		"-var",
+	})
+	want := []string{
+		"-auto-approve",
+		"-var", "password=(redacted)",
+		"-var=token=(redacted)",
+		"-var-file=prod.tfvars",
+		"-backend-config", "secret_key=(redacted)",
+		"--backend-config=access_key=(redacted)",
+		"-backend-config=backend.hcl",
+		"-var",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// HistoryCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type HistoryCommand struct {
+	Meta
+}
+
+func (c *HistoryCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *HistoryCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] history <subcommand> [options] [args]
+
+  This command has subcommands for inspecting the history of the plan,
+  apply and destroy operations run in the current workspace of this
+  working directory.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *HistoryCommand) Synopsis() string {
+	return "Show the history of operations in this working directory"
+}
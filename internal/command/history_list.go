@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/command/history"
+)
+
+// HistoryListCommand is a Command implementation that lists the operations
+// in the history of the current workspace.
+type HistoryListCommand struct {
+	Meta
+}
+
+func (c *HistoryListCommand) Run(args []string) int {
+	var jsonOutput bool
+	var limit int
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("history list")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.IntVar(&limit, "limit", 0, "limit")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The history list command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	entries, err := history.NewLog(c.DataDir(), workspace).Entries()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading the history of workspace %q: %s", workspace, err))
+		return 1
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	if jsonOutput {
+		if entries == nil {
+			entries = []*history.Entry{}
+		}
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error encoding the history: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		return 0
+	}
+
+	if len(entries) == 0 {
+		c.Ui.Output(fmt.Sprintf("No operations have been recorded for workspace %q.", workspace))
+		return 0
+	}
+
+	rows := [][]string{{"ID", "STARTED", "OPERATION", "OUTCOME", "DURATION", "CHANGES", "SERIAL"}}
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			entry.ID,
+			entry.Started.Local().Format(time.DateTime),
+			entry.Operation,
+			string(entry.Outcome),
+			historyDuration(entry),
+			historyChanges(entry),
+			historySerials(entry),
+		})
+	}
+	c.Ui.Output(formatHistoryTable(rows))
+	return 0
+}
+
+// formatHistoryTable formats the given rows as columns separated by two
+// spaces.
+func formatHistoryTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	var buf strings.Builder
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				line.WriteString(cell)
+				break
+			}
+			fmt.Fprintf(&line, "%-*s  ", widths[i], cell)
+		}
+		buf.WriteString(strings.TrimRight(line.String(), " "))
+		buf.WriteString("\n")
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func historyDuration(entry *history.Entry) string {
+	if entry.Finished == nil {
+		return "-"
+	}
+	return entry.Duration().Round(time.Second).String()
+}
+
+func historyChanges(entry *history.Entry) string {
+	if entry.Summary == nil {
+		return "-"
+	}
+	return fmt.Sprintf("+%d ~%d -%d", entry.Summary.Add, entry.Summary.Change, entry.Summary.Remove)
+}
+
+func historySerials(entry *history.Entry) string {
+	serial := func(s *uint64) string {
+		if s == nil {
+			return "-"
+		}
+		return fmt.Sprint(*s)
+	}
+	return serial(entry.SerialBefore) + " -> " + serial(entry.SerialAfter)
+}
+
+func (c *HistoryListCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] history list [options]
+
+  Lists the plan, apply and destroy operations that have been run in the
+  current workspace of this working directory, oldest first.
+
+  For each operation, the list shows its ID, when it started, its outcome,
+  how long it took, the number of resources it added, changed and destroyed
+  (or planned to, for a plan) and the serial of the latest state snapshot
+  before and after it ran. An operation whose outcome is "incomplete" is
+  either still running or was stopped before it could record its result.
+
+Options:
+
+  -json       Produce the list in a machine-readable JSON format.
+
+  -limit=n    Show only the n most recent operations.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *HistoryListCommand) Synopsis() string {
+	return "List the operations in the history of the workspace"
+}
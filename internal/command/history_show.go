@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/command/history"
+)
+
+// HistoryShowCommand is a Command implementation that shows the details of
+// one operation in the history of the current workspace.
+type HistoryShowCommand struct {
+	Meta
+}
+
+func (c *HistoryShowCommand) Run(args []string) int {
+	var jsonOutput bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("history show")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one argument expected: the ID of the operation.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	entry, err := history.NewLog(c.DataDir(), workspace).Entry(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading the history of workspace %q: %s", workspace, err))
+		return 1
+	}
+	if entry == nil {
+		c.Ui.Error(fmt.Sprintf("No operation with ID %q has been recorded for workspace %q.", args[0], workspace))
+		return 1
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error encoding the history: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		return 0
+	}
+
+	rows := [][]string{
+		{"ID:", entry.ID},
+		{"Workspace:", workspace},
+		{"Command:", strings.TrimSpace("tofu " + entry.Operation + " " + strings.Join(entry.Args, " "))},
+		{"User:", historyValue(entry.User)},
+		{"Host:", historyValue(entry.Host)},
+		{"Version:", historyValue(entry.Version)},
+		{"Started:", entry.Started.Local().Format(time.RFC3339)},
+	}
+	if entry.Finished != nil {
+		rows = append(rows,
+			[]string{"Finished:", entry.Finished.Local().Format(time.RFC3339)},
+			[]string{"Duration:", historyDuration(entry)},
+		)
+	}
+	rows = append(rows, []string{"Outcome:", string(entry.Outcome)})
+	if entry.Summary != nil {
+		changes := entry.Summary.String()
+		if entry.Summary.Import != 0 || entry.Summary.Forget != 0 {
+			changes = fmt.Sprintf("%s, %d imported, %d forgotten", changes, entry.Summary.Import, entry.Summary.Forget)
+		}
+		rows = append(rows, []string{"Changes:", changes})
+	}
+	rows = append(rows, []string{"State serial:", historySerials(entry)})
+	out := formatHistoryTable(rows)
+	if entry.Error != "" {
+		out += "\n\nError:\n\n" + entry.Error
+	}
+	c.Ui.Output(out)
+	return 0
+}
+
+func historyValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (c *HistoryShowCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] history show [options] ID
+
+  Shows the details of the operation with the given ID in the history of
+  the current workspace, as listed by "tofu history list". The ID can be
+  shortened to any prefix that identifies only one operation.
+
+  The details include the command line arguments of the operation, with the
+  values given with -var options removed, the user and host that ran it,
+  and the error it failed with, if any.
+
+Options:
+
+  -json       Show the operation in a machine-readable JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *HistoryShowCommand) Synopsis() string {
+	return "Show the details of an operation in the history of the workspace"
+}
@@ -0,0 +1,147 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/history"
+)
+
+func TestHistory_applyAndPlan(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+	if err := os.WriteFile("variables.tf", []byte(`variable "secret" {}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The history is only recorded in initialized working directories.
+	if err := os.Mkdir(DefaultDataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+
+	view, done := testView(t)
+	apply := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	if code := apply.Run([]string{"-auto-approve", "-var", "secret=hunter2"}); code != 0 {
+		t.Fatalf("apply failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+
+	view, done = testView(t)
+	plan := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	if code := plan.Run([]string{"-destroy", "-var=secret=hunter2"}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+
+	// Operations that fail are recorded too.
+	view, done = testView(t)
+	plan = &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	if code := plan.Run(nil); code != 1 {
+		t.Fatalf("plan succeeded; want failure\n\n%s", done(t).Stdout())
+	}
+	done(t)
+
+	entries, err := history.NewLog(DefaultDataDir, "default").Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("wrong number of entries %d; want 3", len(entries))
+	}
+
+	applied := entries[0]
+	if applied.Operation != "apply" || applied.Outcome != history.OutcomeSuccess {
+		t.Errorf("wrong apply entry %s %s", applied.Operation, applied.Outcome)
+	}
+	if got, want := strings.Join(applied.Args, " "), "-auto-approve -var secret=(redacted)"; got != want {
+		t.Errorf("wrong apply args %q; want %q", got, want)
+	}
+	if applied.Summary == nil || applied.Summary.Add != 1 {
+		t.Errorf("wrong apply summary %#v", applied.Summary)
+	}
+	if applied.SerialBefore != nil {
+		t.Errorf("apply has serial before %d; want none", *applied.SerialBefore)
+	}
+	if applied.SerialAfter == nil {
+		t.Errorf("apply has no serial after")
+	}
+
+	planned := entries[1]
+	if planned.Operation != "plan" || planned.Outcome != history.OutcomeSuccess {
+		t.Errorf("wrong plan entry %s %s", planned.Operation, planned.Outcome)
+	}
+	if planned.Summary == nil || planned.Summary.Remove != 1 {
+		t.Errorf("wrong plan summary %#v", planned.Summary)
+	}
+	if got, want := strings.Join(planned.Args, " "), "-destroy -var=secret=(redacted)"; got != want {
+		t.Errorf("wrong plan args %q; want %q", got, want)
+	}
+
+	failed := entries[2]
+	if failed.Outcome != history.OutcomeFailure {
+		t.Errorf("wrong outcome %s for failed plan", failed.Outcome)
+	}
+
+	ui := cli.NewMockUi()
+	list := &HistoryListCommand{Meta: Meta{Ui: ui}}
+	if code := list.Run(nil); code != 0 {
+		t.Fatalf("history list failed: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	for _, want := range []string{applied.ID, planned.ID, "+1 ~0 -0", "+0 ~0 -1", "failure"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history list output does not contain %q\n%s", want, out)
+		}
+	}
+
+	ui = cli.NewMockUi()
+	show := &HistoryShowCommand{Meta: Meta{Ui: ui}}
+	if code := show.Run([]string{applied.ID[:4]}); code != 0 {
+		t.Fatalf("history show failed: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	out = ui.OutputWriter.String()
+	for _, want := range []string{"tofu apply -auto-approve -var secret=(redacted)", "Outcome:       success", "1 to add, 0 to change, 0 to destroy"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history show output does not contain %q\n%s", want, out)
+		}
+	}
+}
+
+func TestHistoryShow_unknown(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &HistoryShowCommand{Meta: Meta{Ui: ui}}
+	if code := c.Run([]string{"abcd"}); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `No operation with ID "abcd"`; !strings.Contains(got, want) {
+		t.Errorf("error output does not contain %q\n%s", want, got)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"log"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/history"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// historyHook records an operation in the history of the current workspace,
+// which "tofu history" shows. It counts the applied changes of the
+// operation while it runs, and the planned changes from its plan. Failing to record the operation is logged,
+// but never fails the operation.
+type historyHook struct {
+	tofu.NilHook
+
+	// log is nil if the operation isn't being recorded.
+	log       *history.Log
+	id        string
+	operation string
+	be        backend.Enhanced
+	workspace string
+
+	mu      sync.Mutex
+	applied history.Summary
+	pending map[string]plans.Action
+	applyOp bool
+}
+
+var _ tofu.Hook = (*historyHook)(nil)
+
+// startHistory records the start of an operation of the given kind, run
+// with the given command line arguments, and returns the hook that records
+// its changes. The caller must call Finish once the operation has completed.
+//
+// The history is only recorded in working directories that have a data
+// directory, so that running an operation, such as applying a saved plan,
+// in a directory that was never initialized doesn't create one.
+func (m *Meta) startHistory(be backend.Enhanced, operation string, args []string) *historyHook {
+	workspace, err := m.Workspace()
+	if err != nil {
+		workspace = backend.DefaultStateName
+	}
+	h := &historyHook{
+		id:        history.NewID(),
+		operation: operation,
+		be:        be,
+		workspace: workspace,
+		pending:   make(map[string]plans.Action),
+	}
+	if info, err := os.Stat(m.DataDir()); err != nil || !info.IsDir() {
+		log.Printf("[TRACE] history: not recording %s operation, since there is no data directory", operation)
+		return h
+	}
+	h.log = history.NewLog(m.DataDir(), workspace)
+
+	ev := history.Event{
+		Type:      history.EventStart,
+		ID:        h.id,
+		Time:      time.Now().UTC(),
+		Operation: operation,
+		Args:      history.RedactArgs(args),
+		Version:   version.String(),
+		Serial:    h.stateSerial(),
+	}
+	if u, err := user.Current(); err == nil {
+		ev.User = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		ev.Host = host
+	}
+	h.append(ev)
+	return h
+}
+
+func (h *historyHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending[addr.String()] = action
+	h.applyOp = true
+	return tofu.HookActionContinue, nil
+}
+
+func (h *historyHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := addr.String()
+	action, ok := h.pending[key]
+	delete(h.pending, key)
+	if err == nil && ok && addr.Resource.Resource.Mode == addrs.ManagedResourceMode {
+		countHistoryAction(&h.applied, action)
+	}
+	return tofu.HookActionContinue, nil
+}
+
+func (h *historyHook) PostApplyImport(addr addrs.AbsResourceInstance, importing plans.ImportingSrc) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.applied.Import++
+	return tofu.HookActionContinue, nil
+}
+
+func (h *historyHook) PostApplyForget(_ addrs.AbsResourceInstance) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.applied.Forget++
+	return tofu.HookActionContinue, nil
+}
+
+// Finish records the result of the operation. The summary is of the applied
+// changes if the operation applied any, or of the planned changes otherwise.
+func (h *historyHook) Finish(op *backend.RunningOperation, diags tfdiags.Diagnostics) {
+	if h.log == nil {
+		return
+	}
+	ev := history.Event{
+		Type:    history.EventFinish,
+		ID:      h.id,
+		Outcome: history.OutcomeSuccess,
+		Serial:  h.stateSerial(),
+	}
+	if op == nil || op.Result != backend.OperationSuccess || diags.HasErrors() {
+		ev.Outcome = history.OutcomeFailure
+		if diags.HasErrors() {
+			ev.Error = diags.Err().Error()
+		}
+	}
+
+	h.mu.Lock()
+	summary := h.applied
+	if !h.applyOp {
+		summary = history.Summary{}
+		if op != nil && op.Plan != nil {
+			summary = countHistoryPlan(op.Plan)
+		}
+	}
+	h.mu.Unlock()
+	ev.Summary = &summary
+
+	ev.Time = time.Now().UTC()
+	h.append(ev)
+}
+
+func (h *historyHook) append(ev history.Event) {
+	if err := h.log.Append(ev); err != nil {
+		log.Printf("[WARN] history: failed to record %s of %s operation %s: %s", ev.Type, h.operation, h.id, err)
+	}
+}
+
+// stateSerial returns the serial of the latest state snapshot of the
+// workspace, or nil if it isn't available.
+func (h *historyHook) stateSerial() *uint64 {
+	mgr, err := h.be.StateMgr(h.workspace)
+	if err != nil {
+		log.Printf("[DEBUG] history: can't read the state serial: %s", err)
+		return nil
+	}
+	meta, ok := mgr.(statemgr.PersistentMeta)
+	if !ok {
+		return nil
+	}
+	if err := mgr.RefreshState(); err != nil {
+		log.Printf("[DEBUG] history: can't read the state serial: %s", err)
+		return nil
+	}
+	if mgr.State() == nil {
+		return nil
+	}
+	serial := meta.StateSnapshotMeta().Serial
+	return &serial
+}
+
+// countHistoryPlan returns the summary of the changes to managed resources
+// in the given plan.
+func countHistoryPlan(plan *plans.Plan) history.Summary {
+	var summary history.Summary
+	for _, change := range plan.Changes.Resources {
+		if change.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+		if change.Importing != nil {
+			summary.Import++
+		}
+		if change.Action == plans.Forget {
+			summary.Forget++
+		}
+		countHistoryAction(&summary, change.Action)
+	}
+	return summary
+}
+
+func countHistoryAction(summary *history.Summary, action plans.Action) {
+	switch action {
+	case plans.CreateThenDelete, plans.DeleteThenCreate:
+		summary.Add++
+		summary.Remove++
+	case plans.Create:
+		summary.Add++
+	case plans.Delete:
+		summary.Remove++
+	case plans.Update:
+		summary.Change++
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/notifications"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// notificationTimeout limits how long OpenTofu waits for a webhook to accept
+// a message, so that an unreachable service can't block an operation.
+const notificationTimeout = 10 * time.Second
+
+// notificationHook counts the planned and applied changes of an operation
+// and sends messages about its progress to the webhooks configured in the
+// notifications block of the CLI configuration. Failing to deliver a message
+// is logged, but never fails the operation.
+type notificationHook struct {
+	tofu.NilHook
+
+	webhooks  []*notifications.Webhook
+	client    *http.Client
+	workspace string
+
+	mu           sync.Mutex
+	planned      map[string]plans.Action
+	applied      notifications.Summary
+	pending      map[string]plans.Action
+	applyStarted bool
+	applyErr     error
+
+	// inflight tracks the messages sent in the background while the
+	// operation is running.
+	inflight sync.WaitGroup
+}
+
+var _ tofu.Hook = (*notificationHook)(nil)
+
+func newNotificationHook(webhooks []*notifications.Webhook, workspace string) *notificationHook {
+	client := httpclient.New()
+	client.Timeout = notificationTimeout
+	return &notificationHook{
+		webhooks:  webhooks,
+		client:    client,
+		workspace: workspace,
+		planned:   make(map[string]plans.Action),
+		pending:   make(map[string]plans.Action),
+	}
+}
+
+func (h *notificationHook) PostDiff(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	// We don't count anything for data resources
+	if addr.Resource.Resource.Mode == addrs.DataResourceMode {
+		return tofu.HookActionContinue, nil
+	}
+
+	// The apply walk reports the final diff of each object again, so we
+	// record the actions by object rather than counting them here.
+	key := addr.String()
+	if dk, ok := gen.(states.DeposedKey); ok {
+		key += " " + dk.String()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.planned[key] = action
+	return tofu.HookActionContinue, nil
+}
+
+func (h *notificationHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	h.mu.Lock()
+	h.pending[addr.String()] = action
+	started := h.applyStarted
+	h.applyStarted = true
+	msg := h.message(notifications.EventApplyStart, h.plannedSummary())
+	h.mu.Unlock()
+
+	if !started {
+		h.inflight.Add(1)
+		go func() {
+			defer h.inflight.Done()
+			h.send(context.Background(), msg)
+		}()
+	}
+	return tofu.HookActionContinue, nil
+}
+
+func (h *notificationHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := addr.String()
+	action, ok := h.pending[key]
+	delete(h.pending, key)
+	if err != nil {
+		if h.applyErr == nil {
+			h.applyErr = err
+		}
+		return tofu.HookActionContinue, nil
+	}
+	if ok && addr.Resource.Resource.Mode == addrs.ManagedResourceMode {
+		countAction(&h.applied, action)
+	}
+	return tofu.HookActionContinue, nil
+}
+
+func (h *notificationHook) PostApplyImport(addr addrs.AbsResourceInstance, importing plans.ImportingSrc) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.applied.Import++
+	return tofu.HookActionContinue, nil
+}
+
+func (h *notificationHook) PostApplyForget(_ addrs.AbsResourceInstance) (tofu.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.applied.Forget++
+	return tofu.HookActionContinue, nil
+}
+
+// PlanComplete sends the plan_complete message, with the number of planned
+// changes.
+func (h *notificationHook) PlanComplete(ctx context.Context) {
+	h.mu.Lock()
+	msg := h.message(notifications.EventPlanComplete, h.plannedSummary())
+	h.mu.Unlock()
+	h.send(ctx, msg)
+}
+
+// ApplyComplete sends the apply_success or apply_failure message, with the
+// number of applied changes, and waits for any messages that are still being
+// sent in the background.
+func (h *notificationHook) ApplyComplete(ctx context.Context, success bool) {
+	h.mu.Lock()
+	event := notifications.EventApplySuccess
+	if !success {
+		event = notifications.EventApplyFailure
+	}
+	msg := h.message(event, h.applied)
+	if !success && h.applyErr != nil {
+		msg.Error = h.applyErr.Error()
+	}
+	h.mu.Unlock()
+
+	h.inflight.Wait()
+	h.send(ctx, msg)
+}
+
+// SensitiveRevealed sends the sensitive_reveal message for the value with
+// the given address.
+func (h *notificationHook) SensitiveRevealed(ctx context.Context, addr string) {
+	h.mu.Lock()
+	msg := h.message(notifications.EventSensitiveReveal, notifications.Summary{})
+	h.mu.Unlock()
+	msg.Address = addr
+	h.send(ctx, msg)
+}
+
+// plannedSummary counts the planned changes. h.mu must be held.
+func (h *notificationHook) plannedSummary() notifications.Summary {
+	var ret notifications.Summary
+	for _, action := range h.planned {
+		countAction(&ret, action)
+	}
+	return ret
+}
+
+// message returns a message for the given event. h.mu must be held.
+func (h *notificationHook) message(event notifications.Event, summary notifications.Summary) notifications.Message {
+	return notifications.Message{
+		Event:     event,
+		Workspace: h.workspace,
+		Summary:   summary,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (h *notificationHook) send(ctx context.Context, msg notifications.Message) {
+	for _, webhook := range h.webhooks {
+		if !webhook.Wants(msg.Event) {
+			continue
+		}
+		if err := webhook.Send(ctx, h.client, msg); err != nil {
+			log.Printf("[WARN] notifications: %s", err)
+			continue
+		}
+		log.Printf("[DEBUG] notifications: sent %s message to webhook %q", msg.Event, webhook.Name)
+	}
+}
+
+func countAction(summary *notifications.Summary, action plans.Action) {
+	switch action {
+	case plans.CreateThenDelete, plans.DeleteThenCreate:
+		summary.Add++
+		summary.Remove++
+	case plans.Create:
+		summary.Add++
+	case plans.Delete:
+		summary.Remove++
+	case plans.Update:
+		summary.Change++
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/command/notifications"
+)
+
+func TestApply_notifications(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	messages, webhooks := testNotificationServer(t, nil, "")
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides:     metaOverridesForProvider(p),
+			View:                 view,
+			NotificationWebhooks: webhooks,
+		},
+	}
+
+	code := c.Run([]string{"-state", testTempFile(t), "-auto-approve"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	want := []testNotification{
+		{
+			Text: `OpenTofu apply started for workspace "default": 1 to add, 0 to change, 0 to destroy.`,
+			Message: notifications.Message{
+				Event:     notifications.EventApplyStart,
+				Workspace: "default",
+				Summary:   notifications.Summary{Add: 1},
+			},
+		},
+		{
+			Text: `OpenTofu apply succeeded for workspace "default": 1 added, 0 changed, 0 destroyed.`,
+			Message: notifications.Message{
+				Event:     notifications.EventApplySuccess,
+				Workspace: "default",
+				Summary:   notifications.Summary{Add: 1},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, messages()); diff != "" {
+		t.Errorf("wrong messages\n%s", diff)
+	}
+}
+
+func TestPlan_notifications(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	messages, webhooks := testNotificationServer(t, []string{"plan_complete"}, "{{.Event}}: {{.Summary.Add}}")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides:     metaOverridesForProvider(p),
+			View:                 view,
+			NotificationWebhooks: webhooks,
+		},
+	}
+
+	code := c.Run([]string{})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	want := []testNotification{
+		{
+			Text: "plan_complete: 1",
+			Message: notifications.Message{
+				Event:     notifications.EventPlanComplete,
+				Workspace: "default",
+				Summary:   notifications.Summary{Add: 1},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, messages()); diff != "" {
+		t.Errorf("wrong messages\n%s", diff)
+	}
+}
+
+func TestShow_revealNotifications(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testStateFileDefault(t, stateWithSensitiveValueForShow())
+
+	messages, webhooks := testNotificationServer(t, nil, "")
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides:     metaOverridesForProvider(testProvider()),
+			View:                 view,
+			NotificationWebhooks: webhooks,
+		},
+	}
+
+	defer testInputMap(t, map[string]string{"reveal": "yes"})()
+	code := c.Run([]string{"-reveal=output.foo"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	want := []testNotification{
+		{
+			Text: `OpenTofu revealed the sensitive value output.foo in workspace "default".`,
+			Message: notifications.Message{
+				Event:     notifications.EventSensitiveReveal,
+				Workspace: "default",
+				Address:   "output.foo",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, messages()); diff != "" {
+		t.Errorf("wrong messages\n%s", diff)
+	}
+}
+
+type testNotification struct {
+	Text string `json:"text"`
+	notifications.Message
+}
+
+// testNotificationServer starts a server that records the messages sent to
+// it, and returns a function that returns them without their timestamps, along
+// with a webhook that sends messages to the server.
+func testNotificationServer(t *testing.T, events []string, tmpl string) (func() []testNotification, []*notifications.Webhook) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var received []testNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg testNotification
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("invalid message: %s", err)
+		}
+		if got, want := r.Header.Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("wrong content type %q; want %q", got, want)
+		}
+		if msg.Timestamp.IsZero() {
+			t.Errorf("message %s has no timestamp", msg.Event)
+		}
+		msg.Timestamp = time.Time{}
+		mu.Lock()
+		received = append(received, msg)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	webhook, err := notifications.NewWebhook("test", server.URL, events, tmpl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := func() []testNotification {
+		mu.Lock()
+		defer mu.Unlock()
+		ret := make([]testNotification, len(received))
+		copy(ret, received)
+		return ret
+	}
+	return messages, []*notifications.Webhook{webhook}
+}
@@ -0,0 +1,465 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ImpactCommand is a Command implementation that lists the objects in the
+// configuration that refer to a given variable, local value, resource or
+// module call, directly or through other objects.
+type ImpactCommand struct {
+	Meta
+}
+
+// impactObject is an object declared in a module that can refer to other
+// objects in its configuration.
+type impactObject struct {
+	Module addrs.Module
+	Key    string
+	Kind   string
+
+	refs []impactRef
+}
+
+// impactRef is a reference made from the configuration of an impactObject.
+type impactRef struct {
+	Key   string
+	Range hcl.Range
+
+	// Arg is the name of the argument the reference is in, when the
+	// referring object is a module call. It's used to follow the reference
+	// into the corresponding variable of the child module.
+	Arg string
+}
+
+// impactResult is an object affected by a change to the object given to the
+// impact command.
+type impactResult struct {
+	Address  string `json:"address"`
+	Kind     string `json:"kind"`
+	Via      string `json:"via"`
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// impactNode identifies an object in the configuration, or one of the outputs
+// of a module call, while walking the references.
+type impactNode struct {
+	Module string
+	Key    string
+}
+
+func (c *ImpactCommand) Run(args []string) int {
+	var jsonOutput bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("impact")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one argument expected: the address of the object to analyze.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	ref, moreDiags := addrs.ParseRefStr(args[0])
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	key := impactKey(ref.Subject)
+	if key == "" || len(ref.Remaining) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid address",
+			fmt.Sprintf("The impact command requires the address of an input variable, local value, resource, data resource or module call in the root module, but %q is not one.", args[0]),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	config, moreDiags := c.loadConfig(".")
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	objects := impactObjects(config)
+	if !impactDeclared(objects[""], key) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Object not declared",
+			fmt.Sprintf("The root module does not declare %s.", key),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	results := impactWalk(objects, key)
+
+	c.showDiagnostics(diags)
+	if jsonOutput {
+		out := struct {
+			Address  string         `json:"address"`
+			Impacted []impactResult `json:"impacted"`
+		}{
+			Address:  key,
+			Impacted: results,
+		}
+		if out.Impacted == nil {
+			out.Impacted = []impactResult{}
+		}
+		jsonOut, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal result to JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(jsonOut))
+		return 0
+	}
+
+	if len(results) == 0 {
+		c.Ui.Output(fmt.Sprintf("No objects in the configuration refer to %s.", key))
+		return 0
+	}
+	c.Ui.Output(fmt.Sprintf("%d object(s) in the configuration depend on %s:\n", len(results), key))
+	for _, result := range results {
+		c.Ui.Output(fmt.Sprintf(
+			"  - %s (%s)\n      refers to %s at %s:%d,%d",
+			result.Address, result.Kind, result.Via, result.Filename, result.Line, result.Column,
+		))
+	}
+	return 0
+}
+
+// impactWalk follows the references to the given object in the root module
+// and returns the objects that refer to it, directly or indirectly, in the
+// order they were found.
+func impactWalk(objects map[string][]*impactObject, key string) []impactResult {
+	// dependents maps each object to the objects referring to it.
+	type dependent struct {
+		object *impactObject
+		ref    impactRef
+	}
+	dependents := make(map[impactNode][]dependent)
+	for mod, objs := range objects {
+		for _, obj := range objs {
+			for _, ref := range obj.refs {
+				node := impactNode{Module: mod, Key: ref.Key}
+				dependents[node] = append(dependents[node], dependent{obj, ref})
+			}
+		}
+	}
+
+	var results []impactResult
+	start := impactNode{Key: key}
+	seen := map[impactNode]bool{start: true}
+	queue := []impactNode{start}
+	if strings.HasPrefix(key, "module.") && strings.Count(key, ".") == 1 {
+		// Changing the module call itself can change all of its outputs.
+		child := addrs.RootModule.Child(strings.TrimPrefix(key, "module.")).String()
+		for _, obj := range objects[child] {
+			if obj.Kind == "output" {
+				seen[impactNode{Module: child, Key: obj.Key}] = true
+				queue = append(queue, impactNode{Module: child, Key: obj.Key})
+			}
+		}
+	}
+	visit := func(node impactNode, kind string, via impactNode, rng hcl.Range) {
+		if seen[node] {
+			return
+		}
+		seen[node] = true
+		queue = append(queue, node)
+		if kind == "" {
+			return
+		}
+		results = append(results, impactResult{
+			Address:  impactAddress(node),
+			Kind:     kind,
+			Via:      impactAddress(via),
+			Filename: rng.Filename,
+			Line:     rng.Start.Line,
+			Column:   rng.Start.Column,
+		})
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range dependents[node] {
+			visit(impactNode{Module: node.Module, Key: dep.object.Key}, dep.object.Kind, node, dep.ref.Range)
+			if dep.object.Kind == "module" && dep.ref.Arg != "" {
+				child := dep.object.Module.Child(strings.TrimPrefix(dep.object.Key, "module."))
+				visit(impactNode{Module: child.String(), Key: "var." + dep.ref.Arg}, "variable", node, dep.ref.Range)
+			}
+		}
+
+		// A changed output affects the references to it from the parent
+		// module, both to the output itself and to the whole module call.
+		if name, ok := strings.CutPrefix(node.Key, "output."); ok && node.Module != "" {
+			mod := impactParseModule(node.Module)
+			call := impactNode{
+				Module: mod.Parent().String(),
+				Key:    "module." + mod[len(mod)-1],
+			}
+			visit(impactNode{Module: call.Module, Key: call.Key + "." + name}, "", node, hcl.Range{})
+			if !seen[call] {
+				// The module call itself isn't affected, but everything
+				// referring to it as a whole is.
+				for _, dep := range dependents[call] {
+					visit(impactNode{Module: call.Module, Key: dep.object.Key}, dep.object.Kind, impactNode{Module: call.Module, Key: call.Key + "." + name}, dep.ref.Range)
+				}
+			}
+		}
+	}
+	return results
+}
+
+// impactObjects collects the objects of all modules in the configuration,
+// keyed by the string representation of the module path.
+func impactObjects(config *configs.Config) map[string][]*impactObject {
+	ret := make(map[string][]*impactObject)
+	config.DeepEach(func(c *configs.Config) {
+		ret[c.Path.String()] = impactModuleObjects(c.Path, c.Module)
+	})
+	return ret
+}
+
+func impactModuleObjects(path addrs.Module, mod *configs.Module) []*impactObject {
+	var ret []*impactObject
+	add := func(key, kind string) *impactObject {
+		obj := &impactObject{Module: path, Key: key, Kind: kind}
+		ret = append(ret, obj)
+		return obj
+	}
+
+	for _, name := range sortedKeys(mod.Variables) {
+		add("var."+name, "variable")
+	}
+	for _, name := range sortedKeys(mod.Locals) {
+		obj := add("local."+name, "local")
+		obj.addExpr(mod.Locals[name].Expr)
+	}
+	for _, resources := range []map[string]*configs.Resource{mod.ManagedResources, mod.DataResources, mod.EphemeralResources} {
+		for _, key := range sortedKeys(resources) {
+			r := resources[key]
+			kind := "resource"
+			switch r.Mode {
+			case addrs.DataResourceMode:
+				kind = "data"
+			case addrs.EphemeralResourceMode:
+				kind = "ephemeral"
+			}
+			obj := add(r.Addr().String(), kind)
+			obj.addBody(r.Config)
+			obj.addExpr(r.Count)
+			obj.addExpr(r.ForEach)
+			obj.addTraversals(r.DependsOn)
+			obj.addCheckRules(r.Preconditions)
+			obj.addCheckRules(r.Postconditions)
+			for _, expr := range r.TriggersReplacement {
+				obj.addExpr(expr)
+			}
+			if r.ProviderConfigRef != nil {
+				obj.addExpr(r.ProviderConfigRef.KeyExpression)
+			}
+			if r.Managed != nil {
+				if r.Managed.Connection != nil {
+					obj.addBody(r.Managed.Connection.Config)
+				}
+				for _, p := range r.Managed.Provisioners {
+					obj.addBody(p.Config)
+					if p.Connection != nil {
+						obj.addBody(p.Connection.Config)
+					}
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(mod.ModuleCalls) {
+		mc := mod.ModuleCalls[name]
+		obj := add("module."+name, "module")
+		obj.addBody(mc.Config)
+		obj.addExpr(mc.Count)
+		obj.addExpr(mc.ForEach)
+		obj.addTraversals(mc.DependsOn)
+	}
+	for _, name := range sortedKeys(mod.Outputs) {
+		o := mod.Outputs[name]
+		obj := add("output."+name, "output")
+		obj.addExpr(o.Expr)
+		obj.addTraversals(o.DependsOn)
+		obj.addCheckRules(o.Preconditions)
+	}
+	return ret
+}
+
+func (o *impactObject) addExpr(expr hcl.Expression) {
+	if expr == nil {
+		return
+	}
+	o.addTraversals(expr.Variables())
+}
+
+func (o *impactObject) addCheckRules(rules []*configs.CheckRule) {
+	for _, rule := range rules {
+		o.addExpr(rule.Condition)
+		o.addExpr(rule.ErrorMessage)
+	}
+}
+
+func (o *impactObject) addTraversals(traversals []hcl.Traversal) {
+	o.addTraversalsForArg(traversals, "")
+}
+
+func (o *impactObject) addTraversalsForArg(traversals []hcl.Traversal, arg string) {
+	for _, traversal := range traversals {
+		ref, diags := addrs.ParseRef(traversal)
+		if diags.HasErrors() {
+			continue
+		}
+		if key := impactKey(ref.Subject); key != "" {
+			o.refs = append(o.refs, impactRef{Key: key, Range: ref.SourceRange.ToHCL(), Arg: arg})
+		}
+	}
+}
+
+// addBody adds the references in all arguments and nested blocks of the given
+// body. The top-level arguments are recorded with their names, so that the
+// references in the arguments of module calls can be followed into the child
+// module.
+func (o *impactObject) addBody(body hcl.Body) {
+	if body == nil {
+		return
+	}
+	if syntaxBody, ok := body.(*hclsyntax.Body); ok {
+		for _, name := range sortedKeys(syntaxBody.Attributes) {
+			o.addTraversalsForArg(syntaxBody.Attributes[name].Expr.Variables(), name)
+		}
+		for _, block := range syntaxBody.Blocks {
+			o.addNestedBody(block.Body)
+		}
+		return
+	}
+	// Other syntaxes, like JSON, can't be decoded without a schema, so we
+	// treat everything in the body as an argument.
+	attrs, _ := body.JustAttributes()
+	for _, name := range sortedKeys(attrs) {
+		o.addTraversalsForArg(attrs[name].Expr.Variables(), name)
+	}
+}
+
+func (o *impactObject) addNestedBody(body *hclsyntax.Body) {
+	for _, name := range sortedKeys(body.Attributes) {
+		o.addTraversals(body.Attributes[name].Expr.Variables())
+	}
+	for _, block := range body.Blocks {
+		o.addNestedBody(block.Body)
+	}
+}
+
+// impactKey returns the key identifying the referenced object within its
+// module, or an empty string if the object isn't tracked by the impact
+// command.
+func impactKey(subject addrs.Referenceable) string {
+	switch s := subject.(type) {
+	case addrs.InputVariable, addrs.LocalValue, addrs.Resource, addrs.ModuleCall:
+		return s.String()
+	case addrs.ResourceInstance:
+		return s.Resource.String()
+	case addrs.ModuleCallInstance:
+		return s.Call.String()
+	case addrs.ModuleCallInstanceOutput:
+		return s.Call.Call.String() + "." + s.Name
+	default:
+		return ""
+	}
+}
+
+func impactDeclared(objects []*impactObject, key string) bool {
+	for _, obj := range objects {
+		if obj.Key == key || (obj.Kind == "module" && strings.HasPrefix(key, obj.Key+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+func impactAddress(node impactNode) string {
+	if node.Module == "" {
+		return node.Key
+	}
+	return node.Module + "." + node.Key
+}
+
+func impactParseModule(s string) addrs.Module {
+	var ret addrs.Module
+	parts := strings.Split(s, ".")
+	for i := 1; i < len(parts); i += 2 {
+		ret = append(ret, parts[i])
+	}
+	return ret
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *ImpactCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] impact [options] ADDRESS
+
+  Lists the objects in the configuration that refer to the given input
+  variable, local value, resource, data resource or module call of the root
+  module, either directly or through other objects. This helps to estimate
+  which parts of the infrastructure are affected by changing a value before
+  creating a plan.
+
+  The analysis only considers the references in the configuration and does
+  not require any state or provider access. Modules must be installed with
+  "tofu init" first.
+
+Options:
+
+  -json    Produce output in a machine-readable JSON format.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ImpactCommand) Synopsis() string {
+	return "Show which objects depend on a configuration value"
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func testImpactCommand(t *testing.T) (*ImpactCommand, *cli.MockUi) {
+	t.Helper()
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("impact"), td)
+	t.Cleanup(testChdir(t, td))
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	return &ImpactCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}, ui
+}
+
+func TestImpact_variable(t *testing.T) {
+	c, ui := testImpactCommand(t)
+
+	if code := c.Run([]string{"-json", "var.region"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got struct {
+		Address  string         `json:"address"`
+		Impacted []impactResult `json:"impacted"`
+	}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	want := []impactResult{
+		{Address: "local.zone", Kind: "local", Via: "var.region", Filename: "main.tf", Line: 6, Column: 13},
+		{Address: "test_instance.zoned", Kind: "resource", Via: "local.zone", Filename: "main.tf", Line: 10, Column: 9},
+		{Address: "module.child", Kind: "module", Via: "local.zone", Filename: "main.tf", Line: 19, Column: 12},
+		{Address: "module.child.var.input", Kind: "variable", Via: "local.zone", Filename: "main.tf", Line: 19, Column: 12},
+		{Address: "output.zone", Kind: "output", Via: "local.zone", Filename: "main.tf", Line: 28, Column: 11},
+		{Address: "module.child.test_instance.from_input", Kind: "resource", Via: "module.child.var.input", Filename: "child/main.tf", Line: 10, Column: 9},
+		{Address: "module.child.output.result", Kind: "output", Via: "module.child.test_instance.from_input", Filename: "child/main.tf", Line: 18, Column: 11},
+		{Address: "test_instance.consumer", Kind: "resource", Via: "module.child.result", Filename: "main.tf", Line: 24, Column: 9},
+	}
+	if got.Address != "var.region" {
+		t.Errorf("wrong address %q", got.Address)
+	}
+	if diff := cmp.Diff(want, got.Impacted); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestImpact_human(t *testing.T) {
+	c, ui := testImpactCommand(t)
+
+	if code := c.Run([]string{"test_instance.zoned"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "No objects in the configuration refer to test_instance.zoned."; !strings.Contains(got, want) {
+		t.Fatalf("output does not contain %q\n%s", want, got)
+	}
+
+	c, ui = testImpactCommand(t)
+	if code := c.Run([]string{"module.child"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		"1 object(s) in the configuration depend on module.child:",
+		"  - test_instance.consumer (resource)\n      refers to module.child.result at main.tf:24,9",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestImpact_invalid(t *testing.T) {
+	tests := map[string]string{
+		"var.missing":     "The root module does not declare var.missing.",
+		"path.module":     "is not one",
+		"var.region.attr": "is not one",
+	}
+	for addr, want := range tests {
+		t.Run(addr, func(t *testing.T) {
+			c, ui := testImpactCommand(t)
+			if code := c.Run([]string{addr}); code != 1 {
+				t.Fatalf("unexpected exit code %d\n\n%s", code, ui.OutputWriter.String())
+			}
+			if got := ui.ErrorWriter.String(); !strings.Contains(got, want) {
+				t.Fatalf("error does not contain %q\n%s", want, got)
+			}
+		})
+	}
+}
@@ -64,6 +64,7 @@ func (c *InitCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.reconfigure, "reconfigure", false, "reconfigure")
 	cmdFlags.BoolVar(&c.migrateState, "migrate-state", false, "migrate state")
 	cmdFlags.BoolVar(&flagUpgrade, "upgrade", false, "")
+	cmdFlags.StringVar(&c.Meta.moduleChannel, "channel", "", "module channel")
 	cmdFlags.Var(&flagPluginPath, "plugin-dir", "plugin directory")
 	cmdFlags.StringVar(&flagLockfile, "lockfile", "", "Set a dependency lockfile mode")
 	cmdFlags.BoolVar(&c.Meta.ignoreRemoteVersion, "ignore-remote-version", false, "continue even if remote and local OpenTofu versions are incompatible")
@@ -323,6 +324,13 @@ func (c *InitCommand) Run(args []string) int {
 		return 1
 	}
 
+	// It's common to run init before selecting the workspace to use, so we
+	// only warn about an undeclared workspace here and leave it to the
+	// other commands to reject it.
+	if workspace, err := c.Workspace(); err == nil {
+		diags = diags.Append(tfdiags.OverrideAll(config.Module.Workspaces.CheckWorkspace(workspace), tfdiags.Warning, nil))
+	}
+
 	if cb, ok := back.(*cloud.Cloud); ok {
 		if c.RunningInAutomation {
 			if err := cb.AssertImportCompatible(config); err != nil {
@@ -703,8 +711,8 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 				alternative := getproviders.MissingProviderSuggestion(ctx, provider, inst.ProviderSource(), reqs)
 				if alternative != provider {
 					suggestion = fmt.Sprintf(
-						"\n\nDid you intend to use %s? If so, you must specify that source address in each module which requires that provider. To see which modules are currently depending on %s, run the following command:\n    tofu providers",
-						alternative.ForDisplay(), provider.ForDisplay(),
+						"\n\nDid you intend to use %s? If so, you must specify that source address in each module which requires that provider:\n\n%s\nTo see which modules are currently depending on %s, run the following command:\n    tofu providers",
+						alternative.ForDisplay(), requiredProvidersSnippet(provider.Type, alternative), provider.ForDisplay(),
 					)
 				}
 
@@ -1045,6 +1053,19 @@ version control system if they represent changes you intended to make.`))
 // warnOnFailedImplicitProvReference returns a warn diagnostic when the downloader fails to fetch a provider that is implicitly referenced.
 // In other words, if the failed to download provider is having no required_providers entry, this function is trying to give to the user
 // more information on the source of the issue and gives also instructions on how to fix it.
+// requiredProvidersSnippet returns a terraform block declaring the given
+// provider under the given local name, for use in error messages.
+func requiredProvidersSnippet(localName string, provider addrs.Provider) string {
+	return fmt.Sprintf(`    terraform {
+      required_providers {
+        %s = {
+          source = %q
+        }
+      }
+    }
+`, localName, provider.ForDisplay())
+}
+
 func warnOnFailedImplicitProvReference(provider addrs.Provider, qualifs *getproviders.ProvidersQualification) tfdiags.Diagnostics {
 	if _, ok := qualifs.Explicit[provider]; ok {
 		return nil
@@ -1193,6 +1214,7 @@ func (c *InitCommand) AutocompleteFlags() complete.Flags {
 		"-backend":        completePredictBoolean,
 		"-cloud":          completePredictBoolean,
 		"-backend-config": complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
+		"-channel":        complete.PredictAnything,
 		"-force-copy":     complete.PredictNothing,
 		"-from-module":    completePredictModuleSource,
 		"-get":            completePredictBoolean,
@@ -1240,6 +1262,12 @@ Options:
                           times. The backend type must be in the configuration
                           itself.
 
+  -channel=NAME           Install the exact registry module versions selected
+                          by the module channel NAME, which is read from the
+                          file channels/NAME.json in the root module
+                          directory. The selected versions must still meet the
+                          version constraints in the configuration.
+
   -compact-warnings       If OpenTofu produces any warnings that are not
                           accompanied by errors, show them in a more compact
                           form that includes only the summary messages.
@@ -1637,6 +1637,44 @@ func TestInit_getProviderDetectedLegacy(t *testing.T) {
 	}
 }
 
+func TestInit_getProviderSuggestion(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-provider-suggestion"), td)
+	defer testChdir(t, td)()
+
+	// The mock source returns ErrRegistryProviderNotKnown for
+	// hashicorp/cloudflare, which the resource type implies.
+	providerSource, psClose := newMockProviderSource(t, map[string][]string{
+		"cloudflare/cloudflare": {"4.0.0"},
+	})
+	defer psClose()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			Ui:             ui,
+			View:           view,
+			ProviderSource: providerSource,
+		},
+	}
+
+	if code := c.Run([]string{"-backend=false"}); code == 0 {
+		t.Fatalf("expected error, got output: \n%s", ui.OutputWriter.String())
+	}
+
+	errOutput := ui.ErrorWriter.String()
+	for _, want := range []string{
+		"Did you intend to use cloudflare/cloudflare?",
+		"cloudflare = {",
+		`source = "cloudflare/cloudflare"`,
+	} {
+		if !strings.Contains(errOutput, want) {
+			t.Errorf("expected error %q: %s", want, errOutput)
+		}
+	}
+}
+
 func TestInit_getProviderDetectedDuplicate(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
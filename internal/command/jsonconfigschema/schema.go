@@ -0,0 +1,309 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonconfigschema implements a machine-readable JSON representation
+// of the declarations in a configuration, such as its input variables, output
+// values, resources, provider requirements and module calls, along with their
+// source locations.
+//
+// Unlike package jsonconfig, which describes the configuration of a plan in
+// terms of the provider schemas, this representation requires nothing but the
+// configuration itself, so that tools such as documentation generators and
+// policy engines can inspect a configuration without parsing it themselves.
+package jsonconfigschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// FormatVersion represents the version of the json format and will be
+// incremented for any change to this format that requires changes to a
+// consuming parser.
+const FormatVersion = "1.0"
+
+// schema is the top-level object returned when exporting a configuration.
+type schema struct {
+	FormatVersion string  `json:"format_version"`
+	RootModule    *module `json:"root_module"`
+}
+
+type module struct {
+	Path              string                       `json:"path"`
+	RequiredCore      []string                     `json:"required_core,omitempty"`
+	RequiredProviders map[string]*requiredProvider `json:"required_providers,omitempty"`
+	Variables         map[string]*variable         `json:"variables,omitempty"`
+	Outputs           map[string]*output           `json:"outputs,omitempty"`
+	Resources         []*resource                  `json:"resources,omitempty"`
+	ModuleCalls       map[string]*moduleCall       `json:"module_calls,omitempty"`
+}
+
+type requiredProvider struct {
+	Source             string    `json:"source"`
+	VersionConstraints []string  `json:"version_constraints,omitempty"`
+	Aliases            []string  `json:"aliases,omitempty"`
+	Range              *srcRange `json:"range,omitempty"`
+}
+
+type variable struct {
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Default     json.RawMessage `json:"default,omitempty"`
+	Required    bool            `json:"required"`
+	Sensitive   bool            `json:"sensitive,omitempty"`
+	Ephemeral   bool            `json:"ephemeral,omitempty"`
+	Nullable    bool            `json:"nullable"`
+	Deprecated  string          `json:"deprecated,omitempty"`
+	Range       *srcRange       `json:"range"`
+}
+
+type output struct {
+	Description string    `json:"description,omitempty"`
+	Sensitive   bool      `json:"sensitive,omitempty"`
+	Ephemeral   bool      `json:"ephemeral,omitempty"`
+	Deprecated  string    `json:"deprecated,omitempty"`
+	Range       *srcRange `json:"range"`
+}
+
+type resource struct {
+	Address        string    `json:"address"`
+	Mode           string    `json:"mode"`
+	Type           string    `json:"type"`
+	Name           string    `json:"name"`
+	Provider       string    `json:"provider"`
+	ProviderConfig string    `json:"provider_config"`
+	Range          *srcRange `json:"range"`
+}
+
+type moduleCall struct {
+	Source             string    `json:"source"`
+	VersionConstraints []string  `json:"version_constraints,omitempty"`
+	Version            string    `json:"version,omitempty"`
+	Range              *srcRange `json:"range"`
+	Module             *module   `json:"module,omitempty"`
+}
+
+type srcRange struct {
+	Filename string `json:"filename"`
+	Start    srcPos `json:"start"`
+	End      srcPos `json:"end"`
+}
+
+type srcPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// Marshal returns the JSON representation of the given configuration.
+func Marshal(config *configs.Config) ([]byte, error) {
+	root, err := marshalModule(config)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema{
+		FormatVersion: FormatVersion,
+		RootModule:    root,
+	})
+}
+
+func marshalModule(c *configs.Config) (*module, error) {
+	m := c.Module
+	ret := &module{
+		Path: c.Path.String(),
+	}
+
+	for _, vc := range m.CoreVersionConstraints {
+		ret.RequiredCore = append(ret.RequiredCore, vc.Required.String())
+	}
+
+	if m.ProviderRequirements != nil && len(m.ProviderRequirements.RequiredProviders) != 0 {
+		ret.RequiredProviders = make(map[string]*requiredProvider, len(m.ProviderRequirements.RequiredProviders))
+		for name, rp := range m.ProviderRequirements.RequiredProviders {
+			p := &requiredProvider{
+				Source: rp.Type.String(),
+				Range:  marshalRange(rp.DeclRange),
+			}
+			if len(rp.Requirement.Required) != 0 {
+				p.VersionConstraints = []string{rp.Requirement.Required.String()}
+			}
+			for _, alias := range rp.Aliases {
+				p.Aliases = append(p.Aliases, alias.StringCompact())
+			}
+			ret.RequiredProviders[name] = p
+		}
+	}
+
+	if len(m.Variables) != 0 {
+		ret.Variables = make(map[string]*variable, len(m.Variables))
+		for name, v := range m.Variables {
+			mv, err := marshalVariable(v)
+			if err != nil {
+				return nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			ret.Variables[name] = mv
+		}
+	}
+
+	if len(m.Outputs) != 0 {
+		ret.Outputs = make(map[string]*output, len(m.Outputs))
+		for name, o := range m.Outputs {
+			ret.Outputs[name] = &output{
+				Description: o.Description,
+				Sensitive:   o.Sensitive,
+				Ephemeral:   o.Ephemeral,
+				Deprecated:  o.Deprecated,
+				Range:       marshalRange(o.DeclRange),
+			}
+		}
+	}
+
+	for _, rs := range []map[string]*configs.Resource{m.ManagedResources, m.DataResources, m.EphemeralResources} {
+		for _, r := range rs {
+			ret.Resources = append(ret.Resources, marshalResource(r))
+		}
+	}
+	sort.Slice(ret.Resources, func(i, j int) bool {
+		return ret.Resources[i].Address < ret.Resources[j].Address
+	})
+
+	if len(m.ModuleCalls) != 0 {
+		ret.ModuleCalls = make(map[string]*moduleCall, len(m.ModuleCalls))
+		for name, mc := range m.ModuleCalls {
+			call := &moduleCall{
+				Source: mc.SourceAddrRaw,
+				Range:  marshalRange(mc.DeclRange),
+			}
+			if len(mc.Version.Required) != 0 {
+				call.VersionConstraints = []string{mc.Version.Required.String()}
+			}
+			// The child module is only present if it has been installed.
+			if child, ok := c.Children[name]; ok && child != nil && child.Module != nil {
+				if child.Version != nil {
+					call.Version = child.Version.String()
+				}
+				childModule, err := marshalModule(child)
+				if err != nil {
+					return nil, err
+				}
+				call.Module = childModule
+			}
+			ret.ModuleCalls[name] = call
+		}
+	}
+
+	return ret, nil
+}
+
+func marshalVariable(v *configs.Variable) (*variable, error) {
+	ret := &variable{
+		Type:        "any",
+		Description: v.Description,
+		Required:    v.Required(),
+		Sensitive:   v.Sensitive,
+		Ephemeral:   v.Ephemeral,
+		Nullable:    v.Nullable,
+		Deprecated:  v.Deprecated,
+		Range:       marshalRange(v.DeclRange),
+	}
+	if v.ConstraintType != cty.NilType {
+		ret.Type = typeString(v.ConstraintType)
+	}
+	if v.Default != cty.NilVal && v.Default.IsWhollyKnown() {
+		def, _ := v.Default.UnmarkDeep()
+		raw, err := ctyjson.Marshal(def, def.Type())
+		if err != nil {
+			return nil, err
+		}
+		ret.Default = raw
+	}
+	return ret, nil
+}
+
+// typeString returns the type constraint syntax for the given type, like
+// typeexpr.TypeString except that it also includes the optional modifier of
+// object attributes.
+func typeString(ty cty.Type) string {
+	switch {
+	case ty == cty.DynamicPseudoType:
+		return "any"
+	case ty.IsPrimitiveType():
+		return ty.FriendlyNameForConstraint()
+	case ty.IsListType():
+		return "list(" + typeString(ty.ElementType()) + ")"
+	case ty.IsSetType():
+		return "set(" + typeString(ty.ElementType()) + ")"
+	case ty.IsMapType():
+		return "map(" + typeString(ty.ElementType()) + ")"
+	case ty.IsTupleType():
+		elems := make([]string, len(ty.TupleElementTypes()))
+		for i, ety := range ty.TupleElementTypes() {
+			elems[i] = typeString(ety)
+		}
+		return "tuple([" + strings.Join(elems, ",") + "])"
+	case ty.IsObjectType():
+		names := make([]string, 0, len(ty.AttributeTypes()))
+		for name := range ty.AttributeTypes() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		attrs := make([]string, len(names))
+		for i, name := range names {
+			aty := typeString(ty.AttributeType(name))
+			if ty.AttributeOptional(name) {
+				aty = "optional(" + aty + ")"
+			}
+			attrs[i] = name + "=" + aty
+		}
+		return "object({" + strings.Join(attrs, ",") + "})"
+	default:
+		return ty.FriendlyNameForConstraint()
+	}
+}
+
+func marshalResource(r *configs.Resource) *resource {
+	ret := &resource{
+		Address:        r.Addr().String(),
+		Type:           r.Type,
+		Name:           r.Name,
+		Provider:       r.Provider.String(),
+		ProviderConfig: r.ProviderConfigAddr().StringCompact(),
+		Range:          marshalRange(r.DeclRange),
+	}
+	switch r.Mode {
+	case addrs.ManagedResourceMode:
+		ret.Mode = "managed"
+	case addrs.DataResourceMode:
+		ret.Mode = "data"
+	case addrs.EphemeralResourceMode:
+		ret.Mode = "ephemeral"
+	}
+	return ret
+}
+
+func marshalRange(rng hcl.Range) *srcRange {
+	return &srcRange{
+		Filename: rng.Filename,
+		Start: srcPos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column,
+			Byte:   rng.Start.Byte,
+		},
+		End: srcPos{
+			Line:   rng.End.Line,
+			Column: rng.End.Column,
+			Byte:   rng.End.Byte,
+		},
+	}
+}
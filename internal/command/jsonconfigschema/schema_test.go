@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonconfigschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/initwd"
+)
+
+func TestMarshal(t *testing.T) {
+	config, _, cleanup := initwd.MustLoadConfigForTests(t, filepath.Join("testdata", "basic"), "tests")
+	defer cleanup()
+
+	got, err := Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSrc, err := os.ReadFile(filepath.Join("testdata", "basic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotObj, wantObj interface{}
+	if err := json.Unmarshal(got, &gotObj); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(wantSrc, &wantObj); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantObj, gotObj); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	config, _, cleanup := initwd.MustLoadConfigForTests(t, filepath.Join("testdata", "basic"), "tests")
+	defer cleanup()
+
+	got := typeString(config.Module.Variables["settings"].ConstraintType)
+	want := "object({size=optional(number),tags=map(string)})"
+	if got != want {
+		t.Errorf("wrong type string\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+variable "token" {
+  type      = string
+  default   = null
+  ephemeral = true
+}
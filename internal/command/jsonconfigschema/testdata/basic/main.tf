@@ -0,0 +1,43 @@
+terraform {
+  required_version = ">= 1.6.0"
+
+  required_providers {
+    test = {
+      source  = "hashicorp/test"
+      version = "~> 1.0"
+    }
+  }
+}
+
+variable "name" {
+  type        = string
+  description = "The name of the instance."
+}
+
+variable "settings" {
+  type = object({
+    size = optional(number, 1)
+    tags = map(string)
+  })
+  default = {
+    tags = {}
+  }
+}
+
+resource "test_instance" "main" {
+  ami = var.name
+}
+
+data "test_data_source" "main" {
+  id = "example"
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "id" {
+  value       = test_instance.main.id
+  description = "The ID of the instance."
+  sensitive   = true
+}
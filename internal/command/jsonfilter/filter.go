@@ -0,0 +1,383 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonfilter implements privacy filters that can be applied to the
+// machine-readable JSON representations of plans and states, so that they
+// can be shared with third-party tools without disclosing secrets or internal
+// identifiers.
+//
+// Filters operate on the already-marshaled JSON document rather than on the
+// plan or state themselves, so that they apply equally to plans produced
+// locally and to pre-rendered plans retrieved from a remote backend.
+package jsonfilter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter is a single step of a Pipeline, which modifies a decoded JSON plan
+// or state document in place.
+type Filter interface {
+	// String returns the filter specification in the syntax accepted by
+	// Parse.
+	String() string
+
+	filter(doc map[string]interface{})
+}
+
+// Pipeline is a sequence of filters that are applied in order.
+type Pipeline []Filter
+
+// Apply returns the given JSON plan or state document with all of the
+// filters in the pipeline applied. If the pipeline is empty, the document is
+// returned unchanged.
+func (p Pipeline) Apply(src []byte) ([]byte, error) {
+	if len(p) == 0 {
+		return src, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(src))
+	// Numbers are kept in their original form so that filtering doesn't
+	// change the precision of any values that are left unfiltered.
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON document: %w", err)
+	}
+
+	for _, f := range p {
+		f.filter(doc)
+	}
+
+	return json.Marshal(doc)
+}
+
+// Parse parses a filter specification, which is one of the following:
+//
+//   - "drop=PATH" removes the resource attribute at the given path.
+//   - "hash=PATH" replaces the value of the resource attribute at the given
+//     path with a SHA256 hash of its JSON encoding, so that changes to the
+//     value can still be detected.
+//   - "strip-provider-config" removes the expressions of all provider
+//     configurations.
+//
+// A path is a sequence of attribute names, map keys or list indices separated
+// by periods, where "*" selects all elements at that step.
+func Parse(spec string) (Filter, error) {
+	kind, rawPath, hasPath := strings.Cut(spec, "=")
+	switch kind {
+	case "drop", "hash":
+		if !hasPath {
+			return nil, fmt.Errorf("the %q filter requires an attribute path, like %s=password", kind, kind)
+		}
+		path, err := parsePath(rawPath)
+		if err != nil {
+			return nil, err
+		}
+		if kind == "drop" {
+			return &dropFilter{path: path}, nil
+		}
+		return &hashFilter{path: path}, nil
+	case "strip-provider-config":
+		if hasPath {
+			return nil, fmt.Errorf("the %q filter does not accept an argument", kind)
+		}
+		return stripProviderConfigFilter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported filter %q; must be drop, hash or strip-provider-config", kind)
+	}
+}
+
+// ParsePipeline parses each of the given filter specifications using Parse,
+// returning a pipeline that applies them in the given order.
+func ParsePipeline(specs []string) (Pipeline, error) {
+	var ret Pipeline
+	for _, spec := range specs {
+		f, err := Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", spec, err)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+func parsePath(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("the attribute path must not be empty")
+	}
+	path := strings.Split(raw, ".")
+	for _, step := range path {
+		if step == "" {
+			return nil, fmt.Errorf("the attribute path %q has an empty step", raw)
+		}
+	}
+	return path, nil
+}
+
+type dropFilter struct {
+	path []string
+}
+
+func (f *dropFilter) String() string {
+	return "drop=" + strings.Join(f.path, ".")
+}
+
+func (f *dropFilter) filter(doc map[string]interface{}) {
+	drop := func(parent interface{}, key string) {
+		// Removing an element from a list would change the indices of all
+		// of the elements after it, and so only attributes and map elements
+		// can be dropped.
+		if obj, ok := parent.(map[string]interface{}); ok {
+			delete(obj, key)
+		}
+	}
+	eachResourceValue(doc, func(val interface{}, isValue bool) {
+		visitPath(val, f.path, drop)
+	})
+	eachResourceExpressions(doc, func(exprs map[string]interface{}) {
+		if len(f.path) == 1 {
+			visitPath(exprs, f.path, drop)
+			return
+		}
+		visitExpressionConstant(exprs, f.path, drop)
+	})
+}
+
+type hashFilter struct {
+	path []string
+}
+
+func (f *hashFilter) String() string {
+	return "hash=" + strings.Join(f.path, ".")
+}
+
+func (f *hashFilter) filter(doc map[string]interface{}) {
+	hash := func(parent interface{}, key string) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			parent[key] = hashValue(parent[key])
+		case []interface{}:
+			idx, _ := strconv.Atoi(key)
+			parent[idx] = hashValue(parent[idx])
+		}
+	}
+	eachResourceValue(doc, func(val interface{}, isValue bool) {
+		// The unknown and sensitive markers don't contain any values, so
+		// they are left unchanged.
+		if isValue {
+			visitPath(val, f.path, hash)
+		}
+	})
+	eachResourceExpressions(doc, func(exprs map[string]interface{}) {
+		if len(f.path) == 1 {
+			if expr, ok := exprs[f.path[0]].(map[string]interface{}); ok {
+				if _, ok := expr["constant_value"]; ok {
+					hash(expr, "constant_value")
+				}
+			}
+			return
+		}
+		visitExpressionConstant(exprs, f.path, hash)
+	})
+}
+
+// hashValue returns the SHA256 hash of the JSON encoding of the given value.
+// Null values are left as null, so that it remains visible whether an
+// attribute is set at all.
+func hashValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		// Values decoded from JSON can always be encoded again.
+		panic(fmt.Sprintf("failed to encode value for hashing: %s", err))
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type stripProviderConfigFilter struct{}
+
+func (f stripProviderConfigFilter) String() string {
+	return "strip-provider-config"
+}
+
+func (f stripProviderConfigFilter) filter(doc map[string]interface{}) {
+	config, ok := doc["configuration"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	pcs, ok := config["provider_config"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, pc := range pcs {
+		if pc, ok := pc.(map[string]interface{}); ok {
+			delete(pc, "expressions")
+		}
+	}
+}
+
+// visitPath calls the given function with the parent container and key of
+// each value selected by the given path, starting at val.
+func visitPath(val interface{}, path []string, fn func(parent interface{}, key string)) {
+	if len(path) == 0 {
+		return
+	}
+	step, rest := path[0], path[1:]
+
+	switch val := val.(type) {
+	case map[string]interface{}:
+		var keys []string
+		if step == "*" {
+			for k := range val {
+				keys = append(keys, k)
+			}
+		} else if _, ok := val[step]; ok {
+			keys = []string{step}
+		}
+		for _, k := range keys {
+			if len(rest) == 0 {
+				fn(val, k)
+			} else {
+				visitPath(val[k], rest, fn)
+			}
+		}
+	case []interface{}:
+		var idxs []int
+		if step == "*" {
+			for i := range val {
+				idxs = append(idxs, i)
+			}
+		} else if i, err := strconv.Atoi(step); err == nil && i >= 0 && i < len(val) {
+			idxs = []int{i}
+		}
+		for _, i := range idxs {
+			if len(rest) == 0 {
+				fn(val, strconv.Itoa(i))
+			} else {
+				visitPath(val[i], rest, fn)
+			}
+		}
+	}
+}
+
+// visitExpressionConstant applies the given path to the constant value of the
+// expression for the attribute named by its first step, if it has one.
+func visitExpressionConstant(exprs map[string]interface{}, path []string, fn func(parent interface{}, key string)) {
+	expr, ok := exprs[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	visitPath(expr["constant_value"], path[1:], fn)
+}
+
+// eachResourceValue calls the given function for each object describing a
+// resource instance value in the given plan or state document. isValue is
+// false for the objects that only mark unknown or sensitive values.
+func eachResourceValue(doc map[string]interface{}, fn func(val interface{}, isValue bool)) {
+	// A state document has its values at the top level.
+	if values, ok := doc["values"].(map[string]interface{}); ok {
+		eachModuleResourceValue(values["root_module"], fn)
+	}
+
+	if values, ok := doc["planned_values"].(map[string]interface{}); ok {
+		eachModuleResourceValue(values["root_module"], fn)
+	}
+	if prior, ok := doc["prior_state"].(map[string]interface{}); ok {
+		if values, ok := prior["values"].(map[string]interface{}); ok {
+			eachModuleResourceValue(values["root_module"], fn)
+		}
+	}
+
+	for _, key := range []string{"resource_changes", "resource_drift"} {
+		changes, _ := doc[key].([]interface{})
+		for _, rc := range changes {
+			rc, ok := rc.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			change, ok := rc["change"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, k := range []string{"before", "after"} {
+				if v, ok := change[k]; ok {
+					fn(v, true)
+				}
+			}
+			for _, k := range []string{"after_unknown", "before_sensitive", "after_sensitive"} {
+				if v, ok := change[k]; ok {
+					fn(v, false)
+				}
+			}
+		}
+	}
+}
+
+func eachModuleResourceValue(mod interface{}, fn func(val interface{}, isValue bool)) {
+	m, ok := mod.(map[string]interface{})
+	if !ok {
+		return
+	}
+	resources, _ := m["resources"].([]interface{})
+	for _, r := range resources {
+		r, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, ok := r["values"]; ok {
+			fn(v, true)
+		}
+		if v, ok := r["sensitive_values"]; ok {
+			fn(v, false)
+		}
+	}
+	children, _ := m["child_modules"].([]interface{})
+	for _, child := range children {
+		eachModuleResourceValue(child, fn)
+	}
+}
+
+// eachResourceExpressions calls the given function with the expressions of
+// each resource in the configuration of the given plan document.
+func eachResourceExpressions(doc map[string]interface{}, fn func(exprs map[string]interface{})) {
+	config, ok := doc["configuration"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	eachModuleResourceExpressions(config["root_module"], fn)
+}
+
+func eachModuleResourceExpressions(mod interface{}, fn func(exprs map[string]interface{})) {
+	m, ok := mod.(map[string]interface{})
+	if !ok {
+		return
+	}
+	resources, _ := m["resources"].([]interface{})
+	for _, r := range resources {
+		r, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if exprs, ok := r["expressions"].(map[string]interface{}); ok {
+			fn(exprs)
+		}
+	}
+	calls, _ := m["module_calls"].(map[string]interface{})
+	for _, call := range calls {
+		if call, ok := call.(map[string]interface{}); ok {
+			eachModuleResourceExpressions(call["module"], fn)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonfilter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testPlan = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "test_instance.a",
+          "values": {"id": "i-1", "password": "hunter2", "tags": {"owner": "alice", "env": "prod"}},
+          "sensitive_values": {"password": true, "tags": {}}
+        }
+      ],
+      "child_modules": [
+        {
+          "resources": [
+            {
+              "address": "module.child.test_instance.b",
+              "values": {"id": "i-2", "password": "swordfish", "ports": [80, 443]}
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {
+      "address": "test_instance.a",
+      "change": {
+        "before": {"id": "i-1", "password": "hunter1"},
+        "after": {"id": "i-1", "password": "hunter2"},
+        "after_unknown": {},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true}
+      }
+    }
+  ],
+  "configuration": {
+    "provider_config": {
+      "test": {"name": "test", "expressions": {"token": {"constant_value": "abc"}}}
+    },
+    "root_module": {
+      "resources": [
+        {
+          "address": "test_instance.a",
+          "expressions": {
+            "password": {"constant_value": "hunter2"},
+            "tags": {"constant_value": {"owner": "alice"}}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestPipelineApply(t *testing.T) {
+	tests := map[string]struct {
+		specs []string
+		want  map[string]string
+	}{
+		"drop": {
+			[]string{"drop=password"},
+			map[string]string{
+				"planned_values.root_module.resources.0.values":                 `{"id":"i-1","tags":{"env":"prod","owner":"alice"}}`,
+				"planned_values.root_module.resources.0.sensitive_values":       `{"tags":{}}`,
+				"planned_values.root_module.child_modules.0.resources.0.values": `{"id":"i-2","ports":[80,443]}`,
+				"resource_changes.0.change.before":                              `{"id":"i-1"}`,
+				"resource_changes.0.change.after_sensitive":                     `{}`,
+				"configuration.root_module.resources.0.expressions":             `{"tags":{"constant_value":{"owner":"alice"}}}`,
+				"configuration.provider_config.test.expressions":                `{"token":{"constant_value":"abc"}}`,
+			},
+		},
+		"drop nested with wildcard": {
+			[]string{"drop=tags.*"},
+			map[string]string{
+				"planned_values.root_module.resources.0.values":     `{"id":"i-1","password":"hunter2","tags":{}}`,
+				"configuration.root_module.resources.0.expressions": `{"password":{"constant_value":"hunter2"},"tags":{"constant_value":{}}}`,
+			},
+		},
+		"hash": {
+			[]string{"hash=password"},
+			map[string]string{
+				"resource_changes.0.change.before":                  `{"id":"i-1","password":"sha256:adb3e4097f5f88e4883e130c51dc7cc22bc6a70388c70cbc6a67bd4795225185"}`,
+				"resource_changes.0.change.after":                   `{"id":"i-1","password":"sha256:4ddbb67bf993867e13253c146a339ed3b33ea5b895543569278e99d5b3c2b7d5"}`,
+				"resource_changes.0.change.after_sensitive":         `{"password":true}`,
+				"configuration.root_module.resources.0.expressions": `{"password":{"constant_value":"sha256:4ddbb67bf993867e13253c146a339ed3b33ea5b895543569278e99d5b3c2b7d5"},"tags":{"constant_value":{"owner":"alice"}}}`,
+			},
+		},
+		"hash list element": {
+			[]string{"hash=ports.1"},
+			map[string]string{
+				"planned_values.root_module.child_modules.0.resources.0.values": `{"id":"i-2","password":"swordfish","ports":[80,"sha256:6d05621ab7cb7b4fb796ca2ffbe1a141e0d4319d3deb6a05322b9de85d69b923"]}`,
+			},
+		},
+		"strip provider config": {
+			[]string{"strip-provider-config"},
+			map[string]string{
+				"configuration.provider_config.test":            `{"name":"test"}`,
+				"planned_values.root_module.resources.0.values": `{"id":"i-1","password":"hunter2","tags":{"env":"prod","owner":"alice"}}`,
+			},
+		},
+		"pipeline": {
+			[]string{"hash=tags.owner", "drop=tags", "strip-provider-config"},
+			map[string]string{
+				"planned_values.root_module.resources.0.values": `{"id":"i-1","password":"hunter2"}`,
+				"configuration.provider_config.test":            `{"name":"test"}`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParsePipeline(test.specs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Apply([]byte(testPlan))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc interface{}
+			if err := json.Unmarshal(got, &doc); err != nil {
+				t.Fatal(err)
+			}
+			for path, want := range test.want {
+				val := doc
+				for _, step := range strings.Split(path, ".") {
+					switch v := val.(type) {
+					case map[string]interface{}:
+						val = v[step]
+					case []interface{}:
+						val = v[int(step[0]-'0')]
+					}
+				}
+				raw, err := json.Marshal(val)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, string(raw)); diff != "" {
+					t.Errorf("wrong value at %s\n%s", path, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPipelineApply_empty(t *testing.T) {
+	var p Pipeline
+	got, err := p.Apply([]byte(testPlan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != testPlan {
+		t.Errorf("document was modified by an empty pipeline")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"drop=password":           "",
+		"hash=tags.*":             "",
+		"strip-provider-config":   "",
+		"drop":                    "requires an attribute path",
+		"hash=":                   "must not be empty",
+		"drop=tags..owner":        "has an empty step",
+		"strip-provider-config=a": "does not accept an argument",
+		"redact=password":         "unsupported filter",
+	}
+
+	for spec, wantErr := range tests {
+		t.Run(spec, func(t *testing.T) {
+			f, err := Parse(spec)
+			if wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if got := f.String(); got != spec {
+					t.Errorf("wrong string\ngot:  %s\nwant: %s", got, spec)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), wantErr) {
+				t.Fatalf("wrong error\ngot:  %v\nwant: %s", err, wantErr)
+			}
+		})
+	}
+}
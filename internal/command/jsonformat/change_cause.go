@@ -0,0 +1,241 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// changeCause describes why OpenTofu proposes a change, so that the plan
+// renderer can show the root causes of a plan before their consequences.
+type changeCause int
+
+const (
+	// causeConfig is for changes that aren't explained by anything else,
+	// which are typically caused directly by changes to the configuration.
+	causeConfig changeCause = iota
+
+	// causeDrift is for changes that undo changes made to an object outside
+	// of OpenTofu.
+	causeDrift
+
+	// causeUpstream is for changes that only exist because another resource
+	// instance that this one depends on will be replaced.
+	causeUpstream
+)
+
+// changeGroup is a set of changes with the same cause, in the order in which
+// the plan renderer should show them.
+type changeGroup struct {
+	cause   changeCause
+	changes []diff
+}
+
+// title returns the heading that the plan renderer shows above the changes
+// in the group.
+func (g changeGroup) title() string {
+	switch g.cause {
+	case causeDrift:
+		return "Changes to undo changes made outside of OpenTofu:"
+	case causeUpstream:
+		return "Changes caused by the replacement of other resources:"
+	default:
+		return "Changes to match the configuration:"
+	}
+}
+
+// groupChangesByCause classifies the given changes, which must be from the
+// given plan, and groups them by cause. The groups are ordered so that root
+// causes come before the changes they cause, and each group keeps the order
+// of the given changes.
+//
+// If all of the changes have the same cause then there is only one group,
+// and the renderer shouldn't show a heading for it.
+func groupChangesByCause(plan Plan, changes []diff) []changeGroup {
+	replaced := make(map[string]bool)
+	for _, change := range changes {
+		switch jsonplan.UnmarshalActions(change.change.Change.Actions) {
+		case plans.CreateThenDelete, plans.DeleteThenCreate:
+			if len(change.change.Deposed) == 0 {
+				replaced[change.change.Address] = true
+			}
+		}
+	}
+	drift := make(map[string]jsonplan.ResourceChange)
+	for _, dr := range plan.ResourceDrift {
+		if len(dr.Deposed) == 0 {
+			drift[dr.Address] = dr
+		}
+	}
+
+	groups := []changeGroup{{cause: causeConfig}, {cause: causeDrift}, {cause: causeUpstream}}
+	for _, change := range changes {
+		upstream := upstreamReplacement(change.change, plan.ResourceDependencies[change.change.Address], replaced)
+		switch {
+		case upstream != "":
+			change.upstream = upstream
+			groups[causeUpstream].changes = append(groups[causeUpstream].changes, change)
+		case reconcilesDrift(change.change, drift):
+			groups[causeDrift].changes = append(groups[causeDrift].changes, change)
+		default:
+			groups[causeConfig].changes = append(groups[causeConfig].changes, change)
+		}
+	}
+
+	var ret []changeGroup
+	for _, group := range groups {
+		if len(group.changes) > 0 {
+			ret = append(ret, group)
+		}
+	}
+	return ret
+}
+
+// upstreamReplacement returns the address of a resource instance that the
+// given change is caused by the replacement of, or an empty string if the
+// change isn't caused by the replacement of a dependency.
+//
+// We consider a change to be caused by the replacement of a dependency if all
+// of the attributes that it changes will only be known after apply, because
+// those are then the values derived from the new object.
+func upstreamReplacement(change jsonplan.ResourceChange, deps []string, replaced map[string]bool) string {
+	if len(change.Deposed) != 0 {
+		return ""
+	}
+
+	var upstream string
+	for _, dep := range deps {
+		if replaced[dep] && dep != change.Address {
+			upstream = dep
+			break
+		}
+	}
+	if upstream == "" {
+		return ""
+	}
+
+	switch jsonplan.UnmarshalActions(change.Change.Actions) {
+	case plans.Read:
+		// Data resources are read during apply when a dependency has
+		// changes pending, whatever their attributes are.
+		if change.ActionReason == jsonplan.ResourceInstanceReadBecauseDependencyPending || change.ActionReason == jsonplan.ResourceInstanceReadBecauseConfigUnknown {
+			return upstream
+		}
+	case plans.CreateThenDelete, plans.DeleteThenCreate:
+		if change.ActionReason == jsonplan.ResourceInstanceReplaceByTriggers {
+			return upstream
+		}
+		fallthrough
+	case plans.Update:
+		before, after, unknown := changeAttributes(change.Change)
+		changed := changedAttributes(before, after, unknown)
+		if len(changed) == 0 {
+			return ""
+		}
+		for _, name := range changed {
+			if !containsTrue(unknown[name]) {
+				return ""
+			}
+		}
+		return upstream
+	}
+	return ""
+}
+
+// reconcilesDrift returns true if the given change does nothing but restore
+// the values that the given drift reports as changed outside of OpenTofu, or
+// recreate an object that was deleted outside of OpenTofu.
+func reconcilesDrift(change jsonplan.ResourceChange, drift map[string]jsonplan.ResourceChange) bool {
+	if len(change.Deposed) != 0 {
+		return false
+	}
+	dr, ok := drift[change.Address]
+	if !ok {
+		return false
+	}
+	driftAction := jsonplan.UnmarshalActions(dr.Change.Actions)
+
+	switch jsonplan.UnmarshalActions(change.Change.Actions) {
+	case plans.Create:
+		return driftAction == plans.Delete
+	case plans.Update, plans.CreateThenDelete, plans.DeleteThenCreate:
+		if driftAction != plans.Update {
+			return false
+		}
+		driftBefore, _, _ := changeAttributes(dr.Change)
+		before, after, unknown := changeAttributes(change.Change)
+		changed := changedAttributes(before, after, unknown)
+		if len(changed) == 0 {
+			return false
+		}
+		for _, name := range changed {
+			if containsTrue(unknown[name]) || !reflect.DeepEqual(after[name], driftBefore[name]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// changeAttributes decodes the top-level attributes of the before and after
+// values of the given change, and of its after_unknown value. Any of the
+// results can be nil if the corresponding value is null or missing.
+func changeAttributes(change jsonplan.Change) (before, after, unknown map[string]interface{}) {
+	// Errors just mean that the value isn't an object, which we treat in
+	// the same way as null.
+	_ = json.Unmarshal(change.Before, &before)
+	_ = json.Unmarshal(change.After, &after)
+	_ = json.Unmarshal(change.AfterUnknown, &unknown)
+	return before, after, unknown
+}
+
+// changedAttributes returns the names of the top-level attributes that differ
+// between the given before and after values, including those whose new value
+// is unknown.
+func changedAttributes(before, after, unknown map[string]interface{}) []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for _, attrs := range []map[string]interface{}{before, after, unknown} {
+		for name := range attrs {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if containsTrue(unknown[name]) || !reflect.DeepEqual(before[name], after[name]) {
+				ret = append(ret, name)
+			}
+		}
+	}
+	return ret
+}
+
+// containsTrue returns true if the given value from an after_unknown object
+// marks any part of the corresponding attribute as unknown.
+func containsTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		for _, elem := range v {
+			if containsTrue(elem) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			if containsTrue(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -97,6 +97,10 @@ func (d diffs) Empty() bool {
 type diff struct {
 	change jsonplan.ResourceChange
 	diff   computed.Diff
+
+	// upstream is the address of the resource instance whose replacement
+	// causes this change, if any.
+	upstream string
 }
 
 func (d diff) Moved() bool {
@@ -33,6 +33,12 @@ type Plan struct {
 	ResourceDrift      []jsonplan.ResourceChange  `json:"resource_drift"`
 	RelevantAttributes []jsonplan.ResourceAttr    `json:"relevant_attributes"`
 
+	// ResourceDependencies is side-loaded from jsonplan.ResourceDependencies,
+	// because the JSON plan format doesn't include it. Without it, the
+	// renderer can't tell which changes are caused by the replacement of
+	// other resources.
+	ResourceDependencies map[string][]string `json:"-"`
+
 	ProviderFormatVersion string                            `json:"provider_format_version"`
 	ProviderSchemas       map[string]*jsonprovider.Provider `json:"provider_schemas"`
 }
@@ -220,11 +226,17 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 			renderer.Streams.Printf("\nOpenTofu will perform the following actions:\n")
 		}
 
-		for _, change := range changes {
-			diff, render := renderHumanDiff(renderer, change, proposedChange)
-			if render {
-				fmt.Fprintln(renderer.Streams.Stdout.File)
-				renderer.Streams.Println(diff)
+		groups := groupChangesByCause(plan, changes)
+		for _, group := range groups {
+			if len(groups) > 1 {
+				renderer.Streams.Print(renderer.Colorize.Color(fmt.Sprintf("\n[bold]%s[reset]\n", group.title())))
+			}
+			for _, change := range group.changes {
+				diff, render := renderHumanDiff(renderer, change, proposedChange)
+				if render {
+					fmt.Fprintln(renderer.Streams.Stdout.File)
+					renderer.Streams.Println(diff)
+				}
 			}
 		}
 
@@ -377,6 +389,9 @@ func renderHumanDiff(renderer Renderer, diff diff, cause string) (string, bool)
 
 	var buf bytes.Buffer
 	buf.WriteString(renderer.Colorize.Color(resourceChangeComment(diff.change, action, cause)))
+	if len(diff.upstream) > 0 {
+		buf.WriteString(renderer.Colorize.Color(fmt.Sprintf("  # [reset](caused by the replacement of %s)\n", diff.upstream)))
+	}
 
 	opts := computed.NewRenderHumanOpts(renderer.Colorize, renderer.ShowSensitive)
 
@@ -541,6 +556,12 @@ func resourceChangeComment(resource jsonplan.ResourceChange, action plans.Action
 	if resource.Change.Importing != nil && (action == plans.CreateThenDelete || action == plans.DeleteThenCreate) {
 		buf.WriteString("  # [reset][yellow]Warning: this will destroy the imported resource[reset]\n")
 	}
+	if len(resource.Note) > 0 {
+		// Notes are free-form text from "tofu state annotate", so we fold
+		// them onto a single line to keep the comment well-formed.
+		note := strings.Join(strings.Fields(resource.Note), " ")
+		buf.WriteString(fmt.Sprintf("  # [reset][yellow](note: %s)[reset]\n", note))
+	}
 
 	return buf.String()
 }
@@ -429,6 +429,176 @@ Plan: 1 to import, 1 to add, 0 to change, 1 to destroy.
 	}
 }
 
+func TestRenderHuman_ChangeCauses(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_resource": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+							"value": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resourceChange := func(name string, actions []string, before, after, afterUnknown map[string]interface{}) jsonplan.ResourceChange {
+		change := jsonplan.ResourceChange{
+			Address:      "test_resource." + name,
+			Mode:         "managed",
+			Type:         "test_resource",
+			Name:         name,
+			ProviderName: "test",
+			Change: jsonplan.Change{
+				Actions:      actions,
+				Before:       marshalJson(t, before),
+				After:        marshalJson(t, after),
+				AfterUnknown: marshalJson(t, afterUnknown),
+			},
+		}
+		if len(actions) > 1 {
+			change.Change.ReplacePaths = marshalJson(t, [][]string{{"value"}})
+		}
+		return change
+	}
+
+	tcs := map[string]struct {
+		plan   Plan
+		output string
+	}{
+		"direct_only": {
+			plan: Plan{
+				ResourceChanges: []jsonplan.ResourceChange{
+					resourceChange("a", []string{"update"},
+						map[string]interface{}{"id": "a", "value": "old"},
+						map[string]interface{}{"id": "a", "value": "new"},
+						map[string]interface{}{}),
+				},
+				ResourceDependencies: map[string][]string{},
+			},
+			output: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  ~ update in-place
+
+OpenTofu will perform the following actions:
+
+  # test_resource.a will be updated in-place
+  ~ resource "test_resource" "a" {
+        id    = "a"
+      ~ value = "old" -> "new"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+		"grouped": {
+			plan: Plan{
+				ResourceChanges: []jsonplan.ResourceChange{
+					resourceChange("a", []string{"delete", "create"},
+						map[string]interface{}{"id": "a", "value": "old"},
+						map[string]interface{}{"value": "new"},
+						map[string]interface{}{"id": true}),
+					resourceChange("b", []string{"update"},
+						map[string]interface{}{"id": "b", "value": "a"},
+						map[string]interface{}{"id": "b"},
+						map[string]interface{}{"value": true}),
+					resourceChange("c", []string{"update"},
+						map[string]interface{}{"id": "c", "value": "changed"},
+						map[string]interface{}{"id": "c", "value": "original"},
+						map[string]interface{}{}),
+					resourceChange("d", []string{"update"},
+						map[string]interface{}{"id": "d", "value": "a"},
+						map[string]interface{}{"id": "d", "value": "new"},
+						map[string]interface{}{}),
+				},
+				ResourceDrift: []jsonplan.ResourceChange{
+					resourceChange("c", []string{"update"},
+						map[string]interface{}{"id": "c", "value": "original"},
+						map[string]interface{}{"id": "c", "value": "changed"},
+						map[string]interface{}{}),
+				},
+				ResourceDependencies: map[string][]string{
+					"test_resource.b": {"test_resource.a"},
+					"test_resource.d": {"test_resource.a"},
+				},
+			},
+			output: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  ~ update in-place
+-/+ destroy and then create replacement
+
+OpenTofu will perform the following actions:
+
+Changes to match the configuration:
+
+  # test_resource.a must be replaced
+-/+ resource "test_resource" "a" {
+      ~ id    = "a" -> (known after apply)
+      ~ value = "old" -> "new" # forces replacement
+    }
+
+  # test_resource.d will be updated in-place
+  ~ resource "test_resource" "d" {
+        id    = "d"
+      ~ value = "a" -> "new"
+    }
+
+Changes to undo changes made outside of OpenTofu:
+
+  # test_resource.c will be updated in-place
+  ~ resource "test_resource" "c" {
+        id    = "c"
+      ~ value = "changed" -> "original"
+    }
+
+Changes caused by the replacement of other resources:
+
+  # test_resource.b will be updated in-place
+  # (caused by the replacement of test_resource.a)
+  ~ resource "test_resource" "b" {
+        id    = "b"
+      ~ value = "a" -> (known after apply)
+    }
+
+Plan: 1 to add, 3 to change, 1 to destroy.
+`,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+
+			plan := tc.plan
+			plan.PlanFormatVersion = jsonplan.FormatVersion
+			plan.ProviderFormatVersion = jsonprovider.FormatVersion
+			plan.ProviderSchemas = schemas
+
+			renderer := Renderer{
+				Colorize: color,
+				Streams:  streams,
+			}
+			plan.renderHuman(renderer, plans.NormalMode)
+
+			got := done(t).Stdout()
+			want := tc.output
+			if diff := cmp.Diff(want, got); len(diff) > 0 {
+				t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, want, diff)
+			}
+		})
+	}
+}
+
 func TestResourceChange_primitiveTypes(t *testing.T) {
 	testCases := map[string]testCase{
 		"creation": {
@@ -7103,6 +7273,59 @@ func TestResourceChange_moved(t *testing.T) {
 	runTestCases(t, testCases)
 }
 
+func TestResourceChange_note(t *testing.T) {
+	testCases := map[string]testCase{
+		"updated": {
+			Action: plans.Update,
+			Mode:   addrs.ManagedResourceMode,
+			Before: cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("12345"),
+				"bar": cty.StringVal("baz"),
+			}),
+			After: cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("12345"),
+				"bar": cty.StringVal("boop"),
+			}),
+			Schema: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id":  {Type: cty.String, Computed: true},
+					"bar": {Type: cty.String, Optional: true},
+				},
+			},
+			RequiredReplace: cty.NewPathSet(),
+			Note:            "ticket-1234 frozen until Q3",
+			ExpectedOutput: `  # test_instance.example will be updated in-place
+  # (note: ticket-1234 frozen until Q3)
+  ~ resource "test_instance" "example" {
+      ~ bar = "baz" -> "boop"
+        id  = "12345"
+    }`,
+		},
+		"destroyed with multi-line note": {
+			Action: plans.Delete,
+			Mode:   addrs.ManagedResourceMode,
+			Before: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("12345"),
+			}),
+			After: cty.NullVal(cty.EmptyObject),
+			Schema: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+			RequiredReplace: cty.NewPathSet(),
+			Note:            "owned by team-a\ndo not destroy",
+			ExpectedOutput: `  # test_instance.example will be destroyed
+  # (note: owned by team-a do not destroy)
+  - resource "test_instance" "example" {
+      - id = "12345" -> null
+    }`,
+		},
+	}
+
+	runTestCases(t, testCases)
+}
+
 type testCase struct {
 	Action          plans.Action
 	ActionReason    plans.ResourceInstanceChangeActionReason
@@ -7118,6 +7341,7 @@ type testCase struct {
 	RequiredReplace cty.PathSet
 	ExpectedOutput  string
 	PrevRunAddr     addrs.AbsResourceInstance
+	Note            string
 }
 
 func runTestCases(t *testing.T, testCases map[string]testCase) {
@@ -7208,6 +7432,8 @@ func runTestCases(t *testing.T, testCases map[string]testCase) {
 				return
 			}
 
+			jsonchanges[0].Note = tc.Note
+
 			jsonschemas := jsonprovider.MarshalForRenderer(tfschemas)
 			change := structured.FromJsonChange(jsonchanges[0].Change, attribute_path.AlwaysMatcher())
 			renderer := Renderer{Colorize: color}
@@ -187,6 +187,7 @@ func MarshalForRenderer(
 	if output.ResourceChanges, err = MarshalResourceChanges(p.Changes.Resources, schemas); err != nil {
 		return nil, nil, nil, nil, err
 	}
+	marshalResourceChangeNotes(p, output.ResourceChanges)
 
 	if len(p.DriftedResources) > 0 {
 		// In refresh-only mode, we render all resources marked as drifted,
@@ -207,6 +208,7 @@ func MarshalForRenderer(
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
+		marshalResourceChangeNotes(p, output.ResourceDrift)
 	}
 
 	if err := output.marshalRelevantAttrs(p); err != nil {
@@ -260,6 +262,7 @@ func MarshalForLog(
 		if err != nil {
 			return nil, fmt.Errorf("error in marshaling resource drift: %w", err)
 		}
+		marshalResourceChangeNotes(p, output.ResourceDrift)
 	}
 
 	if err := output.marshalRelevantAttrs(p); err != nil {
@@ -272,6 +275,7 @@ func MarshalForLog(
 		if err != nil {
 			return nil, fmt.Errorf("error in marshaling resource changes: %w", err)
 		}
+		marshalResourceChangeNotes(p, output.ResourceChanges)
 	}
 
 	// output.OutputChanges
@@ -279,6 +283,14 @@ func MarshalForLog(
 		return nil, fmt.Errorf("error in marshaling output changes: %w", err)
 	}
 
+	// Internal outputs are only available within the configuration.
+	for name, oc := range config.Module.Outputs {
+		if oc.Internal {
+			delete(output.OutputChanges, name)
+			delete(output.PlannedValues.Outputs, name)
+		}
+	}
+
 	// output.Checks
 	if p.Checks != nil && p.Checks.ConfigResults.Len() > 0 {
 		output.Checks = jsonchecks.MarshalCheckStates(p.Checks)
@@ -352,6 +364,11 @@ func (p *Plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 		if _, ok := p.Variables[name]; ok {
 			continue
 		}
+		if decl.Ephemeral {
+			// Ephemeral variables are not saved in the plan, so we don't
+			// know whether the default value was used.
+			continue
+		}
 		if val := decl.Default; val != cty.NilVal {
 			valJSON, err := ctyjson.Marshal(val, val.Type())
 			if err != nil {
@@ -370,6 +387,71 @@ func (p *Plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 	return nil
 }
 
+// marshalResourceChangeNotes copies the operator notes of the changed resource
+// instances from the states recorded in the plan into the given changes.
+func marshalResourceChangeNotes(p *plans.Plan, changes []ResourceChange) {
+	for i := range changes {
+		addr, diags := addrs.ParseAbsResourceInstanceStr(changes[i].Address)
+		if diags.HasErrors() {
+			continue
+		}
+		// Instances that were deleted outside of OpenTofu are only present
+		// in the state from the previous run.
+		for _, state := range []*states.State{p.PriorState, p.PrevRunState} {
+			if state == nil {
+				continue
+			}
+			if is := state.ResourceInstance(addr); is != nil {
+				changes[i].Note = is.Note
+				break
+			}
+		}
+	}
+}
+
+// ResourceDependencies returns, for each resource instance in the prior state
+// of the given plan, the addresses of the resource instances it depends on,
+// as recorded in the state at the end of the previous apply.
+//
+// The JSON plan format doesn't include this, so the renderer receives it
+// separately in order to explain which changes are caused by changes to
+// other resources.
+func ResourceDependencies(p *plans.Plan) map[string][]string {
+	if p.PriorState == nil {
+		return nil
+	}
+
+	// The state records dependencies as resources rather than as resource
+	// instances, so we need to find the instances of each of them.
+	instances := make(map[string][]string)
+	for _, ms := range p.PriorState.Modules {
+		for _, rs := range ms.Resources {
+			key := rs.Addr.Config().String()
+			for instKey := range rs.Instances {
+				instances[key] = append(instances[key], rs.Addr.Instance(instKey).String())
+			}
+		}
+	}
+
+	ret := make(map[string][]string)
+	for _, ms := range p.PriorState.Modules {
+		for _, rs := range ms.Resources {
+			for instKey, is := range rs.Instances {
+				if is.Current == nil || len(is.Current.Dependencies) == 0 {
+					continue
+				}
+				var deps []string
+				for _, dep := range is.Current.Dependencies {
+					deps = append(deps, instances[dep.String()]...)
+				}
+				sort.Strings(deps)
+				ret[rs.Addr.Instance(instKey).String()] = deps
+			}
+		}
+	}
+	return ret
+}
+
 // MarshalResourceChanges converts the provided internal representation of
 // ResourceInstanceChangeSrc objects into the public structured JSON changes.
 //
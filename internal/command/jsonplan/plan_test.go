@@ -17,6 +17,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
 )
 
 func TestOmitUnknowns(t *testing.T) {
@@ -459,6 +460,55 @@ func TestOutputs(t *testing.T) {
 	}
 }
 
+func TestResourceDependencies(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	upstream := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_thing",
+		Name: "upstream",
+	}
+	downstream := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_thing",
+		Name: "downstream",
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1)} {
+			s.SetResourceInstanceCurrent(
+				upstream.Instance(key).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{}`),
+				},
+				provider,
+				addrs.NoKey,
+			)
+		}
+		s.SetResourceInstanceCurrent(
+			downstream.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				Status:       states.ObjectReady,
+				AttrsJSON:    []byte(`{}`),
+				Dependencies: []addrs.ConfigResource{upstream.InModule(addrs.RootModule)},
+			},
+			provider,
+			addrs.NoKey,
+		)
+	})
+
+	got := ResourceDependencies(&plans.Plan{PriorState: state})
+	want := map[string][]string{
+		"test_thing.downstream": {"test_thing.upstream[0]", "test_thing.upstream[1]"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
 func deepObjectValue(depth int) cty.Value {
 	v := cty.ObjectVal(map[string]cty.Value{
 		"a": cty.StringVal("a"),
@@ -94,4 +94,8 @@ type ResourceChange struct {
 	// information should be resilient to encountering unrecognized values
 	// and treat them as an unspecified reason.
 	ActionReason string `json:"action_reason,omitempty"`
+
+	// Note is the operator note attached to the resource instance using
+	// "tofu state annotate", if any.
+	Note string `json:"note,omitempty"`
 }
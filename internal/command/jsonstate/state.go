@@ -223,7 +223,7 @@ func (jsonstate *State) marshalStateValues(s *states.State, schemas *tofu.Schema
 }
 
 // MarshalOutputs translates a map of states.OutputValue to a map of jsonstate.Output,
-// which are defined for json encoding.
+// which are defined for json encoding. Internal outputs are omitted.
 func MarshalOutputs(outputs map[string]*states.OutputValue) (map[string]Output, error) {
 	if outputs == nil {
 		return nil, nil
@@ -231,6 +231,10 @@ func MarshalOutputs(outputs map[string]*states.OutputValue) (map[string]Output,
 
 	ret := make(map[string]Output)
 	for k, v := range outputs {
+		if v.Internal {
+			// Internal outputs are only available within the configuration.
+			continue
+		}
 		ty := v.Value.Type()
 		ov, err := ctyjson.Marshal(v.Value, ty)
 		if err != nil {
@@ -0,0 +1,179 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lint"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// LintCommand is a Command implementation that checks a configuration for
+// likely mistakes and outdated patterns.
+type LintCommand struct {
+	Meta
+}
+
+func (c *LintCommand) Run(rawArgs []string) int {
+	// Parse and apply global view arguments
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Parse and validate flags
+	args, diags := arguments.ParseLint(rawArgs)
+	if diags.HasErrors() {
+		c.View.Diagnostics(diags)
+		c.View.HelpPrompt("lint")
+		return 1
+	}
+
+	rules, moreDiags := lintRules(args.Disable)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.View.Diagnostics(diags)
+		c.View.HelpPrompt("lint")
+		return 1
+	}
+
+	view := views.NewLint(args.ViewType, c.View)
+
+	// After this point, we must only produce JSON output if JSON mode is
+	// enabled, so all errors should be accumulated into diags and we'll
+	// print out a suitable result at the end, depending on the format
+	// selection. All returns from this point on must be tail-calls into
+	// view.Results in order to produce the expected output.
+
+	dir, err := filepath.Abs(args.Path)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("unable to locate module: %w", err))
+		return view.Results(nil, diags)
+	}
+
+	// Inject variables from args into meta for static evaluation
+	c.GatherVariables(args.Vars)
+
+	cfg, cfgDiags := c.loadConfig(dir)
+	if cfgDiags.HasErrors() {
+		return view.Results(nil, diags.Append(cfgDiags))
+	}
+
+	// The warnings about deprecated language features are reported by the
+	// deprecated rule instead, if it's enabled, so that each issue is only
+	// reported once and can be disabled like any other.
+	var deprecations tfdiags.Diagnostics
+	for _, diag := range cfgDiags {
+		if configs.IsDeprecationWarning(diag) {
+			deprecations = append(deprecations, diag)
+			continue
+		}
+		diags = diags.Append(diag)
+	}
+
+	issues := lint.Check(cfg, c.configSources(), deprecations, rules)
+	return view.Results(issues, diags)
+}
+
+func (c *LintCommand) GatherVariables(args *arguments.Vars) {
+	// FIXME the arguments package currently trivially gathers variable related
+	// arguments in a heterogeneous slice, in order to minimize the number of
+	// code paths gathering variables during the transition to this structure.
+	// Once all commands that gather variables have been converted to this
+	// structure, we could move the variable gathering code to the arguments
+	// package directly, removing this shim layer.
+
+	varArgs := args.All()
+	items := make([]rawFlag, len(varArgs))
+	for i := range varArgs {
+		items[i].Name = varArgs[i].Name
+		items[i].Value = varArgs[i].Value
+	}
+	c.Meta.variableArgs = rawFlags{items: &items}
+}
+
+// lintRules returns all of the lint rules except for those with the given
+// names, or errors if any of the names isn't the name of a rule.
+func lintRules(disable []string) ([]*lint.Rule, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	disabled := make(map[string]bool, len(disable))
+	for _, name := range disable {
+		if lint.LookupRule(name) == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid rule name",
+				fmt.Sprintf("There is no lint rule named %q. Run \"tofu lint -help\" to see the available rules.", name),
+			))
+			continue
+		}
+		disabled[name] = true
+	}
+
+	var rules []*lint.Rule
+	for _, r := range lint.Rules() {
+		if !disabled[r.Name] {
+			rules = append(rules, r)
+		}
+	}
+	return rules, diags
+}
+
+func (c *LintCommand) Synopsis() string {
+	return "Check the configuration for likely mistakes"
+}
+
+func (c *LintCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] lint [options] [DIR]
+
+  Check the configuration in a directory, and the modules it calls, for
+  likely mistakes and outdated patterns that are not errors, such as
+  variables that nothing refers to or uses of deprecated arguments.
+
+  Lint only refers to the configuration, like the validate command, and
+  requires an initialized working directory with any referenced modules
+  installed.
+
+  The exit status is 0 if no issues are found, 2 if there are issues, and 1
+  if the configuration can't be checked.
+
+Rules:
+
+` + lintRulesHelp() + `
+Options:
+
+  -disable=name         Don't run the rule with the given name. Use this
+                        option more than once to disable more than one rule.
+
+  -json                 Produce output in a machine-readable JSON format,
+                        suitable for use in text editor integrations and other
+                        automated systems. Always disables color.
+
+  -no-color             If specified, output won't contain any color.
+
+  -var 'foo=bar'        Set a value for one of the input variables in the root
+                        module of the configuration. Use this option more than
+                        once to set more than one variable.
+
+  -var-file=filename    Load variable values from the given file, in addition
+                        to the default files terraform.tfvars and *.auto.tfvars.
+                        Use this option more than once to include more than one
+                        variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func lintRulesHelp() string {
+	var buf strings.Builder
+	for _, r := range lint.Rules() {
+		fmt.Fprintf(&buf, "  %-22s%s\n\n", r.Name, r.Description)
+	}
+	return buf.String()
+}
@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("lint"), td)
+	defer testChdir(t, td)()
+
+	t.Run("human", func(t *testing.T) {
+		view, done := testView(t)
+		c := &LintCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				View:             view,
+			},
+		}
+
+		code := c.Run([]string{"-no-color"})
+		output := done(t)
+		if code != 2 {
+			t.Fatalf("wrong exit code %d; want 2\n\n%s", code, output.All())
+		}
+		got := output.All()
+		for _, want := range []string{
+			`Warning: Unused variable`,
+			`The input variable "unused" is declared`,
+			`-disable=unused_variable`,
+			`Warning: Version constraints inside provider configuration blocks are deprecated`,
+			`-disable=deprecated`,
+			`Found 2 issue(s).`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output does not contain %q\n%s", want, got)
+			}
+		}
+		// The deprecation is only reported as an issue, not again as an
+		// ordinary warning.
+		if n := strings.Count(got, "Version constraints inside provider"); n != 1 {
+			t.Errorf("deprecation reported %d times\n%s", n, got)
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		view, done := testView(t)
+		c := &LintCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				View:             view,
+			},
+		}
+
+		code := c.Run([]string{"-no-color", "-disable=deprecated", "-disable=unused_variable"})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("wrong exit code %d; want 0\n\n%s", code, output.All())
+		}
+		if got, want := output.Stdout(), "Success! No issues found."; !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		view, done := testView(t)
+		c := &LintCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				View:             view,
+			},
+		}
+
+		code := c.Run([]string{"-json", "-disable=deprecated"})
+		output := done(t)
+		if code != 2 {
+			t.Fatalf("wrong exit code %d; want 2\n\n%s", code, output.All())
+		}
+
+		var got struct {
+			IssueCount int `json:"issue_count"`
+			Issues     []struct {
+				Rule    string `json:"rule"`
+				Summary string `json:"summary"`
+				Range   struct {
+					Filename string `json:"filename"`
+				} `json:"range"`
+			} `json:"issues"`
+		}
+		if err := json.Unmarshal([]byte(output.Stdout()), &got); err != nil {
+			t.Fatalf("invalid JSON output: %s\n%s", err, output.Stdout())
+		}
+		if got.IssueCount != 1 || len(got.Issues) != 1 {
+			t.Fatalf("wrong issues\n%s", output.Stdout())
+		}
+		if issue := got.Issues[0]; issue.Rule != "unused_variable" || issue.Summary != "Unused variable" || issue.Range.Filename != "main.tf" {
+			t.Errorf("wrong issue %#v", issue)
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		view, done := testView(t)
+		c := &LintCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				View:             view,
+			},
+		}
+
+		code := c.Run([]string{"-disable=nonexistent"})
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.All())
+		}
+		if got, want := output.Stderr(), `There is no lint rule named "nonexistent"`; !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	})
+}
@@ -29,16 +29,20 @@ import (
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/notifications"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/command/webbrowser"
 	"github.com/opentofu/opentofu/internal/command/workdir"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
+	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -108,6 +112,15 @@ type Meta struct {
 	// into the given directory.
 	PluginCacheDir string
 
+	// EncryptionPluginChecksums are the SHA-256 checksums of the encryption
+	// plugins in PluginCacheDir that OpenTofu may run, keyed by the file name
+	// of the plugin executable.
+	EncryptionPluginChecksums map[string][]byte
+
+	// ModuleCacheDir, if non-empty, enables caching of downloaded registry
+	// module packages into the given directory.
+	ModuleCacheDir string
+
 	// PluginCacheMayBreakDependencyLockFile is a temporary CLI configuration-based
 	// opt out for the behavior of only using the plugin cache dir if its
 	// contents match checksums recorded in the dependency lock file.
@@ -124,11 +137,30 @@ type Meta struct {
 	// longer any compelling reasons for folks to not lock their dependencies.
 	PluginCacheMayBreakDependencyLockFile bool
 
+	// StrictDeprecations causes the configuration loader to report uses of
+	// deprecated language features as errors rather than warnings. It is
+	// set either by the CLI configuration or by "tofu validate -strict".
+	StrictDeprecations bool
+
+	// StateLockRetryPolicy is the policy for retrying to acquire a state
+	// lock held by another process, from the CLI configuration. The zero
+	// value selects statemgr.DefaultLockRetryPolicy.
+	StateLockRetryPolicy statemgr.LockRetryPolicy
+
+	// NotificationWebhooks are the webhooks to notify about the progress of
+	// plan and apply operations, from the CLI configuration.
+	NotificationWebhooks []*notifications.Webhook
+
 	// ProviderSource allows determining the available versions of a provider
 	// and determines where a distribution package for a particular
 	// provider version can be obtained.
 	ProviderSource getproviders.Source
 
+	// ModulePackageFetcherEnv provides access to OCI registries when
+	// installing module packages from them. If nil, OCI registries are
+	// accessed without any credentials.
+	ModulePackageFetcherEnv getmodules.PackageFetcherEnvironment
+
 	// BrowserLauncher is used by commands that need to open a URL in a
 	// web browser.
 	BrowserLauncher webbrowser.Launcher
@@ -239,6 +271,22 @@ type Meta struct {
 	// parallelism is used to control the number of concurrent operations
 	// allowed when walking the graph
 	//
+	// schedule is the policy used to order graph nodes that are waiting for
+	// one of the parallelism slots
+	//
+	// quarantineAfter is the number of consecutive failed applies after
+	// which a resource instance is quarantined, or zero to disable it
+	//
+	// runTimeout is the maximum duration of an operation started by
+	// RunOperation before it is stopped gracefully, or zero for no limit
+	//
+	// runTimeoutGrace is how long an operation that was stopped because it
+	// exceeded runTimeout may take to finish before it is canceled, or zero
+	// to use defaultRunTimeoutGrace
+	//
+	// moduleChannel is the name of the module channel that selects the
+	// versions of registry modules during module installation, if any
+	//
 	// provider is to specify specific resource providers
 	//
 	// stateLock is set to false to disable state locking
@@ -246,6 +294,9 @@ type Meta struct {
 	// stateLockTimeout is the optional duration to retry a state locks locks
 	// when it is already locked by another process.
 	//
+	// stateLockRetry overrides parts of StateLockRetryPolicy from the command
+	// line.
+	//
 	// forceInitCopy suppresses confirmation for copying state data during
 	// init.
 	//
@@ -266,8 +317,14 @@ type Meta struct {
 	stateOutPath        string
 	backupPath          string
 	parallelism         int
+	schedule            string
+	quarantineAfter     int
+	runTimeout          time.Duration
+	runTimeoutGrace     time.Duration
+	moduleChannel       string
 	stateLock           bool
 	stateLockTimeout    time.Duration
+	stateLockRetry      arguments.LockRetry
 	forceInitCopy       bool
 	reconfigure         bool
 	migrateState        bool
@@ -285,6 +342,21 @@ type Meta struct {
 	// This helps prevent duplicate errors/warnings.
 	rootModuleCallCache *configs.StaticModuleCall
 	inputVariableCache  map[string]backend.UnparsedVariableValue
+
+	// workspaceOverride, if set, is used as the current workspace instead of
+	// the selected one, so that "tofu workspace sync" can load the variables
+	// and the encryption configuration of each declared workspace in turn.
+	workspaceOverride string
+
+	// encryptionRegistry is the encryption registry shared by all the
+	// encryption configurations loaded by the command, so that secrets
+	// entered interactively are only requested once. It is initialized on
+	// first use.
+	encryptionRegistry registry.Registry
+
+	// notifications is the hook that sends the messages for the
+	// NotificationWebhooks. It is initialized on first use.
+	notifications *notificationHook
 }
 
 type testingOverrides struct {
@@ -510,7 +582,14 @@ func (m *Meta) RunOperation(ctx context.Context, b backend.Enhanced, opReq *back
 		return nil, diags.Append(fmt.Errorf("error starting operation: %w", err))
 	}
 
-	// Wait for the operation to complete or an interrupt to occur
+	var timeoutCh <-chan time.Time
+	if m.runTimeout > 0 {
+		timer := time.NewTimer(m.runTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	// Wait for the operation to complete, an interrupt, or the run timeout
 	select {
 	case <-m.ShutdownCh:
 		// gracefully stop the operation
@@ -520,30 +599,99 @@ func (m *Meta) RunOperation(ctx context.Context, b backend.Enhanced, opReq *back
 		opReq.View.Interrupted()
 
 		// Still get the result, since there is still one
-		select {
-		case <-m.ShutdownCh:
-			opReq.View.FatalInterrupt()
+		if err := m.waitStoppedOperation(op, opReq.View, nil); err != nil {
+			return nil, diags.Append(err)
+		}
+	case <-timeoutCh:
+		// Stopping gracefully prevents any new nodes from starting, asks
+		// the providers to cancel their in-flight requests, and lets the
+		// backend persist whatever state was produced so far, so this is
+		// just like the user interrupting the operation.
+		op.Stop()
+		opReq.View.TimedOut(m.runTimeout)
+
+		// A provider that ignores the request to stop could keep the
+		// operation running forever, so it's canceled if it doesn't stop
+		// within the grace period.
+		grace := m.runTimeoutGrace
+		if grace == 0 {
+			grace = defaultRunTimeoutGrace
+		}
+		graceTimer := time.NewTimer(grace)
+		defer graceTimer.Stop()
+
+		err := m.waitStoppedOperation(op, opReq.View, graceTimer.C)
+		if err == errStopDeadlineExceeded {
+			return nil, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Run timeout exceeded",
+				fmt.Sprintf(
+					"OpenTofu canceled the operation because it exceeded the -run-timeout of %s and did not stop within %s after that. Changes that were in progress when the operation was canceled might not have been saved in the state.",
+					m.runTimeout, grace,
+				),
+			))
+		}
+		if err != nil {
+			return nil, diags.Append(err)
+		}
+		if op.Result != backend.OperationSuccess {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Run timeout exceeded",
+				fmt.Sprintf(
+					"OpenTofu stopped the operation because it exceeded the -run-timeout of %s. Any changes applied before the timeout have been saved in the state, and any remaining changes can be made by running the operation again.",
+					m.runTimeout,
+				),
+			))
+		}
+	case <-op.Done():
+		// operation completed normally
+	}
 
-			// cancel the operation completely
-			op.Cancel()
+	return op, diags
+}
 
-			// the operation should return asap
-			// but timeout just in case
-			select {
-			case <-op.Done():
-			case <-time.After(5 * time.Second):
-			}
+// defaultRunTimeoutGrace is how long an operation that was stopped because it
+// exceeded the -run-timeout may take to finish before it is canceled.
+const defaultRunTimeoutGrace = time.Minute
 
-			return nil, diags.Append(errors.New("operation canceled"))
+// errStopDeadlineExceeded is returned by waitStoppedOperation when the
+// operation didn't stop before the deadline.
+var errStopDeadlineExceeded = errors.New("operation canceled because it did not stop in time")
+
+// waitStoppedOperation waits for an operation that has been asked to stop
+// gracefully to complete. If another interrupt arrives first, the operation
+// is canceled instead and waitStoppedOperation returns an error. If the
+// given deadline channel isn't nil and receives first, the operation is
+// canceled too and waitStoppedOperation returns errStopDeadlineExceeded.
+func (m *Meta) waitStoppedOperation(op *backend.RunningOperation, view views.Operation, deadline <-chan time.Time) error {
+	select {
+	case <-m.ShutdownCh:
+		view.FatalInterrupt()
+		cancelOperation(op)
+		return errors.New("operation canceled")
+
+	case <-deadline:
+		cancelOperation(op)
+		return errStopDeadlineExceeded
 
-		case <-op.Done():
-			// operation completed after Stop
-		}
 	case <-op.Done():
-		// operation completed normally
+		// operation completed after Stop
+		return nil
 	}
+}
 
-	return op, diags
+// cancelOperation cancels the given operation completely and waits briefly
+// for it to return.
+func cancelOperation(op *backend.RunningOperation) {
+	op.Cancel()
+
+	// the operation should return asap
+	// but timeout just in case
+	select {
+	case <-op.Done():
+	case <-time.After(5 * time.Second):
+	}
 }
 
 // contextOpts returns the options to use to initialize a OpenTofu
@@ -558,6 +706,11 @@ func (m *Meta) contextOpts() (*tofu.ContextOpts, error) {
 
 	opts.UIInput = m.UIInput()
 	opts.Parallelism = m.parallelism
+	opts.SchedulePolicy, err = tofu.ParseSchedulePolicy(m.schedule)
+	if err != nil {
+		return nil, err
+	}
+	opts.QuarantineAfter = m.quarantineAfter
 
 	// If testingOverrides are set, we'll skip the plugin discovery process
 	// and just work with what we've been given, thus allowing the tests
@@ -691,6 +844,24 @@ func (m *Meta) uiHook() *views.UiHook {
 	return views.NewUiHook(m.View)
 }
 
+// notificationHook returns the hook that sends messages to the webhooks in the
+// notifications block of the CLI configuration, or nil if there are none.
+// Commands add it to the hooks of their operation, and then use it again to
+// report the outcome of the operation.
+func (m *Meta) notificationHook() *notificationHook {
+	if len(m.NotificationWebhooks) == 0 {
+		return nil
+	}
+	if m.notifications == nil {
+		workspace, err := m.Workspace()
+		if err != nil {
+			workspace = backend.DefaultStateName
+		}
+		m.notifications = newNotificationHook(m.NotificationWebhooks, workspace)
+	}
+	return m.notifications
+}
+
 // confirm asks a yes/no confirmation.
 func (m *Meta) confirm(opts *tofu.InputOpts) (bool, error) {
 	if !m.Input() {
@@ -813,6 +984,10 @@ func (m *Meta) Workspace() (string, error) {
 // corresponding to the desired named state, as well as a bool saying whether
 // this was set via the TF_WORKSPACE environment variable.
 func (m *Meta) WorkspaceOverridden() (string, bool) {
+	if m.workspaceOverride != "" {
+		return m.workspaceOverride, false
+	}
+
 	if envVar := os.Getenv(WorkspaceNameEnvVar); envVar != "" {
 		return envVar, true
 	}
@@ -858,11 +1033,22 @@ func isAutoVarFile(path string) bool {
 func (m *Meta) applyStateArguments(args *arguments.State) {
 	m.stateLock = args.Lock
 	m.stateLockTimeout = args.LockTimeout
+	m.stateLockRetry = args.LockRetry
 	m.statePath = args.StatePath
 	m.stateOutPath = args.StateOutPath
 	m.backupPath = args.BackupPath
 }
 
+// stateLockRetryPolicy returns the policy for retrying to acquire a state
+// lock, combining the CLI configuration with any command line overrides.
+func (m *Meta) stateLockRetryPolicy() statemgr.LockRetryPolicy {
+	policy := m.StateLockRetryPolicy
+	if policy == (statemgr.LockRetryPolicy{}) {
+		policy = statemgr.DefaultLockRetryPolicy()
+	}
+	return m.stateLockRetry.Apply(policy)
+}
+
 // checkRequiredVersion loads the config and check if the
 // core version requirements are satisfied.
 func (m *Meta) checkRequiredVersion() tfdiags.Diagnostics {
@@ -60,6 +60,14 @@ type BackendOpts struct {
 	// You probably don't want to set this.
 	ForceLocal bool
 
+	// ReadOnly should be set to true by commands that only read the state,
+	// such as "tofu state list". Provider plugins that are not consistent
+	// with the dependency lock file are then reported as a warning rather
+	// than an error, so that the state can still be inspected when a
+	// provider can't be installed. Anything that depends on the schema of
+	// such a provider is unavailable.
+	ReadOnly bool
+
 	// ViewType will set console output format for the
 	// initialization operation (JSON or human-readable).
 	ViewType arguments.ViewType
@@ -114,7 +122,10 @@ func (m *Meta) Backend(opts *BackendOpts, enc encryption.StateEncryption) (backe
 		log.Printf("[TRACE] Meta.Backend: instantiated backend of type %T", b)
 	}
 
-	// Set up the CLI opts we pass into backends that support it.
+	// Set up the CLI opts we pass into backends that support it. For
+	// read-only commands, problems with the provider plugins are returned
+	// as warnings in pluginDiags along with the backend.
+	var pluginDiags tfdiags.Diagnostics
 	cliOpts, err := m.backendCLIOpts()
 	if err != nil {
 		if errs := providerPluginErrors(nil); errors.As(err, &errs) {
@@ -135,15 +146,29 @@ func (m *Meta) Backend(opts *BackendOpts, enc encryption.StateEncryption) (backe
 					// Don't mention "tofu init" specifically if we're running in an automation wrapper
 					suggestion = "You must install the required plugins before running OpenTofu operations."
 				}
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Required plugins are not installed",
-					fmt.Sprintf(
-						"The installed provider plugins are not consistent with the packages selected in the dependency lock file:%s\n\nOpenTofu uses external plugins to integrate with a variety of different infrastructure services. %s",
-						buf.String(), suggestion,
-					),
-				))
-				return nil, diags
+				if opts.ReadOnly {
+					// Reading the state doesn't need the plugins, so we
+					// continue without them. Commands that use provider
+					// schemas report the missing ones separately.
+					pluginDiags = pluginDiags.Append(tfdiags.Sourceless(
+						tfdiags.Warning,
+						"Required plugins are not installed",
+						fmt.Sprintf(
+							"The installed provider plugins are not consistent with the packages selected in the dependency lock file:%s\n\nThe state can still be read, but any information that depends on these providers is not available. %s",
+							buf.String(), suggestion,
+						),
+					))
+				} else {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Required plugins are not installed",
+						fmt.Sprintf(
+							"The installed provider plugins are not consistent with the packages selected in the dependency lock file:%s\n\nOpenTofu uses external plugins to integrate with a variety of different infrastructure services. %s",
+							buf.String(), suggestion,
+						),
+					))
+					return nil, diags
+				}
 			}
 		} else {
 			// All other errors just get generic handling.
@@ -169,7 +194,7 @@ func (m *Meta) Backend(opts *BackendOpts, enc encryption.StateEncryption) (backe
 	// then return that as-is. This works even if b == nil (it will be !ok).
 	if enhanced, ok := b.(backend.Enhanced); ok {
 		log.Printf("[TRACE] Meta.Backend: backend %T supports operations", b)
-		return enhanced, nil
+		return enhanced, pluginDiags
 	}
 
 	// We either have a non-enhanced backend or no backend configured at
@@ -210,7 +235,7 @@ func (m *Meta) Backend(opts *BackendOpts, enc encryption.StateEncryption) (backe
 		}
 	}
 
-	return local, nil
+	return local, pluginDiags
 }
 
 // selectWorkspace gets a list of existing workspaces and then checks
@@ -426,7 +451,7 @@ func (m *Meta) Operation(b backend.Backend, vt arguments.ViewType, enc encryptio
 	stateLocker := clistate.NewNoopLocker()
 	if m.stateLock {
 		view := views.NewStateLocker(vt, m.View)
-		stateLocker = clistate.NewLocker(m.stateLockTimeout, view)
+		stateLocker = clistate.NewLockerWithRetryPolicy(m.stateLockTimeout, m.stateLockRetryPolicy(), view)
 	}
 
 	depLocks, diags := m.lockedDependencies()
@@ -926,6 +951,7 @@ func (m *Meta) backend_c_r_S(
 		Source:          b,
 		Destination:     localB,
 		ViewType:        vt,
+		Encryption:      enc,
 	})
 	if err != nil {
 		diags = diags.Append(err)
@@ -1019,6 +1045,7 @@ func (m *Meta) backend_C_r_s(c *configs.Backend, cHash int, sMgr *clistate.Local
 			Source:          localB,
 			Destination:     b,
 			ViewType:        vt,
+			Encryption:      enc,
 		})
 		if err != nil {
 			diags = diags.Append(err)
@@ -1057,7 +1084,7 @@ func (m *Meta) backend_C_r_s(c *configs.Backend, cHash int, sMgr *clistate.Local
 
 	if m.stateLock {
 		view := views.NewStateLocker(vt, m.View)
-		stateLocker := clistate.NewLocker(m.stateLockTimeout, view)
+		stateLocker := clistate.NewLockerWithRetryPolicy(m.stateLockTimeout, m.stateLockRetryPolicy(), view)
 		if d := stateLocker.Lock(sMgr, "backend from plan"); d != nil {
 			diags = diags.Append(fmt.Errorf("Error locking state: %s", d))
 			return nil, diags
@@ -1193,6 +1220,7 @@ func (m *Meta) backend_C_r_S_changed(c *configs.Backend, cHash int, sMgr *clista
 			Source:          oldB,
 			Destination:     b,
 			ViewType:        vt,
+			Encryption:      enc,
 		})
 		if err != nil {
 			diags = diags.Append(err)
@@ -1201,7 +1229,7 @@ func (m *Meta) backend_C_r_S_changed(c *configs.Backend, cHash int, sMgr *clista
 
 		if m.stateLock {
 			view := views.NewStateLocker(vt, m.View)
-			stateLocker := clistate.NewLocker(m.stateLockTimeout, view)
+			stateLocker := clistate.NewLockerWithRetryPolicy(m.stateLockTimeout, m.stateLockRetryPolicy(), view)
 			if d := stateLocker.Lock(sMgr, "backend from plan"); d != nil {
 				diags = diags.Append(fmt.Errorf("Error locking state: %s", d))
 				return nil, diags
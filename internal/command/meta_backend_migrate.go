@@ -33,6 +33,10 @@ type backendMigrateOpts struct {
 	Source, Destination         backend.Backend
 	ViewType                    arguments.ViewType
 
+	// Encryption is the state encryption of the configuration. It decides if the temporary copies of the states shown
+	// to the user for confirmation are encrypted.
+	Encryption encryption.StateEncryption
+
 	// Fields below are set internally when migrate is called
 
 	sourceWorkspace      string
@@ -352,7 +356,7 @@ func (m *Meta) backendMigrateState_s_s(opts *backendMigrateOpts) error {
 			vt = arguments.ViewHuman
 		}
 		view := views.NewStateLocker(vt, m.View)
-		locker := clistate.NewLocker(m.stateLockTimeout, view)
+		locker := clistate.NewLockerWithRetryPolicy(m.stateLockTimeout, m.stateLockRetryPolicy(), view)
 
 		lockerSource := locker.WithContext(lockCtx)
 		if diags := lockerSource.Lock(sourceState, "migration source state"); diags.HasErrors() {
@@ -498,7 +502,7 @@ func (m *Meta) backendMigrateNonEmptyConfirm(
 
 	// Helper to write the state
 	saveHelper := func(n, path string, s *states.State) error {
-		return statemgr.WriteAndPersist(statemgr.NewFilesystem(path, encryption.StateEncryptionDisabled()), s, nil)
+		return statemgr.WriteAndPersist(statemgr.NewFilesystem(path, encryption.PlaintextUnlessEnforced(opts.Encryption)), s, nil)
 	}
 
 	// Write the states
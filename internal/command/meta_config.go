@@ -24,7 +24,9 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/modchannel"
 	"github.com/opentofu/opentofu/internal/registry"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -86,6 +88,29 @@ func (m *Meta) loadConfigWithTests(rootDir, testDir string) (*configs.Config, tf
 	return config, diags
 }
 
+// forgetConfigFiles removes the given files, which must be absolute paths,
+// from the cache of the configuration loader, so that the next load reads
+// them again while reusing the already-parsed versions of all other files.
+func (m *Meta) forgetConfigFiles(paths []string) {
+	if m.configLoader == nil {
+		return
+	}
+	forget := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		forget[path] = true
+	}
+	parser := m.configLoader.Parser()
+	for filename := range parser.Sources() {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			continue
+		}
+		if forget[abs] {
+			parser.ForgetFile(filename)
+		}
+	}
+}
+
 // loadSingleModule reads configuration from the given directory and returns
 // a description of that module only, without attempting to assemble a module
 // tree for referenced child modules.
@@ -291,7 +316,30 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	// The hashes of remote module packages, and the artifacts selected for
+	// module packages from OCI registries, are recorded in the dependency
+	// lock file, so we'll update it if the installer records anything new.
+	locks, lockDiags := m.lockedDependencies()
+	diags = diags.Append(lockDiags)
+	if lockDiags.HasErrors() {
+		return true, diags
+	}
+	previousLocks := locks.DeepCopy()
+
 	inst := initwd.NewModuleInstaller(m.modulesDir(), loader, m.registryClient())
+	inst.SetPackageFetcherEnvironment(m.ModulePackageFetcherEnv)
+	inst.SetLocks(locks)
+	if m.ModuleCacheDir != "" {
+		inst.SetPackageCache(getmodules.NewPackageCache(m.ModuleCacheDir))
+	}
+	if m.moduleChannel != "" {
+		ch, chDiags := modchannel.Load(rootDir, m.moduleChannel)
+		diags = diags.Append(chDiags)
+		if chDiags.HasErrors() {
+			return true, diags
+		}
+		inst.SetChannel(ch)
+	}
 
 	call, vDiags := m.rootModuleCall(rootDir)
 	diags = diags.Append(vDiags)
@@ -308,6 +356,10 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	if !locks.Equal(previousLocks) {
+		diags = diags.Append(m.replaceLockedDependencies(locks))
+	}
+
 	return false, diags
 }
 
@@ -456,6 +508,7 @@ func (m *Meta) initConfigLoader() (*configload.Loader, error) {
 			return nil, err
 		}
 		loader.AllowLanguageExperiments(m.AllowExperimentalFeatures)
+		loader.StrictDeprecations(m.StrictDeprecations)
 		m.configLoader = loader
 		if m.View != nil {
 			m.View.SetConfigSources(loader.Sources)
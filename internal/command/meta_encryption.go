@@ -6,13 +6,20 @@
 package command
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/grpcplugin"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 const encryptionConfigEnvName = "TF_ENCRYPTION"
@@ -42,6 +49,34 @@ func (m *Meta) EncryptionFromModule(module *configs.Module) (encryption.Encrypti
 	cfg := module.Encryption
 	var diags tfdiags.Diagnostics
 
+	// The encryption configuration file of the selected workspace in the
+	// workspace file is merged before the environment variable, so the
+	// environment variable can still override it.
+	wsFile, ws, wsDiags := m.selectedWorkspaceDecl()
+	diags = diags.Append(wsDiags)
+	if wsDiags.HasErrors() {
+		return nil, diags
+	}
+	if ws != nil && ws.EncryptionFile != "" {
+		path := wsFile.EncryptionFilePath(ws)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read workspace encryption configuration",
+				fmt.Sprintf("Error while reading the encryption configuration of workspace %q from %s: %s.", ws.Name, path, err),
+			))
+		}
+		if len(bytes.TrimSpace(src)) != 0 {
+			wsCfg, wsCfgDiags := config.LoadConfigFromString(path, string(src))
+			diags = diags.Append(wsCfgDiags)
+			if wsCfgDiags.HasErrors() {
+				return nil, diags
+			}
+			cfg = cfg.Merge(wsCfg)
+		}
+	}
+
 	env := os.Getenv(encryptionConfigEnvName)
 	if len(env) != 0 {
 		envCfg, envDiags := config.LoadConfigFromString(encryptionConfigEnvName, env)
@@ -52,8 +87,84 @@ func (m *Meta) EncryptionFromModule(module *configs.Module) (encryption.Encrypti
 		cfg = cfg.Merge(envCfg)
 	}
 
-	enc, encDiags := encryption.New(encryption.DefaultRegistry, cfg, module.StaticEvaluator)
+	reg, regDiags := m.loadEncryptionRegistry()
+	diags = diags.Append(regDiags)
+	enc, encDiags := encryption.New(reg, cfg, module.StaticEvaluator)
 	diags = diags.Append(encDiags)
 
 	return enc, diags
 }
+
+// loadEncryptionRegistry returns the encryption registry for the command,
+// which lets key providers prompt for secrets using the command's UI and
+// includes the encryption plugins found in the plugin cache directory whose
+// checksums are declared in the CLI configuration. The registry is created
+// once per command, so the problems found with the plugins are only returned
+// by the first call.
+func (m *Meta) loadEncryptionRegistry() (registry.Registry, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if m.encryptionRegistry == nil {
+		m.encryptionRegistry = encryption.NewDefaultRegistry(&encryptionPrompter{
+			ctx:     m.CommandContext(),
+			input:   m.UIInput(),
+			enabled: m.InputMode() != 0,
+			secrets: make(map[keyprovider.Addr]string),
+		})
+		if m.PluginCacheDir != "" {
+			// Out-of-tree key providers and methods are installed into the
+			// plugin cache directory.
+			diags = diags.Append(grpcplugin.Discover(m.PluginCacheDir, m.EncryptionPluginChecksums, m.encryptionRegistry))
+		}
+	}
+	return m.encryptionRegistry, diags
+}
+
+// encryptionPrompter implements keyprovider.Prompter on top of the command
+// UI. Each secret is requested at most once per command, since encryption
+// is usually configured several times while running a single command.
+type encryptionPrompter struct {
+	ctx     context.Context
+	input   tofu.UIInput
+	enabled bool
+
+	mu      sync.Mutex
+	secrets map[keyprovider.Addr]string
+}
+
+func (p *encryptionPrompter) PromptSecret(addr keyprovider.Addr, query string, confirm bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if secret, ok := p.secrets[addr]; ok {
+		return secret, nil
+	}
+	if !p.enabled {
+		return "", fmt.Errorf("cannot prompt for the secret of %s because interactive input is disabled", addr)
+	}
+
+	secret, err := p.input.Input(p.ctx, &tofu.InputOpts{
+		Id:     string(addr),
+		Query:  query,
+		Secret: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := p.input.Input(p.ctx, &tofu.InputOpts{
+			Id:          string(addr) + ".confirm",
+			Query:       query,
+			Description: "Enter the same value again to confirm.",
+			Secret:      true,
+		})
+		if err != nil {
+			return "", err
+		}
+		if again != secret {
+			return "", fmt.Errorf("the values entered for %s do not match", addr)
+		}
+	}
+
+	p.secrets[addr] = secret
+	return secret, nil
+}
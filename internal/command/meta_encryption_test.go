@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestEncryptionPrompter(t *testing.T) {
+	input := &tofu.MockUIInput{
+		InputReturnMap: map[string]string{
+			"key_provider.pbkdf2.foo":         "Hello world! 123",
+			"key_provider.pbkdf2.foo.confirm": "Hello world! 123",
+		},
+	}
+	p := &encryptionPrompter{
+		ctx:     context.Background(),
+		input:   input,
+		enabled: true,
+		secrets: make(map[keyprovider.Addr]string),
+	}
+
+	secret, err := p.PromptSecret("key_provider.pbkdf2.foo", "Enter the passphrase", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "Hello world! 123" {
+		t.Fatalf("wrong secret %q", secret)
+	}
+
+	// The second call must be answered from the cache.
+	input.InputCalled = false
+	secret, err = p.PromptSecret("key_provider.pbkdf2.foo", "Enter the passphrase", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.InputCalled {
+		t.Fatalf("expected the cached secret to be used")
+	}
+	if secret != "Hello world! 123" {
+		t.Fatalf("wrong secret %q", secret)
+	}
+}
+
+func TestEncryptionPrompter_mismatch(t *testing.T) {
+	p := &encryptionPrompter{
+		ctx: context.Background(),
+		input: &tofu.MockUIInput{
+			InputReturnMap: map[string]string{
+				"key_provider.pbkdf2.foo":         "Hello world! 123",
+				"key_provider.pbkdf2.foo.confirm": "Hello world! 124",
+			},
+		},
+		enabled: true,
+		secrets: make(map[keyprovider.Addr]string),
+	}
+
+	if _, err := p.PromptSecret("key_provider.pbkdf2.foo", "Enter the passphrase", true); err == nil {
+		t.Fatalf("expected an error for mismatching secrets")
+	}
+}
+
+func TestEncryptionPrompter_disabled(t *testing.T) {
+	p := &encryptionPrompter{
+		ctx:     context.Background(),
+		input:   &tofu.MockUIInput{InputReturnString: "Hello world! 123"},
+		secrets: make(map[keyprovider.Addr]string),
+	}
+
+	if _, err := p.PromptSecret("key_provider.pbkdf2.foo", "Enter the passphrase", false); err == nil {
+		t.Fatalf("expected an error when input is disabled")
+	}
+}
+
+func TestEncryptionFromModule_workspaceFile(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	t.Setenv(WorkspaceNameEnvVar, "prod")
+
+	if err := os.WriteFile("prod.encryption.hcl", []byte(`
+key_provider "static_test" "prod" {
+  key                    = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+  unsafe_allow_test_keys = true
+}
+method "aes_gcm" "prod" {
+  keys = key_provider.static_test.prod
+}
+state {
+  method = method.aes_gcm.prod
+}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+workspace "prod" {
+  encryption_file = "prod.encryption.hcl"
+}
+workspace "dev" {}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := []byte(`{"version": 4, "serial": 1, "lineage": "magic", "outputs": {}, "resources": []}`)
+	for ws, wantEncrypted := range map[string]bool{"prod": true, "dev": false} {
+		t.Setenv(WorkspaceNameEnvVar, ws)
+		m := Meta{}
+		enc, diags := m.EncryptionFromModule(&configs.Module{
+			StaticEvaluator: configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()),
+		})
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		out, err := enc.State().EncryptState(context.Background(), plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypted := !bytes.Equal(out, plain); encrypted != wantEncrypted {
+			t.Errorf("wrong encryption for workspace %q: encrypted %t, want %t", ws, encrypted, wantEncrypted)
+		}
+	}
+}
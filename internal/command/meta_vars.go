@@ -42,17 +42,35 @@ func (m *Meta) collectVariableValuesWithTests(testDir string) (map[string]backen
 // but the values themselves may produce additional diagnostics when finally
 // parsed.
 func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue, tfdiags.Diagnostics) {
-	var diags tfdiags.Diagnostics
-
 	if m.inputVariableCache != nil {
 		return m.inputVariableCache, nil
 	}
 
 	ret := map[string]backend.UnparsedVariableValue{}
+	diags := m.visitVariableSources(func(_ string, values map[string]backend.UnparsedVariableValue) {
+		for name, v := range values {
+			ret[name] = v
+		}
+	})
+	m.inputVariableCache = ret
+
+	return ret, diags
+}
+
+// visitVariableSources calls the given function once for each of the places
+// that root module input variable values can come from, in increasing order
+// of precedence, with a description of the source and the values it sets.
+//
+// A value from a later call overrides any value for the same variable from
+// earlier calls. collectVariableValues merges the values in this way, while
+// "tofu vars explain" reports each of them separately.
+func (m *Meta) visitVariableSources(fn func(source string, values map[string]backend.UnparsedVariableValue)) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
 
 	// First we'll deal with environment variables, since they have the lowest
 	// precedence.
 	{
+		values := map[string]backend.UnparsedVariableValue{}
 		env := os.Environ()
 		for _, raw := range env {
 			if !strings.HasPrefix(raw, VarEnvPrefix) {
@@ -69,19 +87,33 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 			name := raw[:eq]
 			rawVal := raw[eq+1:]
 
-			ret[name] = unparsedVariableValueString{
+			values[name] = unparsedVariableValueString{
 				str:        rawVal,
 				name:       name,
 				sourceType: tofu.ValueFromEnvVar,
 			}
 		}
+		fn("environment variables", values)
 	}
 
 	// Next up we load implicit files from the specified directory (first root then tests dir
 	// as tests dir files have higher precedence). These files are automatically loaded if present.
 	// There's the original terraform.tfvars (DefaultVarsFilename) along with the later-added
 	// search for all files ending in .auto.tfvars.
-	diags = diags.Append(m.addVarsFromDir(".", ret))
+	diags = diags.Append(m.visitVarsFromDir(".", fn))
+
+	// The variable definitions files of the selected workspace in the
+	// workspace file are loaded as if they were given first on the command
+	// line, so explicit options still take precedence.
+	wsFile, ws, wsDiags := m.selectedWorkspaceDecl()
+	diags = diags.Append(wsDiags)
+	if ws != nil {
+		for _, path := range wsFile.VarFilePaths(ws) {
+			values := map[string]backend.UnparsedVariableValue{}
+			diags = diags.Append(m.addVarsFromFile(path, tofu.ValueFromNamedFile, values))
+			fn(fmt.Sprintf("workspace %q variables file %s", ws.Name, path), values)
+		}
+	}
 
 	// Finally we process values given explicitly on the command line, either
 	// as individual literal settings or as additional files to read.
@@ -111,15 +143,18 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 				))
 				continue
 			}
-			ret[name] = unparsedVariableValueString{
-				str:        rawVal,
-				name:       name,
-				sourceType: tofu.ValueFromCLIArg,
-			}
+			fn("-var option", map[string]backend.UnparsedVariableValue{
+				name: unparsedVariableValueString{
+					str:        rawVal,
+					name:       name,
+					sourceType: tofu.ValueFromCLIArg,
+				},
+			})
 
 		case "-var-file":
-			moreDiags := m.addVarsFromFile(rawFlag.Value, tofu.ValueFromNamedFile, ret)
-			diags = diags.Append(moreDiags)
+			values := map[string]backend.UnparsedVariableValue{}
+			diags = diags.Append(m.addVarsFromFile(rawFlag.Value, tofu.ValueFromNamedFile, values))
+			fn("-var-file="+rawFlag.Value, values)
 
 		default:
 			// Should never happen; always a bug in the code that built up
@@ -127,9 +162,8 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 			diags = diags.Append(fmt.Errorf("unsupported variable option name %q (this is a bug in OpenTofu)", rawFlag.Name))
 		}
 	}
-	m.inputVariableCache = ret
 
-	return ret, diags
+	return diags
 }
 
 func (m *Meta) updateInputVariableCache(key string, value backend.UnparsedVariableValue) {
@@ -137,16 +171,26 @@ func (m *Meta) updateInputVariableCache(key string, value backend.UnparsedVariab
 }
 
 func (m *Meta) addVarsFromDir(currDir string, ret map[string]backend.UnparsedVariableValue) tfdiags.Diagnostics {
+	return m.visitVarsFromDir(currDir, func(_ string, values map[string]backend.UnparsedVariableValue) {
+		for name, v := range values {
+			ret[name] = v
+		}
+	})
+}
+
+// visitVarsFromDir calls the given function for each of the variable
+// definitions files that are loaded automatically from the given directory,
+// in increasing order of precedence, as for visitVariableSources.
+func (m *Meta) visitVarsFromDir(currDir string, fn func(source string, values map[string]backend.UnparsedVariableValue)) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
+	var filenames []string
 	if _, err := os.Stat(filepath.Join(currDir, DefaultVarsFilename)); err == nil {
-		moreDiags := m.addVarsFromFile(filepath.Join(currDir, DefaultVarsFilename), tofu.ValueFromAutoFile, ret)
-		diags = diags.Append(moreDiags)
+		filenames = append(filenames, filepath.Join(currDir, DefaultVarsFilename))
 	}
 	const defaultVarsFilenameJSON = DefaultVarsFilename + ".json"
 	if _, err := os.Stat(filepath.Join(currDir, defaultVarsFilenameJSON)); err == nil {
-		moreDiags := m.addVarsFromFile(filepath.Join(currDir, defaultVarsFilenameJSON), tofu.ValueFromAutoFile, ret)
-		diags = diags.Append(moreDiags)
+		filenames = append(filenames, filepath.Join(currDir, defaultVarsFilenameJSON))
 	}
 	if infos, err := os.ReadDir(currDir); err == nil {
 		// "infos" is already sorted by name, so we just need to filter it here.
@@ -155,11 +199,16 @@ func (m *Meta) addVarsFromDir(currDir string, ret map[string]backend.UnparsedVar
 			if !isAutoVarFile(name) {
 				continue
 			}
-			moreDiags := m.addVarsFromFile(filepath.Join(currDir, name), tofu.ValueFromAutoFile, ret)
-			diags = diags.Append(moreDiags)
+			filenames = append(filenames, filepath.Join(currDir, name))
 		}
 	}
 
+	for _, filename := range filenames {
+		values := map[string]backend.UnparsedVariableValue{}
+		diags = diags.Append(m.addVarsFromFile(filename, tofu.ValueFromAutoFile, values))
+		fn(filename, values)
+	}
+
 	return diags
 }
 
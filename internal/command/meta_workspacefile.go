@@ -0,0 +1,28 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"github.com/opentofu/opentofu/internal/command/workspacefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// selectedWorkspaceDecl returns the workspace file of the working directory
+// and the declaration of the currently-selected workspace in it. Both are nil
+// if there is no workspace file, and the declaration is nil if the file does
+// not declare the selected workspace.
+func (m *Meta) selectedWorkspaceDecl() (*workspacefile.File, *workspacefile.Workspace, tfdiags.Diagnostics) {
+	file, diags := workspacefile.Load(".")
+	if file == nil {
+		return nil, nil, diags
+	}
+	name, err := m.Workspace()
+	if err != nil {
+		// The invalid workspace name is reported elsewhere.
+		return file, nil, diags
+	}
+	return file, file.Workspace(name), diags
+}
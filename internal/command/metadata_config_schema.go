@@ -0,0 +1,103 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/jsonconfigschema"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// MetadataConfigSchemaCommand is a Command implementation that prints out a
+// machine-readable description of the declarations in the configuration.
+type MetadataConfigSchemaCommand struct {
+	Meta
+}
+
+func (c *MetadataConfigSchemaCommand) Help() string {
+	return metadataConfigSchemaCommandHelp
+}
+
+func (c *MetadataConfigSchemaCommand) Synopsis() string {
+	return "Show the variables, outputs, resources and module calls of the configuration"
+}
+
+func (c *MetadataConfigSchemaCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("metadata config-schema")
+	c.Meta.varFlagSet(cmdFlags)
+	var jsonOutput bool
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	if !jsonOutput {
+		c.Ui.Error(
+			"The `tofu metadata config-schema` command requires the `-json` flag.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The `tofu metadata config-schema` command expects no positional arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+	config, configDiags := c.loadConfig(".")
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	jsonSchema, err := jsonconfigschema.Marshal(config)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize configuration",
+			err.Error(),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Warnings are written to stderr so that they don't interfere with
+	// the JSON document on stdout.
+	c.showDiagnostics(diags)
+	c.Ui.Output(string(jsonSchema))
+
+	return 0
+}
+
+const metadataConfigSchemaCommandHelp = `
+Usage: tofu [global options] metadata config-schema -json [options]
+
+  Prints out a JSON representation of the declarations in the configuration
+  in the current directory: its input variables, output values, resources,
+  provider requirements and module calls, along with their source locations.
+
+  The called modules are included if they have been installed with
+  "tofu init".
+
+Options:
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable. Only needed when
+                      the variables are used in module sources or other
+                      settings that OpenTofu evaluates early.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+`
@@ -0,0 +1,93 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestMetadataConfigSchema_error(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &MetadataConfigSchemaCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	// This test will always error because it's missing the -json flag
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("expected error, got:\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestMetadataConfigSchema_output(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("metadata-config-schema"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &MetadataConfigSchemaCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	var got struct {
+		FormatVersion string `json:"format_version"`
+		RootModule    struct {
+			Variables map[string]struct {
+				Type     string `json:"type"`
+				Required bool   `json:"required"`
+			} `json:"variables"`
+			Outputs   map[string]json.RawMessage `json:"outputs"`
+			Resources []struct {
+				Address string `json:"address"`
+				Range   struct {
+					Filename string `json:"filename"`
+					Start    struct {
+						Line int `json:"line"`
+					} `json:"start"`
+				} `json:"range"`
+			} `json:"resources"`
+		} `json:"root_module"`
+	}
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	if got.FormatVersion != "1.0" {
+		t.Errorf("wrong format version %q", got.FormatVersion)
+	}
+	if v := got.RootModule.Variables["name"]; v.Type != "string" || !v.Required {
+		t.Errorf("wrong variable %#v", v)
+	}
+	if _, ok := got.RootModule.Outputs["id"]; !ok {
+		t.Errorf("missing output")
+	}
+	if len(got.RootModule.Resources) != 1 {
+		t.Fatalf("wrong resources %#v", got.RootModule.Resources)
+	}
+	if r := got.RootModule.Resources[0]; r.Address != "test_instance.main" || r.Range.Filename != "main.tf" || r.Range.Start.Line != 5 {
+		t.Errorf("wrong resource %#v", r)
+	}
+
+	if stderr := ui.ErrorWriter.String(); stderr != "" {
+		t.Fatalf("expected empty stderr, got:\n%s", stderr)
+	}
+	if _, err := os.Stat(".terraform"); err == nil {
+		t.Errorf("the command must not create a .terraform directory")
+	}
+}
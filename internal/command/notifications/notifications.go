@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package notifications sends messages about the progress of plan and apply
+// operations to external services, such as chat webhooks, as configured in the
+// CLI configuration.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Event identifies the point of an operation that a message is sent for.
+type Event string
+
+const (
+	// EventPlanComplete is sent when "tofu plan" has created a plan.
+	EventPlanComplete Event = "plan_complete"
+
+	// EventApplyStart is sent when an apply operation starts to apply the
+	// first change.
+	EventApplyStart Event = "apply_start"
+
+	// EventApplySuccess is sent when an apply operation completed
+	// successfully.
+	EventApplySuccess Event = "apply_success"
+
+	// EventApplyFailure is sent when an apply operation failed.
+	EventApplyFailure Event = "apply_failure"
+
+	// EventSensitiveReveal is sent when "tofu show -reveal" displays a
+	// sensitive value.
+	EventSensitiveReveal Event = "sensitive_reveal"
+)
+
+// Events are all the supported events. The events of plan and apply
+// operations are in the order they happen.
+var Events = []Event{EventPlanComplete, EventApplyStart, EventApplySuccess, EventApplyFailure, EventSensitiveReveal}
+
+// ParseEvent returns the event with the given name.
+func ParseEvent(s string) (Event, error) {
+	if e := Event(s); slices.Contains(Events, e) {
+		return e, nil
+	}
+	names := make([]string, len(Events))
+	for i, e := range Events {
+		names[i] = fmt.Sprintf("%q", e)
+	}
+	return "", fmt.Errorf("unsupported event %q; must be one of %s", s, strings.Join(names, ", "))
+}
+
+// Summary counts the resource instances changed by an operation. For the
+// plan_complete and apply_start events, it counts the planned changes.
+type Summary struct {
+	Add    int `json:"add"`
+	Change int `json:"change"`
+	Remove int `json:"remove"`
+	Import int `json:"import"`
+	Forget int `json:"forget"`
+}
+
+// Message is the data available to message templates, and is also sent as
+// part of the JSON payload of a webhook.
+type Message struct {
+	Event     Event     `json:"event"`
+	Workspace string    `json:"workspace"`
+	Summary   Summary   `json:"summary"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Address is the address of the value revealed by a sensitive_reveal
+	// event.
+	Address string `json:"address,omitempty"`
+}
+
+// defaultTemplates are the templates used for webhooks that don't configure
+// a template of their own.
+var defaultTemplates = map[Event]string{
+	EventPlanComplete:    `OpenTofu plan for workspace "{{.Workspace}}": {{.Summary.Add}} to add, {{.Summary.Change}} to change, {{.Summary.Remove}} to destroy.`,
+	EventApplyStart:      `OpenTofu apply started for workspace "{{.Workspace}}": {{.Summary.Add}} to add, {{.Summary.Change}} to change, {{.Summary.Remove}} to destroy.`,
+	EventApplySuccess:    `OpenTofu apply succeeded for workspace "{{.Workspace}}": {{.Summary.Add}} added, {{.Summary.Change}} changed, {{.Summary.Remove}} destroyed.`,
+	EventApplyFailure:    `OpenTofu apply failed for workspace "{{.Workspace}}": {{.Summary.Add}} added, {{.Summary.Change}} changed, {{.Summary.Remove}} destroyed.{{if .Error}} {{.Error}}{{end}}`,
+	EventSensitiveReveal: `OpenTofu revealed the sensitive value {{.Address}} in workspace "{{.Workspace}}".`,
+}
+
+// Webhook sends messages as a JSON object in an HTTP POST request to a URL.
+// The "text" property of the object contains the rendered template, which
+// is understood by the incoming webhooks of common chat services, and the
+// remaining properties contain the fields of the Message.
+type Webhook struct {
+	Name    string
+	URL     string
+	Headers map[string]string
+
+	// Events are the events to send messages for. All events are sent if
+	// it is empty.
+	Events []Event
+
+	// Template overrides the default text of the messages. It is a Go
+	// text/template that is executed with a Message.
+	Template *template.Template
+}
+
+// NewWebhook validates the given arguments of a webhook and returns it.
+func NewWebhook(name, rawURL string, events []string, tmpl string, headers map[string]string) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url %q: must be an http or https URL", rawURL)
+	}
+
+	ret := &Webhook{
+		Name:    name,
+		URL:     rawURL,
+		Headers: headers,
+	}
+	for _, s := range events {
+		e, err := ParseEvent(s)
+		if err != nil {
+			return nil, err
+		}
+		ret.Events = append(ret.Events, e)
+	}
+	if tmpl != "" {
+		ret.Template, err = template.New(name).Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return ret, nil
+}
+
+// Wants returns true if the webhook sends messages for the given event.
+func (w *Webhook) Wants(e Event) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, e)
+}
+
+// Text renders the text of the message.
+func (w *Webhook) Text(msg Message) (string, error) {
+	tmpl := w.Template
+	if tmpl == nil {
+		tmpl = template.Must(template.New(string(msg.Event)).Parse(defaultTemplates[msg.Event]))
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Send delivers the message using the given client.
+func (w *Webhook) Send(ctx context.Context, client *http.Client, msg Message) error {
+	text, err := w.Text(msg)
+	if err != nil {
+		return fmt.Errorf("failed to render the message for webhook %q: %w", w.Name, err)
+	}
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+		Message
+	}{
+		Text:    text,
+		Message: msg,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send the message for webhook %q: %w", w.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send the message for webhook %q: server responded with %s", w.Name, resp.Status)
+	}
+	return nil
+}
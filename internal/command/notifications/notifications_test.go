@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var got map[string]interface{}
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %s", err)
+		}
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook("test", server.URL, nil, "", map[string]string{"Authorization": "Bearer secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := Message{
+		Event:     EventApplyFailure,
+		Workspace: "prod",
+		Summary:   Summary{Add: 1, Remove: 2},
+		Error:     "boom",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := webhook.Send(context.Background(), server.Client(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAuth != "Bearer secret" {
+		t.Errorf("wrong Authorization header %q", gotAuth)
+	}
+	want := map[string]interface{}{
+		"text":      `OpenTofu apply failed for workspace "prod": 1 added, 0 changed, 2 destroyed. boom`,
+		"event":     "apply_failure",
+		"workspace": "prod",
+		"summary": map[string]interface{}{
+			"add": 1.0, "change": 0.0, "remove": 2.0, "import": 0.0, "forget": 0.0,
+		},
+		"error":     "boom",
+		"timestamp": "2024-01-02T03:04:05Z",
+	}
+	for k, v := range want {
+		if gotJSON, wantJSON := mustJSON(t, got[k]), mustJSON(t, v); gotJSON != wantJSON {
+			t.Errorf("wrong %s: got %s, want %s", k, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestWebhookSend_errorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook("test", server.URL, nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = webhook.Send(context.Background(), server.Client(), Message{Event: EventPlanComplete})
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden") {
+		t.Fatalf("expected a 403 error, got %v", err)
+	}
+}
+
+func TestNewWebhook(t *testing.T) {
+	tests := map[string]struct {
+		url     string
+		events  []string
+		tmpl    string
+		wantErr string
+	}{
+		"valid": {
+			url:    "https://example.com/hook",
+			events: []string{"plan_complete", "apply_start"},
+			tmpl:   "{{.Workspace}}",
+		},
+		"not http": {
+			url:     "ftp://example.com",
+			wantErr: `invalid url "ftp://example.com": must be an http or https URL`,
+		},
+		"unknown event": {
+			url:     "https://example.com/hook",
+			events:  []string{"destroy"},
+			wantErr: `unsupported event "destroy"`,
+		},
+		"invalid template": {
+			url:     "https://example.com/hook",
+			tmpl:    "{{.Workspace",
+			wantErr: "invalid template",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			webhook, err := NewWebhook(name, test.url, test.events, test.tmpl, nil)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("expected error %q, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !webhook.Wants(EventPlanComplete) || webhook.Wants(EventApplySuccess) {
+				t.Errorf("wrong events: %v", webhook.Events)
+			}
+		})
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	ret, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(ret)
+}
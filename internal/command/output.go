@@ -78,11 +78,13 @@ func (c *OutputCommand) Outputs(statePath string, enc encryption.Encryption) (ma
 		c.Meta.statePath = statePath
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(nil, enc.State())
-	diags = diags.Append(backendDiags)
-	if diags.HasErrors() {
-		return nil, diags
+	// Load the backend. Reading the outputs doesn't need the providers, so
+	// it must still work when they can't be installed. The warnings about
+	// them are not shown, since they would only get in the way of scripts
+	// reading the outputs.
+	b, backendDiags := c.Backend(&BackendOpts{ReadOnly: true}, enc.State())
+	if backendDiags.HasErrors() {
+		return nil, diags.Append(backendDiags)
 	}
 
 	// This is a read-only command
@@ -106,7 +108,7 @@ func (c *OutputCommand) Outputs(statePath string, enc encryption.Encryption) (ma
 		return nil, diags.Append(err)
 	}
 
-	return output, diags
+	return states.PublicOutputValues(output), diags
 }
 
 func (c *OutputCommand) GatherVariables(args *arguments.Vars) {
@@ -52,6 +52,45 @@ func TestOutput(t *testing.T) {
 	}
 }
 
+// TestOutput_missingProvider checks that the outputs can be read when a
+// provider in the dependency lock file isn't installed.
+func TestOutput_missingProvider(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testMissingProviderLockFile(t)
+
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-raw",
+		"foo",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.All())
+	}
+
+	if got := output.Stdout(); got != "bar" {
+		t.Fatalf("bad: %#v", got)
+	}
+}
+
 func TestOutput_json(t *testing.T) {
 	originalState := states.BuildState(func(s *states.SyncState) {
 		s.SetOutputValue(
@@ -88,6 +127,56 @@ func TestOutput_json(t *testing.T) {
 	}
 }
 
+func TestOutput_internal(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+		s.SetInternalOutputValue(
+			addrs.OutputValue{Name: "plumbing"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("baz"),
+			false,
+		)
+	})
+
+	statePath := testStateFile(t, originalState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-json",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	actual := strings.TrimSpace(output.Stdout())
+	expected := "{\n  \"foo\": {\n    \"sensitive\": false,\n    \"type\": \"string\",\n    \"value\": \"bar\"\n  }\n}"
+	if actual != expected {
+		t.Fatalf("wrong output\ngot:  %#v\nwant: %#v", actual, expected)
+	}
+
+	// Internal outputs can't be requested by name either.
+	view, done = testView(t)
+	c.View = view
+	code = c.Run([]string{"-state", statePath, "plumbing"})
+	output = done(t)
+	if code != 1 {
+		t.Fatalf("unexpected success for internal output: %s", output.Stdout())
+	}
+}
+
 func TestOutput_emptyOutputs(t *testing.T) {
 	originalState := states.NewState()
 	statePath := testStateFile(t, originalState)
@@ -24,6 +24,7 @@ type PlanCommand struct {
 
 func (c *PlanCommand) Run(rawArgs []string) int {
 	ctx := c.CommandContext()
+	historyArgs := rawArgs
 
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
@@ -70,6 +71,8 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.schedule = args.Operation.Schedule
+	c.Meta.runTimeout = args.Operation.RunTimeout
 
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
 
@@ -106,8 +109,13 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	view.Diagnostics(diags)
 	diags = nil
 
+	// Record the operation in the history of the workspace
+	hist := c.startHistory(be, "plan", historyArgs)
+	opReq.Hooks = append(opReq.Hooks, hist)
+
 	// Perform the operation
 	op, diags := c.RunOperation(ctx, be, opReq)
+	hist.Finish(op, diags)
 	view.Diagnostics(diags)
 	if diags.HasErrors() {
 		return 1
@@ -116,6 +124,9 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
+	if hook := c.notificationHook(); hook != nil {
+		hook.PlanComplete(ctx)
+	}
 	if args.DetailedExitCode && !op.PlanEmpty {
 		return 2
 	}
@@ -164,12 +175,19 @@ func (c *PlanCommand) OperationRequest(
 	opReq.ConfigDir = "."
 	opReq.PlanMode = args.PlanMode
 	opReq.Hooks = view.Hooks()
+	if hook := c.notificationHook(); hook != nil {
+		opReq.Hooks = append(opReq.Hooks, hook)
+	}
 	opReq.PlanRefresh = args.Refresh
 	opReq.PlanOutPath = planOutPath
 	opReq.GenerateConfigOut = generateConfigOut
 	opReq.Targets = args.Targets
 	opReq.Excludes = args.Excludes
 	opReq.ForceReplace = args.ForceReplace
+	opReq.FrozenModules = args.FrozenModules
+	opReq.UnfrozenModules = args.UnfrozenModules
+	opReq.Reencrypt = args.Reencrypt
+	opReq.ExplainUnknown = args.ExplainUnknown
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
 
@@ -250,6 +268,15 @@ Plan Customization Options:
                       This is for exceptional use only. Cannot be used alongside
                       the -target flag
 
+  -frozen-modules     Fail the plan if the source code of a module has changed
+                      since it was last applied. OpenTofu records the source
+                      code of each module instance in the state when applying.
+
+  -unfreeze=module    Allow the source code of the given module, and the
+                      modules nested in it, to change when -frozen-modules is
+                      set. You can use this option multiple times to unfreeze
+                      more than one module.
+
   -var 'foo=bar'      Set a value for one of the input variables in the root
                       module of the configuration. Use this option more than
                       once to set more than one variable.
@@ -279,6 +306,11 @@ Other Options:
                              1 - Errored
                              2 - Succeeded, there is a diff
 
+  -explain-unknown           Explain why values in the plan are known only
+                             after apply, and why a count or for_each argument
+                             is unknown, by tracing them back through the
+                             expressions and resources they depend on.
+
   -generate-config-out=path  (Experimental) If import blocks are present in
                              configuration, instructs OpenTofu to generate HCL
                              for any imported resources not already present. The
@@ -294,6 +326,21 @@ Other Options:
 
   -lock-timeout=0s           Duration to retry a state lock.
 
+  -lock-retry-delay=1s       Delay before the first retry of a state lock held
+                             by another process. Overrides the CLI
+                             configuration.
+
+  -lock-retry-max-delay=16s  Maximum delay between retries of a state lock.
+
+  -lock-retry-multiplier=2   Factor to increase the delay by after each retry.
+
+  -lock-retry-jitter=0       Fraction between 0 and 1 to randomly vary each
+                             delay by.
+
+  -lock-retry-max-attempts=0 Maximum number of attempts to acquire a state
+                             lock. Defaults to no limit; -lock-timeout limits
+                             the total time.
+
   -no-color                  If specified, output won't contain any color.
 
   -concise                   Disables progress-related messages in the output.
@@ -304,6 +351,21 @@ Other Options:
   -parallelism=n             Limit the number of concurrent operations. Defaults
                              to 10.
 
+  -schedule=policy           Order in which operations that are ready to run
+                             are started when all parallelism slots are busy:
+                             "fifo" (the default), "fair" to share the slots
+                             between modules, or "critical-path" to start the
+                             longest dependency chains first.
+
+  -run-timeout=duration      Stop gracefully, as if interrupted, when the
+                             plan takes longer than the given duration, such
+                             as "90m".
+
+  -reencrypt                 Write the state back encrypted with the primary
+                             encryption configuration, even if it has not
+                             changed. Use this after changing the state
+                             encryption configuration.
+
   -state=statefile           A legacy option used for the local backend only.
                              See the local backend's documentation for more
                              information.
@@ -0,0 +1,413 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configwrite"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// providersConstrainNewFilename is the file that "tofu providers constrain"
+// writes a required_providers block to when the module doesn't have one.
+const providersConstrainNewFilename = "versions.tf"
+
+// providerConstraintStrategies are the supported values of the -strategy
+// option of "tofu providers constrain".
+var providerConstraintStrategies = map[string]bool{
+	"patch":   true,
+	"minor":   true,
+	"exact":   true,
+	"minimum": true,
+}
+
+// ProvidersConstrainCommand is a Command implementation that implements the
+// "tofu providers constrain" command, which writes version constraints for
+// the providers of the root module based on the versions recorded in the
+// dependency lock file.
+type ProvidersConstrainCommand struct {
+	Meta
+}
+
+// providerConstraintChange is a change to the entry of a provider in the
+// required_providers block of a module.
+type providerConstraintChange struct {
+	// Req is the entry with its new version constraint.
+	Req *configs.RequiredProvider
+
+	// Old is the previous version constraint, which is empty if the entry
+	// is new or had no version constraint.
+	Old string
+
+	// Add is true if the module uses the provider without declaring it in
+	// its required_providers block, so that the entry must be added.
+	Add bool
+}
+
+func (c *ProvidersConstrainCommand) Synopsis() string {
+	return "Write version constraints for providers from the lock file"
+}
+
+func (c *ProvidersConstrainCommand) Run(args []string) int {
+	var strategy string
+	var dryRun bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("providers constrain")
+	cmdFlags.StringVar(&strategy, "strategy", "patch", "constraint strategy")
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "dry-run")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The providers constrain command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	if !providerConstraintStrategies[strategy] {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid constraint strategy",
+			fmt.Sprintf("The strategy %q is not supported. Use patch, minor, exact or minimum.", strategy),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	dir := c.normalizePath(".")
+	mod, moreDiags := c.loadSingleModule(dir, configs.SelectiveLoadAll)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	locks, moreDiags := c.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	changes, moreDiags := providerConstraintChanges(mod, locks, strategy)
+	diags = diags.Append(moreDiags)
+	if len(changes) == 0 {
+		c.showDiagnostics(diags)
+		if diags.HasErrors() {
+			return 1
+		}
+		c.Ui.Output("The version constraints of the providers already match the dependency lock file.")
+		return 0
+	}
+
+	changed, moreDiags := constrainProvidersInDir(dir, mod, changes)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	for _, f := range changed {
+		if dryRun {
+			diff, err := bytesDiff(f.Old, f.New, f.DiffLabel)
+			if err != nil {
+				diags = diags.Append(fmt.Errorf("Failed to generate diff for %s: %w", f.Path, err))
+				continue
+			}
+			c.Ui.Output(strings.TrimRight(string(diff), "\n"))
+			continue
+		}
+		if err := os.WriteFile(f.Path, f.New, f.Mode); err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to write %s: %w", f.Path, err))
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	if !dryRun {
+		for _, change := range changes {
+			old := change.Old
+			if old == "" {
+				old = "(none)"
+			}
+			c.Ui.Output(fmt.Sprintf("- %s: %s -> %s", change.Req.Type.ForDisplay(), old, change.Req.Requirement.Required))
+		}
+		c.Ui.Output(fmt.Sprintf("Updated the version constraints of %d provider(s).", len(changes)))
+	}
+	return 0
+}
+
+// providerConstraintForVersion returns the version constraint that the given
+// strategy produces for a locked provider version. The strategy must be one
+// of providerConstraintStrategies.
+//
+// A range of versions never includes prereleases, so a prerelease version is
+// always constrained exactly.
+func providerConstraintForVersion(v getproviders.Version, strategy string) string {
+	if v.Prerelease != "" {
+		strategy = "exact"
+	}
+	switch strategy {
+	case "patch":
+		return fmt.Sprintf("~> %d.%d.%d", v.Major, v.Minor, v.Patch)
+	case "minor":
+		return fmt.Sprintf("~> %d.%d", v.Major, v.Minor)
+	case "minimum":
+		return fmt.Sprintf(">= %d.%d.%d", v.Major, v.Minor, v.Patch)
+	default:
+		return "= " + v.String()
+	}
+}
+
+// providerConstraintChanges returns the changes to the required_providers
+// block of the given module that make the version constraints of its
+// providers match the given locks, in order of local name.
+//
+// Providers that the module uses without declaring them get a new entry.
+// Providers that aren't locked are left alone, with a warning.
+func providerConstraintChanges(mod *configs.Module, locks *depsfile.Locks, strategy string) ([]providerConstraintChange, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	reqs := make(map[string]*configs.RequiredProvider)
+	for name, req := range mod.ProviderRequirements.RequiredProviders {
+		reqs[name] = req
+	}
+	implied := make(map[string]bool)
+	addImplied := func(provider addrs.Provider) {
+		if provider.IsBuiltIn() {
+			return
+		}
+		name := mod.LocalNameForProvider(provider)
+		if _, exists := reqs[name]; exists {
+			return
+		}
+		reqs[name] = &configs.RequiredProvider{
+			Name:   name,
+			Source: provider.ForDisplay(),
+			Type:   provider,
+		}
+		implied[name] = true
+	}
+	for _, pc := range mod.ProviderConfigs {
+		addImplied(mod.ProviderForLocalConfig(pc.Addr()))
+	}
+	for _, resources := range []map[string]*configs.Resource{mod.ManagedResources, mod.DataResources, mod.EphemeralResources} {
+		for _, r := range resources {
+			addImplied(r.Provider)
+		}
+	}
+
+	names := make([]string, 0, len(reqs))
+	for name := range reqs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []providerConstraintChange
+	var unlocked []string
+	for _, name := range names {
+		req := reqs[name]
+		lock := locks.Provider(req.Type)
+		if lock == nil {
+			unlocked = append(unlocked, req.Type.ForDisplay())
+			continue
+		}
+
+		str := providerConstraintForVersion(lock.Version(), strategy)
+		constraints, err := version.NewConstraint(str)
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("Invalid version constraint %q for %s: %w", str, req.Type.ForDisplay(), err))
+			continue
+		}
+
+		var old string
+		if len(req.Requirement.Required) != 0 {
+			old = req.Requirement.Required.String()
+		}
+		if old == constraints.String() {
+			continue
+		}
+
+		updated := *req
+		updated.Requirement.Required = constraints
+		changes = append(changes, providerConstraintChange{
+			Req: &updated,
+			Old: old,
+			Add: implied[name],
+		})
+	}
+
+	if len(unlocked) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Providers not locked",
+			fmt.Sprintf("The dependency lock file has no version for %s, so their version constraints were not changed. Run \"tofu init\" to lock them.", strings.Join(unlocked, ", ")),
+		))
+	}
+	return changes, diags
+}
+
+// constrainProvidersInDir applies the given changes to the required_providers
+// blocks in the native syntax configuration files of dir.
+//
+// Updated entries are rewritten wherever they are declared, including in
+// override files. New entries are added to the module's required_providers
+// block, or to a new one in versions.tf if the module doesn't have one.
+func constrainProvidersInDir(dir string, mod *configs.Module, changes []providerConstraintChange) ([]renamedFile, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var changed []renamedFile
+
+	var addFile string
+	if rng := mod.ProviderRequirements.DeclRange; rng.Filename != "" {
+		addFile = filepath.Base(rng.Filename)
+	}
+	var adds []providerConstraintChange
+	for _, change := range changes {
+		if change.Add {
+			adds = append(adds, change)
+		}
+	}
+
+	applied := make(map[string]bool)
+	added := false
+	names := configFileNames(dir)
+	for _, name := range names {
+		files, moreDiags := rewriteConfigFiles(dir, []string{name}, func(body *hclwrite.Body) {
+			for _, block := range body.Blocks() {
+				if block.Type() != "terraform" {
+					continue
+				}
+				for _, nested := range block.Body().Blocks() {
+					if nested.Type() != "required_providers" {
+						continue
+					}
+					for _, change := range changes {
+						if change.Add || nested.Body().GetAttribute(change.Req.Name) == nil {
+							continue
+						}
+						nested.Body().SetAttributeRaw(change.Req.Name, configwrite.RequiredProviderTokens(change.Req))
+						applied[change.Req.Name] = true
+					}
+					if name == addFile && !added {
+						for _, change := range adds {
+							nested.Body().SetAttributeRaw(change.Req.Name, configwrite.RequiredProviderTokens(change.Req))
+							applied[change.Req.Name] = true
+						}
+						added = true
+					}
+				}
+			}
+			if addFile == "" && name == providersConstrainNewFilename {
+				appendRequiredProvidersBlock(body, adds)
+				for _, change := range adds {
+					applied[change.Req.Name] = true
+				}
+			}
+		})
+		diags = diags.Append(moreDiags)
+		changed = append(changed, files...)
+	}
+
+	if addFile == "" && len(adds) != 0 && !fileNamesContain(names, providersConstrainNewFilename) {
+		f := hclwrite.NewEmptyFile()
+		appendRequiredProvidersBlock(f.Body(), adds)
+		changed = append(changed, renamedFile{
+			Path:      filepath.Join(dir, providersConstrainNewFilename),
+			Mode:      0644,
+			New:       f.Bytes(),
+			DiffLabel: providersConstrainNewFilename,
+		})
+		for _, change := range adds {
+			applied[change.Req.Name] = true
+		}
+	}
+
+	for _, change := range changes {
+		if !applied[change.Req.Name] {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot update provider requirement",
+				fmt.Sprintf("The required_providers entry for %s is not declared in a native syntax configuration file, so it cannot be updated automatically.", change.Req.Type.ForDisplay()),
+			))
+		}
+	}
+	return changed, diags
+}
+
+// appendRequiredProvidersBlock appends a terraform block with a
+// required_providers block containing the given entries to body.
+func appendRequiredProvidersBlock(body *hclwrite.Body, changes []providerConstraintChange) {
+	if len(body.Blocks()) != 0 || len(body.Attributes()) != 0 {
+		body.AppendNewline()
+	}
+	block := body.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil)
+	for _, change := range changes {
+		block.Body().SetAttributeRaw(change.Req.Name, configwrite.RequiredProviderTokens(change.Req))
+	}
+}
+
+func fileNamesContain(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ProvidersConstrainCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] providers constrain [options]
+
+  Writes version constraints for the providers of the module in the current
+  directory to its required_providers block, based on the provider versions
+  selected in the dependency lock file. This keeps the constraints of a
+  module in line with the versions it has been tested with, which is useful
+  before publishing it.
+
+  Providers that the module uses without declaring them in its
+  required_providers block are added to it, or to a new block in
+  versions.tf if the module doesn't have one. Providers that aren't in the
+  dependency lock file are left alone. Files written in the JSON
+  configuration syntax are not updated.
+
+Options:
+
+  -strategy=NAME    How to constrain each provider to its locked version,
+                    which is 1.2.3 in these examples:
+
+                      patch    allows newer patch releases (~> 1.2.3).
+                               This is the default.
+                      minor    allows newer minor releases (~> 1.2).
+                      exact    allows only the locked version (= 1.2.3).
+                      minimum  allows the locked version and any later
+                               one (>= 1.2.3).
+
+                    Prerelease versions are always constrained exactly.
+
+  -dry-run          Show the changes as a diff instead of writing them.
+`
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,200 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func TestProvidersConstrain(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("providers-constrain/basic"), td)
+		defer testChdir(t, td)()
+
+		ui := cli.NewMockUi()
+		c := &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run(nil); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+
+		want := `terraform {
+  required_providers {
+    # The test provider is used for everything.
+    test = {
+      source  = "hashicorp/test"
+      version = "~> 1.2.3"
+    }
+    null = {
+      source  = "hashicorp/null"
+      version = "~> 3.2.0"
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = "~> 3.6.1"
+    }
+  }
+}
+
+resource "test_instance" "foo" {
+}
+
+resource "random_id" "foo" {
+}
+
+resource "terraform_data" "foo" {
+}
+`
+		got, err := os.ReadFile("main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Fatalf("wrong configuration\n%s", diff)
+		}
+
+		output := ui.OutputWriter.String()
+		for _, line := range []string{
+			"- hashicorp/random: (none) -> ~> 3.6.1",
+			"- hashicorp/test: >= 1.0.0 -> ~> 1.2.3",
+			"Updated the version constraints of 3 provider(s).",
+		} {
+			if !strings.Contains(output, line) {
+				t.Errorf("output is missing %q\n%s", line, output)
+			}
+		}
+
+		// Running the command again changes nothing.
+		ui = cli.NewMockUi()
+		c = &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run(nil); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		if got, want := ui.OutputWriter.String(), "already match"; !strings.Contains(got, want) {
+			t.Fatalf("output is missing %q\n%s", want, got)
+		}
+	})
+
+	t.Run("strategy", func(t *testing.T) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("providers-constrain/basic"), td)
+		defer testChdir(t, td)()
+
+		ui := cli.NewMockUi()
+		c := &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run([]string{"-strategy=minor"}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		got, err := os.ReadFile("main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `version = "~> 1.2"`; !strings.Contains(string(got), want) {
+			t.Fatalf("configuration is missing %q\n%s", want, got)
+		}
+	})
+
+	t.Run("implied", func(t *testing.T) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("providers-constrain/implied"), td)
+		defer testChdir(t, td)()
+
+		ui := cli.NewMockUi()
+		c := &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run(nil); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+
+		// A prerelease is constrained exactly, whatever the strategy.
+		want := `terraform {
+  required_providers {
+    test = {
+      source  = "hashicorp/test"
+      version = "= 2.0.0-beta1"
+    }
+  }
+}
+`
+		got, err := os.ReadFile("versions.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Fatalf("wrong configuration\n%s", diff)
+		}
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("providers-constrain/basic"), td)
+		defer testChdir(t, td)()
+
+		before, err := os.ReadFile("main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ui := cli.NewMockUi()
+		c := &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run([]string{"-dry-run"}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+
+		after, err := os.ReadFile("main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(before) != string(after) {
+			t.Fatal("configuration was changed in dry-run mode")
+		}
+		if got, want := ui.OutputWriter.String(), `+      version = "~> 1.2.3"`; !strings.Contains(got, want) {
+			t.Fatalf("output is missing %q\n%s", want, got)
+		}
+	})
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		td := t.TempDir()
+		defer testChdir(t, td)()
+
+		ui := cli.NewMockUi()
+		c := &ProvidersConstrainCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+		if code := c.Run([]string{"-strategy=latest"}); code != 1 {
+			t.Fatalf("wrong exit code %d; want 1", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "Invalid constraint strategy"; !strings.Contains(got, want) {
+			t.Fatalf("error is missing %q\n%s", want, got)
+		}
+	})
+}
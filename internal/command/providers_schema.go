@@ -8,11 +8,17 @@ package command
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 // ProvidersSchemaCommand is a Command implementation that prints out information
@@ -36,7 +42,12 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("providers schema")
 	c.Meta.varFlagSet(cmdFlags)
 	var jsonOutput bool
+	var providerArgs, resourceTypes FlagStringSlice
+	var outputDir string
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.Var(&providerArgs, "provider", "provider")
+	cmdFlags.Var(&resourceTypes, "resource-type", "resource type")
+	cmdFlags.StringVar(&outputDir, "output-dir", "", "output directory")
 
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
@@ -51,6 +62,26 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
+	var diags tfdiags.Diagnostics
+
+	var providerAddrs []addrs.Provider
+	for _, raw := range providerArgs {
+		addr, moreDiags := addrs.ParseProviderSourceString(raw)
+		if moreDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider address",
+				fmt.Sprintf("The -provider option %q is not a valid provider source address: %s", raw, moreDiags.Err()),
+			))
+			continue
+		}
+		providerAddrs = append(providerAddrs, addr)
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -58,8 +89,6 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
-	var diags tfdiags.Diagnostics
-
 	enc, encDiags := c.Encryption()
 	diags = diags.Append(encDiags)
 	if encDiags.HasErrors() {
@@ -127,6 +156,21 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
+	schemas, moreDiags = filterProviderSchemas(schemas, providerAddrs, resourceTypes)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if outputDir != "" {
+		if err := writeProviderSchemaFiles(schemas, outputDir); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write provider schemas: %s", err))
+			return 1
+		}
+		return 0
+	}
+
 	jsonSchemas, err := jsonprovider.Marshal(schemas)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to marshal provider schemas to json: %s", err))
@@ -137,6 +181,116 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	return 0
 }
 
+// filterProviderSchemas returns a copy of the given schemas that includes
+// only the given providers and, within those, only the given resource and
+// data source types. An empty filter includes everything.
+func filterProviderSchemas(schemas *tofu.Schemas, providerAddrs []addrs.Provider, resourceTypes []string) (*tofu.Schemas, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if len(providerAddrs) == 0 && len(resourceTypes) == 0 {
+		return schemas, diags
+	}
+
+	ret := &tofu.Schemas{
+		Providers:    make(map[addrs.Provider]providers.ProviderSchema),
+		Provisioners: schemas.Provisioners,
+	}
+
+	if len(providerAddrs) == 0 {
+		for addr := range schemas.Providers {
+			providerAddrs = append(providerAddrs, addr)
+		}
+	}
+	for _, addr := range providerAddrs {
+		schema, ok := schemas.Providers[addr]
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Provider not used by the configuration",
+				fmt.Sprintf("There is no schema for %s because the current configuration and state do not use it.", addr.ForDisplay()),
+			))
+			continue
+		}
+		ret.Providers[addr] = schema
+	}
+
+	if len(resourceTypes) == 0 {
+		return ret, diags
+	}
+
+	found := make(map[string]bool)
+	for addr, schema := range ret.Providers {
+		filtered := providers.ProviderSchema{
+			Provider:      schema.Provider,
+			ProviderMeta:  schema.ProviderMeta,
+			ResourceTypes: make(map[string]providers.Schema),
+			DataSources:   make(map[string]providers.Schema),
+		}
+		for _, typeName := range resourceTypes {
+			if rs, ok := schema.ResourceTypes[typeName]; ok {
+				filtered.ResourceTypes[typeName] = rs
+				found[typeName] = true
+			}
+			if ds, ok := schema.DataSources[typeName]; ok {
+				filtered.DataSources[typeName] = ds
+				found[typeName] = true
+			}
+		}
+		ret.Providers[addr] = filtered
+	}
+	for _, typeName := range resourceTypes {
+		if !found[typeName] {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Resource type not found",
+				fmt.Sprintf("None of the selected providers has a resource or data source type named %q.", typeName),
+			))
+		}
+	}
+
+	return ret, diags
+}
+
+// writeProviderSchemaFiles writes the schema of each provider to a separate
+// file in the given directory. Each file has the same format as the output
+// of the command with a single provider, and is named after the provider's
+// source address.
+func writeProviderSchemaFiles(schemas *tofu.Schemas, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	addrList := make([]addrs.Provider, 0, len(schemas.Providers))
+	for addr := range schemas.Providers {
+		addrList = append(addrList, addr)
+	}
+	sort.Slice(addrList, func(i, j int) bool {
+		return addrList[i].LessThan(addrList[j])
+	})
+
+	for _, addr := range addrList {
+		single := &tofu.Schemas{
+			Providers: map[addrs.Provider]providers.ProviderSchema{
+				addr: schemas.Providers[addr],
+			},
+		}
+		src, err := jsonprovider.Marshal(single)
+		if err != nil {
+			return fmt.Errorf("marshaling schema for %s: %w", addr, err)
+		}
+		filename := filepath.Join(dir, providerSchemaFilename(addr))
+		if err := os.WriteFile(filename, src, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// providerSchemaFilename returns the name of the file used for the schema
+// of the given provider, like "registry.opentofu.org_hashicorp_aws.json".
+func providerSchemaFilename(addr addrs.Provider) string {
+	return strings.ReplaceAll(addr.String(), "/", "_") + ".json"
+}
+
 const providersSchemaCommandHelp = `
 Usage: tofu [global options] providers schema [options] -json
 
@@ -145,6 +299,19 @@ Usage: tofu [global options] providers schema [options] -json
 
 Options:
 
+  -provider=ADDR          Include only the schema for the given provider,
+                          such as "hashicorp/aws". Use this option more than
+                          once to include more than one provider.
+
+  -resource-type=TYPE     Include only the schemas for the given resource or
+                          data source type, along with the configuration
+                          schema of its provider. Use this option more than
+                          once to include more than one type.
+
+  -output-dir=DIR         Write the schema of each provider to a separate file
+                          in the given directory, named after the provider's
+                          source address, instead of printing all schemas.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
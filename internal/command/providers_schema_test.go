@@ -11,10 +11,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -105,6 +107,106 @@ func TestProvidersSchema_output(t *testing.T) {
 	}
 }
 
+func TestProvidersSchema_filter(t *testing.T) {
+	aws := addrs.NewDefaultProvider("aws")
+	google := addrs.NewDefaultProvider("google")
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			aws: {
+				ResourceTypes: map[string]providers.Schema{
+					"aws_instance": {},
+					"aws_vpc":      {},
+				},
+				DataSources: map[string]providers.Schema{
+					"aws_vpc": {},
+					"aws_ami": {},
+				},
+			},
+			google: {
+				ResourceTypes: map[string]providers.Schema{
+					"google_compute_instance": {},
+				},
+			},
+		},
+	}
+
+	got, diags := filterProviderSchemas(schemas, []addrs.Provider{aws}, []string{"aws_vpc"})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if _, ok := got.Providers[google]; ok {
+		t.Error("google provider was not filtered out")
+	}
+	want := providers.ProviderSchema{
+		ResourceTypes: map[string]providers.Schema{"aws_vpc": {}},
+		DataSources:   map[string]providers.Schema{"aws_vpc": {}},
+	}
+	if diff := cmp.Diff(want, got.Providers[aws]); diff != "" {
+		t.Errorf("wrong aws schema\n%s", diff)
+	}
+
+	_, diags = filterProviderSchemas(schemas, []addrs.Provider{addrs.NewDefaultProvider("azurerm")}, nil)
+	if got, want := diags.Err().Error(), "Provider not used by the configuration"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+
+	_, diags = filterProviderSchemas(schemas, nil, []string{"google_compute_instance", "aws_bucket"})
+	if got, want := diags.Err().Error(), `named "aws_bucket"`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestProvidersSchema_outputDir(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, "testdata/providers-schema/basic", td)
+	defer testChdir(t, td)()
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer close()
+
+	ui := new(cli.MockUi)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(providersSchemaFixtureProvider()),
+		Ui:               ui,
+		ProviderSource:   providerSource,
+	}
+	ic := &InitCommand{Meta: m}
+	if code := ic.Run([]string{}); code != 0 {
+		t.Fatalf("init failed\n%s", ui.ErrorWriter)
+	}
+	ui.OutputWriter.Reset()
+
+	pc := &ProvidersSchemaCommand{Meta: m}
+	args := []string{"-json", "-provider=hashicorp/test", "-resource-type=test_instance", "-output-dir=schemas"}
+	if code := pc.Run(args); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+	if got := ui.OutputWriter.String(); got != "" {
+		t.Errorf("unexpected output: %s", got)
+	}
+
+	src, err := os.ReadFile(filepath.Join("schemas", "registry.opentofu.org_hashicorp_test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got providerSchemas
+	if err := json.Unmarshal(src, &got); err != nil {
+		t.Fatal(err)
+	}
+	schema, ok := got.Schemas["registry.opentofu.org/hashicorp/test"]
+	if !ok {
+		t.Fatalf("schema for hashicorp/test missing from output: %s", src)
+	}
+	if _, ok := schema.ResourceSchemas["test_instance"]; !ok {
+		t.Errorf("test_instance missing from output: %s", src)
+	}
+	if len(schema.Functions) != 0 {
+		t.Errorf("functions were not filtered out: %s", src)
+	}
+}
+
 type providerSchemas struct {
 	FormatVersion string                    `json:"format_version"`
 	Schemas       map[string]providerSchema `json:"provider_schemas"`
@@ -0,0 +1,41 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// RefactorCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type RefactorCommand struct {
+	Meta
+}
+
+func (c *RefactorCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *RefactorCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] refactor <subcommand> [options] [args]
+
+  This command has subcommands for making automated, syntax-aware changes
+  to the configuration files of the current module.
+
+  The subcommands edit the configuration in place while preserving
+  comments and formatting. Use the -dry-run option of each subcommand to
+  review the changes before they are written.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RefactorCommand) Synopsis() string {
+	return "Automated configuration refactoring"
+}
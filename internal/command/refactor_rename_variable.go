@@ -0,0 +1,426 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// RefactorRenameVariableCommand is a Command implementation that renames an
+// input variable, local value or output value throughout the configuration
+// files of a module.
+type RefactorRenameVariableCommand struct {
+	Meta
+}
+
+// renameSymbol describes the symbol being renamed by
+// RefactorRenameVariableCommand.
+type renameSymbol struct {
+	// Prefix is the root name used to refer to the symbol in expressions,
+	// like "var" or "local".
+	Prefix string
+
+	// BlockType is the type of the top-level block that declares the
+	// symbol, like "variable" or "locals".
+	BlockType string
+
+	From, To string
+}
+
+// renamedFile is a configuration file whose content changed as a result of
+// a rename.
+type renamedFile struct {
+	Path      string
+	Mode      os.FileMode
+	Old, New  []byte
+	DiffLabel string
+}
+
+func (c *RefactorRenameVariableCommand) Run(args []string) int {
+	var dryRun bool
+	var moduleCall string
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("refactor rename-variable")
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "dry-run")
+	cmdFlags.StringVar(&moduleCall, "module", "", "module")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("Exactly two arguments expected: the address of the symbol to rename and its new name.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	sym, diags := parseRenameSymbol(args[0], args[1])
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	rootDir := c.normalizePath(".")
+	targetDir := rootDir
+	if moduleCall != "" {
+		dir, moreDiags := c.localModuleCallDir(rootDir, moduleCall)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		targetDir = dir
+	}
+
+	mod, moreDiags := c.loadSingleModule(targetDir, configs.SelectiveLoadAll)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	diags = diags.Append(checkRenameSymbol(mod, sym))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	changed, moreDiags := renameSymbolInDir(targetDir, sym)
+	diags = diags.Append(moreDiags)
+	if moduleCall != "" {
+		callChanged, moreDiags := renameModuleCallSites(rootDir, moduleCall, sym)
+		diags = diags.Append(moreDiags)
+		changed = append(changed, callChanged...)
+	} else if sym.Prefix == "var" {
+		varsChanged, moreDiags := renameVariableInVarsFiles(rootDir, sym)
+		diags = diags.Append(moreDiags)
+		changed = append(changed, varsChanged...)
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	for _, f := range changed {
+		if dryRun {
+			diff, err := bytesDiff(f.Old, f.New, f.DiffLabel)
+			if err != nil {
+				diags = diags.Append(fmt.Errorf("Failed to generate diff for %s: %w", f.Path, err))
+				continue
+			}
+			c.Ui.Output(strings.TrimRight(string(diff), "\n"))
+			continue
+		}
+		if err := os.WriteFile(f.Path, f.New, f.Mode); err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to write %s: %w", f.Path, err))
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	if !dryRun {
+		c.Ui.Output(fmt.Sprintf("Renamed %s.%s to %s.%s in %d file(s).", sym.Prefix, sym.From, sym.Prefix, sym.To, len(changed)))
+	}
+	return 0
+}
+
+// localModuleCallDir returns the directory containing the source code of
+// the module called by the given module call, which must use a local path
+// as its source address.
+func (c *RefactorRenameVariableCommand) localModuleCallDir(rootDir, name string) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	mod, moreDiags := c.loadSingleModule(rootDir, configs.SelectiveLoadAll)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return "", diags
+	}
+
+	call, ok := mod.ModuleCalls[name]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module call not found",
+			fmt.Sprintf("The current module does not contain a module block named %q.", name),
+		))
+		return "", diags
+	}
+	src, ok := call.SourceAddr.(addrs.ModuleSourceLocal)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported module source",
+			fmt.Sprintf("The module %q is not installed from a local path, so its source code cannot be refactored.", name),
+		))
+		return "", diags
+	}
+	return filepath.Join(rootDir, filepath.FromSlash(string(src))), diags
+}
+
+// parseRenameSymbol parses the address of the symbol to rename, like
+// "var.foo", along with its new name.
+func parseRenameSymbol(addr, newName string) (renameSymbol, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var sym renameSymbol
+
+	prefix, name, ok := strings.Cut(addr, ".")
+	switch {
+	case ok && prefix == "var":
+		sym = renameSymbol{Prefix: "var", BlockType: "variable"}
+	case ok && prefix == "local":
+		sym = renameSymbol{Prefix: "local", BlockType: "locals"}
+	case ok && prefix == "output":
+		sym = renameSymbol{Prefix: "output", BlockType: "output"}
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid symbol address",
+			fmt.Sprintf("The address %q is not valid. Use var.NAME, local.NAME or output.NAME.", addr),
+		))
+		return sym, diags
+	}
+
+	if !hclsyntax.ValidIdentifier(name) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid symbol address",
+			fmt.Sprintf("The address %q does not contain a valid name.", addr),
+		))
+	}
+	if !hclsyntax.ValidIdentifier(newName) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid new name",
+			fmt.Sprintf("%q is not a valid name. A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes.", newName),
+		))
+	}
+	sym.From = name
+	sym.To = newName
+	return sym, diags
+}
+
+// checkRenameSymbol verifies that the symbol to rename is declared in the
+// given module and that the new name is not already in use.
+func checkRenameSymbol(mod *configs.Module, sym renameSymbol) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var declared func(string) bool
+	switch sym.Prefix {
+	case "var":
+		declared = func(n string) bool { _, ok := mod.Variables[n]; return ok }
+	case "local":
+		declared = func(n string) bool { _, ok := mod.Locals[n]; return ok }
+	case "output":
+		declared = func(n string) bool { _, ok := mod.Outputs[n]; return ok }
+	}
+
+	if !declared(sym.From) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Symbol not declared",
+			fmt.Sprintf("The module at %s does not declare %s.%s.", mod.SourceDir, sym.Prefix, sym.From),
+		))
+	}
+	if declared(sym.To) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Name already in use",
+			fmt.Sprintf("The module at %s already declares %s.%s.", mod.SourceDir, sym.Prefix, sym.To),
+		))
+	}
+	return diags
+}
+
+// renameSymbolInDir renames the declaration of the given symbol and all
+// references to it in the native syntax configuration files of dir.
+func renameSymbolInDir(dir string, sym renameSymbol) ([]renamedFile, tfdiags.Diagnostics) {
+	return rewriteConfigFiles(dir, configFileNames(dir), func(body *hclwrite.Body) {
+		renameSymbolInBody(body, sym, true)
+	})
+}
+
+// renameModuleCallSites updates the arguments and references of the module
+// call with the given name in the native syntax configuration files of dir
+// to match the renamed symbol inside the called module.
+func renameModuleCallSites(dir, call string, sym renameSymbol) ([]renamedFile, tfdiags.Diagnostics) {
+	return rewriteConfigFiles(dir, configFileNames(dir), func(body *hclwrite.Body) {
+		switch sym.Prefix {
+		case "var":
+			for _, block := range body.Blocks() {
+				if block.Type() == "module" && len(block.Labels()) == 1 && block.Labels()[0] == call {
+					block.Body().RenameAttribute(sym.From, sym.To)
+				}
+			}
+		case "output":
+			renameTraversalsInBody(body, []string{"module", call, sym.From}, []string{"module", call, sym.To})
+		}
+	})
+}
+
+// renameVariableInVarsFiles renames the given input variable in the
+// variable definitions files that OpenTofu loads automatically.
+func renameVariableInVarsFiles(dir string, sym renameSymbol) ([]renamedFile, tfdiags.Diagnostics) {
+	var names []string
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, tfdiags.Diagnostics{}.Append(err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && (name == DefaultVarsFilename || strings.HasSuffix(name, ".auto.tfvars")) {
+			names = append(names, name)
+		}
+	}
+	return rewriteConfigFiles(dir, names, func(body *hclwrite.Body) {
+		body.RenameAttribute(sym.From, sym.To)
+	})
+}
+
+// configFileNames returns the names of the native syntax configuration
+// files in the given directory, in lexical order.
+func configFileNames(dir string) []string {
+	var names []string
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && (strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tofu")) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rewriteConfigFiles applies the given edit to each of the named files in
+// dir, returning only the files whose content changed. Files are not
+// written to disk.
+func rewriteConfigFiles(dir string, names []string, edit func(*hclwrite.Body)) ([]renamedFile, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var changed []renamedFile
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			diags = diags.Append(err)
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			diags = diags.Append(err)
+			continue
+		}
+
+		f, hclDiags := hclwrite.ParseConfig(src, path, hcl.InitialPos)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+		edit(f.Body())
+
+		result := f.Bytes()
+		if !bytes.Equal(src, result) {
+			changed = append(changed, renamedFile{
+				Path:      path,
+				Mode:      info.Mode().Perm(),
+				Old:       src,
+				New:       result,
+				DiffLabel: name,
+			})
+		}
+	}
+
+	return changed, diags
+}
+
+// renameSymbolInBody renames all references to the given symbol in body
+// and in any nested blocks, and also renames its declaration when body is
+// the top-level body of a file.
+func renameSymbolInBody(body *hclwrite.Body, sym renameSymbol, topLevel bool) {
+	if topLevel {
+		for _, block := range body.Blocks() {
+			if block.Type() != sym.BlockType {
+				continue
+			}
+			if sym.Prefix == "local" {
+				block.Body().RenameAttribute(sym.From, sym.To)
+				continue
+			}
+			if labels := block.Labels(); len(labels) == 1 && labels[0] == sym.From {
+				block.SetLabels([]string{sym.To})
+			}
+		}
+	}
+
+	// Output values cannot be referred to from within their own module.
+	if sym.Prefix == "output" {
+		return
+	}
+	renameTraversalsInBody(body, []string{sym.Prefix, sym.From}, []string{sym.Prefix, sym.To})
+}
+
+// renameTraversalsInBody replaces the given traversal prefix in every
+// expression of body and of its nested blocks.
+func renameTraversalsInBody(body *hclwrite.Body, search, replacement []string) {
+	for _, attr := range body.Attributes() {
+		attr.Expr().RenameVariablePrefix(search, replacement)
+	}
+	for _, block := range body.Blocks() {
+		renameTraversalsInBody(block.Body(), search, replacement)
+	}
+}
+
+func (c *RefactorRenameVariableCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] refactor rename-variable [options] ADDRESS NEW_NAME
+
+  Renames an input variable, local value or output value of the module in
+  the current directory, updating its declaration and every reference to it
+  across the module's configuration files. Comments and formatting are
+  preserved.
+
+  ADDRESS is the current address of the symbol, using one of the forms
+  var.NAME, local.NAME or output.NAME. NEW_NAME is the new name to use.
+
+  When renaming an input variable of the root module, the variable is also
+  renamed in terraform.tfvars and any *.auto.tfvars files.
+
+  Files written in the JSON configuration syntax are not updated.
+
+Options:
+
+  -dry-run         Show the changes as a diff instead of writing them.
+
+  -module=NAME     Rename the symbol in the local child module called by
+                   the module block NAME instead, and update that module
+                   block's arguments and references to match.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RefactorRenameVariableCommand) Synopsis() string {
+	return "Rename a variable, local value or output across a module"
+}
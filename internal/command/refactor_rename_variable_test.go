@@ -0,0 +1,217 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func TestRefactorRenameVariable_variable(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-rename"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &RefactorRenameVariableCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"var.prefix", "name_prefix"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := `# The name prefix for all resources.
+variable "name_prefix" {
+  type = string
+}
+
+locals {
+  # Derived name
+  name = "${var.name_prefix}-app"
+}
+
+module "child" {
+  source = "./child"
+
+  label = local.name
+}
+
+output "child_label" {
+  value = module.child.label_out
+}
+`
+	if diff := cmp.Diff(want, testReadFile(t, filepath.Join(td, "main.tf"))); diff != "" {
+		t.Errorf("wrong main.tf\n%s", diff)
+	}
+	if diff := cmp.Diff("name_prefix = \"test\"\n", testReadFile(t, filepath.Join(td, "terraform.tfvars"))); diff != "" {
+		t.Errorf("wrong terraform.tfvars\n%s", diff)
+	}
+	if got, want := ui.OutputWriter.String(), "in 2 file(s)"; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestRefactorRenameVariable_local(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-rename"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &RefactorRenameVariableCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"local.name", "app_name"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	got := testReadFile(t, filepath.Join(td, "main.tf"))
+	for _, want := range []string{
+		"  # Derived name\n  app_name = \"${var.prefix}-app\"\n",
+		"  label = local.app_name\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("main.tf does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestRefactorRenameVariable_moduleCall(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-rename"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &RefactorRenameVariableCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"-module=child", "var.label", "title"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if code := c.Run([]string{"-module=child", "output.label_out", "title_out"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	wantChild := `variable "title" {
+  type = string
+}
+
+output "title_out" {
+  value = upper(var.title)
+}
+`
+	if diff := cmp.Diff(wantChild, testReadFile(t, filepath.Join(td, "child", "main.tf"))); diff != "" {
+		t.Errorf("wrong child/main.tf\n%s", diff)
+	}
+	got := testReadFile(t, filepath.Join(td, "main.tf"))
+	for _, want := range []string{
+		"  title = local.name\n",
+		"  value = module.child.title_out\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("main.tf does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestRefactorRenameVariable_dryRun(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-rename"), td)
+	defer testChdir(t, td)()
+
+	before := testReadFile(t, filepath.Join(td, "main.tf"))
+
+	ui := cli.NewMockUi()
+	c := &RefactorRenameVariableCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"-dry-run", "var.prefix", "name_prefix"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if after := testReadFile(t, filepath.Join(td, "main.tf")); after != before {
+		t.Fatalf("dry run modified main.tf\n%s", after)
+	}
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		`-variable "prefix" {`,
+		`+variable "name_prefix" {`,
+		`+  name = "${var.name_prefix}-app"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diff does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestRefactorRenameVariable_invalid(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("refactor-rename"), td)
+	defer testChdir(t, td)()
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"undeclared": {
+			[]string{"var.missing", "other"},
+			"does not declare var.missing",
+		},
+		"in use": {
+			[]string{"output.child_label", "child_label"},
+			"already declares output.child_label",
+		},
+		"bad address": {
+			[]string{"resource.foo", "bar"},
+			"is not valid",
+		},
+		"bad name": {
+			[]string{"var.prefix", "1abc"},
+			"is not a valid name",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := &RefactorRenameVariableCommand{
+				Meta: Meta{
+					Ui: ui,
+				},
+			}
+			if code := c.Run(test.args); code != 1 {
+				t.Fatalf("unexpected exit code %d", code)
+			}
+			if got := ui.ErrorWriter.String(); !strings.Contains(got, test.want) {
+				t.Errorf("output does not contain %q\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+func testReadFile(t *testing.T, path string) string {
+	t.Helper()
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(src)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -69,6 +70,8 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.schedule = args.Operation.Schedule
+	c.Meta.runTimeout = args.Operation.RunTimeout
 
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
@@ -111,7 +114,7 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	}
 
 	if op.State != nil {
-		view.Outputs(op.State.RootModule().OutputValues)
+		view.Outputs(states.PublicOutputValues(op.State.RootModule().OutputValues))
 	}
 
 	return op.Result.ExitStatus()
@@ -220,12 +223,39 @@ Options:
 
   -lock-timeout=0s       Duration to retry a state lock.
 
+  -lock-retry-delay=1s   Delay before the first retry of a state lock held by
+                         another process. Overrides the CLI configuration.
+
+  -lock-retry-max-delay=16s
+                         Maximum delay between retries of a state lock.
+
+  -lock-retry-multiplier=2
+                         Factor to increase the delay by after each retry.
+
+  -lock-retry-jitter=0   Fraction between 0 and 1 to randomly vary each delay
+                         by.
+
+  -lock-retry-max-attempts=0
+                         Maximum number of attempts to acquire a state lock.
+                         Defaults to no limit; -lock-timeout limits the total
+                         time.
+
   -no-color              If specified, output won't contain any color.
 
   -concise               Disables progress-related messages in the output.
 
   -parallelism=n         Limit the number of concurrent operations. Defaults to 10.
 
+  -schedule=policy       Order in which operations that are ready to run
+                         are started when all parallelism slots are busy:
+                         "fifo" (the default), "fair" to share the slots
+                         between modules, or "critical-path" to start the
+                         longest dependency chains first.
+
+  -run-timeout=duration  Stop gracefully, as if interrupted, when the run takes
+                         longer than the given duration, such as "90m".
+                         Changes applied before then are saved in the state.
+
   -target=resource       Resource to target. Operation will be limited to this
                          resource and its dependencies. This flag can be used
                          multiple times.  Cannot be used alongside the -exclude
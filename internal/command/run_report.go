@@ -0,0 +1,617 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofumigrate"
+	"github.com/opentofu/opentofu/version"
+)
+
+// runReportFormatVersion is the version of the layout of the bundle written by
+// the run-report command. It follows the same rules as the other JSON format
+// versions: minor version changes are backward compatible additions.
+const runReportFormatVersion = "1.0"
+
+// runReportRedacted replaces sensitive values in the JSON plan included in a
+// run report.
+const runReportRedacted = "(sensitive value)"
+
+// RunReportCommand is a Command implementation that bundles the artifacts of
+// a plan or apply run into a single compressed archive, for attaching to
+// incident reviews and support requests.
+type RunReportCommand struct {
+	Meta
+}
+
+// runReportMetadata is the content of the metadata.json file of a run report.
+type runReportMetadata struct {
+	FormatVersion   string             `json:"format_version"`
+	OpenTofuVersion string             `json:"opentofu_version"`
+	Platform        string             `json:"platform"`
+	CreatedAt       string             `json:"created_at"`
+	Plan            *runReportPlanMeta `json:"plan,omitempty"`
+	Log             *runReportLogMeta  `json:"log,omitempty"`
+}
+
+// runReportPlanMeta describes the plan file a run report was created from.
+type runReportPlanMeta struct {
+	Filename     string `json:"filename"`
+	SHA256       string `json:"sha256"`
+	StateLineage string `json:"state_lineage,omitempty"`
+	StateSerial  uint64 `json:"state_serial"`
+}
+
+// runReportLogMeta describes the machine-readable log a run report was
+// created from.
+type runReportLogMeta struct {
+	Filename        string `json:"filename"`
+	SHA256          string `json:"sha256"`
+	OpenTofuVersion string `json:"opentofu_version,omitempty"`
+	UIVersion       string `json:"ui_version,omitempty"`
+	Events          int    `json:"events"`
+	SkippedLines    int    `json:"skipped_lines,omitempty"`
+}
+
+// runReportTiming is the content of the timing.json file of a run report.
+type runReportTiming struct {
+	StartedAt       string                        `json:"started_at,omitempty"`
+	FinishedAt      string                        `json:"finished_at,omitempty"`
+	DurationSeconds float64                       `json:"duration_seconds"`
+	Operations      []runReportOperationTime      `json:"operations"`
+	ChangeSummary   json.RawMessage               `json:"change_summary,omitempty"`
+	MessageCounts   map[viewsjson.MessageType]int `json:"message_counts"`
+}
+
+// runReportOperationTime is the time spent refreshing or applying a single
+// resource instance.
+type runReportOperationTime struct {
+	Address        string  `json:"address"`
+	Operation      string  `json:"operation"`
+	Action         string  `json:"action,omitempty"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	Errored        bool    `json:"errored,omitempty"`
+}
+
+// runReportLogEvent holds the parts of a machine-readable log message that
+// the run-report command is interested in.
+type runReportLogEvent struct {
+	Type       viewsjson.MessageType `json:"type"`
+	Timestamp  string                `json:"@timestamp"`
+	Tofu       string                `json:"tofu"`
+	UI         string                `json:"ui"`
+	Diagnostic json.RawMessage       `json:"diagnostic"`
+	Changes    json.RawMessage       `json:"changes"`
+	Hook       struct {
+		Resource struct {
+			Addr string `json:"addr"`
+		} `json:"resource"`
+		Action  string   `json:"action"`
+		Elapsed *float64 `json:"elapsed_seconds"`
+	} `json:"hook"`
+}
+
+// runReportFile is a single file in a run report bundle.
+type runReportFile struct {
+	Name    string
+	Content []byte
+}
+
+func (c *RunReportCommand) Run(args []string) int {
+	var planPath, logPath, outPath string
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("run-report")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.StringVar(&planPath, "plan", "", "path")
+	cmdFlags.StringVar(&logPath, "log", "", "path")
+	cmdFlags.StringVar(&outPath, "out", "run-report.tar.gz", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Streams.Eprintf("Error parsing command-line flags: %s\n", err.Error())
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Streams.Eprint("The run-report command expects no arguments.\n")
+		return cli.RunResultHelp
+	}
+	if planPath == "" && logPath == "" {
+		c.Streams.Eprint("The run-report command requires at least one of the -plan and -log options.\n")
+		return cli.RunResultHelp
+	}
+
+	var diags tfdiags.Diagnostics
+	var files []runReportFile
+	meta := runReportMetadata{
+		FormatVersion:   runReportFormatVersion,
+		OpenTofuVersion: version.String(),
+		Platform:        runtime.GOOS + "_" + runtime.GOARCH,
+		CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if planPath != "" {
+		planMeta, planJSON, planDiags := c.reportPlan(planPath)
+		diags = diags.Append(planDiags)
+		if planDiags.HasErrors() {
+			c.View.Diagnostics(diags)
+			return 1
+		}
+		meta.Plan = planMeta
+		files = append(files, runReportFile{Name: "plan.json", Content: planJSON})
+	}
+
+	if logPath != "" {
+		logMeta, logFiles, err := runReportLog(logPath)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read log",
+				fmt.Sprintf("Could not read the machine-readable log %s: %s.", logPath, err),
+			))
+			c.View.Diagnostics(diags)
+			return 1
+		}
+		meta.Log = logMeta
+		if logMeta.SkippedLines != 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Log contains unrecognized lines",
+				fmt.Sprintf("%d lines of %s are not OpenTofu machine-readable log messages and are not included in the report. Use the -json option of the plan and apply commands to produce the log.", logMeta.SkippedLines, logPath),
+			))
+		}
+		files = append(files, logFiles...)
+	}
+
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		c.Streams.Eprintf("Failed to marshal report metadata: %s\n", err)
+		return 1
+	}
+	files = append([]runReportFile{{Name: "metadata.json", Content: metaJSON}}, files...)
+
+	if err := writeRunReport(outPath, files); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write run report",
+			fmt.Sprintf("Could not write the run report to %s: %s.", outPath, err),
+		))
+		c.View.Diagnostics(diags)
+		return 1
+	}
+
+	c.View.Diagnostics(diags)
+	c.Streams.Printf("Run report written to %s\n", outPath)
+	return 0
+}
+
+// reportPlan reads the saved plan file at the given path and returns its
+// metadata along with its JSON representation, with all sensitive values
+// redacted.
+func (c *RunReportCommand) reportPlan(path string) (*runReportPlanMeta, []byte, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	sum, err := runReportChecksum(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Could not read the plan file %s: %s.", path, err),
+		))
+		return nil, nil, diags
+	}
+
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		diags = diags.Append(fmt.Errorf("error loading plugin path: %w", err))
+		return nil, nil, diags
+	}
+
+	enc, encDiags := c.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	rootCall, callDiags := c.rootModuleCall(".")
+	diags = diags.Append(callDiags)
+	if callDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	pf, err := planfile.OpenWrapped(path, enc.Plan())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Could not read the plan file %s: %s.", path, err),
+		))
+		return nil, nil, diags
+	}
+	lp, ok := pf.Local()
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported plan file",
+			"The run-report command supports only local plan files. Use the remote system's own reporting features for saved cloud plans.",
+		))
+		return nil, nil, diags
+	}
+
+	plan, stateFile, config, err := getDataFromPlanfileReader(lp, rootCall)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Could not read the plan file %s: %s.", path, err),
+		))
+		return nil, nil, diags
+	}
+
+	var migrateDiags tfdiags.Diagnostics
+	stateFile.State, migrateDiags = tofumigrate.MigrateStateProviderAddresses(config, stateFile.State)
+	diags = diags.Append(migrateDiags)
+	if migrateDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	schemas, schemaDiags := c.MaybeGetSchemas(stateFile.State, config)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	raw, err := jsonplan.Marshal(config, plan, stateFile, schemas)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to marshal plan to json: %w", err))
+		return nil, nil, diags
+	}
+	planJSON, err := redactPlanJSON(raw)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to redact the json plan: %w", err))
+		return nil, nil, diags
+	}
+
+	meta := &runReportPlanMeta{
+		Filename:     filepath.Base(path),
+		SHA256:       sum,
+		StateLineage: stateFile.Lineage,
+		StateSerial:  stateFile.Serial,
+	}
+	return meta, planJSON, diags
+}
+
+// runReportLog reads the machine-readable log at the given path, as produced
+// by the -json option of the plan and apply commands, and returns its
+// metadata along with the events, diagnostics and timing files of the report.
+func runReportLog(path string) (*runReportLogMeta, []runReportFile, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := sha256.Sum256(src)
+	meta := &runReportLogMeta{
+		Filename: filepath.Base(path),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+
+	var events bytes.Buffer
+	diagnostics := []json.RawMessage{}
+	timing := runReportTiming{
+		Operations:    []runReportOperationTime{},
+		MessageCounts: make(map[viewsjson.MessageType]int),
+	}
+	var first, last time.Time
+	refreshStarts := make(map[string]time.Time)
+
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	sc.Buffer(nil, 16*1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var event runReportLogEvent
+		if err := json.Unmarshal(line, &event); err != nil || event.Type == "" {
+			meta.SkippedLines++
+			continue
+		}
+		meta.Events++
+		events.Write(line)
+		events.WriteByte('\n')
+		timing.MessageCounts[event.Type]++
+
+		ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+		if err == nil {
+			if first.IsZero() || ts.Before(first) {
+				first = ts
+			}
+			if ts.After(last) {
+				last = ts
+			}
+		}
+
+		switch event.Type {
+		case viewsjson.MessageVersion:
+			meta.OpenTofuVersion = event.Tofu
+			meta.UIVersion = event.UI
+		case viewsjson.MessageDiagnostic:
+			if len(event.Diagnostic) != 0 {
+				diagnostics = append(diagnostics, event.Diagnostic)
+			}
+		case viewsjson.MessageChangeSummary:
+			timing.ChangeSummary = event.Changes
+		case viewsjson.MessageApplyComplete, viewsjson.MessageApplyErrored:
+			if event.Hook.Elapsed != nil {
+				timing.Operations = append(timing.Operations, runReportOperationTime{
+					Address:        event.Hook.Resource.Addr,
+					Operation:      "apply",
+					Action:         event.Hook.Action,
+					ElapsedSeconds: *event.Hook.Elapsed,
+					Errored:        event.Type == viewsjson.MessageApplyErrored,
+				})
+			}
+		case viewsjson.MessageRefreshStart:
+			if !ts.IsZero() {
+				refreshStarts[event.Hook.Resource.Addr] = ts
+			}
+		case viewsjson.MessageRefreshComplete:
+			if start, ok := refreshStarts[event.Hook.Resource.Addr]; ok && !ts.IsZero() {
+				timing.Operations = append(timing.Operations, runReportOperationTime{
+					Address:        event.Hook.Resource.Addr,
+					Operation:      "refresh",
+					ElapsedSeconds: ts.Sub(start).Seconds(),
+				})
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !first.IsZero() {
+		timing.StartedAt = first.Format(time.RFC3339Nano)
+		timing.FinishedAt = last.Format(time.RFC3339Nano)
+		timing.DurationSeconds = last.Sub(first).Seconds()
+	}
+	// The slowest operations are the most interesting ones when reviewing a
+	// run, so we list them first.
+	sort.SliceStable(timing.Operations, func(i, j int) bool {
+		return timing.Operations[i].ElapsedSeconds > timing.Operations[j].ElapsedSeconds
+	})
+
+	diagsJSON, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	timingJSON, err := json.MarshalIndent(timing, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []runReportFile{
+		{Name: "events.jsonl", Content: events.Bytes()},
+		{Name: "diagnostics.json", Content: diagsJSON},
+		{Name: "timing.json", Content: timingJSON},
+	}
+	return meta, files, nil
+}
+
+// redactPlanJSON replaces all of the values that the given JSON plan
+// describes as sensitive with a placeholder, so that the plan can be shared
+// with people who are not allowed to see them.
+func redactPlanJSON(src []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var plan map[string]interface{}
+	if err := dec.Decode(&plan); err != nil {
+		return nil, err
+	}
+
+	// Root module variables don't carry their own sensitivity, so we must
+	// look up their declarations in the configuration.
+	if vars, ok := plan["variables"].(map[string]interface{}); ok {
+		decls := runReportLookup(plan, "configuration", "root_module", "variables")
+		for name, v := range vars {
+			if sensitive, _ := runReportLookup(decls, name, "sensitive").(bool); !sensitive {
+				continue
+			}
+			if v, ok := v.(map[string]interface{}); ok {
+				v["value"] = runReportRedacted
+			}
+		}
+	}
+	redactSensitiveJSONValues(plan)
+
+	return json.MarshalIndent(plan, "", "  ")
+}
+
+// redactSensitiveJSONValues walks the given decoded JSON plan or state and
+// redacts the values described as sensitive by their neighbouring
+// sensitivity information.
+func redactSensitiveJSONValues(node interface{}) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		for _, pair := range [][2]string{
+			{"before", "before_sensitive"},
+			{"after", "after_sensitive"},
+			{"values", "sensitive_values"},
+		} {
+			val, ok := node[pair[0]]
+			if !ok {
+				continue
+			}
+			if sensitive, ok := node[pair[1]]; ok {
+				node[pair[0]] = redactSensitiveJSONValue(val, sensitive)
+			}
+		}
+		// Outputs and variable declarations have a single "sensitive" flag
+		// covering the whole value.
+		if sensitive, _ := node["sensitive"].(bool); sensitive {
+			for _, key := range []string{"value", "default"} {
+				if val, ok := node[key]; ok && val != nil {
+					node[key] = runReportRedacted
+				}
+			}
+		}
+		for _, child := range node {
+			redactSensitiveJSONValues(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			redactSensitiveJSONValues(child)
+		}
+	}
+}
+
+// redactSensitiveJSONValue redacts the parts of val that are marked as
+// sensitive by the given sensitivity structure, which mirrors the shape of
+// val with true in place of each sensitive value.
+func redactSensitiveJSONValue(val, sensitive interface{}) interface{} {
+	switch sensitive := sensitive.(type) {
+	case bool:
+		if sensitive && val != nil {
+			return runReportRedacted
+		}
+	case map[string]interface{}:
+		if val, ok := val.(map[string]interface{}); ok {
+			for k, s := range sensitive {
+				if v, ok := val[k]; ok {
+					val[k] = redactSensitiveJSONValue(v, s)
+				}
+			}
+		}
+	case []interface{}:
+		if val, ok := val.([]interface{}); ok {
+			for i, s := range sensitive {
+				if i < len(val) {
+					val[i] = redactSensitiveJSONValue(val[i], s)
+				}
+			}
+		}
+	}
+	return val
+}
+
+// runReportLookup returns the value at the given path of object attributes
+// in a decoded JSON value, or nil if there is none.
+func runReportLookup(node interface{}, path ...string) interface{} {
+	for _, key := range path {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = obj[key]
+	}
+	return node
+}
+
+// runReportChecksum returns the hex-encoded SHA-256 checksum of the file at
+// the given path.
+func runReportChecksum(path string) (string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeRunReport writes the given files into a gzip-compressed tar archive
+// at the given path.
+func writeRunReport(path string, files []runReportFile) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    file.Name,
+			Mode:    0o600,
+			Size:    int64(len(file.Content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (c *RunReportCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] run-report [options]
+
+  Creates a single compressed archive describing a plan or apply run, for
+  attaching to incident reviews and support requests.
+
+  The archive can include the JSON representation of a saved plan file and
+  the events, diagnostics and timing information from the machine-readable
+  log written by the -json option of "tofu plan" and "tofu apply". It
+  always includes the version of OpenTofu, the platform it runs on and the
+  checksums of the given files.
+
+  Values that OpenTofu considers sensitive are replaced with
+  "(sensitive value)" in the JSON plan. Review the archive before sharing
+  it, because values that are not marked as sensitive are included as-is.
+
+Options:
+
+  -plan=path          A saved plan file, created by "tofu plan -out=path", to
+                      include in the report.
+
+  -log=path           A file containing the output of "tofu plan -json" or
+                      "tofu apply -json" to include in the report.
+
+  -out=path           Path of the archive to write. Defaults to
+                      "run-report.tar.gz".
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RunReportCommand) Synopsis() string {
+	return "Bundle the artifacts of a run for sharing"
+}
@@ -0,0 +1,266 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+const testRunReportLog = `{"@level":"info","@message":"OpenTofu 1.9.0","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:00.000000Z","tofu":"1.9.0","type":"version","ui":"1.2"}
+{"@level":"info","@message":"test_instance.foo: Refreshing state... [id=foo]","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:01.000000Z","hook":{"resource":{"addr":"test_instance.foo"},"id_key":"id","id_value":"foo"},"type":"refresh_start"}
+{"@level":"info","@message":"test_instance.foo: Refresh complete [id=foo]","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:03.000000Z","hook":{"resource":{"addr":"test_instance.foo"},"id_key":"id","id_value":"foo"},"type":"refresh_complete"}
+{"@level":"warn","@message":"Warning: Deprecated","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:04.000000Z","diagnostic":{"severity":"warning","summary":"Deprecated","detail":""},"type":"diagnostic"}
+not a log message
+{"@level":"info","@message":"test_instance.foo: Creation complete after 10s","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:14.000000Z","hook":{"resource":{"addr":"test_instance.foo"},"action":"create","elapsed_seconds":10},"type":"apply_complete"}
+{"@level":"error","@message":"test_instance.bar: Creation errored after 1s","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:15.000000Z","hook":{"resource":{"addr":"test_instance.bar"},"action":"create","elapsed_seconds":1},"type":"apply_errored"}
+{"@level":"info","@message":"Apply complete!","@module":"tofu.ui","@timestamp":"2024-01-02T10:00:16.000000Z","changes":{"add":1,"change":0,"import":0,"remove":0,"operation":"apply"},"type":"change_summary"}
+`
+
+func TestRunReport(t *testing.T) {
+	td := t.TempDir()
+
+	_, snap := testModuleWithSnapshot(t, "show")
+	plannedVal := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.UnknownVal(cty.String),
+		"ami": cty.StringVal("hunter2"),
+	})
+	priorValRaw, err := plans.NewDynamicValue(cty.NullVal(plannedVal.Type()), plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plannedValRaw, err := plans.NewDynamicValue(plannedVal, plannedVal.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := testPlan(t)
+	plan.Changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr: addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: "foo",
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		ProviderAddr: addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		ChangeSrc: plans.ChangeSrc{
+			Action: plans.Create,
+			Before: priorValRaw,
+			After:  plannedValRaw,
+			AfterValMarks: []cty.PathValueMarks{
+				{Path: cty.GetAttrPath("ami"), Marks: cty.NewValueMarks(marks.Sensitive)},
+			},
+		},
+	})
+	planPath := testPlanFile(t, snap, states.NewState(), plan)
+
+	logPath := filepath.Join(td, "apply.log")
+	if err := os.WriteFile(logPath, []byte(testRunReportLog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(td, "report.tar.gz")
+
+	streams, done := terminal.StreamsForTesting(t)
+	view, viewDone := testView(t)
+	c := &RunReportCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			Streams:          streams,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-plan", planPath, "-log", logPath, "-out", outPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("wrong exit code %d\n\n%s", code, output.Stderr())
+	}
+	if viewOutput := viewDone(t); !strings.Contains(viewOutput.All(), "Log contains unrecognized lines") {
+		t.Errorf("missing warning about the unrecognized line:\n%s", viewOutput.All())
+	}
+
+	files := testReadRunReport(t, outPath)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	wantNames := []string{"diagnostics.json", "events.jsonl", "metadata.json", "plan.json", "timing.json"}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Errorf("wrong files in report\n%s", diff)
+	}
+
+	if strings.Contains(files["plan.json"], "hunter2") {
+		t.Errorf("plan.json contains sensitive value:\n%s", files["plan.json"])
+	}
+	if !strings.Contains(files["plan.json"], runReportRedacted) {
+		t.Errorf("plan.json does not contain redacted value:\n%s", files["plan.json"])
+	}
+
+	var meta runReportMetadata
+	if err := json.Unmarshal([]byte(files["metadata.json"]), &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.Plan == nil || meta.Plan.Filename != filepath.Base(planPath) || len(meta.Plan.SHA256) != 64 {
+		t.Errorf("wrong plan metadata: %#v", meta.Plan)
+	}
+	wantLog := &runReportLogMeta{
+		Filename:        "apply.log",
+		SHA256:          meta.Log.SHA256,
+		OpenTofuVersion: "1.9.0",
+		UIVersion:       "1.2",
+		Events:          7,
+		SkippedLines:    1,
+	}
+	if diff := cmp.Diff(wantLog, meta.Log); diff != "" {
+		t.Errorf("wrong log metadata\n%s", diff)
+	}
+
+	if got := strings.Count(files["events.jsonl"], "\n"); got != 7 {
+		t.Errorf("wrong number of events %d", got)
+	}
+
+	var diagnostics []map[string]interface{}
+	if err := json.Unmarshal([]byte(files["diagnostics.json"]), &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0]["summary"] != "Deprecated" {
+		t.Errorf("wrong diagnostics: %#v", diagnostics)
+	}
+
+	var timing runReportTiming
+	if err := json.Unmarshal([]byte(files["timing.json"]), &timing); err != nil {
+		t.Fatal(err)
+	}
+	if timing.DurationSeconds != 16 {
+		t.Errorf("wrong duration %v", timing.DurationSeconds)
+	}
+	wantOps := []runReportOperationTime{
+		{Address: "test_instance.foo", Operation: "apply", Action: "create", ElapsedSeconds: 10},
+		{Address: "test_instance.foo", Operation: "refresh", ElapsedSeconds: 2},
+		{Address: "test_instance.bar", Operation: "apply", Action: "create", ElapsedSeconds: 1, Errored: true},
+	}
+	if diff := cmp.Diff(wantOps, timing.Operations); diff != "" {
+		t.Errorf("wrong operations\n%s", diff)
+	}
+}
+
+func TestRunReport_noInputs(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	c := &RunReportCommand{
+		Meta: Meta{
+			Streams: streams,
+		},
+	}
+
+	if code := c.Run(nil); code == 0 {
+		t.Fatal("expected failure without -plan and -log")
+	}
+	output := done(t)
+	if !strings.Contains(output.Stderr(), "requires at least one of the -plan and -log options") {
+		t.Errorf("wrong error:\n%s", output.Stderr())
+	}
+}
+
+func TestRedactPlanJSON(t *testing.T) {
+	src := `{
+  "variables": {"password": {"value": "hunter2"}, "region": {"value": "us-east-1"}},
+  "output_changes": {"secret": {"before": null, "after": "hunter2", "before_sensitive": false, "after_sensitive": true}},
+  "planned_values": {"outputs": {"secret": {"sensitive": true, "value": "hunter2"}}},
+  "prior_state": {"values": {"root_module": {"resources": [
+    {"address": "test_instance.foo", "values": {"tags": {"a": "hunter2", "b": "visible"}, "list": [1, 2]}, "sensitive_values": {"tags": {"a": true}, "list": [false, true]}}
+  ]}}},
+  "configuration": {"root_module": {"variables": {
+    "password": {"sensitive": true, "default": "hunter2"},
+    "region": {}
+  }}}
+}`
+	got, err := redactPlanJSON([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "hunter2") {
+		t.Errorf("sensitive value not redacted:\n%s", got)
+	}
+
+	var plan map[string]interface{}
+	if err := json.Unmarshal(got, &plan); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		path []string
+		want interface{}
+	}{
+		{[]string{"variables", "password", "value"}, runReportRedacted},
+		{[]string{"variables", "region", "value"}, "us-east-1"},
+		{[]string{"output_changes", "secret", "after"}, runReportRedacted},
+		{[]string{"planned_values", "outputs", "secret", "value"}, runReportRedacted},
+		{[]string{"configuration", "root_module", "variables", "password", "default"}, runReportRedacted},
+	} {
+		if got := runReportLookup(plan, tc.path...); got != tc.want {
+			t.Errorf("wrong value at %s: got %#v, want %#v", strings.Join(tc.path, "."), got, tc.want)
+		}
+	}
+
+	resources := runReportLookup(plan, "prior_state", "values", "root_module", "resources").([]interface{})
+	values := resources[0].(map[string]interface{})["values"]
+	wantValues := map[string]interface{}{
+		"tags": map[string]interface{}{"a": runReportRedacted, "b": "visible"},
+		"list": []interface{}{float64(1), runReportRedacted},
+	}
+	if diff := cmp.Diff(wantValues, values); diff != "" {
+		t.Errorf("wrong resource values\n%s", diff)
+	}
+}
+
+func testReadRunReport(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
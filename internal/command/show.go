@@ -9,16 +9,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonfilter"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -74,8 +78,21 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	// Set up view
 	view := views.NewShow(args.ViewType, c.View)
 
+	filters, err := jsonfilter.ParsePipeline(args.JSONFilters)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid JSON filter",
+			err.Error(),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	if jsonView, ok := view.(*views.ShowJSON); ok {
+		jsonView.Filters = filters
+	}
+
 	// Check for user-supplied plugin path
-	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
 		diags = diags.Append(fmt.Errorf("error loading plugin path: %w", err))
 		view.Diagnostics(diags)
@@ -101,10 +118,71 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	if args.Reveal != "" {
+		return c.reveal(view, args.Reveal, plan, jsonPlan, stateFile, schemas)
+	}
+
 	// Display the data
 	return view.Display(config, plan, jsonPlan, stateFile, schemas)
 }
 
+// reveal displays the single value with the given address, which may be
+// sensitive, after the user confirmed it. Revealing a value is reported to
+// the webhooks configured in the CLI configuration, if any.
+func (c *ShowCommand) reveal(view views.Show, rawAddr string, plan *plans.Plan, jsonPlan *cloudplan.RemotePlanJSON, stateFile *statefile.File, schemas *tofu.Schemas) int {
+	var diags tfdiags.Diagnostics
+
+	if jsonPlan != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't reveal values of a cloud plan",
+			"The -reveal option only supports local plan files and state snapshots.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	addr, addrDiags := parseShowRevealAddr(rawAddr)
+	diags = diags.Append(addrDiags)
+	if addrDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	val, valDiags := addr.Value(plan, stateFile, schemas)
+	diags = diags.Append(valDiags)
+	if valDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	desc := fmt.Sprintf(
+		"OpenTofu will display the value of %s in plain text, even if it is sensitive.\n"+
+			"Revealing the value is reported to the webhooks configured to receive the\n"+
+			"sensitive_reveal event. Only 'yes' will be accepted to confirm.",
+		addr,
+	)
+	v, err := c.UIInput().Input(context.Background(), &tofu.InputOpts{
+		Id:          "reveal",
+		Query:       "Do you really want to reveal this value?",
+		Description: desc,
+	})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error asking for confirmation: %s", err))
+		return 1
+	}
+	if v != "yes" {
+		c.Ui.Output("Reveal cancelled.")
+		return 1
+	}
+
+	log.Printf("[WARN] show: revealing the value of %s", addr)
+	if hook := c.notificationHook(); hook != nil {
+		hook.SensitiveRevealed(context.Background(), addr.String())
+	}
+
+	return view.DisplayValue(addr.String(), val)
+}
+
 func (c *ShowCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] show [options] [path]
@@ -119,8 +197,22 @@ Options:
   -json               If specified, output the OpenTofu plan or state in
                       a machine-readable form.
 
+  -json-filter=FILTER If specified with -json, apply a privacy filter to the
+                      output before it is displayed. FILTER is one of
+                      drop=PATH, which removes the resource attribute at PATH,
+                      hash=PATH, which replaces its value with a SHA256 hash,
+                      or strip-provider-config, which removes the arguments
+                      of all provider configurations. Use this option more
+                      than once to apply several filters in order.
+
   -show-sensitive     If specified, sensitive values will be displayed.
 
+  -reveal=ADDRESS     Display only the value at the given address, such as
+                      aws_db_instance.main.password or output.token, even if
+                      it is sensitive. OpenTofu asks for confirmation first,
+                      and reports the reveal to the webhooks configured in
+                      the CLI configuration.
+
   -var 'foo=bar'      Set a value for one of the input variables in the root
                       module of the configuration. Use this option more than
                       once to set more than one variable.
@@ -377,6 +469,10 @@ func getDataFromPlanfileReader(planReader *planfile.Reader, rootCall configs.Sta
 		name := variable.Name
 		v, ok := plan.VariableValues[name]
 		if !ok {
+			if variable.Ephemeral {
+				// Ephemeral variables are not saved in the plan file.
+				return cty.UnknownVal(variable.Type), nil
+			}
 			if variable.Required() {
 				// This should not happen...
 				return cty.DynamicVal, diags.Append(&hcl.Diagnostic{
@@ -439,3 +535,217 @@ func getStateFromBackend(b backend.Backend, workspace string) (*statefile.File,
 	stateFile := statemgr.Export(stateStore)
 	return stateFile, nil
 }
+
+// showRevealAddr is the address of a value for the -reveal option of the show
+// command: either an attribute of a resource instance, or a root module output
+// value, optionally followed by further attributes and indices.
+type showRevealAddr struct {
+	// Exactly one of Resource and Output is set.
+	Resource *addrs.AbsResourceInstance
+	Output   *addrs.AbsOutputValue
+
+	Path cty.Path
+	raw  string
+}
+
+func (a *showRevealAddr) String() string {
+	return a.raw
+}
+
+func parseShowRevealAddr(s string) (*showRevealAddr, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	invalid := func(detail string) tfdiags.Diagnostics {
+		return diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid value address",
+			fmt.Sprintf("Can't reveal %q: %s", s, detail),
+		))
+	}
+
+	traversal, hclDiags := hclsyntax.ParseTraversalAbs([]byte(s), "", hcl.InitialPos)
+	if hclDiags.HasErrors() {
+		return nil, invalid("the address must be a resource instance attribute, such as aws_db_instance.main.password, or a root module output value, such as output.token.")
+	}
+
+	ret := &showRevealAddr{raw: s}
+	var rest hcl.Traversal
+	if traversal.RootName() == "output" {
+		if len(traversal) < 2 {
+			return nil, invalid("an output value address must include the name of the output value.")
+		}
+		attr, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return nil, invalid("an output value address must include the name of the output value.")
+		}
+		addr := addrs.OutputValue{Name: attr.Name}.Absolute(addrs.RootModuleInstance)
+		ret.Output = &addr
+		rest = traversal[2:]
+	} else {
+		// The resource instance address ends before the first attribute of
+		// the resource, so we find its length from the shape of the
+		// address before parsing it.
+		i := 0
+		for i+1 < len(traversal) && showRevealStepName(traversal[i]) == "module" {
+			i += 2
+			if i < len(traversal) {
+				if _, ok := traversal[i].(hcl.TraverseIndex); ok {
+					i++
+				}
+			}
+		}
+		if i < len(traversal) && showRevealStepName(traversal[i]) == "data" {
+			i++
+		}
+		i += 2
+		if i < len(traversal) {
+			if _, ok := traversal[i].(hcl.TraverseIndex); ok {
+				i++
+			}
+		}
+		if i >= len(traversal) {
+			return nil, invalid("the address must include an attribute of the resource instance, such as aws_db_instance.main.password.")
+		}
+
+		addr, addrDiags := addrs.ParseAbsResourceInstance(traversal[:i])
+		if addrDiags.HasErrors() {
+			return nil, diags.Append(addrDiags)
+		}
+		ret.Resource = &addr
+		rest = traversal[i:]
+	}
+
+	for _, step := range rest {
+		switch step := step.(type) {
+		case hcl.TraverseAttr:
+			ret.Path = ret.Path.GetAttr(step.Name)
+		case hcl.TraverseIndex:
+			ret.Path = ret.Path.Index(step.Key)
+		default:
+			return nil, invalid("only attribute names and index keys are allowed after the object address.")
+		}
+	}
+	return ret, diags
+}
+
+func showRevealStepName(step hcl.Traverser) string {
+	switch step := step.(type) {
+	case hcl.TraverseRoot:
+		return step.Name
+	case hcl.TraverseAttr:
+		return step.Name
+	default:
+		return ""
+	}
+}
+
+// Value returns the value at the address, without any marks. The planned
+// value takes precedence over the prior state when showing a plan.
+func (a *showRevealAddr) Value(plan *plans.Plan, stateFile *statefile.File, schemas *tofu.Schemas) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var val cty.Value
+	var found bool
+	var err error
+	if a.Output != nil {
+		val, found, err = a.outputValue(plan, stateFile)
+	} else {
+		val, found, err = a.resourceValue(plan, stateFile, schemas)
+	}
+	if err != nil {
+		return cty.DynamicVal, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to decode value",
+			fmt.Sprintf("Can't reveal %s: %s.", a, err),
+		))
+	}
+	if !found {
+		return cty.DynamicVal, diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Value not found",
+			fmt.Sprintf("There is no object for %s in the given plan or state.", a),
+		))
+	}
+
+	val, _ = val.UnmarkDeep()
+	for _, step := range a.Path {
+		val, err = step.Apply(val)
+		if err != nil {
+			return cty.DynamicVal, diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid value address",
+				fmt.Sprintf("Can't reveal %s: %s.", a, err),
+			))
+		}
+	}
+	return val, diags
+}
+
+func (a *showRevealAddr) outputValue(plan *plans.Plan, stateFile *statefile.File) (cty.Value, bool, error) {
+	if plan != nil {
+		if oc := plan.Changes.OutputValue(*a.Output); oc != nil {
+			change, err := oc.Decode()
+			if err != nil {
+				return cty.DynamicVal, false, err
+			}
+			if change.Action == plans.Delete {
+				return change.Before, true, nil
+			}
+			return change.After, true, nil
+		}
+	}
+	if stateFile == nil || stateFile.State == nil {
+		return cty.DynamicVal, false, nil
+	}
+	ov := stateFile.State.OutputValue(*a.Output)
+	if ov == nil {
+		return cty.DynamicVal, false, nil
+	}
+	return ov.Value, true, nil
+}
+
+func (a *showRevealAddr) resourceValue(plan *plans.Plan, stateFile *statefile.File, schemas *tofu.Schemas) (cty.Value, bool, error) {
+	addr := *a.Resource
+	schema := func(provider addrs.Provider) (cty.Type, error) {
+		if schemas != nil {
+			if block, _ := schemas.ResourceTypeConfig(provider, addr.Resource.Resource.Mode, addr.Resource.Resource.Type); block != nil {
+				return block.ImpliedType(), nil
+			}
+		}
+		return cty.DynamicPseudoType, fmt.Errorf("no schema is available for %s", addr.Resource.Resource.Type)
+	}
+
+	if plan != nil {
+		if rc := plan.Changes.ResourceInstance(addr); rc != nil {
+			ty, err := schema(rc.ProviderAddr.Provider)
+			if err != nil {
+				return cty.DynamicVal, false, err
+			}
+			change, err := rc.Decode(ty)
+			if err != nil {
+				return cty.DynamicVal, false, err
+			}
+			if change.Action == plans.Delete {
+				return change.Before, true, nil
+			}
+			return change.After, true, nil
+		}
+	}
+	if stateFile == nil || stateFile.State == nil {
+		return cty.DynamicVal, false, nil
+	}
+	rs := stateFile.State.Resource(addr.ContainingResource())
+	is := stateFile.State.ResourceInstance(addr)
+	if rs == nil || is == nil || is.Current == nil {
+		return cty.DynamicVal, false, nil
+	}
+	ty, err := schema(rs.ProviderConfig.Provider)
+	if err != nil {
+		return cty.DynamicVal, false, err
+	}
+	obj, err := is.Current.Decode(ty)
+	if err != nil {
+		return cty.DynamicVal, false, err
+	}
+	return obj.Value, true, nil
+}
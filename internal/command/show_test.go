@@ -496,6 +496,70 @@ func TestShow_plan_json(t *testing.T) {
 	}
 }
 
+func TestShow_plan_jsonFilter(t *testing.T) {
+	planPath := showFixturePlanFile(t, plans.Create)
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-json-filter=hash=ami",
+		planPath,
+		"-no-color",
+	}
+	code := c.Run(args)
+	output := done(t)
+
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	if strings.Contains(got, `"ami":"bar"`) {
+		t.Errorf("filtered attribute value was not hashed\n%s", got)
+	}
+	// This is the SHA256 hash of the JSON string "bar".
+	want := `"ami":"sha256:4c293ff010a730f0972761331d1b5678478d425c2dc5cefd16d8f20059e497f3"`
+	if !strings.Contains(got, want) {
+		t.Errorf("missing hashed attribute value %s\n%s", want, got)
+	}
+}
+
+func TestShow_plan_jsonFilterInvalid(t *testing.T) {
+	planPath := showFixturePlanFile(t, plans.Create)
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-json-filter=redact=ami",
+		planPath,
+		"-no-color",
+	}
+	code := c.Run(args)
+	output := done(t)
+
+	if code != 1 {
+		t.Fatalf("unexpected exit status %d; want 1\ngot: %s", code, output.Stdout())
+	}
+	want := `unsupported filter "redact"`
+	if got := output.Stderr(); !strings.Contains(got, want) {
+		t.Errorf("missing error %q\n%s", want, got)
+	}
+}
+
 func TestShow_state(t *testing.T) {
 	originalState := testState()
 	root := originalState.RootModule()
@@ -1062,6 +1126,166 @@ func TestShow_withoutShowSensitiveArg(t *testing.T) {
 	}
 }
 
+func TestShow_reveal(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		answer  string
+		want    string
+		wantErr string
+	}{
+		"resource attribute": {
+			args:   []string{"-reveal=test_instance.foo.password"},
+			answer: "yes",
+			want:   `test_instance.foo.password = "secret"`,
+		},
+		"output value": {
+			args:   []string{"-reveal=output.foo"},
+			answer: "yes",
+			want:   `output.foo = "bar"`,
+		},
+		"json": {
+			args:   []string{"-json", "-reveal=test_instance.foo.password"},
+			answer: "yes",
+			want:   `{"address":"test_instance.foo.password","type":"string","value":"secret"}`,
+		},
+		"cancelled": {
+			args:    []string{"-reveal=test_instance.foo.password"},
+			answer:  "no",
+			wantErr: "",
+		},
+		"no attribute": {
+			args:    []string{"-reveal=test_instance.foo"},
+			wantErr: "the address must include an attribute of",
+		},
+		"unknown attribute": {
+			args:    []string{"-reveal=test_instance.foo.nope"},
+			wantErr: "Invalid value address",
+		},
+		"unknown resource": {
+			args:    []string{"-reveal=test_instance.bar.password"},
+			wantErr: "There is no object for test_instance.bar.password",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			defer testChdir(t, td)()
+
+			state := stateWithSensitiveValueForShow()
+			state.RootModule().SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: "foo",
+				}.Instance(addrs.NoKey),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar","ami":"baz","password":"secret"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+				addrs.NoKey,
+			)
+			testStateFileDefault(t, state)
+
+			view, done := testView(t)
+			c := &ShowCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(showFixtureSensitiveProvider()),
+					View:             view,
+					Ui:               cli.NewMockUi(),
+				},
+			}
+
+			if test.answer != "" {
+				defer testInputMap(t, map[string]string{"reveal": test.answer})()
+			}
+			code := c.Run(test.args)
+			output := done(t)
+
+			if test.want == "" {
+				if code != 1 {
+					t.Fatalf("unexpected exit status %d; want 1\ngot: %s", code, output.Stdout())
+				}
+				if !strings.Contains(output.Stderr(), test.wantErr) {
+					t.Fatalf("wrong error\ngot: %s\nwant: %s", output.Stderr(), test.wantErr)
+				}
+				if strings.Contains(output.Stdout(), "secret") {
+					t.Fatalf("value was revealed: %s", output.Stdout())
+				}
+				return
+			}
+			if code != 0 {
+				t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+			}
+			if got := strings.TrimSpace(output.Stdout()); got != test.want {
+				t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseShowRevealAddr(t *testing.T) {
+	tests := map[string]struct {
+		resource string
+		output   string
+		path     cty.Path
+		wantErr  bool
+	}{
+		`test_instance.foo.password`: {
+			resource: `test_instance.foo`,
+			path:     cty.GetAttrPath("password"),
+		},
+		`module.a["x"].module.b.data.test_data.foo[0].tags["k"]`: {
+			resource: `module.a["x"].module.b.data.test_data.foo[0]`,
+			path:     cty.GetAttrPath("tags").Index(cty.StringVal("k")),
+		},
+		`output.creds.password`: {
+			output: `output.creds`,
+			path:   cty.GetAttrPath("password"),
+		},
+		`output.token`: {
+			output: `output.token`,
+		},
+		`test_instance.foo`:       {wantErr: true},
+		`test_instance.foo[*].id`: {wantErr: true},
+		`output`:                  {wantErr: true},
+		`not an address`:          {wantErr: true},
+	}
+
+	for input, test := range tests {
+		t.Run(input, func(t *testing.T) {
+			got, diags := parseShowRevealAddr(input)
+			if test.wantErr {
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success")
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+
+			var gotResource, gotOutput string
+			if got.Resource != nil {
+				gotResource = got.Resource.String()
+			}
+			if got.Output != nil {
+				gotOutput = got.Output.String()
+			}
+			if gotResource != test.resource || gotOutput != test.output {
+				t.Errorf("wrong address %q %q; want %q %q", gotResource, gotOutput, test.resource, test.output)
+			}
+			if !got.Path.Equals(test.path) {
+				t.Errorf("wrong path %#v; want %#v", got.Path, test.path)
+			}
+		})
+	}
+}
+
 // stateWithSensitiveValueForShow return a state with an output value
 // marked as sensitive.
 func stateWithSensitiveValueForShow() *states.State {
@@ -0,0 +1,205 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateAnnotateCommand is a Command implementation that attaches an operator
+// note to resource instances in the state.
+type StateAnnotateCommand struct {
+	StateMeta
+}
+
+func (c *StateAnnotateCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var note string
+	var clearNote bool
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state annotate")
+	cmdFlags.StringVar(&note, "note", "", "note")
+	cmdFlags.BoolVar(&clearNote, "clear", false, "clear")
+	cmdFlags.StringVar(&c.backupPath, "backup", "-", "backup")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.StringVar(&c.statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one address is required.\n")
+		return cli.RunResultHelp
+	}
+
+	note = strings.TrimSpace(note)
+	switch {
+	case clearNote && note != "":
+		c.Ui.Error("The -note and -clear options are mutually exclusive.\n")
+		return cli.RunResultHelp
+	case !clearNote && note == "":
+		c.Ui.Error("Either a non-empty -note or -clear is required.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption()
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Get the state
+	stateMgr, err := c.State(enc)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-annotate"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		c.Ui.Error(errStateNotFound)
+		return 1
+	}
+
+	addrs, diags := c.lookupResourceInstanceAddr(state, false, args[0])
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if len(addrs) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid target address",
+			"No matching objects found. To view the available instances, use \"tofu state list\". Please modify the address to reference a specific instance.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	for _, addr := range addrs {
+		state.ResourceInstance(addr).Note = note
+		if clearNote {
+			c.Ui.Output("Removed note from " + addr.String())
+		} else {
+			c.Ui.Output("Annotated " + addr.String())
+		}
+	}
+
+	b, backendDiags := c.Backend(nil, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Get schemas, if possible, before writing state
+	var schemas *tofu.Schemas
+	if isCloudMode(b) {
+		var schemaDiags tfdiags.Diagnostics
+		schemas, schemaDiags = c.MaybeGetSchemas(state, nil)
+		diags = diags.Append(schemaDiags)
+	}
+
+	if err := stateMgr.WriteState(state); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateAnnotatePersist, err))
+		return 1
+	}
+	if err := stateMgr.PersistState(schemas); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateAnnotatePersist, err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf("Successfully updated %d resource instance(s).", len(addrs)))
+	return 0
+}
+
+func (c *StateAnnotateCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state annotate [options] ADDRESS
+
+  Attach an operator note to one or more resource instances in the OpenTofu
+  state, such as a ticket reference explaining why an instance must not be
+  changed.
+
+  Notes are only saved in the state. Whenever a plan proposes to change an
+  annotated resource instance, OpenTofu shows its note alongside the change.
+  A note is discarded along with its resource instance once the instance is
+  destroyed, including when it is replaced.
+
+  If you give the address of a resource that has "count" or "for_each" set,
+  or of an entire module, all of the matching instances are annotated.
+
+Options:
+
+  -note=TEXT              The note to attach, replacing any existing note.
+
+  -clear                  Remove the existing notes instead of setting one.
+
+  -backup=PATH            Path where OpenTofu should write the backup
+                          state.
+
+  -lock=false             Don't hold a state lock during the operation. This is
+                          dangerous if others might concurrently run commands
+                          against the same workspace.
+
+  -lock-timeout=0s        Duration to retry a state lock.
+
+  -state=PATH             Path to the state file to update. Defaults to the
+                          current workspace state.
+
+  -ignore-remote-version  Continue even if remote and local OpenTofu versions
+                          are incompatible. This may result in an unusable
+                          workspace, and should be used with extreme caution.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateAnnotateCommand) Synopsis() string {
+	return "Attach an operator note to instances in the state"
+}
+
+const errStateAnnotatePersist = `Error saving the state: %s
+
+The state was not saved. No notes were changed in the persisted
+state. No backup was created since no modification occurred. Please
+resolve the issue above and try again.`
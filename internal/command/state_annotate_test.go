@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func testStateAnnotateState() *states.State {
+	return states.BuildState(func(s *states.SyncState) {
+		for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1)} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: "foo",
+				}.Instance(key).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+				addrs.NoKey,
+			)
+		}
+	})
+}
+
+func testStateAnnotateCommand(t *testing.T) (*StateAnnotateCommand, *cli.MockUi) {
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	return &StateAnnotateCommand{
+		StateMeta{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+				View:             view,
+			},
+		},
+	}, ui
+}
+
+func TestStateAnnotate(t *testing.T) {
+	statePath := testStateFile(t, testStateAnnotateState())
+
+	c, ui := testStateAnnotateCommand(t)
+	args := []string{
+		"-state", statePath,
+		"-note", "ticket-1234 frozen until Q3",
+		"test_instance.foo[1]",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	state := testStateRead(t, statePath)
+	if got := state.RootModule().Resources["test_instance.foo"].Instances[addrs.IntKey(0)].Note; got != "" {
+		t.Errorf("unexpected note on test_instance.foo[0]: %q", got)
+	}
+	if got, want := state.RootModule().Resources["test_instance.foo"].Instances[addrs.IntKey(1)].Note, "ticket-1234 frozen until Q3"; got != want {
+		t.Errorf("wrong note on test_instance.foo[1]\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestStateAnnotate_clear(t *testing.T) {
+	state := testStateAnnotateState()
+	for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1)} {
+		state.RootModule().Resources["test_instance.foo"].Instances[key].Note = "old note"
+	}
+	statePath := testStateFile(t, state)
+
+	c, ui := testStateAnnotateCommand(t)
+	args := []string{
+		"-state", statePath,
+		"-clear",
+		"test_instance.foo",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	state = testStateRead(t, statePath)
+	for _, rs := range state.RootModule().Resources {
+		for key, is := range rs.Instances {
+			if is.Note != "" {
+				t.Errorf("note on %s wasn't removed: %q", rs.Addr.Instance(key), is.Note)
+			}
+		}
+	}
+}
+
+func TestStateAnnotate_noNote(t *testing.T) {
+	statePath := testStateFile(t, testStateAnnotateState())
+
+	c, ui := testStateAnnotateCommand(t)
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo",
+	}
+	if code := c.Run(args); code != cli.RunResultHelp {
+		t.Fatalf("wrong exit code %d; want %d\n\n%s", code, cli.RunResultHelp, ui.OutputWriter.String())
+	}
+}
+
+func TestStateAnnotate_missing(t *testing.T) {
+	statePath := testStateFile(t, testStateAnnotateState())
+
+	c, ui := testStateAnnotateCommand(t)
+	args := []string{
+		"-state", statePath,
+		"-note", "hello",
+		"test_instance.bar",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+}
@@ -47,10 +47,13 @@ func (c *StateListCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(nil, enc.State())
+	// Load the backend. Listing the state doesn't need the providers, so
+	// it must still work when they can't be installed.
+	var diags tfdiags.Diagnostics
+	b, backendDiags := c.Backend(&BackendOpts{ReadOnly: true}, enc.State())
+	diags = diags.Append(backendDiags)
 	if backendDiags.HasErrors() {
-		c.showDiagnostics(backendDiags)
+		c.showDiagnostics(diags)
 		return 1
 	}
 
@@ -80,12 +83,13 @@ func (c *StateListCommand) Run(args []string) int {
 	}
 
 	var addrs []addrs.AbsResourceInstance
-	var diags tfdiags.Diagnostics
+	var lookupDiags tfdiags.Diagnostics
 	if len(args) == 0 {
-		addrs, diags = c.lookupAllResourceInstanceAddrs(state)
+		addrs, lookupDiags = c.lookupAllResourceInstanceAddrs(state)
 	} else {
-		addrs, diags = c.lookupResourceInstanceAddrs(state, args...)
+		addrs, lookupDiags = c.lookupResourceInstanceAddrs(state, args...)
 	}
+	diags = diags.Append(lookupDiags)
 	if diags.HasErrors() {
 		c.showDiagnostics(diags)
 		return 1
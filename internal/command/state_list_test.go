@@ -40,6 +40,38 @@ func TestStateList(t *testing.T) {
 	}
 }
 
+// TestStateList_missingProvider checks that the state can be listed when a
+// provider in the dependency lock file isn't installed.
+func TestStateList_missingProvider(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testMissingProviderLockFile(t)
+
+	statePath := testStateFile(t, testState())
+
+	ui := cli.NewMockUi()
+	c := &StateListCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	expected := strings.TrimSpace(testStateListOutput) + "\n"
+	if actual := ui.OutputWriter.String(); actual != expected {
+		t.Fatalf("Expected:\n%q\n\nTo equal: %q", actual, expected)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Required plugins are not installed") {
+		t.Errorf("missing warning in\n%s", ui.ErrorWriter.String())
+	}
+}
+
 func TestStateListWithID(t *testing.T) {
 	state := testState()
 	statePath := testStateFile(t, state)
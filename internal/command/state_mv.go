@@ -107,7 +107,7 @@ func (c *StateMvCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateFromMgr, "state-mv"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -145,7 +145,7 @@ func (c *StateMvCommand) Run(args []string) int {
 		}
 
 		if c.stateLock {
-			stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+			stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
 			if diags := stateLocker.Lock(stateToMgr, "state-mv"); diags.HasErrors() {
 				c.showDiagnostics(diags)
 				return 1
@@ -73,7 +73,7 @@ func (c *StatePullCommand) Run(args []string) int {
 
 	if stateFile != nil { // we produce no output if the statefile is nil
 		var buf bytes.Buffer
-		err = statefile.Write(stateFile, &buf, encryption.StateEncryptionDisabled()) // Don't encrypt to stdout
+		err = statefile.Write(stateFile, &buf, encryption.PlaintextUnlessEnforced(enc.State())) // Don't encrypt to stdout unless enforced
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
 			return 1
@@ -76,7 +76,7 @@ func (c *StatePushCommand) Run(args []string) int {
 	}
 
 	// Read the state
-	srcStateFile, err := statefile.Read(r, encryption.StateEncryptionDisabled()) // Assume the given statefile is not encrypted
+	srcStateFile, err := statefile.Read(r, encryption.PlaintextUnlessEnforced(enc.State())) // Assume the given statefile is not encrypted unless enforced
 	if c, ok := r.(io.Closer); ok {
 		// Close the reader if possible right now since we're done with it.
 		c.Close()
@@ -115,7 +115,7 @@ func (c *StatePushCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "state-push"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -64,7 +64,7 @@ func (c *StateRmCommand) Run(args []string) int {
 	}
 
 	if c.stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "state-rm"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
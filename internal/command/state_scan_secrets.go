@@ -0,0 +1,399 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/secretscan"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/internal/tofumigrate"
+)
+
+// StateScanSecretsCommand is a Command implementation that scans the values
+// in the state that are not marked as sensitive for likely secrets.
+type StateScanSecretsCommand struct {
+	Meta
+	StateMeta
+}
+
+// stateScanSecretsFinding is a single candidate leak, as included in the
+// JSON output of the command.
+type stateScanSecretsFinding struct {
+	Address   string   `json:"address"`
+	Deposed   string   `json:"deposed,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Detectors []string `json:"detectors"`
+}
+
+func (c *StateScanSecretsCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+
+	var detectorNames FlagStringSlice
+	var patterns FlagStringKV
+	var jsonOutput bool
+	var threshold float64
+	var minLength int
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("state scan-secrets")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.StringVar(&c.Meta.statePath, "state", "", "path")
+	cmdFlags.Var(&detectorNames, "detector", "detector")
+	cmdFlags.Var(&patterns, "pattern", "name=regexp")
+	cmdFlags.Float64Var(&threshold, "entropy-threshold", secretscan.DefaultEntropyThreshold, "bits per character")
+	cmdFlags.IntVar(&minLength, "min-length", secretscan.DefaultMinLength, "length")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Streams.Eprintf("Error parsing command-line flags: %s\n", err.Error())
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Streams.Eprint("The state scan-secrets command expects no arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	detectors, err := c.detectors(detectorNames, patterns, threshold, minLength)
+	if err != nil {
+		c.Streams.Eprintf("Invalid detector configuration: %s\n", err)
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Streams.Eprintf("Error loading plugin path: %s\n", err)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption()
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(nil, enc.State())
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.Streams.Eprint(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// We expect the config dir to always be the cwd
+	cwd, err := os.Getwd()
+	if err != nil {
+		c.Streams.Eprintf("Error getting cwd: %s\n", err)
+		return 1
+	}
+
+	// Build the operation (required to get the schemas, which tell us which
+	// attributes the providers consider sensitive)
+	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq.AllowUnsetVariables = true
+	opReq.ConfigDir = cwd
+	var callDiags tfdiags.Diagnostics
+	opReq.RootCall, callDiags = c.rootModuleCall(opReq.ConfigDir)
+	if callDiags.HasErrors() {
+		c.showDiagnostics(callDiags)
+		return 1
+	}
+
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		c.Streams.Eprintf("Error initializing config loader: %s\n", err)
+		return 1
+	}
+
+	lr, _, ctxDiags := local.LocalRun(ctx, opReq)
+	if ctxDiags.HasErrors() {
+		c.View.Diagnostics(ctxDiags)
+		return 1
+	}
+
+	schemas, diags := lr.Core.Schemas(lr.Config, lr.InputState)
+	if diags.HasErrors() {
+		c.View.Diagnostics(diags)
+		return 1
+	}
+
+	// Get the state
+	env, err := c.Workspace()
+	if err != nil {
+		c.Streams.Eprintf("Error selecting workspace: %s\n", err)
+		return 1
+	}
+	stateMgr, err := b.StateMgr(env)
+	if err != nil {
+		c.Streams.Eprintln(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Streams.Eprintf("Failed to refresh state: %s\n", err)
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		c.Streams.Eprintln(errStateNotFound)
+		return 1
+	}
+	migratedState, migrateDiags := tofumigrate.MigrateStateProviderAddresses(lr.Config, state)
+	diags = diags.Append(migrateDiags)
+	if migrateDiags.HasErrors() {
+		c.View.Diagnostics(diags)
+		return 1
+	}
+	state = migratedState
+
+	findings, scanDiags := stateScanSecrets(state, schemas, detectors)
+	diags = diags.Append(scanDiags)
+	c.View.Diagnostics(diags)
+
+	if jsonOutput {
+		if findings == nil {
+			findings = []stateScanSecretsFinding{}
+		}
+		out, err := json.MarshalIndent(struct {
+			Findings []stateScanSecretsFinding `json:"findings"`
+		}{findings}, "", "  ")
+		if err != nil {
+			c.Streams.Eprintf("Failed to marshal findings to json: %s\n", err)
+			return 1
+		}
+		c.Streams.Println(string(out))
+	} else {
+		c.printFindings(findings)
+	}
+
+	if len(findings) != 0 {
+		return 2
+	}
+	return 0
+}
+
+// detectors builds the list of detectors from the command line options.
+func (c *StateScanSecretsCommand) detectors(names []string, patterns map[string]string, threshold float64, minLength int) ([]secretscan.Detector, error) {
+	var selected []string
+	for _, name := range names {
+		for _, n := range strings.Split(name, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				selected = append(selected, n)
+			}
+		}
+	}
+
+	var ret []secretscan.Detector
+	// Custom patterns replace the built-in detectors unless the user also
+	// selects some of them explicitly.
+	if len(patterns) == 0 || len(selected) != 0 {
+		builtin, err := secretscan.DefaultDetectors(selected, threshold, minLength)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, builtin...)
+	}
+	for _, name := range sortedKeys(patterns) {
+		d, err := secretscan.NewPatternDetector(name, patterns[name])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, d)
+	}
+	return ret, nil
+}
+
+func (c *StateScanSecretsCommand) printFindings(findings []stateScanSecretsFinding) {
+	if len(findings) == 0 {
+		c.Streams.Println("No candidate secrets found in values that are not marked as sensitive.")
+		return
+	}
+
+	c.Streams.Printf("Found %d candidate secret(s) in values that are not marked as sensitive:\n\n", len(findings))
+	for _, f := range findings {
+		addr := f.Address
+		if f.Deposed != "" {
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, f.Deposed)
+		}
+		c.Streams.Printf("  %s%s [%s]\n", addr, f.Path, strings.Join(f.Detectors, ", "))
+	}
+	c.Streams.Println(c.Colorize().Color(strings.TrimSpace(`
+[reset]
+The detectors are heuristics, so some of these values may not be secrets.
+Values that are secrets should be marked as sensitive in the configuration,
+for example with the "sensitive" argument of output and variable blocks or
+the sensitive() function, so that OpenTofu redacts them from its output.
+`)))
+}
+
+// stateScanSecrets runs the given detectors on all values in the given state
+// that are not marked as sensitive, either in the state itself or in the
+// schema of the resource type, and returns the findings ordered by address.
+//
+// Resource instance objects whose schema is not available are skipped with a
+// warning, because without the schema we cannot tell which of their
+// attributes the provider considers sensitive.
+func stateScanSecrets(state *states.State, schemas *tofu.Schemas, detectors []secretscan.Detector) ([]stateScanSecretsFinding, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var findings []stateScanSecretsFinding
+
+	add := func(addr string, deposed states.DeposedKey, val cty.Value) {
+		for _, f := range secretscan.ScanValue(val, detectors) {
+			finding := stateScanSecretsFinding{
+				Address:   addr,
+				Path:      tfdiags.FormatCtyPath(f.Path),
+				Detectors: f.Detectors,
+			}
+			if deposed != states.NotDeposed {
+				finding.Deposed = string(deposed)
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			provider := rs.ProviderConfig.Provider
+			schema, _ := schemas.ResourceTypeConfig(provider, rs.Addr.Resource.Mode, rs.Addr.Resource.Type)
+			if schema == nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Warning,
+					"Missing resource type schema",
+					fmt.Sprintf("No schema is available for %s in %s, so its instances were not scanned. Run \"tofu init\" to install the provider.", rs.Addr.Resource.Type, provider),
+				))
+				continue
+			}
+			ty := schema.ImpliedType()
+
+			for key, is := range rs.Instances {
+				addr := rs.Addr.Instance(key)
+				objs := make(map[states.DeposedKey]*states.ResourceInstanceObjectSrc, len(is.Deposed)+1)
+				if is.Current != nil {
+					objs[states.NotDeposed] = is.Current
+				}
+				for dk, obj := range is.Deposed {
+					objs[dk] = obj
+				}
+
+				for dk, src := range objs {
+					obj, err := src.Decode(ty)
+					if err != nil {
+						diags = diags.Append(tfdiags.Sourceless(
+							tfdiags.Warning,
+							"Failed to decode resource instance",
+							fmt.Sprintf("The state of %s could not be decoded using the current schema of %s, so it was not scanned: %s. Run \"tofu refresh\" to upgrade the object to the current schema.", addr, provider, err),
+						))
+						continue
+					}
+					val, pvm := obj.Value.UnmarkDeepWithPaths()
+					pvm = append(pvm, schema.ValueMarks(val, nil)...)
+					add(addr.String(), dk, val.MarkWithPaths(pvm))
+				}
+			}
+		}
+
+		for _, ov := range ms.OutputValues {
+			if ov.Sensitive {
+				continue
+			}
+			add(ov.Addr.String(), states.NotDeposed, ov.Value)
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		if a.Deposed != b.Deposed {
+			return a.Deposed < b.Deposed
+		}
+		return a.Path < b.Path
+	})
+	return findings, diags
+}
+
+func (c *StateScanSecretsCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state scan-secrets [options]
+
+  Scans the values in the OpenTofu state that are not marked as sensitive
+  for likely secrets and reports where they were found.
+
+  Each string in the attributes of the resource instances and in the
+  outputs is checked by a set of detectors, which recognize the formats of
+  well-known tokens and keys and strings with a high entropy. Values that
+  are marked as sensitive, either in the configuration or by the provider,
+  are skipped. The secrets themselves are never printed.
+
+  The detectors are heuristics, so not every reported value is necessarily
+  a secret, and secrets in unusual formats may be missed.
+
+  The command exits with status 0 if no candidate secrets were found, 2 if
+  some were found and 1 if an error occurred.
+
+Options:
+
+  -state=statefile        Path to a OpenTofu state file to use to look
+                          up OpenTofu-managed resources. By default it will
+                          use the state "terraform.tfstate" if it exists.
+
+  -detector=name          Only run the built-in detector with the given name.
+                          Use this option more than once, or separate names
+                          with commas, to run more than one detector. By
+                          default, all built-in detectors run. The built-in
+                          detectors are:
+                          ` + strings.Join(secretscan.DefaultDetectorNames(), "\n                          ") + `
+
+  -pattern=name=regexp    Add a detector with the given name that reports all
+                          values matching the given regular expression. If
+                          this option is used without -detector, only the
+                          custom detectors run.
+
+  -entropy-threshold=4.5  Minimum Shannon entropy in bits per character of
+                          the strings reported by the high-entropy detector.
+
+  -min-length=20          Minimum length of the strings reported by the
+                          high-entropy detector.
+
+  -json                   Produce the report in a machine-readable JSON format.
+
+  -var 'foo=bar'          Set a value for one of the input variables in the root
+                          module of the configuration. Use this option more than
+                          once to set more than one variable.
+
+  -var-file=filename      Load variable values from the given file, in addition
+                          to the default files terraform.tfvars and *.auto.tfvars.
+                          Use this option more than once to include more than one
+                          variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateScanSecretsCommand) Synopsis() string {
+	return "Scan the state for secrets not marked as sensitive"
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
@@ -18,9 +19,12 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonformat"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/opentofu/opentofu/internal/tofumigrate"
 )
 
@@ -65,10 +69,14 @@ func (c *StateShowCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(nil, enc.State())
+	// Load the backend. The state must still be readable when some of the
+	// providers can't be installed, so the resources that belong to them are
+	// shown without their schemas.
+	var diags tfdiags.Diagnostics
+	b, backendDiags := c.Backend(&BackendOpts{ReadOnly: true}, enc.State())
+	diags = diags.Append(backendDiags)
 	if backendDiags.HasErrors() {
-		c.showDiagnostics(backendDiags)
+		c.showDiagnostics(diags)
 		return 1
 	}
 
@@ -115,16 +123,35 @@ func (c *StateShowCommand) Run(args []string) int {
 
 	// Get the context (required to get the schemas)
 	lr, _, ctxDiags := local.LocalRun(ctx, opReq)
+	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
-		c.View.Diagnostics(ctxDiags)
+		c.View.Diagnostics(diags)
 		return 1
 	}
 
-	// Get the schemas from the context
-	schemas, diags := lr.Core.Schemas(lr.Config, lr.InputState)
-	if diags.HasErrors() {
-		c.View.Diagnostics(diags)
-		return 1
+	// Get the schemas from the context. The schemas of the providers that
+	// fail to load are left empty, and the resource is then shown with a
+	// schema implied by its attributes.
+	schemas, schemaDiags := lr.Core.Schemas(lr.Config, lr.InputState)
+	if schemaDiags.HasErrors() {
+		if schemas == nil {
+			c.View.Diagnostics(diags.Append(schemaDiags))
+			return 1
+		}
+		for _, diag := range schemaDiags {
+			if diag.Severity() != tfdiags.Error {
+				diags = diags.Append(diag)
+				continue
+			}
+			desc := diag.Description()
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				desc.Summary,
+				desc.Detail+"\n\nThe resource is shown as it is recorded in the state, so its attributes may not be formatted as they are by its provider.",
+			))
+		}
+	} else {
+		diags = diags.Append(schemaDiags)
 	}
 
 	// Get the state
@@ -177,6 +204,14 @@ func (c *StateShowCommand) Run(args []string) int {
 		addrs.NoKey,
 	)
 
+	if schemaDiags.HasErrors() {
+		if err := impliedResourceSchema(schemas, addr, rs.ProviderConfig.Provider, is.Current); err != nil {
+			c.View.Diagnostics(diags)
+			c.Streams.Eprintf("Failed to decode the resource without its schema: %s\n", err)
+			return 1
+		}
+	}
+
 	root, outputs, err := jsonstate.MarshalForRenderer(statefile.New(singleInstance, "", 0), schemas)
 	if err != nil {
 		c.Streams.Eprintf("Failed to marshal state to json: %s", err)
@@ -197,10 +232,64 @@ func (c *StateShowCommand) Run(args []string) int {
 		ShowSensitive:       showSensitive,
 	}
 
+	c.View.Diagnostics(diags)
 	renderer.RenderHumanState(jstate)
 	return 0
 }
 
+// impliedResourceSchema adds a schema for the resource type of the given
+// object to the given schemas, if its provider's schema isn't available. The
+// schema is implied by the attributes of the object, so it has no nested
+// blocks and every attribute is optional.
+func impliedResourceSchema(schemas *tofu.Schemas, addr addrs.AbsResourceInstance, provider addrs.Provider, obj *states.ResourceInstanceObjectSrc) error {
+	res := addr.Resource.Resource
+	if schema, _ := schemas.ResourceTypeConfig(provider, res.Mode, res.Type); schema != nil {
+		return nil
+	}
+	if obj.AttrsJSON == nil {
+		return fmt.Errorf("the state of %s uses a legacy format", addr)
+	}
+
+	ty, err := ctyjson.ImpliedType(obj.AttrsJSON)
+	if err != nil {
+		return err
+	}
+	if !ty.IsObjectType() {
+		return fmt.Errorf("the state of %s is not an object", addr)
+	}
+	block := &configschema.Block{
+		Attributes: make(map[string]*configschema.Attribute),
+	}
+	for name, attrTy := range ty.AttributeTypes() {
+		block.Attributes[name] = &configschema.Attribute{
+			Type:     attrTy,
+			Optional: true,
+		}
+	}
+
+	ps := schemas.Providers[provider]
+	if ps.ResourceTypes == nil {
+		ps.ResourceTypes = make(map[string]providers.Schema)
+	}
+	if ps.DataSources == nil {
+		ps.DataSources = make(map[string]providers.Schema)
+	}
+	schema := providers.Schema{
+		Version: int64(obj.SchemaVersion),
+		Block:   block,
+	}
+	switch res.Mode {
+	case addrs.ManagedResourceMode:
+		ps.ResourceTypes[res.Type] = schema
+	case addrs.DataResourceMode:
+		ps.DataSources[res.Type] = schema
+	default:
+		return fmt.Errorf("unsupported resource mode %s", res.Mode)
+	}
+	schemas.Providers[provider] = ps
+	return nil
+}
+
 func (c *StateShowCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state show [options] ADDRESS
@@ -6,11 +6,13 @@
 package command
 
 import (
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
@@ -350,6 +352,62 @@ resource "test_instance" "foo" {
 	}
 }
 
+// TestStateShow_missingProvider checks that a resource can be shown when its
+// provider is in the dependency lock file but isn't installed.
+func TestStateShow_missingProvider(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	testMissingProviderLockFile(t)
+
+	statePath := testStateFile(t, stateWithSensitiveValueForStateShow())
+
+	streams, done := terminal.StreamsForTesting(t)
+	c := &StateShowCommand{
+		Meta: Meta{
+			Streams: streams,
+			View:    views.NewView(streams),
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	// The warnings are shown before the resource.
+	stdout := output.Stdout()
+	expected := strings.TrimSpace(testStateShowOutput) + "\n"
+	if !strings.HasSuffix(stdout, expected) {
+		t.Fatalf("wrong output\n%s", stdout)
+	}
+	for _, want := range []string{"Required plugins are not installed", "Failed to load plugin schemas"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("missing warning %q in\n%s", want, stdout)
+		}
+	}
+}
+
+// testMissingProviderLockFile writes a dependency lock file that selects a
+// version of the "test" provider, which isn't installed, to the current
+// working directory.
+func testMissingProviderLockFile(t *testing.T) {
+	t.Helper()
+
+	locks := `
+provider "registry.opentofu.org/hashicorp/test" {
+  version = "1.0.0"
+}
+`
+	if err := os.WriteFile(".terraform.lock.hcl", []byte(locks), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // stateWithSensitiveValueForStateShow returns a state with a resource
 // instance.
 func stateWithSensitiveValueForStateShow() *states.State {
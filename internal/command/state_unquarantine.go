@@ -0,0 +1,209 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateUnquarantineCommand is a Command implementation that releases
+// resource instances that were quarantined after failing to apply too many
+// times in a row.
+type StateUnquarantineCommand struct {
+	StateMeta
+}
+
+func (c *StateUnquarantineCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state unquarantine")
+	cmdFlags.StringVar(&c.backupPath, "backup", "-", "backup")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.StringVar(&c.statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one address is required.\n")
+		return cli.RunResultHelp
+	}
+
+	var diags tfdiags.Diagnostics
+	target, targetDiags := addrs.ParseTargetStr(args[0])
+	diags = diags.Append(targetDiags)
+	if targetDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption()
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Get the state
+	stateMgr, err := c.State(enc)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-unquarantine"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		c.Ui.Error(errStateNotFound)
+		return 1
+	}
+
+	// The quarantined resource instances might not have any objects in the
+	// state, if creating them is what failed, so we match the address
+	// against the quarantined instances rather than the state's resources.
+	var released []*states.ApplyFailure
+	quarantined := state.QuarantinedResourceInstances()
+	for _, addr := range quarantined {
+		if target.Subject.TargetContains(addr) {
+			released = append(released, state.ApplyFailure(addr))
+		}
+	}
+	if len(released) == 0 {
+		detail := "There are no quarantined resource instances in the state."
+		if len(quarantined) > 0 {
+			var b strings.Builder
+			b.WriteString("No quarantined resource instances match the given address. The following resource instances are quarantined:\n")
+			for _, addr := range quarantined {
+				fmt.Fprintf(&b, "  - %s\n", addr)
+			}
+			detail = strings.TrimSpace(b.String())
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid target address",
+			detail,
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	for _, f := range released {
+		state.RemoveApplyFailure(f.Addr)
+		c.Ui.Output(fmt.Sprintf("Released %s, which failed to apply %d times", f.Addr, f.Count))
+	}
+
+	b, backendDiags := c.Backend(nil, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Get schemas, if possible, before writing state
+	var schemas *tofu.Schemas
+	if isCloudMode(b) {
+		var schemaDiags tfdiags.Diagnostics
+		schemas, schemaDiags = c.MaybeGetSchemas(state, nil)
+		diags = diags.Append(schemaDiags)
+	}
+
+	if err := stateMgr.WriteState(state); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateUnquarantinePersist, err))
+		return 1
+	}
+	if err := stateMgr.PersistState(schemas); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateUnquarantinePersist, err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf("Successfully released %d resource instance(s).", len(released)))
+	return 0
+}
+
+func (c *StateUnquarantineCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state unquarantine [options] ADDRESS
+
+  Release one or more resource instances that OpenTofu quarantined after
+  they failed to apply too many times in a row, as configured with the
+  -quarantine-after option of "tofu apply".
+
+  OpenTofu skips quarantined resource instances, and the resources that
+  depend on them, in every plan. Once you have fixed the cause of the
+  failures, release them so that the next plan includes them again. This
+  also resets the count of their failures.
+
+  If you give the address of a resource that has "count" or "for_each" set,
+  or of an entire module, all of the matching quarantined instances are
+  released.
+
+Options:
+
+  -backup=PATH            Path where OpenTofu should write the backup
+                          state.
+
+  -lock=false             Don't hold a state lock during the operation. This is
+                          dangerous if others might concurrently run commands
+                          against the same workspace.
+
+  -lock-timeout=0s        Duration to retry a state lock.
+
+  -state=PATH             Path to the state file to update. Defaults to the
+                          current workspace state.
+
+  -ignore-remote-version  Continue even if remote and local OpenTofu versions
+                          are incompatible. This may result in an unusable
+                          workspace, and should be used with extreme caution.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateUnquarantineCommand) Synopsis() string {
+	return "Release quarantined instances so that plans include them again"
+}
+
+const errStateUnquarantinePersist = `Error saving the state: %s
+
+The state was not saved. No resource instances were released in the
+persisted state. No backup was created since no modification occurred.
+Please resolve the issue above and try again.`
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func testStateUnquarantineState() *states.State {
+	state := testStateAnnotateState()
+	for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1)} {
+		state.SetApplyFailure(&states.ApplyFailure{
+			Addr:        mustResourceAddr("test_instance.foo").Resource.Instance(key).Absolute(addrs.RootModuleInstance),
+			Count:       3,
+			LastError:   "quota exceeded",
+			Quarantined: true,
+		})
+	}
+	// An instance that keeps failing to be created has no objects in the
+	// state, but can still be quarantined.
+	state.SetApplyFailure(&states.ApplyFailure{
+		Addr:        mustResourceAddr("test_instance.new").Resource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		Count:       5,
+		LastError:   "quota exceeded",
+		Quarantined: true,
+	})
+	return state
+}
+
+func testStateUnquarantineCommand(t *testing.T) (*StateUnquarantineCommand, *cli.MockUi) {
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	return &StateUnquarantineCommand{
+		StateMeta{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+				View:             view,
+			},
+		},
+	}, ui
+}
+
+func TestStateUnquarantine(t *testing.T) {
+	statePath := testStateFile(t, testStateUnquarantineState())
+
+	c, ui := testStateUnquarantineCommand(t)
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo[1]",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "Released test_instance.foo[1], which failed to apply 3 times"; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+
+	state := testStateRead(t, statePath)
+	got := state.QuarantinedResourceInstances()
+	want := []addrs.AbsResourceInstance{
+		mustResourceAddr("test_instance.foo").Resource.Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance),
+		mustResourceAddr("test_instance.new").Resource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong quarantined instances\ngot:  %s\nwant: %s", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("wrong quarantined instances\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestStateUnquarantine_notInState(t *testing.T) {
+	statePath := testStateFile(t, testStateUnquarantineState())
+
+	c, ui := testStateUnquarantineCommand(t)
+	args := []string{
+		"-state", statePath,
+		"test_instance.new",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	state := testStateRead(t, statePath)
+	if f := state.ApplyFailure(mustResourceAddr("test_instance.new").Resource.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)); f != nil {
+		t.Errorf("test_instance.new wasn't released: %#v", f)
+	}
+	if got := len(state.QuarantinedResourceInstances()); got != 2 {
+		t.Errorf("wrong number of quarantined instances %d; want 2", got)
+	}
+}
+
+func TestStateUnquarantine_wholeResource(t *testing.T) {
+	statePath := testStateFile(t, testStateUnquarantineState())
+
+	c, ui := testStateUnquarantineCommand(t)
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	state := testStateRead(t, statePath)
+	got := state.QuarantinedResourceInstances()
+	if len(got) != 1 || got[0].String() != "test_instance.new" {
+		t.Errorf("wrong quarantined instances: %s", got)
+	}
+}
+
+func TestStateUnquarantine_notQuarantined(t *testing.T) {
+	statePath := testStateFile(t, testStateUnquarantineState())
+
+	c, ui := testStateUnquarantineCommand(t)
+	args := []string{
+		"-state", statePath,
+		"test_instance.bar",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "test_instance.new"; !strings.Contains(got, want) {
+		t.Errorf("error doesn't list the quarantined instances\n%s", got)
+	}
+}
@@ -1042,7 +1042,9 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 			runConfig = state.Run.Config.ConfigUnderTest
 		}
 
-		evalCtx, ctxDiags := getEvalContextForTest(runner.States, runConfig, runner.Suite.GlobalVariables)
+		// The providers must be configured, and expanded, the same way as
+		// they were when the run block was executed.
+		evalCtx, ctxDiags := buildEvalContextForProviderConfigTransform(runner.States, state.Run, file, runConfig, runner.Suite.GlobalVariables)
 		diags = diags.Append(ctxDiags)
 
 		reset, configDiags := runConfig.TransformForTest(state.Run.Config, file.Config, evalCtx)
@@ -1218,8 +1220,8 @@ func parseAndApplyDefaultValues(unparsedVariables map[string]backend.UnparsedVar
 
 		// Even so the variable is declared, some of the fields could
 		// be empty and filled in via type default values.
-		if confVariable, ok := configVariables[name]; ok && confVariable.TypeDefaults != nil {
-			value.Value = confVariable.TypeDefaults.Apply(value.Value)
+		if confVariable, ok := configVariables[name]; ok {
+			value.Value = confVariable.ApplyTypeDefaults(value.Value)
 		}
 
 		inputs[name] = value
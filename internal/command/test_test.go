@@ -443,6 +443,37 @@ func TestTest_ProviderAlias(t *testing.T) {
 	}
 }
 
+// TestTest_ProviderForEach checks that provider configurations in test files
+// can use for_each, and that each instance is configured separately.
+func TestTest_ProviderForEach(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "provider_for_each")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+
+	view, done := testView(t)
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	if !strings.Contains(output.Stdout(), "1 passed, 0 failed") {
+		t.Errorf("wrong output\n%s", output.All())
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %s", provider.ResourceString())
+	}
+}
+
 func TestTest_ModuleDependencies(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath(path.Join("test", "with_setup_module")), td)
@@ -877,6 +908,10 @@ func TestTest_Modules(t *testing.T) {
 		"run_mod_output_in_provider_undefined_ref": {
 			code: 1,
 		},
+		"stub_module": {
+			expected: "main.tftest.hcl... pass\n  run \"default_name\"... pass\n  run \"custom_name\"... pass\n\nSuccess! 2 passed, 0 failed.\n",
+			code:     0,
+		},
 	}
 
 	for name, tc := range tcs {
@@ -1463,6 +1498,64 @@ digits, underscores, and dashes.
 	}
 }
 
+// TestTest_MockProviderSelf checks that the defaults of mock resources can
+// refer to the configuration of the mocked resource using self.
+func TestTest_MockProviderSelf(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("test/mock_provider_self"), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+
+	view, done := testView(t)
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	if !strings.Contains(output.Stdout(), "2 passed, 0 failed") {
+		t.Errorf("wrong output\n%s", output.All())
+	}
+}
+
+// TestTest_MockProviderGenerators checks that mock resources can generate a
+// different value of a computed attribute for each object.
+func TestTest_MockProviderGenerators(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("test/mock_provider_generators"), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	// The mocked provider is never configured, but the second run block
+	// needs it to upgrade the state of the objects that the first one
+	// created, as a real provider would.
+	provider.Provider.ConfigureProviderCalled = true
+
+	view, done := testView(t)
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output.All())
+	}
+	if !strings.Contains(output.Stdout(), "2 passed, 0 failed") {
+		t.Errorf("wrong output\n%s", output.All())
+	}
+}
+
 // TestTest_MockProviderValidation checks if tofu test runs proper validation for
 // mock_provider. Even if provider schema has required fields, tofu test should
 // ignore it completely, because the provider is mocked.
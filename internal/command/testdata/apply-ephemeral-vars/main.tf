@@ -0,0 +1,12 @@
+variable "token" {
+  type      = string
+  ephemeral = true
+}
+
+variable "foo" {
+  default = "bar"
+}
+
+resource "test_instance" "foo" {
+  ami = var.foo
+}
@@ -0,0 +1,9 @@
+assert {
+  condition     = output.replicas >= 2
+  error_message = "At least two replicas are required."
+}
+
+assert {
+  condition     = var.environment == "production"
+  error_message = "Expected production, got ${var.environment}."
+}
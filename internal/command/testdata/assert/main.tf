@@ -0,0 +1,8 @@
+variable "environment" {
+  type    = string
+  default = "staging"
+}
+
+output "replicas" {
+  value = 3
+}
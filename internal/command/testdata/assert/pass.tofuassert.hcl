@@ -0,0 +1,8 @@
+assert {
+  condition     = output.replicas >= 2
+  error_message = "At least two replicas are required."
+}
+
+assert {
+  condition = var.environment == "staging"
+}
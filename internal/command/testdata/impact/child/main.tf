@@ -0,0 +1,23 @@
+variable "input" {
+  type = string
+}
+
+variable "other" {
+  type = string
+}
+
+resource "test_instance" "from_input" {
+  ami = var.input
+}
+
+resource "test_instance" "from_other" {
+  ami = var.other
+}
+
+output "result" {
+  value = test_instance.from_input.id
+}
+
+output "unrelated" {
+  value = test_instance.from_other.id
+}
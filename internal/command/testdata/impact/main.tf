@@ -0,0 +1,29 @@
+variable "region" {
+  type = string
+}
+
+locals {
+  zone = "${var.region}a"
+}
+
+resource "test_instance" "zoned" {
+  ami = local.zone
+}
+
+resource "test_instance" "unrelated" {
+  ami = "bar"
+}
+
+module "child" {
+  source = "./child"
+  input  = local.zone
+  other  = "static"
+}
+
+resource "test_instance" "consumer" {
+  ami = module.child.result
+}
+
+output "zone" {
+  value = local.zone
+}
@@ -0,0 +1,2 @@
+resource "cloudflare_record" "www" {
+}
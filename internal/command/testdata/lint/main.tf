@@ -0,0 +1,20 @@
+variable "name" {
+  type = string
+}
+
+variable "unused" {
+  type    = string
+  default = ""
+}
+
+locals {
+  name = upper(var.name)
+}
+
+provider "test" {
+  version = "1.0.0"
+}
+
+resource "test_instance" "foo" {
+  ami = local.name
+}
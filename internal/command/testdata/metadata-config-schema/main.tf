@@ -0,0 +1,11 @@
+variable "name" {
+  type = string
+}
+
+resource "test_instance" "main" {
+  ami = var.name
+}
+
+output "id" {
+  value = test_instance.main.id
+}
@@ -0,0 +1,11 @@
+provider "registry.opentofu.org/hashicorp/null" {
+  version = "3.2.0"
+}
+
+provider "registry.opentofu.org/hashicorp/random" {
+  version = "3.6.1"
+}
+
+provider "registry.opentofu.org/hashicorp/test" {
+  version = "1.2.3"
+}
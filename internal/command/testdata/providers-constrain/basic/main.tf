@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    # The test provider is used for everything.
+    test = {
+      source  = "hashicorp/test"
+      version = ">= 1.0.0"
+    }
+    null = {
+      source = "hashicorp/null"
+    }
+  }
+}
+
+resource "test_instance" "foo" {
+}
+
+resource "random_id" "foo" {
+}
+
+resource "terraform_data" "foo" {
+}
@@ -0,0 +1,3 @@
+provider "registry.opentofu.org/hashicorp/test" {
+  version = "2.0.0-beta1"
+}
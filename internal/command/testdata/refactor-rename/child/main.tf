@@ -0,0 +1,7 @@
+variable "label" {
+  type = string
+}
+
+output "label_out" {
+  value = upper(var.label)
+}
@@ -0,0 +1,19 @@
+# The name prefix for all resources.
+variable "prefix" {
+  type = string
+}
+
+locals {
+  # Derived name
+  name = "${var.prefix}-app"
+}
+
+module "child" {
+  source = "./child"
+
+  label = local.name
+}
+
+output "child_label" {
+  value = module.child.label_out
+}
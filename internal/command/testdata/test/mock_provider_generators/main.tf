@@ -0,0 +1,18 @@
+variable "previous_ids" {
+  type    = list(string)
+  default = []
+}
+
+resource "test_resource" "primary" {
+  count = 3
+  value = "foo"
+}
+
+data "test_data_source" "lookup" {
+  count = 2
+  id    = test_resource.primary[count.index].id
+}
+
+output "ids" {
+  value = test_resource.primary[*].id
+}
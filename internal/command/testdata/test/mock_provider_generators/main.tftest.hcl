@@ -0,0 +1,59 @@
+mock_provider "test" {
+  mock_resource "test_resource" {
+    generate "id" {
+      type    = "regex"
+      pattern = "^res-[a-f0-9]{8}$"
+    }
+  }
+
+  mock_data "test_data_source" {
+    generate "value" {
+      type    = "cidr"
+      pool    = "10.0.0.0/16"
+      newbits = 8
+    }
+
+    generate "interrupt_count" {
+      type  = "sequence"
+      start = 100
+    }
+  }
+}
+
+run "apply" {
+  assert {
+    condition     = length(toset(test_resource.primary[*].id)) == 3
+    error_message = "Generated values are not unique"
+  }
+
+  assert {
+    condition     = alltrue([for id in test_resource.primary[*].id : can(regex("^res-[a-f0-9]{8}$", id))])
+    error_message = "Generated values don't match the pattern"
+  }
+
+  assert {
+    condition     = toset(data.test_data_source.lookup[*].value) == toset(["10.0.0.0/24", "10.0.1.0/24"])
+    error_message = "Unexpected CIDR blocks"
+  }
+
+  assert {
+    condition     = toset(data.test_data_source.lookup[*].interrupt_count) == toset([100, 101])
+    error_message = "Unexpected sequence"
+  }
+}
+
+run "reapply" {
+  variables {
+    previous_ids = run.apply.ids
+  }
+
+  assert {
+    condition     = output.ids == var.previous_ids
+    error_message = "Generated values of existing objects changed"
+  }
+
+  assert {
+    condition     = toset(data.test_data_source.lookup[*].value) == toset(["10.0.2.0/24", "10.0.3.0/24"])
+    error_message = "Generators restarted in a new run block"
+  }
+}
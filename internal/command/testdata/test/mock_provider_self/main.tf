@@ -0,0 +1,7 @@
+resource "test_resource" "primary" {
+  value = "foo"
+}
+
+data "test_data_source" "lookup" {
+  id = test_resource.primary.id
+}
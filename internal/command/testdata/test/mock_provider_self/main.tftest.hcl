@@ -0,0 +1,29 @@
+mock_provider "test" {
+  mock_resource "test_resource" {
+    defaults = {
+      id = "id-${self.value}"
+    }
+  }
+
+  mock_data "test_data_source" {
+    defaults = {
+      value = upper(self.id)
+    }
+  }
+}
+
+run "plan" {
+  command = plan
+
+  assert {
+    condition     = test_resource.primary.id == "id-foo"
+    error_message = "Unexpected computed value"
+  }
+}
+
+run "apply" {
+  assert {
+    condition     = data.test_data_source.lookup.value == "ID-FOO"
+    error_message = "Unexpected computed value of data source"
+  }
+}
@@ -0,0 +1,21 @@
+variable "regions" {
+  type    = set(string)
+  default = ["primary", "secondary"]
+}
+
+provider "test" {
+  alias    = "regional"
+  for_each = var.regions
+
+  resource_prefix = each.key
+}
+
+resource "test_resource" "primary" {
+  provider = test.regional["primary"]
+  value    = "primary"
+}
+
+resource "test_resource" "secondary" {
+  provider = test.regional["secondary"]
+  value    = "secondary"
+}
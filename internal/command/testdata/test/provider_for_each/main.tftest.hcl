@@ -0,0 +1,19 @@
+variables {
+  regions = ["primary", "secondary"]
+}
+
+// The provider configuration of the module is replaced by this one, which
+// has the same instances.
+provider "test" {
+  alias    = "regional"
+  for_each = toset(var.regions)
+
+  data_prefix = each.key
+}
+
+run "test" {
+  assert {
+    condition     = test_resource.primary.value == "primary" && test_resource.secondary.value == "secondary"
+    error_message = "bad values"
+  }
+}
@@ -0,0 +1,15 @@
+variable "name" {
+  type    = string
+  default = "app"
+}
+
+module "network" {
+  source = "./network"
+
+  name = var.name
+  cidr = "10.0.0.0/16"
+}
+
+resource "test_resource" "app" {
+  value = module.network.subnet_id
+}
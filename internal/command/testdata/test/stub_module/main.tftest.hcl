@@ -0,0 +1,29 @@
+stub_module {
+  target = module.network
+  outputs = {
+    subnet_id = "subnet-${var.name}-${replace(var.cidr, "/", "_")}"
+  }
+}
+
+run "default_name" {
+  assert {
+    condition     = test_resource.app.value == "subnet-app-10.0.0.0_16"
+    error_message = "The stubbed output was not computed from the module inputs"
+  }
+
+  assert {
+    condition     = module.network.cidr == null
+    error_message = "Outputs not declared in the stub should be null"
+  }
+}
+
+run "custom_name" {
+  variables {
+    name = "web"
+  }
+
+  assert {
+    condition     = test_resource.app.value == "subnet-web-10.0.0.0_16"
+    error_message = "The stubbed output was not computed from the module inputs"
+  }
+}
@@ -0,0 +1,19 @@
+variable "name" {
+  type = string
+}
+
+variable "cidr" {
+  type = string
+}
+
+resource "test_resource" "subnet" {
+  value = "${var.name}-${var.cidr}"
+}
+
+output "subnet_id" {
+  value = test_resource.subnet.id
+}
+
+output "cidr" {
+  value = test_resource.subnet.value
+}
@@ -0,0 +1,7 @@
+provider "test" {
+  version = "1.0.0"
+}
+
+resource "test_instance" "foo" {
+  ami = "bar"
+}
@@ -0,0 +1,10 @@
+provider "test" {
+}
+
+provider "test" {
+  alias = "unused"
+}
+
+resource "test_instance" "foo" {
+  ami = "bar"
+}
@@ -0,0 +1,14 @@
+variable "region" {
+  type    = string
+  default = "us-east-1"
+}
+
+variable "token" {
+  type      = string
+  default   = "default-token"
+  sensitive = true
+}
+
+variable "unset" {
+  type = string
+}
@@ -0,0 +1,15 @@
+variables {
+  region = "sa-east-1"
+}
+
+run "first" {
+  variables {
+    region = "ca-central-1"
+  }
+}
+
+run "second" {
+  variables {
+    region = run.first.region
+  }
+}
@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
@@ -62,16 +64,43 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
 
-	validateDiags := c.validate(ctx, dir, args.TestDirectory, args.NoTests)
-	diags = diags.Append(validateDiags)
+	if args.Strict {
+		c.Meta.StrictDeprecations = true
+	}
+
+	if !args.Watch {
+		return view.Results(diags.Append(c.validateOnce(ctx, dir, args)))
+	}
+
+	// In watch mode we keep the same configuration loader for the whole
+	// session, so that only the files that changed are parsed again.
+	ctx, done := c.InterruptibleContext(ctx)
+	defer done()
+	watcher := newFileWatcher([]string{dir}, true, configs.IsConfigFile)
+	for {
+		ret := view.Results(diags.Append(c.validateOnce(ctx, dir, args)))
+		diags = nil
+
+		changed := watcher.Wait(ctx)
+		if changed == nil {
+			return ret
+		}
+		c.forgetConfigFiles(changed)
+		for i, path := range changed {
+			changed[i] = c.normalizePath(path)
+		}
+		view.Changed(changed)
+	}
+}
+
+func (c *ValidateCommand) validateOnce(ctx context.Context, dir string, args *arguments.Validate) tfdiags.Diagnostics {
+	diags := c.validate(ctx, dir, args.TestDirectory, args.NoTests, args.UnusedProviders)
 
 	// Validating with dev overrides in effect means that the result might
 	// not be valid for a stable release, so we'll warn about that in case
 	// the user is trying to use "tofu validate" as a sort of pre-flight
 	// check before submitting a change.
-	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
-
-	return view.Results(diags)
+	return diags.Append(c.providerDevOverrideRuntimeWarnings())
 }
 
 func (c *ValidateCommand) GatherVariables(args *arguments.Vars) {
@@ -91,7 +120,7 @@ func (c *ValidateCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
-func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noTests bool) tfdiags.Diagnostics {
+func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noTests, unusedProviders bool) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	var cfg *configs.Config
 
@@ -124,6 +153,10 @@ func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noT
 
 	diags = diags.Append(validate(cfg))
 
+	if unusedProviders {
+		diags = diags.Append(unusedProviderConfigDiagnostics(cfg))
+	}
+
 	if noTests {
 		return diags
 	}
@@ -167,6 +200,40 @@ func (c *ValidateCommand) validate(ctx context.Context, dir, testDir string, noT
 	return diags
 }
 
+// unusedProviderConfigDiagnostics returns a warning for each provider
+// configuration in the given configuration tree that is never used, followed
+// by a summary of how many were found.
+func unusedProviderConfigDiagnostics(cfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	unused := cfg.UnusedProviderConfigs()
+	for _, pc := range unused {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unused provider configuration",
+			Detail: fmt.Sprintf(
+				"The provider configuration %s is not used by any resource or import block and is not passed to any module, so it can be removed.",
+				pc.Addr,
+			),
+			Subject: pc.Config.DeclRange.Ptr(),
+		})
+	}
+
+	if len(unused) > 0 {
+		noun := "configurations"
+		if len(unused) == 1 {
+			noun = "configuration"
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Unused provider configurations found",
+			fmt.Sprintf("Found %d unused provider %s in the configuration.", len(unused), noun),
+		))
+	}
+
+	return diags
+}
+
 func (c *ValidateCommand) Synopsis() string {
 	return "Check whether the configuration is valid"
 }
@@ -219,10 +286,18 @@ Options:
 
   -no-tests             If specified, OpenTofu will not validate test files.
 
+  -strict               Report uses of deprecated language features as errors
+                        rather than warnings, so that automation can prevent
+                        new uses of them.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
 
+  -unused-providers     Report provider configurations that are not used by any
+                        resource or import block and are not passed to any
+                        module, so that they can be removed.
+
   -var 'foo=bar'        Set a value for one of the input variables in the root
                         module of the configuration. Use this option more than
                         once to set more than one variable.
@@ -231,6 +306,11 @@ Options:
                         to the default files terraform.tfvars and *.auto.tfvars.
                         Use this option more than once to include more than one
                         variables file.
+
+  -watch                Keep running after validating the configuration, and
+                        validate it again whenever any of its files change,
+                        until interrupted. Only the changed files are parsed
+                        again.
 `
 	return strings.TrimSpace(helpText)
 }
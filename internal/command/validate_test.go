@@ -6,10 +6,12 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -99,6 +101,94 @@ func TestValidateFailingCommand(t *testing.T) {
 	}
 }
 
+func TestValidateCommand_deprecated(t *testing.T) {
+	output, code := setupTest(t, "validate-deprecated")
+	if code != 0 {
+		t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+	}
+	wantWarning := "Warning: Version constraints inside provider configuration blocks are deprecated"
+	if !strings.Contains(output.Stdout(), wantWarning) {
+		t.Fatalf("Missing warning string %q\n\n'%s'", wantWarning, output.Stdout())
+	}
+}
+
+func TestValidateCommand_deprecatedStrict(t *testing.T) {
+	output, code := setupTest(t, "validate-deprecated", "-strict")
+	if code != 1 {
+		t.Fatalf("Should have failed: %d\n\n%s", code, output.Stdout())
+	}
+	wantError := "Error: Version constraints inside provider configuration blocks are deprecated"
+	if !strings.Contains(output.Stderr(), wantError) {
+		t.Fatalf("Missing error string %q\n\n'%s'", wantError, output.Stderr())
+	}
+	wantDetail := "strict deprecation mode is enabled"
+	if !strings.Contains(output.Stderr(), wantDetail) {
+		t.Fatalf("Missing error detail %q\n\n'%s'", wantDetail, output.Stderr())
+	}
+}
+
+func TestValidateCommand_unusedProviders(t *testing.T) {
+	output, code := setupTest(t, "validate-unused-providers")
+	if code != 0 {
+		t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+	}
+	if got := output.Stdout(); strings.Contains(got, "Unused provider configuration") {
+		t.Fatalf("unexpected unused provider warning without -unused-providers\n\n'%s'", got)
+	}
+
+	output, code = setupTest(t, "validate-unused-providers", "-unused-providers")
+	if code != 0 {
+		t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+	}
+	for _, want := range []string{
+		"Warning: Unused provider configuration",
+		`provider["registry.opentofu.org/hashicorp/test"].unused is not used`,
+		"main.tf line 4",
+		"Found 1 unused provider configuration in the configuration.",
+	} {
+		if !strings.Contains(output.Stdout(), want) {
+			t.Errorf("Missing output string %q\n\n'%s'", want, output.Stdout())
+		}
+	}
+}
+
+func TestValidateCommand_forgetConfigFiles(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	path := filepath.Join(td, "main.tf")
+	if err := os.WriteFile(path, []byte(`locals { name = "a" }`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	defer done(t)
+	c := &ValidateCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	if diags := c.validate(context.Background(), td, "tests", true, false); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	if err := os.WriteFile(path, []byte(`locals { name = }`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The loader is reused, so the previously-parsed file is used until it
+	// has been forgotten.
+	if diags := c.validate(context.Background(), td, "tests", true, false); diags.HasErrors() {
+		t.Fatalf("unexpected errors before forgetting the file: %s", diags.Err())
+	}
+	c.forgetConfigFiles([]string{path})
+	if diags := c.validate(context.Background(), td, "tests", true, false); !diags.HasErrors() {
+		t.Fatal("succeeded after changing the file; want errors")
+	}
+}
+
 func TestValidateFailingCommandMissingQuote(t *testing.T) {
 	output, code := setupTest(t, "validate-invalid/missing_quote")
 
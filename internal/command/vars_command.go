@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// VarsCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type VarsCommand struct {
+	Meta
+}
+
+func (c *VarsCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *VarsCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] vars <subcommand> [options] [args]
+
+  This command has subcommands for inspecting the values of the input
+  variables of the root module.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarsCommand) Synopsis() string {
+	return "Input variable related commands"
+}
@@ -0,0 +1,272 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// VarsExplainCommand is a Command implementation that shows every source
+// that sets a value for a root module input variable, in order of
+// precedence.
+type VarsExplainCommand struct {
+	Meta
+}
+
+// varsExplainSource is one source of a value for the variable that
+// VarsExplainCommand explains.
+type varsExplainSource struct {
+	// Desc describes the source, like "-var option".
+	Desc string
+
+	// Value is the value the source sets, formatted for display.
+	Value string
+}
+
+func (c *VarsExplainCommand) Run(args []string) int {
+	var testDir, testFile, runName string
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("vars explain")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.StringVar(&testDir, "test-directory", configs.DefaultTestDirectory, "test-directory")
+	cmdFlags.StringVar(&testFile, "test-file", "", "test-file")
+	cmdFlags.StringVar(&runName, "run", "", "run")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one argument expected: the name of the input variable.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	if runName != "" && testFile == "" {
+		c.Ui.Error("The -run option requires the -test-file option.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	name := strings.TrimPrefix(args[0], "var.")
+
+	var diags tfdiags.Diagnostics
+	mod, moreDiags := c.loadSingleModule(".", configs.SelectiveLoadAll)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	decl, ok := mod.Variables[name]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Undeclared input variable",
+			fmt.Sprintf("The root module does not declare a variable named %q.", name),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	var sources []varsExplainSource
+	if !decl.Required() {
+		sources = append(sources, varsExplainSource{
+			Desc:  fmt.Sprintf("default value (%s)", varsExplainPos(decl.DeclRange)),
+			Value: c.varsExplainValue(decl, decl.Default),
+		})
+	}
+
+	addSource := func(source string, values map[string]backend.UnparsedVariableValue) {
+		v, ok := values[name]
+		if !ok {
+			return
+		}
+		val, valDiags := v.ParseVariableValue(decl.ParsingMode)
+		diags = diags.Append(valDiags)
+		desc := source
+		switch {
+		case val.SourceType == tofu.ValueFromEnvVar:
+			desc = "environment variable " + VarEnvPrefix + name
+		case val.SourceRange.Filename != "":
+			desc = fmt.Sprintf("%s (line %d)", source, val.SourceRange.Start.Line)
+		}
+		formatted := "(invalid value)"
+		if !valDiags.HasErrors() {
+			formatted = c.varsExplainValue(decl, val.Value)
+		}
+		sources = append(sources, varsExplainSource{
+			Desc:  desc,
+			Value: formatted,
+		})
+	}
+	diags = diags.Append(c.visitVariableSources(addSource))
+
+	if testFile != "" {
+		diags = diags.Append(c.visitVarsFromDir(testDir, addSource))
+		sources = append(sources, c.varsExplainTestSources(decl, testFile, runName, &diags)...)
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	if len(sources) == 0 {
+		c.Ui.Output(fmt.Sprintf("No source sets a value for var.%s. OpenTofu will prompt for it, or fail if input is disabled.", name))
+		return 0
+	}
+
+	width := 0
+	for _, s := range sources {
+		width = max(width, len(s.Desc))
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Sources of var.%s, from lowest to highest precedence:\n\n", name)
+	for i, s := range sources {
+		line := fmt.Sprintf("%-*s = %s", width, s.Desc, s.Value)
+		if i == len(sources)-1 {
+			buf.WriteString(c.Colorize().Color(fmt.Sprintf("[bold]> %s[reset]\n", line)))
+			continue
+		}
+		fmt.Fprintf(&buf, "  %s\n", line)
+	}
+	buf.WriteString("\nOpenTofu uses the value from the source marked with \">\".")
+	c.Ui.Output(buf.String())
+	return 0
+}
+
+// varsExplainTestSources returns the sources of a value for the given
+// variable in the given test file and, if runName isn't empty, in the run
+// block of that name.
+func (c *VarsExplainCommand) varsExplainTestSources(decl *configs.Variable, testFile, runName string, diags *tfdiags.Diagnostics) []varsExplainSource {
+	loader, err := c.initConfigLoader()
+	if err != nil {
+		*diags = diags.Append(err)
+		return nil
+	}
+	file, hclDiags := loader.Parser().LoadTestFile(testFile)
+	*diags = diags.Append(hclDiags)
+	if file == nil {
+		return nil
+	}
+
+	var sources []varsExplainSource
+	if expr, ok := file.Variables[decl.Name]; ok {
+		sources = append(sources, varsExplainSource{
+			Desc:  fmt.Sprintf("variables block (%s)", varsExplainPos(expr.Range())),
+			Value: c.varsExplainExpr(decl, expr),
+		})
+	}
+	if runName == "" {
+		return sources
+	}
+	for _, run := range file.Runs {
+		if run.Name != runName {
+			continue
+		}
+		if expr, ok := run.Variables[decl.Name]; ok {
+			sources = append(sources, varsExplainSource{
+				Desc:  fmt.Sprintf("run %q (%s)", runName, varsExplainPos(expr.Range())),
+				Value: c.varsExplainExpr(decl, expr),
+			})
+		}
+		return sources
+	}
+	*diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Unknown run block",
+		fmt.Sprintf("The test file %s does not contain a run block named %q.", testFile, runName),
+	))
+	return sources
+}
+
+// varsExplainExpr formats the value of an expression from a test file.
+// These expressions can refer to the outputs of earlier run blocks, so
+// when the value isn't available until the test runs we show the source
+// code of the expression instead.
+func (c *VarsExplainCommand) varsExplainExpr(decl *configs.Variable, expr hcl.Expression) string {
+	val, hclDiags := expr.Value(nil)
+	if !hclDiags.HasErrors() {
+		return c.varsExplainValue(decl, val)
+	}
+	rng := expr.Range()
+	if f, ok := c.configSources()[rng.Filename]; ok && f != nil {
+		return string(rng.SliceBytes(f.Bytes))
+	}
+	return "(known only during the test)"
+}
+
+// varsExplainValue formats the given value of the given variable for
+// display, hiding the values of sensitive variables.
+func (c *VarsExplainCommand) varsExplainValue(decl *configs.Variable, val cty.Value) string {
+	switch {
+	case decl.Sensitive:
+		return "(sensitive value)"
+	case !val.IsWhollyKnown() || val.ContainsMarked():
+		return "(known only during the test)"
+	}
+	return strings.TrimSpace(string(hclwrite.TokensForValue(val).Bytes()))
+}
+
+// varsExplainPos returns the filename and line of the start of the given
+// range.
+func varsExplainPos(rng hcl.Range) string {
+	return fmt.Sprintf("%s line %d", rng.Filename, rng.Start.Line)
+}
+
+func (c *VarsExplainCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] vars explain [options] NAME
+
+  Shows every source that sets a value for the given root module input
+  variable, from the lowest to the highest precedence, and which value
+  OpenTofu uses.
+
+  The sources include the default value from the variable declaration,
+  TF_VAR_ environment variables, automatically-loaded variable definitions
+  files, the variable definitions files of the selected workspace and the
+  -var and -var-file options given to this command.
+
+Options:
+
+  -var 'foo=bar'        Set a value for one of the input variables in the root
+                        module of the configuration, as for "tofu plan".
+                        Use this option more than once to set more than one
+                        variable.
+
+  -var-file=filename    Load variable values from the given file, as for
+                        "tofu plan". Use this option more than once to
+                        include more than one variables file.
+
+  -test-file=path       Also include the sources used by "tofu test" for the
+                        given test file: the automatically-loaded variable
+                        definitions files of the test directory and the
+                        variables block of the test file.
+
+  -run=name             With -test-file, also include the variables block of
+                        the run block with the given name.
+
+  -test-directory=path  Set the test directory whose variable definitions
+                        files are included with -test-file. Defaults to
+                        "tests".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarsExplainCommand) Synopsis() string {
+	return "Show the sources of the value of an input variable"
+}
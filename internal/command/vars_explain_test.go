@@ -0,0 +1,148 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func TestVarsExplain(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("vars-explain"), td)
+	defer testChdir(t, td)()
+	t.Setenv("TF_VAR_region", "us-west-2")
+
+	ui := cli.NewMockUi()
+	c := &VarsExplainCommand{
+		Meta: Meta{
+			Ui:    ui,
+			Color: false,
+		},
+	}
+
+	args := []string{"-var-file=prod.tfvars", "-var", "region=eu-south-1", "var.region"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := `Sources of var.region, from lowest to highest precedence:
+
+  default value (main.tf line 1)     = "us-east-1"
+  environment variable TF_VAR_region = "us-west-2"
+  terraform.tfvars (line 1)          = "eu-central-1"
+  override.auto.tfvars (line 2)      = "eu-west-2"
+  -var-file=prod.tfvars (line 1)     = "eu-north-1"
+> -var option                        = "eu-south-1"
+
+OpenTofu uses the value from the source marked with ">".
+`
+	if diff := cmp.Diff(want, ui.OutputWriter.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func TestVarsExplain_test(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("vars-explain"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &VarsExplainCommand{
+		Meta: Meta{
+			Ui:    ui,
+			Color: false,
+		},
+	}
+
+	args := []string{"-test-file=tests/main.tftest.hcl", "-run=second", "region"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := `Sources of var.region, from lowest to highest precedence:
+
+  default value (main.tf line 1)                 = "us-east-1"
+  terraform.tfvars (line 1)                      = "eu-central-1"
+  override.auto.tfvars (line 2)                  = "eu-west-2"
+  tests/override.auto.tfvars (line 1)            = "ap-south-1"
+  variables block (tests/main.tftest.hcl line 2) = "sa-east-1"
+> run "second" (tests/main.tftest.hcl line 13)   = run.first.region
+
+OpenTofu uses the value from the source marked with ">".
+`
+	if diff := cmp.Diff(want, ui.OutputWriter.String()); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
+
+func TestVarsExplain_sensitive(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("vars-explain"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &VarsExplainCommand{
+		Meta: Meta{
+			Ui:    ui,
+			Color: false,
+		},
+	}
+
+	if code := c.Run([]string{"token"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	got := ui.OutputWriter.String()
+	if strings.Contains(got, "auto-token") {
+		t.Errorf("output contains sensitive value\n%s", got)
+	}
+	if want := "> override.auto.tfvars (line 3)  = (sensitive value)"; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestVarsExplain_noSources(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("vars-explain"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &VarsExplainCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"unset"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "No source sets a value for var.unset"; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestVarsExplain_undeclared(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("vars-explain"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	c := &VarsExplainCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"missing"}); code != 1 {
+		t.Fatalf("unexpected exit code %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "Undeclared input variable"; !strings.Contains(got, want) {
+		t.Errorf("error output does not contain %q\n%s", want, got)
+	}
+}
@@ -29,6 +29,7 @@ func newJSONHook(view *JSONView) *jsonHook {
 	return &jsonHook{
 		view:      view,
 		applying:  make(map[string]applyProgress),
+		calls:     make(map[string]providerCall),
 		timeNow:   time.Now,
 		timeAfter: time.After,
 	}
@@ -43,6 +44,8 @@ type jsonHook struct {
 	// Concurrent map of resource addresses to allow the sequence of pre-apply,
 	// progress, and post-apply messages to share data about the resource
 	applying map[string]applyProgress
+	// Provider calls that are in progress, by resource address
+	calls map[string]providerCall
 
 	// Mockable functions for testing the progress timer goroutine
 	timeNow   func() time.Time
@@ -67,6 +70,26 @@ type applyProgress struct {
 	elapsed chan time.Duration
 }
 
+type providerCall struct {
+	method string
+	start  time.Time
+}
+
+func (h *jsonHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.applyingLock.Lock()
+	defer h.applyingLock.Unlock()
+	h.calls[addr.String()] = providerCall{
+		method: method,
+		start:  h.timeNow().Round(time.Second),
+	}
+}
+
+func (h *jsonHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.applyingLock.Lock()
+	defer h.applyingLock.Unlock()
+	delete(h.calls, addr.String())
+}
+
 func (h *jsonHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
 	if action != plans.NoOp {
 		idKey, idValue := format.ObjectValueIDOrName(priorState)
@@ -101,8 +124,15 @@ func (h *jsonHook) applyingHeartbeat(progress applyProgress) {
 		case <-h.timeAfter(heartbeatInterval):
 		}
 
-		elapsed := h.timeNow().Round(time.Second).Sub(progress.start)
-		h.view.Hook(json.NewApplyProgress(progress.addr, progress.action, elapsed))
+		now := h.timeNow().Round(time.Second)
+		elapsed := now.Sub(progress.start)
+		var call *json.ProviderCall
+		h.applyingLock.Lock()
+		if c, ok := h.calls[progress.addr.String()]; ok {
+			call = json.NewProviderCall(c.method, now.Sub(c.start))
+		}
+		h.applyingLock.Unlock()
+		h.view.Hook(json.NewApplyProgress(progress.addr, progress.action, elapsed, call))
 		progress.elapsed <- elapsed
 	}
 }
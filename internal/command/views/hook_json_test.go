@@ -75,6 +75,9 @@ func TestJSONHook_create(t *testing.T) {
 	elapsed := <-elapsedChan
 	testDurationEqual(t, 10*time.Second, elapsed)
 
+	// The next progress message attributes the time to the provider call
+	hook.PreProviderCall(addr, "ApplyResourceChange")
+
 	// Travel 10s into the future, notify the progress goroutine, and wait
 	// for execution via 'elapsed' progress
 	nowMu.Lock()
@@ -89,6 +92,7 @@ func TestJSONHook_create(t *testing.T) {
 	now = now.Add(2 * time.Second)
 	nowMu.Unlock()
 
+	hook.PostProviderCall(addr, "ApplyResourceChange")
 	action, err = hook.PostApply(addr, states.CurrentGen, plannedNewState, nil)
 	testHookReturnValues(t, action, err)
 
@@ -172,7 +176,11 @@ func TestJSONHook_create(t *testing.T) {
 			"hook": map[string]interface{}{
 				"action":          string("create"),
 				"elapsed_seconds": float64(20),
-				"resource":        wantResource,
+				"provider_call": map[string]interface{}{
+					"method":          "ApplyResourceChange",
+					"elapsed_seconds": float64(10),
+				},
+				"resource": wantResource,
 			},
 		},
 		{
@@ -32,6 +32,7 @@ func NewUiHook(view *View) *UiHook {
 		view:            view,
 		periodicUiTimer: defaultPeriodicUiTimer,
 		resources:       make(map[string]uiResourceState),
+		providerCalls:   make(map[string]uiProviderCall),
 	}
 }
 
@@ -52,6 +53,7 @@ type UiHook struct {
 
 	resourcesLock sync.Mutex
 	resources     map[string]uiResourceState
+	providerCalls map[string]uiProviderCall
 }
 
 var _ tofu.Hook = (*UiHook)(nil)
@@ -68,6 +70,14 @@ type uiResourceState struct {
 	done chan struct{} // used to coordinate tests
 }
 
+// uiProviderCall tracks the provider call that is currently in progress for a
+// single resource, so that the periodic progress messages can attribute the
+// time spent to it.
+type uiProviderCall struct {
+	Method string
+	Start  time.Time
+}
+
 // uiResourceOp is an enum for operations on a resource
 type uiResourceOp byte
 
@@ -183,16 +193,40 @@ func (h *UiHook) stillApplying(state uiResourceState) {
 			idSuffix = fmt.Sprintf("%s=%s, ", state.IDKey, truncateId(state.IDValue, maxIdLen))
 		}
 
+		callSuffix := ""
+		h.resourcesLock.Lock()
+		call, ok := h.providerCalls[state.DispAddr]
+		h.resourcesLock.Unlock()
+		if ok {
+			callSuffix = fmt.Sprintf(", %s running for %s", call.Method, time.Now().Round(time.Second).Sub(call.Start))
+		}
+
 		h.println(fmt.Sprintf(
-			h.view.colorize.Color("[reset][bold]%s: %s [%s%s elapsed][reset]"),
+			h.view.colorize.Color("[reset][bold]%s: %s [%s%s elapsed%s][reset]"),
 			state.DispAddr,
 			msg,
 			idSuffix,
 			time.Now().Round(time.Second).Sub(state.Start),
+			callSuffix,
 		))
 	}
 }
 
+func (h *UiHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.resourcesLock.Lock()
+	defer h.resourcesLock.Unlock()
+	h.providerCalls[addr.String()] = uiProviderCall{
+		Method: method,
+		Start:  time.Now().Round(time.Second),
+	}
+}
+
+func (h *UiHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.resourcesLock.Lock()
+	defer h.resourcesLock.Unlock()
+	delete(h.providerCalls, addr.String())
+}
+
 func (h *UiHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, applyerr error) (tofu.HookAction, error) {
 	id := addr.String()
 
@@ -144,6 +144,51 @@ test_instance\.foo: Still modifying... \[id=test, \ds elapsed\]
 	}
 }
 
+// Test that the periodic messages attribute the time spent to the provider call
+// that is in progress.
+func TestUiHookPreApply_periodicTimerProviderCall(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	h := NewUiHook(view)
+	h.periodicUiTimer = 1 * time.Second
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	plannedNewState := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.UnknownVal(cty.String),
+	})
+
+	_, err := h.PreApply(addr, states.CurrentGen, plans.Create, cty.NullVal(plannedNewState.Type()), plannedNewState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.PreProviderCall(addr, "ApplyResourceChange")
+
+	time.Sleep(1500 * time.Millisecond)
+
+	h.PostProviderCall(addr, "ApplyResourceChange")
+	if _, ok := h.providerCalls[addr.String()]; ok {
+		t.Errorf("provider call is still tracked after PostProviderCall")
+	}
+
+	// stop the background writer
+	uiState := h.resources[addr.String()]
+	close(uiState.DoneCh)
+	<-uiState.done
+
+	expectedRegexp := `test_instance\.foo: Creating...
+test_instance\.foo: Still creating... \[\ds elapsed, ApplyResourceChange running for \ds\]
+`
+	output := done(t).Stdout()
+	if matched, _ := regexp.MatchString(expectedRegexp, output); !matched {
+		t.Fatalf("Output didn't match.\nExpected: %q\nGiven: %q", expectedRegexp, output)
+	}
+}
+
 // Test the PreApply hook's destroy path, including passing a deposed key as
 // the gen argument.
 func TestUiHookPreApply_destroy(t *testing.T) {
@@ -56,11 +56,28 @@ func NewApplyStart(addr addrs.AbsResourceInstance, action plans.Action, idKey st
 // ApplyProgress: currently triggered by a timer started on PreApply. In
 // future, this might also be triggered by provider progress reporting.
 type applyProgress struct {
-	Resource   ResourceAddr `json:"resource"`
-	Action     ChangeAction `json:"action"`
-	Elapsed    float64      `json:"elapsed_seconds"`
-	actionVerb string
-	elapsed    time.Duration
+	Resource     ResourceAddr  `json:"resource"`
+	Action       ChangeAction  `json:"action"`
+	Elapsed      float64       `json:"elapsed_seconds"`
+	ProviderCall *ProviderCall `json:"provider_call,omitempty"`
+	actionVerb   string
+	elapsed      time.Duration
+}
+
+// ProviderCall describes the provider call that is in progress for a
+// resource, so that the time spent applying it can be attributed to the
+// provider. It is only included in the hook object: the message keeps the
+// same format whether or not a provider call is in progress.
+type ProviderCall struct {
+	Method  string  `json:"method"`
+	Elapsed float64 `json:"elapsed_seconds"`
+}
+
+func NewProviderCall(method string, elapsed time.Duration) *ProviderCall {
+	return &ProviderCall{
+		Method:  method,
+		Elapsed: elapsed.Seconds(),
+	}
 }
 
 var _ Hook = (*applyProgress)(nil)
@@ -73,13 +90,16 @@ func (h *applyProgress) String() string {
 	return fmt.Sprintf("%s: Still %s... [%s elapsed]", h.Resource.Addr, h.actionVerb, h.elapsed)
 }
 
-func NewApplyProgress(addr addrs.AbsResourceInstance, action plans.Action, elapsed time.Duration) Hook {
+// NewApplyProgress returns an apply progress message. The provider call is
+// nil unless a call to the provider is in progress.
+func NewApplyProgress(addr addrs.AbsResourceInstance, action plans.Action, elapsed time.Duration, call *ProviderCall) Hook {
 	return &applyProgress{
-		Resource:   newResourceAddr(addr),
-		Action:     changeAction(action),
-		Elapsed:    elapsed.Seconds(),
-		actionVerb: progressActionVerb(action),
-		elapsed:    elapsed,
+		Resource:     newResourceAddr(addr),
+		Action:       changeAction(action),
+		Elapsed:      elapsed.Seconds(),
+		ProviderCall: call,
+		actionVerb:   progressActionVerb(action),
+		elapsed:      elapsed,
 	}
 }
 
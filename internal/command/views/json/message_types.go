@@ -19,6 +19,8 @@ const (
 	MessageChangeSummary MessageType = "change_summary"
 	MessageOutputs       MessageType = "outputs"
 
+	MessageUnknownExplanation MessageType = "unknown_explanation"
+
 	// Hook-driven messages
 	MessageApplyStart        MessageType = "apply_start"
 	MessageApplyProgress     MessageType = "apply_progress"
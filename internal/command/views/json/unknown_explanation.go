@@ -0,0 +1,55 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"github.com/opentofu/opentofu/internal/plans/explain"
+)
+
+// UnknownExplanation explains why a value in a plan is unknown until apply,
+// as produced by "tofu plan -explain-unknown".
+type UnknownExplanation struct {
+	// Subject describes the unknown value, such as "aws_instance.a.ami".
+	Subject string `json:"subject"`
+
+	// Reason explains why the value is unknown, if it isn't unknown only
+	// because of its causes.
+	Reason string `json:"reason,omitempty"`
+
+	// Range is the source range of the expression or reference that the
+	// explanation is about, if any.
+	Range *DiagnosticRange `json:"range,omitempty"`
+
+	// Causes explain the unknown values that the value's expression refers
+	// to.
+	Causes []*UnknownExplanation `json:"causes,omitempty"`
+}
+
+func NewUnknownExplanation(e *explain.Explanation) *UnknownExplanation {
+	ret := &UnknownExplanation{
+		Subject: e.Subject,
+		Reason:  e.Reason,
+	}
+	if e.Range != nil {
+		ret.Range = &DiagnosticRange{
+			Filename: e.Range.Filename,
+			Start: Pos{
+				Line:   e.Range.Start.Line,
+				Column: e.Range.Start.Column,
+				Byte:   e.Range.Start.Byte,
+			},
+			End: Pos{
+				Line:   e.Range.End.Line,
+				Column: e.Range.End.Column,
+				Byte:   e.Range.End.Byte,
+			},
+		}
+	}
+	for _, cause := range e.Causes {
+		ret.Causes = append(ret.Causes, NewUnknownExplanation(cause))
+	}
+	return ret
+}
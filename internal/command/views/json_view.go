@@ -19,7 +19,7 @@ import (
 // This version describes the schema of JSON UI messages. This version must be
 // updated after making any changes to this view, the jsonHook, or any of the
 // command/views/json package.
-const JSON_UI_VERSION = "1.2"
+const JSON_UI_VERSION = "1.3"
 
 func NewJSONView(view *View) *JSONView {
 	log := hclog.New(&hclog.LoggerOptions{
@@ -130,6 +130,14 @@ func (v *JSONView) Outputs(outputs json.Outputs) {
 	)
 }
 
+func (v *JSONView) UnknownExplanation(e *json.UnknownExplanation) {
+	v.log.Info(
+		fmt.Sprintf("%s: Known only after apply", e.Subject),
+		"type", json.MessageUnknownExplanation,
+		"explanation", e,
+	)
+}
+
 // Output is designed for supporting command.WrappedUi
 func (v *JSONView) Output(message string) {
 	v.log.Info(message, "type", "output")
@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/format"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/lint"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// The Lint view is used for the lint command.
+type Lint interface {
+	// Results renders the issues found in the configuration along with any
+	// other diagnostics, and returns a CLI exit code: 1 if there are errors,
+	// 2 if there are issues, and 0 otherwise.
+	Results(issues []lint.Issue, diags tfdiags.Diagnostics) int
+
+	// Diagnostics renders early diagnostics, resulting from argument parsing.
+	Diagnostics(diags tfdiags.Diagnostics)
+}
+
+// NewLint returns an initialized Lint implementation for the given ViewType.
+func NewLint(vt arguments.ViewType, view *View) Lint {
+	switch vt {
+	case arguments.ViewJSON:
+		return &LintJSON{view: view}
+	case arguments.ViewHuman:
+		return &LintHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", vt))
+	}
+}
+
+func lintExitCode(issues []lint.Issue, diags tfdiags.Diagnostics) int {
+	switch {
+	case diags.HasErrors():
+		return 1
+	case len(issues) != 0:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// The LintHuman implementation renders each issue as a warning, followed by
+// a summary of the number of issues found.
+type LintHuman struct {
+	view *View
+}
+
+var _ Lint = (*LintHuman)(nil)
+
+func (v *LintHuman) Results(issues []lint.Issue, diags tfdiags.Diagnostics) int {
+	v.Diagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	var issueDiags tfdiags.Diagnostics
+	for _, issue := range issues {
+		issue.Detail += fmt.Sprintf("\n\nThis issue was found by the rule %q. To disable the rule, use the option -disable=%s.", issue.Rule, issue.Rule)
+		issueDiags = issueDiags.Append(issue.Diagnostic())
+	}
+	v.view.Diagnostics(issueDiags)
+
+	columns := v.view.outputColumns()
+	if len(issues) == 0 {
+		v.view.streams.Println(format.WordWrap(v.view.colorize.Color(lintSuccess), columns))
+	} else {
+		v.view.streams.Println(format.WordWrap(v.view.colorize.Color(fmt.Sprintf(lintIssues, len(issues))), columns))
+	}
+	return lintExitCode(issues, diags)
+}
+
+const lintSuccess = "[green][bold]Success![reset] No issues found."
+
+const lintIssues = "[yellow][bold]Found %d issue(s).[reset]"
+
+func (v *LintHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// The LintJSON implementation renders the issues as a JSON object, with each
+// issue in the same form as a JSON diagnostic, along with the name of the
+// rule that found it.
+type LintJSON struct {
+	view *View
+}
+
+var _ Lint = (*LintJSON)(nil)
+
+func (v *LintJSON) Results(issues []lint.Issue, diags tfdiags.Diagnostics) int {
+	// FormatVersion represents the version of the json format and will be
+	// incremented for any change to this format that requires changes to a
+	// consuming parser.
+	const FormatVersion = "1.0"
+
+	type Issue struct {
+		Rule string `json:"rule"`
+		*viewsjson.Diagnostic
+	}
+
+	type Output struct {
+		FormatVersion string                  `json:"format_version"`
+		IssueCount    int                     `json:"issue_count"`
+		Issues        []Issue                 `json:"issues"`
+		Diagnostics   []*viewsjson.Diagnostic `json:"diagnostics"`
+	}
+
+	output := Output{
+		FormatVersion: FormatVersion,
+		IssueCount:    len(issues),
+		// Make sure these always appear as arrays in our output, since
+		// this is easier to consume for dynamically-typed languages.
+		Issues:      []Issue{},
+		Diagnostics: []*viewsjson.Diagnostic{},
+	}
+	configSources := v.view.configSources()
+	for _, issue := range issues {
+		output.Issues = append(output.Issues, Issue{
+			Rule:       issue.Rule,
+			Diagnostic: viewsjson.NewDiagnostic(issue.Diagnostic(), configSources),
+		})
+	}
+	for _, diag := range diags {
+		output.Diagnostics = append(output.Diagnostics, viewsjson.NewDiagnostic(diag, configSources))
+	}
+
+	j, err := json.MarshalIndent(&output, "", "  ")
+	if err != nil {
+		// Should never happen because we fully-control the input here
+		panic(err)
+	}
+	v.view.streams.Println(string(j))
+
+	return lintExitCode(issues, diags)
+}
+
+// Diagnostics should only be called if the configuration can't be checked.
+// In this case, we choose to render human-readable diagnostic output, as for
+// the validate command.
+func (v *LintJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/lint"
+	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestLint(t *testing.T) {
+	issue := lint.Issue{
+		Rule:    "unused_local",
+		Summary: "Unused local value",
+		Detail:  `The local value "a" is declared, but nothing in its module refers to it.`,
+		Subject: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 2, Column: 3, Byte: 10},
+			End:      hcl.Pos{Line: 2, Column: 10, Byte: 17},
+		},
+	}
+
+	testCases := map[string]struct {
+		issues        []lint.Issue
+		diag          tfdiags.Diagnostic
+		wantCode      int
+		wantSubstring string
+		wantCount     float64
+	}{
+		"success": {
+			nil,
+			nil,
+			0,
+			"Success! No issues found.",
+			0,
+		},
+		"issues": {
+			[]lint.Issue{issue},
+			nil,
+			2,
+			"-disable=unused_local",
+			1,
+		},
+		"error": {
+			nil,
+			tfdiags.Sourceless(
+				tfdiags.Error,
+				"Configuration is missing random_pet",
+				"Every configuration should have a random_pet.",
+			),
+			1,
+			"Error: Configuration is missing random_pet",
+			0,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name+" human", func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			view := NewView(streams)
+			view.Configure(&arguments.View{NoColor: true})
+			v := NewLint(arguments.ViewHuman, view)
+
+			var diags tfdiags.Diagnostics
+			if tc.diag != nil {
+				diags = diags.Append(tc.diag)
+			}
+
+			if got := v.Results(tc.issues, diags); got != tc.wantCode {
+				t.Errorf("expected %d return code, got %d", tc.wantCode, got)
+			}
+
+			got := done(t).All()
+			if !strings.Contains(got, tc.wantSubstring) {
+				t.Errorf("expected output to include %q, but was:\n%s", tc.wantSubstring, got)
+			}
+		})
+
+		t.Run(name+" json", func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			view := NewView(streams)
+			view.Configure(&arguments.View{NoColor: true})
+			v := NewLint(arguments.ViewJSON, view)
+
+			var diags tfdiags.Diagnostics
+			if tc.diag != nil {
+				diags = diags.Append(tc.diag)
+			}
+
+			if got := v.Results(tc.issues, diags); got != tc.wantCode {
+				t.Errorf("expected %d return code, got %d", tc.wantCode, got)
+			}
+
+			var result map[string]interface{}
+			if err := json.Unmarshal([]byte(done(t).Stdout()), &result); err != nil {
+				t.Fatal(err)
+			}
+			if got := result["issue_count"]; got != tc.wantCount {
+				t.Errorf("wrong issue_count %v; want %v", got, tc.wantCount)
+			}
+			if len(tc.issues) != 0 {
+				issue := result["issues"].([]interface{})[0].(map[string]interface{})
+				if issue["rule"] != "unused_local" || issue["severity"] != "warning" {
+					t.Errorf("wrong issue %#v", issue)
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -19,6 +20,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/explain"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -27,6 +29,7 @@ import (
 type Operation interface {
 	Interrupted()
 	FatalInterrupt()
+	TimedOut(timeout time.Duration)
 	Stopping()
 	Cancelled(planMode plans.Mode)
 
@@ -35,6 +38,7 @@ type Operation interface {
 	PlannedChange(change *plans.ResourceInstanceChangeSrc)
 	Plan(plan *plans.Plan, schemas *tofu.Schemas)
 	PlanNextStep(planPath string, genConfigPath string)
+	UnknownExplanations(explanations []*explain.Explanation)
 
 	Diagnostics(diags tfdiags.Diagnostics)
 }
@@ -71,6 +75,10 @@ func (v *OperationHuman) FatalInterrupt() {
 	v.view.streams.Eprintln(format.WordWrap(fatalInterrupt, v.view.errorColumns()))
 }
 
+func (v *OperationHuman) TimedOut(timeout time.Duration) {
+	v.view.streams.Println(format.WordWrap(fmt.Sprintf(timedOut, timeout), v.view.outputColumns()))
+}
+
 func (v *OperationHuman) Stopping() {
 	v.view.streams.Println("Stopping operation...")
 }
@@ -116,6 +124,7 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		ResourceDrift:         drift,
 		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 		RelevantAttributes:    attrs,
+		ResourceDependencies:  jsonplan.ResourceDependencies(plan),
 	}
 
 	// Side load some data that we can't extract from the JSON plan.
@@ -171,6 +180,51 @@ func (v *OperationHuman) PlanNextStep(planPath string, genConfigPath string) {
 	}
 }
 
+// UnknownExplanations renders the explanations of why values in the plan are
+// unknown, as a tree of the references that caused each of them.
+func (v *OperationHuman) UnknownExplanations(explanations []*explain.Explanation) {
+	if len(explanations) == 0 {
+		v.view.streams.Print(
+			format.WordWrap(
+				"\nNo values in this plan are unknown because of other values in the configuration.",
+				v.view.outputColumns(),
+			) + "\n",
+		)
+		return
+	}
+
+	var buf strings.Builder
+	buf.WriteString(v.view.colorize.Color("\n[bold]Values known only after apply:[reset]\n"))
+	for _, e := range explanations {
+		buf.WriteString("\n")
+		writeUnknownExplanation(&buf, e, "  ", true)
+	}
+	v.view.streams.Print(buf.String())
+}
+
+func writeUnknownExplanation(buf *strings.Builder, e *explain.Explanation, indent string, top bool) {
+	buf.WriteString(indent)
+	if top {
+		buf.WriteString("# ")
+	} else {
+		buf.WriteString("- ")
+	}
+	buf.WriteString(e.Subject)
+	if e.Range != nil {
+		fmt.Fprintf(buf, " (at %s)", e.Range.StartString())
+	}
+	switch {
+	case e.Reason != "":
+		fmt.Fprintf(buf, ": %s", e.Reason)
+	case len(e.Causes) != 0:
+		buf.WriteString(" depends on:")
+	}
+	buf.WriteString("\n")
+	for _, cause := range e.Causes {
+		writeUnknownExplanation(buf, cause, indent+"  ", false)
+	}
+}
+
 func (v *OperationHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -189,6 +243,10 @@ func (v *OperationJSON) FatalInterrupt() {
 	v.view.Log(fatalInterrupt)
 }
 
+func (v *OperationJSON) TimedOut(timeout time.Duration) {
+	v.view.Log(fmt.Sprintf(timedOut, timeout))
+}
+
 func (v *OperationJSON) Stopping() {
 	v.view.Log("Stopping operation...")
 }
@@ -284,6 +342,12 @@ func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 func (v *OperationJSON) PlanNextStep(planPath string, genConfigPath string) {
 }
 
+func (v *OperationJSON) UnknownExplanations(explanations []*explain.Explanation) {
+	for _, e := range explanations {
+		v.view.UnknownExplanation(json.NewUnknownExplanation(e))
+	}
+}
+
 func (v *OperationJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -292,6 +356,12 @@ const fatalInterrupt = `
 Two interrupts received. Exiting immediately. Note that data loss may have occurred.
 `
 
+const timedOut = `
+Run timeout of %s exceeded.
+Please wait for OpenTofu to exit or data loss may occur.
+Gracefully shutting down...
+`
+
 const interrupted = `
 Interrupt received.
 Please wait for OpenTofu to exit or data loss may occur.
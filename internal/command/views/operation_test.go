@@ -10,15 +10,18 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/explain"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -463,6 +466,64 @@ func TestOperation_planNextStep(t *testing.T) {
 	}
 }
 
+func testUnknownExplanations() []*explain.Explanation {
+	return []*explain.Explanation{
+		{
+			Subject: "test_instance.b.value",
+			Range: &tfdiags.SourceRange{
+				Filename: "main.tf",
+				Start:    tfdiags.SourcePos{Line: 10, Column: 11, Byte: 100},
+				End:      tfdiags.SourcePos{Line: 10, Column: 29, Byte: 118},
+			},
+			Causes: []*explain.Explanation{
+				{
+					Subject: "local.name",
+					Range: &tfdiags.SourceRange{
+						Filename: "main.tf",
+						Start:    tfdiags.SourcePos{Line: 10, Column: 11, Byte: 100},
+						End:      tfdiags.SourcePos{Line: 10, Column: 21, Byte: 110},
+					},
+					Causes: []*explain.Explanation{
+						{
+							Subject: "test_instance.a.id",
+							Reason:  "test_instance.a will be created, and the provider decides its id during apply",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestOperation_unknownExplanations(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOperation(arguments.ViewHuman, false, NewView(streams))
+
+	v.UnknownExplanations(testUnknownExplanations())
+
+	want := `
+Values known only after apply:
+
+  # test_instance.b.value (at main.tf:10,11) depends on:
+    - local.name (at main.tf:10,11) depends on:
+      - test_instance.a.id: test_instance.a will be created, and the provider decides its id during apply
+`
+	if got := done(t).Stdout(); got != want {
+		t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOperation_unknownExplanationsNone(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOperation(arguments.ViewHuman, false, NewView(streams))
+
+	v.UnknownExplanations(nil)
+
+	if got, want := done(t).Stdout(), "No values in this plan are unknown"; !strings.Contains(got, want) {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 // The in-automation state is on the view itself, so testing it separately is
 // clearer.
 func TestOperation_planNextStepInAutomation(t *testing.T) {
@@ -487,6 +548,7 @@ func TestOperationJSON_logs(t *testing.T) {
 	v.Stopping()
 	v.Interrupted()
 	v.FatalInterrupt()
+	v.TimedOut(90 * time.Minute)
 
 	want := []map[string]interface{}{
 		{
@@ -519,6 +581,12 @@ func TestOperationJSON_logs(t *testing.T) {
 			"@module":  "tofu.ui",
 			"type":     "log",
 		},
+		{
+			"@level":   "info",
+			"@message": "\nRun timeout of 1h30m0s exceeded.\nPlease wait for OpenTofu to exit or data loss may occur.\nGracefully shutting down...\n",
+			"@module":  "tofu.ui",
+			"type":     "log",
+		},
 	}
 
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
@@ -1337,3 +1405,44 @@ func TestOperationJSON_plannedChange(t *testing.T) {
 
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
+
+func TestOperationJSON_unknownExplanations(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	v.UnknownExplanations(testUnknownExplanations())
+
+	rng := func(start, end float64) map[string]interface{} {
+		return map[string]interface{}{
+			"filename": "main.tf",
+			"start":    map[string]interface{}{"line": float64(10), "column": float64(11), "byte": float64(100)},
+			"end":      map[string]interface{}{"line": float64(10), "column": end - start + 11, "byte": end},
+		}
+	}
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "test_instance.b.value: Known only after apply",
+			"@module":  "tofu.ui",
+			"type":     "unknown_explanation",
+			"explanation": map[string]interface{}{
+				"subject": "test_instance.b.value",
+				"range":   rng(100, 118),
+				"causes": []interface{}{
+					map[string]interface{}{
+						"subject": "local.name",
+						"range":   rng(100, 110),
+						"causes": []interface{}{
+							map[string]interface{}{
+								"subject": "test_instance.a.id",
+								"reason":  "test_instance.a will be created, and the provider decides its id during apply",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
@@ -10,14 +10,19 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
 	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsonfilter"
 	"github.com/opentofu/opentofu/internal/command/jsonformat"
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/repl"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -27,6 +32,10 @@ type Show interface {
 	// Display renders the plan, if it is available. If plan is nil, it renders the statefile.
 	Display(config *configs.Config, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, stateFile *statefile.File, schemas *tofu.Schemas) int
 
+	// DisplayValue renders a single value that was revealed with the -reveal
+	// option, along with its address.
+	DisplayValue(addr string, val cty.Value) int
+
 	// Diagnostics renders early diagnostics, resulting from argument parsing.
 	Diagnostics(diags tfdiags.Diagnostics)
 }
@@ -88,6 +97,7 @@ func (v *ShowHuman) Display(config *configs.Config, plan *plans.Plan, planJSON *
 			ResourceDrift:         drift,
 			ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 			RelevantAttributes:    attrs,
+			ResourceDependencies:  jsonplan.ResourceDependencies(plan),
 		}
 
 		var opts []plans.Quality
@@ -124,12 +134,21 @@ func (v *ShowHuman) Display(config *configs.Config, plan *plans.Plan, planJSON *
 	return 0
 }
 
+func (v *ShowHuman) DisplayValue(addr string, val cty.Value) int {
+	v.view.streams.Printf("%s = %s\n", addr, repl.FormatValue(val, 0))
+	return 0
+}
+
 func (v *ShowHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
 type ShowJSON struct {
 	view *View
+
+	// Filters are the privacy filters applied to the plan or state before
+	// it is displayed.
+	Filters jsonfilter.Pipeline
 }
 
 var _ Show = (*ShowJSON)(nil)
@@ -142,7 +161,7 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, planJSON *c
 			v.view.streams.Eprintf("Didn't get external JSON plan format")
 			return 1
 		}
-		v.view.streams.Println(string(planJSON.JSONBytes))
+		return v.print(planJSON.JSONBytes)
 	} else if plan != nil {
 		planJSON, err := jsonplan.Marshal(config, plan, stateFile, schemas)
 
@@ -150,7 +169,7 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, planJSON *c
 			v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
 			return 1
 		}
-		v.view.streams.Println(string(planJSON))
+		return v.print(planJSON)
 	} else {
 		// It is possible that there is neither state nor a plan.
 		// That's ok, we'll just return an empty object.
@@ -159,8 +178,50 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, planJSON *c
 			v.view.streams.Eprintf("Failed to marshal state to json: %s", err)
 			return 1
 		}
-		v.view.streams.Println(string(jsonState))
+		return v.print(jsonState)
+	}
+}
+
+// print applies the filters to the given JSON document and then prints it.
+func (v *ShowJSON) print(doc []byte) int {
+	filtered, err := v.Filters.Apply(doc)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to apply JSON filters: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(filtered))
+	return 0
+}
+
+func (v *ShowJSON) DisplayValue(addr string, val cty.Value) int {
+	if !val.IsWhollyKnown() {
+		v.view.streams.Eprintf("The value of %s is not known until apply", addr)
+		return 1
+	}
+	jsonVal, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal value to json: %s", err)
+		return 1
+	}
+	jsonType, err := ctyjson.MarshalType(val.Type())
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal value to json: %s", err)
+		return 1
+	}
+	ret, err := json.Marshal(struct {
+		Address string          `json:"address"`
+		Type    json.RawMessage `json:"type"`
+		Value   json.RawMessage `json:"value"`
+	}{
+		Address: addr,
+		Type:    jsonType,
+		Value:   jsonVal,
+	})
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal value to json: %s", err)
+		return 1
 	}
+	v.view.streams.Println(string(ret))
 	return 0
 }
 
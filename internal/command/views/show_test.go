@@ -216,6 +216,52 @@ func TestShowJSON(t *testing.T) {
 	}
 }
 
+func TestShowDisplayValue(t *testing.T) {
+	val := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("hunter2"),
+	})
+
+	t.Run("human", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		v := NewShow(arguments.ViewHuman, view)
+
+		if code := v.DisplayValue("test_resource.foo.creds", val); code != 0 {
+			t.Errorf("expected 0 return code, got %d", code)
+		}
+		want := "test_resource.foo.creds = {\n  \"password\" = \"hunter2\"\n}\n"
+		if got := done(t).Stdout(); got != want {
+			t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewShow(arguments.ViewJSON, NewView(streams))
+
+		if code := v.DisplayValue("test_resource.foo.creds", val); code != 0 {
+			t.Errorf("expected 0 return code, got %d", code)
+		}
+		want := `{"address":"test_resource.foo.creds","type":["object",{"password":"string"}],"value":{"password":"hunter2"}}` + "\n"
+		if got := done(t).Stdout(); got != want {
+			t.Fatalf("unexpected output\ngot: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("json unknown", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewShow(arguments.ViewJSON, NewView(streams))
+
+		if code := v.DisplayValue("test_resource.foo.id", cty.UnknownVal(cty.String)); code != 1 {
+			t.Errorf("expected 1 return code, got %d", code)
+		}
+		if got := done(t).Stderr(); !strings.Contains(got, "not known until apply") {
+			t.Fatalf("unexpected error output: %s", got)
+		}
+	})
+}
+
 // testState returns a test State structure.
 func testState() *states.State {
 	return states.BuildState(func(s *states.SyncState) {
@@ -8,9 +8,11 @@ package views
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
 // The StateLocker view is used to display locking/unlocking status messages
@@ -18,6 +20,10 @@ import (
 type StateLocker interface {
 	Locking()
 	Unlocking()
+
+	// Waiting reports that the lock is held by another process, and that
+	// OpenTofu will wait before trying to acquire it again.
+	Waiting(wait statemgr.LockWait)
 }
 
 // NewStateLocker returns an initialized StateLocker implementation for the given ViewType.
@@ -49,6 +55,30 @@ func (v *StateLockerHuman) Unlocking() {
 	v.view.streams.Println("Releasing state lock. This may take a few moments...")
 }
 
+func (v *StateLockerHuman) Waiting(wait statemgr.LockWait) {
+	var held strings.Builder
+	held.WriteString("The state is locked")
+	if info := wait.Info; info != nil {
+		if info.Who != "" {
+			fmt.Fprintf(&held, " by %s", info.Who)
+		}
+		if info.Operation != "" {
+			fmt.Fprintf(&held, " for %s", info.Operation)
+		}
+		if info.ID != "" {
+			fmt.Fprintf(&held, " (lock ID %s)", info.ID)
+		}
+	}
+	attempt := fmt.Sprintf("attempt %d", wait.Attempt)
+	if wait.MaxAttempts > 0 {
+		attempt = fmt.Sprintf("attempt %d of %d", wait.Attempt, wait.MaxAttempts)
+	}
+	v.view.streams.Printf(
+		"%s. Retrying in %s after %s (%s)...\n",
+		held.String(), wait.Delay.Round(time.Millisecond), wait.Elapsed.Round(time.Millisecond), attempt,
+	)
+}
+
 // StateLockerJSON is an implementation of StateLocker which prints the state lock status
 // to a terminal in machine-readable JSON form.
 type StateLockerJSON struct {
@@ -82,3 +112,30 @@ func (v *StateLockerJSON) Unlocking() {
 	lock_info_message, _ := json.Marshal(json_data)
 	v.view.streams.Println(string(lock_info_message))
 }
+
+func (v *StateLockerJSON) Waiting(wait statemgr.LockWait) {
+	current_timestamp := time.Now().Format(time.RFC3339)
+
+	json_data := map[string]interface{}{
+		"@level":          "info",
+		"@message":        fmt.Sprintf("State is locked. Retrying in %s...", wait.Delay.Round(time.Millisecond)),
+		"@module":         "tofu.ui",
+		"@timestamp":      current_timestamp,
+		"type":            "state_lock_wait",
+		"attempt":         wait.Attempt,
+		"max_attempts":    wait.MaxAttempts,
+		"delay_seconds":   wait.Delay.Seconds(),
+		"elapsed_seconds": wait.Elapsed.Seconds(),
+	}
+	if info := wait.Info; info != nil {
+		json_data["lock"] = map[string]string{
+			"id":        info.ID,
+			"operation": info.Operation,
+			"who":       info.Who,
+			"created":   info.Created.Format(time.RFC3339),
+		}
+	}
+
+	lock_wait_message, _ := json.Marshal(json_data)
+	v.view.streams.Println(string(lock_wait_message))
+}
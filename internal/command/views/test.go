@@ -194,6 +194,7 @@ func (t *TestHuman) Run(run *moduletest.Run, file *moduletest.File) {
 					ResourceDrift:         drift,
 					ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
 					RelevantAttributes:    attrs,
+					ResourceDependencies:  jsonplan.ResourceDependencies(run.Verbose.Plan),
 				}
 
 				var opts []plans.Quality
@@ -23,6 +23,10 @@ type Validate interface {
 
 	// Diagnostics renders early diagnostics, resulting from argument parsing.
 	Diagnostics(diags tfdiags.Diagnostics)
+
+	// Changed reports that the given files have changed while running with
+	// -watch, before the configuration is validated again.
+	Changed(paths []string)
 }
 
 // NewValidate returns an initialized Validate implementation for the given ViewType.
@@ -73,6 +77,13 @@ func (v *ValidateHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+func (v *ValidateHuman) Changed(paths []string) {
+	v.view.streams.Println()
+	for _, path := range paths {
+		v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("[bold]Changed:[reset] %s", path)))
+	}
+}
+
 // The ValidateJSON implementation renders validation results as a JSON object.
 // This object includes top-level fields summarizing the result, and an array
 // of JSON diagnostic objects.
@@ -141,3 +152,7 @@ func (v *ValidateJSON) Results(diags tfdiags.Diagnostics) int {
 func (v *ValidateJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
+
+// Changed does nothing, so that the output remains a sequence of validation
+// results that each have the same form as the output without -watch.
+func (v *ValidateJSON) Changed(paths []string) {}
@@ -0,0 +1,143 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// watchInterval is how often commands running with -watch check for changed
+// files.
+const watchInterval = 100 * time.Millisecond
+
+type watchedFileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileWatcher detects changes to the files in a set of files and directories.
+//
+// It periodically compares the modification times and sizes of the files
+// rather than relying on filesystem notifications, so that it behaves the
+// same way on all platforms and on network filesystems. Configuration
+// directories are small enough that this is cheap.
+type fileWatcher struct {
+	paths     []string
+	recursive bool
+	match     func(name string) bool
+
+	stamps map[string]watchedFileStamp
+}
+
+// newFileWatcher returns a watcher for the given paths, which may be files or
+// directories. Files in the directories are only watched if match returns
+// true for their names, and subdirectories are only watched if recursive is
+// set. Files that are given directly are always watched.
+func newFileWatcher(paths []string, recursive bool, match func(name string) bool) *fileWatcher {
+	w := &fileWatcher{
+		paths:     paths,
+		recursive: recursive,
+		match:     match,
+	}
+	w.stamps = w.scan()
+	return w
+}
+
+// Wait blocks until at least one of the watched files is created, changed or
+// deleted, returning their absolute paths in lexical order, or until the
+// given context is cancelled, returning nil.
+func (w *fileWatcher) Wait(ctx context.Context) []string {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if changed := w.changes(); len(changed) != 0 {
+				return changed
+			}
+		}
+	}
+}
+
+// changes scans the watched paths again, returning the files that were
+// created, changed or deleted since the previous scan.
+func (w *fileWatcher) changes() []string {
+	stamps := w.scan()
+
+	var changed []string
+	for path, stamp := range stamps {
+		if prev, ok := w.stamps[path]; !ok || prev != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.stamps {
+		if _, ok := stamps[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	w.stamps = stamps
+	return changed
+}
+
+func (w *fileWatcher) scan() map[string]watchedFileStamp {
+	stamps := make(map[string]watchedFileStamp)
+	for _, path := range w.paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			// The path may be created later, so we'll keep trying.
+			continue
+		}
+		if info.IsDir() {
+			w.scanDir(path, stamps)
+			continue
+		}
+		stamps[path] = watchedFileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps
+}
+
+func (w *fileWatcher) scanDir(dir string, stamps map[string]watchedFileStamp) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if configs.IsIgnoredFile(name) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if entry.IsDir() {
+			if w.recursive {
+				w.scanDir(path, stamps)
+			}
+			continue
+		}
+		if !w.match(name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		stamps[path] = watchedFileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func TestFileWatcher(t *testing.T) {
+	td := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(td, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	main := write("main.tf", "")
+	child := write("modules/child/main.tf", "")
+	write("README.md", "")
+	write(".terraform/modules/foo/main.tf", "")
+
+	w := newFileWatcher([]string{td}, true, configs.IsConfigFile)
+	if got := w.changes(); len(got) != 0 {
+		t.Fatalf("unexpected changes before modifying any files: %v", got)
+	}
+
+	// Changes to files that don't match, or that are in hidden directories,
+	// are ignored.
+	write("README.md", "changed")
+	write(".terraform/modules/foo/main.tf", "changed")
+	if got := w.changes(); len(got) != 0 {
+		t.Fatalf("unexpected changes to ignored files: %v", got)
+	}
+
+	write("main.tf", "changed")
+	write("modules/child/main.tf", "changed")
+	added := write("variables.tf", "")
+	if diff := cmp.Diff([]string{main, child, added}, w.changes()); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+
+	if err := os.Remove(added); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{added}, w.changes()); diff != "" {
+		t.Errorf("wrong changes after removing a file\n%s", diff)
+	}
+
+	// Without recursion, subdirectories are ignored.
+	w = newFileWatcher([]string{td}, false, configs.IsConfigFile)
+	write("modules/child/main.tf", "changed again")
+	if got := w.changes(); len(got) != 0 {
+		t.Fatalf("unexpected changes in subdirectory: %v", got)
+	}
+
+	// Wait returns nil once the context is cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*watchInterval)
+	defer cancel()
+	start := time.Now()
+	if got := w.Wait(ctx); got != nil {
+		t.Fatalf("unexpected changes: %v", got)
+	}
+	if time.Since(start) < 2*watchInterval {
+		t.Fatal("returned before the context was cancelled")
+	}
+}
@@ -33,7 +33,7 @@ func (c *WorkspaceCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] workspace
 
-  new, list, show, select and delete OpenTofu workspaces.
+  new, list, show, select, delete and sync OpenTofu workspaces.
 
 `
 	return strings.TrimSpace(helpText)
@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -469,3 +470,274 @@ func TestWorkspace_selectWithOrCreate(t *testing.T) {
 	}
 
 }
+
+func TestWorkspace_sync(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	if err := os.WriteFile("dev.tfvars", []byte(`foo = "dev"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+workspace "dev" {
+  var_files = ["dev.tfvars"]
+}
+workspace "prod" {}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A workspace that exists already but is not declared.
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	newCmd := &WorkspaceNewCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := newCmd.Run([]string{"legacy"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	syncCmd := &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	for _, ws := range []string{"dev", "prod"} {
+		if !strings.Contains(ui.OutputWriter.String(), "Created workspace \""+ws+"\"") {
+			t.Errorf("workspace %q was not created:\n%s", ws, ui.OutputWriter)
+		}
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), `The workspace "legacy" exists, but is not declared`) {
+		t.Errorf("missing warning about the undeclared workspace:\n%s", ui.ErrorWriter)
+	}
+
+	// The selected workspace must not change.
+	if current, _ := syncCmd.Workspace(); current != "legacy" {
+		t.Errorf("the selected workspace changed to %q", current)
+	}
+
+	listUi := new(cli.MockUi)
+	listCmd := &WorkspaceListCommand{Meta: Meta{Ui: listUi, View: view}}
+	if code := listCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d", code)
+	}
+	for _, ws := range []string{"dev", "prod", "legacy"} {
+		if !strings.Contains(listUi.OutputWriter.String(), ws) {
+			t.Errorf("workspace %q is missing from the list", ws)
+		}
+	}
+
+	// Running it again doesn't create anything.
+	ui = new(cli.MockUi)
+	syncCmd = &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if strings.Contains(ui.OutputWriter.String(), "Created") {
+		t.Errorf("unexpected workspace created:\n%s", ui.OutputWriter)
+	}
+	for _, ws := range []string{"dev", "prod"} {
+		if !strings.Contains(ui.OutputWriter.String(), "Workspace \""+ws+"\" is up to date") {
+			t.Errorf("workspace %q was not reported up to date:\n%s", ws, ui.OutputWriter)
+		}
+	}
+}
+
+func TestWorkspace_syncReencrypt(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+	ui := new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := newCmd.Run([]string{"dev"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	statePath := filepath.Join(local.DefaultWorkspaceDir, "dev", local.DefaultStateFilename)
+	if err := os.WriteFile(statePath, []byte(`{"version": 4, "terraform_version": "1.9.0", "serial": 1, "lineage": "magic", "outputs": {}, "resources": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The workspace file now declares an encryption for the existing
+	// workspace, which can still read its unencrypted state.
+	if err := os.WriteFile("dev.encryption.hcl", []byte(`
+key_provider "static_test" "dev" {
+  key                    = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+  unsafe_allow_test_keys = true
+}
+method "aes_gcm" "dev" {
+  keys = key_provider.static_test.dev
+}
+method "unencrypted" "migrate" {}
+state {
+  method = method.aes_gcm.dev
+  fallback {
+    method = method.unencrypted.migrate
+  }
+}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+workspace "dev" {
+  encryption_file = "dev.encryption.hcl"
+}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui = new(cli.MockUi)
+	syncCmd := &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.OutputWriter.String(), `Updated workspace "dev"`) {
+		t.Errorf("workspace was not updated:\n%s", ui.OutputWriter)
+	}
+	raw, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "encrypted_data") {
+		t.Errorf("the state was not encrypted:\n%s", raw)
+	}
+
+	// The state is now encrypted with the primary method, so there is
+	// nothing left to update.
+	ui = new(cli.MockUi)
+	syncCmd = &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if !strings.Contains(ui.OutputWriter.String(), `Workspace "dev" is up to date`) {
+		t.Errorf("workspace was not reported up to date:\n%s", ui.OutputWriter)
+	}
+}
+
+func TestWorkspace_syncLocked(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+	ui := new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := newCmd.Run([]string{"dev"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`workspace "dev" {}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	statePath := filepath.Join(local.DefaultWorkspaceDir, "dev", local.DefaultStateFilename)
+	unlock, err := testLockState(t, testDataDir, statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ui = new(cli.MockUi)
+	syncCmd := &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code == 0 {
+		t.Fatalf("expected failure while the state is locked")
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "lock") {
+		t.Errorf("missing error about the state lock:\n%s", ui.ErrorWriter)
+	}
+}
+
+func TestWorkspace_syncBackendKeys(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+	for _, pattern := range []string{"states/{workspace}.tfstate", "terraform.tfstate.d/{workspace}/terraform.tfstate"} {
+		if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+backend_key_pattern = "`+pattern+`"
+workspace "dev" {}
+`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		ui := new(cli.MockUi)
+		syncCmd := &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+		code := syncCmd.Run(nil)
+		if pattern == "states/{workspace}.tfstate" {
+			if code == 0 {
+				t.Fatalf("expected failure for the mismatching pattern")
+			}
+			if !strings.Contains(ui.ErrorWriter.String(), "Backend key mismatch") {
+				t.Errorf("missing error about the backend key:\n%s", ui.ErrorWriter)
+			}
+			if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "dev")); !os.IsNotExist(err) {
+				t.Errorf("the workspace was created despite the error")
+			}
+			continue
+		}
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+		if !strings.Contains(ui.OutputWriter.String(), `Created workspace "dev"`) {
+			t.Errorf("workspace was not created:\n%s", ui.OutputWriter)
+		}
+	}
+}
+
+func TestWorkspace_syncMissingFile(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+workspace "dev" {
+  var_files = ["missing.tfvars"]
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	syncCmd := &WorkspaceSyncCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := syncCmd.Run(nil); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "missing.tfvars") {
+		t.Errorf("missing error about the variables file:\n%s", ui.ErrorWriter)
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "dev")); !os.IsNotExist(err) {
+		t.Errorf("the workspace was created despite the error")
+	}
+}
+
+func TestWorkspace_fileVarFiles(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	t.Setenv(WorkspaceNameEnvVar, "dev")
+
+	if err := os.WriteFile("terraform.tfvars", []byte("foo = \"auto\"\nbar = \"auto\""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("dev.tfvars", []byte(`foo = "dev"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("opentofu.workspace.hcl", []byte(`
+workspace "dev" {
+  var_files = ["dev.tfvars"]
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Meta{}
+	vals, diags := m.collectVariableValues()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	for name, want := range map[string]string{"foo": "dev", "bar": "auto"} {
+		got, diags := vals[name].ParseVariableValue(configs.VariableParseLiteral)
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		if got.Value.AsString() != want {
+			t.Errorf("wrong value for %s %q; want %q", name, got.Value.AsString(), want)
+		}
+	}
+}
@@ -141,7 +141,7 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 	}
 
 	if stateLock {
-		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "workspace-new"); diags.HasErrors() {
 			c.showDiagnostics(diags)
 			return 1
@@ -160,7 +160,7 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 		return 1
 	}
 
-	stateFile, err := statefile.Read(f, encryption.StateEncryptionDisabled()) // Assume given statefile is not encrypted
+	stateFile, err := statefile.Read(f, encryption.PlaintextUnlessEnforced(enc.State())) // Assume given statefile is not encrypted unless enforced
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
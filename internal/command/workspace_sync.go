@@ -0,0 +1,333 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	backendLocal "github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/command/workspacefile"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WorkspaceSyncCommand is a Command implementation that creates and updates
+// the workspaces declared in the workspace file.
+type WorkspaceSyncCommand struct {
+	Meta
+}
+
+func (c *WorkspaceSyncCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("workspace sync")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	configPath, err := modulePath(args)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	file, fileDiags := workspacefile.Load(".")
+	diags = diags.Append(fileDiags)
+	if fileDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if file == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No workspace file",
+			fmt.Sprintf("The current directory does not contain a %s file declaring the workspaces to create.", workspacefile.Filename),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Check the files of all workspaces first, so that we don't create some
+	// of the workspaces before reporting a problem with the others.
+	for _, ws := range file.Workspaces {
+		paths := file.VarFilePaths(ws)
+		if path := file.EncryptionFilePath(ws); path != "" {
+			paths = append(paths, path)
+		}
+		for _, path := range paths {
+			if _, err := os.Stat(path); err != nil {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Missing workspace file",
+					Detail:   fmt.Sprintf("The workspace %q refers to %s, which cannot be read: %s.", ws.Name, path, err),
+					Subject:  ws.DeclRange.Ptr(),
+				})
+			}
+		}
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Listing the workspaces doesn't write state
+	c.ignoreRemoteVersionConflict(b)
+
+	// Like the backend, the backend keys don't depend on the workspace
+	// configuration, so we check all of them before changing anything.
+	diags = diags.Append(c.checkBackendKeys(b, file))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to get configured named states: %s", err))
+		return 1
+	}
+	existing := make(map[string]bool, len(workspaces))
+	for _, ws := range workspaces {
+		existing[ws] = true
+	}
+
+	for _, ws := range file.Workspaces {
+		wsDiags := c.syncWorkspace(configPath, backendConfig, ws.Name, existing[ws.Name])
+		diags = diags.Append(wsDiags)
+		if wsDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	for _, name := range workspaces {
+		if name != backend.DefaultStateName && file.Workspace(name) == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Undeclared workspace",
+				fmt.Sprintf("The workspace %q exists, but is not declared in %s. OpenTofu does not delete workspaces automatically; use \"tofu workspace delete\" if it is no longer needed.", name, workspacefile.Filename),
+			))
+		}
+	}
+	c.showDiagnostics(diags)
+
+	return 0
+}
+
+// checkBackendKeys returns an error for each declared workspace whose state
+// the backend doesn't store under the key declared in the workspace file.
+func (c *WorkspaceSyncCommand) checkBackendKeys(b backend.Backend, file *workspacefile.File) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// The local backend runs the operations of the other backends, which
+	// store the states.
+	if l, ok := b.(*backendLocal.Local); ok && l.Backend != nil {
+		b = l.Backend
+	}
+	keyer, ok := b.(backend.StateKeyer)
+
+	for _, ws := range file.Workspaces {
+		want := file.BackendKey(ws)
+		if want == "" {
+			continue
+		}
+		if !ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported backend key",
+				Detail:   fmt.Sprintf("The workspace %q declares the key under which the backend stores its state, but the configured backend doesn't store states under keys that OpenTofu can check.", ws.Name),
+				Subject:  ws.DeclRange.Ptr(),
+			})
+			continue
+		}
+		if got := keyer.StateKey(ws.Name); got != want {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Backend key mismatch",
+				Detail:   fmt.Sprintf("The backend stores the state of workspace %q under the key %q, but %s declares %q. Change the backend configuration, for example the prefix of the workspace keys, or the workspace file, so that they agree.", ws.Name, got, workspacefile.Filename, want),
+				Subject:  ws.DeclRange.Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
+// syncWorkspace creates the given declared workspace if it doesn't exist, or
+// otherwise updates it to match its declaration, while holding the lock of
+// its state. The variables and the encryption configuration are loaded as if
+// the workspace was selected, since its state must be encrypted with its own
+// encryption configuration.
+func (c *WorkspaceSyncCommand) syncWorkspace(configPath string, backendConfig *configs.Backend, name string, exists bool) (diags tfdiags.Diagnostics) {
+	c.workspaceOverride = name
+	c.rootModuleCallCache = nil
+	c.inputVariableCache = nil
+	defer func() {
+		c.workspaceOverride = ""
+		c.rootModuleCallCache = nil
+		c.inputVariableCache = nil
+	}()
+
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return diags
+	}
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return diags
+	}
+	if exists {
+		diags = diags.Append(c.remoteVersionCheck(b, name))
+		if diags.HasErrors() {
+			return diags
+		}
+	} else {
+		// Creating a workspace doesn't write state
+		c.ignoreRemoteVersionConflict(b)
+	}
+
+	stateMgr, err := b.StateMgr(name)
+	if err != nil {
+		verb := "update"
+		if !exists {
+			verb = "create"
+		}
+		return diags.Append(fmt.Errorf("Failed to %s workspace %q: %w", verb, name, err))
+	}
+	if !exists {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Created workspace %q.", name)))
+		return diags
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLockerWithRetryPolicy(c.stateLockTimeout, c.stateLockRetryPolicy(), views.NewStateLocker(arguments.ViewHuman, c.View))
+		if lockDiags := stateLocker.Lock(stateMgr, "workspace-sync"); lockDiags.HasErrors() {
+			return diags.Append(lockDiags)
+		}
+		defer func() {
+			diags = diags.Append(stateLocker.Unlock())
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		return diags.Append(fmt.Errorf("Failed to read the state of workspace %q: %w", name, err))
+	}
+
+	// The only part of an existing workspace that the workspace file can
+	// change is the encryption of its state, so we update the workspace if
+	// its state was read with a fallback encryption configuration, or was
+	// not encrypted while the workspace declares an encryption.
+	r, ok := stateMgr.(statemgr.Reencrypter)
+	if state := stateMgr.State(); !ok || state == nil || r.EncryptionStatus() != encryption.StatusMigration {
+		c.Ui.Output(fmt.Sprintf("Workspace %q is up to date.", name))
+		return diags
+	}
+	r.ForceReencrypt()
+	if err := stateMgr.WriteState(stateMgr.State()); err != nil {
+		return diags.Append(fmt.Errorf("Failed to update workspace %q: %w", name, err))
+	}
+	if err := stateMgr.PersistState(nil); err != nil {
+		return diags.Append(fmt.Errorf("Failed to update workspace %q: %w", name, err))
+	}
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Updated workspace %q: its state is now encrypted with its encryption configuration.", name)))
+	return diags
+}
+
+func (c *WorkspaceSyncCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("")
+}
+
+func (c *WorkspaceSyncCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *WorkspaceSyncCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace sync [OPTIONS]
+
+  Create the workspaces declared in the opentofu.workspace.hcl file of the
+  current directory that don't exist yet, and update the ones that do, so
+  that their states are encrypted with the encryption configuration declared
+  for them. The selected workspace is not changed, and workspaces that are not
+  declared in the file are not deleted.
+
+  If the file declares the keys under which the backend stores the state of
+  each workspace, the backend configuration is checked against them before
+  any workspace is changed.
+
+Options:
+
+  -ignore-remote-version  Continue even if remote and local OpenTofu versions
+                          are incompatible. This may result in an unusable
+                          workspace, and should be used with extreme caution.
+
+  -lock=false             Don't hold a state lock while updating a workspace.
+                          This is dangerous if others might concurrently run
+                          commands against the same workspace.
+
+  -lock-timeout=0s        Duration to retry a state lock.
+
+  -var 'foo=bar'          Set a value for one of the input variables in the
+                          root module of the configuration. Use this option
+                          more than once to set more than one variable.
+
+  -var-file=filename      Load variable values from the given file, in
+                          addition to the default files terraform.tfvars and
+                          *.auto.tfvars. Use this option more than once to
+                          include more than one variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceSyncCommand) Synopsis() string {
+	return "Create and update the workspaces declared in the workspace file"
+}
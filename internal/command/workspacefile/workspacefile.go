@@ -0,0 +1,197 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package workspacefile contains the types representing and the logic to load
+// the workspace file, opentofu.workspace.hcl, which declares the workspaces of
+// a working directory along with their settings.
+//
+// The workspace file is not part of the configuration written in the
+// OpenTofu language, so it cannot refer to variables or other objects. It is
+// read from the current working directory, like the automatically-loaded
+// variable definitions files.
+package workspacefile
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Filename is the name of the workspace file in the working directory.
+const Filename = "opentofu.workspace.hcl"
+
+// WorkspacePlaceholder stands for the name of the workspace in the backend key
+// pattern.
+const WorkspacePlaceholder = "{workspace}"
+
+// File represents a workspace file.
+type File struct {
+	// BackendKeyPattern is the key under which the backend must store the
+	// state of each workspace, with WorkspacePlaceholder standing for the
+	// name of the workspace.
+	BackendKeyPattern string `hcl:"backend_key_pattern,optional"`
+
+	Workspaces []*Workspace `hcl:"workspace,block"`
+
+	// Dir is the directory containing the file, which relative paths in the
+	// file are resolved against.
+	Dir string
+}
+
+// Workspace represents a workspace block in a workspace file.
+type Workspace struct {
+	Name string `hcl:"name,label"`
+
+	// VarFiles are variable definitions files that are loaded whenever the
+	// workspace is selected, as if they were given with -var-file.
+	VarFiles []string `hcl:"var_files,optional"`
+
+	// EncryptionFile is a file containing an encryption configuration that
+	// is merged into the encryption block of the configuration whenever the
+	// workspace is selected.
+	EncryptionFile string `hcl:"encryption_file,optional"`
+
+	// BackendKey is the key under which the backend must store the state of
+	// the workspace, overriding the backend key pattern of the file.
+	BackendKey string `hcl:"backend_key,optional"`
+
+	DeclRange hcl.Range
+}
+
+// Load reads the workspace file from the given directory. It returns nil
+// without diagnostics if the directory has no workspace file.
+func Load(dir string) (*File, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	filename := filepath.Join(dir, Filename)
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, diags
+	}
+
+	parser := hclparse.NewParser()
+	f, hclDiags := parser.ParseHCLFile(filename)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := &File{Dir: dir}
+	hclDiags = gohcl.DecodeBody(f.Body, nil, ret)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	// gohcl does not record the ranges of blocks, so we find them again to
+	// be able to refer to them in diagnostics.
+	content, _ := f.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "backend_key_pattern"}},
+		Blocks:     []hcl.BlockHeaderSchema{{Type: "workspace", LabelNames: []string{"name"}}},
+	})
+	for i, block := range content.Blocks {
+		if i < len(ret.Workspaces) {
+			ret.Workspaces[i].DeclRange = block.DefRange
+		}
+	}
+
+	if ret.BackendKeyPattern != "" && !strings.Contains(ret.BackendKeyPattern, WorkspacePlaceholder) {
+		var subject *hcl.Range
+		if attr, ok := content.Attributes["backend_key_pattern"]; ok {
+			subject = attr.Expr.Range().Ptr()
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid backend key pattern",
+			Detail:   fmt.Sprintf("The backend key pattern must contain %s, which stands for the name of each workspace.", WorkspacePlaceholder),
+			Subject:  subject,
+		})
+	}
+
+	seen := make(map[string]*Workspace)
+	for _, ws := range ret.Workspaces {
+		if ws.Name == "" || ws.Name != url.PathEscape(ws.Name) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid workspace name",
+				Detail:   fmt.Sprintf("The workspace name %q is not allowed. The name must contain only URL safe characters, and no path separators.", ws.Name),
+				Subject:  ws.DeclRange.Ptr(),
+			})
+			continue
+		}
+		if prev, ok := seen[ws.Name]; ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspace",
+				Detail:   fmt.Sprintf("The workspace %q was already declared at %s.", ws.Name, prev.DeclRange),
+				Subject:  ws.DeclRange.Ptr(),
+			})
+			continue
+		}
+		seen[ws.Name] = ws
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return ret, diags
+}
+
+// Workspace returns the declaration of the workspace with the given name, or
+// nil if the file doesn't declare it. It is safe to call on a nil file.
+func (f *File) Workspace(name string) *Workspace {
+	if f == nil {
+		return nil
+	}
+	for _, ws := range f.Workspaces {
+		if ws.Name == name {
+			return ws
+		}
+	}
+	return nil
+}
+
+// VarFilePaths returns the paths of the variable definitions files of the
+// given workspace, resolved against the directory of the workspace file.
+func (f *File) VarFilePaths(ws *Workspace) []string {
+	paths := make([]string, len(ws.VarFiles))
+	for i, path := range ws.VarFiles {
+		paths[i] = f.path(path)
+	}
+	return paths
+}
+
+// EncryptionFilePath returns the path of the encryption configuration file of
+// the given workspace, resolved against the directory of the workspace file,
+// or an empty string if it has none.
+func (f *File) EncryptionFilePath(ws *Workspace) string {
+	if ws.EncryptionFile == "" {
+		return ""
+	}
+	return f.path(ws.EncryptionFile)
+}
+
+// BackendKey returns the key under which the backend must store the state of
+// the given workspace, or an empty string if the file doesn't declare it.
+func (f *File) BackendKey(ws *Workspace) string {
+	if ws.BackendKey != "" {
+		return ws.BackendKey
+	}
+	return strings.ReplaceAll(f.BackendKeyPattern, WorkspacePlaceholder, ws.Name)
+}
+
+func (f *File) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(f.Dir, path)
+}
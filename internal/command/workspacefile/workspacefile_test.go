@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workspacefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want map[string]*Workspace
+		err  string
+	}{
+		"valid": {
+			src: `
+workspace "dev" {
+  var_files = ["env/dev.tfvars"]
+}
+workspace "prod" {
+  var_files       = ["env/common.tfvars", "/etc/tofu/prod.tfvars"]
+  encryption_file = "env/prod.encryption.hcl"
+}
+`,
+			want: map[string]*Workspace{
+				"dev": {
+					Name:     "dev",
+					VarFiles: []string{"env/dev.tfvars"},
+				},
+				"prod": {
+					Name:           "prod",
+					VarFiles:       []string{"env/common.tfvars", "/etc/tofu/prod.tfvars"},
+					EncryptionFile: "env/prod.encryption.hcl",
+				},
+			},
+		},
+		"duplicate": {
+			src: `
+workspace "dev" {}
+workspace "dev" {}
+`,
+			err: "Duplicate workspace",
+		},
+		"invalid name": {
+			src: `workspace "dev/a" {}`,
+			err: "Invalid workspace name",
+		},
+		"unknown argument": {
+			src: `
+workspace "dev" {
+  backend = "s3"
+}
+`,
+			err: "Unsupported argument",
+		},
+		"expression": {
+			src: `
+workspace "dev" {
+  var_files = [var.file]
+}
+`,
+			err: "Variables not allowed",
+		},
+		"backend key pattern without placeholder": {
+			src: `
+backend_key_pattern = "states/dev.tfstate"
+workspace "dev" {}
+`,
+			err: "Invalid backend key pattern",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, Filename), []byte(test.src), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			file, diags := Load(dir)
+			if test.err != "" {
+				if !diags.HasErrors() {
+					t.Fatal("expected an error")
+				}
+				if msg := diags.Err().Error(); !strings.Contains(msg, test.err) {
+					t.Fatalf("wrong error %q; want %q", msg, test.err)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+
+			got := make(map[string]*Workspace)
+			for _, ws := range file.Workspaces {
+				if ws.DeclRange.Filename == "" {
+					t.Errorf("missing declaration range for %q", ws.Name)
+				}
+				got[ws.Name] = &Workspace{Name: ws.Name, VarFiles: ws.VarFiles, EncryptionFile: ws.EncryptionFile}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Fatalf("wrong result\n%s", diff)
+			}
+
+			prod := file.Workspace("prod")
+			wantPaths := []string{filepath.Join(dir, "env/common.tfvars"), "/etc/tofu/prod.tfvars"}
+			if diff := cmp.Diff(wantPaths, file.VarFilePaths(prod)); diff != "" {
+				t.Errorf("wrong variables file paths\n%s", diff)
+			}
+			if got, want := file.EncryptionFilePath(prod), filepath.Join(dir, "env/prod.encryption.hcl"); got != want {
+				t.Errorf("wrong encryption file path %q; want %q", got, want)
+			}
+			if file.Workspace("staging") != nil {
+				t.Error("found an undeclared workspace")
+			}
+		})
+	}
+}
+
+func TestFile_BackendKey(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, Filename), []byte(`
+backend_key_pattern = "states/{workspace}/terraform.tfstate"
+workspace "dev" {}
+workspace "prod" {
+  backend_key = "legacy/prod.tfstate"
+}
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, diags := Load(dir)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	for name, want := range map[string]string{
+		"dev":  "states/dev/terraform.tfstate",
+		"prod": "legacy/prod.tfstate",
+	} {
+		if got := file.BackendKey(file.Workspace(name)); got != want {
+			t.Errorf("wrong backend key for %q: %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestLoad_missing(t *testing.T) {
+	file, diags := Load(t.TempDir())
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if file != nil {
+		t.Fatal("expected no workspace file")
+	}
+	if file.Workspace("dev") != nil {
+		t.Fatal("a missing workspace file must not declare workspaces")
+	}
+}
@@ -71,6 +71,14 @@ func (b *Backend) Hash(schema *configschema.Block) (int, hcl.Diagnostics) {
 			Subject:  b.DeclRange.Ptr(),
 		})
 	}
+	if marks.Contains(val, marks.Ephemeral) {
+		return -1, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Backend config contains ephemeral values",
+			Detail:   "The backend configuration is stored in .terraform/terraform.tfstate as well as plan files, so it cannot refer to ephemeral values. It is recommended to instead supply short-lived credentials via backend specific environment variables",
+			Subject:  b.DeclRange.Ptr(),
+		})
+	}
 
 	toHash := cty.TupleVal([]cty.Value{
 		cty.StringVal(b.Type),
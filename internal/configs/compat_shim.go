@@ -80,6 +80,7 @@ func shimTraversalInString(expr hcl.Expression, wantKeyword bool) (hcl.Expressio
 			Summary:  "Quoted keywords are deprecated",
 			Detail:   "In this context, keywords are expected literally rather than in quotes. OpenTofu 0.11 and earlier required quotes, but quoted keywords are now deprecated and will be removed in a future version of OpenTofu. Remove the quotes surrounding this keyword to silence this warning.",
 			Subject:  &srcRange,
+			Extra:    deprecationDiagExtra{},
 		})
 	} else {
 		diags = append(diags, &hcl.Diagnostic{
@@ -87,6 +88,7 @@ func shimTraversalInString(expr hcl.Expression, wantKeyword bool) (hcl.Expressio
 			Summary:  "Quoted references are deprecated",
 			Detail:   "In this context, references are expected literally rather than in quotes. OpenTofu 0.11 and earlier required quotes, but quoted references are now deprecated and will be removed in a future version of OpenTofu. Remove the quotes surrounding this reference to silence this warning.",
 			Subject:  &srcRange,
+			Extra:    deprecationDiagExtra{},
 		})
 	}
 
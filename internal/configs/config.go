@@ -460,6 +460,17 @@ func (c *Config) addProviderRequirements(reqs getproviders.Requirements, qualifs
 		})
 		reqs[fqn] = nil
 	}
+	for _, rc := range c.Module.EphemeralResources {
+		fqn := rc.Provider
+		qualifs.AddImplicitProvider(fqn, getproviders.ResourceRef{
+			CfgRes:            rc.Addr().InModule(c.Path),
+			Ref:               tfdiags.SourceRangeFromHCL(rc.DeclRange),
+			ProviderAttribute: rc.ProviderConfigRef != nil,
+		})
+		if _, exists := reqs[fqn]; !exists {
+			reqs[fqn] = nil
+		}
+	}
 
 	// Import blocks that are generating config may also have a custom provider
 	// meta argument. Like the provider meta argument used in resource blocks,
@@ -897,11 +908,13 @@ func (c *Config) TransformForTest(run *TestRun, file *TestFile, evalCtx *hcl.Eva
 	var diags hcl.Diagnostics
 
 	// These transformation functions must be in sync of what is being transformed,
-	// currently all the functions operate on different fields of configuration.
+	// currently all the functions operate on different fields of configuration,
+	// except for overridden and stubbed modules, which must have different targets.
 	transformFuncs := []testConfigTransformFunc{
 		c.getProviderConfigTransformForTest(evalCtx),
 		c.transformOverriddenResourcesForTest,
 		c.transformOverriddenModulesForTest,
+		c.transformStubbedModulesForTest,
 	}
 
 	var resetFuncs []func()
@@ -1002,8 +1015,10 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 					IsMocked:          testProvider.IsMocked,
 					MockResources:     testProvider.MockResources,
 					OverrideResources: testProvider.OverrideResources,
+					ForEach:           testProvider.ForEach,
+					Count:             testProvider.Count,
 				}
-
+				diags = append(diags, next[ref.InChild.String()].decodeTestInstances(evalCtx)...)
 			}
 		} else {
 			// Otherwise, let's copy over and overwrite all providers specified by
@@ -1014,6 +1029,7 @@ func (c *Config) getProviderConfigTransformForTest(evalCtx *hcl.EvalContext) tes
 				if ctxRunOutputExists && provider.Config != nil {
 					provider.Config = testProviderBody{originalBody: provider.Config, evalCtx: evalCtx}
 				}
+				diags = append(diags, provider.decodeTestInstances(evalCtx)...)
 				next[key] = provider
 			}
 			for _, mp := range file.MockProviders {
@@ -1159,6 +1175,100 @@ func (c *Config) transformOverriddenModulesForTest(run *TestRun, file *TestFile)
 	}, diags
 }
 
+func (c *Config) transformStubbedModulesForTest(run *TestRun, file *TestFile) (func(), hcl.Diagnostics) {
+	modules, diags := mergeStubbedModules(run.StubModules, file.StubModules)
+
+	// Stubs and overrides set the same fields, so a module that is stubbed in
+	// one scope and overridden in the other can't be transformed by both.
+	overrides, _ := mergeOverriddenModules(run.OverrideModules, file.OverrideModules)
+	overridden := make(map[string]bool, len(overrides))
+	for _, o := range overrides {
+		overridden[o.TargetParsed.String()] = true
+	}
+
+	var stubbed []*StubModule
+	for _, stub := range modules {
+		targetConfig := c.Root.Descendent(stub.TargetParsed)
+		if targetConfig == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Module not found: %v", stub.TargetParsed),
+				Detail:   "Target points to an undefined module. Please, ensure module exists.",
+				Subject:  stub.Target.SourceRange().Ptr(),
+			})
+			continue
+		}
+
+		if overridden[stub.TargetParsed.String()] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting `stub_module` block",
+				Detail:   fmt.Sprintf("The module `%v` is already overridden by an `override_module` block, so it cannot also be replaced with a stub.", stub.TargetParsed),
+				Subject:  stub.Target.SourceRange().Ptr(),
+			})
+			continue
+		}
+
+		valid := true
+		for name, expr := range stub.Outputs {
+			if _, ok := targetConfig.Module.Outputs[name]; !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Output not found: %v", name),
+					Detail:   fmt.Sprintf("The stub declares an output value that the module `%v` does not declare. The outputs of a stub must match the outputs of the module it replaces.", stub.TargetParsed),
+					Subject:  expr.Range().Ptr(),
+				})
+				valid = false
+			}
+		}
+		for _, traversal := range stub.outputVariables() {
+			attr, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				// Invalid references are reported when the output is evaluated.
+				continue
+			}
+			if _, ok := targetConfig.Module.Variables[attr.Name]; !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Input variable not found: %v", attr.Name),
+					Detail:   fmt.Sprintf("The stub refers to an input variable that the module `%v` does not declare.", stub.TargetParsed),
+					Subject:  traversal.SourceRange().Ptr(),
+				})
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		targetConfig.Module.IsOverridden = true
+
+		for key, output := range targetConfig.Module.Outputs {
+			output.IsOverridden = true
+
+			// Outputs that are not declared in the stub are null.
+			if expr, ok := stub.Outputs[key]; ok {
+				output.OverrideExpr = expr
+			}
+		}
+
+		stubbed = append(stubbed, stub)
+	}
+
+	return func() {
+		for _, stub := range stubbed {
+			targetConfig := c.Root.Descendent(stub.TargetParsed)
+
+			targetConfig.Module.IsOverridden = false
+
+			for _, output := range targetConfig.Module.Outputs {
+				output.IsOverridden = false
+				output.OverrideExpr = nil
+			}
+		}
+	}, diags
+}
+
 func mergeOverriddenResources(runResources, fileResources []*OverrideResource) ([]*OverrideResource, hcl.Diagnostics) {
 	// resAddrsInRun is a unique set of resource addresses in run block.
 	// It's already validated for duplicates previously.
@@ -1191,6 +1301,38 @@ func mergeOverriddenResources(runResources, fileResources []*OverrideResource) (
 	return resources, diags
 }
 
+func mergeStubbedModules(runModules, fileModules []*StubModule) ([]*StubModule, hcl.Diagnostics) {
+	// modAddrsInRun is a unique set of module addresses in run block.
+	// It's already validated for duplicates previously.
+	modAddrsInRun := make(map[string]struct{})
+	for _, m := range runModules {
+		modAddrsInRun[m.TargetParsed.String()] = struct{}{}
+	}
+
+	var diags hcl.Diagnostics
+
+	modules := runModules
+	for _, m := range fileModules {
+		addr := m.TargetParsed.String()
+
+		// Run and file stub modules could have overlap
+		// so we warn user and proceed with the definition from the smaller scope.
+		if _, ok := modAddrsInRun[addr]; ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Multiple `stub_module` blocks for the same address",
+				Detail:   fmt.Sprintf("Module `%v` is stubbed in both global file and local run blocks. The declaration in global file block will be ignored.", addr),
+				Subject:  m.Target.SourceRange().Ptr(),
+			})
+			continue
+		}
+
+		modules = append(modules, m)
+	}
+
+	return modules, diags
+}
+
 func mergeOverriddenModules(runModules, fileModules []*OverrideModule) ([]*OverrideModule, hcl.Diagnostics) {
 	// modAddrsInRun is a unique set of module addresses in run block.
 	// It's already validated for duplicates previously.
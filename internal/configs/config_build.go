@@ -128,6 +128,16 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 	}
 	sort.Strings(callNames)
 
+	// The references in the parent module are only needed to report uses of
+	// deprecated outputs, so we find them only on first use.
+	var parentRefs []hcl.Traversal
+	parentRefsFunc := func() []hcl.Traversal {
+		if parentRefs == nil {
+			parentRefs = moduleTraversals(parent.Module)
+		}
+		return parentRefs
+	}
+
 	for _, callName := range callNames {
 		call := calls[callName]
 		path := make([]string, len(parent.Path)+1)
@@ -139,6 +149,7 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			Path:              path,
 			SourceAddr:        call.SourceAddr,
 			VersionConstraint: call.Version,
+			Integrity:         call.Integrity,
 			Parent:            parent,
 			CallRange:         call.DeclRange,
 			Call:              NewStaticModuleCall(path, call.Variables, parent.Root.Module.SourceDir, call.Workspace),
@@ -147,6 +158,9 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			// Invalid modules sometimes have a nil source field which is handled through loadModule below
 			req.SourceAddrRange = call.Source.Range()
 		}
+		if call.IntegrityAttr != nil {
+			req.IntegrityRange = call.IntegrityAttr.Expr.Range()
+		}
 		child, modDiags := loadModule(parent.Root, &req, walker)
 		diags = append(diags, modDiags...)
 		if child == nil {
@@ -155,6 +169,8 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			continue
 		}
 
+		diags = append(diags, checkModuleCallDeprecations(call, child.Module, parentRefsFunc)...)
+
 		ret[call.Name] = child
 	}
 
@@ -196,6 +212,15 @@ func loadModule(root *Config, req *ModuleRequest, walker ModuleWalker) (*Config,
 		})
 	}
 
+	if mod.Workspaces != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Workspaces configuration ignored",
+			Detail:   "The selected workspace applies to the entire configuration, so OpenTofu expects the workspaces to be declared only in the root module.\n\nThis is a warning rather than an error because it's sometimes convenient to temporarily call a root module as a child module for testing purposes, but this workspaces block will have no effect.",
+			Subject:  mod.Workspaces.DeclRange.Ptr(),
+		})
+	}
+
 	if len(mod.Import) > 0 {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -289,6 +314,13 @@ type ModuleRequest struct {
 	// available versions of a module whose source is otherwise valid.
 	VersionConstraint VersionConstraint
 
+	// Integrity is the hash that the content of the module package must
+	// match, from the "integrity" argument in configuration, or an empty
+	// string if the module call doesn't specify one. IntegrityRange is the
+	// source range of that argument.
+	Integrity      string
+	IntegrityRange hcl.Range
+
 	// Parent is the partially-constructed module tree node that the loaded
 	// module will be added to. Callers may refer to any field of this
 	// structure except Children, which is still under construction when
@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/google/go-cmp/cmp"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
@@ -162,6 +163,94 @@ func TestBuildConfigChildModuleBackend(t *testing.T) {
 	}
 }
 
+func TestBuildConfigChildModuleDeprecations(t *testing.T) {
+	parser := NewParser(nil)
+	mod, diags := parser.LoadConfigDir("testdata/nested-deprecation-warning", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+	if mod == nil {
+		t.Fatal("got nil root module; want non-nil")
+	}
+
+	_, diags = BuildConfig(mod, ModuleWalkerFunc(
+		func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			sourcePath := filepath.Join("testdata/nested-deprecation-warning", req.SourceAddr.String())
+
+			mod, modDiags := parser.LoadConfigDir(sourcePath, req.Call)
+			version, _ := version.NewVersion("1.0.0")
+			return mod, version, modDiags
+		},
+	))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, fmt.Sprintf("%s: %s: %s", diag.Subject, diag.Summary, diag.Detail))
+	}
+	want := []string{
+		`testdata/nested-deprecation-warning/root.tf:4,3-17: Deprecated variable: The input variable "old_name" of module "child" is deprecated: Use new_name instead.
+
+The variable is declared as deprecated at testdata/nested-deprecation-warning/child/child.tf:1,1-20.`,
+		`testdata/nested-deprecation-warning/root.tf:9,9-32: Deprecated output value: The output value "old_output" of module "child" is deprecated: Use new_output instead.
+
+The output value is declared as deprecated at testdata/nested-deprecation-warning/child/child.tf:11,1-20.`,
+		`testdata/nested-deprecation-warning/root.tf:14,14-37: Deprecated output value: The output value "old_output" of module "child" is deprecated: Use new_output instead.
+
+The output value is declared as deprecated at testdata/nested-deprecation-warning/child/child.tf:11,1-20.`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+
+	upgraded := UpgradeDeprecations(diags)
+	if got, want := len(upgraded.Errs()), len(diags); got != want {
+		t.Errorf("wrong number of errors in strict deprecation mode %d; want %d", got, want)
+	}
+	if diags.HasErrors() {
+		t.Errorf("UpgradeDeprecations modified the original diagnostics")
+	}
+}
+
+func TestParserStrictDeprecations(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf": `
+provider "test" {
+  version = "1.0.0"
+}
+
+resource "test_instance" "foo" {
+  provider = "test"
+}
+`,
+	})
+
+	_, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors without strict deprecation mode: %s", diags.Error())
+	}
+	if got, want := len(diags), 2; got != want {
+		t.Fatalf("wrong number of warnings %d; want %d\n%s", got, want, diags.Error())
+	}
+
+	parser.StrictDeprecations(true)
+	_, diags = parser.LoadConfigFile("main.tf")
+	var got []string
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			t.Errorf("unexpected warning in strict deprecation mode: %s", diag.Summary)
+		}
+		got = append(got, diag.Summary)
+	}
+	want := []string{
+		"Version constraints inside provider configuration blocks are deprecated",
+		"Quoted references are deprecated",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
+
 func TestBuildConfigInvalidModules(t *testing.T) {
 	testDir := "testdata/config-diagnostics"
 	dirs, err := os.ReadDir(testDir)
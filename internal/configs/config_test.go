@@ -657,6 +657,26 @@ func TestConfigProviderForConfigAddr(t *testing.T) {
 	}
 }
 
+func TestConfigUnusedProviderConfigs(t *testing.T) {
+	cfg, diags := testNestedModuleConfigFromDir(t, "testdata/unused-providers")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	var got []string
+	for _, pc := range cfg.UnusedProviderConfigs() {
+		got = append(got, fmt.Sprintf("%s at %s", pc.Addr, pc.Config.DeclRange))
+	}
+	want := []string{
+		`module.child.provider["registry.opentofu.org/hashicorp/bar"] at testdata/unused-providers/child/main.tf:14,1-15`,
+		`provider["registry.opentofu.org/hashicorp/baz"] at testdata/unused-providers/main.tf:34,1-15`,
+		`provider["registry.opentofu.org/hashicorp/foo"].west at testdata/unused-providers/main.tf:25,1-15`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
 func TestConfigAddProviderRequirements(t *testing.T) {
 	cfg, diags := testModuleConfigFromFile("testdata/valid-files/providers-explicit-implied.tf")
 	assertNoDiagnostics(t, diags)
@@ -29,6 +29,11 @@ type Loader struct {
 	// modules is used to install and locate descendent modules that are
 	// referenced (directly or indirectly) from the root module.
 	modules moduleMgr
+
+	// strictDeprecations is set when the parser is in strict deprecation
+	// mode, so that we also upgrade the deprecation warnings from building
+	// the configuration.
+	strictDeprecations bool
 }
 
 // Config is used with NewLoader to specify configuration arguments for the
@@ -167,3 +172,11 @@ func (l *Loader) ImportSourcesFromSnapshot(snap *Snapshot) {
 func (l *Loader) AllowLanguageExperiments(allowed bool) {
 	l.parser.AllowLanguageExperiments(allowed)
 }
+
+// StrictDeprecations specifies whether subsequent LoadConfig (and similar)
+// calls will report uses of deprecated language features as errors rather
+// than as warnings.
+func (l *Loader) StrictDeprecations(strict bool) {
+	l.strictDeprecations = strict
+	l.parser.StrictDeprecations(strict)
+}
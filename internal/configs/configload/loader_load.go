@@ -46,6 +46,9 @@ func (l *Loader) loadConfig(rootMod *configs.Module, diags hcl.Diagnostics) (*co
 	}
 
 	cfg, cDiags := configs.BuildConfig(rootMod, configs.ModuleWalkerFunc(l.moduleWalkerLoad))
+	if l.strictDeprecations {
+		cDiags = configs.UpgradeDeprecations(cDiags)
+	}
 	diags = append(diags, cDiags...)
 
 	return cfg, diags
@@ -156,3 +156,45 @@ func (o *Object) ValueMarks(val cty.Value, path cty.Path) []cty.PathValueMarks {
 	}
 	return pvm
 }
+
+// SensitiveStrings returns the known string values within the given value
+// that are sensitive, either because the schema marks their attribute as
+// sensitive or because the value itself is marked as sensitive, such as when
+// it was derived from a sensitive input variable.
+//
+// This is intended for masking sensitive values in output that can't carry
+// marks, such as logs, and so it doesn't return the paths of the values. A
+// nil block is treated as having no sensitive attributes.
+func (b *Block) SensitiveStrings(val cty.Value) []string {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+	if b != nil {
+		val = val.MarkWithPaths(b.ValueMarks(val, nil))
+	}
+
+	var ret []string
+	collectSensitiveStrings(val, false, &ret)
+	return ret
+}
+
+func collectSensitiveStrings(val cty.Value, sensitive bool, ret *[]string) {
+	sensitive = sensitive || val.HasMark(marks.Sensitive)
+	val, _ = val.Unmark()
+	if val.IsNull() || !val.IsKnown() {
+		return
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		if sensitive {
+			*ret = append(*ret, val.AsString())
+		}
+	case ty.IsObjectType() || ty.IsTupleType() || ty.IsCollectionType():
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			collectSensitiveStrings(v, sensitive, ret)
+		}
+	}
+}
@@ -6,8 +6,11 @@
 package configschema
 
 import (
+	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -185,3 +188,74 @@ func TestBlockValueMarks(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockSensitiveStrings(t *testing.T) {
+	schema := &Block{
+		Attributes: map[string]*Attribute{
+			"region": {
+				Type:     cty.String,
+				Optional: true,
+			},
+			"token": {
+				Type:      cty.String,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"headers": {
+				Type:     cty.Map(cty.String),
+				Optional: true,
+			},
+			"credentials": {
+				NestedType: &Object{
+					Attributes: map[string]*Attribute{
+						"user":     {Type: cty.String},
+						"password": {Type: cty.String, Sensitive: true},
+					},
+					Nesting: NestingSingle,
+				},
+				Optional: true,
+			},
+		},
+		BlockTypes: map[string]*NestedBlock{
+			"assume_role": {
+				Nesting: NestingList,
+				Block: Block{
+					Attributes: map[string]*Attribute{
+						"role_arn":    {Type: cty.String, Optional: true},
+						"external_id": {Type: cty.String, Optional: true, Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	val := cty.ObjectVal(map[string]cty.Value{
+		"region": cty.StringVal("us-east-1"),
+		"token":  cty.StringVal("secret-token"),
+		"headers": cty.MapVal(map[string]cty.Value{
+			"Authorization": cty.StringVal("Bearer from-variable").Mark(marks.Sensitive),
+			"Accept":        cty.StringVal("application/json"),
+		}),
+		"credentials": cty.ObjectVal(map[string]cty.Value{
+			"user":     cty.StringVal("admin"),
+			"password": cty.StringVal("hunter2"),
+		}),
+		"assume_role": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"role_arn":    cty.StringVal("arn:aws:iam::123456789012:role/example"),
+				"external_id": cty.StringVal("external"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"role_arn":    cty.StringVal("arn:aws:iam::123456789012:role/other"),
+				"external_id": cty.UnknownVal(cty.String),
+			}),
+		}),
+	})
+
+	got := schema.SensitiveStrings(val)
+	sort.Strings(got)
+	want := []string{"Bearer from-variable", "external", "hunter2", "secret-token"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
@@ -0,0 +1,534 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configwrite
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// ConfigWriter writes configuration structures decoded by package configs,
+// such as *configs.Provider and *configs.ModuleCall, back out as blocks.
+//
+// When the source code that a structure was decoded from is available, the
+// writer starts from a copy of the original block, so that comments, nested
+// blocks and arguments that the structure doesn't describe are preserved,
+// and then updates the block to match the fields of the structure. This
+// allows callers to decode a configuration, change some of its structures
+// and write them back without losing anything they didn't change.
+//
+// Otherwise, such as for structures built by the caller, the writer builds a
+// new block from the fields of the structure alone.
+type ConfigWriter struct {
+	sources map[string]*hcl.File
+	bodies  map[string]*hclsyntax.Body
+}
+
+// NewConfigWriter returns a writer that looks for the source code of the
+// structures it writes in the given files, as returned by the Sources method
+// of the parser that decoded them.
+//
+// The sources may be nil, in which case every block is built from the
+// fields of its structure.
+func NewConfigWriter(sources map[string]*hcl.File) *ConfigWriter {
+	return &ConfigWriter{
+		sources: sources,
+		bodies:  make(map[string]*hclsyntax.Body),
+	}
+}
+
+// Provider returns a provider block for the given provider configuration.
+//
+// The alias, version, count, for_each and depends_on arguments are written
+// from the fields of the configuration. The provider's own arguments and the
+// lifecycle block are copied from the source code, if it's available.
+func (w *ConfigWriter) Provider(p *configs.Provider) (*Block, error) {
+	block, fromSource, err := w.sourceBlock(p.DeclRange)
+	if err != nil {
+		return nil, err
+	}
+	if !fromSource {
+		block = NewBlock("provider", p.Name)
+		if err := w.writeRemainingBody(block.Body(), p.Config); err != nil {
+			return nil, fmt.Errorf("writing provider %q: %w", p.Name, err)
+		}
+	}
+	block.b.SetLabels([]string{p.Name})
+
+	body := block.Body()
+	body.setString("alias", p.Alias)
+	body.setVersion("version", p.Version)
+	if err := w.setExpr(body, "count", p.Count); err != nil {
+		return nil, err
+	}
+	if err := w.setExpr(body, "for_each", p.ForEach); err != nil {
+		return nil, err
+	}
+	body.setTraversals("depends_on", p.DependsOn)
+	return tidyBlock(block)
+}
+
+// RequiredProviders returns a required_providers block, to be appended to a
+// terraform block, for the given provider requirements.
+//
+// Entries that are no longer required are removed and new entries are
+// appended in name order. Comments before and after each existing entry are
+// preserved, but each entry is rewritten in the canonical object form, so
+// comments within an entry are not.
+func (w *ConfigWriter) RequiredProviders(reqs *configs.RequiredProviders) (*Block, error) {
+	block, fromSource, err := w.sourceBlock(reqs.DeclRange)
+	if err != nil {
+		return nil, err
+	}
+	if !fromSource {
+		block = NewBlock("required_providers")
+	}
+
+	body := block.Body()
+	for name := range body.b.Attributes() {
+		if _, ok := reqs.RequiredProviders[name]; !ok {
+			body.b.RemoveAttribute(name)
+		}
+	}
+
+	names := make([]string, 0, len(reqs.RequiredProviders))
+	for name := range reqs.RequiredProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		body.b.SetAttributeRaw(name, RequiredProviderTokens(reqs.RequiredProviders[name]))
+	}
+	return tidyBlock(block)
+}
+
+// RequiredProviderTokens returns the canonical object form of the given
+// entry of a required_providers block, for use as the value of its attribute.
+func RequiredProviderTokens(req *configs.RequiredProvider) hclwrite.Tokens {
+	var attrs []hclwrite.ObjectAttrTokens
+	if req.Source != "" {
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier("source"),
+			Value: hclwrite.TokensForValue(cty.StringVal(req.Source)),
+		})
+	}
+	if len(req.Requirement.Required) != 0 {
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier("version"),
+			Value: hclwrite.TokensForValue(cty.StringVal(req.Requirement.Required.String())),
+		})
+	}
+	if len(req.Aliases) != 0 {
+		aliases := make([]hclwrite.Tokens, len(req.Aliases))
+		for i, alias := range req.Aliases {
+			aliases[i] = hclwrite.TokensForTraversal(localProviderConfigTraversal(alias))
+		}
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier("configuration_aliases"),
+			Value: hclwrite.TokensForTuple(aliases),
+		})
+	}
+	return hclwrite.TokensForObject(attrs)
+}
+
+// ModuleCall returns a module block for the given module call.
+//
+// The source, version, integrity, count, for_each, providers and depends_on
+// arguments and the enabled argument of the lifecycle block are written from
+// the fields of the module call. The input variables and the rest of the
+// lifecycle block are copied from the source code, if it's available.
+func (w *ConfigWriter) ModuleCall(mc *configs.ModuleCall) (*Block, error) {
+	block, fromSource, err := w.sourceBlock(mc.DeclRange)
+	if err != nil {
+		return nil, err
+	}
+	if !fromSource {
+		block = NewBlock("module", mc.Name)
+		if err := w.writeRemainingBody(block.Body(), mc.Config); err != nil {
+			return nil, fmt.Errorf("writing module %q: %w", mc.Name, err)
+		}
+	}
+	block.b.SetLabels([]string{mc.Name})
+
+	body := block.Body()
+	if mc.Source != nil {
+		if err := w.setExpr(body, "source", mc.Source); err != nil {
+			return nil, err
+		}
+	} else {
+		body.setString("source", mc.SourceAddrRaw)
+	}
+	if mc.VersionAttr != nil {
+		if err := w.setExpr(body, "version", mc.VersionAttr.Expr); err != nil {
+			return nil, err
+		}
+	} else {
+		body.setVersion("version", mc.Version)
+	}
+	if mc.IntegrityAttr != nil {
+		if err := w.setExpr(body, "integrity", mc.IntegrityAttr.Expr); err != nil {
+			return nil, err
+		}
+	} else {
+		body.setString("integrity", mc.Integrity)
+	}
+	if err := w.setExpr(body, "count", mc.Count); err != nil {
+		return nil, err
+	}
+	if err := w.setExpr(body, "for_each", mc.ForEach); err != nil {
+		return nil, err
+	}
+
+	if len(mc.Providers) == 0 {
+		body.b.RemoveAttribute("providers")
+	} else {
+		attrs := make([]hclwrite.ObjectAttrTokens, len(mc.Providers))
+		for i, passed := range mc.Providers {
+			parent, err := w.providerConfigRefTokens(passed.InParent)
+			if err != nil {
+				return nil, err
+			}
+			if passed.AllInstances {
+				parent = append(parent,
+					&hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")},
+					&hclwrite.Token{Type: hclsyntax.TokenStar, Bytes: []byte("*")},
+					&hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")},
+				)
+			}
+			child, err := w.providerConfigRefTokens(passed.InChild)
+			if err != nil {
+				return nil, err
+			}
+			attrs[i] = hclwrite.ObjectAttrTokens{Name: child, Value: parent}
+		}
+		body.b.SetAttributeRaw("providers", hclwrite.TokensForObject(attrs))
+	}
+
+	body.setTraversals("depends_on", mc.DependsOn)
+	if err := w.setLifecycleExpr(body, "enabled", mc.Enabled); err != nil {
+		return nil, err
+	}
+	return tidyBlock(block)
+}
+
+// Resource returns a resource, data or ephemeral block for the given
+// resource.
+//
+// The count, for_each, provider and depends_on arguments and the enabled
+// argument of the lifecycle block are written from the fields of the
+// resource. The resource's own arguments, the rest of the lifecycle block
+// and any provisioner and connection blocks are copied from the source
+// code, if it's available.
+func (w *ConfigWriter) Resource(r *configs.Resource) (*Block, error) {
+	block, fromSource, err := w.sourceBlock(r.DeclRange)
+	if err != nil {
+		return nil, err
+	}
+	addr := r.Addr()
+	if !fromSource {
+		block = NewResourceBlock(addr)
+		if err := w.writeRemainingBody(block.Body(), r.Config); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", addr, err)
+		}
+	}
+	block.b.SetLabels([]string{r.Type, r.Name})
+
+	body := block.Body()
+	if err := w.setExpr(body, "count", r.Count); err != nil {
+		return nil, err
+	}
+	if err := w.setExpr(body, "for_each", r.ForEach); err != nil {
+		return nil, err
+	}
+	if r.ProviderConfigRef == nil {
+		body.b.RemoveAttribute("provider")
+	} else {
+		tokens, err := w.providerConfigRefTokens(r.ProviderConfigRef)
+		if err != nil {
+			return nil, err
+		}
+		body.b.SetAttributeRaw("provider", tokens)
+	}
+	body.setTraversals("depends_on", r.DependsOn)
+	if err := w.setLifecycleExpr(body, "enabled", r.Enabled); err != nil {
+		return nil, err
+	}
+	return tidyBlock(block)
+}
+
+// sourceBlock returns a copy of the block whose definition starts at the
+// given range, including the comments directly above it. It returns false
+// if the source code of the block isn't available.
+func (w *ConfigWriter) sourceBlock(declRange hcl.Range) (*Block, bool, error) {
+	file, ok := w.sources[declRange.Filename]
+	if !ok || file == nil || isJSONFilename(declRange.Filename) {
+		return nil, false, nil
+	}
+	body, ok := w.bodies[declRange.Filename]
+	if !ok {
+		parsed, diags := hclsyntax.ParseConfig(file.Bytes, declRange.Filename, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, false, diags
+		}
+		body = parsed.Body.(*hclsyntax.Body)
+		w.bodies[declRange.Filename] = body
+	}
+
+	syntaxBlock := findSyntaxBlock(body, declRange.Start.Byte)
+	if syntaxBlock == nil {
+		return nil, false, nil
+	}
+	start := leadCommentsStart(file.Bytes, syntaxBlock.TypeRange.Start.Byte)
+	src := file.Bytes[start:syntaxBlock.Range().End.Byte]
+	f, diags := hclwrite.ParseConfig(src, declRange.Filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false, diags
+	}
+	blocks := f.Body().Blocks()
+	if len(blocks) != 1 {
+		// This should not happen, since the source is exactly one block.
+		return nil, false, fmt.Errorf("the source code at %s is not a single block", declRange)
+	}
+	return &Block{b: blocks[0]}, true, nil
+}
+
+// tidyBlock returns a copy of the given block without the extra empty lines
+// that removing arguments can leave behind: at most one empty line is kept
+// between items, and none at the start or end of a body. The block also ends
+// with a newline, like blocks built by hclwrite, even if it was copied from
+// source code that doesn't.
+func tidyBlock(block *Block) (*Block, error) {
+	endsLine := func(tok *hclwrite.Token) bool {
+		return tok.Type == hclsyntax.TokenNewline || (tok.Type == hclsyntax.TokenComment && bytes.HasSuffix(tok.Bytes, []byte("\n")))
+	}
+
+	var tokens hclwrite.Tokens
+	for _, tok := range block.b.BuildTokens(nil) {
+		n := len(tokens)
+		switch {
+		case tok.Type == hclsyntax.TokenNewline && n >= 2 && endsLine(tokens[n-1]) &&
+			(endsLine(tokens[n-2]) || tokens[n-2].Type == hclsyntax.TokenOBrace):
+			continue
+		case tok.Type == hclsyntax.TokenCBrace && n >= 2 && tokens[n-1].Type == hclsyntax.TokenNewline && endsLine(tokens[n-2]):
+			tokens = tokens[:n-1]
+		}
+		tokens = append(tokens, tok)
+	}
+
+	if n := len(tokens); n == 0 || !endsLine(tokens[n-1]) {
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+	}
+
+	f, diags := hclwrite.ParseConfig(tokens.Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		// This should not happen, since we only removed empty lines.
+		return nil, diags
+	}
+	return &Block{b: f.Body().Blocks()[0]}, nil
+}
+
+// findSyntaxBlock returns the block in the given body, or in a block nested
+// within it, whose type name starts at the given byte offset.
+func findSyntaxBlock(body *hclsyntax.Body, offset int) *hclsyntax.Block {
+	for _, block := range body.Blocks {
+		if block.TypeRange.Start.Byte == offset {
+			return block
+		}
+		if block.Range().Start.Byte <= offset && offset < block.Range().End.Byte {
+			return findSyntaxBlock(block.Body, offset)
+		}
+	}
+	return nil
+}
+
+// leadCommentsStart returns the offset of the start of the comment lines
+// directly above the line containing the given offset, or of that line
+// itself if there are none.
+func leadCommentsStart(src []byte, offset int) int {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	for start > 0 {
+		prev := bytes.LastIndexByte(src[:start-1], '\n') + 1
+		line := strings.TrimSpace(string(src[prev : start-1]))
+		if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "//") {
+			break
+		}
+		start = prev
+	}
+	return start
+}
+
+// writeRemainingBody writes the arguments of the given body, which is what
+// remains of a block after decoding the arguments that configs knows about,
+// for blocks whose source code isn't available.
+//
+// The body can only be written if it contains only arguments whose
+// expressions can be written without their source code.
+func (w *ConfigWriter) writeRemainingBody(body *Body, remain hcl.Body) error {
+	if remain == nil {
+		return nil
+	}
+	attrs, diags := remain.JustAttributes()
+	if diags.HasErrors() {
+		return fmt.Errorf("the body contains nested blocks, which can only be written from their source code")
+	}
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := w.setExpr(body, name, attrs[name].Expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setExpr sets the argument with the given name to the given expression, or
+// removes it if the expression is nil.
+func (w *ConfigWriter) setExpr(body *Body, name string, expr hcl.Expression) error {
+	if expr == nil {
+		body.b.RemoveAttribute(name)
+		return nil
+	}
+	tokens, err := w.exprTokens(expr)
+	if err != nil {
+		return fmt.Errorf("writing %s argument: %w", name, err)
+	}
+	body.b.SetAttributeRaw(name, tokens)
+	return nil
+}
+
+// setLifecycleExpr sets the argument with the given name in the lifecycle
+// block of the given body to the given expression, adding the block if
+// needed. If the expression is nil it removes the argument, along with the
+// block if nothing else remains in it.
+func (w *ConfigWriter) setLifecycleExpr(body *Body, name string, expr hcl.Expression) error {
+	lifecycle := body.b.FirstMatchingBlock("lifecycle", nil)
+	if expr == nil {
+		if lifecycle == nil {
+			return nil
+		}
+		lifecycle.Body().RemoveAttribute(name)
+		if len(lifecycle.Body().Attributes()) == 0 && len(lifecycle.Body().Blocks()) == 0 {
+			body.b.RemoveBlock(lifecycle)
+		}
+		return nil
+	}
+	if lifecycle == nil {
+		body.b.AppendNewline()
+		lifecycle = body.b.AppendNewBlock("lifecycle", nil)
+	}
+	return w.setExpr(&Body{b: lifecycle.Body()}, name, expr)
+}
+
+// exprTokens returns the tokens of the given expression. The source code of
+// the expression is copied if it's available, including any comments within
+// it. Otherwise, the expression can be written only if it's a reference or
+// its value doesn't depend on anything.
+func (w *ConfigWriter) exprTokens(expr hcl.Expression) (hclwrite.Tokens, error) {
+	rng := expr.Range()
+	if file, ok := w.sources[rng.Filename]; ok && file != nil && !isJSONFilename(rng.Filename) {
+		if rng.Start.Byte >= 0 && rng.Start.Byte <= rng.End.Byte && rng.End.Byte <= len(file.Bytes) {
+			return lexTokens(file.Bytes[rng.Start.Byte:rng.End.Byte], rng.Filename, rng.Start)
+		}
+	}
+	if traversal, diags := hcl.AbsTraversalForExpr(expr); !diags.HasErrors() {
+		return hclwrite.TokensForTraversal(traversal), nil
+	}
+	if len(expr.Variables()) == 0 {
+		if val, diags := expr.Value(nil); !diags.HasErrors() {
+			return hclwrite.TokensForValue(val), nil
+		}
+	}
+	return nil, fmt.Errorf("the source code of the expression at %s is not available", rng)
+}
+
+// providerConfigRefTokens returns the tokens of a reference to the given
+// provider configuration, as used in the provider argument of a resource and
+// the providers argument of a module call.
+func (w *ConfigWriter) providerConfigRefTokens(ref *configs.ProviderConfigRef) (hclwrite.Tokens, error) {
+	tokens := hclwrite.TokensForTraversal(localProviderConfigTraversal(addrs.LocalProviderConfig{
+		LocalName: ref.Name,
+		Alias:     ref.Alias,
+	}))
+	if ref.KeyExpression == nil {
+		return tokens, nil
+	}
+	var key hclwrite.Tokens
+	if val, diags := ref.KeyExpression.Value(nil); !diags.HasErrors() {
+		// A key written as a literal in a traversal is decoded as a static
+		// expression whose range is the whole traversal, so we must write
+		// its value rather than copying its source code.
+		key = hclwrite.TokensForValue(val)
+	} else {
+		var err error
+		key, err = w.exprTokens(ref.KeyExpression)
+		if err != nil {
+			return nil, fmt.Errorf("writing provider instance key: %w", err)
+		}
+	}
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")})
+	tokens = append(tokens, key...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+	return tokens, nil
+}
+
+// setString sets the argument with the given name to the given string, or
+// removes it if the string is empty.
+func (b *Body) setString(name string, s string) {
+	if s == "" {
+		b.b.RemoveAttribute(name)
+		return
+	}
+	b.b.SetAttributeValue(name, cty.StringVal(s))
+}
+
+// setVersion sets the argument with the given name to the given version
+// constraint, or removes it if there is no constraint.
+func (b *Body) setVersion(name string, constraint configs.VersionConstraint) {
+	if len(constraint.Required) == 0 {
+		b.b.RemoveAttribute(name)
+		return
+	}
+	b.b.SetAttributeValue(name, cty.StringVal(constraint.Required.String()))
+}
+
+// setTraversals sets the argument with the given name to a list of the given
+// references, or removes it if there are none.
+func (b *Body) setTraversals(name string, traversals []hcl.Traversal) {
+	if len(traversals) == 0 {
+		b.b.RemoveAttribute(name)
+		return
+	}
+	elems := make([]hclwrite.Tokens, len(traversals))
+	for i, traversal := range traversals {
+		elems[i] = hclwrite.TokensForTraversal(traversal)
+	}
+	b.b.SetAttributeRaw(name, hclwrite.TokensForTuple(elems))
+}
+
+func localProviderConfigTraversal(addr addrs.LocalProviderConfig) hcl.Traversal {
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: addr.LocalName}}
+	if addr.Alias != "" {
+		traversal = append(traversal, hcl.TraverseAttr{Name: addr.Alias})
+	}
+	return traversal
+}
+
+func isJSONFilename(filename string) bool {
+	return strings.HasSuffix(filename, ".json")
+}
@@ -0,0 +1,225 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configwrite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/afero"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func loadTestConfigFile(t *testing.T, src string) (*configs.File, map[string]*hcl.File) {
+	t.Helper()
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile("main.tf", []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parser := configs.NewParser(fs)
+	file, diags := parser.LoadConfigFile("main.tf")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return file, parser.Sources()
+}
+
+func TestConfigWriter_roundTrip(t *testing.T) {
+	src := `terraform {
+  # Providers used by this module.
+  required_providers {
+    aws = {
+      source                = "hashicorp/aws"
+      version               = "~> 5.0"
+      configuration_aliases = [aws.peer]
+    }
+  }
+}
+
+# One configuration for each region.
+provider "aws" {
+  alias    = "by_region"
+  for_each = toset(var.regions) # keep in sync with the modules
+
+  region = each.key // the region of this instance
+}
+
+module "network" {
+  source   = "./network"
+  for_each = toset(var.regions)
+
+  providers = {
+    aws = aws.by_region[each.key]
+  }
+
+  cidr = "10.0.0.0/16"
+}
+
+resource "aws_instance" "web" {
+  provider = aws.by_region["us-east-1"]
+
+  ami = "ami-123" # pinned
+
+  lifecycle {
+    enabled               = var.web
+    create_before_destroy = true
+  }
+}
+`
+	file, sources := loadTestConfigFile(t, src)
+	w := NewConfigWriter(sources)
+
+	var blocks []*Block
+	reqs, err := w.RequiredProviders(file.RequiredProviders[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	terraform := NewBlock("terraform")
+	terraform.Body().AppendBlock(reqs)
+	blocks = append(blocks, terraform)
+	provider, err := w.Provider(file.ProviderConfigs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks = append(blocks, provider)
+	module, err := w.ModuleCall(file.ModuleCalls[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks = append(blocks, module)
+	resource, err := w.Resource(file.ManagedResources[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks = append(blocks, resource)
+
+	f := NewFile()
+	for _, block := range blocks {
+		f.AppendBlock(block)
+	}
+	if diff := cmp.Diff(src, string(f.Bytes())); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigWriter_changes(t *testing.T) {
+	file, sources := loadTestConfigFile(t, `
+module "network" {
+  # Older releases don't support IPv6.
+  source = "example.com/net/network/aws"
+  count  = var.network ? 1 : 0
+
+  depends_on = [aws_vpc.main]
+
+  cidr = "10.0.0.0/16"
+}
+`)
+	w := NewConfigWriter(sources)
+
+	// Replace count with the enabled lifecycle argument and rename the
+	// module call, as a refactoring command might.
+	mc := file.ModuleCalls[0]
+	mc.Name = "net"
+	mc.Enabled = mc.Count.(*hclsyntax.ConditionalExpr).Condition
+	mc.Count = nil
+	mc.DependsOn = nil
+
+	block, err := w.ModuleCall(mc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `module "net" {
+  # Older releases don't support IPv6.
+  source = "example.com/net/network/aws"
+
+  cidr = "10.0.0.0/16"
+
+  lifecycle {
+    enabled = var.network
+  }
+}
+`
+	if diff := cmp.Diff(want, string(block.Bytes())); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigWriter_noSource(t *testing.T) {
+	w := NewConfigWriter(nil)
+
+	block, err := w.Provider(&configs.Provider{
+		Name:      "aws",
+		Alias:     "west",
+		Config:    hcl.EmptyBody(),
+		DependsOn: []hcl.Traversal{{hcl.TraverseRoot{Name: "aws_iam_role"}, hcl.TraverseAttr{Name: "deploy"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `provider "aws" {
+  alias      = "west"
+  depends_on = [aws_iam_role.deploy]
+}
+`
+	if diff := cmp.Diff(want, string(block.Bytes())); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	reqs, err := w.RequiredProviders(&configs.RequiredProviders{
+		RequiredProviders: map[string]*configs.RequiredProvider{
+			"aws": {
+				Name:    "aws",
+				Source:  "hashicorp/aws",
+				Aliases: []addrs.LocalProviderConfig{{LocalName: "aws", Alias: "peer"}},
+			},
+			"random": {
+				Name:   "random",
+				Source: "hashicorp/random",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `required_providers {
+  aws = {
+    source                = "hashicorp/aws"
+    configuration_aliases = [aws.peer]
+  }
+  random = {
+    source = "hashicorp/random"
+  }
+}
+`
+	if diff := cmp.Diff(want, string(reqs.Bytes())); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// Expressions that depend on other values can only be written from
+	// their source code.
+	expr, diags := hclsyntax.ParseExpression([]byte("length(var.names)"), "generated.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	_, err = w.Resource(&configs.Resource{
+		Mode:   addrs.ManagedResourceMode,
+		Type:   "aws_instance",
+		Name:   "web",
+		Config: hcl.EmptyBody(),
+		Count:  expr,
+	})
+	if err == nil {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := err.Error(), "writing count argument: the source code of the expression at generated.tf:1,1-18 is not available"; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package configwrite knows how to write configuration blocks, such as
+// resource, provider and module blocks, as canonically-formatted HCL source
+// code. It is used by features that generate configuration for the user to
+// review, such as import config generation, so that they all produce output
+// in the same style.
+//
+// It is a thin layer over package hclwrite, adding support for the shapes of
+// values and expressions that are common in OpenTofu configuration: values
+// that are JSON documents, multi-line strings that read better as heredocs,
+// and expressions that should be copied verbatim from existing source code.
+//
+// ConfigWriter writes the structures that package configs decodes, such as
+// provider configurations and module calls, back out as blocks, preserving
+// the comments and other content of the original source code where it's
+// available. This is the basis for commands that refactor configuration and
+// for tools that generate configuration from the same structures.
+package configwrite
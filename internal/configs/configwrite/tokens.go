@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configwrite
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// TokensForValue returns the tokens of a literal expression for the given
+// value, like hclwrite.TokensForValue, except that a string containing a JSON
+// object or array is written as a call to jsonencode, so that the document
+// can be read and edited as HCL.
+func TokensForValue(val cty.Value) hclwrite.Tokens {
+	if tokens, err := tokensForJSONString(val); err == nil {
+		return tokens
+	}
+	return hclwrite.TokensForValue(val)
+}
+
+func tokensForJSONString(val cty.Value) (hclwrite.Tokens, error) {
+	if val.IsNull() || val.Type() != cty.String || !val.IsKnown() {
+		return nil, errors.New("value cannot be treated as JSON string")
+	}
+
+	src := []byte(strings.TrimSpace(val.AsString()))
+	if len(src) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	if src[0] != '{' && src[0] != '[' {
+		return nil, errors.New("value is not a JSON object, nor a JSON array")
+	}
+
+	ty, err := ctyjson.ImpliedType(src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot define implied cty type (possibly not a JSON string): %w", err)
+	}
+
+	val, err = ctyjson.Unmarshal(src, ty)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal using implied type (possible not a JSON string): %w", err)
+	}
+
+	return hclwrite.TokensForFunctionCall("jsonencode", hclwrite.TokensForValue(val)), nil
+}
+
+// TokensForHeredoc returns the tokens of a heredoc template that evaluates to
+// the given string, with a final newline added if it doesn't have one. The
+// delimiter is chosen so that it doesn't appear as a line of the string.
+//
+// The lines are written without indentation, rather than as an indented
+// heredoc, so that any leading whitespace in the string is preserved.
+func TokensForHeredoc(content string) hclwrite.Tokens {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	delim := "EOT"
+	for i := 1; containsLine(lines, delim); i++ {
+		delim = fmt.Sprintf("EOT%d", i)
+	}
+
+	var body strings.Builder
+	for _, line := range lines {
+		// Template sequences must be escaped so that they are not evaluated.
+		line = strings.ReplaceAll(line, "${", "$${")
+		line = strings.ReplaceAll(line, "%{", "%%{")
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + delim + "\n")},
+		{Type: hclsyntax.TokenStringLit, Bytes: []byte(body.String())},
+		{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(delim)},
+	}
+}
+
+func containsLine(lines []string, s string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lexTokens converts the given source code into tokens that can be written
+// as part of a body, leaving it to formatting to decide on the spacing
+// between them.
+func lexTokens(src []byte, filename string, start hcl.Pos) (hclwrite.Tokens, error) {
+	syntaxTokens, diags := hclsyntax.LexConfig(src, filename, start)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	tokens := make(hclwrite.Tokens, 0, len(syntaxTokens))
+	for _, tok := range syntaxTokens {
+		if tok.Type == hclsyntax.TokenEOF {
+			continue
+		}
+		tokens = append(tokens, &hclwrite.Token{
+			Type:  tok.Type,
+			Bytes: tok.Bytes,
+		})
+	}
+	return tokens, nil
+}
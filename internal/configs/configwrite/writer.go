@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configwrite
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// File is a configuration file under construction.
+type File struct {
+	f *hclwrite.File
+}
+
+// NewFile returns a new, empty file.
+func NewFile() *File {
+	return &File{f: hclwrite.NewEmptyFile()}
+}
+
+// Body returns the top-level body of the file, to which blocks and comments
+// can be appended.
+func (f *File) Body() *Body {
+	return &Body{b: f.f.Body()}
+}
+
+// AppendBlock appends the given block to the top-level body of the file,
+// separated from any previous content by an empty line.
+func (f *File) AppendBlock(block *Block) {
+	body := f.f.Body()
+	if len(body.BuildTokens(nil)) != 0 {
+		body.AppendNewline()
+	}
+	body.AppendBlock(block.b)
+}
+
+// Bytes returns the canonically-formatted source code of the file.
+func (f *File) Bytes() []byte {
+	return hclwrite.Format(f.f.Bytes())
+}
+
+// WriteTo writes the canonically-formatted source code of the file to the
+// given writer.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(f.Bytes())
+	return int64(n), err
+}
+
+// Block is a configuration block under construction.
+type Block struct {
+	b *hclwrite.Block
+}
+
+// NewBlock returns a new, empty block of the given type with the given
+// labels.
+func NewBlock(typeName string, labels ...string) *Block {
+	return &Block{b: hclwrite.NewBlock(typeName, labels)}
+}
+
+// NewResourceBlock returns a new, empty resource, data or ephemeral block
+// for the given resource.
+func NewResourceBlock(addr addrs.Resource) *Block {
+	var typeName string
+	switch addr.Mode {
+	case addrs.ManagedResourceMode:
+		typeName = "resource"
+	case addrs.DataResourceMode:
+		typeName = "data"
+	case addrs.EphemeralResourceMode:
+		typeName = "ephemeral"
+	default:
+		// This should not happen, the above should be exhaustive.
+		panic(fmt.Sprintf("unsupported resource mode %s", addr.Mode))
+	}
+	return NewBlock(typeName, addr.Type, addr.Name)
+}
+
+// NewProviderBlock returns a new provider block for the given provider
+// configuration, which sets the alias argument if the configuration has one.
+func NewProviderBlock(addr addrs.LocalProviderConfig) *Block {
+	block := NewBlock("provider", addr.LocalName)
+	if addr.Alias != "" {
+		block.Body().SetAttributeValue("alias", cty.StringVal(addr.Alias))
+	}
+	return block
+}
+
+// NewModuleBlock returns a new module block with the given name, which sets
+// the source argument to the given source address.
+func NewModuleBlock(name string, source string) *Block {
+	block := NewBlock("module", name)
+	block.Body().SetAttributeValue("source", cty.StringVal(source))
+	return block
+}
+
+// Body returns the body of the block, to which arguments, nested blocks and
+// comments can be appended.
+func (b *Block) Body() *Body {
+	return &Body{b: b.b.Body()}
+}
+
+// Bytes returns the canonically-formatted source code of the block alone.
+func (b *Block) Bytes() []byte {
+	f := hclwrite.NewEmptyFile()
+	f.Body().AppendBlock(b.b)
+	return hclwrite.Format(f.Bytes())
+}
+
+// Body is the body of a file or block under construction.
+//
+// Arguments are written in the order they are first set; setting an argument
+// that is already present replaces its value in place.
+type Body struct {
+	b *hclwrite.Body
+}
+
+// SetAttributeValue sets the argument with the given name to a literal
+// representation of the given value, as returned by TokensForValue.
+func (b *Body) SetAttributeValue(name string, val cty.Value) {
+	b.b.SetAttributeRaw(name, TokensForValue(val))
+}
+
+// SetAttributeTraversal sets the argument with the given name to a reference
+// to the object at the given traversal, like var.name or aws_vpc.main.id.
+func (b *Body) SetAttributeTraversal(name string, traversal hcl.Traversal) {
+	b.b.SetAttributeTraversal(name, traversal)
+}
+
+// SetAttributeHeredoc sets the argument with the given name to the given
+// string, written as a heredoc template as returned by TokensForHeredoc.
+//
+// Template sequences in the string are escaped, so that the result evaluates
+// to exactly the given string.
+func (b *Body) SetAttributeHeredoc(name string, content string) {
+	b.b.SetAttributeRaw(name, TokensForHeredoc(content))
+}
+
+// SetAttributeExpr sets the argument with the given name to a copy of the
+// source code of the given expression, which must have been parsed from the
+// given source. This preserves the expression exactly as it was written,
+// including any references, function calls and comments within it.
+func (b *Body) SetAttributeExpr(name string, expr hcl.Expression, src []byte) error {
+	rng := expr.Range()
+	if rng.Start.Byte < 0 || rng.End.Byte > len(src) || rng.Start.Byte > rng.End.Byte {
+		return fmt.Errorf("the source range %s of the expression is not within the given source", rng)
+	}
+	tokens, err := lexTokens(src[rng.Start.Byte:rng.End.Byte], rng.Filename, rng.Start)
+	if err != nil {
+		return err
+	}
+	b.b.SetAttributeRaw(name, tokens)
+	return nil
+}
+
+// SetAttributeRaw sets the argument with the given name to the given
+// expression source code, which is written as-is apart from formatting.
+func (b *Body) SetAttributeRaw(name string, src string) error {
+	tokens, err := lexTokens([]byte(src), "", hcl.InitialPos)
+	if err != nil {
+		return err
+	}
+	b.b.SetAttributeRaw(name, tokens)
+	return nil
+}
+
+// AppendBlock appends the given nested block to the body.
+func (b *Body) AppendBlock(block *Block) {
+	b.b.AppendBlock(block.b)
+}
+
+// AppendComment appends a comment to the body. Each line of the given text
+// is written as a separate line comment.
+func (b *Body) AppendComment(text string) {
+	var tokens hclwrite.Tokens
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight("# "+line, " ")
+		tokens = append(tokens, &hclwrite.Token{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte(line + "\n"),
+		})
+	}
+	b.b.AppendUnstructuredTokens(tokens)
+}
+
+// AppendNewline appends an empty line to the body, to separate groups of
+// arguments or blocks.
+func (b *Body) AppendNewline() {
+	b.b.AppendNewline()
+}
+
+// AppendRaw appends the given source code, which may contain any number of
+// arguments, blocks and comments, to the body as-is apart from formatting.
+func (b *Body) AppendRaw(src string) error {
+	tokens, err := lexTokens([]byte(src), "", hcl.InitialPos)
+	if err != nil {
+		return err
+	}
+	b.b.AppendUnstructuredTokens(tokens)
+	return nil
+}
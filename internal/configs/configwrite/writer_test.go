@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configwrite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestFile(t *testing.T) {
+	src := []byte(`value = upper(var.name) # shout it
+count = length(local.items)
+`)
+	parsed, diags := hclsyntax.ParseConfig(src, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	attrs := parsed.Body.(*hclsyntax.Body).Attributes
+
+	f := NewFile()
+
+	provider := NewProviderBlock(addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"})
+	provider.Body().SetAttributeValue("region", cty.StringVal("us-west-2"))
+	f.AppendBlock(provider)
+
+	resource := NewResourceBlock(addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "aws_instance",
+		Name: "web",
+	})
+	body := resource.Body()
+	body.SetAttributeTraversal("provider", hcl.Traversal{
+		hcl.TraverseRoot{Name: "aws"},
+		hcl.TraverseAttr{Name: "west"},
+	})
+	body.SetAttributeValue("ami", cty.StringVal("ami-12345678"))
+	body.SetAttributeValue("policy", cty.StringVal(`{"Version": "2012-10-17"}`))
+	body.SetAttributeHeredoc("user_data", "#!/bin/sh\necho ${HOME}\n")
+	if err := body.SetAttributeExpr("name", attrs["value"].Expr, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := body.SetAttributeRaw("count", "var.enabled ? 1 : 0"); err != nil {
+		t.Fatal(err)
+	}
+	body.AppendNewline()
+	body.AppendComment("The root volume is\nencrypted by default.")
+	nested := NewBlock("root_block_device")
+	nested.Body().SetAttributeValue("volume_size", cty.NumberIntVal(20))
+	body.AppendBlock(nested)
+	f.AppendBlock(resource)
+
+	module := NewModuleBlock("network", "./network")
+	if err := module.Body().AppendRaw("cidr = \"10.0.0.0/16\"\n\n# Subnets\nsubnets = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.AppendBlock(module)
+
+	want := `provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
+
+resource "aws_instance" "web" {
+  provider = aws.west
+  ami      = "ami-12345678"
+  policy = jsonencode({
+    Version = "2012-10-17"
+  })
+  user_data = <<EOT
+#!/bin/sh
+echo $${HOME}
+EOT
+  name      = upper(var.name)
+  count     = var.enabled ? 1 : 0
+
+  # The root volume is
+  # encrypted by default.
+  root_block_device {
+    volume_size = 20
+  }
+}
+
+module "network" {
+  source = "./network"
+  cidr   = "10.0.0.0/16"
+
+  # Subnets
+  subnets = 3
+}
+`
+	if diff := cmp.Diff(want, string(f.Bytes())); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// The result must be valid configuration.
+	if _, diags := hclsyntax.ParseConfig(f.Bytes(), "generated.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+}
+
+func TestTokensForHeredoc(t *testing.T) {
+	tests := map[string]string{
+		"simple":          "hello\nworld\n",
+		"no final line":   "hello\nworld",
+		"templates":       "${var.name} %{ if true }yes%{ endif }\n",
+		"delimiter":       "EOT\n  EOT1\nEOT2\n",
+		"single line":     "hello",
+		"trailing spaces": "  indented\n    more\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			src := append([]byte("value = "), TokensForHeredoc(content).Bytes()...)
+			src = append(src, '\n')
+			file, diags := hclsyntax.ParseConfig(src, "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("invalid heredoc:\n%s\n%s", src, diags.Error())
+			}
+			attrs, _ := file.Body.JustAttributes()
+			got, diags := attrs["value"].Expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			want := content
+			if !strings.HasSuffix(want, "\n") {
+				want += "\n"
+			}
+			if got.AsString() != want {
+				t.Errorf("wrong value\ngot:  %q\nwant: %q", got.AsString(), want)
+			}
+		})
+	}
+}
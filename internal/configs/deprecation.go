@@ -0,0 +1,239 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// deprecationDiagExtra is the Extra value of the warnings that report the use
+// of a deprecated language feature, so that they can be found and upgraded to
+// errors in strict deprecation mode.
+type deprecationDiagExtra struct{}
+
+// UpgradeDeprecations returns a copy of the given diagnostics where each
+// warning about the use of a deprecated language feature is an error
+// instead, for strict deprecation mode. Other diagnostics are unchanged.
+func UpgradeDeprecations(diags hcl.Diagnostics) hcl.Diagnostics {
+	if len(diags) == 0 {
+		return diags
+	}
+	ret := make(hcl.Diagnostics, len(diags))
+	for i, diag := range diags {
+		if _, ok := diag.Extra.(deprecationDiagExtra); !ok || diag.Severity != hcl.DiagWarning {
+			ret[i] = diag
+			continue
+		}
+		upgraded := *diag
+		upgraded.Severity = hcl.DiagError
+		upgraded.Detail += "\n\nThis is an error because strict deprecation mode is enabled."
+		ret[i] = &upgraded
+	}
+	return ret
+}
+
+// IsDeprecationWarning returns true if the given diagnostic is a warning about
+// the use of a deprecated language feature.
+func IsDeprecationWarning(diag tfdiags.Diagnostic) bool {
+	_, ok := diag.ExtraInfo().(deprecationDiagExtra)
+	return ok && diag.Severity() == tfdiags.Warning
+}
+
+// checkModuleCallDeprecations returns warnings for each argument of the given
+// module call that sets a deprecated input variable of the child module, and
+// for each reference in the calling module to a deprecated output value of
+// the child module.
+//
+// The references are collected lazily by calling parentRefs, since most
+// module calls have nothing deprecated in them.
+func checkModuleCallDeprecations(call *ModuleCall, child *Module, parentRefs func() []hcl.Traversal) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	hasDeprecatedVariables := false
+	for _, v := range child.Variables {
+		if v.Deprecated != "" {
+			hasDeprecatedVariables = true
+			break
+		}
+	}
+	if hasDeprecatedVariables && call.Config != nil {
+		// The module call body only contains the input variables at this
+		// point, but it may still be invalid, in which case we'll report
+		// whatever we can and leave the errors to later validation.
+		attrs, _ := call.Config.JustAttributes()
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, ok := child.Variables[name]
+			if !ok || v.Deprecated == "" {
+				continue
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Deprecated variable",
+				Detail: fmt.Sprintf(
+					"The input variable %q of module %q is deprecated: %s\n\nThe variable is declared as deprecated at %s.",
+					name, call.Name, v.Deprecated, v.DeclRange,
+				),
+				Subject: attrs[name].Range.Ptr(),
+				Extra:   deprecationDiagExtra{},
+			})
+		}
+	}
+
+	hasDeprecatedOutputs := false
+	for _, o := range child.Outputs {
+		if o.Deprecated != "" {
+			hasDeprecatedOutputs = true
+			break
+		}
+	}
+	if !hasDeprecatedOutputs {
+		return diags
+	}
+	for _, traversal := range parentRefs() {
+		ref, refDiags := addrs.ParseRef(traversal)
+		if refDiags.HasErrors() {
+			continue
+		}
+		output, ok := ref.Subject.(addrs.ModuleCallInstanceOutput)
+		if !ok || output.Call.Call.Name != call.Name {
+			continue
+		}
+		o, ok := child.Outputs[output.Name]
+		if !ok || o.Deprecated == "" {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Deprecated output value",
+			Detail: fmt.Sprintf(
+				"The output value %q of module %q is deprecated: %s\n\nThe output value is declared as deprecated at %s.",
+				output.Name, call.Name, o.Deprecated, o.DeclRange,
+			),
+			Subject: ref.SourceRange.ToHCL().Ptr(),
+			Extra:   deprecationDiagExtra{},
+		})
+	}
+
+	return diags
+}
+
+// moduleTraversals returns all of the traversals in the expressions of the
+// given module that could refer to the outputs of its child modules, in the
+// order they appear in the source files.
+func moduleTraversals(m *Module) []hcl.Traversal {
+	var exprs []hcl.Expression
+	var bodies []hcl.Body
+
+	for _, l := range m.Locals {
+		exprs = append(exprs, l.Expr)
+	}
+	for _, o := range m.Outputs {
+		exprs = append(exprs, o.Expr)
+		exprs = append(exprs, checkRuleExprs(o.Preconditions)...)
+	}
+	for _, mc := range m.ModuleCalls {
+		bodies = append(bodies, mc.Config)
+		exprs = append(exprs, mc.Count, mc.ForEach)
+		exprs = append(exprs, checkRuleExprs(mc.Preconditions)...)
+		exprs = append(exprs, checkRuleExprs(mc.Postconditions)...)
+	}
+	for _, pc := range m.ProviderConfigs {
+		bodies = append(bodies, pc.Config)
+		exprs = append(exprs, pc.Count, pc.ForEach)
+	}
+	for _, rs := range []map[string]*Resource{m.ManagedResources, m.DataResources, m.EphemeralResources} {
+		for _, r := range rs {
+			bodies = append(bodies, r.Config)
+			exprs = append(exprs, r.Count, r.ForEach)
+			exprs = append(exprs, checkRuleExprs(r.Preconditions)...)
+			exprs = append(exprs, checkRuleExprs(r.Postconditions)...)
+		}
+	}
+	for _, c := range m.Checks {
+		exprs = append(exprs, checkRuleExprs(c.Asserts)...)
+	}
+	for _, i := range m.Import {
+		exprs = append(exprs, i.ID)
+	}
+
+	var ret []hcl.Traversal
+	for _, body := range bodies {
+		ret = append(ret, bodyTraversals(body)...)
+	}
+	for _, expr := range exprs {
+		if expr != nil {
+			ret = append(ret, expr.Variables()...)
+		}
+	}
+
+	// A native syntax body also contains the meta-arguments that we've
+	// added separately above, so we remove the duplicates.
+	seen := make(map[string]bool, len(ret))
+	unique := ret[:0]
+	for _, traversal := range ret {
+		key := traversal.SourceRange().String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, traversal)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		ri, rj := unique[i].SourceRange(), unique[j].SourceRange()
+		if ri.Filename != rj.Filename {
+			return ri.Filename < rj.Filename
+		}
+		return ri.Start.Byte < rj.Start.Byte
+	})
+	return unique
+}
+
+// bodyTraversals returns the traversals in all of the expressions of the
+// given body. Native syntax bodies are walked in full, including their nested
+// blocks, but for other bodies we can only find the traversals in their
+// top-level attributes without a schema.
+func bodyTraversals(body hcl.Body) []hcl.Traversal {
+	if body == nil {
+		return nil
+	}
+
+	var ret []hcl.Traversal
+	if synBody, ok := body.(*hclsyntax.Body); ok {
+		_ = hclsyntax.VisitAll(synBody, func(node hclsyntax.Node) hcl.Diagnostics {
+			if expr, ok := node.(*hclsyntax.ScopeTraversalExpr); ok {
+				ret = append(ret, expr.Traversal)
+			}
+			return nil
+		})
+		return ret
+	}
+
+	attrs, _ := body.JustAttributes()
+	for _, attr := range attrs {
+		ret = append(ret, attr.Expr.Variables()...)
+	}
+	return ret
+}
+
+func checkRuleExprs(rules []*CheckRule) []hcl.Expression {
+	exprs := make([]hcl.Expression, 0, len(rules)*2)
+	for _, rule := range rules {
+		exprs = append(exprs, rule.Condition, rule.ErrorMessage)
+	}
+	return exprs
+}
@@ -7,6 +7,7 @@ package configs
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -15,8 +16,16 @@ import (
 )
 
 type Import struct {
+	// ID is the import ID of the resource. It must evaluate either to a
+	// string or to a list of strings, which are joined using IDSeparator to
+	// build a composite ID.
 	ID hcl.Expression
 
+	// IDSeparator is the separator used to join the elements of an ID given
+	// as a list. It is empty unless the "id_separator" argument is set, in
+	// which case DefaultImportIDSeparator is used.
+	IDSeparator string
+
 	// To is the address HCL expression given in the `import` block configuration.
 	// It supports the following address formats:
 	// - aws_s3_bucket.my_bucket
@@ -27,6 +36,8 @@ type Import struct {
 	// - module.my_module[expression].aws_s3_bucket.my_buckets[expression]
 	// A dynamic instance key supports a dynamic expression like - a variable, a local, a condition (for example,
 	//  ternary), a resource block attribute, a data block attribute, etc.
+	// If the import block has a for_each argument and the address given in the configuration has no resource
+	// instance key, then the key is each.key, and so To is an index expression with each.key as the key.
 	To hcl.Expression
 	// StaticTo is the corresponding resource and module that the address is referring to. When decoding, as long
 	// as the `to` field is in the accepted format, we could determine the actual modules and resource that the
@@ -67,6 +78,23 @@ func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
 		imp.ID = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["id_separator"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &imp.IDSeparator)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() && imp.IDSeparator == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import id_separator argument",
+				Detail:   "The separator for the elements of a composite import ID must not be empty.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["for_each"]; exists {
+		imp.ForEach = attr.Expr
+	}
+
 	if attr, exists := content.Attributes["to"]; exists {
 		toExpr := attr.Expr
 		// Since we are manually parsing the 'to' argument, we need to specially
@@ -85,6 +113,10 @@ func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
 			toExpr = convertedExpr
 		}
 
+		if imp.ForEach != nil {
+			toExpr = importToExprWithEachKey(toExpr)
+		}
+
 		imp.To = toExpr
 		staticAddress, addressDiags := staticImportAddress(toExpr)
 		diags = append(diags, addressDiags.ToHCL()...)
@@ -114,11 +146,67 @@ func decodeImportBlock(block *hcl.Block) (*Import, hcl.Diagnostics) {
 		diags = append(diags, providerDiags...)
 	}
 
-	if attr, exists := content.Attributes["for_each"]; exists {
-		imp.ForEach = attr.Expr
+	return imp, diags
+}
+
+// DefaultImportIDSeparator is the separator used to join the elements of an
+// import ID given as a list, unless the import block sets id_separator.
+const DefaultImportIDSeparator = "/"
+
+// IDSeparatorOrDefault returns the separator to use to join the elements of
+// an import ID given as a list.
+func (i *Import) IDSeparatorOrDefault() string {
+	if i.IDSeparator == "" {
+		return DefaultImportIDSeparator
 	}
+	return i.IDSeparator
+}
 
-	return imp, diags
+// importToExprWithEachKey returns the given "to" expression of an import block
+// with for_each, with each.key added as the resource instance key if the
+// expression has no instance key of its own. This allows importing into a
+// resource with for_each using the same map for both, like:
+//
+//	import {
+//	  for_each = var.buckets
+//	  to       = aws_s3_bucket.this
+//	  id       = each.value.name
+//	}
+//
+// Expressions that aren't resource addresses are returned unchanged, so that
+// they are reported as invalid in the usual way.
+func importToExprWithEachKey(expr hcl.Expression) hcl.Expression {
+	var collection hclsyntax.Expression
+	var traversal hcl.Traversal
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		collection, traversal = e, e.Traversal
+	case *hclsyntax.RelativeTraversalExpr:
+		collection, traversal = e, e.Traversal
+	default:
+		return expr
+	}
+	if len(traversal) == 0 {
+		return expr
+	}
+	if _, ok := traversal[len(traversal)-1].(hcl.TraverseIndex); ok {
+		return expr
+	}
+
+	rng := expr.Range()
+	return &hclsyntax.IndexExpr{
+		Collection: collection,
+		Key: &hclsyntax.ScopeTraversalExpr{
+			Traversal: hcl.Traversal{
+				hcl.TraverseRoot{Name: "each", SrcRange: rng},
+				hcl.TraverseAttr{Name: "key", SrcRange: rng},
+			},
+			SrcRange: rng,
+		},
+		SrcRange:     rng,
+		OpenRange:    rng,
+		BracketRange: rng,
+	}
 }
 
 var importBlockSchema = &hcl.BodySchema{
@@ -134,6 +222,9 @@ var importBlockSchema = &hcl.BodySchema{
 			Name:     "to",
 			Required: true,
 		},
+		{
+			Name: "id_separator",
+		},
 		{
 			Name: "for_each",
 		},
@@ -290,6 +290,85 @@ func TestImportBlock_decode(t *testing.T) {
 	}
 }
 
+func TestImportBlock_decodeForEach(t *testing.T) {
+	parse := func(src string) hcl.Expression {
+		t.Helper()
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		return expr
+	}
+	block := func(to hcl.Expression, extra hcl.Attributes) *hcl.Block {
+		attrs := hcl.Attributes{
+			"id":       {Name: "id", Expr: parse(`each.value`)},
+			"to":       {Name: "to", Expr: to},
+			"for_each": {Name: "for_each", Expr: parse(`var.ids`)},
+		}
+		for name, attr := range extra {
+			attrs[name] = attr
+		}
+		return &hcl.Block{
+			Type: "import",
+			Body: hcltest.MockBody(&hcl.BodyContent{Attributes: attrs}),
+		}
+	}
+	eachKey := hcl.Traversal{hcl.TraverseRoot{Name: "each"}, hcl.TraverseAttr{Name: "key"}}
+
+	t.Run("implied instance key", func(t *testing.T) {
+		got, diags := decodeImportBlock(block(parse(`module.foo.test_instance.bar`), nil))
+		if diags.HasErrors() {
+			t.Fatal(diags.Errs())
+		}
+		to, ok := got.To.(*hclsyntax.IndexExpr)
+		if !ok {
+			t.Fatalf("wrong to expression type %T; want *hclsyntax.IndexExpr", got.To)
+		}
+		key, ok := to.Key.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || !traversalsAreEquivalent(key.Traversal, eachKey) {
+			t.Errorf("wrong instance key expression %#v; want each.key", to.Key)
+		}
+		if got.ResolvedTo != nil {
+			t.Errorf("unexpected resolved address %s", got.ResolvedTo)
+		}
+		if got, want := got.StaticTo.String(), "module.foo.test_instance.bar"; got != want {
+			t.Errorf("wrong static address\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("explicit instance key", func(t *testing.T) {
+		to := parse(`test_instance.bar[each.value]`)
+		got, diags := decodeImportBlock(block(to, nil))
+		if diags.HasErrors() {
+			t.Fatal(diags.Errs())
+		}
+		if got.To != to {
+			t.Errorf("to expression was modified")
+		}
+	})
+
+	t.Run("id separator", func(t *testing.T) {
+		got, diags := decodeImportBlock(block(parse(`test_instance.bar`), hcl.Attributes{
+			"id_separator": {Name: "id_separator", Expr: parse(`":"`)},
+		}))
+		if diags.HasErrors() {
+			t.Fatal(diags.Errs())
+		}
+		if got, want := got.IDSeparatorOrDefault(), ":"; got != want {
+			t.Errorf("wrong separator %q; want %q", got, want)
+		}
+	})
+
+	t.Run("empty id separator", func(t *testing.T) {
+		_, diags := decodeImportBlock(block(parse(`test_instance.bar`), hcl.Attributes{
+			"id_separator": {Name: "id_separator", Expr: parse(`""`)},
+		}))
+		if !diags.HasErrors() || diags[0].Summary != "Invalid import id_separator argument" {
+			t.Fatalf("wrong diagnostics: %s", diags)
+		}
+	})
+}
+
 // Taken from traversalsAreEquivalent of hcl/v2
 func traversalsAreEquivalent(a, b hcl.Traversal) bool {
 	if len(a) != len(b) {
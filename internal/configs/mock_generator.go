@@ -0,0 +1,255 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+)
+
+const blockNameMockGenerator = "generate"
+
+// The types of generators that a generate block can use.
+const (
+	MockGeneratorUUID     = "uuid"
+	MockGeneratorSequence = "sequence"
+	MockGeneratorCIDR     = "cidr"
+	MockGeneratorRegex    = "regex"
+)
+
+// MockGenerator represents a generate block in a mock_resource or mock_data
+// block, which generates a different value of a computed attribute for each
+// object that the mock provider creates or reads, instead of using the same
+// value for all of them.
+//
+// Which of the fields are set depends on Type.
+type MockGenerator struct {
+	// Attribute is the name of the top-level attribute to generate.
+	Attribute string
+	Type      string
+
+	// Start and Step are the first value and the increment of a sequence.
+	Start int64
+	Step  int64
+
+	// Pool and NewBits describe the CIDR blocks of a cidr generator, which
+	// are the consecutive subnets of Pool with NewBits more bits in their
+	// prefixes, as for the cidrsubnet function.
+	Pool    *net.IPNet
+	NewBits int
+
+	// Pattern is the regular expression that the strings of a regex
+	// generator match.
+	Pattern *regexp.Regexp
+
+	DeclRange hcl.Range
+
+	// next is the number of values generated so far. It belongs to the
+	// configuration rather than to a mock provider so that, like real
+	// objects, the generated values stay unique across the run blocks of a
+	// test file, each of which uses new provider instances.
+	mu   sync.Mutex
+	next int64
+}
+
+// Next returns the index of the next value to generate, starting from zero,
+// and advances the generator.
+func (g *MockGenerator) Next() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := g.next
+	g.next++
+	return n
+}
+
+func decodeMockGeneratorBlock(block *hcl.Block) (*MockGenerator, hcl.Diagnostics) {
+	gen := &MockGenerator{
+		Attribute: block.Labels[0],
+		Start:     1,
+		Step:      1,
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(mockGeneratorBlockSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	typeAttr := content.Attributes["type"]
+	diags = append(diags, gohcl.DecodeExpression(typeAttr.Expr, nil, &gen.Type)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Each type of generator has its own arguments.
+	var required, optional []string
+	switch gen.Type {
+	case MockGeneratorUUID:
+	case MockGeneratorSequence:
+		optional = []string{"start", "step"}
+	case MockGeneratorCIDR:
+		required = []string{"pool", "newbits"}
+	case MockGeneratorRegex:
+		required = []string{"pattern"}
+	default:
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid generator type",
+			Detail:   fmt.Sprintf("The generator type must be %q, %q, %q, or %q.", MockGeneratorUUID, MockGeneratorSequence, MockGeneratorCIDR, MockGeneratorRegex),
+			Subject:  typeAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	allowed := make(map[string]bool)
+	for _, name := range append(required, optional...) {
+		allowed[name] = true
+	}
+	for name, attr := range content.Attributes {
+		if name != "type" && !allowed[name] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported generator argument",
+				Detail:   fmt.Sprintf("The argument %q is not supported by %q generators.", name, gen.Type),
+				Subject:  attr.NameRange.Ptr(),
+			})
+		}
+	}
+	for _, name := range required {
+		if _, ok := content.Attributes[name]; !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing generator argument",
+				Detail:   fmt.Sprintf("The argument %q is required by %q generators.", name, gen.Type),
+				Subject:  block.Body.MissingItemRange().Ptr(),
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if attr, ok := content.Attributes["start"]; ok {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &gen.Start)...)
+	}
+	if attr, ok := content.Attributes["step"]; ok {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &gen.Step)...)
+	}
+	if attr, ok := content.Attributes["pool"]; ok {
+		var pool string
+		moreDiags := gohcl.DecodeExpression(attr.Expr, nil, &pool)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			_, ipNet, err := net.ParseCIDR(pool)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid CIDR pool",
+					Detail:   fmt.Sprintf("The pool must be a CIDR block: %s.", err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+			gen.Pool = ipNet
+		}
+	}
+	if attr, ok := content.Attributes["newbits"]; ok {
+		moreDiags := gohcl.DecodeExpression(attr.Expr, nil, &gen.NewBits)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() && gen.Pool != nil {
+			prefixLen, bits := gen.Pool.Mask.Size()
+			if gen.NewBits < 1 || prefixLen+gen.NewBits > bits {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid CIDR newbits",
+					Detail:   fmt.Sprintf("The newbits must be between 1 and %d for the pool %s.", bits-prefixLen, gen.Pool),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+	if attr, ok := content.Attributes["pattern"]; ok {
+		var pattern string
+		moreDiags := gohcl.DecodeExpression(attr.Expr, nil, &pattern)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid generator pattern",
+					Detail:   fmt.Sprintf("The pattern must be a valid regular expression: %s.", err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+			gen.Pattern = re
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return gen, diags
+}
+
+// checkForDuplicatedMockGenerators returns an error for each generator of
+// the given mock resource that generates the same attribute as an earlier
+// generator, or an attribute that also has a default value.
+func checkForDuplicatedMockGenerators(res *MockResource) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	seen := make(map[string]bool, len(res.Generators))
+	for _, gen := range res.Generators {
+		_, hasDefault := res.Defaults[gen.Attribute]
+		_, hasDefaultExpr := res.DefaultExprs[gen.Attribute]
+		switch {
+		case seen[gen.Attribute]:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Duplicated `%v` block", blockNameMockGenerator),
+				Detail:   fmt.Sprintf("It is not allowed to have multiple `%v` blocks for the attribute `%v`.", blockNameMockGenerator, gen.Attribute),
+				Subject:  gen.DeclRange.Ptr(),
+			})
+		case hasDefault || hasDefaultExpr:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting mock resource default",
+				Detail:   fmt.Sprintf("The attribute `%v` can't both have a default value and be generated.", gen.Attribute),
+				Subject:  gen.DeclRange.Ptr(),
+			})
+		}
+		seen[gen.Attribute] = true
+	}
+
+	return diags
+}
+
+var mockGeneratorBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "type",
+			Required: true,
+		},
+		{
+			Name: "start",
+		},
+		{
+			Name: "step",
+		},
+		{
+			Name: "pool",
+		},
+		{
+			Name: "newbits",
+		},
+		{
+			Name: "pattern",
+		},
+	},
+}
@@ -31,6 +31,15 @@ type Module struct {
 	// values.
 	SourceDir string
 
+	// ContentHash is a hash of the names and contents of the files in the
+	// module directory, excluding nested modules, in the same "h1:" format
+	// used for provider package hashes. It is used to detect changes to the
+	// source of a module between runs.
+	//
+	// Like SourceDir, this is populated automatically only for child modules
+	// loaded with LoadConfigDir, and is empty otherwise.
+	ContentHash string
+
 	CoreVersionConstraints []VersionConstraint
 
 	ActiveExperiments experiments.Set
@@ -42,6 +51,7 @@ type Module struct {
 	ProviderLocalNames   map[addrs.Provider]string
 	ProviderMetas        map[addrs.Provider]*ProviderMeta
 	Encryption           *config.EncryptionConfig
+	Workspaces           *Workspaces
 
 	Variables map[string]*Variable
 	Locals    map[string]*Local
@@ -49,8 +59,9 @@ type Module struct {
 
 	ModuleCalls map[string]*ModuleCall
 
-	ManagedResources map[string]*Resource
-	DataResources    map[string]*Resource
+	ManagedResources   map[string]*Resource
+	DataResources      map[string]*Resource
+	EphemeralResources map[string]*Resource
 
 	Moved   []*Moved
 	Import  []*Import
@@ -97,6 +108,7 @@ type File struct {
 	ProviderMetas     []*ProviderMeta
 	RequiredProviders []*RequiredProviders
 	Encryptions       []*config.EncryptionConfig
+	Workspaces        []*Workspaces
 
 	Variables []*Variable
 	Locals    []*Local
@@ -104,8 +116,9 @@ type File struct {
 
 	ModuleCalls []*ModuleCall
 
-	ManagedResources []*Resource
-	DataResources    []*Resource
+	ManagedResources   []*Resource
+	DataResources      []*Resource
+	EphemeralResources []*Resource
 
 	Moved   []*Moved
 	Import  []*Import
@@ -134,6 +147,8 @@ func (s SelectiveLoader) filter(input []*File) []*File {
 		outFile := &File{
 			Variables: inFile.Variables,
 			Locals:    inFile.Locals,
+			// The selected workspace may change the variable defaults.
+			Workspaces: inFile.Workspaces,
 		}
 
 		switch s {
@@ -177,6 +192,7 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		ModuleCalls:        map[string]*ModuleCall{},
 		ManagedResources:   map[string]*Resource{},
 		DataResources:      map[string]*Resource{},
+		EphemeralResources: map[string]*Resource{},
 		Checks:             map[string]*Check{},
 		ProviderMetas:      map[addrs.Provider]*ProviderMeta{},
 		Tests:              map[string]*TestFile{},
@@ -232,6 +248,12 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		diags = append(diags, fileDiags...)
 	}
 
+	diags = append(diags, mod.checkRemovedProviders()...)
+
+	// The workspace-specific variable defaults must be in place before we
+	// statically evaluate anything that might refer to the variables.
+	diags = append(diags, mod.decodeWorkspaceVariables(call)...)
+
 	// Static evaluation to build a StaticContext now that module has all relevant Locals / Variables
 	mod.StaticEvaluator = NewStaticEvaluator(mod, call)
 
@@ -272,6 +294,8 @@ func (m *Module) ResourceByAddr(addr addrs.Resource) *Resource {
 		return m.ManagedResources[key]
 	case addrs.DataResourceMode:
 		return m.DataResources[key]
+	case addrs.EphemeralResourceMode:
+		return m.EphemeralResources[key]
 	default:
 		return nil
 	}
@@ -313,6 +337,19 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		m.CloudConfig = c
 	}
 
+	for _, w := range file.Workspaces {
+		if m.Workspaces != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspaces configuration",
+				Detail:   fmt.Sprintf("A module may have only one workspaces block. The workspaces were previously declared at %s.", m.Workspaces.DeclRange),
+				Subject:  &w.DeclRange,
+			})
+			continue
+		}
+		m.Workspaces = w
+	}
+
 	if m.Backend != nil && m.CloudConfig != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -448,6 +485,30 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		}
 	}
 
+	for _, r := range file.EphemeralResources {
+		key := r.moduleUniqueKey()
+		if existing, exists := m.EphemeralResources[key]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Duplicate ephemeral %q configuration", existing.Type),
+				Detail:   fmt.Sprintf("A %s ephemeral resource named %q was already declared at %s. Resource names must be unique per type in each module.", existing.Type, existing.Name, existing.DeclRange),
+				Subject:  &r.DeclRange,
+			})
+			continue
+		}
+		m.EphemeralResources[key] = r
+
+		// set the provider FQN for the resource
+		if r.ProviderConfigRef != nil {
+			r.Provider = m.ProviderForLocalConfig(r.ProviderConfigAddr())
+		} else {
+			implied, err := addrs.ParseProviderPart(r.Addr().ImpliedProvider())
+			if err == nil {
+				r.Provider = m.ImpliedProviderForUnqualifiedType(implied)
+			}
+		}
+	}
+
 	// Data sources can either be defined at the module root level, or within a
 	// single check block. We'll merge the data sources from both into the
 	// single module level DataResources map.
@@ -595,6 +656,20 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		}
 	}
 
+	if len(file.Workspaces) != 0 {
+		switch len(file.Workspaces) {
+		case 1:
+			m.Workspaces = file.Workspaces[0]
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspaces configuration",
+				Detail:   fmt.Sprintf("Each override file may have only one workspaces block. The workspaces were previously declared at %s.", file.Workspaces[0].DeclRange),
+				Subject:  &file.Workspaces[1].DeclRange,
+			})
+		}
+	}
+
 	for _, pc := range file.ProviderConfigs {
 		key := pc.moduleUniqueKey()
 		existing, exists := m.ProviderConfigs[key]
@@ -735,6 +810,22 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		diags = append(diags, mergeDiags...)
 	}
 
+	for _, r := range file.EphemeralResources {
+		key := r.moduleUniqueKey()
+		existing, exists := m.EphemeralResources[key]
+		if !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing ephemeral resource to override",
+				Detail:   fmt.Sprintf("There is no %s ephemeral resource named %q. An override file can only override an ephemeral block defined in a primary configuration file.", r.Type, r.Name),
+				Subject:  &r.DeclRange,
+			})
+			continue
+		}
+		mergeDiags := existing.merge(r, m.ProviderRequirements.RequiredProviders)
+		diags = append(diags, mergeDiags...)
+	}
+
 	for _, m := range file.Moved {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -765,6 +856,30 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 	return diags
 }
 
+// checkRemovedProviders returns an error for each removed block that
+// declares the removal of a provider configuration which is still declared in
+// the module.
+func (m *Module) checkRemovedProviders() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, r := range m.Removed {
+		if r.FromProvider == nil {
+			continue
+		}
+		if _, exists := m.ProviderConfigs[r.FromProvider.Addr.StringCompact()]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Removed provider configuration still exists",
+				Detail: fmt.Sprintf(
+					"This statement declares a removal of the provider configuration %s, but this provider block still exists in the configuration. Please remove the provider block.",
+					r.FromProvider.Addr,
+				),
+				Subject: r.FromProvider.DeclRange.Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
 // gatherProviderLocalNames is a helper function that populatesA a map of
 // provider FQNs -> provider local names. This information is useful for
 // user-facing output, which should include both the FQN and LocalName. It must
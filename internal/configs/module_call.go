@@ -35,13 +35,31 @@ type ModuleCall struct {
 	VersionAttr *hcl.Attribute
 	Version     VersionConstraint
 
+	// IntegrityAttr is the "integrity" argument, if set, and Integrity is
+	// the module package hash it evaluates to, which the installed package
+	// must match.
+	IntegrityAttr *hcl.Attribute
+	Integrity     string
+
 	Count   hcl.Expression
 	ForEach hcl.Expression
 
+	// Enabled is the "enabled" lifecycle argument, which declares either a
+	// single instance of the module with no key or no instances at all. It's
+	// mutually exclusive with Count and ForEach.
+	Enabled hcl.Expression
+
 	Providers []PassedProviderConfig
 
 	DependsOn []hcl.Traversal
 
+	// Preconditions are checked for each instance of the module call before
+	// any of the objects in the module are evaluated, and Postconditions are
+	// checked after all of its output values are evaluated. Postconditions
+	// can refer to the output values of the instance using "self".
+	Preconditions  []*CheckRule
+	Postconditions []*CheckRule
+
 	DeclRange hcl.Range
 }
 
@@ -75,6 +93,10 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 		mc.VersionAttr = attr
 	}
 
+	if attr, exists := content.Attributes["integrity"]; exists {
+		mc.IntegrityAttr = attr
+	}
+
 	if attr, exists := content.Attributes["source"]; exists {
 		mc.SourceSet = true
 		mc.Source = attr.Expr
@@ -110,8 +132,27 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 	}
 
 	var seenEscapeBlock *hcl.Block
+	var seenLifecycle *hcl.Block
 	for _, block := range content.Blocks {
 		switch block.Type {
+		case "lifecycle":
+			if seenLifecycle != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate lifecycle block",
+					Detail:   fmt.Sprintf("This module call already has a lifecycle block at %s.", seenLifecycle.DefRange),
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+			seenLifecycle = block
+
+			lcContent, lcDiags := block.Body.Content(moduleLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				mc.Enabled = attr.Expr
+			}
+
 		case "_":
 			if seenEscapeBlock != nil {
 				diags = append(diags, &hcl.Diagnostic{
@@ -132,6 +173,16 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 			// will see a blend of both.
 			mc.Config = hcl.MergeBodies([]hcl.Body{mc.Config, block.Body})
 
+		case "precondition":
+			cr, moreDiags := decodeCheckRuleBlock(block, override)
+			diags = append(diags, moreDiags...)
+			mc.Preconditions = append(mc.Preconditions, cr)
+
+		case "postcondition":
+			cr, moreDiags := decodeCheckRuleBlock(block, override)
+			diags = append(diags, moreDiags...)
+			mc.Postconditions = append(mc.Postconditions, cr)
+
 		default:
 			// All of the other block types in our schema are reserved.
 			diags = append(diags, &hcl.Diagnostic{
@@ -143,6 +194,8 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 		}
 	}
 
+	diags = append(diags, checkEnabledRepetition(mc.Enabled, mc.Count, mc.ForEach)...)
+
 	return mc, diags
 }
 
@@ -153,6 +206,7 @@ func (mc *ModuleCall) decodeStaticFields(eval *StaticEvaluator) hcl.Diagnostics
 	var diags hcl.Diagnostics
 	diags = diags.Extend(mc.decodeStaticSource(eval))
 	diags = diags.Extend(mc.decodeStaticVersion(eval))
+	diags = diags.Extend(mc.decodeStaticIntegrity(eval))
 	return diags
 }
 
@@ -246,6 +300,48 @@ func (mc *ModuleCall) decodeStaticVersion(eval *StaticEvaluator) hcl.Diagnostics
 	return diags.Extend(verDiags)
 }
 
+func (mc *ModuleCall) decodeStaticIntegrity(eval *StaticEvaluator) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if mc.IntegrityAttr == nil {
+		return diags
+	}
+
+	diags = eval.DecodeExpression(mc.IntegrityAttr.Expr, StaticIdentifier{
+		Module:    eval.call.addr,
+		Subject:   fmt.Sprintf("module.%s.integrity", mc.Name),
+		DeclRange: mc.IntegrityAttr.Range,
+	}, &mc.Integrity)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if err := getmodules.ValidatePackageHash(mc.Integrity); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module integrity hash",
+			Detail:   fmt.Sprintf("The integrity hash for a module package %s, like the hashes recorded for modules in the dependency lock file.", err),
+			Subject:  mc.IntegrityAttr.Expr.Range().Ptr(),
+		})
+		return diags
+	}
+
+	switch mc.SourceAddr.(type) {
+	case addrs.ModuleSourceRemote, nil:
+		// Only remote module packages can be verified, and we'll report
+		// an invalid source address separately.
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module integrity hash",
+			Detail:   "The integrity of a module can be verified only for a remote module package, such as one from a git repository or an HTTP URL. Local modules are part of the calling module's package, and registry modules are selected with the \"version\" argument instead.",
+			Subject:  mc.IntegrityAttr.NameRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
 func (mc *ModuleCall) decodeStaticVariables(eval *StaticEvaluator) {
 	attr, _ := mc.Config.JustAttributes()
 
@@ -289,6 +385,12 @@ func (mc *ModuleCall) EntersNewPackage() bool {
 type PassedProviderConfig struct {
 	InChild  *ProviderConfigRef
 	InParent *ProviderConfigRef
+
+	// AllInstances is set when the parent passes all of the instances of a
+	// provider configuration that uses count or for_each, written as
+	// aws.by_region[*]. The child module then selects an instance for each
+	// of its resources with an instance key of its own.
+	AllInstances bool
 }
 
 func decodePassedProviderConfigs(attr *hcl.Attribute) ([]PassedProviderConfig, hcl.Diagnostics) {
@@ -301,11 +403,29 @@ func decodePassedProviderConfigs(attr *hcl.Attribute) ([]PassedProviderConfig, h
 	for _, pair := range pairs {
 		key, keyDiags := decodeProviderConfigRef(pair.Key, "providers")
 		diags = append(diags, keyDiags...)
-		value, valueDiags := decodeProviderConfigRef(pair.Value, "providers")
+
+		valueExpr := pair.Value
+		allInstances := false
+		if splat, ok := valueExpr.(*hclsyntax.SplatExpr); ok {
+			if _, ok := splat.Each.(*hclsyntax.AnonSymbolExpr); ok {
+				valueExpr = splat.Source
+				allInstances = true
+			}
+		}
+		value, valueDiags := decodeProviderConfigRef(valueExpr, "providers")
 		diags = append(diags, valueDiags...)
 		if keyDiags.HasErrors() || valueDiags.HasErrors() {
 			continue
 		}
+		if allInstances && value.KeyExpression != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration reference",
+				Detail:   "A reference to all instances of a provider configuration, using [*], cannot also include an instance key.",
+				Subject:  pair.Value.Range().Ptr(),
+			})
+			continue
+		}
 
 		matchKey := key.String()
 		if prev, exists := seen[matchKey]; exists {
@@ -321,8 +441,9 @@ func decodePassedProviderConfigs(attr *hcl.Attribute) ([]PassedProviderConfig, h
 		rng := hcl.RangeBetween(pair.Key.Range(), pair.Value.Range())
 		seen[matchKey] = rng
 		providers = append(providers, PassedProviderConfig{
-			InChild:  key,
-			InParent: value,
+			InChild:      key,
+			InParent:     value,
+			AllInstances: allInstances,
 		})
 	}
 	return providers, diags
@@ -337,6 +458,9 @@ var moduleBlockSchema = &hcl.BodySchema{
 		{
 			Name: "version",
 		},
+		{
+			Name: "integrity",
+		},
 		{
 			Name: "count",
 		},
@@ -352,14 +476,32 @@ var moduleBlockSchema = &hcl.BodySchema{
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "_"}, // meta-argument escaping block
+		{Type: "precondition"},
+		{Type: "postcondition"},
+		{Type: "lifecycle"},
 
 		// These are all reserved for future use.
-		{Type: "lifecycle"},
 		{Type: "locals"},
 		{Type: "provider", LabelNames: []string{"type"}},
 	},
 }
 
+var moduleLifecycleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "enabled",
+		},
+	},
+}
+
+// moduleBlockUnreservedArguments are the meta-arguments in moduleBlockSchema
+// that were added after modules could already declare input variables of the
+// same name, and so remain valid variable names. The caller of such a module
+// must set the variable inside the "_" escaping block.
+var moduleBlockUnreservedArguments = map[string]bool{
+	"integrity": true,
+}
+
 func moduleSourceAddrEntersNewPackage(addr addrs.ModuleSource) bool {
 	switch addr.(type) {
 	case nil:
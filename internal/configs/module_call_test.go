@@ -145,6 +145,119 @@ func TestLoadModuleCall(t *testing.T) {
 	}
 }
 
+func TestModuleCallIntegrity(t *testing.T) {
+	const hash = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+	t.Run("remote package", func(t *testing.T) {
+		parser := testParser(map[string]string{
+			"main.tf": `
+module "network" {
+  source    = "git::https://example.com/network.git?ref=v1.0.0"
+  integrity = "` + hash + `"
+}
+`,
+		})
+		mod, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+		assertNoDiagnostics(t, diags)
+		if got := mod.ModuleCalls["network"].Integrity; got != hash {
+			t.Errorf("wrong integrity %q; want %q", got, hash)
+		}
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		parser := testParser(map[string]string{
+			"main.tf": `
+module "network" {
+  source    = "git::https://example.com/network.git?ref=v1.0.0"
+  integrity = "sha256:abc"
+}
+`,
+		})
+		_, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+		assertExactDiagnostics(t, diags, []string{
+			`main.tf:4,15-27: Invalid module integrity hash; The integrity hash for a module package must start with "h1:", like the hashes recorded for modules in the dependency lock file.`,
+		})
+	})
+
+	t.Run("local module", func(t *testing.T) {
+		parser := testParser(map[string]string{
+			"main.tf": `
+module "network" {
+  source    = "./network"
+  integrity = "` + hash + `"
+}
+`,
+		})
+		_, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+		assertDiagnosticSummary(t, diags, "Invalid module integrity hash")
+	})
+
+	t.Run("variable name", func(t *testing.T) {
+		// Modules that declared a variable named "integrity" before it was
+		// a meta-argument must remain valid.
+		parser := testParser(map[string]string{
+			"main.tf": `
+variable "integrity" {
+  type = string
+}
+`,
+		})
+		_, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+		assertNoDiagnostics(t, diags)
+	})
+}
+
+func TestModuleCallConditions(t *testing.T) {
+	parser := testParser(map[string]string{
+		"config/main.tf": `
+variable "subnets" {
+  type = list(string)
+}
+
+module "network" {
+  source  = "./network"
+  subnets = var.subnets
+
+  precondition {
+    condition     = length(var.subnets) > 0
+    error_message = "At least one subnet is required."
+  }
+
+  postcondition {
+    condition     = self.vpc_id != ""
+    error_message = "The network module didn't create a VPC."
+  }
+}
+`,
+		"config/main_override.tf": `
+module "network" {
+  postcondition {
+    condition     = self.vpc_id == "vpc-1"
+    error_message = "Overridden."
+  }
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("config", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`config/main_override.tf:3,3-16: Can't override postcondition blocks; Override files cannot override "postcondition" blocks.`,
+	})
+
+	mc := mod.ModuleCalls["network"]
+	if mc == nil {
+		t.Fatal("module call not found")
+	}
+	if got, want := len(mc.Preconditions), 1; got != want {
+		t.Fatalf("wrong number of preconditions: got %d, want %d", got, want)
+	}
+	if got, want := len(mc.Postconditions), 1; got != want {
+		t.Fatalf("wrong number of postconditions: got %d, want %d", got, want)
+	}
+	if got, want := mc.Postconditions[0].DeclRange.Start.Line, 15; got != want {
+		t.Errorf("wrong postcondition line: got %d, want %d", got, want)
+	}
+}
+
 func TestModuleSourceAddrEntersNewPackage(t *testing.T) {
 	absolutePath := "/absolute/path"
 	if runtime.GOOS == "windows" {
@@ -35,8 +35,24 @@ func (p *Provider) merge(op *Provider) hcl.Diagnostics {
 		p.Version = op.Version
 	}
 
+	if op.denyDestroyExpr != nil {
+		p.denyDestroyExpr = op.denyDestroyExpr
+		p.DenyDestroyRange = op.DenyDestroyRange
+	}
+
 	p.Config = MergeBodies(p.Config, op.Config)
 
+	// We don't allow depends_on to be overridden because that is likely to
+	// cause confusing misbehavior.
+	if len(op.DependsOn) != 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported override",
+			Detail:   "The depends_on argument may not be overridden.",
+			Subject:  op.DependsOn[0].SourceRange().Ptr(), // the first item is the closest range we have
+		})
+	}
+
 	return diags
 }
 
@@ -51,12 +67,21 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Sensitive = ov.Sensitive
 		v.SensitiveSet = ov.SensitiveSet
 	}
+	if ov.EphemeralSet {
+		v.Ephemeral = ov.Ephemeral
+		v.EphemeralSet = ov.EphemeralSet
+	}
+	if ov.DeprecatedSet {
+		v.Deprecated = ov.Deprecated
+		v.DeprecatedSet = ov.DeprecatedSet
+	}
 	if ov.Default != cty.NilVal {
 		v.Default = ov.Default
 	}
 	if ov.Type != cty.NilType {
 		v.Type = ov.Type
 		v.ConstraintType = ov.ConstraintType
+		v.TypeRefinements = ov.TypeRefinements
 	}
 	if ov.ParsingMode != 0 {
 		v.ParsingMode = ov.ParsingMode
@@ -65,6 +90,10 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Nullable = ov.Nullable
 		v.NullableSet = ov.NullableSet
 	}
+	if ov.DeepMergeDefaultsSet {
+		v.DeepMergeDefaults = ov.DeepMergeDefaults
+		v.DeepMergeDefaultsSet = ov.DeepMergeDefaultsSet
+	}
 
 	// If the override file overrode type without default or vice-versa then
 	// it may have created an invalid situation, which we'll catch now by
@@ -78,6 +107,9 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 	// should be rare since most of our conversions are interchangeable.
 	if v.Default != cty.NilVal {
 		val, err := convert.Convert(v.Default, v.ConstraintType)
+		if err == nil {
+			val, err = v.TypeRefinements.Apply(val)
+		}
 		if err != nil {
 			// What exactly we'll say in the error message here depends on whether
 			// it was Default or Type that was overridden here.
@@ -150,6 +182,18 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 		o.Sensitive = oo.Sensitive
 		o.SensitiveSet = oo.SensitiveSet
 	}
+	if oo.InternalSet {
+		o.Internal = oo.Internal
+		o.InternalSet = oo.InternalSet
+	}
+	if oo.EphemeralSet {
+		o.Ephemeral = oo.Ephemeral
+		o.EphemeralSet = oo.EphemeralSet
+	}
+	if oo.DeprecatedSet {
+		o.Deprecated = oo.Deprecated
+		o.DeprecatedSet = oo.DeprecatedSet
+	}
 
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
@@ -175,16 +219,28 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 
 	if omc.Count != nil {
 		mc.Count = omc.Count
+		mc.Enabled = nil
 	}
 
 	if omc.ForEach != nil {
 		mc.ForEach = omc.ForEach
+		mc.Enabled = nil
+	}
+
+	if omc.Enabled != nil {
+		mc.Enabled = omc.Enabled
+		mc.Count = nil
+		mc.ForEach = nil
 	}
 
 	if omc.VersionAttr != nil {
 		mc.VersionAttr = omc.VersionAttr
 	}
 
+	if omc.IntegrityAttr != nil {
+		mc.IntegrityAttr = omc.IntegrityAttr
+	}
+
 	mc.Config = MergeBodies(mc.Config, omc.Config)
 
 	if len(omc.Providers) != 0 {
@@ -216,9 +272,16 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 
 	if or.Count != nil {
 		r.Count = or.Count
+		r.Enabled = nil
 	}
 	if or.ForEach != nil {
 		r.ForEach = or.ForEach
+		r.Enabled = nil
+	}
+	if or.Enabled != nil {
+		r.Enabled = or.Enabled
+		r.Count = nil
+		r.ForEach = nil
 	}
 
 	if or.ProviderConfigRef != nil {
@@ -248,10 +311,23 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 		if or.Managed.IgnoreAllChanges {
 			r.Managed.IgnoreAllChanges = true
 		}
+		if len(or.Managed.JSONAttributes) != 0 {
+			r.Managed.JSONAttributes = or.Managed.JSONAttributes
+		}
+		if len(or.Managed.YAMLAttributes) != 0 {
+			r.Managed.YAMLAttributes = or.Managed.YAMLAttributes
+		}
+		if or.Managed.OnDrift != "" {
+			r.Managed.OnDrift = or.Managed.OnDrift
+		}
 		if or.Managed.PreventDestroySet {
 			r.Managed.PreventDestroy = or.Managed.PreventDestroy
 			r.Managed.PreventDestroySet = or.Managed.PreventDestroySet
 		}
+		if or.Managed.AdoptExistingSet {
+			r.Managed.AdoptExisting = or.Managed.AdoptExisting
+			r.Managed.AdoptExistingSet = or.Managed.AdoptExistingSet
+		}
 		if len(or.Managed.Provisioners) != 0 {
 			r.Managed.Provisioners = or.Managed.Provisioners
 		}
@@ -6,6 +6,8 @@
 package configs
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 )
@@ -14,9 +16,36 @@ type Moved struct {
 	From *addrs.MoveEndpoint
 	To   *addrs.MoveEndpoint
 
+	// FromProvider and ToProvider are set instead of From and To when the
+	// block moves the objects bound to a provider configuration, such as
+	// from = provider.aws.old, to the configuration named in ToProvider.
+	FromProvider *MovedProviderEndpoint
+	ToProvider   *MovedProviderEndpoint
+
 	DeclRange hcl.Range
 }
 
+// MovedProviderEndpoint is a provider configuration address, or the address of
+// one instance of a provider configuration that uses for_each, as written in
+// a "moved" block.
+type MovedProviderEndpoint struct {
+	Config addrs.LocalProviderConfig
+
+	// Key is the instance key given in the address, or addrs.NoKey if the
+	// address refers to the provider configuration as a whole.
+	Key addrs.InstanceKey
+
+	SourceRange hcl.Range
+}
+
+func (e *MovedProviderEndpoint) String() string {
+	ret := "provider." + e.Config.StringCompact()
+	if e.Key != addrs.NoKey {
+		ret += e.Key.String()
+	}
+	return ret
+}
+
 func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	moved := &Moved{
@@ -30,9 +59,15 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 		from, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
-			from, fromDiags := addrs.ParseMoveEndpoint(from)
-			diags = append(diags, fromDiags.ToHCL()...)
-			moved.From = from
+			if isMovedProviderTraversal(from) {
+				fromProvider, fromDiags := parseMovedProviderEndpoint(from)
+				diags = append(diags, fromDiags...)
+				moved.FromProvider = fromProvider
+			} else {
+				from, fromDiags := addrs.ParseMoveEndpoint(from)
+				diags = append(diags, fromDiags.ToHCL()...)
+				moved.From = from
+			}
 		}
 	}
 
@@ -40,12 +75,48 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 		to, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
-			to, toDiags := addrs.ParseMoveEndpoint(to)
-			diags = append(diags, toDiags.ToHCL()...)
-			moved.To = to
+			if isMovedProviderTraversal(to) {
+				toProvider, toDiags := parseMovedProviderEndpoint(to)
+				diags = append(diags, toDiags...)
+				moved.ToProvider = toProvider
+			} else {
+				to, toDiags := addrs.ParseMoveEndpoint(to)
+				diags = append(diags, toDiags.ToHCL()...)
+				moved.To = to
+			}
 		}
 	}
 
+	if !diags.HasErrors() && (moved.FromProvider != nil || moved.ToProvider != nil) {
+		switch {
+		case moved.FromProvider == nil || moved.ToProvider == nil:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"moved\" addresses",
+				Detail:   "The \"from\" and \"to\" addresses must either both refer to provider configurations or both refer to resources or modules.",
+				Subject:  &moved.DeclRange,
+			})
+		case moved.FromProvider.Config.LocalName != moved.ToProvider.Config.LocalName:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"moved\" addresses",
+				Detail: fmt.Sprintf(
+					"Resources can only be moved between configurations of the same provider, but %s and %s are configurations of different providers.",
+					moved.FromProvider, moved.ToProvider,
+				),
+				Subject: &moved.DeclRange,
+			})
+		case moved.FromProvider.String() == moved.ToProvider.String():
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"moved\" addresses",
+				Detail:   "The \"from\" and \"to\" addresses must refer to different provider configurations.",
+				Subject:  &moved.DeclRange,
+			})
+		}
+		return moved, diags
+	}
+
 	// we can only move from a module to a module, resource to resource, etc.
 	if !diags.HasErrors() {
 		if !moved.From.MightUnifyWith(moved.To) {
@@ -63,6 +134,67 @@ func decodeMovedBlock(block *hcl.Block) (*Moved, hcl.Diagnostics) {
 	return moved, diags
 }
 
+// isMovedProviderTraversal returns true if the given traversal refers to a
+// provider configuration, like provider.aws.foo.
+func isMovedProviderTraversal(traversal hcl.Traversal) bool {
+	return traversal.RootName() == "provider"
+}
+
+// parseMovedProviderEndpoint parses a traversal of the form
+// provider.<local name>[.<alias>][[<key>]].
+func parseMovedProviderEndpoint(traversal hcl.Traversal) (*MovedProviderEndpoint, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := &MovedProviderEndpoint{
+		SourceRange: traversal.SourceRange(),
+	}
+
+	invalid := func(detail string) hcl.Diagnostics {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider configuration address",
+			Detail:   detail,
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+	}
+
+	remain := traversal[1:]
+	if len(remain) == 0 {
+		return nil, invalid("The provider keyword must be followed by the local name of the provider, like provider.aws.")
+	}
+	name, ok := remain[0].(hcl.TraverseAttr)
+	if !ok {
+		return nil, invalid("The provider keyword must be followed by the local name of the provider, like provider.aws.")
+	}
+	ret.Config.LocalName = name.Name
+	remain = remain[1:]
+
+	if len(remain) > 0 {
+		if alias, ok := remain[0].(hcl.TraverseAttr); ok {
+			ret.Config.Alias = alias.Name
+			remain = remain[1:]
+		}
+	}
+
+	if len(remain) > 0 {
+		idx, ok := remain[0].(hcl.TraverseIndex)
+		if !ok || ret.Config.Alias == "" {
+			return nil, invalid("A provider configuration address must have the form provider.<local name>.<alias>, optionally followed by an instance key in brackets.")
+		}
+		key, err := addrs.ParseInstanceKey(idx.Key)
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("Invalid provider instance key: %s.", err))
+		}
+		ret.Key = key
+		remain = remain[1:]
+	}
+
+	if len(remain) > 0 {
+		return nil, invalid("A provider configuration address must have the form provider.<local name>.<alias>, optionally followed by an instance key in brackets.")
+	}
+
+	return ret, diags
+}
+
 var movedBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
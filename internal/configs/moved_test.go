@@ -30,6 +30,9 @@ func TestMovedBlock_decode(t *testing.T) {
 	mod_foo_expr := hcltest.MockExprTraversalSrc("module.foo")
 	mod_bar_expr := hcltest.MockExprTraversalSrc("module.bar")
 
+	provider_old_expr := hcltest.MockExprTraversalSrc("provider.aws.old")
+	provider_new_expr := hcltest.MockExprTraversalSrc("provider.aws.new[\"us-east-1\"]")
+
 	tests := map[string]struct {
 		input *hcl.Block
 		want  *Moved
@@ -107,6 +110,37 @@ func TestMovedBlock_decode(t *testing.T) {
 			},
 			``,
 		},
+		"provider configurations": {
+			&hcl.Block{
+				Type: "moved",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: provider_old_expr,
+						},
+						"to": {
+							Name: "to",
+							Expr: provider_new_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Moved{
+				FromProvider: &MovedProviderEndpoint{
+					Config:      addrs.LocalProviderConfig{LocalName: "aws", Alias: "old"},
+					SourceRange: provider_old_expr.Range(),
+				},
+				ToProvider: &MovedProviderEndpoint{
+					Config:      addrs.LocalProviderConfig{LocalName: "aws", Alias: "new"},
+					Key:         addrs.StringKey("us-east-1"),
+					SourceRange: provider_new_expr.Range(),
+				},
+				DeclRange: blockRange,
+			},
+			``,
+		},
 		"error: missing argument": {
 			&hcl.Block{
 				Type: "moved",
@@ -174,6 +208,55 @@ func TestMovedBlock_decode(t *testing.T) {
 	}
 }
 
+func TestMovedBlock_decodeProviderErrors(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+		want     string
+	}{
+		"provider to resource": {
+			"provider.aws.old", "test_instance.foo",
+			"Invalid \"moved\" addresses",
+		},
+		"different providers": {
+			"provider.aws.old", "provider.google.old",
+			"Invalid \"moved\" addresses",
+		},
+		"same address": {
+			"provider.aws.old[0]", "provider.aws.old[0]",
+			"Invalid \"moved\" addresses",
+		},
+		"key without alias": {
+			"provider.aws[0]", "provider.aws.new",
+			"Invalid provider configuration address",
+		},
+		"missing local name": {
+			"provider", "provider.aws.new",
+			"Invalid provider configuration address",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			block := &hcl.Block{
+				Type: "moved",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {Name: "from", Expr: hcltest.MockExprTraversalSrc(test.from)},
+						"to":   {Name: "to", Expr: hcltest.MockExprTraversalSrc(test.to)},
+					},
+				}),
+			}
+			_, diags := decodeMovedBlock(block)
+			if !diags.HasErrors() {
+				t.Fatal("expected error")
+			}
+			if got := diags[0].Summary; got != test.want {
+				t.Errorf("wrong error, got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
 func TestMovedBlock_inModule(t *testing.T) {
 	parser := NewParser(nil)
 	mod, diags := parser.LoadConfigDir("testdata/valid-modules/moved-blocks", RootModuleCallForTesting())
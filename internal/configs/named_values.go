@@ -7,6 +7,7 @@ package configs
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
@@ -35,12 +36,36 @@ type Variable struct {
 	ConstraintType cty.Type
 	TypeDefaults   *typeexpr.Defaults
 
+	// TypeRefinements are the union types, string patterns, and number
+	// ranges in the type constraint, which must be applied to values after
+	// converting them to ConstraintType. It is nil if there are none.
+	TypeRefinements *TypeRefinements
+
+	// DeepMergeDefaults, if set, causes the default values of optional
+	// object attributes to be merged into the objects given for those
+	// attributes, rather than being used only when the attribute is
+	// omitted. See ApplyTypeDefaults.
+	DeepMergeDefaults bool
+
 	ParsingMode VariableParsingMode
 	Validations []*CheckRule
 	Sensitive   bool
 
+	// Ephemeral variables can be set to a different value in each run, and
+	// so their values are never saved in plan files. Values derived from
+	// them must not be saved in state or plan files either.
+	Ephemeral bool
+
+	// Deprecated, if set, is a message explaining that the variable is
+	// deprecated, which OpenTofu reports to the calling modules that set it.
+	Deprecated string
+
 	DescriptionSet bool
 	SensitiveSet   bool
+	EphemeralSet   bool
+	DeprecatedSet  bool
+
+	DeepMergeDefaultsSet bool
 
 	// Nullable indicates that null is a valid value for this variable. Setting
 	// Nullable to false means that the module can expect this variable to
@@ -82,7 +107,7 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 	// reserved attribute and block type names in a "module" block, since
 	// these won't be usable for child modules.
 	for _, attr := range moduleBlockSchema.Attributes {
-		if attr.Name == v.Name {
+		if attr.Name == v.Name && !moduleBlockUnreservedArguments[attr.Name] {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid variable name",
@@ -113,16 +138,42 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		diags = append(diags, tyDiags...)
 		v.ConstraintType = ty
 		v.TypeDefaults = tyDefaults
+		if !tyDiags.HasErrors() {
+			refinements, refinementDiags := decodeTypeRefinements(attr.Expr)
+			diags = append(diags, refinementDiags...)
+			v.TypeRefinements = refinements
+		}
 		v.Type = ty.WithoutOptionalAttributesDeep()
 		v.ParsingMode = parseMode
 	}
 
+	// This must be decoded before the default value, which has the type
+	// defaults applied to it.
+	if attr, exists := content.Attributes["deep_merge_defaults"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.DeepMergeDefaults)
+		diags = append(diags, valDiags...)
+		v.DeepMergeDefaultsSet = true
+	}
+
 	if attr, exists := content.Attributes["sensitive"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Sensitive)
 		diags = append(diags, valDiags...)
 		v.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Ephemeral)
+		diags = append(diags, valDiags...)
+		v.EphemeralSet = true
+	}
+
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		msg, valDiags := decodeDeprecatedMessage(attr)
+		diags = append(diags, valDiags...)
+		v.Deprecated = msg
+		v.DeprecatedSet = true
+	}
+
 	if attr, exists := content.Attributes["nullable"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Nullable)
 		diags = append(diags, valDiags...)
@@ -150,10 +201,11 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 			// unless the default value is null. Null is excluded from the
 			// type default application process as a special case, to allow
 			// nullable variables to have a null default value.
-			if v.TypeDefaults != nil && !val.IsNull() {
-				val = v.TypeDefaults.Apply(val)
-			}
+			val = v.ApplyTypeDefaults(val)
 			val, err = convert.Convert(val, v.ConstraintType)
+			if err == nil {
+				val, err = v.TypeRefinements.Apply(val)
+			}
 			if err != nil {
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
@@ -255,7 +307,9 @@ func decodeVariableType(expr hcl.Expression) (cty.Type, *typeexpr.Defaults, Vari
 		return cty.Map(cty.DynamicPseudoType), nil, VariableParseHCL, nil
 	}
 
-	ty, typeDefaults, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	// The refined type constructors are decoded separately by
+	// decodeTypeRefinements, so typeexpr must only see their base types.
+	ty, typeDefaults, diags := typeexpr.TypeConstraintWithDefaults(baseTypeExpr{expr})
 	if diags.HasErrors() {
 		return cty.DynamicPseudoType, nil, VariableParseHCL, diags
 	}
@@ -270,6 +324,22 @@ func decodeVariableType(expr hcl.Expression) (cty.Type, *typeexpr.Defaults, Vari
 	}
 }
 
+// ApplyTypeDefaults applies the default values of the optional object
+// attributes in the variable's type constraint to the given value, which
+// must then still be converted to the constraint type.
+//
+// Null values are returned unchanged, as a special case to allow nullable
+// variables to be set to null.
+func (v *Variable) ApplyTypeDefaults(val cty.Value) cty.Value {
+	if v.TypeDefaults == nil || val.IsNull() {
+		return val
+	}
+	if v.DeepMergeDefaults {
+		val = deepMergeTypeDefaults(v.TypeDefaults, val)
+	}
+	return v.TypeDefaults.Apply(val)
+}
+
 func (v *Variable) Addr() addrs.InputVariable {
 	return addrs.InputVariable{Name: v.Name}
 }
@@ -328,14 +398,15 @@ func (m VariableParsingMode) Parse(name, value string) (cty.Value, hcl.Diagnosti
 }
 
 // decodeVariableValidationBlock is a wrapper around decodeCheckRuleBlock
-// that imposes the additional rule that the condition expression can refer
-// only to an input variable of the given name.
+// that imposes the additional rule that the condition expression must refer
+// to the input variable of the given name.
 func decodeVariableValidationBlock(varName string, block *hcl.Block, override bool) (*CheckRule, hcl.Diagnostics) {
 	vv, diags := decodeCheckRuleBlock(block, override)
 	if vv.Condition != nil {
-		// The validation condition can only refer to the variable itself,
-		// to ensure that the variable declaration can't create additional
-		// edges in the dependency graph.
+		// The validation condition must refer to the variable itself, since
+		// otherwise it wouldn't be testing the incoming value. It may also
+		// refer to other objects, such as other input variables and local
+		// values, to check constraints that involve more than one variable.
 		goodRefs := 0
 		for _, traversal := range vv.Condition.Variables() {
 			ref, moreDiags := addrs.ParseRef(traversal)
@@ -387,10 +458,29 @@ type Output struct {
 	DependsOn   []hcl.Traversal
 	Sensitive   bool
 
+	// Internal outputs of the root module are available within the
+	// configuration, for example to tests, but are not exposed to users of
+	// the root module: they are excluded from "tofu output", the JSON output
+	// formats and terraform_remote_state data sources.
+	Internal bool
+
+	// Ephemeral outputs of child modules can return values derived from
+	// ephemeral input variables, which the calling module must not save in
+	// state or plan files either. Root module outputs can't be ephemeral.
+	Ephemeral bool
+
+	// Deprecated, if set, is a message explaining that the output value is
+	// deprecated, which OpenTofu reports to the calling modules that refer
+	// to it.
+	Deprecated string
+
 	Preconditions []*CheckRule
 
 	DescriptionSet bool
 	SensitiveSet   bool
+	InternalSet    bool
+	EphemeralSet   bool
+	DeprecatedSet  bool
 
 	DeclRange hcl.Range
 
@@ -401,6 +491,10 @@ type Output struct {
 	// should be used instead of evaluated expression. It's possible to have no
 	// OverrideValue even with IsOverridden is set to true.
 	OverrideValue *cty.Value
+	// OverrideExpr is like OverrideValue, except that it is an expression to
+	// evaluate in the scope of the module instead of Expr. It's used by the
+	// testing framework for modules that are replaced with stubs.
+	OverrideExpr hcl.Expression
 }
 
 func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostics) {
@@ -444,6 +538,25 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["internal"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Internal)
+		diags = append(diags, valDiags...)
+		o.InternalSet = true
+	}
+
+	if attr, exists := content.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Ephemeral)
+		diags = append(diags, valDiags...)
+		o.EphemeralSet = true
+	}
+
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		msg, valDiags := decodeDeprecatedMessage(attr)
+		diags = append(diags, valDiags...)
+		o.Deprecated = msg
+		o.DeprecatedSet = true
+	}
+
 	if attr, exists := content.Attributes["depends_on"]; exists {
 		deps, depsDiags := decodeDependsOn(attr)
 		diags = append(diags, depsDiags...)
@@ -473,6 +586,25 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 	return o, diags
 }
 
+// decodeDeprecatedMessage decodes the "deprecated" argument of a variable or
+// output block, which must be a non-empty string explaining the deprecation.
+func decodeDeprecatedMessage(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	var msg string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &msg)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	if strings.TrimSpace(msg) == "" {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid deprecation message",
+			Detail:   "The \"deprecated\" argument must be a message explaining the deprecation, such as which alternative to use instead.",
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+	return msg, diags
+}
+
 func (o *Output) Addr() addrs.OutputValue {
 	return addrs.OutputValue{Name: o.Name}
 }
@@ -535,9 +667,18 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
+		{
+			Name: "deprecated",
+		},
 		{
 			Name: "nullable",
 		},
+		{
+			Name: "deep_merge_defaults",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -561,6 +702,15 @@ var outputBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "internal",
+		},
+		{
+			Name: "ephemeral",
+		},
+		{
+			Name: "deprecated",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
@@ -29,6 +29,10 @@ type Parser struct {
 	// for itself whether to enable it so that tests can cover both the
 	// allowed and not-allowed situations.
 	allowExperiments bool
+
+	// strictDeprecations causes the warnings about uses of deprecated
+	// language features to be reported as errors instead.
+	strictDeprecations bool
 }
 
 // NewParser creates and returns a new Parser that reads files from the given
@@ -96,6 +100,16 @@ func (p *Parser) Sources() map[string]*hcl.File {
 	return p.p.Files()
 }
 
+// ForgetFile removes the file with the given filename (as requested when it
+// was loaded) from the cache of parsed files, so that the next attempt to
+// load it will read and parse it again.
+//
+// This allows a long-lived parser to reload a configuration incrementally
+// after some of its files have changed, reparsing only those files.
+func (p *Parser) ForgetFile(filename string) {
+	delete(p.p.Files(), filename)
+}
+
 // ForceFileSource artificially adds source code to the cache of file sources,
 // as if it had been loaded from the given filename.
 //
@@ -123,3 +137,10 @@ func (p *Parser) ForceFileSource(filename string, src []byte) {
 func (p *Parser) AllowLanguageExperiments(allowed bool) {
 	p.allowExperiments = allowed
 }
+
+// StrictDeprecations specifies whether subsequent LoadConfigFile (and similar)
+// calls will report uses of deprecated language features as errors rather
+// than as warnings, so that automation can prevent new uses of them.
+func (p *Parser) StrictDeprecations(strict bool) {
+	p.strictDeprecations = strict
+}
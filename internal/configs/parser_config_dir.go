@@ -6,7 +6,9 @@
 package configs
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path"
@@ -15,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"golang.org/x/mod/sumdb/dirhash"
 )
 
 const (
@@ -67,6 +70,11 @@ func (p *Parser) LoadConfigDirSelective(path string, call StaticModuleCall, load
 
 	mod, modDiags := NewModule(primary, override, call, path, load)
 	diags = append(diags, modDiags...)
+	if mod != nil && !call.addr.IsRoot() {
+		// Only the hashes of child modules are recorded, and walking the
+		// root module directory could be expensive.
+		mod.ContentHash = p.moduleFilesHash(path)
+	}
 
 	return mod, diags
 }
@@ -88,6 +96,11 @@ func (p *Parser) LoadConfigDirWithTests(path string, testDirectory string, call
 
 	mod, modDiags := NewModuleWithTests(primary, override, tests, call, path)
 	diags = append(diags, modDiags...)
+	if mod != nil && !call.addr.IsRoot() {
+		// Only the hashes of child modules are recorded, and walking the
+		// root module directory could be expensive.
+		mod.ContentHash = p.moduleFilesHash(path)
+	}
 
 	return mod, diags
 }
@@ -138,6 +151,66 @@ func (p *Parser) loadFiles(paths []string, override bool) ([]*File, hcl.Diagnost
 	return files, diags
 }
 
+// moduleFilesHash returns a hash of the names and contents of the files in
+// the given module directory and its subdirectories, so that changes to
+// templates and other files that the module reads are detected along with
+// changes to its configuration files. It returns an empty string if any of
+// the files cannot be read.
+//
+// Hidden files and directories, test files, and subdirectories containing
+// configuration files are skipped, because the latter are usually nested
+// modules, which have their own hashes.
+func (p *Parser) moduleFilesHash(dir string) string {
+	var names []string
+	err := p.fs.Walk(dir, func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fullPath == dir {
+			return nil
+		}
+		name := info.Name()
+		if strings.HasPrefix(name, ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if p.IsConfigDir(fullPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || isTestFileExt(fileExt(name)) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, fullPath)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to hash the files of a module: %s", err)
+		return ""
+	}
+
+	hash, err := dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		src, err := p.fs.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(src)), nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to hash the files of a module: %s", err)
+		return ""
+	}
+	return hash
+}
+
 // dirFiles finds OpenTofu configuration files within dir, splitting them into
 // primary and override files based on the filename.
 //
@@ -355,6 +428,18 @@ func isTestFileExt(ext string) bool {
 	return ext == tfTestExt || ext == tfTestJSONExt || ext == tofuTestExt || ext == tofuTestJSONExt
 }
 
+// IsConfigFile returns true if the given filename has one of the extensions
+// of OpenTofu configuration files, including test files.
+func IsConfigFile(name string) bool {
+	return fileExt(name) != ""
+}
+
+// IsTestFile returns true if the given filename has one of the extensions of
+// OpenTofu test files.
+func IsTestFile(name string) bool {
+	return isTestFileExt(fileExt(name))
+}
+
 // IsIgnoredFile returns true if the given filename (which must not have a
 // directory path ahead of it) should be ignored as e.g. an editor swap file.
 func IsIgnoredFile(name string) bool {
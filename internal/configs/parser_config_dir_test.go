@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -320,6 +321,80 @@ func TestParserLoadConfigDirWithTests_TofuFiles(t *testing.T) {
 	}
 }
 
+func TestParserLoadConfigDirContentHash(t *testing.T) {
+	call := NewStaticModuleCall(addrs.RootModule.Child("mod"), nil, "<testing>", "")
+	hash := func(files map[string]string) string {
+		t.Helper()
+		parser := testParser(files)
+		mod, diags := parser.LoadConfigDir("mod", call)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return mod.ContentHash
+	}
+
+	base := hash(map[string]string{
+		"mod/main.tf":                 `locals { a = 1 }`,
+		"mod/other.tf":                `locals { b = 2 }`,
+		"mod/templates/user_data.tpl": `#!/bin/sh`,
+		"mod/sub/main.tf":             `locals { c = 3 }`,
+		"mod/.terraform/cache":        `cached`,
+		"mod/main.tftest.hcl":         `run "test" {}`,
+	})
+	if !strings.HasPrefix(base, "h1:") {
+		t.Fatalf("unexpected hash %q", base)
+	}
+
+	// Nested modules, hidden files and test files aren't hashed.
+	if got := hash(map[string]string{
+		"mod/main.tf":                 `locals { a = 1 }`,
+		"mod/other.tf":                `locals { b = 2 }`,
+		"mod/templates/user_data.tpl": `#!/bin/sh`,
+	}); got != base {
+		t.Errorf("hash changed for unrelated files\ngot:  %s\nwant: %s", got, base)
+	}
+
+	for name, files := range map[string]map[string]string{
+		"changed content": {
+			"mod/main.tf":                 `locals { a = 2 }`,
+			"mod/other.tf":                `locals { b = 2 }`,
+			"mod/templates/user_data.tpl": `#!/bin/sh`,
+		},
+		"renamed file": {
+			"mod/main.tf":                 `locals { a = 1 }`,
+			"mod/other2.tf":               `locals { b = 2 }`,
+			"mod/templates/user_data.tpl": `#!/bin/sh`,
+		},
+		"added override": {
+			"mod/main.tf":                 `locals { a = 1 }`,
+			"mod/other.tf":                `locals { b = 2 }`,
+			"mod/main_override.tf":        `locals { a = 3 }`,
+			"mod/templates/user_data.tpl": `#!/bin/sh`,
+		},
+		"changed template": {
+			"mod/main.tf":                 `locals { a = 1 }`,
+			"mod/other.tf":                `locals { b = 2 }`,
+			"mod/templates/user_data.tpl": `#!/bin/bash`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := hash(files); got == base {
+				t.Errorf("hash did not change")
+			}
+		})
+	}
+
+	// The root module isn't hashed.
+	parser := testParser(map[string]string{"root/main.tf": `locals { a = 1 }`})
+	mod, diags := parser.LoadConfigDir("root", RootModuleCallForTesting())
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	if mod.ContentHash != "" {
+		t.Errorf("unexpected hash for the root module: %s", mod.ContentHash)
+	}
+}
+
 func TestIsEmptyDir(t *testing.T) {
 	val, err := IsEmptyDir(filepath.Join("testdata", "valid-files"))
 	if err != nil {
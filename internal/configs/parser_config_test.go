@@ -106,6 +106,12 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			"Invalid data resource lifecycle argument",
 			`The lifecycle argument "ignore_changes" is defined only for managed resources ("resource" blocks), and is not valid for data resources.`,
 		},
+		{
+			"invalid-files/ephemeral-resource-lifecycle.tf",
+			hcl.DiagError,
+			"Invalid lifecycle block",
+			`Ephemeral resources do not support "lifecycle" blocks, because OpenTofu never plans changes to them.`,
+		},
 		{
 			"invalid-files/variable-type-unknown.tf",
 			hcl.DiagError,
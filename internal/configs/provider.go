@@ -48,10 +48,26 @@ type Provider struct {
 	OverrideResources []*OverrideResource
 
 	ForEach   hcl.Expression
+	Count     hcl.Expression
 	Instances map[addrs.InstanceKey]instances.RepetitionData
+
+	// DependsOn lists the objects that must be created or updated before
+	// the provider is configured.
+	DependsOn []hcl.Traversal
+
+	// Preconditions are checked before each instance of the provider is
+	// configured.
+	Preconditions []*CheckRule
+
+	// DenyDestroy is set when the provider configuration forbids plans that
+	// destroy any of the objects belonging to it. The value is evaluated
+	// statically, so it can only refer to variables and locals.
+	DenyDestroy      bool
+	DenyDestroyRange *hcl.Range // nil if deny_destroy is not set
+	denyDestroyExpr  hcl.Expression
 }
 
-func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
+func decodeProviderBlock(block *hcl.Block, override bool) (*Provider, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	content, config, moreDiags := block.Body.PartialContent(providerBlockSchema)
@@ -96,6 +112,7 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 			Summary:  "Version constraints inside provider configuration blocks are deprecated",
 			Detail:   "OpenTofu 0.13 and earlier allowed provider version constraints inside the provider configuration block, but that is now deprecated and will be removed in a future version of OpenTofu. To silence this warning, move the provider version constraint into the required_providers block.",
 			Subject:  attr.Expr.Range().Ptr(),
+			Extra:    deprecationDiagExtra{},
 		})
 		var versionDiags hcl.Diagnostics
 		provider.Version, versionDiags = decodeVersionConstraint(attr)
@@ -106,6 +123,30 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 		provider.ForEach = attr.Expr
 	}
 
+	if attr, exists := content.Attributes["count"]; exists {
+		provider.Count = attr.Expr
+
+		if provider.ForEach != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  `Invalid combination of "count" and "for_each"`,
+				Detail:   `The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of provider instances to be created.`,
+				Subject:  &attr.NameRange,
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["depends_on"]; exists {
+		deps, depsDiags := decodeDependsOn(attr)
+		diags = append(diags, depsDiags...)
+		provider.DependsOn = append(provider.DependsOn, deps...)
+	}
+
+	if attr, exists := content.Attributes["deny_destroy"]; exists {
+		provider.denyDestroyExpr = attr.Expr
+		provider.DenyDestroyRange = attr.Range.Ptr()
+	}
+
 	if len(provider.Alias) == 0 && provider.ForEach != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -115,8 +156,17 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 		})
 	}
 
+	if len(provider.Alias) == 0 && provider.Count != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  `Alias required when using "count"`,
+			Detail:   `The count argument is allowed only for provider configurations with an alias.`,
+			Subject:  provider.Count.Range().Ptr(),
+		})
+	}
+
 	// Reserved attribute names
-	for _, name := range []string{"count", "depends_on", "source"} {
+	for _, name := range []string{"source"} {
 		if attr, exists := content.Attributes[name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -127,9 +177,38 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 		}
 	}
 
+	var seenLifecycle *hcl.Block
 	var seenEscapeBlock *hcl.Block
 	for _, block := range content.Blocks {
 		switch block.Type {
+		case "lifecycle":
+			if seenLifecycle != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate lifecycle block",
+					Detail:   fmt.Sprintf("This provider configuration already has a lifecycle block at %s.", seenLifecycle.DefRange),
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+			seenLifecycle = block
+
+			lcContent, lcDiags := block.Body.Content(providerLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+
+			for _, block := range lcContent.Blocks {
+				switch block.Type {
+				case "precondition":
+					cr, moreDiags := decodeCheckRuleBlock(block, override)
+					diags = append(diags, moreDiags...)
+					provider.Preconditions = append(provider.Preconditions, cr)
+				default:
+					// The cases above should be exhaustive for all block types
+					// defined in the lifecycle schema, so this shouldn't happen.
+					panic(fmt.Sprintf("unexpected lifecycle sub-block type %q", block.Type))
+				}
+			}
+
 		case "_":
 			if seenEscapeBlock != nil {
 				diags = append(diags, &hcl.Diagnostic{
@@ -168,17 +247,43 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 func (p *Provider) decodeStaticFields(eval *StaticEvaluator) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
-	if p.ForEach != nil {
-		forEachRefsFunc := func(refs []*addrs.Reference) (*hcl.EvalContext, tfdiags.Diagnostics) {
-			var diags tfdiags.Diagnostics
-			evalContext, evalDiags := eval.EvalContext(StaticIdentifier{
-				Module:    eval.call.addr,
-				Subject:   fmt.Sprintf("provider.%s.%s.for_each", p.Name, p.Alias),
-				DeclRange: p.ForEach.Range(),
-			}, refs)
-			return evalContext, diags.Append(evalDiags)
-		}
+	if p.denyDestroyExpr != nil {
+		diags = append(diags, eval.DecodeExpression(p.denyDestroyExpr, StaticIdentifier{
+			Module:    eval.call.addr,
+			Subject:   fmt.Sprintf("provider.%s.deny_destroy", p.moduleUniqueKey()),
+			DeclRange: p.denyDestroyExpr.Range(),
+		}, &p.DenyDestroy)...)
+	}
+
+	forEachRefsFunc := func(refs []*addrs.Reference) (*hcl.EvalContext, tfdiags.Diagnostics) {
+		var diags tfdiags.Diagnostics
+		evalContext, evalDiags := eval.EvalContext(StaticIdentifier{
+			Module:    eval.call.addr,
+			Subject:   fmt.Sprintf("provider.%s.%s.for_each", p.Name, p.Alias),
+			DeclRange: p.ForEach.Range(),
+		}, refs)
+		return evalContext, diags.Append(evalDiags)
+	}
+	countFunc := func(expr hcl.Expression) (cty.Value, tfdiags.Diagnostics) {
+		var diags tfdiags.Diagnostics
+		val, evalDiags := eval.Evaluate(expr, StaticIdentifier{
+			Module:    eval.call.addr,
+			Subject:   fmt.Sprintf("provider.%s.%s.count", p.Name, p.Alias),
+			DeclRange: expr.Range(),
+		})
+		return val, diags.Append(evalDiags)
+	}
+	diags = append(diags, p.decodeInstances(forEachRefsFunc, countFunc)...)
 
+	return diags
+}
+
+// decodeInstances sets the instances of the provider configuration from its
+// for_each or count argument, evaluated using the given functions.
+func (p *Provider) decodeInstances(forEachRefsFunc evalchecks.ContextFunc, countFunc evalchecks.EvaluateFunc) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if p.ForEach != nil {
 		forVal, evalDiags := evalchecks.EvaluateForEachExpression(p.ForEach, forEachRefsFunc, nil)
 		diags = append(diags, evalDiags.ToHCL()...)
 		if evalDiags.HasErrors() {
@@ -194,9 +299,44 @@ func (p *Provider) decodeStaticFields(eval *StaticEvaluator) hcl.Diagnostics {
 		}
 	}
 
+	if p.Count != nil {
+		count, evalDiags := evalchecks.EvaluateCountExpression(p.Count, countFunc, nil)
+		diags = append(diags, evalDiags.ToHCL()...)
+		if evalDiags.HasErrors() {
+			return diags
+		}
+
+		p.Instances = make(map[addrs.InstanceKey]instances.RepetitionData, count)
+		for i := 0; i < count; i++ {
+			p.Instances[addrs.IntKey(i)] = instances.RepetitionData{
+				CountIndex: cty.NumberIntVal(int64(i)),
+			}
+		}
+	}
+
 	return diags
 }
 
+// decodeTestInstances sets the instances of a provider configuration from a
+// test file. Test files have no static evaluator, so for_each and count are
+// evaluated in the given context instead, which has the variables of the test
+// and the outputs of the run blocks that have already been executed.
+func (p *Provider) decodeTestInstances(evalCtx *hcl.EvalContext) hcl.Diagnostics {
+	if p.ForEach == nil && p.Count == nil {
+		return nil
+	}
+
+	forEachRefsFunc := func(refs []*addrs.Reference) (*hcl.EvalContext, tfdiags.Diagnostics) {
+		return evalCtx, nil
+	}
+	countFunc := func(expr hcl.Expression) (cty.Value, tfdiags.Diagnostics) {
+		var diags tfdiags.Diagnostics
+		val, evalDiags := expr.Value(evalCtx)
+		return val, diags.Append(evalDiags)
+	}
+	return p.decodeInstances(forEachRefsFunc, countFunc)
+}
+
 // Addr returns the address of the receiving provider configuration, relative
 // to its containing module.
 func (p *Provider) Addr() addrs.LocalProviderConfig {
@@ -302,21 +442,34 @@ var providerBlockSchema = &hcl.BodySchema{
 		{
 			Name: "for_each",
 		},
+		{
+			Name: "count",
+		},
+		{
+			Name: "depends_on",
+		},
+		{
+			Name: "deny_destroy",
+		},
 
 		// Attribute names reserved for future expansion.
-		{Name: "count"},
-		{Name: "depends_on"},
 		{Name: "source"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "_"}, // meta-argument escaping block
+		{Type: "lifecycle"},
 
 		// The rest of these are reserved for future expansion.
-		{Type: "lifecycle"},
 		{Type: "locals"},
 	},
 }
 
+var providerLifecycleBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "precondition"},
+	},
+}
+
 // checkProviderNameNormalized verifies that the given string is already
 // normalized and returns an error if not.
 func checkProviderNameNormalized(name string, declrange hcl.Range) hcl.Diagnostics {
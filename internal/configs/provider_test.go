@@ -7,12 +7,16 @@ package configs
 
 import (
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/go-test/deep"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/instances"
 )
 
 func TestProviderReservedNames(t *testing.T) {
@@ -28,12 +32,169 @@ func TestProviderReservedNames(t *testing.T) {
 	assertExactDiagnostics(t, diags, []string{
 		//TODO: This deprecation warning will be removed in OpenTofu v0.15.
 		`config.tf:4,13-20: Version constraints inside provider configuration blocks are deprecated; OpenTofu 0.13 and earlier allowed provider version constraints inside the provider configuration block, but that is now deprecated and will be removed in a future version of OpenTofu. To silence this warning, move the provider version constraint into the required_providers block.`,
-		`config.tf:10,3-8: Reserved argument name in provider block; The provider argument name "count" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:11,3-13: Reserved argument name in provider block; The provider argument name "depends_on" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:12,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:13,3-12: Reserved block type name in provider block; The block type name "lifecycle" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:14,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:10,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:11,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
+	})
+}
+
+func TestProviderCount(t *testing.T) {
+	parser := testParser(map[string]string{
+		"config/main.tf": `
+locals {
+  regions = ["us-east-1", "eu-west-1"]
+}
+
+provider "aws" {
+  alias  = "region"
+  count  = length(local.regions)
+  region = local.regions[count.index]
+}
+
+provider "aws" {
+  count = 2
+}
+
+provider "aws" {
+  alias    = "both"
+  count    = 2
+  for_each = toset(["a"])
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("config", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`config/main.tf:13,11-12: Alias required when using "count"; The count argument is allowed only for provider configurations with an alias.`,
+		`config/main.tf:18,3-8: Invalid combination of "count" and "for_each"; The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of provider instances to be created.`,
+	})
+
+	pc := mod.ProviderConfigs["aws.region"]
+	if pc == nil {
+		t.Fatal("aws.region configuration not found")
+	}
+	want := map[addrs.InstanceKey]instances.RepetitionData{
+		addrs.IntKey(0): {CountIndex: cty.NumberIntVal(0)},
+		addrs.IntKey(1): {CountIndex: cty.NumberIntVal(1)},
+	}
+	for _, problem := range deep.Equal(pc.Instances, want) {
+		t.Error(problem)
+	}
+}
+
+func TestProviderDependsOn(t *testing.T) {
+	parser := testParser(map[string]string{
+		"config/main.tf": `
+provider "aws" {
+  alias      = "cluster"
+  depends_on = [module.eks, aws_iam_role.admin]
+}
+`,
+		"config/main_override.tf": `
+provider "aws" {
+  alias      = "cluster"
+  depends_on = [module.eks]
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("config", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`config/main_override.tf:4,17-27: Unsupported override; The depends_on argument may not be overridden.`,
+	})
+
+	pc := mod.ProviderConfigs["aws.cluster"]
+	if pc == nil {
+		t.Fatal("aws.cluster configuration not found")
+	}
+	var got []string
+	for _, traversal := range pc.DependsOn {
+		got = append(got, traversal.RootName())
+	}
+	if want := []string{"module", "aws_iam_role"}; !slices.Equal(got, want) {
+		t.Errorf("wrong depends_on\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestProviderLifecycle(t *testing.T) {
+	parser := testParser(map[string]string{
+		"config/main.tf": `
+provider "aws" {
+  region = "us-east-1"
+
+  lifecycle {
+    precondition {
+      condition     = terraform.workspace == "prod"
+      error_message = "This provider is only for the prod workspace."
+    }
+  }
+}
+
+provider "aws" {
+  alias = "dup"
+
+  lifecycle {}
+  lifecycle {}
+}
+`,
+		"config/main_override.tf": `
+provider "aws" {
+  lifecycle {
+    precondition {
+      condition     = terraform.workspace == "dev"
+      error_message = "Overridden."
+    }
+  }
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("config", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`config/main.tf:17,3-12: Duplicate lifecycle block; This provider configuration already has a lifecycle block at config/main.tf:16,3-12.`,
+		`config/main_override.tf:4,5-17: Can't override precondition blocks; Override files cannot override "precondition" blocks.`,
 	})
+
+	pc := mod.ProviderConfigs["aws"]
+	if pc == nil {
+		t.Fatal("aws configuration not found")
+	}
+	if got, want := len(pc.Preconditions), 1; got != want {
+		t.Fatalf("wrong number of preconditions: got %d, want %d", got, want)
+	}
+	if got, want := pc.Preconditions[0].DeclRange.Start.Line, 6; got != want {
+		t.Errorf("wrong precondition line: got %d, want %d", got, want)
+	}
+}
+
+func TestProviderDenyDestroy(t *testing.T) {
+	parser := testParser(map[string]string{
+		"config/main.tf": `
+locals {
+  protected = true
+}
+
+provider "aws" {
+  alias        = "prod"
+  deny_destroy = local.protected
+}
+
+provider "aws" {
+  alias        = "invalid"
+  deny_destroy = "sometimes"
+}
+
+provider "aws" {
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("config", RootModuleCallForTesting())
+	assertExactDiagnostics(t, diags, []string{
+		`config/main.tf:13,19-28: Unsuitable value type; Unsuitable value: a bool is required`,
+	})
+
+	if !mod.ProviderConfigs["aws.prod"].DenyDestroy {
+		t.Error("deny_destroy not set for aws.prod")
+	}
+	if mod.ProviderConfigs["aws"].DenyDestroy {
+		t.Error("deny_destroy unexpectedly set for the default aws configuration")
+	}
 }
 
 func TestParseProviderConfigCompact(t *testing.T) {
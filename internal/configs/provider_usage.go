@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// UnusedProviderConfig describes a provider configuration block that is not
+// used by anything in the configuration tree.
+type UnusedProviderConfig struct {
+	// Addr is the absolute address of the provider configuration.
+	Addr addrs.AbsProviderConfig
+
+	// Config is the provider block itself, whose DeclRange can be used to
+	// point the user at the declaration to remove.
+	Config *Provider
+}
+
+// UnusedProviderConfigs returns all of the provider configurations declared
+// anywhere in the configuration tree which are never used, either directly
+// by a resource or import block, by being passed to a child module in a
+// "providers" argument, or implicitly by inheritance into a child module.
+//
+// This resolves provider configurations using the same rules as the
+// provider transformers in package tofu, but does so using only the
+// configuration, so it can report the source location of each unused block.
+// The result is sorted by address.
+func (c *Config) UnusedProviderConfigs() []UnusedProviderConfig {
+	configured := make(map[string]UnusedProviderConfig)
+	proxies := make(map[string]addrs.AbsProviderConfig)
+	used := make(map[string]bool)
+
+	c.DeepEach(func(c *Config) {
+		mod := c.Module
+		for _, p := range mod.ProviderConfigs {
+			addr := addrs.AbsProviderConfig{
+				Module:   c.Path,
+				Provider: mod.ProviderForLocalConfig(p.Addr()),
+				Alias:    p.Alias,
+			}
+			configured[addr.String()] = UnusedProviderConfig{Addr: addr, Config: p}
+		}
+
+		for name, mc := range mod.ModuleCalls {
+			child := c.Children[name]
+			if child == nil {
+				continue
+			}
+			for _, pp := range mc.Providers {
+				inChild := addrs.AbsProviderConfig{
+					Module:   child.Path,
+					Provider: child.Module.ProviderForLocalConfig(pp.InChild.Addr()),
+					Alias:    pp.InChild.Alias,
+				}
+				proxies[inChild.String()] = addrs.AbsProviderConfig{
+					Module:   c.Path,
+					Provider: mod.ProviderForLocalConfig(pp.InParent.Addr()),
+					Alias:    pp.InParent.Alias,
+				}
+			}
+		}
+	})
+
+	// resolve returns the key of the provider configuration block that
+	// ultimately serves the given address, or an empty string if there is no
+	// such block, as is the case for an implied empty configuration.
+	var resolve func(addr addrs.AbsProviderConfig) string
+	resolve = func(addr addrs.AbsProviderConfig) string {
+		for {
+			if _, ok := configured[addr.String()]; ok {
+				return addr.String()
+			}
+			if target, ok := proxies[addr.String()]; ok {
+				return resolve(target)
+			}
+			parent, ok := addr.Inherited()
+			if !ok {
+				return ""
+			}
+			addr = parent
+		}
+	}
+
+	c.DeepEach(func(c *Config) {
+		mod := c.Module
+		for _, rs := range []map[string]*Resource{mod.ManagedResources, mod.DataResources, mod.EphemeralResources} {
+			for _, r := range rs {
+				used[resolve(addrs.AbsProviderConfig{
+					Module:   c.Path,
+					Provider: r.Provider,
+					Alias:    r.ProviderConfigAddr().Alias,
+				})] = true
+			}
+		}
+
+		for _, i := range mod.Import {
+			addr := addrs.AbsProviderConfig{
+				Module:   c.Path,
+				Provider: i.Provider,
+			}
+			if i.ProviderConfigRef != nil {
+				addr.Alias = i.ProviderConfigRef.Alias
+			}
+			used[resolve(addr)] = true
+		}
+
+		// Passing a configuration to a child module counts as using it even
+		// if the child module doesn't end up using it, since removing it would
+		// make the module call invalid.
+		for _, mc := range mod.ModuleCalls {
+			for _, pp := range mc.Providers {
+				used[resolve(addrs.AbsProviderConfig{
+					Module:   c.Path,
+					Provider: mod.ProviderForLocalConfig(pp.InParent.Addr()),
+					Alias:    pp.InParent.Alias,
+				})] = true
+			}
+		}
+	})
+
+	var ret []UnusedProviderConfig
+	for key, pc := range configured {
+		if !used[key] {
+			ret = append(ret, pc)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Addr.String() < ret[j].Addr.String()
+	})
+	return ret
+}
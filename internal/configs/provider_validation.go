@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 )
@@ -292,7 +293,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	for name, child := range cfg.Children {
 		mc := mod.ModuleCalls[name]
 		childNoProviderConfigRange := noProviderConfigRange
-		// if the module call has any of count, for_each or depends_on,
+		// if the module call has any of count, for_each, enabled or depends_on,
 		// providers are prohibited from being configured in this module, or
 		// any module beneath this module.
 		switch {
@@ -300,6 +301,8 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 			childNoProviderConfigRange = mc.Count.Range().Ptr()
 		case mc.ForEach != nil:
 			childNoProviderConfigRange = mc.ForEach.Range().Ptr()
+		case mc.Enabled != nil:
+			childNoProviderConfigRange = mc.Enabled.Range().Ptr()
 		case mc.DependsOn != nil:
 			if len(mc.DependsOn) > 0 {
 				childNoProviderConfigRange = mc.DependsOn[0].SourceRange().Ptr()
@@ -346,6 +349,9 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 		}
 
 		instanced[name] = pc.ForEach
+		if pc.Count != nil {
+			instanced[name] = pc.Count
+		}
 	}
 
 	if mod.ProviderRequirements != nil {
@@ -458,12 +464,20 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	}
 	checkImpliedProviderNames(mod.ManagedResources)
 	checkImpliedProviderNames(mod.DataResources)
+	checkImpliedProviderNames(mod.EphemeralResources)
 
 	// collect providers passed from the parent
 	if parentCall != nil {
 		for _, passed := range parentCall.Providers {
 			name := providerName(passed.InChild.Name, passed.InChild.Alias)
 			passedIn[name] = passed
+
+			if passed.AllInstances {
+				// The child refers to the instances with keys of its own, so
+				// it must be treated as instanced. There is no expression
+				// of its own to compare against the for_each of resources.
+				instanced[name] = hcl.StaticExpr(cty.DynamicVal, passed.InParent.NameRange)
+			}
 		}
 	}
 
@@ -490,6 +504,17 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 			}
 
 			instanceExpr := instanced[providerName(passed.InParent.Name, passed.InParent.Alias)]
+			if passed.AllInstances {
+				if instanceExpr == nil {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid module provider configuration",
+						Detail:   "All instances of a provider configuration can be passed using [*] only if the configuration uses count or for_each.",
+						Subject:  passed.InParent.NameRange.Ptr(),
+					})
+				}
+				continue
+			}
 			diags = diags.Extend(passed.InParent.InstanceValidation("module", instanceExpr != nil))
 			// We could theoretically check here if there are resources (ignoring data blocks) within this submodule graph.
 			// The foot-gun only exists in that scenario, but the complexity of differentiating at the moment is not worth it
@@ -519,6 +544,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	}
 	checkProviderKeys(mod.ManagedResources)
 	checkProviderKeys(mod.DataResources)
+	checkProviderKeys(mod.EphemeralResources)
 
 	// Verify that any module calls only refer to named providers, and that
 	// those providers will have a configuration at runtime. This way we can
@@ -583,15 +609,15 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	// there cannot be any configurations if no provider config is allowed
 	if len(configured) > 0 && noProviderConfigRange != nil {
 		// We report this from the perspective of the use of count, for_each,
-		// or depends_on rather than from inside the module, because the
-		// recipient of this message is more likely to be the author of the
+		// enabled or depends_on rather than from inside the module, because
+		// the recipient of this message is more likely to be the author of the
 		// calling module (trying to use an older module that hasn't been
 		// updated yet) than of the called module.
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Module is incompatible with count, for_each, and depends_on",
 			Detail: fmt.Sprintf(
-				"The module at %s is a legacy module which contains its own local provider configurations, and so calls to it may not use the count, for_each, or depends_on arguments.\n\nIf you also control the module %q, consider updating this module to instead expect provider configurations to be passed by its caller.",
+				"The module at %s is a legacy module which contains its own local provider configurations, and so calls to it may not use the count, for_each, enabled, or depends_on arguments.\n\nIf you also control the module %q, consider updating this module to instead expect provider configurations to be passed by its caller.",
 				cfg.Path, cfg.SourceAddr,
 			),
 			Subject: noProviderConfigRange,
@@ -14,6 +14,22 @@ import (
 type Removed struct {
 	From *addrs.RemoveEndpoint
 
+	// FromProvider is set instead of From when the block declares the removal
+	// of a provider configuration, like provider.aws.legacy.
+	FromProvider *RemovedProvider
+
+	// ToProvider is the provider configuration that takes over the objects
+	// in the state that are still bound to FromProvider, if any. It can only
+	// be set when FromProvider is set.
+	ToProvider *RemovedProvider
+
+	DeclRange hcl.Range
+}
+
+// RemovedProvider is the address of a provider configuration in a removed
+// block, relative to the module containing the block.
+type RemovedProvider struct {
+	Addr      addrs.LocalProviderConfig
 	DeclRange hcl.Range
 }
 
@@ -30,20 +46,101 @@ func decodeRemovedBlock(block *hcl.Block) (*Removed, hcl.Diagnostics) {
 		from, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
 		diags = append(diags, traversalDiags...)
 		if !traversalDiags.HasErrors() {
-			from, fromDiags := addrs.ParseRemoveEndpoint(from)
-			diags = append(diags, fromDiags.ToHCL()...)
-			removed.From = from
+			if from.RootName() == "provider" {
+				provider, providerDiags := decodeRemovedProviderAddr(from)
+				diags = append(diags, providerDiags...)
+				removed.FromProvider = provider
+			} else {
+				from, fromDiags := addrs.ParseRemoveEndpoint(from)
+				diags = append(diags, fromDiags.ToHCL()...)
+				removed.From = from
+			}
+		}
+	}
+
+	if attr, exists := content.Attributes["to"]; exists {
+		to, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, traversalDiags...)
+		switch {
+		case traversalDiags.HasErrors():
+			// Nothing more to check.
+		case removed.FromProvider == nil || to.RootName() != "provider":
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid removed block target",
+				Detail:   "The \"to\" argument can only be used when removing a provider configuration, to select the provider configuration that takes over the objects still bound to the removed one, like provider.aws.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		default:
+			provider, providerDiags := decodeRemovedProviderAddr(to)
+			diags = append(diags, providerDiags...)
+			if provider != nil && provider.Addr.LocalName != removed.FromProvider.Addr.LocalName {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid removed block target",
+					Detail:   "Objects can only be moved to another configuration of the same provider.",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				provider = nil
+			}
+			if provider != nil && provider.Addr.Alias == removed.FromProvider.Addr.Alias {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid removed block target",
+					Detail:   "The provider configuration that takes over the objects must not be the one being removed.",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				provider = nil
+			}
+			removed.ToProvider = provider
 		}
 	}
 
 	return removed, diags
 }
 
+// decodeRemovedProviderAddr decodes a provider configuration address like
+// provider.aws or provider.aws.legacy.
+func decodeRemovedProviderAddr(traversal hcl.Traversal) (*RemovedProvider, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	var names []string
+	for _, step := range traversal[1:] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			names = nil
+			break
+		}
+		names = append(names, attr.Name)
+	}
+	if len(names) < 1 || len(names) > 2 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider configuration address",
+			Detail:   "A provider configuration address must be either provider.<NAME> or provider.<NAME>.<ALIAS>.",
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+		return nil, diags
+	}
+
+	ret := &RemovedProvider{
+		Addr:      addrs.NewDefaultLocalProviderConfig(names[0]),
+		DeclRange: traversal.SourceRange(),
+	}
+	if len(names) == 2 {
+		ret.Addr.Alias = names[1]
+	}
+	return ret, diags
+}
+
 var removedBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
 			Name:     "from",
 			Required: true,
 		},
+		{
+			Name: "to",
+		},
 	},
 }
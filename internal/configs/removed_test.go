@@ -24,6 +24,10 @@ func TestRemovedBlock_decode(t *testing.T) {
 	foo_index_expr := hcltest.MockExprTraversalSrc("test_instance.foo[1]")
 	mod_boop_index_foo_expr := hcltest.MockExprTraversalSrc("module.boop[1].test_instance.foo")
 	data_foo_expr := hcltest.MockExprTraversalSrc("data.test_instance.foo")
+	provider_legacy_expr := hcltest.MockExprTraversalSrc("provider.test.legacy")
+	provider_default_expr := hcltest.MockExprTraversalSrc("provider.test")
+	provider_other_expr := hcltest.MockExprTraversalSrc("provider.other")
+	provider_invalid_expr := hcltest.MockExprTraversalSrc("provider.test.a.b")
 
 	tests := map[string]struct {
 		input *hcl.Block
@@ -135,6 +139,113 @@ func TestRemovedBlock_decode(t *testing.T) {
 			},
 			"Data source address is not allowed",
 		},
+		"provider": {
+			&hcl.Block{
+				Type: "removed",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: provider_legacy_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Removed{
+				FromProvider: mustRemovedProviderFromExpr(provider_legacy_expr),
+				DeclRange:    blockRange,
+			},
+			``,
+		},
+		"provider with target": {
+			&hcl.Block{
+				Type: "removed",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: provider_legacy_expr,
+						},
+						"to": {
+							Name: "to",
+							Expr: provider_default_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Removed{
+				FromProvider: mustRemovedProviderFromExpr(provider_legacy_expr),
+				ToProvider:   mustRemovedProviderFromExpr(provider_default_expr),
+				DeclRange:    blockRange,
+			},
+			``,
+		},
+		"error: invalid provider address": {
+			&hcl.Block{
+				Type: "removed",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: provider_invalid_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Removed{
+				DeclRange: blockRange,
+			},
+			"Invalid provider configuration address",
+		},
+		"error: target for resource": {
+			&hcl.Block{
+				Type: "removed",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: foo_expr,
+						},
+						"to": {
+							Name: "to",
+							Expr: provider_default_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Removed{
+				From:      mustRemoveEndpointFromExpr(foo_expr),
+				DeclRange: blockRange,
+			},
+			"Invalid removed block target",
+		},
+		"error: target for another provider": {
+			&hcl.Block{
+				Type: "removed",
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcl.Attributes{
+						"from": {
+							Name: "from",
+							Expr: provider_legacy_expr,
+						},
+						"to": {
+							Name: "to",
+							Expr: provider_other_expr,
+						},
+					},
+				}),
+				DefRange: blockRange,
+			},
+			&Removed{
+				FromProvider: mustRemovedProviderFromExpr(provider_legacy_expr),
+				DeclRange:    blockRange,
+			},
+			"Invalid removed block target",
+		},
 	}
 
 	for name, test := range tests {
@@ -196,3 +307,17 @@ func mustRemoveEndpointFromExpr(expr hcl.Expression) *addrs.RemoveEndpoint {
 
 	return ep
 }
+
+func mustRemovedProviderFromExpr(expr hcl.Expression) *RemovedProvider {
+	traversal, hcldiags := hcl.AbsTraversalForExpr(expr)
+	if hcldiags.HasErrors() {
+		panic(hcldiags.Errs())
+	}
+
+	provider, hcldiags := decodeRemovedProviderAddr(traversal)
+	if hcldiags.HasErrors() {
+		panic(hcldiags.Errs())
+	}
+
+	return provider
+}
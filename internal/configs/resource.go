@@ -20,7 +20,8 @@ import (
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
-// Resource represents a "resource" or "data" block in a module or file.
+// Resource represents a "resource", "data" or "ephemeral" block in a module or
+// file.
 type Resource struct {
 	Mode    addrs.ResourceMode
 	Name    string
@@ -29,6 +30,11 @@ type Resource struct {
 	Count   hcl.Expression
 	ForEach hcl.Expression
 
+	// Enabled is the "enabled" lifecycle argument, which declares either a
+	// single instance with no key or no instances at all. It's mutually
+	// exclusive with Count and ForEach.
+	Enabled hcl.Expression
+
 	ProviderConfigRef *ProviderConfigRef
 	Provider          addrs.Provider
 
@@ -73,8 +79,27 @@ type ManagedResource struct {
 	IgnoreChanges       []hcl.Traversal
 	IgnoreAllChanges    bool
 
+	// AdoptExisting allows OpenTofu to plan to import and update an existing
+	// object instead of creating a new one, when the resource instance has no
+	// object in the state.
+	AdoptExisting bool
+
+	// JSONAttributes and YAMLAttributes are the paths of string attributes
+	// holding JSON or YAML documents, for which OpenTofu ignores differences
+	// that don't change the meaning of the document, such as whitespace or
+	// the order of object keys.
+	JSONAttributes []hcl.Traversal
+	YAMLAttributes []hcl.Traversal
+
+	// OnDrift is the policy for changes to the remote object made outside
+	// of OpenTofu, as detected when refreshing it during planning. It is
+	// empty if the lifecycle block doesn't set it, which has the same effect
+	// as DriftReconcile.
+	OnDrift DriftPolicy
+
 	CreateBeforeDestroySet bool
 	PreventDestroySet      bool
+	AdoptExistingSet       bool
 }
 
 func (r *Resource) moduleUniqueKey() string {
@@ -198,6 +223,10 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 			lcContent, lcDiags := block.Body.Content(resourceLifecycleBlockSchema)
 			diags = append(diags, lcDiags...)
 
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				r.Enabled = attr.Expr
+			}
+
 			if attr, exists := lcContent.Attributes["create_before_destroy"]; exists {
 				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.CreateBeforeDestroy)
 				diags = append(diags, valDiags...)
@@ -210,6 +239,30 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 				r.Managed.PreventDestroySet = true
 			}
 
+			if attr, exists := lcContent.Attributes["adopt_existing"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.AdoptExisting)
+				diags = append(diags, valDiags...)
+				r.Managed.AdoptExistingSet = true
+			}
+
+			if attr, exists := lcContent.Attributes["json_attributes"]; exists {
+				traversals, travDiags := decodeAttributeTraversals(attr.Expr)
+				diags = append(diags, travDiags...)
+				r.Managed.JSONAttributes = traversals
+			}
+
+			if attr, exists := lcContent.Attributes["yaml_attributes"]; exists {
+				traversals, travDiags := decodeAttributeTraversals(attr.Expr)
+				diags = append(diags, travDiags...)
+				r.Managed.YAMLAttributes = traversals
+			}
+
+			if attr, exists := lcContent.Attributes["on_drift"]; exists {
+				policy, policyDiags := decodeDriftPolicy(attr)
+				diags = append(diags, policyDiags...)
+				r.Managed.OnDrift = policy
+			}
+
 			if attr, exists := lcContent.Attributes["replace_triggered_by"]; exists {
 				exprs, hclDiags := decodeReplaceTriggeredBy(attr.Expr)
 				diags = diags.Extend(hclDiags)
@@ -357,6 +410,8 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 		}
 	}
 
+	diags = append(diags, checkEnabledRepetition(r.Enabled, r.Count, r.ForEach)...)
+
 	// Now we can validate the connection block references if there are any destroy provisioners.
 	// TODO: should we eliminate standalone connection blocks?
 	if r.Managed.Connection != nil {
@@ -498,10 +553,17 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 			lcContent, lcDiags := block.Body.Content(resourceLifecycleBlockSchema)
 			diags = append(diags, lcDiags...)
 
-			// All of the attributes defined for resource lifecycle are for
-			// managed resources only, so we can emit a common error message
-			// for any given attributes that HCL accepted.
+			if attr, exists := lcContent.Attributes["enabled"]; exists && !nested {
+				r.Enabled = attr.Expr
+			}
+
+			// All of the other attributes defined for resource lifecycle are
+			// for managed resources only, so we can emit a common error
+			// message for any given attributes that HCL accepted.
 			for name, attr := range lcContent.Attributes {
+				if name == "enabled" {
+					continue
+				}
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Invalid data resource lifecycle argument",
@@ -544,9 +606,174 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 		}
 	}
 
+	diags = append(diags, checkEnabledRepetition(r.Enabled, r.Count, r.ForEach)...)
+
 	return r, diags
 }
 
+func decodeEphemeralBlock(block *hcl.Block) (*Resource, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	r := &Resource{
+		Mode:      addrs.EphemeralResourceMode,
+		Type:      block.Labels[0],
+		Name:      block.Labels[1],
+		DeclRange: block.DefRange,
+		TypeRange: block.LabelRanges[0],
+	}
+
+	content, remain, moreDiags := block.Body.PartialContent(ephemeralBlockSchema)
+	diags = append(diags, moreDiags...)
+	r.Config = remain
+
+	if !hclsyntax.ValidIdentifier(r.Type) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid ephemeral resource type name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+	if !hclsyntax.ValidIdentifier(r.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid ephemeral resource name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[1],
+		})
+	}
+
+	if attr, exists := content.Attributes["count"]; exists {
+		r.Count = attr.Expr
+	}
+
+	if attr, exists := content.Attributes["for_each"]; exists {
+		r.ForEach = attr.Expr
+		// Cannot have count and for_each on the same ephemeral block
+		if r.Count != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  `Invalid combination of "count" and "for_each"`,
+				Detail:   `The "count" and "for_each" meta-arguments are mutually-exclusive, only one should be used to be explicit about the number of resources to be created.`,
+				Subject:  &attr.NameRange,
+			})
+		}
+	}
+
+	if attr, exists := content.Attributes["provider"]; exists {
+		var providerDiags hcl.Diagnostics
+		r.ProviderConfigRef, providerDiags = decodeProviderConfigRef(attr.Expr, "provider")
+		diags = append(diags, providerDiags...)
+	}
+
+	if attr, exists := content.Attributes["depends_on"]; exists {
+		deps, depsDiags := decodeDependsOn(attr)
+		diags = append(diags, depsDiags...)
+		r.DependsOn = append(r.DependsOn, deps...)
+	}
+
+	var seenEscapeBlock *hcl.Block
+	for _, block := range content.Blocks {
+		switch block.Type {
+
+		case "_":
+			if seenEscapeBlock != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate escaping block",
+					Detail: fmt.Sprintf(
+						"The special block type \"_\" can be used to force particular arguments to be interpreted as resource-type-specific rather than as meta-arguments, but each ephemeral block can have only one such block. The first escaping block was at %s.",
+						seenEscapeBlock.DefRange,
+					),
+					Subject: &block.DefRange,
+				})
+				continue
+			}
+			seenEscapeBlock = block
+
+			// When there's an escaping block its content merges with the
+			// existing config we extracted earlier, so later decoding
+			// will see a blend of both.
+			r.Config = hcl.MergeBodies([]hcl.Body{r.Config, block.Body})
+
+		case "lifecycle":
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid lifecycle block",
+				Detail:   "Ephemeral resources do not support \"lifecycle\" blocks, because OpenTofu never plans changes to them.",
+				Subject:  block.DefRange.Ptr(),
+			})
+
+		default:
+			// Any other block types are ones we're reserving for future use,
+			// but don't have any defined meaning today.
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reserved block type name in ephemeral block",
+				Detail:   fmt.Sprintf("The block type name %q is reserved for use by OpenTofu in a future version.", block.Type),
+				Subject:  block.TypeRange.Ptr(),
+			})
+		}
+	}
+
+	return r, diags
+}
+
+// decodeAttributeTraversals decodes a list of relative traversals referring
+// to attributes of the resource itself, such as:
+//
+//	json_attributes = [policy, statement[0].document]
+func decodeAttributeTraversals(expr hcl.Expression) ([]hcl.Traversal, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(expr)
+
+	var ret []hcl.Traversal
+	for _, expr := range exprs {
+		traversal, travDiags := hcl.RelTraversalForExpr(expr)
+		diags = append(diags, travDiags...)
+		if len(traversal) != 0 {
+			ret = append(ret, traversal)
+		}
+	}
+	return ret, diags
+}
+
+// DriftPolicy is the value of the on_drift lifecycle argument of a managed
+// resource.
+type DriftPolicy string
+
+const (
+	// DriftReconcile plans changes to return the remote object to the
+	// configured state, which is the default behavior.
+	DriftReconcile DriftPolicy = "reconcile"
+
+	// DriftIgnore reports the changes made outside of OpenTofu but keeps
+	// planning against the object as it was after the last apply, so that
+	// the changes are not reverted.
+	DriftIgnore DriftPolicy = "ignore"
+
+	// DriftFail makes planning fail if the remote object was changed outside
+	// of OpenTofu.
+	DriftFail DriftPolicy = "fail"
+)
+
+func decodeDriftPolicy(attr *hcl.Attribute) (DriftPolicy, hcl.Diagnostics) {
+	var raw string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	switch policy := DriftPolicy(raw); policy {
+	case DriftReconcile, DriftIgnore, DriftFail:
+		return policy, diags
+	default:
+		return "", append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid on_drift policy",
+			Detail:   `The "on_drift" argument must be one of "reconcile", "ignore", or "fail".`,
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+}
+
 // decodeReplaceTriggeredBy decodes and does basic validation of the
 // replace_triggered_by expressions, ensuring they only contains references to
 // a single resource, and the only extra variables are count.index or each.key.
@@ -811,7 +1038,7 @@ func (r *ProviderConfigRef) InstanceValidation(blockType string, isInstanced boo
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  summary,
-				Detail:   "An instance key can be specified only for a provider configuration which has an alias and uses for_each.",
+				Detail:   "An instance key can be specified only for a provider configuration which has an alias and uses count or for_each.",
 				Subject:  r.KeyExpression.Range().Ptr(),
 			})
 		}
@@ -819,21 +1046,44 @@ func (r *ProviderConfigRef) InstanceValidation(blockType string, isInstanced boo
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  summary,
-				Detail:   "An instance key can be specified only for a provider configuration that uses for_each.",
+				Detail:   "An instance key can be specified only for a provider configuration that uses count or for_each.",
 				Subject:  r.KeyExpression.Range().Ptr(),
 			})
 		}
 	} else if isInstanced {
+		detail := "A reference to a provider configuration which uses count or for_each requires an instance key."
+		if blockType == "module" {
+			detail += " To pass all of the instances into the child module, add [*] after the provider configuration address."
+		}
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  summary,
-			Detail:   "A reference to a provider configuration which uses for_each requires an instance key. Passing a collection of provider instances into a child module is not allowed.",
+			Detail:   detail,
 			Subject:  r.NameRange.Ptr(),
 		})
 	}
 	return diags
 }
 
+// checkEnabledRepetition returns an error if the "enabled" lifecycle
+// argument of a resource or module call is combined with "count" or
+// "for_each", since each of them decides how many instances there are.
+func checkEnabledRepetition(enabled, count, forEach hcl.Expression) hcl.Diagnostics {
+	if enabled == nil || (count == nil && forEach == nil) {
+		return nil
+	}
+	name := "count"
+	if count == nil {
+		name = "for_each"
+	}
+	return hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf(`Invalid combination of "enabled" and %q`, name),
+		Detail:   fmt.Sprintf(`The "enabled" lifecycle argument and the %q meta-argument are mutually-exclusive. Use "enabled" for an object that either exists without an instance key or doesn't exist at all, or %q to declare instances with keys.`, name, name),
+		Subject:  enabled.Range().Ptr(),
+	}}
+}
+
 var commonResourceAttributes = []hcl.AttributeSchema{
 	{
 		Name: "count",
@@ -874,24 +1124,48 @@ var dataBlockSchema = &hcl.BodySchema{
 	},
 }
 
+var ephemeralBlockSchema = &hcl.BodySchema{
+	Attributes: commonResourceAttributes,
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "lifecycle"}, // reserved for future use
+		{Type: "locals"},    // reserved for future use
+		{Type: "_"},         // meta-argument escaping block
+	},
+}
+
 var resourceLifecycleBlockSchema = &hcl.BodySchema{
 	// We tell HCL that these elements are all valid for both "resource"
 	// and "data" lifecycle blocks, but the rules are actually more restrictive
 	// than that. We deal with that after decoding so that we can return
 	// more specific error messages than HCL would typically return itself.
 	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "enabled",
+		},
 		{
 			Name: "create_before_destroy",
 		},
 		{
 			Name: "prevent_destroy",
 		},
+		{
+			Name: "adopt_existing",
+		},
 		{
 			Name: "ignore_changes",
 		},
 		{
 			Name: "replace_triggered_by",
 		},
+		{
+			Name: "json_attributes",
+		},
+		{
+			Name: "yaml_attributes",
+		},
+		{
+			Name: "on_drift",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
@@ -83,6 +83,11 @@ func NewStaticEvaluator(mod *Module, call StaticModuleCall) *StaticEvaluator {
 	}
 }
 
+// Workspace returns the name of the workspace the module is evaluated in.
+func (s *StaticEvaluator) Workspace() string {
+	return s.call.workspace
+}
+
 func (s *StaticEvaluator) scope(ident StaticIdentifier) *lang.Scope {
 	return newStaticScope(s, ident)
 }
@@ -106,6 +111,14 @@ func (s StaticEvaluator) DecodeExpression(expr hcl.Expression, ident StaticIdent
 			Subject:  expr.Range().Ptr(),
 		})
 	}
+	if marks.Contains(srcVal, marks.Ephemeral) {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Ephemeral value not allowed",
+			Detail:   fmt.Sprintf("Ephemeral values, or values derived from ephemeral values, cannot be used as %s.", ident.String()),
+			Subject:  expr.Range().Ptr(),
+		})
+	}
 
 	return diags.Extend(gohcl.DecodeValue(srcVal, expr.StartRange(), expr.Range(), val))
 }
@@ -373,6 +373,38 @@ terraform {
 	}
 }`,
 		diags: []string{`eval.tf:4,11-25: Module output not supported in static context; Unable to use module.foo.bar in static context, which is required by backend.badeval`},
+	}, {
+		ident: "local_chain",
+		body: `
+locals {
+	env    = "prod"
+	prefix = "state/${local.env}"
+	key    = "${local.prefix}/${lower("NETWORK")}.tfstate"
+}
+
+terraform {
+	backend "local_chain" {
+		thing = local.key
+	}
+}`,
+	}, {
+		ident: "local_dynamic",
+		body: `
+locals {
+	id  = aws_instance.foo.id
+	key = "${local.id}.tfstate"
+}
+
+terraform {
+	backend "local_dynamic" {
+		thing = local.key
+	}
+}`,
+		diags: []string{
+			`eval.tf:3,8-24: Dynamic value in static context; Unable to use aws_instance.foo in static context, which is required by local.id, which is required by local.key, which is required by backend.local_dynamic`,
+			`eval.tf:4,2-29: Unable to compute static value; local.key depends on local.id which is not available`,
+			`eval.tf:8,2-25: Unable to compute static value; backend.local_dynamic depends on local.key which is not available`,
+		},
 	}, {
 		ident: "sensitive",
 		body: `
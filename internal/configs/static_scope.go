@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -73,10 +74,25 @@ func (s staticScopeData) enhanceDiagnostics(ident StaticIdentifier, diags tfdiag
 	return diags
 }
 
+// requiredBy describes the chain of identifiers that led to the evaluation of
+// the top of the stack, such as "local.b, which is required by local.a, which
+// is required by backend.s3", so that the user can see why a value that is
+// not obviously related to a static context is required to be static.
+func (s staticScopeData) requiredBy() string {
+	var buf strings.Builder
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if i != len(s.stack)-1 {
+			buf.WriteString(", which is required by ")
+		}
+		buf.WriteString(s.stack[i].String())
+	}
+	return buf.String()
+}
+
 // Early check to only allow references we expect in a static context
 func (s staticScopeData) StaticValidateReferences(refs []*addrs.Reference, _ addrs.Referenceable, _ addrs.Referenceable) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
-	top := s.stack[len(s.stack)-1]
+	requiredBy := s.requiredBy()
 	for _, ref := range refs {
 		switch subject := ref.Subject.(type) {
 		case addrs.LocalValue:
@@ -91,21 +107,21 @@ func (s staticScopeData) StaticValidateReferences(refs []*addrs.Reference, _ add
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Module output not supported in static context",
-				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), top.String()),
+				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), requiredBy),
 				Subject:  ref.SourceRange.ToHCL().Ptr(),
 			})
 		case addrs.ProviderFunction:
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Provider function in static context",
-				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), top.String()),
+				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), requiredBy),
 				Subject:  ref.SourceRange.ToHCL().Ptr(),
 			})
 		default:
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Dynamic value in static context",
-				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), top.String()),
+				Detail:   fmt.Sprintf("Unable to use %s in static context, which is required by %s", subject.String(), requiredBy),
 				Subject:  ref.SourceRange.ToHCL().Ptr(),
 			})
 		}
@@ -215,6 +231,14 @@ func (s staticScopeData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfdiags.
 		workspaceName := s.eval.call.workspace
 		return cty.StringVal(workspaceName), diags
 
+	case "workspace_tags":
+		// Only the root module knows which workspaces are declared, and
+		// we don't have access to it when evaluating a child module.
+		if !s.eval.call.addr.IsRoot() {
+			return cty.UnknownVal(cty.Set(cty.String)), diags
+		}
+		return s.eval.cfg.Workspaces.TagsValue(s.eval.call.workspace), diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -231,7 +255,7 @@ func (s staticScopeData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfdiags.
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "terraform" attribute`,
-			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attribute is terraform.workspace, the name of the currently-selected workspace.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attributes are terraform.workspace, the name of the currently-selected workspace, and terraform.workspace_tags, the tags declared for it.`, addr.Name),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -288,6 +312,9 @@ func (s staticScopeData) GetInputVariable(ident addrs.InputVariable, rng tfdiags
 	if defaultVal != cty.NilVal {
 		var err error
 		defaultVal, err = convert.Convert(defaultVal, convertTy)
+		if err == nil {
+			defaultVal, err = variable.TypeRefinements.Apply(defaultVal)
+		}
 		if err != nil {
 			// We shouldn't get here because the default value's convertability to
 			// the type constraint is checked during config decoding, but we'll
@@ -307,9 +334,7 @@ func (s staticScopeData) GetInputVariable(ident addrs.InputVariable, rng tfdiags
 
 	// Some type constraints contain default values to use when attributes are null,
 	// so we need to resolve those before we proceed further.
-	if variable.TypeDefaults != nil && !val.IsNull() {
-		val = variable.TypeDefaults.Apply(val)
-	}
+	val = variable.ApplyTypeDefaults(val)
 
 	// The module author specifies what type of value they are expecting. The
 	// caller is allowed to provide any value that can convert to the given
@@ -317,6 +342,9 @@ func (s staticScopeData) GetInputVariable(ident addrs.InputVariable, rng tfdiags
 	// value.
 	var err error
 	val, err = convert.Convert(val, convertTy)
+	if err == nil {
+		val, err = variable.TypeRefinements.Apply(val)
+	}
 	if err != nil {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -362,6 +390,9 @@ func (s staticScopeData) GetInputVariable(ident addrs.InputVariable, rng tfdiags
 		// that their sensitivity can propagate to derived expressions.
 		val = val.Mark(marks.Sensitive)
 	}
+	if variable.Ephemeral {
+		val = val.Mark(marks.Ephemeral)
+	}
 
 	// TODO: We should check the variable validations here too, since module
 	// authors expect that any value that doesn't meet the validation requirements
@@ -71,6 +71,11 @@ type TestFile struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
+	// StubModules is a list of modules to be replaced with stubs, whose
+	// outputs are computed from the module's input variables.
+	// Underlying modules shouldn't be called.
+	StubModules []*StubModule
+
 	// MockProviders is a map of providers that should be mocked. It is merged
 	// with Providers map to use later when instantiating provider instance.
 	MockProviders map[string]*MockProvider
@@ -87,6 +92,7 @@ func (file *TestFile) Validate() tfdiags.Diagnostics {
 	// declared globally in a file with the same target address so we want to ensure there's no such cases.
 	diags = diags.Append(checkForDuplicatedOverrideResources(file.OverrideResources))
 	diags = diags.Append(checkForDuplicatedOverrideModules(file.OverrideModules))
+	diags = diags.Append(checkForDuplicatedStubModules(file.StubModules, file.OverrideModules))
 
 	return diags
 }
@@ -184,6 +190,11 @@ type TestRun struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
+	// StubModules is a list of modules to be replaced with stubs, whose
+	// outputs are computed from the module's input variables.
+	// Underlying modules shouldn't be called.
+	StubModules []*StubModule
+
 	NameDeclRange      hcl.Range
 	VariablesDeclRange hcl.Range
 	DeclRange          hcl.Range
@@ -223,6 +234,7 @@ func (run *TestRun) Validate() tfdiags.Diagnostics {
 	// inside a single run block with the same target address so we want to ensure there's no such cases.
 	diags = diags.Append(checkForDuplicatedOverrideResources(run.OverrideResources))
 	diags = diags.Append(checkForDuplicatedOverrideModules(run.OverrideModules))
+	diags = diags.Append(checkForDuplicatedStubModules(run.StubModules, run.OverrideModules))
 
 	return diags
 }
@@ -302,6 +314,25 @@ type OverrideModule struct {
 	Outputs map[string]cty.Value
 }
 
+const blockNameStubModule = "stub_module"
+
+// StubModule contains information about a module call to be replaced with
+// a stub. Unlike OverrideModule, the outputs of a stub are expressions that
+// are evaluated in the scope of the stubbed module, so they can be computed
+// from the input variables passed by the calling module.
+type StubModule struct {
+	// Target references module call to stub.
+	Target       hcl.Traversal
+	TargetParsed addrs.Module
+
+	// Outputs are the expressions to use instead of the expressions of
+	// the output values of the module. They can refer only to the input
+	// variables of the module.
+	Outputs map[string]hcl.Expression
+
+	DeclRange hcl.Range
+}
+
 const blockNameMockProvider = "mock_provider"
 
 // MockProvider represents mocked provider block. It partially matches
@@ -394,6 +425,16 @@ type MockResource struct {
 	Mode     addrs.ResourceMode
 	Type     string
 	Defaults map[string]cty.Value
+
+	// DefaultExprs are the default values that refer to the mocked resource
+	// itself using "self", like `arn = "arn:aws:s3:::${self.bucket}"`. They
+	// are evaluated against the configuration of each resource instance
+	// whenever the mock provider plans or reads it.
+	DefaultExprs map[string]hcl.Expression
+
+	// Generators generate the values of computed attributes that must be
+	// different for each object, such as identifiers.
+	Generators []*MockGenerator
 }
 
 func (r MockResource) getBlockName() string {
@@ -450,7 +491,7 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 			}
 
 		case "provider":
-			provider, providerDiags := decodeProviderBlock(block)
+			provider, providerDiags := decodeProviderBlock(block, false)
 			diags = append(diags, providerDiags...)
 			if provider != nil {
 				tf.Providers[provider.moduleUniqueKey()] = provider
@@ -470,6 +511,13 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 				tf.OverrideModules = append(tf.OverrideModules, overrideMod)
 			}
 
+		case blockNameStubModule:
+			stubMod, stubModDiags := decodeStubModuleBlock(block)
+			diags = append(diags, stubModDiags...)
+			if !stubModDiags.HasErrors() {
+				tf.StubModules = append(tf.StubModules, stubMod)
+			}
+
 		case blockNameMockProvider:
 			mockProvider, mockProviderDiags := decodeMockProviderBlock(block)
 			diags = append(diags, mockProviderDiags...)
@@ -587,6 +635,13 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 			if !overrideModDiags.HasErrors() {
 				r.OverrideModules = append(r.OverrideModules, overrideMod)
 			}
+
+		case blockNameStubModule:
+			stubMod, stubModDiags := decodeStubModuleBlock(block)
+			diags = append(diags, stubModDiags...)
+			if !stubModDiags.HasErrors() {
+				r.StubModules = append(r.StubModules, stubMod)
+			}
 		}
 	}
 
@@ -847,29 +902,31 @@ func decodeOverrideResourceBlock(block *hcl.Block) (*OverrideResource, hcl.Diagn
 	return res, diags
 }
 
-func decodeOverrideModuleBlock(block *hcl.Block) (*OverrideModule, hcl.Diagnostics) {
-	parseTarget := func(attr *hcl.Attribute) (hcl.Traversal, addrs.Module, hcl.Diagnostics) {
-		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
-		diags := traversalDiags
-		if traversalDiags.HasErrors() {
-			return nil, nil, diags
-		}
-
-		target, targetDiags := addrs.ParseModule(traversal)
-		diags = append(diags, targetDiags.ToHCL()...)
-		if targetDiags.HasErrors() {
-			return nil, nil, diags
-		}
+// decodeModuleTargetAttr decodes the target attribute of an override_module
+// or stub_module block.
+func decodeModuleTargetAttr(attr *hcl.Attribute) (hcl.Traversal, addrs.Module, hcl.Diagnostics) {
+	traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+	diags := traversalDiags
+	if traversalDiags.HasErrors() {
+		return nil, nil, diags
+	}
 
-		return traversal, target, diags
+	target, targetDiags := addrs.ParseModule(traversal)
+	diags = append(diags, targetDiags.ToHCL()...)
+	if targetDiags.HasErrors() {
+		return nil, nil, diags
 	}
 
+	return traversal, target, diags
+}
+
+func decodeOverrideModuleBlock(block *hcl.Block) (*OverrideModule, hcl.Diagnostics) {
 	mod := &OverrideModule{}
 
 	content, diags := block.Body.Content(overrideModuleBlockSchema)
 
 	if attr, exists := content.Attributes["target"]; exists {
-		traversal, target, moreDiags := parseTarget(attr)
+		traversal, target, moreDiags := decodeModuleTargetAttr(attr)
 		mod.Target, mod.TargetParsed = traversal, target
 		diags = append(diags, moreDiags...)
 	}
@@ -882,6 +939,78 @@ func decodeOverrideModuleBlock(block *hcl.Block) (*OverrideModule, hcl.Diagnosti
 	return mod, diags
 }
 
+// outputVariables returns the references to input variables in the outputs of
+// the stub.
+func (m *StubModule) outputVariables() []hcl.Traversal {
+	var ret []hcl.Traversal
+	for _, expr := range m.Outputs {
+		for _, traversal := range expr.Variables() {
+			if traversal.RootName() == "var" && len(traversal) > 1 {
+				ret = append(ret, traversal)
+			}
+		}
+	}
+	return ret
+}
+
+func decodeStubModuleBlock(block *hcl.Block) (*StubModule, hcl.Diagnostics) {
+	mod := &StubModule{
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(stubModuleBlockSchema)
+
+	if attr, exists := content.Attributes["target"]; exists {
+		traversal, target, moreDiags := decodeModuleTargetAttr(attr)
+		mod.Target, mod.TargetParsed = traversal, target
+		diags = append(diags, moreDiags...)
+	}
+
+	if attr, exists := content.Attributes["outputs"]; exists {
+		outputs, moreDiags := decodeStubModuleOutputs(attr)
+		mod.Outputs, diags = outputs, append(diags, moreDiags...)
+	}
+
+	return mod, diags
+}
+
+// decodeStubModuleOutputs decodes the outputs attribute of a stub_module
+// block, which must be an object constructor whose values refer only to
+// input variables.
+func decodeStubModuleOutputs(attr *hcl.Attribute) (map[string]hcl.Expression, hcl.Diagnostics) {
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	outputs := make(map[string]hcl.Expression, len(pairs))
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			keyDiags := gohcl.DecodeExpression(pair.Key, nil, &name)
+			diags = append(diags, keyDiags...)
+			if keyDiags.HasErrors() {
+				continue
+			}
+		}
+
+		for _, traversal := range pair.Value.Variables() {
+			if traversal.RootName() != "var" {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid reference in `stub_module` output",
+					Detail:   "The outputs of a `stub_module` block can refer only to the input variables of the stubbed module, using var.NAME.",
+					Subject:  traversal.SourceRange().Ptr(),
+				})
+			}
+		}
+
+		outputs[name] = pair.Value
+	}
+
+	return outputs, diags
+}
+
 // Some code of decodeMockProviderBlock function was copied from decodeProviderBlock.
 func decodeMockProviderBlock(block *hcl.Block) (*MockProvider, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
@@ -965,13 +1094,75 @@ func decodeMockResourceBlock(block *hcl.Block) (*MockResource, hcl.Diagnostics)
 	content, diags := block.Body.Content(mockResourceBlockSchema)
 
 	if attr, exists := content.Attributes["defaults"]; exists {
-		v, moreDiags := parseObjectAttrWithNoVariables(attr)
-		res.Defaults, diags = v, append(diags, moreDiags...)
+		var moreDiags hcl.Diagnostics
+		res.Defaults, res.DefaultExprs, moreDiags = decodeMockResourceDefaults(attr)
+		diags = append(diags, moreDiags...)
+	}
+
+	for _, block := range content.Blocks {
+		gen, genDiags := decodeMockGeneratorBlock(block)
+		diags = append(diags, genDiags...)
+		if !genDiags.HasErrors() {
+			res.Generators = append(res.Generators, gen)
+		}
 	}
+	diags = append(diags, checkForDuplicatedMockGenerators(res)...)
 
 	return res, diags
 }
 
+// decodeMockResourceDefaults decodes the defaults attribute of a mock_resource
+// or mock_data block. The values that refer to the mocked resource as "self"
+// are returned as expressions, to be evaluated against each resource
+// instance, and all other values are evaluated here.
+func decodeMockResourceDefaults(attr *hcl.Attribute) (map[string]cty.Value, map[string]hcl.Expression, hcl.Diagnostics) {
+	if len(attr.Expr.Variables()) == 0 {
+		values, diags := parseObjectAttrWithNoVariables(attr)
+		return values, nil, diags
+	}
+
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	values := make(map[string]cty.Value)
+	exprs := make(map[string]hcl.Expression)
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			keyDiags := gohcl.DecodeExpression(pair.Key, nil, &name)
+			diags = append(diags, keyDiags...)
+			if keyDiags.HasErrors() {
+				continue
+			}
+		}
+
+		traversals := pair.Value.Variables()
+		if len(traversals) == 0 {
+			value, valueDiags := pair.Value.Value(nil)
+			diags = append(diags, valueDiags...)
+			values[name] = value
+			continue
+		}
+
+		for _, traversal := range traversals {
+			if traversal.RootName() != "self" {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid reference in mock resource defaults",
+					Detail:   "The defaults of a mock resource can refer only to the mocked resource itself, using self.NAME.",
+					Subject:  traversal.SourceRange().Ptr(),
+				})
+			}
+		}
+
+		exprs[name] = pair.Value
+	}
+
+	return values, exprs, diags
+}
+
 func parseObjectAttrWithNoVariables(attr *hcl.Attribute) (map[string]cty.Value, hcl.Diagnostics) {
 	attrVal, valDiags := attr.Expr.Value(nil)
 	diags := valDiags
@@ -1037,6 +1228,45 @@ func checkForDuplicatedOverrideModules(modules []*OverrideModule) hcl.Diagnostic
 	return diags
 }
 
+// checkForDuplicatedStubModules checks that no two `stub_module` blocks, and
+// no `stub_module` and `override_module` blocks, have the same target.
+func checkForDuplicatedStubModules(stubs []*StubModule, overrides []*OverrideModule) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	overridden := make(map[string]struct{}, len(overrides))
+	for _, mod := range overrides {
+		overridden[mod.TargetParsed.String()] = struct{}{}
+	}
+
+	stubbed := make(map[string]struct{}, len(stubs))
+	for _, mod := range stubs {
+		k := mod.TargetParsed.String()
+
+		if _, ok := stubbed[k]; ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicated `stub_module` block",
+				Detail:   fmt.Sprintf("It is not allowed to have multiple `stub_module` blocks with the same target: `%v`.", mod.TargetParsed),
+				Subject:  mod.Target.SourceRange().Ptr(),
+			})
+			continue
+		}
+		if _, ok := overridden[k]; ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting `stub_module` block",
+				Detail:   fmt.Sprintf("The module `%v` is already overridden by an `override_module` block, so it cannot also be replaced with a stub.", mod.TargetParsed),
+				Subject:  mod.Target.SourceRange().Ptr(),
+			})
+			continue
+		}
+
+		stubbed[k] = struct{}{}
+	}
+
+	return diags
+}
+
 // testFileSchema defines the structure of test file configuration for tofu tests.
 var testFileSchema = &hcl.BodySchema{
 	Blocks: []hcl.BlockHeaderSchema{
@@ -1063,6 +1293,9 @@ var testFileSchema = &hcl.BodySchema{
 		{
 			Type: blockNameOverrideModule,
 		},
+		{
+			Type: blockNameStubModule,
+		},
 		{
 			Type:       blockNameMockProvider,
 			LabelNames: []string{"name"},
@@ -1107,6 +1340,9 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{
 			Type: blockNameOverrideModule,
 		},
+		{
+			Type: blockNameStubModule,
+		},
 	},
 }
 
@@ -1162,6 +1398,19 @@ var overrideModuleBlockSchema = &hcl.BodySchema{
 	},
 }
 
+var stubModuleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "target",
+			Required: true,
+		},
+		{
+			Name:     "outputs",
+			Required: false,
+		},
+	},
+}
+
 var mockProviderBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{
@@ -1193,4 +1442,10 @@ var mockResourceBlockSchema = &hcl.BodySchema{
 			Name: "defaults",
 		},
 	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       blockNameMockGenerator,
+			LabelNames: []string{"attribute"},
+		},
+	},
 }
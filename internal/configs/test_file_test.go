@@ -6,6 +6,7 @@
 package configs
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -97,3 +98,310 @@ func parseTraversal(t *testing.T, addr string) hcl.Traversal {
 	}
 	return traversal
 }
+
+func TestTestFile_stubModule(t *testing.T) {
+	tcs := map[string]struct {
+		source string
+		want   []string
+	}{
+		"valid": {
+			source: `
+stub_module {
+  target  = module.child
+  outputs = {
+    id   = "stub-${var.name}"
+    size = 3
+  }
+}
+
+run "test" {
+  stub_module {
+    target = module.other
+  }
+}
+`,
+		},
+		"non-variable reference": {
+			source: `
+stub_module {
+  target  = module.child
+  outputs = {
+    id = local.id
+  }
+}
+`,
+			want: []string{"Invalid reference in `stub_module` output"},
+		},
+		"duplicated": {
+			source: `
+stub_module {
+  target = module.child
+}
+
+stub_module {
+  target = module.child
+}
+`,
+			want: []string{"Duplicated `stub_module` block"},
+		},
+		"conflicting override": {
+			source: `
+override_module {
+  target = module.child
+}
+
+stub_module {
+  target = module.child
+}
+`,
+			want: []string{"Conflicting `stub_module` block"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tftest.hcl": tc.source,
+			})
+
+			file, hclDiags := parser.LoadTestFile("main.tftest.hcl")
+			var got []string
+			for _, diag := range hclDiags {
+				got = append(got, diag.Summary)
+			}
+			if !hclDiags.HasErrors() {
+				for _, diag := range file.Validate() {
+					got = append(got, diag.Description().Summary)
+				}
+				for _, run := range file.Runs {
+					for _, diag := range run.Validate() {
+						got = append(got, diag.Description().Summary)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTestFile_mockResourceDefaults(t *testing.T) {
+	tcs := map[string]struct {
+		source    string
+		wantExprs []string
+		want      []string
+	}{
+		"constants": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_s3_bucket" {
+    defaults = {
+      arn = "arn:aws:s3:::bucket"
+    }
+  }
+}
+`,
+		},
+		"self references": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_s3_bucket" {
+    defaults = {
+      arn    = "arn:aws:s3:::${self.bucket}"
+      domain = lower("${self.bucket}.s3.amazonaws.com")
+      region = "us-east-1"
+    }
+  }
+}
+`,
+			wantExprs: []string{"arn", "domain"},
+		},
+		"other references": {
+			source: `
+mock_provider "aws" {
+  mock_data "aws_s3_bucket" {
+    defaults = {
+      arn = "arn:aws:s3:::${var.bucket}"
+    }
+  }
+}
+`,
+			want: []string{"Invalid reference in mock resource defaults"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tftest.hcl": tc.source,
+			})
+
+			file, hclDiags := parser.LoadTestFile("main.tftest.hcl")
+			var got []string
+			for _, diag := range hclDiags {
+				got = append(got, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			var gotExprs []string
+			for _, provider := range file.MockProviders {
+				for _, res := range provider.MockResources {
+					for name := range res.DefaultExprs {
+						gotExprs = append(gotExprs, name)
+					}
+					for name := range res.DefaultExprs {
+						if _, ok := res.Defaults[name]; ok {
+							t.Errorf("%q is both a value and an expression", name)
+						}
+					}
+				}
+			}
+			sort.Strings(gotExprs)
+			if diff := cmp.Diff(tc.wantExprs, gotExprs); diff != "" {
+				t.Errorf("wrong expressions\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTestFile_mockResourceGenerators(t *testing.T) {
+	tcs := map[string]struct {
+		source string
+		want   []string
+	}{
+		"valid": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_subnet" {
+    defaults = {
+      arn = "arn:aws:ec2:::subnet"
+    }
+
+    generate "id" {
+      type = "uuid"
+    }
+
+    generate "cidr_block" {
+      type    = "cidr"
+      pool    = "10.0.0.0/16"
+      newbits = 8
+    }
+
+    generate "index" {
+      type  = "sequence"
+      start = 100
+      step  = 10
+    }
+
+    generate "name" {
+      type    = "regex"
+      pattern = "^subnet-[a-f0-9]{8}$"
+    }
+  }
+}
+`,
+		},
+		"unknown type": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_subnet" {
+    generate "id" {
+      type = "random"
+    }
+  }
+}
+`,
+			want: []string{"Invalid generator type"},
+		},
+		"unsupported and missing arguments": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_subnet" {
+    generate "cidr_block" {
+      type  = "cidr"
+      start = 1
+    }
+  }
+}
+`,
+			want: []string{"Unsupported generator argument", "Missing generator argument", "Missing generator argument"},
+		},
+		"invalid arguments": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_subnet" {
+    generate "cidr_block" {
+      type    = "cidr"
+      pool    = "10.0.0.0/30"
+      newbits = 8
+    }
+
+    generate "name" {
+      type    = "regex"
+      pattern = "[a-z"
+    }
+  }
+}
+`,
+			want: []string{"Invalid CIDR newbits", "Invalid generator pattern"},
+		},
+		"conflicts": {
+			source: `
+mock_provider "aws" {
+  mock_resource "aws_subnet" {
+    defaults = {
+      id = "subnet"
+    }
+
+    generate "id" {
+      type = "uuid"
+    }
+
+    generate "index" {
+      type = "sequence"
+    }
+
+    generate "index" {
+      type = "sequence"
+    }
+  }
+}
+`,
+			want: []string{"Conflicting mock resource default", "Duplicated `generate` block"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tftest.hcl": tc.source,
+			})
+
+			file, hclDiags := parser.LoadTestFile("main.tftest.hcl")
+			var got []string
+			for _, diag := range hclDiags {
+				got = append(got, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+			if len(tc.want) > 0 {
+				return
+			}
+
+			res := file.MockProviders["aws"].MockResources[0]
+			if got, want := len(res.Generators), 4; got != want {
+				t.Fatalf("wrong number of generators %d; want %d", got, want)
+			}
+			for i := int64(0); i < 3; i++ {
+				if got := res.Generators[2].Next(); got != i {
+					t.Errorf("wrong index %d; want %d", got, i)
+				}
+			}
+		})
+	}
+}
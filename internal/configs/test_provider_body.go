@@ -79,6 +79,15 @@ func (c testProviderBody) MissingItemRange() hcl.Range {
 }
 
 func (c testProviderBody) getLiteralAttr(attr *hcl.Attribute) (*hcl.Attribute, hcl.Diagnostics) {
+	// The instance of a provider configuration with for_each or count is only
+	// known when OpenTofu configures it, so we leave the attributes that refer
+	// to it to be evaluated then.
+	for _, traversal := range attr.Expr.Variables() {
+		if name := traversal.RootName(); name == "each" || name == "count" {
+			return attr, nil
+		}
+	}
+
 	val, diags := attr.Expr.Value(c.evalCtx)
 	if diags.HasErrors() {
 		return nil, diags
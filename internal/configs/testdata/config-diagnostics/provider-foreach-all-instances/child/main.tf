@@ -0,0 +1,13 @@
+terraform {
+  required_providers {
+    null = {
+      source                = "hashicorp/null"
+      configuration_aliases = [null.multi]
+    }
+  }
+}
+
+resource "null_resource" "x" {
+  for_each = toset(["a", "b"])
+  provider = null.multi[each.key]
+}
@@ -0,0 +1,22 @@
+provider "null" {
+  for_each = toset(["a", "b"])
+  alias    = "multi"
+}
+
+provider "null" {
+  alias = "single"
+}
+
+module "ok" {
+  source = "./child"
+  providers = {
+    null.multi = null.multi[*]
+  }
+}
+
+module "not_instanced" {
+  source = "./child"
+  providers = {
+    null.multi = null.single[*]
+  }
+}
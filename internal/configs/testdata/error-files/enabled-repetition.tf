@@ -0,0 +1,34 @@
+resource "aws_instance" "count" {
+  count = 2
+
+  lifecycle {
+    enabled = true # ERROR: Invalid combination of "enabled" and "count"
+  }
+}
+
+data "aws_ami" "for_each" {
+  for_each = toset(["a"])
+
+  lifecycle {
+    enabled = true # ERROR: Invalid combination of "enabled" and "for_each"
+  }
+}
+
+module "count" {
+  source = "./network"
+  count  = 2
+
+  lifecycle {
+    enabled = true # ERROR: Invalid combination of "enabled" and "count"
+  }
+}
+
+module "lifecycle" {
+  source = "./network"
+
+  lifecycle {
+    enabled = true
+  }
+  lifecycle { # ERROR: Duplicate lifecycle block
+  }
+}
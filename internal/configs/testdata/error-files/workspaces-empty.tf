@@ -0,0 +1,2 @@
+workspaces { # ERROR: No workspaces declared
+}
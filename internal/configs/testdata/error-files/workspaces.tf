@@ -0,0 +1,5 @@
+workspaces {
+  workspace "dev" {}
+  workspace "dev" {} # ERROR: Duplicate workspace declaration
+  workspace "dev/eu" {} # ERROR: Invalid workspace name
+}
@@ -0,0 +1,6 @@
+ephemeral "example" "example" {
+  lifecycle {
+    # Ephemeral resources are never planned, so they have no lifecycle.
+    create_before_destroy = true
+  }
+}
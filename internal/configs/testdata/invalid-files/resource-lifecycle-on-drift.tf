@@ -0,0 +1,5 @@
+resource "example" "example" {
+  lifecycle {
+    on_drift = "revert" # ERROR: Invalid on_drift policy
+  }
+}
@@ -0,0 +1,3 @@
+variable "ephemeral-value" {
+  ephemeral = "yes" # must be boolean
+}
@@ -0,0 +1,7 @@
+provider "test" {
+  alias = "legacy"
+}
+
+removed {
+  from = provider.test.legacy
+}
@@ -0,0 +1,18 @@
+variable "old_name" {
+  type       = string
+  default    = null
+  deprecated = "Use new_name instead."
+}
+
+variable "new_name" {
+  type = string
+}
+
+output "old_output" {
+  value      = var.new_name
+  deprecated = "Use new_output instead."
+}
+
+output "new_output" {
+  value = var.new_name
+}
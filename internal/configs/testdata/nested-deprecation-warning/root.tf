@@ -0,0 +1,15 @@
+module "child" {
+  source = "./child"
+
+  old_name = "a"
+  new_name = "b"
+}
+
+locals {
+  old = module.child.old_output
+  new = module.child.new_output
+}
+
+output "combined" {
+  value = "${module.child.old_output}-${local.new}"
+}
@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    foo = {
+      source                = "hashicorp/foo"
+      configuration_aliases = [foo.east]
+    }
+    bar = {
+      source = "hashicorp/bar"
+    }
+  }
+}
+
+# Never used, since the grandchild module has its own configuration.
+provider "bar" {
+}
+
+module "grandchild" {
+  source = "../grandchild"
+}
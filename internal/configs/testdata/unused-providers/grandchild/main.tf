@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    foo = {
+      source = "hashicorp/foo"
+    }
+    bar = {
+      source = "hashicorp/bar"
+    }
+  }
+}
+
+provider "bar" {
+}
+
+resource "foo_instance" "a" {
+}
+
+resource "bar_instance" "a" {
+}
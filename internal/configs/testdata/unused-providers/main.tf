@@ -0,0 +1,46 @@
+terraform {
+  required_providers {
+    foo = {
+      source = "hashicorp/foo"
+    }
+    bar = {
+      source = "hashicorp/bar"
+    }
+    baz = {
+      source = "hashicorp/baz"
+    }
+  }
+}
+
+# Used implicitly by foo_instance.a in the grandchild module.
+provider "foo" {
+}
+
+# Passed explicitly to the child module.
+provider "foo" {
+  alias = "east"
+}
+
+# Never used.
+provider "foo" {
+  alias = "west"
+}
+
+# Used directly by a resource.
+provider "bar" {
+}
+
+# Never used.
+provider "baz" {
+}
+
+resource "bar_instance" "a" {
+}
+
+module "child" {
+  source = "./child"
+
+  providers = {
+    foo.east = foo.east
+  }
+}
@@ -0,0 +1,25 @@
+variable "create" {
+  type = bool
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-1234"
+
+  lifecycle {
+    enabled = var.create
+  }
+}
+
+data "aws_ami" "web" {
+  lifecycle {
+    enabled = var.create
+  }
+}
+
+module "network" {
+  source = "./network"
+
+  lifecycle {
+    enabled = var.create
+  }
+}
@@ -0,0 +1,18 @@
+ephemeral "vault_token" "admin" {
+  policies = ["admin"]
+}
+
+ephemeral "aws_secret" "db" {
+  count    = 2
+  provider = aws.west
+
+  name = "db-${count.index}"
+
+  depends_on = [ephemeral.vault_token.admin]
+}
+
+ephemeral "aws_secret" "by_name" {
+  for_each = toset(["a", "b"])
+
+  name = each.key
+}
@@ -23,3 +23,13 @@ output "π" {
     pizza.cheese,
   ]
 }
+
+output "plumbing" {
+  value    = local.bar
+  internal = true
+}
+
+output "short_lived" {
+  value     = local.bar
+  ephemeral = true
+}
@@ -0,0 +1,9 @@
+resource "aws_iam_policy" "example" {
+  policy   = "{}"
+  template = ""
+
+  lifecycle {
+    json_attributes = [policy]
+    yaml_attributes = [template]
+  }
+}
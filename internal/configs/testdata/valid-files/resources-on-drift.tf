@@ -0,0 +1,17 @@
+resource "aws_security_group" "reconcile" {
+  lifecycle {
+    on_drift = "reconcile"
+  }
+}
+
+resource "aws_autoscaling_group" "ignore" {
+  lifecycle {
+    on_drift = "ignore"
+  }
+}
+
+resource "aws_iam_role" "fail" {
+  lifecycle {
+    on_drift = "fail"
+  }
+}
@@ -2,6 +2,7 @@ resource "aws_security_group" "firewall" {
   lifecycle {
     create_before_destroy = true
     prevent_destroy = true
+    adopt_existing = true
     ignore_changes = [
       description,
     ]
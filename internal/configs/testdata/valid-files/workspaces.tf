@@ -0,0 +1,19 @@
+variable "instance_count" {
+  type    = number
+  default = 1
+}
+
+workspaces {
+  workspace "default" {}
+
+  workspace "staging" {
+    tags = ["nonprod"]
+  }
+
+  workspace "prod" {
+    tags = ["prod", "critical"]
+    variables = {
+      instance_count = 3
+    }
+  }
+}
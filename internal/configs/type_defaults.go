@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// deepMergeTypeDefaults walks the given value alongside the given type
+// defaults and, wherever an optional object attribute with a default value
+// has been set to an object, merges the attributes of the default value into
+// that object for any of its attributes that are missing or null.
+//
+// For example, given the type constraint
+//
+//	object({
+//	  network = optional(object({
+//	    cidr = string
+//	    ipv6 = bool
+//	  }), { cidr = "10.0.0.0/16", ipv6 = false })
+//	})
+//
+// the value { network = { ipv6 = true } } becomes
+// { network = { cidr = "10.0.0.0/16", ipv6 = true } }, whereas
+// typeexpr.Defaults.Apply alone would leave cidr unset.
+//
+// Like typeexpr.Defaults.Apply, this is permissive and leaves anything it
+// can't make sense of unchanged for the subsequent type conversion to report.
+// The result must still have the type defaults applied to it.
+func deepMergeTypeDefaults(d *typeexpr.Defaults, v cty.Value) cty.Value {
+	if d == nil || !v.IsKnown() || v.IsNull() {
+		return v
+	}
+	if len(d.DefaultValues) == 0 && len(d.Children) == 0 {
+		return v
+	}
+
+	v, marks := v.Unmark()
+	ty := v.Type()
+
+	switch {
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		if v.LengthInt() == 0 {
+			break
+		}
+		elems := v.AsValueSlice()
+		for i, elem := range elems {
+			elems[i] = deepMergeTypeDefaults(typeDefaultsChild(d, i), elem)
+		}
+		switch {
+		case ty.IsListType() && valuesHaveSameType(elems):
+			v = cty.ListVal(elems)
+		case ty.IsSetType() && valuesHaveSameType(elems):
+			v = cty.SetVal(elems)
+		default:
+			v = cty.TupleVal(elems)
+		}
+
+	case ty.IsObjectType(), ty.IsMapType():
+		if v.LengthInt() == 0 && len(d.DefaultValues) == 0 {
+			break
+		}
+		attrs := v.AsValueMap()
+		if attrs == nil {
+			attrs = make(map[string]cty.Value)
+		}
+		for name, attr := range attrs {
+			attrs[name] = deepMergeTypeDefaults(typeDefaultsChild(d, name), attr)
+		}
+		if d.Type.IsObjectType() {
+			for name, def := range d.DefaultValues {
+				if attr, ok := attrs[name]; ok {
+					attrs[name] = deepMergeValue(attr, def)
+				}
+			}
+		}
+		if ty.IsMapType() && len(attrs) != 0 && valuesHaveSameType(mapValues(attrs)) {
+			v = cty.MapVal(attrs)
+		} else {
+			v = cty.ObjectVal(attrs)
+		}
+	}
+
+	return v.WithMarks(marks)
+}
+
+// deepMergeValue returns the given value with the attributes of the given
+// default value added wherever they are missing or null, recursing into
+// nested objects. Values other than objects and maps, including lists, are
+// never merged: the given value always replaces the default value.
+func deepMergeValue(given, def cty.Value) cty.Value {
+	if !given.IsKnown() || given.IsNull() || !def.IsKnown() || def.IsNull() {
+		return given
+	}
+	if !isObjectOrMap(given.Type()) || !isObjectOrMap(def.Type()) {
+		return given
+	}
+
+	given, marks := given.Unmark()
+	def, defMarks := def.Unmark()
+
+	attrs := given.AsValueMap()
+	if attrs == nil {
+		attrs = make(map[string]cty.Value)
+	}
+	for name, defAttr := range def.AsValueMap() {
+		attr, ok := attrs[name]
+		if !ok || attr.IsNull() {
+			attrs[name] = defAttr.WithMarks(defMarks)
+			continue
+		}
+		attrs[name] = deepMergeValue(attr, defAttr.WithMarks(defMarks))
+	}
+
+	if given.Type().IsMapType() && len(attrs) != 0 && valuesHaveSameType(mapValues(attrs)) {
+		return cty.MapVal(attrs).WithMarks(marks)
+	}
+	return cty.ObjectVal(attrs).WithMarks(marks)
+}
+
+// typeDefaultsChild returns the defaults for the element of the given
+// defaults at the given key, which is an int for sequences and a string for
+// mappings, following the same rules as typeexpr.Defaults.
+func typeDefaultsChild(d *typeexpr.Defaults, key interface{}) *typeexpr.Defaults {
+	switch key := key.(type) {
+	case int:
+		if d.Type.IsTupleType() {
+			return d.Children[strconv.Itoa(key)]
+		}
+	case string:
+		if d.Type.IsObjectType() {
+			return d.Children[key]
+		}
+	}
+	return d.Children[""]
+}
+
+func isObjectOrMap(ty cty.Type) bool {
+	return ty.IsObjectType() || ty.IsMapType()
+}
+
+func valuesHaveSameType(vals []cty.Value) bool {
+	for _, val := range vals[1:] {
+		if !val.Type().Equals(vals[0].Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func mapValues(m map[string]cty.Value) []cty.Value {
+	ret := make([]cty.Value, 0, len(m))
+	for _, val := range m {
+		ret = append(ret, val)
+	}
+	return ret
+}
@@ -0,0 +1,241 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func TestVariableApplyTypeDefaults(t *testing.T) {
+	const networkType = `object({
+		name = string
+		network = optional(object({
+			cidr = string
+			ipv6 = optional(bool)
+			tags = optional(map(string))
+		}), { cidr = "10.0.0.0/16", ipv6 = false, tags = { env = "dev" } })
+	})`
+
+	tests := map[string]struct {
+		typeExpr string
+		deep     bool
+		given    cty.Value
+		want     cty.Value
+	}{
+		"shallow omitted attribute": {
+			typeExpr: networkType,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.0.0.0/16"),
+					"ipv6": cty.False,
+					"tags": cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+				}),
+			}),
+		},
+		"shallow partial object": {
+			typeExpr: networkType,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.1.0.0/16"),
+				}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.1.0.0/16"),
+					"ipv6": cty.NullVal(cty.Bool),
+					"tags": cty.NullVal(cty.Map(cty.String)),
+				}),
+			}),
+		},
+		"deep partial object": {
+			typeExpr: networkType,
+			deep:     true,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"ipv6": cty.True,
+				}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.0.0.0/16"),
+					"ipv6": cty.True,
+					"tags": cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+				}),
+			}),
+		},
+		"deep null attribute": {
+			typeExpr: networkType,
+			deep:     true,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.1.0.0/16"),
+					"ipv6": cty.NullVal(cty.Bool),
+				}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.1.0.0/16"),
+					"ipv6": cty.False,
+					"tags": cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+				}),
+			}),
+		},
+		"deep merges maps": {
+			typeExpr: networkType,
+			deep:     true,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"tags": cty.ObjectVal(map[string]cty.Value{"team": cty.StringVal("core")}),
+				}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"network": cty.ObjectVal(map[string]cty.Value{
+					"cidr": cty.StringVal("10.0.0.0/16"),
+					"ipv6": cty.False,
+					"tags": cty.MapVal(map[string]cty.Value{
+						"env":  cty.StringVal("dev"),
+						"team": cty.StringVal("core"),
+					}),
+				}),
+			}),
+		},
+		"deep does not merge lists": {
+			typeExpr: `object({ zones = optional(list(string), ["a", "b"]) })`,
+			deep:     true,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"zones": cty.TupleVal([]cty.Value{cty.StringVal("c")}),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"zones": cty.ListVal([]cty.Value{cty.StringVal("c")}),
+			}),
+		},
+		"deep within collection": {
+			typeExpr: `map(object({
+				settings = optional(object({ size = number, tier = string }), { size = 1, tier = "basic" })
+			}))`,
+			deep: true,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"settings": cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(3)}),
+				}),
+				"b": cty.EmptyObjectVal,
+			}),
+			want: cty.MapVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"settings": cty.ObjectVal(map[string]cty.Value{
+						"size": cty.NumberIntVal(3),
+						"tier": cty.StringVal("basic"),
+					}),
+				}),
+				"b": cty.ObjectVal(map[string]cty.Value{
+					"settings": cty.ObjectVal(map[string]cty.Value{
+						"size": cty.NumberIntVal(1),
+						"tier": cty.StringVal("basic"),
+					}),
+				}),
+			}),
+		},
+		"deep null value": {
+			typeExpr: networkType,
+			deep:     true,
+			given:    cty.NullVal(cty.DynamicPseudoType),
+			want: cty.NullVal(cty.Object(map[string]cty.Type{
+				"name": cty.String,
+				"network": cty.Object(map[string]cty.Type{
+					"cidr": cty.String,
+					"ipv6": cty.Bool,
+					"tags": cty.Map(cty.String),
+				}),
+			})),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.typeExpr), "", hcl.InitialPos)
+			assertNoDiagnostics(t, diags)
+
+			ty, defaults, _, diags := decodeVariableType(expr)
+			assertNoDiagnostics(t, diags)
+			v := &Variable{
+				ConstraintType:    ty,
+				TypeDefaults:      defaults,
+				DeepMergeDefaults: test.deep,
+			}
+
+			got, err := convert.Convert(v.ApplyTypeDefaults(test.given), ty)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVariableDeepMergeDefaults_default(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf": `
+variable "network" {
+  type = object({
+    cidr = string
+    ipv6 = bool
+  })
+  default = null
+}
+
+variable "settings" {
+  type = object({
+    network = optional(object({
+      cidr = string
+      ipv6 = bool
+    }), { cidr = "10.0.0.0/16", ipv6 = false })
+  })
+  default = {
+    network = { ipv6 = true }
+  }
+  deep_merge_defaults = true
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir(".", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	v := mod.Variables["settings"]
+	if !v.DeepMergeDefaults {
+		t.Fatalf("DeepMergeDefaults is not set")
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"network": cty.ObjectVal(map[string]cty.Value{
+			"cidr": cty.StringVal("10.0.0.0/16"),
+			"ipv6": cty.True,
+		}),
+	})
+	if !v.Default.RawEquals(want) {
+		t.Errorf("wrong default\ngot:  %#v\nwant: %#v", v.Default, want)
+	}
+	if mod.Variables["network"].DeepMergeDefaults {
+		t.Errorf("DeepMergeDefaults is set for a variable that doesn't enable it")
+	}
+}
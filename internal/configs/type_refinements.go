@@ -0,0 +1,465 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// TypeRefinements represents the parts of a variable type constraint that
+// the cty type system can't represent itself: union types written as
+// union(string, number), strings that must match a pattern written as
+// regex("^[a-z]+$"), and numbers that must be within a range written as
+// range(1, 65535).
+//
+// The type constraint of the variable uses "any" in place of a union, string
+// in place of a pattern, and number in place of a range, so the refinements
+// must be applied to the value after converting it to the type constraint.
+type TypeRefinements struct {
+	// Union is the list of alternative types for a union type, in the
+	// order they were declared.
+	Union []*TypeUnionMember
+
+	// Pattern is the regular expression that a string must match.
+	Pattern *regexp.Regexp
+
+	// Min and Max are the inclusive bounds of a number range. Either of
+	// them can be a null number, meaning that there is no bound.
+	Min, Max cty.Value
+
+	// Children are the refinements for the elements of collection and
+	// structural types, indexed in the same way as the children of
+	// typeexpr.Defaults: by attribute name for objects, by element index for
+	// tuples, and by "" for the single element type of collections.
+	Children map[string]*TypeRefinements
+}
+
+// TypeUnionMember is one of the alternative types of a union type.
+type TypeUnionMember struct {
+	Type        cty.Type
+	Defaults    *typeexpr.Defaults
+	Refinements *TypeRefinements
+}
+
+// refinedTypeBases are the type keywords that typeexpr sees in place of the
+// calls to the refined type constructors.
+var refinedTypeBases = map[string]string{
+	"union": "any",
+	"regex": "string",
+	"range": "number",
+}
+
+// Apply checks that the given value, which must already be converted to the
+// type constraint of the variable, conforms to the refinements, and returns
+// the value with each union type value converted to the first of the
+// alternative types it conforms to.
+//
+// A nil TypeRefinements accepts any value unchanged.
+//
+// Errors describe the location of the problem within the value in the same
+// way as the errors from type conversion, such as: attribute "port": number
+// must be at most 65535.
+func (r *TypeRefinements) Apply(val cty.Value) (cty.Value, error) {
+	ret, err := r.apply(val, nil)
+	var perr cty.PathError
+	if err == nil || !errors.As(err, &perr) || len(perr.Path) == 0 {
+		return ret, err
+	}
+
+	parts := make([]string, 0, len(perr.Path)+1)
+	for _, step := range perr.Path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			parts = append(parts, fmt.Sprintf("attribute %q", step.Name))
+		case cty.IndexStep:
+			switch step.Key.Type() {
+			case cty.String:
+				parts = append(parts, fmt.Sprintf("element %q", step.Key.AsString()))
+			case cty.Number:
+				parts = append(parts, fmt.Sprintf("element %s", step.Key.AsBigFloat().Text('f', -1)))
+			default:
+				parts = append(parts, "element")
+			}
+		}
+	}
+	parts = append(parts, perr.Error())
+	return ret, errors.New(strings.Join(parts, ": "))
+}
+
+func (r *TypeRefinements) apply(val cty.Value, path cty.Path) (cty.Value, error) {
+	if r == nil || !val.IsKnown() || val.IsNull() {
+		return val, nil
+	}
+	val, valMarks := val.Unmark()
+
+	var ret cty.Value
+	var err error
+	switch {
+	case len(r.Union) != 0:
+		ret, err = r.applyUnion(val, path)
+	case r.Pattern != nil:
+		ret = val
+		if val.Type() == cty.String && !r.Pattern.MatchString(val.AsString()) {
+			err = path.NewErrorf("string must match the regular expression %q", r.Pattern.String())
+		}
+	case r.Min != cty.NilVal || r.Max != cty.NilVal:
+		ret = val
+		if val.Type() != cty.Number {
+			break
+		}
+		if !r.Min.IsNull() && val.LessThan(r.Min).True() {
+			err = path.NewErrorf("number must be at least %s", r.Min.AsBigFloat().Text('f', -1))
+		} else if !r.Max.IsNull() && val.GreaterThan(r.Max).True() {
+			err = path.NewErrorf("number must be at most %s", r.Max.AsBigFloat().Text('f', -1))
+		}
+	default:
+		ret, err = r.applyChildren(val, path)
+	}
+	if err != nil {
+		return cty.DynamicVal, err
+	}
+	return ret.WithMarks(valMarks), nil
+}
+
+func (r *TypeRefinements) applyUnion(val cty.Value, path cty.Path) (cty.Value, error) {
+	// We prefer an alternative that the value already conforms to, so that
+	// for example the number 5 is kept as a number for union(string, number),
+	// and otherwise take the first alternative that the value converts to.
+	var members []*TypeUnionMember
+	for _, m := range r.Union {
+		if len(val.Type().TestConformance(m.Type.WithoutOptionalAttributesDeep())) == 0 {
+			members = append(members, m)
+		}
+	}
+	members = append(members, r.Union...)
+
+	var refinementErr error
+	for _, m := range members {
+		v := val
+		if m.Defaults != nil {
+			v = m.Defaults.Apply(v)
+		}
+		v, err := convert.Convert(v, m.Type)
+		if err != nil {
+			continue
+		}
+		v, err = m.Refinements.apply(v, path)
+		if err != nil {
+			if refinementErr == nil {
+				refinementErr = err
+			}
+			continue
+		}
+		return v, nil
+	}
+
+	if refinementErr != nil {
+		return cty.DynamicVal, refinementErr
+	}
+	names := make([]string, len(r.Union))
+	for i, m := range r.Union {
+		names[i] = typeexpr.TypeString(m.Type)
+	}
+	return cty.DynamicVal, path.NewErrorf("value must be one of the following types: %s", strings.Join(names, ", "))
+}
+
+func (r *TypeRefinements) applyChildren(val cty.Value, path cty.Path) (cty.Value, error) {
+	ty := val.Type()
+	switch {
+	case ty.IsListType() || ty.IsSetType() || ty.IsMapType():
+		child := r.Children[""]
+		if child == nil || val.LengthInt() == 0 {
+			return val, nil
+		}
+		var elems []cty.Value
+		elemMap := make(map[string]cty.Value)
+		var elemTy cty.Type
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			key := k
+			if ty.IsSetType() {
+				key = v
+			}
+			v, err := child.apply(v, path.Copy().Index(key))
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			if elemTy == cty.NilType {
+				elemTy = v.Type()
+			} else if !elemTy.Equals(v.Type()) {
+				return cty.DynamicVal, path.NewErrorf("all elements must have the same type, so a union type must select the same alternative type for each of them")
+			}
+			elems = append(elems, v)
+			if ty.IsMapType() {
+				elemMap[k.AsString()] = v
+			}
+		}
+		switch {
+		case ty.IsListType():
+			return cty.ListVal(elems), nil
+		case ty.IsSetType():
+			return cty.SetVal(elems), nil
+		default:
+			return cty.MapVal(elemMap), nil
+		}
+	case ty.IsObjectType():
+		if len(r.Children) == 0 {
+			return val, nil
+		}
+		attrs := val.AsValueMap()
+		for name, child := range r.Children {
+			attr, ok := attrs[name]
+			if !ok {
+				continue
+			}
+			attr, err := child.apply(attr, path.Copy().GetAttr(name))
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			attrs[name] = attr
+		}
+		return cty.ObjectVal(attrs), nil
+	case ty.IsTupleType():
+		if len(r.Children) == 0 {
+			return val, nil
+		}
+		elems := val.AsValueSlice()
+		for i := range elems {
+			child := r.Children[strconv.Itoa(i)]
+			elem, err := child.apply(elems[i], path.Copy().IndexInt(i))
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			elems[i] = elem
+		}
+		return cty.TupleVal(elems), nil
+	default:
+		return val, nil
+	}
+}
+
+// decodeTypeRefinements finds the calls to the refined type constructors
+// in the given type constraint expression, returning nil if there are none.
+//
+// Other problems with the type constraint are left for typeexpr to report
+// when decoding the expression returned by baseTypeExpr.
+func decodeTypeRefinements(expr hcl.Expression) (*TypeRefinements, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	if hcl.ExprAsKeyword(expr) != "" {
+		return nil, diags
+	}
+	call, callDiags := hcl.ExprCall(expr)
+	if callDiags.HasErrors() {
+		return nil, diags
+	}
+
+	switch call.Name {
+	case "union":
+		if len(call.Arguments) < 2 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type specification",
+				Detail:   "The union type constructor requires at least two arguments specifying the alternative types.",
+				Subject:  call.ArgsRange.Ptr(),
+				Context:  expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		ret := &TypeRefinements{}
+		for _, arg := range call.Arguments {
+			ty, defaults, moreDiags := typeexpr.TypeConstraintWithDefaults(baseTypeExpr{arg})
+			diags = append(diags, moreDiags...)
+			refinements, moreDiags := decodeTypeRefinements(arg)
+			diags = append(diags, moreDiags...)
+			ret.Union = append(ret.Union, &TypeUnionMember{
+				Type:        ty,
+				Defaults:    defaults,
+				Refinements: refinements,
+			})
+		}
+		return ret, diags
+
+	case "regex":
+		var pattern *regexp.Regexp
+		if len(call.Arguments) == 1 {
+			val, valDiags := call.Arguments[0].Value(nil)
+			if !valDiags.HasErrors() && val.IsWhollyKnown() && !val.IsNull() && val.Type() == cty.String {
+				pattern, _ = regexp.Compile(val.AsString())
+			}
+		}
+		if pattern == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type specification",
+				Detail:   "The regex type constructor requires one argument, which must be a string containing a valid regular expression.",
+				Subject:  call.ArgsRange.Ptr(),
+				Context:  expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		return &TypeRefinements{Pattern: pattern}, diags
+
+	case "range":
+		bounds := make([]cty.Value, 0, 2)
+		for _, arg := range call.Arguments {
+			val, valDiags := arg.Value(nil)
+			if valDiags.HasErrors() || !val.IsKnown() {
+				break
+			}
+			val, err := convert.Convert(val, cty.Number)
+			if err != nil {
+				break
+			}
+			bounds = append(bounds, val)
+		}
+		if len(call.Arguments) != 2 || len(bounds) != 2 || (bounds[0].IsNull() && bounds[1].IsNull()) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type specification",
+				Detail:   "The range type constructor requires two arguments, which are the minimum and maximum numbers allowed. One of them can be null to leave that side of the range unbounded.",
+				Subject:  call.ArgsRange.Ptr(),
+				Context:  expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		if !bounds[0].IsNull() && !bounds[1].IsNull() && bounds[0].GreaterThan(bounds[1]).True() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type specification",
+				Detail:   "The minimum of a range type must not be greater than its maximum.",
+				Subject:  call.ArgsRange.Ptr(),
+				Context:  expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		return &TypeRefinements{Min: bounds[0], Max: bounds[1]}, diags
+
+	case "list", "set", "map", "optional":
+		if len(call.Arguments) == 0 {
+			return nil, diags
+		}
+		child, moreDiags := decodeTypeRefinements(call.Arguments[0])
+		diags = append(diags, moreDiags...)
+		if child == nil || call.Name == "optional" {
+			return child, diags
+		}
+		return &TypeRefinements{Children: map[string]*TypeRefinements{"": child}}, diags
+
+	case "object":
+		if len(call.Arguments) != 1 {
+			return nil, diags
+		}
+		attrDefs, mapDiags := hcl.ExprMap(call.Arguments[0])
+		if mapDiags.HasErrors() {
+			return nil, diags
+		}
+		children := make(map[string]*TypeRefinements)
+		for _, attrDef := range attrDefs {
+			child, moreDiags := decodeTypeRefinements(attrDef.Value)
+			diags = append(diags, moreDiags...)
+			if child != nil {
+				children[hcl.ExprAsKeyword(attrDef.Key)] = child
+			}
+		}
+		if len(children) == 0 {
+			return nil, diags
+		}
+		return &TypeRefinements{Children: children}, diags
+
+	case "tuple":
+		if len(call.Arguments) != 1 {
+			return nil, diags
+		}
+		elemDefs, listDiags := hcl.ExprList(call.Arguments[0])
+		if listDiags.HasErrors() {
+			return nil, diags
+		}
+		children := make(map[string]*TypeRefinements)
+		for i, elemDef := range elemDefs {
+			child, moreDiags := decodeTypeRefinements(elemDef)
+			diags = append(diags, moreDiags...)
+			if child != nil {
+				children[strconv.Itoa(i)] = child
+			}
+		}
+		if len(children) == 0 {
+			return nil, diags
+		}
+		return &TypeRefinements{Children: children}, diags
+
+	default:
+		return nil, diags
+	}
+}
+
+// baseTypeExpr wraps a type constraint expression so that typeexpr sees the
+// base type keywords in place of the calls to the refined type constructors,
+// at any level of nesting.
+type baseTypeExpr struct {
+	hcl.Expression
+}
+
+func (e baseTypeExpr) AsTraversal() hcl.Traversal {
+	if call, diags := hcl.ExprCall(e.Expression); !diags.HasErrors() {
+		if base, ok := refinedTypeBases[call.Name]; ok {
+			return hcl.Traversal{hcl.TraverseRoot{Name: base, SrcRange: call.NameRange}}
+		}
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(e.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+	return traversal
+}
+
+func (e baseTypeExpr) ExprCall() *hcl.StaticCall {
+	call, diags := hcl.ExprCall(e.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+	if _, ok := refinedTypeBases[call.Name]; ok {
+		return nil
+	}
+	ret := *call
+	ret.Arguments = make([]hcl.Expression, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		ret.Arguments[i] = baseTypeExpr{arg}
+	}
+	return &ret
+}
+
+func (e baseTypeExpr) ExprList() []hcl.Expression {
+	exprs, diags := hcl.ExprList(e.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+	ret := make([]hcl.Expression, len(exprs))
+	for i, expr := range exprs {
+		ret[i] = baseTypeExpr{expr}
+	}
+	return ret
+}
+
+func (e baseTypeExpr) ExprMap() []hcl.KeyValuePair {
+	pairs, diags := hcl.ExprMap(e.Expression)
+	if diags.HasErrors() {
+		return nil
+	}
+	ret := make([]hcl.KeyValuePair, len(pairs))
+	for i, pair := range pairs {
+		ret[i] = hcl.KeyValuePair{Key: pair.Key, Value: baseTypeExpr{pair.Value}}
+	}
+	return ret
+}
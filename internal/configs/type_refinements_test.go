@@ -0,0 +1,177 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func TestTypeRefinements(t *testing.T) {
+	tests := map[string]struct {
+		typeExpr string
+		given    cty.Value
+		want     cty.Value
+		wantErr  string
+	}{
+		"union keeps exact type": {
+			typeExpr: `union(string, number)`,
+			given:    cty.NumberIntVal(5),
+			want:     cty.NumberIntVal(5),
+		},
+		"union converts to first alternative": {
+			typeExpr: `union(number, bool)`,
+			given:    cty.StringVal("true"),
+			want:     cty.True,
+		},
+		"union of structural types": {
+			typeExpr: `union(list(string), object({ name = string, port = optional(number, 80) }))`,
+			given:    cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("web")}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NumberIntVal(80),
+			}),
+		},
+		"union mismatch": {
+			typeExpr: `union(number, bool)`,
+			given:    cty.StringVal("nope"),
+			wantErr:  "value must be one of the following types: number, bool",
+		},
+		"regex match": {
+			typeExpr: `regex("^[a-z]+$")`,
+			given:    cty.StringVal("abc"),
+			want:     cty.StringVal("abc"),
+		},
+		"regex mismatch": {
+			typeExpr: `regex("^[a-z]+$")`,
+			given:    cty.StringVal("ABC"),
+			wantErr:  `string must match the regular expression "^[a-z]+$"`,
+		},
+		"range in bounds": {
+			typeExpr: `range(1, 65535)`,
+			given:    cty.StringVal("443"),
+			want:     cty.NumberIntVal(443),
+		},
+		"range too small": {
+			typeExpr: `range(1, 65535)`,
+			given:    cty.NumberIntVal(0),
+			wantErr:  "number must be at least 1",
+		},
+		"range unbounded maximum": {
+			typeExpr: `range(0.5, null)`,
+			given:    cty.NumberIntVal(1000000),
+			want:     cty.NumberIntVal(1000000),
+		},
+		"range too large": {
+			typeExpr: `range(null, 10)`,
+			given:    cty.NumberIntVal(11),
+			wantErr:  "number must be at most 10",
+		},
+		"nested in collection": {
+			typeExpr: `map(regex("^t[0-9]\\.[a-z]+$"))`,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("t3.micro"),
+				"b": cty.StringVal("m5.large"),
+			}),
+			wantErr: `element "b": string must match the regular expression`,
+		},
+		"nested in object": {
+			typeExpr: `object({ name = string, port = optional(range(1, 65535)) })`,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NumberIntVal(70000),
+			}),
+			wantErr: `attribute "port": number must be at most 65535`,
+		},
+		"null optional attribute": {
+			typeExpr: `object({ name = string, port = optional(range(1, 65535)) })`,
+			given: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+			}),
+			want: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"port": cty.NullVal(cty.Number),
+			}),
+		},
+		"nested in tuple": {
+			typeExpr: `tuple([string, union(bool, number)])`,
+			given:    cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.NumberIntVal(1)}),
+			want:     cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.NumberIntVal(1)}),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.typeExpr), "", hcl.InitialPos)
+			assertNoDiagnostics(t, diags)
+
+			ty, defaults, _, diags := decodeVariableType(expr)
+			assertNoDiagnostics(t, diags)
+			refinements, diags := decodeTypeRefinements(expr)
+			assertNoDiagnostics(t, diags)
+
+			val := test.given
+			if defaults != nil {
+				val = defaults.Apply(val)
+			}
+			got, err := convert.Convert(val, ty)
+			if err == nil {
+				got, err = refinements.Apply(got)
+			}
+
+			if test.wantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success; want error containing %q", test.wantErr)
+				}
+				if got := err.Error(); !strings.Contains(got, test.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTypeRefinements_invalid(t *testing.T) {
+	tests := map[string]string{
+		`union(string)`:       "requires at least two arguments",
+		`union(string, foo)`:  `The keyword "foo" is not a valid type specification.`,
+		`regex("[")`:          "valid regular expression",
+		`regex(1, 2)`:         "valid regular expression",
+		`range(1)`:            "requires two arguments",
+		`range(null, null)`:   "requires two arguments",
+		`range(10, 1)`:        "must not be greater than its maximum",
+		`list(range("a", 1))`: "requires two arguments",
+	}
+
+	for typeExpr, wantErr := range tests {
+		t.Run(typeExpr, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(typeExpr), "", hcl.InitialPos)
+			assertNoDiagnostics(t, diags)
+
+			_, _, _, diags = decodeVariableType(expr)
+			assertNoDiagnostics(t, diags)
+			_, diags = decodeTypeRefinements(expr)
+			if !diags.HasErrors() {
+				t.Fatalf("unexpected success; want error containing %q", wantErr)
+			}
+			if got := diags.Error(); !strings.Contains(got, wantErr) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, wantErr)
+			}
+		})
+	}
+}
@@ -46,6 +46,14 @@ func decodeVersionConstraintValue(attr *hcl.Attribute, val cty.Value) (VersionCo
 			Subject:  attr.Expr.Range().Ptr(),
 		})
 	}
+	if val.HasMark(marks.Ephemeral) {
+		return ret, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid version constraint",
+			Detail:   fmt.Sprintf("Ephemeral values, or values derived from ephemeral values, cannot be used as %s arguments.", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
 
 	var err error
 	val, err = convert.Convert(val, cty.String)
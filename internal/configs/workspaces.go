@@ -0,0 +1,289 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Workspaces represents a "workspaces" block in a module, which declares
+// the names of the workspaces that the configuration may be used with,
+// along with some settings specific to each of them.
+//
+// Only the workspaces block of the root module has any effect.
+type Workspaces struct {
+	Workspaces map[string]*Workspace
+
+	DeclRange hcl.Range
+}
+
+// Workspace represents a single "workspace" block nested inside a
+// "workspaces" block.
+type Workspace struct {
+	Name string
+
+	// Tags are arbitrary labels for the workspace, which the configuration
+	// can access using terraform.workspace_tags.
+	Tags []string
+
+	// Variables are the default values for the root module input variables
+	// to use when this workspace is selected, overriding the defaults
+	// declared in the variable blocks.
+	Variables map[string]cty.Value
+
+	// VariableRanges are the source ranges of the expressions in Variables,
+	// for use in diagnostics.
+	VariableRanges map[string]hcl.Range
+
+	DeclRange hcl.Range
+}
+
+func decodeWorkspacesBlock(block *hcl.Block) (*Workspaces, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := &Workspaces{
+		Workspaces: make(map[string]*Workspace),
+		DeclRange:  block.DefRange,
+	}
+
+	content, moreDiags := block.Body.Content(workspacesBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	for _, block := range content.Blocks {
+		ws, wsDiags := decodeWorkspaceBlock(block)
+		diags = append(diags, wsDiags...)
+		if ws == nil {
+			continue
+		}
+		if existing, exists := ret.Workspaces[ws.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate workspace declaration",
+				Detail:   fmt.Sprintf("The workspace %q was already declared at %s.", ws.Name, existing.DeclRange),
+				Subject:  &ws.DeclRange,
+			})
+			continue
+		}
+		ret.Workspaces[ws.Name] = ws
+	}
+
+	if len(ret.Workspaces) == 0 && !diags.HasErrors() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "No workspaces declared",
+			Detail:   "A workspaces block must declare at least one workspace using a nested workspace block.",
+			Subject:  &ret.DeclRange,
+		})
+	}
+
+	return ret, diags
+}
+
+func decodeWorkspaceBlock(block *hcl.Block) (*Workspace, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ws := &Workspace{
+		Name:           block.Labels[0],
+		Variables:      make(map[string]cty.Value),
+		VariableRanges: make(map[string]hcl.Range),
+		DeclRange:      block.DefRange,
+	}
+
+	if ws.Name == "" || ws.Name != url.PathEscape(ws.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid workspace name",
+			Detail:   "A workspace name must not be empty, and must only contain characters that are valid in URL path segments without escaping.",
+			Subject:  &block.LabelRanges[0],
+		})
+		return nil, diags
+	}
+
+	content, moreDiags := block.Body.Content(workspaceBlockSchema)
+	diags = append(diags, moreDiags...)
+
+	if attr, exists := content.Attributes["tags"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &ws.Tags)
+		diags = append(diags, valDiags...)
+		sort.Strings(ws.Tags)
+	}
+
+	if attr, exists := content.Attributes["variables"]; exists {
+		// We decode the individual items so that we can report problems
+		// with the values against the expressions that produced them.
+		items, itemDiags := hcl.ExprMap(attr.Expr)
+		diags = append(diags, itemDiags...)
+		for _, item := range items {
+			var name string
+			nameDiags := gohcl.DecodeExpression(item.Key, nil, &name)
+			diags = append(diags, nameDiags...)
+			if nameDiags.HasErrors() {
+				continue
+			}
+			val, valDiags := item.Value.Value(nil)
+			diags = append(diags, valDiags...)
+			if valDiags.HasErrors() {
+				continue
+			}
+			ws.Variables[name] = val
+			ws.VariableRanges[name] = item.Value.Range()
+		}
+	}
+
+	return ws, diags
+}
+
+// Workspace returns the declaration of the workspace with the given name,
+// or nil if there is no such workspace or if the receiver is nil.
+func (w *Workspaces) Workspace(name string) *Workspace {
+	if w == nil {
+		return nil
+	}
+	return w.Workspaces[name]
+}
+
+// Names returns the names of all of the declared workspaces, in
+// lexical order.
+func (w *Workspaces) Names() []string {
+	if w == nil {
+		return nil
+	}
+	names := make([]string, 0, len(w.Workspaces))
+	for name := range w.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckWorkspace returns an error if the configuration declares the
+// workspaces it may be used with and the given workspace isn't one of them.
+// A nil receiver allows any workspace.
+func (w *Workspaces) CheckWorkspace(name string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if w == nil || w.Workspaces[name] != nil {
+		return diags
+	}
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Workspace not declared in configuration",
+		Detail: fmt.Sprintf(
+			"The currently selected workspace %q is not declared in the workspaces block of the root module, so this configuration cannot be used with it.\n\nUse \"tofu workspace select\" to select one of the declared workspaces: %s.",
+			name, strings.Join(w.Names(), ", "),
+		),
+		Subject: w.DeclRange.Ptr(),
+	})
+	return diags
+}
+
+// TagsValue returns the tags of the workspace with the given name as a set
+// of strings, which is empty if the workspace isn't declared.
+func (w *Workspaces) TagsValue(name string) cty.Value {
+	ws := w.Workspace(name)
+	if ws == nil || len(ws.Tags) == 0 {
+		return cty.SetValEmpty(cty.String)
+	}
+	vals := make([]cty.Value, len(ws.Tags))
+	for i, tag := range ws.Tags {
+		vals[i] = cty.StringVal(tag)
+	}
+	return cty.SetVal(vals)
+}
+
+// decodeWorkspaceVariables checks the per-workspace variable defaults against
+// the variables declared in the module and, if the module is the root module,
+// replaces the default values of the variables with those of the given
+// workspace.
+func (m *Module) decodeWorkspaceVariables(call StaticModuleCall) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if m.Workspaces == nil {
+		return diags
+	}
+
+	for _, wsName := range m.Workspaces.Names() {
+		ws := m.Workspaces.Workspaces[wsName]
+
+		names := make([]string, 0, len(ws.Variables))
+		for name := range ws.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			val := ws.Variables[name]
+			rng := ws.VariableRanges[name]
+
+			v, exists := m.Variables[name]
+			if !exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared input variable",
+					Detail:   fmt.Sprintf("The workspace %q sets a default value for the input variable %q, which is not declared in this module.", wsName, name),
+					Subject:  rng.Ptr(),
+				})
+				continue
+			}
+
+			if v.ConstraintType != cty.NilType {
+				val = v.ApplyTypeDefaults(val)
+				var err error
+				val, err = convert.Convert(val, v.ConstraintType)
+				if err == nil {
+					val, err = v.TypeRefinements.Apply(val)
+				}
+				if err != nil {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid default value for variable",
+						Detail:   fmt.Sprintf("The default value for the workspace %q is not compatible with the variable's type constraint: %s.", wsName, tfdiags.FormatError(err)),
+						Subject:  rng.Ptr(),
+					})
+					continue
+				}
+			}
+			if !v.Nullable && val.IsNull() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid default value for variable",
+					Detail:   "A null default value is not valid when nullable=false.",
+					Subject:  rng.Ptr(),
+				})
+				continue
+			}
+			ws.Variables[name] = val
+
+			if call.addr.IsRoot() && call.workspace == wsName {
+				v.Default = val
+			}
+		}
+	}
+
+	return diags
+}
+
+var workspacesBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "workspace",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var workspaceBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "tags"},
+		{Name: "variables"},
+	},
+}
@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestModuleWorkspaceVariables(t *testing.T) {
+	src := `
+variable "instance_count" {
+  type    = number
+  default = 1
+}
+
+variable "region" {
+  type    = string
+  default = "eu-west-1"
+}
+
+workspaces {
+  workspace "staging" {}
+
+  workspace "prod" {
+    tags = ["prod", "critical"]
+    variables = {
+      instance_count = "3"
+    }
+  }
+}
+`
+	noVars := func(v *Variable) (cty.Value, hcl.Diagnostics) {
+		return v.Default, nil
+	}
+
+	tests := map[string]struct {
+		call          StaticModuleCall
+		wantInstances cty.Value
+	}{
+		"selected workspace with defaults": {
+			call:          NewStaticModuleCall(addrs.RootModule, noVars, "", "prod"),
+			wantInstances: cty.NumberIntVal(3),
+		},
+		"selected workspace without defaults": {
+			call:          NewStaticModuleCall(addrs.RootModule, noVars, "", "staging"),
+			wantInstances: cty.NumberIntVal(1),
+		},
+		"child module": {
+			call:          NewStaticModuleCall(addrs.RootModule.Child("child"), noVars, "", "prod"),
+			wantInstances: cty.NumberIntVal(1),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{"mod/main.tf": src})
+			mod, diags := parser.LoadConfigDir("mod", test.call)
+			assertNoDiagnostics(t, diags)
+
+			if got := mod.Variables["instance_count"].Default; !got.RawEquals(test.wantInstances) {
+				t.Errorf("wrong default for instance_count\ngot:  %#v\nwant: %#v", got, test.wantInstances)
+			}
+			if got, want := mod.Variables["region"].Default, cty.StringVal("eu-west-1"); !got.RawEquals(want) {
+				t.Errorf("wrong default for region\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestModuleWorkspaceVariables_invalid(t *testing.T) {
+	src := `
+variable "instance_count" {
+  type     = number
+  nullable = false
+}
+
+workspaces {
+  workspace "prod" {
+    variables = {
+      instance_count = "many"
+      instance_type  = "large"
+    }
+  }
+
+  workspace "dev" {
+    variables = {
+      instance_count = null
+    }
+  }
+}
+`
+	parser := testParser(map[string]string{"mod/main.tf": src})
+	_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Summary)
+	}
+	want := []string{
+		"Invalid default value for variable",
+		"Invalid default value for variable",
+		"Reference to undeclared input variable",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong diagnostics\ngot:  %q\nwant: %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong diagnostic %d\ngot:  %q\nwant: %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWorkspaces(t *testing.T) {
+	parser := testParser(map[string]string{"main.tf": `
+workspaces {
+  workspace "prod" {
+    tags = ["prod", "critical"]
+  }
+  workspace "dev" {}
+}
+`})
+	file, diags := parser.LoadConfigFile("main.tf")
+	assertNoDiagnostics(t, diags)
+	if len(file.Workspaces) != 1 {
+		t.Fatalf("wrong number of workspaces blocks %d; want 1", len(file.Workspaces))
+	}
+	ws := file.Workspaces[0]
+
+	if diags := ws.CheckWorkspace("prod"); diags.HasErrors() {
+		t.Errorf("unexpected error for declared workspace: %s", diags.Err())
+	}
+	diags2 := ws.CheckWorkspace("default")
+	if !diags2.HasErrors() {
+		t.Fatal("expected error for undeclared workspace")
+	}
+	if got, want := diags2[0].Description().Detail, `Use "tofu workspace select" to select one of the declared workspaces: dev, prod.`; !strings.Contains(got, want) {
+		t.Errorf("wrong detail\ngot:  %s\nwant to contain: %s", got, want)
+	}
+
+	var nilWorkspaces *Workspaces
+	if diags := nilWorkspaces.CheckWorkspace("anything"); diags.HasErrors() {
+		t.Errorf("unexpected error without a workspaces block: %s", diags.Err())
+	}
+
+	if got, want := ws.TagsValue("prod"), cty.SetVal([]cty.Value{cty.StringVal("critical"), cty.StringVal("prod")}); !got.RawEquals(want) {
+		t.Errorf("wrong tags for prod\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if got, want := ws.TagsValue("dev"), cty.SetValEmpty(cty.String); !got.RawEquals(want) {
+		t.Errorf("wrong tags for dev\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if got, want := nilWorkspaces.TagsValue("dev"), cty.SetValEmpty(cty.String); !got.RawEquals(want) {
+		t.Errorf("wrong tags without a workspaces block\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/opentofu/opentofu/internal/longpath"
 )
 
 // CopyDir recursively copies all of the files within the directory given in
@@ -26,7 +28,9 @@ import (
 //
 // Symlinks in the source directory are recreated with the same target in the
 // destination directory. If the symlink is to a directory itself, that
-// directory is not recursively visited for further copying.
+// directory is not recursively visited for further copying. Directory
+// junctions on Windows are treated in the same way as symlinks, and so are
+// recreated as symlinks with the same target.
 //
 // File and directory modes are not preserved exactly, but the executable
 // flag is preserved for files on operating systems where it is significant.
@@ -89,7 +93,16 @@ func CopyDir(dst, src string) error {
 
 		// If the current path is a symlink, recreate the symlink relative to
 		// the dst directory
-		if info.Mode()&os.ModeSymlink == os.ModeSymlink {
+		isLink := info.Mode()&os.ModeSymlink == os.ModeSymlink
+		if !isLink && info.Mode()&os.ModeIrregular != 0 {
+			// Directory junctions on Windows are reported as irregular
+			// files, but we must not try to copy them as files.
+			isLink, err = longpath.IsJunction(path)
+			if err != nil {
+				return fmt.Errorf("failed to check whether %q is a junction: %w", path, err)
+			}
+		}
+		if isLink {
 			target, err := os.Readlink(path)
 			if err != nil {
 				return fmt.Errorf("failed to read symlink %q: %w", path, err)
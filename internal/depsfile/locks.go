@@ -10,6 +10,7 @@ import (
 	"sort"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 )
 
@@ -37,11 +38,13 @@ type Locks struct {
 	// settings, environment variables, or whatever similar sources.
 	overriddenProviders map[addrs.Provider]struct{}
 
-	// TODO: In future we'll also have module locks, but the design of that
-	// still needs some more work and we're deferring that to get the
-	// provider locking capability out sooner, because it's more common to
-	// directly depend on providers maintained outside your organization than
-	// modules maintained outside your organization.
+	// modules records the hash of the content of each remote module package
+	// that was installed, along with the selected artifact for packages
+	// installed from an OCI registry using a tag, so that a later
+	// installation can detect if the package has changed. Local module
+	// directories and registry module versions are not eligible for
+	// locking.
+	modules map[addrs.ModulePackage]*ModuleLock
 
 	// sources is a copy of the map of source buffers produced by the HCL
 	// parser during loading, which we retain only so that the caller can
@@ -54,6 +57,7 @@ type Locks struct {
 func NewLocks() *Locks {
 	return &Locks{
 		providers: make(map[addrs.Provider]*ProviderLock),
+		modules:   make(map[addrs.ModulePackage]*ModuleLock),
 
 		// no "sources" here, because that's only for locks objects loaded
 		// from files.
@@ -162,6 +166,53 @@ func (l *Locks) SetSameOverriddenProviders(other *Locks) {
 	}
 }
 
+// Module returns the stored lock for the given module package, or nil if that
+// package currently has no lock.
+func (l *Locks) Module(addr addrs.ModulePackage) *ModuleLock {
+	return l.modules[addr]
+}
+
+// AllModules returns a map describing all of the module package locks in the
+// receiver.
+func (l *Locks) AllModules() map[addrs.ModulePackage]*ModuleLock {
+	ret := make(map[addrs.ModulePackage]*ModuleLock, len(l.modules))
+	for k, v := range l.modules {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetModule creates a new lock or replaces the existing lock for the given
+// module package, recording the hash of its content and, for a package in
+// an OCI registry, the digest of the artifact that was selected for it.
+//
+// Only lockable module packages can be passed to this method. If you pass a
+// non-lockable package address then this function will panic. Use function
+// ModuleIsLockable to determine whether a particular module package should
+// participate in the locking mechanism.
+func (l *Locks) SetModule(addr addrs.ModulePackage, digest, hash string) *ModuleLock {
+	if !ModuleIsLockable(addr) {
+		panic(fmt.Sprintf("Locks.SetModule with non-lockable module package %s", addr))
+	}
+
+	new := &ModuleLock{
+		addr:   addr,
+		digest: digest,
+		hash:   hash,
+	}
+	l.modules[addr] = new
+	return new
+}
+
+// RemoveModule removes any existing lock file entry for the given module
+// package.
+//
+// If the given package did not already have a lock entry, RemoveModule is
+// a no-op.
+func (l *Locks) RemoveModule(addr addrs.ModulePackage) {
+	delete(l.modules, addr)
+}
+
 // NewProviderLock creates a new ProviderLock object that isn't associated
 // with any Locks object.
 //
@@ -278,6 +329,16 @@ func (l *Locks) Equal(other *Locks) bool {
 	// We don't need to worry about providers that are in "other" but not
 	// in the receiver, because we tested the lengths being equal above.
 
+	if len(l.modules) != len(other.modules) {
+		return false
+	}
+	for addr, thisLock := range l.modules {
+		otherLock, ok := other.modules[addr]
+		if !ok || thisLock.digest != otherLock.digest || thisLock.hash != otherLock.hash {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -298,7 +359,9 @@ func (l *Locks) EqualProviderAddress(other *Locks) bool {
 	return true
 }
 
-// Empty returns true if the given Locks object contains no actual locks.
+// Empty returns true if the given Locks object contains no actual provider
+// locks. Module package locks are disregarded, because callers are
+// concerned only with provider selections.
 //
 // UI code might wish to use this to distinguish a lock file being
 // written for the first time from subsequent updates to that lock file.
@@ -324,9 +387,64 @@ func (l *Locks) DeepCopy() *Locks {
 		}
 		ret.SetProvider(addr, lock.version, lock.versionConstraints, hashes)
 	}
+	for addr, lock := range l.modules {
+		ret.SetModule(addr, lock.digest, lock.hash)
+	}
 	return ret
 }
 
+// ModuleIsLockable returns true if the given module package is eligible for
+// locking.
+//
+// All remote module packages are eligible, because the content that a
+// package address refers to can change over time, such as when a git tag is
+// moved, except for packages in OCI registries that are selected by digest,
+// which are already pinned by their address.
+func ModuleIsLockable(addr addrs.ModulePackage) bool {
+	return addr != "" && !getmodules.IsOCIPackageAddressPinned(addr.String())
+}
+
+// ModuleDigestIsLockable returns true if the given module package is eligible
+// for locking the digest of the artifact selected for it, which is true only
+// for packages in OCI registries that are selected by tag.
+func ModuleDigestIsLockable(addr addrs.ModulePackage) bool {
+	return getmodules.IsOCIPackageAddress(addr.String()) && ModuleIsLockable(addr)
+}
+
+// ModuleLock represents lock information for a specific module package.
+type ModuleLock struct {
+	// addr is the address of the module package this lock applies to.
+	addr addrs.ModulePackage
+
+	// digest is the digest of the manifest of the OCI artifact that was
+	// previously selected for the package, or empty for packages that are
+	// not in an OCI registry.
+	digest string
+
+	// hash is the hash of the content of the package that was previously
+	// installed, in the format returned by getmodules.PackageHash, or empty
+	// if it is not yet known.
+	hash string
+}
+
+// Package returns the address of the module package this lock applies to.
+func (l *ModuleLock) Package() addrs.ModulePackage {
+	return l.addr
+}
+
+// Digest returns the digest of the artifact that was selected for the
+// corresponding module package, or an empty string if it isn't a package in
+// an OCI registry.
+func (l *ModuleLock) Digest() string {
+	return l.digest
+}
+
+// Hash returns the hash of the content of the corresponding module package,
+// or an empty string if it is not yet known.
+func (l *ModuleLock) Hash() string {
+	return l.hash
+}
+
 // ProviderLock represents lock information for a specific provider.
 type ProviderLock struct {
 	// addr is the address of the provider this lock applies to.
@@ -14,13 +14,14 @@ import (
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	ociDigest "github.com/opencontainers/go-digest"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/replacefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
-	"github.com/opentofu/opentofu/version"
 )
 
 // LoadLocksFromFile reads locks from the given file, expecting it to be a
@@ -163,6 +164,26 @@ func SaveLocksToBytes(locks *Locks) ([]byte, tfdiags.Diagnostics) {
 		}
 	}
 
+	modules := make([]addrs.ModulePackage, 0, len(locks.modules))
+	for addr := range locks.modules {
+		modules = append(modules, addr)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i] < modules[j]
+	})
+
+	for _, addr := range modules {
+		lock := locks.modules[addr]
+		rootBody.AppendNewline()
+		block := rootBody.AppendNewBlock("module", []string{lock.addr.String()})
+		if lock.digest != "" {
+			block.Body().SetAttributeValue("digest", cty.StringVal(lock.digest))
+		}
+		if lock.hash != "" {
+			block.Body().SetAttributeValue("hash", cty.StringVal(lock.hash))
+		}
+	}
+
 	return f.Bytes(), diags
 }
 
@@ -176,20 +197,16 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 				LabelNames: []string{"source_addr"},
 			},
 
-			// "module" is just a placeholder for future enhancement, so we
-			// can mostly-ignore the this block type we intend to add in
-			// future, but warn in case someone tries to use one e.g. if they
-			// downgraded to an earlier version of OpenTofu.
 			{
 				Type:       "module",
-				LabelNames: []string{"path"},
+				LabelNames: []string{"source_addr"},
 			},
 		},
 	})
 	diags = diags.Append(hclDiags)
 
 	seenProviders := make(map[addrs.Provider]hcl.Range)
-	seenModule := false
+	seenModules := make(map[addrs.ModulePackage]hcl.Range)
 	for _, block := range content.Blocks {
 
 		switch block.Type {
@@ -212,19 +229,22 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 			seenProviders[lock.addr] = block.DefRange
 
 		case "module":
-			// We'll just take the first module block to use for a single warning,
-			// because that's sufficient to get the point across without swamping
-			// the output with warning noise.
-			if !seenModule {
-				currentVersion := version.SemVer.String()
+			lock, moreDiags := decodeModuleLockFromHCL(block)
+			diags = diags.Append(moreDiags)
+			if lock == nil {
+				continue
+			}
+			if previousRng, exists := seenModules[lock.addr]; exists {
 				diags = diags.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagWarning,
-					Summary:  "Dependency locks for modules are not yet supported",
-					Detail:   fmt.Sprintf("OpenTofu v%s only supports dependency locks for providers, not for modules. This configuration may be intended for a later version of OpenTofu that also supports dependency locks for modules.", currentVersion),
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate module lock",
+					Detail:   fmt.Sprintf("This lockfile already declared a lock for module package %s at %s.", lock.addr, previousRng.String()),
 					Subject:  block.TypeRange.Ptr(),
 				})
-				seenModule = true
+				continue
 			}
+			locks.modules[lock.addr] = lock
+			seenModules[lock.addr] = block.DefRange
 
 		default:
 			// Shouldn't get here because this should be exhaustive for
@@ -320,6 +340,99 @@ func decodeProviderLockFromHCL(block *hcl.Block) (*ProviderLock, tfdiags.Diagnos
 	return ret, diags
 }
 
+func decodeModuleLockFromHCL(block *hcl.Block) (*ModuleLock, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// The label is the package address exactly as it appears in the
+	// normalized module source address, so that it's clear to a reader which
+	// module calls each block relates to.
+	addr := addrs.ModulePackage(block.Labels[0])
+	if !ModuleIsLockable(addr) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module package address",
+			Detail:   "The module package address for a module lock must be the address of a remote module package, like \"git::https://example.com/network.git?ref=v1.0.0\". Packages in OCI registries that are selected by digest cannot be locked.",
+			Subject:  block.LabelRanges[0].Ptr(),
+		})
+		return nil, diags
+	}
+
+	content, hclDiags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "digest"},
+			{Name: "hash"},
+		},
+	})
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := &ModuleLock{
+		addr: addr,
+	}
+
+	if attr := content.Attributes["digest"]; attr != nil {
+		var raw string
+		hclDiags = gohcl.DecodeExpression(attr.Expr, nil, &raw)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			return nil, diags
+		}
+		if !ModuleDigestIsLockable(addr) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module package digest",
+				Detail:   fmt.Sprintf("Module package %s is not in an OCI registry, so it cannot have a selected artifact digest.", addr),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		digest, err := ociDigest.Parse(raw)
+		if err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module package digest",
+				Detail:   fmt.Sprintf("The selected artifact for module package %s has an invalid digest: %s.", addr, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		ret.digest = digest.String()
+	}
+
+	if attr := content.Attributes["hash"]; attr != nil {
+		var raw string
+		hclDiags = gohcl.DecodeExpression(attr.Expr, nil, &raw)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			return nil, diags
+		}
+		if err := getmodules.ValidatePackageHash(raw); err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module package hash",
+				Detail:   fmt.Sprintf("The hash for module package %s is invalid: %s.", addr, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil, diags
+		}
+		ret.hash = raw
+	}
+
+	if ret.digest == "" && ret.hash == "" {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module lock",
+			Detail:   fmt.Sprintf("The lock for module package %s must include at least one of the arguments \"digest\" and \"hash\".", addr),
+			Subject:  block.DefRange.Ptr(),
+		})
+		return nil, diags
+	}
+
+	return ret, diags
+}
+
 func decodeProviderVersionArgument(provider addrs.Provider, attr *hcl.Attribute) (getproviders.Version, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	if attr == nil {
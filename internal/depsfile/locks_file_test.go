@@ -107,6 +107,31 @@ func TestLoadLocksFromFile(t *testing.T) {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
 				}
 
+			case "valid-module-locks.hcl":
+				if got, want := len(locks.modules), 2; got != want {
+					t.Errorf("wrong number of modules %d; want %d", got, want)
+				}
+				lock := locks.Module(addrs.ModulePackage("oci://example.com/modules/network:1.0.0"))
+				if lock == nil {
+					t.Fatalf("no lock for module package")
+				}
+				if got, want := lock.Digest(), "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"; got != want {
+					t.Errorf("wrong digest\ngot:  %s\nwant: %s", got, want)
+				}
+				if got, want := lock.Hash(), "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="; got != want {
+					t.Errorf("wrong hash\ngot:  %s\nwant: %s", got, want)
+				}
+				lock = locks.Module(addrs.ModulePackage("git::https://example.com/dns.git?ref=v1.2.0"))
+				if lock == nil {
+					t.Fatalf("no lock for git module package")
+				}
+				if got, want := lock.Digest(), ""; got != want {
+					t.Errorf("wrong digest\ngot:  %s\nwant: %s", got, want)
+				}
+				if got, want := lock.Hash(), "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="; got != want {
+					t.Errorf("wrong hash\ngot:  %s\nwant: %s", got, want)
+				}
+
 			case "valid-provider-locks.hcl":
 				if got, want := len(locks.providers), 3; got != want {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
@@ -223,6 +248,8 @@ func TestSaveLocksToFile(t *testing.T) {
 	locks.SetProvider(barProvider, oneDotTwo, pessimisticOneDotOh, nil)
 	locks.SetProvider(bazProvider, oneDotTwo, nil, nil)
 	locks.SetProvider(booProvider, oneDotTwo, abbreviatedOneDotTwo, nil)
+	locks.SetModule(addrs.ModulePackage("oci://example.com/modules/network:1.0.0"), "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	locks.SetModule(addrs.ModulePackage("git::https://example.com/dns.git?ref=v1.2.0"), "", "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
 
 	dir := t.TempDir()
 
@@ -271,6 +298,15 @@ provider "registry.opentofu.org/test/foo" {
     "test:cccccccccccccccccccccccccccccccccccccccccccccccc",
   ]
 }
+
+module "git::https://example.com/dns.git?ref=v1.2.0" {
+  hash = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "oci://example.com/modules/network:1.0.0" {
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
 `
 	if diff := cmp.Diff(wantContent, gotContent); diff != "" {
 		t.Errorf("wrong result\n%s", diff)
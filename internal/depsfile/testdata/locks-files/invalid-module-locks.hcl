@@ -0,0 +1,27 @@
+
+module "git::https://example.com/network.git" {
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" # ERROR: Invalid module package digest
+}
+
+module "git::https://example.com/storage.git" {
+  hash = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" # ERROR: Invalid module package hash
+}
+
+module "git::https://example.com/dns.git" { # ERROR: Invalid module lock
+}
+
+module "oci://example.com/modules/network@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" { # ERROR: Invalid module package address
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+}
+
+module "oci://example.com/modules/network:1.0.0" {
+  digest = "sha256:nope" # ERROR: Invalid module package digest
+}
+
+module "oci://example.com/modules/dns:1.0.0" {
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+}
+
+module "oci://example.com/modules/dns:1.0.0" { # ERROR: Duplicate module lock
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+}
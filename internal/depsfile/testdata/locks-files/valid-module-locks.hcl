@@ -0,0 +1,9 @@
+
+module "oci://example.com/modules/network:1.0.0" {
+  digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "git::https://example.com/dns.git?ref=v1.2.0" {
+  hash = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
@@ -6,9 +6,11 @@
 package encryption
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
@@ -27,9 +29,13 @@ type baseEncryption struct {
 	enc        *encryption
 	name       string
 	methods    []config.MethodConfig
-	encMethod  method.Method
-	encMeta    keyProviderMetadata
 	staticEval *configs.StaticEvaluator
+	workspace  string
+
+	// encMethod and encMeta are set up by newBaseEncryption, or by encryptor if a key provider prompts the user.
+	encMu     sync.Mutex
+	encMethod method.Method
+	encMeta   keyProviderMetadata
 }
 
 type keyProviderMetamap map[keyprovider.MetaStorageKey][]byte
@@ -39,7 +45,7 @@ type keyProviderMetadata struct {
 	output keyProviderMetamap
 }
 
-func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
+func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator, workspace string) (*baseEncryption, hcl.Diagnostics) {
 	// Lookup method configs for the target, ordered by fallback precedence
 	methods, diags := methodConfigsFromTarget(enc.cfg, target, name, enforced)
 	if diags.HasErrors() {
@@ -74,7 +80,38 @@ func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bo
 	//   This performs a e2e validation run of the config -> primary method flow. It serves as a validation step and allows us to return detailed
 	//   diagnostics here and simple errors in the decrypt function below (as long as fallback is not used).
 	//
+	// What about key providers that prompt the user?
+	//
+	//   There is no metadata for the encryptor, so a key provider that prompts would ask the user to confirm the secret on every run. The setup of
+	//   such an encryptor is put off until the existing data has been decrypted, which asks for the secret without confirmation and lets the
+	//   encryptor reuse it. See encryptor.
+	//
+
+	base := &baseEncryption{
+		enc:        enc,
+		name:       name,
+		staticEval: staticEval,
+		workspace:  workspace,
+		methods:    methods,
+	}
+
+	// The key providers of the encryptor are set up here rather than during a single operation, so their telemetry
+	// spans have no parent.
+	encMethod, encMeta, encDiags := base.setupEncryptor(withDeferredPrompts(context.Background()))
+	if isPromptDeferred(encDiags) {
+		return base, diags
+	}
+	diags = diags.Extend(encDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	base.encMethod = encMethod
+	base.encMeta = encMeta
 
+	return base, diags
+}
+
+func (base *baseEncryption) setupEncryptor(ctx context.Context) (method.Method, keyProviderMetadata, hcl.Diagnostics) {
 	encMeta := keyProviderMetadata{
 		input:  make(keyProviderMetamap),
 		output: make(keyProviderMetamap),
@@ -82,22 +119,27 @@ func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bo
 
 	// methodConfigsFromTarget guarantees that there will be at least one encryption method.  They are not optional in the common target
 	// block, which is required to get to this code.
-	encMethod, encDiags := setupMethod(enc.cfg, methods[0], encMeta, enc.reg, staticEval)
-	diags = diags.Extend(encDiags)
-	if diags.HasErrors() {
-		return nil, diags
-	}
+	encMethod, diags := setupMethod(ctx, base.enc.cfg, base.methods[0], encMeta, base.enc.reg, base.staticEval, base.workspace)
+	return encMethod, encMeta, diags
+}
 
-	base := &baseEncryption{
-		enc:        enc,
-		name:       name,
-		staticEval: staticEval,
-		methods:    methods,
-		encMethod:  encMethod,
-		encMeta:    encMeta,
+// encryptor returns the method used for encryption. It is set up by newBaseEncryption unless one of its key providers
+// prompts the user, in which case it is set up here on first use.
+func (base *baseEncryption) encryptor(ctx context.Context) (method.Method, error) {
+	base.encMu.Lock()
+	defer base.encMu.Unlock()
+
+	if base.encMethod != nil {
+		return base.encMethod, nil
 	}
 
-	return base, diags
+	encMethod, encMeta, diags := base.setupEncryptor(ctx)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to set up encryption for %s: %w", base.name, diags)
+	}
+	base.encMethod = encMethod
+	base.encMeta = encMeta
+	return encMethod, nil
 }
 
 type basedata struct {
@@ -117,14 +159,17 @@ func IsEncryptionPayload(data []byte) (bool, error) {
 	return es.Version != "", nil
 }
 
-func (base *baseEncryption) encrypt(data []byte, enhance func(basedata) interface{}) ([]byte, error) {
-	encryptor := base.encMethod
-
-	if unencrypted.Is(encryptor) {
+func (base *baseEncryption) encrypt(ctx context.Context, data []byte, enhance func(basedata) interface{}) ([]byte, error) {
+	if unencrypted.IsConfig(base.methods[0]) {
 		return data, nil
 	}
 
-	encd, err := encryptor.Encrypt(data)
+	encryptor, err := base.encryptor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encd, err := traceMethod(ctx, "encrypt", base.name, base.methods[0], data, encryptor.Encrypt)
 	if err != nil {
 		return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
 	}
@@ -150,8 +195,23 @@ const (
 	StatusMigration EncryptionStatus = 2
 )
 
+// decrypt decrypts the data and then makes sure that the encryptor is set up, so that it is ready before the decrypted
+// data is changed and, if it was put off, reuses the secrets the user entered for decryption.
+func (base *baseEncryption) decrypt(ctx context.Context, data []byte, validator func([]byte) error) ([]byte, EncryptionStatus, error) {
+	result, status, err := base.decryptPayload(ctx, data, validator)
+	if err != nil {
+		return nil, status, err
+	}
+	if !unencrypted.IsConfig(base.methods[0]) {
+		if _, err := base.encryptor(ctx); err != nil {
+			return nil, StatusUnknown, err
+		}
+	}
+	return result, status, nil
+}
+
 // TODO Find a way to make these errors actionable / clear
-func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) ([]byte, EncryptionStatus, error) {
+func (base *baseEncryption) decryptPayload(ctx context.Context, data []byte, validator func([]byte) error) ([]byte, EncryptionStatus, error) {
 	inputData := basedata{}
 	err := json.Unmarshal(data, &inputData)
 
@@ -206,16 +266,16 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 		}
 
 		// TODO Discuss if we should potentially cache this based on a json-encoded version of inputData.Meta and reduce overhead dramatically
-		decMethod, diags := setupMethod(base.enc.cfg, method, keyProviderMetadata{
+		decMethod, diags := setupMethod(ctx, base.enc.cfg, method, keyProviderMetadata{
 			input:  inputData.Meta,
 			output: outputData.Meta,
-		}, base.enc.reg, base.staticEval)
+		}, base.enc.reg, base.staticEval, base.workspace)
 		if diags.HasErrors() {
 			// This cast to error here is safe as we know that at least one error exists
 			return nil, StatusUnknown, diags
 		}
 
-		uncd, err := decMethod.Decrypt(inputData.Data)
+		uncd, err := traceMethod(ctx, "decrypt", base.name, method, inputData.Data, decMethod.Decrypt)
 		if err == nil {
 			// Success
 			if i == 0 {
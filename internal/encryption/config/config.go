@@ -17,6 +17,10 @@ type EncryptionConfig struct {
 	KeyProviderConfigs []KeyProviderConfig `hcl:"key_provider,block"`
 	MethodConfigs      []MethodConfig      `hcl:"method,block"`
 
+	// Enforced forbids reading and writing unencrypted state and plan files anywhere, including when exporting or
+	// importing state files and when migrating between backends.
+	Enforced bool `hcl:"enforced,optional"`
+
 	State  *EnforceableTargetConfig `hcl:"state,block"`
 	Plan   *EnforceableTargetConfig `hcl:"plan,block"`
 	Remote *RemoteConfig            `hcl:"remote_state_data_sources,block"`
@@ -45,7 +49,10 @@ func (c *EncryptionConfig) GetKeyProvider(kpType, kpName string) (KeyProviderCon
 // encryption. The Body field will contain the remaining undeclared fields the key provider can consume.
 type KeyProviderConfig struct {
 	// EncryptedMetadataAlias contains the key to identify the metadata by.
-	EncryptedMetadataAlias string   `hcl:"encrypted_metadata_alias,optional"`
+	EncryptedMetadataAlias string `hcl:"encrypted_metadata_alias,optional"`
+	// WorkspaceKeyDerivation mixes the name of the current workspace into the keys the key provider returns, so the
+	// state and plan files of one workspace cannot be decrypted in another.
+	WorkspaceKeyDerivation bool     `hcl:"workspace_key_derivation,optional"`
 	Type                   string   `hcl:"type,label"`
 	Name                   string   `hcl:"name,label"`
 	Body                   hcl.Body `hcl:",remain"`
@@ -88,6 +95,25 @@ type EnforceableTargetConfig struct {
 	Enforced bool           `hcl:"enforced,optional"`
 	Method   hcl.Expression `hcl:"method,optional"`
 	Fallback *TargetConfig  `hcl:"fallback,block"`
+
+	// SensitiveOnly restricts the encryption to the values marked as sensitive. It is a pointer so that an override
+	// file can switch it off again. Only the state target supports it.
+	SensitiveOnly *bool `hcl:"sensitive_only,optional"`
+
+	// ShareOutputKeys is a list of references to additional methods that the outputs section of the state is
+	// encrypted with, so that consumers of terraform_remote_state can read the outputs without being able to decrypt
+	// the whole state. Only the state target supports it.
+	ShareOutputKeys hcl.Expression `hcl:"share_output_keys,optional"`
+}
+
+// IsSensitiveOnly returns true if only the sensitive values of the target should be encrypted.
+func (e EnforceableTargetConfig) IsSensitiveOnly() bool {
+	return e.SensitiveOnly != nil && *e.SensitiveOnly
+}
+
+// HasShareOutputKeys returns true if the share_output_keys argument is set.
+func (e EnforceableTargetConfig) HasShareOutputKeys() bool {
+	return isExprSet(e.ShareOutputKeys)
 }
 
 // AsTargetConfig converts the struct into its parent TargetConfig.
@@ -115,3 +141,13 @@ func (n NamedTargetConfig) AsTargetConfig() *TargetConfig {
 		Fallback: n.Fallback,
 	}
 }
+
+// isExprSet returns false if the expression is missing or is the null expression gohcl uses in place of an optional
+// attribute that is not set.
+func isExprSet(expr hcl.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	val, diags := expr.Value(nil)
+	return diags.HasErrors() || !val.IsNull()
+}
@@ -22,6 +22,8 @@ func MergeConfigs(cfg *EncryptionConfig, override *EncryptionConfig) *Encryption
 		KeyProviderConfigs: mergeKeyProviderConfigs(cfg.KeyProviderConfigs, override.KeyProviderConfigs),
 		MethodConfigs:      mergeMethodConfigs(cfg.MethodConfigs, override.MethodConfigs),
 
+		Enforced: cfg.Enforced || override.Enforced,
+
 		State:  mergeEnforceableTargetConfigs(cfg.State, override.State),
 		Plan:   mergeEnforceableTargetConfigs(cfg.Plan, override.Plan),
 		Remote: mergeRemoteConfigs(cfg.Remote, override.Remote),
@@ -114,11 +116,20 @@ func mergeEnforceableTargetConfigs(cfg *EnforceableTargetConfig, override *Enfor
 	}
 
 	mergeTarget := mergeTargetConfigs(cfg.AsTargetConfig(), override.AsTargetConfig())
-	return &EnforceableTargetConfig{
-		Enforced: cfg.Enforced || override.Enforced,
-		Method:   mergeTarget.Method,
-		Fallback: mergeTarget.Fallback,
+	merged := &EnforceableTargetConfig{
+		Enforced:        cfg.Enforced || override.Enforced,
+		Method:          mergeTarget.Method,
+		Fallback:        mergeTarget.Fallback,
+		SensitiveOnly:   cfg.SensitiveOnly,
+		ShareOutputKeys: cfg.ShareOutputKeys,
 	}
+	if override.SensitiveOnly != nil {
+		merged.SensitiveOnly = override.SensitiveOnly
+	}
+	if override.HasShareOutputKeys() {
+		merged.ShareOutputKeys = override.ShareOutputKeys
+	}
+	return merged
 }
 
 func mergeRemoteConfigs(cfg *RemoteConfig, override *RemoteConfig) *RemoteConfig {
@@ -255,6 +255,9 @@ func TestMergeTargetConfigs(t *testing.T) {
 	expressionOne := hcltest.MockExprLiteral(cty.UnknownVal(cty.Set(cty.String)))
 	expressionTwo := hcltest.MockExprLiteral(cty.UnknownVal(cty.Set(cty.Bool)))
 
+	sensitiveOnly := true
+	notSensitiveOnly := false
+
 	tests := []struct {
 		name     string
 		input    *EnforceableTargetConfig
@@ -321,6 +324,30 @@ func TestMergeTargetConfigs(t *testing.T) {
 			override: makeEnforceableTargetConfig(true, expressionTwo, makeTargetConfig(true, expressionTwo, nil)),
 			expected: makeEnforceableTargetConfig(true, expressionTwo, makeTargetConfig(true, expressionTwo, nil)),
 		},
+		{
+			name:     "sensitive_only is kept if not overridden",
+			input:    &EnforceableTargetConfig{Method: expressionOne, SensitiveOnly: &sensitiveOnly},
+			override: &EnforceableTargetConfig{Method: expressionTwo},
+			expected: &EnforceableTargetConfig{Method: expressionTwo, SensitiveOnly: &sensitiveOnly},
+		},
+		{
+			name:     "sensitive_only can be switched off",
+			input:    &EnforceableTargetConfig{Method: expressionOne, SensitiveOnly: &sensitiveOnly},
+			override: &EnforceableTargetConfig{Method: expressionOne, SensitiveOnly: &notSensitiveOnly},
+			expected: &EnforceableTargetConfig{Method: expressionOne, SensitiveOnly: &notSensitiveOnly},
+		},
+		{
+			name:     "share_output_keys is kept if not overridden",
+			input:    &EnforceableTargetConfig{Method: expressionOne, ShareOutputKeys: expressionOne},
+			override: &EnforceableTargetConfig{Method: expressionTwo, ShareOutputKeys: hcl.StaticExpr(cty.NullVal(cty.DynamicPseudoType), hcl.Range{})},
+			expected: &EnforceableTargetConfig{Method: expressionTwo, ShareOutputKeys: expressionOne},
+		},
+		{
+			name:     "share_output_keys is overridden",
+			input:    &EnforceableTargetConfig{Method: expressionOne, ShareOutputKeys: expressionOne},
+			override: &EnforceableTargetConfig{Method: expressionOne, ShareOutputKeys: expressionTwo},
+			expected: &EnforceableTargetConfig{Method: expressionOne, ShareOutputKeys: expressionTwo},
+		},
 	}
 
 	for _, test := range tests {
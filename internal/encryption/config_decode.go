@@ -0,0 +1,67 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// dynamicConfig is the common part of keyprovider.DynamicConfig and method.DynamicConfig.
+type dynamicConfig interface {
+	ConfigSpec() (hcldec.Spec, error)
+	SetConfigValue(value cty.Value)
+}
+
+// configVariables returns the variables referenced in the body of a key provider or method configuration. The target
+// is either a struct annotated with hcl tags or a dynamicConfig.
+func configVariables(body hcl.Body, target any) ([]hcl.Traversal, hcl.Diagnostics) {
+	dyn, ok := target.(dynamicConfig)
+	if !ok {
+		return gohcl.VariablesInBody(body, target)
+	}
+	spec, diags := dynamicConfigSpec(dyn)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return hcldec.Variables(body, spec), diags
+}
+
+// decodeConfig decodes the body of a key provider or method configuration into the target, which is either a struct
+// annotated with hcl tags or a dynamicConfig.
+func decodeConfig(body hcl.Body, ctx *hcl.EvalContext, target any) hcl.Diagnostics {
+	dyn, ok := target.(dynamicConfig)
+	if !ok {
+		return gohcl.DecodeBody(body, ctx, target)
+	}
+	spec, diags := dynamicConfigSpec(dyn)
+	if diags.HasErrors() {
+		return diags
+	}
+	val, decodeDiags := hcldec.Decode(body, spec, ctx)
+	diags = diags.Extend(decodeDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+	dyn.SetConfigValue(val)
+	return diags
+}
+
+func dynamicConfigSpec(dyn dynamicConfig) (hcldec.Spec, hcl.Diagnostics) {
+	spec, err := dyn.ConfigSpec()
+	if err != nil {
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Unable to load configuration schema",
+			Detail:   fmt.Sprintf("Failed to obtain the configuration schema: %v", err),
+		}}
+	}
+	return spec, nil
+}
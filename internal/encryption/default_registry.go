@@ -6,42 +6,66 @@
 package encryption
 
 import (
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/argon2id"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/aws_kms"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/conjur"
 	externalKeyProvider "github.com/opentofu/opentofu/internal/encryption/keyprovider/external"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/gcp_kms"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/openbao"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/statictest"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/systemd_creds"
 	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
 	externalMethod "github.com/opentofu/opentofu/internal/encryption/method/external"
 	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
 	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
 )
 
-var DefaultRegistry = lockingencryptionregistry.New()
+// DefaultRegistry contains the built-in key providers and methods. Key providers that support interactive input
+// cannot prompt the user when used with this registry, use NewDefaultRegistry to supply a prompter.
+var DefaultRegistry = NewDefaultRegistry(nil)
 
-func init() {
-	if err := DefaultRegistry.RegisterKeyProvider(pbkdf2.New()); err != nil {
+// NewDefaultRegistry creates a registry with the built-in key providers and methods. The prompter is passed to the key
+// providers that can ask the user for input interactively and may be nil if prompting is not available.
+func NewDefaultRegistry(prompter keyprovider.Prompter) registry.Registry {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.NewWithPrompter(prompter)); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterKeyProvider(aws_kms.New()); err != nil {
+	if err := reg.RegisterKeyProvider(argon2id.NewWithPrompter(prompter)); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterKeyProvider(gcp_kms.New()); err != nil {
+	if err := reg.RegisterKeyProvider(aws_kms.New()); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterKeyProvider(openbao.New()); err != nil {
+	if err := reg.RegisterKeyProvider(gcp_kms.New()); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterKeyProvider(externalKeyProvider.New()); err != nil {
+	if err := reg.RegisterKeyProvider(openbao.New()); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterMethod(aesgcm.New()); err != nil {
+	if err := reg.RegisterKeyProvider(conjur.New()); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterMethod(externalMethod.New()); err != nil {
+	if err := reg.RegisterKeyProvider(systemd_creds.New()); err != nil {
 		panic(err)
 	}
-	if err := DefaultRegistry.RegisterMethod(unencrypted.New()); err != nil {
+	if err := reg.RegisterKeyProvider(externalKeyProvider.New()); err != nil {
 		panic(err)
 	}
+	if err := reg.RegisterKeyProvider(statictest.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(externalMethod.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+	return reg
 }
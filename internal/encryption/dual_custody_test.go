@@ -6,6 +6,7 @@
 package encryption
 
 import (
+	"context"
 	"testing"
 
 	"github.com/opentofu/opentofu/internal/configs"
@@ -64,14 +65,14 @@ func TestDualCustody(t *testing.T) {
 
 	sfe := enc.State()
 	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
-	encryptedState, err := sfe.EncryptState(testData)
+	encryptedState, err := sfe.EncryptState(context.Background(), testData)
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
 	if string(encryptedState) == string(testData) {
 		t.Fatalf("The state has not been encrypted.")
 	}
-	decryptedState, _, err := sfe.DecryptState(encryptedState)
+	decryptedState, _, err := sfe.DecryptState(context.Background(), encryptedState)
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
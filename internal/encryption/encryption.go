@@ -23,8 +23,10 @@ type Encryption interface {
 	Plan() PlanEncryption
 
 	// RemoteState produces a StateEncryption for reading remote states using the terraform_remote_state data
-	// source.
-	RemoteState(string) StateEncryption
+	// source. It uses the configuration of the first of the given names that has a remote_state_data_source block,
+	// or the default configuration if none of them has one. This allows callers to pass increasingly general names
+	// for a data source, for example with and without its instance key.
+	RemoteState(names ...string) StateEncryption
 }
 
 type encryption struct {
@@ -66,23 +68,60 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 	}
 	var encDiags hcl.Diagnostics
 
+	if cfg.Enforced {
+		diags = append(diags, checkEnforcedConfig(cfg)...)
+	}
+
 	if cfg.State != nil {
-		enc.state, encDiags = newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, "state", staticEval)
-		diags = append(diags, encDiags...)
+		state, stateDiags := enc.newWorkspaceStateEncryption(staticEval.Workspace(), func(workspace string) (*stateEncryption, hcl.Diagnostics) {
+			state, diags := newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.Enforced || cfg.State.Enforced, "state", staticEval, workspace)
+			state.sensitiveOnly = cfg.State.IsSensitiveOnly()
+			state.enforced = cfg.Enforced
+			if cfg.State.HasShareOutputKeys() && !diags.HasErrors() {
+				diags = append(diags, state.setupSharedOutputs(enc, cfg.State.ShareOutputKeys, cfg.Enforced || cfg.State.Enforced, staticEval, workspace)...)
+			}
+			return state, diags
+		})
+		diags = append(diags, stateDiags...)
+		enc.state = state
 	} else {
 		enc.state = StateEncryptionDisabled()
 	}
 
 	if cfg.Plan != nil {
-		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Plan.Enforced, "plan", staticEval)
+		if cfg.Plan.SensitiveOnly != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported argument",
+				Detail:   "The sensitive_only argument is only supported in the state block. Plan files are always encrypted as a whole.",
+				Subject:  cfg.DeclRange.Ptr(),
+			})
+		}
+		if cfg.Plan.HasShareOutputKeys() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported argument",
+				Detail:   "The share_output_keys argument is only supported in the state block. Plan files do not contain outputs that can be shared.",
+				Subject:  cfg.Plan.ShareOutputKeys.Range().Ptr(),
+			})
+		}
+		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Enforced || cfg.Plan.Enforced, "plan", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.plan = PlanEncryptionDisabled()
 	}
 
 	if cfg.Remote != nil && cfg.Remote.Default != nil {
-		enc.remoteDefault, encDiags = newStateEncryption(enc, cfg.Remote.Default, false, "remote.default", staticEval)
-		diags = append(diags, encDiags...)
+		remoteDefault, remoteDiags := enc.newWorkspaceStateEncryption(staticEval.Workspace(), func(workspace string) (*stateEncryption, hcl.Diagnostics) {
+			remoteDefault, diags := newStateEncryption(enc, cfg.Remote.Default, cfg.Enforced, "remote.default", staticEval, workspace)
+			remoteDefault.enforced = cfg.Enforced
+			remoteDefault.readSharedOutputs = true
+			return remoteDefault, diags
+		})
+		diags = append(diags, remoteDiags...)
+		enc.remoteDefault = remoteDefault
+	} else if cfg.Enforced {
+		enc.remoteDefault = stateEncryptionMissing("remote state data sources")
 	} else {
 		enc.remoteDefault = StateEncryptionDisabled()
 	}
@@ -91,8 +130,14 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 		for _, remoteTarget := range cfg.Remote.Targets {
 			// TODO the addr here should be generated in one place.
 			addr := "remote.remote_state_datasource." + remoteTarget.Name
-			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(enc, remoteTarget.AsTargetConfig(), false, addr, staticEval)
-			diags = append(diags, encDiags...)
+			remote, remoteDiags := enc.newWorkspaceStateEncryption(staticEval.Workspace(), func(workspace string) (*stateEncryption, hcl.Diagnostics) {
+				remote, diags := newStateEncryption(enc, remoteTarget.AsTargetConfig(), cfg.Enforced, addr, staticEval, workspace)
+				remote.enforced = cfg.Enforced
+				remote.readSharedOutputs = true
+				return remote, diags
+			})
+			diags = append(diags, remoteDiags...)
+			enc.remotes[remoteTarget.Name] = remote
 		}
 	}
 	if diags.HasErrors() {
@@ -109,9 +154,11 @@ func (e *encryption) Plan() PlanEncryption {
 	return e.plan
 }
 
-func (e *encryption) RemoteState(name string) StateEncryption {
-	if enc, ok := e.remotes[name]; ok {
-		return enc
+func (e *encryption) RemoteState(names ...string) StateEncryption {
+	for _, name := range names {
+		if enc, ok := e.remotes[name]; ok {
+			return enc
+		}
 	}
 	return e.remoteDefault
 }
@@ -124,6 +171,6 @@ func Disabled() Encryption {
 }
 func (e *encryptionDisabled) State() StateEncryption { return StateEncryptionDisabled() }
 func (e *encryptionDisabled) Plan() PlanEncryption   { return PlanEncryptionDisabled() }
-func (e *encryptionDisabled) RemoteState(name string) StateEncryption {
+func (e *encryptionDisabled) RemoteState(names ...string) StateEncryption {
 	return StateEncryptionDisabled()
 }
@@ -0,0 +1,81 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+// checkEnforcedConfig validates the configuration of an encryption block with enforced = true. In this mode every
+// state and plan file must be encrypted, so the state and plan targets are required and must not leave any data
+// unencrypted. The ban on the unencrypted method is applied by methodConfigsFromTarget.
+func checkEnforcedConfig(cfg *config.EncryptionConfig) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, target := range []struct {
+		name string
+		cfg  *config.EnforceableTargetConfig
+	}{{"state", cfg.State}, {"plan", cfg.Plan}} {
+		if target.cfg == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing encryption target",
+				Detail:   fmt.Sprintf("The encryption block is enforced, so it must contain a %s block. OpenTofu refuses to write unencrypted %s files while encryption is enforced.", target.name, target.name),
+				Subject:  cfg.DeclRange.Ptr(),
+			})
+		}
+	}
+	if cfg.State != nil && cfg.State.IsSensitiveOnly() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Partial encryption is forbidden",
+			Detail:   "The encryption block is enforced, so the state must be encrypted as a whole. Remove sensitive_only from the state block.",
+			Subject:  cfg.DeclRange.Ptr(),
+		})
+	}
+	return diags
+}
+
+// PlaintextUnlessEnforced returns the encryption to use for state files that OpenTofu reads or writes outside a
+// backend, such as the output of "tofu state pull" or the input of "tofu state push". These files are unencrypted
+// unless the given state encryption belongs to an enforced encryption block, in which case the given encryption is
+// returned so that no unencrypted state is read or written.
+func PlaintextUnlessEnforced(enc StateEncryption) StateEncryption {
+	switch e := enc.(type) {
+	case *stateEncryption:
+		if e.enforced {
+			return e
+		}
+	case *stateMissing:
+		return e
+	}
+	return StateEncryptionDisabled()
+}
+
+// stateEncryptionMissing returns a StateEncryption for a target that has no configuration while encryption is
+// enforced. It refuses to read or write any state.
+func stateEncryptionMissing(target string) StateEncryption {
+	return &stateMissing{target: target}
+}
+
+type stateMissing struct {
+	target string
+}
+
+func (s *stateMissing) err() error {
+	return fmt.Errorf("encryption is enforced, but no encryption is configured for %s", s.target)
+}
+
+func (s *stateMissing) EncryptState(context.Context, []byte) ([]byte, error) {
+	return nil, s.err()
+}
+
+func (s *stateMissing) DecryptState(context.Context, []byte) ([]byte, EncryptionStatus, error) {
+	return nil, StatusUnknown, s.err()
+}
@@ -0,0 +1,201 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+const testEnforcedKeyConfig = `
+	key_provider "static" "basic" {
+		key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+	}
+	method "aes_gcm" "example" {
+		keys = key_provider.static.basic
+	}
+	method "unencrypted" "migration" {}
+`
+
+const testPlainState = `{"version": 4, "serial": 1, "lineage": "magic", "outputs": {}, "resources": []}`
+
+func testEnforcedRegistry() registry.Registry {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+	return reg
+}
+
+func TestEnforcedConfig(t *testing.T) {
+	reg := testEnforcedRegistry()
+
+	testCases := map[string]struct {
+		config string
+		err    string
+	}{
+		"valid": {
+			config: `
+				enforced = true
+				state {
+					method = method.aes_gcm.example
+				}
+				plan {
+					method = method.aes_gcm.example
+				}`,
+		},
+		"missing state": {
+			config: `
+				enforced = true
+				plan {
+					method = method.aes_gcm.example
+				}`,
+			err: "must contain a state block",
+		},
+		"missing plan": {
+			config: `
+				enforced = true
+				state {
+					method = method.aes_gcm.example
+				}`,
+			err: "must contain a plan block",
+		},
+		"sensitive only": {
+			config: `
+				enforced = true
+				state {
+					method         = method.aes_gcm.example
+					sensitive_only = true
+				}
+				plan {
+					method = method.aes_gcm.example
+				}`,
+			err: "Remove sensitive_only",
+		},
+		"unencrypted fallback": {
+			config: `
+				enforced = true
+				state {
+					method = method.aes_gcm.example
+					fallback {
+						method = method.unencrypted.migration
+					}
+				}
+				plan {
+					method = method.aes_gcm.example
+				}`,
+			err: "Unable to use unencrypted method",
+		},
+		"unencrypted remote": {
+			config: `
+				enforced = true
+				state {
+					method = method.aes_gcm.example
+				}
+				plan {
+					method = method.aes_gcm.example
+				}
+				remote_state_data_sources {
+					default {
+						method = method.unencrypted.migration
+					}
+				}`,
+			err: "Unable to use unencrypted method",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg, diags := config.LoadConfigFromString("test", testEnforcedKeyConfig+tc.config)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			_, diags = New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+			if tc.err == "" {
+				if diags.HasErrors() {
+					t.Fatalf("unexpected error: %s", diags.Error())
+				}
+				return
+			}
+			for _, diag := range diags {
+				if diag.Severity == hcl.DiagError && strings.Contains(diag.Detail, tc.err) {
+					return
+				}
+			}
+			t.Fatalf("expected an error containing %q, got %v", tc.err, diags)
+		})
+	}
+}
+
+func TestEnforcedStateEncryption(t *testing.T) {
+	reg := testEnforcedRegistry()
+
+	enforced := testRemoteStateEncryption(t, reg, testEnforcedKeyConfig+`
+		enforced = true
+		state {
+			method = method.aes_gcm.example
+		}
+		plan {
+			method = method.aes_gcm.example
+		}`)
+	notEnforced := testRemoteStateEncryption(t, reg, testEnforcedKeyConfig+`
+		state {
+			method   = method.aes_gcm.example
+			enforced = true
+		}`)
+
+	t.Run("plaintext files are encrypted", func(t *testing.T) {
+		enc := PlaintextUnlessEnforced(enforced.State())
+		encrypted, err := enc.EncryptState(context.Background(), []byte(testPlainState))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := IsEncryptionPayload(encrypted); !ok {
+			t.Fatalf("state file was not encrypted:\n%s", encrypted)
+		}
+		if _, _, err := enc.DecryptState(context.Background(), []byte(testPlainState)); err == nil {
+			t.Fatalf("expected an error when reading an unencrypted state file")
+		}
+	})
+
+	t.Run("plaintext files without block level enforcement", func(t *testing.T) {
+		enc := PlaintextUnlessEnforced(notEnforced.State())
+		written, err := enc.EncryptState(context.Background(), []byte(testPlainState))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(written) != testPlainState {
+			t.Fatalf("state file was modified:\n%s", written)
+		}
+	})
+
+	t.Run("remote state without configuration", func(t *testing.T) {
+		enc := enforced.RemoteState("test")
+		_, _, err := enc.DecryptState(context.Background(), []byte(testPlainState))
+		if err == nil || !strings.Contains(err.Error(), "no encryption is configured for remote state data sources") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+		if _, disabled := PlaintextUnlessEnforced(enc).(*stateDisabled); disabled {
+			t.Fatalf("expected the missing encryption to be kept")
+		}
+	})
+}
@@ -6,6 +6,7 @@
 package encryption_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
@@ -70,7 +71,7 @@ func Example() {
 	// Encrypt the data, for this example we will be using the string `{"serial": 42, "lineage": "magic"}`,
 	// but in a real world scenario this would be the plan file.
 	sourceData := []byte(`{"serial": 42, "lineage": "magic"}`)
-	encrypted, err := sfe.EncryptState(sourceData)
+	encrypted, err := sfe.EncryptState(context.Background(), sourceData)
 	if err != nil {
 		panic(err)
 	}
@@ -80,7 +81,7 @@ func Example() {
 	}
 
 	// Decrypt
-	decryptedState, status, err := sfe.DecryptState(encrypted)
+	decryptedState, status, err := sfe.DecryptState(context.Background(), encrypted)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,101 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+const (
+	// KeyProviderPrefix is the file name prefix of key provider plugin executables. The rest of the file name is the
+	// ID of the key provider.
+	KeyProviderPrefix = "tofu-encryption-keyprovider-"
+	// MethodPrefix is the file name prefix of method plugin executables. The rest of the file name is the ID of the
+	// method.
+	MethodPrefix = "tofu-encryption-method-"
+)
+
+// Discover registers the key provider and method plugins found in the given directory. Only the files directly in the
+// directory are considered, and only the plugins whose SHA-256 checksum is declared in checksums, keyed by the file
+// name of the plugin without any ".exe" suffix, are registered. OpenTofu verifies the checksum again each time it starts
+// a plugin, which happens only when a configuration uses it. A missing directory is not an error.
+//
+// A broken or unverified plugin must not prevent configurations that don't use it from working, so the problems found
+// are returned as warnings.
+func Discover(dir string, checksums map[string][]byte, reg registry.Registry) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return diags
+		}
+		return diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to load encryption plugins",
+			fmt.Sprintf("OpenTofu could not read the plugin cache directory %s to find encryption plugins: %s.", dir, err),
+		))
+	}
+
+	invalidPlugin := func(path string, err error) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Invalid encryption plugin",
+			fmt.Sprintf("OpenTofu cannot use the encryption plugin %s: %s.", path, err),
+		))
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".exe")
+		path := filepath.Join(dir, entry.Name())
+		if !strings.HasPrefix(name, KeyProviderPrefix) && !strings.HasPrefix(name, MethodPrefix) {
+			continue
+		}
+		checksum, ok := checksums[name]
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Unverified encryption plugin",
+				fmt.Sprintf("The plugin cache directory contains the encryption plugin %s, but the CLI configuration doesn't declare its checksum, so OpenTofu will not run it. To use this plugin, add an encryption_plugin %q block with its SHA-256 checksum to the CLI configuration.", path, name),
+			))
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(name, KeyProviderPrefix):
+			id := keyprovider.ID(strings.TrimPrefix(name, KeyProviderPrefix))
+			if err := id.Validate(); err != nil {
+				invalidPlugin(path, err)
+				continue
+			}
+			if err := reg.RegisterKeyProvider(NewKeyProviderDescriptor(id, path, checksum)); err != nil {
+				invalidPlugin(path, err)
+			}
+		case strings.HasPrefix(name, MethodPrefix):
+			id := method.ID(strings.TrimPrefix(name, MethodPrefix))
+			if err := id.Validate(); err != nil {
+				invalidPlugin(path, err)
+				continue
+			}
+			if err := reg.RegisterMethod(NewMethodDescriptor(id, path, checksum)); err != nil {
+				invalidPlugin(path, err)
+			}
+		}
+	}
+	return diags
+}
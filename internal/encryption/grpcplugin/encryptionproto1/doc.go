@@ -0,0 +1,11 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package encryptionproto1 is home to the Go stubs generated from the
+// protobuf schema of version 1 of the encryption plugin protocol.
+//
+// From elsewhere in OpenTofu, use the API exported by the grpcplugin package
+// instead of using these stubs directly.
+package encryptionproto1
@@ -0,0 +1,302 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+// testKeyProvider hands out the configured key. On encryption it returns a generation counter as metadata, which is
+// incremented from the stored metadata on each call.
+type testKeyProvider struct{}
+
+type testKeyProviderConfig struct {
+	Key string `json:"key"`
+}
+
+type testKeyProviderMeta struct {
+	Generation int `json:"generation"`
+}
+
+func (testKeyProvider) Schema() []Attribute {
+	return []Attribute{{Name: "key", Type: cty.String, Required: true}}
+}
+
+func (testKeyProvider) Provide(rawConfig []byte, rawMeta []byte) (keyprovider.Output, []byte, error) {
+	var cfg testKeyProviderConfig
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+	if cfg.Key == "" {
+		return keyprovider.Output{}, nil, fmt.Errorf("empty key")
+	}
+	var meta testKeyProviderMeta
+	output := keyprovider.Output{EncryptionKey: []byte(cfg.Key)}
+	if rawMeta != nil {
+		if err := json.Unmarshal(rawMeta, &meta); err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+		output.DecryptionKey = []byte(cfg.Key)
+	}
+	meta.Generation++
+	outMeta, err := json.Marshal(meta)
+	return output, outMeta, err
+}
+
+// testMethod XORs the data with the key received from a key provider.
+type testMethod struct{}
+
+type testMethodConfig struct {
+	Keys struct {
+		RawEncryption []int `json:"encryption_key"`
+		RawDecryption []int `json:"decryption_key"`
+	} `json:"keys"`
+}
+
+func (testMethod) Schema() []Attribute {
+	return []Attribute{{Name: "keys", Type: cty.DynamicPseudoType, Required: true}}
+}
+
+func (testMethod) xor(rawKey []int, data []byte) ([]byte, error) {
+	if len(rawKey) == 0 {
+		return nil, fmt.Errorf("no key")
+	}
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[i] = b ^ byte(rawKey[i%len(rawKey)])
+	}
+	return result, nil
+}
+
+func (m testMethod) Encrypt(rawConfig []byte, data []byte) ([]byte, error) {
+	var cfg testMethodConfig
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return nil, err
+	}
+	return m.xor(cfg.Keys.RawEncryption, data)
+}
+
+func (m testMethod) Decrypt(rawConfig []byte, data []byte) ([]byte, error) {
+	var cfg testMethodConfig
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return nil, err
+	}
+	return m.xor(cfg.Keys.RawDecryption, data)
+}
+
+func testPluginClient(t *testing.T, name string, p plugin.Plugin) func() (any, error) {
+	t.Helper()
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{name: p})
+	t.Cleanup(func() {
+		_ = client.Close()
+		server.Stop()
+	})
+	return func() (any, error) {
+		return client.Dispense(name)
+	}
+}
+
+func TestPluginRoundTrip(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(newKeyProviderDescriptor(
+		"test_plugin",
+		testPluginClient(t, KeyProviderPluginName, &KeyProviderPlugin{Impl: testKeyProvider{}}),
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(newMethodDescriptor(
+		"test_xor",
+		testPluginClient(t, MethodPluginName, &MethodPlugin{Impl: testMethod{}}),
+	)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("test", `
+key_provider "test_plugin" "basic" {
+	key = "secret"
+}
+method "test_xor" "example" {
+	keys = key_provider.test_plugin.basic
+}
+state {
+	method = method.test_xor.example
+}
+`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+	enc, diags := encryption.New(reg, cfg, staticEval)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	sourceData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encrypted, err := enc.State().EncryptState(context.Background(), sourceData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Meta          map[string][]byte `json:"meta"`
+		EncryptedData []byte            `json:"encrypted_data"`
+	}
+	if err := json.Unmarshal(encrypted, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.EncryptedData) == 0 || strings.Contains(string(envelope.EncryptedData), "magic") {
+		t.Fatalf("the data has not been encrypted: %s", encrypted)
+	}
+	if meta := string(envelope.Meta["key_provider.test_plugin.basic"]); meta != `{"generation":1}` {
+		t.Fatalf("the plugin metadata was not stored: %s", encrypted)
+	}
+
+	decrypted, status, err := enc.State().DecryptState(context.Background(), encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != encryption.StatusSatisfied {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	if string(decrypted) != string(sourceData) {
+		t.Fatalf("unexpected decrypted data: %s", decrypted)
+	}
+}
+
+func TestPluginConfigValidation(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(newKeyProviderDescriptor(
+		"test_plugin",
+		testPluginClient(t, KeyProviderPluginName, &KeyProviderPlugin{Impl: testKeyProvider{}}),
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(newMethodDescriptor(
+		"test_xor",
+		testPluginClient(t, MethodPluginName, &MethodPlugin{Impl: testMethod{}}),
+	)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("test", `
+key_provider "test_plugin" "basic" {
+	passphrase = "secret"
+}
+method "test_xor" "example" {
+	keys = key_provider.test_plugin.basic
+}
+state {
+	method = method.test_xor.example
+}
+`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+	_, diags = encryption.New(reg, cfg, staticEval)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error for an unsupported argument")
+	}
+	found := false
+	for _, diag := range diags {
+		if diag.Summary == "Unsupported argument" && strings.Contains(diag.Detail, "passphrase") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an unsupported argument error, got: %v", diags)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"tofu-encryption-keyprovider-conjur",
+		"tofu-encryption-keyprovider-unverified",
+		"tofu-encryption-method-xchacha.exe",
+		"tofu-encryption-method-invalid.name",
+		"terraform-provider-null",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "tofu-encryption-keyprovider-dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	checksum := sha256.Sum256(nil)
+	checksums := map[string][]byte{
+		"tofu-encryption-keyprovider-conjur":  checksum[:],
+		"tofu-encryption-keyprovider-dir":     checksum[:],
+		"tofu-encryption-method-xchacha":      checksum[:],
+		"tofu-encryption-method-invalid.name": checksum[:],
+	}
+
+	reg := lockingencryptionregistry.New()
+	diags := Discover(dir, checksums, reg)
+	if diags.HasErrors() {
+		t.Fatalf("discovery problems must not be errors: %s", diags.Err())
+	}
+	var summaries []string
+	for _, diag := range diags {
+		summaries = append(summaries, diag.Description().Summary+": "+diag.Description().Detail)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 warnings, got %d:\n%s", len(summaries), strings.Join(summaries, "\n"))
+	}
+	if !strings.Contains(summaries[0], "Unverified encryption plugin") || !strings.Contains(summaries[0], "keyprovider-unverified") {
+		t.Errorf("expected a warning for the unverified plugin, got %s", summaries[0])
+	}
+	if !strings.Contains(summaries[1], "Invalid encryption plugin") || !strings.Contains(summaries[1], "invalid.name") {
+		t.Errorf("expected a warning for the invalid plugin name, got %s", summaries[1])
+	}
+	if _, err := reg.GetKeyProviderDescriptor("conjur"); err != nil {
+		t.Fatalf("key provider plugin not registered: %v", err)
+	}
+	if _, err := reg.GetMethodDescriptor("xchacha"); err != nil {
+		t.Fatalf("method plugin not registered: %v", err)
+	}
+	if _, err := reg.GetKeyProviderDescriptor("unverified"); err == nil {
+		t.Fatal("plugins without a declared checksum must not be registered")
+	}
+	if _, err := reg.GetKeyProviderDescriptor("dir"); err == nil {
+		t.Fatal("directories must not be registered as plugins")
+	}
+
+	if diags := Discover(filepath.Join(dir, "missing"), checksums, lockingencryptionregistry.New()); len(diags) != 0 {
+		t.Fatalf("a missing directory must not be a problem: %s", diags.ErrWithWarnings())
+	}
+}
+
+func TestStartPlugin_checksumMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tofu-encryption-keyprovider-conjur")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	checksum := sha256.Sum256([]byte("something else"))
+
+	_, err := startPlugin(path, checksum[:], KeyProviderPluginName)
+	if err == nil || !strings.Contains(err.Error(), "doesn't match the checksum declared") {
+		t.Fatalf("expected a checksum mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+
+	"github.com/opentofu/opentofu/internal/encryption/grpcplugin/encryptionproto1"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// KeyProvider is implemented by plugins that supply a key provider.
+type KeyProvider interface {
+	// Schema returns the attributes accepted in the key_provider block.
+	Schema() []Attribute
+
+	// Provide returns the encryption and decryption keys. The config is the JSON encoding of an object with the
+	// attributes described by Schema. The meta is the metadata returned by an earlier call to Provide and stored
+	// alongside the data being decrypted, or nil if there is no such data. The returned metadata is stored alongside
+	// newly encrypted data and may be nil.
+	Provide(config []byte, meta []byte) (keyprovider.Output, []byte, error)
+}
+
+// KeyProviderPlugin is the go-plugin implementation of key provider plugins. Impl only needs to be set on the plugin
+// side.
+type KeyProviderPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+
+	Impl KeyProvider
+}
+
+func (p *KeyProviderPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	encryptionproto1.RegisterKeyProviderServer(s, &keyProviderServer{impl: p.Impl})
+	return nil
+}
+
+func (p *KeyProviderPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, conn *grpc.ClientConn) (any, error) {
+	return &keyProviderClient{client: encryptionproto1.NewKeyProviderClient(conn)}, nil
+}
+
+// keyProviderServer is the plugin side of the connection, which passes the requests from OpenTofu on to the key
+// provider implemented by the plugin.
+type keyProviderServer struct {
+	encryptionproto1.UnimplementedKeyProviderServer
+
+	impl KeyProvider
+}
+
+func (s *keyProviderServer) GetSchema(_ context.Context, _ *encryptionproto1.GetSchema_Request) (*encryptionproto1.GetSchema_Response, error) {
+	return encodeSchema(s.impl.Schema())
+}
+
+func (s *keyProviderServer) Provide(_ context.Context, req *encryptionproto1.Provide_Request) (*encryptionproto1.Provide_Response, error) {
+	var meta []byte
+	if len(req.Meta) != 0 && string(req.Meta) != "null" {
+		meta = req.Meta
+	}
+	output, outMeta, err := s.impl.Provide(req.Config, meta)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptionproto1.Provide_Response{
+		EncryptionKey: output.EncryptionKey,
+		DecryptionKey: output.DecryptionKey,
+		Meta:          outMeta,
+	}, nil
+}
+
+// keyProviderClient is the OpenTofu side of the connection to a key provider plugin.
+type keyProviderClient struct {
+	client encryptionproto1.KeyProviderClient
+
+	schemaOnce sync.Once
+	schema     []Attribute
+	schemaErr  error
+}
+
+func (c *keyProviderClient) getSchema() ([]Attribute, error) {
+	c.schemaOnce.Do(func() {
+		resp, err := c.client.GetSchema(context.Background(), &encryptionproto1.GetSchema_Request{})
+		if err != nil {
+			c.schemaErr = err
+			return
+		}
+		c.schema, c.schemaErr = decodeSchema(resp)
+	})
+	return c.schema, c.schemaErr
+}
+
+func (c *keyProviderClient) provide(ctx context.Context, config json.RawMessage, meta json.RawMessage) (*encryptionproto1.Provide_Response, error) {
+	return c.client.Provide(ctx, &encryptionproto1.Provide_Request{Config: config, Meta: meta})
+}
+
+// NewKeyProviderDescriptor returns a descriptor for a key provider implemented by the plugin executable at the given
+// path. The plugin is started when a configuration using the key provider is first loaded, after verifying that the
+// executable has the given SHA-256 checksum.
+func NewKeyProviderDescriptor(id keyprovider.ID, path string, checksum []byte) keyprovider.Descriptor {
+	return newKeyProviderDescriptor(id, func() (any, error) {
+		return startPlugin(path, checksum, KeyProviderPluginName)
+	})
+}
+
+func newKeyProviderDescriptor(id keyprovider.ID, start func() (any, error)) *keyProviderDescriptor {
+	return &keyProviderDescriptor{
+		id:   id,
+		conn: &pluginConn{start: start},
+	}
+}
+
+type keyProviderDescriptor struct {
+	id   keyprovider.ID
+	conn *pluginConn
+}
+
+func (d *keyProviderDescriptor) ID() keyprovider.ID {
+	return d.id
+}
+
+func (d *keyProviderDescriptor) ConfigStruct() keyprovider.Config {
+	return &keyProviderConfig{descriptor: d}
+}
+
+func (d *keyProviderDescriptor) client() (*keyProviderClient, error) {
+	raw, err := d.conn.get()
+	if err != nil {
+		return nil, err
+	}
+	client, ok := raw.(*keyProviderClient)
+	if !ok {
+		return nil, fmt.Errorf("bug: unexpected key provider plugin client type %T", raw)
+	}
+	return client, nil
+}
+
+// keyProviderConfig is the configuration of a key provider implemented by a plugin. Its schema is obtained from the
+// plugin, so it implements keyprovider.DynamicConfig.
+type keyProviderConfig struct {
+	descriptor *keyProviderDescriptor
+	value      cty.Value
+}
+
+func (c *keyProviderConfig) ConfigSpec() (hcldec.Spec, error) {
+	client, err := c.descriptor.client()
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := client.getSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain the schema of the %s key provider: %w", c.descriptor.id, err)
+	}
+	return configSpec(attrs), nil
+}
+
+func (c *keyProviderConfig) SetConfigValue(value cty.Value) {
+	c.value = value
+}
+
+func (c *keyProviderConfig) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	client, err := c.descriptor.client()
+	if err != nil {
+		return nil, nil, &keyprovider.ErrKeyProviderFailure{Message: "failed to start the plugin", Cause: err}
+	}
+	config, err := encodeConfig(c.value)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "failed to encode the configuration", Cause: err}
+	}
+	return &pluginKeyProvider{client: client, config: config}, new(json.RawMessage), nil
+}
+
+// pluginKeyProvider passes the requests for keys on to a plugin. The metadata is opaque to OpenTofu and is passed on
+// as raw JSON.
+type pluginKeyProvider struct {
+	client *keyProviderClient
+	config json.RawMessage
+}
+
+func (p *pluginKeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	return p.ProvideContext(context.Background(), rawMeta)
+}
+
+func (p *pluginKeyProvider) ProvideContext(ctx context.Context, rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	inMeta, ok := rawMeta.(*json.RawMessage)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: incorrect metadata type of %T provided", rawMeta),
+		}
+	}
+
+	resp, err := p.client.provide(ctx, p.config, *inMeta)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{Message: "the plugin failed to provide keys", Cause: err}
+	}
+
+	output := keyprovider.Output{
+		EncryptionKey: resp.EncryptionKey,
+		DecryptionKey: resp.DecryptionKey,
+	}
+	if len(resp.Meta) == 0 {
+		return output, nil, nil
+	}
+	// The metadata is stored as raw JSON alongside the encrypted data.
+	if !json.Valid(resp.Meta) {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{Message: "the plugin returned metadata that is not valid JSON"}
+	}
+	return output, json.RawMessage(resp.Meta), nil
+}
@@ -0,0 +1,215 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+
+	"github.com/opentofu/opentofu/internal/encryption/grpcplugin/encryptionproto1"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// Method is implemented by plugins that supply an encryption method.
+type Method interface {
+	// Schema returns the attributes accepted in the method block. Use an attribute with a dynamic type to receive
+	// the output of a key provider.
+	Schema() []Attribute
+
+	// Encrypt encrypts the data. The config is the JSON encoding of an object with the attributes described by
+	// Schema.
+	Encrypt(config []byte, data []byte) ([]byte, error)
+
+	// Decrypt decrypts the data. The config is the JSON encoding of an object with the attributes described by
+	// Schema.
+	Decrypt(config []byte, data []byte) ([]byte, error)
+}
+
+// MethodPlugin is the go-plugin implementation of method plugins. Impl only needs to be set on the plugin side.
+type MethodPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+
+	Impl Method
+}
+
+func (p *MethodPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	encryptionproto1.RegisterMethodServer(s, &methodServer{impl: p.Impl})
+	return nil
+}
+
+func (p *MethodPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, conn *grpc.ClientConn) (any, error) {
+	return &methodClient{client: encryptionproto1.NewMethodClient(conn)}, nil
+}
+
+// methodServer is the plugin side of the connection, which passes the requests from OpenTofu on to the method
+// implemented by the plugin.
+type methodServer struct {
+	encryptionproto1.UnimplementedMethodServer
+
+	impl Method
+}
+
+func (s *methodServer) GetSchema(_ context.Context, _ *encryptionproto1.GetSchema_Request) (*encryptionproto1.GetSchema_Response, error) {
+	return encodeSchema(s.impl.Schema())
+}
+
+func (s *methodServer) Encrypt(_ context.Context, req *encryptionproto1.Encrypt_Request) (*encryptionproto1.Encrypt_Response, error) {
+	data, err := s.impl.Encrypt(req.Config, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptionproto1.Encrypt_Response{Data: data}, nil
+}
+
+func (s *methodServer) Decrypt(_ context.Context, req *encryptionproto1.Decrypt_Request) (*encryptionproto1.Decrypt_Response, error) {
+	data, err := s.impl.Decrypt(req.Config, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptionproto1.Decrypt_Response{Data: data}, nil
+}
+
+// methodClient is the OpenTofu side of the connection to a method plugin.
+type methodClient struct {
+	client encryptionproto1.MethodClient
+
+	schemaOnce sync.Once
+	schema     []Attribute
+	schemaErr  error
+}
+
+func (c *methodClient) getSchema() ([]Attribute, error) {
+	c.schemaOnce.Do(func() {
+		resp, err := c.client.GetSchema(context.Background(), &encryptionproto1.GetSchema_Request{})
+		if err != nil {
+			c.schemaErr = err
+			return
+		}
+		c.schema, c.schemaErr = decodeSchema(resp)
+	})
+	return c.schema, c.schemaErr
+}
+
+func (c *methodClient) encrypt(config json.RawMessage, data []byte) ([]byte, error) {
+	resp, err := c.client.Encrypt(context.Background(), &encryptionproto1.Encrypt_Request{Config: config, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (c *methodClient) decrypt(config json.RawMessage, data []byte) ([]byte, error) {
+	resp, err := c.client.Decrypt(context.Background(), &encryptionproto1.Decrypt_Request{Config: config, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// NewMethodDescriptor returns a descriptor for an encryption method implemented by the plugin executable at the given
+// path. The plugin is started when a configuration using the method is first loaded, after verifying that the
+// executable has the given SHA-256 checksum.
+func NewMethodDescriptor(id method.ID, path string, checksum []byte) method.Descriptor {
+	return newMethodDescriptor(id, func() (any, error) {
+		return startPlugin(path, checksum, MethodPluginName)
+	})
+}
+
+func newMethodDescriptor(id method.ID, start func() (any, error)) *methodDescriptor {
+	return &methodDescriptor{
+		id:   id,
+		conn: &pluginConn{start: start},
+	}
+}
+
+type methodDescriptor struct {
+	id   method.ID
+	conn *pluginConn
+}
+
+func (d *methodDescriptor) ID() method.ID {
+	return d.id
+}
+
+func (d *methodDescriptor) ConfigStruct() method.Config {
+	return &methodConfig{descriptor: d}
+}
+
+func (d *methodDescriptor) client() (*methodClient, error) {
+	raw, err := d.conn.get()
+	if err != nil {
+		return nil, err
+	}
+	client, ok := raw.(*methodClient)
+	if !ok {
+		return nil, fmt.Errorf("bug: unexpected method plugin client type %T", raw)
+	}
+	return client, nil
+}
+
+// methodConfig is the configuration of a method implemented by a plugin. Its schema is obtained from the plugin, so it
+// implements method.DynamicConfig.
+type methodConfig struct {
+	descriptor *methodDescriptor
+	value      cty.Value
+}
+
+func (c *methodConfig) ConfigSpec() (hcldec.Spec, error) {
+	client, err := c.descriptor.client()
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := client.getSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain the schema of the %s method: %w", c.descriptor.id, err)
+	}
+	return configSpec(attrs), nil
+}
+
+func (c *methodConfig) SetConfigValue(value cty.Value) {
+	c.value = value
+}
+
+func (c *methodConfig) Build() (method.Method, error) {
+	client, err := c.descriptor.client()
+	if err != nil {
+		return nil, &method.ErrInvalidConfiguration{Cause: err}
+	}
+	config, err := encodeConfig(c.value)
+	if err != nil {
+		return nil, &method.ErrInvalidConfiguration{Cause: err}
+	}
+	return &pluginMethod{client: client, config: config}, nil
+}
+
+// pluginMethod passes encryption and decryption requests on to a plugin.
+type pluginMethod struct {
+	client *methodClient
+	config json.RawMessage
+}
+
+func (m *pluginMethod) Encrypt(data []byte) ([]byte, error) {
+	result, err := m.client.encrypt(m.config, data)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+	return result, nil
+}
+
+func (m *pluginMethod) Decrypt(data []byte) ([]byte, error) {
+	result, err := m.client.decrypt(m.config, data)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/logging"
+)
+
+// pluginSet contains the plugins that may be dispensed by an encryption plugin executable.
+var pluginSet = plugin.PluginSet{
+	KeyProviderPluginName: &KeyProviderPlugin{},
+	MethodPluginName:      &MethodPlugin{},
+}
+
+// pluginConn lazily starts a plugin and dispenses the client for it. The plugin is started at most once, so that a
+// single plugin process serves all the configurations using it.
+type pluginConn struct {
+	start func() (any, error)
+
+	once   sync.Once
+	client any
+	err    error
+}
+
+func (c *pluginConn) get() (any, error) {
+	c.once.Do(func() {
+		c.client, c.err = c.start()
+	})
+	return c.client, c.err
+}
+
+// startPlugin starts the plugin executable at the given path and dispenses the named plugin from it. go-plugin refuses
+// to start the executable unless it has the given SHA-256 checksum. The process is managed by go-plugin and is stopped
+// by plugin.CleanupClients.
+func startPlugin(path string, checksum []byte, name string) (any, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         pluginSet,
+		Cmd:             exec.Command(path),
+		SecureConfig: &plugin.SecureConfig{
+			Checksum: checksum,
+			Hash:     sha256.New(),
+		},
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Managed:          true,
+		Logger:           logging.NewLogger("encryption-plugin"),
+		SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", path)),
+		SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", path)),
+	})
+	rpcClient, err := client.Client()
+	if errors.Is(err, plugin.ErrChecksumsDoNotMatch) {
+		client.Kill()
+		return nil, fmt.Errorf("the checksum of encryption plugin %s doesn't match the checksum declared in the CLI configuration, so it may have been modified or replaced", path)
+	}
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to start encryption plugin %s: %w", path, err)
+	}
+	raw, err := rpcClient.Dispense(name)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("encryption plugin %s does not provide a %s: %w", path, name, err)
+	}
+	return raw, nil
+}
+
+// encodeConfig encodes a decoded configuration as JSON for sending it to a plugin.
+func encodeConfig(value cty.Value) (json.RawMessage, error) {
+	if value == cty.NilVal || value.IsNull() {
+		return json.RawMessage("{}"), nil
+	}
+	value, _ = value.UnmarkDeep()
+	return ctyjson.Marshal(value, value.Type())
+}
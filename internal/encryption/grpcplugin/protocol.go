@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/encryption/grpcplugin/encryptionproto1"
+)
+
+const (
+	// ProtocolVersion is the version of the encryption plugin protocol. It is negotiated during the go-plugin
+	// handshake and matches the major version of the protocol buffers schema in encryptionproto1.
+	ProtocolVersion = 1
+
+	// KeyProviderPluginName is the go-plugin name under which key provider plugins are dispensed.
+	KeyProviderPluginName = "key_provider"
+	// MethodPluginName is the go-plugin name under which method plugins are dispensed.
+	MethodPluginName = "method"
+)
+
+// Handshake is the go-plugin handshake configuration shared by OpenTofu and encryption plugins.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   "TF_ENCRYPTION_PLUGIN_MAGIC_COOKIE",
+	MagicCookieValue: "1b1a4f6a5a37e4b2d3f02a5cbe4d8b0c0f3a7e9164d55c7a6e1c04ab8f1c8b1e",
+}
+
+// Attribute describes an attribute a plugin accepts in its key_provider or method block.
+type Attribute struct {
+	Name     string
+	Type     cty.Type
+	Required bool
+}
+
+// configSpec returns the specification for decoding a configuration body with the given attributes.
+func configSpec(attrs []Attribute) hcldec.Spec {
+	spec := make(hcldec.ObjectSpec, len(attrs))
+	for _, attr := range attrs {
+		spec[attr.Name] = &hcldec.AttrSpec{
+			Name:     attr.Name,
+			Type:     attr.Type,
+			Required: attr.Required,
+		}
+	}
+	return spec
+}
+
+func encodeSchema(attrs []Attribute) (*encryptionproto1.GetSchema_Response, error) {
+	resp := &encryptionproto1.GetSchema_Response{Attributes: make([]*encryptionproto1.Attribute, len(attrs))}
+	for i, attr := range attrs {
+		ty, err := attr.Type.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the type of attribute %q: %w", attr.Name, err)
+		}
+		resp.Attributes[i] = &encryptionproto1.Attribute{Name: attr.Name, Type: ty, Required: attr.Required}
+	}
+	return resp, nil
+}
+
+func decodeSchema(resp *encryptionproto1.GetSchema_Response) ([]Attribute, error) {
+	attrs := make([]Attribute, len(resp.Attributes))
+	for i, attr := range resp.Attributes {
+		var ty cty.Type
+		if err := ty.UnmarshalJSON(attr.Type); err != nil {
+			return nil, fmt.Errorf("invalid type for attribute %q: %w", attr.Name, err)
+		}
+		attrs[i] = Attribute{Name: attr.Name, Type: ty, Required: attr.Required}
+	}
+	return attrs, nil
+}
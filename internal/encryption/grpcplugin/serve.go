@@ -0,0 +1,34 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcplugin
+
+import (
+	"github.com/hashicorp/go-plugin"
+)
+
+// ServeKeyProvider serves a key provider plugin. It is meant to be called from the main function of the plugin
+// executable and does not return until OpenTofu stops the plugin.
+func ServeKeyProvider(impl KeyProvider) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: plugin.PluginSet{
+			KeyProviderPluginName: &KeyProviderPlugin{Impl: impl},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
+
+// ServeMethod serves an encryption method plugin. It is meant to be called from the main function of the plugin
+// executable and does not return until OpenTofu stops the plugin.
+func ServeMethod(impl Method) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: plugin.PluginSet{
+			MethodPluginName: &MethodPlugin{Impl: impl},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
@@ -6,12 +6,12 @@
 package encryption
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
@@ -110,21 +110,58 @@ func filterKeyProviderReferences(cfg *config.EncryptionConfig, deps []hcl.Traver
 	return keyProviderDeps, refs, diags
 }
 
+// deferPromptsKey is the context key marking a setup in which key providers that prompt the user must not be built yet.
+type deferPromptsKey struct{}
+
+// withDeferredPrompts returns a context in which setupKeyProvider stops with promptDeferredDiag instead of building a
+// key provider that prompts the user.
+func withDeferredPrompts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deferPromptsKey{}, true)
+}
+
+func promptsDeferred(ctx context.Context) bool {
+	deferred, _ := ctx.Value(deferPromptsKey{}).(bool)
+	return deferred
+}
+
+// promptDeferredDiag is returned by setupKeyProvider when it was asked not to build a key provider that prompts the
+// user. It is never shown to the user.
+var promptDeferredDiag = &hcl.Diagnostic{
+	Severity: hcl.DiagError,
+	Summary:  "Key provider prompt deferred",
+	Detail:   "The key provider prompts the user and is set up once it is used.",
+}
+
+// isPromptDeferred returns true if the diagnostics only contain promptDeferredDiag besides warnings.
+func isPromptDeferred(diags hcl.Diagnostics) bool {
+	found := false
+	for _, diag := range diags {
+		switch {
+		case diag == promptDeferredDiag:
+			found = true
+		case diag.Severity == hcl.DiagError:
+			return false
+		}
+	}
+	return found
+}
+
 // setupKeyProviders sets up the key providers for encryption. It returns a list of diagnostics if any of the key providers
-// are invalid.
-func setupKeyProviders(enc *config.EncryptionConfig, cfgs []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) (*hcl.EvalContext, hcl.Diagnostics) {
+// are invalid. Key providers with workspace_key_derivation set derive their keys from the given workspace, which is the
+// workspace of the state or plan being encrypted or decrypted.
+func setupKeyProviders(ctx context.Context, enc *config.EncryptionConfig, cfgs []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator, workspace string) (*hcl.EvalContext, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	kpData := make(valueMap)
 
 	for _, keyProviderConfig := range cfgs {
-		diags = diags.Extend(setupKeyProvider(enc, keyProviderConfig, kpData, nil, meta, reg, staticEval))
+		diags = diags.Extend(setupKeyProvider(ctx, enc, keyProviderConfig, kpData, nil, meta, reg, staticEval, workspace))
 	}
 
 	return kpData.hclEvalContext("key_provider"), diags
 }
 
-func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig, kpData valueMap, stack []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) hcl.Diagnostics {
+func setupKeyProvider(ctx context.Context, enc *config.EncryptionConfig, cfg config.KeyProviderConfig, kpData valueMap, stack []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator, workspace string) hcl.Diagnostics {
 	// Check if we have already setup this Descriptor (due to dependency loading)
 	// if we've already setup this key provider, then we don't need to do it again
 	// and we can return early
@@ -186,7 +223,7 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 	keyProviderConfig := keyProviderDescriptor.ConfigStruct()
 
 	// Locate all the dependencies
-	deps, varDiags := gohcl.VariablesInBody(cfg.Body, keyProviderConfig)
+	deps, varDiags := configVariables(cfg.Body, keyProviderConfig)
 	diags = diags.Extend(varDiags)
 	if diags.HasErrors() {
 		return diags
@@ -201,7 +238,7 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 
 	// Ensure all key provider dependencies have been initialized
 	for _, kp := range kpConfigs {
-		diags = diags.Extend(setupKeyProvider(enc, kp, kpData, stack, meta, reg, staticEval))
+		diags = diags.Extend(setupKeyProvider(ctx, enc, kp, kpData, stack, meta, reg, staticEval, workspace))
 	}
 	if diags.HasErrors() {
 		return diags
@@ -217,21 +254,30 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 		return diags
 	}
 
-	// gohcl does not handle marks, we need to remove the sensitive marks from any input variables
-	// We assume that the entire configuration in the encryption block should be treated as sensitive
+	// gohcl does not handle marks, we need to remove the sensitive and ephemeral marks from any input variables
+	// We assume that the entire configuration in the encryption block should be treated as sensitive, and it is
+	// never persisted, so it can also use ephemeral values
 	for key, sv := range evalCtx.Variables {
-		if marks.Contains(sv, marks.Sensitive) {
+		if marks.Contains(sv, marks.Sensitive) || marks.Contains(sv, marks.Ephemeral) {
 			evalCtx.Variables[key], _ = sv.UnmarkDeep()
 		}
 	}
 
 	// Initialize the Key Provider
-	decodeDiags := gohcl.DecodeBody(cfg.Body, evalCtx, keyProviderConfig)
+	decodeDiags := decodeConfig(cfg.Body, evalCtx, keyProviderConfig)
 	diags = diags.Extend(decodeDiags)
 	if diags.HasErrors() {
 		return diags
 	}
 
+	if addressable, ok := keyProviderConfig.(keyprovider.AddressableConfig); ok {
+		addressable.SetAddr(tmpMetaKey)
+	}
+
+	if prompting, ok := keyProviderConfig.(keyprovider.PromptingConfig); ok && prompting.Prompts() && promptsDeferred(ctx) {
+		return diags.Append(promptDeferredDiag)
+	}
+
 	// Build the Key Provider from the configuration
 	keyProvider, keyMetaIn, err := keyProviderConfig.Build()
 	if err != nil {
@@ -243,8 +289,17 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 	}
 
 	// Add the metadata
+	migrations := keyProviderMetaMigrations(keyProviderDescriptor)
 	if meta, ok := meta.input[metaKey]; ok {
-		err := json.Unmarshal(meta, keyMetaIn)
+		meta, err := decodeKeyProviderMeta(migrations, meta)
+		if err != nil {
+			return diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unable to decode encrypted metadata",
+				Detail:   fmt.Sprintf("metadata decoder for %s failed with error: %s", metaKey, err.Error()),
+			})
+		}
+		err = json.Unmarshal(meta, keyMetaIn)
 		if err != nil {
 			return diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -254,7 +309,16 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 		}
 	}
 
-	output, keyMetaOut, err := keyProvider.Provide(keyMetaIn)
+	var output keyprovider.Output
+	var keyMetaOut keyprovider.KeyMeta
+	err = traceKeyProvider(ctx, cfg, func(ctx context.Context) (err error) {
+		if contextKeyProvider, ok := keyProvider.(keyprovider.ContextKeyProvider); ok {
+			output, keyMetaOut, err = contextKeyProvider.ProvideContext(ctx, keyMetaIn)
+		} else {
+			output, keyMetaOut, err = keyProvider.Provide(keyMetaIn)
+		}
+		return err
+	})
 	if err != nil {
 		return diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -263,6 +327,17 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 		})
 	}
 
+	if cfg.WorkspaceKeyDerivation {
+		output, err = deriveWorkspaceKeys(output, workspace)
+		if err != nil {
+			return diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unable to derive workspace encryption key",
+				Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
+			})
+		}
+	}
+
 	if keyMetaOut != nil {
 		if _, ok := meta.output[metaKey]; ok {
 			return diags.Append(&hcl.Diagnostic{
@@ -271,8 +346,10 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 				Detail:   fmt.Sprintf("The metadata key %s is duplicated across multiple key providers for the same method; use the encrypted_metadata_alias option to specify unique metadata keys for each key provider in an encryption method", metaKey),
 			})
 		}
-		meta.output[metaKey], err = json.Marshal(keyMetaOut)
-
+		encoded, err := json.Marshal(keyMetaOut)
+		if err == nil {
+			meta.output[metaKey], err = encodeKeyProviderMeta(migrations, encoded)
+		}
 		if err != nil {
 			return diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
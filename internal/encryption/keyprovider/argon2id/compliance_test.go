@@ -0,0 +1,307 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+func TestCompliance(t *testing.T) {
+	validConfig := &Config{
+		randomSource: rand.Reader,
+		Passphrase:   "Hello world! 123",
+		KeyLength:    DefaultKeyLength,
+		Iterations:   DefaultIterations,
+		Memory:       DefaultMemory,
+		Parallelism:  DefaultParallelism,
+		SaltLength:   DefaultSaltLength,
+	}
+	validMeta := func(modify func(meta *Metadata)) *Metadata {
+		meta := &Metadata{
+			Salt:        []byte("Hello world!"),
+			Iterations:  DefaultIterations,
+			Memory:      DefaultMemory,
+			Parallelism: DefaultParallelism,
+			KeyLength:   32,
+		}
+		modify(meta)
+		return meta
+	}
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *Metadata, *argon2idKeyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *argon2idKeyProvider]{
+				"invalid": {
+					HCL: `key_provider "argon2id" "foo" {
+    chain = {
+        encryption_key = "Hello world! 123"
+    }
+}`,
+					ValidHCL: false,
+				},
+				"empty": {
+					HCL: `key_provider "argon2id" "foo" {
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+					Validate:   nil,
+				},
+				"basic": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *argon2idKeyProvider) error {
+						if config.Passphrase != "Hello world! 123" {
+							return fmt.Errorf("invalid passphrase after HCL parsing")
+						}
+						if keyProvider.Passphrase != "Hello world! 123" {
+							return fmt.Errorf("invalid passphrase in key provider")
+						}
+						return nil
+					},
+				},
+				"both-passphrase-and-chain": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    chain = {
+        encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]
+    }
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"chain": {
+					HCL: `key_provider "argon2id" "foo" {
+    chain = {
+        encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]
+    }
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *argon2idKeyProvider) error {
+						if config.Chain == nil {
+							return fmt.Errorf("no chain after parsing")
+						}
+						if !bytes.Equal(config.Chain.EncryptionKey, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}) {
+							return fmt.Errorf("incorrect encryption key")
+						}
+						return nil
+					},
+				},
+				"extended": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    key_length = %d
+    iterations = %d
+    memory = %d
+    parallelism = %d
+    salt_length = %d
+}`, DefaultKeyLength+1, DefaultIterations+1, DefaultMemory+1, DefaultParallelism+1, DefaultSaltLength+1),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *argon2idKeyProvider) error {
+						if config.KeyLength != DefaultKeyLength+1 {
+							return fmt.Errorf("incorrect key length after HCL parsing: %d", config.KeyLength)
+						}
+						if config.Iterations != DefaultIterations+1 {
+							return fmt.Errorf("incorrect iterations after HCL parsing: %d", config.Iterations)
+						}
+						if config.Memory != DefaultMemory+1 {
+							return fmt.Errorf("incorrect memory after HCL parsing: %d", config.Memory)
+						}
+						if config.Parallelism != DefaultParallelism+1 {
+							return fmt.Errorf("incorrect parallelism after HCL parsing: %d", config.Parallelism)
+						}
+						if config.SaltLength != DefaultSaltLength+1 {
+							return fmt.Errorf("incorrect salt length after HCL parsing: %d", config.SaltLength)
+						}
+						return nil
+					},
+				},
+				"short-passphrase": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 12"
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-little-memory": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    memory = %d
+}`, MinimumMemory-1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-much-memory": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    memory = %d
+}`, MaximumMemory+1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"zero-iterations": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    iterations = 0
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-much-parallelism": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    parallelism = %d
+}`, MaximumParallelism+1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-long-key": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    key_length = %d
+}`, MaximumKeyLength+1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-long-salt": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    salt_length = %d
+}`, MaximumSaltLength+1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *argon2idKeyProvider]{},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *Metadata]{
+				"not-present-salt": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Salt = nil }),
+					IsPresent:   false,
+				},
+				"not-present-iterations": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Iterations = 0 }),
+					IsPresent:   false,
+				},
+				"not-present-memory": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Memory = 0 }),
+					IsPresent:   false,
+				},
+				"not-present-parallelism": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Parallelism = 0 }),
+					IsPresent:   false,
+				},
+				"not-present-key-length": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.KeyLength = 0 }),
+					IsPresent:   false,
+				},
+				"present-valid": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) {}),
+					IsPresent:   true,
+					IsValid:     true,
+				},
+				"present-valid-low-memory": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Memory = MinimumMemory - 1 }),
+					IsPresent:   true,
+					IsValid:     true,
+				},
+				"invalid-iterations": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Iterations = MaximumIterations + 1 }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"invalid-memory": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Memory = MaximumMemory + 1 }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"invalid-parallelism": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Parallelism = -1 }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"invalid-key-length": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.KeyLength = -1 }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"too-long-key-length": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.KeyLength = MaximumKeyLength + 1 }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"too-long-salt": {
+					ValidConfig: validConfig,
+					Meta:        validMeta(func(meta *Metadata) { meta.Salt = make([]byte, MaximumSaltLength+1) }),
+					IsPresent:   true,
+					IsValid:     false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *Metadata]{
+				ValidConfig: &Config{
+					randomSource: &testRandomSource{t: t},
+					Passphrase:   "Hello world! 123",
+					KeyLength:    DefaultKeyLength,
+					Iterations:   DefaultIterations,
+					Memory:       DefaultMemory,
+					Parallelism:  DefaultParallelism,
+					SaltLength:   DefaultSaltLength,
+				},
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: []byte{135, 195, 201, 55, 146, 96, 150, 61, 107, 178, 189, 252, 35, 233, 168, 6, 240, 169, 158, 122, 184, 215, 156, 233, 246, 74, 181, 37, 222, 226, 241, 116},
+					DecryptionKey: []byte{135, 195, 201, 55, 146, 96, 150, 61, 107, 178, 189, 252, 35, 233, 168, 6, 240, 169, 158, 122, 184, 215, 156, 233, 246, 74, 181, 37, 222, 226, 241, 116},
+				},
+				ValidateKeys: nil,
+				ValidateMetadata: func(meta *Metadata) error {
+					if !meta.isPresent() {
+						return fmt.Errorf("output metadata is not present")
+					}
+					if err := meta.validate(); err != nil {
+						return err
+					}
+					if meta.KeyLength != DefaultKeyLength {
+						return fmt.Errorf("incorrect output metadata key length: %d", meta.KeyLength)
+					}
+					if meta.Iterations != DefaultIterations {
+						return fmt.Errorf("incorrect output metadata iterations: %d", meta.Iterations)
+					}
+					if meta.Memory != DefaultMemory {
+						return fmt.Errorf("incorrect output metadata memory: %d", meta.Memory)
+					}
+					if meta.Parallelism != DefaultParallelism {
+						return fmt.Errorf("incorrect output metadata parallelism: %d", meta.Parallelism)
+					}
+					if len(meta.Salt) != DefaultSaltLength {
+						return fmt.Errorf("incorrect output salt length: %d", len(meta.Salt))
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type Config struct {
+	// Set by the descriptor.
+	randomSource io.Reader
+	prompter     keyprovider.Prompter
+
+	// Set by the encryption setup.
+	addr keyprovider.Addr
+
+	// Passprase is a single passphrase to use for encryption. This is mutually exclusive with Chain.
+	Passphrase string `hcl:"passphrase,optional"`
+	// Chain are two separate passphrases supplied from a chained provider. This is mutually exclusive with
+	// Passphrase.
+	Chain *keyprovider.Output `hcl:"chain,optional"`
+	// Prompt asks the user for the passphrase interactively. This is mutually exclusive with Passphrase and Chain.
+	Prompt     bool `hcl:"prompt,optional"`
+	KeyLength  int  `hcl:"key_length,optional"`
+	Iterations int  `hcl:"iterations,optional"`
+	// Memory is the amount of memory to use in KiB.
+	Memory      int `hcl:"memory,optional"`
+	Parallelism int `hcl:"parallelism,optional"`
+	SaltLength  int `hcl:"salt_length,optional"`
+}
+
+// WithPassphrase adds the passphrase and returns the same config for chaining.
+func (c *Config) WithPassphrase(passphrase string) *Config {
+	c.Passphrase = passphrase
+	return c
+}
+
+// WithChain adds a separate encryption/decryption key chained from an upstream keyprovider.
+func (c *Config) WithChain(chain *keyprovider.Output) *Config {
+	c.Chain = chain
+	return c
+}
+
+// WithPrompt enables interactive passphrase prompting and returns the same config for chaining.
+func (c *Config) WithPrompt(prompt bool) *Config {
+	c.Prompt = prompt
+	return c
+}
+
+// SetAddr records the address of the key provider block this configuration was decoded from. It is used to identify
+// the key provider when prompting for a passphrase.
+func (c *Config) SetAddr(addr keyprovider.Addr) {
+	c.addr = addr
+}
+
+// Prompts returns true if the passphrase is asked for interactively.
+func (c *Config) Prompts() bool {
+	return c.Prompt
+}
+
+// WithKeyLength sets the key length and returns the same config for chaining
+func (c *Config) WithKeyLength(length int) *Config {
+	c.KeyLength = length
+	return c
+}
+
+// WithIterations sets the iterations and returns the same config for chaining
+func (c *Config) WithIterations(iterations int) *Config {
+	c.Iterations = iterations
+	return c
+}
+
+// WithMemory sets the memory in KiB and returns the same config for chaining
+func (c *Config) WithMemory(memory int) *Config {
+	c.Memory = memory
+	return c
+}
+
+// WithParallelism sets the parallelism and returns the same config for chaining
+func (c *Config) WithParallelism(parallelism int) *Config {
+	c.Parallelism = parallelism
+	return c
+}
+
+// WithSaltLength sets the salt length and returns the same config for chaining
+func (c *Config) WithSaltLength(length int) *Config {
+	c.SaltLength = length
+	return c
+}
+
+func (c *Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.randomSource == nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing randomness source (please don't initialize the Config struct directly, use the descriptor)",
+		}
+	}
+
+	if c.Prompt {
+		if c.Passphrase != "" || c.Chain != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "prompt is mutually exclusive with passphrase and chain",
+			}
+		}
+		if c.prompter == nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "interactive passphrase prompting is not available in this context",
+			}
+		}
+	} else if c.Passphrase == "" && c.Chain == nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no passphrase provided and no chained provider defined",
+		}
+	}
+	if c.Passphrase != "" && c.Chain != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "passphrase and chain are mutually exclusive",
+		}
+	}
+	if c.Chain != nil {
+		if c.Chain.EncryptionKey == nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "no encryption key provided from upstream key provider",
+			}
+		}
+		if len(c.Chain.EncryptionKey) < MinimumPassphraseLength {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("upstream key provider supplied an encryption key that is too short (minimum %d characters)", MinimumPassphraseLength),
+			}
+		}
+		if c.Chain.DecryptionKey != nil {
+			if len(c.Chain.DecryptionKey) < MinimumPassphraseLength {
+				return nil, nil, &keyprovider.ErrInvalidConfiguration{
+					Message: fmt.Sprintf("upstream key provider supplied an decryption key that is too short (minimum %d characters)", MinimumPassphraseLength),
+				}
+			}
+		}
+	}
+	if c.Passphrase != "" && len(c.Passphrase) < MinimumPassphraseLength {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("passphrase is too short (minimum %d characters)", MinimumPassphraseLength),
+		}
+	}
+
+	if c.KeyLength <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the key length must be larger than zero",
+		}
+	}
+	if c.KeyLength > MaximumKeyLength {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the key length must not be larger than %d", MaximumKeyLength),
+		}
+	}
+
+	if c.Iterations <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the number of iterations must be larger than zero",
+		}
+	}
+	if c.Iterations > MaximumIterations {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the number of iterations must not be larger than %d", MaximumIterations),
+		}
+	}
+
+	if c.Memory < MinimumMemory {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the amount of memory is dangerously low (<%d KiB), refusing to generate key", MinimumMemory),
+		}
+	}
+	if c.Memory > MaximumMemory {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the amount of memory must not be larger than %d KiB", MaximumMemory),
+		}
+	}
+
+	if c.Parallelism <= 0 || c.Parallelism > MaximumParallelism {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the parallelism must be between 1 and %d", MaximumParallelism),
+		}
+	}
+
+	if c.SaltLength <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the salt length must be larger than zero",
+		}
+	}
+	if c.SaltLength > MaximumSaltLength {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the salt length must not be larger than %d", MaximumSaltLength),
+		}
+	}
+
+	return &argon2idKeyProvider{*c}, new(Metadata), nil
+}
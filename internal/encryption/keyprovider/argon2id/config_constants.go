@@ -0,0 +1,40 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+const (
+	// DefaultSaltLength specifies the default salt length in bytes.
+	DefaultSaltLength int = 32
+	// DefaultIterations, DefaultMemory and DefaultParallelism are set to the second recommended option in RFC 9106
+	// for environments where the first option (2 GiB of memory) is not feasible:
+	// https://www.rfc-editor.org/rfc/rfc9106.html#section-4
+	DefaultIterations int = 3
+	// DefaultMemory is the default amount of memory to use in KiB (64 MiB).
+	DefaultMemory int = 64 * 1024
+	// DefaultParallelism is the default number of threads to use.
+	DefaultParallelism int = 4
+	// DefaultKeyLength is the default output length. We set it to the key length required by AES-GCM 256
+	DefaultKeyLength int = 32
+)
+
+const (
+	MinimumPassphraseLength int = 16
+	// MinimumMemory is the minimum amount of memory in KiB the configuration may specify. It matches the lowest
+	// setting recommended here:
+	// https://cheatsheetseries.owasp.org/cheatsheets/Password_Storage_Cheat_Sheet.html#argon2id
+	MinimumMemory int = 19 * 1024
+	// MaximumMemory is the maximum amount of memory in KiB (4 GiB) the key provider uses. The limit also applies to
+	// the metadata, which is not authenticated, so that it cannot be used to exhaust the memory of the machine.
+	MaximumMemory int = 4 * 1024 * 1024
+	// MaximumIterations limits the number of iterations read from the metadata for the same reason.
+	MaximumIterations int = 100
+	// MaximumParallelism is the largest parallelism Argon2 supports.
+	MaximumParallelism int = 255
+	// MaximumKeyLength and MaximumSaltLength limit the key and salt lengths in bytes read from the metadata, so that
+	// they cannot be used to allocate arbitrary amounts of memory either.
+	MaximumKeyLength  int = 1024
+	MaximumSaltLength int = 1024
+)
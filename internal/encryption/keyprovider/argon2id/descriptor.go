@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// New creates a new Argon2id key provider descriptor.
+func New() Descriptor {
+	return &descriptor{
+		randomSource: rand.Reader,
+	}
+}
+
+// NewWithPrompter creates a new Argon2id key provider descriptor that uses the given prompter to ask for the
+// passphrase when the configuration sets prompt = true.
+func NewWithPrompter(prompter keyprovider.Prompter) Descriptor {
+	return &descriptor{
+		randomSource: rand.Reader,
+		prompter:     prompter,
+	}
+}
+
+// Descriptor provides TypedConfig on top of keyprovider.Descriptor.
+type Descriptor interface {
+	keyprovider.Descriptor
+
+	TypedConfig() *Config
+}
+
+type descriptor struct {
+	randomSource io.Reader
+	prompter     keyprovider.Prompter
+}
+
+func (f descriptor) ID() keyprovider.ID {
+	return "argon2id"
+}
+
+func (f descriptor) TypedConfig() *Config {
+	return &Config{
+		randomSource: f.randomSource,
+		prompter:     f.prompter,
+		Passphrase:   "",
+		Chain:        nil,
+		KeyLength:    DefaultKeyLength,
+		Iterations:   DefaultIterations,
+		Memory:       DefaultMemory,
+		Parallelism:  DefaultParallelism,
+		SaltLength:   DefaultSaltLength,
+	}
+}
+
+func (f descriptor) ConfigStruct() keyprovider.Config {
+	return f.TypedConfig()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// Metadata describes the metadata to be stored alongside the encrypted form.
+type Metadata struct {
+	Salt        []byte `json:"salt"`
+	Iterations  int    `json:"iterations"`
+	Memory      int    `json:"memory"`
+	Parallelism int    `json:"parallelism"`
+	KeyLength   int    `json:"key_length"`
+}
+
+func (m Metadata) isPresent() bool {
+	return len(m.Salt) != 0 && m.Iterations != 0 && m.Memory != 0 && m.Parallelism != 0 && m.KeyLength != 0
+}
+
+func (m Metadata) validate() error {
+	if m.Iterations < 0 || m.Iterations > MaximumIterations {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid number of iterations (%d)", m.Iterations),
+		}
+	}
+	if m.Memory < 0 || m.Memory > MaximumMemory {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid amount of memory (%d KiB)", m.Memory),
+		}
+	}
+	if m.Parallelism < 0 || m.Parallelism > MaximumParallelism {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid parallelism (%d)", m.Parallelism),
+		}
+	}
+	if m.KeyLength < 0 || m.KeyLength > MaximumKeyLength {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid key length (%d)", m.KeyLength),
+		}
+	}
+	if len(m.Salt) > MaximumSaltLength {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid salt length (%d)", len(m.Salt)),
+		}
+	}
+	return nil
+}
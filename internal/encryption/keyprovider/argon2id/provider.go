@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package argon2id contains a key provider that takes a passphrase and emits an Argon2id hash of the configured
+// length.
+package argon2id
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type argon2idKeyProvider struct {
+	Config
+}
+
+func (p argon2idKeyProvider) generateMetadata() (*Metadata, error) {
+	// Build outMeta based on current configuration
+	outMeta := &Metadata{
+		Iterations:  p.Iterations,
+		Memory:      p.Memory,
+		Parallelism: p.Parallelism,
+		Salt:        make([]byte, p.SaltLength),
+		KeyLength:   p.KeyLength,
+	}
+	// Generate new salt
+	if _, err := io.ReadFull(p.randomSource, outMeta.Salt); err != nil {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("failed to obtain %d bytes of random data", p.SaltLength),
+			Cause:   err,
+		}
+	}
+	return outMeta, nil
+}
+
+func (p argon2idKeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*Metadata)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: incorrect metadata type of %T provided", rawMeta),
+		}
+	}
+
+	outMeta, err := p.generateMetadata()
+	if err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+
+	passphrase := p.Passphrase
+	if p.Prompt {
+		// Without metadata there is no existing data the passphrase could be validated against, so we ask for
+		// confirmation to avoid encrypting with a mistyped passphrase.
+		passphrase, err = keyprovider.PromptPassphrase(p.prompter, p.addr, !inMeta.isPresent(), MinimumPassphraseLength)
+		if err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+	}
+
+	var decryptionKey []byte
+	if inMeta.isPresent() {
+		if err := inMeta.validate(); err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+		var decryptionPassphrase []byte
+		if p.Chain != nil {
+			decryptionPassphrase = p.Chain.DecryptionKey
+		} else {
+			decryptionPassphrase = []byte(passphrase)
+		}
+		decryptionKey = deriveKey(decryptionPassphrase, inMeta)
+	}
+
+	var encryptionPassphrase []byte
+	if p.Chain != nil {
+		encryptionPassphrase = p.Chain.EncryptionKey
+	} else {
+		encryptionPassphrase = []byte(passphrase)
+	}
+	return keyprovider.Output{
+		EncryptionKey: deriveKey(encryptionPassphrase, outMeta),
+		DecryptionKey: decryptionKey,
+	}, outMeta, nil
+}
+
+// deriveKey derives the key from the passphrase with the parameters stored in the metadata. The metadata must be
+// validated before calling this function.
+func deriveKey(passphrase []byte, meta *Metadata) []byte {
+	return argon2.IDKey(
+		passphrase,
+		meta.Salt,
+		uint32(meta.Iterations),
+		uint32(meta.Memory),
+		uint8(meta.Parallelism),
+		uint32(meta.KeyLength),
+	)
+}
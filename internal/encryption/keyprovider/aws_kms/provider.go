@@ -34,6 +34,15 @@ type keyProvider struct {
 }
 
 func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	return p.provide(p.ctx, rawMeta)
+}
+
+func (p keyProvider) ProvideContext(ctx context.Context, rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	ctx, _ = attachLoggerToContext(ctx)
+	return p.provide(ctx, rawMeta)
+}
+
+func (p keyProvider) provide(ctx context.Context, rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
 	if rawMeta == nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
 	}
@@ -48,7 +57,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 	// as validation has happened in the config, we can safely cast here and not worry about the cast failing
 	spec := types.DataKeySpec(p.KeySpec)
 
-	generatedKeyData, err := p.svc.GenerateDataKey(p.ctx, &kms.GenerateDataKeyInput{
+	generatedKeyData, err := p.svc.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
 		KeyId:   aws.String(p.KMSKeyID),
 		KeySpec: spec,
 	})
@@ -69,7 +78,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 
 	if inMeta.isPresent() {
 		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey
-		decryptedKeyData, decryptErr := p.svc.Decrypt(p.ctx, &kms.DecryptInput{
+		decryptedKeyData, decryptErr := p.svc.Decrypt(ctx, &kms.DecryptInput{
 			KeyId:          aws.String(p.KMSKeyID),
 			CiphertextBlob: inMeta.CiphertextBlob,
 		})
@@ -5,6 +5,11 @@
 
 package keyprovider
 
+import (
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+)
+
 // Config is a struct annotated with HCL (and preferably JSON) tags that OpenTofu reads the user-provided configuration
 // into. The Build function assembles the configuration into a usable key provider.
 type Config interface {
@@ -14,3 +19,16 @@ type Config interface {
 	// If a key provider does not need metadata, it may return nil.
 	Build() (KeyProvider, KeyMeta, error)
 }
+
+// DynamicConfig is implemented by key provider configurations whose schema is only known at runtime, such as the
+// configurations of key providers supplied by plugins. Instead of decoding the configuration based on struct tags,
+// the encryption setup decodes the configuration body according to ConfigSpec and passes the result to
+// SetConfigValue before calling Build.
+type DynamicConfig interface {
+	Config
+
+	// ConfigSpec returns the specification used to decode the configuration body.
+	ConfigSpec() (hcldec.Spec, error)
+	// SetConfigValue receives the decoded configuration.
+	SetConfigValue(value cty.Value)
+}
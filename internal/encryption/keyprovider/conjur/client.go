@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package conjur
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/version"
+)
+
+// maxResponseSize limits how much of a Conjur response we read. Access tokens and secrets are small, so anything
+// larger is not a valid response.
+const maxResponseSize = 1 << 20
+
+// service implements the parts of the Conjur REST API the key provider needs: authenticating as a host and
+// retrieving the value of a variable.
+type service struct {
+	httpClient   *http.Client
+	applianceURL string
+	account      string
+	auth         authenticator
+}
+
+// authenticator builds the request to exchange the configured credentials for a short-lived Conjur access token.
+type authenticator interface {
+	request(ctx context.Context, applianceURL string, account string) (*http.Request, error)
+}
+
+// apiKeyAuth authenticates a host identity with its API key using the default authenticator.
+type apiKeyAuth struct {
+	login  string
+	apiKey string
+}
+
+func (a apiKeyAuth) request(ctx context.Context, applianceURL string, account string) (*http.Request, error) {
+	u := fmt.Sprintf("%s/authn/%s/%s/authenticate", applianceURL, url.PathEscape(account), url.PathEscape(a.login))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(a.apiKey))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	return req, nil
+}
+
+// jwtAuth authenticates with a JWT using the authn-jwt authenticator. If no host is set, Conjur identifies the host
+// from the claims in the token.
+type jwtAuth struct {
+	serviceID string
+	host      string
+	token     string
+}
+
+func (a jwtAuth) request(ctx context.Context, applianceURL string, account string) (*http.Request, error) {
+	u := fmt.Sprintf("%s/authn-jwt/%s/%s", applianceURL, url.PathEscape(a.serviceID), url.PathEscape(account))
+	if a.host != "" {
+		u += "/" + url.PathEscape(a.host)
+	}
+	u += "/authenticate"
+	body := url.Values{"jwt": []string{a.token}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+func (s service) authenticate(ctx context.Context) (string, error) {
+	req, err := s.auth.request(ctx, s.applianceURL, s.account)
+	if err != nil {
+		return "", fmt.Errorf("error creating Conjur authentication request: %w", err)
+	}
+	token, err := s.do(req)
+	if err != nil {
+		return "", fmt.Errorf("error authenticating to Conjur: %w", err)
+	}
+	return string(token), nil
+}
+
+func (s service) retrieveSecret(ctx context.Context, variableID string) ([]byte, error) {
+	accessToken, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("%s/secrets/%s/variable/%s", s.applianceURL, url.PathEscape(s.account), url.PathEscape(variableID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Conjur secret request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%q", base64.StdEncoding.EncodeToString([]byte(accessToken))))
+
+	secret, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving variable %q from Conjur: %w", variableID, err)
+	}
+	return secret, nil
+}
+
+func (s service) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("User-Agent", httpclient.OpenTofuUserAgent(version.Version))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Conjur doesn't include details in error responses, and the body may echo parts of the request, so we only
+		// report the status.
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return body, nil
+}
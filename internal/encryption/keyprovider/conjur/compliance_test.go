@@ -0,0 +1,250 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package conjur
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+const (
+	testAccount    = "myorg"
+	testLogin      = "host/tofu/app"
+	testAPIKey     = "test-api-key"
+	testServiceID  = "k8s"
+	testJWT        = "header.payload.signature"
+	testVariableID = "tofu/state-key"
+	testSecret     = "0123456789abcdef0123456789abcdef"
+	testToken      = `{"protected":"test","payload":"test","signature":"test"}`
+)
+
+// newTestServer starts a fake Conjur appliance that implements the authentication and secret retrieval endpoints.
+func newTestServer(t *testing.T) *httptest.Server {
+	// Conjur identifiers are path-escaped, so the routing uses the escaped path.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var authorized bool
+		var response string
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "POST /authn/myorg/host%2Ftofu%2Fapp/authenticate":
+			body, _ := io.ReadAll(r.Body)
+			authorized = string(body) == testAPIKey
+			response = testToken
+		case "POST /authn-jwt/k8s/myorg/authenticate":
+			authorized = r.PostFormValue("jwt") == testJWT
+			response = testToken
+		case "GET /secrets/myorg/variable/tofu%2Fstate-key":
+			authorized = r.Header.Get("Authorization") == fmt.Sprintf("Token token=%q", base64.StdEncoding.EncodeToString([]byte(testToken)))
+			response = testSecret
+		default:
+			t.Errorf("unexpected request to %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !authorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func clearEnv(t *testing.T) {
+	for _, name := range []string{envApplianceURL, envAccount, envCertFile, envLogin, envAPIKey, envJWTServiceID, envJWTHost, envJWTPath} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestKeyProvider(t *testing.T) {
+	clearEnv(t)
+	server := newTestServer(t)
+
+	jwtPath := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(jwtPath, []byte(testJWT+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	validConfig := &Config{
+		ApplianceURL: server.URL,
+		Account:      testAccount,
+		Login:        testLogin,
+		APIKey:       testAPIKey,
+		VariableID:   testVariableID,
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success-api-key": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							appliance_url = %q
+							account = %q
+							login = %q
+							api_key = %q
+							variable_id = %q
+						}`, server.URL, testAccount, testLogin, testAPIKey, testVariableID),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"success-jwt": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							appliance_url = %q
+							account = %q
+							authn_jwt_service_id = %q
+							jwt_token_path = %q
+							variable_id = %q
+						}`, server.URL, testAccount, testServiceID, jwtPath, testVariableID),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, p *keyProvider) error {
+						auth, ok := p.svc.auth.(jwtAuth)
+						if !ok {
+							return fmt.Errorf("expected JWT authentication, got %T", p.svc.auth)
+						}
+						if auth.token != testJWT {
+							return fmt.Errorf("incorrect JWT read from file: %q", auth.token)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "conjur" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"no-credentials": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							appliance_url = %q
+							account = %q
+							variable_id = %q
+						}`, server.URL, testAccount, testVariableID),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"mixed-credentials": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							appliance_url = %q
+							account = %q
+							api_key = %q
+							authn_jwt_service_id = %q
+							jwt = %q
+							variable_id = %q
+						}`, server.URL, testAccount, testAPIKey, testServiceID, testJWT, testVariableID),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							appliance_url = %q
+							account = %q
+							login = %q
+							api_key = %q
+							variable_id = %q
+							key_length = 17
+						}`, server.URL, testAccount, testLogin, testAPIKey, testVariableID),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "conjur" "foo" {
+							variable_id = %q
+							unknown_property = "foo"
+						}`, testVariableID),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success-default-values": {
+					Config:     validConfig,
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.variableID != testVariableID {
+							return fmt.Errorf("variable IDs don't match: %v and %v", p.variableID, testVariableID)
+						}
+						if p.keyLength != 32 {
+							return fmt.Errorf("invalid default key length: %v", p.keyLength)
+						}
+						if _, ok := p.svc.auth.(apiKeyAuth); !ok {
+							return fmt.Errorf("expected API key authentication, got %T", p.svc.auth)
+						}
+						return nil
+					},
+				},
+				"no-appliance-url": {
+					Config: &Config{
+						Account:    testAccount,
+						Login:      testLogin,
+						APIKey:     testAPIKey,
+						VariableID: testVariableID,
+					},
+					ValidBuild: false,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: validConfig,
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) != 32 {
+						return fmt.Errorf("incorrect decryption key length: %d", len(dec))
+					}
+					if len(enc) != 32 {
+						return fmt.Errorf("incorrect encryption key length: %d", len(enc))
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Salt) != saltLength {
+						return fmt.Errorf("incorrect salt length: %d", len(meta.Salt))
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestKeyProvider_unauthorized(t *testing.T) {
+	clearEnv(t)
+	server := newTestServer(t)
+
+	p, meta, err := (&Config{
+		ApplianceURL: server.URL,
+		Account:      testAccount,
+		Login:        testLogin,
+		APIKey:       "wrong",
+		VariableID:   testVariableID,
+	}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.Provide(meta); err == nil {
+		t.Fatal("expected an error with invalid credentials")
+	}
+}
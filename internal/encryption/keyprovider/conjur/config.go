@@ -0,0 +1,205 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package conjur
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type Config struct {
+	ApplianceURL string `hcl:"appliance_url,optional"`
+	Account      string `hcl:"account,optional"`
+	SSLCertPath  string `hcl:"ssl_cert_path,optional"`
+
+	Login  string `hcl:"login,optional"`
+	APIKey string `hcl:"api_key,optional"`
+
+	JWTServiceID string `hcl:"authn_jwt_service_id,optional"`
+	JWTHost      string `hcl:"authn_jwt_host_id,optional"`
+	JWT          string `hcl:"jwt,optional"`
+	JWTPath      string `hcl:"jwt_token_path,optional"`
+
+	VariableID string        `hcl:"variable_id"`
+	KeyLength  DataKeyLength `hcl:"key_length,optional"`
+}
+
+const defaultDataKeyLength DataKeyLength = 32
+
+// The environment variables are the same ones the Conjur CLI and SDKs read, so that existing workload setups work
+// without repeating the settings in the configuration.
+const (
+	envApplianceURL = "CONJUR_APPLIANCE_URL"
+	envAccount      = "CONJUR_ACCOUNT"
+	envCertFile     = "CONJUR_CERT_FILE"
+	envLogin        = "CONJUR_AUTHN_LOGIN"
+	envAPIKey       = "CONJUR_AUTHN_API_KEY"
+	envJWTServiceID = "CONJUR_AUTHN_JWT_SERVICE_ID"
+	envJWTHost      = "CONJUR_AUTHN_JWT_HOST_ID"
+	envJWTPath      = "JWT_TOKEN_PATH"
+)
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.VariableID == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no variable_id found",
+		}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultDataKeyLength
+	}
+	if err := c.KeyLength.Validate(); err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	// Values from HCL supersede the environment variables.
+	c.ApplianceURL = withEnvDefault(c.ApplianceURL, envApplianceURL)
+	c.Account = withEnvDefault(c.Account, envAccount)
+	c.SSLCertPath = withEnvDefault(c.SSLCertPath, envCertFile)
+
+	if c.ApplianceURL == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("no appliance_url found, please set it in the configuration or the %s environment variable", envApplianceURL),
+		}
+	}
+	if _, err := url.ParseRequestURI(c.ApplianceURL); err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "invalid appliance_url",
+			Cause:   err,
+		}
+	}
+	if c.Account == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("no account found, please set it in the configuration or the %s environment variable", envAccount),
+		}
+	}
+
+	auth, err := c.buildAuthenticator()
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	httpClient, err := c.buildHTTPClient()
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	return &keyProvider{
+		svc: service{
+			httpClient:   httpClient,
+			applianceURL: strings.TrimRight(c.ApplianceURL, "/"),
+			account:      c.Account,
+			auth:         auth,
+		},
+		variableID: c.VariableID,
+		keyLength:  c.KeyLength,
+	}, new(keyMeta), nil
+}
+
+// buildAuthenticator selects JWT authentication if a JWT authenticator service is configured, and host API key
+// authentication otherwise.
+func (c Config) buildAuthenticator() (authenticator, error) {
+	if c.JWTServiceID != "" || c.JWT != "" || c.JWTPath != "" || (c.Login == "" && c.APIKey == "" && os.Getenv(envJWTServiceID) != "") {
+		if c.Login != "" || c.APIKey != "" {
+			return nil, errors.New("login and api_key cannot be used together with JWT authentication")
+		}
+		serviceID := withEnvDefault(c.JWTServiceID, envJWTServiceID)
+		if serviceID == "" {
+			return nil, fmt.Errorf("no authn_jwt_service_id found, please set it in the configuration or the %s environment variable", envJWTServiceID)
+		}
+		if c.JWT != "" && c.JWTPath != "" {
+			return nil, errors.New("only one of jwt and jwt_token_path can be set")
+		}
+		token := c.JWT
+		if token == "" {
+			tokenPath := withEnvDefault(c.JWTPath, envJWTPath)
+			if tokenPath == "" {
+				return nil, fmt.Errorf("no jwt or jwt_token_path found, please set one of them in the configuration or the %s environment variable", envJWTPath)
+			}
+			raw, err := os.ReadFile(tokenPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT from %s: %w", tokenPath, err)
+			}
+			token = strings.TrimSpace(string(raw))
+		}
+		return jwtAuth{
+			serviceID: serviceID,
+			host:      withEnvDefault(c.JWTHost, envJWTHost),
+			token:     token,
+		}, nil
+	}
+
+	login := withEnvDefault(c.Login, envLogin)
+	apiKey := withEnvDefault(c.APIKey, envAPIKey)
+	if login == "" || apiKey == "" {
+		return nil, fmt.Errorf("no credentials found, please set login and api_key (or the %s and %s environment variables) or configure JWT authentication", envLogin, envAPIKey)
+	}
+	return apiKeyAuth{
+		login:  login,
+		apiKey: apiKey,
+	}, nil
+}
+
+// buildHTTPClient returns an HTTP client that trusts the certificate in SSLCertPath in addition to the system
+// roots, since Conjur appliances commonly use certificates issued by an internal CA.
+func (c Config) buildHTTPClient() (*http.Client, error) {
+	client := cleanhttp.DefaultPooledClient()
+	if c.SSLCertPath == "" {
+		return client, nil
+	}
+
+	pem, err := os.ReadFile(c.SSLCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Conjur certificate from %s: %w", c.SSLCertPath, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", c.SSLCertPath)
+	}
+
+	client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return client, nil
+}
+
+func withEnvDefault(value string, envName string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(envName)
+}
+
+type DataKeyLength int
+
+func (l DataKeyLength) Validate() error {
+	switch l {
+	case 16, 32, 64:
+		return nil
+	default:
+		return fmt.Errorf("data key length should one of 16, 32 or 64 bytes: got %v", l)
+	}
+}
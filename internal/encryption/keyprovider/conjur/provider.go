@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package conjur
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+const (
+	saltLength = 32
+
+	// minSecretLength is the minimum length of the secret stored in the Conjur variable. The secret is used as
+	// key material directly, so it must not be a short password.
+	minSecretLength = 16
+
+	hkdfInfo = "opentofu conjur key provider"
+)
+
+type keyMeta struct {
+	Salt []byte `json:"salt"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Salt) != 0
+}
+
+type keyProvider struct {
+	svc        service
+	variableID string
+	keyLength  DataKeyLength
+}
+
+// Provide retrieves the secret from the Conjur variable and derives the keys from it. Conjur stores secrets rather
+// than performing cryptographic operations, so each encryption uses a new random salt, which is stored in the
+// metadata to derive the same key for decryption.
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: invalid metadata struct type",
+		}
+	}
+
+	ctx := context.Background()
+
+	secret, err := p.svc.retrieveSecret(ctx, p.variableID)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to retrieve the secret from Conjur (check if the configuration valid and Conjur server accessible)",
+			Cause:   err,
+		}
+	}
+	if len(secret) < minSecretLength {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("the secret in Conjur variable %q is too short, it must be at least %d bytes long", p.variableID, minSecretLength),
+		}
+	}
+
+	outMeta := &keyMeta{
+		Salt: make([]byte, saltLength),
+	}
+	if _, err := io.ReadFull(rand.Reader, outMeta.Salt); err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("failed to obtain %d bytes of random data", saltLength),
+			Cause:   err,
+		}
+	}
+
+	out := keyprovider.Output{}
+	out.EncryptionKey, err = p.deriveKey(secret, outMeta.Salt)
+	if err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+
+	if inMeta.isPresent() {
+		out.DecryptionKey, err = p.deriveKey(secret, inMeta.Salt)
+		if err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+	}
+
+	return out, outMeta, nil
+}
+
+func (p keyProvider) deriveKey(secret []byte, salt []byte) ([]byte, error) {
+	key := make([]byte, p.keyLength)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(hkdfInfo)), key); err != nil {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to derive key",
+			Cause:   err,
+		}
+	}
+	return key, nil
+}
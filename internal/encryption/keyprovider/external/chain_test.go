@@ -6,6 +6,7 @@
 package external_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -72,11 +73,11 @@ state {
 	stateEncryption := enc.State()
 
 	fakeState := "{}"
-	encryptedState, err := stateEncryption.EncryptState([]byte(fakeState))
+	encryptedState, err := stateEncryption.EncryptState(context.Background(), []byte(fakeState))
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	decryptedState, _, err := stateEncryption.DecryptState(encryptedState)
+	decryptedState, _, err := stateEncryption.DecryptState(context.Background(), encryptedState)
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
@@ -35,6 +35,10 @@ type keyProvider struct {
 }
 
 func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	return p.ProvideContext(p.ctx, rawMeta)
+}
+
+func (p keyProvider) ProvideContext(ctx context.Context, rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
 	if rawMeta == nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
 	}
@@ -57,7 +61,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 	}
 
 	// Encrypt new encryption key using kms
-	encryptedKeyData, err := p.svc.Encrypt(p.ctx, &kmspb.EncryptRequest{
+	encryptedKeyData, err := p.svc.Encrypt(ctx, &kmspb.EncryptRequest{
 		Name:      p.keyName,
 		Plaintext: out.EncryptionKey,
 	})
@@ -75,7 +79,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 
 	if inMeta.isPresent() {
 		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey
-		decryptedKeyData, decryptErr := p.svc.Decrypt(p.ctx, &kmspb.DecryptRequest{
+		decryptedKeyData, decryptErr := p.svc.Decrypt(ctx, &kmspb.DecryptRequest{
 			Name:       p.keyName,
 			Ciphertext: inMeta.Ciphertext,
 		})
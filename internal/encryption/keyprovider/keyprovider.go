@@ -5,6 +5,8 @@
 
 package keyprovider
 
+import "context"
+
 // KeyProvider is the usable key provider. The Provide function is responsible for creating both the decryption and
 // encryption key, as well as returning the metadata to be stored.
 type KeyProvider interface {
@@ -14,3 +16,13 @@ type KeyProvider interface {
 	// metadata read in. If no decryption metadata is present, the caller must pass in the struct unmodified.
 	Provide(decryptionMeta KeyMeta) (keysOutput Output, encryptionMeta KeyMeta, err error)
 }
+
+// ContextKeyProvider is implemented by key providers that call remote services, such as a KMS. The encryption setup
+// calls ProvideContext instead of Provide, passing a context that carries the span of the key provider call so that
+// the calls to the remote services are traced as its children.
+type ContextKeyProvider interface {
+	KeyProvider
+
+	// ProvideContext works like Provide, but makes the calls to remote services with the given context.
+	ProvideContext(ctx context.Context, decryptionMeta KeyMeta) (keysOutput Output, encryptionMeta KeyMeta, err error)
+}
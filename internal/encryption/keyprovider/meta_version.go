@@ -0,0 +1,60 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"fmt"
+)
+
+// MetaVersion is the version of the metadata format of a key provider. Version 0 is the format used by key providers
+// that have never changed their metadata, which is stored without a version.
+type MetaVersion uint
+
+// MetaMigration converts the JSON-encoded metadata of one version into the next version.
+type MetaMigration func(meta []byte) ([]byte, error)
+
+// MetaMigrations is the list of migrations of a key provider's metadata format. The element at index N converts the
+// metadata from version N to version N+1, so the current version is the length of the list. Migrations must never be
+// removed or reordered, otherwise data encrypted by older versions of OpenTofu can no longer be decrypted.
+type MetaMigrations []MetaMigration
+
+// Version returns the current metadata version.
+func (m MetaMigrations) Version() MetaVersion {
+	return MetaVersion(len(m))
+}
+
+// Migrate converts the metadata from the given version to the current version.
+func (m MetaMigrations) Migrate(from MetaVersion, meta []byte) ([]byte, error) {
+	if from > m.Version() {
+		return nil, &ErrInvalidMetadata{
+			Message: fmt.Sprintf(
+				"the metadata has version %d, but this version of OpenTofu only supports up to version %d (was the data encrypted by a newer version of OpenTofu?)",
+				from, m.Version(),
+			),
+		}
+	}
+	for version := from; version < m.Version(); version++ {
+		var err error
+		meta, err = m[version](meta)
+		if err != nil {
+			return nil, &ErrInvalidMetadata{
+				Message: fmt.Sprintf("failed to migrate the metadata from version %d to %d", version, version+1),
+				Cause:   err,
+			}
+		}
+	}
+	return meta, nil
+}
+
+// VersionedDescriptor is implemented by the descriptors of key providers that have changed their metadata format.
+// OpenTofu stores the current version alongside the metadata and runs the migrations on metadata written with an
+// older version before passing it to the key provider.
+type VersionedDescriptor interface {
+	Descriptor
+
+	// MetaMigrations returns the migrations of the metadata format.
+	MetaMigrations() MetaMigrations
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+func TestMetaMigrations(t *testing.T) {
+	migrations := keyprovider.MetaMigrations{
+		func(meta []byte) ([]byte, error) {
+			return append(meta, '1'), nil
+		},
+		func(meta []byte) ([]byte, error) {
+			return append(meta, '2'), nil
+		},
+	}
+	if migrations.Version() != 2 {
+		t.Fatalf("unexpected version: %d", migrations.Version())
+	}
+
+	for from, want := range map[keyprovider.MetaVersion]string{
+		0: "m12",
+		1: "m2",
+		2: "m",
+	} {
+		got, err := migrations.Migrate(from, []byte("m"))
+		if err != nil {
+			t.Fatalf("migration from %d failed: %v", from, err)
+		}
+		if !bytes.Equal(got, []byte(want)) {
+			t.Errorf("migration from %d: got %q, want %q", from, got, want)
+		}
+	}
+
+	var typedErr *keyprovider.ErrInvalidMetadata
+	if _, err := migrations.Migrate(3, []byte("m")); !errors.As(err, &typedErr) {
+		t.Fatalf("expected an invalid metadata error for a newer version, got %v", err)
+	}
+
+	failing := keyprovider.MetaMigrations{
+		func(meta []byte) ([]byte, error) {
+			return nil, errors.New("broken")
+		},
+	}
+	if _, err := failing.Migrate(0, []byte("m")); !errors.As(err, &typedErr) {
+		t.Fatalf("expected an invalid metadata error for a failed migration, got %v", err)
+	}
+}
@@ -26,6 +26,10 @@ type keyProvider struct {
 }
 
 func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	return p.ProvideContext(context.Background(), rawMeta)
+}
+
+func (p keyProvider) ProvideContext(ctx context.Context, rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
 	if rawMeta == nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
 			Message: "bug: no metadata struct provided",
@@ -39,8 +43,6 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 		}
 	}
 
-	ctx := context.Background()
-
 	dataKey, err := p.svc.generateDataKey(ctx, p.keyName, p.keyLength.Bits())
 	if err != nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
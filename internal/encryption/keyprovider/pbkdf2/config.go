@@ -40,16 +40,22 @@ func (h HashFunctionName) Function() HashFunction {
 type Config struct {
 	// Set by the descriptor.
 	randomSource io.Reader
+	prompter     keyprovider.Prompter
+
+	// Set by the encryption setup.
+	addr keyprovider.Addr
 
 	// Passprase is a single passphrase to use for encryption. This is mutually exclusive with Passphrases.
 	Passphrase string `hcl:"passphrase,optional"`
 	// Chain are two separate passphrases supplied from a chained provider. This is mutually exclusive with
 	// Passphrase.
-	Chain        *keyprovider.Output `hcl:"chain,optional"`
-	KeyLength    int                 `hcl:"key_length,optional"`
-	Iterations   int                 `hcl:"iterations,optional"`
-	HashFunction HashFunctionName    `hcl:"hash_function,optional"`
-	SaltLength   int                 `hcl:"salt_length,optional"`
+	Chain *keyprovider.Output `hcl:"chain,optional"`
+	// Prompt asks the user for the passphrase interactively. This is mutually exclusive with Passphrase and Chain.
+	Prompt       bool             `hcl:"prompt,optional"`
+	KeyLength    int              `hcl:"key_length,optional"`
+	Iterations   int              `hcl:"iterations,optional"`
+	HashFunction HashFunctionName `hcl:"hash_function,optional"`
+	SaltLength   int              `hcl:"salt_length,optional"`
 }
 
 // WithPassphrase adds the passphrase and returns the same config for chaining.
@@ -64,6 +70,23 @@ func (c *Config) WithChain(chain *keyprovider.Output) *Config {
 	return c
 }
 
+// WithPrompt enables interactive passphrase prompting and returns the same config for chaining.
+func (c *Config) WithPrompt(prompt bool) *Config {
+	c.Prompt = prompt
+	return c
+}
+
+// SetAddr records the address of the key provider block this configuration was decoded from. It is used to identify
+// the key provider when prompting for a passphrase.
+func (c *Config) SetAddr(addr keyprovider.Addr) {
+	c.addr = addr
+}
+
+// Prompts returns true if the passphrase is asked for interactively.
+func (c *Config) Prompts() bool {
+	return c.Prompt
+}
+
 // WithKeyLength sets the key length and returns the same config for chaining
 func (c *Config) WithKeyLength(length int) *Config {
 	c.KeyLength = length
@@ -95,7 +118,18 @@ func (c *Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		}
 	}
 
-	if c.Passphrase == "" && c.Chain == nil {
+	if c.Prompt {
+		if c.Passphrase != "" || c.Chain != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "prompt is mutually exclusive with passphrase and chain",
+			}
+		}
+		if c.prompter == nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "interactive passphrase prompting is not available in this context",
+			}
+		}
+	} else if c.Passphrase == "" && c.Chain == nil {
 		return nil, nil, &keyprovider.ErrInvalidConfiguration{
 			Message: "no passphrase provided and no chained provider defined",
 		}
@@ -30,6 +30,15 @@ func New() Descriptor {
 	}
 }
 
+// NewWithPrompter creates a new PBKDF2 key provider descriptor that uses the given prompter to ask for the passphrase
+// when the configuration sets prompt = true.
+func NewWithPrompter(prompter keyprovider.Prompter) Descriptor {
+	return &descriptor{
+		randomSource: rand.Reader,
+		prompter:     prompter,
+	}
+}
+
 // Descriptor provides TypedConfig on top of keyprovider.Descriptor.
 type Descriptor interface {
 	keyprovider.Descriptor
@@ -39,6 +48,7 @@ type Descriptor interface {
 
 type descriptor struct {
 	randomSource io.Reader
+	prompter     keyprovider.Prompter
 }
 
 func (f descriptor) ID() keyprovider.ID {
@@ -48,6 +58,7 @@ func (f descriptor) ID() keyprovider.ID {
 func (f descriptor) TypedConfig() *Config {
 	return &Config{
 		randomSource: f.randomSource,
+		prompter:     f.prompter,
 		Passphrase:   "",
 		Chain:        nil,
 		KeyLength:    DefaultKeyLength,
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pbkdf2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type testPrompter struct {
+	passphrase string
+	calls      []bool
+	addrs      []keyprovider.Addr
+}
+
+func (p *testPrompter) PromptSecret(addr keyprovider.Addr, _ string, confirm bool) (string, error) {
+	p.calls = append(p.calls, confirm)
+	p.addrs = append(p.addrs, addr)
+	return p.passphrase, nil
+}
+
+func TestPrompt(t *testing.T) {
+	prompter := &testPrompter{passphrase: "Hello world! 123"}
+
+	cfg := NewWithPrompter(prompter).TypedConfig().WithPrompt(true)
+	cfg.SetAddr("key_provider.pbkdf2.foo")
+	provider, meta, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without metadata the passphrase must be confirmed.
+	out, outMeta, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompter.calls) != 1 || !prompter.calls[0] {
+		t.Fatalf("expected a single prompt with confirmation, got %v", prompter.calls)
+	}
+	if prompter.addrs[0] != "key_provider.pbkdf2.foo" {
+		t.Fatalf("incorrect address: %s", prompter.addrs[0])
+	}
+
+	// With metadata no confirmation is needed and the key must match the one derived from a configured passphrase.
+	out2, _, err := provider.Provide(outMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompter.calls) != 2 || prompter.calls[1] {
+		t.Fatalf("expected a second prompt without confirmation, got %v", prompter.calls)
+	}
+	if !bytes.Equal(out.EncryptionKey, out2.DecryptionKey) {
+		t.Fatalf("the decryption key does not match the encryption key")
+	}
+
+	static, _, err := New().TypedConfig().WithPassphrase("Hello world! 123").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out3, _, err := static.Provide(outMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.EncryptionKey, out3.DecryptionKey) {
+		t.Fatalf("the prompted passphrase produced a different key than the configured one")
+	}
+}
+
+func TestPrompt_invalid(t *testing.T) {
+	if _, _, err := New().TypedConfig().WithPrompt(true).Build(); err == nil {
+		t.Fatalf("expected an error without a prompter")
+	}
+	prompter := &testPrompter{passphrase: "Hello world! 123"}
+	if _, _, err := NewWithPrompter(prompter).TypedConfig().WithPrompt(true).WithPassphrase("Hello world! 123").Build(); err == nil {
+		t.Fatalf("expected an error when both prompt and passphrase are set")
+	}
+
+	prompter.passphrase = "short"
+	provider, meta, err := NewWithPrompter(prompter).TypedConfig().WithPrompt(true).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := provider.Provide(meta); err == nil {
+		t.Fatalf("expected an error for a short passphrase")
+	}
+}
@@ -53,6 +53,16 @@ func (p pbkdf2KeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Out
 		return keyprovider.Output{}, nil, err
 	}
 
+	passphrase := p.Passphrase
+	if p.Prompt {
+		// Without metadata there is no existing data the passphrase could be validated against, so we ask for
+		// confirmation to avoid encrypting with a mistyped passphrase.
+		passphrase, err = keyprovider.PromptPassphrase(p.prompter, p.addr, !inMeta.isPresent(), MinimumPassphraseLength)
+		if err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+	}
+
 	var decryptionKey []byte
 	if inMeta.isPresent() {
 		if err := inMeta.validate(); err != nil {
@@ -62,7 +72,7 @@ func (p pbkdf2KeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Out
 		if p.Chain != nil {
 			decryptionPassphrase = p.Chain.DecryptionKey
 		} else {
-			decryptionPassphrase = []byte(p.Passphrase)
+			decryptionPassphrase = []byte(passphrase)
 		}
 		decryptionKey = goPBKDF2.Key(
 			decryptionPassphrase,
@@ -77,7 +87,7 @@ func (p pbkdf2KeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Out
 	if p.Chain != nil {
 		encryptionPassphrase = p.Chain.EncryptionKey
 	} else {
-		encryptionPassphrase = []byte(p.Passphrase)
+		encryptionPassphrase = []byte(passphrase)
 	}
 	return keyprovider.Output{
 		EncryptionKey: goPBKDF2.Key(
@@ -0,0 +1,55 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import "fmt"
+
+// Prompter lets key providers interactively ask the user for secret input, such as a passphrase, instead of requiring
+// it to be present in the configuration or the environment. The implementation is supplied by the user interface layer.
+type Prompter interface {
+	// PromptSecret asks the user for the secret belonging to the key provider with the given address. If confirm is
+	// true, the user should be asked to enter the secret twice to guard against typos. Implementations may return a
+	// previously entered secret for the same address without asking again.
+	PromptSecret(addr Addr, query string, confirm bool) (string, error)
+}
+
+// AddressableConfig is implemented by key provider configurations that need to know the address of the key_provider
+// block they were decoded from, for example to identify themselves to the user when prompting. The encryption setup
+// calls SetAddr after decoding the configuration and before calling Build.
+type AddressableConfig interface {
+	Config
+
+	SetAddr(addr Addr)
+}
+
+// PromptingConfig is implemented by key provider configurations that can prompt the user. The encryption setup uses it
+// to put off setting up the encryptor until existing data has been decrypted, so that the prompt for decryption comes
+// first and the user is only asked to confirm the secret when nothing has been encrypted with it yet.
+type PromptingConfig interface {
+	Config
+
+	// Prompts returns true if the key provider built from this configuration prompts the user.
+	Prompts() bool
+}
+
+// PromptPassphrase asks the user for the passphrase of the key provider with the given address using the prompter and
+// returns an error if the passphrase is shorter than minLength. Key providers that support the prompt option share this
+// function so that the prompt and the errors are the same for all of them.
+func PromptPassphrase(prompter Prompter, addr Addr, confirm bool, minLength int) (string, error) {
+	passphrase, err := prompter.PromptSecret(addr, fmt.Sprintf("Enter the passphrase for %s", addr), confirm)
+	if err != nil {
+		return "", &ErrKeyProviderFailure{
+			Message: "failed to read the passphrase",
+			Cause:   err,
+		}
+	}
+	if len(passphrase) < minLength {
+		return "", &ErrKeyProviderFailure{
+			Message: fmt.Sprintf("passphrase is too short (minimum %d characters)", minLength),
+		}
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package keyprovider
+
+import (
+	"errors"
+	"testing"
+)
+
+type testPrompter struct {
+	passphrase string
+	err        error
+
+	addr    Addr
+	query   string
+	confirm bool
+}
+
+func (p *testPrompter) PromptSecret(addr Addr, query string, confirm bool) (string, error) {
+	p.addr = addr
+	p.query = query
+	p.confirm = confirm
+	return p.passphrase, p.err
+}
+
+func TestPromptPassphrase(t *testing.T) {
+	for _, confirm := range []bool{true, false} {
+		prompter := &testPrompter{passphrase: "Hello world! 123"}
+		passphrase, err := PromptPassphrase(prompter, "key_provider.test.foo", confirm, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if passphrase != "Hello world! 123" {
+			t.Fatalf("incorrect passphrase: %s", passphrase)
+		}
+		if prompter.addr != "key_provider.test.foo" {
+			t.Fatalf("incorrect address: %s", prompter.addr)
+		}
+		if prompter.query != "Enter the passphrase for key_provider.test.foo" {
+			t.Fatalf("incorrect query: %s", prompter.query)
+		}
+		if prompter.confirm != confirm {
+			t.Fatalf("expected confirm to be %t", confirm)
+		}
+	}
+}
+
+func TestPromptPassphrase_invalid(t *testing.T) {
+	var failure *ErrKeyProviderFailure
+
+	prompter := &testPrompter{err: errors.New("no terminal")}
+	_, err := PromptPassphrase(prompter, "key_provider.test.foo", false, 16)
+	if !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure, got %v", err)
+	}
+	if !errors.Is(err, prompter.err) {
+		t.Fatalf("the prompter error is not the cause: %v", err)
+	}
+
+	prompter = &testPrompter{passphrase: "short"}
+	_, err = PromptPassphrase(prompter, "key_provider.test.foo", false, 16)
+	if !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure for a short passphrase, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@
 package static_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -54,14 +55,14 @@ func Example() {
 
 	encryptor := enc.Plan()
 
-	encryptedPlan, err := encryptor.EncryptPlan([]byte("Hello world!"))
+	encryptedPlan, err := encryptor.EncryptPlan(context.Background(), []byte("Hello world!"))
 	if err != nil {
 		panic(err)
 	}
 	if strings.Contains(string(encryptedPlan), "Hello world!") {
 		panic("The plan was not encrypted!")
 	}
-	decryptedPlan, err := encryptor.DecryptPlan(encryptedPlan)
+	decryptedPlan, err := encryptor.DecryptPlan(context.Background(), encryptedPlan)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,129 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statictest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+const testKey = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+
+func TestCompliance(t *testing.T) {
+	validConfig := &Config{
+		Key:                 testKey,
+		UnsafeAllowTestKeys: true,
+	}
+	built, _, err := validConfig.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validProvider := built.(*keyProvider)
+	validKeyID := validProvider.keyID()
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"empty": {
+					HCL:      `key_provider "static_test" "foo" {}`,
+					ValidHCL: false,
+				},
+				"not-allowed": {
+					HCL: fmt.Sprintf(`key_provider "static_test" "foo" {
+    key = %q
+}`, testKey),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-hex": {
+					HCL: `key_provider "static_test" "foo" {
+    key                    = "not hex"
+    unsafe_allow_test_keys = true
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"allowed": {
+					HCL: fmt.Sprintf(`key_provider "static_test" "foo" {
+    key                    = %q
+    unsafe_allow_test_keys = true
+}`, testKey),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if len(keyProvider.key) != sha256.Size {
+							return fmt.Errorf("incorrect key length: %d", len(keyProvider.key))
+						}
+						return nil
+					},
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"not-present": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+				},
+				"present-valid": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{KeyID: validKeyID},
+					IsPresent:   true,
+					IsValid:     true,
+				},
+				"present-invalid": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{KeyID: "not hex"},
+					IsPresent:   true,
+					IsValid:     false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: validConfig,
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: validProvider.key,
+					DecryptionKey: validProvider.key,
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if meta.KeyID != validKeyID {
+						return fmt.Errorf("incorrect key ID: %s", meta.KeyID)
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestDeterministicKey(t *testing.T) {
+	build := func(key string) *keyProvider {
+		t.Helper()
+		kp, _, err := Config{Key: key, UnsafeAllowTestKeys: true}.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return kp.(*keyProvider)
+	}
+
+	if !bytes.Equal(build(testKey).key, build(testKey).key) {
+		t.Fatal("the same hex value resulted in different keys")
+	}
+
+	other := build("00")
+	_, _, err := other.Provide(&keyMeta{KeyID: build(testKey).keyID()})
+	var typedError *keyprovider.ErrKeyProviderFailure
+	if !errors.As(err, &typedError) {
+		t.Fatalf("expected a key provider failure for a different key, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statictest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// keyDerivationPrefix separates the keys derived by this key provider from other uses of the same hex value.
+const keyDerivationPrefix = "opentofu-static-test-key:"
+
+// Config describes the configuration of the static_test key provider.
+type Config struct {
+	Key                 string `hcl:"key"`
+	UnsafeAllowTestKeys bool   `hcl:"unsafe_allow_test_keys,optional"`
+}
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if !c.UnsafeAllowTestKeys {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the static_test key provider is only intended for tests and requires unsafe_allow_test_keys = true",
+		}
+	}
+	if c.Key == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing key",
+		}
+	}
+	decoded, err := hex.DecodeString(c.Key)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "failed to hex-decode the provided key",
+			Cause:   err,
+		}
+	}
+
+	log.Printf("[WARN] Using the static_test key provider. Its keys are not secret and must not be used outside of tests.")
+
+	key := sha256.Sum256(append([]byte(keyDerivationPrefix), decoded...))
+	return &keyProvider{key: key[:]}, new(keyMeta), nil
+}
@@ -0,0 +1,29 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package statictest contains the static_test key provider, which derives a deterministic key from a hex value
+// given in the configuration. It is intended for integration tests of encrypted state that must be reproducible
+// without access to a key management service, and is refused unless unsafe_allow_test_keys is set.
+package statictest
+
+import (
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// New creates a new static_test key provider descriptor.
+func New() keyprovider.Descriptor {
+	return &descriptor{}
+}
+
+type descriptor struct {
+}
+
+func (f descriptor) ID() keyprovider.ID {
+	return "static_test"
+}
+
+func (f descriptor) ConfigStruct() keyprovider.Config {
+	return &Config{}
+}
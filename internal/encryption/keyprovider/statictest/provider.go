@@ -0,0 +1,76 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statictest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// keyIDLength is the length of the key ID in bytes.
+const keyIDLength = 8
+
+type keyMeta struct {
+	// KeyID is a fingerprint of the key, so that decrypting with a different test key fails with a clear error.
+	KeyID string `json:"key_id"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return m.KeyID != ""
+}
+
+func (m keyMeta) validate() error {
+	decoded, err := hex.DecodeString(m.KeyID)
+	if err != nil || len(decoded) != keyIDLength {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid key ID: %q", m.KeyID),
+		}
+	}
+	return nil
+}
+
+type keyProvider struct {
+	key []byte
+}
+
+func (p keyProvider) keyID() string {
+	sum := sha256.Sum256(p.key)
+	return hex.EncodeToString(sum[:keyIDLength])
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: p.key,
+	}
+	if inMeta.isPresent() {
+		if err := inMeta.validate(); err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+		if inMeta.KeyID != p.keyID() {
+			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+				Message: fmt.Sprintf("the data was encrypted with a different test key (key ID %s)", inMeta.KeyID),
+			}
+		}
+		out.DecryptionKey = p.key
+	}
+
+	return out, &keyMeta{KeyID: p.keyID()}, nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+var testSalt = []byte("fedcba9876543210")
+
+func TestCompliance(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tofu-state"), testKey, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(credentialsDirectoryEnv, dir)
+
+	validConfig := &Config{
+		Name: "tofu-state",
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"empty": {
+					HCL:      `key_provider "systemd_creds" "foo" {}`,
+					ValidHCL: false,
+				},
+				"empty-name": {
+					HCL: `key_provider "systemd_creds" "foo" {
+    name = ""
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-name": {
+					HCL: `key_provider "systemd_creds" "foo" {
+    name = "../tofu-state"
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"credentials-directory": {
+					HCL: `key_provider "systemd_creds" "foo" {
+    name = "tofu-state"
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						key, err := keyProvider.read()
+						if err != nil {
+							return err
+						}
+						if !bytes.Equal(key, testKey) {
+							return fmt.Errorf("incorrect key read from the credentials directory")
+						}
+						return nil
+					},
+				},
+				"path": {
+					HCL: `key_provider "systemd_creds" "foo" {
+    name = "tofu-state"
+    path = "/etc/credstore.encrypted/tofu-state"
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if config.Path != "/etc/credstore.encrypted/tofu-state" {
+							return fmt.Errorf("incorrect path after parsing: %s", config.Path)
+						}
+						return nil
+					},
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"not-present": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+				},
+				"present-valid": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{Salt: testSalt, KeyCheck: keyCheck(testKey, testSalt)},
+					IsPresent:   true,
+					IsValid:     true,
+				},
+				"present-invalid-salt": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{Salt: []byte("short"), KeyCheck: keyCheck(testKey, testSalt)},
+					IsPresent:   true,
+					IsValid:     false,
+				},
+				"present-invalid-key-check": {
+					ValidConfig: validConfig,
+					Meta:        &keyMeta{Salt: testSalt},
+					IsPresent:   true,
+					IsValid:     false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: validConfig,
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: testKey,
+					DecryptionKey: testKey,
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if err := meta.validate(); err != nil {
+						return err
+					}
+					if !bytes.Equal(meta.KeyCheck, keyCheck(testKey, meta.Salt)) {
+						return fmt.Errorf("incorrect key check: %x", meta.KeyCheck)
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestMissingCredentialsDirectory(t *testing.T) {
+	t.Setenv(credentialsDirectoryEnv, "")
+
+	_, _, err := Config{Name: "tofu-state"}.Build()
+	var typedError *keyprovider.ErrInvalidConfiguration
+	if !errors.As(err, &typedError) {
+		t.Fatalf("expected an invalid configuration error, got %v", err)
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd-creds is not available on Windows")
+	}
+
+	// The fake systemd-creds checks the arguments and prints the credential file, which holds the plain key.
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "systemd-creds")
+	script := `#!/bin/sh
+if [ "$1" != "decrypt" ] || [ "$2" != "--name=tofu-state" ] || [ "$4" != "-" ]; then
+  echo "unexpected arguments: $*" >&2
+  exit 1
+fi
+cat "$3"
+`
+	if err := os.WriteFile(fake, []byte(script), 0700); err != nil { //nolint:gosec // The script needs to be executable.
+		t.Fatal(err)
+	}
+	oldCommand := systemdCredsCommand
+	systemdCredsCommand = fake
+	t.Cleanup(func() {
+		systemdCredsCommand = oldCommand
+	})
+
+	credential := filepath.Join(dir, "tofu-state.cred")
+	if err := os.WriteFile(credential, testKey, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(name string, path string) keyprovider.KeyProvider {
+		t.Helper()
+		kp, _, err := Config{Name: name, Path: path}.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return kp
+	}
+
+	out, meta, err := build("tofu-state", credential).Provide(&keyMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.EncryptionKey, testKey) {
+		t.Fatalf("incorrect encryption key")
+	}
+	if out.DecryptionKey != nil {
+		t.Fatalf("unexpected decryption key without metadata")
+	}
+
+	// The metadata must not identify the key, so each encryption uses a new salt.
+	_, meta2, err := build("tofu-state", credential).Provide(&keyMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(meta.(*keyMeta).KeyCheck, meta2.(*keyMeta).KeyCheck) {
+		t.Fatalf("the key check is the same for two encryptions with the same key")
+	}
+
+	// Decryption with the key check of a different key must fail.
+	if err := os.WriteFile(credential, bytes.Repeat([]byte{1}, 32), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var failure *keyprovider.ErrKeyProviderFailure
+	if _, _, err := build("tofu-state", credential).Provide(meta); !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure for a replaced credential, got %v", err)
+	}
+
+	// Errors from systemd-creds are passed on.
+	if _, _, err := build("other", credential).Provide(&keyMeta{}); !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure for a wrong credential name, got %v", err)
+	}
+
+	// Keys of an invalid length are rejected.
+	if err := os.WriteFile(credential, []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := build("tofu-state", credential).Provide(&keyMeta{}); !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure for an invalid key length, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// credentialsDirectoryEnv is the environment variable systemd sets for services that have credentials, pointing to
+// the directory containing the decrypted credentials.
+const credentialsDirectoryEnv = "CREDENTIALS_DIRECTORY"
+
+// validName matches the credential names systemd accepts, which must be valid file names.
+var validName = regexp.MustCompile(`^[^/\x00]+$`)
+
+// Config describes the configuration of the systemd_creds key provider.
+type Config struct {
+	// Name is the name of the credential. Without Path, the credential is read from the credentials directory of
+	// the service OpenTofu runs in.
+	Name string `hcl:"name"`
+	// Path is the path to an encrypted credential file, which is decrypted with systemd-creds.
+	Path string `hcl:"path,optional"`
+}
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.Name == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing credential name",
+		}
+	}
+	if !validName.MatchString(c.Name) || c.Name == "." || c.Name == ".." {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("invalid credential name %q", c.Name),
+		}
+	}
+
+	if c.Path != "" {
+		return &keyProvider{
+			read: func() ([]byte, error) {
+				return decryptCredential(c.Name, c.Path)
+			},
+		}, new(keyMeta), nil
+	}
+
+	dir := os.Getenv(credentialsDirectoryEnv)
+	if dir == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the %s environment variable is not set, either run OpenTofu in a systemd service with the credential %q or set the path of the encrypted credential file", credentialsDirectoryEnv, c.Name),
+		}
+	}
+	path := filepath.Join(dir, c.Name)
+	return &keyProvider{
+		read: func() ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}, new(keyMeta), nil
+}
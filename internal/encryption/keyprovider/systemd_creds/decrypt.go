@@ -0,0 +1,32 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemdCredsCommand is the command used to decrypt credential files. Tests replace it with a fake.
+var systemdCredsCommand = "systemd-creds"
+
+// decryptCredential decrypts the credential file at the given path. The name is checked by systemd-creds against the
+// name embedded in the credential, so that a credential encrypted for another purpose is not used by accident.
+func decryptCredential(name string, path string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(systemdCredsCommand, "decrypt", "--name="+name, path, "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package systemd_creds contains the systemd_creds key provider, which reads the key from a credential managed by
+// systemd's credential facility. This allows runners on bare-metal machines to keep the key bound to the machine
+// (for example via the TPM2) without a key management service.
+package systemd_creds
+
+import (
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// New creates a new systemd_creds key provider descriptor.
+func New() keyprovider.Descriptor {
+	return &descriptor{}
+}
+
+type descriptor struct {
+}
+
+func (f descriptor) ID() keyprovider.ID {
+	return "systemd_creds"
+}
+
+func (f descriptor) ConfigStruct() keyprovider.Config {
+	return &Config{}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+const (
+	// saltLength is the length of the random salt of the key check in bytes.
+	saltLength = 16
+	// keyCheckLength is the length of the key check in bytes.
+	keyCheckLength = 8
+)
+
+// keyCheckLabel separates the key check from any other use of the key.
+var keyCheckLabel = []byte("opentofu systemd_creds key check")
+
+type keyMeta struct {
+	// Salt is a random value that is different for each encryption, so that the key check doesn't identify the key
+	// across encrypted files.
+	Salt []byte `json:"salt"`
+	// KeyCheck is an HMAC of the salt keyed with the key, so that decrypting after the credential has been replaced
+	// fails with a clear error. It can't be computed without the key.
+	KeyCheck []byte `json:"key_check"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Salt) != 0 || len(m.KeyCheck) != 0
+}
+
+func (m keyMeta) validate() error {
+	if len(m.Salt) != saltLength {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid salt length: %d", len(m.Salt)),
+		}
+	}
+	if len(m.KeyCheck) != keyCheckLength {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid key check length: %d", len(m.KeyCheck)),
+		}
+	}
+	return nil
+}
+
+type keyProvider struct {
+	// read returns the decrypted contents of the credential.
+	read func() ([]byte, error)
+}
+
+// keyCheck returns the key check of the given key with the given salt.
+func keyCheck(key []byte, salt []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(keyCheckLabel)
+	mac.Write(salt)
+	return mac.Sum(nil)[:keyCheckLength]
+}
+
+// newKeyMeta returns the metadata for encrypting with the given key, with a new random salt.
+func newKeyMeta(key []byte) (*keyMeta, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("failed to obtain %d bytes of random data", saltLength),
+			Cause:   err,
+		}
+	}
+	return &keyMeta{Salt: salt, KeyCheck: keyCheck(key, salt)}, nil
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	key, err := p.read()
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to read the systemd credential",
+			Cause:   err,
+		}
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("the systemd credential must contain a key of 16, 24 or 32 bytes, found %d bytes", len(key)),
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: key,
+	}
+	if inMeta.isPresent() {
+		if err := inMeta.validate(); err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+		if !hmac.Equal(inMeta.KeyCheck, keyCheck(key, inMeta.Salt)) {
+			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+				Message: "the data was encrypted with a different key, the systemd credential may have been replaced",
+			}
+		}
+		out.DecryptionKey = key
+	}
+
+	outMeta, err := newKeyMeta(key)
+	if err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+	return out, outMeta, nil
+}
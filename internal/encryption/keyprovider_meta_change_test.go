@@ -6,6 +6,7 @@
 package encryption
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -75,14 +76,14 @@ func TestChangingKeyProviderAddr(t *testing.T) {
 	sfe2 := enc2.State()
 
 	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
-	encryptedState, err := sfe1.EncryptState(testData)
+	encryptedState, err := sfe1.EncryptState(context.Background(), testData)
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
 	if string(encryptedState) == string(testData) {
 		t.Fatalf("The state has not been encrypted.")
 	}
-	decryptedState, _, err := sfe2.DecryptState(encryptedState)
+	decryptedState, _, err := sfe2.DecryptState(context.Background(), encryptedState)
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
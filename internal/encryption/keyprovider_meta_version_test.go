@@ -0,0 +1,215 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+// versionedTestDescriptor describes a key provider that used to store its key in the "k" field of the metadata
+// (version 0) and now stores it in the "key" field (version 1).
+type versionedTestDescriptor struct{}
+
+func (versionedTestDescriptor) ID() keyprovider.ID {
+	return "versioned_test"
+}
+
+func (versionedTestDescriptor) ConfigStruct() keyprovider.Config {
+	return &versionedTestConfig{}
+}
+
+func (versionedTestDescriptor) MetaMigrations() keyprovider.MetaMigrations {
+	return keyprovider.MetaMigrations{
+		func(meta []byte) ([]byte, error) {
+			var v0 struct {
+				K string `json:"k"`
+			}
+			if err := json.Unmarshal(meta, &v0); err != nil {
+				return nil, err
+			}
+			return json.Marshal(versionedTestMeta{Key: v0.K})
+		},
+	}
+}
+
+type versionedTestConfig struct {
+	Key string `hcl:"key"`
+}
+
+func (c *versionedTestConfig) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	return &versionedTestKeyProvider{key: c.Key}, &versionedTestMeta{}, nil
+}
+
+type versionedTestMeta struct {
+	Key string `json:"key"`
+}
+
+type versionedTestKeyProvider struct {
+	key string
+}
+
+func (p *versionedTestKeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	inMeta := rawMeta.(*versionedTestMeta)
+	output := keyprovider.Output{EncryptionKey: []byte(p.key)}
+	if inMeta.Key != "" {
+		output.DecryptionKey = []byte(inMeta.Key)
+	}
+	return output, &versionedTestMeta{Key: p.key}, nil
+}
+
+func TestKeyProviderMetaVersioning(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(versionedTestDescriptor{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("test", `
+key_provider "versioned_test" "basic" {
+	key = "0123456789abcdef0123456789abcdef"
+}
+method "aes_gcm" "example" {
+	keys = key_provider.versioned_test.basic
+}
+state {
+	method = method.aes_gcm.example
+}
+`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	sourceData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encrypted, err := enc.State().EncryptState(context.Background(), sourceData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rewriteMeta replaces the stored metadata of the key provider in the encrypted payload.
+	rewriteMeta := func(t *testing.T, meta string) []byte {
+		t.Helper()
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(encrypted, &payload); err != nil {
+			t.Fatal(err)
+		}
+		rawMeta, err := json.Marshal(keyProviderMetamap{"key_provider.versioned_test.basic": []byte(meta)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload["meta"] = rawMeta
+		result, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("current", func(t *testing.T) {
+		var payload basedata
+		if err := json.Unmarshal(encrypted, &payload); err != nil {
+			t.Fatal(err)
+		}
+		stored := string(payload.Meta["key_provider.versioned_test.basic"])
+		if want := `{"meta_version":1,"meta_data":{"key":"0123456789abcdef0123456789abcdef"}}`; stored != want {
+			t.Fatalf("unexpected stored metadata\ngot:  %s\nwant: %s", stored, want)
+		}
+
+		decrypted, _, err := enc.State().DecryptState(context.Background(), encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decrypted) != string(sourceData) {
+			t.Fatalf("unexpected decrypted data: %s", decrypted)
+		}
+	})
+
+	t.Run("migrated", func(t *testing.T) {
+		legacy := rewriteMeta(t, `{"k":"0123456789abcdef0123456789abcdef"}`)
+		decrypted, _, err := enc.State().DecryptState(context.Background(), legacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decrypted) != string(sourceData) {
+			t.Fatalf("unexpected decrypted data: %s", decrypted)
+		}
+	})
+
+	t.Run("newer", func(t *testing.T) {
+		newer := rewriteMeta(t, `{"meta_version":2,"meta_data":{"key":"0123456789abcdef0123456789abcdef"}}`)
+		_, _, err := enc.State().DecryptState(context.Background(), newer)
+		if err == nil {
+			t.Fatal("expected an error for metadata written by a newer version")
+		}
+		if !strings.Contains(err.Error(), "newer version of OpenTofu") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestUnwrapKeyProviderMeta(t *testing.T) {
+	tests := map[string]struct {
+		stored      string
+		wantVersion keyprovider.MetaVersion
+		wantData    string
+	}{
+		"envelope": {
+			stored:      `{"meta_version":1,"meta_data":{"key":"k"}}`,
+			wantVersion: 1,
+			wantData:    `{"key":"k"}`,
+		},
+		"unversioned": {
+			stored:   `{"key":"k"}`,
+			wantData: `{"key":"k"}`,
+		},
+		"no meta_version": {
+			stored:   `{"meta_data":{"key":"k"},"salt":"s"}`,
+			wantData: `{"meta_data":{"key":"k"},"salt":"s"}`,
+		},
+		"meta_version is not a number": {
+			stored:   `{"meta_version":"1","meta_data":{"key":"k"}}`,
+			wantData: `{"meta_version":"1","meta_data":{"key":"k"}}`,
+		},
+		"meta_version is zero": {
+			stored:   `{"meta_version":0,"meta_data":{"key":"k"}}`,
+			wantData: `{"meta_version":0,"meta_data":{"key":"k"}}`,
+		},
+		"no meta_data": {
+			stored:   `{"meta_version":1,"key":"k"}`,
+			wantData: `{"meta_version":1,"key":"k"}`,
+		},
+		"not an object": {
+			stored:   `"k"`,
+			wantData: `"k"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, data := unwrapKeyProviderMeta([]byte(test.stored))
+			if version != test.wantVersion {
+				t.Errorf("wrong version %d; want %d", version, test.wantVersion)
+			}
+			if string(data) != test.wantData {
+				t.Errorf("wrong data %s; want %s", data, test.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+// cachingTestPrompter records the prompts and, like the prompter of the command package, answers repeated prompts for
+// the same key provider from a cache.
+type cachingTestPrompter struct {
+	passphrase string
+	confirms   []bool
+	cache      map[keyprovider.Addr]string
+}
+
+func (p *cachingTestPrompter) PromptSecret(addr keyprovider.Addr, _ string, confirm bool) (string, error) {
+	if secret, ok := p.cache[addr]; ok {
+		return secret, nil
+	}
+	p.confirms = append(p.confirms, confirm)
+	p.cache[addr] = p.passphrase
+	return p.passphrase, nil
+}
+
+func TestKeyProviderPrompt(t *testing.T) {
+	cfg, diags := config.LoadConfigFromString("test", `
+key_provider "pbkdf2" "prompted" {
+	prompt = true
+}
+method "aes_gcm" "example" {
+	keys = key_provider.pbkdf2.prompted
+}
+state {
+	method = method.aes_gcm.example
+}
+`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	newEncryption := func(t *testing.T, prompter keyprovider.Prompter) Encryption {
+		t.Helper()
+		reg := lockingencryptionregistry.New()
+		if err := reg.RegisterKeyProvider(pbkdf2.NewWithPrompter(prompter)); err != nil {
+			t.Fatal(err)
+		}
+		if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+			t.Fatal(err)
+		}
+		enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return enc
+	}
+
+	sourceData := []byte(`{"serial": 42, "lineage": "magic"}`)
+
+	// Nothing has been encrypted with the passphrase yet, so it has to be confirmed.
+	first := &cachingTestPrompter{passphrase: "Hello world! 123", cache: make(map[keyprovider.Addr]string)}
+	enc := newEncryption(t, first)
+	if len(first.confirms) != 0 {
+		t.Fatalf("prompted before the state was encrypted: %v", first.confirms)
+	}
+	encrypted, err := enc.State().EncryptState(context.Background(), sourceData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]bool{true}, first.confirms); diff != "" {
+		t.Fatalf("wrong prompts for the first encryption\n%s", diff)
+	}
+
+	// The existing state carries metadata for the key provider, so decrypting it and encrypting it again prompts only
+	// once and without confirmation.
+	second := &cachingTestPrompter{passphrase: "Hello world! 123", cache: make(map[keyprovider.Addr]string)}
+	enc = newEncryption(t, second)
+	decrypted, _, err := enc.State().DecryptState(context.Background(), encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decrypted) != string(sourceData) {
+		t.Fatalf("unexpected decrypted data: %s", decrypted)
+	}
+	if _, err := enc.State().EncryptState(context.Background(), decrypted); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]bool{false}, second.confirms); diff != "" {
+		t.Fatalf("wrong prompts for decrypting and encrypting again\n%s", diff)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// workspaceKeyInfo is the HKDF info prefix for workspace keys. Changing it makes existing state and plan files
+// unreadable.
+const workspaceKeyInfo = "opentofu workspace key:"
+
+// deriveWorkspaceKeys derives workspace-specific keys from the output of a key provider with HKDF-SHA256, using the
+// workspace name as context. The derived keys have the same length as the original ones, so they can be used with the
+// same methods.
+func deriveWorkspaceKeys(output keyprovider.Output, workspace string) (keyprovider.Output, error) {
+	encryptionKey, err := deriveWorkspaceKey(output.EncryptionKey, workspace)
+	if err != nil {
+		return keyprovider.Output{}, err
+	}
+	decryptionKey, err := deriveWorkspaceKey(output.DecryptionKey, workspace)
+	if err != nil {
+		return keyprovider.Output{}, err
+	}
+	return keyprovider.Output{
+		EncryptionKey: encryptionKey,
+		DecryptionKey: decryptionKey,
+	}, nil
+}
+
+func deriveWorkspaceKey(key []byte, workspace string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	derived := make([]byte, len(key))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(workspaceKeyInfo+workspace)), derived); err != nil {
+		return nil, err
+	}
+	return derived, nil
+}
+
+// StateEncryptionForWorkspace returns the encryption for the state of the given workspace. Backends use it for the state
+// of each workspace they read or write, because the key providers that have workspace_key_derivation set must derive
+// their keys from the workspace the state belongs to rather than the currently selected one, for example when reading
+// the state of another workspace with terraform_remote_state, creating a new workspace or migrating all workspaces to
+// another backend.
+func StateEncryptionForWorkspace(enc StateEncryption, workspace string) (StateEncryption, error) {
+	s, ok := enc.(*stateEncryption)
+	if !ok || s.workspaces == nil || s.base.workspace == workspace {
+		return enc, nil
+	}
+	return s.workspaces.get(workspace)
+}
+
+// workspaceStateEncryptions sets up a state encryption for each workspace on demand and keeps it, so that the key
+// providers are only called once per workspace.
+type workspaceStateEncryptions struct {
+	setup func(workspace string) (*stateEncryption, hcl.Diagnostics)
+
+	mu     sync.Mutex
+	states map[string]*stateEncryption
+}
+
+func (w *workspaceStateEncryptions) get(workspace string) (StateEncryption, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if state, ok := w.states[workspace]; ok {
+		return state, nil
+	}
+	state, diags := w.setup(workspace)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	state.workspaces = w
+	w.states[workspace] = state
+	return state, nil
+}
+
+// newWorkspaceStateEncryption sets up a state encryption for the given workspace with the setup function. If any of the
+// key providers derive their keys from the workspace, the setup function is kept to set up the state encryption for
+// other workspaces.
+func (e *encryption) newWorkspaceStateEncryption(workspace string, setup func(workspace string) (*stateEncryption, hcl.Diagnostics)) (*stateEncryption, hcl.Diagnostics) {
+	state, diags := setup(workspace)
+	if !diags.HasErrors() && hasWorkspaceKeyDerivation(e.cfg) {
+		state.workspaces = &workspaceStateEncryptions{
+			setup:  setup,
+			states: map[string]*stateEncryption{workspace: state},
+		}
+	}
+	return state, diags
+}
+
+func hasWorkspaceKeyDerivation(cfg *config.EncryptionConfig) bool {
+	for _, kp := range cfg.KeyProviderConfigs {
+		if kp.WorkspaceKeyDerivation {
+			return true
+		}
+	}
+	return false
+}
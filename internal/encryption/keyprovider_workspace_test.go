@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+func TestWorkspaceKeys(t *testing.T) {
+	testCases := map[string]struct {
+		config string
+		// shared is true if all workspaces use the same key.
+		shared bool
+	}{
+		"shared": {
+			config: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}`,
+			shared: true,
+		},
+		"derived": {
+			config: `
+				key_provider "static" "basic" {
+					key                      = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+					workspace_key_derivation = true
+				}`,
+		},
+		"map": {
+			config: `
+				key_provider "static" "basic" {
+					key = {
+						prod = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+						dev  = "6861656b6f6f6c61656368316165306569783369656e676f6f6b6f6f6a616973"
+					}[terraform.workspace]
+				}`,
+		},
+	}
+
+	reg := testEnforcedRegistry()
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg, diags := config.LoadConfigFromString("test", tc.config+`
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}`)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			stateEncryption := func(workspace string) StateEncryption {
+				t.Helper()
+				staticEval := configs.NewStaticEvaluator(nil, configs.NewStaticModuleCall(addrs.RootModule, nil, "<testing>", workspace))
+				enc, diags := New(reg, cfg, staticEval)
+				if diags.HasErrors() {
+					t.Fatal(diags.Error())
+				}
+				return enc.State()
+			}
+
+			encrypted, err := stateEncryption("prod").EncryptState(context.Background(), []byte(testPlainState))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := stateEncryption("prod").DecryptState(context.Background(), encrypted); err != nil {
+				t.Fatalf("failed to decrypt the state in the same workspace: %v", err)
+			}
+			_, _, err = stateEncryption("dev").DecryptState(context.Background(), encrypted)
+			if tc.shared && err != nil {
+				t.Fatalf("failed to decrypt the state with a shared key: %v", err)
+			}
+			if !tc.shared && err == nil {
+				t.Fatalf("decrypted the state of another workspace")
+			}
+		})
+	}
+}
+
+func TestStateEncryptionForWorkspace(t *testing.T) {
+	cfg, diags := config.LoadConfigFromString("test", `
+		key_provider "static" "basic" {
+			key                      = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+			workspace_key_derivation = true
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		state {
+			method = method.aes_gcm.example
+		}`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	reg := testEnforcedRegistry()
+	stateEncryption := func(workspace string) StateEncryption {
+		t.Helper()
+		staticEval := configs.NewStaticEvaluator(nil, configs.NewStaticModuleCall(addrs.RootModule, nil, "<testing>", workspace))
+		enc, diags := New(reg, cfg, staticEval)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return enc.State()
+	}
+
+	// The state of the dev workspace is written while prod is selected, such
+	// as when creating the workspace or migrating it to another backend.
+	dev, err := StateEncryptionForWorkspace(stateEncryption("prod"), "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := dev.EncryptState(context.Background(), []byte(testPlainState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := stateEncryption("dev").DecryptState(context.Background(), encrypted); err != nil {
+		t.Fatalf("failed to decrypt the state in its own workspace: %v", err)
+	}
+	if _, _, err := stateEncryption("prod").DecryptState(context.Background(), encrypted); err == nil {
+		t.Fatalf("decrypted the state of another workspace")
+	}
+
+	// Repeated calls must reuse the state encryption of the workspace.
+	again, err := StateEncryptionForWorkspace(stateEncryption("prod"), "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := again.DecryptState(context.Background(), encrypted); err != nil {
+		t.Fatalf("failed to decrypt the state of the workspace: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// versionedKeyProviderMeta is the envelope the metadata of a versioned key provider is stored in. The metadata of key
+// providers without a version is stored as-is, so that older versions of OpenTofu can still read it.
+type versionedKeyProviderMeta struct {
+	Version keyprovider.MetaVersion `json:"meta_version"`
+	Data    json.RawMessage         `json:"meta_data"`
+}
+
+// keyProviderMetaMigrations returns the metadata migrations of the given key provider, if any.
+func keyProviderMetaMigrations(descriptor keyprovider.Descriptor) keyprovider.MetaMigrations {
+	if versioned, ok := descriptor.(keyprovider.VersionedDescriptor); ok {
+		return versioned.MetaMigrations()
+	}
+	return nil
+}
+
+// decodeKeyProviderMeta unwraps the stored metadata and migrates it to the current version of the key provider.
+func decodeKeyProviderMeta(migrations keyprovider.MetaMigrations, stored []byte) ([]byte, error) {
+	version, data := unwrapKeyProviderMeta(stored)
+	return migrations.Migrate(version, data)
+}
+
+// unwrapKeyProviderMeta returns the version and the metadata stored in the envelope, or version 0 and the unmodified
+// metadata if it has no envelope. The envelope is only recognised by its meta_version field, which must hold a version
+// of at least 1 because metadata of version 0 is never wrapped. Unversioned metadata that happens to use the same field
+// names is therefore only mistaken for an envelope if it also carries such a version.
+func unwrapKeyProviderMeta(stored []byte) (keyprovider.MetaVersion, []byte) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(stored, &fields); err != nil {
+		return 0, stored
+	}
+	rawVersion, ok := fields["meta_version"]
+	if !ok {
+		return 0, stored
+	}
+	var version keyprovider.MetaVersion
+	if err := json.Unmarshal(rawVersion, &version); err != nil || version == 0 {
+		return 0, stored
+	}
+	data, ok := fields["meta_data"]
+	if !ok || len(fields) != 2 {
+		return 0, stored
+	}
+	return version, data
+}
+
+// encodeKeyProviderMeta wraps the metadata in an envelope with the current version of the key provider.
+func encodeKeyProviderMeta(migrations keyprovider.MetaMigrations, data []byte) ([]byte, error) {
+	if migrations.Version() == 0 {
+		return data, nil
+	}
+	return json.Marshal(versionedKeyProviderMeta{
+		Version: migrations.Version(),
+		Data:    data,
+	})
+}
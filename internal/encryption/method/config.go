@@ -5,6 +5,11 @@
 
 package method
 
+import (
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+)
+
 // Config describes a configuration struct for setting up an encryption Method. You should always implement this
 // interface with a struct, and you should tag the fields with HCL tags so the encryption implementation can read
 // the .tf code into it. For example:
@@ -19,3 +24,16 @@ type Config interface {
 	// TODO this may be better changed to return hcl.Diagnostics so warnings can be issued?
 	Build() (Method, error)
 }
+
+// DynamicConfig is implemented by method configurations whose schema is only known at runtime, such as the
+// configurations of methods supplied by plugins. Instead of decoding the configuration based on struct tags, the
+// encryption setup decodes the configuration body according to ConfigSpec and passes the result to SetConfigValue
+// before calling Build.
+type DynamicConfig interface {
+	Config
+
+	// ConfigSpec returns the specification used to decode the configuration body.
+	ConfigSpec() (hcldec.Spec, error)
+	// SetConfigValue receives the decoded configuration.
+	SetConfigValue(value cty.Value)
+}
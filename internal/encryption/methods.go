@@ -6,11 +6,11 @@
 package encryption
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
@@ -19,7 +19,7 @@ import (
 )
 
 // setupMethod sets up a single method for encryption. It returns a list of diagnostics if the method is invalid.
-func setupMethod(enc *config.EncryptionConfig, cfg config.MethodConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) (method.Method, hcl.Diagnostics) {
+func setupMethod(ctx context.Context, enc *config.EncryptionConfig, cfg config.MethodConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator, workspace string) (method.Method, hcl.Diagnostics) {
 	// Lookup the definition of the encryption method from the registry
 	encryptionMethod, err := reg.GetMethodDescriptor(method.ID(cfg.Type))
 	if err != nil {
@@ -44,7 +44,7 @@ func setupMethod(enc *config.EncryptionConfig, cfg config.MethodConfig, meta key
 
 	methodConfig := encryptionMethod.ConfigStruct()
 
-	deps, diags := gohcl.VariablesInBody(cfg.Body, methodConfig)
+	deps, diags := configVariables(cfg.Body, methodConfig)
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -55,7 +55,7 @@ func setupMethod(enc *config.EncryptionConfig, cfg config.MethodConfig, meta key
 		return nil, diags
 	}
 
-	hclCtx, kpDiags := setupKeyProviders(enc, kpConfigs, meta, reg, staticEval)
+	hclCtx, kpDiags := setupKeyProviders(ctx, enc, kpConfigs, meta, reg, staticEval, workspace)
 	diags = diags.Extend(kpDiags)
 	if diags.HasErrors() {
 		return nil, diags
@@ -71,7 +71,7 @@ func setupMethod(enc *config.EncryptionConfig, cfg config.MethodConfig, meta key
 		return nil, diags
 	}
 
-	methodDiags := gohcl.DecodeBody(cfg.Body, hclCtx, methodConfig)
+	methodDiags := decodeConfig(cfg.Body, hclCtx, methodConfig)
 	diags = diags.Extend(methodDiags)
 	if diags.HasErrors() {
 		return nil, diags
@@ -6,6 +6,7 @@
 package encryption
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
@@ -15,6 +16,8 @@ import (
 
 // PlanEncryption describes the methods that you can use for encrypting a plan file. Plan files are opaque values with
 // no standardized format, so the encrypted form should be treated equally an opaque value.
+//
+// The context passed to each method is the parent of the telemetry spans of the operation.
 type PlanEncryption interface {
 	// EncryptPlan encrypts a plan file and returns the encrypted form.
 	//
@@ -29,7 +32,7 @@ type PlanEncryption interface {
 	// Make sure that you pass a valid plan file as an input. Failing to provide a valid plan file may result in an
 	// error. However, output values may not be valid plan files and you should not pass the encrypted plan file to any
 	// additional functions that normally work with plan files.
-	EncryptPlan([]byte) ([]byte, error)
+	EncryptPlan(context.Context, []byte) ([]byte, error)
 
 	// DecryptPlan decrypts an encrypted plan file.
 	//
@@ -43,7 +46,7 @@ type PlanEncryption interface {
 	//
 	// Pass a potentially encrypted plan file as an input, and you will receive the decrypted plan file or an error as
 	// a result.
-	DecryptPlan([]byte) ([]byte, error)
+	DecryptPlan(context.Context, []byte) ([]byte, error)
 }
 
 type planEncryption struct {
@@ -51,16 +54,16 @@ type planEncryption struct {
 }
 
 func newPlanEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (PlanEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
+	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval, staticEval.Workspace())
 	return &planEncryption{base}, diags
 }
 
-func (p planEncryption) EncryptPlan(data []byte) ([]byte, error) {
-	return p.base.encrypt(data, func(base basedata) interface{} { return base })
+func (p planEncryption) EncryptPlan(ctx context.Context, data []byte) ([]byte, error) {
+	return p.base.encrypt(ctx, data, func(base basedata) interface{} { return base })
 }
 
-func (p planEncryption) DecryptPlan(data []byte) ([]byte, error) {
-	data, _, err := p.base.decrypt(data, func(data []byte) error {
+func (p planEncryption) DecryptPlan(ctx context.Context, data []byte) ([]byte, error) {
+	data, _, err := p.base.decrypt(ctx, data, func(data []byte) error {
 		// Check magic bytes
 		if len(data) < 2 || string(data[:2]) != "PK" {
 			return fmt.Errorf("Invalid plan file %v", string(data[:2]))
@@ -76,9 +79,9 @@ func PlanEncryptionDisabled() PlanEncryption {
 
 type planDisabled struct{}
 
-func (s *planDisabled) EncryptPlan(plainPlan []byte) ([]byte, error) {
+func (s *planDisabled) EncryptPlan(_ context.Context, plainPlan []byte) ([]byte, error) {
 	return plainPlan, nil
 }
-func (s *planDisabled) DecryptPlan(encryptedPlan []byte) ([]byte, error) {
+func (s *planDisabled) DecryptPlan(_ context.Context, encryptedPlan []byte) ([]byte, error) {
 	return encryptedPlan, nil
 }
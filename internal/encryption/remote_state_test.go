@@ -0,0 +1,148 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestRemoteStatePerDataSource(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	// Each team encrypts its own state with its own key provider type.
+	teamA := testRemoteStateEncryption(t, reg, `
+		key_provider "pbkdf2" "team" {
+			passphrase = "Team A has a long passphrase"
+		}
+		method "aes_gcm" "team" {
+			keys = key_provider.pbkdf2.team
+		}
+		state {
+			method = method.aes_gcm.team
+		}`)
+	teamB := testRemoteStateEncryption(t, reg, `
+		key_provider "static" "team" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "team" {
+			keys = key_provider.static.team
+		}
+		state {
+			method = method.aes_gcm.team
+		}`)
+
+	// The consumer reads both states, and uses a different key for each.
+	consumer := testRemoteStateEncryption(t, reg, `
+		key_provider "pbkdf2" "team_a" {
+			passphrase               = "Team A has a long passphrase"
+			encrypted_metadata_alias = "key_provider.pbkdf2.team"
+		}
+		key_provider "static" "team_b" {
+			key                      = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+			encrypted_metadata_alias = "key_provider.static.team"
+		}
+		method "aes_gcm" "team_a" {
+			keys = key_provider.pbkdf2.team_a
+		}
+		method "aes_gcm" "team_b" {
+			keys = key_provider.static.team_b
+		}
+		remote_state_data_sources {
+			remote_state_data_source "team_a" {
+				method = method.aes_gcm.team_a
+			}
+			remote_state_data_source "network.team_b" {
+				method = method.aes_gcm.team_b
+			}
+		}`)
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	stateA, err := teamA.State().EncryptState(context.Background(), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateB, err := teamB.State().EncryptState(context.Background(), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		names   []string
+		state   []byte
+		wantErr bool
+	}{
+		"team A": {
+			names: []string{"team_a"},
+			state: stateA,
+		},
+		"team A instance falls back to the data source": {
+			names: []string{`team_a["x"]`, "team_a"},
+			state: stateA,
+		},
+		"team B in a module instance falls back to the module": {
+			names: []string{"network[0].team_b", "network.team_b"},
+			state: stateB,
+		},
+		"team B key cannot read team A state": {
+			names:   []string{"network.team_b"},
+			state:   stateA,
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, err := consumer.RemoteState(test.names...).DecryptState(context.Background(), test.state)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(testData) {
+				t.Fatalf("incorrect decrypted state: %s", got)
+			}
+		})
+	}
+}
+
+func testRemoteStateEncryption(t *testing.T, reg registry.Registry, src string) Encryption {
+	t.Helper()
+
+	cfg, diags := config.LoadConfigFromString("test", src)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return enc
+}
@@ -6,6 +6,7 @@
 package encryption
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -15,6 +16,8 @@ import (
 )
 
 // StateEncryption describes the interface for encrypting state files.
+//
+// The context passed to each method is the parent of the telemetry spans of the operation.
 type StateEncryption interface {
 	// DecryptState decrypts a potentially encrypted state file and returns a valid JSON-serialized state file.
 	//
@@ -32,7 +35,7 @@ type StateEncryption interface {
 	// function. Do not attempt to determine if the state file is encrypted as this function will take care of any
 	// and all encryption-related matters. After the function returns, use the returned byte array as a normal state
 	// file.
-	DecryptState([]byte) ([]byte, EncryptionStatus, error)
+	DecryptState(context.Context, []byte) ([]byte, EncryptionStatus, error)
 
 	// EncryptState encrypts a state file and returns the encrypted form.
 	//
@@ -51,16 +54,37 @@ type StateEncryption interface {
 	// Pass in a valid JSON-serialized state file as an input and store the output. Note that you should not pass the
 	// output to any additional functions that require a valid state file as it may not contain the fields typically
 	// present in a state file.
-	EncryptState([]byte) ([]byte, error)
+	EncryptState(context.Context, []byte) ([]byte, error)
 }
 
 type stateEncryption struct {
 	base *baseEncryption
+
+	// sensitiveOnly causes EncryptState to only encrypt the sensitive values and leave the rest of the state as
+	// plaintext. DecryptState supports both forms regardless of this setting.
+	sensitiveOnly bool
+
+	// enforced is set when the encryption block is enforced, in which case state files must not be written without
+	// encryption anywhere. See PlaintextUnlessEnforced.
+	enforced bool
+
+	// shared holds an encryptor for each method listed in share_output_keys. EncryptState additionally encrypts the
+	// outputs of the state with each of them.
+	shared []*baseEncryption
+
+	// readSharedOutputs allows DecryptState to return only the outputs of a state that it cannot decrypt as a whole,
+	// if they were shared with one of its methods. This is only set for remote state data sources, as OpenTofu must
+	// never write back a state that was read this way.
+	readSharedOutputs bool
+
+	// workspaces sets up the state encryption for the states of other workspaces if any of the key providers derive
+	// their keys from the workspace, and is nil otherwise. See StateEncryptionForWorkspace.
+	workspaces *workspaceStateEncryptions
 }
 
-func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
-	return &stateEncryption{base}, diags
+func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator, workspace string) (*stateEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval, workspace)
+	return &stateEncryption{base: base}, diags
 }
 
 type statedata struct {
@@ -68,27 +92,50 @@ type statedata struct {
 	Lineage string `json:"lineage"`
 }
 
-func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
+func (s *stateEncryption) EncryptState(ctx context.Context, plainState []byte) ([]byte, error) {
 	var passthrough statedata
 	err := json.Unmarshal(plainState, &passthrough)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.base.encrypt(plainState, func(base basedata) interface{} {
-		// Merge together the base encryption data and the passthrough fields
-		return struct {
-			statedata
-			basedata
-		}{
-			statedata: passthrough,
-			basedata:  base,
+	var encrypted []byte
+	if s.sensitiveOnly {
+		encrypted, err = s.encryptSensitiveValues(ctx, plainState)
+	} else {
+		encrypted, err = s.base.encrypt(ctx, plainState, func(base basedata) interface{} {
+			// Merge together the base encryption data and the passthrough fields
+			return struct {
+				statedata
+				basedata
+			}{
+				statedata: passthrough,
+				basedata:  base,
+			}
+		})
+	}
+	if err != nil || len(s.shared) == 0 {
+		return encrypted, err
+	}
+	return s.addSharedOutputs(ctx, plainState, encrypted)
+}
+
+func (s *stateEncryption) DecryptState(ctx context.Context, encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	decryptedState, status, err := s.decryptState(ctx, encryptedState)
+	if err != nil && s.readSharedOutputs && hasSharedOutputs(encryptedState) {
+		if outputs, sharedStatus, sharedErr := s.decryptSharedOutputs(ctx, encryptedState); sharedErr == nil {
+			return outputs, sharedStatus, nil
 		}
-	})
+	}
+	return decryptedState, status, err
 }
 
-func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
-	decryptedState, status, err := s.base.decrypt(encryptedState, func(data []byte) error {
+func (s *stateEncryption) decryptState(ctx context.Context, encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	if hasEncryptedSensitiveValues(encryptedState) {
+		return s.decryptSensitiveValues(ctx, encryptedState)
+	}
+
+	decryptedState, status, err := s.base.decrypt(ctx, encryptedState, func(data []byte) error {
 		tmp := struct {
 			FormatVersion string `json:"terraform_version"`
 		}{}
@@ -139,9 +186,12 @@ func StateEncryptionDisabled() StateEncryption {
 
 type stateDisabled struct{}
 
-func (s *stateDisabled) EncryptState(plainState []byte) ([]byte, error) {
+func (s *stateDisabled) EncryptState(_ context.Context, plainState []byte) ([]byte, error) {
 	return plainState, nil
 }
-func (s *stateDisabled) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
+func (s *stateDisabled) DecryptState(_ context.Context, encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	if hasEncryptedSensitiveValues(encryptedState) {
+		return nil, StatusUnknown, fmt.Errorf("the state contains encrypted sensitive values, but no state encryption is configured")
+	}
 	return encryptedState, StatusSatisfied, nil
 }
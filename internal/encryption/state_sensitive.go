@@ -0,0 +1,323 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+)
+
+// sensitiveValuesField is the top level field of a state file in which the sensitive values are stored when the
+// state target is configured with sensitive_only = true. Its presence identifies such a state file.
+const sensitiveValuesField = "encrypted_sensitive_values"
+
+// sensitiveValue is a single value that was removed from the state before encryption. The pointer is a JSON pointer
+// (RFC 6901) to the location of the value in the state file.
+type sensitiveValue struct {
+	Pointer string          `json:"pointer"`
+	Value   json.RawMessage `json:"value"`
+}
+
+func hasEncryptedSensitiveValues(state []byte) bool {
+	// Avoid parsing every unencrypted state file twice.
+	if !bytes.Contains(state, []byte(`"`+sensitiveValuesField+`"`)) {
+		return false
+	}
+	var tmp map[string]json.RawMessage
+	if err := json.Unmarshal(state, &tmp); err != nil {
+		return false
+	}
+	_, ok := tmp[sensitiveValuesField]
+	return ok
+}
+
+// encryptSensitiveValues replaces the values of sensitive outputs and sensitive resource instance attributes with
+// null and stores them, encrypted, in the sensitiveValuesField of the state. The remainder of the state is kept in
+// plaintext and is indented so that changes to it can be reviewed line by line.
+func (s *stateEncryption) encryptSensitiveValues(ctx context.Context, plainState []byte) ([]byte, error) {
+	if unencrypted.IsConfig(s.base.methods[0]) {
+		return plainState, nil
+	}
+
+	state, err := decodeGenericJSON(plainState)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := state.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("given payload is not a state file")
+	}
+
+	values, err := extractSensitiveValues(root)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.base.encrypt(ctx, payload, func(base basedata) interface{} {
+		return base
+	})
+	if err != nil {
+		return nil, err
+	}
+	root[sensitiveValuesField] = json.RawMessage(encrypted)
+
+	result, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode state as json: %w", err)
+	}
+	return append(result, '\n'), nil
+}
+
+func (s *stateEncryption) decryptSensitiveValues(ctx context.Context, encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	state, err := decodeGenericJSON(encryptedState)
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+	root := state.(map[string]interface{})
+	encrypted, err := json.Marshal(root[sensitiveValuesField])
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+	delete(root, sensitiveValuesField)
+	delete(root, sharedOutputsField)
+
+	payload, status, err := s.base.decrypt(ctx, encrypted, func([]byte) error {
+		return fmt.Errorf("the %s field of the state does not contain an encrypted payload", sensitiveValuesField)
+	})
+	if err != nil {
+		return nil, status, err
+	}
+
+	var values []sensitiveValue
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, StatusUnknown, fmt.Errorf("invalid encrypted sensitive values: %w", err)
+	}
+	// Restore in reverse order, so that a value nested inside another sensitive value is put back after its parent.
+	for i := len(values) - 1; i >= 0; i-- {
+		value, err := decodeGenericJSON(values[i].Value)
+		if err != nil {
+			return nil, StatusUnknown, err
+		}
+		if err := setJSONPointer(root, values[i].Pointer, value); err != nil {
+			return nil, StatusUnknown, fmt.Errorf("unable to restore sensitive value at %s: %w", values[i].Pointer, err)
+		}
+	}
+
+	result, err := json.Marshal(root)
+	if err != nil {
+		return nil, StatusUnknown, fmt.Errorf("unable to encode state as json: %w", err)
+	}
+	return result, status, nil
+}
+
+// extractSensitiveValues removes the sensitive values from the given state and returns them in the order they were
+// removed.
+func extractSensitiveValues(root map[string]interface{}) ([]sensitiveValue, error) {
+	var values []sensitiveValue
+	extract := func(parent interface{}, key interface{}, pointer string) error {
+		value := getChild(parent, key)
+		if value == nil {
+			// Nothing to protect
+			return nil
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		values = append(values, sensitiveValue{Pointer: pointer, Value: raw})
+		setChild(parent, key, nil)
+		return nil
+	}
+
+	if outputs, ok := root["outputs"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(outputs))
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			output, ok := outputs[name].(map[string]interface{})
+			if !ok || output["sensitive"] != true {
+				continue
+			}
+			if err := extract(output, "value", "/outputs/"+escapeJSONPointer(name)+"/value"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resources, _ := root["resources"].([]interface{})
+	for i, r := range resources {
+		resource, _ := r.(map[string]interface{})
+		instances, _ := resource["instances"].([]interface{})
+		for j, inst := range instances {
+			instance, _ := inst.(map[string]interface{})
+			paths, _ := instance["sensitive_attributes"].([]interface{})
+			for _, path := range paths {
+				steps, _ := path.([]interface{})
+				parent, key, pointer := resolveSensitivePath(instance, steps)
+				pointer = fmt.Sprintf("/resources/%d/instances/%d%s", i, j, pointer)
+				if err := extract(parent, key, pointer); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// resolveSensitivePath follows the steps of a sensitive attribute path, as stored in the sensitive_attributes field of
+// a resource instance, and returns the container and key of the value the path refers to, as well as the JSON pointer
+// to it relative to the instance. If a step cannot be followed, for example because it refers to an element of a set,
+// the deepest value that could be reached is returned instead so that the sensitive value is still covered.
+func resolveSensitivePath(instance map[string]interface{}, steps []interface{}) (interface{}, interface{}, string) {
+	var parent interface{} = instance
+	var key interface{} = "attributes"
+	pointer := "/attributes"
+
+	for _, s := range steps {
+		step, _ := s.(map[string]interface{})
+		current := getChild(parent, key)
+
+		var next interface{}
+		switch step["type"] {
+		case "get_attr":
+			name, ok := step["value"].(string)
+			if _, isObject := current.(map[string]interface{}); !ok || !isObject {
+				return parent, key, pointer
+			}
+			next = name
+		case "index":
+			stepKey, _ := step["value"].(map[string]interface{})
+			switch c := current.(type) {
+			case map[string]interface{}:
+				name, ok := stepKey["value"].(string)
+				if !ok {
+					return parent, key, pointer
+				}
+				next = name
+			case []interface{}:
+				num, ok := stepKey["value"].(json.Number)
+				if !ok {
+					return parent, key, pointer
+				}
+				idx, err := strconv.Atoi(num.String())
+				if err != nil || idx < 0 || idx >= len(c) {
+					return parent, key, pointer
+				}
+				next = idx
+			default:
+				return parent, key, pointer
+			}
+		default:
+			return parent, key, pointer
+		}
+
+		parent, key = current, next
+		switch k := next.(type) {
+		case string:
+			pointer += "/" + escapeJSONPointer(k)
+		case int:
+			pointer += "/" + strconv.Itoa(k)
+		}
+	}
+	return parent, key, pointer
+}
+
+func getChild(parent interface{}, key interface{}) interface{} {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			return p[k]
+		}
+	case []interface{}:
+		if k, ok := key.(int); ok && k >= 0 && k < len(p) {
+			return p[k]
+		}
+	}
+	return nil
+}
+
+func setChild(parent interface{}, key interface{}, value interface{}) bool {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			p[k] = value
+			return true
+		}
+	case []interface{}:
+		if k, ok := key.(int); ok && k >= 0 && k < len(p) {
+			p[k] = value
+			return true
+		}
+	}
+	return false
+}
+
+// setJSONPointer replaces the value the given JSON pointer refers to. All but the last token of the pointer must
+// refer to existing values.
+func setJSONPointer(root interface{}, pointer string, value interface{}) error {
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("invalid pointer")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+
+	var parent interface{}
+	var key interface{}
+	current := root
+	for _, token := range tokens {
+		token = unescapeJSONPointer(token)
+		switch c := current.(type) {
+		case map[string]interface{}:
+			key = token
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return fmt.Errorf("index %q out of range", token)
+			}
+			key = idx
+		default:
+			return fmt.Errorf("%q does not refer to an object or array", token)
+		}
+		parent = current
+		current = getChild(parent, key)
+	}
+	if !setChild(parent, key, value) {
+		return fmt.Errorf("invalid pointer")
+	}
+	return nil
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// decodeGenericJSON decodes JSON into the generic Go representation, keeping numbers as json.Number so that they
+// survive the round trip without losing precision.
+func decodeGenericJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var result interface{}
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
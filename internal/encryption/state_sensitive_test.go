@@ -0,0 +1,187 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+const testSensitiveState = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 3,
+  "lineage": "magic",
+  "outputs": {
+    "password": {"value": "hunter2", "type": "string", "sensitive": true},
+    "region": {"value": "eu-west-1", "type": "string"}
+  },
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "test_instance",
+      "name": "foo",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {
+            "id": "foo",
+            "big": 12345678901234567890,
+            "secret": "hunter3",
+            "tags": {"a/b": "hunter4", "b": "visible"},
+            "list": ["visible", "hunter5"],
+            "set": [{"key": "hunter6"}],
+            "block": [{"token": "hunter7", "name": "visible"}]
+          },
+          "sensitive_attributes": [
+            [{"type": "get_attr", "value": "secret"}],
+            [{"type": "get_attr", "value": "tags"}, {"type": "index", "value": {"value": "a/b", "type": "string"}}],
+            [{"type": "get_attr", "value": "list"}, {"type": "index", "value": {"value": 1, "type": "number"}}],
+            [{"type": "get_attr", "value": "set"}, {"type": "index", "value": {"value": {"key": "x"}, "type": ["object", {"key": "string"}]}}],
+            [{"type": "get_attr", "value": "block"}],
+            [{"type": "get_attr", "value": "block"}, {"type": "index", "value": {"value": 0, "type": "number"}}, {"type": "get_attr", "value": "token"}],
+            [{"type": "get_attr", "value": "missing"}]
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+func TestStateEncryptionSensitiveOnly(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	const keyConfig = `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}`
+	sensitiveOnly := testRemoteStateEncryption(t, reg, keyConfig+`
+		state {
+			method         = method.aes_gcm.example
+			sensitive_only = true
+		}`)
+	full := testRemoteStateEncryption(t, reg, keyConfig+`
+		state {
+			method = method.aes_gcm.example
+		}`)
+
+	encrypted, err := sensitiveOnly.State().EncryptState(context.Background(), []byte(testSensitiveState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "hunter3", "hunter4", "hunter5", "hunter6", "hunter7"} {
+		if strings.Contains(string(encrypted), secret) {
+			t.Errorf("encrypted state contains %q:\n%s", secret, encrypted)
+		}
+	}
+	for _, visible := range []string{`"region"`, `"eu-west-1"`, `"id": "foo"`, `"visible"`, `"lineage": "magic"`} {
+		if !strings.Contains(string(encrypted), visible) {
+			t.Errorf("encrypted state does not contain %s:\n%s", visible, encrypted)
+		}
+	}
+
+	for name, enc := range map[string]Encryption{"sensitive only": sensitiveOnly, "full": full} {
+		t.Run(name, func(t *testing.T) {
+			decrypted, status, err := enc.State().DecryptState(context.Background(), encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != StatusSatisfied {
+				t.Errorf("wrong status %v", status)
+			}
+			testAssertEqualJSON(t, testSensitiveState, string(decrypted))
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		_, _, err := StateEncryptionDisabled().DecryptState(context.Background(), encrypted)
+		if err == nil || !strings.Contains(err.Error(), "encrypted sensitive values") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+	})
+
+	t.Run("full state is still readable", func(t *testing.T) {
+		fullState, err := full.State().EncryptState(context.Background(), []byte(testSensitiveState))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, _, err := sensitiveOnly.State().DecryptState(context.Background(), fullState)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testAssertEqualJSON(t, testSensitiveState, string(decrypted))
+	})
+}
+
+func TestStateEncryptionSensitiveOnlyPlan(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("test", `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		plan {
+			method         = method.aes_gcm.example
+			sensitive_only = true
+		}`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	_, diags = New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if !diags.HasErrors() || !strings.Contains(diags.Error(), "only supported in the state block") {
+		t.Fatalf("expected an error, got %v", diags)
+	}
+}
+
+func testAssertEqualJSON(t *testing.T, want, got string) {
+	t.Helper()
+
+	wantVal, err := decodeGenericJSON([]byte(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotVal, err := decodeGenericJSON([]byte(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wantVal, gotVal) {
+		wantIndent, _ := json.MarshalIndent(wantVal, "", "  ")
+		gotIndent, _ := json.MarshalIndent(gotVal, "", "  ")
+		t.Errorf("wrong result\nwant: %s\ngot:  %s", wantIndent, gotIndent)
+	}
+}
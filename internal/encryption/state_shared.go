@@ -0,0 +1,175 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+)
+
+// sharedOutputsField is the top level field of a state file in which the outputs are stored, encrypted once for each
+// method listed in share_output_keys.
+const sharedOutputsField = "encrypted_shared_outputs"
+
+// sharedStateFields are the fields of the state that are copied into the outputs-only state shared with the methods
+// listed in share_output_keys.
+var sharedStateFields = []string{"version", "terraform_version", "serial", "lineage", "outputs"}
+
+// setupSharedOutputs prepares an encryptor for each method referenced in the share_output_keys expression.
+func (s *stateEncryption) setupSharedOutputs(enc *encryption, expr hcl.Expression, enforced bool, staticEval *configs.StaticEvaluator, workspace string) hcl.Diagnostics {
+	exprs, diags := hcl.ExprList(expr)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for i, methodExpr := range exprs {
+		name := fmt.Sprintf("state.share_output_keys[%d]", i)
+		base, baseDiags := newBaseEncryption(enc, &config.TargetConfig{Method: methodExpr}, enforced, name, staticEval, workspace)
+		diags = append(diags, baseDiags...)
+		if baseDiags.HasErrors() {
+			continue
+		}
+		if unencrypted.IsConfig(base.methods[0]) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unencrypted method is not allowed",
+				Detail:   "The share_output_keys argument must only reference methods that encrypt the outputs. Outputs that are not sensitive can be read without sharing them.",
+				Subject:  methodExpr.Range().Ptr(),
+			})
+			continue
+		}
+		s.shared = append(s.shared, base)
+	}
+	return diags
+}
+
+func hasSharedOutputs(state []byte) bool {
+	// Avoid parsing every state file twice.
+	if !bytes.Contains(state, []byte(`"`+sharedOutputsField+`"`)) {
+		return false
+	}
+	var tmp map[string]json.RawMessage
+	if err := json.Unmarshal(state, &tmp); err != nil {
+		return false
+	}
+	_, ok := tmp[sharedOutputsField]
+	return ok
+}
+
+// addSharedOutputs encrypts a copy of the state that only contains its outputs with each of the shared methods, and
+// stores the results in the sharedOutputsField of the already encrypted state.
+func (s *stateEncryption) addSharedOutputs(ctx context.Context, plainState []byte, encryptedState []byte) ([]byte, error) {
+	var plain map[string]json.RawMessage
+	if err := json.Unmarshal(plainState, &plain); err != nil {
+		return nil, err
+	}
+	outputsOnly := map[string]json.RawMessage{
+		"resources": json.RawMessage("[]"),
+	}
+	for _, field := range sharedStateFields {
+		if value, ok := plain[field]; ok {
+			outputsOnly[field] = value
+		}
+	}
+	if outputs, ok := outputsOnly["outputs"]; ok {
+		public, err := publicOutputs(outputs)
+		if err != nil {
+			return nil, err
+		}
+		outputsOnly["outputs"] = public
+	}
+	payload, err := json.Marshal(outputsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make([]json.RawMessage, 0, len(s.shared))
+	for _, base := range s.shared {
+		encrypted, err := base.encrypt(ctx, payload, func(base basedata) interface{} {
+			return base
+		})
+		if err != nil {
+			return nil, err
+		}
+		shared = append(shared, encrypted)
+	}
+
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(encryptedState, &root); err != nil {
+		return nil, fmt.Errorf("unable to decode encrypted state: %w", err)
+	}
+	root[sharedOutputsField], err = json.Marshal(shared)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.sensitiveOnly {
+		result, err := json.Marshal(root)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode state as json: %w", err)
+		}
+		return result, nil
+	}
+	// Keep the state readable line by line, see encryptSensitiveValues.
+	result, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode state as json: %w", err)
+	}
+	return append(result, '\n'), nil
+}
+
+// publicOutputs removes the outputs declared with internal = true from the outputs of a state, because they must not
+// be readable through remote state data sources.
+func publicOutputs(outputs json.RawMessage) (json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(outputs, &all); err != nil {
+		return nil, fmt.Errorf("unable to decode the outputs of the state: %w", err)
+	}
+	public := make(map[string]json.RawMessage, len(all))
+	for name, output := range all {
+		var flags struct {
+			Internal bool `json:"internal"`
+		}
+		if err := json.Unmarshal(output, &flags); err != nil {
+			return nil, fmt.Errorf("unable to decode output %q of the state: %w", name, err)
+		}
+		if !flags.Internal {
+			public[name] = output
+		}
+	}
+	return json.Marshal(public)
+}
+
+// decryptSharedOutputs attempts to decrypt the outputs-only copies of the state with the configured methods and
+// returns the first one that succeeds.
+func (s *stateEncryption) decryptSharedOutputs(ctx context.Context, encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	var root struct {
+		Shared []json.RawMessage `json:"encrypted_shared_outputs"`
+	}
+	if err := json.Unmarshal(encryptedState, &root); err != nil {
+		return nil, StatusUnknown, err
+	}
+
+	errs := make([]error, 0, len(root.Shared))
+	for _, shared := range root.Shared {
+		payload, status, err := s.base.decrypt(ctx, shared, func([]byte) error {
+			return fmt.Errorf("the %s field of the state does not contain an encrypted payload", sharedOutputsField)
+		})
+		if err == nil {
+			return payload, status, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, StatusUnknown, errors.Join(errs...)
+}
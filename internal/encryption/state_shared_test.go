@@ -0,0 +1,256 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/static"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func TestShareOutputKeys(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	ownerKeys := `
+		key_provider "static" "owner" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		key_provider "pbkdf2" "consumer" {
+			passphrase = "The consumer has a long passphrase"
+		}
+		method "aes_gcm" "owner" {
+			keys = key_provider.static.owner
+		}
+		method "aes_gcm" "consumer" {
+			keys = key_provider.pbkdf2.consumer
+		}`
+	owner := testRemoteStateEncryption(t, reg, ownerKeys+`
+		state {
+			method            = method.aes_gcm.owner
+			share_output_keys = [method.aes_gcm.consumer]
+		}`)
+	ownerSensitiveOnly := testRemoteStateEncryption(t, reg, ownerKeys+`
+		state {
+			method            = method.aes_gcm.owner
+			sensitive_only    = true
+			share_output_keys = [method.aes_gcm.consumer]
+		}`)
+	consumer := testRemoteStateEncryption(t, reg, `
+		key_provider "pbkdf2" "shared" {
+			passphrase               = "The consumer has a long passphrase"
+			encrypted_metadata_alias = "key_provider.pbkdf2.consumer"
+		}
+		method "aes_gcm" "shared" {
+			keys = key_provider.pbkdf2.shared
+		}
+		state {
+			method = method.aes_gcm.shared
+		}
+		remote_state_data_sources {
+			default {
+				method = method.aes_gcm.shared
+			}
+		}`)
+
+	wantOutputs := `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 3,
+  "lineage": "magic",
+  "outputs": {
+    "password": {"value": "hunter2", "type": "string", "sensitive": true},
+    "region": {"value": "eu-west-1", "type": "string"}
+  },
+  "resources": []
+}`
+
+	for name, enc := range map[string]Encryption{"full": owner, "sensitive_only": ownerSensitiveOnly} {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := enc.State().EncryptState(context.Background(), []byte(testSensitiveState))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(encrypted), "hunter2") {
+				t.Fatalf("the sensitive output is not encrypted: %s", encrypted)
+			}
+
+			// The owner still reads the whole state.
+			decrypted, _, err := enc.State().DecryptState(context.Background(), encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testAssertEqualJSON(t, testSensitiveState, string(decrypted))
+
+			// The consumer reads only the outputs through a remote state data source.
+			decrypted, status, err := consumer.RemoteState("network").DecryptState(context.Background(), encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != StatusSatisfied {
+				t.Errorf("wrong status %v", status)
+			}
+			testAssertEqualJSON(t, wantOutputs, string(decrypted))
+
+			// The shared key cannot be used to read the state as the owner.
+			if _, _, err := consumer.State().DecryptState(context.Background(), encrypted); err == nil {
+				t.Fatal("expected an error when decrypting the state with the shared key")
+			}
+		})
+	}
+}
+
+func TestShareOutputKeys_invalid(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	keys := `
+		key_provider "static" "owner" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "owner" {
+			keys = key_provider.static.owner
+		}
+		method "unencrypted" "plain" {}`
+
+	tests := map[string]struct {
+		src     string
+		wantErr string
+	}{
+		"unencrypted": {
+			src: `
+				state {
+					method            = method.aes_gcm.owner
+					share_output_keys = [method.unencrypted.plain]
+				}`,
+			wantErr: "Unencrypted method is not allowed",
+		},
+		"undeclared method": {
+			src: `
+				state {
+					method            = method.aes_gcm.owner
+					share_output_keys = [method.aes_gcm.missing]
+				}`,
+			wantErr: "Reference to undeclared encryption method",
+		},
+		"plan": {
+			src: `
+				plan {
+					method            = method.aes_gcm.owner
+					share_output_keys = [method.aes_gcm.owner]
+				}`,
+			wantErr: "The share_output_keys argument is only supported in the state block",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, diags := config.LoadConfigFromString("test", keys+test.src)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			_, diags = New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+			for _, diag := range diags {
+				if strings.Contains(diag.Summary+" "+diag.Detail, test.wantErr) {
+					return
+				}
+			}
+			t.Fatalf("expected %q, got: %v", test.wantErr, diags)
+		})
+	}
+}
+
+func TestShareOutputKeys_internalOutputs(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+
+	keys := `
+		key_provider "static" "owner" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		key_provider "static" "consumer" {
+			key = "3169686565796f6f72653169616872756f37517561656f67686f6834366f6f70"
+		}
+		method "aes_gcm" "owner" {
+			keys = key_provider.static.owner
+		}
+		method "aes_gcm" "consumer" {
+			keys = key_provider.static.consumer
+		}`
+	owner := testRemoteStateEncryption(t, reg, keys+`
+		state {
+			method            = method.aes_gcm.owner
+			share_output_keys = [method.aes_gcm.consumer]
+		}`)
+	consumer := testRemoteStateEncryption(t, reg, keys+`
+		remote_state_data_sources {
+			default {
+				method = method.aes_gcm.consumer
+			}
+		}`)
+
+	state := `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 1,
+  "lineage": "magic",
+  "outputs": {
+    "endpoint": {"value": "https://example.com", "type": "string"},
+    "bootstrap_token": {"value": "internal-token", "type": "string", "internal": true}
+  },
+  "resources": []
+}`
+	encrypted, err := owner.State().EncryptState(context.Background(), []byte(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, _, err := consumer.RemoteState("network").DecryptState(context.Background(), encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAssertEqualJSON(t, `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "serial": 1,
+  "lineage": "magic",
+  "outputs": {
+    "endpoint": {"value": "https://example.com", "type": "string"}
+  },
+  "resources": []
+}`, string(decrypted))
+}
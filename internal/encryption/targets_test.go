@@ -6,6 +6,7 @@
 package encryption
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -364,7 +365,7 @@ func (testCase btmTestCase) newTestRun(reg registry.Registry, staticEval *config
 		var methods []method.Method
 		methodConfigs, diags := methodConfigsFromTarget(cfg, target, "test", cfg.State.Enforced)
 		for _, methodConfig := range methodConfigs {
-			m, mDiags := setupMethod(cfg, methodConfig, meta, reg, staticEval)
+			m, mDiags := setupMethod(context.Background(), cfg, methodConfig, meta, reg, staticEval, staticEval.Workspace())
 			diags = diags.Extend(mDiags)
 			if !mDiags.HasErrors() {
 				methods = append(methods, m)
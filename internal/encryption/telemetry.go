@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+const telemetryName = "github.com/opentofu/opentofu/internal/encryption"
+
+var (
+	tracer trace.Tracer
+
+	keyProviderDuration metric.Float64Histogram
+	methodDuration      metric.Float64Histogram
+	methodBytes         metric.Int64Counter
+)
+
+func init() {
+	tracer = otel.Tracer(telemetryName)
+
+	meter := otel.Meter(telemetryName)
+	var err error
+	keyProviderDuration, err = meter.Float64Histogram(
+		"opentofu.encryption.key_provider.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the calls to key providers."),
+	)
+	if err != nil {
+		panic(err)
+	}
+	methodDuration, err = meter.Float64Histogram(
+		"opentofu.encryption.method.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of the encrypt and decrypt calls to encryption methods."),
+	)
+	if err != nil {
+		panic(err)
+	}
+	methodBytes, err = meter.Int64Counter(
+		"opentofu.encryption.method.bytes",
+		metric.WithUnit("By"),
+		metric.WithDescription("Number of bytes passed to the encrypt and decrypt calls of encryption methods."),
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// traceKeyProvider runs the given call to the Provide function of a key provider in a span and records its duration.
+// The call receives the context of the span so that the calls the key provider makes to remote services are traced as
+// its children.
+func traceKeyProvider(ctx context.Context, cfg config.KeyProviderConfig, provide func(ctx context.Context) error) error {
+	attrs := []attribute.KeyValue{
+		attribute.String("opentofu.encryption.key_provider.type", cfg.Type),
+		attribute.String("opentofu.encryption.key_provider.name", cfg.Name),
+	}
+	ctx, span := tracer.Start(ctx, "provide encryption key", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	err := provide(ctx)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		span.SetStatus(codes.Error, "key provider failed")
+	}
+	attrs = append(attrs, attribute.Bool("error", err != nil))
+	keyProviderDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+	return err
+}
+
+// traceMethod runs the given encrypt or decrypt call of an encryption method in a span that records the size of its
+// input and output, and records its duration and the number of input bytes.
+func traceMethod(ctx context.Context, operation string, target string, cfg config.MethodConfig, input []byte, call func([]byte) ([]byte, error)) ([]byte, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("opentofu.encryption.operation", operation),
+		attribute.String("opentofu.encryption.target", target),
+		attribute.String("opentofu.encryption.method.type", cfg.Type),
+		attribute.String("opentofu.encryption.method.name", cfg.Name),
+	}
+	ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	output, err := call(input)
+	elapsed := time.Since(start)
+
+	span.SetAttributes(
+		attribute.Int("opentofu.encryption.input_bytes", len(input)),
+		attribute.Int("opentofu.encryption.output_bytes", len(output)),
+	)
+	if err != nil {
+		span.SetStatus(codes.Error, operation+" failed")
+	}
+	attrs = append(attrs, attribute.Bool("error", err != nil))
+	methodDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+	methodBytes.Add(ctx, int64(len(input)), metric.WithAttributes(attrs...))
+	return output, err
+}
@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+func TestTelemetry(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	meter := &testMeter{recorded: map[string]int{}}
+	otel.SetMeterProvider(testMeterProvider{meter: meter})
+
+	reg := testEnforcedRegistry()
+	cfg, diags := config.LoadConfigFromString("test", testEnforcedKeyConfig+`
+		state {
+			method = method.aes_gcm.example
+		}`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "test")
+	encrypted, err := enc.State().EncryptState(ctx, []byte(testPlainState))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := enc.State().DecryptState(ctx, encrypted); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	counts := map[string]int{}
+	rootKeyProviderSpans := 0
+	for _, span := range recorder.Ended() {
+		if span.Name() == "test" {
+			continue
+		}
+		if !span.Parent().IsValid() && span.Name() == "provide encryption key" {
+			// The key for encryption is provided when setting up the encryption, outside any operation.
+			rootKeyProviderSpans++
+		} else {
+			counts[span.Name()]++
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span %q is not a child of the given context", span.Name())
+			}
+		}
+
+		attrs := attribute.NewSet(span.Attributes()...)
+		switch span.Name() {
+		case "provide encryption key":
+			if v, _ := attrs.Value("opentofu.encryption.key_provider.type"); v.AsString() != "static" {
+				t.Errorf("wrong key provider type %q", v.AsString())
+			}
+		case "encrypt", "decrypt":
+			if v, _ := attrs.Value("opentofu.encryption.method.type"); v.AsString() != "aes_gcm" {
+				t.Errorf("wrong method type %q", v.AsString())
+			}
+			if v, _ := attrs.Value("opentofu.encryption.target"); v.AsString() != "state" {
+				t.Errorf("wrong target %q", v.AsString())
+			}
+			if v, ok := attrs.Value("opentofu.encryption.input_bytes"); !ok || v.AsInt64() == 0 {
+				t.Errorf("missing input size on %q span", span.Name())
+			}
+		default:
+			t.Errorf("unexpected span %q", span.Name())
+		}
+	}
+
+	if rootKeyProviderSpans != 1 {
+		t.Errorf("wrong number of key provider spans without parent %d; want 1", rootKeyProviderSpans)
+	}
+	// Decryption provides the key again, using the key provider metadata stored in the state.
+	want := map[string]int{"provide encryption key": 1, "encrypt": 1, "decrypt": 1}
+	for name, count := range want {
+		if counts[name] != count {
+			t.Errorf("wrong number of %q spans %d; want %d", name, counts[name], count)
+		}
+	}
+
+	wantMetrics := map[string]int{
+		"opentofu.encryption.key_provider.duration": 2,
+		"opentofu.encryption.method.duration":       2,
+		"opentofu.encryption.method.bytes":          2,
+	}
+	for name, count := range wantMetrics {
+		if got := meter.count(name); got != count {
+			t.Errorf("wrong number of %q measurements %d; want %d", name, got, count)
+		}
+	}
+}
+
+func TestTraceKeyProvider_context(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(telemetryName)
+	t.Cleanup(func() {
+		tracer = otel.Tracer(telemetryName)
+	})
+
+	var providerSpan trace.SpanContext
+	err := traceKeyProvider(context.Background(), config.KeyProviderConfig{Type: "static", Name: "basic"}, func(ctx context.Context) error {
+		providerSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("wrong number of spans %d; want 1", len(spans))
+	}
+	if !providerSpan.Equal(spans[0].SpanContext()) {
+		t.Errorf("the key provider was not called with the context of the %q span", spans[0].Name())
+	}
+}
+
+// testMeterProvider hands out a testMeter that counts the measurements of each instrument.
+type testMeterProvider struct {
+	noop.MeterProvider
+
+	meter *testMeter
+}
+
+func (p testMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+type testMeter struct {
+	noop.Meter
+
+	mu       sync.Mutex
+	recorded map[string]int
+}
+
+func (m *testMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return testFloat64Histogram{name: name, meter: m}, nil
+}
+
+func (m *testMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return testInt64Counter{name: name, meter: m}, nil
+}
+
+func (m *testMeter) record(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorded[name]++
+}
+
+func (m *testMeter) count(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.recorded[name]
+}
+
+type testFloat64Histogram struct {
+	noop.Float64Histogram
+
+	name  string
+	meter *testMeter
+}
+
+func (h testFloat64Histogram) Record(context.Context, float64, ...metric.RecordOption) {
+	h.meter.record(h.name)
+}
+
+type testInt64Counter struct {
+	noop.Int64Counter
+
+	name  string
+	meter *testMeter
+}
+
+func (c testInt64Counter) Add(context.Context, int64, ...metric.AddOption) {
+	c.meter.record(c.name)
+}
@@ -6,7 +6,6 @@
 package genconfig
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -15,10 +14,10 @@ import (
 	hclsyntax "github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
-	"github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/configs/configwrite"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -56,16 +55,17 @@ func GenerateResourceContents(addr addrs.AbsResourceInstance,
 	return string(formatted), diags
 }
 
+// WrapResourceContents places an OpenTofu resource header around the
+// attributes and blocks returned by GenerateResourceContents.
 func WrapResourceContents(addr addrs.AbsResourceInstance, config string) string {
-	var buf strings.Builder
-
-	buf.WriteString(fmt.Sprintf("resource %q %q {\n", addr.Resource.Resource.Type, addr.Resource.Resource.Name))
-	buf.WriteString(config)
-	buf.WriteString("}")
-
-	// The output better be valid HCL which can be parsed and formatted.
-	formatted := hclwrite.Format([]byte(buf.String()))
-	return string(formatted)
+	block := configwrite.NewResourceBlock(addr.Resource.Resource)
+	if err := block.Body().AppendRaw(config); err != nil {
+		// The generated contents are always valid HCL tokens, so this
+		// should never happen.
+		panic(fmt.Sprintf("failed to wrap generated config for %s: %s", addr, err))
+	}
+	// Callers add their own separation between generated blocks.
+	return strings.TrimSuffix(string(block.Bytes()), "\n")
 }
 
 func writeConfigAttributes(addr addrs.AbsResourceInstance, buf *strings.Builder, attrs map[string]*configschema.Attribute, indent int) tfdiags.Diagnostics {
@@ -180,7 +180,7 @@ func writeConfigAttributesFromExisting(addr addrs.AbsResourceInstance, buf *stri
 					}
 				}
 
-				tok := tryWrapAsJsonEncodeFunctionCall(val)
+				tok := configwrite.TokensForValue(val)
 				if _, err := tok.WriteTo(buf); err != nil {
 					diags = diags.Append(&hcl.Diagnostic{
 						Severity: hcl.DiagWarning,
@@ -614,40 +614,3 @@ func omitUnknowns(val cty.Value) cty.Value {
 		panic(fmt.Sprintf("omitUnknowns cannot handle %#v", val))
 	}
 }
-
-func tryWrapAsJsonEncodeFunctionCall(v cty.Value) hclwrite.Tokens {
-	tokens, err := wrapAsJSONEncodeFunctionCall(v)
-	if err != nil {
-		return hclwrite.TokensForValue(v)
-	}
-	return tokens
-}
-
-func wrapAsJSONEncodeFunctionCall(v cty.Value) (hclwrite.Tokens, error) {
-	if v.IsNull() || v.Type() != cty.String || !v.IsKnown() {
-		return nil, errors.New("value cannot be treated as JSON string")
-	}
-
-	s := []byte(strings.TrimSpace(v.AsString()))
-	if len(s) == 0 {
-		return nil, errors.New("empty value")
-	}
-
-	if s[0] != '{' && s[0] != '[' {
-		return nil, errors.New("value is not a JSON object, nor a JSON array")
-	}
-
-	t, err := json.ImpliedType(s)
-	if err != nil {
-		return nil, fmt.Errorf("cannot define implied cty type (possibly not a JSON string): %w", err)
-	}
-
-	v, err = json.Unmarshal(s, t)
-	if err != nil {
-		return nil, fmt.Errorf("cannot unmarshal using implied type (possible not a JSON string): %w", err)
-	}
-
-	tokens := hclwrite.TokensForFunctionCall("jsonencode", hclwrite.TokensForValue(v))
-
-	return tokens, nil
-}
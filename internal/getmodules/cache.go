@@ -0,0 +1,240 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/replacefile"
+)
+
+// PackageCache is a directory of module packages that can be shared between
+// many working directories on the same computer, so that a package that is
+// used by several configurations only needs to be downloaded once.
+//
+// The cache is content-addressed: each package is stored only once, in a
+// directory named after its hash, and a separate index maps the keys that
+// callers look packages up by to those hashes. A key must identify exactly
+// one package content, such as a registry module address together with a
+// version, so packages whose content can change over time, such as those
+// selected by a git branch name, must not be cached.
+//
+// The layout of the cache directory is:
+//
+//	packages/<hash>/...  the content of each package
+//	index/<key hash>     the package hash for each key
+//
+// The index entry for a key is written only after the package content is
+// complete, so several OpenTofu processes can safely share a cache.
+type PackageCache struct {
+	dir string
+}
+
+// NewPackageCache returns a PackageCache for the given directory, which
+// should already exist.
+func NewPackageCache(dir string) *PackageCache {
+	return &PackageCache{dir: dir}
+}
+
+// Install installs the package that the cache has for the given key into the
+// given installation directory, which must not already exist. It returns
+// false if the cache has no package for the key.
+//
+// Where possible, the installation directory becomes a symbolic link to the
+// package in the cache, and so the installed package must not be modified.
+// Otherwise, Install copies the package.
+//
+// Before installing a package, Install verifies that its content still
+// matches its hash, and it treats a package that has been modified as
+// missing from the cache.
+func (c *PackageCache) Install(instDir, key string) (bool, error) {
+	raw, err := os.ReadFile(c.indexPath(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read module cache index: %w", err)
+	}
+	hash := strings.TrimSpace(string(raw))
+	pkgDir, err := c.packagePath(hash)
+	if err != nil {
+		return false, fmt.Errorf("invalid module cache index entry for %s: %w", key, err)
+	}
+
+	got, err := PackageHash(pkgDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to verify cached module package: %w", err)
+	}
+	if got != hash {
+		log.Printf("[WARN] getmodules: cached package for %s in %s has been modified, so ignoring it", key, pkgDir)
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(instDir), os.ModePerm); err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(instDir), err)
+	}
+	absPkgDir, err := filepath.Abs(pkgDir)
+	if err != nil {
+		return false, err
+	}
+	err = os.Symlink(absPkgDir, instDir)
+	if err == nil {
+		log.Printf("[TRACE] getmodules: linked %s to cached package for %s in %s", instDir, key, pkgDir)
+		return true, nil
+	}
+	// Creating symbolic links requires special privileges on some versions
+	// of Windows, so we'll fall back on copying.
+	log.Printf("[TRACE] getmodules: failed to link %s to the module cache, so copying instead: %s", instDir, err)
+	if err := copyPackage(instDir, pkgDir); err != nil {
+		return false, fmt.Errorf("failed to copy cached module package: %w", err)
+	}
+	log.Printf("[TRACE] getmodules: copied cached package for %s from %s to %s", key, pkgDir, instDir)
+	return true, nil
+}
+
+// Store adds the package installed in the given directory to the cache for
+// the given key, so that a later call to Install can install it elsewhere.
+//
+// The installation directory is left unchanged.
+func (c *PackageCache) Store(instDir, key string) error {
+	hash, err := PackageHash(instDir)
+	if err != nil {
+		return fmt.Errorf("failed to hash module package: %w", err)
+	}
+	pkgDir, err := c.packagePath(hash)
+	if err != nil {
+		// Should never happen, because we just computed the hash.
+		return err
+	}
+
+	if _, err := os.Stat(pkgDir); os.IsNotExist(err) {
+		// We'll copy the package into a temporary directory first and then
+		// move it into place, so that a concurrent Install can never find
+		// an incomplete package.
+		packagesDir := filepath.Dir(pkgDir)
+		if err := os.MkdirAll(packagesDir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create module cache directory: %w", err)
+		}
+		tmpDir, err := os.MkdirTemp(packagesDir, ".tmp-")
+		if err != nil {
+			return fmt.Errorf("failed to create module cache directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		if err := copyPackage(tmpDir, instDir); err != nil {
+			return fmt.Errorf("failed to copy module package into the cache: %w", err)
+		}
+		if err := os.Rename(tmpDir, pkgDir); err != nil {
+			// Another process might have stored the same package
+			// concurrently, in which case we can use that.
+			if _, statErr := os.Stat(pkgDir); statErr != nil {
+				return fmt.Errorf("failed to add module package to the cache: %w", err)
+			}
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to read module cache: %w", err)
+	}
+
+	indexPath := c.indexPath(key)
+	if err := os.MkdirAll(filepath.Dir(indexPath), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create module cache index directory: %w", err)
+	}
+	if err := replacefile.AtomicWriteFile(indexPath, []byte(hash+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to update module cache index: %w", err)
+	}
+	log.Printf("[TRACE] getmodules: stored package for %s in module cache at %s", key, pkgDir)
+	return nil
+}
+
+// indexPath returns the path of the index entry for the given key. Keys are
+// arbitrary strings, so the entry is named after a hash of the key.
+func (c *PackageCache) indexPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, "index", hex.EncodeToString(sum[:]))
+}
+
+// packagePath returns the directory for the package with the given hash, as
+// returned by PackageHash.
+func (c *PackageCache) packagePath(hash string) (string, error) {
+	if err := ValidatePackageHash(hash); err != nil {
+		return "", err
+	}
+	// The hash is base64, which isn't safe to use in filenames, so we'll
+	// use hexadecimal instead.
+	sum, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, packageHashSchemePrefix))
+	return filepath.Join(c.dir, "packages", hex.EncodeToString(sum)), nil
+}
+
+// copyPackage copies the content of the module package in the src directory
+// into the dst directory, which must either not exist or be empty.
+//
+// Unlike copy.CopyDir, copyPackage copies every file that PackageHash takes
+// into account, including "dot files", so that the copy has the same hash as
+// the original.
+func copyPackage(dst, src string) error {
+	src, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			if packageHashIgnoredDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, os.ModePerm)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return copyPackageFile(target, path, info.Mode().Perm())
+		}
+	})
+}
+
+// copyPackageFile copies a single file for copyPackage.
+func copyPackageFile(dst, src string, mode fs.FileMode) error {
+	srcF, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcF.Close()
+
+	dstF, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dstF, srcF); err != nil {
+		dstF.Close()
+		return err
+	}
+	return dstF.Close()
+}
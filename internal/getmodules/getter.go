@@ -15,6 +15,7 @@ import (
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	getter "github.com/hashicorp/go-getter"
 	"github.com/opentofu/opentofu/internal/copy"
+	"github.com/opentofu/opentofu/internal/longpath"
 )
 
 // We configure our own go-getter detector and getter sets here, because
@@ -76,6 +77,8 @@ var goGetterDecompressors = map[string]getter.Decompressor{
 	"txz":    new(getter.TarXzDecompressor),
 }
 
+// Packages in OCI registries, using "oci://" addresses, are not fetched
+// through go-getter at all: see FetchPinnedPackage.
 var goGetterGetters = map[string]getter.Getter{
 	"file":  new(getter.FileGetter),
 	"gcs":   new(getter.GCSGetter),
@@ -128,24 +131,18 @@ type reusingGetter map[string]string
 // reasonable way to improve these error messages at this layer because
 // the underlying errors are not separately recognizable.
 func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr string) error {
-	var err error
-
-	if prevDir, exists := g[packageAddr]; exists {
-		log.Printf("[TRACE] getmodules: copying previous install of %q from %s to %s", packageAddr, prevDir, instPath)
-		err := os.Mkdir(instPath, os.ModePerm)
-		if err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", instPath, err)
-		}
-		err = copy.CopyDir(instPath, prevDir)
-		if err != nil {
-			return fmt.Errorf("failed to copy from %s to %s: %w", prevDir, instPath, err)
-		}
-	} else {
+	reused, err := g.copyPrevious(instPath, packageAddr)
+	if err != nil {
+		return err
+	}
+	if !reused {
 		log.Printf("[TRACE] getmodules: fetching %q to %q", packageAddr, instPath)
 		client := getter.Client{
 			Src: packageAddr,
-			Dst: instPath,
-			Pwd: instPath,
+			// go-getter and the tools it runs, such as git, don't
+			// necessarily handle paths beyond the Windows MAX_PATH limit.
+			Dst: longpath.Fix(instPath),
+			Pwd: longpath.Fix(instPath),
 
 			Mode: getter.ClientModeDir,
 
@@ -169,6 +166,26 @@ func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAdd
 	return nil
 }
 
+// copyPrevious copies a previous installation of the package at the given
+// address into the given target directory, if there is one, and returns
+// true if it did so.
+func (g reusingGetter) copyPrevious(instPath, packageAddr string) (bool, error) {
+	prevDir, exists := g[packageAddr]
+	if !exists {
+		return false, nil
+	}
+	log.Printf("[TRACE] getmodules: copying previous install of %q from %s to %s", packageAddr, prevDir, instPath)
+	err := os.Mkdir(instPath, os.ModePerm)
+	if err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", instPath, err)
+	}
+	err = copy.CopyDir(instPath, prevDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to copy from %s to %s: %w", prevDir, instPath, err)
+	}
+	return true, nil
+}
+
 // withoutQueryParams implements getter.Detector and can be used to wrap another detector.
 // This will look for any query params that might exist in the src and strip that away before calling
 // getter.Detector#Detect. After the response is returned, the query params are attached back to the resulted src.
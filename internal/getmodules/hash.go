@@ -0,0 +1,89 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// packageHashSchemePrefix is the prefix of a module package hash, which
+// selects the hashing scheme used to produce it.
+//
+// This is the same "h1:" scheme used for provider packages, which is the Go
+// Modules directory hash, version 1.
+const packageHashSchemePrefix = "h1:"
+
+// packageHashIgnoredDirs are the names of directories that some installation
+// methods leave in the package directory alongside the package content, and
+// whose content can vary between installations of the same package.
+var packageHashIgnoredDirs = map[string]bool{
+	".git": true,
+	".hg":  true,
+}
+
+// PackageHash computes a hash of the content of the module package that is
+// installed in the given directory, in the "h1:" format.
+//
+// Version control metadata directories, such as ".git", are not included in
+// the hash, so that the result depends only on the files that make up the
+// package.
+func PackageHash(instDir string) (string, error) {
+	// Some installation methods, such as the "file" getter, link the
+	// installation directory to the package rather than copying it.
+	instDir, err := filepath.EvalSymlinks(instDir)
+	if err != nil {
+		return "", err
+	}
+
+	var files []string
+	err = filepath.WalkDir(instDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if packageHashIgnoredDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(instDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(instDir, filepath.FromSlash(name)))
+	})
+}
+
+// ValidatePackageHash returns an error if the given string is not a valid
+// module package hash, as would be returned by [PackageHash].
+func ValidatePackageHash(hash string) error {
+	encoded, ok := strings.CutPrefix(hash, packageHashSchemePrefix)
+	if !ok {
+		return fmt.Errorf("must start with %q", packageHashSchemePrefix)
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sum) != sha256.Size {
+		return fmt.Errorf("must be %q followed by a base64-encoded SHA256 hash", packageHashSchemePrefix)
+	}
+	return nil
+}
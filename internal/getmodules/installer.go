@@ -7,6 +7,7 @@ package getmodules
 
 import (
 	"context"
+	"log"
 )
 
 // PackageFetcher is a low-level utility for fetching remote module packages
@@ -24,11 +25,25 @@ import (
 // live only for the duration of a single initialization process.
 type PackageFetcher struct {
 	getter reusingGetter
+	env    PackageFetcherEnvironment
+
+	// ociDigests records the manifest digest of each package fetched from
+	// an OCI registry, keyed by package address, so that a later fetch of
+	// the same package that reuses the earlier installation can report it.
+	ociDigests map[string]string
 }
 
-func NewPackageFetcher() *PackageFetcher {
+// NewPackageFetcher creates a new PackageFetcher that uses the given
+// environment to access OCI registries. If env is nil then OCI registries
+// are accessed without any credentials.
+func NewPackageFetcher(env PackageFetcherEnvironment) *PackageFetcher {
+	if env == nil {
+		env = anonymousPackageFetcherEnvironment{}
+	}
 	return &PackageFetcher{
-		getter: reusingGetter{},
+		getter:     reusingGetter{},
+		env:        env,
+		ociDigests: make(map[string]string),
 	}
 }
 
@@ -45,5 +60,38 @@ func NewPackageFetcher() *PackageFetcher {
 // caller must resolve that itself, possibly with the help of the
 // getmodules.SplitPackageSubdir and getmodules.ExpandSubdirGlobs functions.
 func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string) error {
-	return f.getter.getWithGoGetter(ctx, instDir, packageAddr)
+	_, err := f.FetchPinnedPackage(ctx, instDir, packageAddr, "")
+	return err
+}
+
+// FetchPinnedPackage is like FetchPackage, but for a package in an OCI
+// registry that is selected by tag it fetches the artifact whose manifest has
+// the given digest, if not empty, instead of whichever artifact the tag
+// currently refers to.
+//
+// For a package in an OCI registry it returns the digest of the manifest of
+// the artifact that was fetched, which the caller can record to pin that
+// artifact for future installations. For other packages the digest is always
+// empty, and pinnedDigest is ignored.
+func (f *PackageFetcher) FetchPinnedPackage(ctx context.Context, instDir string, packageAddr string, pinnedDigest string) (digest string, err error) {
+	if !IsOCIPackageAddress(packageAddr) {
+		return "", f.getter.getWithGoGetter(ctx, instDir, packageAddr)
+	}
+
+	reused, err := f.getter.copyPrevious(instDir, packageAddr)
+	if err != nil {
+		return "", err
+	}
+	if reused {
+		return f.ociDigests[packageAddr], nil
+	}
+
+	log.Printf("[TRACE] getmodules: fetching %q from OCI registry to %q", packageAddr, instDir)
+	got, err := fetchOCIPackage(ctx, f.env, instDir, packageAddr, pinnedDigest)
+	if err != nil {
+		return "", err
+	}
+	f.getter[packageAddr] = instDir
+	f.ociDigests[packageAddr] = got.String()
+	return got.String(), nil
 }
@@ -0,0 +1,268 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	getter "github.com/hashicorp/go-getter"
+	ociDigest "github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	orasContent "oras.land/oras-go/v2/content"
+	orasRegistry "oras.land/oras-go/v2/registry"
+	orasRemote "oras.land/oras-go/v2/registry/remote"
+	orasAuth "oras.land/oras-go/v2/registry/remote/auth"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+)
+
+// ociPackageAddrPrefix is the prefix of a module package address that
+// refers to an artifact in an OCI Distribution repository, such as
+// "oci://ghcr.io/example/network:1.2.0".
+//
+// The part after the prefix uses the same syntax as a container image
+// reference, and must include either a tag or a digest.
+const ociPackageAddrPrefix = "oci://"
+
+// ociModuleArtifactType is the artifact type we expect for the manifest that
+// an OCI module package address refers to.
+const ociModuleArtifactType = "application/vnd.opentofu.modulepkg"
+
+// ociModulePackageMediaType is the media type of the single layer blob in
+// a module package manifest, which contains the module package itself.
+const ociModulePackageMediaType = "archive/zip"
+
+// ociManifestSizeLimit is the maximum size of a module package manifest
+// that we'll accept, which matches the limit that the OCI Distribution
+// specification recommends registries enforce on push.
+const ociManifestSizeLimit = 4 << 20
+
+// OCIRepositoryStore is the interface used by the module package fetcher to
+// interact with the content of a specific OCI Distribution repository.
+//
+// This intentionally matches a subset of the API of ORAS-Go's
+// remote.Repository, so that it can be satisfied by that type in normal use
+// and by a local or in-memory store in tests.
+type OCIRepositoryStore interface {
+	// Resolve finds the descriptor associated with the given tag name or
+	// digest in the repository, if any.
+	Resolve(ctx context.Context, reference string) (ociv1.Descriptor, error)
+
+	// Fetch retrieves the content of the blob identified by the digest in
+	// the given descriptor. Callers must verify the content against the
+	// descriptor and must close the returned reader.
+	Fetch(ctx context.Context, target ociv1.Descriptor) (io.ReadCloser, error)
+}
+
+// PackageFetcherEnvironment provides the external dependencies that a
+// [PackageFetcher] needs for some kinds of module package.
+type PackageFetcherEnvironment interface {
+	// OCIRepositoryStore returns a client for the given repository in the
+	// given OCI Distribution registry, configured with any credentials needed
+	// to access it.
+	OCIRepositoryStore(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error)
+}
+
+// anonymousPackageFetcherEnvironment is the [PackageFetcherEnvironment] used
+// when the caller doesn't provide one, which accesses OCI registries without
+// any credentials.
+type anonymousPackageFetcherEnvironment struct{}
+
+func (anonymousPackageFetcherEnvironment) OCIRepositoryStore(_ context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error) {
+	repo, err := orasRemote.NewRepository(registryDomain + "/" + repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	repo.Client = &orasAuth.Client{
+		Client: httpclient.New(),
+		Cache:  orasAuth.NewCache(),
+	}
+	return repo, nil
+}
+
+// IsOCIPackageAddress returns true if the given package address, formatted
+// as if it were the result of an addrs.ModulePackage.String() call, refers to
+// an artifact in an OCI Distribution repository.
+func IsOCIPackageAddress(packageAddr string) bool {
+	return strings.HasPrefix(packageAddr, ociPackageAddrPrefix)
+}
+
+// IsOCIPackageAddressPinned returns true if the given OCI package address
+// selects its artifact by digest, rather than by a tag that could later
+// refer to a different artifact.
+func IsOCIPackageAddressPinned(packageAddr string) bool {
+	ref, err := parseOCIPackageAddress(packageAddr)
+	return err == nil && ref.ValidateReferenceAsDigest() == nil
+}
+
+// parseOCIPackageAddress parses an OCI package address into the registry,
+// repository and tag or digest that it refers to.
+func parseOCIPackageAddress(packageAddr string) (orasRegistry.Reference, error) {
+	raw := strings.TrimPrefix(packageAddr, ociPackageAddrPrefix)
+	if strings.Contains(raw, "?") {
+		return orasRegistry.Reference{}, fmt.Errorf("invalid OCI module source %q: query string arguments are not supported", packageAddr)
+	}
+	ref, err := orasRegistry.ParseReference(raw)
+	if err != nil {
+		return orasRegistry.Reference{}, fmt.Errorf("invalid OCI module source %q: %w", packageAddr, err)
+	}
+	if ref.Reference == "" {
+		return orasRegistry.Reference{}, fmt.Errorf("invalid OCI module source %q: must select an artifact using either a tag, like %s:1.0.0, or a digest, like %s@sha256:...", packageAddr, packageAddr, packageAddr)
+	}
+	return ref, nil
+}
+
+// fetchOCIPackage retrieves the module package at the given OCI package
+// address into the given local installation directory, and returns the
+// digest of the manifest of the artifact that it fetched.
+//
+// If pinnedDigest is not empty and the address selects its artifact by tag
+// then the artifact with that digest is fetched instead of whichever artifact
+// the tag currently refers to.
+func fetchOCIPackage(ctx context.Context, env PackageFetcherEnvironment, instDir, packageAddr string, pinnedDigest string) (ociDigest.Digest, error) {
+	ref, err := parseOCIPackageAddress(packageAddr)
+	if err != nil {
+		return "", err
+	}
+
+	var wantDigest ociDigest.Digest
+	reference := ref.Reference
+	if ref.ValidateReferenceAsDigest() == nil {
+		wantDigest = ociDigest.Digest(reference)
+	} else if pinnedDigest != "" {
+		wantDigest, err = ociDigest.Parse(pinnedDigest)
+		if err != nil {
+			return "", fmt.Errorf("invalid digest %q recorded in the dependency lock file: %w", pinnedDigest, err)
+		}
+		log.Printf("[TRACE] getmodules: using locked digest %s instead of tag %q for %s", wantDigest, reference, packageAddr)
+		reference = wantDigest.String()
+	}
+
+	store, err := env.OCIRepositoryStore(ctx, ref.Registry, ref.Repository)
+	if err != nil {
+		return "", fmt.Errorf("accessing OCI registry at %s: %w", ref.Registry, err)
+	}
+
+	desc, err := store.Resolve(ctx, reference)
+	if err != nil {
+		if pinnedDigest != "" && reference == wantDigest.String() {
+			return "", fmt.Errorf("resolving digest %s recorded in the dependency lock file: %w", wantDigest, err)
+		}
+		return "", fmt.Errorf("resolving %q: %w", reference, err)
+	}
+	if wantDigest != "" && desc.Digest != wantDigest {
+		return "", fmt.Errorf("registry returned manifest %s when asked for %s", desc.Digest, wantDigest)
+	}
+
+	manifest, err := fetchOCIModuleManifest(ctx, desc, store)
+	if err != nil {
+		return "", err
+	}
+	blobDesc, err := selectOCIModulePackageBlob(manifest.Layers)
+	if err != nil {
+		return "", err
+	}
+	err = fetchOCIModulePackageBlob(ctx, blobDesc, store, instDir)
+	if err != nil {
+		return "", fmt.Errorf("fetching module package blob %s: %w", blobDesc.Digest, err)
+	}
+	return desc.Digest, nil
+}
+
+func fetchOCIModuleManifest(ctx context.Context, desc ociv1.Descriptor, store OCIRepositoryStore) (*ociv1.Manifest, error) {
+	if desc.MediaType != ociv1.MediaTypeImageManifest {
+		if desc.MediaType == ociv1.MediaTypeImageIndex {
+			return nil, fmt.Errorf("selected an OCI index manifest, but module packages must be selected through an image manifest")
+		}
+		return nil, fmt.Errorf("unsupported media type %q for OCI image manifest", desc.MediaType)
+	}
+	if desc.Size > ociManifestSizeLimit {
+		return nil, fmt.Errorf("manifest is too large (%d bytes)", desc.Size)
+	}
+
+	// FetchAll verifies that the content matches the size and digest
+	// from the descriptor.
+	src, err := orasContent.FetchAll(ctx, store, desc)
+	if err != nil {
+		return nil, fmt.Errorf("fetching manifest %s: %w", desc.Digest, err)
+	}
+	var manifest ociv1.Manifest
+	err = json.Unmarshal(src, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest content: %w", err)
+	}
+	if manifest.ArtifactType != ociModuleArtifactType {
+		switch manifest.ArtifactType {
+		case "application/vnd.opentofu.provider", "application/vnd.opentofu.provider-target":
+			// Confusion between providers and modules is common for those
+			// new to OpenTofu terminology, so this gets a specialized error.
+			return nil, fmt.Errorf("selected OCI artifact is an OpenTofu provider package, not a module package")
+		case "":
+			return nil, fmt.Errorf("selected OCI artifact has no artifact type, but OpenTofu module packages must use artifact type %q", ociModuleArtifactType)
+		default:
+			return nil, fmt.Errorf("unsupported OCI artifact type %q", manifest.ArtifactType)
+		}
+	}
+	return &manifest, nil
+}
+
+func selectOCIModulePackageBlob(layers []ociv1.Descriptor) (ociv1.Descriptor, error) {
+	var ret ociv1.Descriptor
+	found := false
+	for _, layer := range layers {
+		// Layers with other media types are ignored, so that future versions
+		// of OpenTofu can support other archive formats alongside this one.
+		if layer.MediaType != ociModulePackageMediaType {
+			continue
+		}
+		if found {
+			return ret, fmt.Errorf("artifact has more than one layer of media type %q", ociModulePackageMediaType)
+		}
+		ret = layer
+		found = true
+	}
+	if !found {
+		return ret, fmt.Errorf("artifact has no layer of media type %q", ociModulePackageMediaType)
+	}
+	return ret, nil
+}
+
+// fetchOCIModulePackageBlob downloads the given module package blob into
+// a temporary file, verifying its digest, and then extracts it into the
+// given installation directory.
+func fetchOCIModulePackageBlob(ctx context.Context, desc ociv1.Descriptor, store OCIRepositoryStore, instDir string) error {
+	readCloser, err := store.Fetch(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer readCloser.Close()
+
+	f, err := os.CreateTemp("", "opentofu-module")
+	if err != nil {
+		return fmt.Errorf("failed to open temporary file: %w", err)
+	}
+	defer os.Remove(f.Name()) // Best effort to remove the temporary file before we return
+
+	verifier := orasContent.NewVerifyReader(readCloser, desc)
+	_, err = io.Copy(f, verifier)
+	if err == nil {
+		err = verifier.Verify()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return new(getter.ZipDecompressor).Decompress(instDir, f.Name(), true, 0)
+}
@@ -70,5 +70,13 @@ func NormalizePackageAddress(given string) (packageAddr, subDir string, err erro
 	}
 
 	packageAddr, subDir = SplitPackageSubdir(result)
+
+	// Packages in OCI registries are fetched without go-getter, so go-getter
+	// won't have checked anything about their addresses.
+	if IsOCIPackageAddress(packageAddr) {
+		if _, err := parseOCIPackageAddress(packageAddr); err != nil {
+			return "", "", err
+		}
+	}
 	return packageAddr, subDir, nil
 }
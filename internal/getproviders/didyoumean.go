@@ -58,6 +58,17 @@ func MissingProviderSuggestion(ctx context.Context, addr addrs.Provider, source
 		}
 	}
 
+	// Many popular providers are published outside of the hashicorp
+	// namespace under a type name matching their resource type prefix, so
+	// we can suggest those without asking the registry.
+	if ns, ok := wellKnownProviderNamespaces[addr.Type]; ok {
+		return addrs.Provider{
+			Hostname:  addr.Hostname,
+			Namespace: ns,
+			Type:      addr.Type,
+		}
+	}
+
 	// Our strategy here, for a default provider, is to use the default
 	// registry's special API for looking up "legacy" providers and try looking
 	// for a legacy provider whose type name matches the type of the given
@@ -97,6 +108,45 @@ func MissingProviderSuggestion(ctx context.Context, addr addrs.Provider, source
 	}
 }
 
+// wellKnownProviderNamespaces is a bundled index of the namespaces of
+// popular providers in the default registry whose namespace is not
+// "hashicorp", keyed by provider type. Because OpenTofu infers a provider
+// type from the prefix of a resource type name, a configuration that uses
+// one of these providers without declaring it in required_providers would
+// otherwise fail with only an error about the missing hashicorp provider.
+var wellKnownProviderNamespaces = map[string]string{
+	"alicloud":     "aliyun",
+	"auth0":        "auth0",
+	"cloudflare":   "cloudflare",
+	"confluent":    "confluentinc",
+	"databricks":   "databricks",
+	"datadog":      "datadog",
+	"digitalocean": "digitalocean",
+	"docker":       "kreuzwerker",
+	"fastly":       "fastly",
+	"github":       "integrations",
+	"gitlab":       "gitlabhq",
+	"grafana":      "grafana",
+	"hcloud":       "hetznercloud",
+	"heroku":       "heroku",
+	"ibm":          "ibm-cloud",
+	"keycloak":     "mrparkers",
+	"linode":       "linode",
+	"mongodbatlas": "mongodb",
+	"newrelic":     "newrelic",
+	"oci":          "oracle",
+	"okta":         "okta",
+	"opsgenie":     "opsgenie",
+	"pagerduty":    "pagerduty",
+	"postgresql":   "cyrilgdn",
+	"rabbitmq":     "cyrilgdn",
+	"scaleway":     "scaleway",
+	"sentry":       "jianyuan",
+	"snowflake":    "snowflake-labs",
+	"tailscale":    "tailscale",
+	"vultr":        "vultr",
+}
+
 // findLegacyProviderLookupSource tries to find a *RegistrySource that can talk
 // to the given registry host in the given Source. It might be given directly,
 // or it might be given indirectly via a MultiSource where the selector
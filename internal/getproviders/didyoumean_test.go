@@ -122,6 +122,26 @@ func TestMissingProviderSuggestion(t *testing.T) {
 			t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
 		}
 	})
+	t.Run("well-known provider", func(t *testing.T) {
+		ctx := context.Background()
+
+		// The bundled index doesn't need a registry, so this works even
+		// without a source that can do legacy lookups.
+		got := MissingProviderSuggestion(
+			ctx,
+			addrs.NewDefaultProvider("cloudflare"),
+			MultiSource{},
+			Requirements{},
+		)
+		want := addrs.Provider{
+			Hostname:  defaultRegistryHost,
+			Namespace: "cloudflare",
+			Type:      "cloudflare",
+		}
+		if got != want {
+			t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+		}
+	})
 	t.Run("another registry", func(t *testing.T) {
 		ctx := context.Background()
 		source, _, close := testRegistrySource(t)
@@ -10,6 +10,8 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-getter"
+
+	"github.com/opentofu/opentofu/internal/longpath"
 )
 
 // We borrow the "unpack a zip file into a target directory" logic from
@@ -64,8 +66,10 @@ func (p PackageLocalArchive) InstallProviderPackage(_ context.Context, meta Pack
 	// match the allowed hashes and so our caller should catch that after
 	// we return if so.
 
+	// The archive extractor doesn't handle paths beyond the Windows MAX_PATH
+	// limit itself, and provider packages are often deeply nested.
 	//nolint:mnd // magic number predates us using this linter
-	err := unzip.Decompress(targetDir, filename, true, 0000)
+	err := unzip.Decompress(longpath.Fix(targetDir), filename, true, 0000)
 	if err != nil {
 		return authResult, err
 	}
@@ -7,6 +7,7 @@ package grpcwrap
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/plugin/convert"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -14,6 +15,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // New wraps a providers.Interface to implement a grpc ProviderServer.
@@ -37,8 +39,9 @@ func (p *provider) GetMetadata(context.Context, *tfplugin5.GetMetadata_Request)
 
 func (p *provider) GetSchema(_ context.Context, req *tfplugin5.GetProviderSchema_Request) (*tfplugin5.GetProviderSchema_Response, error) {
 	resp := &tfplugin5.GetProviderSchema_Response{
-		ResourceSchemas:   make(map[string]*tfplugin5.Schema),
-		DataSourceSchemas: make(map[string]*tfplugin5.Schema),
+		ResourceSchemas:          make(map[string]*tfplugin5.Schema),
+		DataSourceSchemas:        make(map[string]*tfplugin5.Schema),
+		EphemeralResourceSchemas: make(map[string]*tfplugin5.Schema),
 	}
 
 	resp.Provider = &tfplugin5.Schema{
@@ -67,9 +70,16 @@ func (p *provider) GetSchema(_ context.Context, req *tfplugin5.GetProviderSchema
 			Block:   convert.ConfigSchemaToProto(dat.Block),
 		}
 	}
+	for typ, eph := range p.schema.EphemeralResources {
+		resp.EphemeralResourceSchemas[typ] = &tfplugin5.Schema{
+			Version: eph.Version,
+			Block:   convert.ConfigSchemaToProto(eph.Block),
+		}
+	}
 
 	resp.ServerCapabilities = &tfplugin5.ServerCapabilities{
-		PlanDestroy: p.schema.ServerCapabilities.PlanDestroy,
+		PlanDestroy:          p.schema.ServerCapabilities.PlanDestroy,
+		ApplyResourceChanges: p.schema.ServerCapabilities.ApplyResourceChanges,
 	}
 
 	// include any diagnostics from the original GetSchema call
@@ -177,6 +187,9 @@ func (p *provider) Configure(_ context.Context, req *tfplugin5.Configure_Request
 	})
 
 	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, configureResp.Diagnostics)
+	if !configureResp.CredentialsExpiry.IsZero() {
+		resp.CredentialsTtlSeconds = max(int64(time.Until(configureResp.CredentialsExpiry).Seconds()), 1)
+	}
 	return resp, nil
 }
 
@@ -329,6 +342,18 @@ func (p *provider) ApplyResourceChange(_ context.Context, req *tfplugin5.ApplyRe
 	return resp, nil
 }
 
+func (p *provider) ApplyResourceChanges(ctx context.Context, req *tfplugin5.ApplyResourceChanges_Request) (*tfplugin5.ApplyResourceChanges_Response, error) {
+	resp := &tfplugin5.ApplyResourceChanges_Response{}
+	for _, change := range req.Changes {
+		changeResp, err := p.ApplyResourceChange(ctx, change)
+		if err != nil {
+			return nil, err
+		}
+		resp.Changes = append(resp.Changes, changeResp)
+	}
+	return resp, nil
+}
+
 func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportResourceState_Request) (*tfplugin5.ImportResourceState_Response, error) {
 	resp := &tfplugin5.ImportResourceState_Response{}
 
@@ -397,23 +422,91 @@ func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSour
 }
 
 // CloseEphemeralResource implements tfplugin5.ProviderServer.
-func (p *provider) CloseEphemeralResource(context.Context, *tfplugin5.CloseEphemeralResource_Request) (*tfplugin5.CloseEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider) CloseEphemeralResource(_ context.Context, req *tfplugin5.CloseEphemeralResource_Request) (*tfplugin5.CloseEphemeralResource_Response, error) {
+	resp := &tfplugin5.CloseEphemeralResource_Response{}
+
+	closeResp := p.provider.CloseEphemeralResource(providers.CloseEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, closeResp.Diagnostics)
+	return resp, nil
 }
 
 // OpenEphemeralResource implements tfplugin5.ProviderServer.
-func (p *provider) OpenEphemeralResource(context.Context, *tfplugin5.OpenEphemeralResource_Request) (*tfplugin5.OpenEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider) OpenEphemeralResource(_ context.Context, req *tfplugin5.OpenEphemeralResource_Request) (*tfplugin5.OpenEphemeralResource_Response, error) {
+	resp := &tfplugin5.OpenEphemeralResource_Response{}
+	ty := p.schema.EphemeralResources[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	openResp := p.provider.OpenEphemeralResource(providers.OpenEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, openResp.Diagnostics)
+	if openResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+	resp.Private = openResp.Private
+	if !openResp.RenewAt.IsZero() {
+		resp.RenewAt = timestamppb.New(openResp.RenewAt)
+	}
+
+	resp.Result, err = encodeDynamicValue(openResp.Result, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	return resp, nil
 }
 
 // RenewEphemeralResource implements tfplugin5.ProviderServer.
-func (p *provider) RenewEphemeralResource(context.Context, *tfplugin5.RenewEphemeralResource_Request) (*tfplugin5.RenewEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider) RenewEphemeralResource(_ context.Context, req *tfplugin5.RenewEphemeralResource_Request) (*tfplugin5.RenewEphemeralResource_Response, error) {
+	resp := &tfplugin5.RenewEphemeralResource_Response{}
+
+	renewResp := p.provider.RenewEphemeralResource(providers.RenewEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, renewResp.Diagnostics)
+	if renewResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+	resp.Private = renewResp.Private
+	if !renewResp.RenewAt.IsZero() {
+		resp.RenewAt = timestamppb.New(renewResp.RenewAt)
+	}
+
+	return resp, nil
 }
 
 // ValidateEphemeralResourceConfig implements tfplugin5.ProviderServer.
-func (p *provider) ValidateEphemeralResourceConfig(context.Context, *tfplugin5.ValidateEphemeralResourceConfig_Request) (*tfplugin5.ValidateEphemeralResourceConfig_Response, error) {
-	panic("unimplemented")
+func (p *provider) ValidateEphemeralResourceConfig(_ context.Context, req *tfplugin5.ValidateEphemeralResourceConfig_Request) (*tfplugin5.ValidateEphemeralResourceConfig_Response, error) {
+	resp := &tfplugin5.ValidateEphemeralResourceConfig_Response{}
+	ty := p.schema.EphemeralResources[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	validateResp := p.provider.ValidateEphemeralResourceConfig(providers.ValidateEphemeralResourceConfigRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, validateResp.Diagnostics)
+	return resp, nil
 }
 
 func (p *provider) Stop(context.Context, *tfplugin5.Stop_Request) (*tfplugin5.Stop_Response, error) {
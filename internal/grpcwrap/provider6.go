@@ -7,6 +7,7 @@ package grpcwrap
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/plugin6/convert"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -14,6 +15,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // New wraps a providers.Interface to implement a grpc ProviderServer using
@@ -37,8 +39,9 @@ func (p *provider6) GetMetadata(context.Context, *tfplugin6.GetMetadata_Request)
 
 func (p *provider6) GetProviderSchema(_ context.Context, req *tfplugin6.GetProviderSchema_Request) (*tfplugin6.GetProviderSchema_Response, error) {
 	resp := &tfplugin6.GetProviderSchema_Response{
-		ResourceSchemas:   make(map[string]*tfplugin6.Schema),
-		DataSourceSchemas: make(map[string]*tfplugin6.Schema),
+		ResourceSchemas:          make(map[string]*tfplugin6.Schema),
+		DataSourceSchemas:        make(map[string]*tfplugin6.Schema),
+		EphemeralResourceSchemas: make(map[string]*tfplugin6.Schema),
 	}
 
 	resp.Provider = &tfplugin6.Schema{
@@ -67,9 +70,16 @@ func (p *provider6) GetProviderSchema(_ context.Context, req *tfplugin6.GetProvi
 			Block:   convert.ConfigSchemaToProto(dat.Block),
 		}
 	}
+	for typ, eph := range p.schema.EphemeralResources {
+		resp.EphemeralResourceSchemas[typ] = &tfplugin6.Schema{
+			Version: eph.Version,
+			Block:   convert.ConfigSchemaToProto(eph.Block),
+		}
+	}
 
 	resp.ServerCapabilities = &tfplugin6.ServerCapabilities{
-		PlanDestroy: p.schema.ServerCapabilities.PlanDestroy,
+		PlanDestroy:          p.schema.ServerCapabilities.PlanDestroy,
+		ApplyResourceChanges: p.schema.ServerCapabilities.ApplyResourceChanges,
 	}
 
 	// include any diagnostics from the original GetSchema call
@@ -177,6 +187,9 @@ func (p *provider6) ConfigureProvider(_ context.Context, req *tfplugin6.Configur
 	})
 
 	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, configureResp.Diagnostics)
+	if !configureResp.CredentialsExpiry.IsZero() {
+		resp.CredentialsTtlSeconds = max(int64(time.Until(configureResp.CredentialsExpiry).Seconds()), 1)
+	}
 	return resp, nil
 }
 
@@ -329,6 +342,18 @@ func (p *provider6) ApplyResourceChange(_ context.Context, req *tfplugin6.ApplyR
 	return resp, nil
 }
 
+func (p *provider6) ApplyResourceChanges(ctx context.Context, req *tfplugin6.ApplyResourceChanges_Request) (*tfplugin6.ApplyResourceChanges_Response, error) {
+	resp := &tfplugin6.ApplyResourceChanges_Response{}
+	for _, change := range req.Changes {
+		changeResp, err := p.ApplyResourceChange(ctx, change)
+		if err != nil {
+			return nil, err
+		}
+		resp.Changes = append(resp.Changes, changeResp)
+	}
+	return resp, nil
+}
+
 func (p *provider6) ImportResourceState(_ context.Context, req *tfplugin6.ImportResourceState_Request) (*tfplugin6.ImportResourceState_Response, error) {
 	resp := &tfplugin6.ImportResourceState_Response{}
 
@@ -397,23 +422,91 @@ func (p *provider6) ReadDataSource(_ context.Context, req *tfplugin6.ReadDataSou
 }
 
 // CloseEphemeralResource implements tfplugin6.ProviderServer.
-func (p *provider6) CloseEphemeralResource(context.Context, *tfplugin6.CloseEphemeralResource_Request) (*tfplugin6.CloseEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider6) CloseEphemeralResource(_ context.Context, req *tfplugin6.CloseEphemeralResource_Request) (*tfplugin6.CloseEphemeralResource_Response, error) {
+	resp := &tfplugin6.CloseEphemeralResource_Response{}
+
+	closeResp := p.provider.CloseEphemeralResource(providers.CloseEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, closeResp.Diagnostics)
+	return resp, nil
 }
 
 // OpenEphemeralResource implements tfplugin6.ProviderServer.
-func (p *provider6) OpenEphemeralResource(context.Context, *tfplugin6.OpenEphemeralResource_Request) (*tfplugin6.OpenEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider6) OpenEphemeralResource(_ context.Context, req *tfplugin6.OpenEphemeralResource_Request) (*tfplugin6.OpenEphemeralResource_Response, error) {
+	resp := &tfplugin6.OpenEphemeralResource_Response{}
+	ty := p.schema.EphemeralResources[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue6(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	openResp := p.provider.OpenEphemeralResource(providers.OpenEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, openResp.Diagnostics)
+	if openResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+	resp.Private = openResp.Private
+	if !openResp.RenewAt.IsZero() {
+		resp.RenewAt = timestamppb.New(openResp.RenewAt)
+	}
+
+	resp.Result, err = encodeDynamicValue6(openResp.Result, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	return resp, nil
 }
 
 // RenewEphemeralResource implements tfplugin6.ProviderServer.
-func (p *provider6) RenewEphemeralResource(context.Context, *tfplugin6.RenewEphemeralResource_Request) (*tfplugin6.RenewEphemeralResource_Response, error) {
-	panic("unimplemented")
+func (p *provider6) RenewEphemeralResource(_ context.Context, req *tfplugin6.RenewEphemeralResource_Request) (*tfplugin6.RenewEphemeralResource_Response, error) {
+	resp := &tfplugin6.RenewEphemeralResource_Response{}
+
+	renewResp := p.provider.RenewEphemeralResource(providers.RenewEphemeralResourceRequest{
+		TypeName: req.TypeName,
+		Private:  req.Private,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, renewResp.Diagnostics)
+	if renewResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+	resp.Private = renewResp.Private
+	if !renewResp.RenewAt.IsZero() {
+		resp.RenewAt = timestamppb.New(renewResp.RenewAt)
+	}
+
+	return resp, nil
 }
 
 // ValidateEphemeralResourceConfig implements tfplugin6.ProviderServer.
-func (p *provider6) ValidateEphemeralResourceConfig(context.Context, *tfplugin6.ValidateEphemeralResourceConfig_Request) (*tfplugin6.ValidateEphemeralResourceConfig_Response, error) {
-	panic("unimplemented")
+func (p *provider6) ValidateEphemeralResourceConfig(_ context.Context, req *tfplugin6.ValidateEphemeralResourceConfig_Request) (*tfplugin6.ValidateEphemeralResourceConfig_Response, error) {
+	resp := &tfplugin6.ValidateEphemeralResourceConfig_Response{}
+	ty := p.schema.EphemeralResources[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue6(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	validateResp := p.provider.ValidateEphemeralResourceConfig(providers.ValidateEphemeralResourceConfigRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, validateResp.Diagnostics)
+	return resp, nil
 }
 
 func (p *provider6) StopProvider(context.Context, *tfplugin6.StopProvider_Request) (*tfplugin6.StopProvider_Response, error) {
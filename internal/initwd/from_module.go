@@ -168,7 +168,7 @@ func DirFromModule(ctx context.Context, loader *configload.Loader, rootDir, modu
 		Key: "",
 		Dir: rootDir,
 	}
-	fetcher := getmodules.NewPackageFetcher()
+	fetcher := getmodules.NewPackageFetcher(nil)
 
 	walker := inst.moduleInstallWalker(ctx, instManifest, true, wrapHooks, fetcher)
 	_, cDiags := inst.installDescendentModules(fakeRootModule, instManifest, walker, true)
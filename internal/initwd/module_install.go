@@ -7,6 +7,8 @@ package initwd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -24,7 +26,9 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/modchannel"
 	"github.com/opentofu/opentofu/internal/modsdir"
 	"github.com/opentofu/opentofu/internal/registry"
 	"github.com/opentofu/opentofu/internal/registry/regsrc"
@@ -44,6 +48,28 @@ type ModuleInstaller struct {
 	// The keys in moduleVersionsUrl are the moduleVersion struct below and
 	// addresses and the values are underlying remote source addresses.
 	registryPackageSources map[moduleVersion]addrs.ModuleSourceRemote
+
+	// channel, if set, selects exact versions for some of the registry
+	// modules, overriding the selection of the newest matching version.
+	channel *modchannel.Channel
+
+	// fetcherEnv, if set, is used to access OCI registries when fetching
+	// module packages from them.
+	fetcherEnv getmodules.PackageFetcherEnvironment
+
+	// cache, if set, is a module package cache shared with other working
+	// directories, which is used for registry modules.
+	cache *getmodules.PackageCache
+
+	// locks, if set, records the hashes of remote module packages and the
+	// artifacts selected for module packages in OCI registries, and is
+	// updated in place to record new selections.
+	locks *depsfile.Locks
+
+	// lockedPackages are the module packages that were locked during the
+	// current call to InstallModules, so that the locks for any other
+	// packages can be removed afterwards.
+	lockedPackages map[addrs.ModulePackage]struct{}
 }
 
 type moduleVersion struct {
@@ -61,6 +87,44 @@ func NewModuleInstaller(modsDir string, loader *configload.Loader, reg *registry
 	}
 }
 
+// SetChannel selects a module channel that pins the versions of the registry
+// modules that it mentions. The pinned versions must still be allowed by the
+// version constraints of the corresponding module calls.
+func (i *ModuleInstaller) SetChannel(ch *modchannel.Channel) {
+	i.channel = ch
+}
+
+// SetPackageFetcherEnvironment selects the environment used to access OCI
+// registries when installing module packages from them. Without it, OCI
+// registries are accessed without any credentials.
+func (i *ModuleInstaller) SetPackageFetcherEnvironment(env getmodules.PackageFetcherEnvironment) {
+	i.fetcherEnv = env
+}
+
+// SetPackageCache selects a module package cache, shared with other working
+// directories, from which to install registry modules that it already has,
+// and into which to store the registry modules that are downloaded.
+//
+// Only registry modules are cached, because a registry module version always
+// refers to the same package content, whereas other module sources, such as
+// a git branch, may not.
+func (i *ModuleInstaller) SetPackageCache(cache *getmodules.PackageCache) {
+	i.cache = cache
+}
+
+// SetLocks selects the dependency locks that record the hashes of remote
+// module packages, and that pin the artifacts selected for module packages in
+// OCI registries that are selected by tag.
+//
+// The installer verifies each remote module package against its lock, if
+// any, and updates the given locks in place to record the hash and artifact
+// of each package it installs, and to remove the locks of packages that are
+// no longer used. It disregards the existing locks when the upgrade flag is
+// set.
+func (i *ModuleInstaller) SetLocks(locks *depsfile.Locks) {
+	i.locks = locks
+}
+
 // InstallModules analyses the root module in the given directory and installs
 // all of its direct and transitive dependencies into the given modules
 // directory, which must already exist.
@@ -124,7 +188,7 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 		return nil, diags
 	}
 
-	fetcher := getmodules.NewPackageFetcher()
+	fetcher := getmodules.NewPackageFetcher(i.fetcherEnv)
 
 	if hooks == nil {
 		// Use our no-op implementation as a placeholder
@@ -137,11 +201,21 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 		Key: "",
 		Dir: rootDir,
 	}
+	i.lockedPackages = make(map[addrs.ModulePackage]struct{})
 	walker := i.moduleInstallWalker(ctx, manifest, upgrade, hooks, fetcher)
 
 	cfg, instDiags := i.installDescendentModules(rootMod, manifest, walker, installErrsOnly)
 	diags = append(diags, instDiags...)
 
+	if i.locks != nil && !diags.HasErrors() {
+		for addr := range i.locks.AllModules() {
+			if _, ok := i.lockedPackages[addr]; !ok {
+				log.Printf("[TRACE] ModuleInstaller: removing lock for module package %s, which is no longer used", addr)
+				i.locks.RemoveModule(addr)
+			}
+		}
+	}
+
 	return cfg, diags
 }
 
@@ -193,6 +267,14 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 				case record.Version != nil && !req.VersionConstraint.Required.Check(record.Version):
 					log.Printf("[TRACE] ModuleInstaller: %s version %s no longer compatible with constraints %s", key, record.Version, req.VersionConstraint.Required)
 					replace = true
+				case record.Version != nil && i.channelVersionChanged(req, record.Version):
+					log.Printf("[TRACE] ModuleInstaller: %s version %s is not the version selected by channel %q", key, record.Version, i.channel.Name)
+					replace = true
+				case isRemoteModuleSource(req.SourceAddr) && !pathIsWithin(record.Dir, instPath):
+					// An earlier version of OpenTofu installed this module
+					// under a name that we'd now shorten.
+					log.Printf("[TRACE] ModuleInstaller: %s is installed in %s rather than in %s", key, record.Dir, instPath)
+					replace = true
 				}
 			}
 
@@ -241,6 +323,17 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 				// keep our existing record.
 				info, err := os.Stat(record.Dir)
 				if err == nil && info.IsDir() {
+					// A remote module package is verified against its
+					// expected hash on every installation, even if it's
+					// already installed.
+					if addr, ok := req.SourceAddr.(addrs.ModuleSourceRemote); ok {
+						hashDiags := i.checkPackageHash(req, addr.Package, instPath, "", false)
+						diags = diags.Extend(hashDiags)
+						if hashDiags.HasErrors() {
+							return nil, nil, diags
+						}
+					}
+
 					mod, mDiags := i.loader.Parser().LoadConfigDir(record.Dir, req.Call)
 					if mod == nil {
 						// nil indicates an unreadable module, which should never happen,
@@ -281,7 +374,7 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 
 			case addrs.ModuleSourceRemote:
 				log.Printf("[TRACE] ModuleInstaller: %s address %q will be handled by go-getter", key, addr.String())
-				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, manifest, hooks, fetcher)
+				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, manifest, upgrade, hooks, fetcher)
 				diags = append(diags, mDiags...)
 				return mod, nil, diags
 
@@ -294,6 +387,17 @@ func (i *ModuleInstaller) moduleInstallWalker(ctx context.Context, manifest mods
 	)
 }
 
+// channelVersionChanged returns true if the selected channel pins the module
+// requested by req to a version other than the given installed version.
+func (i *ModuleInstaller) channelVersionChanged(req *configs.ModuleRequest, installed *version.Version) bool {
+	addr, ok := req.SourceAddr.(addrs.ModuleSourceRegistry)
+	if !ok {
+		return false
+	}
+	pinned := i.channel.ModuleVersion(addr.Package)
+	return pinned != nil && !pinned.Equal(installed)
+}
+
 func (i *ModuleInstaller) installDescendentModules(rootMod *configs.Module, manifest modsdir.Manifest, installWalker configs.ModuleWalker, installErrsOnly bool) (*configs.Config, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -492,6 +596,11 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	modMeta := resp.Modules[0]
 
+	pinned := i.channel.ModuleVersion(packageAddr)
+	if pinned != nil {
+		log.Printf("[TRACE] ModuleInstaller: %s is pinned to %s by channel %q", key, pinned, i.channel.Name)
+	}
+
 	var latestMatch *version.Version
 	var latestVersion *version.Version
 	for _, mv := range modMeta.Versions {
@@ -509,6 +618,18 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 			continue
 		}
 
+		// A channel selects exactly one version, which may be a pre-release.
+		if pinned != nil {
+			if !v.Equal(pinned) {
+				continue
+			}
+			latestVersion = v
+			if req.VersionConstraint.Required.Check(v) {
+				latestMatch = v
+			}
+			break
+		}
+
 		// If we've found a pre-release version then we'll ignore it unless
 		// it was exactly requested.
 		//
@@ -611,6 +732,25 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 		}
 	}
 
+	if pinned != nil && latestMatch == nil {
+		if latestVersion == nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Module channel version not available",
+				Detail:   fmt.Sprintf("The module channel %q selects version %s of module %q (%s:%d), but that version is not available on %s.", i.channel.Name, pinned, addr, req.CallRange.Filename, req.CallRange.Start.Line, hostname),
+				Subject:  req.CallRange.Ptr(),
+			})
+		} else {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Module channel version not allowed",
+				Detail:   fmt.Sprintf("The module channel %q selects version %s of module %q (%s:%d), but the module call's version constraint %q does not allow that version.", i.channel.Name, pinned, addr, req.CallRange.Filename, req.CallRange.Start.Line, req.VersionConstraint.Required),
+				Subject:  req.VersionConstraint.DeclRange.Ptr(),
+			})
+		}
+		return nil, nil, diags
+	}
+
 	if latestVersion == nil {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -681,6 +821,22 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
 
+	// The package for a registry module version never changes, so we can
+	// use a copy from the shared package cache if there is one.
+	cacheKey := fmt.Sprintf("%s %s", packageAddr, latestMatch)
+	if i.cache != nil {
+		cached, err := i.cache.Install(instPath, cacheKey)
+		if err != nil {
+			// We can still download the package instead.
+			log.Printf("[WARN] ModuleInstaller: failed to install %s %s from the module cache: %s", packageAddr, latestMatch, err)
+		}
+		if cached {
+			log.Printf("[TRACE] ModuleInstaller: %s %s %s was installed from the module cache", key, packageAddr, latestMatch)
+			mod, v, loadDiags := i.loadRegistryModule(req, key, instPath, addr, dlAddr, latestMatch, manifest, hooks)
+			return mod, v, diags.Extend(loadDiags)
+		}
+	}
+
 	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String())
 	if errors.Is(err, context.Canceled) {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -707,6 +863,24 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, dlAddr.Package, instPath)
 
+	if i.cache != nil {
+		if err := i.cache.Store(instPath, cacheKey); err != nil {
+			// The cache is only an optimization, so we'll continue without
+			// it.
+			log.Printf("[WARN] ModuleInstaller: failed to store %s %s in the module cache: %s", packageAddr, latestMatch, err)
+		}
+	}
+
+	mod, v, loadDiags := i.loadRegistryModule(req, key, instPath, addr, dlAddr, latestMatch, manifest, hooks)
+	return mod, v, diags.Extend(loadDiags)
+}
+
+// loadRegistryModule loads a registry module from the package that was
+// installed for it in the given directory, and records the installation in
+// the manifest.
+func (i *ModuleInstaller) loadRegistryModule(req *configs.ModuleRequest, key string, instPath string, addr addrs.ModuleSourceRegistry, dlAddr addrs.ModuleSourceRemote, latestMatch *version.Version, manifest modsdir.Manifest, hooks ModuleInstallHooks) (*configs.Module, *version.Version, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
 	// Incorporate any subdir information from the original path into the
 	// address returned by the registry in order to find the final directory
 	// of the target module.
@@ -751,7 +925,7 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 	return mod, latestMatch, diags
 }
 
-func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
+func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	// Report up to the caller that we're about to start downloading.
@@ -769,7 +943,17 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	// Packages in OCI registries that are selected by tag are pinned to the
+	// artifact recorded in the dependency lock file, if any, unless we're
+	// upgrading.
+	var pinnedDigest string
+	if i.locks != nil && depsfile.ModuleDigestIsLockable(packageAddr) && !upgrade {
+		if lock := i.locks.Module(packageAddr); lock != nil {
+			pinnedDigest = lock.Digest()
+		}
+	}
+
+	digest, err := fetcher.FetchPinnedPackage(ctx, instPath, packageAddr.String(), pinnedDigest)
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
@@ -805,6 +989,12 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
+	hashDiags := i.checkPackageHash(req, packageAddr, instPath, digest, upgrade)
+	diags = diags.Extend(hashDiags)
+	if hashDiags.HasErrors() {
+		return nil, diags
+	}
+
 	modDir, err := getmodules.ExpandSubdirGlobs(instPath, addr.Subdir)
 	if err != nil {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -851,8 +1041,124 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 	return mod, diags
 }
 
+// checkPackageHash verifies that the content of the given module package,
+// installed in the given directory, matches the hash given in the "integrity"
+// argument of the module call, if any, and the hash recorded in the
+// dependency lock file, if any and unless we're upgrading. It then records
+// the hash and the given OCI artifact digest, if any, in the locks.
+func (i *ModuleInstaller) checkPackageHash(req *configs.ModuleRequest, packageAddr addrs.ModulePackage, instPath string, digest string, upgrade bool) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	lockable := i.locks != nil && depsfile.ModuleIsLockable(packageAddr)
+	if !lockable && req.Integrity == "" {
+		return diags
+	}
+
+	hash, err := getmodules.PackageHash(instPath)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to verify module package",
+			Detail:   fmt.Sprintf("Could not compute the hash of module package %s for module %q: %s.", packageAddr, req.Name, err),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return diags
+	}
+
+	if req.Integrity != "" && hash != req.Integrity {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Module package integrity check failed",
+			Detail: fmt.Sprintf(
+				"The content of module package %s for module %q has hash %s, which doesn't match the expected hash %s given in the \"integrity\" argument.\n\nThe package content has changed since the hash was recorded, which could be because its source was modified, such as by moving a git tag, or because it was tampered with. If the change is expected, update the \"integrity\" argument.",
+				packageAddr, req.Name, hash, req.Integrity,
+			),
+			Subject: req.IntegrityRange.Ptr(),
+		})
+		return diags
+	}
+
+	if !lockable {
+		return diags
+	}
+	i.lockedPackages[packageAddr] = struct{}{}
+
+	if lock := i.locks.Module(packageAddr); lock != nil {
+		if !upgrade && lock.Hash() != "" && hash != lock.Hash() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Module package doesn't match the dependency lock file",
+				Detail: fmt.Sprintf(
+					"The content of module package %s for module %q has hash %s, which doesn't match the hash %s recorded in the dependency lock file.\n\nThe package content has changed since it was last installed, which could be because its source was modified, such as by moving a git tag, or because it was tampered with. If the change is expected, run \"tofu init -upgrade\" to record the new hash.",
+					packageAddr, req.Name, hash, lock.Hash(),
+				),
+				Subject: req.CallRange.Ptr(),
+			})
+			return diags
+		}
+		if digest == "" && !upgrade {
+			// An already-installed package keeps its selected artifact.
+			digest = lock.Digest()
+		}
+	}
+
+	i.locks.SetModule(packageAddr, digest, hash)
+	return diags
+}
+
+// maxModuleDirNameLen is the longest name that packageInstallPath will use
+// for a module's directory before shortening it. Deeply-nested module paths
+// can otherwise produce directory names that, together with the files within
+// the module packages, exceed the Windows MAX_PATH limit.
+const maxModuleDirNameLen = 64
+
+// packageInstallPath returns the directory that the module package for the
+// given module path should be installed into.
+//
+// The directory is usually named after the module path itself, but a name
+// longer than maxModuleDirNameLen is replaced with a truncated prefix of the
+// name followed by a hash of the whole name, which is still unique and
+// stable between runs.
 func (i *ModuleInstaller) packageInstallPath(modulePath addrs.Module) string {
-	return filepath.Join(i.modsDir, strings.Join(modulePath, "."))
+	name := strings.Join(modulePath, ".")
+	if len(name) > maxModuleDirNameLen {
+		sum := sha256.Sum256([]byte(name))
+		suffix := "~" + hex.EncodeToString(sum[:8])
+		name = name[:maxModuleDirNameLen-len(suffix)] + suffix
+	}
+	return filepath.Join(i.modsDir, name)
+}
+
+// isRemoteModuleSource returns true if the given module source is installed
+// into a package directory of its own, rather than being used in place.
+func isRemoteModuleSource(addr addrs.ModuleSource) bool {
+	switch addr.(type) {
+	case addrs.ModuleSourceRemote, addrs.ModuleSourceRegistry:
+		return true
+	default:
+		return false
+	}
+}
+
+// pathIsWithin returns true if the given path is the given directory or is
+// somewhere beneath it.
+func pathIsWithin(path, dir string) bool {
+	// The manifest records directories in the same form as the modules
+	// directory it belongs to, but we'll make both absolute anyway in case
+	// one of them was recorded differently.
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // maybeImproveLocalInstallError is a helper function which can recognize
@@ -6,8 +6,12 @@
 package initwd
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -20,12 +24,20 @@ import (
 	"github.com/google/go-cmp/cmp"
 	version "github.com/hashicorp/go-version"
 	svchost "github.com/hashicorp/terraform-svchost"
+	ociDigest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	orasOCI "oras.land/oras-go/v2/content/oci"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/copy"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/modchannel"
 	"github.com/opentofu/opentofu/internal/registry"
+	registrytest "github.com/opentofu/opentofu/internal/registry/test"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 
 	_ "github.com/opentofu/opentofu/internal/logging"
@@ -360,6 +372,338 @@ func TestModuleInstaller_invalid_version_constraint_error(t *testing.T) {
 	}
 }
 
+func TestModuleInstaller_channel(t *testing.T) {
+	server := registrytest.Registry()
+	defer server.Close()
+
+	pkgAddr, err := modchannel.ParseModulePackage("example.com/test-versions/name/provider")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		constraint  string
+		pinned      string
+		wantVersion string
+		wantErr     string
+	}{
+		"not pinned": {
+			wantVersion: "2.2.0",
+		},
+		"pinned": {
+			pinned:      "1.2.1",
+			wantVersion: "1.2.1",
+		},
+		"pinned within constraint": {
+			constraint:  "~> 1.2",
+			pinned:      "1.2.1",
+			wantVersion: "1.2.1",
+		},
+		"pinned outside constraint": {
+			constraint: "~> 2.0",
+			pinned:     "1.2.2",
+			wantErr:    "Module channel version not allowed",
+		},
+		"pinned version not available": {
+			pinned:  "3.0.0",
+			wantErr: "Module channel version not available",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "module \"child\" {\n  source = \"example.com/test-versions/name/provider\"\n"
+			if test.constraint != "" {
+				src += fmt.Sprintf("  version = %q\n", test.constraint)
+			}
+			src += "}\n"
+			if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			ch := modchannel.New("prod")
+			if test.pinned != "" {
+				ch.SetModuleVersion(pkgAddr, version.Must(version.NewVersion(test.pinned)))
+			}
+
+			hooks := &testInstallHooks{}
+			loader, close := configload.NewLoaderForTests(t)
+			defer close()
+			inst := NewModuleInstaller(filepath.Join(dir, ".terraform/modules"), loader, registry.NewClient(registrytest.Disco(server), nil))
+			inst.SetChannel(ch)
+			_, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, hooks, configs.RootModuleCallForTesting())
+
+			if test.wantErr != "" {
+				assertDiagnosticSummary(t, diags, test.wantErr)
+				return
+			}
+
+			// The mock registry can't provide a download location for this
+			// module, so we only check which version we tried to download.
+			var got *version.Version
+			for _, call := range hooks.Calls {
+				if call.Name == "Download" {
+					got = call.Version
+				}
+			}
+			if got == nil || got.String() != test.wantVersion {
+				t.Fatalf("wrong version downloaded\ngot:  %s\nwant: %s\ndiags: %s", got, test.wantVersion, diags.Err())
+			}
+		})
+	}
+}
+
+func TestModuleInstaller_packageCache(t *testing.T) {
+	server := registrytest.Registry()
+	defer server.Close()
+
+	// The mock registry returns a download location for this module
+	// relative to the current working directory.
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	archivePath := filepath.Join("testdata", "registry-tar", "module.tgz")
+	writeTestModuleArchive(t, archivePath, map[string]string{
+		"main.tf":       "output \"greeting\" {\n  value = \"hello\"\n}\n",
+		".tflint.hcl":   "# included in the package hash\n",
+		"sub/nested.tf": "locals {}\n",
+	})
+
+	cacheDir := t.TempDir()
+	install := func(t *testing.T, name string) (*configs.Config, string, tfdiags.Diagnostics) {
+		t.Helper()
+		dir := filepath.Join(workDir, name)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		src := "module \"child\" {\n  source = \"example.com/registry/local/archive\"\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		modulesDir := filepath.Join(dir, ".terraform/modules")
+		inst := NewModuleInstaller(modulesDir, loader, registry.NewClient(registrytest.Disco(server), nil))
+		inst.SetPackageCache(getmodules.NewPackageCache(cacheDir))
+		cfg, diags := inst.InstallModules(context.Background(), dir, "tests", false, false, &testInstallHooks{}, configs.RootModuleCallForTesting())
+		return cfg, filepath.Join(modulesDir, "child"), diags
+	}
+	checkInstalled := func(t *testing.T, cfg *configs.Config, diags tfdiags.Diagnostics) {
+		t.Helper()
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		child := cfg.Children["child"]
+		if child == nil {
+			t.Fatal("child module not loaded")
+		}
+		if _, ok := child.Module.Outputs["greeting"]; !ok {
+			t.Fatal("child module has no greeting output")
+		}
+	}
+
+	// The first installation downloads the package and stores it.
+	cfg, first, diags := install(t, "first")
+	checkInstalled(t, cfg, diags)
+	if info, err := os.Lstat(first); err != nil {
+		t.Fatal(err)
+	} else if info.Mode()&os.ModeSymlink != 0 {
+		t.Errorf("first installation at %s links to the cache; want a downloaded copy", first)
+	}
+	firstHash, err := getmodules.PackageHash(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second installation must come from the cache, because the
+	// package can no longer be downloaded.
+	if err := os.Remove(archivePath); err != nil {
+		t.Fatal(err)
+	}
+	cfg, second, diags := install(t, "second")
+	checkInstalled(t, cfg, diags)
+	secondHash, err := getmodules.PackageHash(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secondHash != firstHash {
+		t.Errorf("cached package has different content\ngot:  %s\nwant: %s", secondHash, firstHash)
+	}
+	target, err := filepath.EvalSymlinks(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	realCacheDir, err := filepath.EvalSymlinks(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pathIsWithin(target, realCacheDir) {
+		t.Errorf("second installation at %s is not from the cache in %s", target, realCacheDir)
+	}
+
+	// A cached package that was modified must not be used.
+	if err := os.WriteFile(filepath.Join(target, "main.tf"), []byte("# modified\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, diags = install(t, "third")
+	assertDiagnosticSummary(t, diags, "Failed to download module")
+}
+
+// writeTestModuleArchive writes a gzipped tar archive with the given files
+// to the given path.
+func writeTestModuleArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModuleInstaller_oci(t *testing.T) {
+	store, err := orasOCI.NewWithContext(t.Context(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := pushOCIModulePackage(t, store, "1.0.0", `variable "first" {}`)
+
+	dir := t.TempDir()
+	src := "module \"child\" {\n  source = \"oci://example.com/modules/network:1.0.0\"\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pkgAddr := addrs.ModulePackage("oci://example.com/modules/network:1.0.0")
+	locks := depsfile.NewLocks()
+
+	install := func(t *testing.T, modulesDir string, upgrade bool) *configs.Module {
+		t.Helper()
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		inst := NewModuleInstaller(filepath.Join(dir, modulesDir), loader, nil)
+		inst.SetPackageFetcherEnvironment(testOCIFetcherEnv{store: store})
+		inst.SetLocks(locks)
+		cfg, diags := inst.InstallModules(context.Background(), dir, "tests", upgrade, false, &testInstallHooks{}, configs.RootModuleCallForTesting())
+		assertNoDiagnostics(t, diags)
+		return cfg.Children["child"].Module
+	}
+
+	mod := install(t, ".terraform/modules-1", false)
+	if _, ok := mod.Variables["first"]; !ok {
+		t.Fatalf("wrong module package installed")
+	}
+	if lock := locks.Module(pkgAddr); lock == nil || lock.Digest() != first.Digest.String() {
+		t.Fatalf("wrong lock for %s: %#v", pkgAddr, lock)
+	}
+
+	// Moving the tag doesn't affect a new installation, because the lock
+	// pins the artifact it previously referred to.
+	second := pushOCIModulePackage(t, store, "1.0.0", `variable "second" {}`)
+	mod = install(t, ".terraform/modules-2", false)
+	if _, ok := mod.Variables["first"]; !ok {
+		t.Fatalf("installed the artifact the tag refers to, rather than the locked artifact")
+	}
+
+	// Upgrading selects whichever artifact the tag now refers to.
+	mod = install(t, ".terraform/modules-3", true)
+	if _, ok := mod.Variables["second"]; !ok {
+		t.Fatalf("didn't upgrade to the artifact the tag refers to")
+	}
+	if got, want := locks.Module(pkgAddr).Digest(), second.Digest.String(); got != want {
+		t.Fatalf("wrong digest after upgrade\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestModuleInstaller_packageHash(t *testing.T) {
+	pkgDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pkgDir, "main.tf"), []byte(`variable "a" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wantHash, err := getmodules.PackageHash(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := "file::" + filepath.ToSlash(pkgDir)
+	pkgAddr := addrs.ModulePackage("file::file://" + filepath.ToSlash(pkgDir))
+
+	install := func(t *testing.T, dir string, locks *depsfile.Locks, upgrade bool) tfdiags.Diagnostics {
+		t.Helper()
+		loader, close := configload.NewLoaderForTests(t)
+		defer close()
+		inst := NewModuleInstaller(filepath.Join(dir, ".terraform/modules"), loader, nil)
+		inst.SetLocks(locks)
+		_, diags := inst.InstallModules(context.Background(), dir, "tests", upgrade, false, &testInstallHooks{}, configs.RootModuleCallForTesting())
+		return diags
+	}
+	writeConfig := func(t *testing.T, dir, integrity string) {
+		t.Helper()
+		src := fmt.Sprintf("module \"child\" {\n  source = %q\n", source)
+		if integrity != "" {
+			src += fmt.Sprintf("  integrity = %q\n", integrity)
+		}
+		src += "}\n"
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	writeConfig(t, dir, wantHash)
+	locks := depsfile.NewLocks()
+	locks.SetModule("git::https://example.com/unused.git", "", wantHash)
+	assertNoDiagnostics(t, install(t, dir, locks, false))
+	if lock := locks.Module(pkgAddr); lock == nil || lock.Hash() != wantHash {
+		t.Fatalf("wrong lock for %s: %#v", pkgAddr, lock)
+	}
+	if got := len(locks.AllModules()); got != 1 {
+		t.Errorf("the lock for the unused module package was not removed")
+	}
+
+	// The package changes without its address changing, as when a git tag
+	// is moved. Because this package is linked rather than copied, this
+	// also changes the existing installation, which is verified too.
+	if err := os.WriteFile(filepath.Join(pkgDir, "main.tf"), []byte(`variable "b" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diags := install(t, dir, locks, false)
+	assertDiagnosticSummary(t, diags, "Module package integrity check failed")
+
+	writeConfig(t, dir, "")
+	diags = install(t, dir, locks, false)
+	assertDiagnosticSummary(t, diags, "Module package doesn't match the dependency lock file")
+
+	// Upgrading accepts the new content and records its hash.
+	assertNoDiagnostics(t, install(t, dir, locks, true))
+	if lock := locks.Module(pkgAddr); lock == nil || lock.Hash() == wantHash {
+		t.Fatalf("the lock was not updated: %#v", lock)
+	}
+}
+
 func TestModuleInstaller_invalidVersionConstraintGetter(t *testing.T) {
 	fixtureDir := filepath.Clean("testdata/invalid-version-constraint")
 	dir, done := tempChdir(t, fixtureDir)
@@ -960,6 +1304,63 @@ func (h *testInstallHooks) Install(moduleAddr string, version *version.Version,
 // working directory.
 //
 // Tests using this helper cannot safely be run in parallel with other tests.
+// testOCIFetcherEnv is a getmodules.PackageFetcherEnvironment that serves
+// all OCI repositories from the same store.
+type testOCIFetcherEnv struct {
+	store getmodules.OCIRepositoryStore
+}
+
+func (e testOCIFetcherEnv) OCIRepositoryStore(_ context.Context, _, _ string) (getmodules.OCIRepositoryStore, error) {
+	return e.store, nil
+}
+
+// pushOCIModulePackage pushes a module package containing a main.tf file with
+// the given content to the given store, and tags it with the given tag.
+func pushOCIModulePackage(t *testing.T, store *orasOCI.Store, tag, mainTF string) ociv1.Descriptor {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("main.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(mainTF)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	push := func(mediaType string, content []byte) ociv1.Descriptor {
+		desc := ociv1.Descriptor{
+			MediaType: mediaType,
+			Digest:    ociDigest.FromBytes(content),
+			Size:      int64(len(content)),
+		}
+		if err := store.Push(t.Context(), desc, bytes.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+		return desc
+	}
+	blob := push("archive/zip", buf.Bytes())
+	manifest, err := json.Marshal(ociv1.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ociv1.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.opentofu.modulepkg",
+		Config:       ociv1.DescriptorEmptyJSON,
+		Layers:       []ociv1.Descriptor{blob},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := push(ociv1.MediaTypeImageManifest, manifest)
+	if err := store.Tag(t.Context(), desc, tag); err != nil {
+		t.Fatal(err)
+	}
+	return desc
+}
+
 func tempChdir(t *testing.T, sourceDir string) (string, func()) {
 	t.Helper()
 
@@ -1046,3 +1447,37 @@ func assertResultDeepEqual(t *testing.T, got, want interface{}) bool {
 	}
 	return false
 }
+
+func TestModuleInstaller_packageInstallPath(t *testing.T) {
+	inst := &ModuleInstaller{modsDir: filepath.Join(".terraform", "modules")}
+
+	short := addrs.Module{"network", "subnets"}
+	if got, want := inst.packageInstallPath(short), filepath.Join(".terraform", "modules", "network.subnets"); got != want {
+		t.Errorf("wrong path for short module path\ngot:  %s\nwant: %s", got, want)
+	}
+
+	long := addrs.Module{"platform", "regional_network", "availability_zones", "private_subnets", "nat_gateway"}
+	got := inst.packageInstallPath(long)
+	name := filepath.Base(got)
+	if len(name) != maxModuleDirNameLen {
+		t.Errorf("wrong name length %d for long module path; want %d", len(name), maxModuleDirNameLen)
+	}
+	if !strings.HasPrefix(name, "platform.regional_network.") || !strings.Contains(name, "~") {
+		t.Errorf("wrong name for long module path: %s", name)
+	}
+	if again := inst.packageInstallPath(long); again != got {
+		t.Errorf("path is not stable\nfirst:  %s\nsecond: %s", got, again)
+	}
+
+	other := addrs.Module{"platform", "regional_network", "availability_zones", "private_subnets", "nat_gateway_b"}
+	if otherPath := inst.packageInstallPath(other); otherPath == got {
+		t.Errorf("different module paths share the directory %s", got)
+	}
+
+	if !pathIsWithin(filepath.Join(got, "modules", "eip"), got) {
+		t.Errorf("subdirectory of %s is not considered to be within it", got)
+	}
+	if pathIsWithin(filepath.Join(".terraform", "modules", "platform.regional_network.availability_zones.private_subnets.nat_gateway"), got) {
+		t.Errorf("previous unshortened directory is considered to be within %s", got)
+	}
+}
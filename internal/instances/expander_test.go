@@ -501,6 +501,70 @@ func TestExpander(t *testing.T) {
 	})
 }
 
+func TestExpander_enabled(t *testing.T) {
+	onResourceAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test",
+		Name: "on",
+	}
+	offResourceAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test",
+		Name: "off",
+	}
+
+	// The steps below are assuming a configuration tree like the following:
+	// - root module
+	//   - resource test.on with enabled = true
+	//   - resource test.off with enabled = false
+	//   - child module "on" with enabled = true
+	//     - resource test.on with enabled = true
+	//   - child module "off" with enabled = false
+	ex := NewExpander()
+	ex.SetResourceEnabled(addrs.RootModuleInstance, onResourceAddr, true)
+	ex.SetResourceEnabled(addrs.RootModuleInstance, offResourceAddr, false)
+	ex.SetModuleEnabled(addrs.RootModuleInstance, addrs.ModuleCall{Name: "on"}, true)
+	ex.SetResourceEnabled(addrs.RootModuleInstance.Child("on", addrs.NoKey), onResourceAddr, true)
+	ex.SetModuleEnabled(addrs.RootModuleInstance, addrs.ModuleCall{Name: "off"}, false)
+
+	t.Run("resource enabled", func(t *testing.T) {
+		got := ex.ExpandModuleResource(addrs.RootModule, onResourceAddr)
+		want := []addrs.AbsResourceInstance{
+			mustAbsResourceInstanceAddr("test.on"),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("resource disabled", func(t *testing.T) {
+		got := ex.ExpandModuleResource(addrs.RootModule, offResourceAddr)
+		if len(got) != 0 {
+			t.Errorf("unexpected instances %#v", got)
+		}
+	})
+	t.Run("module enabled", func(t *testing.T) {
+		got := ex.ExpandModuleResource(mustModuleAddr("on"), onResourceAddr)
+		want := []addrs.AbsResourceInstance{
+			mustAbsResourceInstanceAddr("module.on.test.on"),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+	t.Run("module disabled", func(t *testing.T) {
+		got := ex.ExpandModule(mustModuleAddr("off"))
+		if len(got) != 0 {
+			t.Errorf("unexpected instances %#v", got)
+		}
+	})
+	t.Run("resource enabled repetition data", func(t *testing.T) {
+		got := ex.GetResourceInstanceRepetitionData(mustAbsResourceInstanceAddr("test.on"))
+		if got != (RepetitionData{}) {
+			t.Errorf("unexpected repetition data %#v", got)
+		}
+	})
+}
+
 func mustAbsResourceInstanceAddr(str string) addrs.AbsResourceInstance {
 	addr, diags := addrs.ParseAbsResourceInstanceStr(str)
 	if diags.HasErrors() {
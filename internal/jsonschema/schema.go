@@ -0,0 +1,423 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonschema validates JSON documents against a JSON Schema.
+//
+// It supports the validation keywords of JSON Schema draft 2020-12 that
+// don't need access to other documents, along with the array form of items
+// and the additionalItems keyword of draft 7. References are only resolved
+// within the same document, using JSON pointers like "#/$defs/name".
+// Annotation keywords, such as title, description and format, are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Schema is a parsed JSON Schema document.
+type Schema struct {
+	root *schema
+}
+
+// schema is a parsed schema object, or a boolean schema if always is set.
+type schema struct {
+	always *bool
+
+	types      []string
+	enum       []any
+	constVal   any
+	hasConst   bool
+	ref        *schema
+	allOf      []*schema
+	anyOf      []*schema
+	oneOf      []*schema
+	not        *schema
+	ifSchema   *schema
+	thenSchema *schema
+	elseSchema *schema
+
+	minimum          *big.Rat
+	maximum          *big.Rat
+	exclusiveMinimum *big.Rat
+	exclusiveMaximum *big.Rat
+	multipleOf       *big.Rat
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	prefixItems []*schema
+	items       *schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+	contains    *schema
+
+	properties           map[string]*schema
+	patternProperties    []patternSchema
+	additionalProperties *schema
+	required             []string
+	dependentRequired    map[string][]string
+	propertyNames        *schema
+	minProperties        *int
+	maxProperties        *int
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *schema
+}
+
+// Parse parses the given JSON Schema document.
+func Parse(src []byte) (*Schema, error) {
+	doc, err := decode(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	c := &compiler{
+		doc:  doc,
+		refs: make(map[string]*schema),
+	}
+	root, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// decode decodes a JSON document, keeping its numbers as json.Number so
+// that they don't lose precision.
+func decode(src []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// compiler parses the schema objects of a document, resolving the references
+// between them.
+type compiler struct {
+	doc any
+
+	// refs are the schemas that references point to, by JSON pointer. They
+	// are added before they are compiled, so that recursive schemas refer to
+	// themselves.
+	refs map[string]*schema
+}
+
+func (c *compiler) compile(v any, ptr string) (*schema, error) {
+	if b, ok := v.(bool); ok {
+		return &schema{always: &b}, nil
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, c.errorf(ptr, "a schema must be an object or a boolean")
+	}
+
+	s := &schema{}
+	var err error
+	for _, key := range sortedKeys(obj) {
+		val := obj[key]
+		kp := ptr + "/" + escapePointer(key)
+		switch key {
+		case "type":
+			s.types, err = c.types(val, kp)
+		case "enum":
+			arr, ok := val.([]any)
+			if !ok {
+				return nil, c.errorf(kp, "must be an array")
+			}
+			s.enum = arr
+		case "const":
+			s.constVal, s.hasConst = val, true
+		case "$ref":
+			s.ref, err = c.ref(val, kp)
+		case "allOf":
+			s.allOf, err = c.schemaArray(val, kp)
+		case "anyOf":
+			s.anyOf, err = c.schemaArray(val, kp)
+		case "oneOf":
+			s.oneOf, err = c.schemaArray(val, kp)
+		case "not":
+			s.not, err = c.compile(val, kp)
+		case "if":
+			s.ifSchema, err = c.compile(val, kp)
+		case "then":
+			s.thenSchema, err = c.compile(val, kp)
+		case "else":
+			s.elseSchema, err = c.compile(val, kp)
+		case "minimum":
+			s.minimum, err = c.number(val, kp)
+		case "maximum":
+			s.maximum, err = c.number(val, kp)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum, err = c.number(val, kp)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum, err = c.number(val, kp)
+		case "multipleOf":
+			s.multipleOf, err = c.number(val, kp)
+			if err == nil && s.multipleOf.Sign() <= 0 {
+				err = c.errorf(kp, "must be greater than zero")
+			}
+		case "minLength":
+			s.minLength, err = c.count(val, kp)
+		case "maxLength":
+			s.maxLength, err = c.count(val, kp)
+		case "pattern":
+			s.pattern, err = c.pattern(val, kp)
+		case "prefixItems":
+			s.prefixItems, err = c.schemaArray(val, kp)
+		case "items":
+			// Before draft 2020-12, an array of schemas was used for what
+			// is now prefixItems, and additionalItems for what is now items.
+			if _, isArray := val.([]any); isArray {
+				s.prefixItems, err = c.schemaArray(val, kp)
+			} else {
+				s.items, err = c.compile(val, kp)
+			}
+		case "additionalItems":
+			if _, isArray := obj["items"].([]any); isArray {
+				s.items, err = c.compile(val, kp)
+			}
+		case "minItems":
+			s.minItems, err = c.count(val, kp)
+		case "maxItems":
+			s.maxItems, err = c.count(val, kp)
+		case "uniqueItems":
+			b, ok := val.(bool)
+			if !ok {
+				return nil, c.errorf(kp, "must be a boolean")
+			}
+			s.uniqueItems = b
+		case "contains":
+			s.contains, err = c.compile(val, kp)
+		case "properties":
+			s.properties, err = c.schemaMap(val, kp)
+		case "patternProperties":
+			var schemas map[string]*schema
+			schemas, err = c.schemaMap(val, kp)
+			for _, p := range sortedKeys(schemas) {
+				re, reErr := c.pattern(p, kp+"/"+escapePointer(p))
+				if reErr != nil {
+					return nil, reErr
+				}
+				s.patternProperties = append(s.patternProperties, patternSchema{re, schemas[p]})
+			}
+		case "additionalProperties":
+			s.additionalProperties, err = c.compile(val, kp)
+		case "required":
+			s.required, err = c.strings(val, kp)
+		case "dependentRequired":
+			props, ok := val.(map[string]any)
+			if !ok {
+				return nil, c.errorf(kp, "must be an object")
+			}
+			s.dependentRequired = make(map[string][]string, len(props))
+			for name, deps := range props {
+				s.dependentRequired[name], err = c.strings(deps, kp+"/"+escapePointer(name))
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "propertyNames":
+			s.propertyNames, err = c.compile(val, kp)
+		case "minProperties":
+			s.minProperties, err = c.count(val, kp)
+		case "maxProperties":
+			s.maxProperties, err = c.count(val, kp)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (c *compiler) ref(v any, ptr string) (*schema, error) {
+	ref, ok := v.(string)
+	if !ok {
+		return nil, c.errorf(ptr, "must be a string")
+	}
+	target, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, c.errorf(ptr, "only references within the same document, starting with #, are supported")
+	}
+	target, err := url.PathUnescape(target)
+	if err != nil {
+		return nil, c.errorf(ptr, "invalid reference %q: %s", ref, err)
+	}
+	if s, ok := c.refs[target]; ok {
+		return s, nil
+	}
+
+	v, err = resolvePointer(c.doc, target)
+	if err != nil {
+		return nil, c.errorf(ptr, "invalid reference %q: %s", ref, err)
+	}
+	s := &schema{}
+	c.refs[target] = s
+	compiled, err := c.compile(v, target)
+	if err != nil {
+		return nil, err
+	}
+	*s = *compiled
+	return s, nil
+}
+
+func (c *compiler) schemaArray(v any, ptr string) ([]*schema, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, c.errorf(ptr, "must be an array of schemas")
+	}
+	ret := make([]*schema, len(arr))
+	for i, elem := range arr {
+		s, err := c.compile(elem, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = s
+	}
+	return ret, nil
+}
+
+func (c *compiler) schemaMap(v any, ptr string) (map[string]*schema, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, c.errorf(ptr, "must be an object")
+	}
+	ret := make(map[string]*schema, len(obj))
+	for key, elem := range obj {
+		s, err := c.compile(elem, ptr+"/"+escapePointer(key))
+		if err != nil {
+			return nil, err
+		}
+		ret[key] = s
+	}
+	return ret, nil
+}
+
+func (c *compiler) types(v any, ptr string) ([]string, error) {
+	if str, ok := v.(string); ok {
+		v = []any{str}
+	}
+	types, err := c.strings(v, ptr)
+	if err != nil {
+		return nil, err
+	}
+	for _, ty := range types {
+		switch ty {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, c.errorf(ptr, "unsupported type %q", ty)
+		}
+	}
+	return types, nil
+}
+
+func (c *compiler) strings(v any, ptr string) ([]string, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, c.errorf(ptr, "must be an array of strings")
+	}
+	ret := make([]string, len(arr))
+	for i, elem := range arr {
+		str, ok := elem.(string)
+		if !ok {
+			return nil, c.errorf(ptr, "must be an array of strings")
+		}
+		ret[i] = str
+	}
+	return ret, nil
+}
+
+func (c *compiler) number(v any, ptr string) (*big.Rat, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, c.errorf(ptr, "must be a number")
+	}
+	r, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return nil, c.errorf(ptr, "invalid number %s", n)
+	}
+	return r, nil
+}
+
+func (c *compiler) count(v any, ptr string) (*int, error) {
+	r, err := c.number(v, ptr)
+	if err != nil || !r.IsInt() || r.Sign() < 0 || !r.Num().IsInt64() {
+		return nil, c.errorf(ptr, "must be a non-negative integer")
+	}
+	n := int(r.Num().Int64())
+	return &n, nil
+}
+
+func (c *compiler) pattern(v any, ptr string) (*regexp.Regexp, error) {
+	str, ok := v.(string)
+	if !ok {
+		return nil, c.errorf(ptr, "must be a string")
+	}
+	re, err := regexp.Compile(str)
+	if err != nil {
+		return nil, c.errorf(ptr, "invalid regular expression: %s", err)
+	}
+	return re, nil
+}
+
+func (c *compiler) errorf(ptr string, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if ptr == "" {
+		return fmt.Errorf("invalid schema: %s", msg)
+	}
+	return fmt.Errorf("invalid schema at %s: %s", ptr, msg)
+}
+
+// resolvePointer returns the value that the given JSON pointer refers to
+// within doc.
+func resolvePointer(doc any, ptr string) (any, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("JSON pointer must start with /")
+	}
+	v := doc
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		switch cur := v.(type) {
+		case map[string]any:
+			next, ok := cur[tok]
+			if !ok {
+				return nil, fmt.Errorf("no property %q", tok)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(cur) {
+				return nil, fmt.Errorf("no element %q", tok)
+			}
+			v = cur[i]
+		default:
+			return nil, fmt.Errorf("no value at %q", tok)
+		}
+	}
+	return v, nil
+}
+
+func escapePointer(tok string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+}
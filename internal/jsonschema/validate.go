@@ -0,0 +1,372 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error describes a way in which a document doesn't conform to a schema.
+type Error struct {
+	// Location is the JSON pointer to the value that is invalid, which is
+	// empty for the document as a whole.
+	Location string
+
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Location == "" {
+		return e.Message
+	}
+	return e.Location + ": " + e.Message
+}
+
+// ValidateJSON validates the given JSON document against the schema. It
+// returns an error only if the document isn't valid JSON, and otherwise
+// returns the ways in which it doesn't conform to the schema, which is none
+// if it's valid.
+func (s *Schema) ValidateJSON(src []byte) ([]Error, error) {
+	doc, err := decode(src)
+	if err != nil {
+		return nil, err
+	}
+	var errs []Error
+	s.root.validate(doc, "", &errs)
+	return errs, nil
+}
+
+// validate appends the ways in which v, at the given location, doesn't
+// conform to s to errs.
+func (s *schema) validate(v any, loc string, errs *[]Error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, Error{Location: loc, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.always != nil {
+		if !*s.always {
+			fail("no value is allowed here")
+		}
+		return
+	}
+
+	if s.ref != nil {
+		s.ref.validate(v, loc, errs)
+	}
+	if len(s.types) != 0 && !matchesType(v, s.types) {
+		fail("must be %s, not %s", strings.Join(s.types, " or "), typeName(v))
+		// The other keywords would only report the same problem in
+		// different ways.
+		return
+	}
+	if s.enum != nil && !containsValue(s.enum, v) {
+		vals := make([]string, len(s.enum))
+		for i, val := range s.enum {
+			vals[i] = displayValue(val)
+		}
+		fail("must be one of %s", strings.Join(vals, ", "))
+	}
+	if s.hasConst && !equal(s.constVal, v) {
+		fail("must be %s", displayValue(s.constVal))
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, loc, errs)
+	}
+	if s.anyOf != nil {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.valid(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("must match at least one of the schemas in anyOf")
+		}
+	}
+	if s.oneOf != nil {
+		matches := 0
+		for _, sub := range s.oneOf {
+			if sub.valid(v) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("must match exactly one of the schemas in oneOf, but matches %d", matches)
+		}
+	}
+	if s.not != nil && s.not.valid(v) {
+		fail("must not match the schema in not")
+	}
+	if s.ifSchema != nil {
+		if s.ifSchema.valid(v) {
+			if s.thenSchema != nil {
+				s.thenSchema.validate(v, loc, errs)
+			}
+		} else if s.elseSchema != nil {
+			s.elseSchema.validate(v, loc, errs)
+		}
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		s.validateNumber(v, fail)
+	case string:
+		s.validateString(v, fail)
+	case []any:
+		s.validateArray(v, loc, errs, fail)
+	case map[string]any:
+		s.validateObject(v, loc, errs, fail)
+	}
+}
+
+// valid returns true if v conforms to s.
+func (s *schema) valid(v any) bool {
+	var errs []Error
+	s.validate(v, "", &errs)
+	return len(errs) == 0
+}
+
+func (s *schema) validateNumber(v json.Number, fail func(string, ...any)) {
+	n, ok := new(big.Rat).SetString(v.String())
+	if !ok {
+		fail("invalid number %s", v)
+		return
+	}
+	if s.minimum != nil && n.Cmp(s.minimum) < 0 {
+		fail("must be greater than or equal to %s", s.minimum.RatString())
+	}
+	if s.maximum != nil && n.Cmp(s.maximum) > 0 {
+		fail("must be less than or equal to %s", s.maximum.RatString())
+	}
+	if s.exclusiveMinimum != nil && n.Cmp(s.exclusiveMinimum) <= 0 {
+		fail("must be greater than %s", s.exclusiveMinimum.RatString())
+	}
+	if s.exclusiveMaximum != nil && n.Cmp(s.exclusiveMaximum) >= 0 {
+		fail("must be less than %s", s.exclusiveMaximum.RatString())
+	}
+	if s.multipleOf != nil && !new(big.Rat).Quo(n, s.multipleOf).IsInt() {
+		fail("must be a multiple of %s", s.multipleOf.RatString())
+	}
+}
+
+func (s *schema) validateString(v string, fail func(string, ...any)) {
+	length := utf8.RuneCountInString(v)
+	if s.minLength != nil && length < *s.minLength {
+		fail("must be at least %d characters long", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		fail("must be at most %d characters long", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		fail("must match the pattern %s", s.pattern.String())
+	}
+}
+
+func (s *schema) validateArray(v []any, loc string, errs *[]Error, fail func(string, ...any)) {
+	if s.minItems != nil && len(v) < *s.minItems {
+		fail("must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		fail("must have at most %d items", *s.maxItems)
+	}
+	if s.uniqueItems {
+	Unique:
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if equal(v[i], v[j]) {
+					fail("must not contain duplicate items, but items %d and %d are equal", i, j)
+					break Unique
+				}
+			}
+		}
+	}
+	if s.contains != nil {
+		found := false
+		for _, elem := range v {
+			if s.contains.valid(elem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must contain at least one item that matches the schema in contains")
+		}
+	}
+
+	for i, elem := range v {
+		elemLoc := loc + "/" + strconv.Itoa(i)
+		switch {
+		case i < len(s.prefixItems):
+			s.prefixItems[i].validate(elem, elemLoc, errs)
+		case s.items != nil:
+			s.items.validate(elem, elemLoc, errs)
+		}
+	}
+}
+
+func (s *schema) validateObject(v map[string]any, loc string, errs *[]Error, fail func(string, ...any)) {
+	if s.minProperties != nil && len(v) < *s.minProperties {
+		fail("must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(v) > *s.maxProperties {
+		fail("must have at most %d properties", *s.maxProperties)
+	}
+	for _, name := range s.required {
+		if _, ok := v[name]; !ok {
+			fail("missing required property %q", name)
+		}
+	}
+	for _, name := range sortedKeys(s.dependentRequired) {
+		if _, ok := v[name]; !ok {
+			continue
+		}
+		for _, dep := range s.dependentRequired[name] {
+			if _, ok := v[dep]; !ok {
+				fail("missing property %q, which is required when %q is present", dep, name)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(v) {
+		elem := v[name]
+		elemLoc := loc + "/" + escapePointer(name)
+		if s.propertyNames != nil && !s.propertyNames.valid(name) {
+			fail("property name %q does not match the schema in propertyNames", name)
+		}
+
+		matched := false
+		if sub, ok := s.properties[name]; ok {
+			sub.validate(elem, elemLoc, errs)
+			matched = true
+		}
+		for _, pp := range s.patternProperties {
+			if pp.pattern.MatchString(name) {
+				pp.schema.validate(elem, elemLoc, errs)
+				matched = true
+			}
+		}
+		if matched || s.additionalProperties == nil {
+			continue
+		}
+		if s.additionalProperties.always != nil && !*s.additionalProperties.always {
+			fail("property %q is not allowed", name)
+			continue
+		}
+		s.additionalProperties.validate(elem, elemLoc, errs)
+	}
+}
+
+func matchesType(v any, types []string) bool {
+	got := typeName(v)
+	for _, ty := range types {
+		if ty == got {
+			return true
+		}
+		if ty == "integer" && got == "number" {
+			n, ok := new(big.Rat).SetString(v.(json.Number).String())
+			if ok && n.IsInt() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeName returns the JSON Schema type of the given decoded JSON value.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		panic(fmt.Sprintf("unexpected JSON value of type %T", v))
+	}
+}
+
+// equal returns true if the given decoded JSON values are equal. Numbers are
+// equal if they have the same value, however they are written.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		an, aOK := new(big.Rat).SetString(a.String())
+		bn, bOK := new(big.Rat).SetString(b.String())
+		return aOK && bOK && an.Cmp(bn) == 0
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func containsValue(vals []any, v any) bool {
+	for _, val := range vals {
+		if equal(val, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayValue returns the given value from a schema as JSON, for use in
+// messages.
+func displayValue(v any) string {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(buf)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
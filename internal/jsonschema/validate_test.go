@@ -0,0 +1,208 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateJSON(t *testing.T) {
+	tests := map[string]struct {
+		Schema string
+		Doc    string
+		Want   []string
+	}{
+		"true": {
+			`true`,
+			`{"a": 1}`,
+			nil,
+		},
+		"false": {
+			`false`,
+			`{"a": 1}`,
+			[]string{"no value is allowed here"},
+		},
+		"type": {
+			`{"type": ["string", "null"]}`,
+			`1`,
+			[]string{"must be string or null, not number"},
+		},
+		"integer": {
+			`{"type": "integer"}`,
+			`1.0`,
+			nil,
+		},
+		"not integer": {
+			`{"type": "integer"}`,
+			`1.5`,
+			[]string{"must be integer, not number"},
+		},
+		"enum": {
+			`{"enum": ["a", 1, null]}`,
+			`"b"`,
+			[]string{`must be one of "a", 1, null`},
+		},
+		"enum number": {
+			`{"enum": [1, 2]}`,
+			`2.0`,
+			nil,
+		},
+		"const": {
+			`{"const": {"a": [1]}}`,
+			`{"a": [2]}`,
+			[]string{`must be {"a":[1]}`},
+		},
+		"numbers": {
+			`{"minimum": 1, "exclusiveMaximum": 10, "multipleOf": 0.5}`,
+			`10`,
+			[]string{"must be less than 10"},
+		},
+		"multipleOf": {
+			`{"multipleOf": 0.1}`,
+			`0.3`,
+			nil,
+		},
+		"strings": {
+			`{"minLength": 3, "pattern": "^[a-z]+$"}`,
+			`"Aé"`,
+			[]string{
+				"must be at least 3 characters long",
+				"must match the pattern ^[a-z]+$",
+			},
+		},
+		"arrays": {
+			`{"items": {"type": "string"}, "prefixItems": [{"type": "number"}], "maxItems": 3, "uniqueItems": true}`,
+			`[1, "a", 2, "a"]`,
+			[]string{
+				"must have at most 3 items",
+				"must not contain duplicate items, but items 1 and 3 are equal",
+				"/2: must be string, not number",
+			},
+		},
+		"draft 7 items": {
+			`{"items": [{"type": "number"}], "additionalItems": false}`,
+			`[1, 2]`,
+			[]string{"/1: no value is allowed here"},
+		},
+		"contains": {
+			`{"contains": {"const": "x"}}`,
+			`["a", "b"]`,
+			[]string{"must contain at least one item that matches the schema in contains"},
+		},
+		"objects": {
+			`{
+				"type": "object",
+				"required": ["name", "size"],
+				"properties": {
+					"name": {"type": "string"},
+					"tags": {
+						"type": "object",
+						"additionalProperties": {"type": "string"}
+					}
+				},
+				"patternProperties": {"^x-": {"type": "boolean"}},
+				"additionalProperties": false
+			}`,
+			`{"name": 1, "tags": {"a/b": true}, "x-enabled": true, "other": 1}`,
+			[]string{
+				`missing required property "size"`,
+				"/name: must be string, not number",
+				`property "other" is not allowed`,
+				"/tags/a~1b: must be string, not boolean",
+			},
+		},
+		"dependentRequired": {
+			`{"dependentRequired": {"port": ["host"]}}`,
+			`{"port": 80}`,
+			[]string{`missing property "host", which is required when "port" is present`},
+		},
+		"propertyNames": {
+			`{"propertyNames": {"maxLength": 3}, "maxProperties": 1}`,
+			`{"abcd": 1, "a": 2}`,
+			[]string{
+				"must have at most 1 properties",
+				`property name "abcd" does not match the schema in propertyNames`,
+			},
+		},
+		"combinators": {
+			`{"anyOf": [{"type": "string"}, {"type": "boolean"}], "oneOf": [{"minimum": 1}, {"minimum": 2}], "not": {"const": 5}}`,
+			`5`,
+			[]string{
+				"must match at least one of the schemas in anyOf",
+				"must match exactly one of the schemas in oneOf, but matches 2",
+				"must not match the schema in not",
+			},
+		},
+		"if then else": {
+			`{"if": {"properties": {"kind": {"const": "a"}}}, "then": {"required": ["a"]}, "else": {"required": ["b"]}}`,
+			`{"kind": "c"}`,
+			[]string{`missing required property "b"`},
+		},
+		"ref": {
+			`{
+				"$defs": {
+					"node": {
+						"type": "object",
+						"properties": {
+							"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+						},
+						"required": ["name"]
+					}
+				},
+				"$ref": "#/$defs/node"
+			}`,
+			`{"name": "a", "children": [{"name": "b"}, {"children": []}]}`,
+			[]string{`/children/1: missing required property "name"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse([]byte(test.Schema))
+			if err != nil {
+				t.Fatalf("unexpected error parsing schema: %s", err)
+			}
+			errs, err := s.ValidateJSON([]byte(test.Doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong errors\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	tests := map[string]string{
+		`{`:                           `invalid JSON: unexpected EOF`,
+		`1`:                           `invalid schema: a schema must be an object or a boolean`,
+		`{"type": "text"}`:            `invalid schema at /type: unsupported type "text"`,
+		`{"minLength": -1}`:           `invalid schema at /minLength: must be a non-negative integer`,
+		`{"pattern": "("}`:            "invalid schema at /pattern: invalid regular expression: error parsing regexp: missing closing ): `(`",
+		`{"$ref": "other.json#/a"}`:   `invalid schema at /$ref: only references within the same document, starting with #, are supported`,
+		`{"$ref": "#/$defs/missing"}`: `invalid schema at /$ref: invalid reference "#/$defs/missing": no property "$defs"`,
+		`{"properties": {"a": {"multipleOf": 0}}}`: `invalid schema at /properties/a/multipleOf: must be greater than zero`,
+	}
+
+	for src, want := range tests {
+		t.Run(src, func(t *testing.T) {
+			_, err := Parse([]byte(src))
+			if err == nil {
+				t.Fatal("succeeded; want error")
+			}
+			if got := err.Error(); got != want {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
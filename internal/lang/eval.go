@@ -382,35 +382,37 @@ func (s *Scope) evalContext(parent *hcl.EvalContext, refs []*addrs.Reference, se
 type evalVarBuilder struct {
 	s *Scope
 
-	dataResources    map[string]map[string]cty.Value
-	managedResources map[string]map[string]cty.Value
-	wholeModules     map[string]cty.Value
-	inputVariables   map[string]cty.Value
-	localValues      map[string]cty.Value
-	outputValues     map[string]cty.Value
-	pathAttrs        map[string]cty.Value
-	terraformAttrs   map[string]cty.Value
-	countAttrs       map[string]cty.Value
-	forEachAttrs     map[string]cty.Value
-	checkBlocks      map[string]cty.Value
-	self             cty.Value
+	dataResources      map[string]map[string]cty.Value
+	managedResources   map[string]map[string]cty.Value
+	ephemeralResources map[string]map[string]cty.Value
+	wholeModules       map[string]cty.Value
+	inputVariables     map[string]cty.Value
+	localValues        map[string]cty.Value
+	outputValues       map[string]cty.Value
+	pathAttrs          map[string]cty.Value
+	terraformAttrs     map[string]cty.Value
+	countAttrs         map[string]cty.Value
+	forEachAttrs       map[string]cty.Value
+	checkBlocks        map[string]cty.Value
+	self               cty.Value
 }
 
 func (s *Scope) newEvalVarBuilder() *evalVarBuilder {
 	return &evalVarBuilder{
 		s: s,
 
-		dataResources:    map[string]map[string]cty.Value{},
-		managedResources: map[string]map[string]cty.Value{},
-		wholeModules:     map[string]cty.Value{},
-		inputVariables:   map[string]cty.Value{},
-		localValues:      map[string]cty.Value{},
-		outputValues:     map[string]cty.Value{},
-		pathAttrs:        map[string]cty.Value{},
-		terraformAttrs:   map[string]cty.Value{},
-		countAttrs:       map[string]cty.Value{},
-		forEachAttrs:     map[string]cty.Value{},
-		checkBlocks:      map[string]cty.Value{},
+		dataResources:      map[string]map[string]cty.Value{},
+		managedResources:   map[string]map[string]cty.Value{},
+		ephemeralResources: map[string]map[string]cty.Value{},
+		wholeModules:       map[string]cty.Value{},
+		inputVariables:     map[string]cty.Value{},
+		localValues:        map[string]cty.Value{},
+		outputValues:       map[string]cty.Value{},
+		pathAttrs:          map[string]cty.Value{},
+		terraformAttrs:     map[string]cty.Value{},
+		countAttrs:         map[string]cty.Value{},
+		forEachAttrs:       map[string]cty.Value{},
+		checkBlocks:        map[string]cty.Value{},
 	}
 }
 
@@ -435,14 +437,22 @@ func (b *evalVarBuilder) putSelfValue(selfAddr addrs.Referenceable, ref *addrs.R
 		panic("scope SelfAddr attempting to alias itself")
 	}
 
-	// self can only be used within a resource instance
-	subj, ok := selfAddr.(addrs.ResourceInstance)
-	if !ok {
-		panic("BUG: self addr must be a resource instance, got " + reflect.TypeOf(selfAddr).String())
+	// self can only be used within a resource instance or, in the
+	// postconditions of a module call, a module call instance.
+	var val cty.Value
+	var valDiags tfdiags.Diagnostics
+	var key addrs.InstanceKey
+	switch subj := selfAddr.(type) {
+	case addrs.ResourceInstance:
+		val, valDiags = normalizeRefValue(b.s.Data.GetResource(subj.ContainingResource(), ref.SourceRange))
+		key = subj.Key
+	case addrs.ModuleCallInstance:
+		val, valDiags = normalizeRefValue(b.s.Data.GetModule(subj.Call, ref.SourceRange))
+		key = subj.Key
+	default:
+		panic("BUG: self addr must be a resource instance or module call instance, got " + reflect.TypeOf(selfAddr).String())
 	}
 
-	val, valDiags := normalizeRefValue(b.s.Data.GetResource(subj.ContainingResource(), ref.SourceRange))
-
 	diags = diags.Append(valDiags)
 
 	// Self is an exception in that it must always resolve to a
@@ -451,7 +461,7 @@ func (b *evalVarBuilder) putSelfValue(selfAddr addrs.Referenceable, ref *addrs.R
 	var hclDiags hcl.Diagnostics
 	// We should always have a valid self index by this point, but in
 	// the case of an error, self may end up as a cty.DynamicValue.
-	switch k := subj.Key.(type) {
+	switch k := key.(type) {
 	case addrs.IntKey:
 		b.self, hclDiags = hcl.Index(val, cty.NumberIntVal(int64(k)), ref.SourceRange.ToHCL().Ptr())
 	case addrs.StringKey:
@@ -533,6 +543,8 @@ func (b *evalVarBuilder) putResourceValue(res addrs.Resource, rng tfdiags.Source
 		into = b.managedResources
 	case addrs.DataResourceMode:
 		into = b.dataResources
+	case addrs.EphemeralResourceMode:
+		into = b.ephemeralResources
 	case addrs.InvalidResourceMode:
 		panic("BUG: got invalid resource mode")
 	default:
@@ -561,6 +573,7 @@ func (b *evalVarBuilder) buildAllVariablesInto(vals map[string]cty.Value) {
 	vals["resource"] = cty.ObjectVal(buildResourceObjects(b.managedResources))
 
 	vals["data"] = cty.ObjectVal(buildResourceObjects(b.dataResources))
+	vals["ephemeral"] = cty.ObjectVal(buildResourceObjects(b.ephemeralResources))
 	vals["module"] = cty.ObjectVal(b.wholeModules)
 	vals["var"] = cty.ObjectVal(b.inputVariables)
 	vals["local"] = cty.ObjectVal(b.localValues)
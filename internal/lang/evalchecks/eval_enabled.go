@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package evalchecks
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// EvaluateEnabledExpression is our standard mechanism for interpreting an
+// expression given for the "enabled" lifecycle argument of a resource or a
+// module call. This should be called during expansion in order to determine
+// whether the object has its single instance.
+//
+// EvaluateEnabledExpression differs from EvaluateEnabledExpressionValue by
+// returning an error if the value is not known, and converting the cty.Value
+// to a bool.
+//
+// If excludableAddr is non-nil then the unknown value error will include
+// an additional idea to exclude that address using the -exclude
+// planning option to converge over multiple plan/apply rounds.
+func EvaluateEnabledExpression(expr hcl.Expression, ctx EvaluateFunc, excludableAddr addrs.Targetable) (bool, tfdiags.Diagnostics) {
+	enabledVal, diags := EvaluateEnabledExpressionValue(expr, ctx)
+	if !enabledVal.IsKnown() {
+		suggestion := countCommandLineExcludeSuggestion(excludableAddr)
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   "The \"enabled\" value depends on resource attributes that cannot be determined until apply, so OpenTofu cannot predict whether the instance will be created.\n\n" + suggestion,
+			Subject:  expr.Range().Ptr(),
+			Extra:    DiagnosticCausedByUnknown(true),
+		})
+	}
+
+	if enabledVal.IsNull() || !enabledVal.IsKnown() {
+		return false, diags
+	}
+	return enabledVal.True(), diags
+}
+
+// EvaluateEnabledExpressionValue is like EvaluateEnabledExpression
+// except that it returns a cty.Value which must be a cty.Bool and can be
+// unknown.
+func EvaluateEnabledExpressionValue(expr hcl.Expression, ctx EvaluateFunc) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	nullEnabled := cty.NullVal(cty.Bool)
+	if expr == nil {
+		return nullEnabled, nil
+	}
+
+	enabledVal, enabledDiags := ctx(expr)
+	diags = diags.Append(enabledDiags)
+	if diags.HasErrors() {
+		return nullEnabled, diags
+	}
+
+	// Sensitive values are allowed here, as for count, because whether an
+	// object exists is visible anyway.
+	enabledVal, _ = enabledVal.Unmark()
+
+	enabledVal, err := convert.Convert(enabledVal, cty.Bool)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   fmt.Sprintf(`The given "enabled" argument value is unsuitable: %s.`, tfdiags.FormatError(err)),
+			Subject:  expr.Range().Ptr(),
+		})
+		return nullEnabled, diags
+	}
+
+	if enabledVal.IsNull() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   `The given "enabled" argument value is null. A boolean is required.`,
+			Subject:  expr.Range().Ptr(),
+		})
+		return nullEnabled, diags
+	}
+
+	return enabledVal, diags
+}
@@ -0,0 +1,93 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+package evalchecks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEvaluateEnabledExpression_valid(t *testing.T) {
+	tests := map[string]struct {
+		val  cty.Value
+		want bool
+	}{
+		"true": {
+			cty.True,
+			true,
+		},
+		"false": {
+			cty.False,
+			false,
+		},
+		"string": {
+			cty.StringVal("true"),
+			true,
+		},
+		"sensitive": {
+			cty.True.Mark(marks.Sensitive),
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := EvaluateEnabledExpression(hcltest.MockExprLiteral(test.val), mockEvaluateFunc(test.val), nil)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics: %s", diags.Err())
+			}
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %t\nwant: %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEnabledExpression_errors(t *testing.T) {
+	tests := map[string]struct {
+		val             cty.Value
+		detail          string
+		causedByUnknown bool
+	}{
+		"null": {
+			cty.NullVal(cty.Bool),
+			`The given "enabled" argument value is null. A boolean is required.`,
+			false,
+		},
+		"number": {
+			cty.NumberIntVal(1),
+			`The given "enabled" argument value is unsuitable: bool required.`,
+			false,
+		},
+		"unknown": {
+			cty.UnknownVal(cty.Bool),
+			`The "enabled" value depends on resource attributes that cannot be determined until apply`,
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := EvaluateEnabledExpression(hcltest.MockExprLiteral(test.val), mockEvaluateFunc(test.val), nil)
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics; want 1\n%s", len(diags), diags.Err())
+			}
+			if got, want := diags[0].Description().Summary, "Invalid enabled argument"; got != want {
+				t.Errorf("wrong summary\ngot:  %s\nwant: %s", got, want)
+			}
+			if got := diags[0].Description().Detail; !strings.HasPrefix(got, test.detail) {
+				t.Errorf("wrong detail\ngot:  %s\nwant: %s", got, test.detail)
+			}
+			if got, want := tfdiags.DiagnosticCausedByUnknown(diags[0]), test.causedByUnknown; got != want {
+				t.Errorf("wrong result from tfdiags.DiagnosticCausedByUnknown\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
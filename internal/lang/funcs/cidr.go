@@ -270,6 +270,111 @@ var CidrContainsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrOverlapsFunc constructs a function that checks whether two IP network
+// address prefixes have any addresses in common.
+var CidrOverlapsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix_a",
+			Type: cty.String,
+		},
+		{
+			Name: "prefix_b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		prefixA := args[0].AsString()
+		prefixB := args[1].AsString()
+
+		_, a, err := ipaddr.ParseCIDR(prefixA)
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		_, b, err := ipaddr.ParseCIDR(prefixB)
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid CIDR expression: %s", err)
+		}
+
+		// As for cidrcontains, we return an error for prefixes of different
+		// address families rather than false, so that the caller can
+		// distinguish a legitimate result from an erroneous check.
+		if (a.IP.To4() == nil) != (b.IP.To4() == nil) {
+			return cty.UnknownVal(cty.Bool), fmt.Errorf("address family mismatch: %s vs. %s", prefixA, prefixB)
+		}
+
+		// Two prefixes are either disjoint or one contains the other, so
+		// they overlap if either contains the first address of the other.
+		return cty.BoolVal(a.Contains(b.IP) || b.Contains(a.IP)), nil
+	},
+})
+
+// CidrSplitFunc constructs a function that divides an IP network address
+// prefix into all of the subnets of a longer prefix length that it contains.
+var CidrSplitFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "newbits",
+			Type: cty.Number,
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		var newbits int
+		if err := gocty.FromCtyValue(args[1], &newbits); err != nil {
+			return cty.UnknownVal(retType), function.NewArgError(1, err)
+		}
+
+		if newbits < 1 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "must extend prefix by at least one bit")
+		}
+		// The result has one element for each subnet, so we limit the number
+		// of subnets to keep the result a reasonable size even for the very
+		// large IPv6 prefixes.
+		if newbits > cidrSplitMaxBits {
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "may not extend prefix by more than %d bits", cidrSplitMaxBits)
+		}
+		prefixLen, addrLen := network.Mask.Size()
+		length := prefixLen + newbits
+		if length > addrLen {
+			protocol := "IPv4"
+			if addrLen == 128 {
+				protocol = "IPv6"
+			}
+			return cty.UnknownVal(retType), function.NewArgErrorf(1, "would extend prefix to %d bits, which is too long for an %s address", length, protocol)
+		}
+
+		current, err := cidr.Subnet(network, newbits, 0)
+		if err != nil {
+			return cty.UnknownVal(retType), err
+		}
+		retVals := make([]cty.Value, 0, 1<<newbits)
+		for i := 0; i < 1<<newbits; i++ {
+			if i > 0 {
+				current, _ = cidr.NextSubnet(current, length)
+			}
+			retVals = append(retVals, cty.StringVal(current.String()))
+		}
+
+		return cty.ListVal(retVals), nil
+	},
+})
+
+// cidrSplitMaxBits is the greatest number of bits by which cidrsplit can
+// extend a prefix, which limits its result to 65536 subnets.
+const cidrSplitMaxBits = 16
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
@@ -298,3 +403,15 @@ func CidrSubnets(prefix cty.Value, newbits ...cty.Value) (cty.Value, error) {
 func CidrContains(prefix, address cty.Value) (cty.Value, error) {
 	return CidrContainsFunc.Call([]cty.Value{prefix, address})
 }
+
+// CidrOverlaps checks whether two IP network address prefixes have any
+// addresses in common.
+func CidrOverlaps(prefixA, prefixB cty.Value) (cty.Value, error) {
+	return CidrOverlapsFunc.Call([]cty.Value{prefixA, prefixB})
+}
+
+// CidrSplit divides an IP network address prefix into all of the subnets
+// that extend it by the given number of bits.
+func CidrSplit(prefix, newbits cty.Value) (cty.Value, error) {
+	return CidrSplitFunc.Call([]cty.Value{prefix, newbits})
+}
@@ -450,6 +450,20 @@ func TestCidrContains(t *testing.T) {
 			cty.False,
 			noError,
 		},
+		{
+			// IPv6, contained (CIDR).
+			cty.StringVal("2001:db8::/32"),
+			cty.StringVal("2001:db8:ffff::/48"),
+			cty.True,
+			noError,
+		},
+		{
+			// IPv6, not contained (CIDR).
+			cty.StringVal("2001:db8::/32"),
+			cty.StringVal("2001:d00::/24"),
+			cty.False,
+			noError,
+		},
 		{
 			// Address family mismatch: IPv4 containing_prefix, IPv6 contained_ip_or_prefix (IP).
 			cty.StringVal("192.168.2.0/20"),
@@ -520,3 +534,189 @@ func TestCidrContains(t *testing.T) {
 		})
 	}
 }
+
+func TestCidrOverlaps(t *testing.T) {
+	tests := []struct {
+		PrefixA cty.Value
+		PrefixB cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.StringVal("10.0.0.0/16"),
+			cty.StringVal("10.0.128.0/24"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("10.0.128.0/24"),
+			cty.StringVal("10.0.0.0/16"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/24"),
+			cty.StringVal("10.0.1.0/24"),
+			cty.False,
+			``,
+		},
+		{
+			// The host bits of the prefixes are ignored.
+			cty.StringVal("10.0.0.5/24"),
+			cty.StringVal("10.0.0.200/30"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("2001:db8::/32"),
+			cty.StringVal("2001:db8:1234::/48"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("2001:db8:1::/48"),
+			cty.StringVal("2001:db8:2::/48"),
+			cty.False,
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("fe80::/10"),
+			cty.UnknownVal(cty.Bool),
+			`address family mismatch: 10.0.0.0/8 vs. fe80::/10`,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("10.0.0.1"),
+			cty.UnknownVal(cty.Bool),
+			`invalid CIDR expression: invalid CIDR address: 10.0.0.1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidroverlaps(%#v, %#v)", test.PrefixA, test.PrefixB), func(t *testing.T) {
+			got, err := CidrOverlaps(test.PrefixA, test.PrefixB)
+			wantErr := test.Err != ""
+
+			if wantErr {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCidrSplit(t *testing.T) {
+	tests := []struct {
+		Prefix  cty.Value
+		Newbits cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.StringVal("10.0.0.0/22"),
+			cty.NumberIntVal(2),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/24"),
+				cty.StringVal("10.0.1.0/24"),
+				cty.StringVal("10.0.2.0/24"),
+				cty.StringVal("10.0.3.0/24"),
+			}),
+			``,
+		},
+		{
+			// The host bits of the prefix are ignored.
+			cty.StringVal("10.0.0.1/31"),
+			cty.NumberIntVal(1),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("10.0.0.0/32"),
+				cty.StringVal("10.0.0.1/32"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("2001:db8::/32"),
+			cty.NumberIntVal(2),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("2001:db8::/34"),
+				cty.StringVal("2001:db8:4000::/34"),
+				cty.StringVal("2001:db8:8000::/34"),
+				cty.StringVal("2001:db8:c000::/34"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("fe80::/127"),
+			cty.NumberIntVal(1),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("fe80::/128"),
+				cty.StringVal("fe80::1/128"),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("10.0.0.0/30"),
+			cty.NumberIntVal(3),
+			cty.UnknownVal(cty.List(cty.String)),
+			`would extend prefix to 33 bits, which is too long for an IPv4 address`,
+		},
+		{
+			cty.StringVal("fe80::/120"),
+			cty.NumberIntVal(9),
+			cty.UnknownVal(cty.List(cty.String)),
+			`would extend prefix to 129 bits, which is too long for an IPv6 address`,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.NumberIntVal(0),
+			cty.UnknownVal(cty.List(cty.String)),
+			`must extend prefix by at least one bit`,
+		},
+		{
+			cty.StringVal("2001:db8::/32"),
+			cty.NumberIntVal(17),
+			cty.UnknownVal(cty.List(cty.String)),
+			`may not extend prefix by more than 16 bits`,
+		},
+		{
+			cty.StringVal("10.0.0.0"),
+			cty.NumberIntVal(1),
+			cty.UnknownVal(cty.List(cty.String)),
+			`invalid CIDR expression: invalid CIDR address: 10.0.0.0`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrsplit(%#v, %#v)", test.Prefix, test.Newbits), func(t *testing.T) {
+			got, err := CidrSplit(test.Prefix, test.Newbits)
+			wantErr := test.Err != ""
+
+			if wantErr {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err.Error(), test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -6,18 +6,25 @@
 package funcs
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/asn1"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
+	"math/big"
 	"strings"
+	"sync"
+	"time"
 
 	uuidv5 "github.com/google/uuid"
 	uuid "github.com/hashicorp/go-uuid"
@@ -41,6 +48,104 @@ var UUIDFunc = function.New(&function.Spec{
 	},
 })
 
+// UUIDV7Func constructs a function that generates a version 7 UUID, which
+// starts with the current time and so sorts in the order of generation.
+var UUIDV7Func = function.New(&function.Spec{
+	Params:       []function.Parameter{},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		// NewV7 also guarantees that the UUIDs generated by this process
+		// increase even if they have the same timestamp.
+		result, err := uuidv5.NewV7()
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		return cty.StringVal(result.String()), nil
+	},
+})
+
+// ULIDFunc constructs a function that generates a ULID, which is a 128-bit
+// identifier that starts with the current time and so sorts in the order of
+// generation, like a version 7 UUID, but is encoded as 26 characters of
+// Crockford's base32.
+var ULIDFunc = function.New(&function.Spec{
+	Params:       []function.Parameter{},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		result, err := newULID(time.Now())
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		return cty.StringVal(result), nil
+	},
+})
+
+// ulidAlphabet is Crockford's base32 alphabet, which ULIDs are encoded with.
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLast is the timestamp and the random part of the last ULID that
+// newULID generated, so that ULIDs generated in the same millisecond can
+// still be in order.
+var ulidLast struct {
+	mu      sync.Mutex
+	ms      uint64
+	entropy [10]byte
+}
+
+// newULID generates a ULID for the given time, as described in the
+// specification at https://github.com/ulid/spec, with 48 bits of Unix time
+// in milliseconds followed by 80 random bits.
+//
+// If the time is the same as or earlier than that of the previous ULID,
+// such as when the system clock goes backwards, then newULID uses the time
+// of the previous ULID and increments its random part instead, so that the
+// ULIDs generated by this process always increase.
+func newULID(now time.Time) (string, error) {
+	ulidLast.mu.Lock()
+	defer ulidLast.mu.Unlock()
+
+	ms := uint64(now.UnixMilli())
+	if ms >= 1<<48 {
+		return "", fmt.Errorf("time %s is too late for a ULID", now.UTC().Format(time.RFC3339))
+	}
+	if ms > ulidLast.ms {
+		if _, err := rand.Read(ulidLast.entropy[:]); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		ulidLast.ms = ms
+	} else {
+		i := len(ulidLast.entropy) - 1
+		for ; i >= 0; i-- {
+			ulidLast.entropy[i]++
+			if ulidLast.entropy[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return "", errors.New("too many ULIDs generated in the same millisecond")
+		}
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(ulidLast.ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ulidLast.ms))
+	copy(id[6:], ulidLast.entropy[:])
+
+	// The 128 bits are encoded as 26 characters of 5 bits each, most
+	// significant first, so the first character is at most 7.
+	n := new(big.Int).SetBytes(id[:])
+	mask := big.NewInt(31)
+	digit := new(big.Int)
+	var buf [26]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = ulidAlphabet[digit.And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(buf[:]), nil
+}
+
 var UUIDV5Func = function.New(&function.Spec{
 	Params: []function.Parameter{
 		{
@@ -134,6 +239,43 @@ var BcryptFunc = function.New(&function.Spec{
 	},
 })
 
+// HmacFunc constructs a function that computes the HMAC of a given message
+// with a given key, using a given hash algorithm, and encodes it with
+// hexadecimal digits.
+var HmacFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "algorithm",
+			Type: cty.String,
+		},
+		{
+			Name: "key",
+			Type: cty.String,
+		},
+		{
+			Name: "message",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		var hf func() hash.Hash
+		switch alg := args[0].AsString(); alg {
+		case "sha256":
+			hf = sha256.New
+		case "sha512":
+			hf = sha512.New
+		default:
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "unsupported algorithm %q; must be \"sha256\" or \"sha512\"", alg)
+		}
+
+		h := hmac.New(hf, []byte(args[1].AsString()))
+		h.Write([]byte(args[2].AsString()))
+		return cty.StringVal(hex.EncodeToString(h.Sum(nil))), nil
+	},
+})
+
 // Md5Func constructs a function that computes the MD5 hash of a given string and encodes it with hexadecimal digits.
 var Md5Func = makeStringHashFunction(md5.New, hex.EncodeToString)
 
@@ -282,6 +424,26 @@ func UUID() (cty.Value, error) {
 	return UUIDFunc.Call(nil)
 }
 
+// UUIDV7 generates and returns a Type-7 UUID in the standard hexadecimal
+// string format.
+//
+// This is not a pure function: it will generate a different result for each
+// call. It must therefore be registered as an impure function in the function
+// table in the "lang" package.
+func UUIDV7() (cty.Value, error) {
+	return UUIDV7Func.Call(nil)
+}
+
+// ULID generates and returns a ULID in its standard 26-character string
+// format.
+//
+// This is not a pure function: it will generate a different result for each
+// call. It must therefore be registered as an impure function in the function
+// table in the "lang" package.
+func ULID() (cty.Value, error) {
+	return ULIDFunc.Call(nil)
+}
+
 // UUIDV5 generates and returns a Type-5 UUID in the standard hexadecimal string
 // format.
 func UUIDV5(namespace cty.Value, name cty.Value) (cty.Value, error) {
@@ -318,6 +480,15 @@ func Bcrypt(str cty.Value, cost ...cty.Value) (cty.Value, error) {
 	return BcryptFunc.Call(args)
 }
 
+// Hmac computes the HMAC of a given message with a given key, using the hash
+// algorithm named "sha256" or "sha512", and encodes it with hexadecimal digits.
+//
+// The key and the message are both encoded as UTF-8 before the HMAC is
+// computed as defined in RFC 2104.
+func Hmac(algorithm, key, message cty.Value) (cty.Value, error) {
+	return HmacFunc.Call([]cty.Value{algorithm, key, message})
+}
+
 // Md5 computes the MD5 hash of a given string and encodes it with hexadecimal digits.
 func Md5(str cty.Value) (cty.Value, error) {
 	return Md5Func.Call([]cty.Value{str})
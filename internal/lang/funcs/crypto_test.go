@@ -7,10 +7,14 @@ package funcs
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
 )
 
 func TestUUID(t *testing.T) {
@@ -25,6 +29,73 @@ func TestUUID(t *testing.T) {
 	}
 }
 
+func TestUUIDV7(t *testing.T) {
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	var prev string
+	for i := 0; i < 100; i++ {
+		result, err := UUIDV7()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := result.AsString()
+		if !uuidRe.MatchString(got) {
+			t.Fatalf("%q is not a version 7 UUID", got)
+		}
+		if got <= prev {
+			t.Fatalf("%q was generated after %q, but doesn't sort after it", got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestULID(t *testing.T) {
+	ulidRe := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	var prev string
+	for i := 0; i < 100; i++ {
+		result, err := ULID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := result.AsString()
+		if !ulidRe.MatchString(got) {
+			t.Fatalf("%q is not a ULID", got)
+		}
+		if got <= prev {
+			t.Fatalf("%q was generated after %q, but doesn't sort after it", got, prev)
+		}
+		prev = got
+	}
+
+	t.Run("timestamp", func(t *testing.T) {
+		ulidLast.mu.Lock()
+		ulidLast.ms = 0
+		ulidLast.mu.Unlock()
+
+		// This is the example from the specification.
+		now := time.UnixMilli(1469918176385)
+		first, err := newULID(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := first[:10], "01ARYZ6S41"; got != want {
+			t.Errorf("wrong timestamp %q; want %q", got, want)
+		}
+
+		// A ULID for the same time, or for an earlier time if the clock
+		// goes backwards, keeps the timestamp and still sorts later.
+		for _, at := range []time.Time{now, now.Add(-time.Second)} {
+			next, err := newULID(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next[:10] != first[:10] || next <= first {
+				t.Errorf("%q was generated after %q, but doesn't sort after it with the same timestamp", next, first)
+			}
+			first = next
+		}
+	})
+}
+
 func TestUUIDV5(t *testing.T) {
 	tests := []struct {
 		Namespace cty.Value
@@ -282,6 +353,74 @@ func TestBcrypt(t *testing.T) {
 	}
 }
 
+func TestHmac(t *testing.T) {
+	tests := []struct {
+		Algorithm cty.Value
+		Key       cty.Value
+		Message   cty.Value
+		Want      cty.Value
+		Err       string
+	}{
+		{ // RFC 4231 test case 2
+			cty.StringVal("sha256"),
+			cty.StringVal("Jefe"),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"),
+			``,
+		},
+		{
+			cty.StringVal("sha512"),
+			cty.StringVal("Jefe"),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737"),
+			``,
+		},
+		{ // We accept empty strings too
+			cty.StringVal("sha256"),
+			cty.StringVal(""),
+			cty.StringVal(""),
+			cty.StringVal("b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad"),
+			``,
+		},
+		{ // A sensitive key makes the result sensitive
+			cty.StringVal("sha256"),
+			cty.StringVal("Jefe").Mark(marks.Sensitive),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.StringVal("5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.StringVal("md5"),
+			cty.StringVal("Jefe"),
+			cty.StringVal("what do ya want for nothing?"),
+			cty.NilVal,
+			`unsupported algorithm "md5"; must be "sha256" or "sha512"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("hmac(%#v, %#v, %#v)", test.Algorithm, test.Key, test.Message), func(t *testing.T) {
+			got, err := Hmac(test.Algorithm, test.Key, test.Message)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestMd5(t *testing.T) {
 	tests := []struct {
 		String cty.Value
@@ -0,0 +1,229 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// listMergeStrategy decides how the deepmerge functions merge two lists,
+// tuples or sets found at the same place in their arguments.
+type listMergeStrategy int
+
+const (
+	// listMergeReplace replaces the earlier list with the later one, like
+	// any other value.
+	listMergeReplace listMergeStrategy = iota
+
+	// listMergeConcat appends the elements of the later list to the earlier
+	// one.
+	listMergeConcat
+
+	// listMergeDistinct appends the elements of the later list that are not
+	// already in the earlier one.
+	listMergeDistinct
+)
+
+// DeepMergeFunc constructs a function that merges maps and objects
+// recursively. Lists are replaced by the ones in later arguments.
+var DeepMergeFunc = makeDeepMergeFunc(listMergeReplace)
+
+// DeepMergeConcatFunc constructs a function that merges maps and objects
+// recursively, and concatenates the lists found at the same place.
+var DeepMergeConcatFunc = makeDeepMergeFunc(listMergeConcat)
+
+// DeepMergeDistinctFunc constructs a function that merges maps and objects
+// recursively, and concatenates the lists found at the same place without
+// repeating the elements that are already in the earlier list.
+var DeepMergeDistinctFunc = makeDeepMergeFunc(listMergeDistinct)
+
+func makeDeepMergeFunc(strategy listMergeStrategy) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{},
+		VarParam: &function.Parameter{
+			Name:             "maps",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+			AllowNull:        true,
+			AllowMarked:      true,
+		},
+		Type: func(args []cty.Value) (cty.Type, error) {
+			for i, arg := range args {
+				ty := arg.Type()
+				if ty != cty.DynamicPseudoType && !isMapLike(ty) {
+					return cty.NilType, function.NewArgErrorf(i, "must be a map or an object, not %s", ty.FriendlyName())
+				}
+			}
+			return cty.DynamicPseudoType, nil
+		},
+		RefineResult: refineNotNull,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			result := cty.NilVal
+			var sameType cty.Type
+			for _, arg := range args {
+				if arg.IsNull() {
+					continue
+				}
+
+				if result == cty.NilVal {
+					result = arg
+					sameType = arg.Type()
+					continue
+				}
+				if !sameType.Equals(arg.Type()) {
+					sameType = cty.DynamicPseudoType
+				}
+				var err error
+				result, err = deepMerge(result, arg, strategy)
+				if err != nil {
+					return cty.NilVal, err
+				}
+			}
+			if result == cty.NilVal {
+				return cty.EmptyObjectVal, nil
+			}
+
+			// If all of the arguments are maps of the same type, the result
+			// is too. Otherwise it is an object, since its attributes can have
+			// different types.
+			if sameType.IsMapType() && result.IsKnown() {
+				if converted, err := convert.Convert(result, sameType); err == nil {
+					return converted, nil
+				}
+			}
+			return result, nil
+		},
+	})
+}
+
+// deepMerge merges b into a, which are the values at the same place in two
+// arguments of a deepmerge function.
+func deepMerge(a, b cty.Value, strategy listMergeStrategy) (cty.Value, error) {
+	aTy, bTy := a.Type(), b.Type()
+	mergeable := func(ty cty.Type) bool {
+		return isMapLike(ty) || (strategy != listMergeReplace && isListLike(ty))
+	}
+
+	switch {
+	case isMapLike(aTy) && isMapLike(bTy):
+		return deepMergeMaps(a, b, strategy)
+	case strategy != listMergeReplace && isListLike(aTy) && isListLike(bTy):
+		return deepMergeLists(a, b, strategy)
+	case (aTy == cty.DynamicPseudoType && mergeable(bTy)) || (bTy == cty.DynamicPseudoType && mergeable(aTy)):
+		// We can't know whether the values will be merged until we know
+		// their types.
+		return cty.DynamicVal.WithMarks(a.Marks(), b.Marks()), nil
+	default:
+		return b, nil
+	}
+}
+
+func deepMergeMaps(a, b cty.Value, strategy listMergeStrategy) (cty.Value, error) {
+	av, aMarks := a.Unmark()
+	bv, bMarks := b.Unmark()
+	if av.IsNull() || bv.IsNull() {
+		return b, nil
+	}
+	if !av.IsKnown() || !bv.IsKnown() {
+		// The keys of an unknown map aren't known, so we can't know which
+		// of them will be merged.
+		return cty.DynamicVal.WithMarks(aMarks, bMarks), nil
+	}
+
+	attrs := make(map[string]cty.Value)
+	for it := av.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		attrs[k.AsString()] = v
+	}
+	for it := bv.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		key := k.AsString()
+		existing, ok := attrs[key]
+		if !ok {
+			attrs[key] = v
+			continue
+		}
+		merged, err := deepMerge(existing, v, strategy)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		attrs[key] = merged
+	}
+	return cty.ObjectVal(attrs).WithMarks(aMarks, bMarks), nil
+}
+
+func deepMergeLists(a, b cty.Value, strategy listMergeStrategy) (cty.Value, error) {
+	av, aMarks := a.Unmark()
+	bv, bMarks := b.Unmark()
+	if av.IsNull() || bv.IsNull() {
+		return b, nil
+	}
+	if !av.IsKnown() || !bv.IsKnown() {
+		return cty.DynamicVal.WithMarks(aMarks, bMarks), nil
+	}
+
+	elems := make([]cty.Value, 0, av.LengthInt()+bv.LengthInt())
+	for it := av.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		elems = append(elems, v)
+	}
+	for it := bv.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if strategy == listMergeDistinct {
+			if !v.IsWhollyKnown() {
+				// We can't know whether the element is already in the list.
+				return cty.DynamicVal.WithMarks(aMarks, bMarks), nil
+			}
+			if containsValue(elems, v) {
+				continue
+			}
+		}
+		elems = append(elems, v)
+	}
+	return cty.TupleVal(elems).WithMarks(aMarks, bMarks), nil
+}
+
+// containsValue returns true if the given known value is equal to any of the
+// given values, ignoring their marks.
+func containsValue(vals []cty.Value, v cty.Value) bool {
+	v, _ = v.UnmarkDeep()
+	for _, existing := range vals {
+		existing, _ = existing.UnmarkDeep()
+		if existing.RawEquals(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMapLike(ty cty.Type) bool {
+	return ty.IsMapType() || ty.IsObjectType()
+}
+
+func isListLike(ty cty.Type) bool {
+	return ty.IsListType() || ty.IsTupleType() || ty.IsSetType()
+}
+
+// DeepMerge merges the given maps or objects recursively, replacing lists with
+// the ones in later arguments.
+func DeepMerge(maps ...cty.Value) (cty.Value, error) {
+	return DeepMergeFunc.Call(maps)
+}
+
+// DeepMergeConcat merges the given maps or objects recursively, concatenating
+// the lists found at the same place.
+func DeepMergeConcat(maps ...cty.Value) (cty.Value, error) {
+	return DeepMergeConcatFunc.Call(maps)
+}
+
+// DeepMergeDistinct merges the given maps or objects recursively,
+// concatenating the lists found at the same place without repeating elements.
+func DeepMergeDistinct(maps ...cty.Value) (cty.Value, error) {
+	return DeepMergeDistinctFunc.Call(maps)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		Fn   func(...cty.Value) (cty.Value, error)
+		Maps []cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			DeepMerge,
+			nil,
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			DeepMerge,
+			[]cty.Value{
+				cty.NullVal(cty.Map(cty.String)),
+				cty.NullVal(cty.DynamicPseudoType),
+			},
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			// Nested objects are merged, and other values are replaced by the
+			// ones in later arguments.
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("a"),
+					"tags": cty.ObjectVal(map[string]cty.Value{
+						"env":  cty.StringVal("dev"),
+						"team": cty.StringVal("core"),
+					}),
+					"ports": cty.TupleVal([]cty.Value{cty.NumberIntVal(80)}),
+				}),
+				cty.NullVal(cty.EmptyObject),
+				cty.ObjectVal(map[string]cty.Value{
+					"tags": cty.ObjectVal(map[string]cty.Value{
+						"env": cty.StringVal("prod"),
+					}),
+					"ports": cty.TupleVal([]cty.Value{cty.NumberIntVal(443)}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("a"),
+				"tags": cty.ObjectVal(map[string]cty.Value{
+					"env":  cty.StringVal("prod"),
+					"team": cty.StringVal("core"),
+				}),
+				"ports": cty.TupleVal([]cty.Value{cty.NumberIntVal(443)}),
+			}),
+			``,
+		},
+		{
+			// A map or object replaces a value of any other type.
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a"),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.StringVal("b"),
+					}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.StringVal("b"),
+				}),
+			}),
+			``,
+		},
+		{
+			// Maps of the same type produce a map of that type.
+			DeepMerge,
+			[]cty.Value{
+				cty.MapVal(map[string]cty.Value{
+					"a": cty.MapVal(map[string]cty.Value{"x": cty.StringVal("1")}),
+				}),
+				cty.MapVal(map[string]cty.Value{
+					"a": cty.MapVal(map[string]cty.Value{"y": cty.StringVal("2")}),
+					"b": cty.MapVal(map[string]cty.Value{"z": cty.StringVal("3")}),
+				}),
+			},
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.MapVal(map[string]cty.Value{
+					"x": cty.StringVal("1"),
+					"y": cty.StringVal("2"),
+				}),
+				"b": cty.MapVal(map[string]cty.Value{"z": cty.StringVal("3")}),
+			}),
+			``,
+		},
+		{
+			DeepMergeConcat,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.TupleVal([]cty.Value{cty.StringVal("y"), cty.NumberIntVal(1)}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.TupleVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y"), cty.StringVal("y"), cty.NumberIntVal(1)}),
+			}),
+			``,
+		},
+		{
+			DeepMergeDistinct,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.SetVal([]cty.Value{cty.StringVal("y"), cty.StringVal("z")}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.TupleVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y"), cty.StringVal("z")}),
+			}),
+			``,
+		},
+		{
+			// Marks are kept on the values they were on.
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.StringVal("secret").Mark(marks.Sensitive),
+					}),
+				}).Mark("outer"),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"c": cty.StringVal("c"),
+					}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.StringVal("secret").Mark(marks.Sensitive),
+					"c": cty.StringVal("c"),
+				}),
+			}).Mark("outer"),
+			``,
+		},
+		{
+			// Unknown values that are replaced don't make the result unknown.
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.UnknownVal(cty.String),
+					"b": cty.UnknownVal(cty.String),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a"),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.UnknownVal(cty.String),
+			}),
+			``,
+		},
+		{
+			// An unknown map makes the merged value unknown, since its keys
+			// aren't known.
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.UnknownVal(cty.Map(cty.String)),
+					"b": cty.StringVal("b"),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.MapVal(map[string]cty.Value{"x": cty.StringVal("x")}),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.DynamicVal,
+				"b": cty.StringVal("b"),
+			}),
+			``,
+		},
+		{
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a"),
+				}),
+				cty.DynamicVal,
+			},
+			cty.DynamicVal,
+			``,
+		},
+		{
+			DeepMergeConcat,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.UnknownVal(cty.List(cty.String)),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.DynamicVal,
+			}),
+			``,
+		},
+		{
+			DeepMerge,
+			[]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a"),
+				}),
+				cty.ListVal([]cty.Value{cty.StringVal("b")}),
+			},
+			cty.NilVal,
+			`must be a map or an object, not list of string`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d-%#v", i, test.Maps), func(t *testing.T) {
+			got, err := test.Fn(test.Maps...)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -117,6 +117,20 @@ var DescriptionList = map[string]descriptionEntry{
 			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
 		},
 	},
+	"cidroverlaps": {
+		Description: "`cidroverlaps` determines whether two IP network address prefixes given in CIDR notation have any addresses in common.",
+		ParamDescription: []string{
+			"`prefix_a` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`prefix_b` must be given in CIDR notation, and must belong to the same address family as `prefix_a`.",
+		},
+	},
+	"cidrsplit": {
+		Description: "`cidrsplit` divides an IP network address prefix into all of the subnets that extend it by a given number of bits.",
+		ParamDescription: []string{
+			"`prefix` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`newbits` is the number of additional bits with which to extend the prefix, from 1 to 16.",
+		},
+	},
 	"cidrsubnet": {
 		Description: "`cidrsubnet` calculates a subnet address within given IP network address prefix.",
 		ParamDescription: []string{
@@ -157,6 +171,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`csvdecode` decodes a string containing CSV-formatted data and produces a list of maps representing that data.",
 		ParamDescription: []string{""},
 	},
+	"deepmerge": {
+		Description:      "`deepmerge` takes an arbitrary number of maps or objects and returns a single object that contains a recursively merged set of elements from all arguments. Lists are replaced by the ones in later arguments.",
+		ParamDescription: []string{""},
+	},
+	"deepmergeconcat": {
+		Description:      "`deepmergeconcat` is like `deepmerge`, but concatenates the lists found at the same place in its arguments.",
+		ParamDescription: []string{""},
+	},
+	"deepmergedistinct": {
+		Description:      "`deepmergedistinct` is like `deepmergeconcat`, but leaves out the elements that are already in the earlier list.",
+		ParamDescription: []string{""},
+	},
 	"dirname": {
 		Description:      "`dirname` takes a string containing a filesystem path and removes the last portion from it.",
 		ParamDescription: []string{""},
@@ -233,6 +259,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`formatlist` produces a list of strings by formatting a number of other values according to a specification string.",
 		ParamDescription: []string{"", ""},
 	},
+	"hmac": {
+		Description: "`hmac` computes the HMAC of a given message with a given key, using the given hash algorithm, and encodes it with hexadecimal digits.",
+		ParamDescription: []string{
+			"The hash algorithm to use, either `\"sha256\"` or `\"sha512\"`.",
+			"",
+			"",
+		},
+	},
 	"indent": {
 		Description: "`indent` adds a given number of spaces to the beginnings of all but the first line in a given multi-line string.",
 		ParamDescription: []string{
@@ -263,6 +297,17 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`jsonencode` encodes a given value to a string using JSON syntax.",
 		ParamDescription: []string{""},
 	},
+	"jsonschemavalidate": {
+		Description: "`jsonschemavalidate` validates a value against a JSON Schema and returns a list of the validation errors, which is empty if the value is valid.",
+		ParamDescription: []string{
+			"A string containing a JSON Schema document, or an object that represents one.",
+			"",
+		},
+	},
+	"jwtdecode": {
+		Description:      "`jwtdecode` decodes the header and the claims of a JSON Web Token, without verifying its signature.",
+		ParamDescription: []string{""},
+	},
 	"keys": {
 		Description: "`keys` takes a map and returns a list containing the keys from that map.",
 		ParamDescription: []string{
@@ -345,6 +390,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`regexall` applies a [regular expression](https://en.wikipedia.org/wiki/Regular_expression) to a string and returns a list of all matches.",
 		ParamDescription: []string{"", ""},
 	},
+	"regexreplace": {
+		Description:      "`regexreplace` replaces each match of a [regular expression](https://en.wikipedia.org/wiki/Regular_expression) in a string with a replacement string, which can refer to the capture groups of the match.",
+		ParamDescription: []string{"", "", ""},
+	},
 	"replace": {
 		Description:      "`replace` searches a given string for another given substring, and replaces each occurrence with a given replacement string.",
 		ParamDescription: []string{"", "", ""},
@@ -357,6 +406,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`rsadecrypt` decrypts an RSA-encrypted ciphertext, returning the corresponding cleartext.",
 		ParamDescription: []string{"", ""},
 	},
+	"semvercompare": {
+		Description:      "`semvercompare` compares two version numbers, returning -1 if the first is lower than the second, 0 if they are equal, or 1 if the first is higher than the second.",
+		ParamDescription: []string{"", ""},
+	},
+	"semverconstraint": {
+		Description:      "`semverconstraint` returns true if a version number meets a version constraint string, using the same constraint syntax as the `version` arguments in OpenTofu configuration.",
+		ParamDescription: []string{"", ""},
+	},
 	"sensitive": {
 		Description:      "`sensitive` takes any value and returns a copy of it marked so that OpenTofu will treat it as sensitive, with the same meaning and behavior as for [sensitive input variables](/language/values/variables#suppressing-values-in-cli-output).",
 		ParamDescription: []string{""},
@@ -475,6 +532,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`tomap` converts its argument to a map value.",
 		ParamDescription: []string{""},
 	},
+	"tomldecode": {
+		Description:      "`tomldecode` parses a string as a TOML document, and produces a representation of its value.",
+		ParamDescription: []string{""},
+	},
+	"tomlencode": {
+		Description:      "`tomlencode` encodes a given object or map to a string using [TOML](https://toml.io/en/v1.0.0) syntax.",
+		ParamDescription: []string{""},
+	},
 	"tonumber": {
 		Description:      "`tonumber` converts its argument to a number value.",
 		ParamDescription: []string{""},
@@ -518,10 +583,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`type` returns the type of a given value.",
 		ParamDescription: []string{""},
 	},
+	"ulid": {
+		Description:      "`ulid` generates a [ULID](https://github.com/ulid/spec), a unique identifier string that starts with the current time, so that identifiers generated later sort after those generated earlier.",
+		ParamDescription: []string{},
+	},
 	"upper": {
 		Description:      "`upper` converts all cased letters in the given string to uppercase.",
 		ParamDescription: []string{""},
 	},
+	"urlbuild": {
+		Description:      "`urlbuild` assembles a URL from an object of its components, such as one returned by `urlparse`.",
+		ParamDescription: []string{""},
+	},
 	"urlencode": {
 		Description:      "`urlencode` applies URL encoding to a given string.",
 		ParamDescription: []string{""},
@@ -530,6 +603,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`urldecode` applies URL decoding to a given encoded string.",
 		ParamDescription: []string{""},
 	},
+	"urlparse": {
+		Description:      "`urlparse` parses a URL into an object of its components: the scheme, username, password, host, port, path, query parameters, and fragment.",
+		ParamDescription: []string{""},
+	},
 	"uuid": {
 		Description:      "`uuid` generates a unique identifier string.",
 		ParamDescription: []string{},
@@ -538,6 +615,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`uuidv5` generates a _name-based_ UUID, as described in [RFC 4122 section 4.3](https://tools.ietf.org/html/rfc4122#section-4.3), also known as a \"version 5\" UUID.",
 		ParamDescription: []string{"", ""},
 	},
+	"uuidv7": {
+		Description:      "`uuidv7` generates a _time-ordered_ UUID, as described in [RFC 9562 section 5.7](https://www.rfc-editor.org/rfc/rfc9562#section-5.7), also known as a \"version 7\" UUID.",
+		ParamDescription: []string{},
+	},
 	"values": {
 		Description:      "`values` takes a map and returns a list containing the values of the elements in that map.",
 		ParamDescription: []string{""},
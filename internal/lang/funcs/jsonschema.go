@@ -0,0 +1,85 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/jsonschema"
+)
+
+// JSONSchemaValidateFunc constructs a function that validates a value against
+// a JSON Schema and returns the validation errors, which are none if the value
+// is valid.
+var JSONSchemaValidateFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "schema",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+			AllowMarked:      true,
+		},
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+			AllowNull:        true,
+			AllowMarked:      true,
+		},
+	},
+	Type:         function.StaticReturnType(cty.List(cty.String)),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		schemaVal, schemaMarks := args[0].UnmarkDeep()
+		val, valMarks := args[1].UnmarkDeep()
+		if !schemaVal.IsWhollyKnown() || !val.IsWhollyKnown() {
+			return cty.UnknownVal(retType).WithMarks(schemaMarks, valMarks), nil
+		}
+
+		// The schema is usually a JSON document read from a file, but it can
+		// also be written as an object in the configuration.
+		var schemaSrc []byte
+		if schemaVal.Type() == cty.String {
+			schemaSrc = []byte(schemaVal.AsString())
+		} else {
+			var err error
+			schemaSrc, err = ctyjson.Marshal(schemaVal, schemaVal.Type())
+			if err != nil {
+				return cty.NilVal, function.NewArgError(0, err)
+			}
+		}
+		schema, err := jsonschema.Parse(schemaSrc)
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+
+		src, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(1, err)
+		}
+		errs, err := schema.ValidateJSON(src)
+		if err != nil {
+			return cty.NilVal, function.NewArgError(1, err)
+		}
+
+		if len(errs) == 0 {
+			return cty.ListValEmpty(cty.String).WithMarks(schemaMarks, valMarks), nil
+		}
+		msgs := make([]cty.Value, len(errs))
+		for i, err := range errs {
+			msgs[i] = cty.StringVal(err.Error())
+		}
+		return cty.ListVal(msgs).WithMarks(schemaMarks, valMarks), nil
+	},
+})
+
+// JSONSchemaValidate validates the given value against the given JSON Schema,
+// returning the validation errors as a list of strings.
+func JSONSchemaValidate(schema, val cty.Value) (cty.Value, error) {
+	return JSONSchemaValidateFunc.Call([]cty.Value{schema, val})
+}
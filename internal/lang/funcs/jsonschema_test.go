@@ -0,0 +1,123 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema := cty.StringVal(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"ports": {"type": "array", "items": {"type": "integer", "maximum": 65535}}
+		},
+		"additionalProperties": false
+	}`)
+
+	tests := []struct {
+		Schema cty.Value
+		Value  cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			schema,
+			cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("web"),
+				"ports": cty.TupleVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+			}),
+			cty.ListValEmpty(cty.String),
+			``,
+		},
+		{
+			schema,
+			cty.ObjectVal(map[string]cty.Value{
+				"ports": cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberFloatVal(1.5), cty.NumberIntVal(70000)}),
+				"other": cty.True,
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal(`missing required property "name"`),
+				cty.StringVal(`property "other" is not allowed`),
+				cty.StringVal(`/ports/1: must be integer, not number`),
+				cty.StringVal(`/ports/2: must be less than or equal to 65535`),
+			}),
+			``,
+		},
+		{
+			// The schema can also be an object.
+			cty.ObjectVal(map[string]cty.Value{
+				"enum": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+			cty.StringVal("c"),
+			cty.ListVal([]cty.Value{cty.StringVal(`must be one of "a", "b"`)}),
+			``,
+		},
+		{
+			cty.StringVal(`{"type": "null"}`),
+			cty.NullVal(cty.String),
+			cty.ListValEmpty(cty.String),
+			``,
+		},
+		{
+			schema,
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("secret").Mark(marks.Sensitive),
+			}),
+			cty.ListValEmpty(cty.String).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			schema,
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.UnknownVal(cty.String),
+			}),
+			cty.UnknownVal(cty.List(cty.String)).RefineNotNull(),
+			``,
+		},
+		{
+			cty.StringVal(`{"type": "text"}`),
+			cty.StringVal("a"),
+			cty.NilVal,
+			`invalid schema at /type: unsupported type "text"`,
+		},
+		{
+			cty.StringVal(`{`),
+			cty.StringVal("a"),
+			cty.NilVal,
+			`invalid JSON: unexpected EOF`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d-%#v", i, test.Value), func(t *testing.T) {
+			got, err := JSONSchemaValidate(test.Schema, test.Value)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
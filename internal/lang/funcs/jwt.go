@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// JWTDecodeFunc constructs a function that decodes the header and the claims
+// of a JSON Web Token in the compact serialization.
+//
+// The signature is not verified, so the result must not be trusted any more
+// than the source of the token is.
+var JWTDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "token",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		val, err := jwtDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return val.Type(), nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val, err := jwtDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		return val, nil
+	},
+})
+
+// jwtDecode decodes the given token into an object with the attributes
+// "header" and "claims".
+func jwtDecode(token string) (cty.Value, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	switch len(parts) {
+	case 3:
+		// A JWS, which is what we expect.
+	case 5:
+		return cty.NilVal, fmt.Errorf("the token is encrypted, so its claims can't be decoded")
+	default:
+		return cty.NilVal, fmt.Errorf("invalid JWT: must have three parts separated by periods, but has %d", len(parts))
+	}
+
+	header, err := jwtDecodePart(parts[0], "header")
+	if err != nil {
+		return cty.NilVal, err
+	}
+	claims, err := jwtDecodePart(parts[1], "claims")
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"header": header,
+		"claims": claims,
+	}), nil
+}
+
+// jwtDecodePart decodes one of the base64url-encoded JSON objects from a
+// token. The name is used in error messages.
+func jwtDecodePart(part string, name string) (cty.Value, error) {
+	// Tokens shouldn't be padded, but we'll tolerate it.
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("invalid JWT: the %s is not valid base64url: %w", name, err)
+	}
+	ty, err := ctyjson.ImpliedType(buf)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("invalid JWT: the %s is not valid JSON: %w", name, err)
+	}
+	if !ty.IsObjectType() {
+		return cty.NilVal, fmt.Errorf("invalid JWT: the %s must be a JSON object, not %s", name, ty.FriendlyName())
+	}
+	return ctyjson.Unmarshal(buf, ty)
+}
+
+// JWTDecode decodes the header and the claims of the given JSON Web Token,
+// without verifying its signature.
+func JWTDecode(token cty.Value) (cty.Value, error) {
+	return JWTDecodeFunc.Call([]cty.Value{token})
+}
@@ -0,0 +1,268 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	resyntax "regexp/syntax"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// RegexFunc constructs a function that applies a regular expression pattern
+// to a string and returns information about the first match, or raises an
+// error if there is no match.
+//
+// This is like stdlib.RegexFunc except that a pattern can mix named and
+// unnamed capture groups, in which case the result is an object with just
+// the named groups, and so unnamed groups can be used only for grouping.
+var RegexFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "pattern",
+			Type: cty.String,
+		},
+		{
+			Name: "string",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			// We can't predict our type without seeing our pattern.
+			return cty.DynamicPseudoType, nil
+		}
+
+		retTy, err := regexPatternResultType(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return retTy, nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		if retType == cty.DynamicPseudoType {
+			return cty.DynamicVal, nil
+		}
+
+		re, err := regexp.Compile(args[0].AsString())
+		if err != nil {
+			// Should never happen, since we checked this in the Type function above.
+			return cty.NilVal, function.NewArgErrorf(0, "error parsing pattern: %s", err)
+		}
+		str := args[1].AsString()
+
+		captureIdxs := re.FindStringSubmatchIndex(str)
+		if captureIdxs == nil {
+			return cty.NilVal, errors.New("pattern did not match any part of the given string")
+		}
+
+		return regexPatternResult(re, str, captureIdxs, retType), nil
+	},
+})
+
+// RegexAllFunc constructs a function that is like RegexFunc except that it
+// returns a list of all of the non-overlapping matches, which is empty if
+// there are no matches.
+var RegexAllFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "pattern",
+			Type: cty.String,
+		},
+		{
+			Name: "string",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			// We can't predict our type without seeing our pattern, but we
+			// do know it'll always be a list of something.
+			return cty.List(cty.DynamicPseudoType), nil
+		}
+
+		retTy, err := regexPatternResultType(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return cty.List(retTy), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		ety := retType.ElementType()
+		if ety == cty.DynamicPseudoType {
+			return cty.DynamicVal, nil
+		}
+
+		re, err := regexp.Compile(args[0].AsString())
+		if err != nil {
+			// Should never happen, since we checked this in the Type function above.
+			return cty.NilVal, function.NewArgErrorf(0, "error parsing pattern: %s", err)
+		}
+		str := args[1].AsString()
+
+		captureIdxsEach := re.FindAllStringSubmatchIndex(str, -1)
+		if len(captureIdxsEach) == 0 {
+			return cty.ListValEmpty(ety), nil
+		}
+
+		elems := make([]cty.Value, len(captureIdxsEach))
+		for i, captureIdxs := range captureIdxsEach {
+			elems[i] = regexPatternResult(re, str, captureIdxs, ety)
+		}
+		return cty.ListVal(elems), nil
+	},
+})
+
+// RegexReplaceFunc constructs a function that replaces each match of a
+// regular expression pattern in a string with a replacement string.
+//
+// The replacement can refer to the capture groups of the pattern, using
+// $1 or ${1} for unnamed groups and ${name} for named groups, as described
+// for regexp.Regexp.Expand. $$ inserts a literal $.
+var RegexReplaceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "string",
+			Type: cty.String,
+		},
+		{
+			Name: "pattern",
+			Type: cty.String,
+		},
+		{
+			Name: "replacement",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str := args[0].AsString()
+		re, err := compileRegexPattern(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgError(1, err)
+		}
+		replacement := args[2].AsString()
+
+		return cty.StringVal(re.ReplaceAllString(str, replacement)), nil
+	},
+})
+
+// compileRegexPattern compiles the given pattern, returning an error message
+// that is suitable to show to users if it isn't valid.
+func compileRegexPattern(pattern string) (*regexp.Regexp, error) {
+	re, rawErr := regexp.Compile(pattern)
+	var syntaxErr *resyntax.Error
+	switch {
+	case errors.As(rawErr, &syntaxErr):
+		return nil, fmt.Errorf("invalid regexp pattern: %s in %s", syntaxErr.Code, syntaxErr.Expr)
+	case rawErr != nil:
+		// Should never happen, since all regexp compile errors should
+		// be resyntax.Error, but just in case...
+		return nil, fmt.Errorf("error parsing pattern: %w", rawErr)
+	}
+	return re, nil
+}
+
+// regexPatternResultType parses the given regular expression pattern and
+// returns the type that would be returned to represent its capture groups:
+//
+//   - If there are no capture groups, a string for the whole match.
+//   - If there are only unnamed capture groups, a tuple of strings for the
+//     groups in order.
+//   - If there are any named capture groups, an object with a string
+//     attribute for each named group. Unnamed groups are not included.
+func regexPatternResultType(pattern string) (cty.Type, error) {
+	re, err := compileRegexPattern(pattern)
+	if err != nil {
+		return cty.NilType, err
+	}
+
+	groups := re.SubexpNames()[1:] // index 0 is the whole pattern
+	atys := make(map[string]cty.Type)
+	for _, name := range groups {
+		if name != "" {
+			atys[name] = cty.String
+		}
+	}
+	switch {
+	case len(groups) == 0:
+		return cty.String, nil
+	case len(atys) == 0:
+		etys := make([]cty.Type, len(groups))
+		for i := range etys {
+			etys[i] = cty.String
+		}
+		return cty.Tuple(etys), nil
+	default:
+		return cty.Object(atys), nil
+	}
+}
+
+// regexPatternResult builds the result of the given type, as returned by
+// regexPatternResultType, for a single match.
+//
+// The result for a capture group that didn't participate in the match, such
+// as one inside an optional group, is null.
+func regexPatternResult(re *regexp.Regexp, str string, captureIdxs []int, retType cty.Type) cty.Value {
+	submatch := func(i int) cty.Value {
+		start, end := captureIdxs[i*2], captureIdxs[i*2+1]
+		if start < 0 || end < 0 {
+			return cty.NullVal(cty.String)
+		}
+		return cty.StringVal(str[start:end])
+	}
+
+	switch {
+	case retType == cty.String:
+		return submatch(0)
+	case retType.IsTupleType():
+		vals := make([]cty.Value, re.NumSubexp())
+		for i := range vals {
+			vals[i] = submatch(i + 1)
+		}
+		return cty.TupleVal(vals)
+	case retType.IsObjectType():
+		vals := make(map[string]cty.Value, len(retType.AttributeTypes()))
+		for i, name := range re.SubexpNames() {
+			if i == 0 || name == "" {
+				continue
+			}
+			// A name can be used for more than one group, such as in
+			// alternatives, in which case the group that matched wins.
+			if v := submatch(i); !v.IsNull() || vals[name] == cty.NilVal {
+				vals[name] = v
+			}
+		}
+		return cty.ObjectVal(vals)
+	default:
+		// Should never happen
+		panic(fmt.Sprintf("invalid return type %#v", retType))
+	}
+}
+
+// Regex applies the given regular expression pattern to the given string and
+// returns information about the first match.
+func Regex(pattern, str cty.Value) (cty.Value, error) {
+	return RegexFunc.Call([]cty.Value{pattern, str})
+}
+
+// RegexAll applies the given regular expression pattern to the given string
+// and returns a list of information about all of the matches.
+func RegexAll(pattern, str cty.Value) (cty.Value, error) {
+	return RegexAllFunc.Call([]cty.Value{pattern, str})
+}
+
+// RegexReplace replaces each match of the given regular expression pattern
+// in the given string with the given replacement.
+func RegexReplace(str, pattern, replacement cty.Value) (cty.Value, error) {
+	return RegexReplaceFunc.Call([]cty.Value{str, pattern, replacement})
+}
@@ -0,0 +1,234 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		Pattern cty.Value
+		String  cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.StringVal("[a-z]+"),
+			cty.StringVal("123abc456"),
+			cty.StringVal("abc"),
+			``,
+		},
+		{
+			cty.StringVal(`(\d+)-(\d+)`),
+			cty.StringVal("10-20"),
+			cty.TupleVal([]cty.Value{cty.StringVal("10"), cty.StringVal("20")}),
+			``,
+		},
+		{
+			cty.StringVal(`(?P<major>\d+)\.(?P<minor>\d+)`),
+			cty.StringVal("v1.2"),
+			cty.ObjectVal(map[string]cty.Value{
+				"major": cty.StringVal("1"),
+				"minor": cty.StringVal("2"),
+			}),
+			``,
+		},
+		{
+			// Unnamed groups are left out of the result when there are
+			// also named groups.
+			cty.StringVal(`(?P<name>[a-z]+)(-(?P<suffix>\d+))?`),
+			cty.StringVal("web"),
+			cty.ObjectVal(map[string]cty.Value{
+				"name":   cty.StringVal("web"),
+				"suffix": cty.NullVal(cty.String),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(`(?P<v>a+)|(?P<v>b+)`),
+			cty.StringVal("bb"),
+			cty.ObjectVal(map[string]cty.Value{
+				"v": cty.StringVal("bb"),
+			}),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal("abc"),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal("[a-z]+"),
+			cty.StringVal("123"),
+			cty.NilVal,
+			`pattern did not match any part of the given string`,
+		},
+		{
+			cty.StringVal("[a-z"),
+			cty.StringVal("abc"),
+			cty.NilVal,
+			`invalid regexp pattern: missing closing ] in [a-z`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("regex(%#v, %#v)", test.Pattern, test.String), func(t *testing.T) {
+			got, err := Regex(test.Pattern, test.String)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestRegexAll(t *testing.T) {
+	tests := []struct {
+		Pattern cty.Value
+		String  cty.Value
+		Want    cty.Value
+	}{
+		{
+			cty.StringVal(`(?P<key>\w+)=(?P<value>\w*)`),
+			cty.StringVal("a=1, b="),
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"key":   cty.StringVal("a"),
+					"value": cty.StringVal("1"),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"key":   cty.StringVal("b"),
+					"value": cty.StringVal(""),
+				}),
+			}),
+		},
+		{
+			cty.StringVal(`(?P<key>\w+)=(?P<value>\w*)`),
+			cty.StringVal("nothing here"),
+			cty.ListValEmpty(cty.Object(map[string]cty.Type{
+				"key":   cty.String,
+				"value": cty.String,
+			})),
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal("abc"),
+			cty.UnknownVal(cty.List(cty.DynamicPseudoType)).RefineNotNull(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("regexall(%#v, %#v)", test.Pattern, test.String), func(t *testing.T) {
+			got, err := RegexAll(test.Pattern, test.String)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestRegexReplace(t *testing.T) {
+	tests := []struct {
+		String      cty.Value
+		Pattern     cty.Value
+		Replacement cty.Value
+		Want        cty.Value
+		Err         string
+	}{
+		{
+			cty.StringVal("hello world"),
+			cty.StringVal("o"),
+			cty.StringVal("0"),
+			cty.StringVal("hell0 w0rld"),
+			``,
+		},
+		{
+			cty.StringVal("2019-02-01"),
+			cty.StringVal(`(\d+)-(\d+)-(\d+)`),
+			cty.StringVal("${3}/${2}/${1}"),
+			cty.StringVal("01/02/2019"),
+			``,
+		},
+		{
+			cty.StringVal("first=Ada last=Lovelace"),
+			cty.StringVal(`first=(?P<first>\w+) last=(?P<last>\w+)`),
+			cty.StringVal("${last}, ${first}"),
+			cty.StringVal("Lovelace, Ada"),
+			``,
+		},
+		{
+			cty.StringVal("cost: 5"),
+			cty.StringVal(`(\d+)`),
+			cty.StringVal("$$$1"),
+			cty.StringVal("cost: $5"),
+			``,
+		},
+		{
+			cty.StringVal("abc"),
+			cty.StringVal("x"),
+			cty.StringVal("y"),
+			cty.StringVal("abc"),
+			``,
+		},
+		{
+			cty.StringVal("abc"),
+			cty.UnknownVal(cty.String),
+			cty.StringVal("y"),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.StringVal("abc"),
+			cty.StringVal("(abc"),
+			cty.StringVal("y"),
+			cty.NilVal,
+			`invalid regexp pattern: missing closing ) in (abc`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("regexreplace(%#v, %#v, %#v)", test.String, test.Pattern, test.Replacement), func(t *testing.T) {
+			got, err := RegexReplace(test.String, test.Pattern, test.Replacement)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
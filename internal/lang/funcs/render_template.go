@@ -15,8 +15,34 @@ import (
 )
 
 func renderTemplate(expr hcl.Expression, varsVal cty.Value, funcs map[string]function.Function) (cty.Value, error) {
+	ctx, err := templateEvalContext(expr, varsVal, funcs)
+	if err != nil {
+		return cty.DynamicVal, err
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		for _, diag := range diags {
+			// Roll up recursive errors
+			if extra, ok := diag.Extra.(hclsyntax.FunctionCallDiagExtra); ok {
+				if extra.CalledFunctionName() == "templatefile" {
+					err := extra.FunctionCallError()
+					if err, ok := err.(ErrorTemplateRecursionLimit); ok {
+						return cty.DynamicVal, ErrorTemplateRecursionLimit{sources: append(err.sources, diag.Subject.String())}
+					}
+				}
+			}
+		}
+		return cty.DynamicVal, diags
+	}
+
+	return val, nil
+}
+
+// templateEvalContext checks the variables given to a template and returns the evaluation context to render it in.
+func templateEvalContext(expr hcl.Expression, varsVal cty.Value, funcs map[string]function.Function) (*hcl.EvalContext, error) {
 	if varsTy := varsVal.Type(); !(varsTy.IsMapType() || varsTy.IsObjectType()) {
-		return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map") // or an object, but we don't strongly distinguish these most of the time
+		return nil, function.NewArgErrorf(1, "invalid vars value: must be a map") // or an object, but we don't strongly distinguish these most of the time
 	}
 
 	ctx := &hcl.EvalContext{
@@ -34,7 +60,7 @@ func renderTemplate(expr hcl.Expression, varsVal cty.Value, funcs map[string]fun
 			// the different permutations that are technically valid as an
 			// HCL identifier, but rather focuses on what we might
 			// consider to be an "idiomatic" variable name.
-			return cty.DynamicVal, function.NewArgErrorf(1, "invalid template variable name %q: must start with a letter, followed by zero or more letters, digits, and underscores", n)
+			return nil, function.NewArgErrorf(1, "invalid template variable name %q: must start with a letter, followed by zero or more letters, digits, and underscores", n)
 		}
 	}
 
@@ -51,25 +77,9 @@ func renderTemplate(expr hcl.Expression, varsVal cty.Value, funcs map[string]fun
 			referencedPos = fmt.Sprintf("%q, referenced at %s", root, traversal[0].SourceRange())
 		}
 		if _, ok := ctx.Variables[root]; !ok {
-			return cty.DynamicVal, function.NewArgErrorf(1, "vars map does not contain key %s", referencedPos)
+			return nil, function.NewArgErrorf(1, "vars map does not contain key %s", referencedPos)
 		}
 	}
 
-	val, diags := expr.Value(ctx)
-	if diags.HasErrors() {
-		for _, diag := range diags {
-			// Roll up recursive errors
-			if extra, ok := diag.Extra.(hclsyntax.FunctionCallDiagExtra); ok {
-				if extra.CalledFunctionName() == "templatefile" {
-					err := extra.FunctionCallError()
-					if err, ok := err.(ErrorTemplateRecursionLimit); ok {
-						return cty.DynamicVal, ErrorTemplateRecursionLimit{sources: append(err.sources, diag.Subject.String())}
-					}
-				}
-			}
-		}
-		return cty.DynamicVal, diags
-	}
-
-	return val, nil
+	return ctx, nil
 }
@@ -0,0 +1,176 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// RenderTemplateFile renders the template file at the given path in the same
+// way as the templatefile function, but writes the result to w as it is
+// produced instead of returning it as a single string.
+//
+// The literal parts, interpolations and the iterations of for directives are
+// written one at a time, so the largest value held in memory is a single
+// interpolation result rather than the whole rendered template. This allows
+// rendering artifacts that are too large to materialize as a cty string.
+//
+// Unlike templatefile, the result is always converted to a string, and
+// unknown values are reported as errors because there is no way to represent
+// them in the output.
+func RenderTemplateFile(w io.Writer, baseDir, path string, varsVal cty.Value, funcs map[string]function.Function) error {
+	templateValue, err := File(baseDir, cty.StringVal(path))
+	if err != nil {
+		return err
+	}
+	templateValue, _ = templateValue.Unmark()
+
+	expr, diags := hclsyntax.ParseTemplate([]byte(templateValue.AsString()), path, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	ctx, err := templateEvalContext(expr, varsVal, funcs)
+	if err != nil {
+		return err
+	}
+
+	return streamTemplate(w, expr, ctx)
+}
+
+func streamTemplate(w io.Writer, expr hclsyntax.Expression, ctx *hcl.EvalContext) error {
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateExpr:
+		for _, part := range e.Parts {
+			if err := streamTemplate(w, part, ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *hclsyntax.TemplateWrapExpr:
+		return streamTemplate(w, e.Wrapped, ctx)
+	case *hclsyntax.TemplateJoinExpr:
+		if forExpr, ok := e.Tuple.(*hclsyntax.ForExpr); ok && forExpr.KeyExpr == nil {
+			return streamTemplateFor(w, forExpr, ctx)
+		}
+	case *hclsyntax.ConditionalExpr:
+		// Only if directives are streamed, the branches of a conditional
+		// expression in an interpolation must have consistent types.
+		_, trueIsTemplate := e.TrueResult.(*hclsyntax.TemplateExpr)
+		_, falseIsTemplate := e.FalseResult.(*hclsyntax.TemplateExpr)
+		if trueIsTemplate && falseIsTemplate {
+			cond, err := streamTemplateCondition(e.Condition, ctx)
+			if err != nil {
+				return err
+			}
+			if cond {
+				return streamTemplate(w, e.TrueResult, ctx)
+			}
+			return streamTemplate(w, e.FalseResult, ctx)
+		}
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return diags
+	}
+	return writeTemplateValue(w, val, expr)
+}
+
+func streamTemplateFor(w io.Writer, e *hclsyntax.ForExpr, ctx *hcl.EvalContext) error {
+	coll, diags := e.CollExpr.Value(ctx)
+	if diags.HasErrors() {
+		return diags
+	}
+	coll, _ = coll.Unmark()
+	switch {
+	case coll.IsNull():
+		return templateStreamError(e.CollExpr, "Iteration over null value", "A null value cannot be used as the collection in a 'for' directive.")
+	case !coll.IsKnown():
+		return templateStreamError(e.CollExpr, "Iteration over unknown value", "The collection of a 'for' directive must be known when rendering a template to a file.")
+	case !coll.CanIterateElements():
+		return templateStreamError(e.CollExpr, "Iteration over non-iterable value", fmt.Sprintf("A value of type %s cannot be used as the collection in a 'for' directive.", coll.Type().FriendlyName()))
+	}
+
+	for it := coll.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		childCtx := ctx.NewChild()
+		childCtx.Variables = map[string]cty.Value{
+			e.ValVar: v,
+		}
+		if e.KeyVar != "" {
+			childCtx.Variables[e.KeyVar] = k
+		}
+
+		if e.CondExpr != nil {
+			include, err := streamTemplateCondition(e.CondExpr, childCtx)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+		}
+
+		if err := streamTemplate(w, e.ValExpr, childCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func streamTemplateCondition(expr hclsyntax.Expression, ctx *hcl.EvalContext) (bool, error) {
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	val, _ = val.Unmark()
+	val, err := convert.Convert(val, cty.Bool)
+	if err != nil {
+		return false, templateStreamError(expr, "Invalid condition", fmt.Sprintf("The condition value is unsuitable: %s.", err))
+	}
+	switch {
+	case val.IsNull():
+		return false, templateStreamError(expr, "Invalid condition", "The condition value is null.")
+	case !val.IsKnown():
+		return false, templateStreamError(expr, "Invalid condition", "The condition must be known when rendering a template to a file.")
+	}
+	return val.True(), nil
+}
+
+func writeTemplateValue(w io.Writer, val cty.Value, expr hclsyntax.Expression) error {
+	val, _ = val.UnmarkDeep()
+	if val.IsNull() {
+		return templateStreamError(expr, "Invalid template interpolation value", "The expression result is null. Cannot include a null value in a string template.")
+	}
+	if !val.IsWhollyKnown() {
+		return templateStreamError(expr, "Invalid template interpolation value", "The expression result must be known when rendering a template to a file.")
+	}
+	strVal, err := convert.Convert(val, cty.String)
+	if err != nil {
+		return templateStreamError(expr, "Invalid template interpolation value", fmt.Sprintf("Cannot include the given value in a string template: %s.", err))
+	}
+	_, err = io.WriteString(w, strVal.AsString())
+	return err
+}
+
+func templateStreamError(expr hclsyntax.Expression, summary, detail string) error {
+	return hcl.Diagnostics{
+		&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  summary,
+			Detail:   detail,
+			Subject:  expr.Range().Ptr(),
+		},
+	}
+}
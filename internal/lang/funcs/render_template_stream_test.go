@@ -0,0 +1,133 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestRenderTemplateFile(t *testing.T) {
+	// Other tests set this without restoring it.
+	t.Setenv("TF_TEMPLATE_RECURSION_DEPTH", "")
+
+	funcs := func() map[string]function.Function {
+		return map[string]function.Function{
+			"join":   stdlib.JoinFunc,
+			"length": stdlib.LengthFunc,
+			"upper":  stdlib.UpperFunc,
+		}
+	}
+	templateFileFn := MakeTemplateFileFunc(".", funcs)
+
+	tests := map[string]struct {
+		Path string
+		Vars cty.Value
+		Err  string
+	}{
+		"plain": {
+			Path: "testdata/hello.txt",
+			Vars: cty.EmptyObjectVal,
+		},
+		"interpolation": {
+			Path: "testdata/hello.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("Jodie"),
+			}),
+		},
+		"bare non-string": {
+			Path: "testdata/bare.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"val": cty.NumberIntVal(42),
+			}),
+		},
+		"function": {
+			Path: "testdata/func.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"list": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+		},
+		"for directive": {
+			Path: "testdata/list.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"list": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b").Mark(marks.Sensitive)}),
+			}),
+		},
+		"for and if directives": {
+			Path: "testdata/stream.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"items": cty.MapVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("first"), "enabled": cty.True}),
+					"b": cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("second"), "enabled": cty.False}),
+					"c": cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("third"), "enabled": cty.True}),
+				}),
+			}),
+		},
+		"missing variable": {
+			Path: "testdata/hello.tmpl",
+			Vars: cty.EmptyObjectVal,
+			Err:  `vars map does not contain key "name"`,
+		},
+		"unknown value": {
+			Path: "testdata/hello.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.UnknownVal(cty.String),
+			}),
+			Err: "must be known when rendering a template to a file",
+		},
+		"unknown collection": {
+			Path: "testdata/list.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"list": cty.UnknownVal(cty.List(cty.String)),
+			}),
+			Err: "must be known when rendering a template to a file",
+		},
+		"null value": {
+			Path: "testdata/hello.tmpl",
+			Vars: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.NullVal(cty.String),
+			}),
+			Err: "Cannot include a null value in a string template",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf strings.Builder
+			err := RenderTemplateFile(&buf, ".", test.Path, test.Vars, funcs())
+			if test.Err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %v\nwant: %s", err, test.Err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// The streamed result must match the result of templatefile.
+			want, err := templateFileFn.Call([]cty.Value{cty.StringVal(test.Path), test.Vars})
+			if err != nil {
+				t.Fatalf("unexpected error from templatefile: %s", err)
+			}
+			want, _ = want.UnmarkDeep()
+			want, err = convert.Convert(want, cty.String)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != want.AsString() {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want.AsString())
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TOMLDecodeFunc constructs a function that parses a string as a TOML
+// document and returns the value it represents.
+//
+// Tables become objects and arrays become tuples, as for jsondecode, and date
+// and time values become strings in the same form as they are written in the
+// document, since the language has no date or time types.
+var TOMLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		val, err := tomlDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return val.Type(), nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val, err := tomlDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		return val, nil
+	},
+})
+
+// TOMLEncodeFunc constructs a function that encodes an object or map as a
+// TOML document.
+var TOMLEncodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "val",
+			Type: cty.DynamicPseudoType,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val := args[0]
+		if !val.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		// A TOML document is always a table.
+		ty := val.Type()
+		if val.IsNull() || !(ty.IsObjectType() || ty.IsMapType()) {
+			return cty.NilVal, function.NewArgErrorf(0, "a TOML document must be an object or a map, not %s", ty.FriendlyName())
+		}
+
+		doc, err := tomlGoValue(val, nil)
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(doc); err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		return cty.StringVal(buf.String()), nil
+	},
+})
+
+// tomlDecode parses the given TOML document into a value.
+func tomlDecode(src string) (cty.Value, error) {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(src, &doc); err != nil {
+		return cty.NilVal, fmt.Errorf("invalid TOML: %s", strings.TrimPrefix(err.Error(), "toml: "))
+	}
+	return tomlCtyValue(doc)
+}
+
+// tomlCtyValue converts a value decoded by the TOML library into the
+// corresponding value.
+func tomlCtyValue(raw interface{}) (cty.Value, error) {
+	switch raw := raw.(type) {
+	case string:
+		return cty.StringVal(raw), nil
+	case bool:
+		return cty.BoolVal(raw), nil
+	case int64:
+		return cty.NumberIntVal(raw), nil
+	case float64:
+		if math.IsNaN(raw) || math.IsInf(raw, 0) {
+			return cty.NilVal, fmt.Errorf("the TOML value %v has no equivalent number", raw)
+		}
+		return cty.NumberFloatVal(raw), nil
+	case time.Time:
+		// The TOML library uses some special locations to represent the
+		// date and time values that have no time zone.
+		switch raw.Location().String() {
+		case "datetime-local":
+			return cty.StringVal(raw.Format("2006-01-02T15:04:05.999999999")), nil
+		case "date-local":
+			return cty.StringVal(raw.Format("2006-01-02")), nil
+		case "time-local":
+			return cty.StringVal(raw.Format("15:04:05.999999999")), nil
+		default:
+			return cty.StringVal(raw.Format(time.RFC3339Nano)), nil
+		}
+	case []interface{}:
+		if len(raw) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		elems := make([]cty.Value, len(raw))
+		for i, elem := range raw {
+			var err error
+			if elems[i], err = tomlCtyValue(elem); err != nil {
+				return cty.NilVal, err
+			}
+		}
+		return cty.TupleVal(elems), nil
+	case []map[string]interface{}:
+		elems := make([]interface{}, len(raw))
+		for i, elem := range raw {
+			elems[i] = elem
+		}
+		return tomlCtyValue(elems)
+	case map[string]interface{}:
+		if len(raw) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		attrs := make(map[string]cty.Value, len(raw))
+		for name, attr := range raw {
+			var err error
+			if attrs[name], err = tomlCtyValue(attr); err != nil {
+				return cty.NilVal, err
+			}
+		}
+		return cty.ObjectVal(attrs), nil
+	default:
+		// Should never happen, since we've handled all of the types that
+		// the TOML library decodes into.
+		return cty.NilVal, fmt.Errorf("unsupported TOML value of type %T", raw)
+	}
+}
+
+// tomlGoValue converts the given known value into a value that the TOML
+// library can encode. The path is the location of the value within the
+// document being encoded, for use in error messages.
+func tomlGoValue(val cty.Value, path cty.Path) (interface{}, error) {
+	if val.IsNull() {
+		return nil, fmt.Errorf("cannot encode the null value at %s, because TOML has no null value", tfdiags.FormatCtyPath(path))
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString(), nil
+	case ty == cty.Bool:
+		return val.True(), nil
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if bf.IsInt() {
+			if i, acc := bf.Int64(); acc == big.Exact {
+				return i, nil
+			}
+			return nil, fmt.Errorf("cannot encode the number at %s, because it's too large for a TOML integer", tfdiags.FormatCtyPath(path))
+		}
+		f, _ := bf.Float64()
+		return f, nil
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		elems := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elem, err := tomlGoValue(v, path.Index(k))
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return elems, nil
+	case ty.IsMapType() || ty.IsObjectType():
+		attrs := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			var step cty.PathStep = cty.IndexStep{Key: k}
+			if ty.IsObjectType() {
+				step = cty.GetAttrStep{Name: k.AsString()}
+			}
+			attr, err := tomlGoValue(v, append(path.Copy(), step))
+			if err != nil {
+				return nil, err
+			}
+			attrs[k.AsString()] = attr
+		}
+		return attrs, nil
+	default:
+		return nil, fmt.Errorf("cannot encode the value of type %s at %s as TOML", ty.FriendlyName(), tfdiags.FormatCtyPath(path))
+	}
+}
+
+// TOMLDecode parses the given string as a TOML document.
+func TOMLDecode(src cty.Value) (cty.Value, error) {
+	return TOMLDecodeFunc.Call([]cty.Value{src})
+}
+
+// TOMLEncode encodes the given object or map as a TOML document.
+func TOMLEncode(val cty.Value) (cty.Value, error) {
+	return TOMLEncodeFunc.Call([]cty.Value{val})
+}
@@ -0,0 +1,218 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestTOMLDecode(t *testing.T) {
+	tests := []struct {
+		Src  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.StringVal(`
+title = "example"
+port = 8080
+ratio = 0.5
+enabled = true
+tags = ["a", 1]
+
+[owner]
+name = "Tom"
+born = 1979-05-27T07:32:00-08:00
+date = 1979-05-27
+time = 07:32:00
+local = 1979-05-27T07:32:00.5
+
+[[servers]]
+host = "alpha"
+
+[[servers]]
+host = "beta"
+`),
+			cty.ObjectVal(map[string]cty.Value{
+				"title":   cty.StringVal("example"),
+				"port":    cty.NumberIntVal(8080),
+				"ratio":   cty.NumberFloatVal(0.5),
+				"enabled": cty.True,
+				"tags":    cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.NumberIntVal(1)}),
+				"owner": cty.ObjectVal(map[string]cty.Value{
+					"name":  cty.StringVal("Tom"),
+					"born":  cty.StringVal("1979-05-27T07:32:00-08:00"),
+					"date":  cty.StringVal("1979-05-27"),
+					"time":  cty.StringVal("07:32:00"),
+					"local": cty.StringVal("1979-05-27T07:32:00.5"),
+				}),
+				"servers": cty.TupleVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"host": cty.StringVal("alpha")}),
+					cty.ObjectVal(map[string]cty.Value{"host": cty.StringVal("beta")}),
+				}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(``),
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			cty.StringVal(`a = []`),
+			cty.ObjectVal(map[string]cty.Value{"a": cty.EmptyTupleVal}),
+			``,
+		},
+		{
+			cty.StringVal(`a = "secret"`).Mark(marks.Sensitive),
+			cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("secret")}).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal(`a = nan`),
+			cty.NilVal,
+			`the TOML value NaN has no equivalent number`,
+		},
+		{
+			cty.StringVal("a = 1\na = 2\n"),
+			cty.NilVal,
+			`invalid TOML: line 2 (last key "a"): Key 'a' has already been defined.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomldecode(%#v)", test.Src), func(t *testing.T) {
+			got, err := TOMLDecode(test.Src)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTOMLEncode(t *testing.T) {
+	tests := []struct {
+		Val  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"title": cty.StringVal("example"),
+				"port":  cty.NumberIntVal(8080),
+				"ratio": cty.NumberFloatVal(0.5),
+				"tags":  cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				"owner": cty.MapVal(map[string]cty.Value{
+					"name": cty.StringVal("Tom"),
+				}),
+				"servers": cty.TupleVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"host": cty.StringVal("alpha")}),
+					cty.ObjectVal(map[string]cty.Value{"host": cty.StringVal("beta")}),
+				}),
+			}),
+			cty.StringVal(`port = 8080
+ratio = 0.5
+tags = ["a", "b"]
+title = "example"
+
+[owner]
+name = "Tom"
+
+[[servers]]
+host = "alpha"
+
+[[servers]]
+host = "beta"
+`),
+			``,
+		},
+		{
+			cty.EmptyObjectVal,
+			cty.StringVal(``),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("secret").Mark(marks.Sensitive),
+			}),
+			cty.StringVal("a = \"secret\"\n").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.UnknownVal(cty.String),
+			}),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.StringVal("a"),
+			cty.NilVal,
+			`a TOML document must be an object or a map, not string`,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.NullVal(cty.String),
+				}),
+			}),
+			cty.NilVal,
+			`cannot encode the null value at .a.b, because TOML has no null value`,
+		},
+		{
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.ListVal([]cty.Value{cty.MustParseNumberVal("1e30")}),
+			}),
+			cty.NilVal,
+			`cannot encode the number at ["a"][0], because it's too large for a TOML integer`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomlencode(%#v)", test.Val), func(t *testing.T) {
+			got, err := TOMLEncode(test.Val)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
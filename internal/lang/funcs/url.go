@@ -0,0 +1,290 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// urlObjectType is the type of the objects that urlparse returns and that
+// urlbuild accepts.
+//
+// The username, password, and port are null if the URL doesn't have them,
+// while the other attributes are empty strings.
+var urlObjectType = cty.Object(map[string]cty.Type{
+	"scheme":   cty.String,
+	"username": cty.String,
+	"password": cty.String,
+	"host":     cty.String,
+	"port":     cty.Number,
+	"path":     cty.String,
+	"query":    cty.Map(cty.List(cty.String)),
+	"fragment": cty.String,
+})
+
+// urlSchemeRe matches the valid URL schemes, as defined by RFC 3986.
+var urlSchemeRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+// URLParseFunc constructs a function that parses a URL into its components.
+var URLParseFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "url",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(urlObjectType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		u, err := url.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid URL: %s", urlErrorReason(err))
+		}
+
+		username, password := cty.NullVal(cty.String), cty.NullVal(cty.String)
+		if u.User != nil {
+			username = cty.StringVal(u.User.Username())
+			if p, ok := u.User.Password(); ok {
+				password = cty.StringVal(p)
+			}
+		}
+
+		port := cty.NullVal(cty.Number)
+		if p := u.Port(); p != "" {
+			n, err := strconv.ParseUint(p, 10, 16)
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid URL: invalid port %q", p)
+			}
+			port = cty.NumberUIntVal(n)
+		}
+
+		path := u.Path
+		if u.Opaque != "" {
+			// A URL like mailto:user@example.com has no authority, and so
+			// url.Parse doesn't treat the rest of it as a path.
+			path, err = url.PathUnescape(u.Opaque)
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid URL: %s", urlErrorReason(err))
+			}
+		}
+
+		values, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid URL query: %s", urlErrorReason(err))
+		}
+		query := make(map[string]cty.Value, len(values))
+		for k, vs := range values {
+			elems := make([]cty.Value, len(vs))
+			for i, v := range vs {
+				elems[i] = cty.StringVal(v)
+			}
+			query[k] = cty.ListVal(elems)
+		}
+		queryVal := cty.MapValEmpty(cty.List(cty.String))
+		if len(query) > 0 {
+			queryVal = cty.MapVal(query)
+		}
+
+		return cty.ObjectVal(map[string]cty.Value{
+			"scheme":   cty.StringVal(u.Scheme),
+			"username": username,
+			"password": password,
+			"host":     cty.StringVal(u.Hostname()),
+			"port":     port,
+			"path":     cty.StringVal(path),
+			"query":    queryVal,
+			"fragment": cty.StringVal(u.Fragment),
+		}), nil
+	},
+})
+
+// URLBuildFunc constructs a function that assembles a URL from its
+// components, which is the inverse of URLParseFunc.
+//
+// The components are given as an object with any of the attributes of the
+// result of URLParseFunc. Null and missing attributes are treated as absent.
+// The query values can be either strings or lists of strings.
+var URLBuildFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "components",
+			Type: cty.DynamicPseudoType,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		components := args[0]
+		ty := components.Type()
+		if !ty.IsObjectType() && !ty.IsMapType() {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "must be an object of URL components")
+		}
+		if components.IsNull() {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "must not be null")
+		}
+		if !components.IsWhollyKnown() {
+			return cty.UnknownVal(cty.String), nil
+		}
+
+		attrs := components.AsValueMap()
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := urlObjectType.AttributeTypes()[name]; !ok {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "unsupported URL component %q", name)
+			}
+		}
+
+		str := func(name string) (string, bool, error) {
+			v, ok := attrs[name]
+			if !ok || v.IsNull() {
+				return "", false, nil
+			}
+			v, err := convert.Convert(v, cty.String)
+			if err != nil {
+				return "", false, function.NewArgErrorf(0, "invalid URL component %q: %s", name, err)
+			}
+			return v.AsString(), true, nil
+		}
+
+		u := &url.URL{}
+		scheme, hasScheme, err := str("scheme")
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		if hasScheme && !urlSchemeRe.MatchString(scheme) {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid URL scheme %q", scheme)
+		}
+		u.Scheme = scheme
+
+		username, hasUsername, err := str("username")
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		password, hasPassword, err := str("password")
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		switch {
+		case hasPassword:
+			u.User = url.UserPassword(username, password)
+		case hasUsername:
+			u.User = url.User(username)
+		}
+
+		host, _, err := str("host")
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		port, hasPort, err := str("port")
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		switch {
+		case hasPort:
+			n, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid URL port %q: must be a whole number between 0 and 65535", port)
+			}
+			u.Host = net.JoinHostPort(host, strconv.FormatUint(n, 10))
+		case strings.Contains(host, ":"):
+			// IPv6 addresses must be in brackets.
+			u.Host = "[" + host + "]"
+		default:
+			u.Host = host
+		}
+
+		if u.Path, _, err = str("path"); err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		if u.Host == "" && u.User == nil && !strings.HasPrefix(u.Path, "/") {
+			// Without this, a URL like mailto:user@example.com would get
+			// an empty authority, as in mailto://user@example.com.
+			u.OmitHost = true
+		}
+
+		if q, ok := attrs["query"]; ok && !q.IsNull() {
+			values, err := urlQueryValues(q)
+			if err != nil {
+				return cty.UnknownVal(cty.String), err
+			}
+			u.RawQuery = values.Encode()
+		}
+
+		if u.Fragment, _, err = str("fragment"); err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+
+		return cty.StringVal(u.String()), nil
+	},
+})
+
+// urlQueryValues converts the query component given to urlbuild, which is a
+// map or object whose values are either strings or lists of strings.
+func urlQueryValues(q cty.Value) (url.Values, error) {
+	ty := q.Type()
+	if !ty.IsObjectType() && !ty.IsMapType() {
+		return nil, function.NewArgErrorf(0, "invalid URL component \"query\": must be a map")
+	}
+
+	values := make(url.Values)
+	for k, v := range q.AsValueMap() {
+		if v.IsNull() {
+			continue
+		}
+		if ty := v.Type(); ty.IsListType() || ty.IsTupleType() || ty.IsSetType() {
+			list, err := convert.Convert(v, cty.List(cty.String))
+			if err != nil {
+				return nil, function.NewArgErrorf(0, "invalid URL query parameter %q: %s", k, err)
+			}
+			for _, elem := range list.AsValueSlice() {
+				if elem.IsNull() {
+					return nil, function.NewArgErrorf(0, "invalid URL query parameter %q: must not contain null values", k)
+				}
+				values.Add(k, elem.AsString())
+			}
+			continue
+		}
+		str, err := convert.Convert(v, cty.String)
+		if err != nil {
+			return nil, function.NewArgErrorf(0, "invalid URL query parameter %q: %s", k, err)
+		}
+		values.Add(k, str.AsString())
+	}
+	return values, nil
+}
+
+// urlErrorReason returns the reason for the given error from the net/url
+// package, without the operation and the URL, which the user already knows.
+func urlErrorReason(err error) string {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// URLParse parses a URL into an object of its components.
+func URLParse(str cty.Value) (cty.Value, error) {
+	return URLParseFunc.Call([]cty.Value{str})
+}
+
+// URLBuild assembles a URL from an object of its components, as returned by
+// URLParse.
+func URLBuild(components cty.Value) (cty.Value, error) {
+	return URLBuildFunc.Call([]cty.Value{components})
+}
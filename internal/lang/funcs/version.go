@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	version "github.com/hashicorp/go-version"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// SemverConstraintFunc constructs a function that checks whether a version
+// number meets a version constraint string.
+//
+// It uses the same constraint syntax and matching rules as the version
+// arguments in the configuration, such as required_version and the version
+// of a module call.
+var SemverConstraintFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "version",
+			Type: cty.String,
+		},
+		{
+			Name: "constraint",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v, err := version.NewVersion(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid version: %s", err)
+		}
+		constraints, err := version.NewConstraint(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid version constraint: %s", err)
+		}
+		return cty.BoolVal(constraints.Check(v)), nil
+	},
+})
+
+// SemverCompareFunc constructs a function that compares two version numbers,
+// returning -1 if the first is lower than the second, 0 if they are equal,
+// and 1 if the first is higher than the second.
+var SemverCompareFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "a",
+			Type: cty.String,
+		},
+		{
+			Name: "b",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Number),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		versions := make([]*version.Version, len(args))
+		for i, arg := range args {
+			v, err := version.NewVersion(arg.AsString())
+			if err != nil {
+				return cty.UnknownVal(cty.Number), function.NewArgErrorf(i, "invalid version: %s", err)
+			}
+			versions[i] = v
+		}
+		return cty.NumberIntVal(int64(versions[0].Compare(versions[1]))), nil
+	},
+})
+
+// SemverConstraint returns true if the given version meets the given
+// version constraint.
+func SemverConstraint(v, constraint cty.Value) (cty.Value, error) {
+	return SemverConstraintFunc.Call([]cty.Value{v, constraint})
+}
+
+// SemverCompare compares the given versions, returning -1, 0, or 1.
+func SemverCompare(a, b cty.Value) (cty.Value, error) {
+	return SemverCompareFunc.Call([]cty.Value{a, b})
+}
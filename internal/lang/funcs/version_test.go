@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSemverConstraint(t *testing.T) {
+	tests := []struct {
+		Version    cty.Value
+		Constraint cty.Value
+		Want       cty.Value
+		Err        string
+	}{
+		{
+			cty.StringVal("1.2.3"),
+			cty.StringVal(">= 1.0.0, < 2.0.0"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("v1.10.0"),
+			cty.StringVal("~> 1.9"),
+			cty.True,
+			``,
+		},
+		{
+			cty.StringVal("2.0.0"),
+			cty.StringVal("~> 1.9"),
+			cty.False,
+			``,
+		},
+		{
+			// A prerelease version only meets a constraint that refers
+			// to a prerelease of the same version.
+			cty.StringVal("1.3.0-beta1"),
+			cty.StringVal(">= 1.0.0"),
+			cty.False,
+			``,
+		},
+		{
+			cty.StringVal("1.3.0-beta2"),
+			cty.StringVal(">= 1.3.0-beta1"),
+			cty.True,
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.StringVal(">= 1.0.0"),
+			cty.UnknownVal(cty.Bool).RefineNotNull(),
+			``,
+		},
+		{
+			cty.StringVal("latest"),
+			cty.StringVal(">= 1.0.0"),
+			cty.NilVal,
+			`invalid version: Malformed version: latest`,
+		},
+		{
+			cty.StringVal("1.0.0"),
+			cty.StringVal("=> 1.0.0"),
+			cty.NilVal,
+			`invalid version constraint: Malformed constraint: => 1.0.0`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semverconstraint(%#v, %#v)", test.Version, test.Constraint), func(t *testing.T) {
+			got, err := SemverConstraint(test.Version, test.Constraint)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	tests := []struct {
+		A    cty.Value
+		B    cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.StringVal("1.9.0"),
+			cty.StringVal("1.10.0"),
+			cty.NumberIntVal(-1),
+			``,
+		},
+		{
+			cty.StringVal("v1.2"),
+			cty.StringVal("1.2.0"),
+			cty.NumberIntVal(0),
+			``,
+		},
+		{
+			cty.StringVal("1.2.0"),
+			cty.StringVal("1.2.0-rc1"),
+			cty.NumberIntVal(1),
+			``,
+		},
+		{
+			cty.StringVal("1.2.0"),
+			cty.StringVal("foo"),
+			cty.NilVal,
+			`invalid version: Malformed version: foo`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semvercompare(%#v, %#v)", test.A, test.B), func(t *testing.T) {
+			got, err := SemverCompare(test.A, test.B)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package lang
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
 	ctyyaml "github.com/zclconf/go-cty-yaml"
@@ -22,7 +23,9 @@ import (
 var impureFunctions = []string{
 	"bcrypt",
 	"timestamp",
+	"ulid",
 	"uuid",
+	"uuidv7",
 }
 
 // This should probably be replaced with addrs.Function everywhere
@@ -70,6 +73,14 @@ func (s *Scope) Functions() map[string]function.Function {
 	return s.funcs
 }
 
+// RenderTemplateFile renders the template file at the given path with the
+// functions of the receiving scope, like the templatefile function does, but
+// writes the result to w as it is produced instead of building the whole
+// result in memory. See funcs.RenderTemplateFile for the details.
+func (s *Scope) RenderTemplateFile(w io.Writer, path string, vars cty.Value) error {
+	return funcs.RenderTemplateFile(w, s.BaseDir, path, vars, s.Functions())
+}
+
 // experimentalFunction checks whether the given experiment is enabled for
 // the receiving scope. If so, it will return the given function verbatim.
 // If not, it will return a placeholder function that just returns an
@@ -116,125 +127,142 @@ func makeBaseFunctionTable(baseDir string) map[string]function.Function {
 	// that would be useful to all applications using cty functions.
 
 	ret := map[string]function.Function{
-		"abs":              stdlib.AbsoluteFunc,
-		"abspath":          funcs.AbsPathFunc,
-		"alltrue":          funcs.AllTrueFunc,
-		"anytrue":          funcs.AnyTrueFunc,
-		"basename":         funcs.BasenameFunc,
-		"base64decode":     funcs.Base64DecodeFunc,
-		"base64encode":     funcs.Base64EncodeFunc,
-		"base64gzip":       funcs.Base64GzipFunc,
-		"base64gunzip":     funcs.Base64GunzipFunc,
-		"base64sha256":     funcs.Base64Sha256Func,
-		"base64sha512":     funcs.Base64Sha512Func,
-		"bcrypt":           funcs.BcryptFunc,
-		"can":              tryfunc.CanFunc,
-		"ceil":             stdlib.CeilFunc,
-		"chomp":            stdlib.ChompFunc,
-		"cidrcontains":     funcs.CidrContainsFunc,
-		"cidrhost":         funcs.CidrHostFunc,
-		"cidrnetmask":      funcs.CidrNetmaskFunc,
-		"cidrsubnet":       funcs.CidrSubnetFunc,
-		"cidrsubnets":      funcs.CidrSubnetsFunc,
-		"coalesce":         funcs.CoalesceFunc,
-		"coalescelist":     stdlib.CoalesceListFunc,
-		"compact":          stdlib.CompactFunc,
-		"concat":           stdlib.ConcatFunc,
-		"contains":         stdlib.ContainsFunc,
-		"csvdecode":        stdlib.CSVDecodeFunc,
-		"dirname":          funcs.DirnameFunc,
-		"distinct":         stdlib.DistinctFunc,
-		"element":          stdlib.ElementFunc,
-		"endswith":         funcs.EndsWithFunc,
-		"chunklist":        stdlib.ChunklistFunc,
-		"file":             funcs.MakeFileFunc(baseDir, false),
-		"fileexists":       funcs.MakeFileExistsFunc(baseDir),
-		"fileset":          funcs.MakeFileSetFunc(baseDir),
-		"filebase64":       funcs.MakeFileFunc(baseDir, true),
-		"filebase64sha256": funcs.MakeFileBase64Sha256Func(baseDir),
-		"filebase64sha512": funcs.MakeFileBase64Sha512Func(baseDir),
-		"filemd5":          funcs.MakeFileMd5Func(baseDir),
-		"filesha1":         funcs.MakeFileSha1Func(baseDir),
-		"filesha256":       funcs.MakeFileSha256Func(baseDir),
-		"filesha512":       funcs.MakeFileSha512Func(baseDir),
-		"flatten":          stdlib.FlattenFunc,
-		"floor":            stdlib.FloorFunc,
-		"format":           stdlib.FormatFunc,
-		"formatdate":       stdlib.FormatDateFunc,
-		"formatlist":       stdlib.FormatListFunc,
-		"indent":           stdlib.IndentFunc,
-		"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
-		"join":             stdlib.JoinFunc,
-		"jsondecode":       stdlib.JSONDecodeFunc,
-		"jsonencode":       stdlib.JSONEncodeFunc,
-		"keys":             stdlib.KeysFunc,
-		"length":           funcs.LengthFunc,
-		"list":             funcs.ListFunc,
-		"log":              stdlib.LogFunc,
-		"lookup":           funcs.LookupFunc,
-		"lower":            stdlib.LowerFunc,
-		"map":              funcs.MapFunc,
-		"matchkeys":        funcs.MatchkeysFunc,
-		"max":              stdlib.MaxFunc,
-		"md5":              funcs.Md5Func,
-		"merge":            stdlib.MergeFunc,
-		"min":              stdlib.MinFunc,
-		"one":              funcs.OneFunc,
-		"parseint":         stdlib.ParseIntFunc,
-		"pathexpand":       funcs.PathExpandFunc,
-		"pow":              stdlib.PowFunc,
-		"range":            stdlib.RangeFunc,
-		"regex":            stdlib.RegexFunc,
-		"regexall":         stdlib.RegexAllFunc,
-		"replace":          funcs.ReplaceFunc,
-		"reverse":          stdlib.ReverseListFunc,
-		"rsadecrypt":       funcs.RsaDecryptFunc,
-		"sensitive":        funcs.SensitiveFunc,
-		"nonsensitive":     funcs.NonsensitiveFunc,
-		"issensitive":      funcs.IsSensitiveFunc,
-		"setintersection":  stdlib.SetIntersectionFunc,
-		"setproduct":       stdlib.SetProductFunc,
-		"setsubtract":      stdlib.SetSubtractFunc,
-		"setunion":         stdlib.SetUnionFunc,
-		"sha1":             funcs.Sha1Func,
-		"sha256":           funcs.Sha256Func,
-		"sha512":           funcs.Sha512Func,
-		"signum":           stdlib.SignumFunc,
-		"slice":            stdlib.SliceFunc,
-		"sort":             stdlib.SortFunc,
-		"split":            stdlib.SplitFunc,
-		"startswith":       funcs.StartsWithFunc,
-		"strcontains":      funcs.StrContainsFunc,
-		"strrev":           stdlib.ReverseFunc,
-		"substr":           stdlib.SubstrFunc,
-		"sum":              funcs.SumFunc,
-		"textdecodebase64": funcs.TextDecodeBase64Func,
-		"textencodebase64": funcs.TextEncodeBase64Func,
-		"timestamp":        funcs.TimestampFunc,
-		"timeadd":          stdlib.TimeAddFunc,
-		"timecmp":          funcs.TimeCmpFunc,
-		"title":            stdlib.TitleFunc,
-		"tostring":         funcs.MakeToFunc(cty.String),
-		"tonumber":         funcs.MakeToFunc(cty.Number),
-		"tobool":           funcs.MakeToFunc(cty.Bool),
-		"toset":            funcs.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
-		"tolist":           funcs.MakeToFunc(cty.List(cty.DynamicPseudoType)),
-		"tomap":            funcs.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
-		"transpose":        funcs.TransposeFunc,
-		"trim":             stdlib.TrimFunc,
-		"trimprefix":       stdlib.TrimPrefixFunc,
-		"trimspace":        stdlib.TrimSpaceFunc,
-		"trimsuffix":       stdlib.TrimSuffixFunc,
-		"try":              tryfunc.TryFunc,
-		"upper":            stdlib.UpperFunc,
-		"urlencode":        funcs.URLEncodeFunc,
-		"urldecode":        funcs.URLDecodeFunc,
-		"uuid":             funcs.UUIDFunc,
-		"uuidv5":           funcs.UUIDV5Func,
-		"values":           stdlib.ValuesFunc,
-		"yamldecode":       ctyyaml.YAMLDecodeFunc,
-		"yamlencode":       ctyyaml.YAMLEncodeFunc,
-		"zipmap":           stdlib.ZipmapFunc,
+		"abs":                stdlib.AbsoluteFunc,
+		"abspath":            funcs.AbsPathFunc,
+		"alltrue":            funcs.AllTrueFunc,
+		"anytrue":            funcs.AnyTrueFunc,
+		"basename":           funcs.BasenameFunc,
+		"base64decode":       funcs.Base64DecodeFunc,
+		"base64encode":       funcs.Base64EncodeFunc,
+		"base64gzip":         funcs.Base64GzipFunc,
+		"base64gunzip":       funcs.Base64GunzipFunc,
+		"base64sha256":       funcs.Base64Sha256Func,
+		"base64sha512":       funcs.Base64Sha512Func,
+		"bcrypt":             funcs.BcryptFunc,
+		"can":                tryfunc.CanFunc,
+		"ceil":               stdlib.CeilFunc,
+		"chomp":              stdlib.ChompFunc,
+		"cidrcontains":       funcs.CidrContainsFunc,
+		"cidrhost":           funcs.CidrHostFunc,
+		"cidrnetmask":        funcs.CidrNetmaskFunc,
+		"cidroverlaps":       funcs.CidrOverlapsFunc,
+		"cidrsplit":          funcs.CidrSplitFunc,
+		"cidrsubnet":         funcs.CidrSubnetFunc,
+		"cidrsubnets":        funcs.CidrSubnetsFunc,
+		"coalesce":           funcs.CoalesceFunc,
+		"coalescelist":       stdlib.CoalesceListFunc,
+		"compact":            stdlib.CompactFunc,
+		"concat":             stdlib.ConcatFunc,
+		"contains":           stdlib.ContainsFunc,
+		"csvdecode":          stdlib.CSVDecodeFunc,
+		"deepmerge":          funcs.DeepMergeFunc,
+		"deepmergeconcat":    funcs.DeepMergeConcatFunc,
+		"deepmergedistinct":  funcs.DeepMergeDistinctFunc,
+		"dirname":            funcs.DirnameFunc,
+		"distinct":           stdlib.DistinctFunc,
+		"element":            stdlib.ElementFunc,
+		"endswith":           funcs.EndsWithFunc,
+		"chunklist":          stdlib.ChunklistFunc,
+		"file":               funcs.MakeFileFunc(baseDir, false),
+		"fileexists":         funcs.MakeFileExistsFunc(baseDir),
+		"fileset":            funcs.MakeFileSetFunc(baseDir),
+		"filebase64":         funcs.MakeFileFunc(baseDir, true),
+		"filebase64sha256":   funcs.MakeFileBase64Sha256Func(baseDir),
+		"filebase64sha512":   funcs.MakeFileBase64Sha512Func(baseDir),
+		"filemd5":            funcs.MakeFileMd5Func(baseDir),
+		"filesha1":           funcs.MakeFileSha1Func(baseDir),
+		"filesha256":         funcs.MakeFileSha256Func(baseDir),
+		"filesha512":         funcs.MakeFileSha512Func(baseDir),
+		"flatten":            stdlib.FlattenFunc,
+		"floor":              stdlib.FloorFunc,
+		"format":             stdlib.FormatFunc,
+		"formatdate":         stdlib.FormatDateFunc,
+		"formatlist":         stdlib.FormatListFunc,
+		"hmac":               funcs.HmacFunc,
+		"indent":             stdlib.IndentFunc,
+		"index":              funcs.IndexFunc, // stdlib.IndexFunc is not compatible
+		"join":               stdlib.JoinFunc,
+		"jsondecode":         stdlib.JSONDecodeFunc,
+		"jsonencode":         stdlib.JSONEncodeFunc,
+		"jsonschemavalidate": funcs.JSONSchemaValidateFunc,
+		"jwtdecode":          funcs.JWTDecodeFunc,
+		"keys":               stdlib.KeysFunc,
+		"length":             funcs.LengthFunc,
+		"list":               funcs.ListFunc,
+		"log":                stdlib.LogFunc,
+		"lookup":             funcs.LookupFunc,
+		"lower":              stdlib.LowerFunc,
+		"map":                funcs.MapFunc,
+		"matchkeys":          funcs.MatchkeysFunc,
+		"max":                stdlib.MaxFunc,
+		"md5":                funcs.Md5Func,
+		"merge":              stdlib.MergeFunc,
+		"min":                stdlib.MinFunc,
+		"one":                funcs.OneFunc,
+		"parseint":           stdlib.ParseIntFunc,
+		"pathexpand":         funcs.PathExpandFunc,
+		"pow":                stdlib.PowFunc,
+		"range":              stdlib.RangeFunc,
+		"regex":              funcs.RegexFunc,
+		"regexall":           funcs.RegexAllFunc,
+		"regexreplace":       funcs.RegexReplaceFunc,
+		"replace":            funcs.ReplaceFunc,
+		"reverse":            stdlib.ReverseListFunc,
+		"rsadecrypt":         funcs.RsaDecryptFunc,
+		"semvercompare":      funcs.SemverCompareFunc,
+		"semverconstraint":   funcs.SemverConstraintFunc,
+		"sensitive":          funcs.SensitiveFunc,
+		"nonsensitive":       funcs.NonsensitiveFunc,
+		"issensitive":        funcs.IsSensitiveFunc,
+		"setintersection":    stdlib.SetIntersectionFunc,
+		"setproduct":         stdlib.SetProductFunc,
+		"setsubtract":        stdlib.SetSubtractFunc,
+		"setunion":           stdlib.SetUnionFunc,
+		"sha1":               funcs.Sha1Func,
+		"sha256":             funcs.Sha256Func,
+		"sha512":             funcs.Sha512Func,
+		"signum":             stdlib.SignumFunc,
+		"slice":              stdlib.SliceFunc,
+		"sort":               stdlib.SortFunc,
+		"split":              stdlib.SplitFunc,
+		"startswith":         funcs.StartsWithFunc,
+		"strcontains":        funcs.StrContainsFunc,
+		"strrev":             stdlib.ReverseFunc,
+		"substr":             stdlib.SubstrFunc,
+		"sum":                funcs.SumFunc,
+		"textdecodebase64":   funcs.TextDecodeBase64Func,
+		"textencodebase64":   funcs.TextEncodeBase64Func,
+		"timestamp":          funcs.TimestampFunc,
+		"timeadd":            stdlib.TimeAddFunc,
+		"timecmp":            funcs.TimeCmpFunc,
+		"title":              stdlib.TitleFunc,
+		"tostring":           funcs.MakeToFunc(cty.String),
+		"tonumber":           funcs.MakeToFunc(cty.Number),
+		"tobool":             funcs.MakeToFunc(cty.Bool),
+		"toset":              funcs.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+		"tolist":             funcs.MakeToFunc(cty.List(cty.DynamicPseudoType)),
+		"tomap":              funcs.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+		"tomldecode":         funcs.TOMLDecodeFunc,
+		"tomlencode":         funcs.TOMLEncodeFunc,
+		"transpose":          funcs.TransposeFunc,
+		"trim":               stdlib.TrimFunc,
+		"trimprefix":         stdlib.TrimPrefixFunc,
+		"trimspace":          stdlib.TrimSpaceFunc,
+		"trimsuffix":         stdlib.TrimSuffixFunc,
+		"try":                tryfunc.TryFunc,
+		"ulid":               funcs.ULIDFunc,
+		"upper":              stdlib.UpperFunc,
+		"urlbuild":           funcs.URLBuildFunc,
+		"urlencode":          funcs.URLEncodeFunc,
+		"urldecode":          funcs.URLDecodeFunc,
+		"urlparse":           funcs.URLParseFunc,
+		"uuid":               funcs.UUIDFunc,
+		"uuidv5":             funcs.UUIDV5Func,
+		"uuidv7":             funcs.UUIDV7Func,
+		"values":             stdlib.ValuesFunc,
+		"yamldecode":         ctyyaml.YAMLDecodeFunc,
+		"yamlencode":         ctyyaml.YAMLEncodeFunc,
+		"zipmap":             stdlib.ZipmapFunc,
 	}
 
 	ret["templatefile"] = funcs.MakeTemplateFileFunc(baseDir, func() map[string]function.Function {
@@ -213,6 +213,23 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidroverlaps": {
+			{
+				`cidroverlaps("10.0.0.0/16", "10.0.128.0/24")`,
+				cty.True,
+			},
+		},
+
+		"cidrsplit": {
+			{
+				`cidrsplit("10.0.0.0/23", 1)`,
+				cty.ListVal([]cty.Value{
+					cty.StringVal("10.0.0.0/24"),
+					cty.StringVal("10.0.1.0/24"),
+				}),
+			},
+		},
+
 		"cidrsubnet": {
 			{
 				`cidrsubnet("192.168.2.0/20", 4, 6)`,
@@ -313,6 +330,41 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"deepmerge": {
+			{
+				`deepmerge({a = {b = 1, c = [1]}}, {a = {d = 2, c = [2]}})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"b": cty.NumberIntVal(1),
+						"c": cty.TupleVal([]cty.Value{cty.NumberIntVal(2)}),
+						"d": cty.NumberIntVal(2),
+					}),
+				}),
+			},
+		},
+
+		"deepmergeconcat": {
+			{
+				`deepmergeconcat({a = {c = [1, 2]}}, {a = {c = [2, 3]}})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"c": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2), cty.NumberIntVal(2), cty.NumberIntVal(3)}),
+					}),
+				}),
+			},
+		},
+
+		"deepmergedistinct": {
+			{
+				`deepmergedistinct({a = {c = [1, 2]}}, {a = {c = [2, 3]}})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.ObjectVal(map[string]cty.Value{
+						"c": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2), cty.NumberIntVal(3)}),
+					}),
+				}),
+			},
+		},
+
 		"dirname": {
 			{
 				`dirname("testdata/hello.txt")`,
@@ -516,6 +568,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"hmac": {
+			{
+				`hmac("sha256", "key", "message")`,
+				cty.StringVal("6e9ef29b75fffc5b7abae527d58fdadb2fe42e7219011976917343065f58ed4a"),
+			},
+		},
+
 		"indent": {
 			{
 				fmt.Sprintf("indent(4, %#v)", Poem),
@@ -570,6 +629,31 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"jsonschemavalidate": {
+			{
+				`jsonschemavalidate("{\"type\": \"object\", \"required\": [\"name\"]}", {size = 1})`,
+				cty.ListVal([]cty.Value{cty.StringVal(`missing required property "name"`)}),
+			},
+			{
+				`jsonschemavalidate({type = "string"}, "a")`,
+				cty.ListValEmpty(cty.String),
+			},
+		},
+
+		"jwtdecode": {
+			{
+				`jwtdecode("eyJhbGciOiJub25lIn0.eyJzdWIiOiJhIn0.")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"header": cty.ObjectVal(map[string]cty.Value{
+						"alg": cty.StringVal("none"),
+					}),
+					"claims": cty.ObjectVal(map[string]cty.Value{
+						"sub": cty.StringVal("a"),
+					}),
+				}),
+			},
+		},
+
 		"keys": {
 			{
 				`keys({"hello"=1, "goodbye"=42})`,
@@ -761,6 +845,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"regexreplace": {
+			{
+				`regexreplace("2019-02-01", "(\\d+)-(\\d+)-(\\d+)", "$3/$2/$1")`,
+				cty.StringVal("01/02/2019"),
+			},
+		},
+
 		"replace": {
 			{
 				`replace("hello", "hel", "bel")`,
@@ -782,6 +873,20 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"semvercompare": {
+			{
+				`semvercompare("1.9.0", "1.10.0")`,
+				cty.NumberIntVal(-1),
+			},
+		},
+
+		"semverconstraint": {
+			{
+				`semverconstraint("1.10.0", "~> 1.9")`,
+				cty.True,
+			},
+		},
+
 		"sensitive": {
 			{
 				`sensitive(1)`,
@@ -1045,6 +1150,25 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"tomldecode": {
+			{
+				`tomldecode("a = 1\n[b]\nc = \"d\"\n")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.NumberIntVal(1),
+					"b": cty.ObjectVal(map[string]cty.Value{
+						"c": cty.StringVal("d"),
+					}),
+				}),
+			},
+		},
+
+		"tomlencode": {
+			{
+				`tomlencode({a = 1, b = {c = "d"}})`,
+				cty.StringVal("a = 1\n\n[b]\nc = \"d\"\n"),
+			},
+		},
+
 		"tonumber": {
 			{
 				`tonumber("42")`,
@@ -1138,6 +1262,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"urlbuild": {
+			{
+				`urlbuild({scheme = "https", host = "example.com", path = "/v1/items", query = {limit = 10}})`,
+				cty.StringVal("https://example.com/v1/items?limit=10"),
+			},
+		},
+
 		"urlencode": {
 			{
 				`urlencode("foo:bar@localhost?foo=bar&bar=baz")`,
@@ -1151,6 +1282,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"urlparse": {
+			{
+				`urlparse("https://example.com:8443/v1/items?limit=10").port`,
+				cty.NumberIntVal(8443),
+			},
+		},
+
 		"uuidv5": {
 			{
 				`uuidv5("dns", "tada")`,
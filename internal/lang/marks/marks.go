@@ -45,3 +45,8 @@ const Sensitive = valueMark("Sensitive")
 // another value's type. This is part of the implementation of the console-only
 // `type` function.
 const TypeType = valueMark("TypeType")
+
+// Ephemeral indicates that this value is derived from an ephemeral input
+// variable or output value, and so must not be persisted in state or plan
+// files.
+const Ephemeral = valueMark("Ephemeral")
@@ -7,6 +7,7 @@ package tofu
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 
 	"github.com/zclconf/go-cty/cty"
@@ -169,6 +170,28 @@ func (p *MockProvider) ValidateDataResourceConfig(r providers.ValidateDataResour
 	return p.ValidateDataResourceConfigResponse
 }
 
+// The legacy mock provider doesn't support ephemeral resources.
+
+func (p *MockProvider) ValidateEphemeralResourceConfig(r providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", r.TypeName))
+	return resp
+}
+
+func (p *MockProvider) OpenEphemeralResource(r providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", r.TypeName))
+	return resp
+}
+
+func (p *MockProvider) RenewEphemeralResource(r providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", r.TypeName))
+	return resp
+}
+
+func (p *MockProvider) CloseEphemeralResource(r providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", r.TypeName))
+	return resp
+}
+
 func (p *MockProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
 	p.Lock()
 	defer p.Unlock()
@@ -291,6 +314,10 @@ func (p *MockProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 	return p.ApplyResourceChangeResponse
 }
 
+func (p *MockProvider) ApplyResourceChanges(r providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+	panic("Not Implemented")
+}
+
 func (p *MockProvider) ImportResourceState(r providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
 	p.Lock()
 	defer p.Unlock()
@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// deprecatedRule reports the uses of deprecated language features that the
+// configuration loader warns about, such as setting a deprecated input
+// variable of a module or constraining the version of a provider in its
+// provider block, so that they are found by the same logic as in every
+// other command.
+var deprecatedRule = &Rule{
+	Name:        "deprecated",
+	Description: "Uses of deprecated language features",
+	check: func(m *Module) []Issue {
+		issues := make([]Issue, 0, len(m.Deprecations))
+		for _, diag := range m.Deprecations {
+			desc := diag.Description()
+			issue := Issue{
+				Summary: desc.Summary,
+				Detail:  desc.Detail,
+			}
+			if subject := diag.Source().Subject; subject != nil {
+				issue.Subject = subject.ToHCL()
+			} else {
+				issue.Subject = hcl.Range{Filename: m.Module.SourceDir}
+			}
+			issues = append(issues, issue)
+		}
+		return issues
+	},
+}
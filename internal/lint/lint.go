@@ -0,0 +1,174 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package lint implements the static checks of "tofu lint", which look for
+// likely mistakes and outdated patterns in a configuration that is otherwise
+// valid.
+//
+// The checks work on the configuration as decoded by package configs, and on
+// the syntax trees of the files it was loaded from, so that they always agree
+// with the language as OpenTofu itself understands it.
+package lint
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Rule is a check that is run against each module of a configuration.
+type Rule struct {
+	// Name identifies the rule in the output and in the -disable option of
+	// "tofu lint".
+	Name string
+
+	// Description is a one-line summary of what the rule reports.
+	Description string
+
+	check func(m *Module) []Issue
+}
+
+// Issue is a problem in a configuration found by a rule.
+type Issue struct {
+	// Rule is the name of the rule that found the issue.
+	Rule string
+
+	Summary string
+	Detail  string
+	Subject hcl.Range
+}
+
+// Diagnostic returns the issue as a warning diagnostic.
+func (i Issue) Diagnostic() tfdiags.Diagnostic {
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  i.Summary,
+		Detail:   i.Detail,
+		Subject:  i.Subject.Ptr(),
+	})
+	return diags[0]
+}
+
+// Module is a module of the configuration being checked, along with the
+// files it was loaded from.
+type Module struct {
+	*configs.Config
+
+	// Files are the configuration files of the module, in name order. Test
+	// files are not included.
+	Files []*hcl.File
+
+	// Deprecations are the warnings about the use of deprecated language
+	// features in the module that were produced while loading it.
+	Deprecations tfdiags.Diagnostics
+}
+
+// nativeBodies returns the bodies of the files of the module, or false if
+// any of them is written in the JSON syntax, since we can't find all of the
+// expressions in a JSON body without a schema.
+func (m *Module) nativeBodies() ([]*hclsyntax.Body, bool) {
+	bodies := make([]*hclsyntax.Body, 0, len(m.Files))
+	for _, f := range m.Files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			return nil, false
+		}
+		bodies = append(bodies, body)
+	}
+	return bodies, true
+}
+
+var rules = []*Rule{
+	childModuleProviderRule,
+	deprecatedRule,
+	shadowedNameRule,
+	unusedLocalRule,
+	unusedVariableRule,
+}
+
+// Rules returns all of the rules, in name order.
+func Rules() []*Rule {
+	return rules
+}
+
+// LookupRule returns the rule with the given name, or nil if there is none.
+func LookupRule(name string) *Rule {
+	for _, r := range rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Check runs the given rules against each module of the given configuration
+// and returns the issues they find, in source order.
+//
+// The sources are the files that the configuration was loaded from, as
+// returned by the Sources method of the configuration loader, and the
+// deprecations are the warnings about deprecated language features that the
+// loader produced, as reported by configs.IsDeprecationWarning.
+func Check(cfg *configs.Config, sources map[string]*hcl.File, deprecations tfdiags.Diagnostics, rules []*Rule) []Issue {
+	var modules []*Module
+	byFile := make(map[string]*Module)
+	cfg.DeepEach(func(c *configs.Config) {
+		m := &Module{Config: c}
+		modules = append(modules, m)
+
+		dir := filepath.Clean(c.Module.SourceDir)
+		for name, f := range sources {
+			if filepath.Dir(name) != dir || !configs.IsConfigFile(name) || configs.IsTestFile(name) {
+				continue
+			}
+			m.Files = append(m.Files, f)
+			byFile[name] = m
+		}
+		sort.Slice(m.Files, func(i, j int) bool {
+			return m.Files[i].Body.MissingItemRange().Filename < m.Files[j].Body.MissingItemRange().Filename
+		})
+	})
+
+	for _, diag := range deprecations {
+		m := modules[0]
+		if subject := diag.Source().Subject; subject != nil {
+			if owner, ok := byFile[subject.Filename]; ok {
+				m = owner
+			}
+		}
+		m.Deprecations = append(m.Deprecations, diag)
+	}
+
+	// A module that is called more than once appears in the configuration
+	// once for each call, but we only report each of its issues once.
+	var issues []Issue
+	seen := make(map[string]bool)
+	for _, m := range modules {
+		for _, r := range rules {
+			for _, issue := range r.check(m) {
+				issue.Rule = r.Name
+				key := issue.Rule + " " + issue.Subject.String() + " " + issue.Detail
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		si, sj := issues[i].Subject, issues[j].Subject
+		if si.Filename != sj.Filename {
+			return si.Filename < sj.Filename
+		}
+		return si.Start.Byte < sj.Start.Byte
+	})
+	return issues
+}
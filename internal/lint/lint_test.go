@@ -0,0 +1,81 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestCheck(t *testing.T) {
+	cfg, loader, cleanup, diags := initwd.LoadConfigForTests(t, "testdata/basic", "tests")
+	defer cleanup()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	var deprecations tfdiags.Diagnostics
+	for _, diag := range diags {
+		if configs.IsDeprecationWarning(diag) {
+			deprecations = append(deprecations, diag)
+		}
+	}
+
+	tests := map[string]struct {
+		Rules []*Rule
+		Want  []string
+	}{
+		"all rules": {
+			Rules(),
+			[]string{
+				"testdata/basic/child/main.tf:11,1-16: child_module_provider: Provider configuration in child module",
+				"testdata/basic/main.tf:5,1-18: unused_variable: Unused variable",
+				"testdata/basic/main.tf:16,3-20: unused_local: Unused local value",
+				"testdata/basic/main.tf:18,12-40: shadowed_name: Shadowed name",
+				"testdata/basic/main.tf:19,12-56: shadowed_name: Shadowed name",
+				"testdata/basic/main.tf:20,47-63: shadowed_name: Shadowed name",
+				"testdata/basic/main.tf:27,3-19: deprecated: Deprecated variable",
+				"testdata/basic/main.tf:33,5-20: shadowed_name: Shadowed name",
+				"testdata/basic/main.tf:44,15-21: shadowed_name: Shadowed name",
+			},
+		},
+		"some rules": {
+			[]*Rule{LookupRule("unused_local"), LookupRule("deprecated")},
+			[]string{
+				"testdata/basic/main.tf:16,3-20: unused_local: Unused local value",
+				"testdata/basic/main.tf:27,3-19: deprecated: Deprecated variable",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, issue := range Check(cfg, loader.Sources(), deprecations, test.Rules) {
+				got = append(got, fmt.Sprintf("%s: %s: %s", issue.Subject, issue.Rule, issue.Summary))
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong issues\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	for _, r := range Rules() {
+		if got := LookupRule(r.Name); got != r {
+			t.Errorf("wrong rule for %q: %#v", r.Name, got)
+		}
+	}
+	if got := LookupRule("nonexistent"); got != nil {
+		t.Errorf("unexpected rule for nonexistent name: %#v", got)
+	}
+}
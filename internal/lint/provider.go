@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// childModuleProviderRule reports the provider blocks of child modules that
+// have a configuration of their own, rather than receiving the configuration
+// from their caller. Such modules can't be called with count, for_each or
+// depends_on, and they can't be removed from the configuration while their
+// resources still exist.
+var childModuleProviderRule = &Rule{
+	Name:        "child_module_provider",
+	Description: "Provider configurations in child modules",
+	check: func(m *Module) []Issue {
+		if m.Parent == nil {
+			return nil
+		}
+
+		names := make([]string, 0, len(m.Module.ProviderConfigs))
+		for name := range m.Module.ProviderConfigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var issues []Issue
+		for _, name := range names {
+			pc := m.Module.ProviderConfigs[name]
+			// A provider block without any arguments only declares a
+			// configuration that the module expects its caller to pass in.
+			// This matches how the configuration loader decides whether a
+			// module is a legacy module.
+			if _, diags := pc.Config.Content(&hcl.BodySchema{}); !diags.HasErrors() && pc.Version.Required == nil {
+				continue
+			}
+			issues = append(issues, Issue{
+				Summary: "Provider configuration in child module",
+				Detail:  fmt.Sprintf("The module configures the provider %q itself. A module that contains provider configurations can't be called with count, for_each or depends_on, and it can't be removed from the configuration while its resources still exist. Declare the provider in the required_providers block of the module instead, and pass a configuration to the module from its caller with the providers argument.", pc.Addr().StringCompact()),
+				Subject: pc.DeclRange,
+			})
+		}
+		return issues
+	},
+}
@@ -0,0 +1,170 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// reservedRootNames are the names that start references to objects other
+// than resources, which a local symbol of the same name would hide.
+var reservedRootNames = map[string]bool{
+	"count":     true,
+	"data":      true,
+	"each":      true,
+	"ephemeral": true,
+	"local":     true,
+	"module":    true,
+	"path":      true,
+	"resource":  true,
+	"self":      true,
+	"terraform": true,
+	"tofu":      true,
+	"var":       true,
+}
+
+// shadowedNameRule reports the symbols of for expressions and the iterators
+// of dynamic blocks whose names hide a reference that would otherwise be
+// available in the same place: a built-in object like count or var, a
+// resource type of the module, or a symbol of an enclosing for expression
+// or dynamic block.
+var shadowedNameRule = &Rule{
+	Name:        "shadowed_name",
+	Description: "Symbols that hide other objects of the same name",
+	check: func(m *Module) []Issue {
+		bodies, ok := m.nativeBodies()
+		if !ok {
+			return nil
+		}
+		w := &shadowWalker{
+			resourceTypes: make(map[string]bool),
+		}
+		for _, r := range m.Module.ManagedResources {
+			w.resourceTypes[r.Type] = true
+		}
+		for _, body := range bodies {
+			hclsyntax.Walk(body, w)
+		}
+		return w.issues
+	},
+}
+
+// shadowScope is the set of symbols that a for expression or dynamic block
+// declares for its nested expressions.
+type shadowScope struct {
+	names []string
+	what  string
+
+	// outside is the range of the expression that is evaluated outside of
+	// the scope, even though it's nested inside it: the collection of a for
+	// expression, or the for_each argument of a dynamic block.
+	outside hcl.Range
+}
+
+type shadowWalker struct {
+	resourceTypes map[string]bool
+	scopes        []shadowScope
+	issues        []Issue
+}
+
+func (w *shadowWalker) Enter(node hclsyntax.Node) hcl.Diagnostics {
+	switch node := node.(type) {
+	case *hclsyntax.ForExpr:
+		scope := shadowScope{
+			what:    "for expression",
+			outside: node.CollExpr.Range(),
+		}
+		for _, name := range []string{node.KeyVar, node.ValVar} {
+			if name == "" {
+				continue
+			}
+			w.checkName(name, scope.what, node.Range(), node.SrcRange)
+			scope.names = append(scope.names, name)
+		}
+		w.scopes = append(w.scopes, scope)
+	case *hclsyntax.Block:
+		if node.Type != "dynamic" {
+			return nil
+		}
+		scope := shadowScope{what: "dynamic block"}
+		if attr, ok := node.Body.Attributes["for_each"]; ok {
+			scope.outside = attr.Expr.Range()
+		}
+		if len(node.Labels) == 1 {
+			name, subject := node.Labels[0], node.LabelRanges[0]
+			if attr, ok := node.Body.Attributes["iterator"]; ok {
+				name, subject = hcl.ExprAsKeyword(attr.Expr), attr.SrcRange
+			}
+			if name != "" {
+				w.checkName(name, scope.what, node.Range(), subject)
+				scope.names = append(scope.names, name)
+			}
+		}
+		w.scopes = append(w.scopes, scope)
+	}
+	return nil
+}
+
+func (w *shadowWalker) Exit(node hclsyntax.Node) hcl.Diagnostics {
+	switch node := node.(type) {
+	case *hclsyntax.ForExpr:
+		w.scopes = w.scopes[:len(w.scopes)-1]
+	case *hclsyntax.Block:
+		if node.Type == "dynamic" {
+			w.scopes = w.scopes[:len(w.scopes)-1]
+		}
+	}
+	return nil
+}
+
+// checkName records an issue if the given symbol name, declared by the
+// construct described by what at rng, hides another object.
+func (w *shadowWalker) checkName(name, what string, rng, subject hcl.Range) {
+	var detail string
+	switch {
+	case reservedRootNames[name]:
+		detail = fmt.Sprintf("The %s declares a symbol named %q, so references to the built-in %q object can't be used inside it.", what, name, name)
+	case w.resourceTypes[name]:
+		detail = fmt.Sprintf("The %s declares a symbol named %q, so the resources of type %q can't be referred to inside it.", what, name, name)
+	default:
+		for i := len(w.scopes) - 1; i >= 0; i-- {
+			scope := w.scopes[i]
+			if rangeWithin(rng, scope.outside) || !scopeDeclares(scope, name) {
+				continue
+			}
+			detail = fmt.Sprintf("The %s declares a symbol named %q, which is also the name of a symbol of an enclosing %s, so that symbol can't be referred to inside it.", what, name, scope.what)
+			break
+		}
+	}
+	if detail == "" {
+		return
+	}
+	w.issues = append(w.issues, Issue{
+		Summary: "Shadowed name",
+		Detail:  detail + " Choose a different name to make it clear which object each reference refers to.",
+		Subject: subject,
+	})
+}
+
+func scopeDeclares(scope shadowScope, name string) bool {
+	for _, n := range scope.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeWithin returns true if inner lies entirely within outer.
+func rangeWithin(inner, outer hcl.Range) bool {
+	return inner.Filename == outer.Filename &&
+		inner.Start.Byte >= outer.Start.Byte &&
+		inner.End.Byte <= outer.End.Byte &&
+		outer.End.Byte > outer.Start.Byte
+}
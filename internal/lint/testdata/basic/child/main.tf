@@ -0,0 +1,21 @@
+variable "old" {
+  type       = string
+  deprecated = "Use new instead."
+}
+
+variable "new" {
+  type    = string
+  default = ""
+}
+
+provider "test" {
+  region = "us-east-1"
+}
+
+resource "test_instance" "b" {
+  ami = coalesce(var.new, var.old)
+}
+
+output "id" {
+  value = test_instance.b.id
+}
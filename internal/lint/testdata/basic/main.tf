@@ -0,0 +1,57 @@
+variable "used" {
+  type = string
+}
+
+variable "unused" {
+  type = string
+
+  validation {
+    condition     = length(var.unused) > 0
+    error_message = "Must not be empty."
+  }
+}
+
+locals {
+  used   = upper(var.used)
+  unused = "unused"
+
+  counts = [for count in ["a"] : count]
+  types  = [for test_instance in ["a"] : test_instance]
+  pairs  = { for k, v in { a = ["b"] } : k => [for k in v : k] }
+  nested = [for s in [for s in ["a"] : s] : s]
+}
+
+module "child" {
+  source = "./child"
+
+  old = local.used
+}
+
+resource "test_instance" "a" {
+  dynamic "setting" {
+    for_each = local.pairs
+    iterator = each
+
+    content {
+      name = each.key
+    }
+  }
+
+  dynamic "rule" {
+    for_each = local.nested
+
+    content {
+      dynamic "rule" {
+        for_each = rule.value
+
+        content {
+          name = rule.value
+        }
+      }
+    }
+  }
+}
+
+output "result" {
+  value = [local.counts, local.types, module.child.id]
+}
@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lint
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+var unusedVariableRule = &Rule{
+	Name:        "unused_variable",
+	Description: "Input variables that nothing refers to",
+	check: func(m *Module) []Issue {
+		refs, ok := m.references()
+		if !ok {
+			return nil
+		}
+		var issues []Issue
+		for name, v := range m.Module.Variables {
+			if refs["var."+name] {
+				continue
+			}
+			issues = append(issues, Issue{
+				Summary: "Unused variable",
+				Detail:  fmt.Sprintf("The input variable %q is declared, but nothing in its module refers to it, so its value has no effect.", name),
+				Subject: v.DeclRange,
+			})
+		}
+		return issues
+	},
+}
+
+var unusedLocalRule = &Rule{
+	Name:        "unused_local",
+	Description: "Local values that nothing refers to",
+	check: func(m *Module) []Issue {
+		refs, ok := m.references()
+		if !ok {
+			return nil
+		}
+		var issues []Issue
+		for name, l := range m.Module.Locals {
+			if refs["local."+name] {
+				continue
+			}
+			issues = append(issues, Issue{
+				Summary: "Unused local value",
+				Detail:  fmt.Sprintf("The local value %q is declared, but nothing in its module refers to it.", name),
+				Subject: l.DeclRange,
+			})
+		}
+		return issues
+	},
+}
+
+// references returns the input variables and local values that are referred
+// to in the module, like "var.name" and "local.name". It returns false if
+// the references can't all be found, because some of the files of the module
+// are written in the JSON syntax.
+//
+// A reference to an input variable from within its own declaration, such as
+// in a validation rule, doesn't count as a use of the variable.
+func (m *Module) references() (map[string]bool, bool) {
+	bodies, ok := m.nativeBodies()
+	if !ok {
+		return nil, false
+	}
+
+	refs := make(map[string]bool)
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			var self string
+			if block.Type == "variable" && len(block.Labels) == 1 {
+				self = "var." + block.Labels[0]
+			}
+			_ = hclsyntax.VisitAll(block.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+				expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+				if !ok || len(expr.Traversal) < 2 {
+					return nil
+				}
+				attr, ok := expr.Traversal[1].(hcl.TraverseAttr)
+				if !ok {
+					return nil
+				}
+				if ref := expr.Traversal.RootName() + "." + attr.Name; ref != self {
+					refs[ref] = true
+				}
+				return nil
+			})
+		}
+	}
+	return refs, true
+}
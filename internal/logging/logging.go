@@ -70,7 +70,7 @@ func RegisterSink(f *os.File) {
 
 	l.RegisterSink(hclog.NewSinkAdapter(&hclog.LoggerOptions{
 		Level:  hclog.Trace,
-		Output: f,
+		Output: RedactingWriter(f),
 	}))
 }
 
@@ -101,7 +101,7 @@ func newHCLogger(name string) hclog.Logger {
 	return hclog.NewInterceptLogger(&hclog.LoggerOptions{
 		Name:              name,
 		Level:             logLevel,
-		Output:            logOutput,
+		Output:            RedactingWriter(logOutput),
 		IndependentLevels: true,
 		JSONFormat:        json,
 	})
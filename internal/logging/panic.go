@@ -161,7 +161,7 @@ func (p *panicRecorder) registerPlugin(name string) func(string) {
 		}
 		count++
 
-		p.panics[name] = append(p.panics[name], line)
+		p.panics[name] = append(p.panics[name], Redact(line))
 	}
 }
 
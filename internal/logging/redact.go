@@ -0,0 +1,147 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces sensitive values in the logs.
+const redactedValue = "(sensitive value)"
+
+// minRedactLength is the length of the shortest value that we'll redact.
+// Shorter values, such as "true" in a sensitive boolean-like string, are too
+// likely to appear elsewhere in the logs for redacting them to be useful,
+// and are unlikely to be meaningful secrets.
+const minRedactLength = 6
+
+// redactor replaces the registered sensitive values in the logs.
+var redactor = &sensitiveValues{
+	values: make(map[string]struct{}),
+}
+
+// RegisterSensitiveValues registers values that must not appear in the logs,
+// such as the values of provider configuration arguments that are marked as
+// sensitive in the provider's schema.
+//
+// Any occurrence of a registered value is replaced in all subsequent log
+// output, including the output of provider plugins and the plugin panic
+// output shown after a crash. Values that were logged before they were
+// registered are not affected.
+func RegisterSensitiveValues(values ...string) {
+	redactor.register(values...)
+}
+
+// Redact returns the given string with any registered sensitive values
+// replaced.
+func Redact(s string) string {
+	return redactor.redact(s)
+}
+
+// RedactingWriter returns a writer that replaces any registered sensitive
+// values in what is written to it before writing it to the given writer.
+//
+// The replacement is done for each call to Write, so a sensitive value that
+// is split over multiple calls won't be redacted. This is suitable for
+// loggers, which write each log entry in a single call.
+func RedactingWriter(w io.Writer) io.Writer {
+	return redactingWriter{w: w}
+}
+
+type sensitiveValues struct {
+	mu       sync.RWMutex
+	values   map[string]struct{}
+	replacer *strings.Replacer
+}
+
+func (s *sensitiveValues) register(values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, v := range values {
+		if len(v) < minRedactLength {
+			continue
+		}
+		// Values also appear JSON-escaped in JSON logs and in the trace logs
+		// of plugin requests, so we redact both forms.
+		for _, form := range []string{v, jsonEscaped(v)} {
+			if _, exists := s.values[form]; !exists {
+				s.values[form] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return
+	}
+
+	// The replacer prefers earlier arguments when more than one value
+	// matches at the same position, so we put longer values first in case
+	// one sensitive value is a prefix of another.
+	sorted := make([]string, 0, len(s.values))
+	for v := range s.values {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	oldnew := make([]string, 0, len(sorted)*2)
+	for _, v := range sorted {
+		oldnew = append(oldnew, v, redactedValue)
+	}
+	s.replacer = strings.NewReplacer(oldnew...)
+}
+
+func (s *sensitiveValues) redact(str string) string {
+	replacer := s.currentReplacer()
+	if replacer == nil {
+		return str
+	}
+	return replacer.Replace(str)
+}
+
+// currentReplacer returns the replacer for the registered values, or nil if
+// no values are registered.
+func (s *sensitiveValues) currentReplacer() *strings.Replacer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replacer
+}
+
+// jsonEscaped returns the given string as it would appear within a JSON
+// string literal.
+func jsonEscaped(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return string(b[1 : len(b)-1])
+}
+
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (w redactingWriter) Write(p []byte) (int, error) {
+	replacer := redactor.currentReplacer()
+	if replacer == nil {
+		return w.w.Write(p)
+	}
+	if _, err := replacer.WriteString(w.w, string(p)); err != nil {
+		return 0, err
+	}
+	// We report the length of the original data, since that is what the
+	// caller asked us to write.
+	return len(p), nil
+}
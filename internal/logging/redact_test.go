@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSensitiveValuesRedact(t *testing.T) {
+	values := &sensitiveValues{values: make(map[string]struct{})}
+
+	if got, want := values.redact("token=hunter2"), "token=hunter2"; got != want {
+		t.Fatalf("wrong result before registering\ngot:  %s\nwant: %s", got, want)
+	}
+
+	values.register("hunter2", "hunter2-extended", `quo"ted-secret`, "short")
+
+	tests := map[string]string{
+		"token=hunter2":                                         "token=(sensitive value)",
+		"token=hunter2-extended":                                "token=(sensitive value)",
+		`{"password":"quo\"ted-secret"}`:                        `{"password":"(sensitive value)"}`,
+		`password=quo"ted-secret`:                               "password=(sensitive value)",
+		"values shorter than the minimum, like short, are kept": "values shorter than the minimum, like short, are kept",
+	}
+	for input, want := range tests {
+		if got := values.redact(input); got != want {
+			t.Errorf("wrong result for %q\ngot:  %s\nwant: %s", input, got, want)
+		}
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	RegisterSensitiveValues("TestRedactingWriter-secret")
+
+	var buf bytes.Buffer
+	w := RedactingWriter(&buf)
+	input := []byte("[TRACE] configuring with TestRedactingWriter-secret\n")
+	n, err := w.Write(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(input) {
+		t.Errorf("wrong length %d; want %d", n, len(input))
+	}
+	if got, want := buf.String(), "[TRACE] configuring with (sensitive value)\n"; got != want {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package longpath is a small helper package for working with deeply-nested
+// directories, such as those created when installing modules and providers,
+// on Windows.
+//
+// Most Windows APIs refuse paths longer than the historical MAX_PATH limit of
+// 260 characters unless they are written in the "extended-length" form with
+// a \\?\ prefix. The Go standard library adds that prefix itself when calling
+// those APIs, but other code that handles paths, such as libraries that
+// extract archives and external programs, doesn't. Windows also has
+// "directory junctions", which behave like symbolic links to directories but
+// aren't reported as symbolic links by the standard library.
+//
+// This package uses conditional compilation to select a different
+// implementation for Windows vs. all other platforms. On other platforms its
+// functions have no effect.
+package longpath
@@ -0,0 +1,26 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows
+// +build !windows
+
+package longpath
+
+// Fix returns the given path in a form that can be used with the operating
+// system APIs regardless of its length.
+//
+// Paths have no length limit on this platform, so Fix returns the given path
+// unchanged.
+func Fix(path string) string {
+	return path
+}
+
+// IsJunction returns true if the given path is a Windows directory junction.
+//
+// There are no directory junctions on this platform, so IsJunction always
+// returns false.
+func IsJunction(path string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build windows
+// +build windows
+
+package longpath
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// maxShortPath is the length from which a path must be written in the
+// extended-length form. MAX_PATH is 260, but a directory path must leave room
+// for an 8.3 filename within that, so the effective limit is 248.
+const maxShortPath = 248
+
+// Fix returns the given path in a form that can be used with the operating
+// system APIs regardless of its length.
+//
+// A path that is long enough to exceed the MAX_PATH limit is made absolute and
+// given the extended-length \\?\ prefix, or \\?\UNC\ for a network path.
+// Extended-length paths are not normalized by Windows, so the path is also
+// cleaned, and forward slashes are replaced with backslashes. Shorter paths
+// are returned unchanged, so that they still look familiar in messages.
+func Fix(path string) string {
+	if len(path) < maxShortPath || strings.HasPrefix(path, `\\?\`) || strings.HasPrefix(path, `\??\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		// We'll let the operation that uses the path report the problem.
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
+
+// IsJunction returns true if the given path is a Windows directory junction,
+// also known as a mount point, rather than a symbolic link or an ordinary
+// file or directory.
+//
+// Recent versions of the Go standard library report junctions as irregular
+// files, so callers that walk directory trees should check for junctions
+// among the irregular files they find, to avoid treating them as files.
+func IsJunction(path string) (bool, error) {
+	name, err := windows.UTF16PtrFromString(Fix(path))
+	if err != nil {
+		return false, err
+	}
+	var data windows.Win32finddata
+	h, err := windows.FindFirstFile(name, &data)
+	if err != nil {
+		return false, err
+	}
+	windows.FindClose(h)
+
+	// When a file is a reparse point, FindFirstFile returns its reparse
+	// tag in the Reserved0 field.
+	return data.FileAttributes&windows.FILE_ATTRIBUTE_REPARSE_POINT != 0 &&
+		data.Reserved0 == windows.IO_REPARSE_TAG_MOUNT_POINT, nil
+}
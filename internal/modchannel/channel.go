@@ -0,0 +1,252 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package modchannel contains the logic for reading and writing module
+// channel files.
+//
+// A channel is a named set of exact versions for registry modules, such as
+// "staging" or "prod", which is maintained separately from the configuration
+// in the file channels/NAME.json under the root module directory. Selecting
+// a channel during "tofu init" pins each registry module in the configuration
+// tree to the version recorded in the channel, which allows rolling out new
+// module versions one environment at a time by promoting them from one
+// channel to the next, without editing the version constraints in every
+// module block.
+package modchannel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/replacefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Dir is the name of the directory, relative to the root module directory,
+// that contains the channel files.
+const Dir = "channels"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ValidName returns true if the given string is acceptable as the name of a
+// channel, which must also be usable as part of a filename.
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Filename returns the path of the file for the channel with the given name,
+// relative to the given root module directory.
+func Filename(rootDir, name string) string {
+	return filepath.Join(rootDir, Dir, name+".json")
+}
+
+// Channel is the in-memory representation of a channel file.
+type Channel struct {
+	Name string
+
+	modules map[addrs.ModuleRegistryPackage]*version.Version
+}
+
+// New returns a new empty channel with the given name.
+func New(name string) *Channel {
+	return &Channel{
+		Name:    name,
+		modules: make(map[addrs.ModuleRegistryPackage]*version.Version),
+	}
+}
+
+// channelFile is the JSON serialization of a channel file.
+type channelFile struct {
+	Modules map[string]string `json:"modules"`
+}
+
+// Load reads the channel with the given name from the channels directory of
+// the given root module directory.
+//
+// If the file doesn't exist then Load returns error diagnostics, because
+// selecting a channel that doesn't exist is most likely a mistake. Use
+// LoadOrNew when a missing channel should be treated as an empty one.
+func Load(rootDir, name string) (*Channel, tfdiags.Diagnostics) {
+	ch, exists, diags := load(rootDir, name)
+	if !diags.HasErrors() && !exists {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module channel not found",
+			fmt.Sprintf("There is no file for the module channel %q at %s.", name, Filename(rootDir, name)),
+		))
+	}
+	return ch, diags
+}
+
+// LoadOrNew is like Load except that it returns a new empty channel if the
+// channel file doesn't exist yet.
+func LoadOrNew(rootDir, name string) (*Channel, tfdiags.Diagnostics) {
+	ch, _, diags := load(rootDir, name)
+	return ch, diags
+}
+
+func load(rootDir, name string) (*Channel, bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	ch := New(name)
+
+	if !ValidName(name) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid module channel name",
+			fmt.Sprintf("The name %q is not a valid module channel name. A channel name must start with a letter or digit, and can contain only letters, digits, underscores, and dashes.", name),
+		))
+		return ch, false, diags
+	}
+
+	filename := Filename(rootDir, name)
+	src, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ch, false, diags
+	} else if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read module channel file",
+			fmt.Sprintf("Could not read %s: %s.", filename, err),
+		))
+		return ch, true, diags
+	}
+
+	var raw channelFile
+	if err := json.Unmarshal(src, &raw); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid module channel file",
+			fmt.Sprintf("The file %s is not a valid module channel file: %s.", filename, err),
+		))
+		return ch, true, diags
+	}
+
+	for rawAddr, rawVersion := range raw.Modules {
+		addr, err := ParseModulePackage(rawAddr)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module channel file",
+				fmt.Sprintf("The file %s contains the invalid module address %q: %s.", filename, rawAddr, err),
+			))
+			continue
+		}
+		v, err := version.NewVersion(rawVersion)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module channel file",
+				fmt.Sprintf("The file %s contains the invalid version %q for module %s: %s.", filename, rawVersion, addr.ForDisplay(), err),
+			))
+			continue
+		}
+		if _, exists := ch.modules[addr]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module channel file",
+				fmt.Sprintf("The file %s contains more than one version for module %s.", filename, addr.ForDisplay()),
+			))
+			continue
+		}
+		ch.modules[addr] = v
+	}
+
+	return ch, true, diags
+}
+
+// Save writes the channel to its file in the channels directory of the given
+// root module directory, creating the directory if necessary.
+func (c *Channel) Save(rootDir string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	raw := channelFile{
+		Modules: make(map[string]string, len(c.modules)),
+	}
+	for addr, v := range c.modules {
+		raw.Modules[addr.String()] = v.String()
+	}
+	// encoding/json sorts the map keys, so the result is stable.
+	src, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		// Should never happen, because we're only encoding strings.
+		panic(fmt.Sprintf("failed to encode module channel: %s", err))
+	}
+	src = append(src, '\n')
+
+	filename := Filename(rootDir, c.Name)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write module channel file",
+			fmt.Sprintf("Could not create the directory for %s: %s.", filename, err),
+		))
+		return diags
+	}
+	if err := replacefile.AtomicWriteFile(filename, src, 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write module channel file",
+			fmt.Sprintf("Could not write %s: %s.", filename, err),
+		))
+	}
+	return diags
+}
+
+// ModuleVersion returns the version that the channel selects for the given
+// module package, or nil if the channel doesn't mention that package.
+//
+// A nil channel selects no versions at all.
+func (c *Channel) ModuleVersion(addr addrs.ModuleRegistryPackage) *version.Version {
+	if c == nil {
+		return nil
+	}
+	return c.modules[addr]
+}
+
+// SetModuleVersion records the version that the channel selects for the
+// given module package.
+func (c *Channel) SetModuleVersion(addr addrs.ModuleRegistryPackage, v *version.Version) {
+	c.modules[addr] = v
+}
+
+// Modules returns the addresses of all of the module packages that the
+// channel selects a version for, in lexical order.
+func (c *Channel) Modules() []addrs.ModuleRegistryPackage {
+	if c == nil {
+		return nil
+	}
+	ret := make([]addrs.ModuleRegistryPackage, 0, len(c.modules))
+	for addr := range c.modules {
+		ret = append(ret, addr)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+// ParseModulePackage parses the given string as the address of a module
+// package in a module registry, in the same syntax as the "source" argument
+// of a module block but without a sub-directory portion.
+func ParseModulePackage(raw string) (addrs.ModuleRegistryPackage, error) {
+	src, err := addrs.ParseModuleSourceRegistry(raw)
+	if err != nil {
+		return addrs.ModuleRegistryPackage{}, err
+	}
+	addr := src.(addrs.ModuleSourceRegistry)
+	if addr.Subdir != "" {
+		return addrs.ModuleRegistryPackage{}, fmt.Errorf("a module channel selects versions for whole module packages, so the address must not include a sub-directory")
+	}
+	return addr.Package, nil
+}
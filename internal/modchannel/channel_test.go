@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package modchannel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		wantErr string
+	}{
+		"valid": {
+			src: `{"modules": {"hashicorp/consul/aws": "0.11.0"}}`,
+		},
+		"invalid JSON": {
+			src:     `{"modules": [}`,
+			wantErr: "is not a valid module channel file",
+		},
+		"invalid address": {
+			src:     `{"modules": {"./consul": "0.11.0"}}`,
+			wantErr: `contains the invalid module address "./consul"`,
+		},
+		"sub-directory": {
+			src:     `{"modules": {"hashicorp/consul/aws//modules/server": "0.11.0"}}`,
+			wantErr: "must not include a sub-directory",
+		},
+		"invalid version": {
+			src:     `{"modules": {"hashicorp/consul/aws": "latest"}}`,
+			wantErr: `contains the invalid version "latest"`,
+		},
+		"duplicate": {
+			src:     `{"modules": {"hashicorp/consul/aws": "0.11.0", "registry.opentofu.org/hashicorp/consul/aws": "0.10.0"}}`,
+			wantErr: "contains more than one version",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, Dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(Filename(dir, "prod"), []byte(test.src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			ch, diags := Load(dir, "prod")
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("expected error")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+
+			addr, err := ParseModulePackage("registry.opentofu.org/hashicorp/consul/aws")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ch.ModuleVersion(addr); got == nil || got.String() != "0.11.0" {
+				t.Errorf("wrong version %s; want 0.11.0", got)
+			}
+		})
+	}
+}
+
+func TestLoad_notFound(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, diags := Load(dir, "prod"); !diags.HasErrors() {
+		t.Error("Load succeeded for a missing channel")
+	}
+
+	ch, diags := LoadOrNew(dir, "prod")
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if len(ch.Modules()) != 0 {
+		t.Errorf("new channel is not empty: %s", ch.Modules())
+	}
+}
+
+func TestLoad_invalidName(t *testing.T) {
+	if _, diags := LoadOrNew(t.TempDir(), "../prod"); !diags.HasErrors() {
+		t.Error("LoadOrNew succeeded for an invalid channel name")
+	}
+}
+
+func TestChannelSave(t *testing.T) {
+	dir := t.TempDir()
+
+	ch := New("prod")
+	for raw, v := range map[string]string{
+		"hashicorp/vpc/aws":                 "1.1.0",
+		"example.com/network/subnets/azure": "2.0.0-beta1",
+	} {
+		addr, err := ParseModulePackage(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch.SetModuleVersion(addr, version.Must(version.NewVersion(v)))
+	}
+	if diags := ch.Save(dir); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	got, err := os.ReadFile(Filename(dir, "prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "modules": {
+    "example.com/network/subnets/azure": "2.0.0-beta1",
+    "registry.opentofu.org/hashicorp/vpc/aws": "1.1.0"
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("wrong file content\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	loaded, diags := Load(dir, "prod")
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if got, want := len(loaded.Modules()), 2; got != want {
+		t.Errorf("wrong number of modules after reload %d; want %d", got, want)
+	}
+}
@@ -0,0 +1,505 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package explain traces the values in a plan that are unknown until apply
+// back through the configuration, to explain which expressions and upstream
+// resources caused them to be unknown.
+package explain
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// Explanation describes why a value is unknown until apply.
+//
+// An explanation either has causes, which are explanations of the unknown
+// values that the value's expression refers to, or a reason, which explains
+// why the value is unknown when that isn't because of other values.
+type Explanation struct {
+	// Subject describes the unknown value, such as "aws_instance.a.ami" or
+	// "local.name".
+	Subject string
+
+	// Reason explains why the value is unknown, if it isn't unknown only
+	// because of its causes.
+	Reason string
+
+	// Range is the source range of the expression or reference that the
+	// explanation is about, if any.
+	Range *tfdiags.SourceRange
+
+	// Causes are the explanations of the unknown values that the value's
+	// expression refers to.
+	Causes []*Explanation
+}
+
+// Plan returns explanations for the unknown values in the given plan that
+// are caused by other values in the configuration, and for the errors in the
+// given diagnostics that were caused by unknown values, such as a for_each
+// argument that is unknown during planning.
+//
+// Values are traced through references between static configuration
+// objects: a reference to a resource or module output is considered
+// unknown if any instance of that object has an unknown value at the
+// referenced path in the plan. Values that are unknown only because the
+// provider decides them during apply, such as an identifier assigned to a
+// new object, are explained only when another value refers to them.
+func Plan(config *configs.Config, plan *plans.Plan, schemas *tofu.Schemas, diags tfdiags.Diagnostics) []*Explanation {
+	if config == nil || plan == nil || plan.Changes == nil {
+		return nil
+	}
+
+	e := &explainer{
+		config:   config,
+		changes:  make(map[string][]*plans.ResourceInstanceChange),
+		visiting: make(map[string]bool),
+	}
+	var changes []*plans.ResourceInstanceChange
+	for _, rcs := range plan.Changes.Resources {
+		addr := rcs.Addr.Resource.Resource
+		schema, _ := schemas.ResourceTypeConfig(rcs.ProviderAddr.Provider, addr.Mode, addr.Type)
+		if schema == nil {
+			log.Printf("[WARN] explain: no schema for %s, so its unknown values can't be explained", rcs.Addr)
+			continue
+		}
+		rc, err := rcs.Decode(schema.ImpliedType())
+		if err != nil {
+			log.Printf("[WARN] explain: failed to decode planned change for %s: %s", rcs.Addr, err)
+			continue
+		}
+		rc.After, _ = rc.After.UnmarkDeep()
+		key := rc.Addr.ConfigResource().String()
+		e.changes[key] = append(e.changes[key], rc)
+		changes = append(changes, rc)
+	}
+
+	var ret []*Explanation
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Error || !tfdiags.DiagnosticCausedByUnknown(diag) {
+			continue
+		}
+		if explanation := e.diagnostic(diag); explanation != nil {
+			ret = append(ret, explanation)
+		}
+	}
+	for _, rc := range changes {
+		ret = append(ret, e.resourceChange(rc)...)
+	}
+	return ret
+}
+
+type explainer struct {
+	config *configs.Config
+
+	// changes are the decoded planned changes, indexed by the string form
+	// of the static address of their resource.
+	changes map[string][]*plans.ResourceInstanceChange
+
+	// visiting records the references that are currently being explained,
+	// to avoid infinite recursion on configurations with reference cycles,
+	// which are invalid but might appear in a plan that has errors.
+	visiting map[string]bool
+}
+
+// diagnostic explains the expression that caused the given diagnostic, if
+// it can be found in the configuration.
+func (e *explainer) diagnostic(diag tfdiags.Diagnostic) *Explanation {
+	subject := diag.Source().Subject
+	if subject == nil {
+		return nil
+	}
+
+	mod := e.moduleForFile(subject.Filename)
+	if mod == nil {
+		return nil
+	}
+	name, expr, forEach := metaArgumentAt(mod, *subject)
+	if expr == nil {
+		// Not all diagnostics are about meta-arguments, but those that are
+		// caused by unknown values usually record the expression.
+		fromExpr := diag.FromExpr()
+		if fromExpr == nil {
+			return nil
+		}
+		name, expr = diag.Description().Summary, fromExpr.Expression
+	}
+
+	causes := e.exprCauses(mod, expr, forEach)
+	if len(causes) == 0 {
+		return nil
+	}
+	return &Explanation{
+		Subject: name,
+		Range:   subject,
+		Causes:  causes,
+	}
+}
+
+// resourceChange explains the unknown values of the configured arguments of
+// the given planned change.
+func (e *explainer) resourceChange(rc *plans.ResourceInstanceChange) []*Explanation {
+	switch rc.Action {
+	case plans.Create, plans.Update, plans.DeleteThenCreate, plans.CreateThenDelete, plans.Read:
+	default:
+		return nil
+	}
+	if rc.After.IsNull() || rc.After.IsWhollyKnown() {
+		return nil
+	}
+
+	mod := e.config.Descendent(rc.Addr.Module.Module())
+	if mod == nil {
+		return nil
+	}
+	rcfg := mod.Module.ResourceByAddr(rc.Addr.Resource.Resource)
+	if rcfg == nil {
+		return nil
+	}
+
+	var ret []*Explanation
+	attrs := configAttributes(rcfg.Config)
+	for _, name := range sortedAttributeNames(attrs) {
+		if !rc.After.Type().IsObjectType() || !rc.After.Type().HasAttribute(name) {
+			continue
+		}
+		if rc.After.GetAttr(name).IsWhollyKnown() {
+			continue
+		}
+		expr := attrs[name].Expr
+		causes := e.exprCauses(mod, expr, rcfg.ForEach)
+		if len(causes) == 0 {
+			continue
+		}
+		rng := tfdiags.SourceRangeFromHCL(expr.Range())
+		ret = append(ret, &Explanation{
+			Subject: fmt.Sprintf("%s.%s", rc.Addr, name),
+			Range:   &rng,
+			Causes:  causes,
+		})
+	}
+	return ret
+}
+
+// exprCauses returns explanations for the unknown values that the given
+// expression in the given module refers to. forEach is the for_each
+// expression of the block containing the expression, if any, which
+// references to each.key and each.value are traced through.
+func (e *explainer) exprCauses(mod *configs.Config, expr hcl.Expression, forEach hcl.Expression) []*Explanation {
+	refs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+
+	var ret []*Explanation
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		key := ref.DisplayString()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if cause := e.refCause(mod, ref, forEach); cause != nil {
+			ret = append(ret, cause)
+		}
+	}
+	return ret
+}
+
+// refCause returns an explanation of why the value of the given reference
+// is unknown, or nil if it isn't known to be unknown.
+func (e *explainer) refCause(mod *configs.Config, ref *addrs.Reference, forEach hcl.Expression) *Explanation {
+	subject := ref.DisplayString()
+	key := mod.Path.String() + "\x00" + subject
+	if e.visiting[key] {
+		return nil
+	}
+	e.visiting[key] = true
+	defer delete(e.visiting, key)
+
+	rng := ref.SourceRange
+	withCauses := func(causes []*Explanation) *Explanation {
+		if len(causes) == 0 {
+			return nil
+		}
+		return &Explanation{Subject: subject, Range: &rng, Causes: causes}
+	}
+
+	switch addr := ref.Subject.(type) {
+	case addrs.Resource:
+		return e.resourceCause(mod, addr, nil, ref)
+
+	case addrs.ResourceInstance:
+		return e.resourceCause(mod, addr.Resource, addr.Key, ref)
+
+	case addrs.LocalValue:
+		local := mod.Module.Locals[addr.Name]
+		if local == nil {
+			return nil
+		}
+		return withCauses(e.exprCauses(mod, local.Expr, nil))
+
+	case addrs.InputVariable:
+		// Root module variables are always known during planning, so only
+		// the variables of child modules can be unknown, because of the
+		// arguments in their module call.
+		if mod.Parent == nil {
+			return nil
+		}
+		call := mod.Parent.Module.ModuleCalls[mod.Path[len(mod.Path)-1]]
+		if call == nil {
+			return nil
+		}
+		attr := configAttributes(call.Config)[addr.Name]
+		if attr == nil {
+			return nil
+		}
+		return withCauses(e.exprCauses(mod.Parent, attr.Expr, call.ForEach))
+
+	case addrs.ModuleCallInstanceOutput:
+		return withCauses(e.outputCauses(mod, addr.Call.Call.Name, addr.Name))
+
+	case addrs.ModuleCall:
+		return withCauses(e.outputCauses(mod, addr.Name, firstAttrName(ref.Remaining)))
+
+	case addrs.ModuleCallInstance:
+		return withCauses(e.outputCauses(mod, addr.Call.Name, firstAttrName(ref.Remaining)))
+
+	case addrs.ForEachAttr:
+		if forEach == nil {
+			return nil
+		}
+		return withCauses(e.exprCauses(mod, forEach, nil))
+	}
+
+	return nil
+}
+
+// outputCauses returns explanations for the unknown values that the given
+// output of the given module call refers to, or all of its outputs if
+// outputName is empty.
+func (e *explainer) outputCauses(mod *configs.Config, callName, outputName string) []*Explanation {
+	child := mod.Children[callName]
+	if child == nil {
+		return nil
+	}
+
+	if outputName != "" {
+		output := child.Module.Outputs[outputName]
+		if output == nil {
+			return nil
+		}
+		return e.exprCauses(child, output.Expr, nil)
+	}
+
+	names := make([]string, 0, len(child.Module.Outputs))
+	for name := range child.Module.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ret []*Explanation
+	for _, name := range names {
+		output := child.Module.Outputs[name]
+		causes := e.exprCauses(child, output.Expr, nil)
+		if len(causes) == 0 {
+			continue
+		}
+		rng := tfdiags.SourceRangeFromHCL(output.Expr.Range())
+		ret = append(ret, &Explanation{
+			Subject: fmt.Sprintf("module.%s.%s", callName, name),
+			Range:   &rng,
+			Causes:  causes,
+		})
+	}
+	return ret
+}
+
+// resourceCause explains why the value at the given reference to a resource
+// is unknown, using the first planned instance of the resource that has an
+// unknown value there. If key is nil then all instances are considered.
+func (e *explainer) resourceCause(mod *configs.Config, res addrs.Resource, key addrs.InstanceKey, ref *addrs.Reference) *Explanation {
+	var rc *plans.ResourceInstanceChange
+	for _, candidate := range e.changes[res.InModule(mod.Path).String()] {
+		if key != nil && candidate.Addr.Resource.Key != key {
+			continue
+		}
+		if traverse(candidate.After, ref.Remaining).IsWhollyKnown() {
+			continue
+		}
+		rc = candidate
+		break
+	}
+	if rc == nil {
+		return nil
+	}
+
+	rng := ref.SourceRange
+	ret := &Explanation{
+		Subject: ref.DisplayString(),
+		Range:   &rng,
+	}
+
+	attrName := firstAttrName(ref.Remaining)
+	if attrName == "" {
+		ret.Reason = fmt.Sprintf("%s will be %s, so some of its attributes will be known only after apply", rc.Addr, actionVerb(rc.Action))
+		return ret
+	}
+
+	rcfg := mod.Module.ResourceByAddr(res)
+	if rcfg == nil {
+		ret.Reason = fmt.Sprintf("%s will be %s", rc.Addr, actionVerb(rc.Action))
+		return ret
+	}
+	attr := configAttributes(rcfg.Config)[attrName]
+	if attr == nil {
+		ret.Reason = fmt.Sprintf("%s will be %s, and the provider decides its %s during apply", rc.Addr, actionVerb(rc.Action), attrName)
+		return ret
+	}
+
+	ret.Causes = e.exprCauses(mod, attr.Expr, rcfg.ForEach)
+	if len(ret.Causes) == 0 {
+		ret.Reason = fmt.Sprintf("the provider reported that %s.%s will be known only after apply", rc.Addr, attrName)
+	}
+	return ret
+}
+
+// moduleForFile returns the first module in the configuration whose source
+// directory contains the given file.
+func (e *explainer) moduleForFile(filename string) *configs.Config {
+	dir := filepath.Clean(filepath.Dir(filename))
+
+	var ret *configs.Config
+	e.config.DeepEach(func(c *configs.Config) {
+		if ret == nil && c.Module != nil && filepath.Clean(c.Module.SourceDir) == dir {
+			ret = c
+		}
+	})
+	return ret
+}
+
+// metaArgumentAt returns a description and the expression of the count or
+// for_each argument of a resource or module call in the given module whose
+// expression has the given range, along with the for_each expression of the
+// block that contains it.
+func metaArgumentAt(mod *configs.Config, rng tfdiags.SourceRange) (string, hcl.Expression, hcl.Expression) {
+	matches := func(expr hcl.Expression) bool {
+		if expr == nil {
+			return false
+		}
+		exprRng := expr.Range()
+		return exprRng.Filename == rng.Filename && exprRng.Start.Byte == rng.Start.Byte && exprRng.End.Byte == rng.End.Byte
+	}
+
+	var resources []*configs.Resource
+	for _, r := range mod.Module.ManagedResources {
+		resources = append(resources, r)
+	}
+	for _, r := range mod.Module.DataResources {
+		resources = append(resources, r)
+	}
+	for _, r := range mod.Module.EphemeralResources {
+		resources = append(resources, r)
+	}
+	for _, r := range resources {
+		addr := r.Addr().InModule(mod.Path)
+		if matches(r.Count) {
+			return fmt.Sprintf("The count argument of %s", addr), r.Count, nil
+		}
+		if matches(r.ForEach) {
+			return fmt.Sprintf("The for_each argument of %s", addr), r.ForEach, nil
+		}
+	}
+	for _, call := range mod.Module.ModuleCalls {
+		addr := mod.Path.Child(call.Name)
+		if matches(call.Count) {
+			return fmt.Sprintf("The count argument of %s", addr), call.Count, nil
+		}
+		if matches(call.ForEach) {
+			return fmt.Sprintf("The for_each argument of %s", addr), call.ForEach, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// configAttributes returns the attributes of the given body, ignoring any
+// nested blocks.
+func configAttributes(body hcl.Body) hcl.Attributes {
+	if body == nil {
+		return nil
+	}
+	// JustAttributes returns an error if the body has nested blocks, but
+	// still returns the attributes it found, which is all we need.
+	attrs, _ := body.JustAttributes()
+	return attrs
+}
+
+func sortedAttributeNames(attrs hcl.Attributes) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firstAttrName returns the name of the first attribute step of the given
+// traversal, skipping any index steps before it, or an empty string if
+// there is none.
+func firstAttrName(traversal hcl.Traversal) string {
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseAttr:
+			return step.Name
+		case hcl.TraverseIndex:
+			continue
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// traverse returns the value at the given traversal from the given value,
+// stopping early at an unknown or null value, or at a step that can't be
+// applied.
+func traverse(val cty.Value, traversal hcl.Traversal) cty.Value {
+	for _, step := range traversal {
+		if !val.IsKnown() || val.IsNull() {
+			return val
+		}
+		next, diags := step.TraversalStep(val)
+		if diags.HasErrors() {
+			return val
+		}
+		val = next
+	}
+	return val
+}
+
+func actionVerb(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "created"
+	case plans.Update:
+		return "updated"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replaced"
+	case plans.Read:
+		return "read during apply"
+	default:
+		return "changed"
+	}
+}
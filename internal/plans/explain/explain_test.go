@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package explain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestPlan(t *testing.T) {
+	tests := map[string]struct {
+		fixture string
+		want    string
+	}{
+		"chain": {
+			fixture: "testdata/chain",
+			want: `
+test_instance.b.value (testdata/chain/main.tf:14,11)
+  module.child.upper (testdata/chain/main.tf:14,11)
+    var.name (testdata/chain/child/main.tf:6,17)
+      local.name (testdata/chain/main.tf:10,12)
+        test_instance.a.id (testdata/chain/main.tf:5,20): test_instance.a will be created, and the provider decides its id during apply
+`,
+		},
+		"for_each": {
+			fixture: "testdata/for-each",
+			want: `
+The for_each argument of test_instance.b (testdata/for-each/main.tf:5,14)
+  test_instance.a.id (testdata/for-each/main.tf:5,21): test_instance.a will be created, and the provider decides its id during apply
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, _, cleanup, diags := initwd.LoadConfigForTests(t, test.fixture, "tests")
+			defer cleanup()
+			if diags.HasErrors() {
+				t.Fatalf("unexpected problems loading config: %s", diags.Err())
+			}
+
+			p := &tofu.MockProvider{}
+			p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"id":    {Type: cty.String, Computed: true},
+								"value": {Type: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			}
+			ctx, diags := tofu.NewContext(&tofu.ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): providers.FactoryFixed(p),
+				},
+			})
+			if diags.HasErrors() {
+				t.Fatalf("failed to create context: %s", diags.Err())
+			}
+
+			state := states.NewState()
+			plan, planDiags := ctx.Plan(context.Background(), config, state, &tofu.PlanOpts{Mode: plans.NormalMode})
+			schemas, diags := ctx.Schemas(config, state)
+			if diags.HasErrors() {
+				t.Fatalf("failed to get schemas: %s", diags.Err())
+			}
+
+			var buf strings.Builder
+			buf.WriteString("\n")
+			for _, explanation := range Plan(config, plan, schemas, planDiags) {
+				writeExplanation(&buf, explanation, "")
+			}
+			if diff := cmp.Diff(test.want, buf.String()); diff != "" {
+				t.Errorf("wrong explanations\n%s", diff)
+			}
+		})
+	}
+}
+
+func writeExplanation(buf *strings.Builder, explanation *Explanation, indent string) {
+	fmt.Fprintf(buf, "%s%s", indent, explanation.Subject)
+	if explanation.Range != nil {
+		fmt.Fprintf(buf, " (%s)", explanation.Range.StartString())
+	}
+	if explanation.Reason != "" {
+		fmt.Fprintf(buf, ": %s", explanation.Reason)
+	}
+	buf.WriteString("\n")
+	for _, cause := range explanation.Causes {
+		writeExplanation(buf, cause, indent+"  ")
+	}
+}
@@ -0,0 +1,19 @@
+resource "test_instance" "a" {
+}
+
+locals {
+  name = "prefix-${test_instance.a.id}"
+}
+
+module "child" {
+  source = "./child"
+  name   = local.name
+}
+
+resource "test_instance" "b" {
+  value = module.child.upper
+}
+
+resource "test_instance" "known" {
+  value = "known"
+}
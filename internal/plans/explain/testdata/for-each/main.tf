@@ -0,0 +1,7 @@
+resource "test_instance" "a" {
+}
+
+resource "test_instance" "b" {
+  for_each = toset([test_instance.a.id])
+  value    = each.key
+}
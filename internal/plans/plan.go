@@ -42,7 +42,14 @@ type Plan struct {
 	// checked carefully against existing destroy behaviors.
 	UIMode Mode
 
-	VariableValues    map[string]DynamicValue
+	VariableValues map[string]DynamicValue
+
+	// EphemeralVariableValues are the values of the ephemeral root module
+	// input variables that were set for this plan. Unlike VariableValues,
+	// they are never written to plan files, so they must be set again when
+	// applying a saved plan.
+	EphemeralVariableValues map[string]DynamicValue
+
 	Changes           *Changes
 	DriftedResources  []*ResourceInstanceChangeSrc
 	TargetAddrs       []addrs.Targetable
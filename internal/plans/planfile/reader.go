@@ -8,6 +8,9 @@ package planfile
 import (
 	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -51,7 +54,15 @@ func (e *ErrUnusableLocalPlan) Unwrap() error {
 // be used to access the individual portions of the file for further
 // processing.
 type Reader struct {
-	zip *zip.Reader
+	zip  *zip.Reader
+	hash string
+}
+
+// Hash returns a hash of the given raw plan file content, as it is stored
+// on disk, which is suitable for recognizing a particular saved plan.
+func Hash(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
 }
 
 // Open creates a Reader for the file at the given filename, or returns an error
@@ -64,7 +75,7 @@ func Open(filename string, enc encryption.PlanEncryption) (*Reader, error) {
 		return nil, err
 	}
 
-	decrypted, diags := enc.DecryptPlan(raw)
+	decrypted, diags := enc.DecryptPlan(context.TODO(), raw)
 	if diags != nil {
 		return nil, diags
 	}
@@ -104,10 +115,17 @@ func Open(filename string, enc encryption.PlanEncryption) (*Reader, error) {
 	// itself.
 
 	return &Reader{
-		zip: r,
+		zip:  r,
+		hash: Hash(raw),
 	}, nil
 }
 
+// Hash returns a hash of the plan file content, as returned by the
+// package-level Hash function.
+func (r *Reader) Hash() string {
+	return r.hash
+}
+
 // ReadPlan reads the plan embedded in the plan file.
 //
 // Errors can be returned for various reasons, including if the plan file
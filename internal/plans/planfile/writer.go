@@ -8,6 +8,7 @@ package planfile
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -139,7 +140,7 @@ func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) err
 	// Finish zip file
 	zw.Close()
 	// Encrypt payload
-	encrypted, err := enc.EncryptPlan(buff.Bytes())
+	encrypted, err := enc.EncryptPlan(context.TODO(), buff.Bytes())
 	if err != nil {
 		return err
 	}
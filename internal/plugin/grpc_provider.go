@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plugin/convert"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 	proto "github.com/opentofu/opentofu/internal/tfplugin5"
 )
 
@@ -104,6 +106,7 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 
 	resp.ResourceTypes = make(map[string]providers.Schema)
 	resp.DataSources = make(map[string]providers.Schema)
+	resp.EphemeralResources = make(map[string]providers.Schema)
 	resp.Functions = make(map[string]providers.FunctionSpec)
 
 	// Some providers may generate quite large schemas, and the internal default
@@ -147,6 +150,10 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 		resp.DataSources[name] = convert.ProtoToProviderSchema(data)
 	}
 
+	for name, res := range protoResp.EphemeralResourceSchemas {
+		resp.EphemeralResources[name] = convert.ProtoToProviderSchema(res)
+	}
+
 	for name, fn := range protoResp.Functions {
 		resp.Functions[name] = convert.ProtoToFunctionSpec(fn)
 	}
@@ -154,6 +161,7 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 	if protoResp.ServerCapabilities != nil {
 		resp.ServerCapabilities.PlanDestroy = protoResp.ServerCapabilities.PlanDestroy
 		resp.ServerCapabilities.GetProviderSchemaOptional = protoResp.ServerCapabilities.GetProviderSchemaOptional
+		resp.ServerCapabilities.ApplyResourceChanges = protoResp.ServerCapabilities.ApplyResourceChanges
 	}
 
 	// Set the global provider cache so that future calls to this provider can use the cached value.
@@ -285,6 +293,41 @@ func (p *GRPCProvider) ValidateDataResourceConfig(r providers.ValidateDataResour
 	return resp
 }
 
+func (p *GRPCProvider) ValidateEphemeralResourceConfig(r providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	logger.Trace("GRPCProvider: ValidateEphemeralResourceConfig")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	ephemeralSchema, ok := schema.EphemeralResources[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown ephemeral resource %q", r.TypeName))
+		return resp
+	}
+
+	mp, err := msgpack.Marshal(r.Config, ephemeralSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	protoReq := &proto.ValidateEphemeralResourceConfig_Request{
+		TypeName: r.TypeName,
+		Config:   &proto.DynamicValue{Msgpack: mp},
+	}
+
+	protoResp, err := p.client.ValidateEphemeralResourceConfig(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	return resp
+}
+
 func (p *GRPCProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequest) (resp providers.UpgradeResourceStateResponse) {
 	logger.Trace("GRPCProvider: UpgradeResourceState")
 
@@ -357,15 +400,41 @@ func (p *GRPCProvider) ConfigureProvider(r providers.ConfigureProviderRequest) (
 		},
 	}
 
-	protoResp, err := p.client.Configure(p.ctx, protoReq)
+	ctx, cancel := p.callContext(r.Context)
+	defer cancel()
+	protoResp, err := p.client.Configure(ctx, protoReq)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
 	}
 	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	if ttl := protoResp.CredentialsTtlSeconds; ttl > 0 {
+		resp.CredentialsExpiry = time.Now().Add(time.Duration(ttl) * time.Second)
+	}
 	return resp
 }
 
+// callContext returns the context for a call made on behalf of a request
+// that may carry a context of its own. The returned context is canceled when
+// either the plugin process ends or the request's context is canceled, and
+// the returned function must be called once the call is complete.
+func (p *GRPCProvider) callContext(reqCtx context.Context) (context.Context, context.CancelFunc) {
+	parent := p.ctx
+	if parent == nil {
+		// This can happen in tests that construct a GRPCProvider directly.
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	if reqCtx == nil {
+		return ctx, cancel
+	}
+	stop := context.AfterFunc(reqCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 func (p *GRPCProvider) Stop() error {
 	logger.Trace("GRPCProvider: Stop")
 
@@ -531,28 +600,84 @@ func (p *GRPCProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 		return resp
 	}
 
+	protoReq, diags := encodeApplyResourceChange(schema, r)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	protoResp, err := p.client.ApplyResourceChange(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	return decodeApplyResourceChange(schema, r.TypeName, protoResp)
+}
+
+func (p *GRPCProvider) ApplyResourceChanges(r providers.ApplyResourceChangesRequest) (resp providers.ApplyResourceChangesResponse) {
+	logger.Trace("GRPCProvider: ApplyResourceChanges")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	protoReq := &proto.ApplyResourceChanges_Request{}
+	for _, change := range r.Changes {
+		changeReq, diags := encodeApplyResourceChange(schema, change)
+		if diags.HasErrors() {
+			resp.Diagnostics = diags
+			return resp
+		}
+		protoReq.Changes = append(protoReq.Changes, changeReq)
+	}
+
+	protoResp, err := p.client.ApplyResourceChanges(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+
+	if len(protoResp.Changes) != len(r.Changes) {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("provider returned %d results for %d changes", len(protoResp.Changes), len(r.Changes)))
+		return resp
+	}
+	for i, changeResp := range protoResp.Changes {
+		resp.Changes = append(resp.Changes, decodeApplyResourceChange(schema, r.Changes[i].TypeName, changeResp))
+	}
+	return resp
+}
+
+// encodeApplyResourceChange builds the protocol request for the given change,
+// which is sent alone by ApplyResourceChange or along with others by
+// ApplyResourceChanges.
+func encodeApplyResourceChange(schema providers.GetProviderSchemaResponse, r providers.ApplyResourceChangeRequest) (*proto.ApplyResourceChange_Request, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
 	resSchema, ok := schema.ResourceTypes[r.TypeName]
 	if !ok {
-		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
-		return resp
+		diags = diags.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
+		return nil, diags
 	}
 
 	metaSchema := schema.ProviderMeta
 
 	priorMP, err := msgpack.Marshal(r.PriorState, resSchema.Block.ImpliedType())
 	if err != nil {
-		resp.Diagnostics = resp.Diagnostics.Append(err)
-		return resp
+		diags = diags.Append(err)
+		return nil, diags
 	}
 	plannedMP, err := msgpack.Marshal(r.PlannedState, resSchema.Block.ImpliedType())
 	if err != nil {
-		resp.Diagnostics = resp.Diagnostics.Append(err)
-		return resp
+		diags = diags.Append(err)
+		return nil, diags
 	}
 	configMP, err := msgpack.Marshal(r.Config, resSchema.Block.ImpliedType())
 	if err != nil {
-		resp.Diagnostics = resp.Diagnostics.Append(err)
-		return resp
+		diags = diags.Append(err)
+		return nil, diags
 	}
 
 	protoReq := &proto.ApplyResourceChange_Request{
@@ -566,22 +691,22 @@ func (p *GRPCProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 	if metaSchema.Block != nil {
 		metaMP, err := msgpack.Marshal(r.ProviderMeta, metaSchema.Block.ImpliedType())
 		if err != nil {
-			resp.Diagnostics = resp.Diagnostics.Append(err)
-			return resp
+			diags = diags.Append(err)
+			return nil, diags
 		}
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
+	return protoReq, diags
+}
 
-	protoResp, err := p.client.ApplyResourceChange(p.ctx, protoReq)
-	if err != nil {
-		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
-		return resp
-	}
+// decodeApplyResourceChange converts the protocol response for a change to a
+// resource of the given type.
+func decodeApplyResourceChange(schema providers.GetProviderSchemaResponse, typeName string, protoResp *proto.ApplyResourceChange_Response) (resp providers.ApplyResourceChangeResponse) {
 	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
 
 	resp.Private = protoResp.Private
 
-	state, err := decodeDynamicValue(protoResp.NewState, resSchema.Block.ImpliedType())
+	state, err := decodeDynamicValue(protoResp.NewState, schema.ResourceTypes[typeName].Block.ImpliedType())
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(err)
 		return resp
@@ -722,7 +847,9 @@ func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 		protoReq.ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
 	}
 
-	protoResp, err := p.client.ReadDataSource(p.ctx, protoReq)
+	ctx, cancel := p.callContext(r.Context)
+	defer cancel()
+	protoResp, err := p.client.ReadDataSource(ctx, protoReq)
 	if err != nil {
 		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
 		return resp
@@ -739,6 +866,98 @@ func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 	return resp
 }
 
+func (p *GRPCProvider) OpenEphemeralResource(r providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	logger.Trace("GRPCProvider: OpenEphemeralResource")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	ephemeralSchema, ok := schema.EphemeralResources[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown ephemeral resource %q", r.TypeName))
+		return resp
+	}
+
+	config, err := msgpack.Marshal(r.Config, ephemeralSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	protoReq := &proto.OpenEphemeralResource_Request{
+		TypeName: r.TypeName,
+		Config: &proto.DynamicValue{
+			Msgpack: config,
+		},
+	}
+
+	protoResp, err := p.client.OpenEphemeralResource(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	if resp.Diagnostics.HasErrors() {
+		return resp
+	}
+
+	result, err := decodeDynamicValue(protoResp.Result, ephemeralSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Result = result
+	resp.Private = protoResp.Private
+	if protoResp.RenewAt != nil {
+		resp.RenewAt = protoResp.RenewAt.AsTime()
+	}
+
+	return resp
+}
+
+func (p *GRPCProvider) RenewEphemeralResource(r providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	logger.Trace("GRPCProvider: RenewEphemeralResource")
+
+	protoReq := &proto.RenewEphemeralResource_Request{
+		TypeName: r.TypeName,
+		Private:  r.Private,
+	}
+
+	protoResp, err := p.client.RenewEphemeralResource(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	resp.Private = protoResp.Private
+	if protoResp.RenewAt != nil {
+		resp.RenewAt = protoResp.RenewAt.AsTime()
+	}
+
+	return resp
+}
+
+func (p *GRPCProvider) CloseEphemeralResource(r providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	logger.Trace("GRPCProvider: CloseEphemeralResource")
+
+	protoReq := &proto.CloseEphemeralResource_Request{
+		TypeName: r.TypeName,
+		Private:  r.Private,
+	}
+
+	protoResp, err := p.client.CloseEphemeralResource(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+
+	return resp
+}
+
 func (p *GRPCProvider) GetFunctions() (resp providers.GetFunctionsResponse) {
 	logger.Trace("GRPCProvider: GetFunctions")
 
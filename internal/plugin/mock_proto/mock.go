@@ -62,6 +62,26 @@ func (mr *MockProviderClientMockRecorder) ApplyResourceChange(arg0, arg1 any, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyResourceChange", reflect.TypeOf((*MockProviderClient)(nil).ApplyResourceChange), varargs...)
 }
 
+// ApplyResourceChanges mocks base method.
+func (m *MockProviderClient) ApplyResourceChanges(arg0 context.Context, arg1 *tfplugin5.ApplyResourceChanges_Request, arg2 ...grpc.CallOption) (*tfplugin5.ApplyResourceChanges_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApplyResourceChanges", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.ApplyResourceChanges_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyResourceChanges indicates an expected call of ApplyResourceChanges.
+func (mr *MockProviderClientMockRecorder) ApplyResourceChanges(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyResourceChanges", reflect.TypeOf((*MockProviderClient)(nil).ApplyResourceChanges), varargs...)
+}
+
 // CallFunction mocks base method.
 func (m *MockProviderClient) CallFunction(arg0 context.Context, arg1 *tfplugin5.CallFunction_Request, arg2 ...grpc.CallOption) (*tfplugin5.CallFunction_Response, error) {
 	m.ctrl.T.Helper()
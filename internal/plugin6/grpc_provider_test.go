@@ -7,8 +7,10 @@ package plugin6
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -449,6 +451,32 @@ func TestGRPCProvider_Configure(t *testing.T) {
 	checkDiags(t, resp.Diagnostics)
 }
 
+func TestGRPCProvider_ConfigureCredentialsExpiry(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ConfigureProvider(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.ConfigureProvider_Response{
+		CredentialsTtlSeconds: 3600,
+	}, nil)
+
+	before := time.Now()
+	resp := p.ConfigureProvider(providers.ConfigureProviderRequest{
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("foo"),
+		}),
+	})
+	checkDiags(t, resp.Diagnostics)
+
+	if resp.CredentialsExpiry.Before(before.Add(time.Hour)) || resp.CredentialsExpiry.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("wrong credentials expiry %s for a TTL of one hour", resp.CredentialsExpiry)
+	}
+}
+
 func TestGRPCProvider_Stop(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mockproto.NewMockProviderClient(ctrl)
@@ -784,6 +812,99 @@ func TestGRPCProvider_ApplyResourceChangeJSON(t *testing.T) {
 	}
 }
 
+func TestGRPCProvider_ApplyResourceChanges(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ApplyResourceChanges(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ any, req *proto.ApplyResourceChanges_Request, _ ...any) (*proto.ApplyResourceChanges_Response, error) {
+		if got, want := len(req.Changes), 2; got != want {
+			t.Fatalf("wrong number of changes %d; want %d", got, want)
+		}
+		return &proto.ApplyResourceChanges_Response{
+			Changes: []*proto.ApplyResourceChange_Response{
+				{
+					NewState: &proto.DynamicValue{
+						Msgpack: []byte("\x81\xa4attr\xa3bar"),
+					},
+				},
+				{
+					NewState: &proto.DynamicValue{
+						Msgpack: []byte("\x81\xa4attr\xa3baz"),
+					},
+					Diagnostics: []*proto.Diagnostic{
+						{
+							Severity: proto.Diagnostic_WARNING,
+							Summary:  "warning",
+						},
+					},
+				},
+			},
+		}, nil
+	})
+
+	change := func(val string) providers.ApplyResourceChangeRequest {
+		return providers.ApplyResourceChangeRequest{
+			TypeName:   "resource",
+			PriorState: cty.NullVal(cty.Object(map[string]cty.Type{"attr": cty.String})),
+			PlannedState: cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal(val),
+			}),
+			Config: cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal(val),
+			}),
+		}
+	}
+	resp := p.ApplyResourceChanges(providers.ApplyResourceChangesRequest{
+		Changes: []providers.ApplyResourceChangeRequest{change("bar"), change("baz")},
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	if got, want := len(resp.Changes), 2; got != want {
+		t.Fatalf("wrong number of responses %d; want %d", got, want)
+	}
+	for i, want := range []string{"bar", "baz"} {
+		expectedState := cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal(want),
+		})
+		if !cmp.Equal(expectedState, resp.Changes[i].NewState, typeComparer, valueComparer, equateEmpty) {
+			t.Fatal(cmp.Diff(expectedState, resp.Changes[i].NewState, typeComparer, valueComparer, equateEmpty))
+		}
+	}
+	if len(resp.Changes[1].Diagnostics) != 1 {
+		t.Fatalf("expected a warning for the second change, got %s", resp.Changes[1].Diagnostics.ErrWithWarnings())
+	}
+}
+
+func TestGRPCProvider_ApplyResourceChangesWrongCount(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ApplyResourceChanges(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.ApplyResourceChanges_Response{}, nil)
+
+	resp := p.ApplyResourceChanges(providers.ApplyResourceChangesRequest{
+		Changes: []providers.ApplyResourceChangeRequest{
+			{
+				TypeName:     "resource",
+				PriorState:   cty.NullVal(cty.Object(map[string]cty.Type{"attr": cty.String})),
+				PlannedState: cty.ObjectVal(map[string]cty.Value{"attr": cty.StringVal("bar")}),
+				Config:       cty.ObjectVal(map[string]cty.Value{"attr": cty.StringVal("bar")}),
+			},
+		},
+	})
+
+	checkDiagsHasError(t, resp.Diagnostics)
+}
+
 func TestGRPCProvider_ImportResourceState(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -904,6 +1025,35 @@ func TestGRPCProvider_ReadDataSource(t *testing.T) {
 	}
 }
 
+func TestGRPCProvider_ReadDataSourceCancelled(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ReadDataSource(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, _ *proto.ReadDataSource_Request, _ ...any) (*proto.ReadDataSource_Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp := p.ReadDataSource(providers.ReadDataSourceRequest{
+		TypeName: "data",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("foo"),
+		}),
+		Context: reqCtx,
+	})
+
+	if !resp.Diagnostics.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+}
+
 func TestGRPCProvider_ReadDataSourceJSON(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -8,6 +8,7 @@ package simple
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -138,6 +139,13 @@ func (s simple) ApplyResourceChange(req providers.ApplyResourceChangeRequest) (r
 	return resp
 }
 
+func (s simple) ApplyResourceChanges(req providers.ApplyResourceChangesRequest) (resp providers.ApplyResourceChangesResponse) {
+	for _, change := range req.Changes {
+		resp.Changes = append(resp.Changes, s.ApplyResourceChange(change))
+	}
+	return resp
+}
+
 func (s simple) ImportResourceState(providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
 	resp.Diagnostics = resp.Diagnostics.Append(errors.New("unsupported"))
 	return resp
@@ -150,6 +158,26 @@ func (s simple) ReadDataSource(req providers.ReadDataSourceRequest) (resp provid
 	return resp
 }
 
+func (s simple) ValidateEphemeralResourceConfig(req providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", req.TypeName))
+	return resp
+}
+
+func (s simple) OpenEphemeralResource(req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", req.TypeName))
+	return resp
+}
+
+func (s simple) RenewEphemeralResource(req providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", req.TypeName))
+	return resp
+}
+
+func (s simple) CloseEphemeralResource(req providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource %q", req.TypeName))
+	return resp
+}
+
 func (s simple) GetFunctions() providers.GetFunctionsResponse {
 	panic("Not Implemented")
 }
@@ -6,6 +6,9 @@
 package providers
 
 import (
+	"context"
+	"time"
+
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -38,6 +41,10 @@ type Unconfigured interface {
 	// configuration values.
 	ValidateDataResourceConfig(ValidateDataResourceConfigRequest) ValidateDataResourceConfigResponse
 
+	// ValidateEphemeralResourceConfig allows the provider to validate the
+	// ephemeral resource configuration values.
+	ValidateEphemeralResourceConfig(ValidateEphemeralResourceConfigRequest) ValidateEphemeralResourceConfigResponse
+
 	// MoveResourceState requests that the given resource data be moved from one
 	// type to another, potentially between providers as well.
 	MoveResourceState(MoveResourceStateRequest) MoveResourceStateResponse
@@ -92,12 +99,29 @@ type Configured interface {
 	// the final state.
 	ApplyResourceChange(ApplyResourceChangeRequest) ApplyResourceChangeResponse
 
+	// ApplyResourceChanges applies several changes at once, as if
+	// ApplyResourceChange was called for each of them. It may only be called
+	// for providers that declare the ApplyResourceChanges server capability.
+	ApplyResourceChanges(ApplyResourceChangesRequest) ApplyResourceChangesResponse
+
 	// ImportResourceState requests that the given resource be imported.
 	ImportResourceState(ImportResourceStateRequest) ImportResourceStateResponse
 
 	// ReadDataSource returns the data source's current state.
 	ReadDataSource(ReadDataSourceRequest) ReadDataSourceResponse
 
+	// OpenEphemeralResource opens an ephemeral resource, such as a lease on
+	// a secret, and returns its value. The value is never persisted.
+	OpenEphemeralResource(OpenEphemeralResourceRequest) OpenEphemeralResourceResponse
+
+	// RenewEphemeralResource extends the lifetime of an open ephemeral
+	// resource, when OpenEphemeralResource or an earlier renewal asked for it.
+	RenewEphemeralResource(RenewEphemeralResourceRequest) RenewEphemeralResourceResponse
+
+	// CloseEphemeralResource releases an open ephemeral resource once
+	// OpenTofu no longer needs its value.
+	CloseEphemeralResource(CloseEphemeralResourceRequest) CloseEphemeralResourceResponse
+
 	// GetFunctions returns a full list of functions defined in this provider. It should be a super
 	// set of the functions returned in GetProviderSchema()
 	GetFunctions() GetFunctionsResponse
@@ -128,6 +152,10 @@ type GetProviderSchemaResponse struct {
 	// DataSources maps the data source name to that data source's schema.
 	DataSources map[string]Schema
 
+	// EphemeralResources maps the ephemeral resource type name to that
+	// type's schema.
+	EphemeralResources map[string]Schema
+
 	// Diagnostics contains any warnings or errors from the method call.
 	Diagnostics tfdiags.Diagnostics
 
@@ -165,6 +193,11 @@ type ServerCapabilities struct {
 	// In other words, the providers for which GetProviderSchemaOptional is false
 	// require their schema to be read after EVERY instantiation to function normally.
 	GetProviderSchemaOptional bool
+
+	// ApplyResourceChanges signals that this provider supports the
+	// ApplyResourceChanges method, and so can apply several changes to
+	// resources of the same type in one call.
+	ApplyResourceChanges bool
 }
 
 type FunctionSpec struct {
@@ -248,6 +281,20 @@ type ValidateDataResourceConfigResponse struct {
 	Diagnostics tfdiags.Diagnostics
 }
 
+type ValidateEphemeralResourceConfigRequest struct {
+	// TypeName is the name of the ephemeral resource type to validate.
+	TypeName string
+
+	// Config is the configuration value to validate, which may contain unknown
+	// values.
+	Config cty.Value
+}
+
+type ValidateEphemeralResourceConfigResponse struct {
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type UpgradeResourceStateRequest struct {
 	// TypeName is the name of the resource type being upgraded
 	TypeName string
@@ -281,11 +328,24 @@ type ConfigureProviderRequest struct {
 
 	// Config is the complete configuration value for the provider.
 	Config cty.Value
+
+	// Context, if set, is canceled when OpenTofu gives up waiting for the
+	// provider to respond, such as when the operation was interrupted and
+	// the provider didn't stop in time. Plugin clients cancel the request
+	// to the provider plugin at that point.
+	Context context.Context
 }
 
 type ConfigureProviderResponse struct {
 	// Diagnostics contains any warnings or errors from the method call.
 	Diagnostics tfdiags.Diagnostics
+
+	// CredentialsExpiry optionally signals when the credentials the provider
+	// configured itself with expire. If set, OpenTofu configures the provider
+	// again with the same request before this time, so that long-running
+	// operations don't fail when the credentials expire. The zero value means
+	// the credentials don't expire or the provider doesn't know.
+	CredentialsExpiry time.Time
 }
 
 type ReadResourceRequest struct {
@@ -421,6 +481,22 @@ type ApplyResourceChangeResponse struct {
 	LegacyTypeSystem bool
 }
 
+type ApplyResourceChangesRequest struct {
+	// Changes are the changes to apply, each as it would be requested from
+	// ApplyResourceChange.
+	Changes []ApplyResourceChangeRequest
+}
+
+type ApplyResourceChangesResponse struct {
+	// Changes contains the response to each of the requested changes, in the
+	// same order as the request.
+	Changes []ApplyResourceChangeResponse
+
+	// Diagnostics contains any warnings or errors about the call as a whole,
+	// which apply to each of the changes.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type ImportResourceStateRequest struct {
 	// TypeName is the name of the resource type to be imported.
 	TypeName string
@@ -521,6 +597,10 @@ type ReadDataSourceRequest struct {
 	// each provider, and it should not be used without coordination with
 	// HashiCorp. It is considered experimental and subject to change.
 	ProviderMeta cty.Value
+
+	// Context, if set, is canceled when OpenTofu gives up waiting for the
+	// provider to respond, as for ConfigureProviderRequest.Context.
+	Context context.Context
 }
 
 type ReadDataSourceResponse struct {
@@ -531,6 +611,67 @@ type ReadDataSourceResponse struct {
 	Diagnostics tfdiags.Diagnostics
 }
 
+type OpenEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to open.
+	TypeName string
+
+	// Config is the complete configuration for the requested ephemeral
+	// resource, which never contains unknown values.
+	Config cty.Value
+}
+
+type OpenEphemeralResourceResponse struct {
+	// Result is the value of the ephemeral resource.
+	Result cty.Value
+
+	// Private is opaque data that must be passed to RenewEphemeralResource
+	// and CloseEphemeralResource.
+	Private []byte
+
+	// RenewAt is the time at which RenewEphemeralResource must be called to
+	// keep the ephemeral resource open, or the zero time if it doesn't need
+	// to be renewed.
+	RenewAt time.Time
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type RenewEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to renew.
+	TypeName string
+
+	// Private is the opaque data returned by OpenEphemeralResource, or by the
+	// previous renewal.
+	Private []byte
+}
+
+type RenewEphemeralResourceResponse struct {
+	// Private replaces the opaque data for later calls.
+	Private []byte
+
+	// RenewAt is the time at which RenewEphemeralResource must be called
+	// again, or the zero time if it doesn't need to be renewed anymore.
+	RenewAt time.Time
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type CloseEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to close.
+	TypeName string
+
+	// Private is the latest opaque data returned by OpenEphemeralResource or
+	// RenewEphemeralResource.
+	Private []byte
+}
+
+type CloseEphemeralResourceResponse struct {
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type GetFunctionsResponse struct {
 	Functions map[string]FunctionSpec
 
@@ -6,6 +6,8 @@
 package provisioners
 
 import (
+	"io"
+
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
@@ -79,6 +81,19 @@ type ProvisionResourceRequest struct {
 
 	// UIOutput is used to return output during the Apply operation.
 	UIOutput UIOutput
+
+	// TemplateRenderer renders template files with the functions available
+	// to the configuration. It is only set for provisioners that run within
+	// the OpenTofu process, and is nil otherwise.
+	TemplateRenderer TemplateRenderer
+}
+
+// TemplateRenderer renders template files for provisioners, so that they can
+// write large rendered templates without evaluating the template themselves.
+type TemplateRenderer interface {
+	// RenderTemplateFile renders the template file at the given path with
+	// the given variables, writing the result to w as it is produced.
+	RenderTemplateFile(w io.Writer, path string, vars cty.Value) error
 }
 
 type ProvisionResourceResponse struct {
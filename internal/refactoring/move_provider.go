@@ -0,0 +1,241 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ProviderMoveStatement represents a "moved" block whose addresses refer to
+// provider configurations, which rebinds the resource instances in the state
+// from one provider configuration (or provider instance) to another.
+type ProviderMoveStatement struct {
+	// Module is the module that declares both provider configurations.
+	Module addrs.Module
+
+	// Provider is the provider that both configurations belong to.
+	Provider addrs.Provider
+
+	From, To  *configs.MovedProviderEndpoint
+	DeclRange tfdiags.SourceRange
+}
+
+// FromConfig returns the absolute address of the provider configuration that
+// the resource instances are moved away from.
+func (s *ProviderMoveStatement) FromConfig() addrs.AbsProviderConfig {
+	return addrs.AbsProviderConfig{
+		Module:   s.Module,
+		Provider: s.Provider,
+		Alias:    s.From.Config.Alias,
+	}
+}
+
+// ToConfig returns the absolute address of the provider configuration that
+// the resource instances are moved to.
+func (s *ProviderMoveStatement) ToConfig() addrs.AbsProviderConfig {
+	return addrs.AbsProviderConfig{
+		Module:   s.Module,
+		Provider: s.Provider,
+		Alias:    s.To.Config.Alias,
+	}
+}
+
+// FindProviderMoveStatements recurses through the modules of the given
+// configuration and returns all of the "moved" blocks that refer to provider
+// configurations, in a deterministic but undefined order.
+func FindProviderMoveStatements(rootCfg *configs.Config) []ProviderMoveStatement {
+	return findProviderMoveStatements(rootCfg, nil)
+}
+
+func findProviderMoveStatements(cfg *configs.Config, into []ProviderMoveStatement) []ProviderMoveStatement {
+	for _, mc := range cfg.Module.Moved {
+		if mc.FromProvider == nil || mc.ToProvider == nil {
+			continue
+		}
+		into = append(into, ProviderMoveStatement{
+			Module:    cfg.Path,
+			Provider:  cfg.Module.ProviderForLocalConfig(mc.FromProvider.Config),
+			From:      mc.FromProvider,
+			To:        mc.ToProvider,
+			DeclRange: tfdiags.SourceRangeFromHCL(mc.DeclRange),
+		})
+	}
+
+	for _, childCfg := range cfg.Children {
+		into = findProviderMoveStatements(childCfg, into)
+	}
+
+	return into
+}
+
+// ApplyProviderMoves modifies in-place the given state object so that the
+// resource instances bound to the "from" provider configuration of each
+// statement are instead bound to its "to" provider configuration.
+//
+// The state records the absolute address of the provider configuration that
+// each resource is bound to, so this also moves the resources of descendant
+// modules that received the configuration through the "providers" argument of
+// a module call, or by inheriting it.
+//
+// The instance keys of the resource instances are preserved when neither
+// address of a statement has an instance key, so renaming a provider
+// configuration that uses for_each keeps each resource instance bound to the
+// instance with the same key.
+//
+// The provider configuration is recorded per resource in the state, so a
+// statement that moves only some of the instances of a resource to a
+// different provider configuration can't be applied, and returns an error.
+func ApplyProviderMoves(stmts []ProviderMoveStatement, state *states.State) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, stmt := range stmts {
+		from, to := stmt.FromConfig(), stmt.ToConfig()
+		sameConfig := from.String() == to.String()
+
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				if rs.ProviderConfig.String() != from.String() {
+					continue
+				}
+
+				var matched []*states.ResourceInstance
+				for _, is := range rs.Instances {
+					// An address without a key refers to all instances of
+					// another configuration, but only to the unkeyed
+					// instance when adding a key within the same one.
+					if is.ProviderKey == stmt.From.Key || (stmt.From.Key == addrs.NoKey && !sameConfig) {
+						matched = append(matched, is)
+					}
+				}
+				if len(matched) == 0 {
+					continue
+				}
+				if !sameConfig && len(matched) != len(rs.Instances) {
+					diags = diags.Append(&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Cannot move some instances of a resource to another provider configuration",
+						Detail: fmt.Sprintf(
+							"This statement moves resources from %s to %s, but only some of the instances of %s use %s. OpenTofu records the provider configuration of each resource as a whole, so all of the instances of a resource must use the same provider configuration.",
+							stmt.From, stmt.To, rs.Addr, stmt.From,
+						),
+						Subject: stmt.DeclRange.ToHCL().Ptr(),
+					})
+					continue
+				}
+
+				log.Printf("[TRACE] refactoring.ApplyProviderMoves: %s moves from %s to %s", rs.Addr, stmt.From, stmt.To)
+				rs.ProviderConfig = to
+				for _, is := range matched {
+					switch {
+					case stmt.To.Key != addrs.NoKey:
+						is.ProviderKey = stmt.To.Key
+					case stmt.From.Key != addrs.NoKey:
+						is.ProviderKey = addrs.NoKey
+					}
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// ValidateProviderMoves tests whether the "moved" blocks that refer to
+// provider configurations are valid for the given configuration: the "to"
+// address must refer to a provider configuration (or instance) that exists,
+// and the "from" address must refer to one that doesn't exist anymore.
+func ValidateProviderMoves(stmts []ProviderMoveStatement, rootCfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, stmt := range stmts {
+		modCfg := rootCfg.Descendent(stmt.Module)
+		if modCfg == nil {
+			// Statements are found in the configuration, so this can't happen.
+			panic(fmt.Sprintf("provider move statement in %s refers to an unknown module", stmt.DeclRange.ToHCL()))
+		}
+
+		toCfg, toExists := modCfg.Module.ProviderConfigs[stmt.To.Config.StringCompact()]
+		switch {
+		case !toExists && (stmt.To.Config.Alias != "" || stmt.To.Key != addrs.NoKey):
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Moved provider configuration does not exist",
+				Detail: fmt.Sprintf(
+					"This statement moves resources to %s, but there is no provider configuration with that address in %s.",
+					stmt.To, moduleDisplayName(stmt.Module),
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		case stmt.To.Key != addrs.NoKey && !hasProviderInstance(toCfg, stmt.To.Key):
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Moved provider instance does not exist",
+				Detail: fmt.Sprintf(
+					"This statement moves resources to %s, but the provider configuration has no instance with the key %s.",
+					stmt.To, stmt.To.Key,
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		case toExists && toCfg.Instances != nil && stmt.To.Key == addrs.NoKey && stmt.From.Key != addrs.NoKey:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing provider instance key",
+				Detail: fmt.Sprintf(
+					"The provider configuration %s uses for_each, so the \"to\" address must include the key of the instance to move the resources to.",
+					stmt.To,
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		}
+
+		fromCfg, fromExists := modCfg.Module.ProviderConfigs[stmt.From.Config.StringCompact()]
+		if fromExists {
+			if stmt.From.Key != addrs.NoKey {
+				fromExists = hasProviderInstance(fromCfg, stmt.From.Key)
+			} else if stmt.From.Config == stmt.To.Config {
+				// Adding for_each to a configuration removes its unkeyed
+				// instance.
+				fromExists = fromCfg.Instances == nil
+			}
+		}
+		if fromExists {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Moved provider configuration still exists",
+				Detail: fmt.Sprintf(
+					"This statement moves resources away from %s, but that provider configuration still exists in %s. Remove it, or remove this statement.",
+					stmt.From, moduleDisplayName(stmt.Module),
+				),
+				Subject: stmt.DeclRange.ToHCL().Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+func hasProviderInstance(pc *configs.Provider, key addrs.InstanceKey) bool {
+	if pc == nil {
+		return false
+	}
+	_, ok := pc.Instances[key]
+	return ok
+}
+
+func moduleDisplayName(addr addrs.Module) string {
+	if addr.IsRoot() {
+		return "the root module"
+	}
+	return addr.String()
+}
@@ -0,0 +1,150 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package refactoring
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestApplyProviderMoves(t *testing.T) {
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/move-provider/valid")
+	stmts := FindProviderMoveStatements(rootCfg)
+	if len(stmts) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(stmts))
+	}
+	if diags := ValidateProviderMoves(stmts, rootCfg); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	awsProvider := addrs.NewDefaultProvider("aws")
+	providerConfig := func(alias string) addrs.AbsProviderConfig {
+		return addrs.AbsProviderConfig{
+			Module:   addrs.RootModule,
+			Provider: awsProvider,
+			Alias:    alias,
+		}
+	}
+	obj := &states.ResourceInstanceObjectSrc{
+		Status:    states.ObjectReady,
+		AttrsJSON: []byte(`{}`),
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "a"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			obj, providerConfig("old"), addrs.NoKey,
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "b"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			obj, providerConfig("single"), addrs.NoKey,
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "c"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			obj, providerConfig("other"), addrs.NoKey,
+		)
+		// A resource in a child module that received the provider
+		// configuration from the root module.
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "d"}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance.Child("child", addrs.NoKey)),
+			obj, providerConfig("old"), addrs.NoKey,
+		)
+	})
+
+	if diags := ApplyProviderMoves(stmts, state); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	got := make(map[string]string)
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				got[rs.Addr.Instance(key).String()] = rs.ProviderConfig.InstanceString(is.ProviderKey)
+			}
+		}
+	}
+	want := map[string]string{
+		"aws_instance.a":              `provider["registry.opentofu.org/hashicorp/aws"].renamed`,
+		"module.child.aws_instance.d": `provider["registry.opentofu.org/hashicorp/aws"].renamed`,
+		"aws_instance.b":              `provider["registry.opentofu.org/hashicorp/aws"].regions["us-east-1"]`,
+		"aws_instance.c":              `provider["registry.opentofu.org/hashicorp/aws"].other`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong provider instances\n%s", diff)
+	}
+}
+
+func TestApplyProviderMoves_someInstances(t *testing.T) {
+	awsProvider := addrs.NewDefaultProvider("aws")
+	stmts := []ProviderMoveStatement{
+		{
+			Module:   addrs.RootModule,
+			Provider: awsProvider,
+			From: &configs.MovedProviderEndpoint{
+				Config: addrs.LocalProviderConfig{LocalName: "aws", Alias: "regions"},
+				Key:    addrs.StringKey("us-east-1"),
+			},
+			To: &configs.MovedProviderEndpoint{
+				Config: addrs.LocalProviderConfig{LocalName: "aws", Alias: "east"},
+			},
+		},
+	}
+
+	providerConfig := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: awsProvider,
+		Alias:    "regions",
+	}
+	obj := &states.ResourceInstanceObjectSrc{
+		Status:    states.ObjectReady,
+		AttrsJSON: []byte(`{}`),
+	}
+	resource := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "aws_instance", Name: "a"}
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			resource.Instance(addrs.StringKey("us-east-1")).Absolute(addrs.RootModuleInstance),
+			obj, providerConfig, addrs.StringKey("us-east-1"),
+		)
+		s.SetResourceInstanceCurrent(
+			resource.Instance(addrs.StringKey("us-west-2")).Absolute(addrs.RootModuleInstance),
+			obj, providerConfig, addrs.StringKey("us-west-2"),
+		)
+	})
+
+	diags := ApplyProviderMoves(stmts, state)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error")
+	}
+	if got, want := diags[0].Description().Summary, "Cannot move some instances of a resource to another provider configuration"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	if got := state.RootModule().Resources["aws_instance.a"].ProviderConfig; got.String() != providerConfig.String() {
+		t.Errorf("resource was moved to %s", got)
+	}
+}
+
+func TestValidateProviderMoves(t *testing.T) {
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/move-provider/invalid")
+	diags := ValidateProviderMoves(FindProviderMoveStatements(rootCfg), rootCfg)
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Description().Summary+": "+diag.Description().Detail)
+	}
+	want := []string{
+		`Moved provider configuration does not exist: This statement moves resources to provider.aws.missing, but there is no provider configuration with that address in the root module.`,
+		`Moved provider instance does not exist: This statement moves resources to provider.aws.regions["eu-west-1"], but the provider configuration has no instance with the key ["eu-west-1"].`,
+		`Moved provider configuration still exists: This statement moves resources away from provider.aws.current, but that provider configuration still exists in the root module. Remove it, or remove this statement.`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
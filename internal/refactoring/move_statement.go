@@ -42,6 +42,11 @@ func FindMoveStatements(rootCfg *configs.Config) []MoveStatement {
 func findMoveStatements(cfg *configs.Config, into []MoveStatement) []MoveStatement {
 	modAddr := cfg.Path
 	for _, mc := range cfg.Module.Moved {
+		if mc.FromProvider != nil {
+			// Moves of provider configurations are handled separately, by
+			// FindProviderMoveStatements.
+			continue
+		}
 		fromAddr, toAddr := addrs.UnifyMoveEndpoints(modAddr, mc.From, mc.To)
 		if fromAddr == nil || toAddr == nil {
 			// Invalid combination should've been caught during original
@@ -37,6 +37,11 @@ func findRemoveStatements(cfg *configs.Config, into []*RemoveStatement) []*Remov
 	modAddr := cfg.Path
 
 	for _, rc := range cfg.Module.Removed {
+		if rc.From == nil {
+			// Removed provider configurations are handled when resolving
+			// the provider of each resource instance, in package tofu.
+			continue
+		}
 		var removedEndpoint *RemoveStatement
 		switch FromAddress := rc.From.RelSubject.(type) {
 		case addrs.ConfigResource:
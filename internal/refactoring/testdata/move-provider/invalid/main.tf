@@ -0,0 +1,25 @@
+provider "aws" {
+  alias = "current"
+}
+
+provider "aws" {
+  alias = "regions"
+  for_each = {
+    "us-east-1" = "us-east-1"
+  }
+}
+
+moved {
+  from = provider.aws.old
+  to   = provider.aws.missing
+}
+
+moved {
+  from = provider.aws.old
+  to   = provider.aws.regions["eu-west-1"]
+}
+
+moved {
+  from = provider.aws.current
+  to   = provider.aws.regions["us-east-1"]
+}
@@ -0,0 +1,24 @@
+provider "aws" {
+  alias = "renamed"
+}
+
+provider "aws" {
+  alias = "regions"
+  for_each = {
+    "us-east-1" = "us-east-1"
+    "us-west-2" = "us-west-2"
+  }
+}
+
+# Renaming an alias.
+moved {
+  from = provider.aws.old
+  to   = provider.aws.renamed
+}
+
+# Replacing an alias with an instance of a provider configuration that uses
+# for_each.
+moved {
+  from = provider.aws.single
+  to   = provider.aws.regions["us-east-1"]
+}
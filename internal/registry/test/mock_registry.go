@@ -79,6 +79,10 @@ var testMods = map[string][]testMod{
 		location: "testdata/registry-tar-subdir/foo.tgz//*?archive=tar.gz",
 		version:  "0.1.2",
 	}},
+	"registry/local/archive": {{
+		location: "testdata/registry-tar/module.tgz?archive=tar.gz",
+		version:  "0.3.0",
+	}},
 	"exists-in-registry/identifier/provider": {{
 		location: "file:///registry/exists",
 		version:  "0.2.0",
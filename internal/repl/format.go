@@ -25,6 +25,12 @@ func FormatValue(v cty.Value, indent int) string {
 	if v.HasMark(marks.Sensitive) {
 		return "(sensitive value)"
 	}
+	if v.HasMark(marks.Ephemeral) {
+		// Ephemeral values can be displayed, since they are not saved.
+		unmarked, valMarks := v.Unmark()
+		delete(valMarks, marks.Ephemeral)
+		v = unmarked.WithMarks(valMarks)
+	}
 	if v.IsNull() {
 		return formatNullValue(v.Type())
 	}
@@ -0,0 +1,188 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package secretscan
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Detector decides whether a single string value looks like a secret.
+type Detector interface {
+	// Name returns the name of the detector, which is included in reports
+	// and is used to select detectors on the command line.
+	Name() string
+
+	// Detect returns true if the given value looks like a secret.
+	Detect(value string) bool
+}
+
+// EntropyDetectorName is the name of the detector returned by
+// NewEntropyDetector.
+const EntropyDetectorName = "high-entropy"
+
+const (
+	// DefaultEntropyThreshold is the default minimum Shannon entropy, in
+	// bits per character, of a string reported by the entropy detector.
+	DefaultEntropyThreshold = 4.5
+
+	// DefaultMinLength is the default minimum length of a string reported
+	// by the entropy detector. Shorter strings rarely reach a meaningful
+	// entropy and are mostly identifiers.
+	DefaultMinLength = 20
+)
+
+// hexEntropyPenalty is subtracted from the entropy threshold for strings that
+// only consist of hexadecimal digits. The entropy of a hex string can never
+// exceed 4 bits per character, so without this adjustment hex-encoded keys
+// would never be reported with the default threshold.
+const hexEntropyPenalty = 1.5
+
+var (
+	hexString    = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	base64String = regexp.MustCompile(`^[A-Za-z0-9+/_\-]+=*$`)
+)
+
+type patternDetector struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// NewPatternDetector returns a detector with the given name that reports all
+// values matching the given regular expression.
+func NewPatternDetector(name string, expr string) (Detector, error) {
+	if name == "" {
+		return nil, fmt.Errorf("the detector name must not be empty")
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for detector %q: %w", name, err)
+	}
+	return &patternDetector{name: name, pattern: pattern}, nil
+}
+
+func (d *patternDetector) Name() string {
+	return d.name
+}
+
+func (d *patternDetector) Detect(value string) bool {
+	return d.pattern.MatchString(value)
+}
+
+type entropyDetector struct {
+	threshold float64
+	minLength int
+}
+
+// NewEntropyDetector returns a detector that reports strings of at least
+// minLength characters that look like encoded random data and whose Shannon
+// entropy is at least the given threshold, in bits per character.
+//
+// Strings containing whitespace or other characters that do not appear in
+// base64 or hexadecimal encodings are never reported, because they are
+// usually human-readable text.
+func NewEntropyDetector(threshold float64, minLength int) Detector {
+	return &entropyDetector{threshold: threshold, minLength: minLength}
+}
+
+func (d *entropyDetector) Name() string {
+	return EntropyDetectorName
+}
+
+func (d *entropyDetector) Detect(value string) bool {
+	if len(value) < d.minLength {
+		return false
+	}
+	switch {
+	case hexString.MatchString(value):
+		return ShannonEntropy(value) >= d.threshold-hexEntropyPenalty
+	case base64String.MatchString(value):
+		return ShannonEntropy(value) >= d.threshold
+	default:
+		return false
+	}
+}
+
+// ShannonEntropy returns the Shannon entropy of the given string in bits per
+// character.
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// knownPatterns are the regular expressions of the built-in detectors for
+// well-known token formats.
+var knownPatterns = map[string]string{
+	"aws-access-key-id":     `\b(AKIA|ASIA)[0-9A-Z]{16}\b`,
+	"github-token":          `\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`,
+	"gitlab-token":          `\bglpat-[A-Za-z0-9_\-]{20,}\b`,
+	"google-api-key":        `\bAIza[0-9A-Za-z_\-]{35}\b`,
+	"jwt":                   `\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`,
+	"private-key":           `-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----`,
+	"slack-token":           `\bxox[abposr]-[A-Za-z0-9\-]{10,}\b`,
+	"stripe-secret-key":     `\b[sr]k_live_[0-9A-Za-z]{20,}\b`,
+	"url-embedded-password": `[a-zA-Z][a-zA-Z0-9+.\-]*://[^/\s:@]+:[^/\s:@]+@`,
+}
+
+// DefaultDetectorNames returns the names of all built-in detectors in
+// lexical order.
+func DefaultDetectorNames() []string {
+	names := make([]string, 0, len(knownPatterns)+1)
+	for name := range knownPatterns {
+		names = append(names, name)
+	}
+	names = append(names, EntropyDetectorName)
+	sort.Strings(names)
+	return names
+}
+
+// DefaultDetectors returns the built-in detectors with the given names. If no
+// names are given, all built-in detectors are returned. The entropy detector
+// uses the given threshold and minimum length.
+func DefaultDetectors(names []string, threshold float64, minLength int) ([]Detector, error) {
+	if len(names) == 0 {
+		names = DefaultDetectorNames()
+	}
+	var ret []Detector
+	var unknown []string
+	for _, name := range names {
+		if name == EntropyDetectorName {
+			ret = append(ret, NewEntropyDetector(threshold, minLength))
+			continue
+		}
+		expr, ok := knownPatterns[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		d, err := NewPatternDetector(name, expr)
+		if err != nil {
+			// Should never happen, since the built-in patterns are tested.
+			return nil, err
+		}
+		ret = append(ret, d)
+	}
+	if len(unknown) != 0 {
+		return nil, fmt.Errorf("unknown detector(s) %s; valid detectors are %s", strings.Join(unknown, ", "), strings.Join(DefaultDetectorNames(), ", "))
+	}
+	return ret, nil
+}
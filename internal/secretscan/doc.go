@@ -0,0 +1,13 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package secretscan contains heuristics for finding values that look like
+// secrets, such as API tokens, private keys and other high-entropy strings.
+//
+// It is used by "tofu state scan-secrets" to find values in the state that
+// are not marked as sensitive but probably should be. The detectors are
+// heuristics only: they can report values that are not secrets and can miss
+// secrets in unusual formats.
+package secretscan
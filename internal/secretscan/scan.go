@@ -0,0 +1,60 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package secretscan
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// Finding describes a value that one of the detectors considers a likely
+// secret. It deliberately does not include the value itself, so that
+// findings can be printed without leaking the secret.
+type Finding struct {
+	// Path is the path of the value within the scanned value.
+	Path cty.Path
+
+	// Detectors are the names of the detectors that reported the value.
+	Detectors []string
+}
+
+// ScanValue runs the given detectors on all known string values within the
+// given value, including nested ones, and returns a finding for each string
+// reported by at least one detector.
+//
+// Values marked as sensitive, and everything nested inside them, are skipped,
+// since they are already protected.
+func ScanValue(val cty.Value, detectors []Detector) []Finding {
+	var findings []Finding
+	_ = cty.Walk(val, func(path cty.Path, v cty.Value) (bool, error) {
+		if v.HasMark(marks.Sensitive) {
+			return false, nil
+		}
+		if v.IsNull() || !v.IsKnown() || !v.Type().Equals(cty.String) {
+			return true, nil
+		}
+		v, _ = v.Unmark()
+		str := v.AsString()
+
+		var names []string
+		for _, d := range detectors {
+			if d.Detect(str) {
+				names = append(names, d.Name())
+			}
+		}
+		if len(names) != 0 {
+			findings = append(findings, Finding{
+				// The path's backing array is reused by cty.Walk, so we
+				// must copy it before retaining it.
+				Path:      path.Copy(),
+				Detectors: names,
+			})
+		}
+		return true, nil
+	})
+	return findings
+}
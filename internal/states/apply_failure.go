@@ -0,0 +1,90 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package states
+
+import (
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// ApplyFailure records the consecutive applies that failed to change a
+// resource instance, so that a resource instance that keeps failing can be
+// quarantined instead of failing every subsequent apply.
+//
+// A resource instance can have an ApplyFailure even if it has no objects in
+// the state, because creating it may be what keeps failing.
+type ApplyFailure struct {
+	Addr addrs.AbsResourceInstance
+
+	// Count is the number of consecutive applies in which changing the
+	// resource instance failed.
+	Count int
+
+	// LastError is the message of the error from the most recent failure.
+	LastError string
+
+	// Quarantined is true if the resource instance has failed too many times
+	// and so OpenTofu must skip it when planning, until an operator releases
+	// it with "tofu state unquarantine".
+	Quarantined bool
+}
+
+// DeepCopy returns a new ApplyFailure that contains equivalent data to the
+// receiver but shares no backing memory in common.
+func (f *ApplyFailure) DeepCopy() *ApplyFailure {
+	if f == nil {
+		return nil
+	}
+	ret := *f
+	return &ret
+}
+
+// ApplyFailure returns the record of the consecutive apply failures of the
+// resource instance with the given address, or nil if its most recent apply
+// didn't fail.
+func (s *State) ApplyFailure(addr addrs.AbsResourceInstance) *ApplyFailure {
+	return s.ApplyFailures[addr.String()]
+}
+
+// SetApplyFailure saves the given record of apply failures, replacing any
+// existing record for the same resource instance.
+func (s *State) SetApplyFailure(f *ApplyFailure) {
+	if s.ApplyFailures == nil {
+		s.ApplyFailures = make(map[string]*ApplyFailure)
+	}
+	s.ApplyFailures[f.Addr.String()] = f
+}
+
+// RemoveApplyFailure discards the record of apply failures of the resource
+// instance with the given address, if any, which also releases it from
+// quarantine.
+func (s *State) RemoveApplyFailure(addr addrs.AbsResourceInstance) {
+	delete(s.ApplyFailures, addr.String())
+	if len(s.ApplyFailures) == 0 {
+		// A state without apply failures must be equal to one that was
+		// read from a file without any.
+		s.ApplyFailures = nil
+	}
+}
+
+// QuarantinedResourceInstances returns the addresses of all of the resource
+// instances that are quarantined, in lexical order.
+func (s *State) QuarantinedResourceInstances() []addrs.AbsResourceInstance {
+	if s == nil {
+		return nil
+	}
+	var ret []addrs.AbsResourceInstance
+	for _, f := range s.ApplyFailures {
+		if f.Quarantined {
+			ret = append(ret, f.Addr)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret
+}
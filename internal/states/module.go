@@ -26,6 +26,14 @@ type Module struct {
 	// LocalValues contains the value for each named output value. The keys
 	// in this map are local value names.
 	LocalValues map[string]cty.Value
+
+	// ConfigHash is the content hash of the module configuration that was
+	// most recently applied to this module instance, as recorded in
+	// configs.Module.ContentHash. It is empty if no hash has been recorded.
+	//
+	// This does not count as content of the module, so a module that only
+	// has a ConfigHash is still considered empty and is pruned.
+	ConfigHash string
 }
 
 // NewModule constructs an empty module state for the given module address.
@@ -18,4 +18,23 @@ type OutputValue struct {
 	Addr      addrs.AbsOutputValue
 	Value     cty.Value
 	Sensitive bool
+
+	// Internal is set for root module outputs declared with internal = true,
+	// which must not be exposed outside of the configuration.
+	Internal bool
+}
+
+// PublicOutputValues returns the given output values without the internal
+// ones, which must not be exposed outside of the configuration.
+func PublicOutputValues(outputs map[string]*OutputValue) map[string]*OutputValue {
+	if outputs == nil {
+		return nil
+	}
+	ret := make(map[string]*OutputValue, len(outputs))
+	for name, os := range outputs {
+		if !os.Internal {
+			ret[name] = os
+		}
+	}
+	return ret
 }
@@ -6,6 +6,12 @@
 package remote
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
@@ -26,6 +32,18 @@ type ClientForcePusher interface {
 	EnableForcePush()
 }
 
+// ClientStreamer is an optional interface that allows a remote state
+// backend to upload a state snapshot from a reader while it is being
+// serialized, so that neither the backend nor its caller needs to copy the
+// whole snapshot into a buffer of its own.
+//
+// If a client implements this interface then State uses PutStream instead of
+// Put to persist snapshots.
+type ClientStreamer interface {
+	Client
+	PutStream(*StreamPayload) error
+}
+
 // ClientLocker is an optional interface that allows a remote state
 // backend to enable state lock/unlock.
 type ClientLocker interface {
@@ -47,5 +65,59 @@ type Payload struct {
 	Data []byte
 }
 
+// StreamPayload is a state snapshot to upload with ClientStreamer.PutStream.
+//
+// The snapshot is serialized while it is read, so its size and checksums are
+// only known once the whole payload has been read. PutStream must read it
+// only once, to the end, before calling Checksums.
+type StreamPayload struct {
+	body   io.Reader
+	size   int64
+	md5    hash.Hash
+	sha256 hash.Hash
+	eof    bool
+}
+
+// StreamChecksums are the size and the checksums of a StreamPayload.
+type StreamChecksums struct {
+	Size   int64
+	MD5    []byte
+	SHA256 []byte
+}
+
+// NewStreamPayload returns a payload that reads the snapshot from body and
+// calculates its size and checksums as it goes.
+func NewStreamPayload(body io.Reader) *StreamPayload {
+	return &StreamPayload{
+		body:   body,
+		md5:    md5.New(),
+		sha256: sha256.New(),
+	}
+}
+
+func (p *StreamPayload) Read(b []byte) (int, error) {
+	n, err := p.body.Read(b)
+	p.size += int64(n)
+	p.md5.Write(b[:n])
+	p.sha256.Write(b[:n])
+	if err == io.EOF {
+		p.eof = true
+	}
+	return n, err
+}
+
+// Checksums returns the size and the checksums of the snapshot. It returns
+// an error if the payload hasn't been read to the end yet.
+func (p *StreamPayload) Checksums() (*StreamChecksums, error) {
+	if !p.eof {
+		return nil, errors.New("the checksums of the state are not known before it has been read completely")
+	}
+	return &StreamChecksums{
+		Size:   p.size,
+		MD5:    p.md5.Sum(nil),
+		SHA256: p.sha256.Sum(nil),
+	}, nil
+}
+
 // Factory is the factory function to create a remote client.
 type Factory func(map[string]string) (Client, error)
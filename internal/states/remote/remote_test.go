@@ -8,6 +8,7 @@ package remote
 import (
 	"crypto/md5"
 	"encoding/json"
+	"io"
 	"testing"
 )
 
@@ -133,3 +134,27 @@ func (c *mockClientForcePusher) appendLog(method string, content []byte) {
 	}
 	c.log = append(c.log, mockClientRequest{method, contentVal})
 }
+
+// mockClientStreamer is like mockClient, but also implements PutStream, and
+// records the checksums of the payloads that it was given for use in test
+// assertions.
+type mockClientStreamer struct {
+	mockClient
+	checksums []*StreamChecksums
+}
+
+// Implements remote.ClientStreamer
+func (c *mockClientStreamer) PutStream(payload *StreamPayload) error {
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return err
+	}
+	checksums, err := payload.Checksums()
+	if err != nil {
+		return err
+	}
+	c.appendLog("PutStream", data)
+	c.current = data
+	c.checksums = append(c.checksums, checksums)
+	return nil
+}
@@ -8,6 +8,7 @@ package remote
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 
@@ -42,6 +43,7 @@ type State struct {
 	lineage, readLineage string
 	serial, readSerial   uint64
 	readEncryption       encryption.EncryptionStatus
+	forceReencrypt       bool
 	mu                   sync.Mutex
 	state, readState     *states.State
 	disableLocks         bool
@@ -55,6 +57,7 @@ type State struct {
 
 var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
+var _ statemgr.Reencrypter = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
 
 func NewState(client Client, enc encryption.StateEncryption) *State {
@@ -194,7 +197,7 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 		lineageUnchanged := s.readLineage != "" && s.lineage == s.readLineage
 		serialUnchanged := s.readSerial != 0 && s.serial == s.readSerial
 		stateUnchanged := statefile.StatesMarshalEqual(s.state, s.readState)
-		if stateUnchanged && lineageUnchanged && serialUnchanged && s.readEncryption != encryption.StatusMigration {
+		if stateUnchanged && lineageUnchanged && serialUnchanged && s.readEncryption != encryption.StatusMigration && !s.forceReencrypt {
 			// If the state, lineage or serial haven't changed at all then we have nothing to do.
 			return nil
 		}
@@ -221,15 +224,21 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 
 	f := statefile.New(s.state, s.lineage, s.serial)
 
-	var buf bytes.Buffer
-	err := statefile.Write(f, &buf, s.encryption)
-	if err != nil {
-		return err
-	}
+	if streamer, ok := s.Client.(ClientStreamer); ok {
+		if err := s.putStream(streamer, f); err != nil {
+			return err
+		}
+	} else {
+		var buf bytes.Buffer
+		err := statefile.Write(f, &buf, s.encryption)
+		if err != nil {
+			return err
+		}
 
-	err = s.Client.Put(buf.Bytes())
-	if err != nil {
-		return err
+		err = s.Client.Put(buf.Bytes())
+		if err != nil {
+			return err
+		}
 	}
 
 	// After we've successfully persisted, what we just wrote is our new
@@ -241,10 +250,51 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	s.readState = s.state.DeepCopy()
 	s.readLineage = s.lineage
 	s.readEncryption = encryption.StatusSatisfied
+	s.forceReencrypt = false
 	s.readSerial = s.serial
 	return nil
 }
 
+// putStream uploads the given state file with the given client while it is
+// being serialized, through a pipe, so that the serialized snapshot is never
+// copied into a buffer for the upload. The client calculates the size and
+// the checksums of the snapshot as it reads it.
+func (s *State) putStream(client ClientStreamer, f *statefile.File) error {
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := statefile.Write(f, pw, s.encryption)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	err := client.PutStream(NewStreamPayload(pr))
+	// If the client stopped reading early then this unblocks the writer, which
+	// must finish before we return because it reads the state.
+	pr.Close()
+	writeErr := <-written
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// statemgr.Reencrypter impl.
+func (s *State) EncryptionStatus() encryption.EncryptionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readEncryption
+}
+
+// statemgr.Reencrypter impl.
+func (s *State) ForceReencrypt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forceReencrypt = true
+}
+
 // ShouldPersistIntermediateState implements local.IntermediateStateConditionalPersister
 func (s *State) ShouldPersistIntermediateState(info *local.IntermediateStatePersistInfo) bool {
 	if s.disableIntermediateSnapshots {
@@ -6,6 +6,10 @@
 package remote
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
 	"log"
 	"sync"
 	"testing"
@@ -16,6 +20,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/enctest"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -848,3 +853,146 @@ func TestState_IsLockingEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestState_reencrypt(t *testing.T) {
+	// Start with an unencrypted snapshot, which the fallback configuration
+	// can still read.
+	var buf bytes.Buffer
+	f := statefile.New(states.NewState(), "lineage", 1)
+	if err := statefile.Write(f, &buf, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatal(err)
+	}
+	client := &mockClient{current: buf.Bytes()}
+	mgr := NewState(client, enctest.EncryptionWithFallback().State())
+
+	puts := func() int {
+		n := 0
+		for _, req := range client.log {
+			if req.Method == "Put" {
+				n++
+			}
+		}
+		return n
+	}
+
+	if err := mgr.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mgr.EncryptionStatus(), encryption.StatusMigration; got != want {
+		t.Fatalf("wrong encryption status %v; want %v", got, want)
+	}
+
+	// An unchanged state is written anyway, because it must be re-encrypted.
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := puts(); got != 1 {
+		t.Fatalf("wrong number of writes %d; want 1", got)
+	}
+	if got, want := mgr.EncryptionStatus(), encryption.StatusSatisfied; got != want {
+		t.Fatalf("wrong encryption status %v; want %v", got, want)
+	}
+	if ok, err := encryption.IsEncryptionPayload(client.current); err != nil || !ok {
+		t.Fatalf("state was not re-encrypted (err: %v)", err)
+	}
+
+	// Now there is nothing to do...
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := puts(); got != 1 {
+		t.Fatalf("wrong number of writes %d; want 1", got)
+	}
+
+	// ...unless the caller explicitly asks for the state to be re-encrypted.
+	mgr.ForceReencrypt()
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := puts(); got != 2 {
+		t.Fatalf("wrong number of writes %d; want 2", got)
+	}
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := puts(); got != 2 {
+		t.Fatalf("wrong number of writes %d; want 2", got)
+	}
+}
+
+func TestState_putStream(t *testing.T) {
+	client := &mockClientStreamer{}
+	mgr := NewState(client, encryption.StateEncryptionDisabled())
+
+	s := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"), false,
+		)
+	})
+	if err := mgr.WriteState(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var methods []string
+	for _, req := range client.log {
+		methods = append(methods, req.Method)
+	}
+	if diff := cmp.Diff([]string{"Get", "PutStream"}, methods); diff != "" {
+		t.Fatalf("wrong requests\n%s", diff)
+	}
+
+	payload := client.checksums[0]
+	if got, want := payload.Size, int64(len(client.current)); got != want {
+		t.Errorf("wrong size %d; want %d", got, want)
+	}
+	if got, want := payload.MD5, md5.Sum(client.current); !bytes.Equal(got, want[:]) {
+		t.Errorf("wrong MD5 %x; want %x", got, want)
+	}
+	if got, want := payload.SHA256, sha256.Sum256(client.current); !bytes.Equal(got, want[:]) {
+		t.Errorf("wrong SHA256 %x; want %x", got, want)
+	}
+
+	f, err := statefile.Read(bytes.NewReader(client.current), encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("uploaded state is invalid: %s", err)
+	}
+	if !statefile.StatesMarshalEqual(f.State, s) {
+		t.Errorf("wrong state uploaded\n%s", cmp.Diff(s, f.State))
+	}
+}
+
+// failingClientStreamer stops reading the payload after its first byte and
+// fails the upload.
+type failingClientStreamer struct {
+	mockClient
+}
+
+// Implements remote.ClientStreamer
+func (c *failingClientStreamer) PutStream(payload *StreamPayload) error {
+	if _, err := payload.Read(make([]byte, 1)); err != nil {
+		return err
+	}
+	if _, err := payload.Checksums(); err == nil {
+		return errors.New("checksums are available before the payload was read completely")
+	}
+	return errors.New("upload failed")
+}
+
+func TestState_putStreamFailed(t *testing.T) {
+	client := &failingClientStreamer{}
+	mgr := NewState(client, encryption.StateEncryptionDisabled())
+
+	if err := mgr.WriteState(states.NewState()); err != nil {
+		t.Fatal(err)
+	}
+	// The writer of the snapshot is blocked on the pipe until the upload has
+	// failed, so this must not hang.
+	err := mgr.PersistState(nil)
+	if err == nil || err.Error() != "upload failed" {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
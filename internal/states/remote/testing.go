@@ -37,6 +37,10 @@ func TestClient(t *testing.T, c Client) {
 		t.Fatalf("expected full state %q\n\ngot: %q", string(p.Data), string(data))
 	}
 
+	if streamer, ok := c.(ClientStreamer); ok {
+		testClientStreamer(t, streamer)
+	}
+
 	if err := c.Delete(); err != nil {
 		t.Fatalf("delete: %s", err)
 	}
@@ -50,6 +54,32 @@ func TestClient(t *testing.T, c Client) {
 	}
 }
 
+// testClientStreamer tests the PutStream method of a client, overwriting any
+// existing state.
+func testClientStreamer(t *testing.T, c ClientStreamer) {
+	var buf bytes.Buffer
+	s := statemgr.TestFullInitialState()
+	sf := statefile.New(s, "stub-lineage", 3)
+	err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	data := buf.Bytes()
+
+	err = c.PutStream(NewStreamPayload(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("put stream: %s", err)
+	}
+
+	p, err := c.Get()
+	if err != nil {
+		t.Fatalf("get: %s", err)
+	}
+	if !bytes.Equal(p.Data, data) {
+		t.Fatalf("expected full state %q\n\ngot: %q", string(data), string(p.Data))
+	}
+}
+
 // Test the lock implementation for a remote.Client.
 // This test requires 2 client instances, in order to have multiple remote
 // clients since some implementations may tie the client to the lock, or may
@@ -75,6 +75,14 @@ type ResourceInstance struct {
 	// the resource instance's provider configuration. This is only set
 	// when using provider iteration on resources or modules
 	ProviderKey addrs.InstanceKey
+
+	// Note is an optional free-form annotation that an operator attached to
+	// the resource instance using "tofu state annotate", such as a ticket
+	// reference explaining why the instance must not change. It belongs to
+	// the instance rather than to any particular object, and so it is kept
+	// when the instance's current object is updated, but discarded along
+	// with the instance once it has no objects left.
+	Note string
 }
 
 // NewResourceInstance constructs and returns a new ResourceInstance, ready to
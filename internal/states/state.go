@@ -43,6 +43,14 @@ type State struct {
 	// created by a version of OpenTofu that didn't yet support checks
 	// then this field will be nil.
 	CheckResults *CheckResults
+
+	// ApplyFailures records the resource instances whose most recent applies
+	// failed, keyed by the string form of their addresses. It is nil if
+	// there are none.
+	//
+	// Use the ApplyFailure, SetApplyFailure, and RemoveApplyFailure methods
+	// rather than accessing this map directly.
+	ApplyFailures map[string]*ApplyFailure
 }
 
 // NewState constructs a minimal empty state, containing an empty root module.
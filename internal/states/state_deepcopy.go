@@ -33,9 +33,17 @@ func (s *State) DeepCopy() *State {
 	for k, m := range s.Modules {
 		modules[k] = m.DeepCopy()
 	}
+	var applyFailures map[string]*ApplyFailure
+	if s.ApplyFailures != nil {
+		applyFailures = make(map[string]*ApplyFailure, len(s.ApplyFailures))
+		for k, f := range s.ApplyFailures {
+			applyFailures[k] = f.DeepCopy()
+		}
+	}
 	return &State{
-		Modules:      modules,
-		CheckResults: s.CheckResults.DeepCopy(),
+		Modules:       modules,
+		CheckResults:  s.CheckResults.DeepCopy(),
+		ApplyFailures: applyFailures,
 	}
 }
 
@@ -71,6 +79,7 @@ func (ms *Module) DeepCopy() *Module {
 		Resources:    resources,
 		OutputValues: outputValues,
 		LocalValues:  localValues,
+		ConfigHash:   ms.ConfigHash,
 	}
 }
 
@@ -121,6 +130,7 @@ func (i *ResourceInstance) DeepCopy() *ResourceInstance {
 		Current:     i.Current.DeepCopy(),
 		Deposed:     deposed,
 		ProviderKey: i.ProviderKey,
+		Note:        i.Note,
 	}
 }
 
@@ -236,5 +246,6 @@ func (os *OutputValue) DeepCopy() *OutputValue {
 		Addr:      os.Addr,
 		Value:     os.Value,
 		Sensitive: os.Sensitive,
+		Internal:  os.Internal,
 	}
 }
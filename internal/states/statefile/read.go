@@ -6,6 +6,7 @@
 package statefile
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -76,7 +77,7 @@ func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
 		return nil, ErrNoState
 	}
 
-	decrypted, status, err := enc.DecryptState(src)
+	decrypted, status, err := enc.DecryptState(context.TODO(), src)
 	if err != nil {
 		return nil, err
 	}
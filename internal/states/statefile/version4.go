@@ -6,6 +6,7 @@
 package statefile
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -316,6 +317,7 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 				},
 			}
 			os.Sensitive = fos.Sensitive
+			os.Internal = fos.Internal
 
 			ty, err := ctyjson.UnmarshalType([]byte(fos.ValueTypeRaw))
 			if err != nil {
@@ -353,6 +355,44 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 		diags = diags.Append(moreDiags)
 	}
 
+	for addrStr, hash := range sV4.ModuleConfigHashes {
+		moduleAddr, addrDiags := addrs.ParseModuleInstanceStr(addrStr)
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			continue
+		}
+		state.EnsureModule(moduleAddr).ConfigHash = hash
+	}
+
+	for addrStr, note := range sV4.Annotations {
+		instAddr, addrDiags := addrs.ParseAbsResourceInstanceStr(addrStr)
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			continue
+		}
+		// An annotation for an instance that is no longer in the state is
+		// just discarded, and will be dropped when the state is next saved.
+		if is := state.ResourceInstance(instAddr); is != nil {
+			is.Note = note
+		}
+	}
+
+	for addrStr, fV4 := range sV4.ApplyFailures {
+		instAddr, addrDiags := addrs.ParseAbsResourceInstanceStr(addrStr)
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			continue
+		}
+		// Unlike annotations, apply failures are kept for instances that
+		// aren't in the state, since creating them may be what failed.
+		state.SetApplyFailure(&states.ApplyFailure{
+			Addr:        instAddr,
+			Count:       fV4.Count,
+			LastError:   fV4.LastError,
+			Quarantined: fV4.Quarantined,
+		})
+	}
+
 	file.State = state
 	return file, diags
 }
@@ -407,6 +447,7 @@ func writeStateV4(file *File, w io.Writer, enc encryption.StateEncryption) tfdia
 
 		sV4.RootOutputs[name] = outputStateV4{
 			Sensitive:    os.Sensitive,
+			Internal:     os.Internal,
 			ValueRaw:     json.RawMessage(src),
 			ValueTypeRaw: json.RawMessage(typeSrc),
 		}
@@ -478,6 +519,41 @@ func writeStateV4(file *File, w io.Writer, enc encryption.StateEncryption) tfdia
 
 	sV4.CheckResults = encodeCheckResultsV4(file.State.CheckResults)
 
+	for _, ms := range file.State.Modules {
+		if ms.ConfigHash == "" || ms.Addr.IsRoot() {
+			continue
+		}
+		if sV4.ModuleConfigHashes == nil {
+			sV4.ModuleConfigHashes = map[string]string{}
+		}
+		sV4.ModuleConfigHashes[ms.Addr.String()] = ms.ConfigHash
+	}
+
+	for _, ms := range file.State.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				if is.Note == "" {
+					continue
+				}
+				if sV4.Annotations == nil {
+					sV4.Annotations = map[string]string{}
+				}
+				sV4.Annotations[rs.Addr.Instance(key).String()] = is.Note
+			}
+		}
+	}
+
+	for _, f := range file.State.ApplyFailures {
+		if sV4.ApplyFailures == nil {
+			sV4.ApplyFailures = map[string]applyFailureV4{}
+		}
+		sV4.ApplyFailures[f.Addr.String()] = applyFailureV4{
+			Count:       f.Count,
+			LastError:   f.LastError,
+			Quarantined: f.Quarantined,
+		}
+	}
+
 	sV4.normalize()
 
 	src, err := json.Marshal(sV4)
@@ -492,7 +568,7 @@ func writeStateV4(file *File, w io.Writer, enc encryption.StateEncryption) tfdia
 	}
 	src = append(src, '\n')
 
-	encrypted, encDiags := enc.EncryptState(src)
+	encrypted, encDiags := enc.EncryptState(context.TODO(), src)
 	diags = diags.Append(encDiags)
 
 	_, err = w.Write(encrypted)
@@ -748,6 +824,27 @@ type stateV4 struct {
 	RootOutputs      map[string]outputStateV4 `json:"outputs"`
 	Resources        []resourceStateV4        `json:"resources"`
 	CheckResults     []checkResultsV4         `json:"check_results"`
+
+	// ModuleConfigHashes records the content hash of the configuration that
+	// was last applied to each module instance, keyed by module instance
+	// address. It is omitted when no hashes have been recorded.
+	ModuleConfigHashes map[string]string `json:"module_config_hashes,omitempty"`
+
+	// Annotations records the operator notes attached to resource instances,
+	// keyed by absolute resource instance address. It is omitted when no
+	// instances have notes.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ApplyFailures records the consecutive apply failures of resource
+	// instances, keyed by absolute resource instance address. It is omitted
+	// when no recent applies have failed.
+	ApplyFailures map[string]applyFailureV4 `json:"apply_failures,omitempty"`
+}
+
+type applyFailureV4 struct {
+	Count       int    `json:"count"`
+	LastError   string `json:"last_error"`
+	Quarantined bool   `json:"quarantined,omitempty"`
 }
 
 // normalize makes some in-place changes to normalize the way items are
@@ -764,6 +861,7 @@ type outputStateV4 struct {
 	ValueRaw     json.RawMessage `json:"value"`
 	ValueTypeRaw json.RawMessage `json:"type"`
 	Sensitive    bool            `json:"sensitive,omitempty"`
+	Internal     bool            `json:"internal,omitempty"`
 }
 
 // Note: the ProviderConfig field is only set on either the resource or the resource instance object
@@ -0,0 +1,27 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+// Reencrypter is an optional interface for state managers that store
+// encrypted state snapshots, allowing callers to detect snapshots that were
+// not encrypted with the primary encryption configuration and to rewrite
+// them with it.
+type Reencrypter interface {
+	// EncryptionStatus returns how the most recently refreshed snapshot was
+	// decrypted. encryption.StatusMigration indicates that it was decrypted
+	// with a fallback configuration, or was not encrypted at all while
+	// encryption is configured.
+	EncryptionStatus() encryption.EncryptionStatus
+
+	// ForceReencrypt ensures that the next call to PersistState writes the
+	// current snapshot, encrypted with the primary encryption configuration,
+	// even if the snapshot has not changed since it was last refreshed.
+	ForceReencrypt()
+}
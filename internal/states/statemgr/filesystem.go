@@ -66,9 +66,11 @@ type Filesystem struct {
 }
 
 var (
-	_ Full           = (*Filesystem)(nil)
-	_ PersistentMeta = (*Filesystem)(nil)
-	_ Migrator       = (*Filesystem)(nil)
+	_ Full              = (*Filesystem)(nil)
+	_ PersistentMeta    = (*Filesystem)(nil)
+	_ Migrator          = (*Filesystem)(nil)
+	_ Reencrypter       = (*Filesystem)(nil)
+	_ IntentionRecorder = (*Filesystem)(nil)
 )
 
 // NewFilesystem creates a filesystem-based state manager that reads and writes
@@ -325,6 +327,23 @@ func (s *Filesystem) refreshState() error {
 	return nil
 }
 
+// EncryptionStatus is an implementation of Reencrypter.
+func (s *Filesystem) EncryptionStatus() encryption.EncryptionStatus {
+	defer s.mutex()()
+
+	if s.readFile == nil {
+		return encryption.StatusUnknown
+	}
+	return s.readFile.EncryptionStatus
+}
+
+// ForceReencrypt is an implementation of Reencrypter.
+func (s *Filesystem) ForceReencrypt() {
+	// The filesystem state manager rewrites the whole file, encrypted with
+	// the primary configuration, every time the state is persisted, so there
+	// is nothing to do here.
+}
+
 // Lock implements Locker using filesystem discretionary locks.
 func (s *Filesystem) Lock(info *LockInfo) (string, error) {
 	defer s.mutex()()
@@ -0,0 +1,191 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaxIntentions is the number of most recent intentions that a state manager
+// keeps. Older intentions are discarded when new ones are recorded.
+const MaxIntentions = 20
+
+// Intention is a lightweight record that an operation intends to change the
+// objects tracked by a particular state lineage, such as a plan that is
+// expected to be applied later.
+//
+// Intentions are not part of the state itself. They are recorded so that an
+// apply of a saved plan can detect that another plan or apply for the same
+// workspace began after the plan was created, in which case one of the two
+// is likely to be working from stale information.
+type Intention struct {
+	// ID is a unique identifier for the intention.
+	ID string
+
+	// Operation is the name of the operation that recorded the intention,
+	// such as "plan" or "apply".
+	Operation string
+
+	// Who is the user and host that recorded the intention.
+	Who string
+
+	// Version is the OpenTofu version that recorded the intention.
+	Version string
+
+	// Created is when the intention was recorded.
+	Created time.Time
+
+	// Lineage is the lineage of the state that the operation was based on.
+	Lineage string
+
+	// PlanHash is the hash of the saved plan file that the operation
+	// created, if any, as returned by planfile.Hash.
+	PlanHash string `json:",omitempty"`
+}
+
+// NewIntention returns a new intention for the given operation, with the
+// same ID, Who, Version and Created values that NewLockInfo would use.
+func NewIntention(operation, lineage, planHash string) *Intention {
+	info := NewLockInfo()
+	return &Intention{
+		ID:        info.ID,
+		Operation: operation,
+		Who:       info.Who,
+		Version:   info.Version,
+		Created:   info.Created,
+		Lineage:   lineage,
+		PlanHash:  planHash,
+	}
+}
+
+// String returns a short description of the intention for use in messages.
+func (i *Intention) String() string {
+	return fmt.Sprintf("%s by %s at %s", i.Operation, i.Who, i.Created.Format(time.RFC3339))
+}
+
+// IntentionRecorder is an optional interface for state managers that can
+// store intentions alongside the persistent state snapshots.
+//
+// Callers should normally hold the state lock while recording intentions,
+// because implementations may update the stored intentions with a
+// read-modify-write sequence.
+type IntentionRecorder interface {
+	// RecordIntention stores the given intention, discarding the oldest
+	// stored intentions if there are more than MaxIntentions.
+	RecordIntention(*Intention) error
+
+	// Intentions returns the stored intentions, oldest first.
+	Intentions() ([]*Intention, error)
+}
+
+// AppendIntention appends the given intention to the given list, discarding
+// the oldest intentions if the result would have more than MaxIntentions.
+//
+// This is a helper for implementations of IntentionRecorder.
+func AppendIntention(list []*Intention, intention *Intention) []*Intention {
+	list = append(list, intention)
+	if len(list) > MaxIntentions {
+		list = list[len(list)-MaxIntentions:]
+	}
+	return list
+}
+
+// LaterIntentions returns the intentions for the given lineage that were
+// recorded after the plan with the given hash, excluding the plan's own
+// intention.
+//
+// The plan's own intention is found using planHash. If there is no intention
+// for that plan, such as when it was created by a version of OpenTofu that
+// did not record intentions, planCreated is used instead.
+func LaterIntentions(list []*Intention, lineage, planHash string, planCreated time.Time) []*Intention {
+	since := planCreated
+	var own *Intention
+	for _, intention := range list {
+		if planHash != "" && intention.PlanHash == planHash {
+			own = intention
+		}
+	}
+	if own != nil {
+		since = own.Created
+	}
+
+	var ret []*Intention
+	for _, intention := range list {
+		if intention == own || intention.Lineage != lineage {
+			continue
+		}
+		if intention.Created.After(since) {
+			ret = append(ret, intention)
+		}
+	}
+	return ret
+}
+
+// intentionsPath returns the path of the file that intentions for the
+// state file are stored in, which is next to the lock info file.
+func (s *Filesystem) intentionsPath() string {
+	stateDir, stateName := filepath.Split(s.path)
+	if stateName == "" {
+		panic("empty state file path")
+	}
+
+	if stateName[0] == '.' {
+		stateName = stateName[1:]
+	}
+
+	return filepath.Join(stateDir, fmt.Sprintf(".%s.intentions", stateName))
+}
+
+// Intentions is part of our implementation of IntentionRecorder.
+func (s *Filesystem) Intentions() ([]*Intention, error) {
+	defer s.mutex()()
+	return s.intentions()
+}
+
+func (s *Filesystem) intentions() ([]*Intention, error) {
+	path := s.intentionsPath()
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*Intention
+	if err := json.Unmarshal(src, &ret); err != nil {
+		return nil, fmt.Errorf("could not unmarshal intentions from %s: %w", path, err)
+	}
+	return ret, nil
+}
+
+// RecordIntention is part of our implementation of IntentionRecorder.
+func (s *Filesystem) RecordIntention(intention *Intention) error {
+	defer s.mutex()()
+
+	list, err := s.intentions()
+	if err != nil {
+		return err
+	}
+	list = AppendIntention(list, intention)
+
+	src, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.intentionsPath()
+	log.Printf("[TRACE] statemgr.Filesystem: recording %s intention in %s", intention.Operation, path)
+	if err := os.WriteFile(path, src, 0600); err != nil {
+		return fmt.Errorf("could not write intentions for %q: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+func TestFilesystem_intentions(t *testing.T) {
+	ls := NewFilesystem(filepath.Join(t.TempDir(), "terraform.tfstate"), encryption.StateEncryptionDisabled())
+
+	got, err := ls.Intentions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("unexpected intentions before any were recorded: %#v", got)
+	}
+
+	for i := 0; i < MaxIntentions+2; i++ {
+		if err := ls.RecordIntention(NewIntention("plan", "lineage", fmt.Sprintf("hash%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err = ls.Intentions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != MaxIntentions {
+		t.Fatalf("wrong number of intentions %d; want %d", len(got), MaxIntentions)
+	}
+	// The oldest intentions are discarded.
+	if got, want := got[0].PlanHash, "hash2"; got != want {
+		t.Errorf("wrong oldest intention %q; want %q", got, want)
+	}
+	if got, want := got[len(got)-1].PlanHash, fmt.Sprintf("hash%d", MaxIntentions+1); got != want {
+		t.Errorf("wrong newest intention %q; want %q", got, want)
+	}
+}
+
+func TestLaterIntentions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	own := &Intention{ID: "own", Operation: "plan", Lineage: "a", PlanHash: "abc", Created: base.Add(time.Second)}
+	before := &Intention{ID: "before", Operation: "plan", Lineage: "a", Created: base.Add(-time.Minute)}
+	later := &Intention{ID: "later", Operation: "plan", Lineage: "a", Created: base.Add(time.Minute)}
+	otherLineage := &Intention{ID: "other", Operation: "apply", Lineage: "b", Created: base.Add(time.Minute)}
+	list := []*Intention{before, own, later, otherLineage}
+
+	tests := map[string]struct {
+		planHash string
+		want     []string
+	}{
+		"own intention found": {
+			planHash: "abc",
+			want:     []string{"later"},
+		},
+		"own intention not found": {
+			// Falls back to the plan timestamp, which is earlier than the
+			// intention that was recorded after writing the plan file.
+			planHash: "missing",
+			want:     []string{"own", "later"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, intention := range LaterIntentions(list, "a", test.planHash, base) {
+				got = append(got, intention.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("wrong result %v; want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// LockRetryPolicy describes how LockWithRetryPolicy retries acquiring a lock
+// that is held by another process.
+//
+// The delay before the first retry is InitialDelay, and each subsequent delay
+// is the previous one multiplied by Multiplier, up to MaxDelay. Each delay is
+// then randomly adjusted by up to Jitter (a fraction between 0 and 1) in
+// either direction, so that several processes waiting for the same lock
+// don't all retry at the same moment.
+type LockRetryPolicy struct {
+	InitialDelay time.Duration
+	Multiplier   float64
+	MaxDelay     time.Duration
+	Jitter       float64
+
+	// MaxAttempts is the maximum number of times to try acquiring the lock,
+	// including the first attempt. Zero means no limit.
+	MaxAttempts int
+
+	// MaxElapsed is the maximum total time to spend trying to acquire the
+	// lock. Zero means that only the context passed to LockWithRetryPolicy
+	// limits the time spent.
+	MaxElapsed time.Duration
+}
+
+// DefaultLockRetryPolicy returns the policy used when there is no explicit
+// configuration: retry after one second, doubling the delay up to sixteen
+// seconds, without jitter or a limit on the number of attempts.
+func DefaultLockRetryPolicy() LockRetryPolicy {
+	return LockRetryPolicy{
+		InitialDelay: time.Second,
+		Multiplier:   2,
+		MaxDelay:     16 * time.Second,
+	}
+}
+
+// Validate returns an error if the policy can't be used to retry a lock.
+func (p LockRetryPolicy) Validate() error {
+	switch {
+	case p.InitialDelay <= 0:
+		return fmt.Errorf("the initial delay must be greater than zero")
+	case p.Multiplier < 1:
+		return fmt.Errorf("the multiplier must be at least 1")
+	case p.MaxDelay < p.InitialDelay:
+		return fmt.Errorf("the maximum delay must not be less than the initial delay")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("the jitter must be between 0 and 1")
+	case p.MaxAttempts < 0:
+		return fmt.Errorf("the maximum number of attempts must not be negative")
+	case p.MaxElapsed < 0:
+		return fmt.Errorf("the maximum elapsed time must not be negative")
+	}
+	return nil
+}
+
+// delay returns the delay before the given retry, counting from 1, without
+// jitter.
+func (p LockRetryPolicy) delay(retry int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// withJitter randomly adjusts the given delay by up to the policy's jitter.
+func (p LockRetryPolicy) withJitter(delay time.Duration) time.Duration {
+	if p.Jitter == 0 {
+		return delay
+	}
+	factor := 1 + p.Jitter*(2*rand.Float64()-1) //nolint:gosec // jitter doesn't need a secure source
+	return time.Duration(float64(delay) * factor)
+}
+
+// LockWait describes a failed attempt to acquire a lock that is held by
+// another process, reported to the callback of LockWithRetryPolicy before
+// waiting to retry.
+type LockWait struct {
+	// Attempt is the number of attempts made so far, starting at 1.
+	Attempt int
+
+	// MaxAttempts is the maximum number of attempts, or zero if there is
+	// no limit.
+	MaxAttempts int
+
+	// Delay is how long OpenTofu will wait before the next attempt.
+	Delay time.Duration
+
+	// Elapsed is the time spent trying to acquire the lock so far.
+	Elapsed time.Duration
+
+	// Info describes the lock currently held, if the state manager returned
+	// that information.
+	Info *LockInfo
+}
+
+// LockWithRetryPolicy locks the given state manager, retrying according to
+// the given policy while the lock is held by another process, until the
+// policy's limits are reached or the context is cancelled.
+//
+// If onWait is not nil, it's called before each wait.
+func LockWithRetryPolicy(ctx context.Context, s Locker, info *LockInfo, policy LockRetryPolicy, onWait func(LockWait)) (string, error) {
+	if policy.MaxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.MaxElapsed)
+		defer cancel()
+	}
+
+	start := time.Now()
+	retries := 0
+	for attempt := 1; ; attempt++ {
+		id, err := s.Lock(info)
+		if err == nil {
+			return id, nil
+		}
+
+		le, ok := err.(*LockError)
+		if !ok {
+			// not a lock error, so we can't retry
+			return "", err
+		}
+
+		if !le.Retriable() {
+			return "", err
+		}
+
+		if postLockHook != nil {
+			postLockHook()
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return "", err
+		}
+
+		// Lock() can be repeated without sleep
+		if le.RetriableWithoutDelay() {
+			continue
+		}
+
+		retries++
+		delay := policy.withJitter(policy.delay(retries))
+		if ctx.Err() != nil {
+			// No point in reporting a wait that won't happen.
+			return "", err
+		}
+		if onWait != nil {
+			onWait(LockWait{
+				Attempt:     attempt,
+				MaxAttempts: policy.MaxAttempts,
+				Delay:       delay,
+				Elapsed:     time.Since(start),
+				Info:        le.Info,
+			})
+		}
+
+		// there's an existing lock, wait and try again
+		select {
+		case <-ctx.Done():
+			// return the last lock error with the info
+			return "", err
+		case <-time.After(delay):
+		}
+	}
+}
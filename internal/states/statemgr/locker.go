@@ -83,46 +83,10 @@ var postLockHook func()
 // for both timeout and cancellation.
 //
 // This method has a built-in retry/backoff behavior up to the context's
-// timeout.
+// timeout, following DefaultLockRetryPolicy. Use LockWithRetryPolicy to
+// customize the backoff.
 func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, error) {
-	delay := time.Second
-	maxDelay := 16 * time.Second
-	for {
-		id, err := s.Lock(info)
-		if err == nil {
-			return id, nil
-		}
-
-		le, ok := err.(*LockError)
-		if !ok {
-			// not a lock error, so we can't retry
-			return "", err
-		}
-
-		if !le.Retriable() {
-			return "", err
-		}
-
-		if postLockHook != nil {
-			postLockHook()
-		}
-
-		// Lock() can be repeated without sleep
-		if le.RetriableWithoutDelay() {
-			continue
-		}
-
-		// there's an existing lock, wait and try again
-		select {
-		case <-ctx.Done():
-			// return the last lock error with the info
-			return "", err
-		case <-time.After(delay):
-			if delay < maxDelay {
-				delay *= 2
-			}
-		}
-	}
+	return LockWithRetryPolicy(ctx, s, info, DefaultLockRetryPolicy(), nil)
 }
 
 // LockInfo stores lock metadata.
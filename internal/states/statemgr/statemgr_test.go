@@ -94,6 +94,82 @@ func TestLockWithContext(t *testing.T) {
 	}
 }
 
+func TestLockWithRetryPolicy(t *testing.T) {
+	s := NewFullFake(nil, TestFullInitialState())
+
+	if _, err := s.Lock(NewLockInfo()); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := LockRetryPolicy{
+		InitialDelay: time.Millisecond,
+		Multiplier:   3,
+		MaxDelay:     5 * time.Millisecond,
+		MaxAttempts:  4,
+	}
+	var waits []LockWait
+	_, err := LockWithRetryPolicy(context.Background(), s, NewLockInfo(), policy, func(w LockWait) {
+		waits = append(waits, w)
+	})
+	if err == nil {
+		t.Fatal("lock should have failed after the maximum number of attempts")
+	}
+
+	// The last attempt fails without waiting.
+	if len(waits) != 3 {
+		t.Fatalf("wrong number of waits %d", len(waits))
+	}
+	wantDelays := []time.Duration{time.Millisecond, 3 * time.Millisecond, 5 * time.Millisecond}
+	for i, w := range waits {
+		if w.Attempt != i+1 {
+			t.Errorf("wait %d has attempt %d", i, w.Attempt)
+		}
+		if w.MaxAttempts != 4 {
+			t.Errorf("wait %d has max attempts %d", i, w.MaxAttempts)
+		}
+		if w.Delay != wantDelays[i] {
+			t.Errorf("wait %d has delay %s, want %s", i, w.Delay, wantDelays[i])
+		}
+		if w.Info == nil {
+			t.Errorf("wait %d has no lock info", i)
+		}
+	}
+}
+
+func TestLockRetryPolicy_jitter(t *testing.T) {
+	policy := DefaultLockRetryPolicy()
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.withJitter(time.Second)
+		if got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("delay %s out of the jitter range", got)
+		}
+	}
+}
+
+func TestLockRetryPolicy_Validate(t *testing.T) {
+	if err := DefaultLockRetryPolicy().Validate(); err != nil {
+		t.Fatalf("default policy is invalid: %s", err)
+	}
+
+	for name, modify := range map[string]func(p *LockRetryPolicy){
+		"no initial delay":     func(p *LockRetryPolicy) { p.InitialDelay = 0 },
+		"shrinking delay":      func(p *LockRetryPolicy) { p.Multiplier = 0.5 },
+		"small max delay":      func(p *LockRetryPolicy) { p.MaxDelay = time.Millisecond },
+		"jitter out of range":  func(p *LockRetryPolicy) { p.Jitter = 1.5 },
+		"negative attempts":    func(p *LockRetryPolicy) { p.MaxAttempts = -1 },
+		"negative max elapsed": func(p *LockRetryPolicy) { p.MaxElapsed = -time.Second },
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := DefaultLockRetryPolicy()
+			modify(&policy)
+			if err := policy.Validate(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	os.Exit(m.Run())
@@ -102,6 +102,17 @@ func (s *SyncState) SetOutputValue(addr addrs.AbsOutputValue, value cty.Value, s
 	ms.SetOutputValue(addr.OutputValue.Name, value, sensitive)
 }
 
+// SetInternalOutputValue is like SetOutputValue, but marks the output value
+// as internal so that it is not exposed outside of the configuration.
+func (s *SyncState) SetInternalOutputValue(addr addrs.AbsOutputValue, value cty.Value, sensitive bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ms := s.state.EnsureModule(addr.Module)
+	os := ms.SetOutputValue(addr.OutputValue.Name, value, sensitive)
+	os.Internal = true
+}
+
 // RemoveOutputValue removes the stored value for the output value with the
 // given address.
 //
@@ -46,6 +46,18 @@ type ContextOpts struct {
 	Provisioners map[string]provisioners.Factory
 	Encryption   encryption.Encryption
 
+	// SchedulePolicy decides the order in which graph nodes that are ready
+	// to execute are given one of the Parallelism slots. The zero value
+	// selects ScheduleFIFO.
+	SchedulePolicy SchedulePolicy
+
+	// QuarantineAfter is the number of consecutive applies in which a
+	// resource instance must fail before Apply quarantines it, so that
+	// future plans skip it until it's released. Zero disables tracking the
+	// apply failures, although plans still skip the resource instances that
+	// were already quarantined.
+	QuarantineAfter int
+
 	UIInput UIInput
 }
 
@@ -85,7 +97,12 @@ type Context struct {
 	sh      *stopHook
 	uiInput UIInput
 
-	parallelSem         Semaphore
+	// applyFailures collects the outcomes of each apply when
+	// quarantineAfter is positive, and is nil otherwise.
+	applyFailures   *applyFailureHook
+	quarantineAfter int
+
+	scheduler           *scheduler
 	l                   sync.Mutex // Lock acquired during any task
 	providerInputConfig map[string]map[string]cty.Value
 	runCond             *sync.Cond
@@ -134,6 +151,20 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		par = 10
 	}
 
+	if opts.QuarantineAfter < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid quarantine threshold",
+			fmt.Sprintf("The number of failures after which to quarantine a resource instance must not be negative. Not %d.", opts.QuarantineAfter),
+		))
+		return nil, diags
+	}
+	var applyFailures *applyFailureHook
+	if opts.QuarantineAfter > 0 {
+		applyFailures = &applyFailureHook{results: make(map[string]applyFailureResult)}
+		hooks = append(hooks, applyFailures)
+	}
+
 	plugins := newContextPlugins(opts.Providers, opts.Provisioners)
 
 	log.Printf("[TRACE] tofu.NewContext: complete")
@@ -145,14 +176,23 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 
 		plugins: plugins,
 
-		parallelSem:         NewSemaphore(par),
+		scheduler:           newScheduler(par, opts.SchedulePolicy),
 		providerInputConfig: make(map[string]map[string]cty.Value),
 		sh:                  sh,
 
+		applyFailures:   applyFailures,
+		quarantineAfter: opts.QuarantineAfter,
+
 		encryption: opts.Encryption,
 	}, diags
 }
 
+// Schemas returns the schemas of the providers and provisioners used by the
+// given configuration and state.
+//
+// If some of the schemas fail to load, the returned diagnostics have errors
+// but the returned schemas are still usable: the providers whose schemas
+// failed to load have empty schemas.
 func (c *Context) Schemas(config *configs.Config, state *states.State) (*Schemas, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -163,7 +203,6 @@ func (c *Context) Schemas(config *configs.Config, state *states.State) (*Schemas
 			"Failed to load plugin schemas",
 			fmt.Sprintf("Error while loading schemas for plugin components: %s.", err),
 		))
-		return nil, diags
 	}
 	return ret, diags
 }
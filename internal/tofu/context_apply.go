@@ -76,6 +76,10 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 		}
 	}
 
+	if c.applyFailures != nil {
+		c.applyFailures.reset()
+	}
+
 	providerFunctionTracker := make(ProviderFunctionMapping)
 
 	graph, operation, diags := c.applyGraph(plan, config, providerFunctionTracker)
@@ -114,6 +118,17 @@ func (c *Context) Apply(ctx context.Context, plan *plans.Plan, config *configs.C
 		// verified that it'd be safe to do so.
 		newState.PruneResourceHusks()
 	}
+	if c.applyFailures != nil {
+		diags = diags.Append(c.applyFailures.record(newState, c.quarantineAfter))
+	}
+	// The plan skipped any quarantined resource instances, so an apply
+	// doesn't bring their modules in line with the configuration either.
+	if plan.UIMode == plans.NormalMode && len(plan.TargetAddrs) == 0 && len(plan.ExcludeAddrs) == 0 && len(newState.QuarantinedResourceInstances()) == 0 && !diags.HasErrors() {
+		// Only a complete and successful apply of a normal plan brings every
+		// module instance in line with its current configuration, so this
+		// is the only time we record the configuration that was applied.
+		recordModuleConfigHashes(config, newState)
+	}
 
 	if len(plan.TargetAddrs) > 0 || len(plan.ExcludeAddrs) > 0 {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -164,6 +179,22 @@ func (c *Context) applyGraph(plan *plans.Plan, config *configs.Config, providerF
 			SourceType: ValueFromPlan,
 		}
 	}
+	for name, dyVal := range plan.EphemeralVariableValues {
+		val, err := dyVal.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid variable value in plan",
+				fmt.Sprintf("Invalid value for ephemeral variable %q: %s.", name, err),
+			))
+			continue
+		}
+
+		variables[name] = &InputValue{
+			Value:      val,
+			SourceType: ValueFromPlan,
+		}
+	}
 	if diags.HasErrors() {
 		return nil, walkApply, diags
 	}
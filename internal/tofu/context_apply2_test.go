@@ -4828,6 +4828,9 @@ variable "other_var" {
 	})
 
 	t.Run("circular", func(t *testing.T) {
+		// Variables can refer to each other in their validation rules,
+		// because the rules only depend on the values of the other
+		// variables and not on their validation.
 		input := InputValuesFromCaller(map[string]cty.Value{
 			"root_var":  cty.NumberIntVal(10),
 			"other_var": cty.NumberIntVal(10),
@@ -4835,15 +4838,29 @@ variable "other_var" {
 
 		ctx := testContext2(t, &ContextOpts{})
 
+		_, diags := ctx.Plan(context.Background(), circular, nil, &PlanOpts{
+			SetVariables: input,
+		})
+		assertNoErrors(t, diags)
+	})
+
+	t.Run("circular invalid", func(t *testing.T) {
+		input := InputValuesFromCaller(map[string]cty.Value{
+			"root_var":  cty.NumberIntVal(10),
+			"other_var": cty.NumberIntVal(5),
+		})
+
+		ctx := testContext2(t, &ContextOpts{})
+
 		_, diags := ctx.Plan(context.Background(), circular, nil, &PlanOpts{
 			SetVariables: input,
 		})
 		if !diags.HasErrors() || len(diags) != 2 {
-			t.Fatal("expected cycle failure")
+			t.Fatalf("expected two validation failures, got %s", diags.ErrWithWarnings())
 		}
 		for _, diag := range diags {
-			if !strings.HasPrefix(diag.Description().Summary, "Cycle: ") {
-				t.Fatalf("Expected cycle error, got %#v", diag.Description())
+			if got, want := diag.Description().Summary, "Invalid value for variable"; got != want {
+				t.Fatalf("wrong error %q, want %q", got, want)
 			}
 		}
 	})
@@ -4966,3 +4983,940 @@ variable "res_data" {
 		t.Fatalf("Expected: %q, got %q", want, got)
 	}
 }
+
+func TestContext2Apply_adoptExisting(t *testing.T) {
+	tests := map[string]struct {
+		adopt       bool
+		id          string
+		importBlock bool
+		exists      bool
+		replace     bool
+		wantPlan    plans.Action
+		wantWarn    string
+		wantErr     string
+	}{
+		"adopt": {
+			adopt:    true,
+			id:       `"existing"`,
+			exists:   true,
+			wantPlan: plans.Update,
+		},
+		"nothing to adopt": {
+			adopt:    true,
+			id:       `"existing"`,
+			wantPlan: plans.Create,
+		},
+		"unknown id": {
+			adopt:    true,
+			exists:   true,
+			wantPlan: plans.Create,
+			wantWarn: "adoption was skipped because its id is not known until the object is created",
+		},
+		"requires replace": {
+			adopt:   true,
+			id:      `"existing"`,
+			exists:  true,
+			replace: true,
+			wantErr: "OpenTofu will not replace an object that it did not create",
+		},
+		"not set": {
+			id:       `"existing"`,
+			exists:   true,
+			wantPlan: plans.Create,
+		},
+		"import block": {
+			adopt:       true,
+			importBlock: true,
+			exists:      true,
+			wantPlan:    plans.Update,
+		},
+		"import block with nothing to adopt": {
+			adopt:       true,
+			importBlock: true,
+			wantPlan:    plans.Create,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id := ""
+			if test.id != "" {
+				id = "id = " + test.id
+			}
+			files := map[string]string{
+				"main.tf": fmt.Sprintf(`
+resource "test_resource" "a" {
+  %s
+  value = "new"
+
+  lifecycle {
+    adopt_existing = %t
+  }
+}
+`, id, test.adopt),
+			}
+			if test.importBlock {
+				// The ID to adopt comes from the import block instead.
+				files["import.tf"] = `
+import {
+  to = test_resource.a
+  id = "existing"
+}
+`
+			}
+			m := testModuleInline(t, files)
+
+			p := testProvider("test")
+			p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+				ResourceTypes: map[string]*configschema.Block{
+					"test_resource": {
+						Attributes: map[string]*configschema.Attribute{
+							"id":    {Type: cty.String, Optional: true, Computed: true},
+							"value": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			})
+			p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+				resp.PlannedState = req.ProposedNewState
+				if req.PriorState.IsNull() {
+					if req.Config.GetAttr("id").IsNull() {
+						resp.PlannedState = cty.ObjectVal(map[string]cty.Value{
+							"id":    cty.UnknownVal(cty.String),
+							"value": req.Config.GetAttr("value"),
+						})
+					}
+					return resp
+				}
+				if test.replace {
+					resp.RequiresReplace = []cty.Path{cty.GetAttrPath("value")}
+				}
+				return resp
+			}
+			p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+				if req.PriorState.IsNull() && test.exists && test.id != "" {
+					resp.Diagnostics = resp.Diagnostics.Append(errors.New("resource with id \"existing\" already exists"))
+					return resp
+				}
+				resp.NewState = req.PlannedState
+				if !resp.NewState.GetAttr("id").IsKnown() {
+					resp.NewState = cty.ObjectVal(map[string]cty.Value{
+						"id":    cty.StringVal("new"),
+						"value": req.PlannedState.GetAttr("value"),
+					})
+				}
+				return resp
+			}
+			p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+				if req.ID != "existing" {
+					t.Errorf("wrong import ID %q", req.ID)
+				}
+				return providers.ImportResourceStateResponse{
+					ImportedResources: []providers.ImportedResource{
+						{TypeName: "test_resource", State: cty.ObjectVal(map[string]cty.Value{
+							"id":    cty.StringVal(req.ID),
+							"value": cty.NullVal(cty.String),
+						})},
+					},
+				}
+			}
+			p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+				if !test.exists {
+					return providers.ReadResourceResponse{NewState: cty.NullVal(req.PriorState.Type())}
+				}
+				return providers.ReadResourceResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+					"id":    req.PriorState.GetAttr("id"),
+					"value": cty.StringVal("old"),
+				})}
+			}
+
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+			plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+			if test.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatal("expected the plan to fail")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+					t.Fatalf("wrong error %q; want it to contain %q", got, test.wantErr)
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+			if test.wantWarn != "" {
+				if got := diags.ErrWithWarnings().Error(); !strings.Contains(got, test.wantWarn) {
+					t.Errorf("wrong warnings %q; want them to contain %q", got, test.wantWarn)
+				}
+			}
+			if !test.adopt && p.ImportResourceStateCalled {
+				t.Error("imported the existing object without adopt_existing")
+			}
+
+			// The plan shows the adoption of the existing object as an import
+			// and an update.
+			change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_resource.a"))
+			if got := change.Action; got != test.wantPlan {
+				t.Fatalf("wrong planned action %s; want %s", got, test.wantPlan)
+			}
+			if adopted := test.wantPlan == plans.Update; adopted != (change.Importing != nil) {
+				t.Fatalf("wrong importing %#v", change.Importing)
+			}
+
+			state, diags := ctx.Apply(context.Background(), plan, m)
+			if !test.adopt && test.exists {
+				if !diags.HasErrors() {
+					t.Fatal("expected the create to fail")
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+
+			rs := state.ResourceInstance(mustResourceInstanceAddr("test_resource.a"))
+			if rs == nil || rs.Current == nil {
+				t.Fatal("object is not in the state")
+			}
+			want := `{"id":"new","value":"new"}`
+			if test.wantPlan == plans.Update || test.id != "" {
+				want = `{"id":"existing","value":"new"}`
+			}
+			if got := string(rs.Current.AttrsJSON); got != want {
+				t.Errorf("wrong object\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Apply_internalOutputs(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+}
+
+output "public" {
+  value = module.child.plumbing
+}
+
+output "plumbing" {
+  value    = "${module.child.plumbing}-root"
+  internal = true
+}
+`,
+		"child/main.tf": `
+output "plumbing" {
+  value    = "wired"
+  internal = true
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	outputs := state.RootModule().OutputValues
+	if got, want := outputs["public"].Value, cty.StringVal("wired"); !got.RawEquals(want) {
+		t.Errorf("wrong public output %#v; want %#v", got, want)
+	}
+	if outputs["public"].Internal {
+		t.Error("public output is marked as internal")
+	}
+	if got, want := outputs["plumbing"].Value, cty.StringVal("wired-root"); !got.RawEquals(want) {
+		t.Errorf("wrong internal output %#v; want %#v", got, want)
+	}
+	if !outputs["plumbing"].Internal {
+		t.Error("internal output is not marked as internal")
+	}
+}
+
+func TestContext2Apply_plannedComputedValuesDuringApply(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_resource" "a" {
+  value = "a"
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_resource": {
+				Attributes: map[string]*configschema.Attribute{
+					"value":     {Type: cty.String, Optional: true},
+					"generated": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	})
+
+	// The provider generates a value while planning, and records the values
+	// that OpenTofu proposes when planning again during the apply.
+	var proposed []cty.Value
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		proposed = append(proposed, req.ProposedNewState)
+		planned := req.ProposedNewState.AsValueMap()
+		if planned["generated"].IsNull() {
+			planned["generated"] = cty.StringVal("generated during plan")
+		}
+		return providers.PlanResourceChangeResponse{
+			PlannedState: cty.ObjectVal(planned),
+		}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	if len(proposed) != 1 || !proposed[0].GetAttr("generated").IsNull() {
+		t.Fatalf("unexpected proposed value during the plan: %#v", proposed)
+	}
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if len(proposed) != 2 || !proposed[1].GetAttr("generated").RawEquals(cty.StringVal("generated during plan")) {
+		t.Fatalf("the computed value from the plan was not proposed during the apply: %#v", proposed)
+	}
+}
+func TestContext2Apply_providerDependsOn(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				test_string = "a"
+			}
+
+			provider "test" {
+				alias       = "late"
+				test_string = "late"
+				depends_on  = [test_object.a]
+			}
+
+			resource "test_object" "b" {
+				provider    = test.late
+				test_string = "b"
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	var mu sync.Mutex
+	var events []string
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		if v := req.Config.GetAttr("test_string"); !v.IsNull() {
+			events = append(events, "configure "+v.AsString())
+		}
+		return providers.ConfigureProviderResponse{}
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, "apply "+req.PlannedState.GetAttr("test_string").AsString())
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	events = nil
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	want := []string{"apply a", "configure late", "apply b"}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("wrong event order\n%s", diff)
+	}
+}
+
+func TestContext2Apply_providerForEachPassedToModule(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias       = "r"
+				for_each    = toset(["a", "b"])
+				test_string = each.key
+			}
+
+			module "child" {
+				source = "./child"
+				providers = {
+					test.multi = test.r[*]
+				}
+			}
+		`,
+		"child/main.tf": `
+			terraform {
+				required_providers {
+					test = {
+						source                = "hashicorp/test"
+						configuration_aliases = [test.multi]
+					}
+				}
+			}
+
+			resource "test_object" "x" {
+				for_each = toset(["a", "b"])
+				provider = test.multi[each.key]
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	for _, key := range []string{"a", "b"} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("module.child.test_object.x[%q]", key))
+		is := state.ResourceInstance(addr)
+		if is == nil {
+			t.Fatalf("no state for %s", addr)
+		}
+		if got, want := is.ProviderKey, addrs.StringKey(key); got != want {
+			t.Errorf("wrong provider key for %s\ngot:  %#v\nwant: %#v", addr, got, want)
+		}
+	}
+
+	rs := state.Resource(mustAbsResourceAddr("module.child.test_object.x"))
+	if got, want := rs.ProviderConfig.String(), `provider["registry.opentofu.org/hashicorp/test"].r`; got != want {
+		t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Apply_providerInstanceFromEachValue(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			locals {
+				buckets = {
+					logs    = { region = "us-east-1" }
+					backups = { region = "eu-west-1" }
+					images  = { region = "us-east-1" }
+				}
+			}
+
+			provider "test" {
+				alias       = "by_region"
+				for_each    = toset(["us-east-1", "eu-west-1"])
+				test_string = each.key
+			}
+
+			resource "test_object" "bucket" {
+				for_each    = local.buckets
+				provider    = test.by_region[each.value.region]
+				test_string = each.key
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	for bucket, region := range map[string]string{
+		"logs":    "us-east-1",
+		"backups": "eu-west-1",
+		"images":  "us-east-1",
+	} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_object.bucket[%q]", bucket))
+		is := state.ResourceInstance(addr)
+		if is == nil {
+			t.Fatalf("no state for %s", addr)
+		}
+		if got, want := is.ProviderKey, addrs.StringKey(region); got != want {
+			t.Errorf("wrong provider key for %s\ngot:  %#v\nwant: %#v", addr, got, want)
+		}
+	}
+}
+
+func TestContext2Apply_ephemeralResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			ephemeral "test_token" "a" {
+				name = "a"
+			}
+
+			provider "other" {
+				token = ephemeral.test_token.a.token
+			}
+
+			resource "other_object" "x" {
+				test_string = "hello"
+			}
+		`,
+	})
+
+	var eventsMu sync.Mutex
+	var events []string
+	record := func(event string) {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		events = append(events, event)
+	}
+
+	renewed := make(chan struct{}, 1)
+	testProvider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Provider: providers.Schema{Block: &configschema.Block{}},
+			EphemeralResources: map[string]providers.Schema{
+				"test_token": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"name":  {Type: cty.String, Required: true},
+							"token": {Type: cty.String, Computed: true, Sensitive: true},
+						},
+					},
+				},
+			},
+		},
+		OpenEphemeralResourceFn: func(req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+			record("open")
+			name := req.Config.GetAttr("name").AsString()
+			resp.Result = cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal(name),
+				"token": cty.StringVal("secret-" + name),
+			})
+			resp.Private = []byte("opened")
+			resp.RenewAt = time.Now()
+			return resp
+		},
+		RenewEphemeralResourceFn: func(req providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+			record("renew " + string(req.Private))
+			resp.Private = []byte("renewed")
+			renewed <- struct{}{}
+			return resp
+		},
+		CloseEphemeralResourceFn: func(req providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+			record("close " + string(req.Private))
+			return resp
+		},
+	}
+
+	otherProvider := &MockProvider{
+		GetProviderSchemaResponse: &providers.GetProviderSchemaResponse{
+			Provider: providers.Schema{
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"token": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+			ResourceTypes: map[string]providers.Schema{
+				"other_object": {Block: simpleTestSchema()},
+			},
+		},
+		ConfigureProviderFn: func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+			// The ephemeral resource is renewed right after it's opened,
+			// and stays open while the provider that uses it works.
+			select {
+			case <-renewed:
+			case <-time.After(5 * time.Second):
+				resp.Diagnostics = resp.Diagnostics.Append(errors.New("ephemeral resource wasn't renewed"))
+			}
+			record("configure " + req.Config.GetAttr("token").AsString())
+			return resp
+		},
+		PlanResourceChangeFn: func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+			record("plan")
+			return providers.PlanResourceChangeResponse{PlannedState: req.ProposedNewState}
+		},
+		ApplyResourceChangeFn: func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+			record("apply")
+			return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"):  testProviderFuncFixed(testProvider),
+			addrs.NewDefaultProvider("other"): testProviderFuncFixed(otherProvider),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	// The ephemeral resource is opened again during apply, and closed after
+	// everything that depends on it in each walk. The apply walk also plans
+	// the managed resource again with its final configuration.
+	want := []string{
+		"open", "renew opened", "configure secret-a", "plan", "close renewed",
+		"open", "renew opened", "configure secret-a", "plan", "apply", "close renewed",
+	}
+	if diff := cmp.Diff(want, events); diff != "" {
+		t.Errorf("wrong provider calls\n%s", diff)
+	}
+
+	if got := len(state.RootModule().Resources); got != 1 {
+		t.Errorf("wrong number of resources in state: %d\n%s", got, state)
+	}
+	for _, rs := range state.RootModule().Resources {
+		if rs.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			t.Errorf("ephemeral resource %s was saved in the state", rs.Addr)
+		}
+	}
+}
+
+func TestContext2Apply_ephemeralResourceCount(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			ephemeral "test_object" "a" {
+				count       = 2
+				test_string = "token-${count.index}"
+			}
+
+			locals {
+				tokens = ephemeral.test_object.a[*].test_string
+			}
+
+			provider "test" {
+				alias       = "configured"
+				test_string = join(",", local.tokens)
+			}
+
+			resource "test_object" "x" {
+				provider    = test.configured
+				test_string = "hello"
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.EphemeralResources = map[string]providers.Schema{
+		"test_object": {Block: simpleTestSchema()},
+	}
+
+	var configured []string
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+		if v := req.Config.GetAttr("test_string"); !v.IsNull() {
+			configured = append(configured, v.AsString())
+		}
+		return resp
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	want := []string{"token-0,token-1", "token-0,token-1"}
+	if diff := cmp.Diff(want, configured); diff != "" {
+		t.Errorf("wrong provider configuration\n%s", diff)
+	}
+	if !p.CloseEphemeralResourceCalled {
+		t.Errorf("ephemeral resource wasn't closed")
+	}
+}
+
+func TestContext2Apply_resourceInstanceNoteKept(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				test_string = "after"
+			}
+		`,
+	})
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"test_string":"before"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+	state.ResourceInstance(addr).Note = "ticket-1234"
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	if got := plan.PriorState.ResourceInstance(addr).Note; got != "ticket-1234" {
+		t.Errorf("wrong note in prior state: %q", got)
+	}
+
+	state, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if got := state.ResourceInstance(addr).Note; got != "ticket-1234" {
+		t.Errorf("wrong note after apply: %q", got)
+	}
+}
+
+func TestContext2Apply_documentAttributes(t *testing.T) {
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":     {Type: cty.String, Computed: true},
+					"name":   {Type: cty.String, Optional: true},
+					"policy": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		return providers.PlanResourceChangeResponse{PlannedState: req.ProposedNewState}
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		// Like many providers, this one saves the document exactly as
+		// it was given in the configuration.
+		newVal, err := cty.Transform(req.PlannedState, func(path cty.Path, v cty.Value) (cty.Value, error) {
+			if path.Equals(cty.GetAttrPath("policy")) {
+				return req.Config.GetAttr("policy"), nil
+			}
+			return v, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return providers.ApplyResourceChangeResponse{NewState: newVal}
+	}
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"a","name":"old","policy":"{\"a\":1,\"b\":2}"}`),
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  name   = "new"
+  policy = "{\n  \"b\": 2,\n  \"a\": 1\n}"
+
+  lifecycle {
+    json_attributes = [policy]
+  }
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	obj := state.ResourceInstance(addr).Current
+	if got, want := string(obj.AttrsJSON), `{"id":"a","name":"new","policy":"{\"a\":1,\"b\":2}"}`; got != want {
+		t.Errorf("wrong new state\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Apply_applyResourceChanges(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				count       = 4
+				test_string = "a${count.index}"
+			}
+
+			resource "test_object" "b" {
+				test_string = join(",", test_object.a[*].test_string)
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.ServerCapabilities.ApplyResourceChanges = true
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	// The instances of test_object.a don't depend on each other, so they are
+	// applied concurrently and may be grouped, depending on how many of them
+	// are requested while another is in flight, while test_object.b must
+	// wait for all of them.
+	for _, req := range p.ApplyResourceChangesRequests {
+		for _, change := range req.Changes {
+			if strings.Contains(change.PlannedState.GetAttr("test_string").AsString(), ",") {
+				t.Errorf("test_object.b was applied along with the instances it depends on")
+			}
+		}
+	}
+	for i := 0; i < 4; i++ {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_object.a[%d]", i))
+		if state.ResourceInstance(addr) == nil {
+			t.Errorf("%s was not applied", addr)
+		}
+	}
+
+	obj := state.ResourceInstance(mustResourceInstanceAddr("test_object.b")).Current
+	if got, want := string(obj.AttrsJSON), `"test_string":"a0,a1,a2,a3"`; !strings.Contains(got, want) {
+		t.Errorf("wrong state for test_object.b\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Apply_quarantineAfterRepeatedFailures(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				test_string = "a"
+			}
+
+			resource "test_object" "b" {
+				test_string = test_object.a.test_string
+			}
+
+			resource "test_object" "c" {
+				test_string = "c"
+			}
+		`,
+	})
+
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_object.b")
+	p := simpleMockProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		if req.PlannedState.GetAttr("test_string").AsString() == "a" {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("quota exceeded"))
+			return resp
+		}
+		resp.NewState = req.PlannedState
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		QuarantineAfter: 2,
+	})
+
+	hasWarning := func(diags tfdiags.Diagnostics, summary string) bool {
+		for _, diag := range diags {
+			if diag.Severity() == tfdiags.Warning && diag.Description().Summary == summary {
+				return true
+			}
+		}
+		return false
+	}
+
+	state := states.NewState()
+	for i := 1; i <= 2; i++ {
+		plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+		assertNoErrors(t, diags)
+
+		state, diags = ctx.Apply(context.Background(), plan, m)
+		if !diags.HasErrors() {
+			t.Fatalf("apply %d succeeded; want error", i)
+		}
+		f := state.ApplyFailure(addrA)
+		if f == nil {
+			t.Fatalf("no apply failure recorded for %s after apply %d", addrA, i)
+		}
+		if f.Count != i || f.Quarantined != (i == 2) || f.LastError != "quota exceeded" {
+			t.Fatalf("wrong apply failure after apply %d: %#v", i, f)
+		}
+		if got, want := hasWarning(diags, "Resource instances quarantined"), i == 2; got != want {
+			t.Fatalf("quarantine warning after apply %d: got %t, want %t", i, got, want)
+		}
+		if state.ApplyFailure(mustResourceInstanceAddr("test_object.c")) != nil {
+			t.Fatalf("apply failure recorded for test_object.c, which succeeded")
+		}
+	}
+
+	// The quarantined instance and its dependent are skipped, even when
+	// they are targeted.
+	for _, opts := range []*PlanOpts{
+		DefaultPlanOpts,
+		{Mode: plans.NormalMode, Targets: []addrs.Targetable{addrB}},
+	} {
+		plan, diags := ctx.Plan(context.Background(), m, state, opts)
+		assertNoErrors(t, diags)
+		if !hasWarning(diags, "Quarantined resource instances skipped") {
+			t.Errorf("missing warning about the quarantined resource instances: %s", diags.ErrWithWarnings())
+		}
+		if len(plan.ExcludeAddrs) != 0 {
+			t.Errorf("quarantined resource instances recorded as excluded: %s", plan.ExcludeAddrs)
+		}
+		for _, addr := range []addrs.AbsResourceInstance{addrA, addrB} {
+			if rc := plan.Changes.ResourceInstance(addr); rc != nil {
+				t.Errorf("unexpected %s change for %s", rc.Action, addr)
+			}
+		}
+	}
+
+	// Once released and fixed, the instance is planned again, and the
+	// failures are forgotten after it applies successfully.
+	state.RemoveApplyFailure(addrA)
+	p.ApplyResourceChangeFn = nil
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	if rc := plan.Changes.ResourceInstance(addrA); rc == nil || rc.Action != plans.Create {
+		t.Fatalf("expected %s to be created, got %#v", addrA, rc)
+	}
+	state, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if f := state.ApplyFailure(addrA); f != nil {
+		t.Fatalf("apply failure kept after successful apply: %#v", f)
+	}
+	if state.ApplyFailures != nil {
+		t.Fatalf("unexpected apply failures: %#v", state.ApplyFailures)
+	}
+}
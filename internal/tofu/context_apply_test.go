@@ -4319,6 +4319,9 @@ func TestContext2Apply_Provisioner_compute(t *testing.T) {
 		if val != "computed_value" {
 			t.Fatalf("bad value for foo: %q", val)
 		}
+		if req.TemplateRenderer == nil {
+			t.Fatal("provisioner was not given a template renderer")
+		}
 		req.UIOutput.Output(fmt.Sprintf("Executing: %q", val))
 
 		return
@@ -6701,6 +6704,20 @@ func TestContext2Apply_hook(t *testing.T) {
 	if !h.PostStateUpdateCalled {
 		t.Fatalf("should call post state update")
 	}
+	// apply-good has two resources, each of which is created by a single
+	// provider call. The resources are applied concurrently, so the calls
+	// are sorted.
+	wantCalls := []string{
+		"post ApplyResourceChange",
+		"post ApplyResourceChange",
+		"pre ApplyResourceChange",
+		"pre ApplyResourceChange",
+	}
+	gotCalls := append([]string(nil), h.ProviderCalls...)
+	sort.Strings(gotCalls)
+	if diff := cmp.Diff(wantCalls, gotCalls); diff != "" {
+		t.Fatalf("wrong provider calls\n%s", diff)
+	}
 }
 
 func TestContext2Apply_hookOrphan(t *testing.T) {
@@ -164,7 +164,7 @@ func (ri *ImportResolver) ExpandAndResolveImport(importTarget *ImportTarget, ctx
 func (ri *ImportResolver) resolveImport(importTarget *ImportTarget, ctx EvalContext, keyData instances.RepetitionData) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	importId, evalDiags := evaluateImportIdExpression(importTarget.Config.ID, ctx, keyData)
+	importId, evalDiags := evaluateImportIdExpression(importTarget.Config.ID, importTarget.Config.IDSeparatorOrDefault(), ctx, keyData)
 	diags = diags.Append(evalDiags)
 	if diags.HasErrors() {
 		return diags
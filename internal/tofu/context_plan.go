@@ -89,6 +89,16 @@ type PlanOpts struct {
 	// fully-functional new object.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// FrozenModules causes planning to fail if the source code of a module
+	// instance has changed since the configuration was last applied to it,
+	// as recorded in the previous run state.
+	//
+	// UnfrozenModules lists the module instances, using the same matching
+	// rules as Targets, that are allowed to change even when FrozenModules
+	// is set.
+	FrozenModules   bool
+	UnfrozenModules []addrs.ModuleInstance
+
 	// ExternalReferences allows the external caller to pass in references to
 	// nodes that should not be pruned even if they are not referenced within
 	// the actual graph.
@@ -187,6 +197,15 @@ func (c *Context) Plan(ctx context.Context, config *configs.Config, prevRunState
 		return nil, diags
 	}
 
+	if opts.FrozenModules && opts.Mode != plans.DestroyMode {
+		// Destroying a module instance doesn't apply its changed source
+		// code, so there's nothing to protect against in destroy mode.
+		diags = diags.Append(checkFrozenModules(config, prevRunState, opts.UnfrozenModules))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
 	// By the time we get here, we should have values defined for all of
 	// the root module variables, even if some of them are "unknown". It's the
 	// caller's responsibility to have already handled the decoding of these
@@ -207,11 +226,26 @@ The -target and -exclude options are not for routine use, and are provided only
 		))
 	}
 
+	// A normal plan skips any quarantined resource instances by excluding
+	// them. We only add them to the options used for planning, since the
+	// plan's ExcludeAddrs records what the operator asked for.
+	walkOpts := opts
+	if quarantined := prevRunState.QuarantinedResourceInstances(); len(quarantined) > 0 && opts.Mode == plans.NormalMode {
+		diags = diags.Append(quarantinedResourceInstancesWarning(prevRunState, quarantined))
+		optsCopy := *opts
+		optsCopy.Excludes = make([]addrs.Targetable, 0, len(opts.Excludes)+len(quarantined))
+		optsCopy.Excludes = append(optsCopy.Excludes, opts.Excludes...)
+		for _, addr := range quarantined {
+			optsCopy.Excludes = append(optsCopy.Excludes, addr)
+		}
+		walkOpts = &optsCopy
+	}
+
 	var plan *plans.Plan
 	var planDiags tfdiags.Diagnostics
 	switch opts.Mode {
 	case plans.NormalMode:
-		plan, planDiags = c.plan(ctx, config, prevRunState, opts)
+		plan, planDiags = c.plan(ctx, config, prevRunState, walkOpts)
 	case plans.DestroyMode:
 		plan, planDiags = c.destroyPlan(ctx, config, prevRunState, opts)
 	case plans.RefreshOnlyMode:
@@ -219,7 +253,13 @@ The -target and -exclude options are not for routine use, and are provided only
 	default:
 		panic(fmt.Sprintf("unsupported plan mode %s", opts.Mode))
 	}
-	diags = diags.Append(planDiags)
+	// Objects belonging to removed provider instances are reported by each
+	// graph node individually, but the user needs to see them all together
+	// to decide how to deal with them.
+	diags = diags.Append(consolidateProviderInstanceRemovedDiags(planDiags))
+	if plan != nil {
+		diags = diags.Append(checkProviderDenyDestroy(config, plan.Changes))
+	}
 	// NOTE: We're intentionally not returning early when diags.HasErrors
 	// here because we'll still populate other metadata below on a best-effort
 	// basis to try to give the UI some extra context to return alongside the
@@ -227,6 +267,7 @@ The -target and -exclude options are not for routine use, and are provided only
 
 	// convert the variables into the format expected for the plan
 	varVals := make(map[string]plans.DynamicValue, len(opts.SetVariables))
+	ephemeralVarVals := make(map[string]plans.DynamicValue)
 	for k, iv := range opts.SetVariables {
 		if iv.Value == cty.NilVal {
 			continue // We only record values that the caller actually set
@@ -244,6 +285,10 @@ The -target and -exclude options are not for routine use, and are provided only
 			))
 			continue
 		}
+		if vc, ok := config.Module.Variables[k]; ok && vc.Ephemeral {
+			ephemeralVarVals[k] = dv
+			continue
+		}
 		varVals[k] = dv
 	}
 
@@ -251,6 +296,7 @@ The -target and -exclude options are not for routine use, and are provided only
 	// targets and provider SHAs.
 	if plan != nil {
 		plan.VariableValues = varVals
+		plan.EphemeralVariableValues = ephemeralVarVals
 		plan.TargetAddrs = opts.Targets
 		plan.ExcludeAddrs = opts.Excludes
 	} else if !diags.HasErrors() {
@@ -472,7 +518,7 @@ func (c *Context) destroyPlan(ctx context.Context, config *configs.Config, prevR
 	return destroyPlan, diags
 }
 
-func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State) ([]refactoring.MoveStatement, refactoring.MoveResults) {
+func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State) ([]refactoring.MoveStatement, refactoring.MoveResults, tfdiags.Diagnostics) {
 	explicitMoveStmts := refactoring.FindMoveStatements(config)
 	implicitMoveStmts := refactoring.ImpliedMoveStatements(config, prevRunState, explicitMoveStmts)
 	var moveStmts []refactoring.MoveStatement
@@ -483,17 +529,25 @@ func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState
 	}
 	moveResults := refactoring.ApplyMoves(moveStmts, prevRunState)
 
-	return moveStmts, moveResults
+	// Moves of provider configurations only rebind resources to another
+	// provider configuration, so they run after the resources have reached
+	// their new addresses.
+	diags := refactoring.ApplyProviderMoves(refactoring.FindProviderMoveStatements(config), prevRunState)
+
+	return moveStmts, moveResults, diags
 }
 
 func (c *Context) prePlanVerifyTargetedMoves(moveResults refactoring.MoveResults, targets []addrs.Targetable, excludes []addrs.Targetable) tfdiags.Diagnostics {
+	// The -target and -exclude options can't be used together, but the
+	// quarantined resource instances are excluded even from targeted plans.
+	var diags tfdiags.Diagnostics
 	if len(targets) > 0 {
-		return c.prePlanVerifyMovesWithTargetFlag(moveResults, targets)
+		diags = diags.Append(c.prePlanVerifyMovesWithTargetFlag(moveResults, targets))
 	}
 	if len(excludes) > 0 {
-		return c.prePlanVerifyMovesWithExcludeFlag(moveResults, excludes)
+		diags = diags.Append(c.prePlanVerifyMovesWithExcludeFlag(moveResults, excludes))
 	}
-	return nil
+	return diags
 }
 
 func (c *Context) prePlanVerifyMovesWithTargetFlag(moveResults refactoring.MoveResults, targets []addrs.Targetable) tfdiags.Diagnostics {
@@ -625,7 +679,9 @@ func (c *Context) prePlanVerifyMovesWithExcludeFlag(moveResults refactoring.Move
 }
 
 func (c *Context) postPlanValidateMoves(config *configs.Config, stmts []refactoring.MoveStatement, allInsts instances.Set) tfdiags.Diagnostics {
-	return refactoring.ValidateMoves(stmts, config, allInsts)
+	diags := refactoring.ValidateMoves(stmts, config, allInsts)
+	diags = diags.Append(refactoring.ValidateProviderMoves(refactoring.FindProviderMoveStatements(config), config))
+	return diags
 }
 
 // All import target addresses with a key must already exist in config.
@@ -745,7 +801,11 @@ func (c *Context) planWalk(ctx context.Context, config *configs.Config, prevRunS
 	log.Printf("[DEBUG] Building and walking plan graph for %s", opts.Mode)
 
 	prevRunState = prevRunState.DeepCopy() // don't modify the caller's object when we process the moves
-	moveStmts, moveResults := c.prePlanFindAndApplyMoves(config, prevRunState)
+	moveStmts, moveResults, moveDiags := c.prePlanFindAndApplyMoves(config, prevRunState)
+	diags = diags.Append(moveDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
 
 	// If resource targeting is in effect then it might conflict with the
 	// move result.
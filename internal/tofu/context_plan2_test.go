@@ -10,10 +10,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
@@ -26,6 +29,7 @@ import (
 	// "github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
@@ -1391,6 +1395,160 @@ func TestContext2Plan_movedResourceBasic(t *testing.T) {
 	})
 }
 
+func TestContext2Plan_movedProviderConfig(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias    = "regions"
+				for_each = {
+					"a" = "a"
+				}
+			}
+
+			moved {
+				from = provider.test.old
+				to   = provider.test.regions["a"]
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		// test_object.a is no longer in the configuration, and its provider
+		// configuration was replaced by an instance of another one.
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].old`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+	}
+
+	instPlan := plan.Changes.ResourceInstance(addr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addr)
+	}
+	if got, want := instPlan.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.ProviderAddr.String(), `provider["registry.opentofu.org/hashicorp/test"].regions`; got != want {
+		t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+	}
+	rs := plan.PrevRunState.Resource(addr.ContainingResource())
+	if got, want := rs.Instances[addrs.NoKey].ProviderKey, addrs.StringKey("a"); got != want {
+		t.Errorf("wrong provider key\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_movedProviderConfigChildModule(t *testing.T) {
+	addr := mustResourceInstanceAddr("module.child.test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias = "new"
+			}
+
+			module "child" {
+				source = "./child"
+				providers = {
+					test = test.new
+				}
+			}
+
+			moved {
+				from = provider.test.old
+				to   = provider.test.new
+			}
+		`,
+		"child/main.tf": `
+			terraform {
+				required_providers {
+					test = {
+						source = "hashicorp/test"
+					}
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		// module.child.test_object.a is no longer in the configuration, and
+		// it was bound to the provider configuration that the root module
+		// passed to the child module before it was renamed.
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].old`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+	}
+
+	instPlan := plan.Changes.ResourceInstance(addr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addr)
+	}
+	if got, want := instPlan.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.ProviderAddr.String(), `provider["registry.opentofu.org/hashicorp/test"].new`; got != want {
+		t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_movedProviderConfigStillExists(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias = "old"
+			}
+
+			provider "test" {
+				alias = "new"
+			}
+
+			moved {
+				from = provider.test.old
+				to   = provider.test.new
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error")
+	}
+	if got, want := diags.Err().Error(), "Moved provider configuration still exists"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func constructProviderSchemaForTesting(attrs map[string]*configschema.Attribute) providers.Schema {
 	return providers.Schema{
 		Block: &configschema.Block{Attributes: attrs},
@@ -4910,6 +5068,57 @@ func TestContext2Plan_dataSourceReadPlanError(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_dataSourceReadCancelled(t *testing.T) {
+	defer func(d time.Duration) { providerStopGracePeriod = d }(providerStopGracePeriod)
+	providerStopGracePeriod = 10 * time.Millisecond
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+data "test_data_source" "foo" {
+}
+`})
+
+	p := testProvider("test")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	// This provider ignores the request to stop, but it does notice when
+	// its call is cancelled, like a plugin client does.
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
+		go ctx.Stop()
+		<-req.Context.Done()
+		resp.Diagnostics = resp.Diagnostics.Append(req.Context.Err())
+		return resp
+	}
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	var found bool
+	for _, diag := range diags {
+		desc := diag.Description()
+		if desc.Summary == "context canceled" {
+			t.Errorf("the provider's own error was reported: %s", desc.Summary)
+		}
+		if desc.Summary == "Operation cancelled by user" {
+			found = true
+			if !strings.Contains(desc.Detail, "read data.test_data_source.foo") {
+				t.Errorf("detail doesn't describe the call: %s", desc.Detail)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("missing cancellation error\n%s", diags.Err())
+	}
+	if !p.StopCalled {
+		t.Error("provider wasn't asked to stop")
+	}
+}
+
 func TestContext2Plan_ignoredMarkedValue(t *testing.T) {
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
@@ -5644,6 +5853,31 @@ import {
   id = each.value.id
   to = module.mod[each.value.moduleKey].test_object.a[each.value.resourceKey]
 }
+`,
+			},
+		},
+		{
+			Description:   "map of objects with implied key and composite id",
+			ImportResults: []ImportResult{{ResolvedAddress: `test_object.a["web"]`, ResolvedId: "prod:web:80"}, {ResolvedAddress: `test_object.a["db"]`, ResolvedId: "prod:db:5432"}},
+			inlineConfiguration: map[string]string{
+				"main.tf": `
+locals {
+  services = {
+    web = { env = "prod", port = 80 }
+    db  = { env = "prod", port = 5432 }
+  }
+}
+
+resource "test_object" "a" {
+  for_each = local.services
+}
+
+import {
+  for_each     = local.services
+  to           = test_object.a
+  id           = [each.value.env, each.key, each.value.port]
+  id_separator = ":"
+}
 `,
 			},
 		},
@@ -7521,66 +7755,344 @@ func TestContext2Plan_providerForEachWithOrphanResourceInstanceNotUsingForEach(t
 	}
 }
 
-func TestContext2Plan_plannedState(t *testing.T) {
-	addr := mustResourceInstanceAddr("test_object.a")
+func TestContext2Plan_providerForEachInstanceRemovedWithOrphans(t *testing.T) {
+	providerConfigAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewBuiltInProvider("test"),
+		Alias:    "multi",
+	}
+	instAddr := func(name string, key string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_thing",
+			Name: name,
+		}.Instance(addrs.StringKey(key)).Absolute(addrs.RootModuleInstance)
+	}
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
-resource "test_object" "a" {
-	test_string = "foo"
-}
+			terraform {
+				required_providers {
+					test = {
+						source = "terraform.io/builtin/test"
+					}
+				}
+			}
 
-locals {
-  local_value = test_object.a.test_string
-}
-`,
+			locals {
+				regions = toset(["a"])
+			}
+
+			provider "test" {
+				alias    = "multi"
+				for_each = local.regions
+			}
+
+			resource "test_thing" "a" {
+				for_each = local.regions
+				provider = test.multi[each.key]
+			}
+
+			resource "test_thing" "b" {
+				for_each = local.regions
+				provider = test.multi[each.key]
+			}
+		`,
+	})
+	s := states.BuildState(func(ss *states.SyncState) {
+		for _, name := range []string{"a", "b"} {
+			for _, key := range []string{"a", "b"} {
+				ss.SetResourceInstanceCurrent(
+					instAddr(name, key),
+					&states.ResourceInstanceObjectSrc{
+						Status:    states.ObjectReady,
+						AttrsJSON: []byte(`{}`),
+					},
+					providerConfigAddr,
+					addrs.StringKey(key),
+				)
+			}
+		}
 	})
+	p := &MockProvider{}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_thing": {
+				Block: &configschema.Block{},
+			},
+		},
+	}
 
-	p := simpleMockProvider()
-	ctx := testContext2(t, &ContextOpts{
+	tofuCtx := testContext2(t, &ContextOpts{
 		Providers: map[addrs.Provider]providers.Factory{
-			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			providerConfigAddr.Provider: testProviderFuncFixed(p),
 		},
 	})
-
-	state := states.NewState()
-	plan, diags := ctx.Plan(context.Background(), m, state, nil)
-	if diags.HasErrors() {
-		t.Errorf("expected no errors, but got %s", diags)
+	_, diags := tofuCtx.Plan(context.Background(), m, s, DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success; want an error about the removed provider instance")
 	}
 
-	module := state.RootModule()
-
-	// So, the original state shouldn't have been updated at all.
-	if len(module.LocalValues) > 0 {
-		t.Errorf("expected no local values in the state but found %d", len(module.LocalValues))
+	// All of the orphaned objects must be reported together in a single error.
+	var found []tfdiags.Diagnostic
+	for _, diag := range diags {
+		if diag.Description().Summary == "Provider instance not present" {
+			found = append(found, diag)
+		}
 	}
-
-	if len(module.Resources) > 0 {
-		t.Errorf("expected no resources in the state but found %d", len(module.LocalValues))
+	if len(found) != 1 {
+		t.Fatalf("expected exactly one provider instance error, got %d:\n%s", len(found), diags.Err())
 	}
-
-	// But, this makes it hard for the testing framework to valid things about
-	// the returned plan. So, the plan contains the planned state:
-	module = plan.PlannedState.RootModule()
-
-	if module.LocalValues["local_value"].AsString() != "foo" {
-		t.Errorf("expected local value to be \"foo\" but was \"%s\"", module.LocalValues["local_value"].AsString())
+	detail := found[0].Description().Detail
+	for _, want := range []string{
+		`The element "b" has been removed from the for_each collection of provider["terraform.io/builtin/test"].multi`,
+		`  - test_thing.a["b"]`,
+		`  - test_thing.b["b"]`,
+		`"tofu state rm"`,
+		`"removed" block for test_thing.a, test_thing.b`,
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("missing expected substring %q in error detail:\n%s", want, detail)
+		}
 	}
-
-	if module.ResourceInstance(addr.Resource).Current.Status != states.ObjectPlanned {
-		t.Errorf("expected resource to be in planned state")
+	if strings.Contains(detail, `test_thing.a["a"]`) {
+		t.Errorf("error detail lists an object whose provider instance still exists:\n%s", detail)
 	}
 }
 
-func TestContext2Plan_removedResourceBasic(t *testing.T) {
-	desposedKey := states.DeposedKey("deposed")
-	addr := mustResourceInstanceAddr("test_object.a")
+func TestContext2Plan_providerCountInstanceRemovedWithOrphans(t *testing.T) {
+	providerConfigAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewBuiltInProvider("test"),
+		Alias:    "multi",
+	}
 	m := testModuleInline(t, map[string]string{
 		"main.tf": `
-			removed {
-				from = test_object.a
-			}
-		`,
+			terraform {
+				required_providers {
+					test = {
+						source = "terraform.io/builtin/test"
+					}
+				}
+			}
+
+			provider "test" {
+				alias = "multi"
+				count = 1
+			}
+
+			resource "test_thing" "a" {
+				count    = 1
+				provider = test.multi[count.index]
+			}
+		`,
+	})
+	s := states.BuildState(func(ss *states.SyncState) {
+		for i := 0; i < 2; i++ {
+			ss.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(fmt.Sprintf("test_thing.a[%d]", i)),
+				&states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{}`),
+				},
+				providerConfigAddr,
+				addrs.IntKey(i),
+			)
+		}
+	})
+	p := &MockProvider{}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_thing": {
+				Block: &configschema.Block{},
+			},
+		},
+	}
+
+	tofuCtx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			providerConfigAddr.Provider: testProviderFuncFixed(p),
+		},
+	})
+	_, diags := tofuCtx.Plan(context.Background(), m, s, DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success; want an error about the removed provider instance")
+	}
+
+	var found []tfdiags.Diagnostic
+	for _, diag := range diags {
+		if diag.Description().Summary == "Provider instance not present" {
+			found = append(found, diag)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected exactly one provider instance error, got %d:\n%s", len(found), diags.Err())
+	}
+	detail := found[0].Description().Detail
+	for _, want := range []string{
+		`The index 1 is no longer within the count of provider["terraform.io/builtin/test"].multi`,
+		`  - test_thing.a[1]`,
+		`increase the provider's count to include the index 1 again`,
+		`using the same count both for a resource`,
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("missing expected substring %q in error detail:\n%s", want, detail)
+		}
+	}
+	if strings.Contains(detail, "for_each") {
+		t.Errorf("error detail refers to for_each for a provider configuration that uses count:\n%s", detail)
+	}
+}
+
+func TestContext2Plan_providerCount(t *testing.T) {
+	providerConfigAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewBuiltInProvider("test"),
+		Alias:    "multi",
+	}
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			terraform {
+				required_providers {
+					test = {
+						source = "terraform.io/builtin/test"
+					}
+				}
+			}
+
+			locals {
+				regions = ["a", "b"]
+			}
+
+			provider "test" {
+				alias  = "multi"
+				count  = length(local.regions)
+				region = local.regions[count.index]
+			}
+
+			resource "test_thing" "a" {
+				count    = length(local.regions)
+				provider = test.multi[count.index]
+			}
+		`,
+	})
+	p := &MockProvider{}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"region": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_thing": {
+				Block: &configschema.Block{},
+			},
+		},
+	}
+	var mu sync.Mutex
+	var regions []string
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		regions = append(regions, req.Config.GetAttr("region").AsString())
+		return providers.ConfigureProviderResponse{}
+	}
+
+	tofuCtx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			providerConfigAddr.Provider: testProviderFuncFixed(p),
+		},
+	})
+	plan, diags := tofuCtx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	sort.Strings(regions)
+	if got, want := regions, []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("wrong configured regions\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	state, diags := tofuCtx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	for i := 0; i < 2; i++ {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_thing.a[%d]", i))
+		is := state.ResourceInstance(addr)
+		if is == nil {
+			t.Fatalf("no state for %s", addr)
+		}
+		if got, want := is.ProviderKey, addrs.IntKey(i); got != want {
+			t.Errorf("wrong provider key for %s\ngot:  %#v\nwant: %#v", addr, got, want)
+		}
+	}
+}
+
+func TestContext2Plan_plannedState(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+	test_string = "foo"
+}
+
+locals {
+  local_value = test_object.a.test_string
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	state := states.NewState()
+	plan, diags := ctx.Plan(context.Background(), m, state, nil)
+	if diags.HasErrors() {
+		t.Errorf("expected no errors, but got %s", diags)
+	}
+
+	module := state.RootModule()
+
+	// So, the original state shouldn't have been updated at all.
+	if len(module.LocalValues) > 0 {
+		t.Errorf("expected no local values in the state but found %d", len(module.LocalValues))
+	}
+
+	if len(module.Resources) > 0 {
+		t.Errorf("expected no resources in the state but found %d", len(module.LocalValues))
+	}
+
+	// But, this makes it hard for the testing framework to valid things about
+	// the returned plan. So, the plan contains the planned state:
+	module = plan.PlannedState.RootModule()
+
+	if module.LocalValues["local_value"].AsString() != "foo" {
+		t.Errorf("expected local value to be \"foo\" but was \"%s\"", module.LocalValues["local_value"].AsString())
+	}
+
+	if module.ResourceInstance(addr.Resource).Current.Status != states.ObjectPlanned {
+		t.Errorf("expected resource to be in planned state")
+	}
+}
+
+func TestContext2Plan_removedResourceBasic(t *testing.T) {
+	desposedKey := states.DeposedKey("deposed")
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = test_object.a
+			}
+		`,
 	})
 
 	state := states.BuildState(func(s *states.SyncState) {
@@ -8332,6 +8844,95 @@ func TestContext2Plan_removedModuleButModuleBlockStillExists(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_removedProviderRebindsOrphans(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias = "new"
+			}
+
+			removed {
+				from = provider.test.legacy
+				to   = provider.test.new
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		// The prior state tracks test_object.a as belonging to a provider
+		// configuration that no longer exists.
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].legacy`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+	}
+
+	instPlan := plan.Changes.ResourceInstance(addr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addr)
+	}
+	if got, want := instPlan.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.ProviderAddr, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].new`); got.String() != want.String() {
+		t.Errorf("wrong provider\ngot:  %s\nwant: %s", got, want)
+	}
+
+	newState, diags := ctx.Apply(context.Background(), plan, m)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors during apply\n%s", diags.Err().Error())
+	}
+	if !newState.Empty() {
+		t.Errorf("state is not empty after apply\n%s", newState)
+	}
+}
+
+func TestContext2Plan_removedProviderWithoutTarget(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = provider.test.legacy
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"].legacy`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), "Objects still bound to removed provider configuration"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
 func TestContext2Plan_importResourceWithSensitiveDataSource(t *testing.T) {
 	addr := mustResourceInstanceAddr("test_object.b")
 	m := testModuleInline(t, map[string]string{
@@ -8525,3 +9126,1033 @@ func featuresBlockTestSchema() *configschema.Block {
 		},
 	}
 }
+
+func TestContext2Plan_providerDenyDestroy(t *testing.T) {
+	providerAddr := addrs.NewDefaultProvider("test")
+	instAddr := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_object",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	files := func(production bool) map[string]string {
+		return map[string]string{
+			"main.tf": fmt.Sprintf(`
+			locals {
+				production = %t
+			}
+
+			provider "test" {
+				alias        = "prod"
+				deny_destroy = local.production
+			}
+
+			provider "test" {
+			}
+
+			resource "test_object" "kept" {
+				provider = test.prod
+			}
+		`, production),
+		}
+	}
+	s := states.BuildState(func(ss *states.SyncState) {
+		for _, name := range []string{"kept", "gone_a", "gone_b"} {
+			ss.SetResourceInstanceCurrent(
+				instAddr(name),
+				&states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{"test_string":"foo"}`),
+				},
+				addrs.AbsProviderConfig{
+					Module:   addrs.RootModule,
+					Provider: providerAddr,
+					Alias:    "prod",
+				},
+				addrs.NoKey,
+			)
+		}
+		// Objects belonging to other provider configurations can still be
+		// destroyed.
+		ss.SetResourceInstanceCurrent(
+			instAddr("other"),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"test_string":"foo"}`),
+			},
+			addrs.AbsProviderConfig{
+				Module:   addrs.RootModule,
+				Provider: providerAddr,
+			},
+			addrs.NoKey,
+		)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			providerAddr: testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), testModuleInline(t, files(true)), s, DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success; want an error about deny_destroy")
+	}
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Err())
+	}
+	desc := diags[0].Description()
+	if got, want := desc.Summary, "Provider configuration does not allow destroying objects"; got != want {
+		t.Errorf("wrong summary\ngot:  %s\nwant: %s", got, want)
+	}
+	for _, want := range []string{"test_object.gone_a", "test_object.gone_b"} {
+		if !strings.Contains(desc.Detail, want) {
+			t.Errorf("detail does not mention %s:\n%s", want, desc.Detail)
+		}
+	}
+	for _, unwanted := range []string{"test_object.kept", "test_object.other"} {
+		if strings.Contains(desc.Detail, unwanted) {
+			t.Errorf("detail unexpectedly mentions %s:\n%s", unwanted, desc.Detail)
+		}
+	}
+
+	// Turning deny_destroy off allows the destroys.
+	_, diags = ctx.Plan(context.Background(), testModuleInline(t, files(false)), s, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+}
+
+func TestContext2Plan_frozenModules(t *testing.T) {
+	files := func(childValue string) map[string]string {
+		return map[string]string{
+			"main.tf": `
+			module "child" {
+				source = "./child"
+				count  = 2
+			}
+			`,
+			"child/main.tf": fmt.Sprintf(`
+			resource "test_object" "a" {
+				test_string = %q
+			}
+			`, childValue),
+		}
+	}
+	childAddr := addrs.RootModuleInstance.Child("child", addrs.IntKey(0))
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	m := testModuleInline(t, files("foo"))
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	wantHash := m.Descendent(childAddr.Module()).Module.ContentHash
+	if wantHash == "" {
+		t.Fatal("module has no content hash")
+	}
+	if got := state.Module(childAddr).ConfigHash; got != wantHash {
+		t.Fatalf("wrong recorded hash\ngot:  %s\nwant: %s", got, wantHash)
+	}
+
+	// The unchanged configuration can be planned with frozen modules.
+	_, diags = ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode:          plans.NormalMode,
+		FrozenModules: true,
+	})
+	assertNoErrors(t, diags)
+
+	changed := testModuleInline(t, files("bar"))
+	_, diags = ctx.Plan(context.Background(), changed, state, &PlanOpts{
+		Mode:          plans.NormalMode,
+		FrozenModules: true,
+	})
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success; want an error about the changed module")
+	}
+	if len(diags) != 2 {
+		t.Fatalf("wrong number of diagnostics %d; want 2\n%s", len(diags), diags.Err())
+	}
+	for i, diag := range diags {
+		desc := diag.Description()
+		if got, want := desc.Summary, "Frozen module has changed"; got != want {
+			t.Errorf("wrong summary\ngot:  %s\nwant: %s", got, want)
+		}
+		if want := fmt.Sprintf("module.child[%d]", i); !strings.Contains(desc.Detail, want) {
+			t.Errorf("detail does not mention %s:\n%s", want, desc.Detail)
+		}
+	}
+
+	// Unfreezing a single instance leaves the other one frozen.
+	_, diags = ctx.Plan(context.Background(), changed, state, &PlanOpts{
+		Mode:            plans.NormalMode,
+		FrozenModules:   true,
+		UnfrozenModules: []addrs.ModuleInstance{childAddr},
+	})
+	if len(diags) != 1 || !strings.Contains(diags[0].Description().Detail, "module.child[1]") {
+		t.Fatalf("wrong diagnostics\n%s", diags.ErrWithWarnings())
+	}
+
+	// Unfreezing the module call covers all of its instances.
+	plan, diags = ctx.Plan(context.Background(), changed, state, &PlanOpts{
+		Mode:            plans.NormalMode,
+		FrozenModules:   true,
+		UnfrozenModules: []addrs.ModuleInstance{addrs.RootModuleInstance.Child("child", addrs.NoKey)},
+	})
+	assertNoErrors(t, diags)
+
+	// Modules are not frozen without the option.
+	_, diags = ctx.Plan(context.Background(), changed, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	// Applying the change records the new hash.
+	state, diags = ctx.Apply(context.Background(), plan, changed)
+	assertNoErrors(t, diags)
+	if got, want := state.Module(childAddr).ConfigHash, changed.Descendent(childAddr.Module()).Module.ContentHash; got != want {
+		t.Fatalf("wrong recorded hash\ngot:  %s\nwant: %s", got, want)
+	}
+	if state.Module(childAddr).ConfigHash == wantHash {
+		t.Fatal("recorded hash was not updated")
+	}
+}
+
+func TestContext2Plan_providerPrecondition(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "regions" {
+				type = set(string)
+			}
+
+			provider "test" {
+				alias    = "regional"
+				for_each = toset(["us-east-1", "eu-west-1"])
+
+				lifecycle {
+					precondition {
+						condition     = contains(var.regions, each.key)
+						error_message = "Region ${each.key} is not enabled."
+					}
+				}
+			}
+
+			resource "test_object" "a" {
+				for_each = toset(["us-east-1", "eu-west-1"])
+				provider = test.regional[each.key]
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	_, diags = ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"regions": &InputValue{
+				Value:      cty.SetVal([]cty.Value{cty.StringVal("us-east-1")}),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	var details []string
+	for _, diag := range diags {
+		desc := diag.Description()
+		if desc.Summary == "Provider precondition failed" {
+			details = append(details, desc.Detail)
+		}
+	}
+	if want := []string{"Region eu-west-1 is not enabled."}; !slices.Equal(details, want) {
+		t.Errorf("wrong precondition failures\ngot:  %#v\nwant: %#v", details, want)
+	}
+	if !p.ConfigureProviderCalled {
+		t.Error("the instance with a passing precondition was not configured")
+	}
+}
+
+func TestContext2Plan_ephemeralVariables(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "token" {
+				type      = string
+				ephemeral = true
+			}
+
+			locals {
+				token = trimspace(var.token)
+			}
+
+			module "child" {
+				source = "./child"
+				token  = local.token
+			}
+
+			provider "test" {
+				test_string = module.child.token_header
+			}
+
+			resource "test_object" "a" {
+				test_string = "static"
+			}
+		`,
+		"child/main.tf": `
+			variable "token" {
+				type      = string
+				ephemeral = true
+			}
+
+			output "token_header" {
+				value     = "Bearer ${var.token}"
+				ephemeral = true
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"token": &InputValue{
+				Value:      cty.StringVal("secret"),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	if _, ok := plan.VariableValues["token"]; ok {
+		t.Error("ephemeral variable value is recorded in the plan's variable values")
+	}
+	if _, ok := plan.EphemeralVariableValues["token"]; !ok {
+		t.Error("ephemeral variable value is missing from the plan's ephemeral variable values")
+	}
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	got := p.ConfigureProviderRequest.Config.GetAttr("test_string")
+	if want := cty.StringVal("Bearer secret"); !got.RawEquals(want) {
+		t.Errorf("wrong provider configuration\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Plan_ephemeralVariablesInvalid(t *testing.T) {
+	tests := map[string]struct {
+		config      string
+		child       string
+		wantSummary string
+	}{
+		"resource argument": {
+			config: `
+				resource "test_object" "a" {
+					test_string = var.token
+				}
+			`,
+			wantSummary: "Ephemeral value not allowed",
+		},
+		"for_each argument": {
+			config: `
+				resource "test_object" "a" {
+					for_each = toset([var.token])
+				}
+			`,
+			wantSummary: "Invalid for_each argument",
+		},
+		"non-ephemeral output": {
+			config: `
+				output "token" {
+					value = var.token
+				}
+			`,
+			wantSummary: "Output refers to ephemeral values",
+		},
+		"ephemeral root output": {
+			config: `
+				output "token" {
+					value     = var.token
+					ephemeral = true
+				}
+			`,
+			wantSummary: "Ephemeral output not allowed",
+		},
+		"non-ephemeral module variable": {
+			config: `
+				module "child" {
+					source = "./child"
+					input  = var.token
+				}
+			`,
+			child: `
+				variable "input" {}
+			`,
+			wantSummary: "Ephemeral value not allowed",
+		},
+		"ephemeral module output in resource argument": {
+			config: `
+				module "child" {
+					source = "./child"
+					input  = var.token
+				}
+
+				resource "test_object" "a" {
+					test_string = module.child.output
+				}
+			`,
+			child: `
+				variable "input" {
+					ephemeral = true
+				}
+
+				output "output" {
+					value     = var.input
+					ephemeral = true
+				}
+			`,
+			wantSummary: "Ephemeral value not allowed",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files := map[string]string{
+				"main.tf": `
+					variable "token" {
+						type      = string
+						ephemeral = true
+					}
+				` + test.config,
+			}
+			if test.child != "" {
+				files["child/main.tf"] = test.child
+			}
+			m := testModuleInline(t, files)
+
+			p := simpleMockProvider()
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"token": &InputValue{
+						Value:      cty.StringVal("secret"),
+						SourceType: ValueFromCaller,
+					},
+				},
+			})
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want error")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantSummary) {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.wantSummary)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_documentAttributes(t *testing.T) {
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":       {Type: cty.String, Computed: true},
+					"policy":   {Type: cty.String, Optional: true},
+					"manifest": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"a","policy":"{\"a\":1,\"b\":[1,2]}","manifest":"kind: Pod\nspec:\n  replicas: 1\n"}`),
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	tests := map[string]struct {
+		config     string
+		wantAction plans.Action
+	}{
+		"reformatted documents": {
+			config: `
+resource "test_object" "a" {
+  policy   = "{ \"b\": [1, 2],\n  \"a\": 1 }"
+  manifest = "spec: {replicas: 1}\nkind: Pod"
+
+  lifecycle {
+    json_attributes = [policy]
+    yaml_attributes = [manifest]
+  }
+}
+`,
+			wantAction: plans.NoOp,
+		},
+		"changed document": {
+			config: `
+resource "test_object" "a" {
+  policy   = "{\"a\":1,\"b\":[2,1]}"
+  manifest = "kind: Pod\nspec:\n  replicas: 1\n"
+
+  lifecycle {
+    json_attributes = [policy]
+    yaml_attributes = [manifest]
+  }
+}
+`,
+			wantAction: plans.Update,
+		},
+		"not declared as document": {
+			config: `
+resource "test_object" "a" {
+  policy   = "{ \"b\": [1, 2],\n  \"a\": 1 }"
+  manifest = "kind: Pod\nspec:\n  replicas: 1\n"
+}
+`,
+			wantAction: plans.Update,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testModuleInline(t, map[string]string{
+				"main.tf": test.config,
+			})
+
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+			assertNoErrors(t, diags)
+
+			change := plan.Changes.ResourceInstance(addr)
+			if change == nil {
+				t.Fatalf("no change for %s", addr)
+			}
+			if got, want := change.Action, test.wantAction; got != want {
+				t.Errorf("wrong action %s; want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_onDrift(t *testing.T) {
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":   {Type: cty.String, Computed: true},
+					"size": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+	p.ReadResourceFn = func(req providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
+		// The remote object has been resized outside of OpenTofu.
+		resp.NewState = cty.ObjectVal(map[string]cty.Value{
+			"id":   req.PriorState.GetAttr("id"),
+			"size": cty.StringVal("large"),
+		})
+		return resp
+	}
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"a","size":"small"}`),
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	tests := map[string]struct {
+		policy      string
+		mode        plans.Mode
+		wantAction  plans.Action
+		wantWarning string
+		wantError   string
+	}{
+		"default": {
+			wantAction: plans.Update,
+		},
+		"reconcile": {
+			policy:     "reconcile",
+			wantAction: plans.Update,
+		},
+		"ignore": {
+			policy:      "ignore",
+			wantAction:  plans.NoOp,
+			wantWarning: "Resource drift ignored",
+		},
+		"fail": {
+			policy:    "fail",
+			wantError: "Resource drift detected",
+		},
+		"fail refresh-only": {
+			policy: "fail",
+			mode:   plans.RefreshOnlyMode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lifecycle := ""
+			if test.policy != "" {
+				lifecycle = fmt.Sprintf("lifecycle {\n    on_drift = %q\n  }", test.policy)
+			}
+			m := testModuleInline(t, map[string]string{
+				"main.tf": fmt.Sprintf(`
+resource "test_object" "a" {
+  size = "small"
+
+  %s
+}
+`, lifecycle),
+			})
+
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+				Mode: test.mode,
+			})
+			if test.wantError != "" {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, test.wantError) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantError)
+				}
+				return
+			}
+			assertNoErrors(t, diags)
+
+			if test.wantWarning != "" {
+				if got := diags.ErrWithWarnings().Error(); !strings.Contains(got, test.wantWarning) {
+					t.Errorf("missing warning\ngot:  %s\nwant: %s", got, test.wantWarning)
+				}
+			} else if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %s", diags.ErrWithWarnings())
+			}
+
+			if test.mode == plans.RefreshOnlyMode {
+				return
+			}
+			change := plan.Changes.ResourceInstance(addr)
+			if change == nil {
+				t.Fatalf("no change for %s", addr)
+			}
+			if got, want := change.Action, test.wantAction; got != want {
+				t.Errorf("wrong action %s; want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestContext2Plan_moduleCallConditions(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "sizes" {
+				type = map(number)
+			}
+
+			module "child" {
+				source   = "./child"
+				for_each = var.sizes
+				size     = each.value
+
+				precondition {
+					condition     = each.value > 0
+					error_message = "Size for ${each.key} must be positive."
+				}
+
+				postcondition {
+					condition     = self.doubled < 10
+					error_message = "Doubled size for ${each.key} is ${self.doubled}."
+				}
+			}
+
+			resource "test_object" "a" {
+				for_each   = module.child
+				test_string = tostring(each.value.doubled)
+			}
+		`,
+		"child/main.tf": `
+			variable "size" {
+				type = number
+			}
+
+			output "doubled" {
+				value = var.size * 2
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	plan := func(sizes map[string]cty.Value) []string {
+		t.Helper()
+		_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"sizes": &InputValue{
+					Value:      cty.MapVal(sizes),
+					SourceType: ValueFromCaller,
+				},
+			},
+		})
+		var failures []string
+		for _, diag := range diags {
+			desc := diag.Description()
+			switch desc.Summary {
+			case "Module precondition failed", "Module postcondition failed":
+				failures = append(failures, desc.Summary+": "+desc.Detail)
+			default:
+				if diag.Severity() == tfdiags.Error {
+					t.Errorf("unexpected error: %s: %s", desc.Summary, desc.Detail)
+				}
+			}
+		}
+		return failures
+	}
+
+	got := plan(map[string]cty.Value{
+		"a": cty.NumberIntVal(1),
+		"b": cty.NumberIntVal(2),
+	})
+	if len(got) != 0 {
+		t.Errorf("unexpected failures: %#v", got)
+	}
+
+	got = plan(map[string]cty.Value{
+		"a": cty.NumberIntVal(0),
+	})
+	if want := []string{"Module precondition failed: Size for a must be positive."}; !slices.Equal(got, want) {
+		t.Errorf("wrong failures\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	got = plan(map[string]cty.Value{
+		"a": cty.NumberIntVal(1),
+		"b": cty.NumberIntVal(5),
+	})
+	if want := []string{"Module postcondition failed: Doubled size for b is 10."}; !slices.Equal(got, want) {
+		t.Errorf("wrong failures\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestContext2Validate_moduleCallPreconditionSelf(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			module "child" {
+				source = "./child"
+
+				precondition {
+					condition     = self.value != ""
+					error_message = "Invalid."
+				}
+			}
+		`,
+		"child/main.tf": `
+			output "value" {
+				value = "a"
+			}
+		`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	diags := ctx.Validate(context.Background(), m)
+	if got, want := diags.Err().Error(), `Invalid "self" reference`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestContext2Plan_providerConfigSensitiveLogging(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "api_key" {
+				type      = string
+				sensitive = true
+			}
+
+			provider "test" {
+				token   = "provider-config-schema-secret"
+				headers = {
+					Authorization = var.api_key
+				}
+				region  = "provider-config-not-secret"
+			}
+
+			resource "test_object" "a" {
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.Provider = providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"token":   {Type: cty.String, Optional: true, Sensitive: true},
+				"headers": {Type: cty.Map(cty.String), Optional: true},
+				"region":  {Type: cty.String, Optional: true},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"api_key": &InputValue{
+				Value:      cty.StringVal("provider-config-variable-secret"),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	for _, secret := range []string{"provider-config-schema-secret", "provider-config-variable-secret"} {
+		if got := logging.Redact(secret); got == secret {
+			t.Errorf("%q is not masked in the logs", secret)
+		}
+	}
+	if got, want := logging.Redact("provider-config-not-secret"), "provider-config-not-secret"; got != want {
+		t.Errorf("non-sensitive value is masked in the logs: got %q", got)
+	}
+}
+
+func TestContext2Plan_enabled(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "on" {
+				type = bool
+			}
+
+			resource "test_object" "a" {
+				test_string = "a"
+
+				lifecycle {
+					enabled = var.on
+				}
+			}
+
+			resource "test_object" "b" {
+				test_string = "b"
+
+				lifecycle {
+					enabled = !var.on
+				}
+			}
+
+			module "child" {
+				source = "./child"
+
+				lifecycle {
+					enabled = var.on
+				}
+			}
+
+			resource "test_object" "c" {
+				test_string = try(test_object.b.test_string, "none")
+			}
+
+			output "child" {
+				value = module.child == null ? "disabled" : module.child.out
+			}
+		`,
+		"child/main.tf": `
+			resource "test_object" "d" {
+				test_string = "d"
+			}
+
+			output "out" {
+				value = test_object.d.test_string
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	assertNoErrors(t, diags)
+
+	// test_object.a was previously toggled with count, so its zeroth
+	// instance moves to the address without a key.
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_object.a[0]"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"test_string":"a"}`),
+				Status:    states.ObjectReady,
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+			addrs.NoKey,
+		)
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"on": &InputValue{
+				Value:      cty.True,
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	got := map[string]plans.Action{}
+	for _, c := range plan.Changes.Resources {
+		got[c.Addr.String()] = c.Action
+	}
+	want := map[string]plans.Action{
+		"test_object.a":              plans.NoOp,
+		"test_object.c":              plans.Create,
+		"module.child.test_object.d": plans.Create,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+	if c := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a")); c == nil || !c.PrevRunAddr.Equal(mustResourceInstanceAddr("test_object.a[0]")) {
+		t.Errorf("test_object.a did not move from test_object.a[0]")
+	}
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block
+	cChange, err := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.c")).Decode(schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cChange.After.GetAttr("test_string"), cty.StringVal("none"); !got.RawEquals(want) {
+		t.Errorf("wrong test_object.c value %#v; want %#v", got, want)
+	}
+	if got, want := plan.Changes.OutputValue(addrs.RootModuleInstance.OutputValue("child")), "d"; got == nil {
+		t.Errorf("no change for output")
+	} else if val, err := got.Decode(); err != nil {
+		t.Fatal(err)
+	} else if !val.After.RawEquals(cty.StringVal(want)) {
+		t.Errorf("wrong output value %#v; want %q", val.After, want)
+	}
+
+	// With the objects disabled, test_object.a is destroyed and the others
+	// aren't created.
+	plan, diags = ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"on": &InputValue{
+				Value:      cty.False,
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	got = map[string]plans.Action{}
+	for _, c := range plan.Changes.Resources {
+		got[c.Addr.String()] = c.Action
+	}
+	want = map[string]plans.Action{
+		"test_object.a": plans.Delete,
+		"test_object.b": plans.Create,
+		"test_object.c": plans.Create,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+	if got := plan.Changes.OutputValue(addrs.RootModuleInstance.OutputValue("child")); got == nil {
+		t.Errorf("no change for output")
+	} else if val, err := got.Decode(); err != nil {
+		t.Fatal(err)
+	} else if !val.After.RawEquals(cty.StringVal("disabled")) {
+		t.Errorf("wrong output value %#v; want \"disabled\"", val.After)
+	}
+}
+
+func TestContext2Plan_enabledUnknown(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "on" {
+				type = bool
+			}
+
+			resource "test_object" "a" {
+				lifecycle {
+					enabled = var.on
+				}
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"on": &InputValue{
+				Value:      cty.UnknownVal(cty.Bool),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Invalid enabled argument"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
@@ -7580,6 +7580,144 @@ func TestContext2Plan_variableCustomValidationsSensitive(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_variableCustomValidationsCrossVariable(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "enable_tls" {
+  type = bool
+
+  validation {
+    condition     = var.enable_tls || var.certificate_arn == null
+    error_message = "A certificate requires TLS."
+  }
+}
+
+variable "certificate_arn" {
+  type    = string
+  default = null
+
+  validation {
+    condition     = !var.enable_tls || var.certificate_arn != null
+    error_message = "The certificate must be set when TLS is enabled."
+  }
+
+  validation {
+    condition     = var.certificate_arn == null || startswith(coalesce(var.certificate_arn, "-"), local.prefix)
+    error_message = "The certificate must start with ${local.prefix}."
+  }
+}
+
+variable "child_tls" {
+  type = bool
+}
+
+locals {
+  prefix = "arn:"
+}
+
+module "child" {
+  source = "./child"
+
+  enable_tls      = var.child_tls
+  certificate_arn = var.certificate_arn
+}
+`,
+		"child/main.tf": `
+variable "enable_tls" {
+  type = bool
+}
+
+variable "certificate_arn" {
+  type = string
+
+  validation {
+    condition     = !var.enable_tls || var.certificate_arn != null
+    error_message = "The child module needs a certificate when TLS is enabled."
+  }
+}
+`,
+	})
+
+	tests := map[string]struct {
+		enableTLS      cty.Value
+		certificateARN cty.Value
+		childTLS       cty.Value
+		wantErrs       []string
+	}{
+		"valid": {
+			enableTLS:      cty.True,
+			certificateARN: cty.StringVal("arn:cert"),
+			childTLS:       cty.True,
+		},
+		"valid without tls": {
+			enableTLS:      cty.False,
+			certificateARN: cty.NullVal(cty.String),
+			childTLS:       cty.False,
+		},
+		"missing certificate": {
+			enableTLS:      cty.True,
+			certificateARN: cty.NullVal(cty.String),
+			childTLS:       cty.False,
+			wantErrs: []string{
+				"The certificate must be set when TLS is enabled.",
+			},
+		},
+		"missing certificate in child module": {
+			enableTLS:      cty.False,
+			certificateARN: cty.NullVal(cty.String),
+			childTLS:       cty.True,
+			wantErrs: []string{
+				"The child module needs a certificate when TLS is enabled.",
+			},
+		},
+		"certificate without tls": {
+			enableTLS:      cty.False,
+			certificateARN: cty.StringVal("cert"),
+			childTLS:       cty.False,
+			wantErrs: []string{
+				"A certificate requires TLS.",
+				"The certificate must start with arn:.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext2(t, &ContextOpts{})
+			_, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"enable_tls": &InputValue{
+						Value:      test.enableTLS,
+						SourceType: ValueFromCaller,
+					},
+					"certificate_arn": &InputValue{
+						Value:      test.certificateARN,
+						SourceType: ValueFromCaller,
+					},
+					"child_tls": &InputValue{
+						Value:      test.childTLS,
+						SourceType: ValueFromCaller,
+					},
+				},
+			})
+			if len(test.wantErrs) == 0 {
+				assertNoErrors(t, diags)
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want errors")
+			}
+			got := diags.ErrWithWarnings().Error()
+			for _, want := range test.wantErrs {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing error %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
 func TestContext2Plan_nullOutputNoOp(t *testing.T) {
 	// this should always plan a NoOp change for the output
 	m := testModuleInline(t, map[string]string{
@@ -2506,3 +2506,107 @@ func TestContext2Validate_rangeOverZeroPlanTimestamp(t *testing.T) {
 		t.Fatal(diags.ErrWithWarnings())
 	}
 }
+
+func TestContext2Validate_providerDependsOnInvalid(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "test" {
+				alias      = "late"
+				depends_on = [test_object.missing]
+			}
+
+			resource "test_object" "a" {
+				provider = test.late
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Reference to undeclared resource"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestContext2Validate_ephemeralResourceInManagedResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			ephemeral "test_object" "a" {
+				test_string = "secret"
+			}
+
+			resource "test_object" "b" {
+				test_string = ephemeral.test_object.a.test_string
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.EphemeralResources = map[string]providers.Schema{
+		"test_object": {Block: simpleTestSchema()},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Ephemeral value not allowed"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+	if !p.ValidateEphemeralResourceConfigCalled {
+		t.Errorf("ephemeral resource configuration wasn't validated")
+	}
+}
+
+func TestContext2Validate_documentAttributesNotString(t *testing.T) {
+	p := testProvider("test")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"count_value": {Type: cty.Number, Optional: true},
+				},
+			},
+		},
+	})
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  count_value = 1
+
+  lifecycle {
+    json_attributes = [count_value]
+  }
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(context.Background(), m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Invalid json_attributes element: The json_attributes argument can only refer to string attributes, but count_value is not a string attribute."; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -60,6 +60,10 @@ func (c *Context) walk(ctx context.Context, graph *Graph, operation walkOperatio
 	// Walk the real graph, this will block until it completes
 	diags := graph.Walk(ctx, walker)
 
+	// Ephemeral resources are normally closed during the walk, but some of
+	// them may still be open if the walk was cut short by an error.
+	diags = diags.Append(walker.EphemeralResources.CloseAll())
+
 	// Close the channel so the watcher stops, and wait for it to return.
 	close(watchStop)
 	<-watchWait
@@ -153,6 +157,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		InstanceExpander:        instances.NewExpander(),
 		MoveResults:             opts.MoveResults,
 		ImportResolver:          NewImportResolver(),
+		EphemeralResources:      NewEphemeralResources(),
 		Operation:               operation,
 		StopContext:             c.runContext,
 		PlanTimestamp:           opts.PlanTimeTimestamp,
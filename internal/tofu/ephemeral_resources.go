@@ -0,0 +1,205 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// EphemeralResources tracks the instances of ephemeral resources during a
+// graph walk. Ephemeral resources are never saved in the state or the plan,
+// so this is the only place their results are available from, and it is
+// also responsible for renewing the open instances until they are closed.
+//
+// The key of the map is a string representation of the instance address.
+type EphemeralResources struct {
+	mu        sync.Mutex
+	instances map[string]*ephemeralResourceInstance
+}
+
+type ephemeralResourceInstance struct {
+	addr  addrs.AbsResourceInstance
+	value cty.Value
+
+	// provider is nil for instances that weren't opened because their
+	// configuration wasn't known yet, and which therefore have an unknown
+	// value.
+	provider providers.Interface
+	typeName string
+
+	// mu protects private and renewDiags, which the renewal goroutine
+	// updates.
+	mu         sync.Mutex
+	private    []byte
+	renewDiags tfdiags.Diagnostics
+
+	stopRenew context.CancelFunc
+	renewDone chan struct{}
+}
+
+func NewEphemeralResources() *EphemeralResources {
+	return &EphemeralResources{instances: make(map[string]*ephemeralResourceInstance)}
+}
+
+// Open records the result of opening an instance of an ephemeral resource
+// using the given provider, and starts renewing it if the provider asked for
+// that.
+func (r *EphemeralResources) Open(addr addrs.AbsResourceInstance, provider providers.Interface, typeName string, val cty.Value, resp providers.OpenEphemeralResourceResponse) {
+	inst := &ephemeralResourceInstance{
+		addr:     addr,
+		value:    val,
+		provider: provider,
+		typeName: typeName,
+		private:  resp.Private,
+	}
+	if !resp.RenewAt.IsZero() {
+		ctx, cancel := context.WithCancel(context.Background())
+		inst.stopRenew = cancel
+		inst.renewDone = make(chan struct{})
+		go inst.renew(ctx, resp.RenewAt)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[addr.String()] = inst
+}
+
+// SetUnknown records that an instance of an ephemeral resource exists but
+// couldn't be opened yet, so that references to it have the given unknown
+// value.
+func (r *EphemeralResources) SetUnknown(addr addrs.AbsResourceInstance, val cty.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[addr.String()] = &ephemeralResourceInstance{
+		addr:  addr,
+		value: val,
+	}
+}
+
+// Values returns the values of all of the instances of the given ephemeral
+// resource, by their instance keys.
+func (r *EphemeralResources) Values(addr addrs.AbsResource) map[addrs.InstanceKey]cty.Value {
+	ret := make(map[addrs.InstanceKey]cty.Value)
+	if r == nil {
+		return ret
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, inst := range r.instances {
+		if inst.addr.ContainingResource().Equal(addr) {
+			ret[inst.addr.Resource.Key] = inst.value
+		}
+	}
+	return ret
+}
+
+// Close stops renewing and closes all of the open instances of the given
+// ephemeral resource, across all of the instances of its module.
+func (r *EphemeralResources) Close(addr addrs.ConfigResource) tfdiags.Diagnostics {
+	return r.close(func(inst *ephemeralResourceInstance) bool {
+		return inst.addr.ContainingResource().Config().Equal(addr)
+	})
+}
+
+// CloseAll closes all of the instances that are still open, which can happen
+// when a graph walk ends early because of an error.
+func (r *EphemeralResources) CloseAll() tfdiags.Diagnostics {
+	return r.close(func(*ephemeralResourceInstance) bool {
+		return true
+	})
+}
+
+func (r *EphemeralResources) close(match func(*ephemeralResourceInstance) bool) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if r == nil {
+		return diags
+	}
+
+	// We remove the instances from the map before closing them, so that
+	// nothing can refer to their results afterwards.
+	r.mu.Lock()
+	var closing []*ephemeralResourceInstance
+	for key, inst := range r.instances {
+		if match(inst) {
+			closing = append(closing, inst)
+			delete(r.instances, key)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, inst := range closing {
+		diags = diags.Append(inst.close())
+	}
+	return diags
+}
+
+func (i *ephemeralResourceInstance) close() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if i.provider == nil {
+		return diags
+	}
+
+	if i.stopRenew != nil {
+		i.stopRenew()
+		<-i.renewDone
+	}
+	diags = diags.Append(i.renewDiags)
+
+	log.Printf("[TRACE] EphemeralResources: closing %s", i.addr)
+	resp := i.provider.CloseEphemeralResource(providers.CloseEphemeralResourceRequest{
+		TypeName: i.typeName,
+		Private:  i.private,
+	})
+	return diags.Append(resp.Diagnostics)
+}
+
+// renew renews the instance each time the provider asks for it, until the
+// given context is cancelled or the renewal fails.
+func (i *ephemeralResourceInstance) renew(ctx context.Context, renewAt time.Time) {
+	defer close(i.renewDone)
+
+	for !renewAt.IsZero() {
+		timer := time.NewTimer(time.Until(renewAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		i.mu.Lock()
+		private := i.private
+		i.mu.Unlock()
+
+		log.Printf("[TRACE] EphemeralResources: renewing %s", i.addr)
+		resp := i.provider.RenewEphemeralResource(providers.RenewEphemeralResourceRequest{
+			TypeName: i.typeName,
+			Private:  private,
+		})
+
+		i.mu.Lock()
+		if resp.Diagnostics.HasErrors() {
+			log.Printf("[ERROR] EphemeralResources: failed to renew %s: %s", i.addr, resp.Diagnostics.Err())
+			i.renewDiags = i.renewDiags.Append(resp.Diagnostics)
+			i.mu.Unlock()
+			return
+		}
+		i.private = resp.Private
+		i.mu.Unlock()
+
+		renewAt = resp.RenewAt
+	}
+}
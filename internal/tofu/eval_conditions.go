@@ -271,9 +271,95 @@ You can correct this by removing references to sensitive values, or by carefully
 		})
 		return "", diags
 	}
+	if _, ephemeral := valMarks[marks.Ephemeral]; ephemeral {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Error message refers to ephemeral values",
+			Detail: `The error expression used to explain this condition refers to ephemeral values, so OpenTofu will not display the resulting message, because check results are saved in the state.
+
+You can correct this by removing references to ephemeral values.`,
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: hclCtx,
+		})
+		return "", diags
+	}
 
 	// NOTE: We've discarded any other marks the string might have been carrying,
 	// aside from the sensitive mark.
 
 	return strings.TrimSpace(val.AsString()), diags
 }
+
+// evalUntrackedConditions ensures that all of the given condition rules pass
+// when evaluated in the given scope, for objects whose conditions are not
+// tracked in the checks state, such as provider configurations and module
+// calls. Failed conditions are reported as errors with the given summary.
+//
+// Conditions with an unknown result are skipped, on the assumption that they
+// will be checked again once more values are known.
+func evalUntrackedConditions(rules []*configs.CheckRule, scope *lang.Scope, failureSummary string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, rule := range rules {
+		refs, moreDiags := lang.ReferencesInExpr(addrs.ParseRef, rule.Condition)
+		diags = diags.Append(moreDiags)
+		moreRefs, moreDiags := lang.ReferencesInExpr(addrs.ParseRef, rule.ErrorMessage)
+		diags = diags.Append(moreDiags)
+		refs = append(refs, moreRefs...)
+
+		hclCtx, moreDiags := scope.EvalContext(refs)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		result, hclDiags := rule.Condition.Value(hclCtx)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() || !result.IsKnown() {
+			continue
+		}
+		if result.IsNull() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid condition result",
+				Detail:      "Condition expression must return either true or false, not null.",
+				Subject:     rule.Condition.Range().Ptr(),
+				Expression:  rule.Condition,
+				EvalContext: hclCtx,
+			})
+			continue
+		}
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid condition result",
+				Detail:      fmt.Sprintf("Invalid condition result value: %s.", tfdiags.FormatError(err)),
+				Subject:     rule.Condition.Range().Ptr(),
+				Expression:  rule.Condition,
+				EvalContext: hclCtx,
+			})
+			continue
+		}
+		if result, _ = result.Unmark(); result.True() {
+			continue
+		}
+
+		errorMessage, moreDiags := evalCheckErrorMessage(rule.ErrorMessage, hclCtx)
+		diags = diags.Append(moreDiags)
+		if errorMessage == "" {
+			errorMessage = "This check failed, but has an invalid error message as described in the other accompanying messages."
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     failureSummary,
+			Detail:      errorMessage,
+			Subject:     rule.Condition.Range().Ptr(),
+			Expression:  rule.Condition,
+			EvalContext: hclCtx,
+		})
+	}
+
+	return diags
+}
@@ -212,6 +212,13 @@ type EvalContext interface {
 	// and have a configuration
 	ImportResolver() *ImportResolver
 
+	// EphemeralResources returns the object that tracks the instances of
+	// ephemeral resources that are open during the graph walk.
+	//
+	// The EphemeralResources object is shared across all of the EvalContext
+	// objects for a given graph walk.
+	EphemeralResources() *EphemeralResources
+
 	// WithPath returns a copy of the context with the internal path set to the
 	// path argument.
 	WithPath(path addrs.ModuleInstance) EvalContext
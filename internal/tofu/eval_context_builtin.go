@@ -81,6 +81,7 @@ type BuiltinEvalContext struct {
 	InstanceExpanderValue   *instances.Expander
 	MoveResultsValue        refactoring.MoveResults
 	ImportResolverValue     *ImportResolver
+	EphemeralResourcesValue *EphemeralResources
 	Encryption              encryption.Encryption
 	ProviderFunctionTracker ProviderFunctionMapping
 }
@@ -224,12 +225,33 @@ func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, p
 		return diags
 	}
 
+	callCtx, callDone := providerCallContext(ctx)
+	defer callDone()
 	req := providers.ConfigureProviderRequest{
 		TerraformVersion: version.String(),
 		Config:           cfg,
+		Context:          callCtx,
 	}
 
 	resp := p.ConfigureProvider(req)
+	if cancelDiags := providerCallCancelled(callCtx, addr.InstanceString(providerKey), "configure itself"); cancelDiags.HasErrors() {
+		return cancelDiags
+	}
+	if !resp.CredentialsExpiry.IsZero() && !resp.Diagnostics.HasErrors() {
+		// The provider's credentials expire, so we replace the cached
+		// instance with one that configures it again before they do.
+		ctx.ProviderLock.Lock()
+		p = newCredentialsRefreshingProvider(p, addr.InstanceString(providerKey), req, resp.CredentialsExpiry)
+		ctx.ProviderCache[addr.String()][providerKey] = p
+		ctx.ProviderLock.Unlock()
+	}
+	if p.GetProviderSchema().ServerCapabilities.ApplyResourceChanges && !resp.Diagnostics.HasErrors() {
+		// The provider can apply several changes at once, so we replace the
+		// cached instance with one that groups the changes to apply.
+		ctx.ProviderLock.Lock()
+		ctx.ProviderCache[addr.String()][providerKey] = newBatchingProvider(p, addr.InstanceString(providerKey))
+		ctx.ProviderLock.Unlock()
+	}
 	return resp.Diagnostics
 }
 
@@ -475,7 +497,7 @@ func (ctx *BuiltinEvalContext) EvaluationScope(self addrs.Referenceable, source
 			}
 
 			var keyDiags tfdiags.Diagnostics
-			providerKey, keyDiags = resolveProviderModuleInstance(ctx, providedBy.KeyExpression, moduleInstanceForKey, ctx.PathValue.String()+" "+pf.String())
+			providerKey, keyDiags = resolveProviderModuleInstance(ctx, providedBy.KeyExpression, providedBy.KeyType, moduleInstanceForKey, ctx.PathValue.String()+" "+pf.String())
 			if keyDiags.HasErrors() {
 				return nil, keyDiags
 			}
@@ -585,6 +607,10 @@ func (ctx *BuiltinEvalContext) ImportResolver() *ImportResolver {
 	return ctx.ImportResolverValue
 }
 
+func (ctx *BuiltinEvalContext) EphemeralResources() *EphemeralResources {
+	return ctx.EphemeralResourcesValue
+}
+
 func (ctx *BuiltinEvalContext) GetEncryption() encryption.Encryption {
 	return ctx.Encryption
 }
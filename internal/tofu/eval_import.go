@@ -6,6 +6,7 @@ package tofu
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -15,10 +16,14 @@ import (
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/gocty"
 )
 
-func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData instances.RepetitionData) (string, tfdiags.Diagnostics) {
+// evaluateImportIdExpression evaluates the "id" argument of an import block.
+// The ID is either a string or a list of strings, which are joined using the
+// given separator to build a composite ID.
+func evaluateImportIdExpression(expr hcl.Expression, separator string, ctx EvalContext, keyData instances.RepetitionData) (string, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	if expr == nil {
@@ -31,8 +36,11 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData in
 	}
 
 	// evaluate the import ID and take into consideration the for_each key (if exists)
-	importIdVal, evalDiags := evaluateExprWithRepetitionData(ctx, expr, cty.String, keyData)
+	importIdVal, evalDiags := evaluateExprWithRepetitionData(ctx, expr, cty.DynamicPseudoType, keyData)
 	diags = diags.Append(evalDiags)
+	if evalDiags.HasErrors() {
+		return "", diags
+	}
 
 	if importIdVal.IsNull() {
 		return "", diags.Append(&hcl.Diagnostic{
@@ -43,7 +51,7 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData in
 		})
 	}
 
-	if !importIdVal.IsKnown() {
+	if !importIdVal.IsWhollyKnown() {
 		return "", diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
@@ -55,7 +63,8 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData in
 		})
 	}
 
-	if importIdVal.HasMark(marks.Sensitive) {
+	importIdVal, valMarks := importIdVal.UnmarkDeep()
+	if _, sensitive := valMarks[marks.Sensitive]; sensitive {
 		return "", diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
@@ -63,14 +72,45 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData in
 			Subject:  expr.Range().Ptr(),
 		})
 	}
+	if _, ephemeral := valMarks[marks.Ephemeral]; ephemeral {
+		return "", diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid import id argument",
+			Detail:   "The import ID cannot be ephemeral, because it is saved in the plan.",
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+
+	if ty := importIdVal.Type(); ty.IsListType() || ty.IsTupleType() {
+		var parts []string
+		partsVal, err := convert.Convert(importIdVal, cty.List(cty.String))
+		if err == nil {
+			err = gocty.FromCtyValue(partsVal, &parts)
+		}
+		if err == nil && len(parts) == 0 {
+			err = fmt.Errorf("a composite import ID must have at least one element")
+		}
+		if err != nil {
+			return "", diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import id argument",
+				Detail:   fmt.Sprintf("The import ID value is unsuitable: %s.", err),
+				Subject:  expr.Range().Ptr(),
+			})
+		}
+		return strings.Join(parts, separator), diags
+	}
 
 	var importId string
-	err := gocty.FromCtyValue(importIdVal, &importId)
+	importIdVal, err := convert.Convert(importIdVal, cty.String)
+	if err == nil {
+		err = gocty.FromCtyValue(importIdVal, &importId)
+	}
 	if err != nil {
 		return "", diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid import id argument",
-			Detail:   fmt.Sprintf("The import ID value is unsuitable: %s.", err),
+			Detail:   fmt.Sprintf("The import ID value is unsuitable: %s. The import ID must be a string, or a list of strings to join into a composite ID.", err),
 			Subject:  expr.Range().Ptr(),
 		})
 	}
@@ -189,6 +229,14 @@ func parseImportIndexKeyExpr(ctx EvalContext, expr hcl.Expression, keyData insta
 			Subject:  expr.Range().Ptr(),
 		})
 	}
+	if _, ephemeral := valMarks[marks.Ephemeral]; ephemeral {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Import block 'to' address contains an invalid key",
+			Detail:   "Import block contained a resource address using an index which is ephemeral. Please ensure indexes used in the resource address of an import target are not ephemeral",
+			Subject:  expr.Range().Ptr(),
+		})
+	}
 
 	idx.Key = unmarkedVal
 	return idx, diags
@@ -11,6 +11,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/zclconf/go-cty/cty"
@@ -46,6 +47,26 @@ func TestEvaluateImportIdExpression_SensitiveValue(t *testing.T) {
 			expr:    hcltest.MockExprLiteral(cty.UnknownVal(cty.String)),
 			wantErr: "Invalid import id argument: The import block \"id\" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.", // Adapted the message from your original code
 		},
+		{
+			name:    "sensitive_composite_element",
+			expr:    hcltest.MockExprLiteral(cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b").Mark(marks.Sensitive)})),
+			wantErr: "Invalid import id argument: The import ID cannot be sensitive.",
+		},
+		{
+			name:    "unknown_composite_element",
+			expr:    hcltest.MockExprLiteral(cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.UnknownVal(cty.String)})),
+			wantErr: "Invalid import id argument: The import block \"id\" argument depends on resource attributes that cannot be determined until apply, so OpenTofu cannot plan to import this resource.",
+		},
+		{
+			name:    "empty_composite",
+			expr:    hcltest.MockExprLiteral(cty.EmptyTupleVal),
+			wantErr: "Invalid import id argument: The import ID value is unsuitable: a composite import ID must have at least one element.",
+		},
+		{
+			name:    "object",
+			expr:    hcltest.MockExprLiteral(cty.EmptyObjectVal),
+			wantErr: "Invalid import id argument: The import ID value is unsuitable: string required. The import ID must be a string, or a list of strings to join into a composite ID.",
+		},
 		{
 			name:    "valid_value",
 			expr:    hcltest.MockExprLiteral(cty.StringVal("value")),
@@ -55,7 +76,7 @@ func TestEvaluateImportIdExpression_SensitiveValue(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, diags := evaluateImportIdExpression(tc.expr, ctx, EvalDataForNoInstanceKey)
+			_, diags := evaluateImportIdExpression(tc.expr, configs.DefaultImportIDSeparator, ctx, EvalDataForNoInstanceKey)
 
 			if tc.wantErr != "" {
 				if len(diags) != 1 {
@@ -71,3 +92,48 @@ func TestEvaluateImportIdExpression_SensitiveValue(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateImportIdExpression_composite(t *testing.T) {
+	ctx := &MockEvalContext{}
+	ctx.installSimpleEval()
+	ctx.EvaluationScopeScope = &lang.Scope{}
+
+	testCases := map[string]struct {
+		val       cty.Value
+		separator string
+		want      string
+	}{
+		"string": {
+			val:       cty.StringVal("i-abc123"),
+			separator: configs.DefaultImportIDSeparator,
+			want:      "i-abc123",
+		},
+		"number": {
+			val:       cty.NumberIntVal(42),
+			separator: configs.DefaultImportIDSeparator,
+			want:      "42",
+		},
+		"tuple": {
+			val:       cty.TupleVal([]cty.Value{cty.StringVal("my-project"), cty.StringVal("us-east1"), cty.NumberIntVal(3)}),
+			separator: configs.DefaultImportIDSeparator,
+			want:      "my-project/us-east1/3",
+		},
+		"list with custom separator": {
+			val:       cty.ListVal([]cty.Value{cty.StringVal("sg-123"), cty.StringVal("ingress")}),
+			separator: "_",
+			want:      "sg-123_ingress",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := evaluateImportIdExpression(hcltest.MockExprLiteral(tc.val), tc.separator, ctx, EvalDataForNoInstanceKey)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics %s", spew.Sdump(diags))
+			}
+			if got != tc.want {
+				t.Errorf("wrong import ID\ngot:  %s\nwant: %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -52,21 +53,24 @@ func buildProviderConfig(ctx EvalContext, addr addrs.AbsProviderConfig, config *
 	}
 }
 
-func resolveProviderResourceInstance(ctx EvalContext, keyExpr hcl.Expression, resourcePath addrs.AbsResourceInstance) (addrs.InstanceKey, tfdiags.Diagnostics) {
+func resolveProviderResourceInstance(ctx EvalContext, keyExpr hcl.Expression, keyType addrs.InstanceKeyType, resourcePath addrs.AbsResourceInstance) (addrs.InstanceKey, tfdiags.Diagnostics) {
 	keyData := ctx.InstanceExpander().GetResourceInstanceRepetitionData(resourcePath)
 	keyScope := ctx.EvaluationScope(nil, nil, keyData)
-	return resolveProviderInstance(keyExpr, keyScope, resourcePath.String())
+	return resolveProviderInstance(keyExpr, keyType, keyScope, resourcePath.String())
 }
 
-func resolveProviderModuleInstance(ctx EvalContext, keyExpr hcl.Expression, modulePath addrs.ModuleInstance, source string) (addrs.InstanceKey, tfdiags.Diagnostics) {
+func resolveProviderModuleInstance(ctx EvalContext, keyExpr hcl.Expression, keyType addrs.InstanceKeyType, modulePath addrs.ModuleInstance, source string) (addrs.InstanceKey, tfdiags.Diagnostics) {
 	keyData := ctx.InstanceExpander().GetModuleInstanceRepetitionData(modulePath)
 	// module providers block is evaluated in the parent module scope, similar to GraphNodeReferenceOutside
 	evalPath := modulePath.Parent()
 	keyScope := ctx.WithPath(evalPath).EvaluationScope(nil, nil, keyData)
-	return resolveProviderInstance(keyExpr, keyScope, source)
+	return resolveProviderInstance(keyExpr, keyType, keyScope, source)
 }
 
-func resolveProviderInstance(keyExpr hcl.Expression, keyScope *lang.Scope, source string) (addrs.InstanceKey, tfdiags.Diagnostics) {
+// resolveProviderInstance evaluates the given instance key expression. The keyType
+// is the type of the keys of the target provider configuration: IntKeyType for
+// configurations using count, or StringKeyType for those using for_each.
+func resolveProviderInstance(keyExpr hcl.Expression, keyType addrs.InstanceKeyType, keyScope *lang.Scope, source string) (addrs.InstanceKey, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	keyVal, keyDiags := keyScope.EvalExpr(keyExpr, cty.DynamicPseudoType)
@@ -84,6 +88,14 @@ func resolveProviderInstance(keyExpr hcl.Expression, keyScope *lang.Scope, sourc
 			Extra:    evalchecks.DiagnosticCausedBySensitive(true),
 		})
 	}
+	if keyVal.HasMark(marks.Ephemeral) {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider instance key",
+			Detail:   "A provider instance key must not be derived from an ephemeral value, because it is saved in the state.",
+			Subject:  keyExpr.Range().Ptr(),
+		})
+	}
 	if keyVal.IsNull() {
 		return nil, diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -102,6 +114,28 @@ func resolveProviderInstance(keyExpr hcl.Expression, keyScope *lang.Scope, sourc
 		})
 	}
 
+	if keyType == addrs.IntKeyType {
+		// string values are converted to number, so that keys such as "0" are accepted
+		var index int
+		numVal, err := convert.Convert(keyVal, cty.Number)
+		if err == nil {
+			numVal, _ = numVal.Unmark()
+			err = gocty.FromCtyValue(numVal, &index)
+		}
+		if err == nil && index < 0 {
+			err = fmt.Errorf("must be greater than or equal to zero")
+		}
+		if err != nil {
+			return nil, diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider instance key",
+				Detail:   fmt.Sprintf("The given instance key is unsuitable for a provider configuration that uses count: %s.", tfdiags.FormatError(err)),
+				Subject:  keyExpr.Range().Ptr(),
+			})
+		}
+		return addrs.IntKey(index), diags
+	}
+
 	// bool and number type are converted to string
 	keyVal, convertErr := convert.Convert(keyVal, cty.String)
 	if convertErr != nil {
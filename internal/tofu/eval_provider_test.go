@@ -6,6 +6,7 @@
 package tofu
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -96,7 +97,7 @@ func TestResolveProviderInstance_TypeConversion(t *testing.T) {
 				BaseDir: ".",
 			}
 			// call of the function to test
-			actualKey, diags := resolveProviderInstance(expr, scope, "test-source")
+			actualKey, diags := resolveProviderInstance(expr, addrs.StringKeyType, scope, "test-source")
 
 			if diags.HasErrors() {
 				t.Fatalf("Unexpected error: %s", diags.Err())
@@ -108,3 +109,68 @@ func TestResolveProviderInstance_TypeConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveProviderInstance_countKey(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputValue  cty.Value
+		expectedKey addrs.InstanceKey
+		wantErr     string
+	}{
+		{
+			name:        "integer",
+			inputValue:  cty.NumberIntVal(1),
+			expectedKey: addrs.IntKey(1),
+		},
+		{
+			name:        "numeric_string",
+			inputValue:  cty.StringVal("2"),
+			expectedKey: addrs.IntKey(2),
+		},
+		{
+			name:       "fraction",
+			inputValue: cty.NumberFloatVal(1.5),
+			wantErr:    "The given instance key is unsuitable for a provider configuration that uses count",
+		},
+		{
+			name:       "negative",
+			inputValue: cty.NumberIntVal(-1),
+			wantErr:    "must be greater than or equal to zero",
+		},
+		{
+			name:       "string",
+			inputValue: cty.StringVal("a"),
+			wantErr:    "a number is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr := hcl.StaticExpr(tc.inputValue, hcl.Range{})
+			scope := &lang.Scope{
+				Data: &evaluationStateData{
+					ModulePath:      addrs.RootModuleInstance,
+					InstanceKeyData: instances.RepetitionData{},
+				},
+				BaseDir: ".",
+			}
+			actualKey, diags := resolveProviderInstance(expr, addrs.IntKeyType, scope, "test-source")
+
+			if tc.wantErr != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("unexpected success; want error containing %q", tc.wantErr)
+				}
+				if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("Unexpected error: %s", diags.Err())
+			}
+			if actualKey != tc.expectedKey {
+				t.Fatalf("Incorrect instance key got:  %#v want: %#v", actualKey, tc.expectedKey)
+			}
+		})
+	}
+}
@@ -35,6 +35,9 @@ func prepareFinalInputVariableValue(addr addrs.AbsInputVariableInstance, raw *In
 		log.Printf("[TRACE] prepareFinalInputVariableValue: %s has a default value", addr)
 		var err error
 		defaultVal, err = convert.Convert(cfg.Default, convertTy)
+		if err == nil {
+			defaultVal, err = cfg.TypeRefinements.Apply(defaultVal)
+		}
 		if err != nil {
 			// Validation of the declaration should typically catch this,
 			// but we'll check it here too to be robust.
@@ -103,11 +106,12 @@ func prepareFinalInputVariableValue(addr addrs.AbsInputVariableInstance, raw *In
 	// unless the converted value is null. We do not apply defaults to top-level
 	// null values, as doing so could prevent assigning null to a nullable
 	// variable.
-	if cfg.TypeDefaults != nil && !given.IsNull() {
-		given = cfg.TypeDefaults.Apply(given)
-	}
+	given = cfg.ApplyTypeDefaults(given)
 
 	val, err := convert.Convert(given, convertTy)
+	if err == nil {
+		val, err = cfg.TypeRefinements.Apply(val)
+	}
 	if err != nil {
 		log.Printf("[ERROR] prepareFinalInputVariableValue: %s has unsuitable type\n  got:  %s\n  want: %s", addr, given.Type(), convertTy)
 		var detail string
@@ -222,11 +226,11 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 	// because the value expression (n.Expr, if set) comes from the calling
 	// "module" block in the parent module.
 	//
-	// Validation expressions are statically validated (during configuration
-	// loading) to refer only to the variable being validated, so we can
-	// bypass our usual evaluation machinery here and just produce a minimal
-	// evaluation context containing just the required value, and thus avoid
-	// the problem that ctx's evaluation functions refer to the wrong module.
+	// Validation expressions are evaluated in the module that declares the
+	// variable, where they can also refer to other objects such as other
+	// input variables and local values, but the value of the variable being
+	// validated isn't available there until its checks have been reported,
+	// so we inject it into the evaluation context below.
 	val := ctx.GetVariableValue(addr)
 	if val == cty.NilVal {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -236,13 +240,11 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 		})
 		return diags
 	}
-	if config.Sensitive {
-		// Normally this marking is handled automatically during construction
-		// of the HCL eval context the evaluation scope, but this codepath
-		// augments that scope with the not-yet-finalized input variable
-		// value, so we need to apply this mark separately.
-		val = val.Mark(marks.Sensitive)
-	}
+	// Normally this marking is handled automatically during construction
+	// of the HCL eval context the evaluation scope, but this codepath
+	// augments that scope with the not-yet-finalized input variable
+	// value, so we need to apply the marks separately.
+	val = markInputVariableValue(val, config)
 	for ix, validation := range config.Validations {
 		condRefs, condDiags := lang.ReferencesInExpr(addrs.ParseRef, validation.Condition)
 		diags = diags.Append(condDiags)
@@ -214,6 +214,22 @@ func TestPrepareFinalInputVariableValue(t *testing.T) {
             )
 			default = {}
         }
+		variable "refined_union" {
+			type    = union(bool, number)
+			default = 1
+		}
+		variable "refined_nested_range" {
+			type = list(range(1, 10))
+		}
+		variable "deep_merged_nested_default" {
+			type = object({
+				thing = optional(object({
+					flag = bool
+					name = string
+				}), { flag = true, name = "default" })
+			})
+			deep_merge_defaults = true
+		}
 	`
 	cfg := testModuleInline(t, map[string]string{
 		"main.tf": cfgSrc,
@@ -831,6 +847,53 @@ func TestPrepareFinalInputVariableValue(t *testing.T) {
 			`Invalid value for input variable: Unsuitable value for var.invalid_nested_type set from outside of the configuration: incorrect map element type: attribute "rules": element "destination_addresses": object required.`,
 		},
 
+		// refined types
+		{
+			"refined_union",
+			cty.NilVal,
+			cty.NumberIntVal(1),
+			``,
+		},
+		{
+			"refined_union",
+			cty.StringVal("true"),
+			cty.True,
+			``,
+		},
+		{
+			"refined_union",
+			cty.StringVal("yes"),
+			cty.UnknownVal(cty.DynamicPseudoType),
+			`Invalid value for input variable: Unsuitable value for var.refined_union set from outside of the configuration: value must be one of the following types: bool, number.`,
+		},
+		{
+			"refined_nested_range",
+			cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.StringVal("10")}),
+			cty.ListVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(10)}),
+			``,
+		},
+		{
+			"refined_nested_range",
+			cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(11)}),
+			cty.UnknownVal(cty.List(cty.Number)),
+			`Invalid value for input variable: Unsuitable value for var.refined_nested_range set from outside of the configuration: element 1: number must be at most 10.`,
+		},
+		{
+			"deep_merged_nested_default",
+			cty.ObjectVal(map[string]cty.Value{
+				"thing": cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("given"),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"thing": cty.ObjectVal(map[string]cty.Value{
+					"flag": cty.True,
+					"name": cty.StringVal("given"),
+				}),
+			}),
+			``,
+		},
+
 		// sensitive
 		{
 			"constrained_string_sensitive_required",
@@ -68,6 +68,10 @@ type Evaluator struct {
 	Changes *plans.ChangesSync
 
 	PlanTimestamp time.Time
+
+	// EphemeralResources tracks the instances of ephemeral resources, whose
+	// results aren't saved in State.
+	EphemeralResources *EphemeralResources
 }
 
 // Scope creates an evaluation scope for the given module path and optional
@@ -266,10 +270,7 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 	// more information available and so can be more conservative.
 	if d.Operation == walkValidate {
 		// Ensure variable sensitivity is captured in the validate walk
-		if config.Sensitive {
-			return cty.UnknownVal(config.Type).Mark(marks.Sensitive), diags
-		}
-		return cty.UnknownVal(config.Type), diags
+		return markInputVariableValue(cty.UnknownVal(config.Type), config), diags
 	}
 
 	moduleAddrStr := d.ModulePath.String()
@@ -303,12 +304,19 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 		val = cty.UnknownVal(config.Type)
 	}
 
-	// Mark if sensitive
+	return markInputVariableValue(val, config), diags
+}
+
+// markInputVariableValue marks the given value of the given input variable
+// as sensitive and ephemeral, as declared in its configuration.
+func markInputVariableValue(val cty.Value, config *configs.Variable) cty.Value {
 	if config.Sensitive {
 		val = val.Mark(marks.Sensitive)
 	}
-
-	return val, diags
+	if config.Ephemeral {
+		val = val.Mark(marks.Ephemeral)
+	}
+	return val
 }
 
 func (d *evaluationStateData) GetLocalValue(addr addrs.LocalValue, rng tfdiags.SourceRange) (cty.Value, tfdiags.Diagnostics) {
@@ -473,6 +481,16 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 				instance[cfg.Name] = change.After.Mark(marks.Sensitive)
 			}
 		}
+
+		// The values of ephemeral outputs are not recorded in the planned
+		// changes with their marks, so we mark them here again.
+		if cfg.Ephemeral {
+			for _, instance := range moduleInstances {
+				if val, ok := instance[cfg.Name]; ok {
+					instance[cfg.Name] = val.Mark(marks.Ephemeral)
+				}
+			}
+		}
 	}
 
 	var ret cty.Value
@@ -534,6 +552,10 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 			ret = cty.EmptyObjectVal
 		}
 
+	case callConfig.Enabled != nil && moduleInstances[addrs.NoKey] == nil:
+		// A disabled module call has no instance, and so it's null.
+		ret = cty.NullVal(cty.Object(unknownMap))
+
 	default:
 		val, ok := moduleInstances[addrs.NoKey]
 		if !ok {
@@ -683,6 +705,10 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 	}
 	ty := schema.ImpliedType()
 
+	if addr.Mode == addrs.EphemeralResourceMode {
+		return d.getEphemeralResource(addr, config, ty), diags
+	}
+
 	rs := d.Evaluator.State.Resource(addr.Absolute(d.ModulePath))
 
 	if rs == nil {
@@ -696,6 +722,9 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 				return cty.EmptyTupleVal, diags
 			case config.ForEach != nil:
 				return cty.EmptyObjectVal, diags
+			case config.Enabled != nil:
+				// A disabled resource has no instance, and so it's null.
+				return cty.NullVal(ty), diags
 			default:
 				// While we can reference an expanded resource with 0
 				// instances, we cannot reference instances that do not exist.
@@ -831,6 +860,14 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 		instances[key] = val
 	}
 
+	return resourceInstancesValue(config, instances, ty), diags
+}
+
+// resourceInstancesValue returns the value that represents all of the given
+// instances of a resource, which is a tuple or an object of instances when
+// the resource uses count or for_each respectively, and null when a resource
+// using enabled has no instance.
+func resourceInstancesValue(config *configs.Resource, instances map[addrs.InstanceKey]cty.Value, ty cty.Type) cty.Value {
 	// ret should be populated with a valid value in all cases below
 	var ret cty.Value
 
@@ -892,6 +929,15 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 			ret = cty.EmptyObjectVal
 		}
 
+	case config.Enabled != nil:
+		val, ok := instances[addrs.NoKey]
+		if !ok {
+			// The resource is disabled
+			val = cty.NullVal(ty)
+		}
+
+		ret = val
+
 	default:
 		val, ok := instances[addrs.NoKey]
 		if !ok {
@@ -902,7 +948,30 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 		ret = val
 	}
 
-	return ret, diags
+	return ret
+}
+
+// getEphemeralResource returns the value of an ephemeral resource, whose
+// instances are never saved in the state. Its instances are unknown until
+// they are opened, and always have the ephemeral mark.
+func (d *evaluationStateData) getEphemeralResource(addr addrs.Resource, config *configs.Resource, ty cty.Type) cty.Value {
+	instances := d.Evaluator.EphemeralResources.Values(addr.Absolute(d.ModulePath))
+
+	var ret cty.Value
+	switch d.Operation {
+	case walkPlan, walkApply, walkDestroy, walkImport:
+		ret = resourceInstancesValue(config, instances, ty)
+	default:
+		// Ephemeral resources are only opened in the walks above, so we
+		// don't know how many instances there are during validate.
+		if config.Count != nil || config.ForEach != nil {
+			ret = cty.DynamicVal
+		} else {
+			ret = cty.UnknownVal(ty)
+		}
+	}
+
+	return ret.Mark(marks.Ephemeral)
 }
 
 func (d *evaluationStateData) getResourceSchema(addr addrs.Resource, providerAddr addrs.Provider) *configschema.Block {
@@ -923,6 +992,13 @@ func (d *evaluationStateData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfd
 		workspaceName := d.Evaluator.Meta.Env
 		return cty.StringVal(workspaceName), diags
 
+	case "workspace_tags":
+		var workspaces *configs.Workspaces
+		if d.Evaluator.Config != nil && d.Evaluator.Config.Root != nil {
+			workspaces = d.Evaluator.Config.Root.Module.Workspaces
+		}
+		return workspaces.TagsValue(d.Evaluator.Meta.Env), diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -939,7 +1015,7 @@ func (d *evaluationStateData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfd
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  fmt.Sprintf("Invalid %q attribute", addr.Alias),
-			Detail:   fmt.Sprintf(`The %q object does not have an attribute named %q. The only supported attribute is %s.workspace, the name of the currently-selected workspace.`, addr.Alias, addr.Name, addr.Alias),
+			Detail:   fmt.Sprintf(`The %q object does not have an attribute named %q. The only supported attributes are %s.workspace, the name of the currently-selected workspace, and %s.workspace_tags, the tags declared for it.`, addr.Alias, addr.Name, addr.Alias, addr.Alias),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -56,6 +56,48 @@ func TestEvaluatorGetTerraformAttr(t *testing.T) {
 	})
 }
 
+func TestEvaluatorGetTerraformAttr_workspaceTags(t *testing.T) {
+	cfg := &configs.Config{
+		Module: &configs.Module{
+			Workspaces: &configs.Workspaces{
+				Workspaces: map[string]*configs.Workspace{
+					"foo": {Name: "foo", Tags: []string{"critical", "prod"}},
+					"bar": {Name: "bar"},
+				},
+			},
+		},
+	}
+	cfg.Root = cfg
+
+	tests := map[string]cty.Value{
+		"foo": cty.SetVal([]cty.Value{cty.StringVal("critical"), cty.StringVal("prod")}),
+		"bar": cty.SetValEmpty(cty.String),
+		"baz": cty.SetValEmpty(cty.String),
+	}
+	for workspace, want := range tests {
+		t.Run(workspace, func(t *testing.T) {
+			evaluator := &Evaluator{
+				Meta: &ContextMeta{
+					Env: workspace,
+				},
+				Config: cfg,
+			}
+			data := &evaluationStateData{
+				Evaluator: evaluator,
+			}
+			scope := evaluator.Scope(data, nil, nil, nil)
+
+			got, diags := scope.Data.GetTerraformAttr(addrs.NewTerraformAttr("terraform", "workspace_tags"), tfdiags.SourceRange{})
+			if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics %s", spew.Sdump(diags))
+			}
+			if !got.RawEquals(want) {
+				t.Errorf("wrong result %#v; want %#v", got, want)
+			}
+		})
+	}
+}
+
 func TestEvaluatorGetPathAttr(t *testing.T) {
 	evaluator := &Evaluator{
 		Meta: &ContextMeta{
@@ -196,11 +196,15 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 	var diags tfdiags.Diagnostics
 
 	var modeAdjective string
+	article := "A"
 	switch addr.Mode {
 	case addrs.ManagedResourceMode:
 		modeAdjective = "managed"
 	case addrs.DataResourceMode:
 		modeAdjective = "data"
+	case addrs.EphemeralResourceMode:
+		modeAdjective = "ephemeral"
+		article = "An"
 	default:
 		// should never happen
 		modeAdjective = "<invalid-mode>"
@@ -222,7 +226,7 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Reference to undeclared resource`,
-			Detail:   fmt.Sprintf(`A %s resource %q %q has not been declared in %s.%s`, modeAdjective, addr.Type, addr.Name, moduleConfigDisplayAddr(modCfg.Path), suggestion),
+			Detail:   fmt.Sprintf(`%s %s resource %q %q has not been declared in %s.%s`, article, modeAdjective, addr.Type, addr.Name, moduleConfigDisplayAddr(modCfg.Path), suggestion),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return diags
@@ -258,7 +262,7 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid resource type`,
-			Detail:   fmt.Sprintf(`A %s resource type %q is not supported by provider %q.`, modeAdjective, addr.Type, providerFqn.String()),
+			Detail:   fmt.Sprintf(`%s %s resource type %q is not supported by provider %q.`, article, modeAdjective, addr.Type, providerFqn.String()),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return diags
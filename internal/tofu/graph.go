@@ -32,17 +32,29 @@ func (g *Graph) DirectedGraph() dag.Grapher {
 	return &g.AcyclicGraph
 }
 
+// graphWalkPrioritizer is implemented by graph walkers that want to know the
+// shape of each graph before it is walked, to decide the order of execution.
+type graphWalkPrioritizer interface {
+	prioritize(g *Graph, parent dag.Vertex)
+}
+
 // Walk walks the graph with the given walker for callbacks. The graph
 // will be walked with full parallelism, so the walker should expect
 // to be called in concurrently.
 func (g *Graph) Walk(ctx context.Context, walker GraphWalker) tfdiags.Diagnostics {
-	return g.walk(ctx, walker)
+	return g.walk(ctx, walker, nil)
 }
 
-func (g *Graph) walk(ctx context.Context, walker GraphWalker) tfdiags.Diagnostics {
+// walk walks the graph. parent is the vertex whose dynamic expansion produced
+// this graph, or nil for the main graph.
+func (g *Graph) walk(ctx context.Context, walker GraphWalker, parent dag.Vertex) tfdiags.Diagnostics {
 	// The callbacks for enter/exiting a graph
 	evalCtx := walker.EvalContext()
 
+	if pw, ok := walker.(graphWalkPrioritizer); ok {
+		pw.prioritize(g, parent)
+	}
+
 	// We explicitly create the panicHandler before
 	// spawning many go routines for vertex evaluation
 	// to minimize the performance impact of capturing
@@ -123,7 +135,7 @@ func (g *Graph) walk(ctx context.Context, walker GraphWalker) tfdiags.Diagnostic
 
 				// Walk the subgraph
 				log.Printf("[TRACE] vertex %q: entering dynamic subgraph", dag.VertexName(v))
-				subDiags := g.walk(ctx, walker)
+				subDiags := g.walk(ctx, walker, v)
 				diags = diags.Append(subDiags)
 				if subDiags.HasErrors() {
 					var errs []string
@@ -87,6 +87,11 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 	}
 
 	concreteResource := func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
 		return &nodeExpandApplyableResource{
 			NodeAbstractResource: a,
 		}
@@ -200,6 +205,9 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 		// Close opened plugin connections
 		&CloseProviderTransformer{},
 
+		// Close the ephemeral resources before their providers
+		&ephemeralResourceCloseTransformer{},
+
 		// close the root module
 		&CloseRootModuleTransformer{
 			RootConfig: b.Config,
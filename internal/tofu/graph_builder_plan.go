@@ -248,6 +248,9 @@ func (b *PlanGraphBuilder) Steps() []GraphTransformer {
 		// Close opened plugin connections
 		&CloseProviderTransformer{},
 
+		// Close the ephemeral resources before their providers
+		&ephemeralResourceCloseTransformer{},
+
 		// Close the root module
 		&CloseRootModuleTransformer{
 			RootConfig: b.Config,
@@ -269,6 +272,11 @@ func (b *PlanGraphBuilder) initPlan() {
 	}
 
 	b.ConcreteResource = func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
 		return &nodeExpandPlannableResource{
 			NodeAbstractResource: a,
 			skipRefresh:          b.skipRefresh,
@@ -339,6 +347,11 @@ func (b *PlanGraphBuilder) initImport() {
 	}
 
 	b.ConcreteResource = func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
 		return &nodeExpandPlannableResource{
 			NodeAbstractResource: a,
 
@@ -0,0 +1,224 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+)
+
+// GraphSummaryNodeKind is the kind of object that a node of a GraphSummary
+// represents.
+type GraphSummaryNodeKind string
+
+const (
+	GraphSummaryResource GraphSummaryNodeKind = "resources"
+	GraphSummaryData     GraphSummaryNodeKind = "data"
+	GraphSummaryProvider GraphSummaryNodeKind = "providers"
+
+	// GraphSummaryModule nodes represent all of the objects in a module
+	// that was collapsed, including its descendent modules.
+	GraphSummaryModule GraphSummaryNodeKind = "modules"
+)
+
+// GraphSummaryOpts are the options for SummarizeGraph.
+type GraphSummaryOpts struct {
+	// Include are the kinds of objects to include in the summary. All of
+	// resources, data resources and providers are included if it is empty.
+	Include []GraphSummaryNodeKind
+
+	// CollapseModules is the number of levels of modules to show the objects
+	// of. The objects of deeper modules are represented by a single node for
+	// their ancestor at that level. Modules are never collapsed if it is
+	// negative.
+	CollapseModules int
+}
+
+// GraphSummaryNode is a node of a GraphSummary.
+type GraphSummaryNode struct {
+	// Label is the address of the object, which is also unique within the
+	// summary.
+	Label string
+	Kind  GraphSummaryNodeKind
+}
+
+// GraphSummary is a simplified representation of an OpenTofu graph, meant to
+// be readable by people, that only contains nodes for the objects declared in
+// the configuration. An edge from one node to another means that the first
+// depends on the second, either directly or through nodes of the original
+// graph that are not in the summary.
+type GraphSummary struct {
+	// Nodes are ordered by their labels.
+	Nodes []*GraphSummaryNode
+
+	// Edges are the pairs of labels of the depending and the dependency
+	// nodes, in order.
+	Edges [][2]string
+}
+
+// SummarizeGraph returns the summary of the given graph.
+func SummarizeGraph(g *Graph, opts GraphSummaryOpts) *GraphSummary {
+	include := opts.Include
+	if len(include) == 0 {
+		include = []GraphSummaryNodeKind{GraphSummaryResource, GraphSummaryData, GraphSummaryProvider}
+	}
+	included := make(map[GraphSummaryNodeKind]bool, len(include))
+	for _, kind := range include {
+		included[kind] = true
+	}
+
+	nodes := make(map[string]*GraphSummaryNode)
+	summarized := make(map[dag.Vertex]*GraphSummaryNode)
+	for _, v := range g.Vertices() {
+		kind, label, module, ok := graphSummaryObject(v)
+		if !ok || !included[kind] {
+			continue
+		}
+		if opts.CollapseModules >= 0 && len(module) > opts.CollapseModules {
+			kind = GraphSummaryModule
+			label = module[:opts.CollapseModules+1].String()
+		}
+		node, exists := nodes[label]
+		if !exists {
+			node = &GraphSummaryNode{Label: label, Kind: kind}
+			nodes[label] = node
+		}
+		summarized[v] = node
+	}
+
+	ret := &GraphSummary{}
+	for _, node := range nodes {
+		ret.Nodes = append(ret.Nodes, node)
+	}
+	sort.Slice(ret.Nodes, func(i, j int) bool {
+		return ret.Nodes[i].Label < ret.Nodes[j].Label
+	})
+
+	// Each summarized vertex depends on the summarized vertices that it can
+	// reach without passing through another summarized vertex.
+	edges := make(map[[2]string]struct{})
+	for v, from := range summarized {
+		seen := make(map[dag.Vertex]struct{})
+		queue := g.DownEdges(v).List()
+		for len(queue) > 0 {
+			dep := queue[0]
+			queue = queue[1:]
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+			seen[dep] = struct{}{}
+
+			if to, ok := summarized[dep]; ok {
+				if to != from {
+					edges[[2]string{from.Label, to.Label}] = struct{}{}
+				}
+				continue
+			}
+			queue = append(queue, g.DownEdges(dep).List()...)
+		}
+	}
+	for edge := range edges {
+		ret.Edges = append(ret.Edges, edge)
+	}
+	sort.Slice(ret.Edges, func(i, j int) bool {
+		if ret.Edges[i][0] != ret.Edges[j][0] {
+			return ret.Edges[i][0] < ret.Edges[j][0]
+		}
+		return ret.Edges[i][1] < ret.Edges[j][1]
+	})
+
+	return ret
+}
+
+// graphSummaryObject returns the kind, the address and the module of the
+// object that the given vertex represents in a GraphSummary, or false if the
+// vertex doesn't represent such an object.
+func graphSummaryObject(v dag.Vertex) (GraphSummaryNodeKind, string, addrs.Module, bool) {
+	switch v := v.(type) {
+	case GraphNodeDestroyer, GraphNodeCloseProvider, *graphNodeProxyProvider:
+		// These are additional steps for the objects of other vertices.
+		return "", "", nil, false
+	case GraphNodeConfigResource:
+		addr := v.ResourceAddr()
+		kind := GraphSummaryResource
+		if addr.Resource.Mode == addrs.DataResourceMode {
+			kind = GraphSummaryData
+		}
+		return kind, addr.String(), addr.Module, true
+	case GraphNodeProvider:
+		addr := v.ProviderAddr()
+		return GraphSummaryProvider, addr.String(), addr.Module, true
+	default:
+		return "", "", nil, false
+	}
+}
+
+// Dot returns the summary in the DOT language.
+func (s *GraphSummary) Dot() string {
+	var buf strings.Builder
+	buf.WriteString("digraph {\n")
+	buf.WriteString("\tcompound = \"true\"\n")
+	buf.WriteString("\tnewrank = \"true\"\n")
+	buf.WriteString("\tsubgraph \"root\" {\n")
+	for _, node := range s.Nodes {
+		shape := "box"
+		switch node.Kind {
+		case GraphSummaryProvider:
+			shape = "diamond"
+		case GraphSummaryModule:
+			shape = "component"
+		}
+		fmt.Fprintf(&buf, "\t\t%s [label = %s, shape = %q]\n", graphSummaryDotID(node.Label), graphSummaryDotString(node.Label), shape)
+	}
+	for _, edge := range s.Edges {
+		fmt.Fprintf(&buf, "\t\t%s -> %s\n", graphSummaryDotID(edge[0]), graphSummaryDotID(edge[1]))
+	}
+	buf.WriteString("\t}\n")
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func graphSummaryDotID(label string) string {
+	return graphSummaryDotString("[root] " + label)
+}
+
+func graphSummaryDotString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Mermaid returns the summary as a Mermaid flowchart.
+func (s *GraphSummary) Mermaid() string {
+	ids := make(map[string]string, len(s.Nodes))
+
+	var buf strings.Builder
+	buf.WriteString("flowchart LR\n")
+	for i, node := range s.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.Label] = id
+
+		// Mermaid doesn't support escaping quotes with backslashes, but
+		// it does support HTML entity codes.
+		label := `"` + strings.ReplaceAll(node.Label, `"`, "#quot;") + `"`
+		switch node.Kind {
+		case GraphSummaryData:
+			fmt.Fprintf(&buf, "\t%s([%s])\n", id, label)
+		case GraphSummaryProvider:
+			fmt.Fprintf(&buf, "\t%s{%s}\n", id, label)
+		case GraphSummaryModule:
+			fmt.Fprintf(&buf, "\t%s[[%s]]\n", id, label)
+		default:
+			fmt.Fprintf(&buf, "\t%s[%s]\n", id, label)
+		}
+	}
+	for _, edge := range s.Edges {
+		fmt.Fprintf(&buf, "\t%s --> %s\n", ids[edge[0]], ids[edge[1]])
+	}
+	return buf.String()
+}
@@ -0,0 +1,195 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestSummarizeGraph(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "name" {
+  default = "a"
+}
+
+resource "test_object" "a" {
+  test_string = var.name
+}
+
+data "test_object" "b" {
+  test_string = test_object.a.test_string
+}
+
+module "child" {
+  source = "./child"
+  input  = data.test_object.b.test_string
+}
+`,
+		"child/main.tf": `
+variable "input" {}
+
+resource "test_object" "c" {
+  test_string = var.input
+}
+
+module "grandchild" {
+  source = "./grandchild"
+  input  = test_object.c.test_string
+}
+`,
+		"child/grandchild/main.tf": `
+variable "input" {}
+
+resource "test_object" "d" {
+  test_string = var.input
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+	g, diags := ctx.PlanGraphForUI(m, states.NewState(), plans.NormalMode)
+	assertNoErrors(t, diags)
+
+	// The plan graph is transitively reduced, so only test_object.a
+	// depends on the provider directly.
+	const provider = `provider["registry.opentofu.org/hashicorp/test"]`
+
+	tests := map[string]struct {
+		opts      GraphSummaryOpts
+		wantNodes []string
+		wantEdges [][2]string
+	}{
+		"all": {
+			opts: GraphSummaryOpts{CollapseModules: -1},
+			wantNodes: []string{
+				"data.test_object.b",
+				"module.child.module.grandchild.test_object.d",
+				"module.child.test_object.c",
+				provider,
+				"test_object.a",
+			},
+			wantEdges: [][2]string{
+				{"data.test_object.b", "test_object.a"},
+				{"module.child.module.grandchild.test_object.d", "module.child.test_object.c"},
+				{"module.child.test_object.c", "data.test_object.b"},
+				{"test_object.a", provider},
+			},
+		},
+		"collapse all modules": {
+			opts: GraphSummaryOpts{CollapseModules: 0},
+			wantNodes: []string{
+				"data.test_object.b",
+				"module.child",
+				provider,
+				"test_object.a",
+			},
+			wantEdges: [][2]string{
+				{"data.test_object.b", "test_object.a"},
+				{"module.child", "data.test_object.b"},
+				{"test_object.a", provider},
+			},
+		},
+		"collapse nested modules, resources only": {
+			opts: GraphSummaryOpts{
+				CollapseModules: 1,
+				Include:         []GraphSummaryNodeKind{GraphSummaryResource},
+			},
+			wantNodes: []string{
+				"module.child.module.grandchild",
+				"module.child.test_object.c",
+				"test_object.a",
+			},
+			wantEdges: [][2]string{
+				{"module.child.module.grandchild", "module.child.test_object.c"},
+				// The dependency on the data resource, which is not
+				// included, is replaced by its own dependencies.
+				{"module.child.test_object.c", "test_object.a"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			summary := SummarizeGraph(g, test.opts)
+
+			var gotNodes []string
+			for _, node := range summary.Nodes {
+				gotNodes = append(gotNodes, node.Label)
+			}
+			if diff := cmp.Diff(test.wantNodes, gotNodes); diff != "" {
+				t.Errorf("wrong nodes\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantEdges, summary.Edges); diff != "" {
+				t.Errorf("wrong edges\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGraphSummary_formats(t *testing.T) {
+	summary := &GraphSummary{
+		Nodes: []*GraphSummaryNode{
+			{Label: "data.test_object.b", Kind: GraphSummaryData},
+			{Label: "module.child", Kind: GraphSummaryModule},
+			{Label: `provider["registry.opentofu.org/hashicorp/test"]`, Kind: GraphSummaryProvider},
+			{Label: "test_object.a", Kind: GraphSummaryResource},
+		},
+		Edges: [][2]string{
+			{"data.test_object.b", "test_object.a"},
+			{"module.child", "data.test_object.b"},
+			{"test_object.a", `provider["registry.opentofu.org/hashicorp/test"]`},
+		},
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		want := strings.TrimSpace(`
+digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] data.test_object.b" [label = "data.test_object.b", shape = "box"]
+		"[root] module.child" [label = "module.child", shape = "component"]
+		"[root] provider[\"registry.opentofu.org/hashicorp/test\"]" [label = "provider[\"registry.opentofu.org/hashicorp/test\"]", shape = "diamond"]
+		"[root] test_object.a" [label = "test_object.a", shape = "box"]
+		"[root] data.test_object.b" -> "[root] test_object.a"
+		"[root] module.child" -> "[root] data.test_object.b"
+		"[root] test_object.a" -> "[root] provider[\"registry.opentofu.org/hashicorp/test\"]"
+	}
+}`)
+		if diff := cmp.Diff(want, strings.TrimSpace(summary.Dot())); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		want := strings.TrimSpace(`
+flowchart LR
+	n0(["data.test_object.b"])
+	n1[["module.child"]]
+	n2{"provider[#quot;registry.opentofu.org/hashicorp/test#quot;]"}
+	n3["test_object.a"]
+	n0 --> n3
+	n1 --> n0
+	n3 --> n2`)
+		if diff := cmp.Diff(want, strings.TrimSpace(summary.Mermaid())); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -39,6 +40,7 @@ type ContextGraphWalker struct {
 	Checks                  *checks.State           // Used for safe concurrent writes of checkable objects and their check results
 	InstanceExpander        *instances.Expander     // Tracks our gradual expansion of module and resource instances
 	ImportResolver          *ImportResolver         // Tracks import targets as they are being resolved
+	EphemeralResources      *EphemeralResources     // Tracks the open instances of ephemeral resources
 	MoveResults             refactoring.MoveResults // Read-only record of earlier processing of move statements
 	Operation               walkOperation
 	StopContext             context.Context
@@ -59,6 +61,9 @@ type ContextGraphWalker struct {
 	variableValuesLock sync.Mutex
 	variableValues     map[string]map[string]cty.Value
 
+	prioritiesLock sync.Mutex
+	priorities     map[dag.Vertex]int
+
 	providerLock  sync.Mutex
 	providerCache map[string]map[addrs.InstanceKey]providers.Interface
 
@@ -97,6 +102,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValues:     w.variableValues,
 		VariableValuesLock: &w.variableValuesLock,
 		PlanTimestamp:      w.PlanTimestamp,
+		EphemeralResources: w.EphemeralResources,
 	}
 
 	ctx := &BuiltinEvalContext{
@@ -107,6 +113,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		Plugins:                 w.Context.plugins,
 		MoveResultsValue:        w.MoveResults,
 		ImportResolverValue:     w.ImportResolver,
+		EphemeralResourcesValue: w.EphemeralResources,
 		ProviderCache:           w.providerCache,
 		ProviderInputConfig:     w.Context.providerInputConfig,
 		ProviderLock:            &w.providerLock,
@@ -142,9 +149,41 @@ func (w *ContextGraphWalker) init() {
 }
 
 func (w *ContextGraphWalker) Execute(ctx EvalContext, n GraphNodeExecutable) tfdiags.Diagnostics {
-	// Acquire a lock on the semaphore
-	w.Context.parallelSem.Acquire()
-	defer w.Context.parallelSem.Release()
+	// Wait for one of the parallelism slots. The scheduler uses the module
+	// and the critical path length to decide which waiting node goes next.
+	var module string
+	if mp, ok := n.(GraphNodeModulePath); ok {
+		module = mp.ModulePath().String()
+	}
+	w.Context.scheduler.Acquire(module, w.priority(n))
+	defer w.Context.scheduler.Release(module)
 
 	return n.Execute(ctx, w.Operation)
 }
+
+// prioritize records the critical path lengths of the vertices of the given
+// graph, for use by the critical-path schedule policy. The vertices of a
+// dynamic subgraph are on the critical path of the vertex that expanded them,
+// so their lengths are offset by the length recorded for parent.
+func (w *ContextGraphWalker) prioritize(g *Graph, parent dag.Vertex) {
+	if w.Context == nil || w.Context.scheduler.policy != ScheduleCriticalPath {
+		return
+	}
+	lengths := criticalPathLengths(g)
+
+	w.prioritiesLock.Lock()
+	defer w.prioritiesLock.Unlock()
+	if w.priorities == nil {
+		w.priorities = make(map[dag.Vertex]int)
+	}
+	base := w.priorities[parent]
+	for v, l := range lengths {
+		w.priorities[v] = base + l
+	}
+}
+
+func (w *ContextGraphWalker) priority(v dag.Vertex) int {
+	w.prioritiesLock.Lock()
+	defer w.prioritiesLock.Unlock()
+	return w.priorities[v]
+}
@@ -41,6 +41,14 @@ type Hook interface {
 	PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (HookAction, error)
 	PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (HookAction, error)
 
+	// PreProviderCall and PostProviderCall are called before and after each
+	// call that is made to a provider between PreApply and PostApply, with
+	// the name of the provider RPC, such as "ApplyResourceChange". This
+	// allows attributing the time taken by a slow apply to a specific call.
+	// They cannot control whether OpenTofu continues.
+	PreProviderCall(addr addrs.AbsResourceInstance, method string)
+	PostProviderCall(addr addrs.AbsResourceInstance, method string)
+
 	// PreDiff and PostDiff are called before and after a provider is given
 	// the opportunity to customize the proposed new state to produce the
 	// planned new state.
@@ -133,6 +141,12 @@ func (*NilHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation,
 	return HookActionContinue, nil
 }
 
+func (*NilHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
+func (*NilHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
 func (*NilHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (HookAction, error) {
 	return HookActionContinue, nil
 }
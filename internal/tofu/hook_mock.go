@@ -39,6 +39,10 @@ type MockHook struct {
 	PostApplyReturnError error
 	PostApplyFn          func(addrs.AbsResourceInstance, states.Generation, cty.Value, error) (HookAction, error)
 
+	// ProviderCalls records the PreProviderCall and PostProviderCall calls,
+	// as "pre METHOD" and "post METHOD" respectively.
+	ProviderCalls []string
+
 	PreDiffCalled        bool
 	PreDiffAddr          addrs.AbsResourceInstance
 	PreDiffGen           states.Generation
@@ -181,6 +185,20 @@ func (h *MockHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generati
 	return h.PostApplyReturn, h.PostApplyReturnError
 }
 
+func (h *MockHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.ProviderCalls = append(h.ProviderCalls, "pre "+method)
+}
+
+func (h *MockHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.ProviderCalls = append(h.ProviderCalls, "post "+method)
+}
+
 func (h *MockHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
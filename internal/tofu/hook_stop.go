@@ -33,6 +33,12 @@ func (h *stopHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generati
 	return h.hook()
 }
 
+func (h *stopHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
+func (h *stopHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
 func (h *stopHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (HookAction, error) {
 	return h.hook()
 }
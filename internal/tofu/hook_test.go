@@ -53,6 +53,14 @@ func (h *testHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generati
 	return HookActionContinue, nil
 }
 
+// The provider call hooks are not logged, so that the logged calls only
+// describe the operations on each instance.
+func (h *testHook) PreProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
+func (h *testHook) PostProviderCall(addr addrs.AbsResourceInstance, method string) {
+}
+
 func (h *testHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -0,0 +1,88 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// checkFrozenModules returns an error for each module instance in the given
+// state whose recorded configuration hash differs from the hash of its
+// current configuration, unless the module instance is covered by one of the
+// given unfrozen addresses.
+//
+// Module instances without a recorded hash, and module instances that are no
+// longer part of the configuration, are not checked.
+func checkFrozenModules(config *configs.Config, prevRunState *states.State, unfrozen []addrs.ModuleInstance) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// The modules are checked in address order so that the diagnostics are
+	// reported in a stable order.
+	modules := make([]*states.Module, 0, len(prevRunState.Modules))
+	for _, ms := range prevRunState.Modules {
+		modules = append(modules, ms)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Addr.Less(modules[j].Addr)
+	})
+
+	for _, ms := range modules {
+		if ms.Addr.IsRoot() || ms.ConfigHash == "" {
+			continue
+		}
+		modCfg := config.DescendentForInstance(ms.Addr)
+		if modCfg == nil || modCfg.Module == nil || modCfg.Module.ContentHash == "" {
+			continue
+		}
+		if modCfg.Module.ContentHash == ms.ConfigHash || moduleUnfrozen(ms.Addr, unfrozen) {
+			continue
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Frozen module has changed",
+			Detail: fmt.Sprintf(
+				"The source code of %s has changed since it was last applied, and modules are frozen for this run.\n\nIf the change is expected, review it and then run again with -unfreeze=%s to accept it.",
+				ms.Addr, ms.Addr,
+			),
+			Subject: modCfg.CallRange.Ptr(),
+		})
+	}
+	return diags
+}
+
+func moduleUnfrozen(addr addrs.ModuleInstance, unfrozen []addrs.ModuleInstance) bool {
+	for _, u := range unfrozen {
+		if u.TargetContains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordModuleConfigHashes stores the content hash of the current
+// configuration of each module instance in the given state, so that later
+// runs can detect changes to the module source with checkFrozenModules.
+func recordModuleConfigHashes(config *configs.Config, state *states.State) {
+	for _, ms := range state.Modules {
+		if ms.Addr.IsRoot() {
+			continue
+		}
+		modCfg := config.DescendentForInstance(ms.Addr)
+		if modCfg == nil || modCfg.Module == nil || modCfg.Module.ContentHash == "" {
+			continue
+		}
+		ms.ConfigHash = modCfg.Module.ContentHash
+	}
+}
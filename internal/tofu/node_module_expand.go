@@ -8,9 +8,13 @@ package tofu
 import (
 	"log"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -54,7 +58,7 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 
 	refs = append(refs, n.DependsOn()...)
 
-	// Expansion only uses the count and for_each expressions, so this
+	// Expansion only uses the count, for_each and enabled expressions, so this
 	// particular graph node only refers to those.
 	// Individual variable values in the module call definition might also
 	// refer to other objects, but that's handled by
@@ -73,6 +77,10 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 		forEachRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, n.ModuleCall.ForEach)
 		refs = append(refs, forEachRefs...)
 	}
+	if n.ModuleCall.Enabled != nil {
+		enabledRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, n.ModuleCall.Enabled)
+		refs = append(refs, enabledRefs...)
+	}
 
 	for _, passed := range n.ModuleCall.Providers {
 		if passed.InParent.KeyExpression != nil {
@@ -81,6 +89,15 @@ func (n *nodeExpandModule) References() []*addrs.Reference {
 		}
 	}
 
+	// The preconditions are checked before anything in the module is
+	// evaluated, so the objects they refer to must be ready first.
+	for _, rule := range n.ModuleCall.Preconditions {
+		condRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, rule.Condition)
+		refs = append(refs, condRefs...)
+		condRefs, _ = lang.ReferencesInExpr(addrs.ParseRef, rule.ErrorMessage)
+		refs = append(refs, condRefs...)
+	}
+
 	return refs
 }
 
@@ -138,13 +155,133 @@ func (n *nodeExpandModule) Execute(ctx EvalContext, op walkOperation) (diags tfd
 			}
 			expander.SetModuleForEach(module, call, forEach)
 
+		case n.ModuleCall.Enabled != nil:
+			enabled, enabledDiags := evaluateEnabledExpression(n.ModuleCall.Enabled, ctx, module)
+			diags = diags.Append(enabledDiags)
+			if diags.HasErrors() {
+				return diags
+			}
+			expander.SetModuleEnabled(module, call, enabled)
+
 		default:
 			expander.SetModuleSingle(module, call)
 		}
 	}
 
+	return diags.Append(checkModuleCallConditions(ctx, op, n.Addr, n.ModuleCall, n.ModuleCall.Preconditions, false))
+}
+
+// checkModuleCallConditions evaluates the given preconditions or
+// postconditions of a module call for each of its instances, in the scope of
+// the calling module. If withSelf is set then "self" refers to the object
+// representing the module call instance's output values.
+//
+// The conditions are not checked when destroying, because the module's
+// objects are being removed regardless of its inputs and outputs.
+func checkModuleCallConditions(ctx EvalContext, op walkOperation, addr addrs.Module, call *configs.ModuleCall, rules []*configs.CheckRule, withSelf bool) tfdiags.Diagnostics {
+	if len(rules) == 0 {
+		return nil
+	}
+	switch op {
+	case walkValidate, walkPlan, walkApply:
+	default:
+		return nil
+	}
+
+	var diags tfdiags.Diagnostics
+	summary := "Module precondition failed"
+	if withSelf {
+		summary = "Module postcondition failed"
+	}
+	expander := ctx.InstanceExpander()
+	for _, module := range expander.ExpandModule(addr) {
+		parent, callInst := module.CallInstance()
+
+		var keyData instances.RepetitionData
+		switch {
+		case op != walkValidate:
+			keyData = expander.GetModuleInstanceRepetitionData(module)
+		case call.ForEach != nil:
+			// Every module call has a single instance during validation, so
+			// we use placeholders for the values that aren't known yet.
+			keyData = instances.RepetitionData{
+				EachKey:   cty.UnknownVal(cty.String),
+				EachValue: cty.DynamicVal,
+			}
+			callInst.Key = addrs.StringKey("")
+		case call.Count != nil:
+			keyData = instances.RepetitionData{
+				CountIndex: cty.UnknownVal(cty.Number),
+			}
+			callInst.Key = addrs.IntKey(0)
+		}
+
+		var self addrs.Referenceable
+		if withSelf {
+			self = callInst
+		}
+		scope := ctx.WithPath(parent).EvaluationScope(self, nil, keyData)
+		diags = diags.Append(evalUntrackedConditions(rules, scope, summary))
+	}
 	return diags
+}
 
+// nodeModulePostconditions checks the postconditions of a module call after
+// all of the objects in the module have been evaluated. Anything that refers
+// to the module call waits for this node, so a failed postcondition prevents
+// the module's output values from being used.
+type nodeModulePostconditions struct {
+	Addr       addrs.Module
+	ModuleCall *configs.ModuleCall
+}
+
+var (
+	_ GraphNodeExecutable    = (*nodeModulePostconditions)(nil)
+	_ GraphNodeReferencer    = (*nodeModulePostconditions)(nil)
+	_ GraphNodeReferenceable = (*nodeModulePostconditions)(nil)
+)
+
+func (n *nodeModulePostconditions) Name() string {
+	return n.Addr.String() + " (postconditions)"
+}
+
+// GraphNodeModulePath implementation, which returns the calling module
+// because that is where the postconditions are declared.
+func (n *nodeModulePostconditions) ModulePath() addrs.Module {
+	return n.Addr.Parent()
+}
+
+// GraphNodeReferenceable implementation
+func (n *nodeModulePostconditions) ReferenceableAddrs() []addrs.Referenceable {
+	_, call := n.Addr.Call()
+	return []addrs.Referenceable{call}
+}
+
+// GraphNodeReferencer implementation
+func (n *nodeModulePostconditions) References() []*addrs.Reference {
+	var refs []*addrs.Reference
+	_, call := n.Addr.Call()
+	for _, rule := range n.ModuleCall.Postconditions {
+		for _, expr := range []hcl.Expression{rule.Condition, rule.ErrorMessage} {
+			exprRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+			for _, ref := range exprRefs {
+				if ref.Subject == addrs.Self {
+					// "self" is the module call instance, whose output values
+					// are all ready once the module call is.
+					synthRef := *ref // shallow copy
+					synthRef.Subject = call
+					ref = &synthRef
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// GraphNodeExecutable
+func (n *nodeModulePostconditions) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	return checkModuleCallConditions(ctx, op, n.Addr, n.ModuleCall, n.ModuleCall.Postconditions, true)
 }
 
 // nodeCloseModule represents an expanded module during apply, and is visited
@@ -270,6 +407,9 @@ func (n *nodeValidateModule) Execute(ctx EvalContext, op walkOperation) (diags t
 			const tupleNotAllowed = false
 			_, forEachDiags := evaluateForEachExpressionValue(n.ModuleCall.ForEach, ctx, unknownsAllowed, tupleNotAllowed, module)
 			diags = diags.Append(forEachDiags)
+
+		case n.ModuleCall.Enabled != nil:
+			diags = diags.Append(validateEnabled(ctx, n.ModuleCall.Enabled))
 		}
 
 		diags = diags.Append(validateDependsOn(ctx, n.ModuleCall.DependsOn))
@@ -278,5 +418,5 @@ func (n *nodeValidateModule) Execute(ctx EvalContext, op walkOperation) (diags t
 		expander.SetModuleSingle(module, call)
 	}
 
-	return diags
+	return diags.Append(checkModuleCallConditions(ctx, op, n.Addr, n.ModuleCall, n.ModuleCall.Preconditions, false))
 }
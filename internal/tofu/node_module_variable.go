@@ -17,6 +17,7 @@ import (
 	"github.com/opentofu/opentofu/internal/dag"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -220,6 +221,19 @@ func (n *nodeModuleVariable) evalModuleVariable(ctx EvalContext, validateOnly bo
 		}
 		givenVal = val
 		errSourceRange = tfdiags.SourceRangeFromHCL(expr.Range())
+
+		if !n.Config.Ephemeral && marks.Contains(val, marks.Ephemeral) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Ephemeral value not allowed",
+				Detail: fmt.Sprintf(
+					"The value for %s refers to ephemeral values, or values derived from ephemeral values, but the variable is not declared as ephemeral. To accept ephemeral values, the module must declare the variable with ephemeral = true.",
+					n.Addr,
+				),
+				Subject: expr.Range().Ptr(),
+			})
+			return cty.DynamicVal, diags.ErrWithWarnings()
+		}
 	} else {
 		// We'll use cty.NilVal to represent the variable not being set at all.
 		givenVal = cty.NilVal
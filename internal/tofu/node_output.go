@@ -282,9 +282,11 @@ func referencesForOutput(c *configs.Output) []*addrs.Reference {
 
 	impRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, c.Expr)
 	expRefs, _ := lang.References(addrs.ParseRef, c.DependsOn)
+	overrideRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, c.OverrideExpr)
 
 	refs = append(refs, impRefs...)
 	refs = append(refs, expRefs...)
+	refs = append(refs, overrideRefs...)
 
 	for _, check := range c.Preconditions {
 		condRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, check.Condition)
@@ -353,6 +355,14 @@ func (n *NodeApplyableOutput) Execute(ctx EvalContext, op walkOperation) (diags
 			val, evalDiags = ctx.EvaluateExpr(n.Config.Expr, cty.DynamicPseudoType, nil)
 			diags = diags.Append(evalDiags)
 
+		// If the module is being replaced with a stub, we evaluate the
+		// expression from the stub instead, which can refer only to the
+		// input variables of the module
+		case n.Config.OverrideExpr != nil:
+			var evalDiags tfdiags.Diagnostics
+			val, evalDiags = ctx.EvaluateExpr(n.Config.OverrideExpr, cty.DynamicPseudoType, nil)
+			diags = diags.Append(evalDiags)
+
 		// If the module is being overridden and we have a value to use,
 		// we just use it
 		case n.Config.OverrideValue != nil:
@@ -385,6 +395,29 @@ If you do intend to export this data, annotate the output value as sensitive by
 					Subject: n.Config.DeclRange.Ptr(),
 				})
 			}
+			if n.Config.Ephemeral {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Ephemeral output not allowed",
+					Detail:   "Root module output values can't be ephemeral, because OpenTofu saves their values in the state. Only the outputs of child modules can be declared as ephemeral.",
+					Subject:  n.Config.DeclRange.Ptr(),
+				})
+			}
+		}
+
+		// An output value must also be statically declared as ephemeral in
+		// order to return an ephemeral result, so that the calling module
+		// knows to not save it in the state or plan.
+		if !n.Config.Ephemeral && marks.Contains(val, marks.Ephemeral) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Output refers to ephemeral values",
+				Detail: `The value of this output refers to ephemeral values, or values derived from ephemeral values, which OpenTofu must not save in the state or plan.
+
+If this is a child module output, you can annotate it as ephemeral by adding the following argument:
+    ephemeral = true`,
+				Subject: n.Config.DeclRange.Ptr(),
+			})
 		}
 	}
 
@@ -625,5 +658,9 @@ func (n *NodeApplyableOutput) setValue(state *states.SyncState, changes *plans.C
 		val = cty.UnknownAsNull(val).WithMarks(valMarks)
 	}
 
+	if n.Config.Internal {
+		state.SetInternalOutputValue(n.Addr, val, n.Config.Sensitive)
+		return
+	}
 	state.SetOutputValue(n.Addr, val, n.Config.Sensitive)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
@@ -30,6 +31,10 @@ var (
 func (n *NodeApplyableProvider) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
 	instances, diags := n.initInstances(ctx, op)
 
+	if op == walkValidate && n.Config != nil {
+		diags = diags.Append(validateDependsOn(ctx, n.Config.DependsOn))
+	}
+
 	for key, provider := range instances {
 		diags = diags.Append(n.executeInstance(ctx, op, key, provider))
 	}
@@ -80,18 +85,42 @@ func (n *NodeApplyableProvider) initInstances(ctx EvalContext, op walkOperation)
 	return instances, diags
 }
 func (n *NodeApplyableProvider) executeInstance(ctx EvalContext, op walkOperation, providerKey addrs.InstanceKey, provider providers.Interface) tfdiags.Diagnostics {
+	diags := n.checkPreconditions(ctx, providerKey)
+	if diags.HasErrors() {
+		return diags
+	}
+
 	switch op {
 	case walkValidate:
 		log.Printf("[TRACE] NodeApplyableProvider: validating configuration for %s", n.Addr)
-		return n.ValidateProvider(ctx, providerKey, provider)
+		return diags.Append(n.ValidateProvider(ctx, providerKey, provider))
 	case walkPlan, walkPlanDestroy, walkApply, walkDestroy:
 		log.Printf("[TRACE] NodeApplyableProvider: configuring %s", n.Addr)
-		return n.ConfigureProvider(ctx, providerKey, provider, false)
+		return diags.Append(n.ConfigureProvider(ctx, providerKey, provider, false))
 	case walkImport:
 		log.Printf("[TRACE] NodeApplyableProvider: configuring %s (requiring that configuration is wholly known)", n.Addr)
-		return n.ConfigureProvider(ctx, providerKey, provider, true)
+		return diags.Append(n.ConfigureProvider(ctx, providerKey, provider, true))
+	}
+	return diags
+}
+
+// checkPreconditions evaluates the preconditions from the lifecycle block of
+// the provider configuration for the given instance.
+//
+// Conditions with an unknown result are skipped, on the assumption that they
+// will be checked again once more values are known.
+func (n *NodeApplyableProvider) checkPreconditions(ctx EvalContext, providerKey addrs.InstanceKey) tfdiags.Diagnostics {
+	if n.Config == nil || len(n.Config.Preconditions) == 0 {
+		return nil
 	}
-	return nil
+
+	data := EvalDataForNoInstanceKey
+	if n.Config.Instances != nil {
+		data = n.Config.Instances[providerKey]
+	}
+	scope := ctx.EvaluationScope(nil, nil, data)
+
+	return evalUntrackedConditions(n.Config.Preconditions, scope, "Provider precondition failed")
 }
 
 func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, providerKey addrs.InstanceKey, provider providers.Interface) tfdiags.Diagnostics {
@@ -130,6 +159,10 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, providerKey ad
 	}
 	diags = diags.Append(evalDiags)
 
+	// Providers may log their configuration, so we make sure that any
+	// sensitive values in it are masked in the logs before we send it.
+	logging.RegisterSensitiveValues(configSchema.SensitiveStrings(configVal)...)
+
 	// If our config value contains any marked values, ensure those are
 	// stripped out before sending this to the provider
 	unmarkedConfigVal, _ := configVal.UnmarkDeep()
@@ -170,6 +203,11 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, providerKey a
 		return diags
 	}
 
+	// Providers may log their configuration, and the requests that include
+	// it are logged in gRPC trace logs, so we make sure that any sensitive
+	// values in it are masked in the logs before we send it.
+	logging.RegisterSensitiveValues(configSchema.SensitiveStrings(configVal)...)
+
 	if verifyConfigIsKnown && !configVal.IsWhollyKnown() {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -6,9 +6,12 @@
 package tofu
 
 import (
+	"log"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang"
 
 	"github.com/opentofu/opentofu/internal/dag"
 )
@@ -57,11 +60,46 @@ func (n *NodeAbstractProvider) ModulePath() addrs.Module {
 
 // GraphNodeReferencer
 func (n *NodeAbstractProvider) References() []*addrs.Reference {
-	if n.Config == nil || n.Schema == nil {
+	if n.Config == nil {
+		return nil
+	}
+
+	refs := n.DependsOn()
+	if n.Schema != nil {
+		refs = append(refs, ReferencesFromConfig(n.Config.Config, n.Schema)...)
+	}
+	for _, check := range n.Config.Preconditions {
+		condRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, check.Condition)
+		refs = append(refs, condRefs...)
+		condRefs, _ = lang.ReferencesInExpr(addrs.ParseRef, check.ErrorMessage)
+		refs = append(refs, condRefs...)
+	}
+	return refs
+}
+
+// DependsOn returns the references from the depends_on argument of the
+// provider configuration, which the provider must wait for before it can be
+// configured.
+func (n *NodeAbstractProvider) DependsOn() []*addrs.Reference {
+	if n.Config == nil {
 		return nil
 	}
 
-	return ReferencesFromConfig(n.Config.Config, n.Schema)
+	var refs []*addrs.Reference
+	for _, traversal := range n.Config.DependsOn {
+		ref, diags := addrs.ParseRef(traversal)
+		if diags.HasErrors() {
+			// We ignore this here, because this isn't a suitable place to return
+			// errors. This situation should be caught and rejected during
+			// validation.
+			log.Printf("[ERROR] Can't parse %#v from depends_on as reference: %s", traversal, diags.Err())
+			continue
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs
 }
 
 // GraphNodeProvider
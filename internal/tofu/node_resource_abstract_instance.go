@@ -138,7 +138,7 @@ func (n *NodeAbstractResourceInstance) resolveProvider(ctx EvalContext, hasExpan
 				}
 			}
 			if validExpansion {
-				n.ResolvedProviderKey, diags = resolveProviderResourceInstance(ctx, n.Config.ProviderConfigRef.KeyExpression, n.Addr)
+				n.ResolvedProviderKey, diags = resolveProviderResourceInstance(ctx, n.Config.ProviderConfigRef.KeyExpression, n.ResolvedProvider.KeyType, n.Addr)
 			} else {
 				useStateFallback = true
 			}
@@ -162,7 +162,7 @@ func (n *NodeAbstractResourceInstance) resolveProvider(ctx EvalContext, hasExpan
 			}
 			if validExpansion {
 				// We can use the standard resolver
-				n.ResolvedProviderKey, diags = resolveProviderModuleInstance(ctx, n.ResolvedProvider.KeyExpression, moduleInstanceForKey, n.Addr.String())
+				n.ResolvedProviderKey, diags = resolveProviderModuleInstance(ctx, n.ResolvedProvider.KeyExpression, n.ResolvedProvider.KeyType, moduleInstanceForKey, n.Addr.String())
 			} else {
 				useStateFallback = true
 			}
@@ -214,14 +214,16 @@ func (n *NodeAbstractResourceInstance) resolveProvider(ctx EvalContext, hasExpan
 	if deposedKey == states.NotDeposed {
 		if n.ResolvedProviderKey != nil {
 			// We're associated with an for_each instance key that isn't declared anymore.
-			diags = diags.Append(tfdiags.Sourceless(
+			// The plan consolidates these into a single error per provider instance,
+			// see consolidateProviderInstanceRemovedDiags.
+			diags = diags.Append(withProviderInstanceRemoved(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Provider instance not present",
 				fmt.Sprintf(
 					"To work with %s its original provider instance at %s is required, but it has been removed. This occurs when an element is removed from the provider configuration's for_each collection while objects created by that the associated provider instance still exist in the state. Re-add the for_each element to destroy %s, after which you can remove the provider configuration again.\n\nThis is commonly caused by using the same for_each collection both for a resource (or its containing module) and its associated provider configuration. To successfully remove an instance of a resource it must be possible to remove the corresponding element from the resource's for_each collection while retaining the corresponding element in the provider's for_each collection.",
 					n.Addr, n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), n.Addr,
 				),
-			))
+			), n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr, deposedKey))
 		} else {
 			// We're associated with the no-key instance of a provider configuration, which
 			// suggests that someone is in the process of adopting provider for_each for
@@ -246,14 +248,14 @@ func (n *NodeAbstractResourceInstance) resolveProvider(ctx EvalContext, hasExpan
 			// using the same for_each for the resource and the provider, since deposed
 			// objects are caused by a failed create_before_destroy (a kind of "replace")
 			// rather than by entirely removing an instance.
-			diags = diags.Append(tfdiags.Sourceless(
+			diags = diags.Append(withProviderInstanceRemoved(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Provider instance not present",
 				fmt.Sprintf(
 					"To work with %s's deposed object %s its original provider instance at %s is required, but it has been removed. This occurs when an element is removed from the provider configuration's for_each collection while objects created by that the associated provider instance still exist in the state. Re-add the for_each element to destroy this deposed object for %s, after which you can remove the provider configuration again.",
 					n.Addr, deposedKey, n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), n.Addr,
 				),
-			))
+			), n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr, deposedKey))
 		} else {
 			// We're associated with the no-key instance of a provider configuration, which
 			// suggests that someone is in the process of adopting provider for_each for
@@ -408,6 +410,23 @@ func (n *NodeAbstractResourceInstance) postApplyHook(ctx EvalContext, state *sta
 	return diags
 }
 
+// startProviderCall calls the PreProviderCall hook for the given provider
+// RPC, returning a function that calls the PostProviderCall hook, which must
+// be called once the provider has responded.
+func (n *NodeAbstractResourceInstance) startProviderCall(ctx EvalContext, method string) func() {
+	// These hooks cannot halt the operation, so ctx.Hook never fails.
+	_ = ctx.Hook(func(h Hook) (HookAction, error) {
+		h.PreProviderCall(n.Addr, method)
+		return HookActionContinue, nil
+	})
+	return func() {
+		_ = ctx.Hook(func(h Hook) (HookAction, error) {
+			h.PostProviderCall(n.Addr, method)
+			return HookActionContinue, nil
+		})
+	}
+}
+
 type phaseState int
 
 const (
@@ -806,6 +825,9 @@ func (n *NodeAbstractResourceInstance) refresh(ctx EvalContext, deposedKey state
 		log.Printf("[WARN] Provider %q produced an invalid new value containing null blocks for %q during refresh\n", n.ResolvedProvider.ProviderConfig.Provider, n.Addr)
 	}
 
+	// Reformatting of document attributes by the remote system isn't drift.
+	newState = n.normalizeDocumentAttrs(priorVal, newState)
+
 	ret := state.DeepCopy()
 	ret.Value = newState
 	ret.Private = resp.Private
@@ -921,6 +943,10 @@ func (n *NodeAbstractResourceInstance) plan(
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateResourceConfigNotEphemeral(origConfigVal, &config))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	metaConfigVal, metaDiags := n.providerMetas(ctx)
 	diags = diags.Append(metaDiags)
@@ -991,6 +1017,9 @@ func (n *NodeAbstractResourceInstance) plan(
 	unmarkedPriorVal, _ := priorVal.UnmarkDeepWithPaths()
 
 	proposedNewVal := objchange.ProposedNew(schema, unmarkedPriorVal, unmarkedConfigVal)
+	if plannedChange != nil && unmarkedPriorVal.IsNull() {
+		proposedNewVal = proposePlannedComputedValues(schema, proposedNewVal, plannedChange.After)
+	}
 
 	// Call pre-diff hook
 	diags = diags.Append(ctx.Hook(func(h Hook) (HookAction, error) {
@@ -1095,6 +1124,11 @@ func (n *NodeAbstractResourceInstance) plan(
 		}
 	}
 
+	// A document attribute whose formatting changed without changing its
+	// meaning keeps the value from the prior state, so that it isn't
+	// reported as a change.
+	plannedNewVal = n.normalizeDocumentAttrs(unmarkedPriorVal, plannedNewVal)
+
 	// Add the marks back to the planned new value -- this must happen after ignore changes
 	// have been processed
 	marks := combinePathValueMarks(unmarkedPaths, schema.ValueMarks(plannedNewVal, nil))
@@ -1240,6 +1274,9 @@ func (n *NodeAbstractResourceInstance) plan(
 
 		// create a new proposed value from the null state and the config
 		proposedNewVal = objchange.ProposedNew(schema, nullPriorVal, unmarkedConfigVal)
+		if plannedChange != nil {
+			proposedNewVal = proposePlannedComputedValues(schema, proposedNewVal, plannedChange.After)
+		}
 
 		resp = provider.PlanResourceChange(providers.PlanResourceChangeRequest{
 			TypeName:         n.Addr.Resource.Resource.Type,
@@ -1364,6 +1401,29 @@ func (n *NodeAbstractResourceInstance) plan(
 	return plan, state, keyData, diags
 }
 
+// proposePlannedComputedValues returns the proposed new value for an object
+// that is created while applying, with the values that the provider planned
+// for its computed attributes during the plan phase, if they were known. The
+// provider must plan the same values again during the apply phase, so this
+// lets a provider keep values it generated while planning, without relying on
+// anything that the plugin protocol doesn't send to PlanResourceChange.
+func proposePlannedComputedValues(schema *configschema.Block, proposed, planned cty.Value) cty.Value {
+	if proposed.IsNull() || !proposed.IsKnown() || planned.IsNull() || !planned.IsKnown() {
+		return proposed
+	}
+	planned, _ = planned.UnmarkDeep()
+
+	attrs := proposed.AsValueMap()
+	for name, attr := range schema.Attributes {
+		if !attr.Computed || attr.Optional || !attrs[name].IsNull() {
+			continue
+		}
+		if val := planned.GetAttr(name); !val.IsNull() && val.IsWhollyKnown() {
+			attrs[name] = val
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
 func (n *NodeAbstractResource) processIgnoreChanges(prior, config cty.Value, schema *configschema.Block) (cty.Value, tfdiags.Diagnostics) {
 	// ignore_changes only applies when an object already exists, since we
 	// can't ignore changes to a thing we've not created yet.
@@ -1660,18 +1720,26 @@ func (n *NodeAbstractResourceInstance) readDataSource(ctx EvalContext, configVal
 		return newVal, diags
 	}
 
+	callCtx, callDone := providerCallContext(ctx)
+	defer callDone()
 	req := providers.ReadDataSourceRequest{
 		TypeName:     n.Addr.ContainingResource().Resource.Type,
 		Config:       configVal,
 		ProviderMeta: metaConfigVal,
+		Context:      callCtx,
 	}
 	var resp providers.ReadDataSourceResponse
+	providerCallDone := n.startProviderCall(ctx, "ReadDataSource")
 	if tfp, ok := provider.(ProviderWithEncryption); ok {
 		// Special case for terraform_remote_state
 		resp = tfp.ReadDataSourceEncrypted(req, n.Addr, ctx.GetEncryption())
 	} else {
 		resp = provider.ReadDataSource(req)
 	}
+	providerCallDone()
+	if cancelDiags := providerCallCancelled(callCtx, n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), "read "+n.Addr.String()); cancelDiags.HasErrors() {
+		return newVal, diags.Append(cancelDiags)
+	}
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
 		return newVal, diags
@@ -1816,6 +1884,10 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx EvalContext, checkRule
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateResourceConfigNotEphemeral(configVal, &config))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	check, nested := n.nestedInCheckBlock()
 	if nested {
@@ -2343,6 +2415,9 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx EvalContext, state
 			Config:     unmarkedConfig,
 			Connection: unmarkedConnInfo,
 			UIOutput:   &output,
+			// Template rendering only needs the functions of the scope, so
+			// it doesn't matter which object the scope is evaluated for.
+			TemplateRenderer: ctx.EvaluationScope(nil, nil, EvalDataForNoInstanceKey),
 		})
 		applyDiags := resp.Diagnostics.InConfigBody(prov.Config, n.Addr.String())
 
@@ -2512,6 +2587,7 @@ func (n *NodeAbstractResourceInstance) apply(
 		return newState, diags
 	}
 
+	providerCallDone := n.startProviderCall(ctx, "ApplyResourceChange")
 	resp := provider.ApplyResourceChange(providers.ApplyResourceChangeRequest{
 		TypeName:       n.Addr.Resource.Resource.Type,
 		PriorState:     unmarkedBefore,
@@ -2520,6 +2596,7 @@ func (n *NodeAbstractResourceInstance) apply(
 		PlannedPrivate: change.Private,
 		ProviderMeta:   metaConfigVal,
 	})
+	providerCallDone()
 
 	applyDiags := resp.Diagnostics
 	if applyConfig != nil {
@@ -2624,6 +2701,13 @@ func (n *NodeAbstractResourceInstance) apply(
 		newVal = cty.UnknownAsNull(newVal)
 	}
 
+	if change.Action != plans.Delete {
+		// The plan may have kept the prior formatting of a document
+		// attribute, in which case the provider is likely to return the
+		// formatting from the configuration instead.
+		newVal = n.normalizeDocumentAttrs(change.After, newVal)
+	}
+
 	if change.Action != plans.Delete && !diags.HasErrors() {
 		// Only values that were marked as unknown in the planned value are allowed
 		// to change during the apply operation. (We do this after the unknown-ness
@@ -0,0 +1,117 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/objchange"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// shouldAdoptExisting returns true if the resource instance has
+// lifecycle.adopt_existing set and has no object in the state, in which case
+// the plan imports the existing object, if there is one, instead of planning
+// to create a new one.
+func (n *NodePlannableResourceInstance) shouldAdoptExisting(ctx EvalContext) bool {
+	if n.skipPlanChanges || n.Config == nil || n.Config.Managed == nil || !n.Config.Managed.AdoptExisting {
+		return false
+	}
+	return ctx.State().ResourceInstance(n.ResourceInstanceAddr()) == nil
+}
+
+// adoptID returns the ID to import the existing object of a resource instance
+// with lifecycle.adopt_existing set by, when there's no import block for it.
+// That's the id that the provider plans for the new object, which it must
+// know before the object is created. If it doesn't, the returned ID is empty
+// and the diagnostics warn that adoption was skipped. OpenTofu doesn't retry
+// a failed create as an import during apply, so in that case the apply fails
+// if the object already exists.
+func (n *NodePlannableResourceInstance) adoptID(ctx EvalContext, provider providers.Interface, providerSchema providers.ProviderSchema) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	skipAdoption := func(reason string) tfdiags.Diagnostics {
+		return diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Adoption of existing object skipped",
+			fmt.Sprintf("%s has lifecycle.adopt_existing set, but adoption was skipped because %s. OpenTofu cannot check whether the object already exists, so it will plan to create it, and the apply will fail if the object does exist. To adopt an existing object, add an import block with its ID for this resource instance.", n.Addr, reason),
+		))
+	}
+
+	schema, _ := providerSchema.SchemaForResourceAddr(n.Addr.Resource.Resource)
+	if schema == nil {
+		// Should be caught during validation, so we don't bother with a pretty error here
+		return "", diags.Append(fmt.Errorf("provider does not support resource type %q", n.Addr.Resource.Resource.Type))
+	}
+	if attr := schema.Attributes["id"]; attr == nil || !attr.Type.Equals(cty.String) {
+		return "", skipAdoption(fmt.Sprintf("resource type %q has no id attribute to import the object by", n.Addr.Resource.Resource.Type))
+	}
+
+	forEach, _ := evaluateForEachExpression(n.Config.ForEach, ctx, n.Addr)
+	keyData := EvalDataForInstanceKey(n.ResourceInstanceAddr().Resource.Key, forEach)
+	configVal, _, configDiags := ctx.EvaluateBlock(n.Config.Config, schema, nil, keyData)
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		return "", diags
+	}
+	metaConfigVal, metaDiags := n.providerMetas(ctx)
+	diags = diags.Append(metaDiags)
+	if metaDiags.HasErrors() {
+		return "", diags
+	}
+
+	unmarkedConfigVal, _ := configVal.UnmarkDeep()
+	priorVal := cty.NullVal(schema.ImpliedType())
+	resp := provider.PlanResourceChange(providers.PlanResourceChangeRequest{
+		TypeName:         n.Addr.Resource.Resource.Type,
+		Config:           unmarkedConfigVal,
+		PriorState:       priorVal,
+		ProposedNewState: objchange.ProposedNew(schema, priorVal, unmarkedConfigVal),
+		ProviderMeta:     metaConfigVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
+	if resp.Diagnostics.HasErrors() {
+		return "", diags
+	}
+
+	id := resp.PlannedState.GetAttr("id")
+	if !id.IsKnown() || id.IsNull() {
+		return "", skipAdoption("its id is not known until the object is created")
+	}
+	return id.AsString(), diags
+}
+
+// checkAdoptedChange returns an error if the change planned for an adopted
+// object would replace it, because OpenTofu must not destroy an object that
+// it didn't create and that was not in the state.
+func (n *NodePlannableResourceInstance) checkAdoptedChange(change *plans.ResourceInstanceChange) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !change.Action.IsReplace() {
+		return diags
+	}
+
+	log.Printf("[TRACE] checkAdoptedChange: %s would replace the adopted object with ID %q", n.Addr, change.Importing.ID)
+	detail := fmt.Sprintf("%s has lifecycle.adopt_existing set and an object with ID %q already exists, but it differs from the configuration in arguments that cannot be updated in-place", n.Addr, change.Importing.ID)
+	if len(change.RequiredReplace.List()) > 0 {
+		detail += ":"
+		for _, path := range change.RequiredReplace.List() {
+			detail += "\n  - " + tfdiags.FormatCtyPath(path)
+		}
+	} else {
+		detail += "."
+	}
+	detail += "\n\nOpenTofu will not replace an object that it did not create. Change the configuration to match the existing object, or remove the object before applying."
+	return diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Cannot adopt existing object",
+		detail,
+	))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"log"
+
+	ctyyaml "github.com/zclconf/go-cty-yaml"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// documentFormat is the format of a string attribute that holds a structured
+// document, as declared by lifecycle.json_attributes or
+// lifecycle.yaml_attributes.
+type documentFormat int
+
+const (
+	documentJSON documentFormat = iota
+	documentYAML
+)
+
+type documentAttr struct {
+	Path   cty.Path
+	Format documentFormat
+}
+
+// documentAttrs returns the paths of the attributes that the resource
+// configuration declares as holding JSON or YAML documents.
+func (n *NodeAbstractResource) documentAttrs() []documentAttr {
+	if n.Config == nil || n.Config.Managed == nil {
+		return nil
+	}
+	var ret []documentAttr
+	for _, path := range traversalsToPaths(n.Config.Managed.JSONAttributes) {
+		ret = append(ret, documentAttr{Path: path, Format: documentJSON})
+	}
+	for _, path := range traversalsToPaths(n.Config.Managed.YAMLAttributes) {
+		ret = append(ret, documentAttr{Path: path, Format: documentYAML})
+	}
+	return ret
+}
+
+// normalizeDocumentAttrs returns a copy of val where each of the attributes
+// declared in lifecycle.json_attributes or lifecycle.yaml_attributes is
+// replaced by the corresponding attribute of ref whenever both hold documents
+// with the same meaning, even if they differ in whitespace, key order or
+// other details of the formatting.
+//
+// This allows OpenTofu to suppress the spurious differences that would
+// otherwise appear when a provider or remote API reformats such documents,
+// regardless of whether the provider implements its own diff suppression.
+func (n *NodeAbstractResource) normalizeDocumentAttrs(ref, val cty.Value) cty.Value {
+	attrs := n.documentAttrs()
+	if len(attrs) == 0 || ref.IsNull() || val.IsNull() || !ref.IsKnown() || !val.IsKnown() {
+		return val
+	}
+
+	unmarkedRef, _ := ref.UnmarkDeep()
+	unmarkedVal, valMarks := val.UnmarkDeepWithPaths()
+
+	ret := unmarkedVal
+	for _, attr := range attrs {
+		refAttr, err := attr.Path.Apply(unmarkedRef)
+		if err != nil {
+			continue
+		}
+		valAttr, err := attr.Path.Apply(ret)
+		if err != nil {
+			continue
+		}
+		if !documentsEqual(attr.Format, refAttr, valAttr) {
+			continue
+		}
+		log.Printf("[TRACE] normalizeDocumentAttrs: %s%#v is semantically unchanged", n.Addr, attr.Path)
+		ret, _ = cty.Transform(ret, func(path cty.Path, v cty.Value) (cty.Value, error) {
+			if path.Equals(attr.Path) {
+				return refAttr, nil
+			}
+			return v, nil
+		})
+	}
+
+	if len(valMarks) > 0 {
+		ret = ret.MarkWithPaths(valMarks)
+	}
+	return ret
+}
+
+// documentsEqual returns true if a and b are both known strings that
+// differ from each other but hold documents with the same meaning in the
+// given format.
+func documentsEqual(format documentFormat, a, b cty.Value) bool {
+	if a.IsNull() || b.IsNull() || !a.IsKnown() || !b.IsKnown() {
+		return false
+	}
+	if !a.Type().Equals(cty.String) || !b.Type().Equals(cty.String) {
+		return false
+	}
+	if a.RawEquals(b) {
+		return false
+	}
+
+	aDoc, err := decodeDocument(format, a.AsString())
+	if err != nil {
+		return false
+	}
+	bDoc, err := decodeDocument(format, b.AsString())
+	if err != nil {
+		return false
+	}
+	if !aDoc.Type().Equals(bDoc.Type()) {
+		return false
+	}
+	eq := aDoc.Equals(bDoc)
+	return eq.IsKnown() && eq.True()
+}
+
+func decodeDocument(format documentFormat, src string) (cty.Value, error) {
+	buf := []byte(src)
+	switch format {
+	case documentYAML:
+		ty, err := ctyyaml.ImpliedType(buf)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return ctyyaml.Unmarshal(buf, ty)
+	default:
+		ty, err := ctyjson.ImpliedType(buf)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return ctyjson.Unmarshal(buf, ty)
+	}
+}
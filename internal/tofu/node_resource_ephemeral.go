@@ -0,0 +1,328 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// nodeExpandEphemeralResource represents an ephemeral resource, and
+// implements DynamicExpand to a subgraph containing a node that opens each of
+// its instances.
+//
+// Ephemeral resources are opened again in each graph walk that needs them,
+// and nothing about them is saved in the plan or the state. Each one has a
+// corresponding nodeCloseEphemeralResource that closes its instances once
+// everything that depends on them is complete.
+//
+// This node intentionally doesn't implement graphNodeExpandsInstances,
+// because ephemeral resources are typically used only by provider
+// configurations, which pruneUnusedNodesTransformer doesn't count as users.
+type nodeExpandEphemeralResource struct {
+	*NodeAbstractResource
+}
+
+var (
+	_ GraphNodeDynamicExpandable    = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeReferenceable        = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeReferencer           = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeConfigResource       = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeAttachResourceConfig = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeTargetable           = (*nodeExpandEphemeralResource)(nil)
+)
+
+func (n *nodeExpandEphemeralResource) Name() string {
+	return n.NodeAbstractResource.Name() + " (expand)"
+}
+
+func (n *nodeExpandEphemeralResource) References() []*addrs.Reference {
+	return filterSelfRefs(n.Addr.Resource, n.NodeAbstractResource.References())
+}
+
+func (n *nodeExpandEphemeralResource) DynamicExpand(ctx EvalContext) (*Graph, error) {
+	var g Graph
+	var diags tfdiags.Diagnostics
+
+	expander := ctx.InstanceExpander()
+	for _, module := range expander.ExpandModule(n.Addr.Module) {
+		resAddr := n.Addr.Resource.Absolute(module)
+		moreDiags := n.recordExpansion(ctx.WithPath(module), resAddr)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		for _, addr := range expander.ExpandResource(resAddr) {
+			a := NewNodeAbstractResourceInstance(addr)
+			a.Config = n.Config
+			a.ResolvedProvider = n.ResolvedProvider
+			a.Schema = n.Schema
+			a.dependsOn = n.dependsOn
+			g.Add(&nodeEphemeralResourceInstance{
+				NodeAbstractResourceInstance: a,
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags.ErrWithWarnings()
+	}
+
+	addRootNodeToGraph(&g)
+
+	return &g, diags.ErrWithWarnings()
+}
+
+// recordExpansion evaluates the count or for_each argument of the resource
+// and records the result in the expander. Unlike writeResourceState, it
+// doesn't add the resource to the state.
+func (n *nodeExpandEphemeralResource) recordExpansion(ctx EvalContext, addr addrs.AbsResource) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	expander := ctx.InstanceExpander()
+
+	switch {
+	case n.Config.Count != nil:
+		count, countDiags := evaluateCountExpression(n.Config.Count, ctx, addr)
+		diags = diags.Append(countDiags)
+		if countDiags.HasErrors() {
+			return diags
+		}
+		expander.SetResourceCount(addr.Module, n.Addr.Resource, count)
+
+	case n.Config.ForEach != nil:
+		forEach, forEachDiags := evaluateForEachExpression(n.Config.ForEach, ctx, addr)
+		diags = diags.Append(forEachDiags)
+		if forEachDiags.HasErrors() {
+			return diags
+		}
+		expander.SetResourceForEach(addr.Module, n.Addr.Resource, forEach)
+
+	default:
+		expander.SetResourceSingle(addr.Module, n.Addr.Resource)
+	}
+
+	return diags
+}
+
+// nodeEphemeralResourceInstance opens an instance of an ephemeral resource.
+type nodeEphemeralResourceInstance struct {
+	*NodeAbstractResourceInstance
+}
+
+var (
+	_ GraphNodeModuleInstance       = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeReferenceable        = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeReferencer           = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeConfigResource       = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeResourceInstance     = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeAttachResourceSchema = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeAttachResourceConfig = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeExecutable           = (*nodeEphemeralResourceInstance)(nil)
+)
+
+func (n *nodeEphemeralResourceInstance) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	addr := n.ResourceInstanceAddr()
+
+	diags := n.resolveProvider(ctx, true, states.NotDeposed)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey)
+	diags = diags.Append(err)
+	if diags.HasErrors() {
+		return diags
+	}
+	schema, _ := providerSchema.SchemaForResourceAddr(addr.ContainingResource().Resource)
+	if schema == nil {
+		// Should be caught during validation, so we don't bother with a pretty error here
+		return diags.Append(fmt.Errorf("provider %q does not support ephemeral resource %q", n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), addr.ContainingResource().Resource.Type))
+	}
+
+	config := *n.Config
+	forEach, _ := evaluateForEachExpression(config.ForEach, ctx, addr)
+	keyData := EvalDataForInstanceKey(addr.Resource.Key, forEach)
+
+	configVal, _, configDiags := ctx.EvaluateBlock(config.Config, schema, nil, keyData)
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		return diags
+	}
+
+	if !configVal.IsWhollyKnown() {
+		// This can only happen during plan, when the configuration refers
+		// to values that won't be known until apply.
+		log.Printf("[TRACE] nodeEphemeralResourceInstance: %s configuration isn't known yet, so its result is unknown", addr)
+		ctx.EphemeralResources().SetUnknown(addr, cty.UnknownVal(schema.ImpliedType()).Mark(marks.Ephemeral))
+		return diags
+	}
+
+	// Unmark before sending to provider, will re-mark the result
+	configVal, pvm := configVal.UnmarkDeepWithPaths()
+
+	validateResp := provider.ValidateEphemeralResourceConfig(providers.ValidateEphemeralResourceConfigRequest{
+		TypeName: config.Type,
+		Config:   configVal,
+	})
+	diags = diags.Append(validateResp.Diagnostics.InConfigBody(config.Config, addr.String()))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	log.Printf("[TRACE] nodeEphemeralResourceInstance: opening %s", addr)
+	resp := provider.OpenEphemeralResource(providers.OpenEphemeralResourceRequest{
+		TypeName: config.Type,
+		Config:   configVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, addr.String()))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	newVal := resp.Result
+	if newVal == cty.NilVal {
+		// This can happen with incompletely-configured mocks. We'll allow it
+		// and treat it as an alias for a properly-typed null value.
+		newVal = cty.NullVal(schema.ImpliedType())
+	}
+	for _, err := range newVal.Type().TestConformance(schema.ImpliedType()) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider produced invalid object",
+			fmt.Sprintf(
+				"Provider %q produced an invalid value for %s.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.",
+				n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), tfdiags.FormatErrorPrefixed(err, addr.String()),
+			),
+		))
+	}
+	if !newVal.IsWhollyKnown() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider produced invalid object",
+			fmt.Sprintf(
+				"Provider %q produced a value for %s that is not wholly known.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.",
+				n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), addr,
+			),
+		))
+	}
+	if diags.HasErrors() {
+		// The provider did open the instance, so we still record it to
+		// make sure it gets closed, but with a value that can't be used.
+		ctx.EphemeralResources().Open(addr, provider, config.Type, cty.DynamicVal.Mark(marks.Ephemeral), resp)
+		return diags
+	}
+
+	valMarks := pvm
+	if schema.ContainsSensitive() {
+		valMarks = combinePathValueMarks(valMarks, schema.ValueMarks(newVal, nil))
+	}
+	newVal = newVal.MarkWithPaths(valMarks).Mark(marks.Ephemeral)
+
+	ctx.EphemeralResources().Open(addr, provider, config.Type, newVal, resp)
+	return diags
+}
+
+// nodeCloseEphemeralResource closes all of the instances of an ephemeral
+// resource, after everything that depends on them is complete.
+type nodeCloseEphemeralResource struct {
+	Addr addrs.ConfigResource
+}
+
+var (
+	_ GraphNodeModulePath = (*nodeCloseEphemeralResource)(nil)
+	_ GraphNodeExecutable = (*nodeCloseEphemeralResource)(nil)
+)
+
+func (n *nodeCloseEphemeralResource) Name() string {
+	return n.Addr.String() + " (close)"
+}
+
+func (n *nodeCloseEphemeralResource) ModulePath() addrs.Module {
+	return n.Addr.Module
+}
+
+func (n *nodeCloseEphemeralResource) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	log.Printf("[TRACE] nodeCloseEphemeralResource: closing instances of %s", n.Addr)
+	return ctx.EphemeralResources().Close(n.Addr)
+}
+
+// ephemeralResourceCloseTransformer adds a nodeCloseEphemeralResource for
+// each ephemeral resource, which depends on everything that depends on the
+// ephemeral resource, and which the close node of the provider that opened
+// it depends on.
+//
+// This must run after CloseProviderTransformer.
+type ephemeralResourceCloseTransformer struct{}
+
+func (t *ephemeralResourceCloseTransformer) Transform(g *Graph) error {
+	var resources []*nodeExpandEphemeralResource
+	for _, v := range g.Vertices() {
+		if n, ok := v.(*nodeExpandEphemeralResource); ok {
+			resources = append(resources, n)
+		}
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	providerClosers := make(map[string]*graphNodeCloseProvider)
+	for _, v := range g.Vertices() {
+		if closer, ok := v.(*graphNodeCloseProvider); ok {
+			providerClosers[closer.Addr.String()] = closer
+		}
+	}
+
+	closers := make(map[*nodeExpandEphemeralResource]*nodeCloseEphemeralResource, len(resources))
+	for _, n := range resources {
+		closer := &nodeCloseEphemeralResource{Addr: n.Addr}
+		g.Add(closer)
+		g.Connect(dag.BasicEdge(closer, n))
+		closers[n] = closer
+	}
+
+	// We find all of the dependents before adding any more edges, so that
+	// the close node of an ephemeral resource depends on the close nodes of
+	// the ephemeral resources that refer to it, but not the other way around.
+	dependents := make(map[*nodeExpandEphemeralResource]dag.Set, len(resources))
+	for _, n := range resources {
+		des, err := g.Descendents(n)
+		if err != nil {
+			return err
+		}
+		dependents[n] = des
+	}
+
+	for _, n := range resources {
+		closer := closers[n]
+		for _, v := range dependents[n] {
+			switch v.(type) {
+			case *graphNodeCloseProvider:
+				// The provider that opened the ephemeral resource must stay
+				// open until it's closed, and that provider may itself be
+				// a dependent.
+				continue
+			}
+			if v != closer {
+				g.Connect(dag.BasicEdge(closer, v))
+			}
+		}
+
+		if providerCloser, ok := providerClosers[n.ResolvedProvider.ProviderConfig.String()]; ok {
+			g.Connect(dag.BasicEdge(providerCloser, closer))
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"log"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -202,10 +203,33 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 		return diags
 	}
 
+	// A resource with lifecycle.adopt_existing set imports the existing
+	// object, if there is one, rather than planning to create a new one. The
+	// ID to import comes from the import block for the resource instance, if
+	// there is one, or else from the planned id of the new object.
+	importID := n.importTarget.ID
+	adopting := n.shouldAdoptExisting(ctx)
+	if adopting && !importing {
+		var idDiags tfdiags.Diagnostics
+		importID, idDiags = n.adoptID(ctx, provider, providerSchema)
+		diags = diags.Append(idDiags)
+		if diags.HasErrors() {
+			return diags
+		}
+		importing = importID != ""
+	}
+
 	// If the resource is to be imported, we now ask the provider for an Import
 	// and a Refresh, and save the resulting state to instanceRefreshState.
 	if importing {
-		instanceRefreshState, diags = n.importState(ctx, addr, n.importTarget.ID, provider, providerSchema)
+		var importDiags tfdiags.Diagnostics
+		instanceRefreshState, importDiags = n.importState(ctx, addr, importID, adopting, provider, providerSchema)
+		diags = diags.Append(importDiags)
+		if instanceRefreshState == nil && !diags.HasErrors() {
+			// There's no existing object to adopt, so we plan to create it.
+			log.Printf("[TRACE] managedResourceExecute: no existing object with ID %q to adopt for %s", importID, n.Addr)
+			importing = false
+		}
 	} else {
 		var readDiags tfdiags.Diagnostics
 		instanceRefreshState, readDiags = n.readResourceInstanceState(ctx, addr)
@@ -264,6 +288,17 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 			return diags
 		}
 
+		// The drift policy only applies when planning changes, because
+		// adopting the remote changes is the purpose of refresh-only mode.
+		if !n.skipPlanChanges {
+			var driftDiags tfdiags.Diagnostics
+			s, driftDiags = n.applyDriftPolicy(instanceRefreshState, s)
+			diags = diags.Append(driftDiags)
+			if diags.HasErrors() {
+				return diags
+			}
+		}
+
 		instanceRefreshState = s
 
 		if instanceRefreshState != nil {
@@ -329,7 +364,13 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 		}
 
 		if importing {
-			change.Importing = &plans.Importing{ID: n.importTarget.ID}
+			change.Importing = &plans.Importing{ID: importID}
+			if adopting {
+				diags = diags.Append(n.checkAdoptedChange(change))
+				if diags.HasErrors() {
+					return diags
+				}
+			}
 		}
 
 		// FIXME: here we update the change to reflect the reason for
@@ -473,7 +514,10 @@ func (n *NodePlannableResourceInstance) replaceTriggered(ctx EvalContext, repDat
 	return diags
 }
 
-func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.AbsResourceInstance, importId string, provider providers.Interface, providerSchema providers.ProviderSchema) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
+// importState imports the object with the given ID and refreshes it. If
+// allowMissing is set, there may be no object with the given ID, in which case
+// the returned object is nil.
+func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.AbsResourceInstance, importId string, allowMissing bool, provider providers.Interface, providerSchema providers.ProviderSchema) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	absAddr := addr.Resource.Absolute(ctx.Path())
 
@@ -561,6 +605,9 @@ func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.
 
 	// verify the existence of the imported resource
 	if instanceRefreshState.Value.IsNull() {
+		if allowMissing {
+			return nil, diags
+		}
 		var diags tfdiags.Diagnostics
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -702,6 +749,89 @@ func (n *NodePlannableResourceInstance) generateHCLStringAttributes(addr addrs.A
 	return genconfig.GenerateResourceContents(addr, filteredSchema, providerAddr, state.Value)
 }
 
+// applyDriftPolicy compares the object as it was before refreshing with the
+// refreshed object and handles any differences according to the on_drift
+// lifecycle argument of the resource, returning the object to plan against.
+func (n *NodePlannableResourceInstance) applyDriftPolicy(prior, refreshed *states.ResourceInstanceObject) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if n.Config == nil || n.Config.Managed == nil || prior == nil || prior.Value.IsNull() {
+		return refreshed, diags
+	}
+	policy := n.Config.Managed.OnDrift
+	if policy != configs.DriftIgnore && policy != configs.DriftFail {
+		return refreshed, diags
+	}
+
+	if refreshed == nil || refreshed.Value.IsNull() {
+		// We can't ignore the deletion of the remote object, because there
+		// would be nothing left to manage, so only the fail policy has
+		// anything to do here.
+		if policy == configs.DriftFail {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Resource drift detected",
+				Detail:   fmt.Sprintf("The remote object for %s has been deleted outside of OpenTofu, and the resource's on_drift policy is \"fail\".", n.Addr),
+				Subject:  n.Config.DeclRange.Ptr(),
+			})
+		}
+		return refreshed, diags
+	}
+
+	changed := driftedAttributes(prior.Value, refreshed.Value)
+	if len(changed) == 0 {
+		return refreshed, diags
+	}
+
+	switch policy {
+	case configs.DriftFail:
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Resource drift detected",
+			Detail: fmt.Sprintf(
+				"The remote object for %s has been changed outside of OpenTofu, and the resource's on_drift policy is \"fail\".\n\nThe following attributes have changed: %s.\n\nReconcile the remote object with the configuration, or run \"tofu apply -refresh-only\" to accept the changes.",
+				n.Addr, strings.Join(changed, ", "),
+			),
+			Subject: n.Config.DeclRange.Ptr(),
+		})
+		return refreshed, diags
+	default: // configs.DriftIgnore
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Resource drift ignored",
+			Detail: fmt.Sprintf(
+				"The remote object for %s has been changed outside of OpenTofu, but the resource's on_drift policy is \"ignore\", so OpenTofu will not plan to revert these changes.\n\nThe following attributes have changed: %s.",
+				n.Addr, strings.Join(changed, ", "),
+			),
+			Subject: n.Config.DeclRange.Ptr(),
+		})
+		return prior, diags
+	}
+}
+
+// driftedAttributes returns the names of the top-level attributes of the
+// given object values that differ between them, in lexical order.
+func driftedAttributes(before, after cty.Value) []string {
+	before, _ = before.UnmarkDeep()
+	after, _ = after.UnmarkDeep()
+	if before.RawEquals(after) {
+		return nil
+	}
+	if !before.Type().IsObjectType() || !after.Type().IsObjectType() || !before.Type().Equals(after.Type()) {
+		// Should never happen for objects of the same resource type, but
+		// we'll still report that something changed.
+		return []string{"(all)"}
+	}
+
+	var ret []string
+	for name := range before.Type().AttributeTypes() {
+		if !before.GetAttr(name).RawEquals(after.GetAttr(name)) {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
 // mergeDeps returns the union of 2 sets of dependencies
 func mergeDeps(a, b []addrs.ConfigResource) []addrs.ConfigResource {
 	switch {
@@ -18,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/instances"
 	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -313,6 +314,9 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 		// Evaluate the for_each expression here so we can expose the diagnostics
 		forEachDiags := validateForEach(ctx, n.Config.ForEach)
 		diags = diags.Append(forEachDiags)
+
+	case n.Config.Enabled != nil:
+		diags = diags.Append(validateEnabled(ctx, n.Config.Enabled))
 	}
 
 	diags = diags.Append(validateDependsOn(ctx, n.Config.DependsOn))
@@ -385,6 +389,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateResourceConfigNotEphemeral(configVal, n.Config))
 
 		if n.Config.Managed != nil { // can be nil only in tests with poorly-configured mocks
 			for _, traversal := range n.Config.Managed.IgnoreChanges {
@@ -417,6 +422,33 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 					}
 				}
 			}
+
+			for _, argument := range []struct {
+				name       string
+				traversals []hcl.Traversal
+			}{
+				{"json_attributes", n.Config.Managed.JSONAttributes},
+				{"yaml_attributes", n.Config.Managed.YAMLAttributes},
+			} {
+				for _, traversal := range argument.traversals {
+					moreDiags := schema.StaticValidateTraversal(traversal)
+					diags = diags.Append(moreDiags)
+					if moreDiags.HasErrors() {
+						continue
+					}
+
+					path := traversalToPath(traversal)
+					attrSchema := schema.AttributeByPath(path)
+					if attrSchema == nil || !attrSchema.ImpliedType().Equals(cty.String) {
+						diags = diags.Append(&hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  fmt.Sprintf("Invalid %s element", argument.name),
+							Detail:   fmt.Sprintf("The %s argument can only refer to string attributes, but %s is not a string attribute.", argument.name, strings.TrimPrefix(tfdiags.FormatCtyPath(path), ".")),
+							Subject:  traversal.SourceRange().Ptr(),
+						})
+					}
+				}
+			}
 		}
 
 		// Use unmarked value for validate request
@@ -459,6 +491,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateResourceConfigNotEphemeral(configVal, n.Config))
 
 		// Use unmarked value for validate request
 		unmarkedConfigVal, _ := configVal.UnmarkDeep()
@@ -469,6 +502,46 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 
 		resp := provider.ValidateDataResourceConfig(req)
 		diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
+
+	case addrs.EphemeralResourceMode:
+		schema, _ := providerSchema.SchemaForResourceType(n.Config.Mode, n.Config.Type)
+		if schema == nil {
+			var suggestion string
+			if len(providerSchema.EphemeralResources) > 0 {
+				suggestions := make([]string, 0, len(providerSchema.EphemeralResources))
+				for name := range providerSchema.EphemeralResources {
+					suggestions = append(suggestions, name)
+				}
+				if suggestion = didyoumean.NameSuggestion(n.Config.Type, suggestions); suggestion != "" {
+					suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+				}
+			}
+
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid ephemeral resource",
+				Detail:   fmt.Sprintf("The provider %s does not support ephemeral resource %q.%s", n.Provider().ForDisplay(), n.Config.Type, suggestion),
+				Subject:  &n.Config.TypeRange,
+			})
+			return diags
+		}
+
+		configVal, _, valDiags := ctx.EvaluateBlock(n.Config.Config, schema, nil, keyData)
+		diags = diags.Append(valDiags)
+		if valDiags.HasErrors() {
+			return diags
+		}
+
+		// Ephemeral resources may refer to ephemeral values, since their
+		// configuration is never saved, so there's no need to check for them.
+		unmarkedConfigVal, _ := configVal.UnmarkDeep()
+		req := providers.ValidateEphemeralResourceConfigRequest{
+			TypeName: n.Config.Type,
+			Config:   unmarkedConfigVal,
+		}
+
+		resp := provider.ValidateEphemeralResourceConfig(req)
+		diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
 	}
 
 	return diags
@@ -564,6 +637,21 @@ func validateCount(ctx EvalContext, expr hcl.Expression) (diags tfdiags.Diagnost
 	return diags
 }
 
+func validateEnabled(ctx EvalContext, expr hcl.Expression) (diags tfdiags.Diagnostics) {
+	val, enabledDiags := evaluateEnabledExpressionValue(expr, ctx)
+	// If the value isn't known then that's the best we can do for now, but
+	// we'll check more thoroughly during the plan walk
+	if !val.IsKnown() {
+		return diags
+	}
+
+	if enabledDiags.HasErrors() {
+		diags = diags.Append(enabledDiags)
+	}
+
+	return diags
+}
+
 func validateForEach(ctx EvalContext, expr hcl.Expression) (diags tfdiags.Diagnostics) {
 	const unknownsAllowed = true
 	const tupleNotAllowed = false
@@ -582,6 +670,25 @@ func validateForEach(ctx EvalContext, expr hcl.Expression) (diags tfdiags.Diagno
 	return diags
 }
 
+// validateResourceConfigNotEphemeral returns an error if the given
+// configuration value of a resource contains ephemeral values, because
+// OpenTofu saves the configuration of resources in the plan and the state.
+func validateResourceConfigNotEphemeral(configVal cty.Value, config *configs.Resource) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if marks.Contains(configVal, marks.Ephemeral) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Ephemeral value not allowed",
+			Detail: fmt.Sprintf(
+				"The configuration of %s refers to ephemeral values, or values derived from ephemeral values. Ephemeral values can't be used in resource arguments, because OpenTofu saves them in the plan and the state.",
+				config.Addr(),
+			),
+			Subject: config.DeclRange.Ptr(),
+		})
+	}
+	return diags
+}
+
 func validateDependsOn(ctx EvalContext, dependsOn []hcl.Traversal) (diags tfdiags.Diagnostics) {
 	for _, traversal := range dependsOn {
 		ref, refDiags := addrs.ParseRef(traversal)
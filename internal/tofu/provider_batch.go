@@ -0,0 +1,146 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// batchingProvider wraps a configured provider instance that supports the
+// ApplyResourceChanges method. The apply walk applies the changes to resource
+// instances that don't depend on each other concurrently, up to the
+// configured parallelism. A change is sent to the provider as soon as no
+// other change to a resource of the same type is in flight, and the changes
+// that are requested while one is in flight are grouped and sent together in
+// one ApplyResourceChanges call once it has finished. No change ever waits
+// for others that might be requested later.
+type batchingProvider struct {
+	providers.Interface
+
+	name string
+
+	mu sync.Mutex
+	// inFlight records the resource types that have a call to the provider
+	// in progress.
+	inFlight map[string]bool
+	// pending are the batches waiting for the call in progress, by resource
+	// type.
+	pending map[string]*applyBatch
+}
+
+var _ providers.Interface = (*batchingProvider)(nil)
+
+// applyBatch is a group of changes to resources of the same type. ready is
+// closed when it's the batch's turn to be sent, and done is closed once the
+// provider has responded, after which resps has a response for each of the
+// requests.
+type applyBatch struct {
+	reqs  []providers.ApplyResourceChangeRequest
+	resps []providers.ApplyResourceChangeResponse
+	ready chan struct{}
+	done  chan struct{}
+}
+
+func newBatchingProvider(p providers.Interface, name string) *batchingProvider {
+	return &batchingProvider{
+		Interface: p,
+		name:      name,
+		inFlight:  make(map[string]bool),
+		pending:   make(map[string]*applyBatch),
+	}
+}
+
+func (p *batchingProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	p.mu.Lock()
+	if !p.inFlight[req.TypeName] {
+		p.inFlight[req.TypeName] = true
+		p.mu.Unlock()
+
+		resp := p.Interface.ApplyResourceChange(req)
+		p.next(req.TypeName)
+		return resp
+	}
+
+	batch, ok := p.pending[req.TypeName]
+	if !ok {
+		batch = &applyBatch{
+			ready: make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+		p.pending[req.TypeName] = batch
+	}
+	i := len(batch.reqs)
+	batch.reqs = append(batch.reqs, req)
+	p.mu.Unlock()
+
+	if i == 0 {
+		// The first change in the batch sends it for all of them.
+		<-batch.ready
+		p.send(req.TypeName, batch)
+	}
+	<-batch.done
+	return batch.resps[i]
+}
+
+// next starts sending the batch of changes that are waiting for the call for
+// the given resource type that has just finished, if there are any.
+func (p *batchingProvider) next(typeName string) {
+	p.mu.Lock()
+	batch, ok := p.pending[typeName]
+	delete(p.pending, typeName)
+	if !ok {
+		delete(p.inFlight, typeName)
+	}
+	p.mu.Unlock()
+
+	if ok {
+		close(batch.ready)
+	}
+}
+
+// send applies the changes of the given batch, which can no longer change
+// once it's ready.
+func (p *batchingProvider) send(typeName string, batch *applyBatch) {
+	defer p.next(typeName)
+	defer close(batch.done)
+
+	if len(batch.reqs) == 1 {
+		batch.resps = []providers.ApplyResourceChangeResponse{p.Interface.ApplyResourceChange(batch.reqs[0])}
+		return
+	}
+
+	log.Printf("[TRACE] batchingProvider: applying %d changes to %s resources of %s in one call", len(batch.reqs), typeName, p.name)
+	resp := p.Interface.ApplyResourceChanges(providers.ApplyResourceChangesRequest{
+		Changes: batch.reqs,
+	})
+	diags := resp.Diagnostics
+	if len(resp.Changes) != len(batch.reqs) && !diags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider produced invalid response",
+			fmt.Sprintf(
+				"Provider %q returned %d results after applying %d changes to %s resources, instead of one for each change.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.",
+				p.name, len(resp.Changes), len(batch.reqs), typeName,
+			),
+		))
+	}
+	batch.resps = make([]providers.ApplyResourceChangeResponse, len(batch.reqs))
+	for i := range batch.resps {
+		// If the call failed, or the provider didn't respond to each of the
+		// changes, there may be no response for the change, and so no new
+		// state. The caller keeps track of the prior state of the object in
+		// that case, and reports the error for each of the changes.
+		if i < len(resp.Changes) {
+			batch.resps[i] = resp.Changes[i]
+		}
+		batch.resps[i].Diagnostics = batch.resps[i].Diagnostics.Append(diags)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+func TestBatchingProvider(t *testing.T) {
+	p := simpleMockProvider()
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+	release := blockApply(p, "a")
+	wrapped := newBatchingProvider(p, "test")
+
+	// The change to "a" is sent on its own straight away, and the others are
+	// requested while it's in flight, so they are sent together once it has
+	// finished.
+	resps := applyWhileBlocked(t, wrapped, release, "a", "b", "c", "d")
+
+	if len(p.ApplyResourceChangesRequests) != 1 {
+		t.Fatalf("provider received %d batches; want 1", len(p.ApplyResourceChangesRequests))
+	}
+	var batched []string
+	for _, change := range p.ApplyResourceChangesRequests[0].Changes {
+		batched = append(batched, change.PlannedState.GetAttr("test_string").AsString())
+	}
+	sort.Strings(batched)
+	if got, want := strings.Join(batched, ","), "b,c,d"; got != want {
+		t.Fatalf("wrong batch %s; want %s", got, want)
+	}
+	for i, want := range []string{"a", "b", "c", "d"} {
+		resp := resps[i]
+		if resp.Diagnostics.HasErrors() {
+			t.Fatalf("unexpected errors for %q: %s", want, resp.Diagnostics.Err())
+		}
+		if got := resp.NewState.GetAttr("test_string"); !got.RawEquals(cty.StringVal(want)) {
+			t.Errorf("wrong new state for %q: %#v", want, got)
+		}
+	}
+
+	// A change that is requested while nothing is in flight is applied with
+	// ApplyResourceChange.
+	p.ApplyResourceChangeCalled = false
+	applyConcurrently(wrapped, "e")
+	if !p.ApplyResourceChangeCalled {
+		t.Fatal("ApplyResourceChange not called for a single change")
+	}
+	if len(p.ApplyResourceChangesRequests) != 1 {
+		t.Fatalf("provider received %d batches; want 1", len(p.ApplyResourceChangesRequests))
+	}
+}
+
+func TestBatchingProvider_error(t *testing.T) {
+	p := simpleMockProvider()
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+	release := blockApply(p, "a")
+	p.ApplyResourceChangesFn = func(req providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+		var resp providers.ApplyResourceChangesResponse
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("quota exceeded"))
+		return resp
+	}
+	wrapped := newBatchingProvider(p, "test")
+
+	for _, resp := range applyWhileBlocked(t, wrapped, release, "a", "b", "c")[1:] {
+		if !resp.Diagnostics.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := resp.Diagnostics.Err().Error(), "quota exceeded"; got != want {
+			t.Errorf("wrong error %q; want %q", got, want)
+		}
+		if resp.NewState != cty.NilVal {
+			t.Errorf("unexpected new state %#v", resp.NewState)
+		}
+	}
+}
+
+func TestBatchingProvider_missingResponses(t *testing.T) {
+	p := simpleMockProvider()
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+	release := blockApply(p, "a")
+	p.ApplyResourceChangesFn = func(req providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+		// Only the first change is applied, without reporting an error.
+		return providers.ApplyResourceChangesResponse{
+			Changes: []providers.ApplyResourceChangeResponse{
+				{NewState: req.Changes[0].PlannedState},
+			},
+		}
+	}
+	wrapped := newBatchingProvider(p, "test")
+
+	for _, resp := range applyWhileBlocked(t, wrapped, release, "a", "b", "c")[1:] {
+		if !resp.Diagnostics.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := resp.Diagnostics.Err().Error(), "returned 1 results after applying 2 changes"; !strings.Contains(got, want) {
+			t.Errorf("wrong error %q; want it to contain %q", got, want)
+		}
+	}
+}
+
+// blockApply makes ApplyResourceChange of the given provider block for the
+// change with the given value of test_string until the returned channel is
+// closed, and otherwise apply the planned state.
+func blockApply(p *MockProvider, val string) chan struct{} {
+	release := make(chan struct{})
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		if req.PlannedState.GetAttr("test_string").AsString() == val {
+			<-release
+		}
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+	return release
+}
+
+// applyWhileBlocked applies the change for the first of the given values,
+// which must be blocked until release is closed, and then the changes for
+// the others while it's in flight. It returns the responses in the same
+// order as the values.
+func applyWhileBlocked(t *testing.T, p *batchingProvider, release chan struct{}, vals ...string) []providers.ApplyResourceChangeResponse {
+	t.Helper()
+	resps := make([]providers.ApplyResourceChangeResponse, len(vals))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resps[0] = applyConcurrently(p, vals[0])[0]
+	}()
+	waitForBatchingProvider(p, func() bool {
+		return p.inFlight["test_object"]
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		copy(resps[1:], applyConcurrently(p, vals[1:]...))
+	}()
+	waitForBatchingProvider(p, func() bool {
+		batch := p.pending["test_object"]
+		return batch != nil && len(batch.reqs) == len(vals)-1
+	})
+
+	close(release)
+	wg.Wait()
+	return resps
+}
+
+// waitForBatchingProvider waits until the given condition on the state of
+// the given provider is true.
+func waitForBatchingProvider(p *batchingProvider, cond func() bool) {
+	for {
+		p.mu.Lock()
+		ok := cond()
+		p.mu.Unlock()
+		if ok {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// applyConcurrently applies changes to test_object resources with the given
+// values of test_string at the same time, and returns their responses in the
+// same order.
+func applyConcurrently(p providers.Interface, vals ...string) []providers.ApplyResourceChangeResponse {
+	ty := simpleTestSchema().ImpliedType()
+	resps := make([]providers.ApplyResourceChangeResponse, len(vals))
+	var wg sync.WaitGroup
+	for i, val := range vals {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attrs := make(map[string]cty.Value)
+			for name, attrTy := range ty.AttributeTypes() {
+				attrs[name] = cty.NullVal(attrTy)
+			}
+			attrs["test_string"] = cty.StringVal(val)
+			resps[i] = p.ApplyResourceChange(providers.ApplyResourceChangeRequest{
+				TypeName:     "test_object",
+				PriorState:   cty.NullVal(ty),
+				PlannedState: cty.ObjectVal(attrs),
+				Config:       cty.ObjectVal(attrs),
+			})
+		}()
+	}
+	wg.Wait()
+	return resps
+}
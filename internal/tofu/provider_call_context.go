@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// providerStopGracePeriod is how long a provider has to respond to a call
+// after the operation was interrupted before OpenTofu cancels the call.
+//
+// When interrupted, OpenTofu asks all providers to stop, but a provider that
+// is blocked in a call to a remote system may not notice. Without a deadline,
+// the user would then have to kill the provider processes by hand.
+//
+// This is a variable only so that tests can shorten it.
+var providerStopGracePeriod = 10 * time.Second
+
+// errProviderCallAbandoned is the cause of the cancellation of a context
+// returned by providerCallContext when the provider didn't respond within
+// providerStopGracePeriod of the operation being interrupted.
+var errProviderCallAbandoned = errors.New("provider didn't stop in time")
+
+// providerCallContext returns a context to include in a provider request,
+// which is canceled if the operation is interrupted and the provider then
+// doesn't respond within providerStopGracePeriod.
+//
+// The returned function must be called once the provider has responded, but
+// only after checking for cancellation with providerCallCancelled.
+func providerCallContext(ctx EvalContext) (context.Context, context.CancelFunc) {
+	callCtx, cancel := context.WithCancelCause(context.Background())
+	stopped := ctx.Stopped()
+
+	go func() {
+		select {
+		case <-stopped:
+		case <-callCtx.Done():
+			return
+		}
+
+		timer := time.NewTimer(providerStopGracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			log.Printf("[WARN] Provider didn't respond within %s of the operation being interrupted, so cancelling the call", providerStopGracePeriod)
+			cancel(errProviderCallAbandoned)
+		case <-callCtx.Done():
+		}
+	}()
+
+	return callCtx, func() { cancel(nil) }
+}
+
+// providerCallCancelled returns an error diagnostic if the given context,
+// returned by providerCallContext, was canceled because the provider didn't
+// stop in time, or no diagnostics otherwise.
+//
+// The action describes what the provider was asked to do, such as
+// "read data.example.foo", for use in the error message.
+func providerCallCancelled(callCtx context.Context, provider string, action string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !errors.Is(context.Cause(callCtx), errProviderCallAbandoned) {
+		return diags
+	}
+	return diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Operation cancelled by user",
+		fmt.Sprintf(
+			"OpenTofu was interrupted while waiting for provider %s to %s, and the provider didn't stop within %s, so OpenTofu cancelled the request.",
+			provider, action, providerStopGracePeriod,
+		),
+	))
+}
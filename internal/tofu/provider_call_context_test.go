@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProviderCallContext(t *testing.T) {
+	defer func(d time.Duration) { providerStopGracePeriod = d }(providerStopGracePeriod)
+	providerStopGracePeriod = 10 * time.Millisecond
+
+	t.Run("not stopped", func(t *testing.T) {
+		ctx := &MockEvalContext{StoppedValue: make(chan struct{})}
+		callCtx, done := providerCallContext(ctx)
+
+		time.Sleep(2 * providerStopGracePeriod)
+		if err := callCtx.Err(); err != nil {
+			t.Fatalf("context canceled without being stopped: %s", err)
+		}
+		if diags := providerCallCancelled(callCtx, "provider", "read"); len(diags) != 0 {
+			t.Fatalf("unexpected diagnostics: %s", diags.Err())
+		}
+
+		done()
+		if callCtx.Err() == nil {
+			t.Fatal("context not canceled after the call was done")
+		}
+		if diags := providerCallCancelled(callCtx, "provider", "read"); len(diags) != 0 {
+			t.Fatalf("unexpected diagnostics after the call was done: %s", diags.Err())
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		stopped := make(chan struct{})
+		ctx := &MockEvalContext{StoppedValue: stopped}
+		callCtx, done := providerCallContext(ctx)
+		defer done()
+
+		close(stopped)
+		select {
+		case <-callCtx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context not canceled after the grace period")
+		}
+
+		diags := providerCallCancelled(callCtx, `provider["registry.opentofu.org/hashicorp/test"]`, "read data.test_data_source.foo")
+		if !diags.HasErrors() {
+			t.Fatal("no error diagnostics for the cancelled call")
+		}
+		desc := diags[0].Description()
+		if got, want := desc.Summary, "Operation cancelled by user"; got != want {
+			t.Errorf("wrong summary\ngot:  %s\nwant: %s", got, want)
+		}
+		if !strings.Contains(desc.Detail, "read data.test_data_source.foo") {
+			t.Errorf("detail doesn't describe the call: %s", desc.Detail)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// maxCredentialsRefreshMargin is the longest time before the credentials of a
+// provider expire at which we configure the provider again. Providers with
+// short-lived credentials are re-configured after three quarters of the
+// lifetime of their credentials instead.
+const maxCredentialsRefreshMargin = 5 * time.Minute
+
+// credentialsRefreshingProvider wraps a configured provider instance that has
+// signalled when its credentials expire. Before each call that may use the
+// credentials, it configures the provider again with the original request if
+// the credentials are about to expire, so that long-running operations don't
+// fail part way through. The provider is only configured again once no other
+// calls are in flight, since providers don't expect to be configured while
+// they are using their configuration.
+type credentialsRefreshingProvider struct {
+	providers.Interface
+
+	name string
+	req  providers.ConfigureProviderRequest
+
+	// now returns the current time, and can be overridden in tests.
+	now func() time.Time
+
+	// mu is held for reading by each call that may use the credentials, and
+	// for writing while configuring the provider again.
+	mu sync.RWMutex
+	// refreshAt is when to configure the provider again, or the zero time if
+	// the provider stopped reporting that its credentials expire.
+	refreshAt time.Time
+}
+
+var _ providers.Interface = (*credentialsRefreshingProvider)(nil)
+
+func newCredentialsRefreshingProvider(p providers.Interface, name string, req providers.ConfigureProviderRequest, expiry time.Time) *credentialsRefreshingProvider {
+	// The context of the original request ends once that request is
+	// complete, so it mustn't be reused for the later ones.
+	req.Context = nil
+	ret := &credentialsRefreshingProvider{
+		Interface: p,
+		name:      name,
+		req:       req,
+		now:       time.Now,
+	}
+	ret.refreshAt = ret.refreshTime(expiry)
+	return ret
+}
+
+func (p *credentialsRefreshingProvider) refreshTime(expiry time.Time) time.Time {
+	margin := min(expiry.Sub(p.now())/4, maxCredentialsRefreshMargin)
+	return expiry.Add(-margin)
+}
+
+// beginCall configures the provider again if its credentials are about to
+// expire, and then holds mu for reading so that the provider isn't configured
+// again during the call. The caller must call p.mu.RUnlock once the call is
+// complete.
+//
+// Failures to configure the provider are only logged, because the current
+// credentials may still be valid for the call at hand, and we try again
+// before the next call.
+func (p *credentialsRefreshingProvider) beginCall() {
+	p.mu.RLock()
+	if !p.refreshDue() {
+		return
+	}
+	p.mu.RUnlock()
+
+	p.mu.Lock()
+	// Another call may have configured the provider while we were waiting
+	// for the calls in flight to complete.
+	if p.refreshDue() {
+		p.refreshCredentials()
+	}
+	p.mu.Unlock()
+	p.mu.RLock()
+}
+
+// refreshDue returns true if it's time to configure the provider again. The
+// caller must hold mu.
+func (p *credentialsRefreshingProvider) refreshDue() bool {
+	return !p.refreshAt.IsZero() && !p.now().Before(p.refreshAt)
+}
+
+// refreshCredentials configures the provider again. The caller must hold mu
+// for writing.
+func (p *credentialsRefreshingProvider) refreshCredentials() {
+	log.Printf("[INFO] The credentials of provider %s are about to expire, configuring it again", p.name)
+	resp := p.Interface.ConfigureProvider(p.req)
+	if resp.Diagnostics.HasErrors() {
+		log.Printf("[WARN] Failed to configure provider %s again to renew its credentials: %s", p.name, resp.Diagnostics.Err())
+		return
+	}
+	if resp.CredentialsExpiry.IsZero() {
+		p.refreshAt = time.Time{}
+		return
+	}
+	p.refreshAt = p.refreshTime(resp.CredentialsExpiry)
+}
+
+func (p *credentialsRefreshingProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.ReadResource(req)
+}
+
+func (p *credentialsRefreshingProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.PlanResourceChange(req)
+}
+
+func (p *credentialsRefreshingProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.ApplyResourceChange(req)
+}
+
+func (p *credentialsRefreshingProvider) ApplyResourceChanges(req providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.ApplyResourceChanges(req)
+}
+
+func (p *credentialsRefreshingProvider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.ImportResourceState(req)
+}
+
+func (p *credentialsRefreshingProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.ReadDataSource(req)
+}
+
+func (p *credentialsRefreshingProvider) OpenEphemeralResource(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.OpenEphemeralResource(req)
+}
+
+func (p *credentialsRefreshingProvider) RenewEphemeralResource(req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.RenewEphemeralResource(req)
+}
+
+func (p *credentialsRefreshingProvider) CloseEphemeralResource(req providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.CloseEphemeralResource(req)
+}
+
+func (p *credentialsRefreshingProvider) CallFunction(req providers.CallFunctionRequest) providers.CallFunctionResponse {
+	p.beginCall()
+	defer p.mu.RUnlock()
+	return p.Interface.CallFunction(req)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+func TestCredentialsRefreshingProvider(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	p := simpleMockProvider()
+	configureCount := 0
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+		configureCount++
+		if !req.Config.RawEquals(cty.StringVal("config")) {
+			t.Errorf("wrong config %#v", req.Config)
+		}
+		return providers.ConfigureProviderResponse{
+			CredentialsExpiry: now.Add(time.Hour),
+		}
+	}
+
+	req := providers.ConfigureProviderRequest{Config: cty.StringVal("config")}
+	wrapped := newCredentialsRefreshingProvider(p, "provider[\"registry.opentofu.org/hashicorp/test\"]", req, start.Add(time.Hour))
+	wrapped.now = func() time.Time { return now }
+	wrapped.refreshAt = wrapped.refreshTime(start.Add(time.Hour))
+
+	call := func() {
+		wrapped.ReadDataSource(providers.ReadDataSourceRequest{
+			TypeName: "test_object",
+			Config:   cty.ObjectVal(map[string]cty.Value{"test_string": cty.NullVal(cty.String)}),
+		})
+	}
+
+	// Well before expiry, the provider is not configured again.
+	now = start.Add(30 * time.Minute)
+	call()
+	if configureCount != 0 {
+		t.Fatalf("provider configured %d times before its credentials were about to expire", configureCount)
+	}
+
+	// Within the refresh margin, the provider is configured again once.
+	now = start.Add(56 * time.Minute)
+	call()
+	call()
+	if configureCount != 1 {
+		t.Fatalf("provider configured %d times; want 1", configureCount)
+	}
+
+	// The new expiry from the second configuration is used for the next refresh.
+	now = start.Add(90 * time.Minute)
+	call()
+	if configureCount != 1 {
+		t.Fatalf("provider configured %d times; want 1", configureCount)
+	}
+	now = start.Add(115 * time.Minute)
+	call()
+	if configureCount != 2 {
+		t.Fatalf("provider configured %d times; want 2", configureCount)
+	}
+}
+
+func TestCredentialsRefreshingProvider_shortLived(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wrapped := newCredentialsRefreshingProvider(simpleMockProvider(), "test", providers.ConfigureProviderRequest{}, start)
+	wrapped.now = func() time.Time { return start }
+
+	// Credentials that live for less than four times the maximum margin are
+	// refreshed after three quarters of their lifetime.
+	if got, want := wrapped.refreshTime(start.Add(8*time.Minute)), start.Add(6*time.Minute); !got.Equal(want) {
+		t.Errorf("wrong refresh time %s; want %s", got, want)
+	}
+	if got, want := wrapped.refreshTime(start.Add(time.Hour)), start.Add(55*time.Minute); !got.Equal(want) {
+		t.Errorf("wrong refresh time %s; want %s", got, want)
+	}
+}
+
+func TestCredentialsRefreshingProvider_callsInFlight(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	p := simpleMockProvider()
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+	var inFlight atomic.Int32
+	var configureCount atomic.Int32
+	p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+		configureCount.Add(1)
+		if n := inFlight.Load(); n != 0 {
+			t.Errorf("provider configured while %d calls were in flight", n)
+		}
+		return providers.ConfigureProviderResponse{}
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+		if req.Config.GetAttr("test_string").RawEquals(cty.StringVal("slow")) {
+			close(started)
+			<-release
+		}
+		return providers.ReadDataSourceResponse{State: req.Config}
+	}
+
+	wrapped := newCredentialsRefreshingProvider(p, "test", providers.ConfigureProviderRequest{}, start.Add(time.Hour))
+	wrapped.now = func() time.Time { return now }
+	wrapped.refreshAt = wrapped.refreshTime(start.Add(time.Hour))
+
+	call := func(val string) {
+		wrapped.ReadDataSource(providers.ReadDataSourceRequest{
+			TypeName: "test_object",
+			Config:   cty.ObjectVal(map[string]cty.Value{"test_string": cty.StringVal(val)}),
+		})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		call("slow")
+	}()
+	<-started
+
+	// The credentials are now about to expire, but the provider must not
+	// be configured again until the slow call is complete.
+	now = start.Add(56 * time.Minute)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		call("fast")
+	}()
+	close(release)
+	wg.Wait()
+
+	if got := configureCount.Load(); got != 1 {
+		t.Fatalf("provider configured %d times; want 1", got)
+	}
+}
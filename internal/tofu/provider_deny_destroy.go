@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// checkProviderDenyDestroy returns an error for each provider configuration
+// with deny_destroy set that the given plan would use to destroy objects,
+// listing all of the objects that would be destroyed.
+//
+// Unlike prevent_destroy, which protects individual resources, deny_destroy
+// protects everything managed through a provider configuration, so we check
+// it once the whole plan is known in order to report all affected objects
+// together.
+func checkProviderDenyDestroy(config *configs.Config, changes *plans.Changes) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if config == nil || changes == nil {
+		return diags
+	}
+
+	type denied struct {
+		provider *configs.Provider
+		objects  []string
+	}
+	deniedByAddr := make(map[string]*denied)
+
+	for _, rc := range changes.Resources {
+		if rc.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+		switch rc.Action {
+		case plans.Delete, plans.DeleteThenCreate, plans.CreateThenDelete:
+		default:
+			continue
+		}
+
+		pc := providerConfigForAddr(config, rc.ProviderAddr)
+		if pc == nil || !pc.DenyDestroy {
+			continue
+		}
+
+		key := rc.ProviderAddr.String()
+		d, ok := deniedByAddr[key]
+		if !ok {
+			d = &denied{provider: pc}
+			deniedByAddr[key] = d
+		}
+		line := rc.Addr.String()
+		if rc.DeposedKey != states.NotDeposed {
+			line = fmt.Sprintf("%s (deposed object %s)", line, rc.DeposedKey)
+		}
+		if rc.Action != plans.Delete {
+			line += " (replace)"
+		}
+		d.objects = append(d.objects, "  - "+line)
+	}
+
+	providerAddrs := make([]string, 0, len(deniedByAddr))
+	for addr := range deniedByAddr {
+		providerAddrs = append(providerAddrs, addr)
+	}
+	sort.Strings(providerAddrs)
+
+	for _, addr := range providerAddrs {
+		d := deniedByAddr[addr]
+		sort.Strings(d.objects)
+		var subject *hcl.Range
+		if d.provider.DenyDestroyRange != nil {
+			subject = d.provider.DenyDestroyRange
+		} else {
+			subject = d.provider.DeclRange.Ptr()
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Provider configuration does not allow destroying objects",
+			Detail: fmt.Sprintf(
+				"The provider configuration %s has deny_destroy set, but this plan would destroy the following objects that belong to it:\n%s\n\nTo proceed, either change the plan so that these objects are not destroyed, for example by using \"tofu state rm\" or a \"removed\" block to stop managing them without destroying them, or remove deny_destroy from the provider configuration.",
+				addr, strings.Join(d.objects, "\n"),
+			),
+			Subject: subject,
+		})
+	}
+	return diags
+}
+
+// providerConfigForAddr returns the provider block in the configuration that
+// declares the given provider configuration, or nil if the configuration is
+// implied or the module is no longer part of the configuration.
+func providerConfigForAddr(config *configs.Config, addr addrs.AbsProviderConfig) *configs.Provider {
+	modCfg := config.Descendent(addr.Module)
+	if modCfg == nil {
+		return nil
+	}
+	key := modCfg.Module.LocalNameForProvider(addr.Provider)
+	if addr.Alias != "" {
+		key += "." + addr.Alias
+	}
+	return modCfg.Module.ProviderConfigs[key]
+}
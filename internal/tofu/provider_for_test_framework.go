@@ -9,11 +9,16 @@ import (
 	"fmt"
 	"hash/fnv"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
+	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 var _ providers.Interface = &providerForTest{}
@@ -61,10 +66,26 @@ func newProviderForTestWithSchema(internal providers.Interface, schema providers
 func (p providerForTest) ReadResource(r providers.ReadResourceRequest) providers.ReadResourceResponse {
 	resSchema, _ := p.schema.SchemaForResourceType(addrs.ManagedResourceMode, r.TypeName)
 
-	mockValues := p.getMockValuesForManagedResource(r.TypeName)
-
 	var resp providers.ReadResourceResponse
 
+	mockValues, diags := p.getMockValuesForManagedResource(r.TypeName, r.PriorState)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	// Generated values must stay the same for the lifetime of an object, as
+	// with the values that a real provider generates.
+	generators := p.getMockGenerators(addrs.ManagedResourceMode, r.TypeName)
+	if len(generators) > 0 {
+		mockValues = copyMockValues(mockValues)
+		for _, gen := range generators {
+			if isMockAttrSet(r.PriorState, gen.Attribute) {
+				mockValues[gen.Attribute] = r.PriorState.GetAttr(gen.Attribute)
+			}
+		}
+	}
+
 	resp.NewState, resp.Diagnostics = newMockValueComposer(r.TypeName).
 		ComposeBySchema(resSchema, r.ProviderMeta, mockValues)
 
@@ -80,10 +101,31 @@ func (p providerForTest) PlanResourceChange(r providers.PlanResourceChangeReques
 
 	resSchema, _ := p.schema.SchemaForResourceType(addrs.ManagedResourceMode, r.TypeName)
 
-	mockValues := p.getMockValuesForManagedResource(r.TypeName)
-
 	var resp providers.PlanResourceChangeResponse
 
+	mockValues, diags := p.getMockValuesForManagedResource(r.TypeName, r.Config)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	// Like a real provider, we only generate values when we create an
+	// object, so they are unknown until then.
+	generators := p.getMockGenerators(addrs.ManagedResourceMode, r.TypeName)
+	if len(generators) > 0 {
+		mockValues = copyMockValues(mockValues)
+		for _, gen := range generators {
+			if isMockAttrSet(r.Config, gen.Attribute) {
+				continue
+			}
+			if isMockAttrSet(r.PriorState, gen.Attribute) {
+				mockValues[gen.Attribute] = r.PriorState.GetAttr(gen.Attribute)
+				continue
+			}
+			mockValues[gen.Attribute] = cty.DynamicVal
+		}
+	}
+
 	resp.PlannedState, resp.Diagnostics = newMockValueComposer(r.TypeName).
 		ComposeBySchema(resSchema, r.Config, mockValues)
 
@@ -91,9 +133,38 @@ func (p providerForTest) PlanResourceChange(r providers.PlanResourceChangeReques
 }
 
 func (p providerForTest) ApplyResourceChange(r providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
-	return providers.ApplyResourceChangeResponse{
-		NewState: r.PlannedState,
+	var resp providers.ApplyResourceChangeResponse
+
+	generators := p.getMockGenerators(addrs.ManagedResourceMode, r.TypeName)
+	if len(generators) == 0 || r.PlannedState.IsNull() {
+		resp.NewState = r.PlannedState
+		return resp
+	}
+
+	resSchema, _ := p.schema.SchemaForResourceType(addrs.ManagedResourceMode, r.TypeName)
+	values := r.PlannedState.AsValueMap()
+	for _, gen := range generators {
+		if v, ok := values[gen.Attribute]; !ok || v.IsKnown() {
+			continue
+		}
+		v, diags := generateMockAttrValue(resSchema, r.TypeName, gen)
+		resp.Diagnostics = resp.Diagnostics.Append(diags)
+		if diags.HasErrors() {
+			return resp
+		}
+		values[gen.Attribute] = v
 	}
+	resp.NewState = cty.ObjectVal(values)
+
+	return resp
+}
+
+func (p providerForTest) ApplyResourceChanges(r providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse {
+	var resp providers.ApplyResourceChangesResponse
+	for _, change := range r.Changes {
+		resp.Changes = append(resp.Changes, p.ApplyResourceChange(change))
+	}
+	return resp
 }
 
 func (p providerForTest) ReadDataSource(r providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
@@ -101,7 +172,27 @@ func (p providerForTest) ReadDataSource(r providers.ReadDataSourceRequest) provi
 
 	var resp providers.ReadDataSourceResponse
 
-	mockValues := p.getMockValuesForDataResource(r.TypeName)
+	mockValues, diags := p.getMockValuesForDataResource(r.TypeName, r.Config)
+	if diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+
+	generators := p.getMockGenerators(addrs.DataResourceMode, r.TypeName)
+	if len(generators) > 0 {
+		mockValues = copyMockValues(mockValues)
+		for _, gen := range generators {
+			if isMockAttrSet(r.Config, gen.Attribute) {
+				continue
+			}
+			v, diags := generateMockAttrValue(resSchema, r.TypeName, gen)
+			if diags.HasErrors() {
+				resp.Diagnostics = diags
+				return resp
+			}
+			mockValues[gen.Attribute] = v
+		}
+	}
 
 	resp.State, resp.Diagnostics = newMockValueComposer(r.TypeName).
 		ComposeBySchema(resSchema, r.Config, mockValues)
@@ -109,6 +200,29 @@ func (p providerForTest) ReadDataSource(r providers.ReadDataSourceRequest) provi
 	return resp
 }
 
+// OpenEphemeralResource returns a value generated from the schema, since the
+// internal provider is not configured.
+func (p providerForTest) OpenEphemeralResource(r providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+	resSchema, _ := p.schema.SchemaForResourceType(addrs.EphemeralResourceMode, r.TypeName)
+
+	var resp providers.OpenEphemeralResourceResponse
+
+	resp.Result, resp.Diagnostics = newMockValueComposer(r.TypeName).
+		ComposeBySchema(resSchema, r.Config, nil)
+
+	return resp
+}
+
+// RenewEphemeralResource is irrelevant when provider is mocked or overridden.
+func (p providerForTest) RenewEphemeralResource(_ providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	return providers.RenewEphemeralResourceResponse{}
+}
+
+// CloseEphemeralResource is irrelevant when provider is mocked or overridden.
+func (p providerForTest) CloseEphemeralResource(_ providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	return providers.CloseEphemeralResourceResponse{}
+}
+
 // ValidateProviderConfig is irrelevant when provider is mocked or overridden.
 func (p providerForTest) ValidateProviderConfig(_ providers.ValidateProviderConfigRequest) providers.ValidateProviderConfigResponse {
 	return providers.ValidateProviderConfigResponse{}
@@ -152,6 +266,10 @@ func (p providerForTest) ValidateDataResourceConfig(r providers.ValidateDataReso
 	return p.internal.ValidateDataResourceConfig(r)
 }
 
+func (p providerForTest) ValidateEphemeralResourceConfig(r providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse {
+	return p.internal.ValidateEphemeralResourceConfig(r)
+}
+
 func (p providerForTest) UpgradeResourceState(r providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
 	return p.internal.UpgradeResourceState(r)
 }
@@ -188,7 +306,9 @@ func (p providerForTest) withMockResources(mockResources []*configs.MockResource
 		}
 
 		resources[res.Type] = resourceForTest{
-			values: res.Defaults,
+			values:     res.Defaults,
+			exprs:      res.DefaultExprs,
+			generators: res.Generators,
 		}
 	}
 
@@ -236,6 +356,44 @@ func (p providerForTest) linkWithCurrentResource(addr addrs.ConfigResource) prov
 
 type resourceForTest struct {
 	values map[string]cty.Value
+
+	// exprs are the values that refer to the resource itself as "self",
+	// which are only known once the resource's configuration is.
+	exprs map[string]hcl.Expression
+
+	// generators generate a different value for each object, and are only
+	// used for mock resources.
+	generators []*configs.MockGenerator
+}
+
+// valuesFor returns the values of the resource, with the expressions in exprs
+// evaluated with self as the given object.
+func (r resourceForTest) valuesFor(self cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
+	if len(r.exprs) == 0 || self.IsNull() {
+		return r.values, nil
+	}
+
+	var diags tfdiags.Diagnostics
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"self": self,
+		},
+		Functions: (&lang.Scope{BaseDir: ".", PureOnly: true}).Functions(),
+	}
+
+	values := make(map[string]cty.Value, len(r.values)+len(r.exprs))
+	for k, v := range r.values {
+		values[k] = v
+	}
+	for k, expr := range r.exprs {
+		v, moreDiags := expr.Value(ctx)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		values[k] = v
+	}
+	return values, diags
 }
 
 type mockResourceType = string
@@ -269,26 +427,88 @@ func (res overrideResourcesForTest) copy() overrideResourcesForTest {
 	return resCopy
 }
 
-func (p providerForTest) getMockValuesForManagedResource(typeName string) map[string]cty.Value {
+func (p providerForTest) getMockValuesForManagedResource(typeName string, self cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
 	if p.currentResourceAddress != "" {
 		res, ok := p.overrideResources.managed[p.currentResourceAddress]
 		if ok {
-			return res.values
+			return res.values, nil
 		}
 	}
 
-	return p.mockResources.managed[typeName].values
+	return p.mockResources.managed[typeName].valuesFor(self)
 }
 
-func (p providerForTest) getMockValuesForDataResource(typeName string) map[string]cty.Value {
+func (p providerForTest) getMockValuesForDataResource(typeName string, self cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
 	if p.currentResourceAddress != "" {
 		res, ok := p.overrideResources.data[p.currentResourceAddress]
 		if ok {
-			return res.values
+			return res.values, nil
 		}
 	}
 
-	return p.mockResources.data[typeName].values
+	return p.mockResources.data[typeName].valuesFor(self)
+}
+
+// getMockGenerators returns the generators of the mock resource of the given
+// mode and type, unless the current resource is overridden.
+func (p providerForTest) getMockGenerators(mode addrs.ResourceMode, typeName string) []*configs.MockGenerator {
+	mocks, overrides := p.mockResources.managed, p.overrideResources.managed
+	if mode == addrs.DataResourceMode {
+		mocks, overrides = p.mockResources.data, p.overrideResources.data
+	}
+	if p.currentResourceAddress != "" {
+		if _, ok := overrides[p.currentResourceAddress]; ok {
+			return nil
+		}
+	}
+	return mocks[typeName].generators
+}
+
+// generateMockAttrValue generates the next value of the given generator,
+// converted to the type of the attribute in the given schema.
+func generateMockAttrValue(schema *configschema.Block, typeName string, gen *configs.MockGenerator) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	attr := schema.Attributes[gen.Attribute]
+	if attr == nil || !attr.Computed {
+		return cty.NilVal, diags.Append(tfdiags.WholeContainingBody(
+			tfdiags.Error,
+			fmt.Sprintf("Invalid mock generator for field `%v`", gen.Attribute),
+			"Generators can only generate the values of computed top-level attributes.",
+		))
+	}
+
+	v, err := generateMockValue(typeName, gen)
+	if err == nil {
+		v, err = convert.Convert(v, attr.Type)
+	}
+	if err != nil {
+		return cty.NilVal, diags.Append(tfdiags.WholeContainingBody(
+			tfdiags.Error,
+			fmt.Sprintf("Failed to generate mock value for field `%v`", gen.Attribute),
+			fmt.Sprintf("The %q generator failed: %s.", gen.Type, tfdiags.FormatError(err)),
+		))
+	}
+	return v, diags
+}
+
+// isMockAttrSet returns true if the given object has a non-null value for
+// the given top-level attribute.
+func isMockAttrSet(obj cty.Value, name string) bool {
+	if obj.IsNull() || !obj.IsKnown() || !obj.Type().IsObjectType() || !obj.Type().HasAttribute(name) {
+		return false
+	}
+	return !obj.GetAttr(name).IsNull()
+}
+
+// copyMockValues returns a copy of the given mock values, which can then be
+// modified without affecting the mock resource that they came from.
+func copyMockValues(values map[string]cty.Value) map[string]cty.Value {
+	ret := make(map[string]cty.Value, len(values))
+	for k, v := range values {
+		ret[k] = v
+	}
+	return ret
 }
 
 func newMockValueComposer(typeName string) hcl2shim.MockValueComposer {
@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+
+	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// mockRegexMaxRepeat is the maximum number of extra repetitions that a
+// regex generator produces for an unbounded repetition such as x* or x+.
+const mockRegexMaxRepeat = 4
+
+// generateMockValue generates the next value of the given generator of a
+// mock resource of the given type.
+//
+// The values that use randomness are derived from the resource type, the
+// attribute, and the position of the value in the sequence of generated
+// values, so that each run of a test generates the same values.
+func generateMockValue(typeName string, gen *configs.MockGenerator) (cty.Value, error) {
+	n := gen.Next()
+
+	hash := fnv.New64()
+	hash.Write([]byte(typeName + "." + gen.Attribute))
+	rnd := rand.New(rand.NewSource(int64(hash.Sum64()) + n)) //nolint:gosec // The values only need to look random.
+
+	switch gen.Type {
+	case configs.MockGeneratorUUID:
+		var buf [16]byte
+		rnd.Read(buf[:])
+		buf[6] = (buf[6] & 0x0f) | 0x40 // version 4
+		buf[8] = (buf[8] & 0x3f) | 0x80 // variant 10
+		return cty.StringVal(fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16])), nil
+	case configs.MockGeneratorSequence:
+		return cty.NumberIntVal(gen.Start + n*gen.Step), nil
+	case configs.MockGeneratorCIDR:
+		prefixLen, _ := gen.Pool.Mask.Size()
+		if gen.NewBits < 63 && n >= int64(1)<<gen.NewBits {
+			return cty.NilVal, fmt.Errorf("the pool %s has no more /%d blocks", gen.Pool, prefixLen+gen.NewBits)
+		}
+		subnet, err := cidr.Subnet(gen.Pool, gen.NewBits, int(n))
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(subnet.String()), nil
+	case configs.MockGeneratorRegex:
+		re, err := syntax.Parse(gen.Pattern.String(), syntax.Perl)
+		if err != nil {
+			// Should never happen, because the pattern was already compiled.
+			return cty.NilVal, err
+		}
+		var buf strings.Builder
+		if err := generateRegexString(rnd, re.Simplify(), &buf); err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(buf.String()), nil
+	default:
+		// Should never happen, because the configuration was validated.
+		return cty.NilVal, fmt.Errorf("unsupported generator type %q", gen.Type)
+	}
+}
+
+// generateRegexString writes a random string that matches the given regular
+// expression to buf.
+func generateRegexString(rnd *rand.Rand, re *syntax.Regexp, buf *strings.Builder) error {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		// These match without consuming anything.
+	case syntax.OpLiteral:
+		buf.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		r, ok := randomRuneInClass(rnd, re.Rune)
+		if !ok {
+			return fmt.Errorf("the character class %s can't match anything", re)
+		}
+		buf.WriteRune(r)
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		// We'll stick to characters that are safe to use in most places.
+		const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+		buf.WriteByte(chars[rnd.Intn(len(chars))])
+	case syntax.OpCapture:
+		return generateRegexString(rnd, re.Sub[0], buf)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+		if hi < 0 {
+			hi = lo + mockRegexMaxRepeat
+		}
+		count := lo + rnd.Intn(hi-lo+1)
+		for i := 0; i < count; i++ {
+			if err := generateRegexString(rnd, re.Sub[0], buf); err != nil {
+				return err
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := generateRegexString(rnd, sub, buf); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		return generateRegexString(rnd, re.Sub[rnd.Intn(len(re.Sub))], buf)
+	default:
+		return fmt.Errorf("the pattern can't match anything")
+	}
+	return nil
+}
+
+// randomRuneInClass returns a random rune in the given character class,
+// which is a list of inclusive ranges as in syntax.Regexp.Rune. It prefers
+// printable ASCII characters when the class has any, so that classes such
+// as [^,] generate readable strings.
+func randomRuneInClass(rnd *rand.Rand, ranges []rune) (rune, bool) {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := max(ranges[i], ' '); r <= min(ranges[i+1], '~'); r++ {
+			printable = append(printable, r)
+		}
+	}
+	if len(printable) > 0 {
+		return printable[rnd.Intn(len(printable))], true
+	}
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r <= unicode.MaxRune; r++ {
+			if unicode.IsPrint(r) {
+				return r, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,89 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+func TestGenerateMockValue(t *testing.T) {
+	t.Run("uuid", func(t *testing.T) {
+		gen := &configs.MockGenerator{Attribute: "id", Type: configs.MockGeneratorUUID}
+		uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		seen := make(map[string]bool)
+		for i := 0; i < 10; i++ {
+			v, err := generateMockValue("test_resource", gen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !uuidRe.MatchString(v.AsString()) {
+				t.Errorf("%q is not a version 4 UUID", v.AsString())
+			}
+			if seen[v.AsString()] {
+				t.Errorf("%q was generated twice", v.AsString())
+			}
+			seen[v.AsString()] = true
+		}
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		gen := &configs.MockGenerator{Attribute: "index", Type: configs.MockGeneratorSequence, Start: 10, Step: 5}
+		for _, want := range []int64{10, 15, 20} {
+			v, err := generateMockValue("test_resource", gen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !v.RawEquals(cty.NumberIntVal(want)) {
+				t.Errorf("wrong value %#v; want %d", v, want)
+			}
+		}
+	})
+
+	t.Run("cidr", func(t *testing.T) {
+		_, pool, _ := net.ParseCIDR("10.1.0.0/23")
+		gen := &configs.MockGenerator{Attribute: "cidr_block", Type: configs.MockGeneratorCIDR, Pool: pool, NewBits: 1}
+		for _, want := range []string{"10.1.0.0/24", "10.1.1.0/24"} {
+			v, err := generateMockValue("test_resource", gen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !v.RawEquals(cty.StringVal(want)) {
+				t.Errorf("wrong value %#v; want %q", v, want)
+			}
+		}
+		_, err := generateMockValue("test_resource", gen)
+		if got, want := err.Error(), "the pool 10.1.0.0/23 has no more /24 blocks"; got != want {
+			t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		patterns := []string{
+			`^i-[0-9a-f]{17}$`,
+			`^(web|api)-\d+$`,
+			`[A-Z][a-z]*(-[A-Z][a-z]*)?`,
+			`^arn:aws:iam::\d{12}:role/[\w+=,.@-]+$`,
+		}
+		for _, pattern := range patterns {
+			gen := &configs.MockGenerator{Attribute: "name", Type: configs.MockGeneratorRegex, Pattern: regexp.MustCompile(pattern)}
+			for i := 0; i < 10; i++ {
+				v, err := generateMockValue("test_resource", gen)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !gen.Pattern.MatchString(v.AsString()) {
+					t.Errorf("%q doesn't match %s", v.AsString(), pattern)
+				}
+			}
+		}
+	})
+}
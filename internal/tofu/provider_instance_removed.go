@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// providerInstanceRemoved is the extra info attached to the diagnostics
+// reported when an object in the state refers to an instance of a provider
+// configuration that no longer exists, because its element has been removed
+// from the for_each collection or its index is no longer within the count.
+//
+// Each graph node reports its own diagnostic during the walk, and
+// consolidateProviderInstanceRemovedDiags then replaces them with a single
+// error per provider instance that lists all of the affected objects, so
+// that the user can fix them all at once.
+type providerInstanceRemoved struct {
+	Provider    addrs.AbsProviderConfig
+	ProviderKey addrs.InstanceKey
+	Resource    addrs.AbsResourceInstance
+	DeposedKey  states.DeposedKey
+
+	wrapped interface{}
+}
+
+var _ tfdiags.DiagnosticExtraWrapper = (*providerInstanceRemoved)(nil)
+var _ tfdiags.DiagnosticExtraUnwrapper = (*providerInstanceRemoved)(nil)
+
+func (e *providerInstanceRemoved) WrapDiagnosticExtra(inner interface{}) {
+	e.wrapped = inner
+}
+
+func (e *providerInstanceRemoved) UnwrapDiagnosticExtra() interface{} {
+	return e.wrapped
+}
+
+// withProviderInstanceRemoved marks the given diagnostic as caused by the
+// removal of the provider instance that the given object belongs to.
+func withProviderInstanceRemoved(diag tfdiags.Diagnostic, provider addrs.AbsProviderConfig, key addrs.InstanceKey, addr addrs.AbsResourceInstance, deposedKey states.DeposedKey) tfdiags.Diagnostic {
+	return tfdiags.Override(diag, diag.Severity(), func() tfdiags.DiagnosticExtraWrapper {
+		return &providerInstanceRemoved{
+			Provider:    provider,
+			ProviderKey: key,
+			Resource:    addr,
+			DeposedKey:  deposedKey,
+		}
+	})
+}
+
+// consolidateProviderInstanceRemovedDiags replaces the diagnostics marked by
+// withProviderInstanceRemoved with a single error for each removed provider
+// instance, listing the objects that still belong to it and the ways to
+// resolve the problem. All other diagnostics are returned unchanged.
+func consolidateProviderInstanceRemovedDiags(diags tfdiags.Diagnostics) tfdiags.Diagnostics {
+	var ret tfdiags.Diagnostics
+	removed := make(map[string][]*providerInstanceRemoved)
+	for _, diag := range diags {
+		extra := tfdiags.ExtraInfo[*providerInstanceRemoved](diag)
+		if extra == nil {
+			ret = ret.Append(diag)
+			continue
+		}
+		key := extra.Provider.InstanceString(extra.ProviderKey)
+		removed[key] = append(removed[key], extra)
+	}
+
+	providerAddrs := make([]string, 0, len(removed))
+	for addr := range removed {
+		providerAddrs = append(providerAddrs, addr)
+	}
+	sort.Strings(providerAddrs)
+
+	for _, providerAddr := range providerAddrs {
+		ret = ret.Append(providerInstanceRemovedError(providerAddr, removed[providerAddr]))
+	}
+	return ret
+}
+
+func providerInstanceRemovedError(providerAddr string, objects []*providerInstanceRemoved) tfdiags.Diagnostic {
+	// The same object can be reported more than once, for example while
+	// refreshing and while planning, so we deduplicate them here.
+	seen := make(map[string]bool)
+	var lines []string
+	resources := make(map[string]bool)
+	for _, obj := range objects {
+		line := obj.Resource.String()
+		if obj.DeposedKey != states.NotDeposed {
+			line = fmt.Sprintf("%s (deposed object %s)", line, obj.DeposedKey)
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, "  - "+line)
+		resources[obj.Resource.ContainingResource().String()] = true
+	}
+	sort.Strings(lines)
+
+	var resourceAddrs []string
+	for addr := range resources {
+		resourceAddrs = append(resourceAddrs, addr)
+	}
+	sort.Strings(resourceAddrs)
+
+	// The removed instance, the way to declare it again and the usual cause
+	// depend on whether the provider configuration uses for_each or count.
+	var removed, restore, cause string
+	switch k := objects[0].ProviderKey.(type) {
+	case addrs.IntKey:
+		removed = fmt.Sprintf("The index %d is no longer within the count of %s", int(k), objects[0].Provider)
+		restore = fmt.Sprintf("increase the provider's count to include the index %d again while leaving the resources without an instance that uses it, apply the change, and then decrease the provider's count", int(k))
+		cause = "the same count both for a resource (or its containing module) and its associated provider configuration"
+	default:
+		elem := k.String()
+		if sk, ok := k.(addrs.StringKey); ok {
+			elem = fmt.Sprintf("%q", string(sk))
+		}
+		removed = fmt.Sprintf("The element %s has been removed from the for_each collection of %s", elem, objects[0].Provider)
+		restore = fmt.Sprintf("re-add the element %s to the provider's for_each collection while leaving it out of the collection of the resources, apply the change, and then remove the element from the provider's for_each collection", elem)
+		cause = "the same for_each collection both for a resource (or its containing module) and its associated provider configuration"
+	}
+
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Provider instance not present",
+		fmt.Sprintf(
+			"%s, but the following objects in the state were created by the provider instance %s and still belong to it:\n%s\n\nOpenTofu requires the original provider instance to plan changes for these objects. To proceed, do one of the following:\n  - To destroy the objects, %s.\n  - To stop managing the objects without destroying them, use \"tofu state rm\" to forget them, or add a \"removed\" block for %s if the resource is no longer declared.\n  - To keep managing the objects with another provider instance, use a \"moved\" block or \"tofu state mv\" to move them to a resource instance that is still declared and uses that provider instance.\n\nThis is commonly caused by using %s.",
+			removed, providerAddr, strings.Join(lines, "\n"),
+			restore, strings.Join(resourceAddrs, ", "), cause,
+		),
+	)
+}
@@ -47,6 +47,11 @@ type MockProvider struct {
 	ValidateDataResourceConfigRequest  providers.ValidateDataResourceConfigRequest
 	ValidateDataResourceConfigFn       func(providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse
 
+	ValidateEphemeralResourceConfigCalled   bool
+	ValidateEphemeralResourceConfigResponse *providers.ValidateEphemeralResourceConfigResponse
+	ValidateEphemeralResourceConfigRequest  providers.ValidateEphemeralResourceConfigRequest
+	ValidateEphemeralResourceConfigFn       func(providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse
+
 	UpgradeResourceStateCalled   bool
 	UpgradeResourceStateTypeName string
 	UpgradeResourceStateResponse *providers.UpgradeResourceStateResponse
@@ -83,6 +88,10 @@ type MockProvider struct {
 	ApplyResourceChangeRequest  providers.ApplyResourceChangeRequest
 	ApplyResourceChangeFn       func(providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse
 
+	ApplyResourceChangesCalled   bool
+	ApplyResourceChangesRequests []providers.ApplyResourceChangesRequest
+	ApplyResourceChangesFn       func(providers.ApplyResourceChangesRequest) providers.ApplyResourceChangesResponse
+
 	ImportResourceStateCalled   bool
 	ImportResourceStateResponse *providers.ImportResourceStateResponse
 	ImportResourceStateRequest  providers.ImportResourceStateRequest
@@ -93,6 +102,21 @@ type MockProvider struct {
 	ReadDataSourceRequest  providers.ReadDataSourceRequest
 	ReadDataSourceFn       func(providers.ReadDataSourceRequest) providers.ReadDataSourceResponse
 
+	OpenEphemeralResourceCalled   bool
+	OpenEphemeralResourceResponse *providers.OpenEphemeralResourceResponse
+	OpenEphemeralResourceRequest  providers.OpenEphemeralResourceRequest
+	OpenEphemeralResourceFn       func(providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse
+
+	RenewEphemeralResourceCalled   bool
+	RenewEphemeralResourceResponse *providers.RenewEphemeralResourceResponse
+	RenewEphemeralResourceRequest  providers.RenewEphemeralResourceRequest
+	RenewEphemeralResourceFn       func(providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse
+
+	CloseEphemeralResourceCalled   bool
+	CloseEphemeralResourceResponse *providers.CloseEphemeralResourceResponse
+	CloseEphemeralResourceRequest  providers.CloseEphemeralResourceRequest
+	CloseEphemeralResourceFn       func(providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse
+
 	GetFunctionsCalled   bool
 	GetFunctionsResponse *providers.GetFunctionsResponse
 	GetFunctionsFn       func() providers.GetFunctionsResponse
@@ -208,6 +232,36 @@ func (p *MockProvider) ValidateDataResourceConfig(r providers.ValidateDataResour
 	return resp
 }
 
+func (p *MockProvider) ValidateEphemeralResourceConfig(r providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ValidateEphemeralResourceConfigCalled = true
+	p.ValidateEphemeralResourceConfigRequest = r
+
+	// Marshall the value to replicate behavior by the GRPC protocol
+	ephemeralSchema, ok := p.getProviderSchema().EphemeralResources[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("no schema found for %q", r.TypeName))
+		return resp
+	}
+	_, err := msgpack.Marshal(r.Config, ephemeralSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	if p.ValidateEphemeralResourceConfigFn != nil {
+		return p.ValidateEphemeralResourceConfigFn(r)
+	}
+
+	if p.ValidateEphemeralResourceConfigResponse != nil {
+		return *p.ValidateEphemeralResourceConfigResponse
+	}
+
+	return resp
+}
+
 func (p *MockProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequest) (resp providers.UpgradeResourceStateResponse) {
 	p.Lock()
 	defer p.Unlock()
@@ -518,6 +572,25 @@ func (p *MockProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 	return resp
 }
 
+func (p *MockProvider) ApplyResourceChanges(r providers.ApplyResourceChangesRequest) (resp providers.ApplyResourceChangesResponse) {
+	p.Lock()
+	p.ApplyResourceChangesCalled = true
+	p.ApplyResourceChangesRequests = append(p.ApplyResourceChangesRequests, r)
+	fn := p.ApplyResourceChangesFn
+	p.Unlock()
+
+	if fn != nil {
+		return fn(r)
+	}
+
+	// By default, each change is applied as ApplyResourceChange would apply
+	// it on its own.
+	for _, change := range r.Changes {
+		resp.Changes = append(resp.Changes, p.ApplyResourceChange(change))
+	}
+	return resp
+}
+
 func (p *MockProvider) ImportResourceState(r providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
 	p.Lock()
 	defer p.Unlock()
@@ -583,6 +656,67 @@ func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 	return resp
 }
 
+func (p *MockProvider) OpenEphemeralResource(r providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ConfigureProviderCalled {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before OpenEphemeralResource %q", r.TypeName))
+		return resp
+	}
+
+	p.OpenEphemeralResourceCalled = true
+	p.OpenEphemeralResourceRequest = r
+
+	if p.OpenEphemeralResourceFn != nil {
+		return p.OpenEphemeralResourceFn(r)
+	}
+
+	if p.OpenEphemeralResourceResponse != nil {
+		return *p.OpenEphemeralResourceResponse
+	}
+
+	// Just echo the configuration back by default.
+	resp.Result = r.Config
+	return resp
+}
+
+func (p *MockProvider) RenewEphemeralResource(r providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.RenewEphemeralResourceCalled = true
+	p.RenewEphemeralResourceRequest = r
+
+	if p.RenewEphemeralResourceFn != nil {
+		return p.RenewEphemeralResourceFn(r)
+	}
+
+	if p.RenewEphemeralResourceResponse != nil {
+		resp = *p.RenewEphemeralResourceResponse
+	}
+
+	return resp
+}
+
+func (p *MockProvider) CloseEphemeralResource(r providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.CloseEphemeralResourceCalled = true
+	p.CloseEphemeralResourceRequest = r
+
+	if p.CloseEphemeralResourceFn != nil {
+		return p.CloseEphemeralResourceFn(r)
+	}
+
+	if p.CloseEphemeralResourceResponse != nil {
+		resp = *p.CloseEphemeralResourceResponse
+	}
+
+	return resp
+}
+
 func (p *MockProvider) GetFunctions() (resp providers.GetFunctionsResponse) {
 	p.Lock()
 	defer p.Unlock()
@@ -0,0 +1,136 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// applyFailureHook is a private Hook implementation that collects the
+// outcome of applying each resource instance, so that Context.Apply can
+// track the consecutive failures of each resource instance in the state and
+// quarantine the ones that keep failing.
+type applyFailureHook struct {
+	NilHook
+
+	mu      sync.Mutex
+	results map[string]applyFailureResult
+}
+
+// applyFailureResult is the outcome of applying a resource instance. If any
+// of the actions taken for the instance failed, err is the first error.
+type applyFailureResult struct {
+	addr addrs.AbsResourceInstance
+	err  error
+}
+
+var _ Hook = (*applyFailureHook)(nil)
+
+func (h *applyFailureHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// An instance can have several actions in one apply, such as when it's
+	// replaced, and it only succeeded if all of them did.
+	key := addr.String()
+	if prev, ok := h.results[key]; !ok || prev.err == nil {
+		h.results[key] = applyFailureResult{addr: addr, err: err}
+	}
+	return HookActionContinue, nil
+}
+
+// reset discards the results collected during any earlier apply.
+func (h *applyFailureHook) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.results = make(map[string]applyFailureResult)
+}
+
+// record updates the apply failures in the given state with the results
+// collected since the hook was last reset, quarantining the resource
+// instances that have now failed in threshold consecutive applies.
+func (h *applyFailureHook) record(state *states.State, threshold int) tfdiags.Diagnostics {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	results := make([]applyFailureResult, 0, len(h.results))
+	for _, result := range h.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].addr.Less(results[j].addr)
+	})
+
+	var quarantined []*states.ApplyFailure
+	for _, result := range results {
+		if result.err == nil {
+			state.RemoveApplyFailure(result.addr)
+			continue
+		}
+
+		f := state.ApplyFailure(result.addr).DeepCopy()
+		if f == nil {
+			f = &states.ApplyFailure{Addr: result.addr}
+		}
+		f.Count++
+		f.LastError = result.err.Error()
+		if !f.Quarantined && f.Count >= threshold {
+			f.Quarantined = true
+			quarantined = append(quarantined, f)
+		}
+		state.SetApplyFailure(f)
+	}
+
+	var diags tfdiags.Diagnostics
+	if len(quarantined) == 0 {
+		return diags
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "The following resource instances have failed to apply %d times in a row, so OpenTofu has quarantined them:\n", threshold)
+	for _, f := range quarantined {
+		fmt.Fprintf(&b, "  - %s\n", f.Addr)
+	}
+	b.WriteString("\nOpenTofu will skip quarantined resource instances, and any resources that depend on them, in all future plans. Once you have fixed the cause of the failures, release each of them using \"tofu state unquarantine ADDRESS\".")
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Resource instances quarantined",
+		b.String(),
+	))
+	return diags
+}
+
+// quarantinedResourceInstancesWarning returns the warning that a plan shows
+// when it skips the given quarantined resource instances.
+func quarantinedResourceInstancesWarning(state *states.State, addrs []addrs.AbsResourceInstance) tfdiags.Diagnostic {
+	var b strings.Builder
+	b.WriteString("The following resource instances are quarantined after failing to apply too many times in a row, so this plan doesn't include any changes to them or to the resources that depend on them:\n")
+	for _, addr := range addrs {
+		f := state.ApplyFailure(addr)
+		fmt.Fprintf(&b, "  - %s (failed %d times, most recently with: %s)\n", addr, f.Count, firstLine(f.LastError))
+	}
+	b.WriteString("\nOnce you have fixed the cause of the failures, release each of them using \"tofu state unquarantine ADDRESS\" to include it in future plans again.")
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Quarantined resource instances skipped",
+		b.String(),
+	)
+}
+
+// firstLine returns the first line of the given error message, which is
+// enough to remind the operator why a resource instance was quarantined.
+func firstLine(msg string) string {
+	line, _, _ := strings.Cut(msg, "\n")
+	return line
+}
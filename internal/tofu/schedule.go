@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/dag"
+)
+
+// SchedulePolicy decides which of the graph nodes that are ready to execute
+// is given the next free parallelism slot during a graph walk.
+type SchedulePolicy string
+
+const (
+	// ScheduleFIFO runs ready nodes in the order they became ready. This is
+	// the default.
+	ScheduleFIFO SchedulePolicy = "fifo"
+
+	// ScheduleFair prefers nodes from the module with the fewest nodes
+	// currently running, so that a module with a large number of ready nodes
+	// cannot starve the other modules of parallelism slots.
+	ScheduleFair SchedulePolicy = "fair"
+
+	// ScheduleCriticalPath prefers nodes with the longest chain of nodes
+	// depending on them, so that the longest dependency chains start early.
+	ScheduleCriticalPath SchedulePolicy = "critical-path"
+)
+
+// ParseSchedulePolicy returns the schedule policy with the given name. The
+// empty string selects the default policy.
+func ParseSchedulePolicy(s string) (SchedulePolicy, error) {
+	if s == "" {
+		return ScheduleFIFO, nil
+	}
+	switch p := SchedulePolicy(s); p {
+	case ScheduleFIFO, ScheduleFair, ScheduleCriticalPath:
+		return p, nil
+	}
+	return "", fmt.Errorf("unsupported schedule policy %q; must be one of %q, %q, or %q", s, ScheduleFIFO, ScheduleFair, ScheduleCriticalPath)
+}
+
+// scheduler limits the number of graph nodes executing at once, in the same
+// way as a Semaphore, but hands out free slots to waiting nodes in an order
+// decided by its policy rather than in arrival order.
+type scheduler struct {
+	policy SchedulePolicy
+
+	mu      sync.Mutex
+	free    int
+	waiting []*schedulerWaiter
+	running map[string]int
+}
+
+type schedulerWaiter struct {
+	module   string
+	priority int
+	ready    chan struct{}
+}
+
+func newScheduler(n int, policy SchedulePolicy) *scheduler {
+	if n <= 0 {
+		panic("scheduler with limit <=0")
+	}
+	if policy == "" {
+		policy = ScheduleFIFO
+	}
+	return &scheduler{
+		policy:  policy,
+		free:    n,
+		running: make(map[string]int),
+	}
+}
+
+// Acquire blocks until a slot is available for a node in the given module.
+// The priority is used only by the critical-path policy, where nodes with a
+// higher priority run first.
+func (s *scheduler) Acquire(module string, priority int) {
+	s.mu.Lock()
+	if s.free > 0 && len(s.waiting) == 0 {
+		s.free--
+		s.running[module]++
+		s.mu.Unlock()
+		return
+	}
+	w := &schedulerWaiter{
+		module:   module,
+		priority: priority,
+		ready:    make(chan struct{}),
+	}
+	s.waiting = append(s.waiting, w)
+	s.mu.Unlock()
+
+	<-w.ready
+}
+
+// Release returns a slot acquired for a node in the given module, handing it
+// directly to the next waiting node if there is one.
+func (s *scheduler) Release(module string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running[module]--
+	if s.running[module] <= 0 {
+		delete(s.running, module)
+	}
+
+	if len(s.waiting) == 0 {
+		s.free++
+		return
+	}
+
+	i := s.next()
+	w := s.waiting[i]
+	s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+	s.running[w.module]++
+	close(w.ready)
+}
+
+// next returns the index of the waiting node that should run next. The
+// waiting list is always in arrival order, so ties are broken in favor of the
+// node that has waited longest. s.mu must be held.
+func (s *scheduler) next() int {
+	best := 0
+	for i := 1; i < len(s.waiting); i++ {
+		w, b := s.waiting[i], s.waiting[best]
+		switch s.policy {
+		case ScheduleFair:
+			if s.running[w.module] < s.running[b.module] {
+				best = i
+			}
+		case ScheduleCriticalPath:
+			if w.priority > b.priority {
+				best = i
+			}
+		default:
+			return 0
+		}
+	}
+	return best
+}
+
+// criticalPathLengths returns, for each vertex of the given graph, the number
+// of vertices in the longest chain of vertices that depend on it, including
+// the vertex itself.
+func criticalPathLengths(g *Graph) map[dag.Vertex]int {
+	ret := make(map[dag.Vertex]int)
+	// Vertices come before the targets of their edges, so each vertex is
+	// visited after everything that depends on it.
+	for _, v := range g.TopologicalOrder() {
+		length := 0
+		for _, up := range g.UpEdges(v) {
+			if l := ret[up]; l > length {
+				length = l
+			}
+		}
+		ret[v] = length + 1
+	}
+	return ret
+}
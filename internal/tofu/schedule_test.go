@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestScheduler_order(t *testing.T) {
+	type waiter struct {
+		module   string
+		priority int
+	}
+	waiters := []waiter{
+		{"module.big", 1},
+		{"module.big", 1},
+		{"module.big", 3},
+		{"module.small", 2},
+	}
+
+	tests := map[SchedulePolicy][]int{
+		ScheduleFIFO: {0, 1, 2, 3},
+		// module.big is still running a node in the other slot, so
+		// module.small goes first.
+		ScheduleFair:         {3, 0, 1, 2},
+		ScheduleCriticalPath: {2, 3, 0, 1},
+	}
+
+	for policy, want := range tests {
+		t.Run(string(policy), func(t *testing.T) {
+			s := newScheduler(2, policy)
+			s.Acquire("module.big", 0)
+			s.Acquire("module.big", 0)
+
+			done := make(chan int)
+			for i, w := range waiters {
+				go func() {
+					s.Acquire(w.module, w.priority)
+					done <- i
+					s.Release(w.module)
+				}()
+				waitForWaiters(t, s, i+1)
+			}
+
+			s.Release("module.big")
+			var got []int
+			for range waiters {
+				got = append(got, <-done)
+			}
+			s.Release("module.big")
+			if !slices.Equal(got, want) {
+				t.Errorf("wrong order\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func waitForWaiters(t *testing.T, s *scheduler, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		count := len(s.waiting)
+		s.mu.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters", n)
+}
+
+func TestParseSchedulePolicy(t *testing.T) {
+	for input, want := range map[string]SchedulePolicy{
+		"":              ScheduleFIFO,
+		"fifo":          ScheduleFIFO,
+		"fair":          ScheduleFair,
+		"critical-path": ScheduleCriticalPath,
+	} {
+		got, err := ParseSchedulePolicy(input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", input, err)
+		}
+		if got != want {
+			t.Errorf("wrong result for %q: got %q, want %q", input, got, want)
+		}
+	}
+
+	if _, err := ParseSchedulePolicy("random"); err == nil {
+		t.Error("unexpected success for an unsupported policy")
+	}
+}
+
+func TestCriticalPathLengths(t *testing.T) {
+	// a depends on b, which depends on c; d depends on c directly.
+	var g Graph
+	g.Add("a")
+	g.Add("b")
+	g.Add("c")
+	g.Add("d")
+	g.Connect(dag.BasicEdge("a", "b"))
+	g.Connect(dag.BasicEdge("b", "c"))
+	g.Connect(dag.BasicEdge("d", "c"))
+
+	got := criticalPathLengths(&g)
+	want := map[dag.Vertex]int{
+		"a": 1,
+		"b": 2,
+		"c": 3,
+		"d": 1,
+	}
+	for v, l := range want {
+		if got[v] != l {
+			t.Errorf("wrong length for %s: got %d, want %d", v, got[v], l)
+		}
+	}
+}
+
+func TestContext2Apply_schedulePolicies(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			module "big" {
+				source = "./child"
+				n      = 20
+			}
+
+			module "small" {
+				source = "./child"
+				n      = 1
+			}
+		`,
+		"child/main.tf": `
+			variable "n" {
+				type = number
+			}
+
+			resource "test_object" "a" {
+				count = var.n
+			}
+
+			resource "test_object" "b" {
+				count      = var.n
+				depends_on = [test_object.a]
+			}
+		`,
+	})
+
+	for _, policy := range []SchedulePolicy{ScheduleFIFO, ScheduleFair, ScheduleCriticalPath} {
+		t.Run(string(policy), func(t *testing.T) {
+			p := simpleMockProvider()
+			ctx := testContext2(t, &ContextOpts{
+				Parallelism:    2,
+				SchedulePolicy: policy,
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+			assertNoErrors(t, diags)
+
+			state, diags := ctx.Apply(context.Background(), plan, m)
+			assertNoErrors(t, diags)
+
+			if got, want := len(state.AllResourceInstanceObjectAddrs()), 42; got != want {
+				t.Errorf("wrong number of objects: got %d, want %d", got, want)
+			}
+		})
+	}
+}
@@ -56,6 +56,8 @@ func (t *AttachResourceConfigTransformer) Transform(g *Graph) error {
 			m = config.Module.ManagedResources
 		} else if addr.Resource.Mode == addrs.DataResourceMode {
 			m = config.Module.DataResources
+		} else if addr.Resource.Mode == addrs.EphemeralResourceMode {
+			m = config.Module.EphemeralResources
 		} else {
 			panic("unknown resource mode: " + addr.Resource.Mode.String())
 		}
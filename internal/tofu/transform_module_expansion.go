@@ -138,6 +138,17 @@ func (t *ModuleExpansionTransformer) transform(g *Graph, c *configs.Config, tree
 	g.Connect(dag.BasicEdge(closer, expander))
 	t.closers[c.Path.String()] = closer
 
+	// Postconditions are checked in the calling module once everything in
+	// the called module, including its output values, is complete.
+	if modCall != nil && len(modCall.Postconditions) != 0 {
+		postconditions := &nodeModulePostconditions{
+			Addr:       c.Path,
+			ModuleCall: modCall,
+		}
+		g.Add(postconditions)
+		g.Connect(dag.BasicEdge(postconditions, closer))
+	}
+
 	for _, childV := range tree.findModule(c.Path) {
 		// don't connect a node to itself
 		if childV == expander {
@@ -75,6 +75,9 @@ type ResolvedProvider struct {
 	KeyModule      addrs.Module
 	KeyResource    bool
 	KeyExact       addrs.InstanceKey
+	// KeyType is the type of instance key KeyExpression must resolve to,
+	// as decided by whether the provider configuration uses count or for_each.
+	KeyType addrs.InstanceKeyType
 }
 
 // GraphNodeProviderConsumer is an interface that nodes that require
@@ -123,6 +126,7 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 	// figure out which _actual_ config address each belongs to, after resolving
 	// for provider inheritance and passing.
 	m := providerVertexMap(g)
+	removed := removedProviderConfigs(t.Config)
 	for _, v := range g.Vertices() {
 		pv, isProviderConsumer := v.(GraphNodeProviderConsumer)
 		if !isProviderConsumer {
@@ -136,6 +140,11 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 		switch providerAddr := req.ProviderConfig.(type) {
 		case addrs.AbsProviderConfig:
 			target := m[providerAddr.String()]
+			if rpc, ok := removed[providerAddr.String()]; ok && target == nil {
+				moreDiags := rebindRemovedProvider(g, v, pv, providerAddr, rpc, m)
+				diags = diags.Append(moreDiags)
+				continue
+			}
 			if target == nil {
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
@@ -174,6 +183,7 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 				KeyModule:     req.KeyModule,
 				KeyResource:   req.KeyResource,
 				KeyExact:      req.KeyExact,
+				KeyType:       providerInstanceKeyType(target),
 			})
 			g.Connect(dag.BasicEdge(v, target))
 		case addrs.LocalProviderConfig:
@@ -241,6 +251,7 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 				}
 			}
 			resolved.ProviderConfig = target.ProviderAddr()
+			resolved.KeyType = providerInstanceKeyType(target)
 
 			log.Printf("[DEBUG] ProviderTransformer: %q (%T) needs %s", dag.VertexName(v), v, dag.VertexName(target))
 			pv.SetProvider(resolved)
@@ -253,6 +264,124 @@ func (t *ProviderTransformer) Transform(g *Graph) error {
 	return diags.Err()
 }
 
+// providerInstanceKeyType returns the type of the instance keys of the given
+// provider node: IntKeyType if its configuration uses count and StringKeyType
+// otherwise.
+// removedProviderConfig is a provider configuration that has been declared
+// as removed by a removed block.
+type removedProviderConfig struct {
+	// to is the provider configuration that takes over the objects still
+	// bound to the removed configuration, or nil if none was given.
+	to *addrs.AbsProviderConfig
+
+	declRange hcl.Range
+}
+
+// removedProviderConfigs returns all of the provider configurations declared
+// as removed anywhere in the given configuration, keyed by the string
+// representation of their absolute addresses.
+func removedProviderConfigs(config *configs.Config) map[string]removedProviderConfig {
+	ret := make(map[string]removedProviderConfig)
+	if config == nil {
+		return ret
+	}
+	config.DeepEach(func(c *configs.Config) {
+		for _, r := range c.Module.Removed {
+			if r.FromProvider == nil {
+				continue
+			}
+			from := addrs.AbsProviderConfig{
+				Module:   c.Path,
+				Provider: c.Module.ProviderForLocalConfig(r.FromProvider.Addr),
+				Alias:    r.FromProvider.Addr.Alias,
+			}
+			rpc := removedProviderConfig{declRange: r.DeclRange}
+			if r.ToProvider != nil {
+				rpc.to = &addrs.AbsProviderConfig{
+					Module:   c.Path,
+					Provider: c.Module.ProviderForLocalConfig(r.ToProvider.Addr),
+					Alias:    r.ToProvider.Addr.Alias,
+				}
+			}
+			ret[from.String()] = rpc
+		}
+	})
+	return ret
+}
+
+// rebindRemovedProvider resolves the provider of a node whose provider
+// configuration from the state has been declared as removed, using the
+// configuration given in the "to" argument of the removed block. If there is
+// no such argument, it returns an error that explains how to proceed.
+func rebindRemovedProvider(g *Graph, v dag.Vertex, pv GraphNodeProviderConsumer, from addrs.AbsProviderConfig, rpc removedProviderConfig, m map[string]GraphNodeProvider) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if rpc.to == nil {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Objects still bound to removed provider configuration",
+			Detail: fmt.Sprintf(
+				"The provider configuration %s has been removed, but %s is still bound to it in the state. Add a \"to\" argument to this removed block to select the provider configuration that should manage the objects from now on, or temporarily re-add the provider configuration to destroy them.",
+				from, dag.VertexName(v),
+			),
+			Subject: rpc.declRange.Ptr(),
+		})
+	}
+
+	var target GraphNodeProvider
+	for addr, ok := *rpc.to, true; ok; addr, ok = addr.Inherited() {
+		if target = m[addr.String()]; target != nil {
+			break
+		}
+	}
+	if p, ok := target.(*graphNodeProxyProvider); ok {
+		target = p.Target()
+	}
+	if target == nil {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Provider configuration not present",
+			Detail: fmt.Sprintf(
+				"The objects bound to the removed provider configuration %s cannot be moved to %s, because there is no such provider configuration.",
+				from, *rpc.to,
+			),
+			Subject: rpc.declRange.Ptr(),
+		})
+	}
+	if pn, ok := target.(interface{ ProviderConfig() *configs.Provider }); ok {
+		if config := pn.ProviderConfig(); config != nil && (config.Count != nil || config.ForEach != nil) {
+			return diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid removed block target",
+				Detail: fmt.Sprintf(
+					"The objects bound to the removed provider configuration %s cannot be moved to %s, because it has multiple instances.",
+					from, target.ProviderAddr(),
+				),
+				Subject: rpc.declRange.Ptr(),
+			})
+		}
+	}
+
+	log.Printf("[DEBUG] ProviderTransformer: %q (%T) was bound to removed %s, using %s instead", dag.VertexName(v), v, from, dag.VertexName(target))
+	// The instance key recorded in the state belongs to the removed
+	// configuration, so it is intentionally not passed through.
+	pv.SetProvider(ResolvedProvider{
+		ProviderConfig: target.ProviderAddr(),
+		KeyType:        providerInstanceKeyType(target),
+	})
+	g.Connect(dag.BasicEdge(v, target))
+	return diags
+}
+
+func providerInstanceKeyType(v GraphNodeProvider) addrs.InstanceKeyType {
+	if pn, ok := v.(interface{ ProviderConfig() *configs.Provider }); ok {
+		if config := pn.ProviderConfig(); config != nil && config.Count != nil {
+			return addrs.IntKeyType
+		}
+	}
+	return addrs.StringKeyType
+}
+
 // ProviderFunctionReference is all the information needed to identify
 // the provider required in a given module path. Alternatively, this
 // could be seen as a Module path + addrs.LocalProviderConfig.
@@ -266,6 +395,7 @@ type FunctionProvidedBy struct {
 	Provider      addrs.AbsProviderConfig
 	KeyModule     addrs.Module
 	KeyExpression hcl.Expression
+	KeyType       addrs.InstanceKeyType
 }
 
 // ProviderFunctionMapping maps a provider used by functions at a given location in the graph to the actual AbsProviderConfig
@@ -410,6 +540,7 @@ func (t *ProviderFunctionTransformer) Transform(g *Graph) error {
 						Provider:      provider.ProviderAddr(),
 						KeyModule:     targetPath,
 						KeyExpression: targetExpr,
+						KeyType:       providerInstanceKeyType(provider),
 					}
 				}
 			}
@@ -147,6 +147,15 @@ func (t *ReferenceTransformer) Transform(g *Graph) error {
 				continue
 			}
 
+			// The validation rules of an input variable only need the values
+			// of the other input variables they refer to, which are provided
+			// by separate nodes, and not the results of their validation
+			// rules. Skipping these edges lets input variables refer to each
+			// other in their validation rules without creating a cycle.
+			if graphNodesAreVariableReferences(v, parent) {
+				continue
+			}
+
 			if !graphNodesAreResourceInstancesInDifferentInstancesOfSameModule(v, parent) {
 				g.Connect(dag.BasicEdge(v, parent))
 			} else {
@@ -359,6 +368,14 @@ func (m ReferenceMap) addReference(path addrs.Module, current dag.Vertex, ref *a
 	return matches
 }
 
+// graphNodesAreVariableReferences returns true if both of the given nodes
+// represent the validation of an input variable.
+func graphNodesAreVariableReferences(a, b dag.Vertex) bool {
+	_, aOk := a.(*nodeVariableReference)
+	_, bOk := b.(*nodeVariableReference)
+	return aOk && bOk
+}
+
 // dependsOn returns the set of vertices that the given vertex refers to from
 // the configured depends_on. The bool return value indicates if depends_on was
 // found in a parent module configuration.
@@ -36,23 +36,27 @@ type TargetingTransformer struct {
 }
 
 func (t *TargetingTransformer) Transform(g *Graph) error {
-	var targetedNodes dag.Set
+	// The -target and -exclude options can't be used together, but the
+	// quarantined resource instances are excluded even from targeted plans,
+	// so we first select the targeted nodes and then remove the excluded
+	// ones from what remains.
 	if len(t.Targets) > 0 {
-		targetedNodes = t.selectTargetedNodes(g, t.Targets)
-	} else if len(t.Excludes) > 0 {
-		targetedNodes = t.removeExcludedNodes(g, t.Excludes)
-	} else {
-		return nil
+		t.removeUntargetedNodes(g, t.selectTargetedNodes(g, t.Targets))
+	}
+	if len(t.Excludes) > 0 {
+		t.removeUntargetedNodes(g, t.removeExcludedNodes(g, t.Excludes))
 	}
 
+	return nil
+}
+
+func (t *TargetingTransformer) removeUntargetedNodes(g *Graph, targetedNodes dag.Set) {
 	for _, v := range g.Vertices() {
 		if !targetedNodes.Include(v) {
 			log.Printf("[DEBUG] Removing %q, filtered by targeting.", dag.VertexName(v))
 			g.Remove(v)
 		}
 	}
-
-	return nil
 }
 
 // selectTargetedNodes goes over a list of resource and modules targeted with a -target flag, and returns a set of
@@ -60,6 +60,11 @@ var protocSteps = []protocStep{
 		"internal/plans/internal/planproto",
 		[]string{"--go_out=paths=source_relative:.", "planfile.proto"},
 	},
+	{
+		"encryptionproto1 (encryption plugin protocol version 1)",
+		"internal/encryption/grpcplugin/encryptionproto1",
+		[]string{"--go_out=paths=source_relative:.", "--go-grpc_out=paths=source_relative:.", "encryptionproto1.proto"},
+	},
 	{
 		"cloudproto1 (cloud protocol version 1)",
 		"internal/cloudplugin/cloudproto1",
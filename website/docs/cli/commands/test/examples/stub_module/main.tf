@@ -0,0 +1,19 @@
+variable "environment" {
+  type = string
+}
+
+module "network" {
+  source = "./network"
+
+  name       = "app-${var.environment}"
+  cidr_block = "10.0.0.0/16"
+}
+
+provider "aws" {
+  region = "us-east-2"
+}
+
+resource "aws_instance" "app" {
+  ami       = "ami-12345678"
+  subnet_id = module.network.subnet_id
+}
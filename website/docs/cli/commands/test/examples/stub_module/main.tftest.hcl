@@ -0,0 +1,25 @@
+// The network module will not be called. Instead, its outputs are
+// computed from the input variables passed to the module call, so
+// the test can check that the parent module wires them correctly.
+stub_module {
+  target = module.network
+  outputs = {
+    subnet_id = "subnet-${var.name}"
+  }
+}
+
+run "test" {
+  variables {
+    environment = "staging"
+  }
+
+  // The instance will not be created in AWS for this run.
+  override_resource {
+    target = aws_instance.app
+  }
+
+  assert {
+    condition     = aws_instance.app.subnet_id == "subnet-app-staging"
+    error_message = "Incorrect subnet: ${aws_instance.app.subnet_id}"
+  }
+}
@@ -0,0 +1,27 @@
+variable "name" {
+  type = string
+}
+
+variable "cidr_block" {
+  type = string
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr_block
+  tags = {
+    Name = var.name
+  }
+}
+
+resource "aws_subnet" "main" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = cidrsubnet(var.cidr_block, 8, 0)
+}
+
+output "subnet_id" {
+  value = aws_subnet.main.id
+}
+
+output "vpc_id" {
+  value = aws_vpc.main.id
+}
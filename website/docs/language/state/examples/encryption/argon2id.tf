@@ -0,0 +1,26 @@
+terraform {
+  encryption {
+    key_provider "argon2id" "foo" {
+      # Specify a long / complex passphrase (min. 16 characters)
+      passphrase = "correct-horse-battery-staple"
+
+      # Alternatively, receive the passphrase from another key provider:
+      chain = key_provider.other.provider
+
+      # Adjust the key length to the encryption method (default: 32)
+      key_length = 32
+
+      # Specify the number of passes over the memory (default: 3)
+      iterations = 3
+
+      # Specify the memory in KiB (min. 19456, default: 65536)
+      memory = 65536
+
+      # Specify the number of threads (default: 4)
+      parallelism = 4
+
+      # Specify the salt length in bytes (default: 32)
+      salt_length = 32
+    }
+  }
+}
@@ -0,0 +1,26 @@
+terraform {
+  encryption {
+    key_provider "conjur" "my_conjur" {
+
+      # Required. ID of the Conjur variable that holds the key material.
+      variable_id = "tofu/prod/state-key"
+
+      # Optional. URL and account of the Conjur server. You can also set
+      # these in the CONJUR_APPLIANCE_URL and CONJUR_ACCOUNT environment variables.
+      appliance_url = "https://conjur.example.com"
+      account       = "myorg"
+
+      # Optional. Authenticate as a host with its API key. You can also set
+      # these in the CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY environment variables.
+      login = "host/tofu/prod"
+      # api_key = "..."
+
+      # Alternatively, authenticate with a JWT:
+      # authn_jwt_service_id = "k8s"
+      # jwt_token_path       = "/var/run/secrets/tokens/conjur"
+
+      # Optional. Number of bytes to derive as a key. Default: 32
+      key_length = 32
+    }
+  }
+}
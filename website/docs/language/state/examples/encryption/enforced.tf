@@ -0,0 +1,22 @@
+terraform {
+  encryption {
+    # Refuse to read or write any unencrypted state or plan file.
+    enforced = true
+
+    key_provider "pbkdf2" "my_passphrase" {
+      passphrase = var.passphrase
+    }
+
+    method "aes_gcm" "my_method" {
+      keys = key_provider.pbkdf2.my_passphrase
+    }
+
+    state {
+      method = method.aes_gcm.my_method
+    }
+
+    plan {
+      method = method.aes_gcm.my_method
+    }
+  }
+}
@@ -0,0 +1,18 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "my_passphrase" {
+      passphrase = var.passphrase
+    }
+
+    method "aes_gcm" "my_method" {
+      keys = key_provider.pbkdf2.my_passphrase
+    }
+
+    state {
+      method = method.aes_gcm.my_method
+
+      # Only encrypt the values that are marked as sensitive.
+      sensitive_only = true
+    }
+  }
+}
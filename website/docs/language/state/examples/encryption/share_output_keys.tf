@@ -0,0 +1,27 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "owner" {
+      passphrase = var.owner_passphrase
+    }
+
+    # The passphrase given to the projects that read the outputs.
+    key_provider "pbkdf2" "consumers" {
+      passphrase = var.consumer_passphrase
+    }
+
+    method "aes_gcm" "owner" {
+      keys = key_provider.pbkdf2.owner
+    }
+
+    method "aes_gcm" "consumers" {
+      keys = key_provider.pbkdf2.consumers
+    }
+
+    state {
+      method = method.aes_gcm.owner
+
+      # Also encrypt the outputs with the consumer key.
+      share_output_keys = [method.aes_gcm.consumers]
+    }
+  }
+}
@@ -0,0 +1,29 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "network" {
+      passphrase = var.network_consumer_passphrase
+
+      # The name of the key provider in the project that owns the state.
+      encrypted_metadata_alias = "key_provider.pbkdf2.consumers"
+    }
+
+    method "aes_gcm" "network" {
+      keys = key_provider.pbkdf2.network
+    }
+
+    remote_state_data_sources {
+      remote_state_data_source "network" {
+        method = method.aes_gcm.network
+      }
+    }
+  }
+}
+
+data "terraform_remote_state" "network" {
+  backend = "s3"
+  config = {
+    bucket = "network-state"
+    key    = "terraform.tfstate"
+    region = "eu-west-1"
+  }
+}
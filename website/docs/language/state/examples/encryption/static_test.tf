@@ -0,0 +1,20 @@
+terraform {
+  encryption {
+    key_provider "static_test" "ci" {
+      # Hex-encoded value to derive the key from.
+      # Anyone who can read this configuration can decrypt the data.
+      key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+
+      # Required. Acknowledges that this key provider is unsafe.
+      unsafe_allow_test_keys = true
+    }
+
+    method "aes_gcm" "ci" {
+      keys = key_provider.static_test.ci
+    }
+
+    state {
+      method = method.aes_gcm.ci
+    }
+  }
+}
@@ -0,0 +1,14 @@
+terraform {
+  encryption {
+    key_provider "systemd_creds" "my_creds" {
+
+      # Required. Name of the credential.
+      name = "tofu-state"
+
+      # Optional. Path of the encrypted credential file, decrypted with
+      # systemd-creds. Without this, OpenTofu reads the credential from the
+      # directory systemd provides to the service (CREDENTIALS_DIRECTORY).
+      path = "/etc/credstore.encrypted/tofu-state"
+    }
+  }
+}
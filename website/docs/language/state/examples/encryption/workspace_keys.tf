@@ -0,0 +1,28 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "my_passphrase" {
+      passphrase = var.passphrase
+
+      # Derive a separate key for each workspace.
+      workspace_key_derivation = true
+    }
+
+    # Alternatively, use a different KMS key in each workspace.
+    key_provider "aws_kms" "my_kms" {
+      kms_key_id = {
+        prod = "arn:aws:kms:us-east-1:111111111111:key/prod-key-id"
+        dev  = "arn:aws:kms:us-east-1:222222222222:key/dev-key-id"
+      }[terraform.workspace]
+      region   = "us-east-1"
+      key_spec = "AES_256"
+    }
+
+    method "aes_gcm" "my_method" {
+      keys = key_provider.pbkdf2.my_passphrase
+    }
+
+    state {
+      method = method.aes_gcm.my_method
+    }
+  }
+}